@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/go-errors/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// dialAddressProvider connects to the external address provision service.
+// Connection is encrypted if certificate of the service is given, it is
+// established through the dialer, so that provider could be reached
+// through Tor.
+func dialAddressProvider(address, certPath string,
+	dial common.DialFunc) (*grpc.ClientConn, error) {
+
+	opts := []grpc.DialOption{
+		grpc.WithDialer(func(address string,
+			timeout time.Duration) (net.Conn, error) {
+			return dial("tcp", address)
+		}),
+	}
+
+	if certPath != "" {
+		creds, err := credentials.NewClientTLSFromFile(certPath, "")
+		if err != nil {
+			return nil, errors.Errorf("unable to load address provider "+
+				"certificate: %v", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		mainLog.Warn("Address provider certificate isn't specified, " +
+			"addresses are requested in plaintext")
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(address, opts...)
+	if err != nil {
+		return nil, errors.Errorf("unable to connect to address "+
+			"provider: %v", err)
+	}
+
+	return conn, nil
+}
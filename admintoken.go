@@ -0,0 +1,44 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// adminTokenSize is the size in bytes of the generated admin token.
+const adminTokenSize = 32
+
+// loadAdminToken reads the admin token from the file, if file doesn't exist
+// the new random token is generated and written in it, so that operator
+// could pass it to the client.
+func loadAdminToken(path string) (string, error) {
+	if fileExists(path) {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.Errorf("unable to read admin token: %v", err)
+		}
+
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", errors.Errorf("admin token file(%v) is empty", path)
+		}
+
+		return token, nil
+	}
+
+	b := make([]byte, adminTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Errorf("unable to generate admin token: %v", err)
+	}
+	token := hex.EncodeToString(b)
+
+	if err := ioutil.WriteFile(path, []byte(token), 0600); err != nil {
+		return "", errors.Errorf("unable to write admin token: %v", err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	bitcoind "github.com/bitlum/connector/connectors/daemons/bitcoind_simple"
+	"github.com/bitlum/connector/connectors/daemons/geth"
+	"github.com/bitlum/connector/connectors/daemons/lnd"
+	daemonRPC "github.com/bitlum/connector/connectors/rpc"
+	"github.com/bitlum/connector/connectors/rpc/bitcoin"
+	"github.com/bitlum/connector/connectors/rpc/bitcoincash"
+	"github.com/bitlum/connector/connectors/rpc/dash"
+	"github.com/bitlum/connector/connectors/rpc/litecoin"
+	rpc "github.com/bitlum/connector/crpc"
+	"github.com/bitlum/connector/db/sqlite"
+	"github.com/bitlum/connector/features"
+	"github.com/bitlum/connector/identity"
+	"github.com/bitlum/connector/locale"
+	"github.com/bitlum/connector/policy"
+	"github.com/btcsuite/go-flags"
+	"github.com/go-errors/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// checkConfigCommand is the name of the command which validates the config
+// and probes the configured services, instead of starting the server.
+const checkConfigCommand = "checkconfig"
+
+// probeTimeout is the maximum time to wait for the response of the probed
+// daemon.
+const probeTimeout = 10 * time.Second
+
+// checkStatus is the outcome of the config check.
+type checkStatus string
+
+const (
+	// checkOK means that the checked part of the config is valid.
+	checkOK checkStatus = "ok"
+
+	// checkWarning means that server would start, but the checked part
+	// of the config is likely a mistake, e.g. database doesn't exist.
+	checkWarning checkStatus = "warning"
+
+	// checkFailed means that server wouldn't start, or the configured
+	// service wouldn't work.
+	checkFailed checkStatus = "failed"
+
+	// checkSkipped means that the checked service is disabled.
+	checkSkipped checkStatus = "skipped"
+)
+
+// checkResult is the outcome of the single check.
+type checkResult struct {
+	Name   string      `json:"name"`
+	Status checkStatus `json:"status"`
+	Detail string      `json:"detail,omitempty"`
+}
+
+// checkReport is the structured report printed by the checkconfig command.
+type checkReport struct {
+	ConfigFile string         `json:"config_file"`
+	Network    string         `json:"network"`
+	Checks     []*checkResult `json:"checks"`
+	Failed     int            `json:"failed"`
+	Warnings   int            `json:"warnings"`
+}
+
+// add adds the outcome of the check to the report.
+func (r *checkReport) add(name string, status checkStatus, format string,
+	args ...interface{}) {
+
+	switch status {
+	case checkFailed:
+		r.Failed++
+	case checkWarning:
+		r.Warnings++
+	}
+
+	r.Checks = append(r.Checks, &checkResult{
+		Name:   name,
+		Status: status,
+		Detail: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkConfigMain loads the config, connects to every configured daemon
+// and database with read-only probes, and prints the report. Error is
+// returned if any of the checks has failed, so that deployment could be
+// stopped before the server is started with the broken config.
+func checkConfigMain() error {
+	cfg := getDefaultConfig()
+	if err := cfg.loadConfig(); err != nil {
+		return err
+	}
+
+	// Loggers are writing to the log rotator, which isn't initialized by
+	// the command, and report is the only output of it.
+	setLogLevels("off")
+
+	report := &checkReport{
+		ConfigFile: cfg.ConfigFile,
+		Network:    cfg.Network,
+	}
+
+	checkConfigFile(report, cfg)
+	checkTLSMaterial(report, cfg)
+	checkKeys(report, cfg)
+	checkDatabase(report, cfg)
+	checkBlockchainDaemons(report, cfg)
+	checkLightningDaemon(report, cfg)
+	checkAuxiliaryFiles(report, cfg)
+
+	data, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		return errors.Errorf("unable to encode report: %v", err)
+	}
+	fmt.Println(string(data))
+
+	if report.Failed != 0 {
+		return errors.Errorf("%v of %v config checks failed", report.Failed,
+			len(report.Checks))
+	}
+
+	return nil
+}
+
+// checkConfigFile ensures that config file doesn't contain unknown or
+// malformed options. Such errors are only logged on the start, and
+// options are silently left with their defaults.
+func checkConfigFile(report *checkReport, cfg config) {
+	if !fileExists(cfg.ConfigFile) {
+		report.add("config", checkWarning, "config file(%v) doesn't "+
+			"exist, defaults and command line options are used",
+			cfg.ConfigFile)
+		return
+	}
+
+	fileConfig := getDefaultConfig()
+	if err := flags.IniParse(cfg.ConfigFile, &fileConfig); err != nil {
+		report.add("config", checkFailed, "%v", err)
+		return
+	}
+
+	report.add("config", checkOK, "config file(%v) is valid", cfg.ConfigFile)
+}
+
+// checkTLSMaterial ensures that TLS certificate of the RPC endpoint matches
+// its key and isn't expired.
+func checkTLSMaterial(report *checkReport, cfg config) {
+	if cfg.NoTLS {
+		report.add("tls", checkSkipped, "TLS is disabled")
+		return
+	}
+
+	certExists := fileExists(cfg.TLSCertPath)
+	keyExists := fileExists(cfg.TLSKeyPath)
+
+	switch {
+	case !certExists && !keyExists:
+		report.add("tls", checkOK, "self-signed certificate will be "+
+			"generated on start")
+		return
+
+	case !certExists || !keyExists:
+		report.add("tls", checkWarning, "either certificate(%v) or "+
+			"key(%v) is missing, both will be replaced by the self-signed "+
+			"ones on start", cfg.TLSCertPath, cfg.TLSKeyPath)
+		return
+	}
+
+	pair, err := tls.LoadX509KeyPair(cfg.TLSCertPath, cfg.TLSKeyPath)
+	if err != nil {
+		report.add("tls", checkFailed, "unable to load key pair: %v", err)
+		return
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		report.add("tls", checkFailed, "unable to parse certificate: %v",
+			err)
+		return
+	}
+
+	reason, err := autogenCertRenewReason(cfg.TLSCertPath, cfg.TLSExtraIPs,
+		cfg.TLSExtraDomains)
+	switch {
+	case err != nil:
+		report.add("tls", checkFailed, "%v", err)
+
+	case reason != "":
+		report.add("tls", checkOK, "self-signed certificate will be "+
+			"rotated on start: %v", reason)
+
+	case time.Now().After(cert.NotAfter):
+		report.add("tls", checkFailed, "certificate has expired at %v",
+			cert.NotAfter)
+
+	case time.Now().Add(autogenCertRenewBefore).After(cert.NotAfter):
+		report.add("tls", checkWarning, "certificate expires at %v",
+			cert.NotAfter)
+
+	default:
+		report.add("tls", checkOK, "certificate is valid until %v",
+			cert.NotAfter)
+	}
+}
+
+// checkKeys ensures that admin token and identity key are readable. Missing
+// ones are generated on start, that is why they are not created here.
+func checkKeys(report *checkReport, cfg config) {
+	if !fileExists(cfg.AdminTokenPath) {
+		report.add("admintoken", checkOK, "admin token will be generated "+
+			"on start")
+	} else if _, err := loadAdminToken(cfg.AdminTokenPath); err != nil {
+		report.add("admintoken", checkFailed, "%v", err)
+	} else {
+		report.add("admintoken", checkOK, "admin token is readable")
+	}
+
+	if !fileExists(cfg.IdentityKeyPath) {
+		report.add("identitykey", checkOK, "identity key will be "+
+			"generated on start")
+	} else if key, err := identity.LoadKey(cfg.IdentityKeyPath); err != nil {
+		report.add("identitykey", checkFailed, "%v", err)
+	} else {
+		report.add("identitykey", checkOK, "identity key id: %v", key.ID())
+	}
+}
+
+// checkDatabase opens the database without the migrations and reads its
+// schema.
+func checkDatabase(report *checkReport, cfg config) {
+	path := filepath.Join(cfg.DataDir, "sqlite")
+	if !fileExists(path) {
+		report.add("database", checkWarning, "database(%v) doesn't exist, "+
+			"it will be created on start", path)
+		return
+	}
+
+	db, err := sqlite.Open(cfg.DataDir, "sqlite", false)
+	if err != nil {
+		report.add("database", checkFailed, "unable to open database: %v",
+			err)
+		return
+	}
+	defer db.Close()
+
+	var tables int
+	err = db.DB.DB().QueryRow("SELECT count(*) FROM sqlite_master WHERE " +
+		"type = 'table'").Scan(&tables)
+	if err != nil {
+		report.add("database", checkFailed, "unable to read database: %v",
+			err)
+		return
+	}
+
+	report.add("database", checkOK, "database(%v) has %v tables", path,
+		tables)
+}
+
+// checkBlockchainDaemons ensures that enabled daemons are reachable with the
+// configured credentials, and that they are working on the configured
+// network and on the chain of their asset.
+func checkBlockchainDaemons(report *checkReport, cfg config) {
+	daemons := []struct {
+		name      string
+		daemon    string
+		asset     connectors.Asset
+		cfg       *BitcoindConfig
+		newClient func(bitcoin.ClientConfig) (daemonRPC.BlocksManager, error)
+	}{
+		{"bitcoin", "bitcoind", connectors.BTC, cfg.Bitcoin,
+			func(c bitcoin.ClientConfig) (daemonRPC.BlocksManager, error) {
+				return bitcoin.NewClient(c)
+			}},
+		{"bitcoincash", "bitcoinabc", connectors.BCH, cfg.BitcoinCash,
+			func(c bitcoin.ClientConfig) (daemonRPC.BlocksManager, error) {
+				return bitcoincash.NewClient(bitcoincash.ClientConfig(c))
+			}},
+		{"dash", "dashd", connectors.DASH, cfg.Dash,
+			func(c bitcoin.ClientConfig) (daemonRPC.BlocksManager, error) {
+				return dash.NewClient(dash.ClientConfig(c))
+			}},
+		{"litecoin", "litecoind", connectors.LTC, cfg.Litecoin,
+			func(c bitcoin.ClientConfig) (daemonRPC.BlocksManager, error) {
+				return litecoin.NewClient(litecoin.ClientConfig(c))
+			}},
+	}
+
+	for _, d := range daemons {
+		if d.cfg.Disabled {
+			report.add(d.name, checkSkipped, "daemon is disabled")
+			continue
+		}
+
+		client, err := d.newClient(bitcoin.ClientConfig{
+			Name:     d.daemon,
+			Logger:   rpcLog,
+			Asset:    d.asset,
+			RPCHost:  d.cfg.Host,
+			RPCPort:  d.cfg.Port,
+			User:     d.cfg.User,
+			Password: d.cfg.Password,
+			PoolSize: 1,
+			Timeout:  probeTimeout,
+
+			CookiePath:   d.cfg.CookiePath,
+			PasswordFile: d.cfg.PasswordFile,
+			Proxy:        cfg.Proxy,
+		})
+		if err != nil {
+			report.add(d.name, checkFailed, "unable to create rpc "+
+				"client: %v", err)
+			continue
+		}
+
+		info, err := bitcoind.ProbeDaemon(client, d.asset, cfg.Network)
+		switch {
+		case err == bitcoind.ErrChainNotIdentified:
+			report.add(d.name, checkWarning, "chain(%v) at height %v "+
+				"couldn't be identified yet", info.Chain, info.Blocks)
+
+		case err != nil:
+			report.add(d.name, checkFailed, "%v", err)
+
+		default:
+			report.add(d.name, checkOK, "chain(%v), blocks %v of %v "+
+				"headers", info.Chain, info.Blocks, info.Headers)
+		}
+	}
+
+	if cfg.Ethereum.Disabled {
+		report.add("ethereum", checkSkipped, "daemon is disabled")
+		return
+	}
+
+	network, err := geth.ProbeDaemon(&geth.DaemonConfig{
+		Name:       "geth",
+		ServerHost: cfg.Ethereum.Host,
+		ServerPort: cfg.Ethereum.Port,
+		Password:   cfg.Ethereum.Password,
+		MaxConns:   1,
+		Timeout:    probeTimeout,
+		KeepAlive:  cfg.Ethereum.KeepAlive,
+		Proxy:      cfg.Proxy,
+
+		PasswordFile: cfg.Ethereum.PasswordFile,
+	}, cfg.Network)
+	if err != nil {
+		report.add("ethereum", checkFailed, "%v", err)
+		return
+	}
+
+	report.add("ethereum", checkOK, "network(%v)", network)
+}
+
+// checkLightningDaemon ensures that lnd is reachable with the configured TLS
+// certificate and macaroon, and that it is working on the configured
+// network.
+func checkLightningDaemon(report *checkReport, cfg config) {
+	if cfg.BitcoinLightning.Disabled {
+		report.add("bitcoinlightning", checkSkipped, "daemon is disabled")
+		return
+	}
+
+	info, err := lnd.Probe(&lnd.Config{
+		Net:          cfg.Network,
+		Name:         "lnd",
+		Host:         cfg.BitcoinLightning.Host,
+		Port:         cfg.BitcoinLightning.Port,
+		TlsCertPath:  cfg.BitcoinLightning.TlsCertPath,
+		MacaroonPath: cfg.BitcoinLightning.MacaroonPath,
+		Proxy:        cfg.Proxy,
+	}, probeTimeout)
+	if err != nil {
+		report.add("bitcoinlightning", checkFailed, "%v", err)
+		return
+	}
+
+	if !info.SyncedToChain {
+		report.add("bitcoinlightning", checkWarning, "node(%v) isn't "+
+			"synced to chain", info.IdentityPubkey)
+		return
+	}
+
+	report.add("bitcoinlightning", checkOK, "node(%v), active channels %v",
+		info.IdentityPubkey, info.NumActiveChannels)
+}
+
+// checkAuxiliaryFiles ensures that the policy, translations, feature flags,
+// fiat rates and certificates of the peers are valid.
+func checkAuxiliaryFiles(report *checkReport, cfg config) {
+	if cfg.PolicyFile != "" {
+		if _, err := policy.NewEngine(cfg.PolicyFile, nil); err != nil {
+			report.add("policy", checkFailed, "%v", err)
+		} else {
+			report.add("policy", checkOK, "policy file(%v) is valid",
+				cfg.PolicyFile)
+		}
+	}
+
+	if cfg.MessagesFile != "" {
+		if err := locale.NewCatalog().LoadFile(cfg.MessagesFile); err != nil {
+			report.add("messages", checkFailed, "%v", err)
+		} else {
+			report.add("messages", checkOK, "messages file(%v) is valid",
+				cfg.MessagesFile)
+		}
+	}
+
+	for _, value := range cfg.Features {
+		if _, _, err := features.ParseRule(value); err != nil {
+			report.add("feature", checkFailed, "%v", err)
+		}
+	}
+
+	for _, value := range cfg.FiatRates {
+		if _, _, _, err := rpc.ParseFiatRate(value); err != nil {
+			report.add("fiatrate", checkFailed, "%v", err)
+		}
+	}
+
+	for _, value := range cfg.BalanceTolerances {
+		if _, _, err := rpc.ParseBalanceTolerance(value); err != nil {
+			report.add("balancetolerance", checkFailed, "%v", err)
+		}
+	}
+
+	for _, value := range cfg.SelfTestCanaries {
+		if _, _, err := rpc.ParseSelfTestCanary(value); err != nil {
+			report.add("selftestcanary", checkFailed, "%v", err)
+		}
+	}
+
+	for _, value := range cfg.FederationPeers {
+		peer, err := rpc.ParseFederationPeer(value)
+		if err != nil {
+			report.add("federationpeer", checkFailed, "%v", err)
+			continue
+		}
+
+		if peer.TLSCertPath != "" {
+			_, err := credentials.NewClientTLSFromFile(peer.TLSCertPath, "")
+			if err != nil {
+				report.add("federationpeer", checkFailed, "unable to load "+
+					"certificate of peer(%v): %v", peer.Name, err)
+				continue
+			}
+		}
+
+		report.add("federationpeer", checkOK, "peer(%v) at %v", peer.Name,
+			peer.Address)
+	}
+
+	if cfg.AddressProvider != "" && cfg.AddressProviderTLSCert != "" {
+		_, err := credentials.NewClientTLSFromFile(cfg.AddressProviderTLSCert,
+			"")
+		if err != nil {
+			report.add("addressprovider", checkFailed, "unable to load "+
+				"certificate: %v", err)
+		} else {
+			report.add("addressprovider", checkOK, "certificate(%v) is "+
+				"valid", cfg.AddressProviderTLSCert)
+		}
+	}
+}
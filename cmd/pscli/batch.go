@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bitlum/connector/crpc"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+// maxBatchOutputs is the maximum number of the payments which are sent in
+// one transaction, it is the limit of the outputs of SendPayments.
+const maxBatchOutputs = 250
+
+// batchPayment is the payment of the batch file along with the outcome of
+// its validation and sending.
+type batchPayment struct {
+	// row is the number of the line of the payment in the file.
+	row     int
+	address string
+	amount  decimal.Decimal
+	asset   crpc.Asset
+
+	paymentID string
+	err       string
+}
+
+// batch is the payments of the same asset, which are sent in one
+// transaction, or in several ones if there are more than maxBatchOutputs
+// of them.
+type batch struct {
+	asset    crpc.Asset
+	payments []*batchPayment
+	total    decimal.Decimal
+}
+
+// parseBatchAsset returns the asset of the batch payment, only assets
+// which payments could be sent in one transaction are supported.
+func parseBatchAsset(s string) (crpc.Asset, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "btc", "bitcoin":
+		return crpc.Asset_BTC, nil
+	case "bch", "bitcoincash":
+		return crpc.Asset_BCH, nil
+	case "ltc", "litecoin":
+		return crpc.Asset_LTC, nil
+	case "dash":
+		return crpc.Asset_DASH, nil
+	default:
+		return crpc.Asset_ASSET_NONE, errors.Errorf("invalid asset %v, "+
+			"supported assets are: 'btc', 'bch', 'dash', 'ltc'", s)
+	}
+}
+
+// readBatchPayments reads 'address,amount[,asset]' lines of the CSV file,
+// first line is skipped if it is the header, blank lines and lines starting
+// with '#' are skipped as well. Asset of the lines without it is the default
+// one, payments are numbered by their lines in the file. Unreadable file is
+// returned as the error, while invalid values are saved as the errors of
+// their payments, so that all of them are reported at once.
+func readBatchPayments(path string,
+	defaultAsset crpc.Asset) ([]*batchPayment, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Errorf("unable to open file: %v", err)
+	}
+	defer f.Close()
+
+	// Lines are parsed one by one, rather than by the single CSV reader,
+	// so that payments are numbered by the lines of the file, regardless
+	// of the header, blank lines and comments.
+	scanner := bufio.NewScanner(f)
+
+	var payments []*batchPayment
+	for line, first := 1, true; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		reader := csv.NewReader(strings.NewReader(text))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		record, err := reader.Read()
+		if first && err == nil && strings.EqualFold(record[0], "address") {
+			first = false
+			continue
+		}
+		first = false
+
+		payment := &batchPayment{
+			row:   line,
+			asset: defaultAsset,
+		}
+		payments = append(payments, payment)
+
+		if err != nil {
+			payment.err = "line isn't valid CSV: " + err.Error()
+			continue
+		}
+
+		if len(record) < 2 || len(record) > 3 {
+			payment.err = "row should have 2 or 3 fields, got " +
+				strconv.Itoa(len(record))
+			continue
+		}
+
+		payment.address = strings.TrimSpace(record[0])
+		if payment.address == "" {
+			payment.err = "address is missing"
+			continue
+		}
+
+		payment.amount, err = decimal.NewFromString(
+			strings.TrimSpace(record[1]))
+		if err != nil || payment.amount.Sign() <= 0 {
+			payment.err = "amount should be positive number"
+			continue
+		}
+
+		if len(record) == 3 && strings.TrimSpace(record[2]) != "" {
+			payment.asset, err = parseBatchAsset(record[2])
+			if err != nil {
+				payment.err = err.Error()
+				continue
+			}
+		}
+
+		if payment.asset == crpc.Asset_ASSET_NONE {
+			payment.err = "asset is missing"
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Errorf("unable to read file: %v", err)
+	}
+
+	if len(payments) == 0 {
+		return nil, errors.Errorf("file has no payments")
+	}
+
+	return payments, nil
+}
+
+// groupBatches groups the payments by the asset in the order of their
+// first appearance in the file. Payments to the same address in the same
+// asset are marked as invalid, because transaction couldn't have several
+// outputs to the same address.
+func groupBatches(payments []*batchPayment) []*batch {
+	var batches []*batch
+	byAsset := make(map[crpc.Asset]*batch)
+	rows := make(map[crpc.Asset]map[string]int)
+
+	for _, payment := range payments {
+		if payment.err != "" {
+			continue
+		}
+
+		b, ok := byAsset[payment.asset]
+		if !ok {
+			b = &batch{
+				asset: payment.asset,
+				total: decimal.Zero,
+			}
+			byAsset[payment.asset] = b
+			rows[payment.asset] = make(map[string]int)
+			batches = append(batches, b)
+		}
+
+		if row, ok := rows[payment.asset][payment.address]; ok {
+			payment.err = "address is repeated, first at row " +
+				strconv.Itoa(row)
+			continue
+		}
+		rows[payment.asset][payment.address] = payment.row
+
+		b.payments = append(b.payments, payment)
+		b.total = b.total.Add(payment.amount)
+	}
+
+	return batches
+}
+
+// batchPaymentRows returns the header and the rows of the report of the
+// batch payments. Payment is either failed, sent, or only valid if it
+// hasn't been sent yet.
+func batchPaymentRows(payments []*batchPayment) ([]string, [][]string) {
+	header := []string{"row", "address", "amount", "asset", "status",
+		"payment_id", "error"}
+
+	rows := make([][]string, len(payments))
+	for i, payment := range payments {
+		status := "valid"
+		switch {
+		case payment.err != "":
+			status = "failed"
+		case payment.paymentID != "":
+			status = "sent"
+		}
+
+		rows[i] = []string{
+			strconv.Itoa(payment.row),
+			payment.address,
+			payment.amount.String(),
+			payment.asset.String(),
+			status,
+			payment.paymentID,
+			payment.err,
+		}
+	}
+
+	return header, rows
+}
@@ -1,16 +1,47 @@
 package main
 
 import (
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 	"github.com/bitlum/connector/crpc"
+	"github.com/btcsuite/btcutil"
 	"github.com/urfave/cli"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
 )
 
 const (
 	defaultRPCPort     = "9002"
 	defaultRPCHostPort = "localhost:" + defaultRPCPort
+
+	defaultAdminHostPort = "localhost:9003"
+
+	defaultMaxMsgSize = 64 * 1024 * 1024
+
+	defaultAdminTokenFilename = "admin.token"
+
+	defaultMacaroonFilename = "pay.macaroon"
+
+	defaultTLSCertFilename = "server.cert"
+)
+
+var (
+	defaultAdminTokenPath = filepath.Join(btcutil.AppDataDir("connector",
+		false), defaultAdminTokenFilename)
+
+	defaultMacaroonPath = filepath.Join(btcutil.AppDataDir("connector",
+		false), defaultMacaroonFilename)
+
+	defaultTLSCertPath = filepath.Join(btcutil.AppDataDir("connector",
+		false), defaultTLSCertFilename)
 )
 
 func fatal(err error) {
@@ -19,7 +50,7 @@ func fatal(err error) {
 }
 
 func getClient(ctx *cli.Context) (crpc.PayServerClient, func()) {
-	conn := getClientConn(ctx, false)
+	conn := getClientConn(ctx, ctx.GlobalString("rpcserver"), false)
 
 	cleanUp := func() {
 		conn.Close()
@@ -28,13 +59,143 @@ func getClient(ctx *cli.Context) (crpc.PayServerClient, func()) {
 	return crpc.NewPayServerClient(conn), cleanUp
 }
 
-func getClientConn(ctx *cli.Context, skipMacaroons bool) *grpc.ClientConn {
+// adminToken is the per RPC credentials which pass the admin token in the
+// request metadata.
+type adminToken string
+
+// GetRequestMetadata returns metadata which is attached to every request.
+func (t adminToken) GetRequestMetadata(ctx context.Context,
+	uri ...string) (map[string]string, error) {
+	return map[string]string{
+		crpc.AdminTokenMetadataKey: string(t),
+	}, nil
+}
+
+// RequireTransportSecurity returns false, because admin listener might be
+// the UNIX socket, which isn't encrypted.
+func (t adminToken) RequireTransportSecurity() bool {
+	return false
+}
+
+// macaroonCredential is the per RPC credentials which pass the hex encoded
+// macaroon in the request metadata.
+type macaroonCredential string
+
+// GetRequestMetadata returns metadata which is attached to every request.
+func (m macaroonCredential) GetRequestMetadata(ctx context.Context,
+	uri ...string) (map[string]string, error) {
+	return map[string]string{
+		crpc.MacaroonMetadataKey: string(m),
+	}, nil
+}
+
+// RequireTransportSecurity returns false, because listener might be the
+// UNIX socket, which isn't encrypted.
+func (m macaroonCredential) RequireTransportSecurity() bool {
+	return false
+}
+
+// apiKeyCredential is the per RPC credentials which pass the API key in the
+// request metadata.
+type apiKeyCredential string
+
+// GetRequestMetadata returns metadata which is attached to every request.
+func (k apiKeyCredential) GetRequestMetadata(ctx context.Context,
+	uri ...string) (map[string]string, error) {
+	return map[string]string{
+		crpc.APIKeyMetadataKey: string(k),
+	}, nil
+}
+
+// RequireTransportSecurity returns false, because listener might be the
+// UNIX socket, which isn't encrypted.
+func (k apiKeyCredential) RequireTransportSecurity() bool {
+	return false
+}
+
+func getAdminClient(ctx *cli.Context) (crpc.AdminClient, func()) {
+	data, err := ioutil.ReadFile(ctx.GlobalString("admintokenpath"))
+	if err != nil {
+		fatal(fmt.Errorf("unable to read admin token: %v", err))
+	}
+	token := adminToken(strings.TrimSpace(string(data)))
+
+	conn := getClientConn(ctx, ctx.GlobalString("adminserver"), true,
+		grpc.WithPerRPCCredentials(token))
+
+	cleanUp := func() {
+		conn.Close()
+	}
+
+	return crpc.NewAdminClient(conn), cleanUp
+}
+
+func getClientConn(ctx *cli.Context, target string, skipMacaroons bool,
+	extraOpts ...grpc.DialOption) *grpc.ClientConn {
 	// Create a dial options array.
 	opts := []grpc.DialOption{
-		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(ctx.GlobalInt("maxmsgsize")),
+			grpc.UseCompressor(gzip.Name),
+		),
 	}
 
-	conn, err := grpc.Dial(ctx.GlobalString("rpcserver"), opts...)
+	// UNIX socket address is given in form of "unix:///path/to/socket".
+	// Connections through UNIX sockets aren't encrypted by the server,
+	// others are verified with the server TLS certificate.
+	switch {
+	case strings.HasPrefix(target, "unix:"):
+		target = strings.TrimPrefix(strings.TrimPrefix(target, "unix:"), "//")
+		opts = append(opts, grpc.WithInsecure(), grpc.WithDialer(
+			func(addr string, timeout time.Duration) (net.Conn, error) {
+				return net.DialTimeout("unix", addr, timeout)
+			}))
+
+	case ctx.GlobalBool("notls"):
+		opts = append(opts, grpc.WithInsecure())
+
+	default:
+		creds, err := credentials.NewClientTLSFromFile(
+			ctx.GlobalString("tlscertpath"), "")
+		if err != nil {
+			fatal(fmt.Errorf("unable to read TLS certificate: %v", err))
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	}
+
+	// Admin methods are authorized by the admin token, other methods
+	// require macaroon unless it is disabled on the server.
+	if !skipMacaroons && !ctx.GlobalBool("nomacaroons") {
+		data, err := ioutil.ReadFile(ctx.GlobalString("macaroonpath"))
+		if err != nil {
+			fatal(fmt.Errorf("unable to read macaroon: %v", err))
+		}
+
+		opts = append(opts, grpc.WithPerRPCCredentials(
+			macaroonCredential(hex.EncodeToString(data))))
+	}
+
+	// API key is passed along with the other credentials, it is checked
+	// only if API keys are enabled on the server.
+	if apiKey := ctx.GlobalString("apikey"); apiKey != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(
+			apiKeyCredential(apiKey)))
+	}
+
+	// Generated clients are calling the legacy unversioned services, calls
+	// are redirected to the versioned ones by the interceptors.
+	if ctx.GlobalBool("verbose") {
+		opts = append(opts, verboseInterceptors(target)...)
+	} else {
+		opts = append(opts,
+			grpc.WithUnaryInterceptor(crpc.VersionUnaryClientInterceptor()),
+			grpc.WithStreamInterceptor(crpc.VersionStreamClientInterceptor()),
+		)
+	}
+
+	opts = append(opts, extraOpts...)
+
+	conn, err := grpc.Dial(target, opts...)
 	if err != nil {
 		fatal(err)
 	}
@@ -51,18 +212,164 @@ func main() {
 		cli.StringFlag{
 			Name:  "rpcserver",
 			Value: defaultRPCHostPort,
-			Usage: "host:port of payserver",
+			Usage: "host:port or unix:///path/to/socket of payserver",
+		},
+		cli.StringFlag{
+			Name:  "adminserver",
+			Value: defaultAdminHostPort,
+			Usage: "host:port or unix:///path/to/socket of payserver " +
+				"operator endpoint, which serves admin commands",
+		},
+		cli.IntFlag{
+			Name:  "maxmsgsize",
+			Value: defaultMaxMsgSize,
+			Usage: "maximum size in bytes of the message received from payserver",
+		},
+		cli.StringFlag{
+			Name:  "admintokenpath",
+			Value: defaultAdminTokenPath,
+			Usage: "path to the admin token which is required by the operator commands",
 		},
+		cli.StringFlag{
+			Name:  "macaroonpath",
+			Value: defaultMacaroonPath,
+			Usage: "path to the macaroon which authorizes calls of the payment commands",
+		},
+		cli.BoolFlag{
+			Name:  "nomacaroons",
+			Usage: "disable use of macaroons",
+		},
+		cli.StringFlag{
+			Name:  "tlscertpath",
+			Value: defaultTLSCertPath,
+			Usage: "path to the TLS certificate which is used to verify payserver",
+		},
+		cli.BoolFlag{
+			Name:  "notls",
+			Usage: "disable TLS, if it is disabled on payserver",
+		},
+		cli.StringFlag{
+			Name:  "apikey",
+			Usage: "API key which authorizes calls, if API keys are enabled on payserver",
+		},
+		cli.StringFlag{
+			Name:  "jsonnames",
+			Value: string(crpc.CamelCaseNames),
+			Usage: "naming of the fields in the printed responses, " +
+				"'camel' (paymentId) or 'snake' (payment_id)",
+		},
+		cli.StringFlag{
+			Name:  "output",
+			Value: string(jsonOutput),
+			Usage: "format of the printed responses, 'json' (indented), " +
+				"'raw-json' (compact, one response per line), 'table' " +
+				"or 'csv'. Table and CSV contain the row per element of " +
+				"the listed items, e.g. payments",
+		},
+		cli.BoolFlag{
+			Name: "verbose",
+			Usage: "print request, server, latency and returned metadata " +
+				"of every call to stderr",
+		},
+	}
+	app.Before = func(ctx *cli.Context) error {
+		switch names := crpc.JSONNames(ctx.GlobalString("jsonnames")); names {
+		case crpc.CamelCaseNames, crpc.SnakeCaseNames:
+			jsonNames = names
+		default:
+			return fmt.Errorf("invalid json names %v, supported names "+
+				"are: 'camel' and 'snake'", names)
+		}
+
+		format, err := parseOutputFormat(ctx.GlobalString("output"))
+		if err != nil {
+			return err
+		}
+		printFormat = format
+
+		return nil
 	}
 	app.Commands = []cli.Command{
 		createReceiptCommand,
+		newAddressCommand,
 		validateReceiptCommand,
+		listReceiptsCommand,
+		receiptByIDCommand,
+		attachRefundAddressCommand,
+		subscribeReceiptsCommand,
 		balanceCommand,
+		balanceHistoryCommand,
+		subscribeBalanceCommand,
 		estimateFeeCommand,
+		quotePaymentCommand,
+		quoteReceiptCommand,
 		sendPaymentCommand,
+		sendPaymentsCommand,
+		sendBatchCommand,
+		sendTimeLockedPaymentCommand,
+		listTimeLocksCommand,
 		paymentByIDCommand,
+		labelPaymentCommand,
+		refundPaymentCommand,
+		cancelPaymentCommand,
+		paymentProofCommand,
+		transferFundsCommand,
+		createAccountCommand,
+		listAccountsCommand,
+		listDepositAddressesCommand,
+		accountStatementCommand,
 		paymentByReceiptCommand,
 		listPaymentsCommand,
+		exportCommand,
+		subscribePaymentsCommand,
+		watchCommand,
+		injectTestPaymentCommand,
+		faucetCommand,
+		setPayeeCommand,
+		removePayeeCommand,
+		listPayeesCommand,
+		setBrandingCommand,
+		removeBrandingCommand,
+		getBrandingCommand,
+		addWatchAddressCommand,
+		importWatchAddressesCommand,
+		removeWatchAddressCommand,
+		redeliverWatchEventsCommand,
+		exportAddressBookCommand,
+		importAddressBookCommand,
+		listWatchAddressesCommand,
+		listWatchEventsCommand,
+		getPublicKeysCommand,
+		getInfoCommand,
+		healthCheckCommand,
+		assetsCommand,
+		syncUnspentCommand,
+		getUnspentSyncStatusCommand,
+		listUnspentCommand,
+		transactionByHashCommand,
+		isOurAddressCommand,
+		signMessageCommand,
+		verifyMessageCommand,
+		transferToPeerCommand,
+		listFederationPositionsCommand,
+		settleFederationCommand,
+		sweepFundsCommand,
+		feeReportCommand,
+		pauseWithdrawalsCommand,
+		resumeWithdrawalsCommand,
+		listWithdrawalPausesCommand,
+		balanceInvariantsCommand,
+		resetSendsBreakerCommand,
+		runSelfTestCommand,
+		setFeatureFlagCommand,
+		listFeatureFlagsCommand,
+		diagnoseCommand,
+		createAPIKeyCommand,
+		revokeAPIKeyCommand,
+		listAPIKeysCommand,
+		quarantinePaymentCommand,
+		releasePaymentCommand,
+		returnPaymentCommand,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/protobuf/proto"
+)
+
+// outputFormat is the format of the printed responses.
+type outputFormat string
+
+const (
+	// jsonOutput prints responses as the indented JSON.
+	jsonOutput outputFormat = "json"
+
+	// rawJSONOutput prints every response as the compact JSON on the single
+	// line, so that stream of responses could be read as JSON lines.
+	rawJSONOutput outputFormat = "raw-json"
+
+	// tableOutput prints responses as the aligned table, which is meant to
+	// be read in the terminal.
+	tableOutput outputFormat = "table"
+
+	// csvOutput prints responses as CSV with the header, which is meant to
+	// be read by the scripts.
+	csvOutput outputFormat = "csv"
+)
+
+// printFormat is the format of the printed responses, it is set by the
+// global flag.
+var printFormat = jsonOutput
+
+// printedHeader is the header of the last printed CSV, so that header is
+// printed only once for the stream of the responses of the same type.
+var printedHeader []string
+
+// parseOutputFormat returns the output format given in the global flag.
+func parseOutputFormat(s string) (outputFormat, error) {
+	switch format := outputFormat(strings.ToLower(s)); format {
+	case jsonOutput, rawJSONOutput, tableOutput, csvOutput:
+		return format, nil
+	default:
+		return "", errors.Errorf("invalid output format %v, supported "+
+			"formats are: 'json', 'raw-json', 'table' and 'csv'", s)
+	}
+}
+
+// printResp prints the response in the output format. Table and CSV
+// contain the row per element of the first list of the response, e.g.
+// payments of the ListPayments, or the single row of the response itself
+// if it hasn't any list. Nested messages and lists are printed in the
+// cells as the compact JSON.
+func printResp(resp proto.Message) {
+	jsonMarshaler := jsonNames.Marshaler()
+	jsonMarshaler.EmitDefaults = true
+	if printFormat == jsonOutput {
+		jsonMarshaler.Indent = "    "
+	}
+
+	jsonStr, err := jsonMarshaler.MarshalToString(resp)
+	if err != nil {
+		fmt.Println("unable to decode response: ", err)
+		return
+	}
+
+	if printFormat == jsonOutput || printFormat == rawJSONOutput {
+		fmt.Println(jsonStr)
+		return
+	}
+
+	header, rows, err := tabulate(resp, []byte(jsonStr))
+	if err != nil {
+		fmt.Println("unable to tabulate response: ", err)
+		return
+	}
+
+	switch printFormat {
+	case tableOutput:
+		err = writeTable(os.Stdout, header, rows, listField(resp) == "")
+	case csvOutput:
+		err = writeCSV(os.Stdout, header, rows)
+	}
+	if err != nil {
+		fmt.Println("unable to print response: ", err)
+	}
+}
+
+// orderedObject is the JSON object which keeps the order of its fields,
+// which is the order of the fields in the proto message.
+type orderedObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// decodeObject decodes the JSON object keeping the order of its fields,
+// values are left encoded.
+func decodeObject(data []byte) (*orderedObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, errors.Errorf("response isn't an object")
+	}
+
+	obj := &orderedObject{
+		values: make(map[string]json.RawMessage),
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return nil, errors.Errorf("invalid key %v", token)
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		obj.keys = append(obj.keys, key)
+		obj.values[key] = value
+	}
+
+	return obj, nil
+}
+
+// listField returns the name under which the first list of the messages of
+// the response is encoded, empty if response hasn't such list.
+func listField(resp proto.Message) string {
+	t := reflect.TypeOf(resp)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+	t = t.Elem()
+
+	messageType := reflect.TypeOf((*proto.Message)(nil)).Elem()
+	props := proto.GetProperties(t)
+	for i, prop := range props.Prop {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Slice ||
+			!field.Type.Elem().Implements(messageType) {
+			continue
+		}
+
+		if jsonNames.Marshaler().OrigName {
+			return prop.OrigName
+		}
+		return prop.JSONName
+	}
+
+	return ""
+}
+
+// tabulate converts the encoded response into the header and the rows.
+// Columns are the fields of the rows in the order of their appearance.
+func tabulate(resp proto.Message, data []byte) ([]string, [][]string,
+	error) {
+
+	obj, err := decodeObject(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	objects := []*orderedObject{obj}
+	if name := listField(resp); name != "" {
+		var items []json.RawMessage
+		if err := json.Unmarshal(obj.values[name], &items); err != nil {
+			return nil, nil, err
+		}
+
+		objects = make([]*orderedObject, 0, len(items))
+		for _, item := range items {
+			itemObj, err := decodeObject(item)
+			if err != nil {
+				return nil, nil, err
+			}
+			objects = append(objects, itemObj)
+		}
+	}
+
+	var header []string
+	columns := make(map[string]int)
+	for _, obj := range objects {
+		for _, key := range obj.keys {
+			if _, ok := columns[key]; !ok {
+				columns[key] = len(header)
+				header = append(header, key)
+			}
+		}
+	}
+
+	rows := make([][]string, 0, len(objects))
+	for _, obj := range objects {
+		row := make([]string, len(header))
+		for _, key := range obj.keys {
+			row[columns[key]] = formatCell(obj.values[key])
+		}
+		rows = append(rows, row)
+	}
+
+	return header, rows, nil
+}
+
+// formatCell returns the text of the encoded value, strings are unquoted,
+// nested messages and lists are left as the compact JSON.
+func formatCell(value json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s
+	}
+
+	if string(value) == "null" {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return string(value)
+	}
+	return buf.String()
+}
+
+// writeTable writes the rows as the aligned table. Single response which
+// isn't a list is written vertically as the table of its fields and
+// values, so that wide messages stay readable.
+func writeTable(w io.Writer, header []string, rows [][]string,
+	vertical bool) error {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if vertical && len(rows) == 1 {
+		for i, key := range header {
+			fmt.Fprintf(tw, "%v\t%v\n", strings.ToUpper(key),
+				tableCell(rows[0][i]))
+		}
+		return tw.Flush()
+	}
+
+	if len(header) == 0 {
+		return nil
+	}
+
+	columns := make([]string, len(header))
+	for i, key := range header {
+		columns[i] = strings.ToUpper(key)
+	}
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = tableCell(cell)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// tableCell returns the cell which doesn't break the alignment of the
+// table.
+func tableCell(cell string) string {
+	if cell == "" {
+		return "-"
+	}
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(cell)
+}
+
+// writeCSV writes the rows as CSV. Header is skipped if it is the same as
+// the one of the previous response, so that stream of the responses is
+// written as the single CSV.
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	if len(header) == 0 {
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	if !reflect.DeepEqual(header, printedHeader) {
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		printedHeader = header
+	}
+
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+// printRows prints the rows, which aren't the response of the server, e.g.
+// report of the command, in the output format. JSON is printed as the
+// list of the objects with the header as their keys.
+func printRows(header []string, rows [][]string) {
+	var err error
+	switch printFormat {
+	case jsonOutput, rawJSONOutput:
+		objects := make([]string, len(rows))
+		for i, row := range rows {
+			fields := make([]string, len(header))
+			for j, key := range header {
+				k, _ := json.Marshal(key)
+				v, _ := json.Marshal(row[j])
+				fields[j] = string(k) + ":" + string(v)
+			}
+			objects[i] = "{" + strings.Join(fields, ",") + "}"
+		}
+
+		data := []byte("[" + strings.Join(objects, ",") + "]")
+		if printFormat == jsonOutput {
+			var buf bytes.Buffer
+			if err = json.Indent(&buf, data, "", "    "); err == nil {
+				data = buf.Bytes()
+			}
+		}
+		fmt.Println(string(data))
+	case tableOutput:
+		err = writeTable(os.Stdout, header, rows, false)
+	case csvOutput:
+		err = writeCSV(os.Stdout, header, rows)
+	}
+	if err != nil {
+		fmt.Println("unable to print rows: ", err)
+	}
+}
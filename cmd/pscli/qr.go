@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bitlum/connector/crpc"
+	"github.com/go-errors/errors"
+	"github.com/skip2/go-qrcode"
+)
+
+// qrImageSize is the size in pixels of the written QR code image.
+const qrImageSize = 256
+
+// uriSchemes are the schemes of the payment URIs of the blockchain
+// addresses, which are understood by the wallets.
+var uriSchemes = map[crpc.Asset]string{
+	crpc.Asset_BTC:  "bitcoin",
+	crpc.Asset_BCH:  "bitcoincash",
+	crpc.Asset_LTC:  "litecoin",
+	crpc.Asset_DASH: "dash",
+}
+
+// paymentURI returns the content of the QR code of the receipt. Blockchain
+// address is encoded as the payment URI along with the amount, so that
+// wallet fills it in. Lightning invoice is upper-cased, so that it is
+// encoded more compactly.
+func paymentURI(asset crpc.Asset, media crpc.Media, receipt,
+	amount string) string {
+
+	if media == crpc.Media_LIGHTNING {
+		return strings.ToUpper("lightning:" + receipt)
+	}
+
+	scheme, ok := uriSchemes[asset]
+	if !ok {
+		return receipt
+	}
+
+	uri := receipt
+	if !strings.HasPrefix(receipt, scheme+":") {
+		uri = scheme + ":" + receipt
+	}
+
+	if amount != "" {
+		uri += "?amount=" + amount
+	}
+
+	return uri
+}
+
+// printQR prints the QR code of the content in the terminal. Every line
+// carries two rows of the modules, light modules are printed as blocks,
+// so that code is scanned from the terminal with the dark background.
+func printQR(w io.Writer, content string) error {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return errors.Errorf("unable to encode QR code: %v", err)
+	}
+
+	bitmap := code.Bitmap()
+	for y := 0; y < len(bitmap); y += 2 {
+		var line strings.Builder
+		for x := range bitmap[y] {
+			top := !bitmap[y][x]
+			bottom := y+1 < len(bitmap) && !bitmap[y+1][x]
+
+			switch {
+			case top && bottom:
+				line.WriteString("█")
+			case top:
+				line.WriteString("▀")
+			case bottom:
+				line.WriteString("▄")
+			default:
+				line.WriteString(" ")
+			}
+		}
+		fmt.Fprintln(w, line.String())
+	}
+
+	return nil
+}
+
+// writeQR writes the QR code of the content as PNG image.
+func writeQR(path, content string) error {
+	err := qrcode.WriteFile(content, qrcode.Medium, qrImageSize, path)
+	if err != nil {
+		return errors.Errorf("unable to write QR code: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitlum/connector/crpc"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// verboseInterceptors returns the dial options which print the gRPC
+// exchange with the server to stderr, so that it is not mixed with the
+// response printed to stdout. Methods of the versioned services are
+// called, in the same way as without verbose output.
+func verboseInterceptors(target string) []grpc.DialOption {
+	unary := func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+
+		method = crpc.VersionedMethod(method)
+
+		var header, trailer metadata.MD
+		opts = append(opts, grpc.Header(&header), grpc.Trailer(&trailer))
+
+		printVerbose("server: %v", target)
+		printVerbose("method: %v", method)
+		printVerbose("request: %v", verboseMessage(req))
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		printVerbose("latency: %v", time.Since(start))
+
+		printVerboseMetadata("header", header)
+		printVerboseMetadata("trailer", trailer)
+		printVerbose("status: %v", status.Convert(err).Code())
+
+		return err
+	}
+
+	stream := func(ctx context.Context, desc *grpc.StreamDesc,
+		cc *grpc.ClientConn, method string, streamer grpc.Streamer,
+		opts ...grpc.CallOption) (grpc.ClientStream, error) {
+
+		method = crpc.VersionedMethod(method)
+
+		printVerbose("server: %v", target)
+		printVerbose("method: %v", method)
+
+		start := time.Now()
+		clientStream, err := streamer(ctx, desc, cc, method, opts...)
+		printVerbose("latency: %v", time.Since(start))
+		if err != nil {
+			printVerbose("status: %v", status.Convert(err).Code())
+		}
+
+		return clientStream, err
+	}
+
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(unary),
+		grpc.WithStreamInterceptor(stream),
+	}
+}
+
+// verboseMessage returns the compact JSON form of the request.
+func verboseMessage(msg interface{}) string {
+	protoMsg, ok := msg.(proto.Message)
+	if !ok {
+		return fmt.Sprintf("%v", msg)
+	}
+
+	jsonStr, err := jsonNames.Marshaler().MarshalToString(protoMsg)
+	if err != nil {
+		return fmt.Sprintf("unable to encode request: %v", err)
+	}
+
+	return jsonStr
+}
+
+// printVerboseMetadata prints the metadata which has been returned by the
+// server, e.g. warnings, in the stable order of the keys.
+func printVerboseMetadata(name string, md metadata.MD) {
+	keys := make([]string, 0, len(md))
+	for key := range md {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		printVerbose("%v: %v=%v", name, key, strings.Join(md[key], ", "))
+	}
+}
+
+func printVerbose(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "[pscli] "+format+"\n", args...)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitlum/connector/crpc"
+	"github.com/go-errors/errors"
+)
+
+// watchAddressEntry is the entry of the JSON file with the addresses to
+// watch.
+type watchAddressEntry struct {
+	Address string `json:"address"`
+	Account string `json:"account"`
+	Status  string `json:"status"`
+}
+
+// readWatchAddresses reads addresses from CSV or JSON file, format is
+// chosen by the file extension.
+func readWatchAddresses(path string) ([]*crpc.WatchAddress, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Errorf("unable to open file: %v", err)
+	}
+	defer f.Close()
+
+	var entries []watchAddressEntry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		entries, err = readWatchAddressesCSV(f)
+	case ".json":
+		err = json.NewDecoder(f).Decode(&entries)
+	default:
+		return nil, errors.Errorf("unknown format of the file %v, "+
+			"supported extensions are: '.csv', '.json'", path)
+	}
+	if err != nil {
+		return nil, errors.Errorf("unable to read file: %v", err)
+	}
+
+	addresses := make([]*crpc.WatchAddress, len(entries))
+	for i, entry := range entries {
+		if entry.Address == "" {
+			return nil, errors.Errorf("address of entry(%v) is missing", i)
+		}
+
+		var inactive bool
+		switch strings.ToLower(strings.TrimSpace(entry.Status)) {
+		case "", "active":
+		case "inactive":
+			inactive = true
+		default:
+			return nil, errors.Errorf("invalid status %v of entry(%v), "+
+				"supported statuses are: 'active', 'inactive'",
+				entry.Status, i)
+		}
+
+		addresses[i] = &crpc.WatchAddress{
+			Address:  strings.TrimSpace(entry.Address),
+			Account:  strings.TrimSpace(entry.Account),
+			Inactive: inactive,
+		}
+	}
+
+	return addresses, nil
+}
+
+// readWatchAddressesCSV reads 'address,account[,status]' lines, first line
+// is skipped if it is the header.
+func readWatchAddressesCSV(r io.Reader) ([]watchAddressEntry, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) > 0 && strings.EqualFold(records[0][0], "address") {
+		records = records[1:]
+	}
+
+	entries := make([]watchAddressEntry, len(records))
+	for i, record := range records {
+		if len(record) < 2 || len(record) > 3 {
+			return nil, errors.Errorf("entry(%v) should have 2 or 3 "+
+				"fields, got %v", i, len(record))
+		}
+
+		entries[i] = watchAddressEntry{
+			Address: record[0],
+			Account: record[1],
+		}
+
+		if len(record) == 3 {
+			entries[i].Status = record[2]
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// DialFunc is used to establish outgoing connections.
+type DialFunc func(network, address string) (net.Conn, error)
+
+// NewDialer returns function which establishes connections to the .onion
+// hosts through the SOCKS5 proxy if proxy address is specified, and to the
+// other hosts directly, so that local daemons are not reached through Tor.
+// Onion host names are resolved by the proxy.
+func NewDialer(proxyAddress string, timeout time.Duration) (DialFunc, error) {
+	direct := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	if proxyAddress == "" {
+		return direct.Dial, nil
+	}
+
+	dialer, err := proxy.SOCKS5("tcp", proxyAddress, nil, direct)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if IsOnionHost(host) {
+			return dialer.Dial(network, address)
+		}
+
+		return direct.Dial(network, address)
+	}, nil
+}
+
+// IsOnionHost returns true if host is the Tor hidden service address.
+func IsOnionHost(host string) bool {
+	return strings.HasSuffix(strings.ToLower(host), ".onion")
+}
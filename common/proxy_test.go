@@ -0,0 +1,39 @@
+package common
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDialerBypassesProxy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	// Proxy is unreachable, so connection could be established only
+	// directly.
+	dial, err := NewDialer("127.0.0.1:1", time.Second)
+	if err != nil {
+		t.Fatalf("unable to create dialer: %v", err)
+	}
+
+	conn, err := dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("local host should be dialed directly: %v", err)
+	}
+	conn.Close()
+
+	if _, err := dial("tcp", "example.onion:80"); err == nil {
+		t.Fatalf("onion host should be dialed through the proxy")
+	}
+}
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"sync"
+
+	"github.com/go-errors/errors"
+)
+
+// RunWorkers executes job for every index in range [0, n) using the given
+// number of concurrent workers, and returns first occurred error. Jobs
+// which are not yet started are abandoned once quit is closed.
+func RunWorkers(workers, n int, quit <-chan struct{},
+	job func(i int) error) error {
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	jobs := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				if err := job(i); err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-quit:
+			close(jobs)
+			wg.Wait()
+			return errors.Errorf("workers quit")
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,36 @@
+package common
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-errors/errors"
+)
+
+func TestRunWorkers(t *testing.T) {
+	var done int32
+	err := RunWorkers(3, 10, nil, func(i int) error {
+		atomic.AddInt32(&done, 1)
+		if i == 5 {
+			return errors.New("job failed")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("error of the failed job should be returned")
+	}
+
+	if done != 10 {
+		t.Fatalf("all jobs should be executed, executed: %v", done)
+	}
+
+	quit := make(chan struct{})
+	close(quit)
+
+	err = RunWorkers(0, 1, quit, func(i int) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("workers should quit")
+	}
+}
@@ -2,13 +2,18 @@ package main
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"log"
 
+	"github.com/bitlum/connector/common"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/go-flags"
 )
@@ -17,18 +22,65 @@ const (
 	defaultRPCHost = "0.0.0.0"
 	defaultRPCPort = "9002"
 
+	// defaultRPCAdminListen is the address of the operator RPC endpoint if
+	// it isn't specified, Admin service is reachable only locally.
+	defaultRPCAdminListen = "127.0.0.1:9003"
+
+	// defaultRPCSocketMode is the default permissions of the UNIX socket
+	// files of the RPC listeners.
+	defaultRPCSocketMode = "0660"
+
+	// defaultRPCMaxMsgSize is the maximum size of the gRPC message, it is
+	// higher than gRPC default 4MB, so that big lists could be returned.
+	defaultRPCMaxMsgSize = 64 * 1024 * 1024
+
+	// defaultRESTJSONNames is the naming of the fields in the REST gateway
+	// responses, it matches the names in the proto file.
+	defaultRESTJSONNames = "snake"
+
+	// defaultRESTCacheMaxAge is the time for which clients could reuse the
+	// immutable REST responses.
+	defaultRESTCacheMaxAge = 5 * time.Minute
+
 	defaultPrometheusEndpointHost = "0.0.0.0"
 	defaultPrometheusEndpointPort = "9999"
 
 	defaultTLSCertFilename = "server.cert"
 	defaultTLSKeyFilename  = "server.key"
 
+	defaultAdminTokenFilename = "admin.token"
+
+	defaultIdentityKeyFilename = "identity.key"
+
 	defaultLogDirname  = "logs"
 	defaultLogFilename = "connector.log"
 	defaultLogLevel    = "info"
 
 	defaultNet = "simnet"
 
+	// defaultFiatCurrency is the currency in which payments are valued.
+	defaultFiatCurrency = "USD"
+
+	// defaultBalanceSnapshotInterval is the period with which balances are
+	// saved for the balance history.
+	defaultBalanceSnapshotInterval = 10 * time.Minute
+
+	// defaultInvoiceRegenerationInterval is the period with which lightning
+	// network invoices are checked to be replaced.
+	defaultInvoiceRegenerationInterval = time.Minute
+
+	// defaultAddressProviderTimeout is the maximum time to wait for the
+	// address from the external address provider.
+	defaultAddressProviderTimeout = 10 * time.Second
+
+	// defaultFederationSettleInterval is the period with which positions
+	// with the federation peers are settled.
+	defaultFederationSettleInterval = time.Hour
+
+	// defaultFederationTimeout is the maximum time to wait for the response
+	// of the federation peer.
+	defaultFederationTimeout = 30 * time.Second
+
 	defaultConfigFilename = "connector.conf"
 )
 
@@ -38,6 +90,10 @@ var (
 	defaultTLSCertPath = filepath.Join(homeDir, defaultTLSCertFilename)
 	defaultTLSKeyPath  = filepath.Join(homeDir, defaultTLSKeyFilename)
 	defaultLogDir      = filepath.Join(homeDir, defaultLogDirname)
+
+	defaultAdminTokenPath = filepath.Join(homeDir, defaultAdminTokenFilename)
+
+	defaultIdentityKeyPath = filepath.Join(homeDir, defaultIdentityKeyFilename)
 )
 
 type prometheusConfig struct {
@@ -52,12 +108,49 @@ type prometheusConfig struct {
 type config struct {
 	ShowVersion bool `long:"version" description:"Display version information and exit"`
 
-	TLSCertPath string `long:"tlscertpath" description:"Path to TLS certificate which is used to encrypt RPC endpoint"`
-	TLSKeyPath  string `long:"tlskeypath" description:"Path to TLS private key which is used to encrypt RPC endpoint"`
+	TLSCertPath     string   `long:"tlscertpath" description:"Path to TLS certificate which is used to encrypt RPC endpoint. Self-signed certificate is generated if it doesn't exist, and rotated on start if it is close to expiration"`
+	TLSKeyPath      string   `long:"tlskeypath" description:"Path to TLS private key which is used to encrypt RPC endpoint"`
+	TLSExtraIPs     []string `long:"tlsextraip" description:"IP address which is added to the generated certificate along with the loopback ones, certificate isn't valid for other addresses of the host. Could be specified multiple times"`
+	TLSExtraDomains []string `long:"tlsextradomain" description:"Domain which is added to the generated certificate along with localhost, could be specified multiple times"`
+	NoTLS           bool     `long:"notls" description:"Disable TLS encryption of the RPC endpoint, e.g. if it is terminated by the proxy"`
 
 	RPCHost string `long:"rpchost" description:"The host of the RPC endpoint"`
 	RPCPort string `long:"rpcport" description:"The port of the RPC endpoint"`
 
+	RPCListen      []string `long:"rpclisten" description:"Additional address on which RPC endpoint is listening, either host:port or unix:///path/to/socket, could be specified multiple times"`
+	RPCAdminListen []string `long:"rpcadminlisten" description:"Address of the operator RPC endpoint, either host:port or unix:///path/to/socket, could be specified multiple times. Admin service is served only through these addresses, by default it is 127.0.0.1:9003"`
+	RPCSocketMode  string   `long:"rpcsocketmode" description:"Permissions of the UNIX socket files of the RPC endpoints in octal form"`
+
+	// rpcSocketMode is the parsed permissions of the UNIX socket files.
+	rpcSocketMode os.FileMode
+
+	AdminTokenPath string `long:"admintokenpath" description:"Path to the file with the token which is required to call Admin service methods, token is generated if file doesn't exist"`
+
+	MacaroonDir string `long:"macaroondir" description:"Directory with the root key and the default macaroons (pay, receive, readonly) which authorize calls of the PayServer methods, they are generated if don't exist"`
+	NoMacaroons bool   `long:"nomacaroons" description:"Disable macaroon authentication of the PayServer methods"`
+
+	APIKeys bool `long:"apikeys" description:"Require API key with the appropriate scope to call PayServer methods, keys are managed through the Admin service. Could be used along with macaroons or instead of them with nomacaroons"`
+
+	IdentityKeyPath string `long:"identitykeypath" description:"Path to the server identity key, which signs webhook bodies and optionally REST responses, key is generated if file doesn't exist. Public key is returned by the GetPublicKeys method"`
+	SignResponses   bool   `long:"signresponses" description:"Sign REST gateway responses with the server identity key, signature and key id are passed in the X-Signature and X-Signature-Key-Id headers"`
+
+	RESTListen         []string      `long:"restlisten" description:"Address host:port on which REST/JSON gateway to the RPC endpoint and WebSocket payment events endpoint (/v1/events) are listening, could be specified multiple times. Gateway is disabled if not specified"`
+	RESTAllowedOrigins []string      `long:"restallowedorigin" description:"Origin of the browser page, e.g. https://dashboard.example.com, which is allowed to connect to the WebSocket payment events endpoint, pages of the gateway host are always allowed. Could be specified multiple times"`
+	RESTJSONNames      string        `long:"restjsonnames" description:"Naming of the message fields in the REST gateway responses and WebSocket payment events, snake case as declared in the proto file (payment_id) or lower camel case of the proto3 JSON mapping (paymentId). Requests are accepted with either naming" choice:"snake" choice:"camel"`
+	RESTCacheMaxAge    time.Duration `long:"restcachemaxage" description:"Time for which clients could reuse REST responses of the immutable resources, e.g. decoded lightning invoices, without asking the gateway. Other responses are revalidated by ETag. Every response is revalidated if it is zero"`
+
+	RPCMaxMsgSize int `long:"rpcmaxmsgsize" description:"Maximum size in bytes of the gRPC message which could be received or sent by the RPC endpoint"`
+
+	NoReflection bool `long:"noreflection" description:"Disable gRPC server reflection, through which tools like grpcurl discover the services and messages of the RPC endpoint"`
+
+	RPCLatencyBudgets []string `long:"rpclatencybudget" description:"Latency budget of the RPC method in form of method:duration (e.g. CreateReceipt:500ms), or just duration for the methods without own budget, which is 1s by default. Calls exceeding the budget are logged as slow, could be specified multiple times"`
+
+	RPCRateLimits []string `long:"rpcratelimit" description:"Rate limit of the SendPayment or CreateReceipt method per caller (API key, or peer address if API keys are disabled) in form of method[/asset][@apikeyid]=requests/period, e.g. SendPayment/BTC=10/1m. Limit for the API key takes precedence over the one for the asset, calls are not limited if no rule is matching. Could be specified multiple times"`
+
+	FiatRates []string `long:"fiatrate" description:"Price of the asset in the fiat currency, which is used by QuoteReceipt and to value the payments, in form of asset/currency=price, e.g. BTC/USD=6500. Could be specified multiple times"`
+
+	FiatCurrency string `long:"fiatcurrency" description:"Currency in which the value of the payment is recorded at the moment of its completion, price is taken from the fiatrate of the asset. Payments of the assets without such price are not valued"`
+
 	Network string `long:"network" description:"The network of the daemon to which connector is connecting" choice:"simnet" choice:"testnet" choice:"mainnet"`
 
 	ConfigFile string `long:"config" description:"Path to configuration file"`
@@ -75,6 +168,43 @@ type config struct {
 	Ethereum         *GethConfig     `group:"ethereum" namespace:"ethereum"`
 
 	DataDir string `long:"datadir" description:"Path to data directory"`
+
+	WatchWebhook string `long:"watchwebhook" secret:"true" description:"URL on which events about activity of the watched addresses are posted as JSON. As it might carry the credentials, it could be given in the CONNECTOR_WATCHWEBHOOK environment variable, or in the file which path is given in CONNECTOR_WATCHWEBHOOK_FILE"`
+
+	PreSendHook  string        `long:"presendhook" secret:"true" description:"URL on which outgoing payment is posted as JSON before it is sent, or path to the executable which receives it on stdin. Payment is sent only if hook responds with 2xx status or exits with zero code, otherwise it is rejected with the response as the reason. Could be given in the CONNECTOR_PRESENDHOOK environment variable, or in the file which path is given in CONNECTOR_PRESENDHOOK_FILE"`
+	PostSendHook string        `long:"postsendhook" secret:"true" description:"URL on which outgoing payment is posted as JSON after it is completed or failed, or path to the executable which receives it on stdin. Failures of the hook are only logged. Could be given in the CONNECTOR_POSTSENDHOOK environment variable, or in the file which path is given in CONNECTOR_POSTSENDHOOK_FILE"`
+	HookTimeout  time.Duration `long:"hooktimeout" description:"Maximum time to wait for the pre-send and post-send hooks, pre-send hook which hasn't responded in time rejects the payment"`
+	PolicyFile   string        `long:"policyfile" description:"Path to the JSON send policy, list of rules with allow, deny or hold action which are matched against every outgoing payment by method, asset, media, tenant, account, destination, amount and UTC time, the first matching rule decides. File is reloaded once it is modified, invalid file is ignored"`
+
+	Proxy string `long:"proxy" description:"Address of the SOCKS5 proxy (e.g. Tor) through which connections to the .onion daemons and webhook are established, other hosts are reached directly"`
+
+	BalanceSnapshotInterval time.Duration `long:"balancesnapshotinterval" description:"Period with which balances of the assets are saved, they are returned by the BalanceHistory method. Snapshots are not taken if it is zero"`
+
+	BalanceCheckInterval time.Duration `long:"balancecheckinterval" description:"Period with which total of the ledger, i.e. incoming payments minus outgoing payments with fees, is compared with the funds held on-chain and in the lightning network. Once holdings fall short of the ledger by more than the tolerance, all outgoing payments are suspended until ResetSendsBreaker method is called. Invariants are not checked if it is zero"`
+	BalanceTolerances    []string      `long:"balancetolerance" description:"Shortfall of the holdings of the asset which is tolerated by the balance check, in form of asset=amount, e.g. BTC=0.001. Any shortfall is not tolerated if it isn't specified, could be specified multiple times"`
+
+	SelfTest         bool     `long:"selftest" description:"Run the self-test of every enabled asset and media on the start: validation of the known addresses, crafting of the transaction which isn't broadcasted, decoding of the sample invoice, and sending of the canary payment if it is configured. Results are reported by GetInfo method, self-test could be run on demand by RunSelfTest method"`
+	SelfTestCanaries []string `long:"selftestcanary" description:"Canary payment which is sent by the self-test to the controlled address, in form of asset=address:amount, e.g. BTC=bc1q...:0.0001. Canary is sent only on-chain, could be specified multiple times"`
+
+	InvoiceRegenerations        int           `long:"invoiceregenerations" description:"Maximum number of times lightning network invoice is replaced by the new one, if it has expired unpaid or inbound liquidity became insufficient to pay it. Replacements are sent by the SubscribeReceipts method and WebSocket events. Invoices are not replaced if it is zero"`
+	InvoiceRegenerationInterval time.Duration `long:"invoiceregenerationinterval" description:"Period with which lightning network invoices are checked to be replaced"`
+
+	AddressProvider        string        `long:"addressprovider" description:"Host:port of the external address provision service implementing the AddressProvider gRPC contract. If it is specified deposit addresses are requested from it instead of the daemons, and imported into the daemons to track deposits on them"`
+	AddressProviderTLSCert string        `long:"addressprovidertlscert" description:"Path to the TLS certificate of the address provider, connection is not encrypted if it isn't specified"`
+	AddressProviderTimeout time.Duration `long:"addressprovidertimeout" description:"Maximum time to wait for the address from the address provider"`
+
+	FederationPeers          []string      `long:"federationpeer" description:"Payserver of another region with which internal balances are transferred by the TransferToPeer method, in form of name,host:port,publickey[,tlscertpath], where public key is the hex encoded identity key returned by its GetPublicKeys method. Connection is verified by the system certificates if certificate path isn't specified. Federation service is served on the non-admin listeners if peers are given. Could be specified multiple times"`
+	FederationSettleInterval time.Duration `long:"federationsettleinterval" description:"Period with which amounts owed to the federation peers are sent on-chain, and transfers which haven't reached the peers are retried"`
+	FederationMinSettlement  string        `long:"federationminsettlement" description:"Minimum amount owed to the federation peer which is settled periodically, so that dust amounts are not sent. Smaller amounts could be settled by the SettleFederation method"`
+	FederationTimeout        time.Duration `long:"federationtimeout" description:"Maximum time to wait for the response of the federation peer"`
+
+	TestPayments bool `long:"testpayments" description:"Enable InjectTestPayment admin method, which fabricates incoming payments for QA on staging environments. Not allowed on mainnet"`
+
+	Features []string `long:"feature" description:"Rollout rule of the feature flag in form of flag:value, where value is 'on', 'off', percentage of tenants (e.g. 25%) or comma separated list of tenants, could be specified multiple times. Known flags: rbf"`
+
+	Locale        string   `long:"locale" description:"Default locale of the strings which are shown to the payers, e.g. warnings and invoice descriptions. Built-in locales: en, es, pt"`
+	TenantLocales []string `long:"tenantlocale" description:"Locale of the tenant in form of tenant:locale, where tenant is the id of the API key, could be specified multiple times"`
+	MessagesFile  string   `long:"messagesfile" description:"Path to the JSON file with translations in form of {\"locale\": {\"message\": \"text\"}}, which override the built-in ones or add new locales. Messages: fee_too_high, address_reused, inbound_liquidity_low, invoice_description"`
 }
 
 type LndConfig struct {
@@ -93,26 +223,40 @@ type LndConfig struct {
 }
 
 type GethConfig struct {
-	Disabled         bool   `long:"disable" description:"Disable work with this daemon"`
-	ForceLastHash  string `long:"forcelasthash" description:"Denotes that connector should substitute last sync block hash with specified one"`
-	MinConfirmations int    `long:"minconfirmations" description:"Minimum number of block on top of the one where transaction appeared, before we consider transaction as confirmed."`
-	SyncDelay        int    `long:"syncdelay" description:"For how long processing loop should sleep before start syncing pending, confirmed and mempool transactions."`
-	Host             string `long:"host" description:"The host of the lnd daemon"`
-	Port             int    `long:"port" description:"The port of the lnd daemon"`
-	User             string `long:"user" description:"Part of the credential information needed to connect to the daemon RPC endpoint"`
-	Password         string `long:"password" description:"Part of the credential information needed to connect to the daemon RPC endpoint"`
+	Disabled         bool          `long:"disable" description:"Disable work with this daemon"`
+	ForceLastHash    string        `long:"forcelasthash" description:"Denotes that connector should substitute last sync block hash with specified one"`
+	MinConfirmations int           `long:"minconfirmations" description:"Minimum number of block on top of the one where transaction appeared, before we consider transaction as confirmed."`
+	SyncDelay        int           `long:"syncdelay" description:"For how long processing loop should sleep before start syncing pending, confirmed and mempool transactions."`
+	SyncWorkers      int           `long:"syncworkers" description:"Number of concurrent workers which are fetching blocks and transaction receipts from the daemon during the sync"`
+	MaxPending       int           `long:"maxpending" description:"Maximum number of mempool and unconfirmed payments kept in memory"`
+	Host             string        `long:"host" description:"The host of the lnd daemon"`
+	Port             int           `long:"port" description:"The port of the lnd daemon"`
+	User             string        `long:"user" secret:"true" description:"Part of the credential information needed to connect to the daemon RPC endpoint. Could be given in the CONNECTOR_<GROUP>_USER environment variable, or in the file which path is given in CONNECTOR_<GROUP>_USER_FILE"`
+	Password         string        `long:"password" secret:"true" description:"Part of the credential information needed to connect to the daemon RPC endpoint. Could be given in the CONNECTOR_<GROUP>_PASSWORD environment variable, or in the file which path is given in CONNECTOR_<GROUP>_PASSWORD_FILE"`
+	PasswordFile     string        `long:"passwordfile" description:"Path to the file with the password of the daemon accounts, if specified it is used instead of password, and read again on every use, so that password could be rotated"`
+	MaxConns         int           `long:"maxconns" description:"Maximum number of idle connections to the daemon which are kept open and reused"`
+	Timeout          time.Duration `long:"timeout" description:"Maximum time to wait for the response of the daemon on read calls"`
+	KeepAlive        time.Duration `long:"keepalive" description:"Keep alive period of the connections to the daemon"`
 }
 
 type BitcoindConfig struct {
-	Disabled         bool   `long:"disable" description:"Disable work with this daemon"`
-	ForceLastHash  string `long:"forcelasthash" description:"Denotes that connector should substitute last sync block hash with specified one"`
-	MinConfirmations int    `long:"minconfirmations" description:"Minimum number of block on top of the one where transaction appeared, before we consider transaction as confirmed."`
-	SyncDelay        int    `long:"syncdelay" description:"For how long processing loop should sleep before start syncing pending, confirmed and mempool transactions."`
-	FeePerUnit       int    `long:"feeperunit" description:"Fee for every unit of information needed to put it in the blockchain"`
-	Host             string `long:"host" description:"The host of the lnd daemon"`
-	Port             int    `long:"port" description:"The port of the lnd daemon"`
-	User             string `long:"user" description:"Part of the credential information needed to connect to the daemon RPC endpoint"`
-	Password         string `long:"password" description:"Part of the credential information needed to connect to the daemon RPC endpoint"`
+	Disabled         bool          `long:"disable" description:"Disable work with this daemon"`
+	ForceLastHash    string        `long:"forcelasthash" description:"Denotes that connector should substitute last sync block hash with specified one"`
+	MinConfirmations int           `long:"minconfirmations" description:"Minimum number of block on top of the one where transaction appeared, before we consider transaction as confirmed."`
+	SyncDelay        int           `long:"syncdelay" description:"For how long processing loop should sleep before start syncing pending, confirmed and mempool transactions."`
+	SyncWorkers      int           `long:"syncworkers" description:"Number of concurrent workers which are fetching pages of wallet transactions from the daemon during the sync"`
+	FeePerUnit       int           `long:"feeperunit" description:"Fee for every unit of information needed to put it in the blockchain"`
+	Host             string        `long:"host" description:"The host of the lnd daemon"`
+	Port             int           `long:"port" description:"The port of the lnd daemon"`
+	User             string        `long:"user" secret:"true" description:"Part of the credential information needed to connect to the daemon RPC endpoint. Could be given in the CONNECTOR_<GROUP>_USER environment variable, or in the file which path is given in CONNECTOR_<GROUP>_USER_FILE"`
+	Password         string        `long:"password" secret:"true" description:"Part of the credential information needed to connect to the daemon RPC endpoint. Could be given in the CONNECTOR_<GROUP>_PASSWORD environment variable, or in the file which path is given in CONNECTOR_<GROUP>_PASSWORD_FILE"`
+	PoolSize         int           `long:"poolsize" description:"Number of RPC clients used to talk with the daemon concurrently"`
+	Timeout          time.Duration `long:"timeout" description:"Maximum time to wait for the response of the daemon on read calls"`
+	CookiePath       string        `long:"cookiepath" description:"Path to the .cookie file of the daemon, if specified it is used for authentication instead of user and password, and reloaded when daemon regenerates it"`
+	PasswordFile     string        `long:"passwordfile" description:"Path to the file with the RPC password, if specified it is used instead of password, and reloaded when changed"`
+	MaxCalls         int           `long:"maxcalls" description:"Maximum number of calls sent to the daemon concurrently, other calls are queued, calls of the sync take precedence"`
+	MaxQueuedCalls   int           `long:"maxqueuedcalls" description:"Maximum number of calls waiting for the free slot, new calls are rejected if the queue is full"`
+	QueueTimeout     time.Duration `long:"queuetimeout" description:"Maximum time call waits for the free slot before it is rejected"`
 }
 
 // getDefaultConfig return default version of service config.
@@ -124,12 +268,34 @@ func getDefaultConfig() config {
 		RPCHost: defaultRPCHost,
 		RPCPort: defaultRPCPort,
 
+		RPCSocketMode: defaultRPCSocketMode,
+
+		AdminTokenPath:  defaultAdminTokenPath,
+		IdentityKeyPath: defaultIdentityKeyPath,
+		MacaroonDir:     homeDir,
+
+		RPCMaxMsgSize: defaultRPCMaxMsgSize,
+
+		RESTJSONNames:   defaultRESTJSONNames,
+		RESTCacheMaxAge: defaultRESTCacheMaxAge,
+
 		ConfigFile: defaultConfigFile,
 		LogDir:     defaultLogDir,
 		DebugLevel: defaultLogLevel,
 
 		Network: defaultNet,
 
+		FiatCurrency: defaultFiatCurrency,
+
+		BalanceSnapshotInterval: defaultBalanceSnapshotInterval,
+
+		InvoiceRegenerationInterval: defaultInvoiceRegenerationInterval,
+
+		AddressProviderTimeout: defaultAddressProviderTimeout,
+
+		FederationSettleInterval: defaultFederationSettleInterval,
+		FederationTimeout:        defaultFederationTimeout,
+
 		Prometheus: &prometheusConfig{
 			Host: defaultPrometheusEndpointHost,
 			Port: defaultPrometheusEndpointPort,
@@ -193,10 +359,91 @@ func (c *config) loadConfig() error {
 		return err
 	}
 
+	// Secrets which aren't given inline are taken from the environment,
+	// so that they could be mounted by the orchestrator.
+	if err := c.loadSecrets(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+
 	// Ensure that the paths are expanded and cleaned.
 	c.TLSCertPath = cleanAndExpandPath(c.TLSCertPath)
 	c.TLSKeyPath = cleanAndExpandPath(c.TLSKeyPath)
 	c.LogDir = cleanAndExpandPath(c.LogDir)
+	c.AdminTokenPath = cleanAndExpandPath(c.AdminTokenPath)
+	c.IdentityKeyPath = cleanAndExpandPath(c.IdentityKeyPath)
+	c.MacaroonDir = cleanAndExpandPath(c.MacaroonDir)
+
+	// Admin service is never served on the merchant facing listeners.
+	if len(c.RPCAdminListen) == 0 {
+		c.RPCAdminListen = []string{defaultRPCAdminListen}
+	}
+
+	if c.RPCMaxMsgSize <= 0 {
+		err := fmt.Errorf("%s: rpc max message size should be positive",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return err
+	}
+
+	if c.RESTCacheMaxAge < 0 {
+		err := fmt.Errorf("%s: rest cache max age shouldn't be negative",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return err
+	}
+
+	if c.BalanceCheckInterval < 0 {
+		err := fmt.Errorf("%s: balance check interval shouldn't be "+
+			"negative", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return err
+	}
+
+	if c.InvoiceRegenerations > 0 && c.InvoiceRegenerationInterval <= 0 {
+		err := fmt.Errorf("%s: invoice regeneration interval should be "+
+			"positive", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return err
+	}
+
+	socketMode, err := strconv.ParseUint(c.RPCSocketMode, 8, 32)
+	if err != nil {
+		err := fmt.Errorf("%s: invalid rpc socket mode: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return err
+	}
+	c.rpcSocketMode = os.FileMode(socketMode)
+
+	for _, ip := range c.TLSExtraIPs {
+		if net.ParseIP(ip) == nil {
+			err := fmt.Errorf("%s: invalid tls extra ip(%v)", funcName, ip)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return err
+		}
+	}
+
+	if c.TestPayments && c.Network == "mainnet" {
+		err := fmt.Errorf("%s: test payments are not allowed on mainnet",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return err
+	}
+
+	if err := c.validateOnionHosts(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return err
+	}
 
 	// Parse, validate, and set debug log level(s).
 	if err := parseAndSetDebugLevels(c.DebugLevel); err != nil {
@@ -216,6 +463,78 @@ func (c *config) loadConfig() error {
 	return nil
 }
 
+// validateOnionHosts ensures that proxy is specified if any of the enabled
+// daemons, webhook or hooks is reachable only through Tor.
+func (c *config) validateOnionHosts() error {
+	if c.Proxy != "" {
+		return nil
+	}
+
+	hosts := make(map[string]string)
+	for name, cfg := range map[string]*BitcoindConfig{
+		"bitcoin":     c.Bitcoin,
+		"bitcoincash": c.BitcoinCash,
+		"litecoin":    c.Litecoin,
+		"dash":        c.Dash,
+	} {
+		if cfg != nil && !cfg.Disabled {
+			hosts[name] = cfg.Host
+		}
+	}
+
+	if c.Ethereum != nil && !c.Ethereum.Disabled {
+		hosts["ethereum"] = c.Ethereum.Host
+	}
+
+	if c.BitcoinLightning != nil && !c.BitcoinLightning.Disabled {
+		hosts["bitcoinlightning"] = c.BitcoinLightning.Host
+	}
+
+	if c.WatchWebhook != "" {
+		webhookURL, err := url.Parse(c.WatchWebhook)
+		if err != nil {
+			return fmt.Errorf("invalid watch webhook url: %v", err)
+		}
+
+		hosts["watchwebhook"] = webhookURL.Hostname()
+	}
+
+	if c.AddressProvider != "" {
+		host, _, err := net.SplitHostPort(c.AddressProvider)
+		if err != nil {
+			return fmt.Errorf("invalid address provider: %v", err)
+		}
+
+		hosts["addressprovider"] = host
+	}
+
+	for name, hook := range map[string]string{
+		"presendhook":  c.PreSendHook,
+		"postsendhook": c.PostSendHook,
+	} {
+		if !strings.HasPrefix(hook, "http://") &&
+			!strings.HasPrefix(hook, "https://") {
+			continue
+		}
+
+		hookURL, err := url.Parse(hook)
+		if err != nil {
+			return fmt.Errorf("invalid %v url: %v", name, err)
+		}
+
+		hosts[name] = hookURL.Hostname()
+	}
+
+	for name, host := range hosts {
+		if common.IsOnionHost(host) {
+			return fmt.Errorf("%v host(%v) is onion address, but proxy "+
+				"isn't specified", name, host)
+		}
+	}
+
+	return nil
+}
+
 // cleanAndExpandPath expands environment variables and leading ~ in the
 // passed path, cleans the result, and returns it.
 // This function is taken from https://github.com/btcsuite/btcd
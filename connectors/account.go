@@ -0,0 +1,21 @@
+package connectors
+
+// Account is the account of the ledger, e.g. the customer or the internal
+// product, to which payments, deposit addresses and receipts are bound.
+// Payments could be bound to the account which isn't registered, account
+// is registered in order to be listed along with its balances.
+type Account struct {
+	// ID is the unique identifier of the account, which is used in the
+	// account field of the payments and receipts.
+	ID string
+
+	// Name is the human readable name of the account.
+	Name string
+
+	// Tenant is the id of the API key on behalf of which account has been
+	// created, empty if API keys are not used.
+	Tenant string
+
+	// CreatedAt is the time of the account creation in milliseconds.
+	CreatedAt int64
+}
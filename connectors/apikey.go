@@ -0,0 +1,51 @@
+package connectors
+
+// APIKeyScope is the group of methods which API key allows to call.
+type APIKeyScope string
+
+const (
+	// ReceiveScope allows only to create and validate receipts.
+	ReceiveScope APIKeyScope = "receive"
+
+	// SendScope allows to send payments and to call methods which return
+	// the state, e.g. balance or list of payments.
+	SendScope APIKeyScope = "send"
+
+	// AdminScope allows to call all methods, including the operator ones.
+	AdminScope APIKeyScope = "admin"
+)
+
+// APIKey is the key with which downstream service is authorized to call
+// the methods of the server.
+type APIKey struct {
+	// ID is the unique identifier of the key, which is used to manage it.
+	ID string
+
+	// Name is the name of the downstream service which uses the key.
+	Name string
+
+	// Hash is the hex encoded sha256 hash of the key, key itself isn't
+	// stored.
+	Hash string
+
+	// Scopes is the list of scopes which define methods allowed by the key.
+	Scopes []APIKeyScope
+
+	// CreatedAt is the time of the key creation in milliseconds.
+	CreatedAt int64
+
+	// RevokedAt is the time of the key revocation in milliseconds, zero if
+	// key is active.
+	RevokedAt int64
+}
+
+// HasScope returns true if key allows methods of the given scope. Admin
+// scope allows methods of all scopes.
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == AdminScope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package connectors
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// BalanceSnapshot is the balance of the asset in the media at the moment of
+// time. Snapshots are taken periodically, so that level of the wallet could
+// be tracked, and its drain could be noticed.
+type BalanceSnapshot struct {
+	// Asset is the asset of the balance.
+	Asset Asset
+
+	// Media is the media of the balance.
+	Media PaymentMedia
+
+	// Available is the amount of the funds which could be spent.
+	Available decimal.Decimal
+
+	// Pending is the amount of the funds waiting to be confirmed.
+	Pending decimal.Decimal
+
+	// Quarantined is the amount of the funds which are held by the
+	// compliance review.
+	Quarantined decimal.Decimal
+
+	// CreatedAt is the time of the snapshot in milliseconds.
+	CreatedAt int64
+}
+
+// BalanceSnapshotsQuery is the filter of the balance snapshots, empty
+// fields are not used in the filter.
+type BalanceSnapshotsQuery struct {
+	Asset Asset
+	Media PaymentMedia
+
+	// CreatedFrom and CreatedTo are the bounds of the snapshot time in
+	// milliseconds, inclusive, zero means that bound isn't set.
+	CreatedFrom int64
+	CreatedTo   int64
+
+	// Limit is the maximum number of returned snapshots, the newest ones
+	// are returned if there are more matching snapshots. Zero means that
+	// all matching snapshots are returned.
+	Limit int
+}
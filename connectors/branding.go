@@ -0,0 +1,23 @@
+package connectors
+
+// Branding is the look of the hosted checkout and QR pages of the tenant, so
+// that white-label partners could present them as their own.
+type Branding struct {
+	// Tenant is the id of the API key of the merchant, empty tenant is
+	// the default branding which is used for tenants without their own.
+	Tenant string
+
+	// LogoURL is the URL of the logo image.
+	LogoURL string
+
+	// PrimaryColor is the main color of the page in form of "#rrggbb".
+	PrimaryColor string
+
+	// AccentColor is the color of the buttons and links in form of
+	// "#rrggbb".
+	AccentColor string
+
+	// SuccessURL is the URL to which payer is redirected after the
+	// payment is received.
+	SuccessURL string
+}
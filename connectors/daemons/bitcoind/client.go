@@ -34,6 +34,10 @@ var (
 	//  of information in blockchain.
 	minimumFeeRate = decimal.NewFromFloat(1.0)
 
+	// defaultConfTarget is the number of blocks within which transaction
+	// is expected to be confirmed, if target isn't specified.
+	defaultConfTarget uint32 = 2
+
 	// This value is calculated as being optimal by running emulation of
 	// activity on payserver on 18 Nov 2018. This value is optimised to have
 	// spent less fee, at the same time having high success payment  rate.
@@ -497,6 +501,70 @@ func (c *Connector) CreatePayment(address string, amount string) (*connectors.Pa
 	return payment, nil
 }
 
+// SimulatePayment performs the same coin selection and fee calculation as
+// CreatePayment, but selected inputs are neither locked nor removed from
+// the local cache, and payment is neither signed nor saved.
+//
+// NOTE: Part of the connectors.PaymentSimulator interface.
+func (c *Connector) SimulatePayment(address, amount,
+	memo string) (*connectors.Payment, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	if memo != "" {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("memo isn't supported")
+	}
+
+	if _, err := decodeAddress(c.cfg.Asset, address,
+		c.netParams.Name); err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("invalid address: %v", err)
+	}
+
+	amtInBtc, err := decimal.NewFromString(amount)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("unable to decode amount: %v", err)
+	}
+
+	if c.unspent == nil {
+		if err := c.syncUnspent(); err != nil {
+			m.AddError(metrics.HighSeverity)
+			return nil, errors.Errorf("unable to sync unspent: %v", err)
+		}
+	}
+
+	feeSatoshiPerByte := uint64(c.getFeeRate().IntPart())
+
+	c.unspentSyncMtx.Lock()
+	defer c.unspentSyncMtx.Unlock()
+
+	selectedInputs, changeAmt, fee, err := coinSelect(feeSatoshiPerByte,
+		decAmount2Sat(amtInBtc), c.unspent)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("unable to select inputs: %v", err)
+	}
+
+	c.log.Debugf("Simulated payment selects %v unspent inputs, "+
+		"amount(%v), change(%v), fee(%v)", len(selectedInputs),
+		amtInBtc, printAmount(changeAmt), printAmount(fee))
+
+	return &connectors.Payment{
+		UpdatedAt: connectors.NowInMilliSeconds(),
+		Status:    connectors.Waiting,
+		Direction: connectors.Outgoing,
+		System:    connectors.External,
+		Receipt:   address,
+		Asset:     connectors.Asset(c.cfg.Asset),
+		Media:     connectors.Blockchain,
+		Amount:    amtInBtc.Round(8),
+		MediaFee:  sat2DecAmount(fee),
+	}, nil
+}
+
 // SendPayment sends created previously payment to the
 // blockchain network.
 //
@@ -554,6 +622,7 @@ func (c *Connector) SendPayment(paymentID string) (*connectors.Payment, error) {
 
 	if err = c.client.SendRawTransaction(wireTx); err != nil {
 		payment.Status = connectors.Failed
+		payment.FailureReason = err.Error()
 		payment.UpdatedAt = connectors.NowInMilliSeconds()
 
 		if err := c.cfg.PaymentStore.SavePayment(payment); err != nil {
@@ -564,6 +633,7 @@ func (c *Connector) SendPayment(paymentID string) (*connectors.Payment, error) {
 
 		if changePayment != nil {
 			changePayment.Status = connectors.Failed
+			changePayment.FailureReason = err.Error()
 			changePayment.UpdatedAt = connectors.NowInMilliSeconds()
 			if err := c.cfg.PaymentStore.SavePayment(changePayment); err != nil {
 				m.AddError(metrics.HighSeverity)
@@ -1034,7 +1104,7 @@ func (c *Connector) getFeeRate() decimal.Decimal {
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
 
-	feeRate, err := c.client.EstimateFee()
+	feeRate, err := c.client.EstimateFee(defaultConfTarget)
 	if err != nil {
 		if c.cfg.Net == "mainnet" {
 			// In case of mainnet such situation happens rarely for that
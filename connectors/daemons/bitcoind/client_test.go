@@ -83,6 +83,18 @@ func (c *ReplayRPCClient) GetBestBlockHash() (*chainhash.Hash, error) {
 	}
 }
 
+func (c *ReplayRPCClient) GetBlockHash(height int64) (*chainhash.Hash,
+	error) {
+	c.t.Log(common.GetFunctionName())
+
+	select {
+	case resp := <-c.responses:
+		return resp.data.(*chainhash.Hash), resp.err
+	case <-time.After(c.delay):
+		return nil, errors.Errorf("response delay")
+	}
+}
+
 func (c *ReplayRPCClient) UnlockUnspent() error {
 	c.t.Log(common.GetFunctionName())
 	return nil
@@ -93,6 +105,16 @@ func (c *ReplayRPCClient) LockUnspent(input rpc.UnspentInput) error {
 	return nil
 }
 
+func (c *ReplayRPCClient) UnlockUnspentInput(input rpc.UnspentInput) error {
+	c.t.Log(common.GetFunctionName())
+	return nil
+}
+
+func (c *ReplayRPCClient) ListLockUnspent() ([]*wire.OutPoint, error) {
+	c.t.Log(common.GetFunctionName())
+	return nil, nil
+}
+
 func (c *ReplayRPCClient) ListUnspentMinMax(minConf, maxConf int) ([]rpc.UnspentInput, error) {
 	c.t.Log(common.GetFunctionName())
 
@@ -180,7 +202,7 @@ func (c *ReplayRPCClient) GetTransaction(txHash *chainhash.Hash) (*rpc.Transacti
 	}
 }
 
-func (c *ReplayRPCClient) EstimateFee() (float64, error) {
+func (c *ReplayRPCClient) EstimateFee(confTarget uint32) (float64, error) {
 	c.t.Log(common.GetFunctionName())
 
 	select {
@@ -3,6 +3,7 @@ package bitcoind
 import (
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
@@ -30,17 +31,24 @@ func (e *ErrInsufficientFunds) Error() string {
 
 // selectInputs selects a slice of inputs necessary to meet the specified
 // selection amount. If input selection is unable to succeed to to insufficient
-// funds, a non-nil error is returned.
-// TODO(andrew.shvv) Probably sort inputs, before selecting them.
+// funds, a non-nil error is returned. Inputs are iterated in order of their
+// keys, so that the selection is deterministic for the same unspent set.
 // TODO(andrew.shvv) Develop hierstic algorithm of choosing the outputs
 // efficiently.
 func selectInputs(amt btcutil.Amount,
 	inputsMap map[string]rpc.UnspentInput) (btcutil.Amount,
 	[]rpc.UnspentInput, error) {
 
+	keys := make([]string, 0, len(inputsMap))
+	for key := range inputsMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	var inputs []rpc.UnspentInput
 	satSelected := btcutil.Amount(0)
-	for _, input := range inputsMap {
+	for _, key := range keys {
+		input := inputsMap[key]
 		amount, err := btcutil.NewAmount(input.Amount)
 		if err != nil {
 			return 0, nil, err
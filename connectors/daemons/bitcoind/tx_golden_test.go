@@ -0,0 +1,238 @@
+package bitcoind
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors/rpc"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btclog"
+	"github.com/btcsuite/btcutil"
+)
+
+// updateGolden is used to regenerate golden files after intended change of
+// the coin selection or transaction crafting, e.g.:
+//
+//	go test ./connectors/daemons/bitcoind -run TestCraftTransactionGolden -update
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+const (
+	// goldenTxA, goldenTxB and goldenTxC are the ids of the transactions
+	// of the test unspent outputs. Unspent outputs are selected in order of
+	// their keys, which is goldenTxC, goldenTxB, goldenTxA.
+	goldenTxA = "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb"
+	goldenTxB = "3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d"
+	goldenTxC = "2e7d2c03a9507ae265ecf5b5356885a53393a2029d241394997265a1a25aefc6"
+
+	// goldenChangeAddress is the address returned by the daemon for the
+	// change output.
+	goldenChangeAddress = "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+)
+
+// craftRPCClient is the rpc client which implements only the methods used
+// during transaction crafting, and creates raw transaction locally in the
+// same way as bitcoind does.
+type craftRPCClient struct {
+	rpc.Client
+
+	changeAddress btcutil.Address
+	locked        []rpc.UnspentInput
+}
+
+func (c *craftRPCClient) LockUnspent(input rpc.UnspentInput) error {
+	c.locked = append(c.locked, input)
+	return nil
+}
+
+func (c *craftRPCClient) GetNewRawChangeAddress(label string) (btcutil.Address,
+	error) {
+	return c.changeAddress, nil
+}
+
+// CreateRawTransaction creates transaction of the current bitcoind version
+// with zero lock time. Outputs are passed to the daemon as json object,
+// which keys are sorted, that is why outputs are sorted by address.
+func (c *craftRPCClient) CreateRawTransaction(inputs []rpc.UnspentInput,
+	outputs map[btcutil.Address]btcutil.Amount) (*wire.MsgTx, error) {
+
+	tx := wire.NewMsgTx(2)
+	for _, input := range inputs {
+		hash, err := chainhash.NewHashFromStr(input.TxID)
+		if err != nil {
+			return nil, err
+		}
+
+		outpoint := wire.NewOutPoint(hash, input.Vout)
+		tx.AddTxIn(wire.NewTxIn(outpoint, nil, nil))
+	}
+
+	addresses := make([]btcutil.Address, 0, len(outputs))
+	for address := range outputs {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].EncodeAddress() < addresses[j].EncodeAddress()
+	})
+
+	for _, address := range addresses {
+		pkScript, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			return nil, err
+		}
+
+		tx.AddTxOut(wire.NewTxOut(int64(outputs[address]), pkScript))
+	}
+
+	return tx, nil
+}
+
+// TestCraftTransactionGolden checks that coin selection and transaction
+// crafting with fixed unspent outputs, fee rate and destination produce
+// exactly the same fee, change and serialized transaction as recorded in
+// the golden files.
+func TestCraftTransactionGolden(t *testing.T) {
+	tests := []struct {
+		name           string
+		unspent        []rpc.UnspentInput
+		amount         btcutil.Amount
+		feeRatePerByte uint64
+		address        string
+	}{
+		{
+			name: "p2pkh",
+			unspent: []rpc.UnspentInput{
+				{TxID: goldenTxA, Vout: 0, Amount: 1.0},
+			},
+			amount:         50000000,
+			feeRatePerByte: 10,
+			address:        "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		},
+		{
+			// Two inputs are needed to fund the amount.
+			name: "p2sh",
+			unspent: []rpc.UnspentInput{
+				{TxID: goldenTxA, Vout: 1, Amount: 0.1},
+				{TxID: goldenTxB, Vout: 0, Amount: 0.2},
+				{TxID: goldenTxC, Vout: 2, Amount: 0.3},
+			},
+			amount:         35000000,
+			feeRatePerByte: 20,
+			address:        "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+		},
+		{
+			// First input covers the amount but not the fee, that is
+			// why the second round of selection is needed.
+			name: "p2wpkh",
+			unspent: []rpc.UnspentInput{
+				{TxID: goldenTxA, Vout: 0, Amount: 0.1},
+				{TxID: goldenTxB, Vout: 0, Amount: 0.2},
+			},
+			amount:         20000000,
+			feeRatePerByte: 5,
+			address:        "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			// Input covers exactly the amount and the fee, so the
+			// change output isn't created.
+			name: "no_change",
+			unspent: []rpc.UnspentInput{
+				{TxID: goldenTxA, Vout: 0, Amount: 0.5},
+			},
+			amount:         49999773,
+			feeRatePerByte: 1,
+			address:        "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		},
+	}
+
+	changeAddress, err := btcutil.DecodeAddress(goldenChangeAddress,
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to decode change address: %v", err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address, err := btcutil.DecodeAddress(test.address,
+				&chaincfg.MainNetParams)
+			if err != nil {
+				t.Fatalf("unable to decode address: %v", err)
+			}
+
+			unspent := make(map[string]rpc.UnspentInput, len(test.unspent))
+			for _, u := range test.unspent {
+				unspent[fmt.Sprintf("%v:%v", u.TxID, u.Vout)] = u
+			}
+
+			client := &craftRPCClient{changeAddress: changeAddress}
+			c := &Connector{
+				client:  client,
+				unspent: unspent,
+				log: &common.NamedLogger{
+					Name:   "BTC",
+					Logger: btclog.Disabled,
+				},
+			}
+
+			tx, fee, change, changeAddr, err := c.craftTransaction(
+				test.feeRatePerByte, test.amount, address)
+			if err != nil {
+				t.Fatalf("unable to craft transaction: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := tx.Serialize(&buf); err != nil {
+				t.Fatalf("unable to serialize transaction: %v", err)
+			}
+
+			changeAddrStr := "none"
+			if changeAddr != nil {
+				changeAddrStr = changeAddr.EncodeAddress()
+			}
+
+			var got bytes.Buffer
+			fmt.Fprintf(&got, "fee: %d\n", int64(fee))
+			fmt.Fprintf(&got, "change: %d\n", int64(change))
+			fmt.Fprintf(&got, "change address: %v\n", changeAddrStr)
+			for _, input := range client.locked {
+				fmt.Fprintf(&got, "input: %v:%v\n", input.TxID, input.Vout)
+			}
+			fmt.Fprintf(&got, "tx: %v\n", hex.EncodeToString(buf.Bytes()))
+
+			path := filepath.Join("testdata", "craft_"+test.name+".golden")
+			if *updateGolden {
+				if err := ioutil.WriteFile(path, got.Bytes(), 0644); err != nil {
+					t.Fatalf("unable to update golden file: %v", err)
+				}
+			}
+
+			want, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("unable to read golden file: %v", err)
+			}
+
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Fatalf("crafted transaction doesn't match golden file "+
+					"%v:\ngot:\n%s\nwant:\n%s", path, got.Bytes(), want)
+			}
+
+			// Selected inputs should be removed from the local cache, so
+			// that they couldn't be used by the next transaction.
+			for _, input := range client.locked {
+				key := fmt.Sprintf("%v:%v", input.TxID, input.Vout)
+				if _, ok := c.unspent[key]; ok {
+					t.Fatalf("selected input %v is left in cache", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package bitcoind_simple
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/connectors/rpc"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/go-errors/errors"
+)
+
+// mainnetGenesis are the hashes of the mainnet genesis blocks, by which
+// daemons of the different assets are distinguished. Bitcoin and bitcoin
+// cash are sharing the history before the fork, that is why they have the
+// same genesis block.
+var mainnetGenesis = map[connectors.Asset]string{
+	connectors.BTC:  chaincfg.MainNetParams.GenesisHash.String(),
+	connectors.BCH:  chaincfg.MainNetParams.GenesisHash.String(),
+	connectors.LTC:  "12a765e31ffd4059bada1e25190f6e98c99d9714d334efa41a195a7e7e04bfe2",
+	connectors.DASH: "00000ffd590b1485b3caadc19b22e6379c733355108f107a430458cdf3407ab6",
+}
+
+const (
+	// cashForkHeight is the height of the first mainnet block after the
+	// bitcoin cash fork, which is the first block which differs in the
+	// bitcoin and bitcoin cash chains.
+	cashForkHeight = 478559
+
+	// cashForkHash is the hash of the first bitcoin cash block after the
+	// fork.
+	cashForkHash = "000000000000000000651ef99cb9fcbe0dadde1d424bd9f15ff20136191a5eec"
+)
+
+// ErrChainNotIdentified is returned if the daemon hasn't synced the blocks
+// by which the chain is identified.
+var ErrChainNotIdentified = errors.New("chain couldn't be identified yet")
+
+// ProbeDaemon ensures that daemon is reachable, and that it is working on
+// the given network and on the chain of the asset. Chain info is returned
+// along with ErrChainNotIdentified if daemon hasn't synced the blocks by
+// which the chain is identified.
+func ProbeDaemon(client rpc.BlocksManager, asset connectors.Asset,
+	net string) (*rpc.BlockChainInfoResp, error) {
+
+	resp, err := client.GetBlockChainInfo()
+	if err != nil {
+		return nil, errors.Errorf("unable to get type of network: %v", err)
+	}
+
+	if !isProperNet(net, resp.Chain) {
+		return nil, errors.Errorf("networks are different, desired: %v, "+
+			"actual: %v", net, resp.Chain)
+	}
+
+	// Bitcoin and bitcoin cash daemons are using the same rpc interface
+	// and address format, that is why misconfigured daemon wouldn't be
+	// noticed otherwise.
+	err = checkChain(asset, resp.Chain, resp.Blocks, client.GetBlockHash)
+	switch {
+	case err == ErrChainNotIdentified:
+		return resp, err
+	case err != nil:
+		return nil, errors.Errorf("wrong chain of the daemon: %v", err)
+	}
+
+	return resp, nil
+}
+
+// blockHashFunc returns hash of the block on the given height.
+type blockHashFunc func(height int64) (*chainhash.Hash, error)
+
+// checkChain ensures that daemon is working on the chain of the asset, so
+// that misconfigured connector, e.g. bitcoin cash connector which is
+// pointed to the bitcoin daemon, is rejected before any payment is sent,
+// otherwise payments would be replayed on the foreign chain. Daemon is
+// identified by the genesis block, bitcoin and bitcoin cash daemons are
+// identified by the block after the fork. Only mainnet is checked.
+func checkChain(asset connectors.Asset, network string, height int64,
+	blockHash blockHashFunc) error {
+	if network != "main" {
+		return nil
+	}
+
+	genesis, ok := mainnetGenesis[asset]
+	if !ok {
+		return errors.Errorf("unsupported asset(%v)", asset)
+	}
+
+	hash, err := blockHash(0)
+	if err != nil {
+		return errors.Errorf("unable to get genesis block: %v", err)
+	}
+
+	if hash.String() != genesis {
+		return errors.Errorf("daemon isn't %v daemon, genesis block "+
+			"expected(%v), actual(%v)", asset, genesis, hash)
+	}
+
+	if asset != connectors.BTC && asset != connectors.BCH {
+		return nil
+	}
+
+	if height < cashForkHeight {
+		return ErrChainNotIdentified
+	}
+
+	hash, err = blockHash(cashForkHeight)
+	if err != nil {
+		return errors.Errorf("unable to get fork block: %v", err)
+	}
+
+	isCash := hash.String() == cashForkHash
+	switch {
+	case asset == connectors.BCH && !isCash:
+		return errors.Errorf("daemon is on the bitcoin chain, but bitcoin " +
+			"cash daemon is expected")
+
+	case asset == connectors.BTC && isCash:
+		return errors.Errorf("daemon is on the bitcoin cash chain, but " +
+			"bitcoin daemon is expected")
+	}
+
+	return nil
+}
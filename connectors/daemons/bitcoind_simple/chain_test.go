@@ -0,0 +1,119 @@
+package bitcoind_simple
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/go-errors/errors"
+)
+
+func TestCheckChain(t *testing.T) {
+	// Hash of the first bitcoin block after the bitcoin cash fork.
+	const bitcoinForkHash = "00000000000000000019f112ec0a9982926f1258cd" +
+		"cc558dd7c3b7e5dc7fa148"
+
+	chain := func(genesis, fork string) blockHashFunc {
+		return func(height int64) (*chainhash.Hash, error) {
+			switch height {
+			case 0:
+				return chainhash.NewHashFromStr(genesis)
+			case cashForkHeight:
+				return chainhash.NewHashFromStr(fork)
+			default:
+				return nil, errors.Errorf("unexpected height(%v)", height)
+			}
+		}
+	}
+
+	bitcoinGenesis := chaincfg.MainNetParams.GenesisHash.String()
+	bitcoin := chain(bitcoinGenesis, bitcoinForkHash)
+	bitcoinCash := chain(bitcoinGenesis, cashForkHash)
+	litecoin := chain(mainnetGenesis[connectors.LTC], bitcoinForkHash)
+
+	tests := []struct {
+		name    string
+		asset   connectors.Asset
+		network string
+		height  int64
+		chain   blockHashFunc
+		valid   bool
+	}{
+		{
+			name:    "bitcoin",
+			asset:   connectors.BTC,
+			network: "main",
+			height:  cashForkHeight,
+			chain:   bitcoin,
+			valid:   true,
+		},
+		{
+			name:    "bitcoin cash",
+			asset:   connectors.BCH,
+			network: "main",
+			height:  cashForkHeight,
+			chain:   bitcoinCash,
+			valid:   true,
+		},
+		{
+			name:    "bitcoin cash connector on bitcoin daemon",
+			asset:   connectors.BCH,
+			network: "main",
+			height:  cashForkHeight,
+			chain:   bitcoin,
+			valid:   false,
+		},
+		{
+			name:    "bitcoin connector on bitcoin cash daemon",
+			asset:   connectors.BTC,
+			network: "main",
+			height:  cashForkHeight,
+			chain:   bitcoinCash,
+			valid:   false,
+		},
+		{
+			name:    "bitcoin connector on litecoin daemon",
+			asset:   connectors.BTC,
+			network: "main",
+			height:  cashForkHeight,
+			chain:   litecoin,
+			valid:   false,
+		},
+		{
+			name:    "litecoin",
+			asset:   connectors.LTC,
+			network: "main",
+			height:  cashForkHeight,
+			chain:   litecoin,
+			valid:   true,
+		},
+		{
+			name:    "testnet isn't checked",
+			asset:   connectors.BCH,
+			network: "test",
+			height:  cashForkHeight,
+			chain:   bitcoin,
+			valid:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkChain(test.asset, test.network, test.height,
+				test.chain)
+			if test.valid && err != nil {
+				t.Fatalf("chain should be accepted: %v", err)
+			}
+
+			if !test.valid && err == nil {
+				t.Fatalf("chain should be rejected")
+			}
+		})
+	}
+
+	err := checkChain(connectors.BCH, "main", cashForkHeight-1, bitcoinCash)
+	if err != ErrChainNotIdentified {
+		t.Fatalf("expected chain not identified error, got: %v", err)
+	}
+}
@@ -1,17 +1,25 @@
 package bitcoind_simple
 
 import (
+	"encoding/hex"
+	"fmt"
 	"github.com/bitlum/connector/common"
 	"github.com/bitlum/connector/connectors"
 	"github.com/bitlum/connector/connectors/rpc"
+	"github.com/bitlum/connector/features"
 	"github.com/bitlum/connector/metrics"
 	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/bitlum/go-bitcoind-rpc/btcjson"
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btclog"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 	"github.com/shopspring/decimal"
-	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,6 +36,19 @@ var (
 	// minimumFeeRate is the minimal satoshis which we should pay for one byte
 	//  of information in blockchain.
 	minimumFeeRate = decimal.NewFromFloat(1.0)
+
+	// defaultConfTarget is the number of blocks within which transaction
+	// is expected to be confirmed, if target isn't specified.
+	defaultConfTarget uint32 = 2
+
+	// watchAccount is the label of the watch-only addresses, it is used to
+	// keep them separately from the deposit addresses.
+	watchAccount = "watch"
+
+	// delegatedAccount is the label of the deposit addresses which have
+	// been derived by the external address provider, they are watch-only,
+	// because their keys are kept by the provider.
+	delegatedAccount = "delegated"
 )
 
 // Config is a bitcoind config.
@@ -39,6 +60,11 @@ type Config struct {
 	// to treat transaction as confirmed.
 	MinConfirmations int
 
+	// SyncWorkers is the number of concurrent workers which are fetching
+	// pages of wallet transactions from the daemon during the sync. If zero
+	// the default number is used.
+	SyncWorkers int
+
 	// RPCClient...
 	RPCClient rpc.Client
 
@@ -64,6 +90,38 @@ type Config struct {
 	// PaymentStorage is an external storage for payments, it is used by
 	// connector to save payment as well as update its state.
 	PaymentStore connectors.PaymentsStore
+
+	// WatchStore is an external storage for watched addresses and their
+	// events. If it is not specified activity on watched addresses is not
+	// tracked.
+	WatchStore connectors.WatchStore
+
+	// WatchNotifier is used to notify about activity on watched addresses.
+	WatchNotifier connectors.WatchNotifier
+
+	// TimeLocksStore is used to save time-locked payments along with their
+	// scripts, time-locked payments couldn't be sent without it.
+	TimeLocksStore connectors.TimeLocksStore
+
+	// ReceiptsStore is used to find the tenant of the deposit address, so
+	// that feature flags are checked for it. If it is not specified
+	// deposits are checked as the ones without tenant.
+	ReceiptsStore connectors.ReceiptsStore
+
+	// DepositsStore is used to bind received outputs to the payments, so
+	// that deposit listed again after reindex or resync of the daemon
+	// isn't recorded twice. If it is not specified deposits are
+	// identified by the payment id only.
+	DepositsStore connectors.DepositsStore
+
+	// DelegatedAddresses denotes that deposit addresses are derived by the
+	// external address provider and imported into the daemon, so that
+	// deposits on them should be synced.
+	DelegatedAddresses bool
+
+	// Features is used to check whether risky behaviour is enabled, if it
+	// is not specified all feature flags are disabled.
+	Features *features.Registry
 }
 
 func (c *Config) validate() error {
@@ -104,6 +162,10 @@ func (c *Config) validate() error {
 		return errors.New("state store should be specified")
 	}
 
+	if c.SyncWorkers <= 0 {
+		c.SyncWorkers = 4
+	}
+
 	return nil
 }
 
@@ -113,6 +175,19 @@ type Connector struct {
 	wg       sync.WaitGroup
 	quit     chan struct{}
 
+	// lastSyncAt is the time of the last successful sync in milliseconds,
+	// should be used atomically.
+	lastSyncAt int64
+
+	// syncRequests is used to trigger the sync out of the schedule.
+	syncRequests chan *syncRequest
+
+	// pendingWatchPayments is the set of the events of the unconfirmed
+	// funding of the watched addresses, which received payments have to
+	// be published once again when they are confirmed. It is used only by
+	// the sync goroutine.
+	pendingWatchPayments map[string]struct{}
+
 	cfg    *Config
 	client rpc.Client
 
@@ -124,15 +199,44 @@ type Connector struct {
 // interface.
 var _ connectors.BlockchainConnector = (*Connector)(nil)
 
+// A compile time check to ensure Connector implements the UnspentSyncer
+// interface.
+var _ connectors.UnspentSyncer = (*Connector)(nil)
+
+// A compile time check to ensure Connector implements the TenantSender
+// interface.
+var _ connectors.TenantSender = (*Connector)(nil)
+
+// A compile time check to ensure Connector implements the BatchSender
+// interface.
+var _ connectors.BatchSender = (*Connector)(nil)
+
+// A compile time check to ensure Connector implements the TimeLocker
+// interface.
+var _ connectors.TimeLocker = (*Connector)(nil)
+
+// A compile time check to ensure Connector implements the ChainReporter
+// interface.
+var _ connectors.ChainReporter = (*Connector)(nil)
+
+// A compile time check to ensure Connector implements the AssetReporter
+// interface.
+var _ connectors.AssetReporter = (*Connector)(nil)
+
+// A compile time check to ensure Connector implements the
+// FeeTargetEstimator interface.
+var _ connectors.FeeTargetEstimator = (*Connector)(nil)
+
 func NewConnector(cfg *Config) (*Connector, error) {
 	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
 
 	return &Connector{
-		cfg:    cfg,
-		quit:   make(chan struct{}),
-		client: cfg.RPCClient,
+		cfg:          cfg,
+		quit:         make(chan struct{}),
+		syncRequests: make(chan *syncRequest),
+		client:       cfg.RPCClient,
 		log: &common.NamedLogger{
 			Name:   string(cfg.Asset),
 			Logger: cfg.Logger,
@@ -158,15 +262,15 @@ func (c *Connector) Start() (err error) {
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
 
-	resp, err := c.client.GetBlockChainInfo()
-	if err != nil {
-		m.AddError(metrics.HighSeverity)
-		return errors.Errorf("unable to get type of network: %v", err)
-	}
+	resp, err := ProbeDaemon(c.client, c.cfg.Asset, c.cfg.Net)
+	switch {
+	case err == ErrChainNotIdentified:
+		c.log.Warnf("Unable to identify chain of the daemon, it hasn't "+
+			"synced block(%v) yet", cashForkHeight)
 
-	if !isProperNet(c.cfg.Net, resp.Chain) {
-		return errors.Errorf("networks are different, desired: %v, "+
-			"actual: %v", c.cfg.Net, resp.Chain)
+	case err != nil:
+		m.AddError(metrics.HighSeverity)
+		return err
 	}
 
 	c.log.Infof("Init connector working with '%v' net", c.cfg.Net)
@@ -192,11 +296,21 @@ func (c *Connector) Start() (err error) {
 		for {
 			select {
 			case <-syncPaymentStateTicker.C:
-				if err := c.syncPaymentState(); err != nil {
+				if err := c.sync(); err != nil {
 					m.AddError(metrics.MiddleSeverity)
-					c.log.Errorf("unable to sync payment state: %v", err)
+					c.log.Error(err)
 					continue
 				}
+
+			case req := <-c.syncRequests:
+				err := c.handleSyncRequest(req)
+				if err != nil {
+					m.AddError(metrics.MiddleSeverity)
+					c.log.Error(err)
+				}
+
+				req.errChan <- err
+
 			case <-c.quit:
 				return
 			}
@@ -280,8 +394,19 @@ func (c *Connector) PendingBalance() (decimal.Decimal, error) {
 	return sat2DecAmount(overallBalance - confirmedBalance), nil
 }
 
-// SendPayment sends payment with given amount to the given address.
-func (c *Connector) SendPayment(address, amount string) (*connectors.Payment, error) {
+// SendPayment sends payment with given amount to the given address. Memo,
+// if specified, is attached to the transaction as OP_RETURN output.
+func (c *Connector) SendPayment(address, amount,
+	memo string) (*connectors.Payment, error) {
+	return c.SendTenantPayment("", address, amount, memo)
+}
+
+// SendTenantPayment sends payment on behalf of the tenant, feature flags
+// are checked for the tenant.
+//
+// NOTE: Part of the connectors.TenantSender interface.
+func (c *Connector) SendTenantPayment(tenant, address, amount,
+	memo string) (*connectors.Payment, error) {
 	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
@@ -292,13 +417,46 @@ func (c *Connector) SendPayment(address, amount string) (*connectors.Payment, er
 		return nil, errors.Errorf("invalid address: %v", err)
 	}
 
+	// Store receipt in canonical form, so that payments on the same address
+	// are not looking differently depending on how user wrote it.
+	receipt, err := encodeAddress(c.cfg.Asset, decodedAddress)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("unable to normalize address: %v", err)
+	}
+
 	amtInBtc, err := decimal.NewFromString(amount)
 	if err != nil {
 		m.AddError(metrics.LowSeverity)
 		return nil, errors.Errorf("unable to decode amount: %v", err)
 	}
 
-	txHash, err := c.cfg.RPCClient.SendToAddress(decodedAddress, decAmount2Sat(amtInBtc))
+	if len(memo) > txscript.MaxDataCarrierSize {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("memo is too long, max size is %v bytes",
+			txscript.MaxDataCarrierSize)
+	}
+
+	// Replace-by-fee is gated by the feature flag, and it is recorded on
+	// the payment, so that its effect could be analysed later.
+	var (
+		txHash *chainhash.Hash
+		flags  []string
+	)
+	replaceable := supportsRBF(c.cfg.Asset) &&
+		c.cfg.Features.IsEnabled(features.RBF, tenant)
+
+	if replaceable {
+		txHash, err = c.cfg.RPCClient.SendToAddressReplaceable(decodedAddress,
+			decAmount2Sat(amtInBtc), []byte(memo))
+		flags = append(flags, string(features.RBF))
+	} else if memo != "" {
+		txHash, err = c.cfg.RPCClient.SendToAddressWithData(decodedAddress,
+			decAmount2Sat(amtInBtc), []byte(memo))
+	} else {
+		txHash, err = c.cfg.RPCClient.SendToAddress(decodedAddress,
+			decAmount2Sat(amtInBtc))
+	}
 	if err != nil {
 		m.AddError(metrics.HighSeverity)
 		return nil, errors.Errorf("unable send transaction: %v", err)
@@ -310,17 +468,35 @@ func (c *Connector) SendPayment(address, amount string) (*connectors.Payment, er
 		return nil, errors.Errorf("unable get transaction by hash: %v", err)
 	}
 
+	// Payment on the address of our wallet only moves funds inside it,
+	// wallet transaction has both send and receive entries in this case.
+	entries := make([]btcjson.ListTransactionsResult, len(tx.Details))
+	for i, detail := range tx.Details {
+		entries[i] = btcjson.ListTransactionsResult{
+			TxID:     txHash.String(),
+			Address:  detail.Address,
+			Category: detail.Category,
+		}
+	}
+
+	system := connectors.External
+	if c.internalEntries(entries)[txHash.String()+":"+receipt] {
+		system = connectors.Internal
+	}
+
 	payment := &connectors.Payment{
 		UpdatedAt: connectors.ConvertTimeToMilliSeconds(time.Now()),
 		Status:    connectors.Pending,
 		Direction: connectors.Outgoing,
-		System:    connectors.External,
-		Receipt:   address,
+		System:    system,
+		Receipt:   receipt,
 		Asset:     c.cfg.Asset,
 		Media:     connectors.Blockchain,
 		Amount:    amtInBtc,
 		MediaFee:  decimal.NewFromFloat(tx.Fee).Abs().Round(8),
 		MediaID:   txHash.String(),
+		Memo:      memo,
+		Flags:     flags,
 	}
 
 	payment.PaymentID, err = payment.GenPaymentID()
@@ -337,27 +513,524 @@ func (c *Connector) SendPayment(address, amount string) (*connectors.Payment, er
 	return payment, nil
 }
 
-// DecodeAddress takes the blockchain address and ensure its validity.
-func (c *Connector) ValidateAddress(address string) error {
+// SendPayments sends payments to the given outputs in one transaction. Fee
+// of the transaction is split between the payments in proportion to the
+// sizes of their outputs.
+//
+// NOTE: Part of the connectors.BatchSender interface.
+func (c *Connector) SendPayments(tenant string,
+	outputs []*connectors.PaymentOutput) ([]*connectors.Payment, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	if len(outputs) == 0 {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("outputs are empty")
+	}
+
+	var (
+		receipts = make([]string, len(outputs))
+		amounts  = make([]decimal.Decimal, len(outputs))
+		sizes    = make([]int64, len(outputs))
+		txOuts   = make(map[btcutil.Address]btcutil.Amount, len(outputs))
+		seen     = make(map[string]struct{}, len(outputs))
+	)
+
+	// Replace-by-fee is gated per tenant on behalf of which payments are
+	// sent.
+	replaceable := supportsRBF(c.cfg.Asset) &&
+		c.cfg.Features.IsEnabled(features.RBF, tenant)
+
+	for i, output := range outputs {
+		decodedAddress, err := decodeAddress(c.cfg.Asset, output.Address,
+			c.netParams.Name)
+		if err != nil {
+			m.AddError(metrics.LowSeverity)
+			return nil, errors.Errorf("invalid address(%v): %v",
+				output.Address, err)
+		}
+
+		receipt, err := encodeAddress(c.cfg.Asset, decodedAddress)
+		if err != nil {
+			m.AddError(metrics.LowSeverity)
+			return nil, errors.Errorf("unable to normalize address: %v", err)
+		}
+
+		// Payments are distinguished by the receipt inside the
+		// transaction, that is why address couldn't be repeated.
+		if _, ok := seen[receipt]; ok {
+			m.AddError(metrics.LowSeverity)
+			return nil, errors.Errorf("address(%v) is repeated", receipt)
+		}
+		seen[receipt] = struct{}{}
+
+		amount, err := decimal.NewFromString(output.Amount)
+		if err != nil || amount.Sign() <= 0 {
+			m.AddError(metrics.LowSeverity)
+			return nil, errors.Errorf("invalid amount(%v) of address(%v)",
+				output.Amount, receipt)
+		}
+
+		receipts[i] = receipt
+		amounts[i] = amount
+		sizes[i] = outputSize(decodedAddress)
+		txOuts[decodedAddress] = decAmount2Sat(amount)
+	}
+
+	txHash, err := c.cfg.RPCClient.SendToAddresses(txOuts, replaceable)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable send transaction: %v", err)
+	}
+
+	var flags []string
+	if replaceable {
+		flags = append(flags, string(features.RBF))
+	}
+
+	tx, err := c.cfg.RPCClient.GetTransaction(txHash)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable get transaction by hash: %v", err)
+	}
+
+	entries := make([]btcjson.ListTransactionsResult, len(tx.Details))
+	for i, detail := range tx.Details {
+		entries[i] = btcjson.ListTransactionsResult{
+			TxID:     txHash.String(),
+			Address:  detail.Address,
+			Category: detail.Category,
+		}
+	}
+	internal := c.internalEntries(entries)
+
+	// Fee is split in satoshis by the output sizes, as the size of the
+	// transaction, and thereby its fee, grows with every output.
+	fee := decAmount2Sat(decimal.NewFromFloat(tx.Fee).Abs().Round(8))
+	fees := splitFee(fee, sizes)
+
+	payments := make([]*connectors.Payment, len(outputs))
+	for i, receipt := range receipts {
+		system := connectors.External
+		if internal[txHash.String()+":"+receipt] {
+			system = connectors.Internal
+		}
+
+		payment := &connectors.Payment{
+			UpdatedAt: connectors.ConvertTimeToMilliSeconds(time.Now()),
+			Status:    connectors.Pending,
+			Direction: connectors.Outgoing,
+			System:    system,
+			Receipt:   receipt,
+			Asset:     c.cfg.Asset,
+			Media:     connectors.Blockchain,
+			Amount:    amounts[i],
+			MediaFee:  sat2DecAmount(fees[i]),
+			MediaID:   txHash.String(),
+			Flags:     flags,
+		}
+
+		payment.PaymentID, err = payment.GenPaymentID()
+		if err != nil {
+			m.AddError(metrics.HighSeverity)
+			return nil, errors.Errorf("unable generate payment id: %v", err)
+		}
+
+		if err := c.cfg.PaymentStore.SavePayment(payment); err != nil {
+			m.AddError(metrics.HighSeverity)
+			return nil, errors.Errorf("unable save payment id: %v", err)
+		}
+
+		payments[i] = payment
+	}
+
+	return payments, nil
+}
+
+// SendTimeLockedPayment sends payment on the pay-to-witness-script-hash
+// address of the script, which could be spent by the recipient key only
+// after the lock time.
+//
+// NOTE: Part of the connectors.TimeLocker interface.
+func (c *Connector) SendTimeLockedPayment(recipientPubKey, amount string,
+	lockTime uint32) (*connectors.Payment, *connectors.TimeLock, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	if !supportsTimeLocks(c.cfg.Asset) || c.cfg.TimeLocksStore == nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, nil, errors.Errorf("time locks are not supported for "+
+			"asset(%v)", c.cfg.Asset)
+	}
+
+	// Only compressed keys are standard in the witness scripts.
+	pubKeyBytes, err := hex.DecodeString(recipientPubKey)
+	if err != nil || len(pubKeyBytes) != btcec.PubKeyBytesLenCompressed {
+		m.AddError(metrics.LowSeverity)
+		return nil, nil, errors.Errorf("recipient key should be hex " +
+			"encoded compressed public key")
+	}
+
+	if _, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256()); err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, nil, errors.Errorf("invalid recipient key: %v", err)
+	}
+
+	amtInBtc, err := decimal.NewFromString(amount)
+	if err != nil || amtInBtc.Sign() <= 0 {
+		m.AddError(metrics.LowSeverity)
+		return nil, nil, errors.Errorf("invalid amount(%v)", amount)
+	}
+
+	script, scriptAddress, err := timeLockScript(pubKeyBytes, lockTime,
+		c.netParams)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, nil, err
+	}
+
+	receipt, err := encodeAddress(c.cfg.Asset, scriptAddress)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, nil, errors.Errorf("unable to encode script "+
+			"address: %v", err)
+	}
+
+	txHash, err := c.cfg.RPCClient.SendToAddress(scriptAddress,
+		decAmount2Sat(amtInBtc))
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, nil, errors.Errorf("unable send transaction: %v", err)
+	}
+
+	tx, err := c.cfg.RPCClient.GetTransaction(txHash)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, nil, errors.Errorf("unable get transaction by hash: %v",
+			err)
+	}
+
+	payment := &connectors.Payment{
+		UpdatedAt: connectors.ConvertTimeToMilliSeconds(time.Now()),
+		Status:    connectors.Pending,
+		Direction: connectors.Outgoing,
+		System:    connectors.External,
+		Receipt:   receipt,
+		Asset:     c.cfg.Asset,
+		Media:     connectors.Blockchain,
+		Amount:    amtInBtc,
+		MediaFee:  decimal.NewFromFloat(tx.Fee).Abs().Round(8),
+		MediaID:   txHash.String(),
+	}
+
+	payment.PaymentID, err = payment.GenPaymentID()
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, nil, errors.Errorf("unable generate payment id: %v", err)
+	}
+
+	lock := &connectors.TimeLock{
+		PaymentID:       payment.PaymentID,
+		Asset:           c.cfg.Asset,
+		Address:         receipt,
+		RedeemScript:    script,
+		RecipientPubKey: recipientPubKey,
+		LockTime:        lockTime,
+		CreatedAt:       payment.UpdatedAt,
+	}
+
+	// Payment is saved along with its lock in one transaction, so that
+	// payment is never listed without the script needed to spend it.
+	err = c.cfg.TimeLocksStore.SaveTimeLockedPayment(payment, lock)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, nil, errors.Errorf("unable save time-locked payment of "+
+			"tx(%v): %v", txHash, err)
+	}
+
+	if notifier, ok := c.cfg.PaymentStore.(connectors.PaymentsNotifier); ok {
+		notifier.NotifyPayment(payment)
+	}
+
+	return payment, lock, nil
+}
+
+// BestHeight returns the height of the best block.
+//
+// NOTE: Part of the connectors.TimeLocker interface.
+func (c *Connector) BestHeight() (int64, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	hash, err := c.cfg.RPCClient.GetBestBlockHash()
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return 0, errors.Errorf("unable to get best block hash: %v", err)
+	}
+
+	block, err := c.cfg.RPCClient.GetBlockVerboseByHash(hash)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return 0, errors.Errorf("unable to get best block: %v", err)
+	}
+
+	return block.Height, nil
+}
+
+// ChainState returns current state of the daemon chain.
+//
+// NOTE: Part of the connectors.ChainReporter interface.
+func (c *Connector) ChainState() (*connectors.ChainState, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	resp, err := c.client.GetBlockChainInfo()
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to get blockchain info: %v", err)
+	}
+
+	return &connectors.ChainState{
+		Height:        resp.Blocks,
+		NetworkHeight: resp.Headers,
+		Synced:        resp.Blocks >= resp.Headers,
+	}, nil
+}
+
+// AssetParams returns the parameters of the asset. Outputs below the dust
+// limit of the default relay fee aren't relayed by the daemons, that is
+// why it is the minimum amount of the payment.
+//
+// NOTE: Part of the connectors.AssetReporter interface.
+func (c *Connector) AssetParams() *connectors.AssetParams {
+	dustLimit := sat2DecAmount(txrules.GetDustThreshold(p2pkhOutputSize,
+		txrules.DefaultRelayFeePerKb))
+
+	return &connectors.AssetParams{
+		Decimals:         8,
+		MinAmount:        dustLimit,
+		DustLimit:        dustLimit,
+		MinConfirmations: int64(c.cfg.MinConfirmations),
+	}
+}
+
+// ValidateAddress takes the blockchain address, ensure its validity and
+// returns its canonical form.
+func (c *Connector) ValidateAddress(address string) (*connectors.AddressInfo, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	decodedAddress, err := decodeAddress(c.cfg.Asset, address, c.netParams.Name)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("invalid address: %v", err)
+	}
+
+	normalizedAddress, err := encodeAddress(c.cfg.Asset, decodedAddress)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("unable to normalize address: %v", err)
+	}
+
+	addressType, err := getAddressType(decodedAddress)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, err
+	}
+
+	return &connectors.AddressInfo{
+		Address: normalizedAddress,
+		Type:    addressType,
+		Net:     c.cfg.Net,
+	}, nil
+}
+
+// Runtime check to ensure that Connector implements
+// connectors.AddressOwnershipChecker interface.
+var _ connectors.AddressOwnershipChecker = (*Connector)(nil)
+
+// IsOurAddress returns true if the wallet has the key of the address, or if
+// address is the deposit address derived by the external address provider.
+// Addresses which are only watched are not ours.
+//
+// NOTE: Part of the connectors.AddressOwnershipChecker interface.
+func (c *Connector) IsOurAddress(address string) (bool, error) {
 	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
 
-	_, err := decodeAddress(c.cfg.Asset, address, c.netParams.Name)
+	decodedAddress, err := decodeAddress(c.cfg.Asset, address, c.netParams.Name)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return false, errors.Errorf("invalid address: %v", err)
+	}
+
+	info, err := c.cfg.RPCClient.GetAddressInfo(decodedAddress)
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return false, errors.Errorf("unable to get address info: %v", err)
+	}
+
+	if info.IsMine {
+		return true, nil
+	}
+
+	return info.IsWatchOnly && info.Label == delegatedAccount, nil
+}
+
+// Runtime check to ensure that Connector implements
+// connectors.MessageSigner interface.
+var _ connectors.MessageSigner = (*Connector)(nil)
+
+// SignMessage signs the message with the private key of the address, which
+// should belong to the wallet.
+//
+// NOTE: Daemon signs messages only with the keys of the legacy addresses.
+//
+// NOTE: Part of the connectors.MessageSigner interface.
+func (c *Connector) SignMessage(address, message string) (string, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	decodedAddress, err := decodeAddress(c.cfg.Asset, address, c.netParams.Name)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return "", errors.Errorf("invalid address: %v", err)
+	}
+
+	signature, err := c.cfg.RPCClient.SignMessage(decodedAddress, message)
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return "", errors.Errorf("unable to sign message: %v", err)
+	}
+
+	return signature, nil
+}
+
+// VerifyMessage returns true if the message has been signed with the
+// private key of the address, address doesn't have to belong to the
+// wallet.
+//
+// NOTE: Part of the connectors.MessageSigner interface.
+func (c *Connector) VerifyMessage(address, signature,
+	message string) (bool, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	decodedAddress, err := decodeAddress(c.cfg.Asset, address, c.netParams.Name)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return false, errors.Errorf("invalid address: %v", err)
+	}
+
+	valid, err := c.cfg.RPCClient.VerifyMessage(decodedAddress, signature,
+		message)
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return false, errors.Errorf("unable to verify message: %v", err)
+	}
+
+	return valid, nil
+}
+
+// WatchAddress imports the address in the daemon wallet as watch-only, so
+// that its transactions would be returned along with ours.
+//
+// NOTE: Previous transactions of the address are not rescanned, only new
+// activity is tracked.
+func (c *Connector) WatchAddress(address string) error {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	decodedAddress, err := decodeAddress(c.cfg.Asset, address, c.netParams.Name)
 	if err != nil {
 		m.AddError(metrics.LowSeverity)
 		return errors.Errorf("invalid address: %v", err)
 	}
 
+	err = c.cfg.RPCClient.ImportAddress(decodedAddress, watchAccount, false)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return errors.Errorf("unable to import address: %v", err)
+	}
+
 	return nil
 }
 
+// normalizeAddress returns the canonical form of the address, which was
+// returned by the daemon. If address couldn't be decoded it is returned as is.
+func (c *Connector) normalizeAddress(address string) string {
+	decodedAddress, err := decodeAddress(c.cfg.Asset, address, c.netParams.Name)
+	if err != nil {
+		return address
+	}
+
+	normalizedAddress, err := encodeAddress(c.cfg.Asset, decodedAddress)
+	if err != nil {
+		return address
+	}
+
+	return normalizedAddress
+}
+
+// internalEntries returns the set of "txid:address" keys of the wallet
+// transaction entries, which are moving funds inside our wallet, payments
+// of such entries are internal.
+func (c *Connector) internalEntries(
+	entries []btcjson.ListTransactionsResult) map[string]bool {
+
+	var payments []*connectors.Payment
+	for _, entry := range entries {
+		var direction connectors.PaymentDirection
+		switch entry.Category {
+		case "send":
+			direction = connectors.Outgoing
+		case "receive":
+			direction = connectors.Incoming
+		default:
+			continue
+		}
+
+		payments = append(payments, &connectors.Payment{
+			Direction: direction,
+			System:    connectors.External,
+			Receipt:   c.normalizeAddress(entry.Address),
+			MediaID:   entry.TxID,
+		})
+	}
+
+	internal := make(map[string]bool)
+	for _, payment := range connectors.MarkInternalPayments(payments) {
+		internal[payment.MediaID+":"+payment.Receipt] = true
+	}
+
+	return internal
+}
+
 // EstimateFee estimate fee for the transaction with the given sending
 // amount.
 //
 // NOTE: Fee depends on amount because of the number amount of inputs
 // which has to be used to construct the transaction.
 func (c *Connector) EstimateFee(amount string) (decimal.Decimal, error) {
+	return c.EstimateFeeWithTarget(amount, defaultConfTarget)
+}
+
+// EstimateFeeWithTarget estimate fee for the transaction with the given
+// sending amount, which should be confirmed within the given number of
+// blocks.
+//
+// NOTE: Part of the connectors.FeeTargetEstimator interface.
+func (c *Connector) EstimateFeeWithTarget(amount string,
+	confTarget uint32) (decimal.Decimal, error) {
 	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
@@ -367,7 +1040,7 @@ func (c *Connector) EstimateFee(amount string) (decimal.Decimal, error) {
 	// calculate its size.
 	size := decimal.New(225, 0)
 
-	feeRateSatoshiPerByte := c.getFeeRate()
+	feeRateSatoshiPerByte := c.getFeeRate(confTarget)
 	feeInSatoshis := feeRateSatoshiPerByte.Mul(size)
 	feeInBitcoin := feeInSatoshis.Div(satoshiPerBitcoin)
 
@@ -375,16 +1048,17 @@ func (c *Connector) EstimateFee(amount string) (decimal.Decimal, error) {
 }
 
 // getFeeRate estimates the approximate rate in sat/byte needed for a
-// transaction to begin confirmation within 2 blocks if possible.
+// transaction to begin confirmation within the given number of blocks if
+// possible.
 //
 // NOTE: Uses virtual transaction size as defined in BIP 141
 // (witness data is discounted).
-func (c *Connector) getFeeRate() decimal.Decimal {
+func (c *Connector) getFeeRate(confTarget uint32) decimal.Decimal {
 	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
 
-	feeRate, err := c.client.EstimateFee()
+	feeRate, err := c.client.EstimateFee(confTarget)
 	if err != nil {
 		if c.cfg.Net == "mainnet" {
 			// In case of mainnet such situation happens rarely for that
@@ -429,7 +1103,10 @@ func (c *Connector) syncPaymentState() error {
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
 
-	allTXs, err := c.cfg.RPCClient.ListTransactionByLabel(allAccounts, math.MaxInt16, 0)
+	allTXs, err := c.fetchTransactions(func(count, from int) (
+		[]btcjson.ListTransactionsResult, error) {
+		return c.cfg.RPCClient.ListTransactionByLabel(allAccounts, count, from)
+	})
 	if err != nil {
 		m.AddError(metrics.HighSeverity)
 		return err
@@ -456,6 +1133,10 @@ func (c *Connector) syncPaymentState() error {
 	c.log.Debugf("sync %v payments, last tx counter was %v",
 		len(newTXS), txCounter)
 
+	// Both entries of the transaction which moves funds inside our wallet
+	// are needed to detect it, that is why all transactions are used.
+	internal := c.internalEntries(allTXs)
+
 	for _, tx := range newTXS {
 		var status connectors.PaymentStatus
 		if tx.Confirmations >= int64(c.cfg.MinConfirmations) {
@@ -489,17 +1170,35 @@ func (c *Connector) syncPaymentState() error {
 			fee = decimal.NewFromFloat(*tx.Fee).Abs().Round(8)
 		}
 
+		receipt := c.normalizeAddress(tx.Address)
+
+		system := connectors.External
+		if internal[tx.TxID+":"+receipt] {
+			system = connectors.Internal
+		}
+
+		// Unconfirmed deposits are accepted right away if zero-conf is
+		// enabled for the tenant of the deposit address, and it is
+		// recorded on the payment, so that the risk could be analysed.
+		var flags []string
+		if status == connectors.Pending && direction == connectors.Incoming &&
+			c.cfg.Features.IsEnabled(features.ZeroConf, c.receiptTenant(receipt)) {
+			status = connectors.Completed
+			flags = append(flags, string(features.ZeroConf))
+		}
+
 		p := &connectors.Payment{
 			UpdatedAt: connectors.ConvertTimeToMilliSeconds(time.Now()),
 			Status:    status,
 			Direction: direction,
-			System:    connectors.External,
-			Receipt:   tx.Address,
+			System:    system,
+			Receipt:   receipt,
 			Asset:     c.cfg.Asset,
 			Media:     connectors.Blockchain,
 			Amount:    decimal.NewFromFloat(tx.Amount).Abs().Round(8),
 			MediaFee:  fee,
 			MediaID:   tx.TxID,
+			Flags:     flags,
 		}
 
 		p.PaymentID, err = p.GenPaymentID()
@@ -509,9 +1208,31 @@ func (c *Connector) syncPaymentState() error {
 				tx.TxID, err)
 		}
 
-		if _, err := c.cfg.PaymentStore.PaymentByID(p.PaymentID); err != nil {
+		// Id of the payment is derived from its classification, which
+		// might change if daemon has been reindexed or resynced, that is
+		// why received output is kept bound to the payment as which it
+		// has been recorded first.
+		if direction == connectors.Incoming {
+			depositID := fmt.Sprintf("%v:%v", tx.TxID, tx.Vout)
+			p.PaymentID, err = c.recordDeposit(depositID, p.PaymentID)
+			if err != nil {
+				m.AddError(metrics.HighSeverity)
+				return errors.Errorf("unable to record deposit(%v): %v",
+					depositID, err)
+			}
+		}
+
+		if oldPayment, err := c.cfg.PaymentStore.PaymentByID(p.PaymentID); err != nil {
 			c.log.Infof("New payment(%v) has been found: %v", p.PaymentID,
 				spew.Sdump(p))
+		} else {
+			// Daemon doesn't return OP_RETURN data in the list of
+			// transactions, so memo attached on sending should be kept,
+			// as well as the flags it was sent or accepted with.
+			p.Memo = oldPayment.Memo
+			if len(oldPayment.Flags) != 0 {
+				p.Flags = oldPayment.Flags
+			}
 		}
 
 		if err := c.cfg.PaymentStore.SavePayment(p); err != nil {
@@ -538,6 +1259,44 @@ func (c *Connector) syncPaymentState() error {
 	return nil
 }
 
+// recordDeposit binds the deposit to the payment, and returns id of the
+// payment to which deposit has been bound first.
+func (c *Connector) recordDeposit(depositID, paymentID string) (string,
+	error) {
+	if c.cfg.DepositsStore == nil {
+		return paymentID, nil
+	}
+
+	boundID, err := c.cfg.DepositsStore.RecordDeposit(c.cfg.Asset,
+		depositID, paymentID)
+	if err != nil {
+		return "", err
+	}
+
+	if boundID != paymentID {
+		c.log.Warnf("Deposit(%v) has been already recorded as payment(%v), "+
+			"skip derived payment(%v)", depositID, boundID, paymentID)
+	}
+
+	return boundID, nil
+}
+
+// receiptTenant returns the tenant of the deposit address, or empty string
+// if it couldn't be found.
+func (c *Connector) receiptTenant(receipt string) string {
+	if c.cfg.ReceiptsStore == nil {
+		return ""
+	}
+
+	tenant, err := c.cfg.ReceiptsStore.ReceiptTenant(receipt)
+	if err != nil {
+		c.log.Errorf("Unable to get tenant of receipt(%v): %v", receipt, err)
+		return ""
+	}
+
+	return tenant
+}
+
 // reportMetrics is used to report necessary health metrics about internal
 // state of the connector.
 func (c *Connector) reportMetrics() error {
@@ -598,3 +1357,128 @@ func (c *Connector) reportMetrics() error {
 
 	return nil
 }
+
+// syncWatchEvents looks for the transactions which are touching watched
+// addresses and produces events for them. Funding of the watched address
+// is also published to the payments subscribers as the received payment,
+// first when it is detected and then when it is confirmed.
+//
+// NOTE: Wallet doesn't expose which watch-only address has been spent in
+// the "send" entries, that is why only incoming activity is tracked.
+func (c *Connector) syncWatchEvents() error {
+	if c.cfg.WatchStore == nil {
+		return nil
+	}
+
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	addresses, err := c.cfg.WatchStore.ListWatchAddresses(c.cfg.Asset, "")
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return errors.Errorf("unable to list watch addresses: %v", err)
+	}
+
+	watched := make(map[string]*connectors.WatchAddress, len(addresses))
+	for _, address := range addresses {
+		if address.Inactive {
+			continue
+		}
+
+		watched[address.Address] = address
+	}
+
+	if len(watched) == 0 {
+		return nil
+	}
+
+	txs, err := c.fetchTransactions(func(count, from int) (
+		[]btcjson.ListTransactionsResult, error) {
+		return c.cfg.RPCClient.ListWatchOnlyTransactionByLabel(watchAccount,
+			count, from)
+	})
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return err
+	}
+
+	for _, tx := range txs {
+		if tx.Category != "receive" {
+			continue
+		}
+
+		address := c.normalizeAddress(tx.Address)
+		watchAddress, ok := watched[address]
+		if !ok {
+			continue
+		}
+
+		event := &connectors.WatchEvent{
+			CreatedAt: connectors.NowInMilliSeconds(),
+			Group:     watchAddress.Group,
+			Asset:     c.cfg.Asset,
+			Address:   address,
+			Account:   watchAddress.Account,
+			Direction: connectors.Incoming,
+			Amount:    decimal.NewFromFloat(tx.Amount).Abs().Round(8),
+			TxID:      tx.TxID,
+		}
+
+		created, err := connectors.ProcessWatchEvent(c.cfg.WatchStore,
+			c.cfg.WatchNotifier, event)
+		if err != nil {
+			m.AddError(metrics.HighSeverity)
+			return errors.Errorf("unable to process watch event: %v", err)
+		}
+
+		c.notifyWatchPayment(event, tx.Confirmations, created)
+	}
+
+	return nil
+}
+
+// notifyWatchPayment publishes the received payment of the watched address
+// if its event has been just created, or if its funding has been confirmed
+// since the last sync.
+//
+// NOTE: Unconfirmed events are remembered in memory, after the restart
+// they are picked up again by the next sync, but funding which has been
+// confirmed in the meantime isn't published.
+func (c *Connector) notifyWatchPayment(event *connectors.WatchEvent,
+	confirmations int64, created bool) {
+	if c.pendingWatchPayments == nil {
+		c.pendingWatchPayments = make(map[string]struct{})
+	}
+
+	minConfirmations := int64(c.cfg.MinConfirmations)
+
+	var status connectors.PaymentStatus
+	switch {
+	case confirmations >= minConfirmations:
+		status = connectors.Completed
+	case confirmations < 0:
+		status = connectors.Failed
+	default:
+		status = connectors.Pending
+	}
+
+	_, pending := c.pendingWatchPayments[event.EventID]
+	if status == connectors.Pending {
+		c.pendingWatchPayments[event.EventID] = struct{}{}
+	} else {
+		delete(c.pendingWatchPayments, event.EventID)
+	}
+
+	if !created && !(pending && status != connectors.Pending) {
+		return
+	}
+
+	notifier, ok := c.cfg.PaymentStore.(connectors.PaymentsNotifier)
+	if !ok {
+		return
+	}
+
+	notifier.NotifyPayment(event.ReceivedPayment(status, confirmations,
+		minConfirmations-confirmations))
+}
@@ -0,0 +1,207 @@
+package bitcoind_simple
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/connectors/rpc"
+	"github.com/bitlum/connector/db/inmemory"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/bitlum/go-bitcoind-rpc/btcjson"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btclog"
+	"github.com/shopspring/decimal"
+)
+
+// watchRPCClient is the daemon wallet with the given watch-only
+// transactions.
+type watchRPCClient struct {
+	rpc.Client
+
+	entries []btcjson.ListTransactionsResult
+}
+
+func (c *watchRPCClient) DaemonName() string {
+	return "bitcoind"
+}
+
+func (c *watchRPCClient) ListWatchOnlyTransactionByLabel(label string, count,
+	from int) ([]btcjson.ListTransactionsResult, error) {
+	if from != 0 {
+		return nil, nil
+	}
+	return c.entries, nil
+}
+
+// watchStore keeps the watched addresses and the events in memory.
+type watchStore struct {
+	connectors.WatchStore
+
+	addresses []*connectors.WatchAddress
+	events    map[string]*connectors.WatchEvent
+}
+
+func (s *watchStore) ListWatchAddresses(asset connectors.Asset,
+	group string) ([]*connectors.WatchAddress, error) {
+	return s.addresses, nil
+}
+
+func (s *watchStore) WatchEventExists(eventID string) (bool, error) {
+	_, ok := s.events[eventID]
+	return ok, nil
+}
+
+func (s *watchStore) SaveWatchEvent(event *connectors.WatchEvent) error {
+	s.events[event.EventID] = event
+	return nil
+}
+
+// TestSyncWatchPayments checks that funding of the watched address is
+// published to the payments subscribers as the received payment when it is
+// detected and when it is confirmed, and that it isn't saved in the store.
+func TestSyncWatchPayments(t *testing.T) {
+	const (
+		watched = "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e"
+		other   = "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"
+	)
+
+	client := &watchRPCClient{}
+	store := &watchStore{
+		addresses: []*connectors.WatchAddress{{
+			Group:   "cold",
+			Asset:   connectors.BTC,
+			Address: watched,
+			Account: "vault",
+		}},
+		events: make(map[string]*connectors.WatchEvent),
+	}
+
+	payments := connectors.NewPaymentsBroadcaster(
+		inmemory.NewMemoryPaymentsStore())
+	subscription := payments.SubscribePayments()
+	defer subscription.Cancel()
+
+	c := &Connector{
+		cfg: &Config{
+			Asset:            connectors.BTC,
+			MinConfirmations: 2,
+			SyncWorkers:      1,
+			RPCClient:        client,
+			PaymentStore:     payments,
+			WatchStore:       store,
+			Metrics:          crypto.DisabledBackend,
+		},
+		client:    client,
+		netParams: &chaincfg.MainNetParams,
+		log: &common.NamedLogger{
+			Name:   "BTC",
+			Logger: btclog.Disabled,
+		},
+	}
+
+	sync := func(confirmations int64) {
+		t.Helper()
+
+		client.entries = []btcjson.ListTransactionsResult{{
+			TxID:          "txid",
+			Address:       watched,
+			Category:      "receive",
+			Amount:        1.5,
+			Confirmations: confirmations,
+		}, {
+			TxID:          "txid",
+			Address:       other,
+			Category:      "receive",
+			Amount:        0.5,
+			Confirmations: confirmations,
+		}}
+
+		if err := c.syncWatchEvents(); err != nil {
+			t.Fatalf("unable to sync watch events: %v", err)
+		}
+	}
+
+	expectPayment := func(status connectors.PaymentStatus) *connectors.Payment {
+		t.Helper()
+
+		select {
+		case payment := <-subscription.Updates:
+			if payment.Status != status {
+				t.Fatalf("wrong status of received payment: %v",
+					payment.Status)
+			}
+			return payment
+		default:
+			t.Fatalf("received payment hasn't been published")
+		}
+		return nil
+	}
+
+	expectNothing := func() {
+		t.Helper()
+
+		select {
+		case payment := <-subscription.Updates:
+			t.Fatalf("unexpected payment(%v) is published: %v",
+				payment.PaymentID, payment.Status)
+		default:
+		}
+	}
+
+	// Unconfirmed funding is published as soon as it is detected, and only
+	// once until it is confirmed.
+	sync(0)
+	payment := expectPayment(connectors.Pending)
+	if len(store.events) != 1 {
+		t.Fatalf("watch event hasn't been created")
+	}
+
+	for eventID := range store.events {
+		if payment.PaymentID != eventID {
+			t.Fatalf("payment id(%v) should be the id of event(%v)",
+				payment.PaymentID, eventID)
+		}
+	}
+
+	if payment.Direction != connectors.Incoming ||
+		payment.Receipt != watched ||
+		payment.Account != "vault" ||
+		payment.MediaID != "txid" ||
+		!payment.Amount.Equal(decimal.New(15, -1)) ||
+		payment.Metadata[connectors.WatchGroupLabel] != "cold" {
+		t.Fatalf("wrong received payment: %v", payment)
+	}
+
+	details, ok := payment.Detail.(*connectors.BlockchainPendingDetails)
+	if !ok || details.ConfirmationsLeft != 2 {
+		t.Fatalf("wrong details of pending payment: %v", payment.Detail)
+	}
+
+	sync(1)
+	expectNothing()
+
+	sync(2)
+	expectPayment(connectors.Completed)
+
+	sync(3)
+	expectNothing()
+
+	// Funds of the watched address don't belong to us.
+	if _, err := payments.PaymentByID(payment.PaymentID); err == nil {
+		t.Fatalf("received payment of watched address shouldn't be saved")
+	}
+
+	// Unconfirmed funding is picked up again after the restart, so that
+	// its confirmation is published.
+	store.events = make(map[string]*connectors.WatchEvent)
+	sync(0)
+	expectPayment(connectors.Pending)
+
+	c.pendingWatchPayments = nil
+	sync(0)
+	expectNothing()
+
+	sync(2)
+	expectPayment(connectors.Completed)
+}
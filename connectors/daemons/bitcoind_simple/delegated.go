@@ -0,0 +1,131 @@
+package bitcoind_simple
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/bitlum/go-bitcoind-rpc/btcjson"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Runtime check to ensure that Connector implements
+// connectors.DepositAddressImporter interface.
+var _ connectors.DepositAddressImporter = (*Connector)(nil)
+
+// ImportDepositAddress imports deposit address which has been derived by
+// the external address provider into the daemon as watch-only, so that
+// deposits on it are synced as incoming payments.
+//
+// NOTE: Part of the connectors.DepositAddressImporter interface.
+func (c *Connector) ImportDepositAddress(address string) error {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	if !c.cfg.DelegatedAddresses {
+		return errors.New("delegated addresses are disabled")
+	}
+
+	decodedAddress, err := decodeAddress(c.cfg.Asset, address, c.netParams.Name)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return errors.Errorf("invalid address: %v", err)
+	}
+
+	// Address has just been derived, that is why there is nothing to
+	// rescan.
+	err = c.cfg.RPCClient.ImportAddress(decodedAddress, delegatedAccount,
+		false)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return errors.Errorf("unable to import address: %v", err)
+	}
+
+	return nil
+}
+
+// syncDelegatedPayments saves incoming payments on the deposit addresses
+// which have been derived by the external address provider. Such addresses
+// are watch-only, that is why their transactions aren't listed along with
+// the wallet ones, and they are fetched separately on every sync.
+//
+// NOTE: Funds on these addresses couldn't be spent by the daemon, that is
+// why they aren't included in the balance.
+func (c *Connector) syncDelegatedPayments() error {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	txs, err := c.fetchTransactions(func(count, from int) (
+		[]btcjson.ListTransactionsResult, error) {
+		return c.cfg.RPCClient.ListWatchOnlyTransactionByLabel(
+			delegatedAccount, count, from)
+	})
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return err
+	}
+
+	for _, tx := range txs {
+		// Conflicted transactions have negative number of confirmations.
+		if tx.Category != "receive" || tx.Confirmations < 0 {
+			continue
+		}
+
+		status := connectors.Pending
+		if tx.Confirmations >= int64(c.cfg.MinConfirmations) {
+			status = connectors.Completed
+		}
+
+		p := &connectors.Payment{
+			UpdatedAt: connectors.ConvertTimeToMilliSeconds(time.Now()),
+			Status:    status,
+			Direction: connectors.Incoming,
+			System:    connectors.External,
+			Receipt:   c.normalizeAddress(tx.Address),
+			Asset:     c.cfg.Asset,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.NewFromFloat(tx.Amount).Abs().Round(8),
+			MediaFee:  decimal.Zero,
+			MediaID:   tx.TxID,
+		}
+
+		p.PaymentID, err = p.GenPaymentID()
+		if err != nil {
+			m.AddError(metrics.HighSeverity)
+			return errors.Errorf("unable to generate payment id, txid(%v): %v",
+				tx.TxID, err)
+		}
+
+		depositID := fmt.Sprintf("%v:%v", tx.TxID, tx.Vout)
+		p.PaymentID, err = c.recordDeposit(depositID, p.PaymentID)
+		if err != nil {
+			m.AddError(metrics.HighSeverity)
+			return errors.Errorf("unable to record deposit(%v): %v",
+				depositID, err)
+		}
+
+		// All transactions are listed on every sync, that is why payment
+		// is saved only if it is new or its status has been changed.
+		oldPayment, err := c.cfg.PaymentStore.PaymentByID(p.PaymentID)
+		if err == nil && oldPayment.Status == status {
+			continue
+		}
+
+		if err := c.cfg.PaymentStore.SavePayment(p); err != nil {
+			m.AddError(metrics.HighSeverity)
+			return errors.Errorf("unable to save payment(%v): %v",
+				p.PaymentID, err)
+		}
+
+		c.log.Infof("Delegated deposit payment(%v) is %v", p.PaymentID,
+			status)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package bitcoind_simple
+
+import (
+	"fmt"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/go-bitcoind-rpc/btcjson"
+	"github.com/go-errors/errors"
+)
+
+// txPageSize is the number of wallet transactions which are requested from
+// the daemon at once.
+const txPageSize = 1000
+
+// listFunc returns page of wallet transactions, skipping given number of
+// the most recent ones.
+type listFunc func(count, from int) ([]btcjson.ListTransactionsResult, error)
+
+// fetchTransactions concurrently pages through all wallet transactions
+// and returns them in the order of the daemon, from the oldest one. Number
+// of concurrent requests to daemon is bounded by number of sync workers.
+//
+// NOTE: Transactions which have been received during the fetch shift the
+// pages, and the entries on the boundaries are returned twice by daemon,
+// that is why they are deduplicated.
+func (c *Connector) fetchTransactions(list listFunc) (
+	[]btcjson.ListTransactionsResult, error) {
+
+	// Pages are counted from the most recent transactions.
+	var pages [][]btcjson.ListTransactionsResult
+	for {
+		round := make([][]btcjson.ListTransactionsResult, c.cfg.SyncWorkers)
+		offset := len(pages)
+
+		fetch := func(i int) error {
+			from := (offset + i) * txPageSize
+			txs, err := list(txPageSize, from)
+			if err != nil {
+				return errors.Errorf("unable to list transactions "+
+					"from(%v): %v", from, err)
+			}
+
+			round[i] = txs
+			return nil
+		}
+
+		err := common.RunWorkers(c.cfg.SyncWorkers, len(round), c.quit,
+			fetch)
+		if err != nil {
+			return nil, err
+		}
+
+		pages = append(pages, round...)
+
+		if len(round[len(round)-1]) < txPageSize {
+			break
+		}
+	}
+
+	seen := make(map[string]struct{})
+	var txs []btcjson.ListTransactionsResult
+	for i := len(pages) - 1; i >= 0; i-- {
+		for _, tx := range pages[i] {
+			key := fmt.Sprintf("%v:%v:%v:%v:%v", tx.TxID, tx.Vout,
+				tx.Category, tx.Address, tx.Amount)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs, nil
+}
@@ -0,0 +1,208 @@
+package bitcoind_simple
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/connectors/rpc"
+	"github.com/bitlum/connector/metrics"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/go-errors/errors"
+)
+
+// Runtime check to ensure that Connector implements
+// connectors.QuarantineLocker interface.
+var _ connectors.QuarantineLocker = (*Connector)(nil)
+
+// outPoint returns the key of the output, which is formatted the same way
+// as it is done by the wire.OutPoint, so that locked outputs could be
+// matched.
+func outPoint(txID string, vout uint32) string {
+	return fmt.Sprintf("%v:%v", txID, vout)
+}
+
+// lockedOutPoints returns the set of the outputs which are locked in the
+// daemon.
+func (c *Connector) lockedOutPoints() (map[string]struct{}, error) {
+	locked, err := c.cfg.RPCClient.ListLockUnspent()
+	if err != nil {
+		return nil, errors.Errorf("unable to list locked unspent: %v", err)
+	}
+
+	lockedOutputs := make(map[string]struct{}, len(locked))
+	for _, output := range locked {
+		lockedOutputs[output.String()] = struct{}{}
+	}
+
+	return lockedOutputs, nil
+}
+
+// quarantinedPayments returns the incoming payments of the asset which are
+// held by the compliance review.
+func (c *Connector) quarantinedPayments() ([]*connectors.Payment, error) {
+	payments, _, err := c.cfg.PaymentStore.QueryPayments(
+		connectors.PaymentsQuery{
+			Asset:      c.cfg.Asset,
+			Media:      connectors.Blockchain,
+			Direction:  connectors.Incoming,
+			Quarantine: connectors.Quarantined,
+		})
+	if err != nil {
+		return nil, errors.Errorf("unable to fetch quarantined "+
+			"payments: %v", err)
+	}
+
+	return payments, nil
+}
+
+// paymentOutputs returns the unspent outputs which have been received by
+// the payments, output belongs to the incoming payment if it is the output
+// of the payment transaction on the payment address.
+func (c *Connector) paymentOutputs(unspent []rpc.UnspentInput,
+	payments []*connectors.Payment) []rpc.UnspentInput {
+
+	if len(payments) == 0 {
+		return nil
+	}
+
+	keys := make(map[string]struct{}, len(payments))
+	for _, payment := range payments {
+		keys[payment.MediaID+":"+payment.Receipt] = struct{}{}
+	}
+
+	var outputs []rpc.UnspentInput
+	for _, u := range unspent {
+		key := u.TxID + ":" + c.normalizeAddress(u.Address)
+		if _, ok := keys[key]; ok {
+			outputs = append(outputs, u)
+		}
+	}
+
+	return outputs
+}
+
+// lockPaymentOutputs locks the unspent outputs of the payments, outputs
+// which are already locked are skipped, because daemon refuses to lock
+// them again.
+func (c *Connector) lockPaymentOutputs(payments []*connectors.Payment) error {
+	if len(payments) == 0 {
+		return nil
+	}
+
+	unspent, err := c.cfg.RPCClient.ListUnspentMinMax(0, math.MaxInt32)
+	if err != nil {
+		return errors.Errorf("unable to list unspent: %v", err)
+	}
+
+	locked, err := c.lockedOutPoints()
+	if err != nil {
+		return err
+	}
+
+	for _, u := range c.paymentOutputs(unspent, payments) {
+		if _, ok := locked[outPoint(u.TxID, u.Vout)]; ok {
+			continue
+		}
+
+		if err := c.cfg.RPCClient.LockUnspent(u); err != nil {
+			return errors.Errorf("unable to lock output(%v): %v",
+				outPoint(u.TxID, u.Vout), err)
+		}
+
+		c.log.Infof("Output(%v) of quarantined payment is locked",
+			outPoint(u.TxID, u.Vout))
+	}
+
+	return nil
+}
+
+// lockQuarantined locks the unspent outputs of all quarantined payments.
+// Locks are kept by the daemon only in memory, that is why they are
+// restored on every sync, so that quarantined funds stay excluded from the
+// coin selection after the daemon is restarted.
+func (c *Connector) lockQuarantined() error {
+	payments, err := c.quarantinedPayments()
+	if err != nil {
+		return err
+	}
+
+	return c.lockPaymentOutputs(payments)
+}
+
+// LockPaymentOutputs locks the unspent outputs which have been received by
+// the incoming payment, so that they are not selected by the daemon as the
+// inputs of the sent payments, nor swept.
+//
+// NOTE: Part of the connectors.QuarantineLocker interface.
+func (c *Connector) LockPaymentOutputs(payment *connectors.Payment) error {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	if err := c.lockPaymentOutputs([]*connectors.Payment{payment}); err != nil {
+		m.AddError(metrics.HighSeverity)
+		return err
+	}
+
+	return nil
+}
+
+// UnlockPaymentOutputs unlocks the outputs which have been received by the
+// incoming payment. Outputs of the payment are taken from its
+// transaction, so that they are found regardless of whether daemon lists
+// the locked outputs as unspent.
+//
+// NOTE: Part of the connectors.QuarantineLocker interface.
+func (c *Connector) UnlockPaymentOutputs(payment *connectors.Payment) error {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	txHash, err := chainhash.NewHashFromStr(payment.MediaID)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return errors.Errorf("invalid tx id(%v): %v", payment.MediaID, err)
+	}
+
+	tx, err := c.cfg.RPCClient.GetTransaction(txHash)
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return errors.Errorf("unable to get transaction(%v): %v",
+			payment.MediaID, err)
+	}
+
+	locked, err := c.lockedOutPoints()
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return err
+	}
+
+	for _, detail := range tx.Details {
+		if detail.Category != "receive" ||
+			c.normalizeAddress(detail.Address) != payment.Receipt {
+			continue
+		}
+
+		key := outPoint(payment.MediaID, detail.Vout)
+		if _, ok := locked[key]; !ok {
+			continue
+		}
+
+		err := c.cfg.RPCClient.UnlockUnspentInput(rpc.UnspentInput{
+			TxID: payment.MediaID,
+			Vout: detail.Vout,
+		})
+		if err != nil {
+			m.AddError(metrics.HighSeverity)
+			return errors.Errorf("unable to unlock output(%v): %v", key,
+				err)
+		}
+
+		c.log.Infof("Output(%v) of released payment is unlocked", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package bitcoind_simple
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/connectors/rpc"
+	"github.com/bitlum/connector/db/inmemory"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btclog"
+	"github.com/btcsuite/btcutil"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+// quarantineRPCClient is the daemon wallet with the given unspent outputs
+// and transactions, which keeps the locks of the outputs.
+type quarantineRPCClient struct {
+	rpc.Client
+
+	unspent []rpc.UnspentInput
+	txs     map[string]*rpc.Transaction
+	locked  map[wire.OutPoint]struct{}
+}
+
+func (c *quarantineRPCClient) DaemonName() string {
+	return "bitcoind"
+}
+
+func (c *quarantineRPCClient) outPoint(input rpc.UnspentInput) wire.OutPoint {
+	hash, _ := chainhash.NewHashFromStr(input.TxID)
+	return wire.OutPoint{Hash: *hash, Index: input.Vout}
+}
+
+func (c *quarantineRPCClient) ListUnspentMinMax(minConf,
+	maxConf int) ([]rpc.UnspentInput, error) {
+	var unspent []rpc.UnspentInput
+	for _, u := range c.unspent {
+		if u.Confirmations >= int64(minConf) {
+			unspent = append(unspent, u)
+		}
+	}
+	return unspent, nil
+}
+
+func (c *quarantineRPCClient) ListLockUnspent() ([]*wire.OutPoint, error) {
+	var locked []*wire.OutPoint
+	for outPoint := range c.locked {
+		outPoint := outPoint
+		locked = append(locked, &outPoint)
+	}
+	return locked, nil
+}
+
+func (c *quarantineRPCClient) LockUnspent(input rpc.UnspentInput) error {
+	if _, ok := c.locked[c.outPoint(input)]; ok {
+		return errors.New("output already locked")
+	}
+	c.locked[c.outPoint(input)] = struct{}{}
+	return nil
+}
+
+func (c *quarantineRPCClient) UnlockUnspentInput(input rpc.UnspentInput) error {
+	delete(c.locked, c.outPoint(input))
+	return nil
+}
+
+func (c *quarantineRPCClient) GetTransaction(
+	txHash *chainhash.Hash) (*rpc.Transaction, error) {
+	tx, ok := c.txs[txHash.String()]
+	if !ok {
+		return nil, errors.New("transaction not found")
+	}
+	return tx, nil
+}
+
+// TestQuarantinedOutputs checks that outputs of the quarantined payments
+// are locked, so that they are not selected by the daemon, that they are
+// not swept even if they are not locked, and that they are unlocked once
+// the payment is released.
+func TestQuarantinedOutputs(t *testing.T) {
+	const (
+		address1 = "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e"
+		address2 = "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3"
+		tx1      = "1111111111111111111111111111111111111111111111111111111111111111"
+		tx2      = "2222222222222222222222222222222222222222222222222222222222222222"
+	)
+
+	client := &quarantineRPCClient{
+		unspent: []rpc.UnspentInput{
+			{TxID: tx1, Vout: 0, Address: address1, Amount: 1, Confirmations: 6},
+			{TxID: tx1, Vout: 1, Address: address2, Amount: 2, Confirmations: 6},
+			{TxID: tx2, Vout: 0, Address: address1, Amount: 3, Confirmations: 6},
+		},
+		txs: map[string]*rpc.Transaction{
+			tx1: {
+				TxID: tx1,
+				Details: []rpc.TransactionDetails{
+					{Address: address1, Category: "receive", Vout: 0},
+					{Address: address2, Category: "receive", Vout: 1},
+				},
+			},
+		},
+		locked: make(map[wire.OutPoint]struct{}),
+	}
+
+	store := inmemory.NewMemoryPaymentsStore()
+	c := &Connector{
+		cfg: &Config{
+			Asset:            connectors.BTC,
+			MinConfirmations: 1,
+			RPCClient:        client,
+			PaymentStore:     store,
+			Metrics:          crypto.DisabledBackend,
+		},
+		client:    client,
+		netParams: &chaincfg.MainNetParams,
+		log: &common.NamedLogger{
+			Name:   "BTC",
+			Logger: btclog.Disabled,
+		},
+	}
+
+	payments := []*connectors.Payment{
+		{
+			PaymentID:  "quarantined",
+			Status:     connectors.Completed,
+			Direction:  connectors.Incoming,
+			System:     connectors.External,
+			Receipt:    address1,
+			Asset:      connectors.BTC,
+			Media:      connectors.Blockchain,
+			Amount:     decimal.New(1, 0),
+			MediaFee:   decimal.Zero,
+			MediaID:    tx1,
+			Quarantine: connectors.Quarantined,
+		},
+		{
+			PaymentID: "deposit",
+			Status:    connectors.Completed,
+			Direction: connectors.Incoming,
+			System:    connectors.External,
+			Receipt:   address1,
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(3, 0),
+			MediaFee:  decimal.Zero,
+			MediaID:   tx2,
+		},
+	}
+
+	for _, payment := range payments {
+		if err := store.SavePayment(payment); err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+	}
+
+	sweep := func(expectedInputs int, expectedTotal btcutil.Amount) {
+		t.Helper()
+
+		inputs, total, err := c.sweepInputs()
+		if err != nil {
+			t.Fatalf("unable to get sweep inputs: %v", err)
+		}
+
+		if len(inputs) != expectedInputs || total != expectedTotal {
+			t.Fatalf("wrong sweep inputs, expected %v inputs of %v, "+
+				"got %v inputs of %v", expectedInputs, expectedTotal,
+				len(inputs), total)
+		}
+
+		for _, input := range inputs {
+			if input.TxID == tx1 && input.Vout == 0 {
+				t.Fatalf("quarantined output is swept")
+			}
+		}
+	}
+
+	// Quarantined output isn't swept even before it has been locked.
+	sweep(2, 5e8)
+
+	// Only the output which has been received by the quarantined payment
+	// is locked, and already locked outputs are not locked again.
+	for i := 0; i < 2; i++ {
+		if err := c.lockQuarantined(); err != nil {
+			t.Fatalf("unable to lock quarantined outputs: %v", err)
+		}
+	}
+
+	quarantinedOutput := client.outPoint(client.unspent[0])
+	if _, ok := client.locked[quarantinedOutput]; !ok ||
+		len(client.locked) != 1 {
+		t.Fatalf("wrong locked outputs: %v", client.locked)
+	}
+
+	sweep(2, 5e8)
+
+	// Released payment is unlocked and its output is spendable again.
+	err := store.SetPaymentQuarantine("quarantined",
+		connectors.QuarantineReleased, "")
+	if err != nil {
+		t.Fatalf("unable to release payment: %v", err)
+	}
+
+	if err := c.UnlockPaymentOutputs(payments[0]); err != nil {
+		t.Fatalf("unable to unlock payment outputs: %v", err)
+	}
+
+	if len(client.locked) != 0 {
+		t.Fatalf("outputs are still locked: %v", client.locked)
+	}
+
+	sweep(3, 6e8)
+}
@@ -0,0 +1,186 @@
+package bitcoind_simple
+
+import (
+	"math"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/connectors/rpc"
+	"github.com/bitlum/connector/metrics"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+// dustLimit is the smallest value of the output which is relayed by the
+// daemons with the default relay fee.
+const dustLimit btcutil.Amount = 546
+
+// Runtime check to ensure that Connector implements
+// connectors.FundsSweeper interface.
+var _ connectors.FundsSweeper = (*Connector)(nil)
+
+// SweepFunds spends all confirmed unlocked outputs of the wallet in one
+// transaction to the address, fee is subtracted from the sent amount.
+// Outputs of the watch-only addresses are not ours, that is why they are
+// left intact.
+//
+// NOTE: Part of the connectors.FundsSweeper interface.
+func (c *Connector) SweepFunds(address string) (*connectors.Payment, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	decodedAddress, err := decodeAddress(c.cfg.Asset, address, c.netParams.Name)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("invalid address: %v", err)
+	}
+
+	receipt, err := encodeAddress(c.cfg.Asset, decodedAddress)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("unable to normalize address: %v", err)
+	}
+
+	inputs, total, err := c.sweepInputs()
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return nil, err
+	}
+
+	if len(inputs) == 0 {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.New("there are no confirmed funds to sweep")
+	}
+
+	tx, err := c.cfg.RPCClient.CreateRawTransaction(inputs,
+		map[btcutil.Address]btcutil.Amount{decodedAddress: total})
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to create transaction: %v", err)
+	}
+
+	// Transaction is signed once to learn its size, value of the output
+	// doesn't affect the size, and then it is signed again with the fee
+	// subtracted.
+	signedTx, err := c.cfg.RPCClient.SignRawTransaction(tx)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to sign transaction: %v", err)
+	}
+
+	// Fee is rounded up, so that the rate isn't below the estimated one.
+	feeRate := c.getFeeRate(defaultConfTarget)
+	fee := btcutil.Amount(feeRate.Mul(
+		decimal.New(virtualSize(signedTx), 0)).Ceil().IntPart())
+
+	amount := total - fee
+	if amount < dustLimit {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("funds(%v) are insufficient to pay "+
+			"fee(%v)", printAmount(total), printAmount(fee))
+	}
+
+	tx.TxOut[0].Value = int64(amount)
+	signedTx, err = c.cfg.RPCClient.SignRawTransaction(tx)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to sign transaction: %v", err)
+	}
+
+	if err := c.cfg.RPCClient.SendRawTransaction(signedTx); err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable send transaction: %v", err)
+	}
+
+	txID := signedTx.TxHash().String()
+
+	payment := &connectors.Payment{
+		UpdatedAt: connectors.ConvertTimeToMilliSeconds(time.Now()),
+		Status:    connectors.Pending,
+		Direction: connectors.Outgoing,
+		System:    connectors.External,
+		Receipt:   receipt,
+		Asset:     c.cfg.Asset,
+		Media:     connectors.Blockchain,
+		Amount:    sat2DecAmount(amount),
+		MediaFee:  sat2DecAmount(fee),
+		MediaID:   txID,
+	}
+
+	payment.PaymentID, err = payment.GenPaymentID()
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable generate payment id: %v", err)
+	}
+
+	if err := c.cfg.PaymentStore.SavePayment(payment); err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable save payment id: %v", err)
+	}
+
+	c.log.Infof("Swept %v from %v outputs to address(%v), tx(%v)",
+		printAmount(amount), len(inputs), receipt, txID)
+
+	return payment, nil
+}
+
+// sweepInputs returns the confirmed outputs of the wallet which could be
+// spent, along with their overall value. Locked outputs are reserved by
+// the payments which are being sent, and outputs of the quarantined
+// payments are held by the compliance review, that is why they are
+// skipped. Quarantined outputs are skipped even if they are not locked,
+// e.g. if daemon has been restarted and they haven't been locked again.
+func (c *Connector) sweepInputs() ([]rpc.UnspentInput, btcutil.Amount,
+	error) {
+
+	unspent, err := c.cfg.RPCClient.ListUnspentMinMax(
+		c.cfg.MinConfirmations, math.MaxInt32)
+	if err != nil {
+		return nil, 0, errors.Errorf("unable to list unspent: %v", err)
+	}
+
+	lockedOutputs, err := c.lockedOutPoints()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	quarantined, err := c.quarantinedPayments()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, u := range c.paymentOutputs(unspent, quarantined) {
+		lockedOutputs[outPoint(u.TxID, u.Vout)] = struct{}{}
+	}
+
+	var (
+		inputs []rpc.UnspentInput
+		total  btcutil.Amount
+	)
+	for _, u := range unspent {
+		if u.Account == watchAccount || u.Account == delegatedAccount {
+			continue
+		}
+
+		if _, ok := lockedOutputs[outPoint(u.TxID, u.Vout)]; ok {
+			continue
+		}
+
+		inputs = append(inputs, u)
+		total += decAmount2Sat(decimal.NewFromFloat(u.Amount).Round(8))
+	}
+
+	return inputs, total, nil
+}
+
+// virtualSize returns the size of the transaction as defined in BIP 141,
+// in which witness data is discounted.
+func virtualSize(tx *wire.MsgTx) int64 {
+	weight := int64(tx.SerializeSizeStripped()*3 + tx.SerializeSize())
+	return (weight + 3) / 4
+}
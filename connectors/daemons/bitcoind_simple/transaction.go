@@ -0,0 +1,100 @@
+package bitcoind_simple
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Runtime check to ensure that Connector implements
+// connectors.TransactionInspector interface.
+var _ connectors.TransactionInspector = (*Connector)(nil)
+
+// TransactionByHash returns the details of the wallet transaction, inputs
+// and outputs are taken from the raw transaction, because wallet reports
+// only the entries which are related to its addresses.
+//
+// NOTE: Part of the connectors.TransactionInspector interface.
+func (c *Connector) TransactionByHash(txID string) (*connectors.Transaction,
+	error) {
+
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	txHash, err := chainhash.NewHashFromStr(txID)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("invalid tx id(%v): %v", txID, err)
+	}
+
+	tx, err := c.cfg.RPCClient.GetTransaction(txHash)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("unable to get transaction(%v): %v",
+			txID, err)
+	}
+
+	rawTx, err := hex.DecodeString(tx.Hex)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to decode transaction hex: %v", err)
+	}
+
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to deserialize transaction: %v",
+			err)
+	}
+
+	transaction := &connectors.Transaction{
+		TxID:          tx.TxID,
+		BlockHash:     tx.BlockHash,
+		BlockTime:     tx.BlockTime * 1000,
+		Confirmations: tx.Confirmations,
+		Fee:           decimal.NewFromFloat(tx.Fee).Abs().Round(8),
+		RawTx:         tx.Hex,
+	}
+
+	for _, in := range msgTx.TxIn {
+		transaction.Inputs = append(transaction.Inputs,
+			&connectors.TransactionInput{
+				TxID: in.PreviousOutPoint.Hash.String(),
+				Vout: in.PreviousOutPoint.Index,
+			})
+	}
+
+	for i, out := range msgTx.TxOut {
+		output := &connectors.TransactionOutput{
+			Vout:   uint32(i),
+			Amount: sat2DecAmount(btcutil.Amount(out.Value)),
+		}
+
+		// Only the standard single address scripts are shown with the
+		// address, e.g. OP_RETURN and bare multisig outputs are not.
+		_, addresses, _, err := txscript.ExtractPkScriptAddrs(out.PkScript,
+			c.netParams)
+		if err == nil && len(addresses) == 1 {
+			output.Address, err = encodeAddress(c.cfg.Asset, addresses[0])
+			if err != nil {
+				m.AddError(metrics.MiddleSeverity)
+				return nil, errors.Errorf("unable to encode address: %v", err)
+			}
+		}
+
+		transaction.Outputs = append(transaction.Outputs, output)
+	}
+
+	return transaction, nil
+}
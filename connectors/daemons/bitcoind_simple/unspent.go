@@ -0,0 +1,230 @@
+package bitcoind_simple
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/bitlum/go-bitcoind-rpc/btcjson"
+	"github.com/btcsuite/btcutil"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+// syncRequest is the request to make the sync out of the schedule.
+type syncRequest struct {
+	// force denotes that payments should be synced from the scratch.
+	force bool
+
+	errChan chan error
+}
+
+// sync synchronises state of the payments and watched addresses with the
+// daemon, and remembers time of the successful sync.
+func (c *Connector) sync() error {
+	if err := c.syncPaymentState(); err != nil {
+		return errors.Errorf("unable to sync payment state: %v", err)
+	}
+
+	if err := c.syncWatchEvents(); err != nil {
+		return errors.Errorf("unable to sync watch events: %v", err)
+	}
+
+	if c.cfg.DelegatedAddresses {
+		if err := c.syncDelegatedPayments(); err != nil {
+			return errors.Errorf("unable to sync delegated payments: %v", err)
+		}
+	}
+
+	if err := c.lockQuarantined(); err != nil {
+		return errors.Errorf("unable to lock quarantined outputs: %v", err)
+	}
+
+	atomic.StoreInt64(&c.lastSyncAt, connectors.NowInMilliSeconds())
+	return nil
+}
+
+// handleSyncRequest makes the requested sync. In case of the forced sync
+// synced tx counter is dropped, so that all payments are fetched from the
+// daemon and overwritten.
+func (c *Connector) handleSyncRequest(req *syncRequest) error {
+	if req.force {
+		c.log.Infof("Force sync of the payments from the scratch")
+
+		if err := c.cfg.StateStore.PutLastSyncedTxCounter(0); err != nil {
+			return errors.Errorf("unable to reset last synced tx "+
+				"counter: %v", err)
+		}
+	}
+
+	return c.sync()
+}
+
+// SyncUnspent triggers the sync and waits for it to finish or for the
+// context to be done. If force is true the state is synced from the
+// scratch. Sync which has been already started is finished in the
+// background even if context is done.
+//
+// NOTE: Part of the connectors.UnspentSyncer interface.
+func (c *Connector) SyncUnspent(ctx context.Context, force bool) error {
+	req := &syncRequest{
+		force:   force,
+		errChan: make(chan error, 1),
+	}
+
+	select {
+	case c.syncRequests <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.quit:
+		return errors.New("connector is shutting down")
+	}
+
+	select {
+	case err := <-req.errChan:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.quit:
+		return errors.New("connector is shutting down")
+	}
+}
+
+// UnspentSyncStatus returns the state of the sync, and compares the synced
+// state with the state of the daemon.
+//
+// NOTE: Part of the connectors.UnspentSyncer interface.
+func (c *Connector) UnspentSyncStatus() (*connectors.UnspentSyncStatus, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	unspent, err := c.cfg.RPCClient.ListUnspentMinMax(0, math.MaxInt32)
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return nil, errors.Errorf("unable to list unspent: %v", err)
+	}
+
+	var (
+		unspentOutputs  int
+		unspentAmount   btcutil.Amount
+		inconsistencies []string
+	)
+
+	for _, u := range unspent {
+		// Watch-only outputs are not ours and they are not included in
+		// the balance.
+		if u.Account == watchAccount {
+			continue
+		}
+
+		if u.Confirmations < int64(c.cfg.MinConfirmations) {
+			continue
+		}
+
+		unspentOutputs++
+
+		amount, err := btcutil.NewAmount(u.Amount)
+		if err != nil {
+			m.AddError(metrics.MiddleSeverity)
+			return nil, errors.Errorf("unable to convert amount: %v", err)
+		}
+
+		unspentAmount += amount
+	}
+
+	balance, err := c.cfg.RPCClient.GetBalanceByLabel(allAccounts,
+		c.cfg.MinConfirmations)
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return nil, errors.Errorf("unable to get balance: %v", err)
+	}
+
+	if balance != unspentAmount {
+		inconsistencies = append(inconsistencies, fmt.Sprintf("amount of "+
+			"confirmed unspent outputs(%v) differs from balance(%v)",
+			printAmount(unspentAmount), printAmount(balance)))
+	}
+
+	txCounter, err := c.cfg.StateStore.LastTxCounter()
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return nil, errors.Errorf("unable get last tx counter: %v", err)
+	}
+
+	txs, err := c.fetchTransactions(func(count, from int) (
+		[]btcjson.ListTransactionsResult, error) {
+		return c.cfg.RPCClient.ListTransactionByLabel(allAccounts, count, from)
+	})
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return nil, errors.Errorf("unable to list transactions: %v", err)
+	}
+
+	if txCounter > len(txs) {
+		inconsistencies = append(inconsistencies, fmt.Sprintf("synced tx "+
+			"counter(%v) exceeds number of daemon transactions(%v)",
+			txCounter, len(txs)))
+	}
+
+	return &connectors.UnspentSyncStatus{
+		LastSyncAt:      atomic.LoadInt64(&c.lastSyncAt),
+		UnspentOutputs:  unspentOutputs,
+		Inconsistencies: inconsistencies,
+	}, nil
+}
+
+// ListUnspent returns the unspent outputs of the wallet, including
+// unconfirmed and locked ones. Outputs of the watch-only addresses are not
+// ours, that is why they are skipped.
+//
+// NOTE: Part of the connectors.UnspentLister interface.
+func (c *Connector) ListUnspent() ([]*connectors.UnspentOutput, error) {
+	m := crypto.NewMetric(c.client.DaemonName(), string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	unspent, err := c.cfg.RPCClient.ListUnspentMinMax(0, math.MaxInt32)
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return nil, errors.Errorf("unable to list unspent: %v", err)
+	}
+
+	locked, err := c.cfg.RPCClient.ListLockUnspent()
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return nil, errors.Errorf("unable to list locked unspent: %v", err)
+	}
+
+	lockedOutputs := make(map[string]struct{}, len(locked))
+	for _, output := range locked {
+		lockedOutputs[output.String()] = struct{}{}
+	}
+
+	outputs := make([]*connectors.UnspentOutput, 0, len(unspent))
+	for _, u := range unspent {
+		if u.Account == watchAccount || u.Account == delegatedAccount {
+			continue
+		}
+
+		// Outpoint is formatted the same way as it is done by the
+		// wire.OutPoint, so that locked outputs could be matched.
+		_, isLocked := lockedOutputs[fmt.Sprintf("%v:%v", u.TxID, u.Vout)]
+
+		outputs = append(outputs, &connectors.UnspentOutput{
+			TxID:          u.TxID,
+			Vout:          u.Vout,
+			Address:       c.normalizeAddress(u.Address),
+			Amount:        decimal.NewFromFloat(u.Amount).Round(8),
+			Confirmations: u.Confirmations,
+			Locked:        isLocked,
+		})
+	}
+
+	return outputs, nil
+}
@@ -1,6 +1,7 @@
 package bitcoind_simple
 
 import (
+	"crypto/sha256"
 	"math/big"
 
 	"github.com/bitlum/connector/connectors"
@@ -9,6 +10,8 @@ import (
 	"github.com/bitlum/connector/connectors/rpc/dash"
 	"github.com/bitlum/connector/connectors/rpc/litecoin"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/go-errors/errors"
 	"github.com/shopspring/decimal"
@@ -28,6 +31,75 @@ func sat2DecAmount(amount btcutil.Amount) decimal.Decimal {
 	return amt.Div(satoshiPerBitcoin).Round(8)
 }
 
+// p2pkhOutputSize is the serialized size of the pay-to-pubkey-hash output,
+// it is used for the addresses whose script couldn't be built.
+const p2pkhOutputSize = 34
+
+// outputSize returns the serialized size of the transaction output which
+// pays to the address.
+func outputSize(address btcutil.Address) int64 {
+	script, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return p2pkhOutputSize
+	}
+
+	return int64(wire.NewTxOut(0, script).SerializeSize())
+}
+
+// splitFee splits the fee of the transaction between its outputs in
+// proportion to their sizes, so that recipient with the bigger output pays
+// more. Remainder of the division is attributed to the first output, so that
+// the sum of the shares is equal to the fee.
+func splitFee(fee btcutil.Amount, sizes []int64) []btcutil.Amount {
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+
+	shares := make([]btcutil.Amount, len(sizes))
+	if total == 0 {
+		return shares
+	}
+
+	var split btcutil.Amount
+	for i, size := range sizes {
+		shares[i] = btcutil.Amount(int64(fee) * size / total)
+		split += shares[i]
+	}
+
+	if len(shares) > 0 {
+		shares[0] += fee - split
+	}
+
+	return shares
+}
+
+// timeLockScript returns the script which could be spent by the key only
+// after the lock time, along with its pay-to-witness-script-hash address.
+func timeLockScript(pubKey []byte, lockTime uint32,
+	params *chaincfg.Params) ([]byte, btcutil.Address, error) {
+
+	script, err := txscript.NewScriptBuilder().
+		AddInt64(int64(lockTime)).
+		AddOp(txscript.OP_CHECKLOCKTIMEVERIFY).
+		AddOp(txscript.OP_DROP).
+		AddData(pubKey).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		return nil, nil, errors.Errorf("unable to create script: %v", err)
+	}
+
+	scriptHash := sha256.Sum256(script)
+	address, err := btcutil.NewAddressWitnessScriptHash(scriptHash[:], params)
+	if err != nil {
+		return nil, nil, errors.Errorf("unable to create script "+
+			"address: %v", err)
+	}
+
+	return script, address, nil
+}
+
 func printAmount(a btcutil.Amount) string {
 	return decimal.NewFromFloat(a.ToBTC()).Round(8).String()
 }
@@ -67,6 +139,37 @@ network string) (btcutil.Address, error) {
 	}
 }
 
+// encodeAddress returns the canonical representation of the decoded address.
+// For bitcoin cash it is "Cash Address" with network prefix, for other assets
+// it is the address encoding which is used by btcutil, which is lowercase for
+// bech32 addresses.
+func encodeAddress(asset connectors.Asset, address btcutil.Address) (string, error) {
+	switch asset {
+	case connectors.BCH:
+		return bitcoincash.EncodeCashAddress(address)
+	default:
+		return address.EncodeAddress(), nil
+	}
+}
+
+// getAddressType returns the type of the decoded address.
+func getAddressType(address btcutil.Address) (connectors.AddressType, error) {
+	switch address.(type) {
+	case *btcutil.AddressPubKeyHash:
+		return connectors.P2PKH, nil
+	case *btcutil.AddressScriptHash:
+		return connectors.P2SH, nil
+	case *btcutil.AddressWitnessPubKeyHash:
+		return connectors.P2WPKH, nil
+	case *btcutil.AddressWitnessScriptHash:
+		return connectors.P2WSH, nil
+	case *btcutil.AddressPubKey:
+		return connectors.P2PK, nil
+	default:
+		return "", errors.Errorf("unknown address type(%T)", address)
+	}
+}
+
 func getParams(asset connectors.Asset, network string) (*chaincfg.Params, error) {
 	switch asset {
 	case connectors.BTC:
@@ -81,3 +184,16 @@ func getParams(asset connectors.Asset, network string) (*chaincfg.Params, error)
 		return nil, errors.Errorf("unsupported asset asset(%v)", asset)
 	}
 }
+
+// supportsRBF returns true if asset daemon supports replace-by-fee (BIP125),
+// bitcoin cash and dash have it removed.
+func supportsRBF(asset connectors.Asset) bool {
+	return asset == connectors.BTC || asset == connectors.LTC
+}
+
+// supportsTimeLocks returns true if time-locked payments could be sent for
+// the asset, they are sent on the witness script address, so that segwit
+// should be activated.
+func supportsTimeLocks(asset connectors.Asset) bool {
+	return asset == connectors.BTC || asset == connectors.LTC
+}
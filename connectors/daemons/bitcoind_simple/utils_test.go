@@ -0,0 +1,62 @@
+package bitcoind_simple
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+)
+
+func TestSplitFee(t *testing.T) {
+	p2pkh, err := btcutil.DecodeAddress("1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to decode address: %v", err)
+	}
+
+	p2wsh, err := btcutil.DecodeAddress("bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4"+
+		"nce4xj0gdcccefvpysxf3qccfmv3", &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to decode address: %v", err)
+	}
+
+	sizes := []int64{outputSize(p2pkh), outputSize(p2wsh)}
+	if sizes[0] != 34 || sizes[1] != 43 {
+		t.Fatalf("wrong output sizes: %v", sizes)
+	}
+
+	fees := splitFee(1000, sizes)
+	if fees[0] != 442 || fees[1] != 558 {
+		t.Fatalf("fee isn't split by output size: %v", fees)
+	}
+
+	if fees[0]+fees[1] != 1000 {
+		t.Fatalf("sum of the shares isn't equal to the fee: %v", fees)
+	}
+}
+
+func TestTimeLockScript(t *testing.T) {
+	pubKey, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029b" +
+		"fcdb2dce28d959f2815b16f81798")
+
+	script, address, err := timeLockScript(pubKey, 500000,
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+
+	// <500000> OP_CHECKLOCKTIMEVERIFY OP_DROP <pubkey> OP_CHECKSIG
+	wantScript := "0320a107b175210279be667ef9dcbbac55a06295ce870b07029bfcdb" +
+		"2dce28d959f2815b16f81798ac"
+	if hex.EncodeToString(script) != wantScript {
+		t.Fatalf("wrong script, got(%x), want(%v)", script, wantScript)
+	}
+
+	wantAddress := "bc1qfg7qkd4gwz4dgx57mpu23a5dsv7hzn7ykg5eznthmuajurn0h" +
+		"exsdun087"
+	if address.EncodeAddress() != wantAddress {
+		t.Fatalf("wrong address, got(%v), want(%v)",
+			address.EncodeAddress(), wantAddress)
+	}
+}
@@ -0,0 +1,84 @@
+package geth
+
+import (
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/go-errors/errors"
+)
+
+// Runtime check to ensure that Connector implements
+// connectors.PaymentCanceler interface.
+var _ connectors.PaymentCanceler = (*Connector)(nil)
+
+// CancelPayment aborts the outgoing payment which has been created, but
+// which transaction hasn't been broadcasted, e.g. because connector has been
+// stopped in between. Nonce of the default address is increased only when
+// transaction is broadcasted, that is why it is reused by the next payment,
+// and nothing else has to be released.
+//
+// NOTE: Part of the connectors.PaymentCanceler interface.
+func (c *Connector) CancelPayment(paymentID string) (*connectors.Payment,
+	error) {
+	m := crypto.NewMetric(c.cfg.DaemonCfg.Name, string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	c.sendMtx.Lock()
+	defer c.sendMtx.Unlock()
+
+	payment, err := c.cfg.PaymentStorage.PaymentByID(paymentID)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("unable to find payment(%v): %v",
+			paymentID, err)
+	}
+
+	if payment.Direction != connectors.Outgoing ||
+		payment.System != connectors.External ||
+		payment.Status != connectors.Waiting {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("payment(%v) is %v %v %v, only waiting "+
+			"external outgoing payment could be cancelled", paymentID,
+			payment.Status, payment.System, payment.Direction)
+	}
+
+	details, ok := payment.Detail.(*connectors.GeneratedTxDetails)
+	if !ok {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable get details for payment(%v)",
+			paymentID)
+	}
+
+	// Transaction might be broadcasted without the payment being updated,
+	// if connector has been stopped right after the broadcast.
+	known, err := c.client.EthTransactionKnown(details.TxID)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to get transaction(%v): %v",
+			details.TxID, err)
+	}
+
+	if known {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("transaction(%v) of payment(%v) has been "+
+			"already broadcasted", details.TxID, paymentID)
+	}
+
+	cancelled := *payment
+	cancelled.Status = connectors.Failed
+	cancelled.FailureReason = "cancelled"
+	cancelled.UpdatedAt = connectors.NowInMilliSeconds()
+
+	if err := c.cfg.PaymentStorage.SavePayment(&cancelled); err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to save payment(%v): %v",
+			paymentID, err)
+	}
+
+	c.log.Infof("Payment(%v) has been cancelled, tx(%v) isn't sent",
+		paymentID, details.TxID)
+
+	return &cancelled, nil
+}
@@ -0,0 +1,171 @@
+package geth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/db/inmemory"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/btcsuite/btclog"
+	"github.com/onrik/ethrpc"
+	"github.com/shopspring/decimal"
+)
+
+// mockDaemon is the daemon which knows only the given transactions, and
+// which keeps the methods which have been called.
+type mockDaemon struct {
+	sync.Mutex
+
+	known   map[string]bool
+	methods []string
+}
+
+func (d *mockDaemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     int               `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.methods = append(d.methods, req.Method)
+
+	var result interface{}
+	if req.Method == "eth_getTransactionByHash" {
+		var hash string
+		json.Unmarshal(req.Params[0], &hash)
+		if d.known[hash] {
+			result = map[string]string{"hash": hash}
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.ID,
+		"result":  result,
+	})
+}
+
+func (d *mockDaemon) called(method string) bool {
+	d.Lock()
+	defer d.Unlock()
+
+	for _, m := range d.methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TestCancelPayment checks that only the waiting payment which transaction
+// hasn't been broadcasted could be cancelled, and that cancelled payment
+// isn't sent afterwards.
+func TestCancelPayment(t *testing.T) {
+	daemon := &mockDaemon{
+		known: map[string]bool{"0xbroadcasted": true},
+	}
+	server := httptest.NewServer(daemon)
+	defer server.Close()
+
+	client := &ExtendedEthRpc{ethrpc.NewEthRPC(server.URL)}
+	store := inmemory.NewMemoryPaymentsStore()
+
+	c := &Connector{
+		cfg: &Config{
+			Asset:          connectors.ETH,
+			DaemonCfg:      &DaemonConfig{Name: "geth"},
+			Metrics:        crypto.DisabledBackend,
+			PaymentStorage: store,
+		},
+		client:      client,
+		writeClient: client,
+		log: &common.NamedLogger{
+			Name:   "ETH",
+			Logger: btclog.Disabled,
+		},
+	}
+
+	newPayment := func(txID string,
+		status connectors.PaymentStatus) *connectors.Payment {
+		payment := &connectors.Payment{
+			Status:    status,
+			Direction: connectors.Outgoing,
+			System:    connectors.External,
+			Receipt:   "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			Asset:     connectors.ETH,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(1, 0),
+			MediaFee:  decimal.New(1, -3),
+			MediaID:   txID,
+			Detail: &connectors.GeneratedTxDetails{
+				RawTx: []byte("0xraw"),
+				TxID:  txID,
+			},
+		}
+
+		var err error
+		payment.PaymentID, err = payment.GenPaymentID()
+		if err != nil {
+			t.Fatalf("unable to generate payment id: %v", err)
+		}
+
+		if err := store.SavePayment(payment); err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+
+		return payment
+	}
+
+	pending := newPayment("0xpending", connectors.Pending)
+	if _, err := c.CancelPayment(pending.PaymentID); err == nil {
+		t.Fatalf("pending payment shouldn't be cancelled")
+	}
+
+	// Payment which transaction is known to the daemon has been sent,
+	// even though its state hasn't been saved.
+	broadcasted := newPayment("0xbroadcasted", connectors.Waiting)
+	if _, err := c.CancelPayment(broadcasted.PaymentID); err == nil {
+		t.Fatalf("broadcasted payment shouldn't be cancelled")
+	}
+
+	waiting := newPayment("0xwaiting", connectors.Waiting)
+	cancelled, err := c.CancelPayment(waiting.PaymentID)
+	if err != nil {
+		t.Fatalf("unable to cancel payment: %v", err)
+	}
+
+	if cancelled.Status != connectors.Failed {
+		t.Fatalf("cancelled payment should be failed: %v", cancelled.Status)
+	}
+
+	stored, err := store.PaymentByID(waiting.PaymentID)
+	if err != nil {
+		t.Fatalf("unable to get payment: %v", err)
+	}
+
+	if stored.Status != connectors.Failed {
+		t.Fatalf("cancelled payment should be saved as failed: %v",
+			stored.Status)
+	}
+
+	if _, err := c.sendPayment(waiting.PaymentID, true); err == nil {
+		t.Fatalf("cancelled payment shouldn't be sent")
+	}
+
+	if daemon.called("eth_sendRawTransaction") {
+		t.Fatalf("transaction of cancelled payment has been broadcasted")
+	}
+}
@@ -2,6 +2,8 @@ package geth
 
 import (
 	"fmt"
+	"io/ioutil"
+	"strings"
 	"time"
 
 	"sync"
@@ -10,6 +12,9 @@ import (
 
 	"math/big"
 
+	"net"
+	"net/http"
+
 	"github.com/bitlum/connector/common"
 	"github.com/bitlum/connector/connectors"
 	"github.com/bitlum/connector/connectors/rpc/ethereum"
@@ -29,6 +34,10 @@ var (
 	// defaultTxGas is the number of gas in ethereum which is needed to
 	// propagate the transaction.
 	defaultTxGas = int64(90000)
+
+	// maxMemoSize is the maximum size of the memo in bytes, which could be
+	// attached to the transaction, without exceeding the default gas limit.
+	maxMemoSize = 512
 )
 
 type internalAccount string
@@ -51,6 +60,107 @@ type DaemonConfig struct {
 	ServerHost string
 	ServerPort int
 	Password   string
+
+	// PasswordFile is the path to the file with the password of the daemon
+	// accounts. If it is specified it is used instead of the password, and
+	// it is read on every use, so that password could be rotated.
+	PasswordFile string
+
+	// MaxConns is the maximum number of idle connections to the daemon
+	// which are kept open and reused, so that we don't exhaust ephemeral
+	// ports on connection churn. If zero the default number is used.
+	MaxConns int
+
+	// Timeout is the maximum time we wait for the response of the daemon
+	// on the read calls. Calls which change the state of the daemon, e.g.
+	// sending of the transaction, are not limited, so that we don't report
+	// failure of the request which was actually executed by the daemon.
+	// If zero the default timeout is used.
+	Timeout time.Duration
+
+	// KeepAlive is the keep alive period of the connections to the daemon.
+	// If zero the default period is used.
+	KeepAlive time.Duration
+
+	// Proxy is the address of the SOCKS5 proxy through which connections
+	// to the daemon are established, if daemon is the .onion host.
+	Proxy string
+}
+
+// password returns the password of the daemon accounts, either from the
+// password file or from the config.
+func (cfg *DaemonConfig) password() (string, error) {
+	if cfg.PasswordFile == "" {
+		return cfg.Password, nil
+	}
+
+	data, err := ioutil.ReadFile(cfg.PasswordFile)
+	if err != nil {
+		return "", errors.Errorf("unable to read password file: %v", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+const (
+	defaultMaxConns  = 16
+	defaultTimeout   = time.Minute
+	defaultKeepAlive = 30 * time.Second
+)
+
+// newHTTPClients creates http clients which reuse connections to the
+// daemon. Read client doesn't wait for the response forever, write client
+// is only limited by the dial timeout, because request can't be cancelled
+// on the daemon side once it has been sent.
+func newHTTPClients(cfg *DaemonConfig) (*http.Client, *http.Client, error) {
+	maxConns := cfg.MaxConns
+	if maxConns <= 0 {
+		maxConns = defaultMaxConns
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	keepAlive := cfg.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = defaultKeepAlive
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: keepAlive,
+	}
+
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		MaxIdleConns:        maxConns,
+		MaxIdleConnsPerHost: maxConns,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	if cfg.Proxy != "" {
+		dial, err := common.NewDialer(cfg.Proxy, timeout)
+		if err != nil {
+			return nil, nil, errors.Errorf("unable to create proxy "+
+				"dialer: %v", err)
+		}
+
+		transport.DialContext = nil
+		transport.Dial = dial
+	}
+
+	readClient := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+
+	writeClient := &http.Client{
+		Transport: transport,
+	}
+
+	return readClient, writeClient, nil
 }
 
 // Config is a connector config.
@@ -67,6 +177,17 @@ type Config struct {
 	// start syncing pending, confirmed and mempool transactions.
 	SyncTickDelay int
 
+	// SyncWorkers is the number of concurrent workers which are fetching
+	// blocks and transaction receipts from the daemon during the sync.
+	SyncWorkers int
+
+	// MaxPendingPayments is the maximum number of payments which are kept
+	// in each of the in-memory maps of mempool and unconfirmed payments.
+	// Payments are persisted in the payment storage anyway, in-memory maps
+	// are used only to detect new pending payments, pending balance is
+	// calculated before the eviction, so it isn't affected by the limit.
+	MaxPendingPayments int
+
 	// LastSyncedBlockHash is the hash of block which were proceeded last.
 	// In this field is specified, than hash will be initialized from it,
 	// rather than from database.
@@ -98,6 +219,14 @@ type Config struct {
 	// StateStorage is used to keep data which is needed for connector to
 	// properly synchronise and track transactions.
 	StateStorage connectors.StateStorage
+
+	// WatchStore is an external storage for watched addresses and their
+	// events. If it is not specified activity on watched addresses is not
+	// tracked.
+	WatchStore connectors.WatchStore
+
+	// WatchNotifier is used to notify about activity on watched addresses.
+	WatchNotifier connectors.WatchNotifier
 }
 
 func (c *Config) validate() error {
@@ -117,6 +246,14 @@ func (c *Config) validate() error {
 		c.SyncTickDelay = 5
 	}
 
+	if c.SyncWorkers <= 0 {
+		c.SyncWorkers = 8
+	}
+
+	if c.MaxPendingPayments <= 0 {
+		c.MaxPendingPayments = 10000
+	}
+
 	if c.Asset == "" {
 		return errors.New("asset should be specified")
 	}
@@ -151,6 +288,11 @@ type Connector struct {
 	cfg    *Config
 	client *ExtendedEthRpc
 
+	// writeClient is used for the calls which change the state of the
+	// daemon, it shares connections with the client, but doesn't time out
+	// waiting for the response.
+	writeClient *ExtendedEthRpc
+
 	// defaultAddress is the address which is used as the aggregator address
 	// for all incoming transaction. Every payment we receive will be redirected
 	// on this address, so that later it could be used for sending transaction
@@ -164,7 +306,19 @@ type Connector struct {
 	// unconfirmedTxs is the map of transaction which are already in the
 	// blockchains, but not yet confirmed from our pov.
 	unconfirmedTxs pendingMap
-	pendingLock    sync.Mutex
+
+	// memPoolAmount and unconfirmedAmount are the amounts of the pending
+	// payments of default account, they are calculated on every sync
+	// before the maps are truncated, so that eviction doesn't affect the
+	// pending balance.
+	memPoolAmount     decimal.Decimal
+	unconfirmedAmount decimal.Decimal
+
+	pendingLock sync.Mutex
+
+	// sendMtx serializes sending of the waiting payments with their
+	// cancellation, so that cancelled payment is never broadcasted.
+	sendMtx sync.Mutex
 
 	log *common.NamedLogger
 }
@@ -173,6 +327,18 @@ type Connector struct {
 // interface.
 var _ connectors.BlockchainConnector = (*Connector)(nil)
 
+// A compile time check to ensure Connector implements the QueueReporter
+// interface.
+var _ connectors.QueueReporter = (*Connector)(nil)
+
+// A compile time check to ensure Connector implements the ChainReporter
+// interface.
+var _ connectors.ChainReporter = (*Connector)(nil)
+
+// A compile time check to ensure Connector implements the AssetReporter
+// interface.
+var _ connectors.AssetReporter = (*Connector)(nil)
+
 func NewConnector(cfg *Config) (*Connector, error) {
 	if err := cfg.validate(); err != nil {
 		return nil, err
@@ -190,6 +356,30 @@ func NewConnector(cfg *Config) (*Connector, error) {
 	}, nil
 }
 
+// ProbeDaemon ensures that daemon is reachable, and that it is working on
+// the given network. Returns the network of the daemon.
+func ProbeDaemon(cfg *DaemonConfig, net string) (string, error) {
+	url := fmt.Sprintf("http://%v:%v", cfg.ServerHost, cfg.ServerPort)
+	readClient, _, err := newHTTPClients(cfg)
+	if err != nil {
+		return "", errors.Errorf("unable to create http client: %v", err)
+	}
+
+	client := ethrpc.NewEthRPC(url, ethrpc.WithHttpClient(readClient))
+
+	version, err := client.NetVersion()
+	if err != nil {
+		return "", errors.Errorf("unable to get net version: %v", err)
+	}
+
+	if net != convertVersion(version) {
+		return "", errors.Errorf("networks are different, desired: %v, "+
+			"actual: %v", net, convertVersion(version))
+	}
+
+	return convertVersion(version), nil
+}
+
 func (c *Connector) Start() (err error) {
 	if !atomic.CompareAndSwapInt32(&c.started, 0, 1) {
 		c.log.Warn("client already started")
@@ -211,7 +401,15 @@ func (c *Connector) Start() (err error) {
 	c.log.Info("Creating RPC client...")
 	url := fmt.Sprintf("http://%v:%v", c.cfg.DaemonCfg.ServerHost,
 		c.cfg.DaemonCfg.ServerPort)
-	c.client = &ExtendedEthRpc{ethrpc.NewEthRPC(url)}
+	readClient, writeClient, err := newHTTPClients(c.cfg.DaemonCfg)
+	if err != nil {
+		return errors.Errorf("unable to create http client: %v", err)
+	}
+
+	c.client = &ExtendedEthRpc{ethrpc.NewEthRPC(url,
+		ethrpc.WithHttpClient(readClient))}
+	c.writeClient = &ExtendedEthRpc{ethrpc.NewEthRPC(url,
+		ethrpc.WithHttpClient(writeClient))}
 
 	version, err := c.client.NetVersion()
 	if err != nil {
@@ -363,7 +561,13 @@ func (c *Connector) createAddress(account internalAccount) (string, error) {
 	var address string
 	var err error
 
-	address, err = c.client.PersonalNewAddress(c.cfg.DaemonCfg.Password)
+	password, err := c.cfg.DaemonCfg.password()
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return "", err
+	}
+
+	address, err = c.writeClient.PersonalNewAddress(password)
 	if err != nil {
 		m.AddError(metrics.HighSeverity)
 		return "", errors.Errorf("unable to create address: %v", err)
@@ -382,7 +586,8 @@ func (c *Connector) createAddress(account internalAccount) (string, error) {
 // instead returns the payment id and waits for it to be approved.
 //
 // NOTE: Part of the connectors.BlockchainConnector interface.
-func (c *Connector) SendPayment(toAddress, amountStr string) (*connectors.Payment, error) {
+func (c *Connector) SendPayment(toAddress, amountStr,
+	memo string) (*connectors.Payment, error) {
 	m := crypto.NewMetric(c.cfg.DaemonCfg.Name, string(c.cfg.Asset),
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
@@ -392,6 +597,20 @@ func (c *Connector) SendPayment(toAddress, amountStr string) (*connectors.Paymen
 		return nil, errors.Errorf("unable parse amount: %v", err)
 	}
 
+	// Payment receipt should be stored in checksum encoding, the same way
+	// as receipts of the synchronised transactions.
+	receipt, err := ethereum.NormalizeAddress(toAddress)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("invalid address: %v", err)
+	}
+
+	if len(memo) > maxMemoSize {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("memo is too long, max size is %v bytes",
+			maxMemoSize)
+	}
+
 	// If we send transaction too frequently ethereum transaction counter
 	// is not working properly, for that reason we use internal nonce counter.
 	nonce, err := c.cfg.AccountStorage.DefaultAddressNonce()
@@ -401,7 +620,7 @@ func (c *Connector) SendPayment(toAddress, amountStr string) (*connectors.Paymen
 	}
 
 	details, fee, err := c.generateTransaction(c.defaultAddress, toAddress,
-		amount, false, nonce)
+		amount, false, nonce, memo)
 	if err != nil {
 		m.AddError(metrics.HighSeverity)
 		return nil, err
@@ -412,12 +631,13 @@ func (c *Connector) SendPayment(toAddress, amountStr string) (*connectors.Paymen
 		Status:    connectors.Waiting,
 		Direction: connectors.Outgoing,
 		System:    connectors.External,
-		Receipt:   toAddress,
+		Receipt:   receipt,
 		Asset:     connectors.Asset(c.cfg.Asset),
 		Media:     connectors.Blockchain,
 		Amount:    amount.Round(8),
 		MediaFee:  fee,
 		MediaID:   details.TxID,
+		Memo:      memo,
 		Detail:    details,
 	}
 
@@ -440,7 +660,7 @@ func (c *Connector) SendPayment(toAddress, amountStr string) (*connectors.Paymen
 
 func (c *Connector) generateTransaction(fromAddress, toAddress string,
 	amount decimal.Decimal, includeFee bool,
-	nonce int) (*connectors.GeneratedTxDetails,
+	nonce int, memo string) (*connectors.GeneratedTxDetails,
 	decimal.Decimal, error) {
 
 	// Fetch suggested by the daemon gas price.
@@ -470,18 +690,23 @@ func (c *Connector) generateTransaction(fromAddress, toAddress string,
 		txAmount = new(big.Int).Sub(txAmount, txFee)
 	}
 
-	_, err = c.client.PersonalUnlockAddress(fromAddress, c.cfg.DaemonCfg.Password, 2)
+	password, err := c.cfg.DaemonCfg.password()
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+
+	_, err = c.writeClient.PersonalUnlockAddress(fromAddress, password, 2)
 	if err != nil {
 		return nil, decimal.Zero, errors.Errorf("unable to unlock sender account: %v", err)
 	}
 
-	tx, rawTxStr, err := c.client.EthSignTransaction(ethrpc.T{
+	tx, rawTxStr, err := c.writeClient.EthSignTransaction(ethrpc.T{
 		From:     fromAddress,
 		To:       toAddress,
 		Gas:      int(gas.Int64()),
 		GasPrice: gasPrice,
 		Value:    txAmount,
-		Data:     "",
+		Data:     encodeMemo(memo),
 		Nonce:    nonce,
 	})
 	if err != nil {
@@ -506,6 +731,9 @@ func (c *Connector) sendPayment(paymentID string, isFromDefault bool) (*connecto
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
 
+	c.sendMtx.Lock()
+	defer c.sendMtx.Unlock()
+
 	// We should be able to receive payment which we putted in storage
 	// earlier on the stage of payment generation.
 	payment, err := c.cfg.PaymentStorage.PaymentByID(paymentID)
@@ -515,6 +743,13 @@ func (c *Connector) sendPayment(paymentID string, isFromDefault bool) (*connecto
 			err)
 	}
 
+	// Payment might be cancelled before it has been sent.
+	if payment.Status != connectors.Waiting {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("payment(%v) is %v, only waiting payment "+
+			"could be sent", paymentID, payment.Status)
+	}
+
 	// Extract the detail about payment, which were putter on the stage
 	// of creation of the payment, in order to use raw transaction
 	// to send it in blockchain.
@@ -524,9 +759,10 @@ func (c *Connector) sendPayment(paymentID string, isFromDefault bool) (*connecto
 			paymentID)
 	}
 
-	_, err = c.client.EthSendRawTransaction(string(details.RawTx))
+	_, err = c.writeClient.EthSendRawTransaction(string(details.RawTx))
 	if err != nil {
 		payment.Status = connectors.Failed
+		payment.FailureReason = err.Error()
 		if err := c.cfg.PaymentStorage.SavePayment(payment); err != nil {
 			m.AddError(metrics.HighSeverity)
 			c.log.Errorf("unable update payment(%v) status: %v",
@@ -612,16 +848,21 @@ func (c *Connector) PendingBalance() (decimal.Decimal,
 	c.pendingLock.Lock()
 	defer c.pendingLock.Unlock()
 
-	var amount decimal.Decimal
-	for _, tx := range c.memPoolTxs[string(defaultAccount)] {
-		amount = amount.Add(tx.Amount)
-	}
+	return c.memPoolAmount.Add(c.unconfirmedAmount).Round(8), nil
+}
 
-	for _, tx := range c.unconfirmedTxs[string(defaultAccount)] {
-		amount = amount.Add(tx.Amount)
-	}
+// QueueDepths returns the number of mempool and unconfirmed payments kept
+// in memory.
+//
+// NOTE: Part of the connectors.QueueReporter interface.
+func (c *Connector) QueueDepths() map[string]int {
+	c.pendingLock.Lock()
+	defer c.pendingLock.Unlock()
 
-	return amount.Round(8), nil
+	return map[string]int{
+		"mempool":     c.memPoolTxs.size(),
+		"unconfirmed": c.unconfirmedTxs.size(),
+	}
 }
 
 // syncUnconfirmed process blocks above the minimum confirmations threshold
@@ -670,12 +911,13 @@ lastSyncedBlockNumber int) (pendingMap, error) {
 				UpdatedAt: connectors.NowInMilliSeconds(),
 				Status:    connectors.Pending,
 				Account:   account,
-				Receipt:   tx.To,
+				Receipt:   checksumAddress(tx.To),
 				Asset:     c.cfg.Asset,
 				Media:     connectors.Blockchain,
 				Amount:    amount,
 				MediaFee:  fee,
 				MediaID:   tx.Hash,
+				Memo:      decodeMemo(tx.Input),
 				Detail: &connectors.BlockchainPendingDetails{
 					Confirmations:     confirmations,
 					ConfirmationsLeft: int64(c.cfg.MinConfirmations) - confirmations,
@@ -744,12 +986,13 @@ func (c *Connector) syncPending() (pendingMap, error) {
 			UpdatedAt: connectors.NowInMilliSeconds(),
 			Status:    connectors.Pending,
 			Account:   account,
-			Receipt:   tx.To,
+			Receipt:   checksumAddress(tx.To),
 			Asset:     c.cfg.Asset,
 			Media:     connectors.Blockchain,
 			Amount:    amount,
 			MediaFee:  fee,
 			MediaID:   tx.Hash,
+			Memo:      decodeMemo(tx.Input),
 			Detail: &connectors.BlockchainPendingDetails{
 				Confirmations:     0,
 				ConfirmationsLeft: int64(c.cfg.MinConfirmations),
@@ -808,239 +1051,279 @@ func (c *Connector) syncConfirmed(bestBlockNumber int,
 			return lastSyncedBlock, nil
 		}
 
-		nextBlockNumber := lastSyncedBlock.Number + 1
-		block, err := c.client.EthGetBlockByNumber(nextBlockNumber, true)
+		// Fetch the batch of confirmed blocks concurrently, but process
+		// them one by one, so that last synced block hash which is
+		// persisted after every block could be used as high-water mark.
+		fromBlockNumber := lastSyncedBlock.Number + 1
+		toBlockNumber := bestBlockNumber - c.cfg.MinConfirmations
+		if toBlockNumber-fromBlockNumber >= c.cfg.SyncWorkers {
+			toBlockNumber = fromBlockNumber + c.cfg.SyncWorkers - 1
+		}
+
+		blocks, err := c.fetchBlocks(fromBlockNumber, toBlockNumber)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, confirmedTx := range block.Transactions {
-			// By the given address identify is sender address belongs
-			// to our system.
-			senderAccount, err := c.cfg.AccountStorage.GetAccountByAddress(
-				confirmedTx.From)
-			if err != nil {
+		for _, block := range blocks {
+			if err := c.processConfirmedBlock(block, m); err != nil {
 				return nil, err
 			}
 
-			// By the given address identify is receiver address belongs
-			// to our system.
-			receiverAccount, err := c.cfg.AccountStorage.GetAccountByAddress(
-				confirmedTx.To)
-			if err != nil {
-				return nil, err
+			// Update database with last synced block hash.
+			hash := []byte(block.Hash)
+			if err := c.cfg.StateStorage.PutLastSyncedHash(hash); err != nil {
+				return nil, errors.Errorf("unable to put block hash in db: %v",
+					err)
 			}
 
-			// Skip transactions which do not belongs to us.
-			if senderAccount == "" && receiverAccount == "" {
-				continue
-			}
+			lastSyncedBlock = block
 
-			makeDirection := func(sender, receiver string) string {
-				switch sender {
-				case "default":
-					sender = "default"
-				case "":
-					sender = "unknown"
-				default:
-					sender = "accounts"
-				}
+			// After transaction has been consumed by other subsystem
+			// overwrite cache.
+			c.log.Infof("Process block hash(%v), number(%v)", block.Hash,
+				block.Number)
 
-				switch receiver {
-				case "default":
-					receiver = "default"
-				case "":
-					receiver = "unknown"
-				default:
-					receiver = "accounts"
-				}
+			// Report last synchronised block number from daemon point of
+			// view.
+			m.BlockNumber(int64(lastSyncedBlock.Number))
+		}
+	}
+}
 
-				return fmt.Sprintf("%v => %v", sender, receiver)
-			}
+// processConfirmedBlock handles transactions of the confirmed block, and
+// saves the payments which belong to our system.
+func (c *Connector) processConfirmedBlock(block *ethrpc.Block,
+	m crypto.Metric) error {
 
-			var (
-				// Whether this payment should be considered as user
-				// originated or not.
-				isInternal bool
-
-				needUpdateStatusOfIncoming bool
-				needUpdateStatusOfOutgoing bool
-
-				// Whether this payment has to be redirected on default address.
-				needRedirect bool
-			)
-
-			d := makeDirection(senderAccount, receiverAccount)
-			switch d {
-			case "default => default":
-				// Such payment doesn't make a lot of sense, but if it made
-				// they should be considered as internal, and not be exposed outside.
-				isInternal = true
-				needUpdateStatusOfIncoming = true
-				needUpdateStatusOfOutgoing = true
-
-			case "default => accounts":
-				// This payment from our aggregation address,
-				// to one of addresses which belong to our wallet.
-				// We should track both outgoing and incoming payments.
-				isInternal = false
-
-				// Track payment to account
-				needUpdateStatusOfIncoming = true
-
-				// Track payment from default to account
-				needUpdateStatusOfOutgoing = true
-
-				// After payment will be received on one of the accounts,
-				// it should be redirected back to default address.
-				needRedirect = true
-
-			case "default => unknown":
-				// Is the standard "send" payment from our aggregation
-				// address to
-				// some user in the network.
-				isInternal = false
-				needUpdateStatusOfOutgoing = true
-
-			case "accounts => accounts":
-				// This payment is done from one user account to another.
-				isInternal = false
-				needUpdateStatusOfIncoming = true
-				needUpdateStatusOfOutgoing = true
-
-			case "accounts => unknown":
-				// We are not sending payment from accounts, this should be
-				// unexpected behaviour. Payment are done only from default
-				// address.
-				return nil, errors.Errorf("unexpected behavior, received tx("+
-					"%v) from one of the internal accounts", confirmedTx.Hash)
-
-			case "unknown => unknown":
-				// This should has been handled previously.
-				// We shouldn't handle payment which do not touches our
-				// system.
-				continue
+	watched, err := c.fetchWatchedAddresses()
+	if err != nil {
+		return err
+	}
 
-			case "accounts => default":
-				// In this case we receive previously redirected payment
-				// on our default address.
-				isInternal = true
-				needUpdateStatusOfIncoming = true
-				needUpdateStatusOfOutgoing = true
-
-			case "unknown => default":
-				// Payment on default address from unknown address are
-				// unusual. It might be deposit on connector by mistake,
-				// because it should be done on account address.
-				isInternal = false
-				needUpdateStatusOfIncoming = true
-
-			case "unknown => accounts":
-				// It is standard "receive" payment from unknown user on the
-				// network.
-				isInternal = false
-				needUpdateStatusOfIncoming = true
-				needRedirect = true
-			}
+	// Transaction receipts are needed to identify what gas was actually
+	// used by the network, fetch them for our transactions in advance.
+	receipts, err := c.fetchReceipts(block.Transactions)
+	if err != nil {
+		return err
+	}
 
-			c.log.Infof("Handling %v transaction(%v)", d, confirmedTx.Hash)
+	for _, confirmedTx := range block.Transactions {
+		if err := c.processWatchedTx(watched, confirmedTx); err != nil {
+			return err
+		}
 
-			// We need identify what gas was actually used by the network.
-			receipt, err := c.client.EthGetTransactionReceipt(confirmedTx.Hash)
-			if err != nil {
-				return nil, errors.Errorf("unable to get "+
-					"transaction receipt for tx(%v): %v", confirmedTx.Hash, err)
-			}
+		// By the given address identify is sender address belongs
+		// to our system.
+		senderAccount, err := c.cfg.AccountStorage.GetAccountByAddress(
+			confirmedTx.From)
+		if err != nil {
+			return err
+		}
 
-			// Convert amount from wei representation to Ethereum.
-			amount := decimal.NewFromBigInt(&confirmedTx.Value, 0).Div(weiInEth)
-			gas := decimal.New(int64(receipt.GasUsed), 0)
-			gasPrice := decimal.NewFromBigInt(&confirmedTx.GasPrice, 0)
-			fee := gas.Mul(gasPrice).Div(weiInEth)
+		// By the given address identify is receiver address belongs
+		// to our system.
+		receiverAccount, err := c.cfg.AccountStorage.GetAccountByAddress(
+			confirmedTx.To)
+		if err != nil {
+			return err
+		}
 
-			payment := connectors.Payment{
-				UpdatedAt: connectors.NowInMilliSeconds(),
-				Status:    connectors.Completed,
-				Account:   receiverAccount,
-				Receipt:   confirmedTx.To,
-				Asset:     c.cfg.Asset,
-				Media:     connectors.Blockchain,
-				Amount:    amount,
-				MediaFee:  fee,
-				MediaID:   confirmedTx.Hash,
+		// Skip transactions which do not belongs to us.
+		if senderAccount == "" && receiverAccount == "" {
+			continue
+		}
+
+		makeDirection := func(sender, receiver string) string {
+			switch sender {
+			case "default":
+				sender = "default"
+			case "":
+				sender = "unknown"
+			default:
+				sender = "accounts"
 			}
 
-			if isInternal {
-				payment.System = connectors.Internal
-			} else {
-				payment.System = connectors.External
+			switch receiver {
+			case "default":
+				receiver = "default"
+			case "":
+				receiver = "unknown"
+			default:
+				receiver = "accounts"
 			}
 
-			if needUpdateStatusOfOutgoing {
-				outgoingPayment := payment
-				outgoingPayment.Direction = connectors.Outgoing
-				outgoingPayment.PaymentID, err = outgoingPayment.GenPaymentID()
-				if err != nil {
-					return nil, err
-				}
+			return fmt.Sprintf("%v => %v", sender, receiver)
+		}
 
-				if err := c.cfg.PaymentStorage.SavePayment(&outgoingPayment); err != nil {
-					return nil, errors.Errorf("unable to add payment to storage: %v",
-						outgoingPayment.PaymentID)
-				}
+		var (
+			// Whether this payment should be considered as user
+			// originated or not.
+			isInternal bool
+
+			needUpdateStatusOfIncoming bool
+			needUpdateStatusOfOutgoing bool
+
+			// Whether this payment has to be redirected on default address.
+			needRedirect bool
+		)
+
+		d := makeDirection(senderAccount, receiverAccount)
+		switch d {
+		case "default => default":
+			// Such payment doesn't make a lot of sense, but if it made
+			// they should be considered as internal, and not be exposed outside.
+			isInternal = true
+			needUpdateStatusOfIncoming = true
+			needUpdateStatusOfOutgoing = true
+
+		case "default => accounts":
+			// This payment from our aggregation address,
+			// to one of addresses which belong to our wallet.
+			// We should track both outgoing and incoming payments.
+			isInternal = false
+
+			// Track payment to account
+			needUpdateStatusOfIncoming = true
+
+			// Track payment from default to account
+			needUpdateStatusOfOutgoing = true
+
+			// After payment will be received on one of the accounts,
+			// it should be redirected back to default address.
+			needRedirect = true
+
+		case "default => unknown":
+			// Is the standard "send" payment from our aggregation
+			// address to
+			// some user in the network.
+			isInternal = false
+			needUpdateStatusOfOutgoing = true
+
+		case "accounts => accounts":
+			// This payment is done from one user account to another.
+			isInternal = false
+			needUpdateStatusOfIncoming = true
+			needUpdateStatusOfOutgoing = true
+
+		case "accounts => unknown":
+			// We are not sending payment from accounts, this should be
+			// unexpected behaviour. Payment are done only from default
+			// address.
+			return errors.Errorf("unexpected behavior, received tx("+
+				"%v) from one of the internal accounts", confirmedTx.Hash)
+
+		case "unknown => unknown":
+			// This should has been handled previously.
+			// We shouldn't handle payment which do not touches our
+			// system.
+			continue
 
-				c.log.Infof("Confirm outgoing payment(%v)",
-					spew.Sdump(outgoingPayment))
-			}
+		case "accounts => default":
+			// In this case we receive previously redirected payment
+			// on our default address.
+			isInternal = true
+			needUpdateStatusOfIncoming = true
+			needUpdateStatusOfOutgoing = true
+
+		case "unknown => default":
+			// Payment on default address from unknown address are
+			// unusual. It might be deposit on connector by mistake,
+			// because it should be done on account address.
+			isInternal = false
+			needUpdateStatusOfIncoming = true
+
+		case "unknown => accounts":
+			// It is standard "receive" payment from unknown user on the
+			// network.
+			isInternal = false
+			needUpdateStatusOfIncoming = true
+			needRedirect = true
+		}
 
-			if needUpdateStatusOfIncoming {
-				incomingPayment := payment
-				incomingPayment.Direction = connectors.Incoming
-				incomingPayment.MediaFee = decimal.Zero
-				incomingPayment.PaymentID, err = incomingPayment.GenPaymentID()
-				if err != nil {
-					return nil, err
-				}
+		c.log.Infof("Handling %v transaction(%v)", d, confirmedTx.Hash)
 
-				if err := c.cfg.PaymentStorage.SavePayment(&incomingPayment); err != nil {
-					return nil, errors.Errorf("unable to add payment to storage: %v",
-						incomingPayment.PaymentID)
-				}
+		receipt, ok := receipts[confirmedTx.Hash]
+		if !ok {
+			return errors.Errorf("unable to find transaction receipt "+
+				"for tx(%v)", confirmedTx.Hash)
+		}
 
-				c.log.Infof("Confirmed incoming payment(%v)",
-					spew.Sdump(incomingPayment))
-
-				if needRedirect {
-					// In this case we received transaction on one of our
-					// non-internal accounts we should make money
-					// aggregation on default account.
-					c.log.Infof("Make redirect of payment("+
-						"%v)", incomingPayment.PaymentID)
-					if err := c.makeRedirect(confirmedTx.To, amount); err != nil {
-						c.log.Errorf("unable to make payment(%v) "+
-							"redirection: %v", spew.Sdump(incomingPayment), err)
-						m.AddError(metrics.HighSeverity)
-					}
-				}
-			}
+		// Convert amount from wei representation to Ethereum.
+		amount := decimal.NewFromBigInt(&confirmedTx.Value, 0).Div(weiInEth)
+		gas := decimal.New(int64(receipt.GasUsed), 0)
+		gasPrice := decimal.NewFromBigInt(&confirmedTx.GasPrice, 0)
+		fee := gas.Mul(gasPrice).Div(weiInEth)
+
+		payment := connectors.Payment{
+			UpdatedAt: connectors.NowInMilliSeconds(),
+			Status:    connectors.Completed,
+			Account:   receiverAccount,
+			Receipt:   checksumAddress(confirmedTx.To),
+			Asset:     c.cfg.Asset,
+			Media:     connectors.Blockchain,
+			Amount:    amount,
+			MediaFee:  fee,
+			MediaID:   confirmedTx.Hash,
+			Memo:      decodeMemo(confirmedTx.Input),
+		}
+
+		if isInternal {
+			payment.System = connectors.Internal
+		} else {
+			payment.System = connectors.External
 		}
 
-		// Update database with last synced block hash.
-		hash := []byte(block.Hash)
-		if err := c.cfg.StateStorage.PutLastSyncedHash(hash); err != nil {
-			return nil, errors.Errorf("unable to put block hash in db: %v",
-				err)
+		if needUpdateStatusOfOutgoing {
+			outgoingPayment := payment
+			outgoingPayment.Direction = connectors.Outgoing
+			outgoingPayment.PaymentID, err = outgoingPayment.GenPaymentID()
+			if err != nil {
+				return err
+			}
+
+			if err := c.cfg.PaymentStorage.SavePayment(&outgoingPayment); err != nil {
+				return errors.Errorf("unable to add payment to storage: %v",
+					outgoingPayment.PaymentID)
+			}
+
+			c.log.Infof("Confirm outgoing payment(%v)",
+				spew.Sdump(outgoingPayment))
 		}
 
-		lastSyncedBlock = block
+		if needUpdateStatusOfIncoming {
+			incomingPayment := payment
+			incomingPayment.Direction = connectors.Incoming
+			incomingPayment.MediaFee = decimal.Zero
+			incomingPayment.PaymentID, err = incomingPayment.GenPaymentID()
+			if err != nil {
+				return err
+			}
 
-		// After transaction has been consumed by other subsystem
-		// overwrite cache.
-		c.log.Infof("Process block hash(%v), number(%v)", block.Hash, block.Number)
+			if err := c.cfg.PaymentStorage.SavePayment(&incomingPayment); err != nil {
+				return errors.Errorf("unable to add payment to storage: %v",
+					incomingPayment.PaymentID)
+			}
 
-		// Report last synchronised block number from daemon point of view.
-		m.BlockNumber(int64(lastSyncedBlock.Number))
+			c.log.Infof("Confirmed incoming payment(%v)",
+				spew.Sdump(incomingPayment))
+
+			if needRedirect {
+				// In this case we received transaction on one of our
+				// non-internal accounts we should make money
+				// aggregation on default account.
+				c.log.Infof("Make redirect of payment("+
+					"%v)", incomingPayment.PaymentID)
+				if err := c.makeRedirect(confirmedTx.To, amount); err != nil {
+					c.log.Errorf("unable to make payment(%v) "+
+						"redirection: %v", spew.Sdump(incomingPayment), err)
+					m.AddError(metrics.HighSeverity)
+				}
+			}
+		}
 	}
+
+	return nil
 }
 
 // makeRedirect is used to make a redirect of previously received money on
@@ -1060,7 +1343,7 @@ func (c *Connector) makeRedirect(initialAddress string, amount decimal.Decimal)
 	// address on default account.
 	// TODO(andrew.shvv) What if fee is greater than sending amount?
 	aggregateTx, fee, err := c.generateTransaction(initialAddress, c.defaultAddress,
-		amount, true, txCount)
+		amount, true, txCount, "")
 	if err != nil {
 		return errors.Errorf("unable to generate transfer tx(%v): %v", err)
 	}
@@ -1070,7 +1353,7 @@ func (c *Connector) makeRedirect(initialAddress string, amount decimal.Decimal)
 		Status:    connectors.Waiting,
 		System:    connectors.Internal,
 		Account:   string(defaultAccount),
-		Receipt:   c.defaultAddress,
+		Receipt:   checksumAddress(c.defaultAddress),
 		Asset:     c.cfg.Asset,
 		Media:     connectors.Blockchain,
 		Amount:    amount.Sub(fee),
@@ -1214,7 +1497,11 @@ func (c *Connector) syncPendingTransactions(lastSyncedBlockNumber int) error {
 		return errors.Errorf("unable to sync unconfirmed txs: %v", err)
 	}
 
+	unconfirmedAmount := unconfirmedTxs.amount(string(defaultAccount))
+	c.evictPending("unconfirmed", unconfirmedTxs, m)
+
 	c.pendingLock.Lock()
+	c.unconfirmedAmount = unconfirmedAmount
 	c.unconfirmedTxs.merge(unconfirmedTxs,
 		func(payment *connectors.Payment) {
 			details, ok := payment.Detail.(*connectors.BlockchainPendingDetails)
@@ -1228,6 +1515,7 @@ func (c *Connector) syncPendingTransactions(lastSyncedBlockNumber int) error {
 				"left(%v)", payment.PaymentID, payment.Account, payment.Amount,
 				details.Confirmations, details.ConfirmationsLeft)
 		})
+	m.CacheSize("unconfirmed", c.unconfirmedTxs.size())
 	c.pendingLock.Unlock()
 
 	memPoolTxs, err := c.syncPending()
@@ -1236,30 +1524,157 @@ func (c *Connector) syncPendingTransactions(lastSyncedBlockNumber int) error {
 		return errors.Errorf("unable to fetch mempool txs: %v", err)
 	}
 
+	memPoolAmount := memPoolTxs.amount(string(defaultAccount))
+	c.evictPending("mempool", memPoolTxs, m)
+
 	c.pendingLock.Lock()
+	c.memPoolAmount = memPoolAmount
 	c.memPoolTxs.merge(memPoolTxs, func(tx *connectors.Payment) {
 		c.log.Infof("Mempool tx(%v) were added, "+
 			"account(%v), amount(%v)", tx.PaymentID, tx.Account, tx.Amount)
 	})
+	m.CacheSize("mempool", c.memPoolTxs.size())
 	c.pendingLock.Unlock()
 
 	return nil
 }
 
-// ValidateAddress validates given blockchain address.
+// evictPending bounds the size of the pending map before it is merged in
+// the in-memory cache. Pending balance should be calculated before the
+// eviction. Payments of default account are evicted last, so that their
+// appearance is logged.
+func (c *Connector) evictPending(name string, txs pendingMap,
+	m crypto.Metric) {
+
+	evicted := txs.truncate(c.cfg.MaxPendingPayments, string(defaultAccount))
+	if evicted == 0 {
+		return
+	}
+
+	c.log.Warnf("Number of %v payments exceeded limit(%v), %v payments "+
+		"were evicted from cache", name, c.cfg.MaxPendingPayments, evicted)
+	m.AddError(metrics.LowSeverity)
+}
+
+// WatchAddress prepares connector to track the activity of the address.
+// Every confirmed block is scanned by connector anyway, so no preparation
+// is needed.
+//
+// NOTE: Part of the connectors.Connector interface.
+func (c *Connector) WatchAddress(address string) error {
+	if c.cfg.WatchStore == nil {
+		return errors.New("watch store is not specified")
+	}
+
+	return nil
+}
+
+// fetchWatchedAddresses returns map of active watched addresses by their
+// blockchain address.
+func (c *Connector) fetchWatchedAddresses() (
+	map[string]*connectors.WatchAddress, error) {
+	if c.cfg.WatchStore == nil {
+		return nil, nil
+	}
+
+	addresses, err := c.cfg.WatchStore.ListWatchAddresses(c.cfg.Asset, "")
+	if err != nil {
+		return nil, errors.Errorf("unable to list watch addresses: %v", err)
+	}
+
+	watched := make(map[string]*connectors.WatchAddress, len(addresses))
+	for _, address := range addresses {
+		if address.Inactive {
+			continue
+		}
+
+		watched[address.Address] = address
+	}
+
+	return watched, nil
+}
+
+// processWatchedTx produces watch events if the confirmed transaction is
+// touching the watched addresses.
+func (c *Connector) processWatchedTx(
+	watched map[string]*connectors.WatchAddress, tx ethrpc.Transaction) error {
+	if len(watched) == 0 {
+		return nil
+	}
+
+	amount := decimal.NewFromBigInt(&tx.Value, 0).Div(weiInEth)
+
+	sides := []struct {
+		address   string
+		direction connectors.PaymentDirection
+	}{
+		{address: checksumAddress(tx.From), direction: connectors.Outgoing},
+		{address: checksumAddress(tx.To), direction: connectors.Incoming},
+	}
+
+	for _, side := range sides {
+		address, ok := watched[side.address]
+		if !ok {
+			continue
+		}
+
+		c.log.Infof("Detected activity of watched address(%v), "+
+			"group(%v), tx(%v)", side.address, address.Group, tx.Hash)
+
+		event := &connectors.WatchEvent{
+			CreatedAt: connectors.NowInMilliSeconds(),
+			Group:     address.Group,
+			Asset:     c.cfg.Asset,
+			Address:   side.address,
+			Account:   address.Account,
+			Direction: side.direction,
+			Amount:    amount,
+			TxID:      tx.Hash,
+		}
+
+		created, err := connectors.ProcessWatchEvent(c.cfg.WatchStore,
+			c.cfg.WatchNotifier, event)
+		if err != nil {
+			return errors.Errorf("unable to process watch event: %v", err)
+		}
+
+		// Only confirmed transactions are processed, that is why funding
+		// of the watched address is published once, as the completed
+		// received payment.
+		if !created || side.direction != connectors.Incoming {
+			continue
+		}
+
+		notifier, ok := c.cfg.PaymentStorage.(connectors.PaymentsNotifier)
+		if ok {
+			notifier.NotifyPayment(event.ReceivedPayment(
+				connectors.Completed, 0, 0))
+		}
+	}
+
+	return nil
+}
+
+// ValidateAddress validates given blockchain address, and returns it in
+// checksum encoding.
 //
 // NOTE: Part of the connectors.Connector interface.
-func (c *Connector) ValidateAddress(address string) error {
+func (c *Connector) ValidateAddress(address string) (*connectors.AddressInfo, error) {
 	m := crypto.NewMetric(c.cfg.DaemonCfg.Name, string(c.cfg.Asset),
 		common.GetFunctionName(), c.cfg.Metrics)
 	defer m.Finish()
 
-	if err := ethereum.ValidateAddress(address); err != nil {
+	normalizedAddress, err := ethereum.NormalizeAddress(address)
+	if err != nil {
 		m.AddError(metrics.LowSeverity)
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &connectors.AddressInfo{
+		Address: normalizedAddress,
+		Type:    connectors.AccountAddress,
+		Net:     c.cfg.Net,
+	}, nil
 }
 
 // EstimateFee estimate fee for the transaction with the given sending
@@ -1287,6 +1702,51 @@ func (c *Connector) EstimateFee(amount string) (decimal.Decimal, error) {
 	return decimal.NewFromBigInt(txFee, 0).Div(weiInEth), nil
 }
 
+// AssetParams returns the parameters of the asset, ethereum has no dust
+// limit, any amount could be sent.
+//
+// NOTE: Part of the connectors.AssetReporter interface.
+func (c *Connector) AssetParams() *connectors.AssetParams {
+	return &connectors.AssetParams{
+		Decimals:         18,
+		MinConfirmations: int64(c.cfg.MinConfirmations),
+	}
+}
+
+// ChainState returns current state of the daemon chain.
+//
+// NOTE: Part of the connectors.ChainReporter interface.
+func (c *Connector) ChainState() (*connectors.ChainState, error) {
+	m := crypto.NewMetric(c.cfg.DaemonCfg.Name, string(c.cfg.Asset),
+		common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	height, err := c.client.EthBlockNumber()
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to get best block number: %v", err)
+	}
+
+	syncing, err := c.client.EthSyncing()
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to get sync status: %v", err)
+	}
+
+	state := &connectors.ChainState{
+		Height:        int64(height),
+		NetworkHeight: int64(height),
+		Synced:        !syncing.IsSyncing,
+	}
+
+	if syncing.IsSyncing {
+		state.Height = int64(syncing.CurrentBlock)
+		state.NetworkHeight = int64(syncing.HighestBlock)
+	}
+
+	return state, nil
+}
+
 // reportMetrics is used to report necessary health metrics about internal
 // state of the connector.
 func (c *Connector) reportMetrics() error {
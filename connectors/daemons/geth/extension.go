@@ -58,6 +58,18 @@ func (c *ExtendedEthRpc) EthGasPrice() (string, error) {
 	return response, nil
 }
 
+// EthTransactionKnown returns true if the transaction with the given hash is
+// known to the daemon, i.e. it is either in the mempool or in the
+// blockchain.
+func (c *ExtendedEthRpc) EthTransactionKnown(hash string) (bool, error) {
+	var tx *json.RawMessage
+	if err := c.call("eth_getTransactionByHash", &tx, hash); err != nil {
+		return false, err
+	}
+
+	return tx != nil, nil
+}
+
 func (c *ExtendedEthRpc) call(method string, target interface{},
 	params ...interface{}) error {
 	result, err := c.Call(method, params...)
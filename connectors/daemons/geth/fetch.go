@@ -0,0 +1,83 @@
+package geth
+
+import (
+	"github.com/bitlum/connector/common"
+	"github.com/go-errors/errors"
+	"github.com/onrik/ethrpc"
+)
+
+// fetchBlocks concurrently fetches blocks with transactions in the given
+// range of numbers, and returns them in ascending order. Number of
+// concurrent requests to daemon is bounded by number of sync workers.
+func (c *Connector) fetchBlocks(from, to int) ([]*ethrpc.Block, error) {
+	if to < from {
+		return nil, nil
+	}
+
+	blocks := make([]*ethrpc.Block, to-from+1)
+	fetch := func(i int) error {
+		block, err := c.client.EthGetBlockByNumber(from+i, true)
+		if err != nil {
+			return errors.Errorf("unable to get block(%v): %v", from+i, err)
+		}
+
+		blocks[i] = block
+		return nil
+	}
+
+	err := common.RunWorkers(c.cfg.SyncWorkers, len(blocks), c.quit, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// fetchReceipts concurrently fetches receipts of the transactions which
+// sender or receiver address belongs to our system.
+func (c *Connector) fetchReceipts(txs []ethrpc.Transaction) (
+	map[string]*ethrpc.TransactionReceipt, error) {
+
+	var hashes []string
+	for _, tx := range txs {
+		senderAccount, err := c.cfg.AccountStorage.GetAccountByAddress(tx.From)
+		if err != nil {
+			return nil, err
+		}
+
+		receiverAccount, err := c.cfg.AccountStorage.GetAccountByAddress(tx.To)
+		if err != nil {
+			return nil, err
+		}
+
+		if senderAccount == "" && receiverAccount == "" {
+			continue
+		}
+
+		hashes = append(hashes, tx.Hash)
+	}
+
+	receipts := make([]*ethrpc.TransactionReceipt, len(hashes))
+	fetch := func(i int) error {
+		receipt, err := c.client.EthGetTransactionReceipt(hashes[i])
+		if err != nil {
+			return errors.Errorf("unable to get transaction receipt "+
+				"for tx(%v): %v", hashes[i], err)
+		}
+
+		receipts[i] = receipt
+		return nil
+	}
+
+	err := common.RunWorkers(c.cfg.SyncWorkers, len(hashes), c.quit, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptsByHash := make(map[string]*ethrpc.TransactionReceipt, len(hashes))
+	for i, hash := range hashes {
+		receiptsByHash[hash] = receipts[i]
+	}
+
+	return receiptsByHash, nil
+}
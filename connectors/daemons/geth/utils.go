@@ -1,10 +1,50 @@
 package geth
 
 import (
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/connectors/rpc/ethereum"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/shopspring/decimal"
 )
 
+// checksumAddress returns address in EIP-55 checksum encoding. Daemon returns
+// addresses in lowercase, and if we wouldn't convert them, the same receipt
+// might be stored in different forms. If address is invalid, for example
+// empty in case of contract creation, it is returned as is.
+func checksumAddress(address string) string {
+	normalizedAddress, err := ethereum.NormalizeAddress(address)
+	if err != nil {
+		return address
+	}
+
+	return normalizedAddress
+}
+
+// encodeMemo encodes memo in the hex format, which is used for the data field
+// of the transaction.
+func encodeMemo(memo string) string {
+	if memo == "" {
+		return ""
+	}
+
+	return "0x" + hex.EncodeToString([]byte(memo))
+}
+
+// decodeMemo decodes the data field of the transaction. If data is not a
+// text, for example it is a contract call, than empty memo is returned.
+func decodeMemo(data string) string {
+	b, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	if err != nil || !utf8.Valid(b) {
+		return ""
+	}
+
+	return string(b)
+}
+
 // pendingMap stores the information about pending transactions corresponding
 // to accounts.
 type pendingMap map[string]map[string]*connectors.Payment
@@ -50,14 +90,70 @@ func (m pendingMap) merge(m2 pendingMap,
 			continue
 		}
 
-		for txid, _ := range txs {
-			if _, ok := m[account][txid]; !ok {
+		for txid := range txs {
+			if _, ok := m2[account][txid]; !ok {
 				delete(m[account], txid)
 			}
 		}
 	}
 }
 
+// size returns overall number of transactions in the map.
+func (m pendingMap) size() int {
+	var size int
+	for _, txs := range m {
+		size += len(txs)
+	}
+
+	return size
+}
+
+// amount returns overall amount of the transactions of the account.
+func (m pendingMap) amount(account string) decimal.Decimal {
+	amount := decimal.Zero
+	for _, tx := range m[account] {
+		amount = amount.Add(tx.Amount)
+	}
+
+	return amount
+}
+
+// truncate removes transactions from the map till its size reaches the
+// given maximum, transactions of the kept account are removed last. Returns
+// number of removed transactions.
+func (m pendingMap) truncate(max int, keptAccount string) int {
+	excess := m.size() - max
+	if excess <= 0 {
+		return 0
+	}
+
+	var removed int
+	evict := func(account string) {
+		for txid := range m[account] {
+			if removed == excess {
+				return
+			}
+
+			delete(m[account], txid)
+			removed++
+		}
+
+		if len(m[account]) == 0 {
+			delete(m, account)
+		}
+	}
+
+	for account := range m {
+		if account != keptAccount {
+			evict(account)
+		}
+	}
+
+	evict(keptAccount)
+
+	return removed
+}
+
 func convertVersion(actualNet string) string {
 	net := "simnet"
 
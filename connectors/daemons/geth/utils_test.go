@@ -0,0 +1,125 @@
+package geth
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+)
+
+func TestMemoEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		memo string
+		data string
+	}{
+		{
+			name: "empty",
+			memo: "",
+			data: "",
+		},
+		{
+			name: "text",
+			memo: "deposit 1234",
+			data: "0x6465706f7369742031323334",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if data := encodeMemo(tt.memo); data != tt.data {
+				t.Fatalf("wrong data, got(%v), want(%v)", data, tt.data)
+			}
+
+			if memo := decodeMemo(tt.data); memo != tt.memo {
+				t.Fatalf("wrong memo, got(%v), want(%v)", memo, tt.memo)
+			}
+		})
+	}
+
+	// Contract calls shouldn't be treated as memo.
+	if memo := decodeMemo("0xa9059cbb000000000000000000000000"); memo != "" {
+		t.Fatalf("contract call decoded as memo: %v", memo)
+	}
+}
+
+func TestPendingMapMerge(t *testing.T) {
+	m := make(pendingMap)
+	m.add(&connectors.Payment{Account: "a", PaymentID: "1"})
+	m.add(&connectors.Payment{Account: "a", PaymentID: "2"})
+	m.add(&connectors.Payment{Account: "b", PaymentID: "3"})
+
+	m2 := make(pendingMap)
+	m2.add(&connectors.Payment{Account: "a", PaymentID: "2"})
+	m2.add(&connectors.Payment{Account: "a", PaymentID: "4"})
+
+	var added []string
+	m.merge(m2, func(tx *connectors.Payment) {
+		added = append(added, tx.PaymentID)
+	})
+
+	if len(added) != 1 || added[0] != "4" {
+		t.Fatalf("wrong added payments: %v", added)
+	}
+
+	if _, ok := m["b"]; ok {
+		t.Fatalf("account hasn't been removed")
+	}
+
+	if _, ok := m["a"]["1"]; ok {
+		t.Fatalf("stale payment hasn't been removed")
+	}
+
+	if m.size() != 2 {
+		t.Fatalf("wrong size, got(%v), want(%v)", m.size(), 2)
+	}
+}
+
+func TestPendingMapTruncate(t *testing.T) {
+	m := make(pendingMap)
+	m.add(&connectors.Payment{Account: "default", PaymentID: "1"})
+	m.add(&connectors.Payment{Account: "default", PaymentID: "2"})
+	m.add(&connectors.Payment{Account: "a", PaymentID: "3"})
+	m.add(&connectors.Payment{Account: "b", PaymentID: "4"})
+
+	if removed := m.truncate(5, "default"); removed != 0 {
+		t.Fatalf("payments removed under the limit: %v", removed)
+	}
+
+	if removed := m.truncate(2, "default"); removed != 2 {
+		t.Fatalf("wrong number of removed payments, got(%v), want(%v)",
+			removed, 2)
+	}
+
+	if len(m["default"]) != 2 {
+		t.Fatalf("payments of kept account have been removed")
+	}
+
+	if removed := m.truncate(1, "default"); removed != 1 {
+		t.Fatalf("wrong number of removed payments, got(%v), want(%v)",
+			removed, 1)
+	}
+
+	if m.size() != 1 {
+		t.Fatalf("wrong size, got(%v), want(%v)", m.size(), 1)
+	}
+}
+
+func TestPendingMapAmountBeforeTruncate(t *testing.T) {
+	m := make(pendingMap)
+	m.add(&connectors.Payment{Account: "default", PaymentID: "1",
+		Amount: decimal.New(1, 0)})
+	m.add(&connectors.Payment{Account: "default", PaymentID: "2",
+		Amount: decimal.New(2, 0)})
+	m.add(&connectors.Payment{Account: "a", PaymentID: "3",
+		Amount: decimal.New(4, 0)})
+
+	// Amount is calculated before truncation, which evicts one of the
+	// payments of default account.
+	amount := m.amount("default")
+	m.truncate(1, "default")
+
+	if !amount.Equal(decimal.New(3, 0)) {
+		t.Fatalf("wrong amount, got(%v), want(%v)", amount, 3)
+	}
+}
@@ -0,0 +1,117 @@
+package geth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/db/inmemory"
+	"github.com/btcsuite/btclog"
+	"github.com/onrik/ethrpc"
+	"github.com/shopspring/decimal"
+)
+
+// watchStore keeps the watch events in memory.
+type watchStore struct {
+	connectors.WatchStore
+
+	events map[string]*connectors.WatchEvent
+}
+
+func (s *watchStore) WatchEventExists(eventID string) (bool, error) {
+	_, ok := s.events[eventID]
+	return ok, nil
+}
+
+func (s *watchStore) SaveWatchEvent(event *connectors.WatchEvent) error {
+	s.events[event.EventID] = event
+	return nil
+}
+
+// TestProcessWatchedTx checks that funding of the watched address is
+// published to the payments subscribers as the completed received payment,
+// once, and that spending from it isn't.
+func TestProcessWatchedTx(t *testing.T) {
+	const (
+		watched = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+		other   = "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"
+	)
+
+	store := &watchStore{events: make(map[string]*connectors.WatchEvent)}
+	payments := connectors.NewPaymentsBroadcaster(
+		inmemory.NewMemoryPaymentsStore())
+	subscription := payments.SubscribePayments()
+	defer subscription.Cancel()
+
+	c := &Connector{
+		cfg: &Config{
+			Asset:          connectors.ETH,
+			PaymentStorage: payments,
+			WatchStore:     store,
+		},
+		log: &common.NamedLogger{
+			Name:   "ETH",
+			Logger: btclog.Disabled,
+		},
+	}
+
+	addresses := map[string]*connectors.WatchAddress{
+		watched: {
+			Group:   "cold",
+			Asset:   connectors.ETH,
+			Address: watched,
+			Account: "vault",
+		},
+	}
+
+	value := new(big.Int).Mul(big.NewInt(15), big.NewInt(1e17))
+	funding := ethrpc.Transaction{
+		Hash:  "0xfunding",
+		From:  other,
+		To:    watched,
+		Value: *value,
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := c.processWatchedTx(addresses, funding); err != nil {
+			t.Fatalf("unable to process tx: %v", err)
+		}
+	}
+
+	select {
+	case payment := <-subscription.Updates:
+		if payment.Status != connectors.Completed ||
+			payment.Direction != connectors.Incoming ||
+			payment.Receipt != watched ||
+			payment.MediaID != "0xfunding" ||
+			!payment.Amount.Equal(decimal.New(15, -1)) ||
+			payment.Metadata[connectors.WatchGroupLabel] != "cold" {
+			t.Fatalf("wrong received payment: %v", payment)
+		}
+	default:
+		t.Fatalf("received payment hasn't been published")
+	}
+
+	spending := ethrpc.Transaction{
+		Hash:  "0xspending",
+		From:  watched,
+		To:    other,
+		Value: *value,
+	}
+
+	if err := c.processWatchedTx(addresses, spending); err != nil {
+		t.Fatalf("unable to process tx: %v", err)
+	}
+
+	if len(store.events) != 2 {
+		t.Fatalf("watch events haven't been created: %v", len(store.events))
+	}
+
+	// Neither the repeated funding, nor the spending are published.
+	select {
+	case payment := <-subscription.Updates:
+		t.Fatalf("unexpected payment(%v) is published", payment.PaymentID)
+	default:
+	}
+}
@@ -57,6 +57,10 @@ type Config struct {
 	// RPC requests. Should be empty if lnd run with --no-macaroon option.
 	MacaroonPath string
 
+	// Proxy is the address of the SOCKS5 proxy through which connection
+	// to lnd daemon is established, if daemon is the .onion host.
+	Proxy string
+
 	// Metrics is a metric backend which is used to collect metrics from
 	// connector. In case of prometheus client they stored locally till
 	// they will be collected by prometheus server.
@@ -65,6 +69,12 @@ type Config struct {
 	// PaymentStorage is an external storage for payments, it is used by
 	// connector to save payment as well as update its state.
 	PaymentStore connectors.PaymentsStore
+
+	// DepositsStore is used to bind settled invoices to the payments by
+	// their hash, so that invoice replayed by the daemon after reconnect
+	// or resync isn't recorded twice. If it is not specified settled
+	// invoices are identified by the payment id only.
+	DepositsStore connectors.DepositsStore
 }
 
 func (c *Config) validate() error {
@@ -169,23 +179,11 @@ func (c *Connector) Start() (err error) {
 		return errors.Errorf("unable get lnd node info: %v", err)
 	}
 
-	lndNet := "simnet"
-	if respInfo.Testnet {
-		lndNet = "testnet"
+	if err := checkNet(c.cfg.Net, respInfo); err != nil {
+		return err
 	}
 
-	// TODO(andrew.shvv) not working for mainnet, as far response don't have
-	// a mainnet param.
-	if c.cfg.Net != "mainnet" {
-		if lndNet != c.cfg.Net {
-			return errors.Errorf("hub net is '%v', but config net is '%v'",
-				c.cfg.Net, lndNet)
-		}
-
-		log.Infof("Init connector working with '%v' net", lndNet)
-	} else {
-		log.Info("Init connector working with 'mainnet' net")
-	}
+	log.Infof("Init connector working with '%v' net", c.cfg.Net)
 
 	c.nodeAddr = respInfo.IdentityPubkey
 	var invoiceSubscription lnrpc.Lightning_SubscribeInvoicesClient
@@ -311,6 +309,23 @@ func (c *Connector) Start() (err error) {
 				MediaFee:  decimal.Zero,
 			}
 
+			if c.cfg.DepositsStore != nil {
+				paymentID, err := c.cfg.DepositsStore.RecordDeposit(
+					connectors.BTC, paymentHash, payment.PaymentID)
+				if err != nil {
+					m.AddError(metrics.HighSeverity)
+					log.Errorf("unable to record deposit(%v): %v",
+						paymentHash, err)
+					continue
+				}
+
+				if paymentID != payment.PaymentID {
+					log.Warnf("Invoice(%v) has been already recorded as "+
+						"payment(%v)", paymentHash, paymentID)
+					payment.PaymentID = paymentID
+				}
+			}
+
 			if err := c.cfg.PaymentStore.SavePayment(payment); err != nil {
 				log.Errorf("unable to add payment to storage: %v",
 					payment.PaymentID)
@@ -364,6 +379,43 @@ description string) (string, *zpay32.Invoice, error) {
 		Expiry:  int64(expirationTime.Seconds()),
 	}
 
+	return c.addInvoice(m, invoiceReq)
+}
+
+// Runtime check to ensure that Connector implements
+// connectors.HashedInvoiceCreator interface.
+var _ connectors.HashedInvoiceCreator = (*Connector)(nil)
+
+// CreateHashedInvoice is used to create lightning network invoice which
+// carries the hash of the description instead of the description itself.
+//
+// NOTE: Part of the connectors.HashedInvoiceCreator interface.
+func (c *Connector) CreateHashedInvoice(receipt, amount string,
+	descriptionHash []byte) (string, *zpay32.Invoice, error) {
+	m := crypto.NewMetric(c.cfg.Name, "BTC", common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	satoshis, err := btcToSatoshi(amount)
+	if err != nil {
+		m.AddError(metrics.LowSeverity)
+		return "", nil, err
+	}
+
+	expirationTime := time.Minute * 15
+	invoiceReq := &lnrpc.Invoice{
+		Receipt:         []byte(receipt),
+		Value:           satoshis,
+		DescriptionHash: descriptionHash,
+		Expiry:          int64(expirationTime.Seconds()),
+	}
+
+	return c.addInvoice(m, invoiceReq)
+}
+
+// addInvoice adds the invoice to the node, and decodes its payment
+// request.
+func (c *Connector) addInvoice(m crypto.Metric,
+	invoiceReq *lnrpc.Invoice) (string, *zpay32.Invoice, error) {
 	invoiceResp, err := c.client.AddInvoice(context.Background(), invoiceReq)
 	if err != nil {
 		m.AddError(metrics.HighSeverity)
@@ -457,16 +509,19 @@ func (c *Connector) SendTo(invoiceStr, amountStr string) (*connectors.Payment,
 	receiverNodeAddr := hex.EncodeToString(invoice.Destination.
 		SerializeCompressed())
 
+	system := connectors.External
 	if receiverNodeAddr == c.nodeAddr {
 		// If we try to send payment to ourselves, than lightning network daemon
 		// will fail, for that reason we handle this and pretend as if payment
-		// was actually has been made.
+		// was actually has been made. Such payment doesn't change the
+		// balance, that is why both its sides are internal.
+		system = connectors.Internal
 		payment := &connectors.Payment{
 			PaymentID: generatePaymentID(invoiceStr, connectors.Incoming),
 			UpdatedAt: connectors.NowInMilliSeconds(),
 			Status:    connectors.Completed,
 			Direction: connectors.Incoming,
-			System:    connectors.External,
+			System:    system,
 			Receipt:   invoiceStr,
 			Asset:     connectors.BTC,
 			Media:     connectors.Lightning,
@@ -512,7 +567,7 @@ func (c *Connector) SendTo(invoiceStr, amountStr string) (*connectors.Payment,
 		PaymentID: generatePaymentID(invoiceStr, connectors.Outgoing),
 		UpdatedAt: connectors.NowInMilliSeconds(),
 		Status:    connectors.Completed,
-		System:    connectors.External,
+		System:    system,
 		Direction: connectors.Outgoing,
 		Receipt:   invoiceStr,
 		Asset:     connectors.BTC,
@@ -563,6 +618,49 @@ func (c *Connector) Info() (*connectors.LightningInfo, error) {
 	}, nil
 }
 
+// Runtime check to ensure that Connector implements
+// connectors.NodeMessageSigner interface.
+var _ connectors.NodeMessageSigner = (*Connector)(nil)
+
+// SignNodeMessage signs the message with the identity key of the node.
+//
+// NOTE: Part of the connectors.NodeMessageSigner interface.
+func (c *Connector) SignNodeMessage(message string) (string, error) {
+	m := crypto.NewMetric(c.cfg.Name, "BTC", common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	resp, err := c.client.SignMessage(context.Background(),
+		&lnrpc.SignMessageRequest{Msg: []byte(message)})
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return "", errors.Errorf("unable to sign message: %v", err)
+	}
+
+	return resp.Signature, nil
+}
+
+// VerifyNodeMessage verifies the signature of the message, and returns the
+// public key of the node which has signed it.
+//
+// NOTE: Part of the connectors.NodeMessageSigner interface.
+func (c *Connector) VerifyNodeMessage(message, signature string) (string,
+	bool, error) {
+	m := crypto.NewMetric(c.cfg.Name, "BTC", common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	resp, err := c.client.VerifyMessage(context.Background(),
+		&lnrpc.VerifyMessageRequest{
+			Msg:       []byte(message),
+			Signature: signature,
+		})
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		return "", false, errors.Errorf("unable to verify message: %v", err)
+	}
+
+	return resp.Pubkey, resp.Valid, nil
+}
+
 // QueryRoutes returns list of routes from to the given lnd node,
 // and insures the the capacity of the channels is sufficient.
 //
@@ -651,6 +749,33 @@ amountStr string) (*zpay32.Invoice, error) {
 	return invoice, nil
 }
 
+// InboundCapacity returns the amount of funds which could be received
+// through the active channels, i.e. the sum of remote balances.
+//
+// NOTE: Part of the connectors.LightningConnector interface.
+func (c *Connector) InboundCapacity() (decimal.Decimal, error) {
+	m := crypto.NewMetric(c.cfg.Name, "BTC", common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	req := &lnrpc.ListChannelsRequest{
+		ActiveOnly: true,
+	}
+	resp, err := c.client.ListChannels(context.Background(), req)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return decimal.Zero, err
+	}
+
+	var remoteBalance int64
+	for _, channel := range resp.Channels {
+		remoteBalance += channel.RemoteBalance
+	}
+
+	balanceSatoshis := decimal.New(remoteBalance, 0)
+	balanceBTC := balanceSatoshis.Div(satoshiPerBitcoin)
+	return balanceBTC.Round(8), nil
+}
+
 // ConfirmedBalance return the amount of confirmed funds available for account.
 // TODO(andrew.shvv) Show funds locked in the channels
 //
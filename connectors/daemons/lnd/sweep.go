@@ -0,0 +1,123 @@
+package lnd
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/connectors/rpc/bitcoin"
+	"github.com/bitlum/connector/metrics"
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/btcsuite/btcutil"
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/shopspring/decimal"
+)
+
+// Runtime check to ensure that Connector implements
+// connectors.FundsSweeper interface.
+var _ connectors.FundsSweeper = (*Connector)(nil)
+
+// SweepFunds sends all confirmed funds of the lnd wallet to the address,
+// fee is subtracted from the sent amount by the daemon. Funds which are
+// locked in the channels are not swept, channels should be closed first.
+//
+// NOTE: Part of the connectors.FundsSweeper interface.
+func (c *Connector) SweepFunds(address string) (*connectors.Payment, error) {
+	m := crypto.NewMetric(c.cfg.Name, "BTC", common.GetFunctionName(), c.cfg.Metrics)
+	defer m.Finish()
+
+	netParams, err := bitcoin.GetParams(c.cfg.Net)
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, err
+	}
+
+	decodedAddress, err := btcutil.DecodeAddress(address, netParams)
+	if err != nil || !decodedAddress.IsForNet(netParams) {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.Errorf("invalid address(%v)", address)
+	}
+	receipt := decodedAddress.EncodeAddress()
+
+	balance, err := c.client.WalletBalance(context.Background(),
+		&lnrpc.WalletBalanceRequest{})
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, err
+	}
+
+	if balance.ConfirmedBalance == 0 {
+		m.AddError(metrics.LowSeverity)
+		return nil, errors.New("there are no confirmed funds to sweep")
+	}
+
+	resp, err := c.client.SendCoins(context.Background(),
+		&lnrpc.SendCoinsRequest{
+			Addr:    receipt,
+			SendAll: true,
+		})
+	if err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable to sweep funds: %v", err)
+	}
+
+	// Daemon doesn't return the fee of the sweep, that is why it is taken
+	// from the wallet transaction. Transaction is already broadcasted, so
+	// payment is saved even if the fee is unknown.
+	fee, err := c.transactionFee(resp.Txid)
+	if err != nil {
+		m.AddError(metrics.MiddleSeverity)
+		log.Errorf("Unable to get fee of the sweep tx(%v): %v", resp.Txid,
+			err)
+	}
+
+	amount := decimal.New(balance.ConfirmedBalance-fee, 0).
+		Div(satoshiPerBitcoin).Round(8)
+
+	// Funds leave the wallet once transaction is broadcasted, and
+	// on-chain transactions of the daemon are not synced, that is why
+	// payment is completed right away.
+	payment := &connectors.Payment{
+		PaymentID: generatePaymentID(resp.Txid, connectors.Outgoing),
+		UpdatedAt: connectors.ConvertTimeToMilliSeconds(time.Now()),
+		Status:    connectors.Completed,
+		Direction: connectors.Outgoing,
+		System:    connectors.External,
+		Receipt:   receipt,
+		Asset:     connectors.BTC,
+		Media:     connectors.Lightning,
+		Amount:    amount,
+		MediaFee:  decimal.New(fee, 0).Div(satoshiPerBitcoin).Round(8),
+		MediaID:   resp.Txid,
+	}
+
+	if err := c.cfg.PaymentStore.SavePayment(payment); err != nil {
+		m.AddError(metrics.HighSeverity)
+		return nil, errors.Errorf("unable save payment(%v): %v",
+			payment.PaymentID, err)
+	}
+
+	log.Infof("Swept %v BTC to address(%v), tx(%v)", amount, receipt,
+		resp.Txid)
+
+	return payment, nil
+}
+
+// transactionFee returns the fee in satoshis of the wallet transaction.
+func (c *Connector) transactionFee(txID string) (int64, error) {
+	resp, err := c.client.GetTransactions(context.Background(),
+		&lnrpc.GetTransactionsRequest{})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, tx := range resp.Transactions {
+		if tx.TxHash == txID {
+			return tx.TotalFees, nil
+		}
+	}
+
+	return 0, errors.Errorf("transaction isn't found in the wallet")
+}
@@ -1,6 +1,7 @@
 package lnd
 
 import (
+	"context"
 	"github.com/btcsuite/btcutil"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
@@ -14,6 +15,8 @@ import (
 	"gopkg.in/macaroon.v2"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"net"
+	"time"
+	"github.com/bitlum/connector/common"
 )
 
 var satoshiPerBitcoin = decimal.New(btcutil.SatoshiPerBitcoin, 0)
@@ -72,6 +75,18 @@ func (c *Connector) getClient(macaroonPath string) (lnrpc.LightningClient,
 			grpc.WithPerRPCCredentials(macaroons.NewMacaroonCredential(mac)))
 	}
 
+	if c.cfg.Proxy != "" {
+		opts = append(opts, grpc.WithDialer(
+			func(addr string, timeout time.Duration) (net.Conn, error) {
+				dial, err := common.NewDialer(c.cfg.Proxy, timeout)
+				if err != nil {
+					return nil, err
+				}
+
+				return dial("tcp", addr)
+			}))
+	}
+
 	target := net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Port))
 	log.Infof("lightning client connection to lnd: %v", target)
 
@@ -82,3 +97,52 @@ func (c *Connector) getClient(macaroonPath string) (lnrpc.LightningClient,
 
 	return lnrpc.NewLightningClient(conn), conn, nil
 }
+
+// checkNet ensures that lnd is working on the given network.
+func checkNet(net string, info *lnrpc.GetInfoResponse) error {
+	// TODO(andrew.shvv) not working for mainnet, as far response don't have
+	// a mainnet param.
+	if net == "mainnet" {
+		return nil
+	}
+
+	lndNet := "simnet"
+	if info.Testnet {
+		lndNet = "testnet"
+	}
+
+	if lndNet != net {
+		return errors.Errorf("hub net is '%v', but config net is '%v'",
+			net, lndNet)
+	}
+
+	return nil
+}
+
+// Probe connects to lnd with the TLS certificate and macaroon of the
+// config, and ensures that it is working on the configured network.
+// Returns the info of the node.
+func Probe(cfg *Config, timeout time.Duration) (*lnrpc.GetInfoResponse,
+	error) {
+
+	c := &Connector{cfg: cfg}
+	client, conn, err := c.getClient(cfg.MacaroonPath)
+	if err != nil {
+		return nil, errors.Errorf("unable get grpc client: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	info, err := client.GetInfo(ctx, &lnrpc.GetInfoRequest{})
+	if err != nil {
+		return nil, errors.Errorf("unable get lnd node info: %v", err)
+	}
+
+	if err := checkNet(cfg.Net, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
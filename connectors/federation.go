@@ -0,0 +1,127 @@
+package connectors
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// FederationEntryKind is the kind of the entry of the federation ledger.
+type FederationEntryKind string
+
+const (
+	// FederationTransfer is the transfer of the internal balance between
+	// the account of this server and the account of the peer.
+	FederationTransfer FederationEntryKind = "transfer"
+
+	// FederationSettlement is the on-chain payment which settles the net
+	// position with the peer.
+	FederationSettlement FederationEntryKind = "settlement"
+)
+
+// FederationEntryStatus is the stage of the processing of the federation
+// ledger entry.
+type FederationEntryStatus string
+
+const (
+	// FederationPending means that entry hasn't been acknowledged by the
+	// peer yet, e.g. because peer has been unreachable.
+	FederationPending FederationEntryStatus = "pending"
+
+	// FederationCompleted means that entry is acknowledged by the peer,
+	// incoming settlement is completed once its funds have been received
+	// by the wallet.
+	FederationCompleted FederationEntryStatus = "completed"
+
+	// FederationRejected means that peer has refused the entry, e.g.
+	// because it doesn't serve the asset.
+	FederationRejected FederationEntryStatus = "rejected"
+)
+
+// FederationEntry is the entry of the ledger with the payserver of another
+// region. Entries are recorded by both servers under the same id, with the
+// opposite directions.
+type FederationEntry struct {
+	// ID is the id of the transfer or settlement, which is generated by
+	// the initiating server.
+	ID string
+
+	// Peer is the name of the peer server.
+	Peer string
+
+	// Kind is the kind of the entry.
+	Kind FederationEntryKind
+
+	// Direction is outgoing if entry is initiated by this server, and
+	// incoming if it is initiated by the peer.
+	Direction PaymentDirection
+
+	// Status is the stage of the processing of the entry.
+	Status FederationEntryStatus
+
+	// Asset is an acronym of the crypto currency.
+	Asset Asset
+
+	// Amount is the amount of the transfer or settlement.
+	Amount decimal.Decimal
+
+	// Account is the local account of the transfer, which is debited by
+	// the outgoing transfer and credited by the incoming one.
+	Account string
+
+	// PeerAccount is the account of the transfer on the peer server.
+	PeerAccount string
+
+	// Memo is the description of the transfer.
+	Memo string
+
+	// PaymentID is the id of the local payment of the entry, internal
+	// payment of the account for the transfer and on-chain payment for
+	// the outgoing settlement.
+	PaymentID string
+
+	// TxID is the id of the settlement transaction.
+	TxID string
+
+	// FailureReason is the reason of the rejection.
+	FailureReason string
+
+	// CreatedAt is the time of the entry creation in milliseconds.
+	CreatedAt int64
+
+	// UpdatedAt is the time of the last entry update in milliseconds.
+	UpdatedAt int64
+}
+
+// PositionDelta returns the change of the net position with the peer which
+// is made by the entry. Position is positive if this server owes the peer,
+// i.e. it holds the funds which have been credited to the peer accounts.
+// Outgoing transfer is counted only once peer has credited it, outgoing
+// settlement is counted as soon as it is sent, and incoming settlement
+// only once its funds have been received.
+func (e *FederationEntry) PositionDelta() decimal.Decimal {
+	if e.Status == FederationRejected {
+		return decimal.Zero
+	}
+
+	switch {
+	case e.Kind == FederationTransfer && e.Direction == Outgoing:
+		if e.Status == FederationCompleted {
+			return e.Amount
+		}
+		return decimal.Zero
+
+	case e.Kind == FederationTransfer && e.Direction == Incoming:
+		return e.Amount.Neg()
+
+	case e.Kind == FederationSettlement && e.Direction == Outgoing:
+		return e.Amount.Neg()
+
+	case e.Kind == FederationSettlement && e.Direction == Incoming:
+		if e.Status == FederationCompleted {
+			return e.Amount
+		}
+		return decimal.Zero
+
+	default:
+		return decimal.Zero
+	}
+}
@@ -0,0 +1,31 @@
+package connectors
+
+// Finality is the settlement depth from which the downstream consumer is
+// interested in the updates of the payment, so that risk-sensitive consumer
+// doesn't have to count the confirmations on its own.
+type Finality struct {
+	// MinConfirmations is the number of the confirmations which pending
+	// payment should have in order to be reported. Pending payment which
+	// confirmations are unknown, e.g. lightning one, isn't reported if it
+	// is set.
+	MinConfirmations int64
+
+	// TerminalOnly is used to report only completed and failed payments.
+	TerminalOnly bool
+}
+
+// Reached returns true if payment has reached the settlement depth.
+// Finished payments are always reported, since completed payment has
+// been confirmed as many times as the connector requires.
+func (f Finality) Reached(payment *Payment) bool {
+	if payment.Status == Completed || payment.Status == Failed {
+		return true
+	}
+
+	if f.TerminalOnly {
+		return false
+	}
+
+	return f.MinConfirmations <= 0 ||
+		confirmations(payment) >= f.MinConfirmations
+}
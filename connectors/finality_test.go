@@ -0,0 +1,73 @@
+package connectors
+
+import (
+	"testing"
+)
+
+func TestFinalityReached(t *testing.T) {
+	pending := func(confirmations int64) *Payment {
+		return &Payment{
+			Status: Pending,
+			Media:  Blockchain,
+			Detail: &BlockchainPendingDetails{
+				Confirmations: confirmations,
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		finality Finality
+		payment  *Payment
+		reached  bool
+	}{
+		{
+			name:    "any update",
+			payment: &Payment{Status: Waiting},
+			reached: true,
+		},
+		{
+			name:     "not enough confirmations",
+			finality: Finality{MinConfirmations: 3},
+			payment:  pending(2),
+			reached:  false,
+		},
+		{
+			name:     "enough confirmations",
+			finality: Finality{MinConfirmations: 3},
+			payment:  pending(3),
+			reached:  true,
+		},
+		{
+			name:     "unknown confirmations",
+			finality: Finality{MinConfirmations: 1},
+			payment:  &Payment{Status: Pending, Media: Lightning},
+			reached:  false,
+		},
+		{
+			name:     "completed",
+			finality: Finality{MinConfirmations: 100},
+			payment:  &Payment{Status: Completed},
+			reached:  true,
+		},
+		{
+			name:     "terminal only pending",
+			finality: Finality{TerminalOnly: true},
+			payment:  pending(10),
+			reached:  false,
+		},
+		{
+			name:     "terminal only failed",
+			finality: Finality{TerminalOnly: true},
+			payment:  &Payment{Status: Failed},
+			reached:  true,
+		},
+	}
+
+	for _, test := range tests {
+		if reached := test.finality.Reached(test.payment); reached != test.reached {
+			t.Fatalf("%v: wrong result, expected(%v), got(%v)", test.name,
+				test.reached, reached)
+		}
+	}
+}
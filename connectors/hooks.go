@@ -0,0 +1,89 @@
+package connectors
+
+import (
+	"fmt"
+)
+
+// PaymentIntent is the outgoing payment which is about to be sent, it is
+// passed to the pre-send hook before the payment is broadcasted.
+type PaymentIntent struct {
+	// Method is the name of the method through which payment is sent,
+	// e.g. "SendPayment".
+	Method string
+
+	// Tenant is the id of the API key with which payment is sent, empty
+	// if it is sent by the admin.
+	Tenant string
+
+	// Asset is an acronym of the crypto currency.
+	Asset Asset
+
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media PaymentMedia
+
+	// Outputs are the recipients of the payment. For lightning media it
+	// is the invoice, for time-locked payment it is the public key of the
+	// recipient.
+	Outputs []*PaymentOutput
+
+	// Memo is the description of the payment.
+	Memo string
+
+	// Account is the identifier of the account to which payment belongs.
+	Account string
+}
+
+// SendHook is the external policy check which is called before the
+// outgoing payment is broadcasted.
+type SendHook interface {
+	// BeforeSend checks the payment, returned error vetoes the payment.
+	// Error is the *SendVetoError if payment has been rejected by the
+	// policy, or *SendHoldError if it should be reviewed before sending,
+	// any other error is the failure of the check itself.
+	BeforeSend(intent *PaymentIntent) error
+}
+
+// SendVetoError is returned by the pre-send hook which has rejected the
+// payment.
+type SendVetoError struct {
+	// Reason is the explanation of the rejection returned by the hook.
+	Reason string
+}
+
+// Error returns the reason of the rejection.
+func (e *SendVetoError) Error() string {
+	return fmt.Sprintf("payment is vetoed by pre-send hook: %v", e.Reason)
+}
+
+// SendHoldError is returned by the hook which requires the payment to be
+// reviewed by the operator, such payment isn't sent, but caller could
+// distinguish it from the denied one and submit it again once the review
+// has been passed.
+type SendHoldError struct {
+	// Reason is the explanation of the hold returned by the hook.
+	Reason string
+}
+
+// Error returns the reason of the hold.
+func (e *SendHoldError) Error() string {
+	return fmt.Sprintf("payment is held for review: %v", e.Reason)
+}
+
+// SendHookChain is the list of the hooks which are checked in order,
+// payment is allowed only if all of them have allowed it.
+type SendHookChain []SendHook
+
+// BeforeSend checks the payment by every hook of the chain, error of the
+// first rejecting hook is returned.
+//
+// NOTE: Part of the SendHook interface.
+func (c SendHookChain) BeforeSend(intent *PaymentIntent) error {
+	for _, hook := range c {
+		if err := hook.BeforeSend(intent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package connectors
 
 import (
+	"context"
+
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"github.com/shopspring/decimal"
@@ -14,6 +16,46 @@ type LightningInfo struct {
 	*lnrpc.GetInfoResponse
 }
 
+// AddressType is the type of the blockchain address, detected during its
+// validation.
+type AddressType string
+
+var (
+	// P2PKH is the pay-to-pubkey-hash address.
+	P2PKH AddressType = "p2pkh"
+
+	// P2SH is the pay-to-script-hash address.
+	P2SH AddressType = "p2sh"
+
+	// P2WPKH is the native segwit pay-to-witness-pubkey-hash address.
+	P2WPKH AddressType = "p2wpkh"
+
+	// P2WSH is the native segwit pay-to-witness-script-hash address.
+	P2WSH AddressType = "p2wsh"
+
+	// P2PK is the pay-to-pubkey address.
+	P2PK AddressType = "p2pk"
+
+	// AccountAddress is the address of the account in account based
+	// blockchains, such as ethereum.
+	AccountAddress AddressType = "account"
+)
+
+// AddressInfo is the information about validated blockchain address.
+type AddressInfo struct {
+	// Address is the canonical form of the address, which should be used
+	// instead of the given one to store and compare addresses: EIP-55
+	// checksum encoding for ethereum, lowercase for bech32, and CashAddr with
+	// prefix for bitcoin cash.
+	Address string
+
+	// Type is the detected type of the address.
+	Type AddressType
+
+	// Net is the network to which address belongs.
+	Net string
+}
+
 // BlockchainConnector is an interface which describes the blockchain service
 // which is able to connect to blockchain daemon of particular currency and
 // operate with transactions, addresses, and also  able to notify other
@@ -29,14 +71,22 @@ type BlockchainConnector interface {
 	PendingBalance() (decimal.Decimal, error)
 
 	// SendPayment sends payment with given amount to the given address.
-	SendPayment(address, amount string) (*Payment, error)
+	// If memo is not empty it is attached to the transaction in the way
+	// which is supported by the blockchain, if blockchain doesn't support
+	// memos error is returned.
+	SendPayment(address, amount, memo string) (*Payment, error)
 
-	// ValidateAddress takes the blockchain address and ensure its valid.
-	ValidateAddress(address string) error
+	// ValidateAddress takes the blockchain address, ensure its valid and
+	// returns its normalized form.
+	ValidateAddress(address string) (*AddressInfo, error)
 
 	// EstimateFee estimate fee for the transaction with the given sending
 	// amount.
 	EstimateFee(amount string) (decimal.Decimal, error)
+
+	// WatchAddress prepares connector to track the activity of the address,
+	// which doesn't belong to it. Address should be in the canonical form.
+	WatchAddress(address string) error
 }
 
 // LightningConnector is an interface which describes the service
@@ -74,4 +124,361 @@ type LightningConnector interface {
 	// EstimateFee estimate fee for the payment with the given sending
 	// amount, to the given node.
 	EstimateFee(invoice string) (decimal.Decimal, error)
+
+	// InboundCapacity returns the amount of funds which could be received
+	// through the active channels.
+	InboundCapacity() (decimal.Decimal, error)
+}
+
+// UnspentSyncStatus is the state of the synchronisation of the wallet unspent
+// outputs with the blockchain daemon.
+type UnspentSyncStatus struct {
+	// LastSyncAt is the time of the last successful sync in milliseconds,
+	// zero if sync hasn't been done yet.
+	LastSyncAt int64
+
+	// UnspentOutputs is the number of confirmed unspent outputs of the
+	// wallet, as they are counted by the daemon.
+	UnspentOutputs int
+
+	// Inconsistencies is the list of the found differences between the
+	// synchronised state and the state of the daemon.
+	Inconsistencies []string
+}
+
+// UnspentSyncer is an interface which is implemented by utxo based
+// blockchain connectors, which are able to resync and inspect state of the
+// wallet unspent outputs.
+type UnspentSyncer interface {
+	// SyncUnspent triggers the sync and waits for it to finish or for the
+	// context to be done. If force is true the state is synced from the
+	// scratch.
+	SyncUnspent(ctx context.Context, force bool) error
+
+	// UnspentSyncStatus returns the state of the sync.
+	UnspentSyncStatus() (*UnspentSyncStatus, error)
+}
+
+// UnspentOutput is the unspent output of the wallet.
+type UnspentOutput struct {
+	// TxID is the id of the transaction which created the output.
+	TxID string
+
+	// Vout is the index of the output in the transaction.
+	Vout uint32
+
+	// Address is the wallet address to which output is paid.
+	Address string
+
+	// Amount is the value of the output.
+	Amount decimal.Decimal
+
+	// Confirmations is the number of blocks which confirm the output.
+	Confirmations int64
+
+	// Locked denotes that output is locked by the payment which is being
+	// sent, and it couldn't be selected for the other payments.
+	Locked bool
+}
+
+// UnspentLister is an interface which is implemented by utxo based
+// blockchain connectors, which are able to list the wallet unspent
+// outputs.
+type UnspentLister interface {
+	// ListUnspent returns the unspent outputs of the wallet, including
+	// unconfirmed and locked ones.
+	ListUnspent() ([]*UnspentOutput, error)
+}
+
+// AddressOwnershipChecker is an interface which is implemented by
+// blockchain connectors which are able to check whether the address belongs
+// to the wallet of the daemon.
+type AddressOwnershipChecker interface {
+	// IsOurAddress returns true if address belongs to the wallet. Deposit
+	// addresses derived by the external address provider are ours, while
+	// watched addresses are not.
+	IsOurAddress(address string) (bool, error)
+}
+
+// MessageSigner is an interface which is implemented by blockchain
+// connectors which are able to sign messages with the keys of the wallet,
+// so that ownership of the address could be proven.
+type MessageSigner interface {
+	// SignMessage signs the message with the private key of the address,
+	// and returns the base64 encoded signature.
+	SignMessage(address, message string) (string, error)
+
+	// VerifyMessage returns true if the message has been signed with the
+	// private key of the address.
+	VerifyMessage(address, signature, message string) (bool, error)
+}
+
+// NodeMessageSigner is an interface which is implemented by lightning
+// connectors which are able to sign messages with the key of the node.
+type NodeMessageSigner interface {
+	// SignNodeMessage signs the message with the key of the node, and
+	// returns the zbase32 encoded signature.
+	SignNodeMessage(message string) (string, error)
+
+	// VerifyNodeMessage verifies the signature of the message, and returns
+	// the public key of the node which has signed it. Signature is valid
+	// only if signer is known to the node, i.e. it is the node itself or
+	// the node which is present in the channel graph.
+	VerifyNodeMessage(message, signature string) (string, bool, error)
+}
+
+// HashedInvoiceCreator is an interface which is implemented by lightning
+// connectors which are able to create invoices which commit to the
+// description by its hash, rather than carry the description itself.
+type HashedInvoiceCreator interface {
+	// CreateHashedInvoice is used to create lightning network invoice with
+	// the given SHA256 hash of the description.
+	CreateHashedInvoice(receipt, amount string, descriptionHash []byte) (
+		string, *zpay32.Invoice, error)
+}
+
+// TransactionInput is the output of the previous transaction which is
+// spent by the transaction.
+type TransactionInput struct {
+	// TxID is the id of the transaction which created the spent output.
+	TxID string
+
+	// Vout is the index of the spent output in its transaction.
+	Vout uint32
+}
+
+// TransactionOutput is the output created by the transaction.
+type TransactionOutput struct {
+	// Vout is the index of the output in the transaction.
+	Vout uint32
+
+	// Address is the address to which output is paid, it is empty if
+	// output script isn't the standard one, e.g. OP_RETURN output.
+	Address string
+
+	// Amount is the value of the output.
+	Amount decimal.Decimal
+}
+
+// Transaction is the detailed information about the blockchain
+// transaction.
+type Transaction struct {
+	// TxID is the id of the transaction.
+	TxID string
+
+	// BlockHash is the hash of the block in which transaction is
+	// included, it is empty if transaction is unconfirmed.
+	BlockHash string
+
+	// BlockTime is the time in milliseconds of the block in which
+	// transaction is included.
+	BlockTime int64
+
+	// Confirmations is the number of blocks which confirm the
+	// transaction, it is negative if transaction conflicts with the
+	// confirmed one.
+	Confirmations int64
+
+	// Fee is the fee paid by the transaction, it is known only for the
+	// transactions sent by the wallet.
+	Fee decimal.Decimal
+
+	Inputs  []*TransactionInput
+	Outputs []*TransactionOutput
+
+	// RawTx is the hex encoded serialized transaction.
+	RawTx string
+}
+
+// TransactionInspector is an interface which is implemented by blockchain
+// connectors which are able to return the details of the wallet
+// transactions, so that stuck payments could be investigated without
+// access to the daemon.
+type TransactionInspector interface {
+	// TransactionByHash returns the details of the wallet transaction.
+	TransactionByHash(txID string) (*Transaction, error)
+}
+
+// PaymentOutput is the recipient and the amount of the payment which is
+// sent along with the others in one transaction.
+type PaymentOutput struct {
+	// Address is the blockchain address of the recipient.
+	Address string
+
+	// Amount is the amount which recipient receives.
+	Amount string
+}
+
+// BatchSender is an interface which is implemented by blockchain
+// connectors which are able to combine several payments in one
+// transaction, so that the fee is paid only once.
+type BatchSender interface {
+	// SendPayments sends payments of the tenant to the given outputs in one
+	// transaction, and returns payment for every output in the same order.
+	SendPayments(tenant string, outputs []*PaymentOutput) ([]*Payment, error)
+}
+
+// FundsSweeper is an interface which is implemented by blockchain and
+// lightning network connectors which are able to move the whole wallet
+// balance to the external address, e.g. on the rotation of the cold storage
+// or on the decommissioning of the node.
+type FundsSweeper interface {
+	// SweepFunds sends all confirmed funds of the wallet to the address,
+	// fee is subtracted from the sent amount. Payment is saved before it
+	// is returned.
+	SweepFunds(address string) (*Payment, error)
+}
+
+// MaxConfTarget is the maximum number of blocks within which payment could
+// be requested to be confirmed, daemons are not tracking fees for longer
+// periods.
+const MaxConfTarget = 1008
+
+// FeeTargetEstimator is an interface which is implemented by blockchain
+// connectors which are able to estimate fee for the different confirmation
+// speeds, so that caller could choose between the faster and the cheaper
+// payment.
+type FeeTargetEstimator interface {
+	// EstimateFeeWithTarget is the same as EstimateFee, but fee is
+	// estimated for the transaction to be confirmed within the given
+	// number of blocks.
+	EstimateFeeWithTarget(amount string,
+		confTarget uint32) (decimal.Decimal, error)
+}
+
+// TenantSender is an interface which is implemented by blockchain
+// connectors which are gating the sending behaviour by the feature flags
+// of the tenant, i.e. API key on behalf of which payment is sent.
+type TenantSender interface {
+	// SendTenantPayment is the same as SendPayment, but feature flags are
+	// checked for the given tenant.
+	SendTenantPayment(tenant, address, amount, memo string) (*Payment, error)
+}
+
+// PaymentSimulator is an interface which is implemented by blockchain
+// connectors which are able to build the payment without sending it, so
+// that the payment could be checked before it is sent.
+type PaymentSimulator interface {
+	// SimulatePayment is the same as SendPayment, but inputs are only
+	// selected and not locked, and payment is neither saved nor
+	// broadcasted. Returned payment doesn't have the payment id.
+	SimulatePayment(address, amount, memo string) (*Payment, error)
+}
+
+// QuarantineLocker is an interface which is implemented by utxo based
+// blockchain connectors which are able to exclude the outputs of the
+// quarantined payment from the coin selection of the wallet, so that funds
+// under the compliance review couldn't be spent.
+type QuarantineLocker interface {
+	// LockPaymentOutputs locks the unspent outputs which have been
+	// received by the incoming payment, locked outputs are not selected
+	// as inputs of the sent payments.
+	LockPaymentOutputs(payment *Payment) error
+
+	// UnlockPaymentOutputs unlocks the outputs which have been locked by
+	// LockPaymentOutputs, so that they could be spent again.
+	UnlockPaymentOutputs(payment *Payment) error
+}
+
+// DepositAddressImporter is an interface which is implemented by blockchain
+// connectors which are able to track incoming payments on the deposit
+// addresses which have been derived outside of them.
+type DepositAddressImporter interface {
+	// ImportDepositAddress starts tracking of the incoming payments on the
+	// address. Address should be in the canonical form.
+	ImportDepositAddress(address string) error
+}
+
+// AddressProvider is the source of the deposit addresses, which is used
+// instead of the blockchain connectors, so that addresses could be derived
+// in the separated security domain.
+type AddressProvider interface {
+	// ProvideAddress returns new deposit address of the asset, tenant is
+	// the API key on behalf of which address is requested.
+	ProvideAddress(asset Asset, tenant string) (string, error)
+}
+
+// TimeLocker is an interface which is implemented by blockchain connectors
+// which are able to send payments locked by OP_CHECKLOCKTIMEVERIFY.
+type TimeLocker interface {
+	// SendTimeLockedPayment sends payment on the script address, which
+	// could be spent by the recipient key only after the lock time.
+	// Payment is saved along with its lock before it is returned.
+	SendTimeLockedPayment(recipientPubKey, amount string,
+		lockTime uint32) (*Payment, *TimeLock, error)
+
+	// BestHeight returns the height of the best block, which is used to
+	// determine whether time locks are expired.
+	BestHeight() (int64, error)
+}
+
+// PaymentCanceler is an interface which is implemented by blockchain
+// connectors which create outgoing payments before sending them, and
+// which are able to abort the payment which hasn't been sent yet.
+type PaymentCanceler interface {
+	// CancelPayment aborts the waiting payment, which transaction hasn't
+	// been broadcasted, releases the resources reserved for it, and marks
+	// the payment as failed.
+	CancelPayment(paymentID string) (*Payment, error)
+}
+
+// ChainState is the state of the blockchain daemon which is used by the
+// connector.
+type ChainState struct {
+	// Height is the height of the best block which has been processed by
+	// the daemon.
+	Height int64
+
+	// NetworkHeight is the height of the best block which is known by the
+	// daemon from the network.
+	NetworkHeight int64
+
+	// Synced denotes that daemon has caught up with the network.
+	Synced bool
+}
+
+// ChainReporter is an interface which is implemented by blockchain
+// connectors which are able to report the state of the daemon chain.
+type ChainReporter interface {
+	// ChainState returns current state of the daemon chain.
+	ChainState() (*ChainState, error)
+}
+
+// AssetParams are the parameters of the asset with which blockchain
+// connector is working.
+type AssetParams struct {
+	// Decimals is the number of digits after the decimal point in the
+	// amounts of the asset.
+	Decimals int32
+
+	// MinAmount is the minimum amount of the payment which could be sent.
+	MinAmount decimal.Decimal
+
+	// MaxAmount is the maximum amount of the payment which could be sent,
+	// zero if it isn't limited.
+	MaxAmount decimal.Decimal
+
+	// DustLimit is the amount of the output below which transaction isn't
+	// relayed by the network.
+	DustLimit decimal.Decimal
+
+	// MinConfirmations is the number of confirmations after which payment
+	// is considered completed.
+	MinConfirmations int64
+}
+
+// AssetReporter is an interface which is implemented by blockchain
+// connectors which are able to report the parameters of their asset.
+type AssetReporter interface {
+	// AssetParams returns the parameters of the asset.
+	AssetParams() *AssetParams
+}
+
+// QueueReporter is an interface which is implemented by subsystems which
+// are keeping work in the in-memory queues, so that their depths could be
+// inspected during the diagnostics.
+type QueueReporter interface {
+	// QueueDepths returns the number of elements in every queue by the
+	// name of the queue.
+	QueueDepths() map[string]int
 }
@@ -0,0 +1,27 @@
+package connectors
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Payee is the preset of the repeated payment destination, which is
+// identified by the name and could be used instead of the raw receipt.
+type Payee struct {
+	// Name is the unique name of the payee, e.g. "treasury-cold".
+	Name string
+
+	// Asset is an acronym of the crypto currency.
+	Asset Asset
+
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media PaymentMedia
+
+	// Receipt is a string which identifies the receiver of the
+	// payment. It is address in case of the blockchain media,
+	// and lightning network invoice in case lightning media.
+	Receipt string
+
+	// Amount is the default amount of the payment, zero if it is not set.
+	Amount decimal.Decimal
+}
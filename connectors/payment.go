@@ -89,6 +89,28 @@ var (
 	External PaymentSystem = "External"
 )
 
+// QuarantineState denotes the state of the compliance review of the
+// incoming payment.
+type QuarantineState string
+
+var (
+	// NotQuarantined means that payment hasn't been flagged.
+	NotQuarantined QuarantineState = ""
+
+	// Quarantined means that payment has been flagged and is under the
+	// review, its funds are not spendable and it isn't credited to the
+	// account.
+	Quarantined QuarantineState = "Quarantined"
+
+	// QuarantineReleased means that payment has passed the review and its
+	// funds are spendable.
+	QuarantineReleased QuarantineState = "Released"
+
+	// QuarantineReturned means that payment has been sent back after the
+	// review, it is never credited to the account.
+	QuarantineReturned QuarantineState = "Returned"
+)
+
 type Payment struct {
 	// PaymentID it is unique identificator of the payment generated inside
 	// the system.
@@ -136,9 +158,61 @@ type Payment struct {
 	// payment identificator because of the reason that it is not unique.
 	MediaID string
 
+	// Memo is the message which is attached to the payment in the media,
+	// e.g. OP_RETURN output for bitcoin-like blockchains, and transaction data
+	// for ethereum. Some exchanges require memo to be present on deposit,
+	// in order to identify the user.
+	Memo string
+
 	// Detail stores all additional information which is needed for this type
 	// and status of payment.
 	Detail Serializable
+
+	// Flags is the list of feature flags which were enabled and affected
+	// the payment when it was created, it is used for later analysis.
+	Flags []string
+
+	// AccountID is the identifier of the account, e.g. internal product,
+	// to which payment belongs. Outgoing payment is bound to the account
+	// when it is sent, incoming one belongs to the account of its receipt.
+	// Not to be confused with Account, which is specific to the connector.
+	AccountID string
+
+	// Metadata is the set of the labels, e.g. order id or customer id,
+	// which are attached to the payment by the clients. Incoming payment
+	// receives the metadata of its receipt.
+	Metadata map[string]string
+
+	// RefundOf is the id of the incoming payment which is refunded by this
+	// outgoing payment, it is recorded for the audit of the refunds.
+	RefundOf string
+
+	// Sequence is the global sequence number of the payment, it is
+	// assigned by the store on every change of the payment, and is
+	// strictly increasing across all payments, so that clients could
+	// follow the changes without relying on the time.
+	Sequence uint64
+
+	// Quarantine is the state of the compliance review of the incoming
+	// payment, it is kept when payment is later updated by the connector.
+	Quarantine QuarantineState
+
+	// QuarantineReason is the reason with which payment has been flagged.
+	QuarantineReason string
+
+	// FiatValue is the value of the payment amount in the fiat currency
+	// at the moment when payment has been completed. It is recorded only
+	// once, so that it isn't changed by the later price movements.
+	FiatValue decimal.Decimal
+
+	// FiatCurrency is the currency of the fiat value, empty if payment
+	// hasn't been valued.
+	FiatCurrency string
+
+	// FailureReason is the reason of the failure of the payment, it is
+	// set by the connector along with the failed status and is recorded
+	// in the timeline of the payment, rather than stored with it.
+	FailureReason string
 }
 
 // GenPaymentID generates unique string based on the tx id and receive
@@ -170,6 +244,63 @@ func (p *Payment) GenPaymentID() (string, error) {
 
 }
 
+// MarkInternalPayments derives the system of the payments by the kind of
+// their media transactions. Transaction which both sends and receives funds
+// on the same receipt moves them inside our wallet, e.g. sweep,
+// consolidation or rebalance, that is why both its payments are marked as
+// internal. System of other payments is left untouched, because it might
+// be known only to the connector. Returns the payments which system has
+// been changed.
+func MarkInternalPayments(payments []*Payment) []*Payment {
+	directions := make(map[string]map[PaymentDirection]bool)
+	for _, payment := range payments {
+		key := payment.MediaID + ":" + payment.Receipt
+		if directions[key] == nil {
+			directions[key] = make(map[PaymentDirection]bool)
+		}
+		directions[key][payment.Direction] = true
+	}
+
+	var changed []*Payment
+	for _, payment := range payments {
+		if payment.System == Internal {
+			continue
+		}
+
+		key := payment.MediaID + ":" + payment.Receipt
+		if directions[key][Incoming] && directions[key][Outgoing] {
+			payment.System = Internal
+			changed = append(changed, payment)
+		}
+	}
+
+	return changed
+}
+
+// MergeLabels returns the new metadata with the labels merged into it,
+// label with empty value removes the key. Nil is returned if nothing is
+// left.
+func MergeLabels(metadata, labels map[string]string) map[string]string {
+	merged := make(map[string]string, len(metadata)+len(labels))
+	for key, value := range metadata {
+		merged[key] = value
+	}
+
+	for key, value := range labels {
+		if value == "" {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+
+	return merged
+}
+
 // BlockchainPendingDetails is the information about pending blockchain
 // transaction.
 type BlockchainPendingDetails struct {
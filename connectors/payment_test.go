@@ -0,0 +1,81 @@
+package connectors
+
+import (
+	"testing"
+)
+
+func TestMarkInternalPayments(t *testing.T) {
+	sweepOut := &Payment{
+		Direction: Outgoing,
+		System:    External,
+		Receipt:   "own",
+		MediaID:   "sweep",
+	}
+	sweepIn := &Payment{
+		Direction: Incoming,
+		System:    External,
+		Receipt:   "own",
+		MediaID:   "sweep",
+	}
+	withdrawal := &Payment{
+		Direction: Outgoing,
+		System:    External,
+		Receipt:   "user",
+		MediaID:   "withdrawal",
+	}
+	change := &Payment{
+		Direction: Incoming,
+		System:    Internal,
+		Receipt:   "change",
+		MediaID:   "withdrawal",
+	}
+
+	changed := MarkInternalPayments([]*Payment{
+		sweepOut, sweepIn, withdrawal, change,
+	})
+
+	if len(changed) != 2 {
+		t.Fatalf("wrong number of changed payments, got(%v), want(2)",
+			len(changed))
+	}
+
+	if sweepOut.System != Internal || sweepIn.System != Internal {
+		t.Fatal("payments of the sweep should be internal")
+	}
+
+	if withdrawal.System != External {
+		t.Fatal("withdrawal should be external")
+	}
+
+	// System set by the connector is kept.
+	if change.System != Internal {
+		t.Fatal("change should be internal")
+	}
+}
+
+func TestMergeLabels(t *testing.T) {
+	metadata := map[string]string{
+		"order_id":    "1001",
+		"customer_id": "42",
+	}
+
+	merged := MergeLabels(metadata, map[string]string{
+		"order_id":    "1002",
+		"customer_id": "",
+		"channel":     "web",
+	})
+
+	if len(merged) != 2 || merged["order_id"] != "1002" ||
+		merged["channel"] != "web" {
+		t.Fatalf("wrong metadata: %v", merged)
+	}
+
+	// Original metadata shouldn't be modified.
+	if metadata["order_id"] != "1001" || metadata["customer_id"] != "42" {
+		t.Fatalf("original metadata is modified: %v", metadata)
+	}
+
+	if merged := MergeLabels(nil, map[string]string{"x": ""}); merged != nil {
+		t.Fatalf("empty metadata should be nil, got: %v", merged)
+	}
+}
@@ -0,0 +1,213 @@
+package connectors
+
+import (
+	"crypto/sha256"
+	"strings"
+
+	"github.com/bitlum/connector/connectors/rpc/ethereum"
+	cashAddr "github.com/schancel/cashaddr-converter/address"
+	"github.com/shopspring/decimal"
+)
+
+// ReceiptStatus denotes whether receipt has been paid.
+type ReceiptStatus string
+
+const (
+	// ReceiptPaid means that completed incoming payment has been received
+	// on the receipt.
+	ReceiptPaid ReceiptStatus = "paid"
+
+	// ReceiptUnpaid means that receipt hasn't been paid yet, and it still
+	// could be paid.
+	ReceiptUnpaid ReceiptStatus = "unpaid"
+
+	// ReceiptExpired means that receipt hasn't been paid before its
+	// expiration, e.g. lightning network invoice is expired.
+	ReceiptExpired ReceiptStatus = "expired"
+)
+
+// Receipt is the blockchain address or lightning network invoice which
+// has been created in order to receive money.
+type Receipt struct {
+	// ReceiptID is the identifier of the receipt, which is derived from the
+	// asset, media and receipt itself.
+	ReceiptID string
+
+	// Receipt is the blockchain address or lightning network invoice.
+	Receipt string
+
+	// Asset is the asset of the receipt.
+	Asset Asset
+
+	// Media is the media of the receipt.
+	Media PaymentMedia
+
+	// Amount is the requested amount, zero if it isn't specified.
+	Amount decimal.Decimal
+
+	// Description is the description of the receipt.
+	Description string
+
+	// Reference is the external order reference, which lightning network
+	// invoice commits to by the description hash, empty if invoice carries
+	// the description.
+	Reference string
+
+	// CreatedAt is the time of the receipt creation in milliseconds.
+	CreatedAt int64
+
+	// ExpiresAt is the time in milliseconds after which receipt couldn't be
+	// paid, zero if receipt doesn't expire, e.g. blockchain address which
+	// was created without expiry.
+	ExpiresAt int64
+
+	// Status is the status of the receipt, it is derived from the incoming
+	// payments by the store.
+	Status ReceiptStatus
+
+	// Tenant is the id of the API key on behalf of which receipt has been
+	// created, empty if API keys are not used. It is used to roll out the
+	// feature flags which affect incoming payments.
+	Tenant string
+
+	// AccountID is the identifier of the account to which receipt and its
+	// incoming payments belong.
+	AccountID string
+
+	// Metadata is the set of the labels which are attached to the receipt
+	// and its incoming payments.
+	Metadata map[string]string
+
+	// Replaces is the identifier of the receipt which has been replaced by
+	// this one, e.g. expired lightning network invoice, empty if receipt
+	// has been created on request.
+	Replaces string
+
+	// ReplacedBy is the identifier of the receipt which has replaced this
+	// one, empty if receipt hasn't been replaced.
+	ReplacedBy string
+
+	// Regenerations is the number of the replacements which have been made
+	// from the originally requested receipt up to this one.
+	Regenerations int
+
+	// RefundAddress is the blockchain address supplied by the payer on
+	// which refunds of the receipt payments are sent, empty if it hasn't
+	// been attached.
+	RefundAddress string
+}
+
+// DepositAddress is the blockchain address which has been generated for
+// the integration which manages its own invoicing. Unlike the receipt it
+// doesn't carry the requested amount and isn't listed, it only binds
+// incoming payments on the address to the account and tenant.
+type DepositAddress struct {
+	// Address is the blockchain address in the canonical form.
+	Address string
+
+	// Asset is the asset of the address.
+	Asset Asset
+
+	// CreatedAt is the time of the address creation in milliseconds.
+	CreatedAt int64
+
+	// Tenant is the id of the API key on behalf of which address has been
+	// created, empty if API keys are not used.
+	Tenant string
+
+	// AccountID is the identifier of the account to which incoming
+	// payments on the address belong.
+	AccountID string
+}
+
+// ReceiptsQuery is the filter and page of the receipts which should be
+// returned by the store. Empty filter fields are matching any value.
+type ReceiptsQuery struct {
+	ReceiptID string
+
+	Asset  Asset
+	Media  PaymentMedia
+	Status ReceiptStatus
+
+	// CreatedFrom and CreatedTo are the bounds of the creation time in
+	// milliseconds, inclusive, zero means that bound isn't set.
+	CreatedFrom int64
+	CreatedTo   int64
+
+	// Unreplaced denotes that only receipts which haven't been replaced
+	// are returned.
+	Unreplaced bool
+
+	// Offset is the number of matching receipts which are skipped.
+	Offset int
+
+	// Limit is the maximum number of returned receipts, zero means that
+	// all matching receipts are returned.
+	Limit int
+}
+
+// GenReceiptID generates identifier of the receipt, which is unique for
+// the asset and media.
+func (r *Receipt) GenReceiptID() string {
+	return GeneratePaymentID(string(r.Asset), string(r.Media), r.Receipt)
+}
+
+// DescriptionHash returns the SHA256 hash of the reference which is placed
+// in the lightning network invoice, nil if reference isn't specified.
+func (r *Receipt) DescriptionHash() []byte {
+	if r.Reference == "" {
+		return nil
+	}
+
+	hash := sha256.Sum256([]byte(r.Reference))
+	return hash[:]
+}
+
+// NormalizeReceipt returns the canonical form of the receipt, in which it is
+// stored in the payments: lowercase for lightning network invoices and
+// bech32 addresses, EIP-55 checksum encoding for ethereum addresses, and
+// CashAddr with prefix for bitcoin cash. Normalization doesn't depend on the
+// network, so that it could be used when connectors are not available, and
+// receipt which couldn't be decoded is returned as is.
+func NormalizeReceipt(asset Asset, media PaymentMedia,
+	receipt string) string {
+
+	if media == Lightning {
+		return strings.ToLower(receipt)
+	}
+
+	switch asset {
+	case ETH:
+		normalizedReceipt, err := ethereum.NormalizeAddress(receipt)
+		if err != nil {
+			return receipt
+		}
+		return normalizedReceipt
+
+	case BCH:
+		address, err := cashAddr.NewFromString(receipt)
+		if err != nil {
+			return receipt
+		}
+
+		cashAddress, err := address.CashAddress()
+		if err != nil {
+			return receipt
+		}
+
+		normalizedReceipt, err := cashAddress.Encode()
+		if err != nil {
+			return receipt
+		}
+		return normalizedReceipt
+
+	default:
+		// Base58 addresses are case sensitive, so the receipt in the upper
+		// case might only be bech32 address, which canonical form is the
+		// lower case.
+		if strings.ToUpper(receipt) == receipt {
+			return strings.ToLower(receipt)
+		}
+		return receipt
+	}
+}
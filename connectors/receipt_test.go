@@ -0,0 +1,59 @@
+package connectors
+
+import (
+	"testing"
+)
+
+func TestNormalizeReceipt(t *testing.T) {
+	tests := []struct {
+		asset   Asset
+		media   PaymentMedia
+		receipt string
+		want    string
+	}{
+		{
+			asset:   BTC,
+			media:   Blockchain,
+			receipt: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+			want:    "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			asset:   BTC,
+			media:   Blockchain,
+			receipt: "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu",
+			want:    "1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu",
+		},
+		{
+			asset:   BCH,
+			media:   Blockchain,
+			receipt: "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+			want:    "bitcoincash:qpm47l0kukuzjnk2vsp70256s9pd99qs5u2e7gd5f7",
+		},
+		{
+			asset:   ETH,
+			media:   Blockchain,
+			receipt: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			want:    "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		},
+		{
+			asset:   BTC,
+			media:   Lightning,
+			receipt: "LNBC1PVJLUEZPP5",
+			want:    "lnbc1pvjluezpp5",
+		},
+		{
+			asset:   ETH,
+			media:   Blockchain,
+			receipt: "invalid",
+			want:    "invalid",
+		},
+	}
+
+	for i, test := range tests {
+		receipt := NormalizeReceipt(test.asset, test.media, test.receipt)
+		if receipt != test.want {
+			t.Fatalf("(%v) wrong receipt, got(%v), want(%v)", i, receipt,
+				test.want)
+		}
+	}
+}
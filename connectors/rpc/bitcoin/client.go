@@ -1,6 +1,9 @@
 package bitcoin
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/bitlum/connector/common"
 	"github.com/bitlum/connector/connectors"
@@ -8,11 +11,26 @@ import (
 	"github.com/bitlum/go-bitcoind-rpc/btcjson"
 	"github.com/bitlum/go-bitcoind-rpc/rpcclient"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btclog"
 	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultPoolSize is the default number of rpc clients which are used
+	// to talk with daemon.
+	defaultPoolSize = 4
+
+	// defaultTimeout is the default maximum time we wait for the response
+	// of the daemon on read calls.
+	defaultTimeout = time.Minute
 )
 
 type ClientConfig struct {
@@ -23,11 +41,55 @@ type ClientConfig struct {
 	RPCPort  int
 	User     string
 	Password string
+
+	// PoolSize is the number of rpc clients which are used to talk with
+	// daemon. Rpc client in http post mode sends requests one by one, so
+	// with the single client sync loops stall behind slow calls. If zero
+	// the default pool size is used.
+	PoolSize int
+
+	// Timeout is the maximum time we wait for the response of the daemon
+	// on read calls. If zero the default timeout is used.
+	Timeout time.Duration
+
+	// CookiePath is the path to the .cookie file of the daemon. If it is
+	// specified user and password are taken from the cookie file, and
+	// reloaded when daemon regenerates it.
+	CookiePath string
+
+	// PasswordFile is the path to the file with the rpc password. If it is
+	// specified password is taken from the file instead of the config, and
+	// reloaded when file is changed, so that password could be rotated
+	// without restart.
+	PasswordFile string
+
+	// Proxy is the address of the SOCKS5 proxy through which connection
+	// to the daemon is established, if daemon is the .onion host.
+	Proxy string
 }
 
 // Client bitcoind implementation of rpc.Client interface.
 type Client struct {
-	Daemon     *rpcclient.Client
+	// nextDaemon is used to select client from the pool in round robin
+	// fashion, should be used atomically.
+	nextDaemon uint32
+
+	// credentialsCheckedAt is the unix time in nanoseconds of the last
+	// check of credentials file modification, should be used atomically.
+	credentialsCheckedAt int64
+
+	cfg ClientConfig
+
+	// credentialsModTime is the modification time of the credentials file
+	// from which current pool has been created.
+	credentialsModTime time.Time
+
+	// pool is the current pool of rpc clients, previous pools are shut
+	// down when calls which are using them are finished.
+	pool    *daemonPool
+	poolMtx sync.RWMutex
+
+	timeout    time.Duration
 	Logger     common.NamedLogger
 	daemonName string
 }
@@ -36,44 +98,188 @@ type Client struct {
 var _ rpc.Client = (*Client)(nil)
 
 func NewClient(cfg ClientConfig) (*Client, error) {
-	host := fmt.Sprintf("%v:%v", cfg.RPCHost, cfg.RPCPort)
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = defaultPoolSize
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	c := &Client{
+		cfg:        cfg,
+		timeout:    cfg.Timeout,
+		daemonName: cfg.Name,
+		Logger: common.NamedLogger{
+			Logger: cfg.Logger,
+			Name:   string(cfg.Asset),
+		},
+	}
+
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// connect reads the credentials and creates the pool of rpc clients.
+func (c *Client) connect() error {
+	creds, err := readCredentials(c.cfg)
+	if err != nil {
+		return errors.Errorf("unable to read credentials: %v", err)
+	}
+
+	// Only Tor hidden services are reached through the proxy, rpc client
+	// otherwise sends all requests through it.
+	var proxyAddress string
+	if common.IsOnionHost(c.cfg.RPCHost) {
+		proxyAddress = c.cfg.Proxy
+	}
 
 	rpcCfg := &rpcclient.ConnConfig{
-		Host: host,
-		User: cfg.User,
-		Pass: cfg.Password,
+		Host: fmt.Sprintf("%v:%v", c.cfg.RPCHost, c.cfg.RPCPort),
+		User: creds.user,
+		Pass: creds.password,
+
+		Proxy: proxyAddress,
 		// TODO(andrew.shvv) switch on production
 		DisableTLS:   true,
 		HTTPPostMode: true,
 	}
 
-	// Create RPC client in order to talk with cryptocurrency Daemon.
-	rpcClient, err := rpcclient.New(rpcCfg, nil)
+	// Create RPC clients in order to talk with cryptocurrency Daemon.
+	daemons := make([]*rpcclient.Client, c.cfg.PoolSize)
+	for i := range daemons {
+		rpcClient, err := rpcclient.New(rpcCfg, nil)
+		if err != nil {
+			return errors.Errorf("unable to create RPC client: %v", err)
+		}
+
+		daemons[i] = rpcClient
+	}
+
+	c.poolMtx.Lock()
+	oldPool := c.pool
+	c.pool = &daemonPool{daemons: daemons}
+	c.credentialsModTime = creds.modTime
+	c.poolMtx.Unlock()
+
+	// Calls which are in flight are not interrupted, previous pool is
+	// shut down after they are finished.
+	if oldPool != nil {
+		go oldPool.drain()
+	}
+
+	return nil
+}
+
+// daemonPool is the pool of rpc clients created with the same credentials,
+// which keeps track of the calls using it.
+type daemonPool struct {
+	daemons []*rpcclient.Client
+	calls   sync.WaitGroup
+}
+
+// drain waits for the calls using the pool to finish and shuts down its
+// rpc clients.
+func (p *daemonPool) drain() {
+	p.calls.Wait()
+
+	for _, daemon := range p.daemons {
+		daemon.Shutdown()
+	}
+}
+
+// AcquireDaemon returns the next rpc client from the pool, and the release
+// function which should be called once the client is no longer used, so
+// that pool could be shut down after the credentials reload.
+func (c *Client) AcquireDaemon() (*rpcclient.Client, func()) {
+	c.reloadCredentials()
+
+	c.poolMtx.RLock()
+	defer c.poolMtx.RUnlock()
+
+	pool := c.pool
+	pool.calls.Add(1)
+
+	i := atomic.AddUint32(&c.nextDaemon, 1)
+	return pool.daemons[i%uint32(len(pool.daemons))], pool.calls.Done
+}
+
+// reloadCredentials recreates the pool of rpc clients if the credentials
+// file has been changed. Modification of the file is checked not often
+// than once in the check interval.
+func (c *Client) reloadCredentials() {
+	path := credentialsPath(c.cfg)
+	if path == "" {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	checkedAt := atomic.LoadInt64(&c.credentialsCheckedAt)
+	if now-checkedAt < int64(credentialsCheckInterval) ||
+		!atomic.CompareAndSwapInt64(&c.credentialsCheckedAt, checkedAt, now) {
+		return
+	}
+
+	c.poolMtx.RLock()
+	modTime := c.credentialsModTime
+	c.poolMtx.RUnlock()
+
+	info, err := os.Stat(path)
 	if err != nil {
-		return nil, errors.Errorf("unable to create RPC client: %v", err)
+		c.Logger.Errorf("unable to check credentials file: %v", err)
+		return
 	}
 
-	return &Client{
-		Daemon:     rpcClient,
-		daemonName: cfg.Name,
-		Logger: common.NamedLogger{
-			Logger: cfg.Logger,
-			Name:   string(cfg.Asset),
-		},
-	}, nil
+	if info.ModTime().Equal(modTime) {
+		return
+	}
+
+	c.Logger.Infof("Credentials file(%v) has been changed, reconnecting",
+		path)
+
+	if err := c.connect(); err != nil {
+		c.Logger.Errorf("unable to reload credentials: %v", err)
+	}
+}
+
+// withTimeout executes daemon call and returns error if it wasn't finished
+// in time. Rpc client doesn't support cancellation, so the call itself
+// continues in background, that is why only read calls should be wrapped,
+// otherwise we could report failure of the request which was actually
+// executed by the daemon.
+func (c *Client) withTimeout(call func() error) error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- call()
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(c.timeout):
+		return errors.Errorf("daemon call timed out after %v", c.timeout)
+	}
 }
 
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) GetBlockChainInfo() (*rpc.BlockChainInfoResp, error) {
-	daemonResp, err := c.Daemon.GetBlockChainInfo()
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	daemonResp, err := daemon.GetBlockChainInfo()
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
 	}
 
 	resp := &rpc.BlockChainInfoResp{
-		Chain: daemonResp.Chain,
+		Chain:   daemonResp.Chain,
+		Blocks:  int64(daemonResp.Blocks),
+		Headers: int64(daemonResp.Headers),
 	}
 
 	c.Logger.Tracef("method: %v, response: %v", common.GetFunctionName(),
@@ -87,7 +293,10 @@ func (c *Client) GetBlockChainInfo() (*rpc.BlockChainInfoResp, error) {
 func (c *Client) GetBlockVerboseByHash(blockHash *chainhash.Hash) (
 	*rpc.BlockVerboseResp, error) {
 
-	daemonResp, err := c.Daemon.GetBlockVerbose(blockHash)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	daemonResp, err := daemon.GetBlockVerbose(blockHash)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
@@ -111,7 +320,10 @@ func (c *Client) GetBlockVerboseByHash(blockHash *chainhash.Hash) (
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) GetBestBlockHash() (*chainhash.Hash, error) {
-	resp, err := c.Daemon.GetBestBlockHash()
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	resp, err := daemon.GetBestBlockHash()
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
@@ -123,10 +335,46 @@ func (c *Client) GetBestBlockHash() (*chainhash.Hash, error) {
 	return resp, err
 }
 
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	params, err := marshalParams(height)
+	if err != nil {
+		return nil, err
+	}
+
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	rawResp, err := daemon.RawRequest("getblockhash", params)
+	if err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return nil, err
+	}
+
+	var hash string
+	if err := json.Unmarshal(rawResp, &hash); err != nil {
+		return nil, errors.Errorf("unable to decode response: %v", err)
+	}
+
+	resp, err := chainhash.NewHashFromStr(hash)
+	if err != nil {
+		return nil, errors.Errorf("unable to decode hash: %v", err)
+	}
+
+	c.Logger.Tracef("method: %v, response: %v", common.GetFunctionName(),
+		spew.Sdump(resp))
+
+	return resp, nil
+}
+
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) UnlockUnspent() error {
-	err := c.Daemon.LockUnspent(true, nil)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	err := daemon.LockUnspent(true, nil)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return err
@@ -149,7 +397,36 @@ func (c *Client) LockUnspent(input rpc.UnspentInput) error {
 
 	outputs := []*wire.OutPoint{{Hash: *hash, Index: input.Vout}}
 
-	if err := c.Daemon.LockUnspent(false, outputs); err != nil {
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	if err := daemon.LockUnspent(false, outputs); err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(),
+			err)
+		return err
+	}
+
+	c.Logger.Tracef("method: %v, response: %v", common.GetFunctionName(),
+		"empty")
+
+	return nil
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) UnlockUnspentInput(input rpc.UnspentInput) error {
+	hash, err := chainhash.NewHashFromStr(input.TxID)
+	if err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return err
+	}
+
+	outputs := []*wire.OutPoint{{Hash: *hash, Index: input.Vout}}
+
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	if err := daemon.LockUnspent(true, outputs); err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(),
 			err)
 		return err
@@ -161,12 +438,41 @@ func (c *Client) LockUnspent(input rpc.UnspentInput) error {
 	return nil
 }
 
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) ListLockUnspent() ([]*wire.OutPoint, error) {
+	var outputs []*wire.OutPoint
+	err := c.withTimeout(func() (err error) {
+		daemon, release := c.AcquireDaemon()
+		defer release()
+
+		outputs, err = daemon.ListLockUnspent()
+		return err
+	})
+	if err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return nil, err
+	}
+
+	c.Logger.Tracef("method: %v, response: %v", common.GetFunctionName(),
+		spew.Sdump(outputs))
+
+	return outputs, nil
+}
+
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) ListUnspentMinMax(minConf, maxConf int) ([]rpc.UnspentInput,
 	error) {
 
-	unspent, err := c.Daemon.ListUnspentMinMax(minConf, maxConf)
+	var unspent []btcjson.ListUnspentResult
+	err := c.withTimeout(func() (err error) {
+		daemon, release := c.AcquireDaemon()
+		defer release()
+
+		unspent, err = daemon.ListUnspentMinMax(minConf, maxConf)
+		return err
+	})
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
@@ -193,7 +499,10 @@ func (c *Client) ListUnspentMinMax(minConf, maxConf int) ([]rpc.UnspentInput,
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) GetAddressesByLabel(label string) ([]btcutil.Address, error) {
-	addresses, err := c.Daemon.GetAddressesByAccount(label)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	addresses, err := daemon.GetAddressesByAccount(label)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
@@ -208,7 +517,10 @@ func (c *Client) GetAddressesByLabel(label string) ([]btcutil.Address, error) {
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) GetNewAddress(label string) (btcutil.Address, error) {
-	address, err := c.Daemon.GetNewAddress(label)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	address, err := daemon.GetNewAddress(label)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
@@ -220,10 +532,147 @@ func (c *Client) GetNewAddress(label string) (btcutil.Address, error) {
 	return address, nil
 }
 
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) ImportAddress(address btcutil.Address, label string,
+	rescan bool) error {
+	params, err := marshalParams(address.EncodeAddress(), label, rescan)
+	if err != nil {
+		return err
+	}
+
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	if _, err := daemon.RawRequest("importaddress", params); err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return err
+	}
+
+	return nil
+}
+
+// addressInfoResult is the part of the getaddressinfo and validateaddress
+// responses which describes the relation of the address to the wallet.
+type addressInfoResult struct {
+	IsMine      bool   `json:"ismine"`
+	IsWatchOnly bool   `json:"iswatchonly"`
+	Label       string `json:"label"`
+
+	// Account is returned instead of the label by the daemons which are
+	// not supporting labels yet.
+	Account string `json:"account"`
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) GetAddressInfo(address btcutil.Address) (
+	*rpc.AddressInfoResp, error) {
+
+	params, err := marshalParams(address.EncodeAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	// Older daemons are returning the wallet information of the address
+	// only by validateaddress, newer ones only by getaddressinfo.
+	rawResp, err := daemon.RawRequest("getaddressinfo", params)
+	if rpcErr, ok := err.(*btcjson.RPCError); ok &&
+		rpcErr.Code == btcjson.ErrRPCMethodNotFound.Code {
+		rawResp, err = daemon.RawRequest("validateaddress", params)
+	}
+	if err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return nil, err
+	}
+
+	var info addressInfoResult
+	if err := json.Unmarshal(rawResp, &info); err != nil {
+		return nil, errors.Errorf("unable to decode response: %v", err)
+	}
+
+	resp := &rpc.AddressInfoResp{
+		IsMine:      info.IsMine,
+		IsWatchOnly: info.IsWatchOnly,
+		Label:       info.Label,
+	}
+	if resp.Label == "" {
+		resp.Label = info.Account
+	}
+
+	c.Logger.Tracef("method: %v, response: %v", common.GetFunctionName(),
+		spew.Sdump(resp))
+
+	return resp, nil
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) SignMessage(address btcutil.Address,
+	message string) (string, error) {
+
+	params, err := marshalParams(address.EncodeAddress(), message)
+	if err != nil {
+		return "", err
+	}
+
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	rawResp, err := daemon.RawRequest("signmessage", params)
+	if err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return "", err
+	}
+
+	var signature string
+	if err := json.Unmarshal(rawResp, &signature); err != nil {
+		return "", errors.Errorf("unable to decode response: %v", err)
+	}
+
+	return signature, nil
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) VerifyMessage(address btcutil.Address, signature,
+	message string) (bool, error) {
+
+	params, err := marshalParams(address.EncodeAddress(), signature, message)
+	if err != nil {
+		return false, err
+	}
+
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	rawResp, err := daemon.RawRequest("verifymessage", params)
+	if err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return false, err
+	}
+
+	var valid bool
+	if err := json.Unmarshal(rawResp, &valid); err != nil {
+		return false, errors.Errorf("unable to decode response: %v", err)
+	}
+
+	c.Logger.Tracef("method: %v, response: %v", common.GetFunctionName(),
+		valid)
+
+	return valid, nil
+}
+
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) GetNewRawChangeAddress(label string) (btcutil.Address, error) {
-	address, err := c.Daemon.GetRawChangeAddress(label)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	address, err := daemon.GetRawChangeAddress(label)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
@@ -238,7 +687,10 @@ func (c *Client) GetNewRawChangeAddress(label string) (btcutil.Address, error) {
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) SignRawTransaction(tx *wire.MsgTx) (*wire.MsgTx, error) {
-	signedTx, isSigned, err := c.Daemon.SignRawTransaction(tx)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	signedTx, isSigned, err := daemon.SignRawTransaction(tx)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
@@ -270,7 +722,10 @@ func (c *Client) CreateRawTransaction(inputs []rpc.UnspentInput,
 		}
 	}
 
-	tx, err := c.Daemon.CreateRawTransaction(txInputs, outputs, &lockTime)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	tx, err := daemon.CreateRawTransaction(txInputs, outputs, &lockTime)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
@@ -285,7 +740,10 @@ func (c *Client) CreateRawTransaction(inputs []rpc.UnspentInput,
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) SendRawTransaction(tx *wire.MsgTx) error {
-	_, err := c.Daemon.SendRawTransaction(tx, false)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	_, err := daemon.SendRawTransaction(tx, false)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return err
@@ -302,7 +760,14 @@ func (c *Client) SendRawTransaction(tx *wire.MsgTx) error {
 func (c *Client) GetBalanceByLabel(label string,
 	minConfirms int) (btcutil.Amount, error) {
 
-	amount, err := c.Daemon.GetBalanceMinConf(label, minConfirms)
+	var amount btcutil.Amount
+	err := c.withTimeout(func() (err error) {
+		daemon, release := c.AcquireDaemon()
+		defer release()
+
+		amount, err = daemon.GetBalanceMinConf(label, minConfirms)
+		return err
+	})
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return 0, err
@@ -319,7 +784,14 @@ func (c *Client) GetBalanceByLabel(label string,
 func (c *Client) GetTransaction(txHash *chainhash.Hash) (
 	*rpc.Transaction, error) {
 
-	tx, err := c.Daemon.GetTransaction(txHash)
+	var tx *btcjson.GetTransactionResult
+	err := c.withTimeout(func() (err error) {
+		daemon, release := c.AcquireDaemon()
+		defer release()
+
+		tx, err = daemon.GetTransaction(txHash)
+		return err
+	})
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return nil, err
@@ -344,6 +816,9 @@ func (c *Client) GetTransaction(txHash *chainhash.Hash) (
 		Confirmations: tx.Confirmations,
 		TxID:          tx.TxID,
 		Details:       details,
+		BlockHash:     tx.BlockHash,
+		BlockTime:     tx.BlockTime,
+		Hex:           tx.Hex,
 	}
 
 	c.Logger.Tracef("method: %v, response: %v", common.GetFunctionName(),
@@ -354,9 +829,11 @@ func (c *Client) GetTransaction(txHash *chainhash.Hash) (
 
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
-func (c *Client) EstimateFee() (float64, error) {
-	confTarget := uint32(2)
-	res, err := c.Daemon.EstimateSmartFeeWithMode(confTarget,
+func (c *Client) EstimateFee(confTarget uint32) (float64, error) {
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	res, err := daemon.EstimateSmartFeeWithMode(confTarget,
 		btcjson.ConservativeEstimateMode)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
@@ -404,21 +881,228 @@ func (c *Client) DaemonName() string {
 // the interface description.
 func (c *Client) SendToAddress(address btcutil.Address,
 	amount btcutil.Amount) (*chainhash.Hash, error) {
-	return c.Daemon.SendToAddress(address, amount)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	return daemon.SendToAddress(address, amount)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) SendToAddressWithData(address btcutil.Address,
+	amount btcutil.Amount, data []byte) (*chainhash.Hash, error) {
+	return c.sendFundedTransaction(address, amount, data, false)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) SendToAddressReplaceable(address btcutil.Address,
+	amount btcutil.Amount, data []byte) (*chainhash.Hash, error) {
+	return c.sendFundedTransaction(address, amount, data, true)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) SendToAddresses(amounts map[btcutil.Address]btcutil.Amount,
+	replaceable bool) (*chainhash.Hash, error) {
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for address, amount := range amounts {
+		pkScript, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(),
+				err)
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(int64(amount), pkScript))
+	}
+
+	return c.sendFundedTx(tx, replaceable)
+}
+
+// sendFundedTransaction creates transaction which pays to the address,
+// funds it by the daemon wallet, and sends it in the network. If data is
+// not empty, OP_RETURN output with it is attached.
+func (c *Client) sendFundedTransaction(address btcutil.Address,
+	amount btcutil.Amount, data []byte, replaceable bool) (*chainhash.Hash,
+	error) {
+
+	pkScript, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return nil, err
+	}
+
+	// Create transaction without inputs and change output, and let the
+	// daemon wallet to fund it, as it does in case of sendtoaddress.
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxOut(wire.NewTxOut(int64(amount), pkScript))
+
+	if len(data) != 0 {
+		nullDataScript, err := txscript.NullDataScript(data)
+		if err != nil {
+			c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(),
+				err)
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(0, nullDataScript))
+	}
+
+	return c.sendFundedTx(tx, replaceable)
+}
+
+// sendFundedTx funds the transaction without inputs by the daemon wallet,
+// signs it and sends it in the network.
+func (c *Client) sendFundedTx(tx *wire.MsgTx, replaceable bool) (
+	*chainhash.Hash, error) {
+
+	fundedTx, err := c.fundRawTransaction(tx, replaceable)
+	if err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return nil, err
+	}
+
+	signedTx, err := c.SignRawTransaction(fundedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.SendRawTransaction(signedTx); err != nil {
+		return nil, err
+	}
+
+	txHash := signedTx.TxHash()
+
+	c.Logger.Tracef("method: %v, response: %v", common.GetFunctionName(),
+		txHash)
+
+	return &txHash, nil
+}
+
+// fundRawTransaction adds inputs and change output to the transaction,
+// so that it could be signed and sent in the network. Replaceable
+// transaction signals replace-by-fee (BIP125) in its inputs.
+func (c *Client) fundRawTransaction(tx *wire.MsgTx,
+	replaceable bool) (*wire.MsgTx, error) {
+	var b bytes.Buffer
+	if err := tx.Serialize(&b); err != nil {
+		return nil, errors.Errorf("unable to serialize tx: %v", err)
+	}
+
+	param, err := json.Marshal(hex.EncodeToString(b.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	params := []json.RawMessage{param}
+	if replaceable {
+		options, err := json.Marshal(map[string]bool{"replaceable": true})
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, options)
+	}
+
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	rawResp, err := daemon.RawRequest("fundrawtransaction", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Hex string `json:"hex"`
+	}
+	if err := json.Unmarshal(rawResp, &resp); err != nil {
+		return nil, errors.Errorf("unable to decode response: %v", err)
+	}
+
+	serializedTx, err := hex.DecodeString(resp.Hex)
+	if err != nil {
+		return nil, errors.Errorf("unable to decode tx hex: %v", err)
+	}
+
+	fundedTx := wire.NewMsgTx(wire.TxVersion)
+	if err := fundedTx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
+		return nil, errors.Errorf("unable to deserialize tx: %v", err)
+	}
+
+	return fundedTx, nil
 }
 
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) ListTransactionByLabel(label string, count, from int) (
 	[]btcjson.ListTransactionsResult, error) {
-	return c.Daemon.ListTransactionsCountFrom(label, count, from)
+	var txs []btcjson.ListTransactionsResult
+	err := c.withTimeout(func() (err error) {
+		daemon, release := c.AcquireDaemon()
+		defer release()
+
+		txs, err = daemon.ListTransactionsCountFrom(label, count, from)
+		return err
+	})
+	return txs, err
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *Client) ListWatchOnlyTransactionByLabel(label string, count,
+	from int) ([]btcjson.ListTransactionsResult, error) {
+	params, err := marshalParams(label, count, from, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawResp json.RawMessage
+	err = c.withTimeout(func() (err error) {
+		daemon, release := c.AcquireDaemon()
+		defer release()
+
+		rawResp, err = daemon.RawRequest("listtransactions", params)
+		return err
+	})
+	if err != nil {
+		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
+		return nil, err
+	}
+
+	var txs []btcjson.ListTransactionsResult
+	if err := json.Unmarshal(rawResp, &txs); err != nil {
+		return nil, errors.Errorf("unable to decode response: %v", err)
+	}
+
+	return txs, nil
+}
+
+// marshalParams encodes parameters of the raw daemon request.
+func marshalParams(params ...interface{}) ([]json.RawMessage, error) {
+	rawParams := make([]json.RawMessage, len(params))
+	for i, param := range params {
+		rawParam, err := json.Marshal(param)
+		if err != nil {
+			return nil, errors.Errorf("unable to encode param: %v", err)
+		}
+
+		rawParams[i] = rawParam
+	}
+
+	return rawParams, nil
 }
 
 // NOTE: Part of the rpc.Client interface. For more info look in
 // the interface description.
 func (c *Client) GetTransactionByHash(hash *chainhash.Hash) (
 	*rpc.Transaction, error) {
-	tx, err := c.Daemon.GetTransaction(hash)
+	var tx *btcjson.GetTransactionResult
+	err := c.withTimeout(func() (err error) {
+		daemon, release := c.AcquireDaemon()
+		defer release()
+
+		tx, err = daemon.GetTransaction(hash)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,79 @@
+package bitcoin
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// credentialsCheckInterval is the minimum interval between checks of the
+// credentials file modification.
+const credentialsCheckInterval = 5 * time.Second
+
+// credentials is the user and password which are used to authenticate in
+// the daemon rpc.
+type credentials struct {
+	user     string
+	password string
+
+	// modTime is the modification time of the file from which credentials
+	// were read, zero if they were taken from the config.
+	modTime time.Time
+}
+
+// credentialsPath returns the path of the file from which credentials are
+// read, empty if credentials are taken from the config.
+func credentialsPath(cfg ClientConfig) string {
+	if cfg.CookiePath != "" {
+		return cfg.CookiePath
+	}
+
+	return cfg.PasswordFile
+}
+
+// readCredentials returns credentials from the cookie file, password file
+// or the config, in this order of precedence.
+func readCredentials(cfg ClientConfig) (*credentials, error) {
+	path := credentialsPath(cfg)
+	if path == "" {
+		return &credentials{
+			user:     cfg.User,
+			password: cfg.Password,
+		}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.TrimSpace(string(data))
+
+	if cfg.CookiePath == "" {
+		return &credentials{
+			user:     cfg.User,
+			password: content,
+			modTime:  info.ModTime(),
+		}, nil
+	}
+
+	// Cookie file contains user and password separated by colon.
+	parts := strings.SplitN(content, ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("malformed cookie file: %v", path)
+	}
+
+	return &credentials{
+		user:     parts[0],
+		password: parts[1],
+		modTime:  info.ModTime(),
+	}, nil
+}
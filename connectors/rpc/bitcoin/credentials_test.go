@@ -0,0 +1,86 @@
+package bitcoin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cookiePath := filepath.Join(dir, ".cookie")
+	err = ioutil.WriteFile(cookiePath, []byte("__cookie__:secret\n"), 0600)
+	if err != nil {
+		t.Fatalf("unable to write cookie: %v", err)
+	}
+
+	passwordPath := filepath.Join(dir, "password")
+	err = ioutil.WriteFile(passwordPath, []byte("rotated\n"), 0600)
+	if err != nil {
+		t.Fatalf("unable to write password: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		cfg      ClientConfig
+		user     string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "config",
+			cfg:      ClientConfig{User: "user", Password: "pass"},
+			user:     "user",
+			password: "pass",
+		},
+		{
+			name: "cookie",
+			cfg: ClientConfig{User: "user", Password: "pass",
+				CookiePath: cookiePath, PasswordFile: passwordPath},
+			user:     "__cookie__",
+			password: "secret",
+		},
+		{
+			name: "password file",
+			cfg: ClientConfig{User: "user", Password: "pass",
+				PasswordFile: passwordPath},
+			user:     "user",
+			password: "rotated",
+		},
+		{
+			name:    "malformed cookie",
+			cfg:     ClientConfig{CookiePath: passwordPath},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			cfg:     ClientConfig{CookiePath: filepath.Join(dir, "missing")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := readCredentials(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong error, got(%v), want error(%v)", err,
+					tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if creds.user != tt.user || creds.password != tt.password {
+				t.Fatalf("wrong credentials, got(%v:%v), want(%v:%v)",
+					creds.user, creds.password, tt.user, tt.password)
+			}
+		})
+	}
+}
@@ -22,10 +22,13 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 }
 
 // NOTE: Part of the rpc.Client interface.
-func (c *Client) EstimateFee() (float64, error) {
+func (c *Client) EstimateFee(confTarget uint32) (float64, error) {
 	// Bitcoin Cash has removed estimatesmartfee in 17.2 version of their
 	// client.
-	res, err := c.Client.Daemon.EstimateFee(2)
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	res, err := daemon.EstimateFee(int64(confTarget))
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", err)
 		return 0, err
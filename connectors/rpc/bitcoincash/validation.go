@@ -56,6 +56,23 @@ func DecodeAddress(address, network string) (btcutil.Address, error) {
 	return decodedAddress, nil
 }
 
+// EncodeCashAddress encodes the given address in "Cash Address" format,
+// with network prefix included, which is the canonical representation of the
+// bitcoin cash address.
+func EncodeCashAddress(address btcutil.Address) (string, error) {
+	legacyAddress, err := cashAddr.NewFromString(address.EncodeAddress())
+	if err != nil {
+		return "", errors.Errorf("unable to parse legacy address: %v", err)
+	}
+
+	cashAddress, err := legacyAddress.CashAddress()
+	if err != nil {
+		return "", errors.Errorf("unable convert to cash addr: %v", err)
+	}
+
+	return cashAddress.Encode()
+}
+
 func cashAddrNetToInt(networkType cashAddr.NetworkType) int {
 	switch networkType {
 	case cashAddr.MainNet:
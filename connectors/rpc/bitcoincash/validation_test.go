@@ -261,3 +261,45 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeCashAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		addr string
+		want string
+	}{
+		{
+			name: "BCH mainnet P2PKH legacy",
+			addr: "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+			want: "bitcoincash:qpm47l0kukuzjnk2vsp70256s9pd99qs5u2e7gd5f7",
+		},
+		{
+			name: "BCH mainnet P2PKH CashAddr without prefix",
+			addr: "qrrgpy7nffggd9g0fen82lrhtemauurtnuq46g5jl7",
+			want: "bitcoincash:qrrgpy7nffggd9g0fen82lrhtemauurtnuq46g5jl7",
+		},
+		{
+			name: "BCH mainnet P2SH legacy",
+			addr: "32Y8cHhzt89aZMFKTvDJrfTAdA8VY6rPvp",
+			want: "bitcoincash:pqy5nann5h5cst7qh0dwh0q2v3vp2rp6pv0z4z320u",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decodedAddress, err := DecodeAddress(tt.addr, "mainnet")
+			if err != nil {
+				t.Fatalf("unable to decode address: %v", err)
+			}
+
+			got, err := EncodeCashAddress(decodedAddress)
+			if err != nil {
+				t.Fatalf("unable to encode address: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got(%v), want(%v)", got, tt.want)
+			}
+		})
+	}
+}
@@ -33,7 +33,10 @@ type getDashBlockChainInfoResult struct {
 }
 
 func (c *Client) GetBlockChainInfo() (*rpc.BlockChainInfoResp, error) {
-	res := c.Daemon.GetBlockChainInfoAsync()
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	res := daemon.GetBlockChainInfoAsync()
 	info, err := receiveDashInfo(res)
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", err)
@@ -41,7 +44,9 @@ func (c *Client) GetBlockChainInfo() (*rpc.BlockChainInfoResp, error) {
 	}
 
 	resp := &rpc.BlockChainInfoResp{
-		Chain: info.Chain,
+		Chain:   info.Chain,
+		Blocks:  int64(info.Blocks),
+		Headers: int64(info.Headers),
 	}
 
 	c.Logger.Tracef("method: %v, response: %v", common.GetFunctionName(),
@@ -65,9 +70,11 @@ func receiveDashInfo(r rpcclient.FutureGetBlockChainInfoResult) (
 	return &chainInfo, nil
 }
 
-func (c *Client) EstimateFee() (float64, error) {
-	confTarget := uint32(2)
-	res, err := c.Daemon.EstimateSmartFeeWithMode(confTarget, "")
+func (c *Client) EstimateFee(confTarget uint32) (float64, error) {
+	daemon, release := c.AcquireDaemon()
+	defer release()
+
+	res, err := daemon.EstimateSmartFeeWithMode(confTarget, "")
 	if err != nil {
 		c.Logger.Tracef("method: %v, error: %v", common.GetFunctionName(), err)
 		return 0, err
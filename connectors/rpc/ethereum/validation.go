@@ -18,3 +18,13 @@ func ValidateAddress(address string) error {
 	}
 	return nil
 }
+
+// NormalizeAddress validates Ethereum address and returns it in the mixed-case
+// checksum encoding defined in EIP-55.
+func NormalizeAddress(address string) (string, error) {
+	if err := ValidateAddress(address); err != nil {
+		return "", err
+	}
+
+	return eth.HexToAddress(address).Hex(), nil
+}
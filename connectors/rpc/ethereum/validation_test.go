@@ -87,3 +87,51 @@ func TestValidateAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		addr    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "lowercase",
+			addr: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			want: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		},
+		{
+			name: "uppercase",
+			addr: "0xFB6916095CA1DF60BB79CE92CE3EA74C37C5D359",
+			want: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		},
+		{
+			name: "without prefix",
+			addr: "dbf03b407c01e7cd3cbea99509d93f8dddc8c6fb",
+			want: "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		},
+		{
+			name: "already checksummed",
+			addr: "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+			want: "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+		},
+		{
+			name:    "invalid",
+			addr:    "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9a",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeAddress(tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr = %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("got(%v), want(%v)", got, tt.want)
+			}
+		})
+	}
+}
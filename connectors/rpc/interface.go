@@ -25,8 +25,9 @@ type Client interface {
 	GetBalanceByLabel(label string, minConfirms int) (btcutil.Amount, error)
 
 	// EstimateFee estimates the approximate fee per kilobyte needed
-	// for a transaction in order to be included in the block.
-	EstimateFee() (float64, error)
+	// for a transaction in order to be included in the block within the
+	// given number of blocks.
+	EstimateFee(confTarget uint32) (float64, error)
 }
 
 type InputsManager interface {
@@ -39,6 +40,13 @@ type InputsManager interface {
 	// is marked unlocked again.
 	LockUnspent(input UnspentInput) error
 
+	// UnlockUnspentInput marks the locked output as unlocked, so that it
+	// could be selected as input again.
+	UnlockUnspentInput(input UnspentInput) error
+
+	// ListLockUnspent returns outputs which are currently locked.
+	ListLockUnspent() ([]*wire.OutPoint, error)
+
 	// ListUnspentMinMax returns all unspent transaction outputs known to a
 	// wallet, using the specified number of minimum and maximum number of
 	// confirmations as a filter.
@@ -53,6 +61,11 @@ type TransactionManager interface {
 	// ListTransactionByLabel return list of transactions.
 	ListTransactionByLabel(label string, count, from int) ([]btcjson.ListTransactionsResult, error)
 
+	// ListWatchOnlyTransactionByLabel return list of transactions, which
+	// includes transactions of the watch-only addresses.
+	ListWatchOnlyTransactionByLabel(label string, count, from int) (
+		[]btcjson.ListTransactionsResult, error)
+
 	// GetTransactionByHash
 	GetTransactionByHash(hash *chainhash.Hash) (*Transaction, error)
 
@@ -64,6 +77,24 @@ type TransactionManager interface {
 	// SendToAddress sends the passed amount to the given address.
 	SendToAddress(address btcutil.Address, amount btcutil.Amount) (*chainhash.Hash, error)
 
+	// SendToAddressWithData sends the passed amount to the given address,
+	// and attaches OP_RETURN output with the given data to the transaction.
+	SendToAddressWithData(address btcutil.Address, amount btcutil.Amount,
+		data []byte) (*chainhash.Hash, error)
+
+	// SendToAddressReplaceable sends the passed amount to the given address
+	// in transaction which signals replace-by-fee (BIP125), so that it
+	// could be bumped later. OP_RETURN output is attached if data is
+	// specified.
+	SendToAddressReplaceable(address btcutil.Address, amount btcutil.Amount,
+		data []byte) (*chainhash.Hash, error)
+
+	// SendToAddresses sends the passed amounts to the given addresses in one
+	// transaction. If replaceable is true transaction signals
+	// replace-by-fee (BIP125).
+	SendToAddresses(amounts map[btcutil.Address]btcutil.Amount,
+		replaceable bool) (*chainhash.Hash, error)
+
 	// SendRawTransaction submits the encoded transaction to the server which
 	// will then relay it to the network.
 	SendRawTransaction(tx *wire.MsgTx) error
@@ -84,6 +115,10 @@ type BlocksManager interface {
 	// GetBestBlockHash returns the hash of the best block in the longest block
 	// chain.
 	GetBestBlockHash() (*chainhash.Hash, error)
+
+	// GetBlockHash returns the hash of the block on the given height in the
+	// best block chain.
+	GetBlockHash(height int64) (*chainhash.Hash, error)
 }
 
 type AddressManager interface {
@@ -96,10 +131,34 @@ type AddressManager interface {
 
 	// GetNewRawChangeAddress returns new change address.
 	GetNewRawChangeAddress(label string) (btcutil.Address, error)
+
+	// ImportAddress adds watch-only address with the given label to the
+	// wallet. If rescan is true wallet will scan the blockchain for the
+	// previous transactions of this address, which might take a long time.
+	ImportAddress(address btcutil.Address, label string, rescan bool) error
+
+	// GetAddressInfo returns the information about the address known to
+	// the wallet.
+	GetAddressInfo(address btcutil.Address) (*AddressInfoResp, error)
+
+	// SignMessage signs the message with the private key of the address,
+	// and returns the base64 encoded signature.
+	SignMessage(address btcutil.Address, message string) (string, error)
+
+	// VerifyMessage returns true if the message has been signed with the
+	// private key of the address.
+	VerifyMessage(address btcutil.Address, signature,
+		message string) (bool, error)
 }
 
 type BlockChainInfoResp struct {
 	Chain string
+
+	// Blocks is the height of the best validated block.
+	Blocks int64
+
+	// Headers is the height of the best known header.
+	Headers int64
 }
 
 type BlockVerboseResp struct {
@@ -111,6 +170,17 @@ type BlockVerboseResp struct {
 	Tx            []string
 }
 
+type AddressInfoResp struct {
+	// IsMine denotes that wallet has the private key of the address.
+	IsMine bool
+
+	// IsWatchOnly denotes that address has been imported as watch-only.
+	IsWatchOnly bool
+
+	// Label is the label of the address in the wallet.
+	Label string
+}
+
 type UnspentInput struct {
 	Address       string
 	Account       string
@@ -126,6 +196,17 @@ type Transaction struct {
 	Confirmations int64
 	TxID          string
 	Details       []TransactionDetails
+
+	// BlockHash is the hash of the block in which transaction is included,
+	// it is empty for the unconfirmed transaction.
+	BlockHash string
+
+	// BlockTime is the unix time of the block in which transaction is
+	// included.
+	BlockTime int64
+
+	// Hex is the hex encoded serialized transaction.
+	Hex string
 }
 
 type TransactionDetails struct {
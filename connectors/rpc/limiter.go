@@ -0,0 +1,609 @@
+package rpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/metrics/crypto"
+	"github.com/bitlum/go-bitcoind-rpc/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/go-errors/errors"
+)
+
+const (
+	// defaultMaxConcurrentCalls is the default number of calls which are
+	// sent to the daemon concurrently. It is kept below the default size
+	// of the bitcoind rpc work queue, so that daemon doesn't start to
+	// reject the requests.
+	defaultMaxConcurrentCalls = 8
+
+	// defaultMaxQueuedCalls is the default number of calls which could
+	// wait for the free slot, before new calls are rejected.
+	defaultMaxQueuedCalls = 100
+
+	// defaultQueueTimeout is the default maximum time call waits for the
+	// free slot.
+	defaultQueueTimeout = 10 * time.Second
+)
+
+// ErrTooManyCalls is returned if call to the daemon was rejected because
+// too many calls are already executed or waiting.
+var ErrTooManyCalls = errors.New("too many concurrent calls to the daemon")
+
+// LimiterConfig is the configuration of the client which limits the number
+// of concurrent calls to the daemon.
+type LimiterConfig struct {
+	// Asset is the asset of the daemon, used as the label of the metrics.
+	Asset string
+
+	// MaxConcurrentCalls is the number of calls which are sent to the
+	// daemon concurrently, other calls wait for the free slot. If zero
+	// the default number is used.
+	MaxConcurrentCalls int
+
+	// MaxQueuedCalls is the number of calls which could wait for the free
+	// slot, new calls are rejected if queue is full. Calls made by the
+	// sync of the connector are never rejected, and not counted. If zero
+	// the default number is used.
+	MaxQueuedCalls int
+
+	// QueueTimeout is the maximum time call waits for the free slot
+	// before it is rejected. Calls made by the sync of the connector wait
+	// as long as needed. If zero the default timeout is used.
+	QueueTimeout time.Duration
+
+	// Metrics is used to report the number of executed and queued calls.
+	Metrics crypto.MetricsBackend
+}
+
+// limitedClient is the rpc.Client which caps the number of concurrent
+// calls to the daemon, so that flood of the requests, e.g. fee estimations
+// or balance checks made on behalf of the api users, couldn't overflow the
+// daemon rpc work queue. Calls which are used to sync the blocks and
+// wallet transactions take precedence over the other queued calls, so that
+// deposits are still detected in time.
+type limitedClient struct {
+	client Client
+	cfg    LimiterConfig
+
+	mtx      sync.Mutex
+	inFlight int
+
+	// syncWaiters and waiters are the queues of the calls waiting for the
+	// free slot, channel is closed once slot is handed over to the call.
+	syncWaiters []chan struct{}
+	waiters     []chan struct{}
+}
+
+// Runtime check to ensure that limitedClient implements rpc.Client
+// interface.
+var _ Client = (*limitedClient)(nil)
+
+// NewLimitedClient wraps the client so that number of its concurrent calls
+// is limited.
+func NewLimitedClient(client Client, cfg LimiterConfig) Client {
+	if cfg.MaxConcurrentCalls <= 0 {
+		cfg.MaxConcurrentCalls = defaultMaxConcurrentCalls
+	}
+
+	if cfg.MaxQueuedCalls <= 0 {
+		cfg.MaxQueuedCalls = defaultMaxQueuedCalls
+	}
+
+	if cfg.QueueTimeout <= 0 {
+		cfg.QueueTimeout = defaultQueueTimeout
+	}
+
+	if cfg.Metrics == nil {
+		cfg.Metrics = crypto.DisabledBackend
+	}
+
+	return &limitedClient{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// acquire waits for the free slot and returns function which should be
+// called once the call is finished. Sync calls are put in front of the
+// queue and are never rejected.
+func (c *limitedClient) acquire(request string, sync bool) (func(), error) {
+	c.mtx.Lock()
+	if c.inFlight < c.cfg.MaxConcurrentCalls {
+		c.inFlight++
+		c.reportLocked()
+		c.mtx.Unlock()
+		return c.release, nil
+	}
+
+	if !sync && len(c.waiters) >= c.cfg.MaxQueuedCalls {
+		c.mtx.Unlock()
+		log.Warnf("Rejected %v call to %v daemon, %v calls are queued",
+			request, c.cfg.Asset, c.cfg.MaxQueuedCalls)
+		c.cfg.Metrics.AddRPCCallRejected(c.client.DaemonName(),
+			c.cfg.Asset, request)
+		return nil, ErrTooManyCalls
+	}
+
+	ready := make(chan struct{})
+	if sync {
+		c.syncWaiters = append(c.syncWaiters, ready)
+	} else {
+		c.waiters = append(c.waiters, ready)
+	}
+	c.reportLocked()
+	c.mtx.Unlock()
+
+	start := time.Now()
+	if sync {
+		<-ready
+	} else {
+		timer := time.NewTimer(c.cfg.QueueTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-ready:
+		case <-timer.C:
+			c.mtx.Lock()
+			removed := c.removeWaiterLocked(ready)
+			c.reportLocked()
+			c.mtx.Unlock()
+
+			// Slot might have been handed over right before the
+			// timeout, in this case call is executed.
+			if removed {
+				log.Warnf("Rejected %v call to %v daemon, no free slot "+
+					"within %v", request, c.cfg.Asset, c.cfg.QueueTimeout)
+				c.cfg.Metrics.AddRPCCallRejected(c.client.DaemonName(),
+					c.cfg.Asset, request)
+				return nil, ErrTooManyCalls
+			}
+		}
+	}
+
+	c.cfg.Metrics.AddRPCQueueDuration(c.client.DaemonName(), c.cfg.Asset,
+		time.Since(start))
+	return c.release, nil
+}
+
+// release hands the slot over to the next queued call, or frees it if
+// there are no such calls.
+func (c *limitedClient) release() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	switch {
+	case len(c.syncWaiters) != 0:
+		close(c.syncWaiters[0])
+		c.syncWaiters = c.syncWaiters[1:]
+
+	case len(c.waiters) != 0:
+		close(c.waiters[0])
+		c.waiters = c.waiters[1:]
+
+	default:
+		c.inFlight--
+	}
+
+	c.reportLocked()
+}
+
+// removeWaiterLocked removes the call from the queue, and returns false if
+// it has been already handed the slot.
+//
+// NOTE: Should be called with the mutex held.
+func (c *limitedClient) removeWaiterLocked(ready chan struct{}) bool {
+	for i, waiter := range c.waiters {
+		if waiter == ready {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// reportLocked reports the number of executed and queued calls.
+//
+// NOTE: Should be called with the mutex held.
+func (c *limitedClient) reportLocked() {
+	daemon := c.client.DaemonName()
+	c.cfg.Metrics.RPCCallsInFlight(daemon, c.cfg.Asset, c.inFlight)
+	c.cfg.Metrics.RPCCallsQueued(daemon, c.cfg.Asset,
+		len(c.syncWaiters)+len(c.waiters))
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) DaemonName() string {
+	return c.client.DaemonName()
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetBalanceByLabel(label string,
+	minConfirms int) (btcutil.Amount, error) {
+
+	release, err := c.acquire("GetBalanceByLabel", false)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	return c.client.GetBalanceByLabel(label, minConfirms)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) EstimateFee(confTarget uint32) (float64, error) {
+	release, err := c.acquire("EstimateFee", false)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	return c.client.EstimateFee(confTarget)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) UnlockUnspent() error {
+	release, err := c.acquire("UnlockUnspent", false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return c.client.UnlockUnspent()
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) LockUnspent(input UnspentInput) error {
+	release, err := c.acquire("LockUnspent", false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return c.client.LockUnspent(input)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) UnlockUnspentInput(input UnspentInput) error {
+	release, err := c.acquire("UnlockUnspentInput", false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return c.client.UnlockUnspentInput(input)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) ListLockUnspent() ([]*wire.OutPoint, error) {
+	release, err := c.acquire("ListLockUnspent", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.ListLockUnspent()
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) ListUnspentMinMax(minConf,
+	maxConf int) ([]UnspentInput, error) {
+
+	release, err := c.acquire("ListUnspentMinMax", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.ListUnspentMinMax(minConf, maxConf)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) SignRawTransaction(tx *wire.MsgTx) (*wire.MsgTx,
+	error) {
+
+	release, err := c.acquire("SignRawTransaction", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.SignRawTransaction(tx)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) ListTransactionByLabel(label string, count,
+	from int) ([]btcjson.ListTransactionsResult, error) {
+
+	release, err := c.acquire("ListTransactionByLabel", true)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.ListTransactionByLabel(label, count, from)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) ListWatchOnlyTransactionByLabel(label string, count,
+	from int) ([]btcjson.ListTransactionsResult, error) {
+
+	release, err := c.acquire("ListWatchOnlyTransactionByLabel", true)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.ListWatchOnlyTransactionByLabel(label, count, from)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetTransactionByHash(hash *chainhash.Hash) (
+	*Transaction, error) {
+
+	release, err := c.acquire("GetTransactionByHash", true)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetTransactionByHash(hash)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) CreateRawTransaction(inputs []UnspentInput,
+	amounts map[btcutil.Address]btcutil.Amount) (*wire.MsgTx, error) {
+
+	release, err := c.acquire("CreateRawTransaction", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.CreateRawTransaction(inputs, amounts)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) SendToAddress(address btcutil.Address,
+	amount btcutil.Amount) (*chainhash.Hash, error) {
+
+	release, err := c.acquire("SendToAddress", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.SendToAddress(address, amount)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) SendToAddressWithData(address btcutil.Address,
+	amount btcutil.Amount, data []byte) (*chainhash.Hash, error) {
+
+	release, err := c.acquire("SendToAddressWithData", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.SendToAddressWithData(address, amount, data)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) SendToAddressReplaceable(address btcutil.Address,
+	amount btcutil.Amount, data []byte) (*chainhash.Hash, error) {
+
+	release, err := c.acquire("SendToAddressReplaceable", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.SendToAddressReplaceable(address, amount, data)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) SendToAddresses(
+	amounts map[btcutil.Address]btcutil.Amount,
+	replaceable bool) (*chainhash.Hash, error) {
+
+	release, err := c.acquire("SendToAddresses", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.SendToAddresses(amounts, replaceable)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) SendRawTransaction(tx *wire.MsgTx) error {
+	release, err := c.acquire("SendRawTransaction", false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return c.client.SendRawTransaction(tx)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetTransaction(txHash *chainhash.Hash) (*Transaction,
+	error) {
+
+	release, err := c.acquire("GetTransaction", true)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetTransaction(txHash)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetBlockChainInfo() (*BlockChainInfoResp, error) {
+	release, err := c.acquire("GetBlockChainInfo", true)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetBlockChainInfo()
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetBlockVerboseByHash(blockHash *chainhash.Hash) (
+	*BlockVerboseResp, error) {
+
+	release, err := c.acquire("GetBlockVerboseByHash", true)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetBlockVerboseByHash(blockHash)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetBestBlockHash() (*chainhash.Hash, error) {
+	release, err := c.acquire("GetBestBlockHash", true)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetBestBlockHash()
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	release, err := c.acquire("GetBlockHash", true)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetBlockHash(height)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetAddressesByLabel(label string) ([]btcutil.Address,
+	error) {
+
+	release, err := c.acquire("GetAddressesByLabel", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetAddressesByLabel(label)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetNewAddress(label string) (btcutil.Address, error) {
+	release, err := c.acquire("GetNewAddress", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetNewAddress(label)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetNewRawChangeAddress(label string) (btcutil.Address,
+	error) {
+
+	release, err := c.acquire("GetNewRawChangeAddress", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetNewRawChangeAddress(label)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) ImportAddress(address btcutil.Address, label string,
+	rescan bool) error {
+
+	release, err := c.acquire("ImportAddress", false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return c.client.ImportAddress(address, label, rescan)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) GetAddressInfo(address btcutil.Address) (
+	*AddressInfoResp, error) {
+
+	release, err := c.acquire("GetAddressInfo", false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return c.client.GetAddressInfo(address)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) SignMessage(address btcutil.Address,
+	message string) (string, error) {
+
+	release, err := c.acquire("SignMessage", false)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	return c.client.SignMessage(address, message)
+}
+
+// NOTE: Part of the rpc.Client interface. For more info look in
+// the interface description.
+func (c *limitedClient) VerifyMessage(address btcutil.Address, signature,
+	message string) (bool, error) {
+
+	release, err := c.acquire("VerifyMessage", false)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	return c.client.VerifyMessage(address, signature, message)
+}
@@ -0,0 +1,146 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// blockingClient is the client which calls are blocked until they are
+// unblocked by the test. Calls which are not used in the tests are not
+// implemented.
+type blockingClient struct {
+	Client
+
+	// started receives the name of the call once it reaches the daemon.
+	started chan string
+
+	// unblock is used to finish one of the started calls.
+	unblock chan struct{}
+}
+
+func newBlockingClient() *blockingClient {
+	return &blockingClient{
+		started: make(chan string, 10),
+		unblock: make(chan struct{}),
+	}
+}
+
+func (c *blockingClient) DaemonName() string {
+	return "bitcoind"
+}
+
+func (c *blockingClient) EstimateFee(confTarget uint32) (float64, error) {
+	c.started <- "EstimateFee"
+	<-c.unblock
+	return 0.0001, nil
+}
+
+func (c *blockingClient) GetBestBlockHash() (*chainhash.Hash, error) {
+	c.started <- "GetBestBlockHash"
+	<-c.unblock
+	return &chainhash.Hash{}, nil
+}
+
+// waitStarted waits for the call to reach the daemon.
+func (c *blockingClient) waitStarted(t *testing.T) string {
+	select {
+	case call := <-c.started:
+		return call
+	case <-time.After(time.Second):
+		t.Fatalf("call hasn't reached the daemon")
+		return ""
+	}
+}
+
+// waitQueued waits for the given number of calls to be queued.
+func waitQueued(t *testing.T, c *limitedClient, queued int) {
+	for i := 0; ; i++ {
+		c.mtx.Lock()
+		n := len(c.syncWaiters) + len(c.waiters)
+		c.mtx.Unlock()
+
+		if n == queued {
+			return
+		} else if i == 100 {
+			t.Fatalf("wrong number of queued calls: %v", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLimitedClient(t *testing.T) {
+	daemon := newBlockingClient()
+	client := NewLimitedClient(daemon, LimiterConfig{
+		Asset:              "BTC",
+		MaxConcurrentCalls: 1,
+		MaxQueuedCalls:     1,
+		QueueTimeout:       time.Minute,
+	}).(*limitedClient)
+
+	errs := make(chan error, 10)
+	estimateFee := func() {
+		_, err := client.EstimateFee(2)
+		errs <- err
+	}
+
+	// First call occupies the only slot, second one is queued.
+	go estimateFee()
+	daemon.waitStarted(t)
+
+	go estimateFee()
+	waitQueued(t, client, 1)
+
+	// Queue is full, so that next call is rejected right away.
+	if _, err := client.EstimateFee(2); err != ErrTooManyCalls {
+		t.Fatalf("call isn't rejected: %v", err)
+	}
+
+	// Sync call isn't rejected, and is executed before the queued one.
+	go func() {
+		_, err := client.GetBestBlockHash()
+		errs <- err
+	}()
+	waitQueued(t, client, 2)
+
+	daemon.unblock <- struct{}{}
+	if call := daemon.waitStarted(t); call != "GetBestBlockHash" {
+		t.Fatalf("wrong call is executed: %v", call)
+	}
+
+	daemon.unblock <- struct{}{}
+	if call := daemon.waitStarted(t); call != "EstimateFee" {
+		t.Fatalf("wrong call is executed: %v", call)
+	}
+
+	daemon.unblock <- struct{}{}
+	for i := 0; i < 3; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("call has failed: %v", err)
+		}
+	}
+
+	if client.inFlight != 0 {
+		t.Fatalf("slots aren't freed: %v", client.inFlight)
+	}
+}
+
+func TestLimitedClientQueueTimeout(t *testing.T) {
+	daemon := newBlockingClient()
+	client := NewLimitedClient(daemon, LimiterConfig{
+		Asset:              "BTC",
+		MaxConcurrentCalls: 1,
+		QueueTimeout:       50 * time.Millisecond,
+	}).(*limitedClient)
+
+	go client.EstimateFee(2)
+	daemon.waitStarted(t)
+
+	if _, err := client.EstimateFee(2); err != ErrTooManyCalls {
+		t.Fatalf("call isn't rejected: %v", err)
+	}
+
+	waitQueued(t, client, 0)
+	daemon.unblock <- struct{}{}
+}
@@ -2,11 +2,14 @@ package connectors
 
 import (
 	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
 )
 
 // PaymentStorage is an external storage for payments, it is used by
 // connector to save payment as well as update its state.
 type PaymentsStore interface {
+	PaymentEventsStore
+
 	// PaymentByID returns payment by id.
 	PaymentByID(paymentID string) (*Payment, error)
 
@@ -19,10 +22,384 @@ type PaymentsStore interface {
 	// ListPayments return list of all payments.
 	ListPayments(asset Asset, status PaymentStatus, direction PaymentDirection,
 		media PaymentMedia, system PaymentSystem) ([]*Payment, error)
+
+	// QueryPayments returns sorted page of payments which are matching the
+	// query, and the overall number of matching payments.
+	QueryPayments(query PaymentsQuery) ([]*Payment, int, error)
+
+	// SetPaymentAccount binds the payment to the account, account is kept
+	// when payment is later updated by the connector.
+	SetPaymentAccount(paymentID, accountID string) error
+
+	// LabelPayment merges the labels into the metadata of the payment,
+	// label with empty value is removed. Metadata is kept when payment is
+	// later updated by the connector.
+	LabelPayment(paymentID string, labels map[string]string) error
+
+	// SetPaymentRefund links the refund to the incoming payment which it
+	// refunds, link is kept when refund is later updated by the connector.
+	SetPaymentRefund(paymentID, refundOf string) error
+
+	// PaymentRefunds returns the refunds of the incoming payment.
+	PaymentRefunds(paymentID string) ([]*Payment, error)
+
+	// SetPaymentQuarantine sets the state of the compliance review of the
+	// incoming payment along with its reason, state is kept when payment
+	// is later updated by the connector.
+	SetPaymentQuarantine(paymentID string, state QuarantineState,
+		reason string) error
+}
+
+// PaymentEventsStore is an external storage for the timelines of the
+// payments. Events of the payment state changes are recorded by the
+// payments store itself on save.
+type PaymentEventsStore interface {
+	// AddPaymentEvent appends the event to the timeline of the payment.
+	AddPaymentEvent(event *PaymentEvent) error
+
+	// PaymentTimeline returns the events of the payment ordered by time.
+	PaymentTimeline(paymentID string) ([]*PaymentEvent, error)
+}
+
+// PaymentsSortField is the field of the payment by which payments are
+// sorted.
+type PaymentsSortField string
+
+const (
+	// SortByUpdatedAt sorts payments by the time of last update.
+	SortByUpdatedAt PaymentsSortField = "updated_at"
+
+	// SortByAmount sorts payments by their amount.
+	SortByAmount PaymentsSortField = "amount"
+
+	// SortByStatus sorts payments by their status.
+	SortByStatus PaymentsSortField = "status"
+
+	// SortBySequence sorts payments by their sequence number, i.e. in
+	// order of their changes.
+	SortBySequence PaymentsSortField = "sequence"
+)
+
+// PaymentsQuery is the filter, sort order and page of the payments which
+// should be returned by the store. Empty filter fields are matching any
+// value.
+type PaymentsQuery struct {
+	Asset     Asset
+	Status    PaymentStatus
+	Direction PaymentDirection
+	Media     PaymentMedia
+	System    PaymentSystem
+	AccountID string
+
+	// MediaID is the identifier of the payments inside the media, e.g.
+	// id of the blockchain transaction, empty value matches any id.
+	MediaID string
+
+	// UpdatedFrom and UpdatedTo are the bounds of the time of the last
+	// update in milliseconds, inclusive, zero means that bound isn't set.
+	// Completed payments aren't updated, so that it is the time of their
+	// completion.
+	UpdatedFrom int64
+	UpdatedTo   int64
+
+	// MinAmount and MaxAmount are the bounds of the amount, inclusive, zero
+	// means that bound isn't set.
+	MinAmount decimal.Decimal
+	MaxAmount decimal.Decimal
+
+	// SinceSequence is the sequence number after which payments have been
+	// changed, exclusive, zero means that bound isn't set.
+	SinceSequence uint64
+
+	// Quarantine is the quarantine state of the payments, empty value
+	// matches any state, including not quarantined payments.
+	Quarantine QuarantineState
+
+	// SortBy is the field by which payments are sorted, by default they
+	// are sorted by the time of last update.
+	SortBy PaymentsSortField
+
+	// Ascending denotes that payments are sorted in ascending order,
+	// by default they are sorted in descending one.
+	Ascending bool
+
+	// Offset is the number of matching payments which are skipped.
+	Offset int
+
+	// Limit is the maximum number of returned payments, zero means that
+	// all matching payments are returned.
+	Limit int
 }
 
 var PaymentNotFound = errors.New("payment not found")
 
+// PayeesStore is an external storage for payee presets, which are used to
+// send routine payments without specifying the receipt every time.
+type PayeesStore interface {
+	// PayeeByName returns payee by its name.
+	PayeeByName(name string) (*Payee, error)
+
+	// SavePayee creates or updates the payee in the store.
+	SavePayee(payee *Payee) error
+
+	// RemovePayee removes the payee from the store.
+	RemovePayee(name string) error
+
+	// ListPayees return list of all payees.
+	ListPayees() ([]*Payee, error)
+}
+
+var PayeeNotFound = errors.New("payee not found")
+
+// BrandingStore is an external storage for the branding of the hosted
+// checkout and QR pages of the tenants.
+type BrandingStore interface {
+	// BrandingByTenant returns branding of the tenant.
+	BrandingByTenant(tenant string) (*Branding, error)
+
+	// SaveBranding creates or updates the branding of the tenant.
+	SaveBranding(branding *Branding) error
+
+	// RemoveBranding removes the branding of the tenant.
+	RemoveBranding(tenant string) error
+}
+
+var BrandingNotFound = errors.New("branding not found")
+
+// WatchStore is an external storage for watched addresses and events
+// which were produced by the activity on them.
+type WatchStore interface {
+	// SaveWatchAddress adds address to the watched group.
+	SaveWatchAddress(address *WatchAddress) error
+
+	// ImportWatchAddresses saves addresses in one transaction, addresses
+	// which are already watched are updated, so that import could be
+	// repeated. Returns number of added and updated addresses.
+	ImportWatchAddresses(addresses []*WatchAddress) (int, int, error)
+
+	// RemoveWatchAddress stops watching the address.
+	RemoveWatchAddress(asset Asset, address string) error
+
+	// ListWatchAddresses returns watched addresses of the given asset and
+	// group, empty values are used to return all of them.
+	ListWatchAddresses(asset Asset, group string) ([]*WatchAddress, error)
+
+	// WatchEventExists returns whether event with given id has been
+	// already saved.
+	WatchEventExists(eventID string) (bool, error)
+
+	// SaveWatchEvent add event to the store.
+	SaveWatchEvent(event *WatchEvent) error
+
+	// ListUndeliveredWatchEvents returns events which haven't been
+	// delivered to the listener yet, oldest first.
+	ListUndeliveredWatchEvents() ([]*WatchEvent, error)
+
+	// MarkWatchEventDelivered marks event as delivered, so that it
+	// wouldn't be sent again.
+	MarkWatchEventDelivered(eventID string) error
+
+	// RedeliverWatchEvents marks delivered events of the given group, which
+	// were created within the bounds in milliseconds, inclusive, as
+	// undelivered redeliveries. Empty group and zero bounds are not used in
+	// the filter. Returns number of the events to be redelivered.
+	RedeliverWatchEvents(group string, createdFrom, createdTo int64) (int,
+		error)
+
+	// ListWatchEvents returns events of the given group, empty group is
+	// used to return all of them.
+	ListWatchEvents(group string) ([]*WatchEvent, error)
+}
+
+var WatchAddressNotFound = errors.New("watch address not found")
+
+// APIKeysStore is an external storage for API keys of the downstream
+// services.
+type APIKeysStore interface {
+	// APIKeyByID returns key by its id.
+	APIKeyByID(id string) (*APIKey, error)
+
+	// APIKeyByHash returns key by the hash of the key.
+	APIKeyByHash(hash string) (*APIKey, error)
+
+	// SaveAPIKey creates or updates the key in the store.
+	SaveAPIKey(key *APIKey) error
+
+	// ListAPIKeys return list of all keys, including revoked ones.
+	ListAPIKeys() ([]*APIKey, error)
+}
+
+var APIKeyNotFound = errors.New("api key not found")
+
+// AccountsStore is an external storage for the registered accounts of the
+// ledger.
+type AccountsStore interface {
+	// CreateAccount adds account to the store, AccountExists error is
+	// returned if account with the same id is already stored.
+	CreateAccount(account *Account) error
+
+	// AccountByID returns account by its id, AccountNotFound error is
+	// returned if account isn't stored.
+	AccountByID(id string) (*Account, error)
+
+	// ListAccounts returns accounts of the given tenant ordered by the
+	// creation time, empty tenant is used to return all of them.
+	ListAccounts(tenant string) ([]*Account, error)
+}
+
+var AccountNotFound = errors.New("account not found")
+
+var AccountExists = errors.New("account already exists")
+
+// ReceiptsStore is an external storage for receipts which have been
+// created in order to receive money.
+type ReceiptsStore interface {
+	// SaveReceipt adds receipt to the store.
+	SaveReceipt(receipt *Receipt) error
+
+	// QueryReceipts returns page of receipts which are matching the query,
+	// ordered by the creation time from the newest, and the overall number
+	// of matching receipts.
+	QueryReceipts(query ReceiptsQuery) ([]*Receipt, int, error)
+
+	// ReceiptTenant returns the tenant on behalf of which receipt has been
+	// created, empty if receipt isn't stored.
+	ReceiptTenant(receipt string) (string, error)
+
+	// ReceiptByID returns receipt with its current status, ReceiptNotFound
+	// error is returned if receipt isn't stored.
+	ReceiptByID(receiptID string) (*Receipt, error)
+
+	// ReplaceReceipt saves the replacement and binds the replaced receipt
+	// to it, ReceiptReplaced error is returned if receipt has already been
+	// replaced.
+	ReplaceReceipt(receiptID string, replacement *Receipt) error
+
+	// SetReceiptRefundAddress attaches the refund address to the receipt,
+	// RefundAddressAttached error is returned if the other address has
+	// been already attached.
+	SetReceiptRefundAddress(receiptID, address string) error
+
+	// SaveDepositAddress adds address to the store, incoming payments on
+	// it are bound to its account, and its tenant is returned by the
+	// ReceiptTenant as if it was the receipt.
+	SaveDepositAddress(address *DepositAddress) error
+
+	// DepositAddressByAddress returns the tenant and the account to which
+	// incoming payments on the address are bound. Blockchain receipts are
+	// returned as the deposit addresses too. ReceiptNotFound error is
+	// returned if address isn't stored.
+	DepositAddressByAddress(address string) (*DepositAddress, error)
+
+	// ListDepositAddresses returns the deposit addresses of the account
+	// ordered by the creation time, blockchain receipts of the account are
+	// returned as the deposit addresses too.
+	ListDepositAddresses(accountID string) ([]*DepositAddress, error)
+}
+
+var ReceiptNotFound = errors.New("receipt not found")
+
+var ReceiptReplaced = errors.New("receipt has already been replaced")
+
+var RefundAddressAttached = errors.New("refund address has already been " +
+	"attached")
+
+// TimeLocksStore is an external storage for time-locked payments, which
+// keeps the scripts needed to spend them.
+type TimeLocksStore interface {
+	// SaveTimeLock adds time lock to the store.
+	SaveTimeLock(lock *TimeLock) error
+
+	// SaveTimeLockedPayment saves the payment on the script address along
+	// with its time lock in one transaction, so that payment is never
+	// stored without the script needed to spend it.
+	SaveTimeLockedPayment(payment *Payment, lock *TimeLock) error
+
+	// ListTimeLocks returns time locks of the given asset, empty asset is
+	// used to return all of them.
+	ListTimeLocks(asset Asset) ([]*TimeLock, error)
+}
+
+// TestPaymentSchedule is the time at which fabricated test payment should
+// be completed.
+type TestPaymentSchedule struct {
+	// PaymentID is the id of the pending test payment.
+	PaymentID string
+
+	// CompleteAt is the time of the completion in milliseconds.
+	CompleteAt int64
+}
+
+// TestPaymentsStore is an external storage for the schedule of the test
+// payments, so that they are completed even if server has been restarted.
+type TestPaymentsStore interface {
+	// ScheduleTestPayment adds completion time of the test payment.
+	ScheduleTestPayment(schedule *TestPaymentSchedule) error
+
+	// ListTestPaymentSchedules returns schedules of the test payments
+	// which haven't been completed yet.
+	ListTestPaymentSchedules() ([]*TestPaymentSchedule, error)
+
+	// RemoveTestPaymentSchedule removes schedule of the completed test
+	// payment.
+	RemoveTestPaymentSchedule(paymentID string) error
+}
+
+// BalanceSnapshotsStore is an external storage for the periodic snapshots
+// of the balances, so that their history is kept across restarts.
+type BalanceSnapshotsStore interface {
+	// SaveBalanceSnapshot adds snapshot to the store.
+	SaveBalanceSnapshot(snapshot *BalanceSnapshot) error
+
+	// QueryBalanceSnapshots returns snapshots which are matching the
+	// query, ordered by the time from the oldest.
+	QueryBalanceSnapshots(query BalanceSnapshotsQuery) ([]*BalanceSnapshot,
+		error)
+}
+
+// WithdrawalPausesStore is an external storage for the pauses of the
+// outgoing payments, so that paused asset isn't resumed by the restart.
+type WithdrawalPausesStore interface {
+	// SavePause adds pause to the store, or replaces the pause of the same
+	// asset.
+	SavePause(pause *WithdrawalPause) error
+
+	// DeletePause removes pause of the asset, if there is one.
+	DeletePause(asset Asset) error
+
+	// Pauses returns all pauses.
+	Pauses() ([]*WithdrawalPause, error)
+}
+
+// FederationStore is an external storage for the ledger with the payservers
+// of other regions, from which net positions with them are calculated.
+type FederationStore interface {
+	// SaveFederationEntry adds entry to the store, or replaces the entry
+	// with the same id.
+	SaveFederationEntry(entry *FederationEntry) error
+
+	// FederationEntryByID returns the entry, FederationEntryNotFound error
+	// is returned if there is no such entry.
+	FederationEntryByID(id string) (*FederationEntry, error)
+
+	// FederationEntries returns all entries of the peer, ordered by the
+	// time of creation.
+	FederationEntries(peer string) ([]*FederationEntry, error)
+}
+
+var FederationEntryNotFound = errors.New("federation entry not found")
+
+// DepositsStore is an external storage for the identifiers of the processed
+// deposits, e.g. txid:vout of the received output or hash of the lightning
+// payment, so that deposit which is seen again after rescan, replay or
+// resync of the daemon isn't recorded as the new payment.
+type DepositsStore interface {
+	// RecordDeposit binds the deposit to the payment if deposit hasn't been
+	// processed yet, and returns id of the payment to which deposit is
+	// bound, so that repeated recording is idempotent.
+	RecordDeposit(asset Asset, depositID, paymentID string) (string, error)
+}
+
 // StateStorage is used to keep data which is needed for connector to
 // properly synchronise and track transactions.
 //
@@ -34,3 +411,11 @@ type StateStorage interface {
 	// LastSyncedHash is used to retrieve last synchronised block hash.
 	LastSyncedHash() ([]byte, error)
 }
+
+// WriteChecker is an interface which is implemented by the storage which
+// is able to check that data could be written into it, e.g. that disk
+// isn't full or read-only.
+type WriteChecker interface {
+	// CheckWritable writes the probe into the storage.
+	CheckWritable() error
+}
@@ -0,0 +1,234 @@
+package connectors
+
+import (
+	"sync"
+)
+
+// subscriptionBufferSize is the number of payment updates which could be
+// queued for the subscriber before it is considered to be too slow.
+const subscriptionBufferSize = 100
+
+// PaymentsSubscriber is the payments store which is able to notify about
+// the updates of the payments.
+type PaymentsSubscriber interface {
+	// SubscribePayments returns subscription which receives payment every
+	// time it is saved in the store.
+	SubscribePayments() *PaymentsSubscription
+}
+
+// PaymentsNotifier is the payments store which notifies subscribers about
+// the payments saved bypassing it, e.g. in one transaction along with the
+// other records.
+type PaymentsNotifier interface {
+	// NotifyPayment sends saved payment to the subscribers.
+	NotifyPayment(payment *Payment)
+}
+
+// PaymentsSubscription is the stream of the payment updates.
+type PaymentsSubscription struct {
+	// Updates receives copy of the payment every time it is saved. Channel
+	// is closed if subscription is cancelled, or if subscriber is unable
+	// to keep up with the updates.
+	Updates <-chan *Payment
+
+	updates chan *Payment
+	cancel  func()
+}
+
+// Cancel stops the subscription and closes the updates channel.
+func (s *PaymentsSubscription) Cancel() {
+	s.cancel()
+}
+
+// PaymentsBroadcaster is the payments store wrapper which notifies
+// subscribers about every saved payment, so that clients could receive
+// payment state transitions without polling the store.
+type PaymentsBroadcaster struct {
+	PaymentsStore
+
+	mtx           sync.Mutex
+	nextID        uint64
+	subscriptions map[uint64]*PaymentsSubscription
+}
+
+// Runtime check to ensure that PaymentsBroadcaster implements
+// PaymentsStore and PaymentsSubscriber interfaces.
+var _ PaymentsStore = (*PaymentsBroadcaster)(nil)
+var _ PaymentsSubscriber = (*PaymentsBroadcaster)(nil)
+var _ QueueReporter = (*PaymentsBroadcaster)(nil)
+var _ PaymentsNotifier = (*PaymentsBroadcaster)(nil)
+
+// NewPaymentsBroadcaster wraps the store, so that payments saved through
+// it are broadcasted to the subscribers.
+func NewPaymentsBroadcaster(store PaymentsStore) *PaymentsBroadcaster {
+	return &PaymentsBroadcaster{
+		PaymentsStore: store,
+		subscriptions: make(map[uint64]*PaymentsSubscription),
+	}
+}
+
+// SavePayment saves payment in the underlying store and notifies the
+// subscribers.
+//
+// NOTE: Part of the PaymentsStore interface.
+func (b *PaymentsBroadcaster) SavePayment(payment *Payment) error {
+	if err := b.PaymentsStore.SavePayment(payment); err != nil {
+		return err
+	}
+
+	b.NotifyPayment(payment)
+	return nil
+}
+
+// SetPaymentAccount binds the payment to the account in the underlying
+// store and notifies the subscribers about the updated payment.
+//
+// NOTE: Part of the PaymentsStore interface.
+func (b *PaymentsBroadcaster) SetPaymentAccount(paymentID,
+	accountID string) error {
+	if err := b.PaymentsStore.SetPaymentAccount(paymentID,
+		accountID); err != nil {
+		return err
+	}
+
+	payment, err := b.PaymentsStore.PaymentByID(paymentID)
+	if err != nil {
+		return err
+	}
+
+	b.NotifyPayment(payment)
+	return nil
+}
+
+// LabelPayment labels the payment in the underlying store and notifies the
+// subscribers about the updated payment.
+//
+// NOTE: Part of the PaymentsStore interface.
+func (b *PaymentsBroadcaster) LabelPayment(paymentID string,
+	labels map[string]string) error {
+	if err := b.PaymentsStore.LabelPayment(paymentID, labels); err != nil {
+		return err
+	}
+
+	payment, err := b.PaymentsStore.PaymentByID(paymentID)
+	if err != nil {
+		return err
+	}
+
+	b.NotifyPayment(payment)
+	return nil
+}
+
+// SetPaymentRefund links the refund in the underlying store and notifies
+// the subscribers about the updated refund.
+//
+// NOTE: Part of the PaymentsStore interface.
+func (b *PaymentsBroadcaster) SetPaymentRefund(paymentID,
+	refundOf string) error {
+	if err := b.PaymentsStore.SetPaymentRefund(paymentID,
+		refundOf); err != nil {
+		return err
+	}
+
+	payment, err := b.PaymentsStore.PaymentByID(paymentID)
+	if err != nil {
+		return err
+	}
+
+	b.NotifyPayment(payment)
+	return nil
+}
+
+// SetPaymentQuarantine sets the quarantine state in the underlying store
+// and notifies the subscribers about the updated payment.
+//
+// NOTE: Part of the PaymentsStore interface.
+func (b *PaymentsBroadcaster) SetPaymentQuarantine(paymentID string,
+	state QuarantineState, reason string) error {
+	if err := b.PaymentsStore.SetPaymentQuarantine(paymentID, state,
+		reason); err != nil {
+		return err
+	}
+
+	payment, err := b.PaymentsStore.PaymentByID(paymentID)
+	if err != nil {
+		return err
+	}
+
+	b.NotifyPayment(payment)
+	return nil
+}
+
+// NotifyPayment sends copy of the payment to the subscribers.
+//
+// NOTE: Part of the PaymentsNotifier interface.
+func (b *PaymentsBroadcaster) NotifyPayment(payment *Payment) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	for id, s := range b.subscriptions {
+		update := &Payment{}
+		*update = *payment
+
+		// Saving of the payment shouldn't be blocked by the slow
+		// subscriber, that is why it is dropped, and has to
+		// resubscribe.
+		select {
+		case s.updates <- update:
+		default:
+			delete(b.subscriptions, id)
+			close(s.updates)
+		}
+	}
+}
+
+// SubscribePayments returns subscription which receives payment every time
+// it is saved in the store.
+//
+// NOTE: Part of the PaymentsSubscriber interface.
+func (b *PaymentsBroadcaster) SubscribePayments() *PaymentsSubscription {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	updates := make(chan *Payment, subscriptionBufferSize)
+	s := &PaymentsSubscription{
+		Updates: updates,
+		updates: updates,
+	}
+	s.cancel = func() {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+
+		// Subscription might be already removed, if subscriber was too
+		// slow.
+		if _, ok := b.subscriptions[id]; ok {
+			delete(b.subscriptions, id)
+			close(updates)
+		}
+	}
+	b.subscriptions[id] = s
+
+	return s
+}
+
+// QueueDepths returns the number of subscriptions and the number of updates
+// which are not yet received by the subscribers.
+//
+// NOTE: Part of the QueueReporter interface.
+func (b *PaymentsBroadcaster) QueueDepths() map[string]int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	var queued int
+	for _, s := range b.subscriptions {
+		queued += len(s.updates)
+	}
+
+	return map[string]int{
+		"payments_subscriptions": len(b.subscriptions),
+		"payments_updates":       queued,
+	}
+}
@@ -0,0 +1,56 @@
+package connectors
+
+import (
+	"testing"
+)
+
+// discardPaymentsStore is the payments store which doesn't store anything.
+type discardPaymentsStore struct {
+	PaymentsStore
+}
+
+func (s *discardPaymentsStore) SavePayment(payment *Payment) error {
+	return nil
+}
+
+func TestPaymentsBroadcaster(t *testing.T) {
+	b := NewPaymentsBroadcaster(&discardPaymentsStore{})
+
+	s1 := b.SubscribePayments()
+	s2 := b.SubscribePayments()
+
+	payment := &Payment{PaymentID: "1", Status: Pending}
+	if err := b.SavePayment(payment); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	for _, s := range []*PaymentsSubscription{s1, s2} {
+		update := <-s.Updates
+		if update.PaymentID != "1" || update.Status != Pending {
+			t.Fatalf("wrong update: %v", update)
+		}
+	}
+
+	s1.Cancel()
+	if _, ok := <-s1.Updates; ok {
+		t.Fatal("updates channel should be closed")
+	}
+
+	// Cancel of already cancelled subscription shouldn't panic.
+	s1.Cancel()
+
+	// Subscriber which doesn't read updates is dropped, when its buffer
+	// is full.
+	for i := 0; i <= subscriptionBufferSize; i++ {
+		if err := b.SavePayment(payment); err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+	}
+
+	for i := 0; i < subscriptionBufferSize; i++ {
+		<-s2.Updates
+	}
+	if _, ok := <-s2.Updates; ok {
+		t.Fatal("slow subscriber should be dropped")
+	}
+}
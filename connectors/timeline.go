@@ -0,0 +1,121 @@
+package connectors
+
+import (
+	"strconv"
+)
+
+// PaymentEventType is the type of the event in the timeline of the payment.
+type PaymentEventType string
+
+const (
+	// EventCreated is recorded when outgoing payment is created.
+	EventCreated PaymentEventType = "created"
+
+	// EventBroadcast is recorded when outgoing payment is passed to the
+	// media, e.g. transaction is broadcasted in the blockchain.
+	EventBroadcast PaymentEventType = "broadcast"
+
+	// EventFirstSeen is recorded when incoming payment is seen for the
+	// first time.
+	EventFirstSeen PaymentEventType = "first_seen"
+
+	// EventConfirmed is recorded when the number of the confirmations of
+	// the blockchain payment grows, details are the number of the
+	// confirmations.
+	EventConfirmed PaymentEventType = "confirmed"
+
+	// EventCompleted is recorded when payment is completed.
+	EventCompleted PaymentEventType = "completed"
+
+	// EventFailed is recorded when payment is failed, details are the
+	// reason of the failure if it is known.
+	EventFailed PaymentEventType = "failed"
+
+	// EventHookDelivered is recorded when the finished payment has been
+	// delivered to the post-send hook.
+	EventHookDelivered PaymentEventType = "hook_delivered"
+)
+
+// PaymentEvent is the point in the life of the payment, the ordered list of
+// which forms the timeline of the payment, used to investigate the
+// complaints about the payment.
+type PaymentEvent struct {
+	// PaymentID is the id of the payment to which event belongs.
+	PaymentID string
+
+	// Type is the type of the event.
+	Type PaymentEventType
+
+	// Time is the time of the event in milliseconds.
+	Time int64
+
+	// Details is the additional information which depends on the type of
+	// the event.
+	Details string
+}
+
+// confirmations returns the number of the confirmations of the pending
+// blockchain payment, zero if it is unknown.
+func confirmations(payment *Payment) int64 {
+	if payment == nil {
+		return 0
+	}
+
+	details, ok := payment.Detail.(*BlockchainPendingDetails)
+	if !ok {
+		return 0
+	}
+
+	return details.Confirmations
+}
+
+// PaymentTimelineEvents returns the events which happened to the payment
+// between its previous state and the current one. Previous state is nil if
+// payment is saved for the first time.
+func PaymentTimelineEvents(prev, payment *Payment, now int64) []*PaymentEvent {
+	var events []*PaymentEvent
+	add := func(eventType PaymentEventType, details string) {
+		events = append(events, &PaymentEvent{
+			PaymentID: payment.PaymentID,
+			Type:      eventType,
+			Time:      now,
+			Details:   details,
+		})
+	}
+
+	var prevStatus PaymentStatus
+	if prev != nil {
+		prevStatus = prev.Status
+	}
+
+	if prev == nil {
+		if payment.Direction == Incoming {
+			add(EventFirstSeen, "")
+		} else {
+			add(EventCreated, "")
+		}
+	}
+
+	// Outgoing payment is broadcasted once it leaves the waiting state,
+	// unless it has failed before that.
+	if payment.Direction == Outgoing && payment.Status != Waiting &&
+		payment.Status != Failed &&
+		(prevStatus == "" || prevStatus == Waiting) {
+		add(EventBroadcast, "")
+	}
+
+	if confs := confirmations(payment); confs > confirmations(prev) {
+		add(EventConfirmed, strconv.FormatInt(confs, 10))
+	}
+
+	if payment.Status != prevStatus {
+		switch payment.Status {
+		case Completed:
+			add(EventCompleted, "")
+		case Failed:
+			add(EventFailed, payment.FailureReason)
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,120 @@
+package connectors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPaymentTimelineEvents(t *testing.T) {
+	pending := func(confirmations int64) Serializable {
+		return &BlockchainPendingDetails{Confirmations: confirmations}
+	}
+
+	tests := []struct {
+		name    string
+		prev    *Payment
+		payment *Payment
+		events  []PaymentEventType
+		details []string
+	}{
+		{
+			name: "outgoing created",
+			payment: &Payment{
+				Direction: Outgoing,
+				Status:    Waiting,
+			},
+			events:  []PaymentEventType{EventCreated},
+			details: []string{""},
+		},
+		{
+			name: "outgoing broadcasted",
+			prev: &Payment{
+				Direction: Outgoing,
+				Status:    Waiting,
+			},
+			payment: &Payment{
+				Direction: Outgoing,
+				Status:    Pending,
+				Detail:    pending(0),
+			},
+			events:  []PaymentEventType{EventBroadcast},
+			details: []string{""},
+		},
+		{
+			name: "outgoing failed",
+			prev: &Payment{
+				Direction: Outgoing,
+				Status:    Waiting,
+			},
+			payment: &Payment{
+				Direction:     Outgoing,
+				Status:        Failed,
+				FailureReason: "insufficient fee",
+			},
+			events:  []PaymentEventType{EventFailed},
+			details: []string{"insufficient fee"},
+		},
+		{
+			name: "incoming first seen",
+			payment: &Payment{
+				Direction: Incoming,
+				Status:    Pending,
+				Detail:    pending(1),
+			},
+			events:  []PaymentEventType{EventFirstSeen, EventConfirmed},
+			details: []string{"", "1"},
+		},
+		{
+			name: "incoming confirmed and completed",
+			prev: &Payment{
+				Direction: Incoming,
+				Status:    Pending,
+				Detail:    pending(1),
+			},
+			payment: &Payment{
+				Direction: Incoming,
+				Status:    Completed,
+				Detail:    pending(3),
+			},
+			events:  []PaymentEventType{EventConfirmed, EventCompleted},
+			details: []string{"3", ""},
+		},
+		{
+			name: "unchanged",
+			prev: &Payment{
+				Direction: Incoming,
+				Status:    Pending,
+				Detail:    pending(2),
+			},
+			payment: &Payment{
+				Direction: Incoming,
+				Status:    Pending,
+				Detail:    pending(2),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				events  []PaymentEventType
+				details []string
+			)
+			for _, event := range PaymentTimelineEvents(test.prev,
+				test.payment, 10) {
+				if event.Time != 10 {
+					t.Fatalf("wrong event time: %v", event.Time)
+				}
+
+				events = append(events, event.Type)
+				details = append(details, event.Details)
+			}
+
+			if !reflect.DeepEqual(events, test.events) ||
+				!reflect.DeepEqual(details, test.details) {
+				t.Fatalf("wrong events, expected(%v, %v), got(%v, %v)",
+					test.events, test.details, events, details)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package connectors
+
+import (
+	"time"
+)
+
+// LockTimeThreshold is the value of the lock time below which it is
+// interpreted as the block height, and as the unix time otherwise (BIP65).
+const LockTimeThreshold = 500000000
+
+// TimeLock is the outgoing payment on the script address, which could be
+// spent by the recipient only after the lock time, e.g. escrow which is
+// released to the vendor in the future. Script is in form of
+// "<lock time> OP_CHECKLOCKTIMEVERIFY OP_DROP <recipient key> OP_CHECKSIG".
+type TimeLock struct {
+	// PaymentID is the id of the outgoing payment on the script address.
+	PaymentID string
+
+	// Asset is the asset of the payment.
+	Asset Asset
+
+	// Address is the pay-to-witness-script-hash address of the script.
+	Address string
+
+	// RedeemScript is the script which should be revealed by the
+	// recipient in order to spend the output.
+	RedeemScript []byte
+
+	// RecipientPubKey is the hex encoded compressed public key of the
+	// recipient.
+	RecipientPubKey string
+
+	// LockTime is the block height or the unix time, depending on the
+	// LockTimeThreshold, after which output could be spent.
+	LockTime uint32
+
+	// CreatedAt is the time of the payment in milliseconds.
+	CreatedAt int64
+}
+
+// IsHeight returns true if lock time is the block height.
+func (l *TimeLock) IsHeight() bool {
+	return l.LockTime < LockTimeThreshold
+}
+
+// IsUnlocked returns true if output could be spent in the next block.
+//
+// NOTE: Time based lock is checked against the median time of the past
+// blocks, which is behind the current time, so it is an approximation.
+func (l *TimeLock) IsUnlocked(bestHeight int64, now time.Time) bool {
+	if l.IsHeight() {
+		return bestHeight >= int64(l.LockTime)
+	}
+
+	return now.Unix() >= int64(l.LockTime)
+}
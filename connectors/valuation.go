@@ -0,0 +1,62 @@
+package connectors
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// fiatValuePrecision is the number of decimal places to which fiat value of
+// the payment is rounded.
+const fiatValuePrecision = 2
+
+// FiatPricer is the source of the prices of the assets in the fiat
+// currencies.
+type FiatPricer interface {
+	// Price returns the price of the asset in the currency, false is
+	// returned if price is unknown.
+	Price(asset Asset, currency string) (decimal.Decimal, bool)
+}
+
+// PaymentsValuer is the payments store wrapper which records the fiat value
+// of the payment at the moment when it is completed, because recomputing
+// the historical value later is inaccurate and slow. Store keeps the first
+// recorded value, so that repeated saves of the completed payment by the
+// sync don't revalue it.
+type PaymentsValuer struct {
+	PaymentsStore
+
+	currency string
+	pricer   FiatPricer
+}
+
+// Runtime check to ensure that PaymentsValuer implements PaymentsStore
+// interface.
+var _ PaymentsStore = (*PaymentsValuer)(nil)
+
+// NewPaymentsValuer wraps the store, so that payments completed through it
+// are valued in the given currency.
+func NewPaymentsValuer(store PaymentsStore, currency string,
+	pricer FiatPricer) *PaymentsValuer {
+	return &PaymentsValuer{
+		PaymentsStore: store,
+		currency:      strings.ToUpper(currency),
+		pricer:        pricer,
+	}
+}
+
+// SavePayment values the completed payment, if it hasn't been valued yet
+// and price of its asset is known, and saves it in the underlying store.
+//
+// NOTE: Part of the PaymentsStore interface.
+func (v *PaymentsValuer) SavePayment(payment *Payment) error {
+	if payment.Status == Completed && payment.FiatCurrency == "" {
+		if price, ok := v.pricer.Price(payment.Asset, v.currency); ok {
+			payment.FiatValue = payment.Amount.Mul(price).
+				Round(fiatValuePrecision)
+			payment.FiatCurrency = v.currency
+		}
+	}
+
+	return v.PaymentsStore.SavePayment(payment)
+}
@@ -0,0 +1,91 @@
+package connectors
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// staticPricer is the pricer with the fixed prices in USD.
+type staticPricer map[Asset]decimal.Decimal
+
+func (p staticPricer) Price(asset Asset, currency string) (decimal.Decimal,
+	bool) {
+	if currency != "USD" {
+		return decimal.Zero, false
+	}
+
+	price, ok := p[asset]
+	return price, ok
+}
+
+func TestPaymentsValuer(t *testing.T) {
+	v := NewPaymentsValuer(&discardPaymentsStore{}, "usd", staticPricer{
+		BTC: decimal.New(650012, -2),
+	})
+
+	tests := []struct {
+		name     string
+		payment  *Payment
+		value    string
+		currency string
+	}{
+		{
+			name: "pending",
+			payment: &Payment{
+				Status: Pending,
+				Asset:  BTC,
+				Amount: decimal.New(1, 0),
+			},
+		},
+		{
+			name: "completed",
+			payment: &Payment{
+				Status: Completed,
+				Asset:  BTC,
+				Amount: decimal.New(15, -1),
+			},
+			value:    "9750.18",
+			currency: "USD",
+		},
+		{
+			name: "already valued",
+			payment: &Payment{
+				Status:       Completed,
+				Asset:        BTC,
+				Amount:       decimal.New(1, 0),
+				FiatValue:    decimal.New(6000, 0),
+				FiatCurrency: "USD",
+			},
+			value:    "6000",
+			currency: "USD",
+		},
+		{
+			name: "unknown price",
+			payment: &Payment{
+				Status: Completed,
+				Asset:  ETH,
+				Amount: decimal.New(1, 0),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := v.SavePayment(test.payment); err != nil {
+				t.Fatalf("unable to save payment: %v", err)
+			}
+
+			if test.payment.FiatCurrency != test.currency {
+				t.Fatalf("wrong currency, expected(%v), got(%v)",
+					test.currency, test.payment.FiatCurrency)
+			}
+
+			if test.currency != "" &&
+				test.payment.FiatValue.String() != test.value {
+				t.Fatalf("wrong value, expected(%v), got(%v)",
+					test.value, test.payment.FiatValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package connectors
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// WatchAddress is the address which doesn't belong to the connector, but
+// which activity we are interested in, e.g. old legacy wallets or
+// addresses of the attacker. Addresses are united in the groups, so that
+// the listener could distinguish them.
+type WatchAddress struct {
+	// Group is the label of the group to which address belongs.
+	Group string
+
+	// Asset is an acronym of the crypto currency.
+	Asset Asset
+
+	// Address is the blockchain address in the canonical form.
+	Address string
+
+	// Account is the identificator of the external account to which
+	// address is mapped, e.g. account of the partner which generates
+	// addresses on its own side.
+	Account string
+
+	// Inactive denotes that address is kept in the store, but its
+	// activity doesn't produce watch events.
+	Inactive bool
+}
+
+// WatchEvent is the on-chain activity which touches the watched address.
+type WatchEvent struct {
+	// EventID is the unique identificator of the event, which is used to
+	// avoid notifying about the same activity twice.
+	EventID string
+
+	// CreatedAt denotes the time when event has been detected.
+	CreatedAt int64
+
+	// Group is the label of the group to which watched address belongs.
+	Group string
+
+	// Asset is an acronym of the crypto currency.
+	Asset Asset
+
+	// Address is the watched address which was touched.
+	Address string
+
+	// Account is the external account to which watched address is mapped.
+	Account string
+
+	// Direction denotes whether funds were received or sent by the watched
+	// address.
+	Direction PaymentDirection
+
+	// Amount is the number of funds which were moved.
+	Amount decimal.Decimal
+
+	// TxID is the identificator of the transaction in the blockchain.
+	TxID string
+
+	// Redelivery denotes that event has been already delivered, and it is
+	// sent again on the request of the operator, e.g. after the outage of
+	// the listener. Listener should deduplicate it by the event id.
+	Redelivery bool
+}
+
+// GenEventID generates unique string based on the transaction, address,
+// and direction of the activity.
+func (e *WatchEvent) GenEventID() string {
+	return GeneratePaymentID(e.TxID, e.Address, string(e.Direction), e.Group)
+}
+
+// WatchGroupLabel is the metadata label of the received payment which
+// carries the group of the funded watched address.
+const WatchGroupLabel = "watch_group"
+
+// ReceivedPayment returns the payment which is published to the payments
+// subscribers when the watched address is funded. Funds don't belong to
+// us, that is why payment is never saved in the store, and it is labeled
+// with the group of the address, so that subscribers could distinguish it
+// from our own deposits.
+func (e *WatchEvent) ReceivedPayment(status PaymentStatus,
+	confirmations, confirmationsLeft int64) *Payment {
+	payment := &Payment{
+		PaymentID: e.EventID,
+		UpdatedAt: NowInMilliSeconds(),
+		Status:    status,
+		Direction: Incoming,
+		System:    External,
+		Receipt:   e.Address,
+		Asset:     e.Asset,
+		Account:   e.Account,
+		Media:     Blockchain,
+		Amount:    e.Amount,
+		MediaFee:  decimal.Zero,
+		MediaID:   e.TxID,
+		Metadata:  map[string]string{WatchGroupLabel: e.Group},
+	}
+
+	if status == Pending {
+		payment.Detail = &BlockchainPendingDetails{
+			Confirmations:     confirmations,
+			ConfirmationsLeft: confirmationsLeft,
+		}
+	}
+
+	return payment
+}
+
+// WatchNotifier is used to deliver watch events to the external listeners.
+type WatchNotifier interface {
+	// NotifyWatchEvent sends the event to the listener.
+	NotifyWatchEvent(event *WatchEvent) error
+}
+
+// ProcessWatchEvent saves the event as undelivered if it hasn't been seen
+// previously and notifies the listener about it. Notifier is optional, in
+// this case event is only stored. Notifier is called on the sync path of
+// the connector, so it shouldn't block, delivery itself should be done in
+// the background by reading undelivered events from the store, failed
+// notification is not returned as an error. Returns true if event is new.
+func ProcessWatchEvent(store WatchStore, notifier WatchNotifier,
+	event *WatchEvent) (bool, error) {
+	event.EventID = event.GenEventID()
+
+	exist, err := store.WatchEventExists(event.EventID)
+	if err != nil {
+		return false, err
+	}
+
+	if exist {
+		return false, nil
+	}
+
+	if err := store.SaveWatchEvent(event); err != nil {
+		return false, err
+	}
+
+	if notifier == nil {
+		return true, nil
+	}
+
+	// Event is stored as undelivered, so it will be redelivered later
+	// in case of failure.
+	notifier.NotifyWatchEvent(event)
+	return true, nil
+}
@@ -0,0 +1,16 @@
+package connectors
+
+// WithdrawalPause is the pause of the outgoing payments of the asset, which
+// is set by the operator during the incident, so that funds don't leave
+// the wallet until the cause is found.
+type WithdrawalPause struct {
+	// Asset is the asset which outgoing payments are paused.
+	Asset Asset
+
+	// Reason is the description of the pause, which is returned to the
+	// senders of the rejected payments.
+	Reason string
+
+	// PausedAt is the time of the pause in milliseconds.
+	PausedAt int64
+}
@@ -0,0 +1,659 @@
+package crpc
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"math/rand"
+	"sort"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+// accountBalanceDelta returns the change of the account balance which is
+// made by the payment. Incoming payment is credited only once it is
+// completed, and isn't credited while it is quarantined or if it has been
+// returned. Outgoing payment along with its fee is debited as soon as it
+// is sent, unless it has failed.
+func accountBalanceDelta(payment *connectors.Payment) decimal.Decimal {
+	switch payment.Direction {
+	case connectors.Incoming:
+		if payment.Status == connectors.Completed &&
+			payment.Quarantine != connectors.Quarantined &&
+			payment.Quarantine != connectors.QuarantineReturned {
+			return payment.Amount
+		}
+	case connectors.Outgoing:
+		if payment.Status != connectors.Failed {
+			return payment.Amount.Add(payment.MediaFee).Neg()
+		}
+	}
+
+	return decimal.Zero
+}
+
+// accountBalance returns the balance of the account in the given asset and
+// media, which is calculated from the payments of the account.
+func (s *Server) accountBalance(account string, asset connectors.Asset,
+	media connectors.PaymentMedia) (decimal.Decimal, error) {
+
+	balance := decimal.Zero
+	query := connectors.PaymentsQuery{
+		AccountID: account,
+		Asset:     asset,
+		Media:     media,
+	}
+
+	_, err := s.walkPayments(query, func(payment *connectors.Payment) error {
+		balance = balance.Add(accountBalanceDelta(payment))
+		return nil
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return balance, nil
+}
+
+// checkAccountFunds returns error if the balance of the account doesn't
+// cover the payment along with its estimated fee. Caller should hold the
+// transfer mutex until the payment is attached to the account, so that
+// concurrent sends and transfers couldn't overdraw it.
+func (s *Server) checkAccountFunds(ctx context.Context, account string,
+	req *EstimateFeeRequest) error {
+
+	amount := decimal.Zero
+	if req.Amount != "" {
+		var err error
+		amount, err = decimal.NewFromString(req.Amount)
+		if err != nil {
+			return newErrInvalidArgument("amount")
+		}
+	}
+
+	estimation, err := s.EstimateFee(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	fee, err := decimal.NewFromString(estimation.MediaFee)
+	if err != nil {
+		return newErrInternal(err.Error())
+	}
+
+	asset, err := ConvertAssetFromProto(req.Asset)
+	if err != nil {
+		return newErrInternal(err.Error())
+	}
+
+	media, err := ConvertMediaFromProto(req.Media)
+	if err != nil {
+		return newErrInternal(err.Error())
+	}
+
+	stop := trackStage(ctx, stageDB)
+	balance, err := s.accountBalance(account, asset, media)
+	stop()
+	if err != nil {
+		return newErrInternal(err.Error())
+	}
+
+	if amount.Add(fee).GreaterThan(balance) {
+		log.Errorf("Amount(%v) along with fee(%v) exceeds balance(%v) of "+
+			"account(%v)", amount, fee, balance, account)
+		return newErrInvalidArgument("amount")
+	}
+
+	return nil
+}
+
+// maxAccountNameLength is the maximum length of the name of the account.
+const maxAccountNameLength = 256
+
+// accountBalanceKey is the asset and media of the account balance.
+type accountBalanceKey struct {
+	asset connectors.Asset
+	media connectors.PaymentMedia
+}
+
+// accountBalances returns the balances of the account in every asset and
+// media in which it has payments, ordered by the asset and media. Pending
+// funds are the incoming payments which are neither completed nor failed,
+// quarantined funds are the completed incoming payments which are held by
+// the compliance review.
+func (s *Server) accountBalances(account string) ([]*Balance, error) {
+	type balance struct {
+		available, pending, quarantined decimal.Decimal
+	}
+
+	balances := make(map[accountBalanceKey]*balance)
+	query := connectors.PaymentsQuery{
+		AccountID: account,
+	}
+
+	_, err := s.walkPayments(query, func(payment *connectors.Payment) error {
+		key := accountBalanceKey{
+			asset: payment.Asset,
+			media: payment.Media,
+		}
+
+		b, ok := balances[key]
+		if !ok {
+			b = &balance{
+				available:   decimal.Zero,
+				pending:     decimal.Zero,
+				quarantined: decimal.Zero,
+			}
+			balances[key] = b
+		}
+
+		b.available = b.available.Add(accountBalanceDelta(payment))
+
+		if payment.Direction != connectors.Incoming {
+			return nil
+		}
+
+		switch {
+		case payment.Status == connectors.Completed &&
+			payment.Quarantine == connectors.Quarantined:
+			b.quarantined = b.quarantined.Add(payment.Amount)
+		case payment.Status != connectors.Completed &&
+			payment.Status != connectors.Failed:
+			b.pending = b.pending.Add(payment.Amount)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]accountBalanceKey, 0, len(balances))
+	for key := range balances {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].asset != keys[j].asset {
+			return keys[i].asset < keys[j].asset
+		}
+		return keys[i].media < keys[j].media
+	})
+
+	resp := make([]*Balance, 0, len(keys))
+	for _, key := range keys {
+		asset, err := convertAssetToProto(key.asset)
+		if err != nil {
+			return nil, err
+		}
+
+		media, err := convertMediaToProto(key.media)
+		if err != nil {
+			return nil, err
+		}
+
+		b := balances[key]
+		resp = append(resp, &Balance{
+			Asset:       asset,
+			Media:       media,
+			Available:   b.available.String(),
+			Pending:     b.pending.String(),
+			Quarantined: b.quarantined.String(),
+		})
+	}
+
+	return resp, nil
+}
+
+// createAccount validates the request and registers the account on behalf
+// of the caller.
+func (s *Server) createAccount(ctx context.Context,
+	req *CreateAccountRequest) (*Account, error) {
+
+	if req.Account == "" || !isValidAccount(req.Account) {
+		return nil, newErrInvalidArgument("account")
+	}
+
+	if len(req.Name) > maxAccountNameLength {
+		return nil, newErrInvalidArgument("name")
+	}
+
+	account := &connectors.Account{
+		ID:        req.Account,
+		Name:      req.Name,
+		Tenant:    apiKeyIDFromContext(ctx),
+		CreatedAt: connectors.NowInMilliSeconds(),
+	}
+
+	stop := trackStage(ctx, stageDB)
+	err := s.accountsStore.CreateAccount(account)
+	stop()
+	switch {
+	case err == connectors.AccountExists:
+		return nil, newErrInvalidArgument("account")
+	case err != nil:
+		return nil, newErrInternal(err.Error())
+	}
+
+	return &Account{
+		Account:   account.ID,
+		Name:      account.Name,
+		CreatedAt: account.CreatedAt,
+	}, nil
+}
+
+// listAccounts returns the accounts registered by the caller, callers
+// which are not authorized by the API key see all accounts.
+func (s *Server) listAccounts(ctx context.Context,
+	req *ListAccountsRequest) (*ListAccountsResponse, error) {
+
+	stop := trackStage(ctx, stageDB)
+	accounts, err := s.accountsStore.ListAccounts(apiKeyIDFromContext(ctx))
+	stop()
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	resp := &ListAccountsResponse{}
+	for _, account := range accounts {
+		protoAccount := &Account{
+			Account:   account.ID,
+			Name:      account.Name,
+			CreatedAt: account.CreatedAt,
+		}
+
+		if req.IncludeBalances {
+			stop := trackStage(ctx, stageDB)
+			protoAccount.Balances, err = s.accountBalances(account.ID)
+			stop()
+			if err != nil {
+				return nil, newErrInternal(err.Error())
+			}
+		}
+
+		resp.Accounts = append(resp.Accounts, protoAccount)
+	}
+
+	return resp, nil
+}
+
+// checkAccountTenant returns error if account has been registered by
+// another tenant than the caller, so that it isn't accessible to the
+// caller. Callers which are not authorized by the API key, accounts which
+// are not registered, and requests without the account are not restricted.
+func (s *Server) checkAccountTenant(ctx context.Context, id string) error {
+	tenant := apiKeyIDFromContext(ctx)
+	if tenant == "" || id == "" {
+		return nil
+	}
+
+	stop := trackStage(ctx, stageDB)
+	account, err := s.accountsStore.AccountByID(id)
+	stop()
+	switch {
+	case err == connectors.AccountNotFound:
+		return nil
+	case err != nil:
+		return newErrInternal(err.Error())
+	case account.Tenant != tenant:
+		return newErrInvalidArgument("account")
+	}
+
+	return nil
+}
+
+// listDepositAddresses returns the deposit addresses of the account which
+// have been created by the caller.
+func (s *Server) listDepositAddresses(ctx context.Context,
+	req *ListDepositAddressesRequest) (*ListDepositAddressesResponse, error) {
+
+	if req.Account == "" || !isValidAccount(req.Account) {
+		return nil, newErrInvalidArgument("account")
+	}
+
+	if err := s.checkAccountTenant(ctx, req.Account); err != nil {
+		return nil, err
+	}
+
+	tenant := apiKeyIDFromContext(ctx)
+
+	stop := trackStage(ctx, stageDB)
+	addresses, err := s.receiptsStore.ListDepositAddresses(req.Account)
+	stop()
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	resp := &ListDepositAddressesResponse{}
+	for _, address := range addresses {
+		if tenant != "" && address.Tenant != tenant {
+			continue
+		}
+
+		asset, err := convertAssetToProto(address.Asset)
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		resp.Addresses = append(resp.Addresses, &DepositAddress{
+			Address:   address.Address,
+			Asset:     asset,
+			CreatedAt: address.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+// isMediaSupported returns true if server has the connector of the asset
+// in the given media.
+func (s *Server) isMediaSupported(asset connectors.Asset,
+	media connectors.PaymentMedia) bool {
+	switch media {
+	case connectors.Blockchain:
+		_, ok := s.blockchainConnectors[asset]
+		return ok
+	case connectors.Lightning:
+		_, ok := s.lightningConnectors[asset]
+		return ok
+	default:
+		return false
+	}
+}
+
+// newTransferPayments validates the request and creates the completed
+// internal payments of the transfer, outgoing one of the source account
+// and incoming one of the destination account. Both payments have the
+// same random media id and receipt, so that they are recognized as the
+// two sides of the same internal payment.
+func newTransferPayments(req *TransferFundsRequest) (*connectors.Payment,
+	*connectors.Payment, error) {
+	if req.FromAccount == "" || !isValidAccount(req.FromAccount) {
+		return nil, nil, newErrInvalidArgument("from_account")
+	}
+
+	if req.ToAccount == "" || !isValidAccount(req.ToAccount) ||
+		req.ToAccount == req.FromAccount {
+		return nil, nil, newErrInvalidArgument("to_account")
+	}
+
+	if req.Asset == Asset_ASSET_NONE {
+		return nil, nil, newErrInvalidArgument("asset")
+	}
+
+	asset, err := ConvertAssetFromProto(req.Asset)
+	if err != nil {
+		return nil, nil, newErrInvalidArgument("asset")
+	}
+
+	if req.Media == Media_MEDIA_NONE {
+		return nil, nil, newErrInvalidArgument("media")
+	}
+
+	media, err := ConvertMediaFromProto(req.Media)
+	if err != nil {
+		return nil, nil, newErrInvalidArgument("media")
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || amount.Sign() <= 0 {
+		return nil, nil, newErrInvalidArgument("amount")
+	}
+
+	if !isValidMetadata(req.Metadata) {
+		return nil, nil, newErrInvalidArgument("metadata")
+	}
+
+	var mediaID [16]byte
+	if _, err := crand.Read(mediaID[:]); err != nil {
+		return nil, nil, newErrInternal(err.Error())
+	}
+
+	debit := &connectors.Payment{
+		UpdatedAt: connectors.NowInMilliSeconds(),
+		Status:    connectors.Completed,
+		Direction: connectors.Outgoing,
+		System:    connectors.Internal,
+		Receipt:   req.ToAccount,
+		Asset:     asset,
+		Media:     media,
+		Amount:    amount,
+		MediaFee:  decimal.Zero,
+		MediaID:   "transfer-" + hex.EncodeToString(mediaID[:]),
+		Memo:      req.Memo,
+		AccountID: req.FromAccount,
+		Metadata:  connectors.MergeLabels(nil, req.Metadata),
+	}
+
+	credit := *debit
+	credit.Direction = connectors.Incoming
+	credit.AccountID = req.ToAccount
+	credit.Metadata = connectors.MergeLabels(nil, req.Metadata)
+
+	if debit.PaymentID, err = debit.GenPaymentID(); err != nil {
+		return nil, nil, newErrInternal(err.Error())
+	}
+
+	if credit.PaymentID, err = credit.GenPaymentID(); err != nil {
+		return nil, nil, newErrInternal(err.Error())
+	}
+
+	return debit, &credit, nil
+}
+
+//
+// TransferFunds moves funds between the accounts of the ledger without
+// the blockchain transaction and the fee. Transfer is recorded as the
+// pair of internal payments, outgoing one of the source account and
+// incoming one of the destination account.
+func (s *Server) TransferFunds(ctx context.Context,
+	req *TransferFundsRequest) (*TransferFundsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	debit, credit, err := newTransferPayments(req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Transfer doesn't touch the media, but balance of the asset which
+	// isn't served couldn't be withdrawn, so it isn't accepted either.
+	if !s.isMediaSupported(debit.Asset, debit.Media) {
+		err := newErrAssetNotSupported(req.Asset.String(), req.Media.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if err := s.limitCall(ctx, "TransferFunds", req.Asset, 1); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		return nil, err
+	}
+
+	// Both accounts should be accessible to the caller, otherwise funds
+	// could be moved out of or into the account of another tenant.
+	for _, id := range []string{debit.AccountID, credit.AccountID} {
+		if err := s.checkAccountTenant(ctx, id); err != nil {
+			log.Errorf("command(%v), id(%v), error: %v, account(%v) isn't "+
+				"accessible", common.GetFunctionName(), requestID, err, id)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+	}
+
+	// Transfers are serialized, so that concurrent transfers from the same
+	// account couldn't overdraw it.
+	s.transferMtx.Lock()
+	defer s.transferMtx.Unlock()
+
+	stop := trackStage(ctx, stageDB)
+	balance, err := s.accountBalance(debit.AccountID, debit.Asset,
+		debit.Media)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if debit.Amount.GreaterThan(balance) {
+		err := newErrInvalidArgument("amount")
+		log.Errorf("command(%v), id(%v), error: %v, amount(%v) exceeds "+
+			"balance(%v) of account(%v)", common.GetFunctionName(),
+			requestID, err, debit.Amount, balance, debit.AccountID)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Source account is debited first, so that failure in the middle
+	// couldn't create the funds out of thin air.
+	stop = trackStage(ctx, stageDB)
+	err = s.paymentsStore.SavePayment(debit)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop = trackStage(ctx, stageDB)
+	err = s.paymentsStore.SavePayment(credit)
+	stop()
+	if err != nil {
+		log.Errorf("command(%v), id(%v), unable to credit account(%v): %v",
+			common.GetFunctionName(), requestID, credit.AccountID, err)
+
+		// Debit is failed so that funds are returned to the source
+		// account, if even that isn't possible the operator should fix
+		// the ledger.
+		debit.Status = connectors.Failed
+		debit.FailureReason = err.Error()
+		debit.UpdatedAt = connectors.NowInMilliSeconds()
+		if err := s.paymentsStore.SavePayment(debit); err != nil {
+			log.Errorf("command(%v), id(%v), unable to revert debit(%v) "+
+				"of account(%v): %v", common.GetFunctionName(), requestID,
+				debit.PaymentID, debit.AccountID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+		} else {
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		}
+
+		return nil, newErrInternal(err.Error())
+	}
+
+	resp := &TransferFundsResponse{}
+
+	resp.Debit, err = convertPaymentToProto(debit)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp.Credit, err = convertPaymentToProto(credit)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// CreateAccount registers the account of the ledger, so that it is listed
+// by ListAccounts along with its balances. Payments could be bound to the
+// account before it is registered.
+func (s *Server) CreateAccount(ctx context.Context,
+	req *CreateAccountRequest) (*Account, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.createAccount(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Account(%v) is created", resp.Account)
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListAccounts returns the registered accounts of the caller from the
+// oldest one, optionally along with their balances which are aggregated
+// from the payments of the accounts.
+func (s *Server) ListAccounts(ctx context.Context,
+	req *ListAccountsRequest) (*ListAccountsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.listAccounts(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListDepositAddresses returns the blockchain addresses on which incoming
+// payments of the account are received, i.e. the ones which were created
+// by NewAddress and CreateReceipt, from the oldest one.
+func (s *Server) ListDepositAddresses(ctx context.Context,
+	req *ListDepositAddressesRequest) (*ListDepositAddressesResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.listDepositAddresses(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
@@ -0,0 +1,498 @@
+package crpc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/features"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+func TestTransferFunds(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	h.seedPayments(&connectors.Payment{
+		PaymentID: "deposit",
+		UpdatedAt: 1,
+		Status:    connectors.Completed,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "btc-address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.New(2, 0),
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx-1",
+		AccountID: "customer-1",
+	})
+
+	transfer := func(from, to, amount string) (*TransferFundsResponse,
+		error) {
+		return h.client.TransferFunds(ctx, &TransferFundsRequest{
+			Asset:       Asset_BTC,
+			Media:       Media_BLOCKCHAIN,
+			FromAccount: from,
+			ToAccount:   to,
+			Amount:      amount,
+			Memo:        "settlement",
+			Metadata:    map[string]string{"order_id": "1001"},
+		})
+	}
+
+	_, err := transfer("", "customer-2", "1")
+	expectInvalidArgument(t, err, "from_account")
+
+	_, err = transfer("customer-1", "customer-1", "1")
+	expectInvalidArgument(t, err, "to_account")
+
+	_, err = transfer("customer-1", "customer-2", "0")
+	expectInvalidArgument(t, err, "amount")
+
+	// Account couldn't be overdrawn.
+	_, err = transfer("customer-1", "customer-2", "2.5")
+	expectInvalidArgument(t, err, "amount")
+
+	resp, err := transfer("customer-1", "customer-2", "1.5")
+	if err != nil {
+		t.Fatalf("unable to transfer funds: %v", err)
+	}
+
+	debit, credit := resp.Debit, resp.Credit
+	if debit.Account != "customer-1" ||
+		debit.Direction != PaymentDirection_OUTGOING ||
+		credit.Account != "customer-2" ||
+		credit.Direction != PaymentDirection_INCOMING {
+		t.Fatalf("wrong transfer: %v", resp)
+	}
+
+	for _, payment := range []*Payment{debit, credit} {
+		if payment.System != PaymentSystem_INTERNAL ||
+			payment.Status != PaymentStatus_COMPLETED ||
+			payment.Amount != "1.5" || payment.MediaFee != "0" ||
+			payment.Metadata["order_id"] != "1001" {
+			t.Fatalf("wrong payment: %v", payment)
+		}
+	}
+
+	// Funds are moved only between the accounts of the ledger.
+	if h.btc.sent != 0 {
+		t.Fatalf("transfer shouldn't be sent to the media")
+	}
+
+	tests := []struct {
+		account string
+		balance string
+	}{
+		{account: "customer-1", balance: "0.5"},
+		{account: "customer-2", balance: "1.5"},
+	}
+
+	for _, test := range tests {
+		balance, err := h.server.accountBalance(test.account,
+			connectors.BTC, connectors.Blockchain)
+		if err != nil {
+			t.Fatalf("unable to get balance: %v", err)
+		}
+
+		if balance.String() != test.balance {
+			t.Fatalf("wrong balance of account(%v), expected(%v), "+
+				"got(%v)", test.account, test.balance, balance)
+		}
+	}
+
+	_, err = transfer("customer-1", "customer-2", "1")
+	expectInvalidArgument(t, err, "amount")
+
+	_, err = h.client.TransferFunds(ctx, &TransferFundsRequest{
+		Asset:       Asset_ETH,
+		Media:       Media_BLOCKCHAIN,
+		FromAccount: "customer-1",
+		ToAccount:   "customer-2",
+		Amount:      "1",
+	})
+	if err == nil {
+		t.Fatalf("transfer of unsupported asset should be rejected")
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	h.seedPayments(
+		&connectors.Payment{
+			PaymentID: "deposit",
+			UpdatedAt: 1,
+			Status:    connectors.Completed,
+			System:    connectors.External,
+			Direction: connectors.Incoming,
+			Receipt:   "btc-address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(2, 0),
+			MediaFee:  decimal.Zero,
+			MediaID:   "tx-1",
+			AccountID: "customer-1",
+		},
+		&connectors.Payment{
+			PaymentID: "unconfirmed",
+			UpdatedAt: 2,
+			Status:    connectors.Pending,
+			System:    connectors.External,
+			Direction: connectors.Incoming,
+			Receipt:   "btc-address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(3, 0),
+			MediaFee:  decimal.Zero,
+			MediaID:   "tx-2",
+			AccountID: "customer-1",
+		},
+		&connectors.Payment{
+			PaymentID: "withdrawal",
+			UpdatedAt: 3,
+			Status:    connectors.Completed,
+			System:    connectors.External,
+			Direction: connectors.Outgoing,
+			Receipt:   "external",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(5, -1),
+			MediaFee:  decimal.New(1, -1),
+			MediaID:   "tx-3",
+			AccountID: "customer-1",
+		},
+	)
+
+	_, err := h.client.CreateAccount(ctx, &CreateAccountRequest{})
+	expectInvalidArgument(t, err, "account")
+
+	_, err = h.client.CreateAccount(ctx, &CreateAccountRequest{
+		Account: "customer 1",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	// Account is registered after its payments have been received.
+	account, err := h.client.CreateAccount(ctx, &CreateAccountRequest{
+		Account: "customer-1",
+		Name:    "Alice",
+	})
+	if err != nil {
+		t.Fatalf("unable to create account: %v", err)
+	}
+
+	if account.Account != "customer-1" || account.Name != "Alice" ||
+		account.CreatedAt == 0 {
+		t.Fatalf("wrong account: %v", account)
+	}
+
+	_, err = h.client.CreateAccount(ctx, &CreateAccountRequest{
+		Account: "customer-1",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	// Account of the API key is visible only to the key.
+	merchantCtx := context.WithValue(ctx, apiKeyIDKey{}, "merchant")
+	_, err = h.server.CreateAccount(merchantCtx, &CreateAccountRequest{
+		Account: "customer-2",
+	})
+	if err != nil {
+		t.Fatalf("unable to create account: %v", err)
+	}
+
+	resp, err := h.client.ListAccounts(ctx, &ListAccountsRequest{
+		IncludeBalances: true,
+	})
+	if err != nil {
+		t.Fatalf("unable to list accounts: %v", err)
+	}
+
+	if len(resp.Accounts) != 2 {
+		t.Fatalf("wrong number of accounts: %v", resp.Accounts)
+	}
+
+	balances := resp.Accounts[0].Balances
+	if len(balances) != 1 || balances[0].Asset != Asset_BTC ||
+		balances[0].Media != Media_BLOCKCHAIN ||
+		balances[0].Available != "1.4" || balances[0].Pending != "3" {
+		t.Fatalf("wrong balances: %v", balances)
+	}
+
+	if len(resp.Accounts[1].Balances) != 0 {
+		t.Fatalf("account without payments has balances: %v",
+			resp.Accounts[1].Balances)
+	}
+
+	resp, err = h.server.ListAccounts(merchantCtx, &ListAccountsRequest{})
+	if err != nil {
+		t.Fatalf("unable to list accounts: %v", err)
+	}
+
+	if len(resp.Accounts) != 1 || resp.Accounts[0].Account != "customer-2" ||
+		resp.Accounts[0].Balances != nil {
+		t.Fatalf("wrong accounts of the tenant: %v", resp.Accounts)
+	}
+
+	address, err := h.client.NewAddress(ctx, &NewAddressRequest{
+		Asset:   Asset_BTC,
+		Account: "customer-1",
+	})
+	if err != nil {
+		t.Fatalf("unable to create address: %v", err)
+	}
+
+	addresses, err := h.client.ListDepositAddresses(ctx,
+		&ListDepositAddressesRequest{Account: "customer-1"})
+	if err != nil {
+		t.Fatalf("unable to list addresses: %v", err)
+	}
+
+	if len(addresses.Addresses) != 1 ||
+		addresses.Addresses[0].Address != address.Address ||
+		addresses.Addresses[0].Asset != Asset_BTC {
+		t.Fatalf("wrong addresses: %v", addresses.Addresses)
+	}
+
+	_, err = h.server.ListDepositAddresses(merchantCtx,
+		&ListDepositAddressesRequest{Account: "customer-1"})
+	expectInvalidArgument(t, err, "account")
+
+	_, err = h.client.ListDepositAddresses(ctx,
+		&ListDepositAddressesRequest{})
+	expectInvalidArgument(t, err, "account")
+}
+
+func TestAccountTenantIsolation(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+	aliceCtx := context.WithValue(ctx, apiKeyIDKey{}, "alice")
+	bobCtx := context.WithValue(ctx, apiKeyIDKey{}, "bob")
+
+	for account, ctx := range map[string]context.Context{
+		"alice-1": aliceCtx,
+		"bob-1":   bobCtx,
+	} {
+		_, err := h.server.CreateAccount(ctx, &CreateAccountRequest{
+			Account: account,
+		})
+		if err != nil {
+			t.Fatalf("unable to create account: %v", err)
+		}
+	}
+
+	for _, account := range []string{"alice-1", "bob-1"} {
+		h.seedPayments(&connectors.Payment{
+			PaymentID: "deposit-" + account,
+			UpdatedAt: 1,
+			Status:    connectors.Completed,
+			System:    connectors.External,
+			Direction: connectors.Incoming,
+			Receipt:   "btc-address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(2, 0),
+			MediaFee:  decimal.Zero,
+			MediaID:   "tx-" + account,
+			AccountID: account,
+		})
+	}
+
+	// Funds of another tenant couldn't be moved out of its account, nor
+	// into it.
+	_, err := h.server.TransferFunds(aliceCtx, &TransferFundsRequest{
+		Asset:       Asset_BTC,
+		Media:       Media_BLOCKCHAIN,
+		FromAccount: "bob-1",
+		ToAccount:   "alice-1",
+		Amount:      "1",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	_, err = h.server.TransferFunds(aliceCtx, &TransferFundsRequest{
+		Asset:       Asset_BTC,
+		Media:       Media_BLOCKCHAIN,
+		FromAccount: "alice-1",
+		ToAccount:   "bob-1",
+		Amount:      "1",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	_, err = h.server.SendPayment(aliceCtx, &SendPaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "btc-recipient",
+		Amount:  "1",
+		Account: "bob-1",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	_, err = h.server.SendPayments(aliceCtx, &SendPaymentsRequest{
+		Asset: Asset_BTC,
+		Outputs: []*PaymentOutput{{
+			Receipt: "btc-recipient",
+			Amount:  "1",
+		}},
+		Account: "bob-1",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	_, err = h.server.ListPayments(aliceCtx, &ListPaymentsRequest{
+		Account: "bob-1",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	if h.btc.sent != 0 {
+		t.Fatalf("payment from account of another tenant has been sent")
+	}
+
+	// Deposits couldn't be credited to the account of another tenant.
+	_, err = h.server.CreateReceipt(aliceCtx, &CreateReceiptRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Account: "bob-1",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	_, err = h.server.NewAddress(aliceCtx, &NewAddressRequest{
+		Asset:   Asset_BTC,
+		Account: "bob-1",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	_, err = h.server.CreateReceipt(aliceCtx, &CreateReceiptRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Account: "alice-1",
+	})
+	if err != nil {
+		t.Fatalf("unable to create receipt: %v", err)
+	}
+
+	// Own account is still accessible.
+	resp, err := h.server.ListPayments(aliceCtx, &ListPaymentsRequest{
+		Account: "alice-1",
+	})
+	if err != nil {
+		t.Fatalf("unable to list payments: %v", err)
+	}
+
+	if len(resp.Payments) != 1 || resp.Payments[0].Account != "alice-1" {
+		t.Fatalf("wrong payments of the account: %v", resp.Payments)
+	}
+}
+
+// batchingConnector is the mock connector which is able to send payments
+// to the several outputs at once.
+type batchingConnector struct {
+	*mockBlockchainConnector
+}
+
+func (c *batchingConnector) SendPayments(tenant string,
+	outputs []*connectors.PaymentOutput) ([]*connectors.Payment, error) {
+	var payments []*connectors.Payment
+	for _, output := range outputs {
+		payment, err := c.SendPayment(output.Address, output.Amount, "")
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
+
+func TestAccountSendFunds(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	h.server.blockchainConnectors[connectors.BTC] = &batchingConnector{
+		mockBlockchainConnector: h.btc,
+	}
+	err := h.server.features.Set(features.Batching, features.Rule{
+		Enabled: true,
+	})
+	if err != nil {
+		t.Fatalf("unable to enable batching: %v", err)
+	}
+
+	h.seedPayments(&connectors.Payment{
+		PaymentID: "deposit",
+		UpdatedAt: 1,
+		Status:    connectors.Completed,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "btc-address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.New(2, 0),
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx-1",
+		AccountID: "customer-1",
+	})
+
+	send := func(amount string) (*Payment, error) {
+		return h.client.SendPayment(ctx, &SendPaymentRequest{
+			Asset:   Asset_BTC,
+			Media:   Media_BLOCKCHAIN,
+			Receipt: "btc-recipient",
+			Amount:  amount,
+			Account: "customer-1",
+		})
+	}
+
+	sendBatch := func(amounts ...string) (*SendPaymentsResponse, error) {
+		req := &SendPaymentsRequest{
+			Asset:   Asset_BTC,
+			Account: "customer-1",
+		}
+		for i, amount := range amounts {
+			req.Outputs = append(req.Outputs, &PaymentOutput{
+				Receipt: fmt.Sprintf("btc-recipient-%v", i),
+				Amount:  amount,
+			})
+		}
+
+		return h.client.SendPayments(ctx, req)
+	}
+
+	// Fee is debited from the account along with the amount.
+	_, err = send("2")
+	expectInvalidArgument(t, err, "amount")
+
+	if _, err := send("1.5"); err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	_, err = sendBatch("0.3", "0.2")
+	expectInvalidArgument(t, err, "amount")
+
+	if _, err := sendBatch("0.3", "0.1"); err != nil {
+		t.Fatalf("unable to send payments: %v", err)
+	}
+
+	if h.btc.sent != 3 {
+		t.Fatalf("rejected payments shouldn't be sent: %v", h.btc.sent)
+	}
+
+	// Payment without the account isn't limited by the account balance.
+	_, err = h.client.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "btc-recipient",
+		Amount:  "1",
+	})
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+}
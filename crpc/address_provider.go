@@ -0,0 +1,100 @@
+package crpc
+
+import (
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// RemoteAddressProvider requests deposit addresses from the external
+// address provision service over the AddressProvider gRPC contract.
+type RemoteAddressProvider struct {
+	client  AddressProviderClient
+	network string
+	timeout time.Duration
+}
+
+// Runtime check to ensure that RemoteAddressProvider implements
+// connectors.AddressProvider interface.
+var _ connectors.AddressProvider = (*RemoteAddressProvider)(nil)
+
+// NewRemoteAddressProvider creates new instance of the provider, which
+// requests addresses of the given network over the connection. Request
+// which hasn't been answered in time is failed.
+func NewRemoteAddressProvider(conn *grpc.ClientConn, network string,
+	timeout time.Duration) *RemoteAddressProvider {
+	return &RemoteAddressProvider{
+		client:  NewAddressProviderClient(conn),
+		network: network,
+		timeout: timeout,
+	}
+}
+
+// ProvideAddress returns new deposit address of the asset.
+//
+// NOTE: Part of the connectors.AddressProvider interface.
+func (p *RemoteAddressProvider) ProvideAddress(asset connectors.Asset,
+	tenant string) (string, error) {
+	protoAsset, err := convertAssetToProto(asset)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	resp, err := p.client.ProvideAddress(ctx, &ProvideAddressRequest{
+		Asset:   protoAsset,
+		Network: p.network,
+		Tenant:  tenant,
+	})
+	if err != nil {
+		return "", errors.Errorf("unable to request address: %v", err)
+	}
+
+	if resp.Address == "" {
+		return "", errors.New("provider returned empty address")
+	}
+
+	return resp.Address, nil
+}
+
+// createAddress returns new deposit address of the asset, which is
+// requested from the address provider if it is set, otherwise it is
+// created by the connector.
+func (s *Server) createAddress(ctx context.Context,
+	c connectors.BlockchainConnector, asset connectors.Asset) (string, error) {
+	if s.addressProvider == nil {
+		return c.CreateAddress()
+	}
+
+	importer, ok := c.(connectors.DepositAddressImporter)
+	if !ok {
+		return "", errors.Errorf("connector of %v is unable to track "+
+			"provided addresses", asset)
+	}
+
+	address, err := s.addressProvider.ProvideAddress(asset,
+		apiKeyIDFromContext(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	// Provider is out of our control, that is why address is checked to
+	// belong to our network, otherwise deposits on it would be lost.
+	info, err := c.ValidateAddress(address)
+	if err != nil {
+		return "", errors.Errorf("provided address(%v) is invalid: %v",
+			address, err)
+	}
+
+	if err := importer.ImportDepositAddress(info.Address); err != nil {
+		return "", errors.Errorf("unable to import provided address(%v): %v",
+			info.Address, err)
+	}
+
+	return info.Address, nil
+}
@@ -0,0 +1,100 @@
+package crpc
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// stubAddressProvider is the address provision service which derives
+// sequential addresses and remembers the requests.
+type stubAddressProvider struct {
+	requests []*ProvideAddressRequest
+}
+
+func (p *stubAddressProvider) ProvideAddress(ctx context.Context,
+	req *ProvideAddressRequest) (*ProvideAddressResponse, error) {
+	p.requests = append(p.requests, req)
+
+	return &ProvideAddressResponse{
+		Address: fmt.Sprintf("delegated-%v", len(p.requests)),
+	}, nil
+}
+
+// importingConnector is the mock connector which is able to track the
+// provided addresses.
+type importingConnector struct {
+	*mockBlockchainConnector
+
+	imported []string
+}
+
+func (c *importingConnector) ImportDepositAddress(address string) error {
+	c.imported = append(c.imported, address)
+	return nil
+}
+
+func TestCreateReceiptDelegated(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	provider := &stubAddressProvider{}
+
+	listener := bufconn.Listen(bufSize)
+	providerServer := grpc.NewServer()
+	RegisterAddressProviderServer(providerServer, provider)
+	go providerServer.Serve(listener)
+	defer providerServer.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		t.Fatalf("unable to connect to provider: %v", err)
+	}
+	defer conn.Close()
+
+	h.server.addressProvider = NewRemoteAddressProvider(conn, "simnet",
+		time.Second)
+
+	ctx := context.Background()
+	req := &CreateReceiptRequest{
+		Asset: Asset_BTC,
+		Media: Media_BLOCKCHAIN,
+	}
+
+	// Address shouldn't be handed out if deposits on it couldn't be
+	// tracked.
+	if _, err := h.client.CreateReceipt(ctx, req); err == nil {
+		t.Fatalf("receipt shouldn't be created by connector which is " +
+			"unable to import addresses")
+	}
+
+	btc := &importingConnector{mockBlockchainConnector: h.btc}
+	h.server.blockchainConnectors[connectors.BTC] = btc
+
+	resp, err := h.client.CreateReceipt(ctx, req)
+	if err != nil {
+		t.Fatalf("unable to create receipt: %v", err)
+	}
+
+	if resp.Receipt != "delegated-1" {
+		t.Fatalf("receipt should be provided, got: %v", resp.Receipt)
+	}
+
+	if len(btc.imported) != 1 || btc.imported[0] != "delegated-1" {
+		t.Fatalf("provided address isn't imported: %v", btc.imported)
+	}
+
+	last := provider.requests[len(provider.requests)-1]
+	if last.Asset != Asset_BTC || last.Network != "simnet" {
+		t.Fatalf("wrong provider request: %v", last)
+	}
+}
@@ -0,0 +1,317 @@
+package crpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"sort"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/identity"
+	"github.com/bitlum/connector/metrics"
+	"golang.org/x/net/context"
+)
+
+// addressBookVersion is the version of the address book bundle format.
+const addressBookVersion = 1
+
+// addressBook is the JSON bundle of the payee presets and the watched
+// addresses. Assets and media are named the same way for all chains, e.g.
+// "BTC" and "Blockchain".
+type addressBook struct {
+	Version        int                    `json:"version"`
+	CreatedAt      int64                  `json:"created_at"`
+	Payees         []*addressBookPayee    `json:"payees"`
+	WatchAddresses []*addressBookWatching `json:"watch_addresses"`
+}
+
+type addressBookPayee struct {
+	Name    string `json:"name"`
+	Asset   string `json:"asset"`
+	Media   string `json:"media"`
+	Receipt string `json:"receipt"`
+	Amount  string `json:"amount,omitempty"`
+}
+
+type addressBookWatching struct {
+	Group    string `json:"group"`
+	Asset    string `json:"asset"`
+	Address  string `json:"address"`
+	Account  string `json:"account,omitempty"`
+	Inactive bool   `json:"inactive,omitempty"`
+}
+
+// signedAddressBookPayload returns the data which is signed for the bundle,
+// it is the bundle in the compact JSON form, so that bundle could be
+// reformatted without breaking the signature.
+func signedAddressBookPayload(bundle string) ([]byte, error) {
+	var payload bytes.Buffer
+	if err := json.Compact(&payload, []byte(bundle)); err != nil {
+		return nil, err
+	}
+
+	return payload.Bytes(), nil
+}
+
+// exportAddressBook encodes the payee presets and the watched addresses in
+// the bundle ordered by their names and addresses, so that bundles of the
+// different environments could be compared line by line.
+func (s *Server) exportAddressBook(ctx context.Context) (*AddressBook,
+	error) {
+
+	if s.identityKey == nil {
+		return nil, newErrMethodDisabled("ExportAddressBook",
+			"identitykeypath")
+	}
+
+	stop := trackStage(ctx, stageDB)
+	payees, err := s.payeesStore.ListPayees()
+	stop()
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	stop = trackStage(ctx, stageDB)
+	addresses, err := s.watchStore.ListWatchAddresses("", "")
+	stop()
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	book := &addressBook{
+		Version:        addressBookVersion,
+		CreatedAt:      connectors.NowInMilliSeconds(),
+		Payees:         []*addressBookPayee{},
+		WatchAddresses: []*addressBookWatching{},
+	}
+
+	for _, payee := range payees {
+		entry := &addressBookPayee{
+			Name:    payee.Name,
+			Asset:   string(payee.Asset),
+			Media:   string(payee.Media),
+			Receipt: payee.Receipt,
+		}
+		if !payee.Amount.IsZero() {
+			entry.Amount = payee.Amount.String()
+		}
+
+		book.Payees = append(book.Payees, entry)
+	}
+
+	for _, address := range addresses {
+		book.WatchAddresses = append(book.WatchAddresses,
+			&addressBookWatching{
+				Group:    address.Group,
+				Asset:    string(address.Asset),
+				Address:  address.Address,
+				Account:  address.Account,
+				Inactive: address.Inactive,
+			})
+	}
+
+	sort.Slice(book.Payees, func(i, j int) bool {
+		return book.Payees[i].Name < book.Payees[j].Name
+	})
+
+	sort.Slice(book.WatchAddresses, func(i, j int) bool {
+		a, b := book.WatchAddresses[i], book.WatchAddresses[j]
+		if a.Asset != b.Asset {
+			return a.Asset < b.Asset
+		}
+		return a.Address < b.Address
+	})
+
+	bundle, err := json.MarshalIndent(book, "", "  ")
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	payload, err := signedAddressBookPayload(string(bundle))
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	signature, err := s.identityKey.Sign(payload)
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	return &AddressBook{
+		Bundle:    string(bundle),
+		Signature: signature,
+		KeyId:     s.identityKey.ID(),
+		PublicKey: hex.EncodeToString(s.identityKey.PublicKey()),
+	}, nil
+}
+
+// decodeAddressBook verifies the signature of the bundle and decodes it.
+func decodeAddressBook(req *AddressBook) (*addressBook, error) {
+	publicKey, err := hex.DecodeString(req.PublicKey)
+	if err != nil || len(publicKey) == 0 {
+		return nil, newErrInvalidArgument("public_key")
+	}
+
+	if req.KeyId != identity.KeyID(publicKey) {
+		return nil, newErrInvalidArgument("key_id")
+	}
+
+	payload, err := signedAddressBookPayload(req.Bundle)
+	if err != nil {
+		return nil, newErrInvalidArgument("bundle")
+	}
+
+	if err := identity.Verify(publicKey, payload, req.Signature); err != nil {
+		return nil, newErrInvalidArgument("signature")
+	}
+
+	book := &addressBook{}
+	if err := json.Unmarshal(payload, book); err != nil {
+		return nil, newErrInvalidArgument("bundle")
+	}
+
+	if book.Version != addressBookVersion ||
+		len(book.WatchAddresses) > maxImportWatchAddresses {
+		return nil, newErrInvalidArgument("bundle")
+	}
+
+	return book, nil
+}
+
+// importAddressBook imports the payee presets and the watched addresses of
+// the signed bundle. Payees are validated before the addresses are
+// imported, and addresses are validated by the import before anything is
+// watched, so that invalid entry doesn't leave the bundle imported
+// partially.
+func (s *Server) importAddressBook(ctx context.Context,
+	req *AddressBook) (*ImportAddressBookResponse, error) {
+
+	book, err := decodeAddressBook(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var payees []*connectors.Payee
+	for _, entry := range book.Payees {
+		asset, err := convertAssetToProto(connectors.Asset(entry.Asset))
+		if err != nil {
+			return nil, newErrInvalidArgument("bundle")
+		}
+
+		media, err := convertMediaToProto(connectors.PaymentMedia(entry.Media))
+		if err != nil {
+			return nil, newErrInvalidArgument("bundle")
+		}
+
+		payee, err := s.newPayee(ctx, &Payee{
+			Name:    entry.Name,
+			Asset:   asset,
+			Media:   media,
+			Receipt: entry.Receipt,
+			Amount:  entry.Amount,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		payees = append(payees, payee)
+	}
+
+	resp := &ImportAddressBookResponse{
+		WatchAddresses: &ImportWatchAddressesResponse{},
+	}
+
+	if len(book.WatchAddresses) != 0 {
+		watchReq := &ImportWatchAddressesRequest{}
+		for _, entry := range book.WatchAddresses {
+			asset, err := convertAssetToProto(connectors.Asset(entry.Asset))
+			if err != nil {
+				return nil, newErrInvalidArgument("bundle")
+			}
+
+			watchReq.Addresses = append(watchReq.Addresses, &WatchAddress{
+				Group:    entry.Group,
+				Asset:    asset,
+				Address:  entry.Address,
+				Account:  entry.Account,
+				Inactive: entry.Inactive,
+			})
+		}
+
+		resp.WatchAddresses, err = s.ImportWatchAddresses(ctx, watchReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, payee := range payees {
+		stop := trackStage(ctx, stageDB)
+		err := s.payeesStore.SavePayee(payee)
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		resp.Payees++
+	}
+
+	return resp, nil
+}
+
+//
+// ExportAddressBook returns the payee presets and the watched addresses as
+// the JSON bundle signed by the server identity key, so that the whole
+// destination surface could be reviewed and migrated to another
+// environment.
+func (s *Server) ExportAddressBook(ctx context.Context,
+	req *EmptyRequest) (*AddressBook, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.exportAddressBook(ctx)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ImportAddressBook verifies the signature of the bundle exported by
+// ExportAddressBook and imports its payee presets and watched addresses.
+// Entries are validated before anything is imported, existing entries are
+// updated, so that import could be repeated.
+func (s *Server) ImportAddressBook(ctx context.Context,
+	req *AddressBook) (*ImportAddressBookResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.importAddressBook(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Address book signed by key(%v) is imported: %v payees, "+
+		"%v added and %v updated watched addresses", req.KeyId, resp.Payees,
+		resp.WatchAddresses.Added, resp.WatchAddresses.Updated)
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
@@ -0,0 +1,132 @@
+package crpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitlum/connector/identity"
+	"golang.org/x/net/context"
+)
+
+func TestAddressBook(t *testing.T) {
+	source := newTestHarness(t)
+	defer source.stop()
+
+	target := newTestHarness(t)
+	defer target.stop()
+
+	ctx := context.Background()
+
+	// Bundle couldn't be signed without the identity key.
+	if _, err := source.admin.ExportAddressBook(ctx, &EmptyRequest{}); err == nil {
+		t.Fatalf("address book is exported without identity key")
+	}
+
+	dir, err := ioutil.TempDir("", "addressbook")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	source.server.identityKey, err = identity.LoadKey(filepath.Join(dir,
+		"identity.key"))
+	if err != nil {
+		t.Fatalf("unable to create identity key: %v", err)
+	}
+
+	_, err = source.admin.SetPayee(ctx, &Payee{
+		Name:    "treasury-cold",
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "cold-address",
+		Amount:  "1.5",
+	})
+	if err != nil {
+		t.Fatalf("unable to set payee: %v", err)
+	}
+
+	_, err = source.admin.AddWatchAddress(ctx, &WatchAddress{
+		Group:   "partner",
+		Asset:   Asset_BTC,
+		Address: "partner-address",
+		Account: "partner-1",
+	})
+	if err != nil {
+		t.Fatalf("unable to watch address: %v", err)
+	}
+
+	book, err := source.admin.ExportAddressBook(ctx, &EmptyRequest{})
+	if err != nil {
+		t.Fatalf("unable to export address book: %v", err)
+	}
+
+	if !strings.Contains(book.Bundle, "cold-address") ||
+		!strings.Contains(book.Bundle, "partner-address") {
+		t.Fatalf("entries are missing in the bundle: %v", book.Bundle)
+	}
+
+	// Tampered bundle is rejected.
+	tampered := *book
+	tampered.Bundle = strings.Replace(book.Bundle, "cold-address",
+		"attacker-address", 1)
+	_, err = target.admin.ImportAddressBook(ctx, &tampered)
+	expectInvalidArgument(t, err, "signature")
+
+	// Bundle might be reformatted for the review.
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(book.Bundle)); err != nil {
+		t.Fatalf("unable to compact bundle: %v", err)
+	}
+
+	reformatted := *book
+	reformatted.Bundle = compact.String()
+	resp, err := target.admin.ImportAddressBook(ctx, &reformatted)
+	if err != nil {
+		t.Fatalf("unable to import address book: %v", err)
+	}
+
+	if resp.Payees != 1 || resp.WatchAddresses.Added != 1 {
+		t.Fatalf("wrong import result: %v", resp)
+	}
+
+	payees, err := target.client.ListPayees(ctx, &EmptyRequest{})
+	if err != nil {
+		t.Fatalf("unable to list payees: %v", err)
+	}
+
+	if len(payees.Payees) != 1 || payees.Payees[0].Receipt != "cold-address" ||
+		payees.Payees[0].Amount != "1.5" {
+		t.Fatalf("wrong payees: %v", payees.Payees)
+	}
+
+	addresses, err := target.client.ListWatchAddresses(ctx,
+		&ListWatchAddressesRequest{})
+	if err != nil {
+		t.Fatalf("unable to list watch addresses: %v", err)
+	}
+
+	if len(addresses.Addresses) != 1 ||
+		addresses.Addresses[0].Account != "partner-1" {
+		t.Fatalf("wrong watch addresses: %v", addresses.Addresses)
+	}
+
+	// Import could be repeated.
+	resp, err = target.admin.ImportAddressBook(ctx, book)
+	if err != nil {
+		t.Fatalf("unable to import address book: %v", err)
+	}
+
+	if resp.WatchAddresses.Unchanged != 1 {
+		t.Fatalf("wrong import result: %v", resp)
+	}
+
+	wrongKey := *book
+	wrongKey.KeyId = "unknown"
+	_, err = target.admin.ImportAddressBook(ctx, &wrongKey)
+	expectInvalidArgument(t, err, "key_id")
+}
@@ -0,0 +1,226 @@
+package crpc
+
+import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/bitlum/connector/connectors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKeyMetadataKey is the key of the gRPC metadata through which API key is
+// passed by the downstream service.
+const APIKeyMetadataKey = "api-key"
+
+const (
+	// apiKeySize is the size in bytes of the generated API key.
+	apiKeySize = 32
+
+	// apiKeyIDSize is the size in bytes of the generated API key id.
+	apiKeyIDSize = 8
+)
+
+// methodScopes is the scope which API key should have in order to call the
+// PayServer method. Admin service methods require admin scope.
+var methodScopes = map[string]connectors.APIKeyScope{
+	"CreateReceipt":         connectors.ReceiveScope,
+	"NewAddress":            connectors.ReceiveScope,
+	"ValidateReceipt":       connectors.ReceiveScope,
+	"ListReceipts":          connectors.ReceiveScope,
+	"ReceiptByID":           connectors.ReceiveScope,
+	"AttachRefundAddress":   connectors.ReceiveScope,
+	"QuoteReceipt":          connectors.ReceiveScope,
+	"Balance":               connectors.SendScope,
+	"BalanceHistory":        connectors.SendScope,
+	"SubscribeBalance":      connectors.SendScope,
+	"EstimateFee":           connectors.SendScope,
+	"QuotePayment":          connectors.SendScope,
+	"SendPayment":           connectors.SendScope,
+	"SendPayments":          connectors.SendScope,
+	"SendTimeLockedPayment": connectors.SendScope,
+	"ListTimeLocks":         connectors.SendScope,
+	"PaymentByID":           connectors.SendScope,
+	"LabelPayment":          connectors.SendScope,
+	"RefundPayment":         connectors.SendScope,
+	"CancelPayment":         connectors.SendScope,
+	"PaymentProof":          connectors.ReceiveScope,
+	"TransferFunds":         connectors.SendScope,
+	"CreateAccount":         connectors.ReceiveScope,
+	"ListAccounts":          connectors.SendScope,
+	"ListDepositAddresses":  connectors.ReceiveScope,
+	"AccountStatement":      connectors.SendScope,
+	"PaymentsByReceipt":     connectors.SendScope,
+	"ListPayments":          connectors.SendScope,
+	"StreamPayments":        connectors.SendScope,
+	"ExportPayments":        connectors.SendScope,
+	"SubscribePayments":     connectors.SendScope,
+	"SubscribeReceipts":     connectors.ReceiveScope,
+	"ListPayees":            connectors.SendScope,
+	"ListWatchAddresses":    connectors.SendScope,
+	"ListWatchEvents":       connectors.SendScope,
+	"GetPublicKeys":         connectors.SendScope,
+	"GetInfo":               connectors.ReceiveScope,
+	"HealthCheck":           connectors.ReceiveScope,
+	"Assets":                connectors.ReceiveScope,
+	"GetBranding":           connectors.ReceiveScope,
+}
+
+// apiKeyAdminKey is the context key which denotes that call of the Admin
+// service method is authorized by the API key with admin scope, so that
+// admin token isn't required.
+type apiKeyAdminKey struct{}
+
+// isAdminByAPIKey returns true if call is authorized by the API key with
+// admin scope.
+func isAdminByAPIKey(ctx context.Context) bool {
+	authorized, _ := ctx.Value(apiKeyAdminKey{}).(bool)
+	return authorized
+}
+
+// apiKeyIDKey is the context key under which id of the API key, which
+// authorized the call, is stored.
+type apiKeyIDKey struct{}
+
+// apiKeyIDFromContext returns id of the API key which authorized the call,
+// or empty string if call isn't authorized by the API key.
+func apiKeyIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(apiKeyIDKey{}).(string)
+	return id
+}
+
+// newAPIKey generates random API key and its id.
+func newAPIKey() (string, string, error) {
+	key := make([]byte, apiKeySize)
+	if _, err := crand.Read(key); err != nil {
+		return "", "", err
+	}
+
+	id := make([]byte, apiKeyIDSize)
+	if _, err := crand.Read(id); err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(key), hex.EncodeToString(id), nil
+}
+
+// hashAPIKey returns the hash under which API key is stored.
+func hashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// checkAPIKey ensures that request context contains the active API key
+// which has the scope required by the method. Calls of the Admin service
+// methods without API key are passed as is, in order to be checked by the
+// admin token. Returned context denotes whether Admin service call has been
+// authorized.
+func checkAPIKey(ctx context.Context, fullMethod string,
+	store connectors.APIKeysStore) (context.Context, error) {
+
+	fullMethod = canonicalMethod(fullMethod)
+	method := methodName(fullMethod)
+
+	var values []string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		values = md.Get(APIKeyMetadataKey)
+	}
+
+	var scope connectors.APIKeyScope
+	switch {
+	case strings.HasPrefix(fullMethod, adminServicePrefix):
+		if len(values) == 0 {
+			return ctx, nil
+		}
+		scope = connectors.AdminScope
+
+	case strings.HasPrefix(fullMethod, payServerServicePrefix):
+		var ok bool
+		scope, ok = methodScopes[method]
+		if !ok {
+			return ctx, newErrPermissionDenied(method, "method scope is unknown")
+		}
+
+	default:
+		return ctx, nil
+	}
+
+	if len(values) != 1 {
+		return ctx, newErrInvalidAPIKey(method, "api key is missing")
+	}
+
+	key, err := store.APIKeyByHash(hashAPIKey(values[0]))
+	if err == connectors.APIKeyNotFound {
+		return ctx, newErrInvalidAPIKey(method, "api key is unknown")
+	} else if err != nil {
+		return ctx, newErrInternal(err.Error())
+	}
+
+	if key.RevokedAt != 0 {
+		return ctx, newErrInvalidAPIKey(method, "api key is revoked")
+	}
+
+	if !key.HasScope(scope) {
+		return ctx, newErrPermissionDenied(method, "api key doesn't have "+
+			string(scope)+" scope")
+	}
+
+	ctx = context.WithValue(ctx, apiKeyIDKey{}, key.ID)
+	if scope == connectors.AdminScope {
+		ctx = context.WithValue(ctx, apiKeyAdminKey{}, true)
+	}
+
+	return ctx, nil
+}
+
+// apiKeyStream overrides the context of the stream with the one returned
+// by the API key check.
+type apiKeyStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream.
+func (s *apiKeyStream) Context() context.Context {
+	return s.ctx
+}
+
+// APIKeyUnaryInterceptor rejects calls of the PayServer service methods
+// which don't contain the API key with the required scope. Calls of the
+// Admin service methods with API key require admin scope, and are passed
+// without admin token.
+func APIKeyUnaryInterceptor(
+	store connectors.APIKeysStore) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+
+		ctx, err := checkAPIKey(ctx, info.FullMethod, store)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// APIKeyStreamInterceptor rejects calls of the PayServer service streaming
+// methods which don't contain the API key with the required scope.
+func APIKeyStreamInterceptor(
+	store connectors.APIKeysStore) grpc.StreamServerInterceptor {
+
+	return func(srv interface{}, stream grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		ctx, err := checkAPIKey(stream.Context(), info.FullMethod, store)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &apiKeyStream{ServerStream: stream, ctx: ctx})
+	}
+}
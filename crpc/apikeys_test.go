@@ -0,0 +1,166 @@
+package crpc
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+// memAPIKeysStore is the in-memory API keys store used in tests.
+type memAPIKeysStore struct {
+	keys []*connectors.APIKey
+}
+
+func (s *memAPIKeysStore) APIKeyByID(id string) (*connectors.APIKey, error) {
+	for _, key := range s.keys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return nil, connectors.APIKeyNotFound
+}
+
+func (s *memAPIKeysStore) APIKeyByHash(hash string) (*connectors.APIKey,
+	error) {
+	for _, key := range s.keys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return nil, connectors.APIKeyNotFound
+}
+
+func (s *memAPIKeysStore) SaveAPIKey(key *connectors.APIKey) error {
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *memAPIKeysStore) ListAPIKeys() ([]*connectors.APIKey, error) {
+	return s.keys, nil
+}
+
+func TestMethodScopes(t *testing.T) {
+	for _, method := range _PayServer_serviceDesc.Methods {
+		if _, ok := methodScopes[method.MethodName]; !ok {
+			t.Fatalf("scope of method(%v) is missing", method.MethodName)
+		}
+	}
+
+	for _, stream := range _PayServer_serviceDesc.Streams {
+		if _, ok := methodScopes[stream.StreamName]; !ok {
+			t.Fatalf("scope of method(%v) is missing", stream.StreamName)
+		}
+	}
+}
+
+func TestCheckAPIKey(t *testing.T) {
+	store := &memAPIKeysStore{}
+	for _, key := range []*connectors.APIKey{
+		{
+			ID:     "receive",
+			Hash:   hashAPIKey("receive-key"),
+			Scopes: []connectors.APIKeyScope{connectors.ReceiveScope},
+		},
+		{
+			ID:     "admin",
+			Hash:   hashAPIKey("admin-key"),
+			Scopes: []connectors.APIKeyScope{connectors.AdminScope},
+		},
+		{
+			ID:        "revoked",
+			Hash:      hashAPIKey("revoked-key"),
+			Scopes:    []connectors.APIKeyScope{connectors.AdminScope},
+			RevokedAt: 1,
+		},
+	} {
+		store.SaveAPIKey(key)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		key     string
+		wantErr bool
+		admin   bool
+	}{
+		{
+			name:    "missing key",
+			method:  "/crpc.PayServer/CreateReceipt",
+			wantErr: true,
+		},
+		{
+			name:   "receive scope",
+			method: "/crpc.PayServer/CreateReceipt",
+			key:    "receive-key",
+		},
+		{
+			name:    "send without scope",
+			method:  "/crpc.PayServer/SendPayment",
+			key:     "receive-key",
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			method:  "/crpc.PayServer/CreateReceipt",
+			key:     "unknown-key",
+			wantErr: true,
+		},
+		{
+			name:    "revoked key",
+			method:  "/crpc.PayServer/CreateReceipt",
+			key:     "revoked-key",
+			wantErr: true,
+		},
+		{
+			name:   "admin scope",
+			method: "/crpc.PayServer/SendPayment",
+			key:    "admin-key",
+		},
+		{
+			// Admin service call without key is checked by the admin
+			// token.
+			name:   "admin method without key",
+			method: "/crpc.Admin/SyncUnspent",
+		},
+		{
+			name:    "admin method without scope",
+			method:  "/crpc.Admin/SyncUnspent",
+			key:     "receive-key",
+			wantErr: true,
+		},
+		{
+			name:   "admin method",
+			method: "/crpc.Admin/SyncUnspent",
+			key:    "admin-key",
+			admin:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.key != "" {
+			ctx = metadata.NewIncomingContext(ctx,
+				metadata.Pairs(APIKeyMetadataKey, tt.key))
+		}
+
+		ctx, err := checkAPIKey(ctx, tt.method, store)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%v: wrong error, got(%v), want error(%v)", tt.name,
+				err, tt.wantErr)
+		}
+
+		if isAdminByAPIKey(ctx) != tt.admin {
+			t.Fatalf("%v: wrong admin authorization", tt.name)
+		}
+
+		// Admin token isn't required for the call authorized by the
+		// API key with admin scope.
+		if tt.admin {
+			if err := checkAdminToken(ctx, tt.method, "token"); err != nil {
+				t.Fatalf("%v: admin token shouldn't be required", tt.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package crpc
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+//
+// BalanceHistory returns the periodic snapshots of the balances from the
+// oldest one, so that level of the wallet could be charted and its drain
+// could be noticed.
+func (s *Server) BalanceHistory(ctx context.Context,
+	req *BalanceHistoryRequest) (*BalanceHistoryResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	var (
+		query connectors.BalanceSnapshotsQuery
+		err   error
+	)
+
+	query.Asset, err = ConvertAssetFromProto(req.Asset)
+	if err != nil {
+		err := newErrInvalidArgument("asset")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	query.Media, err = ConvertMediaFromProto(req.Media)
+	if err != nil {
+		err := newErrInvalidArgument("media")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	query.CreatedFrom = req.CreatedFrom
+	query.CreatedTo = req.CreatedTo
+	query.Limit = int(req.Limit)
+
+	stop := trackStage(ctx, stageDB)
+	snapshots, err := s.balanceSnapshotsStore.QueryBalanceSnapshots(query)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &BalanceHistoryResponse{}
+	for _, snapshot := range snapshots {
+		protoSnapshot, err := convertBalanceSnapshotToProto(snapshot)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		resp.Snapshots = append(resp.Snapshots, protoSnapshot)
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// snapshotBalances returns the current balances of the every asset and
+// media served by the server. Balance which couldn't be fetched is skipped,
+// so that unreachable daemon doesn't stop the history of the others.
+func (s *Server) snapshotBalances() []*connectors.BalanceSnapshot {
+	var snapshots []*connectors.BalanceSnapshot
+
+	for asset := range s.blockchainConnectors {
+		snapshot, err := s.snapshotBalance(asset, connectors.Blockchain)
+		if err != nil {
+			log.Errorf("Unable to get balance of %v: %v", asset, err)
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	for asset := range s.lightningConnectors {
+		snapshot, err := s.snapshotBalance(asset, connectors.Lightning)
+		if err != nil {
+			log.Errorf("Unable to get lightning balance of %v: %v", asset,
+				err)
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots
+}
+
+// snapshotBalance returns the current balance of the asset within the
+// media.
+func (s *Server) snapshotBalance(asset connectors.Asset,
+	media connectors.PaymentMedia) (*connectors.BalanceSnapshot, error) {
+	now := connectors.NowInMilliSeconds()
+
+	switch media {
+	case connectors.Blockchain:
+		c, ok := s.blockchainConnectors[asset]
+		if !ok {
+			return nil, errors.Errorf("asset %v isn't supported", asset)
+		}
+
+		confirmed, err := c.ConfirmedBalance()
+		if err != nil {
+			return nil, errors.Errorf("unable to get confirmed "+
+				"balance: %v", err)
+		}
+
+		pending, err := c.PendingBalance()
+		if err != nil {
+			return nil, errors.Errorf("unable to get pending "+
+				"balance: %v", err)
+		}
+
+		quarantined, err := s.quarantinedBalance(asset)
+		if err != nil {
+			return nil, errors.Errorf("unable to get quarantined "+
+				"balance: %v", err)
+		}
+
+		// Available balance is calculated the same way as it is returned
+		// by the Balance method.
+		available := confirmed.Sub(quarantined)
+		if available.Sign() < 0 {
+			available = decimal.Zero
+		}
+
+		return &connectors.BalanceSnapshot{
+			Asset:       asset,
+			Media:       connectors.Blockchain,
+			Available:   available,
+			Pending:     pending,
+			Quarantined: quarantined,
+			CreatedAt:   now,
+		}, nil
+
+	case connectors.Lightning:
+		c, ok := s.lightningConnectors[asset]
+		if !ok {
+			return nil, errors.Errorf("asset %v isn't supported", asset)
+		}
+
+		available, err := c.ConfirmedBalance()
+		if err != nil {
+			return nil, errors.Errorf("unable to get confirmed "+
+				"balance: %v", err)
+		}
+
+		pending, err := c.PendingBalance()
+		if err != nil {
+			return nil, errors.Errorf("unable to get pending "+
+				"balance: %v", err)
+		}
+
+		return &connectors.BalanceSnapshot{
+			Asset:       asset,
+			Media:       connectors.Lightning,
+			Available:   available,
+			Pending:     pending,
+			Quarantined: decimal.Zero,
+			CreatedAt:   now,
+		}, nil
+
+	default:
+		return nil, errors.Errorf("media %v isn't supported", media)
+	}
+}
+
+// BalanceRecorder periodically saves the snapshots of the balances, which
+// are returned by the BalanceHistory method.
+type BalanceRecorder struct {
+	server *Server
+
+	interval time.Duration
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewBalanceRecorder creates new instance of the recorder, which takes
+// snapshots with the given interval.
+func NewBalanceRecorder(s *Server, interval time.Duration) *BalanceRecorder {
+	return &BalanceRecorder{
+		server:   s,
+		interval: interval,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the record goroutine.
+func (r *BalanceRecorder) Start() {
+	r.wg.Add(1)
+	go r.recordHandler()
+}
+
+// Stop stops the record goroutine and waits for it to exit.
+func (r *BalanceRecorder) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+// recordHandler takes the snapshot on start and then after every interval.
+//
+// NOTE: Should be run as goroutine.
+func (r *BalanceRecorder) recordHandler() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.record()
+
+		select {
+		case <-ticker.C:
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// record saves the current balances in the store.
+func (r *BalanceRecorder) record() {
+	for _, snapshot := range r.server.snapshotBalances() {
+		err := r.server.balanceSnapshotsStore.SaveBalanceSnapshot(snapshot)
+		if err != nil {
+			log.Errorf("Unable to save balance snapshot of %v %v: %v",
+				snapshot.Asset, snapshot.Media, err)
+		}
+	}
+}
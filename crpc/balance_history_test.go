@@ -0,0 +1,68 @@
+package crpc
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestBalanceHistory(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+	recorder := NewBalanceRecorder(h.server, time.Hour)
+
+	recorder.record()
+
+	_, err := h.client.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "recipient",
+		Amount:  "1",
+	})
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	recorder.record()
+
+	history := func(limit uint32) []string {
+		resp, err := h.client.BalanceHistory(ctx, &BalanceHistoryRequest{
+			Asset: Asset_BTC,
+			Media: Media_BLOCKCHAIN,
+			Limit: limit,
+		})
+		if err != nil {
+			t.Fatalf("unable to get balance history: %v", err)
+		}
+
+		var available []string
+		for _, snapshot := range resp.Snapshots {
+			if snapshot.CreatedAt == 0 ||
+				snapshot.Balance.Asset != Asset_BTC {
+				t.Fatalf("wrong snapshot: %v", snapshot)
+			}
+			available = append(available, snapshot.Balance.Available)
+		}
+
+		return available
+	}
+
+	// Drain of the wallet is seen in the history from the oldest snapshot.
+	if available := history(0); len(available) != 2 ||
+		available[0] != "10" || available[1] != "8.9999" {
+		t.Fatalf("wrong history: %v", available)
+	}
+
+	if available := history(1); len(available) != 1 ||
+		available[0] != "8.9999" {
+		t.Fatalf("latest snapshot should be returned: %v", available)
+	}
+
+	_, err = h.client.BalanceHistory(ctx, &BalanceHistoryRequest{
+		Asset: Asset(100),
+	})
+	expectInvalidArgument(t, err, "asset")
+}
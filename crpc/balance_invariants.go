@@ -0,0 +1,444 @@
+package crpc
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+// balanceInvariantMetric is the name under which violation of the balance
+// invariant is reported to the metrics backend, so that it is alerted on
+// along with the high severity errors of the methods.
+const balanceInvariantMetric = "BalanceInvariant"
+
+// ParseBalanceTolerance parses the tolerance of the balance invariant of
+// the asset from the config in form of "asset=amount", e.g. "BTC=0.001".
+func ParseBalanceTolerance(s string) (connectors.Asset, decimal.Decimal,
+	error) {
+
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return "", decimal.Zero, errors.Errorf("balance tolerance should "+
+			"be in form of asset=amount, got(%v)", s)
+	}
+
+	asset := strings.ToUpper(parts[0])
+	if _, ok := Asset_value[asset]; !ok || asset == Asset_ASSET_NONE.String() {
+		return "", decimal.Zero, errors.Errorf("unknown asset of the "+
+			"balance tolerance, got(%v)", s)
+	}
+
+	amount, err := decimal.NewFromString(parts[1])
+	if err != nil || amount.Sign() < 0 {
+		return "", decimal.Zero, errors.Errorf("amount of the balance "+
+			"tolerance should be non-negative number, got(%v)", s)
+	}
+
+	return connectors.Asset(asset), amount, nil
+}
+
+// sendsTrip is the violation of the balance invariant which has tripped
+// the sends circuit breaker.
+type sendsTrip struct {
+	reason    string
+	trippedAt int64
+}
+
+// sendsBreaker is the circuit breaker of the outgoing payments in all
+// assets and media, which is tripped by the balance watchdog, so that bug
+// of the ledger couldn't drain the wallet. Breaker is kept in memory, it
+// is tripped once again by the first check after the restart if the
+// shortfall persists.
+type sendsBreaker struct {
+	mtx   sync.RWMutex
+	state *sendsTrip
+}
+
+// trip opens the breaker, and returns true if it wasn't tripped before.
+func (b *sendsBreaker) trip(reason string) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.state != nil {
+		return false
+	}
+
+	b.state = &sendsTrip{
+		reason:    reason,
+		trippedAt: connectors.NowInMilliSeconds(),
+	}
+	return true
+}
+
+// reset closes the breaker, and returns true if it was tripped.
+func (b *sendsBreaker) reset() bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	tripped := b.state != nil
+	b.state = nil
+	return tripped
+}
+
+// tripped returns the violation which has tripped the breaker, or nil if
+// sends are allowed.
+func (b *sendsBreaker) tripped() *sendsTrip {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	return b.state
+}
+
+// balanceInvariant is the comparison of the funds which should be held by
+// the server according to the ledger with the funds it actually holds.
+type balanceInvariant struct {
+	asset    connectors.Asset
+	ledger   decimal.Decimal
+	holdings decimal.Decimal
+}
+
+// shortfall returns the amount by which holdings are below the ledger,
+// zero if holdings cover the ledger.
+func (i *balanceInvariant) shortfall() decimal.Decimal {
+	shortfall := i.ledger.Sub(i.holdings)
+	if shortfall.Sign() < 0 {
+		return decimal.Zero
+	}
+	return shortfall
+}
+
+// ledgerHoldingsDelta returns the change of the funds which should be held
+// by the server, which is made by the payment. Unlike the account balance,
+// incoming payment is counted as soon as it is seen by the daemon, which
+// counts it in the pending balance, and regardless of the quarantine,
+// because quarantined funds are kept in the wallet, and returned ones are
+// sent by the separate outgoing payment.
+func ledgerHoldingsDelta(payment *connectors.Payment) decimal.Decimal {
+	switch payment.Direction {
+	case connectors.Incoming:
+		if payment.Status == connectors.Pending ||
+			payment.Status == connectors.Completed {
+			return payment.Amount
+		}
+	case connectors.Outgoing:
+		if payment.Status != connectors.Failed {
+			return payment.Amount.Add(payment.MediaFee).Neg()
+		}
+	}
+
+	return decimal.Zero
+}
+
+// federationHoldingsDelta returns the change of the funds held by the
+// server, which isn't recorded by the payments because of the transfers
+// with the federation peers. Transfers are recorded as internal payments,
+// but funds stay where they were until the position is settled on-chain,
+// and settlements are recorded as regular payments.
+func federationHoldingsDelta(entry *connectors.FederationEntry) decimal.Decimal {
+	if entry.Kind != connectors.FederationTransfer ||
+		entry.Status == connectors.FederationRejected {
+		return decimal.Zero
+	}
+
+	if entry.Direction == connectors.Outgoing {
+		return entry.Amount
+	}
+	return entry.Amount.Neg()
+}
+
+// invariantAssets returns the assets served by the server in either media
+// in the alphabetical order.
+func (s *Server) invariantAssets() []connectors.Asset {
+	seen := make(map[connectors.Asset]struct{})
+	for asset := range s.blockchainConnectors {
+		seen[asset] = struct{}{}
+	}
+	for asset := range s.lightningConnectors {
+		seen[asset] = struct{}{}
+	}
+
+	assets := make([]connectors.Asset, 0, len(seen))
+	for asset := range seen {
+		assets = append(assets, asset)
+	}
+
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i] < assets[j]
+	})
+
+	return assets
+}
+
+// checkBalanceInvariant compares the total of the ledger of the asset with
+// the confirmed and pending balances of the blockchain wallet and the
+// lightning network node. Pending balance is counted, so that change of
+// the unconfirmed outgoing transaction isn't seen as the shortfall.
+func (s *Server) checkBalanceInvariant(
+	asset connectors.Asset) (*balanceInvariant, error) {
+
+	invariant := &balanceInvariant{
+		asset:    asset,
+		ledger:   decimal.Zero,
+		holdings: decimal.Zero,
+	}
+
+	query := connectors.PaymentsQuery{
+		Asset: asset,
+	}
+
+	_, err := s.walkPayments(query, func(payment *connectors.Payment) error {
+		invariant.ledger = invariant.ledger.Add(ledgerHoldingsDelta(payment))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Errorf("unable to sum payments: %v", err)
+	}
+
+	if s.federation != nil {
+		for _, peer := range s.federation.peerNames() {
+			entries, err := s.federation.store.FederationEntries(peer)
+			if err != nil {
+				return nil, errors.Errorf("unable to get entries of "+
+					"peer(%v): %v", peer, err)
+			}
+
+			for _, entry := range entries {
+				if entry.Asset != asset {
+					continue
+				}
+
+				invariant.ledger = invariant.ledger.Add(
+					federationHoldingsDelta(entry))
+			}
+		}
+	}
+
+	type balancer interface {
+		ConfirmedBalance() (decimal.Decimal, error)
+		PendingBalance() (decimal.Decimal, error)
+	}
+
+	var balancers []balancer
+	if c, ok := s.blockchainConnectors[asset]; ok {
+		balancers = append(balancers, c)
+	}
+	if c, ok := s.lightningConnectors[asset]; ok {
+		balancers = append(balancers, c)
+	}
+
+	for _, c := range balancers {
+		confirmed, err := c.ConfirmedBalance()
+		if err != nil {
+			return nil, errors.Errorf("unable to get confirmed "+
+				"balance: %v", err)
+		}
+
+		pending, err := c.PendingBalance()
+		if err != nil {
+			return nil, errors.Errorf("unable to get pending "+
+				"balance: %v", err)
+		}
+
+		invariant.holdings = invariant.holdings.Add(confirmed).Add(pending)
+	}
+
+	return invariant, nil
+}
+
+//
+// BalanceInvariants compares the total of the ledger, i.e. incoming
+// payments minus outgoing payments with their fees, with the funds held
+// on-chain and in the lightning network for every asset, and returns the
+// state of the sends circuit breaker, which is tripped by the watchdog
+// once holdings fall short of the ledger.
+func (s *Server) BalanceInvariants(ctx context.Context,
+	req *EmptyRequest) (*BalanceInvariantsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp := &BalanceInvariantsResponse{}
+	for _, asset := range s.invariantAssets() {
+		protoAsset, err := convertAssetToProto(asset)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		// Invariant which couldn't be checked is reported along with the
+		// others, so that unreachable daemon doesn't hide them.
+		stop := trackStage(ctx, stageNode)
+		invariant, err := s.checkBalanceInvariant(asset)
+		stop()
+		if err != nil {
+			resp.Invariants = append(resp.Invariants, &BalanceInvariant{
+				Asset: protoAsset,
+				Error: err.Error(),
+			})
+			continue
+		}
+
+		resp.Invariants = append(resp.Invariants, &BalanceInvariant{
+			Asset:     protoAsset,
+			Ledger:    invariant.ledger.String(),
+			Holdings:  invariant.holdings.String(),
+			Shortfall: invariant.shortfall().String(),
+		})
+	}
+
+	if trip := s.breaker.tripped(); trip != nil {
+		resp.SendsSuspended = true
+		resp.SuspensionReason = trip.reason
+		resp.SuspendedAt = trip.trippedAt
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ResetSendsBreaker closes the sends circuit breaker, so that outgoing
+// payments are allowed again. Breaker is tripped once again by the next
+// check of the watchdog if the shortfall persists.
+func (s *Server) ResetSendsBreaker(ctx context.Context,
+	req *EmptyRequest) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	// Reset is idempotent, so that it could be safely retried.
+	if s.breaker.reset() {
+		log.Warnf("Sends circuit breaker has been reset by the operator")
+	}
+
+	resp := &EmptyResponse{}
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// BalanceWatchdog periodically checks that holdings of every asset cover
+// its ledger, and trips the sends circuit breaker once they fall short by
+// more than the tolerance of the asset, so that bug of the ledger, or
+// funds leaking from the wallet, couldn't be amplified by further sends.
+type BalanceWatchdog struct {
+	server *Server
+
+	interval   time.Duration
+	tolerances map[connectors.Asset]decimal.Decimal
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewBalanceWatchdog creates new instance of the watchdog, which checks
+// invariants with the given interval. Assets without tolerance trip the
+// breaker on any shortfall.
+func NewBalanceWatchdog(s *Server, interval time.Duration,
+	tolerances map[connectors.Asset]decimal.Decimal) *BalanceWatchdog {
+	return &BalanceWatchdog{
+		server:     s,
+		interval:   interval,
+		tolerances: tolerances,
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start launches the check goroutine.
+func (w *BalanceWatchdog) Start() {
+	w.wg.Add(1)
+	go w.checkHandler()
+}
+
+// Stop stops the check goroutine and waits for it to exit.
+func (w *BalanceWatchdog) Stop() {
+	close(w.quit)
+	w.wg.Wait()
+}
+
+// checkHandler checks invariants on start and then after every interval.
+//
+// NOTE: Should be run as goroutine.
+func (w *BalanceWatchdog) checkHandler() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.check()
+
+		select {
+		case <-ticker.C:
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// check compares holdings with the ledger of every asset, and trips the
+// breaker on the first shortfall which exceeds the tolerance. Asset which
+// couldn't be checked is skipped, so that unreachable daemon doesn't
+// suspend the sends.
+func (w *BalanceWatchdog) check() {
+	for _, asset := range w.server.invariantAssets() {
+		invariant, err := w.server.checkBalanceInvariant(asset)
+		if err != nil {
+			log.Errorf("Unable to check balance invariant of %v: %v",
+				asset, err)
+			continue
+		}
+
+		tolerance, ok := w.tolerances[asset]
+		if !ok {
+			tolerance = decimal.Zero
+		}
+
+		surplus := invariant.holdings.Sub(invariant.ledger)
+		if surplus.GreaterThan(tolerance) {
+			// Surplus doesn't put funds of the customers at risk, e.g.
+			// wallet has been topped up by the operator, but still could
+			// be the sign of the missing payments.
+			log.Warnf("Holdings of %v exceed ledger by %v, ledger(%v), "+
+				"holdings(%v)", asset, surplus, invariant.ledger,
+				invariant.holdings)
+			continue
+		}
+
+		shortfall := invariant.shortfall()
+		if !shortfall.GreaterThan(tolerance) {
+			continue
+		}
+
+		reason := fmt.Sprintf("holdings of %v are short of ledger by %v, "+
+			"ledger(%v), holdings(%v)", asset, shortfall, invariant.ledger,
+			invariant.holdings)
+
+		if w.server.breaker.trip(reason) {
+			log.Criticalf("Sends circuit breaker is tripped, all outgoing "+
+				"payments are suspended: %v", reason)
+			w.server.metrics.AddError(balanceInvariantMetric,
+				string(metrics.HighSeverity))
+		}
+
+		return
+	}
+}
@@ -0,0 +1,109 @@
+package crpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseBalanceTolerance(t *testing.T) {
+	asset, amount, err := ParseBalanceTolerance("btc=0.001")
+	if err != nil {
+		t.Fatalf("unable to parse tolerance: %v", err)
+	}
+
+	if asset != connectors.BTC || !amount.Equal(decimal.New(1, -3)) {
+		t.Fatalf("wrong tolerance: %v %v", asset, amount)
+	}
+
+	for _, value := range []string{"BTC", "XYZ=1", "BTC=-1", "BTC=abc"} {
+		if _, _, err := ParseBalanceTolerance(value); err == nil {
+			t.Fatalf("invalid tolerance(%v) is parsed", value)
+		}
+	}
+}
+
+func TestBalanceWatchdog(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	send := func() error {
+		_, err := h.client.SendPayment(ctx, &SendPaymentRequest{
+			Asset:   Asset_BTC,
+			Media:   Media_BLOCKCHAIN,
+			Receipt: "recipient",
+			Amount:  "1",
+		})
+		return err
+	}
+
+	// Wallet holds 10 BTC, which are not recorded by the payments, surplus
+	// doesn't trip the breaker.
+	watchdog := NewBalanceWatchdog(h.server, time.Hour,
+		map[connectors.Asset]decimal.Decimal{
+			connectors.BTC: decimal.New(1, 0),
+		})
+	watchdog.check()
+
+	if err := send(); err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	// Ledger now owes 2 BTC more than wallet holds, which is beyond the
+	// tolerance.
+	h.seedPayments(&connectors.Payment{
+		PaymentID: "incoming",
+		UpdatedAt: 1,
+		Status:    connectors.Completed,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "btc-address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.New(12, 0),
+		MediaFee:  decimal.Zero,
+		MediaID:   "incoming",
+	})
+	watchdog.check()
+
+	resp, err := h.admin.BalanceInvariants(ctx, &EmptyRequest{})
+	if err != nil {
+		t.Fatalf("unable to get invariants: %v", err)
+	}
+
+	if len(resp.Invariants) != 1 || resp.Invariants[0].Shortfall != "2" ||
+		resp.Invariants[0].Ledger != "10.9999" {
+		t.Fatalf("wrong invariants: %v", resp.Invariants)
+	}
+
+	if !resp.SendsSuspended || resp.SuspensionReason == "" ||
+		resp.SuspendedAt == 0 {
+		t.Fatalf("breaker should be tripped: %v", resp)
+	}
+
+	expected := newErrSendsSuspended(resp.SuspensionReason).Error()
+	if msg := status.Convert(send()).Message(); msg != expected {
+		t.Fatalf("wrong error, expected(%v), got(%v)", expected, msg)
+	}
+
+	if _, err := h.admin.ResetSendsBreaker(ctx, &EmptyRequest{}); err != nil {
+		t.Fatalf("unable to reset breaker: %v", err)
+	}
+
+	if err := send(); err != nil {
+		t.Fatalf("unable to send payment after reset: %v", err)
+	}
+
+	// Shortfall persists, so that breaker is tripped once again.
+	watchdog.check()
+
+	if h.server.breaker.tripped() == nil {
+		t.Fatal("breaker should be tripped once again")
+	}
+}
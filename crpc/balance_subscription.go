@@ -0,0 +1,202 @@
+package crpc
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/shopspring/decimal"
+)
+
+// balanceCheckInterval is the interval with which balances of the
+// subscription are checked even if no payment has been saved, because
+// funds might be changed bypassing the payments store, e.g. by opening of
+// the lightning channel.
+var balanceCheckInterval = time.Minute
+
+//
+// SubscribeBalance sends balance every time its available or pending funds
+// are changed by at least the given delta, so that treasury tooling could
+// react to large deposits without polling Balance.
+func (s *Server) SubscribeBalance(req *SubscribeBalanceRequest,
+	stream PayServer_SubscribeBalanceServer) error {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	subscriber, ok := s.paymentsStore.(connectors.PaymentsSubscriber)
+	if !ok {
+		err := newErrInternal("payments store doesn't support subscriptions")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return err
+	}
+
+	var (
+		asset    connectors.Asset
+		media    connectors.PaymentMedia
+		minDelta decimal.Decimal
+		err      error
+	)
+
+	if req.Asset != Asset_ASSET_NONE {
+		asset, err = ConvertAssetFromProto(req.Asset)
+		if err != nil {
+			err := newErrInvalidArgument("asset")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return err
+		}
+	}
+
+	if req.Media != Media_MEDIA_NONE {
+		media, err = ConvertMediaFromProto(req.Media)
+		if err != nil {
+			err := newErrInvalidArgument("media")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return err
+		}
+	}
+
+	if req.MinDelta != "" {
+		minDelta, err = decimal.NewFromString(req.MinDelta)
+		if err != nil || minDelta.Sign() < 0 {
+			err := newErrInvalidArgument("min_delta")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return err
+		}
+	}
+
+	matches := func(a connectors.Asset, m connectors.PaymentMedia) bool {
+		return (asset == "" || a == asset) && (media == "" || m == media)
+	}
+
+	// Subscription is made before the first balances are fetched, so
+	// that changes made in between aren't missed.
+	subscription := subscriber.SubscribePayments()
+	defer subscription.Cancel()
+
+	ticker := time.NewTicker(balanceCheckInterval)
+	defer ticker.Stop()
+
+	// Balances which have been sent last, delta is counted from them, so
+	// that slow drift of the balance is sent once it is accumulated.
+	sent := make(map[string]*connectors.BalanceSnapshot)
+
+	send := func(snapshot *connectors.BalanceSnapshot) error {
+		key := string(snapshot.Asset) + ":" + string(snapshot.Media)
+
+		availableDelta := decimal.Zero
+		pendingDelta := decimal.Zero
+		if last, ok := sent[key]; ok {
+			availableDelta = snapshot.Available.Sub(last.Available)
+			pendingDelta = snapshot.Pending.Sub(last.Pending)
+
+			if !exceedsDelta(availableDelta, minDelta) &&
+				!exceedsDelta(pendingDelta, minDelta) {
+				return nil
+			}
+		}
+
+		protoSnapshot, err := convertBalanceSnapshotToProto(snapshot)
+		if err != nil {
+			return newErrInternal(err.Error())
+		}
+
+		update := &BalanceUpdate{
+			UpdatedAt:      snapshot.CreatedAt,
+			Balance:        protoSnapshot.Balance,
+			AvailableDelta: availableDelta.String(),
+			PendingDelta:   pendingDelta.String(),
+		}
+
+		if err := stream.Send(update); err != nil {
+			return err
+		}
+
+		sent[key] = snapshot
+		return nil
+	}
+
+	checkAll := func() error {
+		for _, snapshot := range s.snapshotBalances() {
+			if !matches(snapshot.Asset, snapshot.Media) {
+				continue
+			}
+
+			if err := send(snapshot); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := checkAll(); err != nil {
+		log.Errorf("command(%v), id(%v), unable to send balance: %v",
+			common.GetFunctionName(), requestID, err)
+		return err
+	}
+
+	for {
+		select {
+		case payment, ok := <-subscription.Updates:
+			if !ok {
+				err := newErrInternal("subscriber is unable to keep up " +
+					"with the updates, resubscribe")
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(),
+					string(metrics.LowSeverity))
+				return err
+			}
+
+			if !matches(payment.Asset, payment.Media) {
+				continue
+			}
+
+			snapshot, err := s.snapshotBalance(payment.Asset, payment.Media)
+			if err != nil {
+				// Daemon might be temporary unreachable, balance will
+				// be checked again on the next update.
+				log.Errorf("command(%v), id(%v), unable to get balance "+
+					"of %v %v: %v", common.GetFunctionName(), requestID,
+					payment.Asset, payment.Media, err)
+				continue
+			}
+
+			if err := send(snapshot); err != nil {
+				log.Errorf("command(%v), id(%v), unable to send balance: %v",
+					common.GetFunctionName(), requestID, err)
+				return err
+			}
+
+		case <-ticker.C:
+			if err := checkAll(); err != nil {
+				log.Errorf("command(%v), id(%v), unable to send balance: %v",
+					common.GetFunctionName(), requestID, err)
+				return err
+			}
+
+		case <-stream.Context().Done():
+			log.Tracef("command(%v), id(%v), subscription is closed by "+
+				"client", common.GetFunctionName(), requestID)
+			return nil
+		}
+	}
+}
+
+// exceedsDelta returns true if the change of the funds is non-zero and is
+// at least the minimal delta.
+func exceedsDelta(change, minDelta decimal.Decimal) bool {
+	return !change.IsZero() && change.Abs().GreaterThanOrEqual(minDelta)
+}
@@ -0,0 +1,70 @@
+package crpc
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestSubscribeBalance(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := h.client.SubscribeBalance(ctx, &SubscribeBalanceRequest{
+		Asset:    Asset_BTC,
+		MinDelta: "0.5",
+	})
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+
+	// Current balance is sent first, so that deltas could be followed.
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("unable to receive balance: %v", err)
+	}
+
+	if update.Balance.Available != "10" || update.AvailableDelta != "0" {
+		t.Fatalf("wrong first balance: %v", update)
+	}
+
+	send := func(amount string) {
+		_, err := h.client.SendPayment(ctx, &SendPaymentRequest{
+			Asset:   Asset_BTC,
+			Media:   Media_BLOCKCHAIN,
+			Receipt: "recipient",
+			Amount:  amount,
+		})
+		if err != nil {
+			t.Fatalf("unable to send payment: %v", err)
+		}
+	}
+
+	// Change below the delta isn't sent, but it is accumulated with the
+	// next one.
+	send("0.1")
+	send("1")
+
+	update, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("unable to receive balance: %v", err)
+	}
+
+	if update.Balance.Available != "8.8998" ||
+		update.AvailableDelta != "-1.1002" || update.PendingDelta != "0" {
+		t.Fatalf("wrong balance update: %v", update)
+	}
+
+	stream, err = h.client.SubscribeBalance(ctx, &SubscribeBalanceRequest{
+		MinDelta: "-1",
+	})
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+
+	_, err = stream.Recv()
+	expectInvalidArgument(t, err, "min_delta")
+}
@@ -0,0 +1,138 @@
+package crpc
+
+import (
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+// simulatePayment validates the payment against the media and calculates
+// its fee without sending it. Connectors which are able to build the
+// payment without sending it select its inputs, for the others fee is
+// estimated and the balance is checked. Asset, media and receipt of the
+// request should be already resolved.
+func (s *Server) simulatePayment(ctx context.Context,
+	req *SendPaymentRequest) (*connectors.Payment, error) {
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || amount.Sign() < 0 {
+		return nil, newErrInvalidArgument("amount")
+	}
+
+	asset, _ := ConvertAssetFromProto(req.Asset)
+
+	switch req.Media {
+	case Media_BLOCKCHAIN:
+		c, ok := s.blockchainConnectors[asset]
+		if !ok {
+			return nil, newErrAssetNotSupported(req.Asset.String(),
+				req.Media.String())
+		}
+
+		if simulator, ok := c.(connectors.PaymentSimulator); ok {
+			stop := trackStage(ctx, stageNode)
+			payment, err := simulator.SimulatePayment(req.Receipt,
+				req.Amount, req.Memo)
+			stop()
+			if err != nil {
+				return nil, newErrInternal(err.Error())
+			}
+
+			return payment, nil
+		}
+
+		stop := trackStage(ctx, stageNode)
+		addressInfo, err := c.ValidateAddress(req.Receipt)
+		stop()
+		if err != nil {
+			return nil, newErrInvalidArgument("receipt")
+		}
+
+		stop = trackStage(ctx, stageNode)
+		fee, err := c.EstimateFee(req.Amount)
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		stop = trackStage(ctx, stageNode)
+		balance, err := c.ConfirmedBalance()
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		// Confirmed balance of the wallet still includes quarantined
+		// funds, even though connectors which select the coins lock
+		// their outputs, so they are not counted as available.
+		stop = trackStage(ctx, stageDB)
+		quarantined, err := s.quarantinedBalance(asset)
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		if amount.Add(fee).GreaterThan(balance.Sub(quarantined)) {
+			return nil, newErrInvalidArgument("amount")
+		}
+
+		return &connectors.Payment{
+			UpdatedAt: connectors.NowInMilliSeconds(),
+			Status:    connectors.Waiting,
+			Direction: connectors.Outgoing,
+			System:    connectors.External,
+			Receipt:   addressInfo.Address,
+			Asset:     asset,
+			Media:     connectors.Blockchain,
+			Amount:    amount,
+			MediaFee:  fee,
+			Memo:      req.Memo,
+		}, nil
+
+	case Media_LIGHTNING:
+		c, ok := s.lightningConnectors[asset]
+		if !ok {
+			return nil, newErrAssetNotSupported(req.Asset.String(),
+				req.Media.String())
+		}
+
+		if req.Memo != "" {
+			return nil, newErrInvalidArgument("memo")
+		}
+
+		stop := trackStage(ctx, stageNode)
+		invoice, err := c.ValidateInvoice(req.Receipt, req.Amount)
+		stop()
+		if err != nil {
+			return nil, newErrInvalidArgument("receipt")
+		}
+
+		// Amount of the invoice is paid if it isn't given in the request.
+		if amount.Sign() == 0 && invoice.MilliSat != nil {
+			amount = common.Sat2DecAmount(invoice.MilliSat.ToSatoshis())
+		}
+
+		stop = trackStage(ctx, stageNode)
+		fee, err := c.EstimateFee(req.Receipt)
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		return &connectors.Payment{
+			UpdatedAt: connectors.NowInMilliSeconds(),
+			Status:    connectors.Waiting,
+			Direction: connectors.Outgoing,
+			System:    connectors.External,
+			Receipt:   req.Receipt,
+			Asset:     asset,
+			Media:     connectors.Lightning,
+			Amount:    amount,
+			MediaFee:  fee,
+		}, nil
+
+	default:
+		return nil, newErrInvalidArgument("media")
+	}
+}
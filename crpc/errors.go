@@ -2,6 +2,7 @@ package crpc
 
 import (
 	"fmt"
+	"time"
 )
 
 const (
@@ -11,6 +12,34 @@ const (
 
 	// ErrInternal...
 	ErrInternal
+
+	// ErrUnauthenticated is returned if method requires the admin token,
+	// but it is missing or invalid.
+	ErrUnauthenticated
+
+	// ErrMethodDisabled is returned if method is disabled in the config.
+	ErrMethodDisabled
+
+	// ErrPermissionDenied is returned if macaroon doesn't grant the
+	// permission which is required by the method.
+	ErrPermissionDenied
+
+	// ErrRateLimited is returned if caller exceeded the rate limit of the
+	// method.
+	ErrRateLimited
+
+	// ErrWithdrawalsPaused is returned if outgoing payments of the asset
+	// are paused by the operator.
+	ErrWithdrawalsPaused
+
+	// ErrPaymentHeld is returned if outgoing payment should be reviewed by
+	// the operator according to the send policy.
+	ErrPaymentHeld
+
+	// ErrSendsSuspended is returned if outgoing payments are suspended by
+	// the sends circuit breaker, because holdings have fallen short of the
+	// ledger.
+	ErrSendsSuspended
 )
 
 type Error struct {
@@ -53,3 +82,83 @@ func newErrInvalidArgument(argName string) Error {
 			argName),
 	}
 }
+
+func newErrUnauthenticated(method string) Error {
+	return Error{
+		code: ErrUnauthenticated,
+		errMsg: fmt.Sprintf("%v: method '%v' requires valid admin token",
+			ErrUnauthenticated, method),
+	}
+}
+
+func newErrMethodDisabled(method, option string) Error {
+	return Error{
+		code: ErrMethodDisabled,
+		errMsg: fmt.Sprintf("%v: method '%v' is disabled, it could be "+
+			"enabled with '%v' option", ErrMethodDisabled, method, option),
+	}
+}
+
+func newErrInvalidMacaroon(method, reason string) Error {
+	return Error{
+		code: ErrUnauthenticated,
+		errMsg: fmt.Sprintf("%v: method '%v' requires valid macaroon: %v",
+			ErrUnauthenticated, method, reason),
+	}
+}
+
+func newErrInvalidAPIKey(method, reason string) Error {
+	return Error{
+		code: ErrUnauthenticated,
+		errMsg: fmt.Sprintf("%v: method '%v' requires valid api key: %v",
+			ErrUnauthenticated, method, reason),
+	}
+}
+
+func newErrInvalidSignature(method, reason string) Error {
+	return Error{
+		code: ErrUnauthenticated,
+		errMsg: fmt.Sprintf("%v: method '%v' requires valid signature of "+
+			"federation peer: %v", ErrUnauthenticated, method, reason),
+	}
+}
+
+func newErrPermissionDenied(method, reason string) Error {
+	return Error{
+		code: ErrPermissionDenied,
+		errMsg: fmt.Sprintf("%v: permission denied for method '%v': %v",
+			ErrPermissionDenied, method, reason),
+	}
+}
+
+func newErrRateLimited(method string, retryAfter time.Duration) Error {
+	return Error{
+		code: ErrRateLimited,
+		errMsg: fmt.Sprintf("%v: rate limit of method '%v' is exceeded, "+
+			"retry after %v", ErrRateLimited, method, retryAfter),
+	}
+}
+
+func newErrWithdrawalsPaused(asset, reason string) Error {
+	return Error{
+		code: ErrWithdrawalsPaused,
+		errMsg: fmt.Sprintf("%v: withdrawals of asset(%v) are paused: %v",
+			ErrWithdrawalsPaused, asset, reason),
+	}
+}
+
+func newErrSendsSuspended(reason string) Error {
+	return Error{
+		code: ErrSendsSuspended,
+		errMsg: fmt.Sprintf("%v: outgoing payments are suspended by the "+
+			"balance invariants check: %v", ErrSendsSuspended, reason),
+	}
+}
+
+func newErrPaymentHeld(method, reason string) Error {
+	return Error{
+		code: ErrPaymentHeld,
+		errMsg: fmt.Sprintf("%v: payment of method '%v' is held for "+
+			"review: %v", ErrPaymentHeld, method, reason),
+	}
+}
@@ -0,0 +1,322 @@
+package crpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"golang.org/x/net/context"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
+)
+
+const (
+	// eventsPath is the path of the WebSocket endpoint which streams
+	// payment updates.
+	eventsPath = "/v1/events"
+
+	// eventsHeartbeatInterval is the interval with which heartbeat is sent
+	// to the client, so that both sides could detect the dead connection,
+	// and proxies don't close the idle one.
+	eventsHeartbeatInterval = 30 * time.Second
+
+	// eventsWriteTimeout is the time after which client which doesn't
+	// read the events is disconnected.
+	eventsWriteTimeout = 10 * time.Second
+
+	// eventsProtocol is the WebSocket subprotocol of the events stream,
+	// which is selected by the server in response.
+	eventsProtocol = "payserver.events.v1"
+
+	// macaroonProtocolPrefix and apiKeyProtocolPrefix are the prefixes of
+	// the WebSocket subprotocols in which browsers, which are unable to
+	// set headers, pass the hex encoded macaroon and API key.
+	macaroonProtocolPrefix = "macaroon."
+	apiKeyProtocolPrefix   = "apikey."
+)
+
+const (
+	// eventPayment is sent when the payment state has changed.
+	eventPayment = "payment"
+
+	// eventReceiptUpdated is sent when the receipt has been replaced, e.g.
+	// lightning network invoice has expired unpaid.
+	eventReceiptUpdated = "receipt_updated"
+
+	// eventHeartbeat is sent periodically if there are no other events.
+	eventHeartbeat = "heartbeat"
+
+	// eventPing could be sent by the client, server responds with pong.
+	eventPing = "ping"
+
+	// eventPong is the response on the client ping.
+	eventPong = "pong"
+
+	// eventError is sent before the connection is closed by the server.
+	eventError = "error"
+)
+
+// event is the message of the WebSocket events stream. Payment and receipt
+// event are encoded in the same way as in gRPC JSON mapping.
+type event struct {
+	Type    string          `json:"type"`
+	Payment json.RawMessage `json:"payment,omitempty"`
+	Receipt json.RawMessage `json:"receipt,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// eventsConn serializes writes of the events to the WebSocket connection,
+// which are made by the subscription, heartbeat and ping handlers.
+type eventsConn struct {
+	mtx  sync.Mutex
+	conn *websocket.Conn
+}
+
+// send writes the event to the connection.
+func (c *eventsConn) send(e *event) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if err := c.conn.SetWriteDeadline(
+		time.Now().Add(eventsWriteTimeout)); err != nil {
+		return err
+	}
+
+	return websocket.JSON.Send(c.conn, e)
+}
+
+// eventsStream passes payments sent by the SubscribePayments method to the
+// WebSocket connection, so that browser clients receive the same updates as
+// gRPC ones.
+type eventsStream struct {
+	// ServerStream is embedded only to satisfy the interface, methods
+	// other than Send and Context are not used by the subscription.
+	grpc.ServerStream
+
+	ctx       context.Context
+	conn      *eventsConn
+	marshaler *jsonpb.Marshaler
+}
+
+// A compile time check to ensure that eventsStream could be used as the
+// stream of the SubscribePayments method.
+var _ PayServer_SubscribePaymentsServer = (*eventsStream)(nil)
+
+// Send writes the payment event to the connection.
+func (s *eventsStream) Send(payment *Payment) error {
+	data, err := s.marshaler.MarshalToString(payment)
+	if err != nil {
+		return err
+	}
+
+	return s.conn.send(&event{
+		Type:    eventPayment,
+		Payment: json.RawMessage(data),
+	})
+}
+
+// Context returns the context which is cancelled when connection is closed.
+func (s *eventsStream) Context() context.Context {
+	return s.ctx
+}
+
+// receiptEventsStream passes events sent by the SubscribeReceipts method to
+// the WebSocket connection along with the payment updates.
+type receiptEventsStream struct {
+	// ServerStream is embedded only to satisfy the interface, methods
+	// other than Send and Context are not used by the subscription.
+	grpc.ServerStream
+
+	ctx       context.Context
+	conn      *eventsConn
+	marshaler *jsonpb.Marshaler
+}
+
+// A compile time check to ensure that receiptEventsStream could be used as
+// the stream of the SubscribeReceipts method.
+var _ PayServer_SubscribeReceiptsServer = (*receiptEventsStream)(nil)
+
+// Send writes the receipt event to the connection.
+func (s *receiptEventsStream) Send(e *ReceiptEvent) error {
+	data, err := s.marshaler.MarshalToString(e)
+	if err != nil {
+		return err
+	}
+
+	return s.conn.send(&event{
+		Type:    eventReceiptUpdated,
+		Receipt: json.RawMessage(data),
+	})
+}
+
+// Context returns the context which is cancelled when connection is closed.
+func (s *receiptEventsStream) Context() context.Context {
+	return s.ctx
+}
+
+// newEventsHandler returns WebSocket handler which streams payment updates.
+// Updates are filtered by the query parameters in the same way as in the
+// SubscribePayments request, e.g.
+// "/v1/events?asset=BTC&direction=INCOMING&include=memo,confirmations".
+// Replacements of the receipts are streamed as well, regardless of the
+// filter, so that checkout pages could show the new invoice.
+// Browsers are unable to set headers of the WebSocket request, that is why
+// macaroon and API key could be also passed as the "macaroon.<hex>" and
+// "apikey.<key>" subprotocols, along with the "payserver.events.v1" one,
+// which is selected by the server. Credentials are never accepted in the
+// query, so that they don't appear in the logs of the proxies.
+func newEventsHandler(g *Gateway, s *Server) http.Handler {
+	return websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			if !g.allowedOrigin(r) {
+				return newErrPermissionDenied("SubscribePayments",
+					"origin isn't allowed")
+			}
+
+			macaroon := r.Header.Get(macaroonHeader)
+			apiKey := r.Header.Get(apiKeyHeader)
+
+			var protocols []string
+			for _, protocol := range config.Protocol {
+				switch {
+				case strings.HasPrefix(protocol, macaroonProtocolPrefix):
+					macaroon = strings.TrimPrefix(protocol,
+						macaroonProtocolPrefix)
+				case strings.HasPrefix(protocol, apiKeyProtocolPrefix):
+					apiKey = strings.TrimPrefix(protocol,
+						apiKeyProtocolPrefix)
+				case protocol == eventsProtocol:
+					protocols = []string{eventsProtocol}
+				}
+			}
+
+			// Browsers are failing the connection if requested protocol
+			// isn't selected, credentials shouldn't be echoed back.
+			config.Protocol = protocols
+
+			_, err := g.authenticate(r.Context(), macaroon, apiKey,
+				"SubscribePayments")
+			return err
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			serveEvents(s, g.marshaler, ws)
+		},
+	}
+}
+
+// allowedOrigin returns true if the WebSocket request is made by the
+// non-browser client, from the page of the same host, or from one of the
+// allowed origins. Otherwise any page opened in the browser of the operator
+// would be able to read the events with the cookies or the credentials
+// cached by the browser.
+func (g *Gateway) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range g.allowedOrigins {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// serveEvents subscribes on the payment updates and the receipt events and
+// sends them to the connection until it is closed by either side.
+func serveEvents(s *Server, marshaler *jsonpb.Marshaler, ws *websocket.Conn) {
+	conn := &eventsConn{conn: ws}
+
+	// Deadlines of the REST server are left on the connection after the
+	// upgrade, and stream is long lived, reads are waiting for the client
+	// messages indefinitely, writes set their own deadline.
+	if err := ws.SetDeadline(time.Time{}); err != nil {
+		return
+	}
+
+	req := &SubscribePaymentsRequest{}
+	if err := decodeGatewayRequest(ws.Request(), nil, req); err != nil {
+		conn.send(&event{Type: eventError, Error: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Client messages are read only to respond on pings, and to detect
+	// that connection has been closed.
+	go func() {
+		defer cancel()
+
+		for {
+			var e event
+			if err := websocket.JSON.Receive(ws, &e); err != nil {
+				return
+			}
+
+			if e.Type != eventPing {
+				continue
+			}
+
+			if err := conn.send(&event{Type: eventPong}); err != nil {
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer cancel()
+
+		ticker := time.NewTicker(eventsHeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				err := conn.send(&event{Type: eventHeartbeat})
+				if err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer cancel()
+
+		stream := &receiptEventsStream{
+			ctx:       ctx,
+			conn:      conn,
+			marshaler: marshaler,
+		}
+
+		err := s.SubscribeReceipts(&SubscribeReceiptsRequest{}, stream)
+		if err != nil {
+			conn.send(&event{Type: eventError, Error: err.Error()})
+		}
+	}()
+
+	stream := &eventsStream{
+		ctx:       ctx,
+		conn:      conn,
+		marshaler: marshaler,
+	}
+
+	if err := s.SubscribePayments(req, stream); err != nil {
+		conn.send(&event{Type: eventError, Error: err.Error()})
+	}
+}
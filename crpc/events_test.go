@@ -0,0 +1,122 @@
+package crpc
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics/rpc"
+	"golang.org/x/net/websocket"
+)
+
+// discardPaymentsStore is the payments store which doesn't store anything.
+type discardPaymentsStore struct {
+	connectors.PaymentsStore
+}
+
+func (s *discardPaymentsStore) SavePayment(payment *connectors.Payment) error {
+	return nil
+}
+
+func TestEvents(t *testing.T) {
+	store := connectors.NewPaymentsBroadcaster(&discardPaymentsStore{})
+	s := &Server{
+		paymentsStore: store,
+		metrics:       &rpc.EmptyBackend{},
+	}
+
+	server := httptest.NewServer(NewGateway(s, nil, nil, nil, nil, nil, SnakeCaseNames, 0))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + eventsPath +
+		"?asset=BTC"
+	ws, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatalf("unable to connect: %v", err)
+	}
+	defer ws.Close()
+
+	if err := websocket.JSON.Send(ws, &event{Type: eventPing}); err != nil {
+		t.Fatalf("unable to send ping: %v", err)
+	}
+
+	var e event
+	if err := websocket.JSON.Receive(ws, &e); err != nil {
+		t.Fatalf("unable to receive pong: %v", err)
+	}
+
+	if e.Type != eventPong {
+		t.Fatalf("wrong event, want(%v), got(%v)", eventPong, e.Type)
+	}
+
+	// Subscription is made asynchronously, that is why payments are
+	// saved until the event is received. Payments of the other asset
+	// should be filtered out.
+	quit := make(chan struct{})
+	defer close(quit)
+	go func() {
+		for {
+			select {
+			case <-time.After(10 * time.Millisecond):
+			case <-quit:
+				return
+			}
+
+			store.SavePayment(&connectors.Payment{
+				PaymentID: "eth",
+				Asset:     connectors.ETH,
+			})
+			store.SavePayment(&connectors.Payment{
+				PaymentID: "btc",
+				Asset:     connectors.BTC,
+			})
+		}
+	}()
+
+	for {
+		var e event
+		if err := websocket.JSON.Receive(ws, &e); err != nil {
+			t.Fatalf("unable to receive event: %v", err)
+		}
+
+		if e.Type != eventPayment {
+			continue
+		}
+
+		if !strings.Contains(string(e.Payment), `"payment_id":"btc"`) {
+			t.Fatalf("wrong payment: %v", string(e.Payment))
+		}
+
+		break
+	}
+}
+
+func TestEventsOrigin(t *testing.T) {
+	s := &Server{
+		paymentsStore: connectors.NewPaymentsBroadcaster(
+			&discardPaymentsStore{}),
+		metrics: &rpc.EmptyBackend{},
+	}
+
+	server := httptest.NewServer(NewGateway(s, nil, nil, nil, nil,
+		[]string{"https://dashboard.example.com"}, SnakeCaseNames, 0))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + eventsPath
+
+	ws, err := websocket.Dial(url, "", "https://attacker.example.com")
+	if err == nil {
+		ws.Close()
+		t.Fatal("connection from other origin should be rejected")
+	}
+
+	ws, err = websocket.Dial(url, eventsProtocol,
+		"https://dashboard.example.com")
+	if err != nil {
+		t.Fatalf("connection from allowed origin should be accepted: %v",
+			err)
+	}
+	ws.Close()
+}
@@ -0,0 +1,263 @@
+package crpc
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/go-errors/errors"
+)
+
+// exportChunkSize is the size of the export file parts, after which they
+// are sent to the client.
+const exportChunkSize = 64 * 1024
+
+// exportColumns is the list of the exported payment fields, in the order of
+// the CSV columns.
+var exportColumns = []string{
+	"payment_id",
+	"updated_at",
+	"status",
+	"direction",
+	"system",
+	"asset",
+	"media",
+	"receipt",
+	"tx_id",
+	"amount",
+	"fee",
+	"memo",
+	"fiat_value",
+	"fiat_currency",
+}
+
+// exportRecord is the payment in the form in which it is exported, fields
+// are strings so that amounts are not rounded by the accounting software.
+type exportRecord struct {
+	PaymentID string `json:"payment_id"`
+	UpdatedAt string `json:"updated_at"`
+	Status    string `json:"status"`
+	Direction string `json:"direction"`
+	System    string `json:"system"`
+	Asset     string `json:"asset"`
+	Media     string `json:"media"`
+	Receipt   string `json:"receipt"`
+	TxID      string `json:"tx_id"`
+	Amount    string `json:"amount"`
+	Fee       string `json:"fee"`
+	Memo      string `json:"memo"`
+
+	// FiatValue and FiatCurrency are empty if payment hasn't been valued
+	// at its completion.
+	FiatValue    string `json:"fiat_value"`
+	FiatCurrency string `json:"fiat_currency"`
+}
+
+func newExportRecord(payment *connectors.Payment) *exportRecord {
+	updatedAt := time.Unix(0, payment.UpdatedAt*int64(time.Millisecond))
+
+	var fiatValue string
+	if payment.FiatCurrency != "" {
+		fiatValue = payment.FiatValue.String()
+	}
+
+	return &exportRecord{
+		PaymentID: payment.PaymentID,
+		UpdatedAt: updatedAt.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		Status:    strings.ToLower(string(payment.Status)),
+		Direction: strings.ToLower(string(payment.Direction)),
+		System:    strings.ToLower(string(payment.System)),
+		Asset:     string(payment.Asset),
+		Media:     strings.ToLower(string(payment.Media)),
+		Receipt:   payment.Receipt,
+		TxID:      payment.MediaID,
+		Amount:    payment.Amount.String(),
+		Fee:       payment.MediaFee.String(),
+		Memo:      payment.Memo,
+
+		FiatValue:    fiatValue,
+		FiatCurrency: payment.FiatCurrency,
+	}
+}
+
+func (r *exportRecord) values() []string {
+	return []string{
+		r.PaymentID,
+		r.UpdatedAt,
+		r.Status,
+		r.Direction,
+		r.System,
+		r.Asset,
+		r.Media,
+		r.Receipt,
+		r.TxID,
+		r.Amount,
+		r.Fee,
+		r.Memo,
+		r.FiatValue,
+		r.FiatCurrency,
+	}
+}
+
+// paymentsExporter writes payments in the export format to the buffer.
+type paymentsExporter struct {
+	format ExportFormat
+	buf    bytes.Buffer
+	csv    *csv.Writer
+}
+
+func newPaymentsExporter(format ExportFormat) (*paymentsExporter, error) {
+	e := &paymentsExporter{
+		format: format,
+	}
+
+	switch format {
+	case ExportFormat_EXPORT_CSV:
+		e.csv = csv.NewWriter(&e.buf)
+		if err := e.csv.Write(exportColumns); err != nil {
+			return nil, err
+		}
+
+	case ExportFormat_EXPORT_JSON_LINES:
+
+	default:
+		return nil, errors.Errorf("unknown export format(%v)", format)
+	}
+
+	return e, nil
+}
+
+// write adds payment to the buffer.
+func (e *paymentsExporter) write(payment *connectors.Payment) error {
+	record := newExportRecord(payment)
+
+	if e.format == ExportFormat_EXPORT_JSON_LINES {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		e.buf.Write(data)
+		e.buf.WriteByte('\n')
+		return nil
+	}
+
+	return e.csv.Write(record.values())
+}
+
+// flush returns the data which has been written since the previous flush.
+func (e *paymentsExporter) flush() ([]byte, error) {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return nil, err
+		}
+	}
+
+	data := make([]byte, e.buf.Len())
+	copy(data, e.buf.Bytes())
+	e.buf.Reset()
+
+	return data, nil
+}
+
+// size returns the number of buffered bytes.
+func (e *paymentsExporter) size() int {
+	if e.csv != nil {
+		e.csv.Flush()
+	}
+
+	return e.buf.Len()
+}
+
+//
+// ExportPayments streams payments in the format which is suitable for the
+// accounting software, e.g. CSV. Payments are sent as the chunks of the
+// export file, which should be written one after another.
+func (s *Server) ExportPayments(req *ExportPaymentsRequest,
+	stream PayServer_ExportPaymentsServer) error {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	filter := req.Filter
+	if filter == nil {
+		filter = &ListPaymentsRequest{}
+	}
+
+	query, err := s.paymentsQuery(stream.Context(), filter)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return err
+	}
+
+	exporter, err := newPaymentsExporter(req.Format)
+	if err != nil {
+		err := newErrInvalidArgument("format")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return err
+	}
+
+	var sendErr error
+	send := func() error {
+		data, err := exporter.flush()
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(&ExportChunk{Data: data}); err != nil {
+			sendErr = err
+			return err
+		}
+
+		return nil
+	}
+
+	exported, err := s.walkPayments(query, func(payment *connectors.Payment) error {
+		if err := exporter.write(payment); err != nil {
+			return err
+		}
+
+		if exporter.size() < exportChunkSize {
+			return nil
+		}
+
+		return send()
+	})
+
+	// The rest of the file, or the header of the empty export, is sent
+	// at the end.
+	if err == nil && exporter.size() > 0 {
+		err = send()
+	}
+
+	switch {
+	case sendErr != nil:
+		log.Errorf("command(%v), id(%v), unable to send chunk: %v",
+			common.GetFunctionName(), requestID, sendErr)
+		return sendErr
+
+	case err != nil:
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v payments)",
+		common.GetFunctionName(), requestID, exported)
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package crpc
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+)
+
+func TestPaymentsExporter(t *testing.T) {
+	payment := &connectors.Payment{
+		PaymentID: "id",
+		UpdatedAt: 1546300800123,
+		Status:    connectors.Completed,
+		Direction: connectors.Outgoing,
+		System:    connectors.External,
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Receipt:   "receipt",
+		MediaID:   "txid",
+		Amount:    decimal.New(15, -1),
+		MediaFee:  decimal.New(1, -4),
+		Memo:      "rent, january",
+
+		FiatValue:    decimal.New(975018, -2),
+		FiatCurrency: "USD",
+	}
+
+	tests := []struct {
+		name   string
+		format ExportFormat
+		data   string
+	}{
+		{
+			name:   "csv",
+			format: ExportFormat_EXPORT_CSV,
+			data: "payment_id,updated_at,status,direction,system,asset," +
+				"media,receipt,tx_id,amount,fee,memo,fiat_value," +
+				"fiat_currency\n" +
+				"id,2019-01-01T00:00:00.123Z,completed,outgoing,external," +
+				"BTC,blockchain,receipt,txid,1.5,0.0001,\"rent, january\"," +
+				"9750.18,USD\n",
+		},
+		{
+			name:   "json lines",
+			format: ExportFormat_EXPORT_JSON_LINES,
+			data: `{"payment_id":"id","updated_at":"2019-01-01T00:00:00.123Z",` +
+				`"status":"completed","direction":"outgoing",` +
+				`"system":"external","asset":"BTC","media":"blockchain",` +
+				`"receipt":"receipt","tx_id":"txid","amount":"1.5",` +
+				`"fee":"0.0001","memo":"rent, january",` +
+				`"fiat_value":"9750.18","fiat_currency":"USD"}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter, err := newPaymentsExporter(test.format)
+			if err != nil {
+				t.Fatalf("unable to create exporter: %v", err)
+			}
+
+			if err := exporter.write(payment); err != nil {
+				t.Fatalf("unable to write payment: %v", err)
+			}
+
+			data, err := exporter.flush()
+			if err != nil {
+				t.Fatalf("unable to flush exporter: %v", err)
+			}
+
+			if string(data) != test.data {
+				t.Fatalf("wrong data, expected:\n%v\ngot:\n%v", test.data,
+					string(data))
+			}
+
+			if exporter.size() != 0 {
+				t.Fatalf("buffer should be empty after flush")
+			}
+		})
+	}
+
+	if _, err := newPaymentsExporter(ExportFormat(10)); err == nil {
+		t.Fatalf("unknown format should be rejected")
+	}
+}
@@ -0,0 +1,1538 @@
+package crpc
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/identity"
+	"github.com/bitlum/connector/metrics"
+	"github.com/go-errors/errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// federationSignatureKey is the key of the gRPC metadata with the
+	// signature of the Federation service request.
+	federationSignatureKey = strings.ToLower(identity.SignatureHeader)
+
+	// federationKeyIDKey is the key of the gRPC metadata with the id of
+	// the identity key by which request is signed.
+	federationKeyIDKey = strings.ToLower(identity.KeyIDHeader)
+
+	// federationTimestampKey is the key of the gRPC metadata with the
+	// unix time at which request has been signed.
+	federationTimestampKey = strings.ToLower(identity.TimestampHeader)
+)
+
+// defaultFederationTimeout is the maximum time to wait for the response of
+// the federation peer, if it isn't specified in the config.
+const defaultFederationTimeout = 30 * time.Second
+
+// FederationPeer is the payserver of another region, with which internal
+// balances are transferred.
+type FederationPeer struct {
+	// Name is the name under which peer is known to the operator.
+	Name string
+
+	// PublicKey is the DER encoded identity public key of the peer, which
+	// is returned by its GetPublicKeys method.
+	PublicKey []byte
+
+	client FederationClient
+}
+
+// NewFederationPeer creates the peer which Federation service is called
+// over the connection.
+func NewFederationPeer(name string, publicKey []byte,
+	conn *grpc.ClientConn) *FederationPeer {
+	return &FederationPeer{
+		Name:      name,
+		PublicKey: publicKey,
+		client:    NewFederationClient(conn),
+	}
+}
+
+// FederationPeerConfig is the config of the federation peer in form of
+// name,host:port,publickey[,tlscertpath].
+type FederationPeerConfig struct {
+	Name        string
+	Address     string
+	PublicKey   []byte
+	TLSCertPath string
+}
+
+// ParseFederationPeer parses the config of the federation peer in form of
+// name,host:port,publickey[,tlscertpath], where public key is the hex
+// encoded identity public key of the peer.
+func ParseFederationPeer(value string) (*FederationPeerConfig, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, errors.Errorf("federation peer(%v) should be in form "+
+			"of name,host:port,publickey[,tlscertpath]", value)
+	}
+
+	cfg := &FederationPeerConfig{
+		Name:    parts[0],
+		Address: parts[1],
+	}
+
+	if cfg.Name == "" || !isValidAccount(cfg.Name) {
+		return nil, errors.Errorf("invalid name of federation peer(%v)",
+			value)
+	}
+
+	if cfg.Address == "" {
+		return nil, errors.Errorf("invalid address of federation peer(%v)",
+			value)
+	}
+
+	publicKey, err := hex.DecodeString(parts[2])
+	if err != nil || len(publicKey) == 0 {
+		return nil, errors.Errorf("public key of federation peer(%v) "+
+			"should be hex encoded", value)
+	}
+	cfg.PublicKey = publicKey
+
+	if len(parts) == 4 {
+		cfg.TLSCertPath = parts[3]
+	}
+
+	return cfg, nil
+}
+
+// FederationConfig is the config of the federation with the payservers of
+// other regions.
+type FederationConfig struct {
+	// Peers are the payservers with which balances are transferred.
+	Peers []*FederationPeer
+
+	// Store is the ledger of the transfers and settlements.
+	Store connectors.FederationStore
+
+	// Timeout is the maximum time to wait for the peer response.
+	Timeout time.Duration
+
+	// MinSettlement is the minimum position which is settled by the
+	// periodic settlement, so that dust positions are not sent.
+	MinSettlement decimal.Decimal
+}
+
+// federation is the state of the federation with the payservers of other
+// regions.
+type federation struct {
+	peers         map[string]*FederationPeer
+	store         connectors.FederationStore
+	timeout       time.Duration
+	minSettlement decimal.Decimal
+
+	// sendMtx serializes delivery of the outgoing transfers and
+	// settlements, so that the same position isn't settled twice.
+	sendMtx sync.Mutex
+
+	// receiveMtx serializes processing of the incoming transfers and
+	// settlements, so that retried transfer isn't credited twice.
+	receiveMtx sync.Mutex
+}
+
+// newFederation creates the federation state from the config, nil is
+// returned if federation isn't configured.
+func newFederation(cfg *FederationConfig) *federation {
+	if cfg == nil {
+		return nil
+	}
+
+	f := &federation{
+		peers:         make(map[string]*FederationPeer, len(cfg.Peers)),
+		store:         cfg.Store,
+		timeout:       cfg.Timeout,
+		minSettlement: cfg.MinSettlement,
+	}
+	if f.timeout == 0 {
+		f.timeout = defaultFederationTimeout
+	}
+
+	for _, peer := range cfg.Peers {
+		f.peers[peer.Name] = peer
+	}
+
+	return f
+}
+
+// peerByKeyID returns the peer with the given id of the identity key.
+func (f *federation) peerByKeyID(keyID string) *FederationPeer {
+	for _, peer := range f.peers {
+		if identity.KeyID(peer.PublicKey) == keyID {
+			return peer
+		}
+	}
+
+	return nil
+}
+
+// peerNames returns the names of the peers in the alphabetical order.
+func (f *federation) peerNames() []string {
+	names := make([]string, 0, len(f.peers))
+	for name := range f.peers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// signFederationRequest returns the context with the signature of the
+// request by the server identity key, which is checked by the peer.
+func (s *Server) signFederationRequest(ctx context.Context,
+	req proto.Message) (context.Context, error) {
+
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return nil, errors.Errorf("unable to encode request: %v", err)
+	}
+
+	timestamp := time.Now().Unix()
+	signature, err := s.identityKey.SignTimestamped(timestamp, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata.AppendToOutgoingContext(ctx,
+		federationSignatureKey, signature,
+		federationKeyIDKey, s.identityKey.ID(),
+		federationTimestampKey, strconv.FormatInt(timestamp, 10),
+	), nil
+}
+
+// authenticatePeer returns the peer by which Federation service request
+// is signed. Signature covers the timestamp, so that captured request
+// couldn't be replayed after the freshness window, and requests are
+// idempotent by their ids, so that replay within the window is harmless.
+func (s *Server) authenticatePeer(ctx context.Context, method string,
+	req proto.Message) (*FederationPeer, error) {
+
+	if s.federation == nil {
+		return nil, newErrMethodDisabled(method, "federationpeer")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, newErrInvalidSignature(method, "signature is missing")
+	}
+
+	get := func(key string) string {
+		if values := md.Get(key); len(values) == 1 {
+			return values[0]
+		}
+		return ""
+	}
+
+	signature := get(federationSignatureKey)
+	keyID := get(federationKeyIDKey)
+	timestamp := get(federationTimestampKey)
+	if signature == "" || keyID == "" || timestamp == "" {
+		return nil, newErrInvalidSignature(method, "signature is missing")
+	}
+
+	peer := s.federation.peerByKeyID(keyID)
+	if peer == nil {
+		return nil, newErrInvalidSignature(method, "key id is unknown")
+	}
+
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	err = identity.VerifyTimestamped(peer.PublicKey, payload, timestamp,
+		signature, time.Now())
+	if err != nil {
+		return nil, newErrInvalidSignature(method, err.Error())
+	}
+
+	return peer, nil
+}
+
+// newFederationID returns the random id of the transfer or settlement,
+// which is also used as the media id of its internal payments.
+func newFederationID() (string, error) {
+	var id [16]byte
+	if _, err := crand.Read(id[:]); err != nil {
+		return "", err
+	}
+
+	return "federation-" + hex.EncodeToString(id[:]), nil
+}
+
+// federationPositions returns the net positions with the peer by asset,
+// along with the pending outgoing entries, which are not acknowledged by
+// the peer yet.
+func (s *Server) federationPositions(peer string) (
+	map[connectors.Asset]decimal.Decimal, []*connectors.FederationEntry,
+	error) {
+
+	entries, err := s.federation.store.FederationEntries(peer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pending []*connectors.FederationEntry
+	positions := make(map[connectors.Asset]decimal.Decimal)
+	for _, entry := range entries {
+		position, ok := positions[entry.Asset]
+		if !ok {
+			position = decimal.Zero
+		}
+		positions[entry.Asset] = position.Add(entry.PositionDelta())
+
+		if entry.Direction == connectors.Outgoing &&
+			entry.Status == connectors.FederationPending {
+			pending = append(pending, entry)
+		}
+	}
+
+	return positions, pending, nil
+}
+
+// convertFederationTransferToProto converts the outgoing transfer entry to
+// the proto form.
+func convertFederationTransferToProto(
+	entry *connectors.FederationEntry) (*FederationTransfer, error) {
+	asset, err := convertAssetToProto(entry.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	var status FederationStatus
+	switch entry.Status {
+	case connectors.FederationPending:
+		status = FederationStatus_FEDERATION_PENDING
+	case connectors.FederationCompleted:
+		status = FederationStatus_FEDERATION_COMPLETED
+	case connectors.FederationRejected:
+		status = FederationStatus_FEDERATION_REJECTED
+	default:
+		return nil, errors.Errorf("unknown federation status(%v)",
+			entry.Status)
+	}
+
+	return &FederationTransfer{
+		TransferId:    entry.ID,
+		Peer:          entry.Peer,
+		Asset:         asset,
+		Account:       entry.Account,
+		PeerAccount:   entry.PeerAccount,
+		Amount:        entry.Amount.String(),
+		Status:        status,
+		PaymentId:     entry.PaymentID,
+		FailureReason: entry.FailureReason,
+		CreatedAt:     entry.CreatedAt,
+	}, nil
+}
+
+// deliverTransfer sends the pending outgoing transfer to the peer, and
+// completes or fails its debit according to the peer response. Transfer
+// is left pending if peer is unreachable, so that it is retried later.
+//
+// NOTE: Should be called with the sendMtx held.
+func (s *Server) deliverTransfer(entry *connectors.FederationEntry) error {
+	peer, ok := s.federation.peers[entry.Peer]
+	if !ok {
+		return errors.Errorf("federation peer(%v) isn't configured",
+			entry.Peer)
+	}
+
+	asset, err := convertAssetToProto(entry.Asset)
+	if err != nil {
+		return err
+	}
+
+	req := &FederatedTransfer{
+		TransferId:  entry.ID,
+		FromAccount: entry.Account,
+		ToAccount:   entry.PeerAccount,
+		Asset:       asset,
+		Amount:      entry.Amount.String(),
+		Memo:        entry.Memo,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		s.federation.timeout)
+	defer cancel()
+
+	ctx, err = s.signFederationRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := peer.client.ReceiveTransfer(ctx, req)
+	if err != nil {
+		return errors.Errorf("unable to deliver transfer(%v) to peer(%v): "+
+			"%v", entry.ID, entry.Peer, err)
+	}
+
+	debit, err := s.paymentsStore.PaymentByID(entry.PaymentID)
+	if err != nil {
+		return errors.Errorf("unable to get debit(%v) of transfer(%v): %v",
+			entry.PaymentID, entry.ID, err)
+	}
+
+	now := connectors.NowInMilliSeconds()
+	if resp.Accepted {
+		entry.Status = connectors.FederationCompleted
+		debit.Status = connectors.Completed
+	} else {
+		entry.Status = connectors.FederationRejected
+		entry.FailureReason = resp.Reason
+		debit.Status = connectors.Failed
+		debit.FailureReason = "rejected by federation peer: " + resp.Reason
+	}
+	entry.UpdatedAt = now
+	debit.UpdatedAt = now
+
+	// Debit is saved first, if entry isn't saved afterwards transfer is
+	// delivered again, and peer response is the same.
+	if err := s.paymentsStore.SavePayment(debit); err != nil {
+		return errors.Errorf("unable to save debit(%v) of transfer(%v): %v",
+			debit.PaymentID, entry.ID, err)
+	}
+
+	if err := s.federation.store.SaveFederationEntry(entry); err != nil {
+		return errors.Errorf("unable to save transfer(%v): %v", entry.ID,
+			err)
+	}
+
+	log.Infof("Transfer(%v) of %v %v to account(%v) of peer(%v) is %v",
+		entry.ID, entry.Amount, entry.Asset, entry.PeerAccount, entry.Peer,
+		entry.Status)
+
+	return nil
+}
+
+// deliverSettlement notifies the peer about the sent settlement.
+// Settlement is left pending if peer is unreachable, so that it is
+// retried later.
+//
+// NOTE: Should be called with the sendMtx held.
+func (s *Server) deliverSettlement(entry *connectors.FederationEntry) error {
+	peer, ok := s.federation.peers[entry.Peer]
+	if !ok {
+		return errors.Errorf("federation peer(%v) isn't configured",
+			entry.Peer)
+	}
+
+	asset, err := convertAssetToProto(entry.Asset)
+	if err != nil {
+		return err
+	}
+
+	req := &FederatedSettlement{
+		SettlementId: entry.ID,
+		Asset:        asset,
+		Amount:       entry.Amount.String(),
+		TxId:         entry.TxID,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		s.federation.timeout)
+	defer cancel()
+
+	ctx, err = s.signFederationRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if _, err := peer.client.ReceiveSettlement(ctx, req); err != nil {
+		return errors.Errorf("unable to deliver settlement(%v) to "+
+			"peer(%v): %v", entry.ID, entry.Peer, err)
+	}
+
+	entry.Status = connectors.FederationCompleted
+	entry.UpdatedAt = connectors.NowInMilliSeconds()
+	if err := s.federation.store.SaveFederationEntry(entry); err != nil {
+		return errors.Errorf("unable to save settlement(%v): %v", entry.ID,
+			err)
+	}
+
+	return nil
+}
+
+// deliverPending delivers the outgoing transfers and settlements of the
+// peer, which haven't been acknowledged yet.
+//
+// NOTE: Should be called with the sendMtx held.
+func (s *Server) deliverPending(peer string) error {
+	_, pending, err := s.federationPositions(peer)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range pending {
+		switch {
+		case entry.Kind == connectors.FederationTransfer:
+			err = s.deliverTransfer(entry)
+
+		// Settlement without the transaction has been interrupted
+		// during the send, whether funds have left the wallet should be
+		// checked by the operator.
+		case entry.TxID == "":
+			log.Warnf("Settlement(%v) of %v %v to peer(%v) has no "+
+				"transaction, it should be checked manually", entry.ID,
+				entry.Amount, entry.Asset, entry.Peer)
+			continue
+
+		default:
+			err = s.deliverSettlement(entry)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isSettlementReceived returns true if the funds of the incoming settlement
+// have been received by the wallet, i.e. completed deposits of the
+// settlement transaction are not less than the settlement amount.
+func (s *Server) isSettlementReceived(
+	entry *connectors.FederationEntry) (bool, error) {
+
+	query := connectors.PaymentsQuery{
+		Asset:     entry.Asset,
+		Status:    connectors.Completed,
+		Direction: connectors.Incoming,
+		Media:     connectors.Blockchain,
+		MediaID:   entry.TxID,
+	}
+
+	deposits, _, err := s.paymentsStore.QueryPayments(query)
+	if err != nil {
+		return false, err
+	}
+
+	received := decimal.Zero
+	for _, deposit := range deposits {
+		received = received.Add(deposit.Amount)
+	}
+
+	return received.GreaterThanOrEqual(entry.Amount), nil
+}
+
+// completeSettlements completes the pending incoming settlements of the
+// peer, which funds have been received by the wallet since they were
+// reported by the peer.
+//
+// NOTE: Should be called with the receiveMtx held.
+func (s *Server) completeSettlements(peer string) error {
+	entries, err := s.federation.store.FederationEntries(peer)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Kind != connectors.FederationSettlement ||
+			entry.Direction != connectors.Incoming ||
+			entry.Status != connectors.FederationPending {
+			continue
+		}
+
+		received, err := s.isSettlementReceived(entry)
+		if err != nil {
+			return errors.Errorf("unable to check settlement(%v): %v",
+				entry.ID, err)
+		}
+
+		if !received {
+			continue
+		}
+
+		entry.Status = connectors.FederationCompleted
+		entry.UpdatedAt = connectors.NowInMilliSeconds()
+		if err := s.federation.store.SaveFederationEntry(entry); err != nil {
+			return errors.Errorf("unable to save settlement(%v): %v",
+				entry.ID, err)
+		}
+
+		log.Infof("Settlement(%v) of %v %v from peer(%v) is received, "+
+			"tx(%v)", entry.ID, entry.Amount, entry.Asset, entry.Peer,
+			entry.TxID)
+	}
+
+	return nil
+}
+
+// isSettlementTxUsed returns true if the transaction is already claimed by
+// the incoming settlement of any peer, so that the same funds couldn't
+// settle the position twice.
+func (s *Server) isSettlementTxUsed(txID string) (bool, error) {
+	for _, peer := range s.federation.peerNames() {
+		entries, err := s.federation.store.FederationEntries(peer)
+		if err != nil {
+			return false, err
+		}
+
+		for _, entry := range entries {
+			if entry.Kind == connectors.FederationSettlement &&
+				entry.Direction == connectors.Incoming &&
+				entry.TxID == txID {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// settle sends the amount which is owed to the peer in the asset on-chain,
+// and notifies the peer about it. Nothing is sent if the position is
+// below the given minimum, in this case nil entry is returned.
+func (s *Server) settle(ctx context.Context, peer *FederationPeer,
+	asset connectors.Asset, min decimal.Decimal) (*connectors.FederationEntry,
+	error) {
+
+	s.federation.sendMtx.Lock()
+	defer s.federation.sendMtx.Unlock()
+
+	positions, _, err := s.federationPositions(peer.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, ok := positions[asset]
+	if !ok || amount.Sign() <= 0 || amount.LessThan(min) {
+		return nil, nil
+	}
+
+	c, ok := s.blockchainConnectors[asset]
+	if !ok {
+		return nil, newErrAssetNotSupported(string(asset), "blockchain")
+	}
+
+	protoAsset, err := convertAssetToProto(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &SettlementAddressRequest{Asset: protoAsset}
+	peerCtx, cancel := context.WithTimeout(ctx, s.federation.timeout)
+	defer cancel()
+
+	peerCtx, err = s.signFederationRequest(peerCtx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := peer.client.SettlementAddress(peerCtx, req)
+	if err != nil {
+		return nil, errors.Errorf("unable to get settlement address from "+
+			"peer(%v): %v", peer.Name, err)
+	}
+
+	info, err := c.ValidateAddress(resp.Address)
+	if err != nil {
+		return nil, errors.Errorf("peer(%v) returned invalid settlement "+
+			"address(%v): %v", peer.Name, resp.Address, err)
+	}
+
+	id, err := newFederationID()
+	if err != nil {
+		return nil, err
+	}
+
+	memo := "settlement " + id
+	err = s.checkSendHook(ctx, &connectors.PaymentIntent{
+		Method: "SettleFederation",
+		Asset:  asset,
+		Media:  connectors.Blockchain,
+		Outputs: []*connectors.PaymentOutput{{
+			Address: info.Address,
+			Amount:  amount.String(),
+		}},
+		Memo: memo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Settlement is recorded before it is sent, so that funds which have
+	// left the wallet are always accounted in the position, even if
+	// server crashes in the middle.
+	now := connectors.NowInMilliSeconds()
+	entry := &connectors.FederationEntry{
+		ID:        id,
+		Peer:      peer.Name,
+		Kind:      connectors.FederationSettlement,
+		Direction: connectors.Outgoing,
+		Status:    connectors.FederationPending,
+		Asset:     asset,
+		Amount:    amount,
+		Memo:      memo,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.federation.store.SaveFederationEntry(entry); err != nil {
+		return nil, errors.Errorf("unable to save settlement: %v", err)
+	}
+
+	payment, err := c.SendPayment(info.Address, amount.String(), memo)
+	if err != nil {
+		entry.Status = connectors.FederationRejected
+		entry.FailureReason = err.Error()
+		entry.UpdatedAt = connectors.NowInMilliSeconds()
+		if err := s.federation.store.SaveFederationEntry(entry); err != nil {
+			log.Errorf("Unable to fail settlement(%v): %v", entry.ID, err)
+		}
+
+		return nil, errors.Errorf("unable to send settlement: %v", err)
+	}
+
+	entry.PaymentID = payment.PaymentID
+	entry.TxID = payment.MediaID
+	entry.UpdatedAt = connectors.NowInMilliSeconds()
+	if err := s.federation.store.SaveFederationEntry(entry); err != nil {
+		return nil, errors.Errorf("unable to save settlement(%v) "+
+			"tx(%v): %v", entry.ID, entry.TxID, err)
+	}
+
+	log.Infof("Settlement(%v) of %v %v is sent to peer(%v), tx(%v)",
+		entry.ID, amount, asset, peer.Name, entry.TxID)
+
+	// Funds are already sent, so that failed notification is only
+	// logged, it is retried by the periodic settlement.
+	if err := s.deliverSettlement(entry); err != nil {
+		log.Errorf("Unable to notify peer about settlement: %v", err)
+	}
+
+	return entry, nil
+}
+
+//
+// TransferToPeer moves funds from the account of this server to the
+// account of the federation peer, e.g. the payserver of another region,
+// without the blockchain transaction. Account is debited right away,
+// transfer is completed once peer credits it, and net positions with the
+// peer are settled on-chain periodically.
+func (s *Server) TransferToPeer(ctx context.Context,
+	req *TransferToPeerRequest) (*FederationTransfer, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if s.federation == nil {
+		err := newErrMethodDisabled(common.GetFunctionName(),
+			"federationpeer")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	var (
+		asset  connectors.Asset
+		amount decimal.Decimal
+		err    error
+	)
+
+	peer, ok := s.federation.peers[req.Peer]
+	switch {
+	case !ok:
+		err = newErrInvalidArgument("peer")
+
+	case req.FromAccount == "" || !isValidAccount(req.FromAccount):
+		err = newErrInvalidArgument("from_account")
+
+	case req.ToAccount == "" || !isValidAccount(req.ToAccount):
+		err = newErrInvalidArgument("to_account")
+
+	case req.Asset == Asset_ASSET_NONE:
+		err = newErrInvalidArgument("asset")
+	}
+	if err == nil {
+		if asset, err = ConvertAssetFromProto(req.Asset); err != nil {
+			err = newErrInvalidArgument("asset")
+		}
+	}
+	if err == nil {
+		amount, err = decimal.NewFromString(req.Amount)
+		if err != nil || amount.Sign() <= 0 {
+			err = newErrInvalidArgument("amount")
+		}
+	}
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Positions are settled on-chain, that is why only blockchain balance
+	// of the served asset could be transferred.
+	if !s.isMediaSupported(asset, connectors.Blockchain) {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	id, err := newFederationID()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	now := connectors.NowInMilliSeconds()
+	debit := &connectors.Payment{
+		UpdatedAt: now,
+		Status:    connectors.Pending,
+		Direction: connectors.Outgoing,
+		System:    connectors.Internal,
+		Receipt:   peer.Name + "/" + req.ToAccount,
+		Asset:     asset,
+		Media:     connectors.Blockchain,
+		Amount:    amount,
+		MediaFee:  decimal.Zero,
+		MediaID:   id,
+		Memo:      req.Memo,
+		AccountID: req.FromAccount,
+	}
+	if debit.PaymentID, err = debit.GenPaymentID(); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	entry := &connectors.FederationEntry{
+		ID:          id,
+		Peer:        peer.Name,
+		Kind:        connectors.FederationTransfer,
+		Direction:   connectors.Outgoing,
+		Status:      connectors.FederationPending,
+		Asset:       asset,
+		Amount:      amount,
+		Account:     req.FromAccount,
+		PeerAccount: req.ToAccount,
+		Memo:        req.Memo,
+		PaymentID:   debit.PaymentID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.debitPeerTransfer(ctx, debit, entry); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Account is already debited, so that unreachable peer doesn't fail
+	// the transfer, it is delivered later by the periodic settlement.
+	s.federation.sendMtx.Lock()
+	stop := trackStage(ctx, stageNode)
+	err = s.deliverTransfer(entry)
+	stop()
+	s.federation.sendMtx.Unlock()
+	if err != nil {
+		log.Errorf("command(%v), id(%v), transfer(%v) is left pending: %v",
+			common.GetFunctionName(), requestID, entry.ID, err)
+	}
+
+	resp, err := convertFederationTransferToProto(entry)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// debitPeerTransfer checks that the account balance covers the transfer to
+// the peer, and saves its pending debit along with the ledger entry.
+func (s *Server) debitPeerTransfer(ctx context.Context,
+	debit *connectors.Payment, entry *connectors.FederationEntry) error {
+
+	// Transfers are serialized with the ones between the local accounts,
+	// so that concurrent transfers couldn't overdraw the account.
+	s.transferMtx.Lock()
+	defer s.transferMtx.Unlock()
+
+	stop := trackStage(ctx, stageDB)
+	balance, err := s.accountBalance(debit.AccountID, debit.Asset,
+		debit.Media)
+	stop()
+	if err != nil {
+		return newErrInternal(err.Error())
+	}
+
+	if debit.Amount.GreaterThan(balance) {
+		log.Errorf("Amount(%v) of transfer exceeds balance(%v) of "+
+			"account(%v)", debit.Amount, balance, debit.AccountID)
+		return newErrInvalidArgument("amount")
+	}
+
+	stop = trackStage(ctx, stageDB)
+	defer stop()
+
+	if err := s.paymentsStore.SavePayment(debit); err != nil {
+		return newErrInternal(err.Error())
+	}
+
+	if err := s.federation.store.SaveFederationEntry(entry); err != nil {
+		// Debit without the entry is never delivered, that is why it is
+		// failed, so that funds are returned to the account.
+		debit.Status = connectors.Failed
+		debit.FailureReason = err.Error()
+		debit.UpdatedAt = connectors.NowInMilliSeconds()
+		if err := s.paymentsStore.SavePayment(debit); err != nil {
+			log.Errorf("Unable to revert debit(%v) of account(%v): %v",
+				debit.PaymentID, debit.AccountID, err)
+		}
+
+		return newErrInternal(err.Error())
+	}
+
+	return nil
+}
+
+//
+// ListFederationPositions returns the net positions with the federation
+// peers, i.e. the amounts which are owed to the peers or by them and are
+// not settled yet.
+func (s *Server) ListFederationPositions(ctx context.Context,
+	req *EmptyRequest) (*ListFederationPositionsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if s.federation == nil {
+		err := newErrMethodDisabled(common.GetFunctionName(),
+			"federationpeer")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &ListFederationPositionsResponse{}
+	for _, name := range s.federation.peerNames() {
+		stop := trackStage(ctx, stageDB)
+		positions, pending, err := s.federationPositions(name)
+		stop()
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		pendingTransfers := make(map[connectors.Asset]int64)
+		for _, entry := range pending {
+			if entry.Kind == connectors.FederationTransfer {
+				pendingTransfers[entry.Asset]++
+			}
+		}
+
+		assets := make([]string, 0, len(positions))
+		for asset := range positions {
+			assets = append(assets, string(asset))
+		}
+		sort.Strings(assets)
+
+		for _, asset := range assets {
+			protoAsset, err := convertAssetToProto(connectors.Asset(asset))
+			if err != nil {
+				err := newErrInternal(err.Error())
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+				return nil, err
+			}
+
+			position := &FederationPosition{
+				Peer:   name,
+				Asset:  protoAsset,
+				Amount: positions[connectors.Asset(asset)].String(),
+			}
+			position.PendingTransfers = pendingTransfers[connectors.Asset(asset)]
+			resp.Positions = append(resp.Positions, position)
+		}
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// SettleFederation sends the amount which is owed to the federation peer
+// in the asset on-chain right away, without waiting for the periodic
+// settlement.
+func (s *Server) SettleFederation(ctx context.Context,
+	req *SettleFederationRequest) (*FederationPosition, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if s.federation == nil {
+		err := newErrMethodDisabled(common.GetFunctionName(),
+			"federationpeer")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	peer, ok := s.federation.peers[req.Peer]
+	if !ok {
+		err := newErrInvalidArgument("peer")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	asset, err := ConvertAssetFromProto(req.Asset)
+	if err != nil || req.Asset == Asset_ASSET_NONE {
+		err := newErrInvalidArgument("asset")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageNode)
+	_, err = s.settle(ctx, peer, asset, decimal.Zero)
+	stop()
+	if err != nil {
+		if _, ok := err.(Error); !ok {
+			err = newErrInternal(err.Error())
+		}
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop = trackStage(ctx, stageDB)
+	positions, pending, err := s.federationPositions(peer.Name)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &FederationPosition{
+		Peer:   peer.Name,
+		Asset:  req.Asset,
+		Amount: decimal.Zero.String(),
+	}
+	if position, ok := positions[asset]; ok {
+		resp.Amount = position.String()
+	}
+	for _, entry := range pending {
+		if entry.Kind == connectors.FederationTransfer &&
+			entry.Asset == asset {
+			resp.PendingTransfers++
+		}
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ReceiveTransfer credits the account of the server with the transfer from
+// the account of the peer. Transfer is idempotent by its id, so that it
+// could be retried if response has been lost.
+func (s *Server) ReceiveTransfer(ctx context.Context,
+	req *FederatedTransfer) (*ReceiveTransferResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	peer, err := s.authenticatePeer(ctx, common.GetFunctionName(), req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Invalid transfer is rejected in the response rather than with the
+	// error, so that peer could fail it instead of retrying.
+	reject := func(reason string) (*ReceiveTransferResponse, error) {
+		log.Warnf("Transfer(%v) from peer(%v) is rejected: %v",
+			req.TransferId, peer.Name, reason)
+		resp := &ReceiveTransferResponse{Reason: reason}
+		log.Tracef("command(%v), id(%v), response(%v)",
+			common.GetFunctionName(), requestID, convertProtoMessage(resp))
+		return resp, nil
+	}
+
+	if req.TransferId == "" || !isValidAccount(req.TransferId) {
+		return reject("invalid transfer id")
+	}
+
+	if req.ToAccount == "" || !isValidAccount(req.ToAccount) {
+		return reject("invalid account")
+	}
+
+	asset, err := ConvertAssetFromProto(req.Asset)
+	if err != nil || !s.isMediaSupported(asset, connectors.Blockchain) {
+		return reject("asset isn't supported")
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || amount.Sign() <= 0 {
+		return reject("invalid amount")
+	}
+
+	s.federation.receiveMtx.Lock()
+	defer s.federation.receiveMtx.Unlock()
+
+	stop := trackStage(ctx, stageDB)
+	existing, err := s.federation.store.FederationEntryByID(req.TransferId)
+	stop()
+	switch {
+	case err == nil:
+		if existing.Peer != peer.Name ||
+			existing.Kind != connectors.FederationTransfer ||
+			existing.Direction != connectors.Incoming {
+			return reject("transfer id is already used")
+		}
+
+		// Transfer has been already credited, peer hasn't received
+		// the response.
+		resp := &ReceiveTransferResponse{Accepted: true}
+		log.Tracef("command(%v), id(%v), response(%v)",
+			common.GetFunctionName(), requestID, convertProtoMessage(resp))
+		return resp, nil
+
+	case err != connectors.FederationEntryNotFound:
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	now := connectors.NowInMilliSeconds()
+	credit := &connectors.Payment{
+		UpdatedAt: now,
+		Status:    connectors.Completed,
+		Direction: connectors.Incoming,
+		System:    connectors.Internal,
+		Receipt:   peer.Name + "/" + req.FromAccount,
+		Asset:     asset,
+		Media:     connectors.Blockchain,
+		Amount:    amount,
+		MediaFee:  decimal.Zero,
+		MediaID:   req.TransferId,
+		Memo:      req.Memo,
+		AccountID: req.ToAccount,
+	}
+	if credit.PaymentID, err = credit.GenPaymentID(); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Id of the credit is derived from the transfer id, that is why
+	// transfer which is retried after the failure in the middle
+	// overwrites the same payment.
+	stop = trackStage(ctx, stageDB)
+	err = s.paymentsStore.SavePayment(credit)
+	if err == nil {
+		err = s.federation.store.SaveFederationEntry(
+			&connectors.FederationEntry{
+				ID:          req.TransferId,
+				Peer:        peer.Name,
+				Kind:        connectors.FederationTransfer,
+				Direction:   connectors.Incoming,
+				Status:      connectors.FederationCompleted,
+				Asset:       asset,
+				Amount:      amount,
+				Account:     req.ToAccount,
+				PeerAccount: req.FromAccount,
+				Memo:        req.Memo,
+				PaymentID:   credit.PaymentID,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			})
+	}
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Transfer(%v) of %v %v from peer(%v) is credited to "+
+		"account(%v)", req.TransferId, amount, asset, peer.Name,
+		req.ToAccount)
+
+	resp := &ReceiveTransferResponse{Accepted: true}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ReceiveSettlement records the on-chain payment with which peer has
+// settled its position. Settlement is idempotent by its id. Settlement is
+// reported by the peer, that is why it changes the position only once its
+// funds have been received by the wallet, until then it is pending.
+func (s *Server) ReceiveSettlement(ctx context.Context,
+	req *FederatedSettlement) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	peer, err := s.authenticatePeer(ctx, common.GetFunctionName(), req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	var (
+		asset  connectors.Asset
+		amount decimal.Decimal
+	)
+
+	switch {
+	case req.SettlementId == "" || !isValidAccount(req.SettlementId):
+		err = newErrInvalidArgument("settlement_id")
+
+	case req.TxId == "":
+		err = newErrInvalidArgument("tx_id")
+	}
+	if err == nil {
+		if asset, err = ConvertAssetFromProto(req.Asset); err != nil {
+			err = newErrInvalidArgument("asset")
+		}
+	}
+	if err == nil {
+		amount, err = decimal.NewFromString(req.Amount)
+		if err != nil || amount.Sign() <= 0 {
+			err = newErrInvalidArgument("amount")
+		}
+	}
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	s.federation.receiveMtx.Lock()
+	defer s.federation.receiveMtx.Unlock()
+
+	stop := trackStage(ctx, stageDB)
+	existing, err := s.federation.store.FederationEntryByID(req.SettlementId)
+	stop()
+	switch {
+	case err == nil:
+		if existing.Peer != peer.Name ||
+			existing.Kind != connectors.FederationSettlement ||
+			existing.Direction != connectors.Incoming {
+			err := newErrInvalidArgument("settlement_id")
+			log.Errorf("command(%v), id(%v), error: %v, id is already "+
+				"used", common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		// Settlement which is reported again might have been received
+		// since the last report.
+		stop := trackStage(ctx, stageDB)
+		err := s.completeSettlements(peer.Name)
+		stop()
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		resp := &EmptyResponse{}
+		log.Tracef("command(%v), id(%v), response(%v)",
+			common.GetFunctionName(), requestID, convertProtoMessage(resp))
+		return resp, nil
+
+	case err != connectors.FederationEntryNotFound:
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop = trackStage(ctx, stageDB)
+	used, err := s.isSettlementTxUsed(req.TxId)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if used {
+		err := newErrInvalidArgument("tx_id")
+		log.Errorf("command(%v), id(%v), error: %v, tx(%v) is already "+
+			"used by another settlement", common.GetFunctionName(),
+			requestID, err, req.TxId)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Settlement funds are received by the wallet as the regular
+	// deposit, which doesn't belong to any account, so that only the
+	// position is changed. Settlement is completed right away if the
+	// deposit has been already received, otherwise it is completed by the
+	// settler once the deposit is synced.
+	now := connectors.NowInMilliSeconds()
+	entry := &connectors.FederationEntry{
+		ID:        req.SettlementId,
+		Peer:      peer.Name,
+		Kind:      connectors.FederationSettlement,
+		Direction: connectors.Incoming,
+		Status:    connectors.FederationPending,
+		Asset:     asset,
+		Amount:    amount,
+		TxID:      req.TxId,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	stop = trackStage(ctx, stageDB)
+	received, err := s.isSettlementReceived(entry)
+	if err == nil {
+		if received {
+			entry.Status = connectors.FederationCompleted
+		}
+		err = s.federation.store.SaveFederationEntry(entry)
+	}
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Settlement(%v) of %v %v is reported by peer(%v), tx(%v), "+
+		"status(%v)", req.SettlementId, amount, asset, peer.Name, req.TxId,
+		entry.Status)
+
+	resp := &EmptyResponse{}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// SettlementAddress returns the address to which peer should send the
+// settlement of the asset.
+func (s *Server) SettlementAddress(ctx context.Context,
+	req *SettlementAddressRequest) (*SettlementAddressResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	peer, err := s.authenticatePeer(ctx, common.GetFunctionName(), req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	asset, err := ConvertAssetFromProto(req.Asset)
+	if err != nil {
+		err := newErrInvalidArgument("asset")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	c, ok := s.blockchainConnectors[asset]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageNode)
+	address, err := s.createAddress(ctx, c, asset)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Settlement address(%v) of %v is issued to peer(%v)",
+		address, asset, peer.Name)
+
+	resp := &SettlementAddressResponse{Address: address}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// FederationSettler periodically delivers the transfers and settlements
+// which haven't been acknowledged by the federation peers, and settles the
+// positions which are owed to them on-chain.
+type FederationSettler struct {
+	server *Server
+
+	interval time.Duration
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewFederationSettler creates new instance of the settler, which settles
+// the positions with the given interval.
+func NewFederationSettler(s *Server,
+	interval time.Duration) *FederationSettler {
+	return &FederationSettler{
+		server:   s,
+		interval: interval,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the settle goroutine.
+func (r *FederationSettler) Start() {
+	r.wg.Add(1)
+	go r.settleHandler()
+}
+
+// Stop stops the settle goroutine and waits for it to exit.
+func (r *FederationSettler) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+// settleHandler settles the positions on start, so that entries left
+// pending before the restart are delivered, and then after every interval.
+//
+// NOTE: Should be run as goroutine.
+func (r *FederationSettler) settleHandler() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.settle()
+
+		select {
+		case <-ticker.C:
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// settle completes the incoming settlements of every peer which funds have
+// been received, delivers the pending entries of the peer, and then
+// settles the positions which are owed to it. Peer which is unreachable is
+// skipped until the next interval.
+func (r *FederationSettler) settle() {
+	s := r.server
+	for _, name := range s.federation.peerNames() {
+		s.federation.receiveMtx.Lock()
+		err := s.completeSettlements(name)
+		s.federation.receiveMtx.Unlock()
+		if err != nil {
+			log.Errorf("Unable to complete settlements of peer(%v): %v",
+				name, err)
+		}
+
+		s.federation.sendMtx.Lock()
+		err = s.deliverPending(name)
+		s.federation.sendMtx.Unlock()
+		if err != nil {
+			log.Errorf("Unable to deliver pending entries to peer(%v): %v",
+				name, err)
+			continue
+		}
+
+		positions, _, err := s.federationPositions(name)
+		if err != nil {
+			log.Errorf("Unable to get positions with peer(%v): %v", name,
+				err)
+			continue
+		}
+
+		for asset := range positions {
+			_, err := s.settle(context.Background(), s.federation.peers[name],
+				asset, s.federation.minSettlement)
+			if err != nil {
+				log.Errorf("Unable to settle %v position with peer(%v): %v",
+					asset, name, err)
+			}
+		}
+	}
+}
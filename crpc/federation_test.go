@@ -0,0 +1,259 @@
+package crpc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/db/sqlite"
+	"github.com/bitlum/connector/identity"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+// federate makes the servers of the harnesses federation peers of each
+// other, under the given names.
+func federate(t *testing.T, names [2]string, harnesses [2]*testHarness) {
+	dir, err := ioutil.TempDir("", "federation")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var keys [2]*identity.Key
+	for i, name := range names {
+		keys[i], err = identity.LoadKey(filepath.Join(dir, name+".key"))
+		if err != nil {
+			t.Fatalf("unable to create identity key: %v", err)
+		}
+	}
+
+	for i, h := range harnesses {
+		db, clearDB, err := sqlite.MakeTestDB()
+		if err != nil {
+			t.Fatalf("unable to create test database: %v", err)
+		}
+
+		clearHarness := h.clearDB
+		h.clearDB = func() {
+			clearDB()
+			clearHarness()
+		}
+
+		peer := 1 - i
+		h.server.identityKey = keys[i]
+		h.server.federation = newFederation(&FederationConfig{
+			Peers: []*FederationPeer{
+				NewFederationPeer(names[peer], keys[peer].PublicKey(),
+					harnesses[peer].conn),
+			},
+			Store: sqlite.NewFederationStore(db),
+		})
+	}
+}
+
+func TestFederationTransfer(t *testing.T) {
+	eu := newTestHarness(t)
+	defer eu.stop()
+
+	us := newTestHarness(t)
+	defer us.stop()
+
+	federate(t, [2]string{"eu", "us"}, [2]*testHarness{eu, us})
+
+	ctx := context.Background()
+
+	eu.seedPayments(&connectors.Payment{
+		PaymentID: "deposit",
+		UpdatedAt: 1,
+		Status:    connectors.Completed,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "btc-address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.New(2, 0),
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx-1",
+		AccountID: "merchant-eu",
+	})
+
+	transfer := func(peer, amount string) (*FederationTransfer, error) {
+		return eu.admin.TransferToPeer(ctx, &TransferToPeerRequest{
+			Peer:        peer,
+			Asset:       Asset_BTC,
+			FromAccount: "merchant-eu",
+			ToAccount:   "merchant-us",
+			Amount:      amount,
+			Memo:        "payout",
+		})
+	}
+
+	_, err := transfer("asia", "1")
+	expectInvalidArgument(t, err, "peer")
+
+	// Account couldn't be overdrawn.
+	_, err = transfer("us", "2.5")
+	expectInvalidArgument(t, err, "amount")
+
+	resp, err := transfer("us", "1.5")
+	if err != nil {
+		t.Fatalf("unable to transfer funds: %v", err)
+	}
+
+	if resp.Status != FederationStatus_FEDERATION_COMPLETED ||
+		resp.Peer != "us" || resp.Amount != "1.5" {
+		t.Fatalf("wrong transfer: %v", resp)
+	}
+
+	expectBalance := func(h *testHarness, account, expected string) {
+		t.Helper()
+
+		balance, err := h.server.accountBalance(account, connectors.BTC,
+			connectors.Blockchain)
+		if err != nil {
+			t.Fatalf("unable to get balance: %v", err)
+		}
+
+		if balance.String() != expected {
+			t.Fatalf("wrong balance of %v: %v", account, balance)
+		}
+	}
+
+	expectBalance(eu, "merchant-eu", "0.5")
+	expectBalance(us, "merchant-us", "1.5")
+
+	// Retried transfer isn't credited twice.
+	req := &FederatedTransfer{
+		TransferId:  resp.TransferId,
+		FromAccount: "merchant-eu",
+		ToAccount:   "merchant-us",
+		Asset:       Asset_BTC,
+		Amount:      "1.5",
+		Memo:        "payout",
+	}
+	signedCtx, err := eu.server.signFederationRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("unable to sign request: %v", err)
+	}
+
+	federation := NewFederationClient(us.conn)
+	received, err := federation.ReceiveTransfer(signedCtx, req)
+	if err != nil || !received.Accepted {
+		t.Fatalf("retried transfer isn't accepted: %v, %v", received, err)
+	}
+	expectBalance(us, "merchant-us", "1.5")
+
+	// Request which isn't signed by the peer is rejected.
+	req.TransferId = "federation-forged"
+	if _, err := federation.ReceiveTransfer(ctx, req); err == nil {
+		t.Fatalf("unsigned transfer is accepted")
+	}
+
+	// Signature covers the whole request, so that it couldn't be altered.
+	req.Amount = "100"
+	if _, err := federation.ReceiveTransfer(signedCtx, req); err == nil {
+		t.Fatalf("altered transfer is accepted")
+	}
+
+	// Invalid transfer is rejected in the response, so that it isn't
+	// retried by the peer.
+	req.Amount = "-1"
+	signedCtx, err = eu.server.signFederationRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("unable to sign request: %v", err)
+	}
+	received, err = federation.ReceiveTransfer(signedCtx, req)
+	if err != nil || received.Accepted {
+		t.Fatalf("invalid transfer isn't rejected: %v, %v", received, err)
+	}
+
+	expectPosition := func(h *testHarness, peer, expected string) {
+		t.Helper()
+
+		resp, err := h.admin.ListFederationPositions(ctx, &EmptyRequest{})
+		if err != nil {
+			t.Fatalf("unable to list positions: %v", err)
+		}
+
+		if len(resp.Positions) != 1 || resp.Positions[0].Peer != peer ||
+			resp.Positions[0].Asset != Asset_BTC ||
+			resp.Positions[0].Amount != expected {
+			t.Fatalf("wrong positions: %v", resp.Positions)
+		}
+	}
+
+	// Server which has debited the account holds the funds of the peer.
+	expectPosition(eu, "us", "1.5")
+	expectPosition(us, "eu", "-1.5")
+
+	// Server which is owed has nothing to settle.
+	settled, err := us.admin.SettleFederation(ctx, &SettleFederationRequest{
+		Peer:  "eu",
+		Asset: Asset_BTC,
+	})
+	if err != nil || settled.Amount != "-1.5" {
+		t.Fatalf("wrong settlement: %v, %v", settled, err)
+	}
+
+	settled, err = eu.admin.SettleFederation(ctx, &SettleFederationRequest{
+		Peer:  "us",
+		Asset: Asset_BTC,
+	})
+	if err != nil || settled.Amount != "0" {
+		t.Fatalf("wrong settlement: %v, %v", settled, err)
+	}
+
+	// Settlement is counted by the receiving server only once its funds
+	// have been received by the wallet.
+	expectPosition(eu, "us", "0")
+	expectPosition(us, "eu", "-1.5")
+
+	// Settlement is sent on-chain to the address of the peer.
+	payments, err := eu.payments.ListPayments(connectors.BTC, "",
+		connectors.Outgoing, connectors.Blockchain, connectors.External)
+	if err != nil {
+		t.Fatalf("unable to list payments: %v", err)
+	}
+
+	if len(payments) != 1 || payments[0].Receipt != "btc-address-1" ||
+		!payments[0].Amount.Equal(decimal.NewFromFloat(1.5)) {
+		t.Fatalf("wrong settlement payments: %v", payments)
+	}
+
+	// Settlement with the transaction which is already claimed is
+	// rejected, so that the same funds couldn't settle it twice.
+	settlement := &FederatedSettlement{
+		SettlementId: "federation-forged",
+		Asset:        Asset_BTC,
+		Amount:       "1.5",
+		TxId:         payments[0].MediaID,
+	}
+	signedCtx, err = eu.server.signFederationRequest(ctx, settlement)
+	if err != nil {
+		t.Fatalf("unable to sign request: %v", err)
+	}
+	_, err = federation.ReceiveSettlement(signedCtx, settlement)
+	expectInvalidArgument(t, err, "tx_id")
+
+	us.seedPayments(&connectors.Payment{
+		PaymentID: "settlement-deposit",
+		UpdatedAt: 1,
+		Status:    connectors.Completed,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   payments[0].Receipt,
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    payments[0].Amount,
+		MediaFee:  decimal.Zero,
+		MediaID:   payments[0].MediaID,
+	})
+
+	NewFederationSettler(us.server, time.Hour).settle()
+
+	expectPosition(us, "eu", "0")
+}
@@ -0,0 +1,267 @@
+package crpc
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+// maxFeeReportWindows is the maximum number of the windows in the fee
+// report.
+const maxFeeReportWindows = 1000
+
+// feeWindowStart returns the start of the UTC window of the period which
+// contains the given time.
+func feeWindowStart(t time.Time, period FeeReportPeriod) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case FeeReportPeriod_FEE_REPORT_WEEK:
+		// Weeks start on Monday, rather than on Sunday.
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case FeeReportPeriod_FEE_REPORT_MONTH:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// feeWindowEnd returns the start of the next window.
+func feeWindowEnd(start time.Time, period FeeReportPeriod) time.Time {
+	switch period {
+	case FeeReportPeriod_FEE_REPORT_WEEK:
+		return start.AddDate(0, 0, 7)
+	case FeeReportPeriod_FEE_REPORT_MONTH:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// feeTotalKey is the asset and media for which fees are summed up.
+type feeTotalKey struct {
+	asset connectors.Asset
+	media connectors.PaymentMedia
+}
+
+// feeTotal is the sum of the fees of the payments.
+type feeTotal struct {
+	fees         decimal.Decimal
+	amount       decimal.Decimal
+	payments     uint64
+	transactions map[string]struct{}
+}
+
+// feeTotals is the set of the fee sums per asset and media.
+type feeTotals map[feeTotalKey]*feeTotal
+
+// add adds the payment to the sum of its asset and media.
+func (t feeTotals) add(payment *connectors.Payment) {
+	key := feeTotalKey{asset: payment.Asset, media: payment.Media}
+
+	total, ok := t[key]
+	if !ok {
+		total = &feeTotal{
+			fees:         decimal.Zero,
+			amount:       decimal.Zero,
+			transactions: make(map[string]struct{}),
+		}
+		t[key] = total
+	}
+
+	total.fees = total.fees.Add(payment.MediaFee)
+	total.amount = total.amount.Add(payment.Amount)
+	total.payments++
+
+	// Payment without media id is counted as the separate transaction.
+	mediaID := payment.MediaID
+	if mediaID == "" {
+		mediaID = payment.PaymentID
+	}
+	total.transactions[mediaID] = struct{}{}
+}
+
+// toProto converts the sums to the proto totals sorted by asset and media.
+func (t feeTotals) toProto() ([]*FeeTotal, error) {
+	keys := make([]feeTotalKey, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].asset != keys[j].asset {
+			return keys[i].asset < keys[j].asset
+		}
+		return keys[i].media < keys[j].media
+	})
+
+	totals := make([]*FeeTotal, 0, len(keys))
+	for _, key := range keys {
+		asset, err := convertAssetToProto(key.asset)
+		if err != nil {
+			return nil, err
+		}
+
+		media, err := convertMediaToProto(key.media)
+		if err != nil {
+			return nil, err
+		}
+
+		total := t[key]
+		totals = append(totals, &FeeTotal{
+			Asset:        asset,
+			Media:        media,
+			Fees:         total.fees.String(),
+			Amount:       total.amount.String(),
+			Payments:     total.payments,
+			Transactions: uint64(len(total.transactions)),
+		})
+	}
+
+	return totals, nil
+}
+
+// feeReport sums up the fees of the completed outgoing external payments
+// within the windows. Internal payments don't touch the network, and
+// therefore don't pay the fees.
+func (s *Server) feeReport(ctx context.Context,
+	req *FeeReportRequest) (*FeeReportResponse, error) {
+
+	var asset connectors.Asset
+	if req.Asset != Asset_ASSET_NONE {
+		var err error
+		asset, err = ConvertAssetFromProto(req.Asset)
+		if err != nil {
+			return nil, newErrInvalidArgument("asset")
+		}
+	}
+
+	if _, ok := FeeReportPeriod_name[int32(req.Period)]; !ok {
+		return nil, newErrInvalidArgument("period")
+	}
+
+	if req.From < 0 {
+		return nil, newErrInvalidArgument("from")
+	}
+
+	to := req.To
+	if to == 0 {
+		to = connectors.NowInMilliSeconds()
+	}
+
+	if to < req.From {
+		return nil, newErrInvalidArgument("to")
+	}
+
+	from := req.From
+	if from == 0 {
+		from = to
+	}
+
+	// Windows are built up front, so that the ones in which nothing has
+	// been sent are reported as well.
+	var (
+		windows []*FeeWindow
+		start   = feeWindowStart(time.Unix(0, from*int64(time.Millisecond)),
+			req.Period)
+	)
+	for connectors.ConvertTimeToMilliSeconds(start) <= to {
+		if len(windows) == maxFeeReportWindows {
+			return nil, newErrInvalidArgument("from")
+		}
+
+		end := feeWindowEnd(start, req.Period)
+		windows = append(windows, &FeeWindow{
+			Start: connectors.ConvertTimeToMilliSeconds(start),
+			End:   connectors.ConvertTimeToMilliSeconds(end),
+		})
+		start = end
+	}
+
+	query := connectors.PaymentsQuery{
+		Asset:       asset,
+		Status:      connectors.Completed,
+		Direction:   connectors.Outgoing,
+		System:      connectors.External,
+		UpdatedFrom: windows[0].Start,
+		UpdatedTo:   to,
+		SortBy:      connectors.SortByUpdatedAt,
+		Ascending:   true,
+	}
+
+	var (
+		overall = make(feeTotals)
+		totals  = make([]feeTotals, len(windows))
+		current = 0
+	)
+
+	stop := trackStage(ctx, stageDB)
+	_, err := s.walkPayments(query, func(payment *connectors.Payment) error {
+		// Payments are walked in chronological order, so that windows
+		// are filled one after another.
+		for payment.UpdatedAt >= windows[current].End {
+			current++
+		}
+
+		if totals[current] == nil {
+			totals[current] = make(feeTotals)
+		}
+
+		totals[current].add(payment)
+		overall.add(payment)
+		return nil
+	})
+	stop()
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	for i, window := range windows {
+		window.Totals, err = totals[i].toProto()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+	}
+
+	resp := &FeeReportResponse{
+		Windows: windows,
+	}
+
+	resp.Totals, err = overall.toProto()
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	return resp, nil
+}
+
+//
+// FeeReport summarizes the network fees paid by the completed outgoing
+// payments per asset and media over the day, week or month windows.
+func (s *Server) FeeReport(ctx context.Context,
+	req *FeeReportRequest) (*FeeReportResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.feeReport(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
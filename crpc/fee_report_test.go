@@ -0,0 +1,183 @@
+package crpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+func TestFeeWindows(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2019, time.March, 13, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		period FeeReportPeriod
+		start  time.Time
+		end    time.Time
+	}{
+		{
+			period: FeeReportPeriod_FEE_REPORT_DAY,
+			start:  time.Date(2019, time.March, 13, 0, 0, 0, 0, time.UTC),
+			end:    time.Date(2019, time.March, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			period: FeeReportPeriod_FEE_REPORT_WEEK,
+			start:  time.Date(2019, time.March, 11, 0, 0, 0, 0, time.UTC),
+			end:    time.Date(2019, time.March, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			period: FeeReportPeriod_FEE_REPORT_MONTH,
+			start:  time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC),
+			end:    time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		start := feeWindowStart(now, test.period)
+		if !start.Equal(test.start) {
+			t.Fatalf("%v: wrong start: %v", test.period, start)
+		}
+
+		if end := feeWindowEnd(start, test.period); !end.Equal(test.end) {
+			t.Fatalf("%v: wrong end: %v", test.period, end)
+		}
+	}
+
+	// Sunday belongs to the week which has started on Monday.
+	sunday := time.Date(2019, time.March, 17, 23, 0, 0, 0, time.UTC)
+	start := feeWindowStart(sunday, FeeReportPeriod_FEE_REPORT_WEEK)
+	if !start.Equal(tests[1].start) {
+		t.Fatalf("wrong start of the week: %v", start)
+	}
+}
+
+func TestFeeReport(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	day := time.Date(2019, time.March, 13, 0, 0, 0, 0, time.UTC)
+	at := func(days int, hours time.Duration) int64 {
+		return connectors.ConvertTimeToMilliSeconds(day.AddDate(0, 0,
+			days).Add(hours))
+	}
+
+	newPayment := func(id, mediaID string, updatedAt int64,
+		media connectors.PaymentMedia, fee float64) *connectors.Payment {
+		return &connectors.Payment{
+			PaymentID: id,
+			UpdatedAt: updatedAt,
+			Status:    connectors.Completed,
+			System:    connectors.External,
+			Direction: connectors.Outgoing,
+			Receipt:   "receipt-" + id,
+			Asset:     connectors.BTC,
+			Media:     media,
+			Amount:    decimal.NewFromFloat(1),
+			MediaFee:  decimal.NewFromFloat(fee),
+			MediaID:   mediaID,
+		}
+	}
+
+	// Batched payments share the transaction.
+	batched1 := newPayment("1", "tx-1", at(0, time.Hour),
+		connectors.Blockchain, 0.001)
+	batched2 := newPayment("2", "tx-1", at(0, time.Hour),
+		connectors.Blockchain, 0.001)
+	lightning := newPayment("3", "hash-1", at(0, 2*time.Hour),
+		connectors.Lightning, 0.00001)
+	nextDay := newPayment("4", "tx-2", at(2, time.Hour),
+		connectors.Blockchain, 0.002)
+
+	// Incoming, pending and internal payments don't pay the fees.
+	incoming := newPayment("5", "tx-3", at(0, time.Hour),
+		connectors.Blockchain, 0)
+	incoming.Direction = connectors.Incoming
+
+	pending := newPayment("6", "tx-4", at(0, time.Hour),
+		connectors.Blockchain, 0.005)
+	pending.Status = connectors.Pending
+
+	internal := newPayment("7", "", at(0, time.Hour),
+		connectors.Blockchain, 0)
+	internal.System = connectors.Internal
+
+	h.seedPayments(batched1, batched2, lightning, nextDay, incoming, pending,
+		internal)
+
+	report := func(period FeeReportPeriod, from,
+		to int64) (*FeeReportResponse, error) {
+		return h.admin.FeeReport(ctx, &FeeReportRequest{
+			Period: period,
+			From:   from,
+			To:     to,
+		})
+	}
+
+	resp, err := report(FeeReportPeriod_FEE_REPORT_DAY, at(0, 3*time.Hour),
+		at(2, 3*time.Hour))
+	if err != nil {
+		t.Fatalf("unable to get report: %v", err)
+	}
+
+	// Day without payments is reported as well.
+	if len(resp.Windows) != 3 {
+		t.Fatalf("wrong number of windows: %v", len(resp.Windows))
+	}
+
+	first := resp.Windows[0]
+	if first.Start != at(0, 0) || first.End != at(1, 0) ||
+		len(first.Totals) != 2 {
+		t.Fatalf("wrong window: %v", first)
+	}
+
+	blockchain := first.Totals[0]
+	if blockchain.Media != Media_BLOCKCHAIN || blockchain.Fees != "0.002" ||
+		blockchain.Amount != "2" || blockchain.Payments != 2 ||
+		blockchain.Transactions != 1 {
+		t.Fatalf("wrong blockchain total: %v", blockchain)
+	}
+
+	if first.Totals[1].Media != Media_LIGHTNING ||
+		first.Totals[1].Fees != "0.00001" {
+		t.Fatalf("wrong lightning total: %v", first.Totals[1])
+	}
+
+	if len(resp.Windows[1].Totals) != 0 || len(resp.Windows[2].Totals) != 1 {
+		t.Fatalf("wrong windows: %v", resp.Windows)
+	}
+
+	if len(resp.Totals) != 2 || resp.Totals[0].Fees != "0.004" ||
+		resp.Totals[0].Transactions != 2 {
+		t.Fatalf("wrong totals: %v", resp.Totals)
+	}
+
+	// Whole report fits into the single week.
+	resp, err = report(FeeReportPeriod_FEE_REPORT_WEEK, at(0, 0),
+		at(2, 3*time.Hour))
+	if err != nil {
+		t.Fatalf("unable to get report: %v", err)
+	}
+
+	if len(resp.Windows) != 1 || len(resp.Windows[0].Totals) != 2 ||
+		resp.Windows[0].Totals[0].Fees != "0.004" {
+		t.Fatalf("wrong weekly report: %v", resp.Windows)
+	}
+
+	_, err = report(FeeReportPeriod_FEE_REPORT_DAY, at(2, 0), at(0, 0))
+	expectInvalidArgument(t, err, "to")
+
+	_, err = report(FeeReportPeriod_FEE_REPORT_DAY, -1, 0)
+	expectInvalidArgument(t, err, "from")
+
+	_, err = report(FeeReportPeriod(10), 0, 0)
+	expectInvalidArgument(t, err, "period")
+
+	// Number of windows is limited.
+	_, err = report(FeeReportPeriod_FEE_REPORT_DAY, 1, at(0, 0))
+	expectInvalidArgument(t, err, "from")
+}
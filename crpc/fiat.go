@@ -0,0 +1,95 @@
+package crpc
+
+import (
+	"strings"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+// FiatRates is the prices of the assets by the fiat currency, which are
+// used to quote receipts. Prices are set by the operator, so that server
+// doesn't depend on the third-party price feeds.
+type FiatRates map[string]map[connectors.Asset]decimal.Decimal
+
+// ParseFiatRate parses the price of the asset from the config in form of
+// "asset/currency=price", e.g. "BTC/USD=6500.5".
+func ParseFiatRate(s string) (connectors.Asset, string, decimal.Decimal,
+	error) {
+
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return "", "", decimal.Zero, errors.Errorf("fiat rate should be in "+
+			"form of asset/currency=price, got(%v)", s)
+	}
+
+	pair := strings.SplitN(parts[0], "/", 2)
+	if len(pair) != 2 || pair[1] == "" {
+		return "", "", decimal.Zero, errors.Errorf("fiat rate should be in "+
+			"form of asset/currency=price, got(%v)", s)
+	}
+
+	asset := strings.ToUpper(pair[0])
+	if _, ok := Asset_value[asset]; !ok || asset == Asset_ASSET_NONE.String() {
+		return "", "", decimal.Zero, errors.Errorf("unknown asset of the "+
+			"fiat rate, got(%v)", s)
+	}
+
+	price, err := decimal.NewFromString(parts[1])
+	if err != nil || price.Sign() <= 0 {
+		return "", "", decimal.Zero, errors.Errorf("price of the fiat rate "+
+			"should be positive number, got(%v)", s)
+	}
+
+	return connectors.Asset(asset), strings.ToUpper(pair[1]), price, nil
+}
+
+// Add sets the price of the asset in the currency.
+func (r FiatRates) Add(asset connectors.Asset, currency string,
+	price decimal.Decimal) {
+
+	currency = strings.ToUpper(currency)
+	if r[currency] == nil {
+		r[currency] = make(map[connectors.Asset]decimal.Decimal)
+	}
+	r[currency][asset] = price
+}
+
+// Price returns the price of the asset in the currency.
+func (r FiatRates) Price(asset connectors.Asset, currency string) (
+	decimal.Decimal, bool) {
+
+	price, ok := r[strings.ToUpper(currency)][asset]
+	return price, ok
+}
+
+// fiatToAsset converts the fiat amount into the amount of the asset, amount
+// is rounded up to the satoshi, so that the quoted amount covers the fiat
+// price.
+func fiatToAsset(amount, price decimal.Decimal) decimal.Decimal {
+	converted := amount.Div(price)
+	rounded := converted.Round(8)
+	if rounded.LessThan(converted) {
+		rounded = rounded.Add(decimal.New(1, -8))
+	}
+
+	return rounded
+}
+
+// newReceiptQuote converts the fiat price into the amount of the asset.
+func newReceiptQuote(asset connectors.Asset, media Media, price,
+	rate decimal.Decimal) (*ReceiptQuote, error) {
+
+	protoAsset, err := convertAssetToProto(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReceiptQuote{
+		Asset:  protoAsset,
+		Media:  media,
+		Amount: fiatToAsset(price, rate).String(),
+		Rate:   rate.String(),
+	}, nil
+}
@@ -0,0 +1,41 @@
+package crpc
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+)
+
+func TestParseFiatRate(t *testing.T) {
+	asset, currency, price, err := ParseFiatRate("btc/usd=6500.5")
+	if err != nil {
+		t.Fatalf("unable to parse fiat rate: %v", err)
+	}
+
+	if asset != connectors.BTC || currency != "USD" ||
+		!price.Equal(decimal.New(65005, -1)) {
+		t.Fatalf("wrong fiat rate: %v %v %v", asset, currency, price)
+	}
+
+	for _, s := range []string{"BTC=1", "BTC/=1", "XXX/USD=1",
+		"BTC/USD=-1", "BTC/USD=a"} {
+		if _, _, _, err := ParseFiatRate(s); err == nil {
+			t.Fatalf("fiat rate(%v) should be rejected", s)
+		}
+	}
+}
+
+func TestFiatToAsset(t *testing.T) {
+	// 10 USD by price of 3 USD is 3.33333333(3), amount is rounded up, so
+	// that it covers the price.
+	amount := fiatToAsset(decimal.New(10, 0), decimal.New(3, 0))
+	if !amount.Equal(decimal.New(333333334, -8)) {
+		t.Fatalf("wrong amount: %v", amount)
+	}
+
+	amount = fiatToAsset(decimal.New(10, 0), decimal.New(4, 0))
+	if !amount.Equal(decimal.New(25, -1)) {
+		t.Fatalf("wrong amount: %v", amount)
+	}
+}
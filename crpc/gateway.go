@@ -0,0 +1,758 @@
+package crpc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/identity"
+	"github.com/bitlum/connector/macaroons"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// maxGatewayBodySize is the maximum size of the request body, the largest
+// request is the batch of payments which is well below it.
+const maxGatewayBodySize = 1 << 20
+
+// gatewayHandler calls the method of the server with the decoded request.
+type gatewayHandler func(ctx context.Context, req proto.Message) (
+	proto.Message, error)
+
+// gatewayRoute maps HTTP method and path on the method of the server.
+type gatewayRoute struct {
+	method string
+
+	// pattern is the path of the endpoint, segments in braces are the
+	// names of the request fields, e.g. "/v1/payments/{payment_id}".
+	pattern []string
+
+	// rpcMethod is the name of the PayServer method, which is used to
+	// check the permission of the macaroon.
+	rpcMethod string
+
+	newRequest func() proto.Message
+	handler    gatewayHandler
+
+	// immutable returns true if the response of the route wouldn't change
+	// anymore, so that client could reuse it without asking the gateway,
+	// if nil responses are always revalidated.
+	immutable func(resp proto.Message) bool
+}
+
+// match returns request fields taken from the path if path is matching the
+// route pattern.
+func (r *gatewayRoute) match(path []string) (map[string]string, bool) {
+	if len(path) != len(r.pattern) {
+		return nil, false
+	}
+
+	fields := make(map[string]string)
+	for i, segment := range r.pattern {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			fields[strings.Trim(segment, "{}")] = path[i]
+			continue
+		}
+
+		if segment != path[i] {
+			return nil, false
+		}
+	}
+
+	return fields, true
+}
+
+// JSONNames is the naming of the message fields in the JSON output.
+type JSONNames string
+
+const (
+	// SnakeCaseNames are the names of the fields as they are declared in
+	// the proto file, e.g. "payment_id".
+	SnakeCaseNames JSONNames = "snake"
+
+	// CamelCaseNames are the lowerCamelCase names of the proto3 JSON
+	// mapping, e.g. "paymentId".
+	CamelCaseNames JSONNames = "camel"
+)
+
+// Marshaler returns the JSON marshaler which encodes fields with the names.
+func (n JSONNames) Marshaler() *jsonpb.Marshaler {
+	return &jsonpb.Marshaler{OrigName: n != CamelCaseNames}
+}
+
+// Gateway is the HTTP/JSON proxy which exposes merchant facing methods of
+// the server as REST endpoints, for the clients which are unable to use
+// gRPC. Messages are encoded in the same way as in gRPC JSON mapping, with
+// the configured naming of the fields. Requests are accepted with either
+// naming.
+type Gateway struct {
+	routes    []*gatewayRoute
+	marshaler *jsonpb.Marshaler
+
+	// macaroons verifies macaroons passed in the "Macaroon" header, if
+	// nil authentication is disabled.
+	macaroons *macaroons.Service
+
+	// apiKeys is used to verify API keys passed in the "Api-Key" header, if
+	// nil API keys are not required.
+	apiKeys connectors.APIKeysStore
+
+	// signer signs response bodies with the server identity key, if nil
+	// responses are not signed.
+	signer *identity.Key
+
+	// interceptor wraps the calls of the server methods in the same way as
+	// gRPC calls, e.g. to measure their latency, if nil methods are called
+	// directly.
+	interceptor grpc.UnaryServerInterceptor
+
+	// allowedOrigins is the list of the origins of the pages, other than
+	// the gateway itself, which are allowed to connect to the events
+	// endpoint, e.g. "https://dashboard.example.com".
+	allowedOrigins []string
+
+	// events is the WebSocket endpoint which streams payment updates, for
+	// the browser clients which are unable to hold the gRPC stream.
+	events http.Handler
+
+	// cacheMaxAge is the time for which client could reuse the immutable
+	// responses, if zero every response is revalidated.
+	cacheMaxAge time.Duration
+}
+
+// A compile time check to ensure that Gateway is the HTTP handler.
+var _ http.Handler = (*Gateway)(nil)
+
+// NewGateway creates REST endpoints which are calling the methods of the
+// server. If macaroon service or API keys store is nil, requests are not
+// authenticated by the macaroons or API keys respectively. If signer is
+// nil, responses are not signed. If interceptor is not nil, server methods are called through it.
+// Browser pages are able to connect to the events endpoint only from the
+// same host or from the allowed origins. Responses and events are encoded
+// with the given field names, snake case if it isn't specified. Immutable
+// responses, e.g. completed payments, could be reused by the clients for
+// the given max age.
+func NewGateway(s *Server, macaroonService *macaroons.Service,
+	apiKeys connectors.APIKeysStore, signer *identity.Key, interceptor grpc.UnaryServerInterceptor,
+	allowedOrigins []string, jsonNames JSONNames,
+	cacheMaxAge time.Duration) *Gateway {
+	g := &Gateway{
+		marshaler:      jsonNames.Marshaler(),
+		macaroons:      macaroonService,
+		apiKeys:        apiKeys,
+		signer:         signer,
+		interceptor:    interceptor,
+		allowedOrigins: allowedOrigins,
+		cacheMaxAge:    cacheMaxAge,
+	}
+	g.events = newEventsHandler(g, s)
+
+	g.route("POST", "/v1/receipts", "CreateReceipt",
+		func() proto.Message { return &CreateReceiptRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.CreateReceipt(ctx, req.(*CreateReceiptRequest))
+		})
+
+	g.route("POST", "/v1/addresses", "NewAddress",
+		func() proto.Message { return &NewAddressRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.NewAddress(ctx, req.(*NewAddressRequest))
+		})
+
+	g.route("GET", "/v1/receipts", "ListReceipts",
+		func() proto.Message { return &ListReceiptsRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListReceipts(ctx, req.(*ListReceiptsRequest))
+		})
+
+	g.route("GET", "/v1/receipts/quote", "QuoteReceipt",
+		func() proto.Message { return &QuoteReceiptRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.QuoteReceipt(ctx, req.(*QuoteReceiptRequest))
+		})
+
+	g.route("POST", "/v1/receipts/validate", "ValidateReceipt",
+		func() proto.Message { return &ValidateReceiptRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ValidateReceipt(ctx, req.(*ValidateReceiptRequest))
+		})
+
+	// Validation is also served on GET, so that decoded invoice, which
+	// never changes, could be cached by the clients. Addresses are always
+	// revalidated, because their reuse warnings are changing.
+	validateReceipt := g.route("GET", "/v1/receipts/validate",
+		"ValidateReceipt",
+		func() proto.Message { return &ValidateReceiptRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ValidateReceipt(ctx, req.(*ValidateReceiptRequest))
+		})
+	validateReceipt.immutable = func(resp proto.Message) bool {
+		return resp.(*ValidateReceiptResponse).GetInvoice() != nil
+	}
+
+	g.route("GET", "/v1/receipts/{receipt_id}", "ReceiptByID",
+		func() proto.Message { return &ReceiptByIDRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ReceiptByID(ctx, req.(*ReceiptByIDRequest))
+		})
+
+	g.route("POST", "/v1/receipts/{receipt_id}/refund_address",
+		"AttachRefundAddress",
+		func() proto.Message { return &AttachRefundAddressRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.AttachRefundAddress(ctx,
+				req.(*AttachRefundAddressRequest))
+		})
+
+	g.route("GET", "/v1/receipts/{receipt}/payments", "PaymentsByReceipt",
+		func() proto.Message { return &PaymentsByReceiptRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.PaymentsByReceipt(ctx, req.(*PaymentsByReceiptRequest))
+		})
+
+	g.route("GET", "/v1/balance", "Balance",
+		func() proto.Message { return &BalanceRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.Balance(ctx, req.(*BalanceRequest))
+		})
+
+	g.route("GET", "/v1/balance/history", "BalanceHistory",
+		func() proto.Message { return &BalanceHistoryRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.BalanceHistory(ctx, req.(*BalanceHistoryRequest))
+		})
+
+	g.route("GET", "/v1/fee", "EstimateFee",
+		func() proto.Message { return &EstimateFeeRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.EstimateFee(ctx, req.(*EstimateFeeRequest))
+		})
+
+	g.route("POST", "/v1/quotes", "QuotePayment",
+		func() proto.Message { return &QuotePaymentRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.QuotePayment(ctx, req.(*QuotePaymentRequest))
+		})
+
+	g.route("POST", "/v1/payments", "SendPayment",
+		func() proto.Message { return &SendPaymentRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.SendPayment(ctx, req.(*SendPaymentRequest))
+		})
+
+	g.route("POST", "/v1/payments/batch", "SendPayments",
+		func() proto.Message { return &SendPaymentsRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.SendPayments(ctx, req.(*SendPaymentsRequest))
+		})
+
+	g.route("GET", "/v1/payments", "ListPayments",
+		func() proto.Message { return &ListPaymentsRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListPayments(ctx, req.(*ListPaymentsRequest))
+		})
+
+	// Payment is always revalidated, even the completed one, because its
+	// labels and quarantine state are still changing.
+	g.route("GET", "/v1/payments/{payment_id}", "PaymentByID",
+		func() proto.Message { return &PaymentByIDRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.PaymentByID(ctx, req.(*PaymentByIDRequest))
+		})
+
+	g.route("POST", "/v1/payments/{payment_id}/labels", "LabelPayment",
+		func() proto.Message { return &LabelPaymentRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.LabelPayment(ctx, req.(*LabelPaymentRequest))
+		})
+
+	g.route("POST", "/v1/payments/{payment_id}/refund", "RefundPayment",
+		func() proto.Message { return &RefundPaymentRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.RefundPayment(ctx, req.(*RefundPaymentRequest))
+		})
+
+	g.route("POST", "/v1/payments/{payment_id}/cancel", "CancelPayment",
+		func() proto.Message { return &CancelPaymentRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.CancelPayment(ctx, req.(*CancelPaymentRequest))
+		})
+
+	g.route("GET", "/v1/payments/{payment_id}/proof", "PaymentProof",
+		func() proto.Message { return &PaymentProofRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.PaymentProof(ctx, req.(*PaymentProofRequest))
+		})
+
+	g.route("POST", "/v1/transfers", "TransferFunds",
+		func() proto.Message { return &TransferFundsRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.TransferFunds(ctx, req.(*TransferFundsRequest))
+		})
+
+	g.route("POST", "/v1/accounts", "CreateAccount",
+		func() proto.Message { return &CreateAccountRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.CreateAccount(ctx, req.(*CreateAccountRequest))
+		})
+
+	g.route("GET", "/v1/accounts", "ListAccounts",
+		func() proto.Message { return &ListAccountsRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListAccounts(ctx, req.(*ListAccountsRequest))
+		})
+
+	g.route("GET", "/v1/accounts/{account}/addresses", "ListDepositAddresses",
+		func() proto.Message { return &ListDepositAddressesRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListDepositAddresses(ctx,
+				req.(*ListDepositAddressesRequest))
+		})
+
+	g.route("GET", "/v1/accounts/{account}/statement", "AccountStatement",
+		func() proto.Message { return &AccountStatementRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.AccountStatement(ctx, req.(*AccountStatementRequest))
+		})
+
+	g.route("POST", "/v1/timelocks", "SendTimeLockedPayment",
+		func() proto.Message { return &SendTimeLockedPaymentRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.SendTimeLockedPayment(ctx,
+				req.(*SendTimeLockedPaymentRequest))
+		})
+
+	g.route("GET", "/v1/timelocks", "ListTimeLocks",
+		func() proto.Message { return &ListTimeLocksRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListTimeLocks(ctx, req.(*ListTimeLocksRequest))
+		})
+
+	g.route("GET", "/v1/payees", "ListPayees",
+		func() proto.Message { return &EmptyRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListPayees(ctx, req.(*EmptyRequest))
+		})
+
+	g.route("GET", "/v1/watch/addresses", "ListWatchAddresses",
+		func() proto.Message { return &ListWatchAddressesRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListWatchAddresses(ctx, req.(*ListWatchAddressesRequest))
+		})
+
+	g.route("GET", "/v1/watch/events", "ListWatchEvents",
+		func() proto.Message { return &ListWatchEventsRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.ListWatchEvents(ctx, req.(*ListWatchEventsRequest))
+		})
+
+	g.route("GET", "/v1/keys", "GetPublicKeys",
+		func() proto.Message { return &EmptyRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.GetPublicKeys(ctx, req.(*EmptyRequest))
+		})
+
+	g.route("GET", "/v1/info", "GetInfo",
+		func() proto.Message { return &EmptyRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.GetInfo(ctx, req.(*EmptyRequest))
+		})
+
+	g.route("GET", "/v1/health", "HealthCheck",
+		func() proto.Message { return &EmptyRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.HealthCheck(ctx, req.(*EmptyRequest))
+		})
+
+	g.route("GET", "/v1/assets", "Assets",
+		func() proto.Message { return &EmptyRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.Assets(ctx, req.(*EmptyRequest))
+		})
+
+	g.route("GET", "/v1/branding", "GetBranding",
+		func() proto.Message { return &EmptyRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return s.GetBranding(ctx, req.(*EmptyRequest))
+		})
+
+	return g
+}
+
+// route registers new endpoint which calls the PayServer method.
+func (g *Gateway) route(method, pattern, rpcMethod string,
+	newRequest func() proto.Message, handler gatewayHandler) *gatewayRoute {
+	route := &gatewayRoute{
+		method:     method,
+		pattern:    splitPath(pattern),
+		rpcMethod:  rpcMethod,
+		newRequest: newRequest,
+		handler:    handler,
+	}
+	g.routes = append(g.routes, route)
+	return route
+}
+
+// splitPath splits the path on the segments.
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// ServeHTTP decodes the request from the body in case of POST, and from the
+// path and query parameters in case of GET, calls the server and writes
+// JSON response. Requests on the events path are upgraded to WebSocket.
+// Specification of the endpoints and its UI are served without the
+// authentication, because they don't expose any data of the server.
+//
+// NOTE: Part of the http.Handler interface.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case eventsPath:
+		g.events.ServeHTTP(w, r)
+		return
+
+	case openAPIPath, docsPath:
+		g.serveDocs(w, r)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxGatewayBodySize)
+	path := splitPath(r.URL.Path)
+
+	var pathMatched bool
+	for _, route := range g.routes {
+		fields, ok := route.match(path)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+
+		if route.method != r.Method {
+			continue
+		}
+
+		ctx, err := g.authenticate(r.Context(), r.Header.Get(macaroonHeader),
+			r.Header.Get(apiKeyHeader), route.rpcMethod)
+		if err != nil {
+			g.writeError(w, gatewayStatus(err), err)
+			return
+		}
+
+		req := route.newRequest()
+		if err := decodeGatewayRequest(r, fields, req); err != nil {
+			g.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		// Peer is passed in the same way as in gRPC, so that calls are
+		// rate limited by the server per peer address.
+		if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+		}
+
+		resp, err := g.call(ctx, route, req)
+		if err != nil {
+			g.writeError(w, gatewayStatus(err), err)
+			return
+		}
+
+		var body bytes.Buffer
+		if err := g.marshaler.Marshal(&body, resp); err != nil {
+			log.Errorf("unable to encode gateway response: %v", err)
+			g.writeError(w, http.StatusInternalServerError,
+				newErrInternal(err.Error()))
+			return
+		}
+
+		if route.method == "GET" && g.writeCacheHeaders(w, r, route, resp,
+			body.Bytes()) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		g.writeBody(w, http.StatusOK, body.Bytes())
+		return
+	}
+
+	if pathMatched {
+		g.writeError(w, http.StatusMethodNotAllowed,
+			newErrInvalidArgument("method"))
+		return
+	}
+
+	g.writeError(w, http.StatusNotFound, newErrInvalidArgument("path"))
+}
+
+// writeCacheHeaders sets ETag and Cache-Control headers of the response,
+// and returns true if the copy of the client, given in the If-None-Match
+// header, is still valid. Responses are authenticated, that is why they
+// are cached only by the client, not by the shared proxies.
+func (g *Gateway) writeCacheHeaders(w http.ResponseWriter, r *http.Request,
+	route *gatewayRoute, resp proto.Message, body []byte) bool {
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	if g.cacheMaxAge > 0 && route.immutable != nil && route.immutable(resp) {
+		w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d",
+			int64(g.cacheMaxAge/time.Second)))
+	} else {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+
+	return etagMatches(r.Header.Get("If-None-Match"), etag)
+}
+
+// etagMatches returns true if the list of the entity tags, given in the
+// If-None-Match header, has the tag. Weak tags are compared as strong
+// ones, because body is the same for both.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// call calls the server method of the route, through the interceptor if
+// it is specified.
+func (g *Gateway) call(ctx context.Context, route *gatewayRoute,
+	req proto.Message) (proto.Message, error) {
+	if g.interceptor == nil {
+		return route.handler(ctx, req)
+	}
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: payServerServicePrefix + route.rpcMethod,
+	}
+
+	resp, err := g.interceptor(ctx, req, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return route.handler(ctx, req.(proto.Message))
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(proto.Message), nil
+}
+
+// macaroonHeader is the HTTP header through which hex encoded macaroon is
+// passed by the client.
+const macaroonHeader = "Macaroon"
+
+// apiKeyHeader is the HTTP header through which API key is passed by the
+// client.
+const apiKeyHeader = "Api-Key"
+
+// authenticate ensures that macaroon grants the permission, and API key has
+// the scope, required by the PayServer method. Returned context carries the
+// id of the API key.
+func (g *Gateway) authenticate(ctx context.Context, macaroon, apiKey,
+	rpcMethod string) (context.Context, error) {
+	fullMethod := payServerServicePrefix + rpcMethod
+
+	if g.macaroons != nil {
+		mdCtx := ctx
+		if macaroon != "" {
+			mdCtx = metadata.NewIncomingContext(ctx,
+				metadata.Pairs(MacaroonMetadataKey, macaroon))
+		}
+
+		if err := checkMacaroon(mdCtx, fullMethod, g.macaroons); err != nil {
+			return ctx, err
+		}
+	}
+
+	if g.apiKeys != nil {
+		mdCtx := ctx
+		if apiKey != "" {
+			mdCtx = metadata.NewIncomingContext(ctx,
+				metadata.Pairs(APIKeyMetadataKey, apiKey))
+		}
+
+		keyCtx, err := checkAPIKey(mdCtx, fullMethod, g.apiKeys)
+		if err != nil {
+			return ctx, err
+		}
+
+		id := apiKeyIDFromContext(keyCtx)
+		if id != "" {
+			ctx = context.WithValue(ctx, apiKeyIDKey{}, id)
+		}
+	}
+
+	return ctx, nil
+}
+
+// decodeGatewayRequest fills the request message with the fields from the
+// path, and from the body or query parameters.
+func decodeGatewayRequest(r *http.Request, fields map[string]string,
+	req proto.Message) error {
+
+	var body []byte
+	if r.Method == "POST" {
+		var err error
+		body, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Query parameters are converted to the JSON object, so that enums
+	// and numbers are decoded in the same way as in the body.
+	if len(bytes.TrimSpace(body)) == 0 {
+		values := make(map[string]interface{})
+		for key, value := range r.URL.Query() {
+			if key == includeQueryKey {
+				values[key] = includeQueryValue(value)
+				continue
+			}
+			values[key] = queryValue(value)
+		}
+
+		for key, value := range fields {
+			values[key] = value
+		}
+
+		var err error
+		body, err = json.Marshal(values)
+		if err != nil {
+			return err
+		}
+	} else if len(fields) != 0 {
+		var values map[string]interface{}
+		if err := json.Unmarshal(body, &values); err != nil {
+			return err
+		}
+
+		for key, value := range fields {
+			values[key] = value
+		}
+
+		var err error
+		body, err = json.Marshal(values)
+		if err != nil {
+			return err
+		}
+	}
+
+	return jsonpb.Unmarshal(bytes.NewReader(body), req)
+}
+
+// includeQueryKey is the query parameter with the list of the included
+// payment fields, e.g. "include=memo,confirmations".
+const includeQueryKey = "include"
+
+// includeQueryValue converts comma separated list of the included fields in
+// the JSON array of the enum names.
+func includeQueryValue(values []string) []string {
+	var fields []string
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			fields = append(fields, strings.ToUpper(field))
+		}
+	}
+	return fields
+}
+
+// queryValue converts query parameter in the JSON value, booleans are
+// converted explicitly, because they couldn't be decoded from strings.
+func queryValue(values []string) interface{} {
+	converted := make([]interface{}, len(values))
+	for i, value := range values {
+		switch value {
+		case "true":
+			converted[i] = true
+		case "false":
+			converted[i] = false
+		default:
+			converted[i] = value
+		}
+	}
+
+	if len(converted) == 1 {
+		return converted[0]
+	}
+
+	return converted
+}
+
+// gatewayStatus returns HTTP status code which corresponds to the error.
+func gatewayStatus(err error) int {
+	e, ok := err.(Error)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	switch e.code {
+	case ErrInvalidArgument, ErrAssetNotSupported, ErrNetworkNotSupported:
+		return http.StatusBadRequest
+	case ErrUnauthenticated:
+		return http.StatusUnauthorized
+	case ErrPermissionDenied, ErrPaymentHeld:
+		return http.StatusForbidden
+	case ErrRateLimited:
+		return http.StatusTooManyRequests
+	case ErrWithdrawalsPaused, ErrSendsSuspended:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError writes error in the JSON response.
+func (g *Gateway) writeError(w http.ResponseWriter, status int, err error) {
+	resp := struct {
+		Error string `json:"error"`
+	}{
+		Error: err.Error(),
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		log.Errorf("unable to encode gateway error: %v", err)
+		return
+	}
+
+	g.writeBody(w, status, append(body, '\n'))
+}
+
+// writeBody writes JSON response, and signs it with the identity key if
+// signing is enabled.
+func (g *Gateway) writeBody(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if g.signer != nil {
+		sig, err := g.signer.Sign(body)
+		if err != nil {
+			log.Errorf("unable to sign gateway response: %v", err)
+		} else {
+			w.Header().Set(identity.SignatureHeader, sig)
+			w.Header().Set(identity.KeyIDHeader, g.signer.ID())
+		}
+	}
+
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		log.Errorf("unable to write gateway response: %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package crpc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/identity"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestGatewayRouteMatch(t *testing.T) {
+	route := &gatewayRoute{pattern: splitPath("/v1/receipts/{receipt}/payments")}
+
+	fields, ok := route.match(splitPath("/v1/receipts/addr/payments"))
+	if !ok || fields["receipt"] != "addr" {
+		t.Fatalf("path should match, got(%v, %v)", fields, ok)
+	}
+
+	if _, ok := route.match(splitPath("/v1/receipts/addr")); ok {
+		t.Fatal("path with other length shouldn't match")
+	}
+
+	if _, ok := route.match(splitPath("/v1/payments/addr/payments")); ok {
+		t.Fatal("path with other segment shouldn't match")
+	}
+}
+
+func TestDecodeGatewayRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/payments?asset=BTC&limit=10"+
+		"&ascending=true&sort_by=SORT_AMOUNT", nil)
+
+	req := &ListPaymentsRequest{}
+	if err := decodeGatewayRequest(r, nil, req); err != nil {
+		t.Fatalf("unable to decode request: %v", err)
+	}
+
+	if req.Asset != Asset_BTC || req.Limit != 10 || !req.Ascending ||
+		req.SortBy != PaymentsSortBy_SORT_AMOUNT {
+		t.Fatalf("wrong request: %v", req)
+	}
+
+	r = httptest.NewRequest("POST", "/v1/payments",
+		strings.NewReader(`{"asset": "ETH", "amount": "0.1", "receipt": "0x1"}`))
+
+	sendReq := &SendPaymentRequest{}
+	if err := decodeGatewayRequest(r, nil, sendReq); err != nil {
+		t.Fatalf("unable to decode request: %v", err)
+	}
+
+	if sendReq.Asset != Asset_ETH || sendReq.Amount != "0.1" ||
+		sendReq.Receipt != "0x1" {
+		t.Fatalf("wrong request: %v", sendReq)
+	}
+
+	r = httptest.NewRequest("GET", "/v1/payments/id", nil)
+
+	byIDReq := &PaymentByIDRequest{}
+	err := decodeGatewayRequest(r, map[string]string{"payment_id": "id"},
+		byIDReq)
+	if err != nil {
+		t.Fatalf("unable to decode request: %v", err)
+	}
+
+	if byIDReq.PaymentId != "id" {
+		t.Fatalf("wrong request: %v", byIDReq)
+	}
+
+	r = httptest.NewRequest("GET", "/v1/payments?include=memo,"+
+		"confirmations", nil)
+
+	req = &ListPaymentsRequest{}
+	if err := decodeGatewayRequest(r, nil, req); err != nil {
+		t.Fatalf("unable to decode request: %v", err)
+	}
+
+	if len(req.Include) != 2 || req.Include[0] != PaymentInclude_MEMO ||
+		req.Include[1] != PaymentInclude_CONFIRMATIONS {
+		t.Fatalf("wrong request: %v", req)
+	}
+}
+
+func TestGatewaySignResponse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gateway")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := identity.LoadKey(filepath.Join(dir, "identity.key"))
+	if err != nil {
+		t.Fatalf("unable to create identity key: %v", err)
+	}
+
+	g := NewGateway(&Server{}, nil, nil, key, nil, nil, SnakeCaseNames, 0)
+
+	w := httptest.NewRecorder()
+	g.writeError(w, http.StatusNotFound, newErrInvalidArgument("path"))
+
+	if w.Header().Get(identity.KeyIDHeader) != key.ID() {
+		t.Fatalf("wrong key id: %v", w.Header().Get(identity.KeyIDHeader))
+	}
+
+	err = identity.Verify(key.PublicKey(), w.Body.Bytes(),
+		w.Header().Get(identity.SignatureHeader))
+	if err != nil {
+		t.Fatalf("response signature isn't valid: %v", err)
+	}
+
+	// Responses aren't signed if signer isn't specified.
+	g = NewGateway(&Server{}, nil, nil, nil, nil, nil, SnakeCaseNames, 0)
+
+	w = httptest.NewRecorder()
+	g.writeError(w, http.StatusNotFound, newErrInvalidArgument("path"))
+
+	if w.Header().Get(identity.SignatureHeader) != "" {
+		t.Fatal("response shouldn't be signed")
+	}
+}
+
+func TestGatewayBodyLimit(t *testing.T) {
+	g := NewGateway(&Server{}, nil, nil, nil, nil, nil, SnakeCaseNames, 0)
+
+	body := `{"memo": "` + strings.Repeat("a", maxGatewayBodySize) + `"}`
+	r := httptest.NewRequest("POST", "/v1/payments", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("too large body should be rejected, got(%v)", w.Code)
+	}
+}
+
+func TestGatewayInterceptor(t *testing.T) {
+	var method string
+	interceptor := func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+		method = info.FullMethod
+		return handler(ctx, req)
+	}
+
+	g := NewGateway(&Server{}, nil, nil, nil, interceptor, nil, SnakeCaseNames, 0)
+	g.route("GET", "/v1/test", "Balance",
+		func() proto.Message { return &EmptyRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return &EmptyResponse{}, nil
+		})
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("GET", "/v1/test", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("wrong status: %v", w.Code)
+	}
+
+	if method != payServerServicePrefix+"Balance" {
+		t.Fatalf("call wasn't intercepted, got(%v)", method)
+	}
+}
+
+func TestGatewayJSONNames(t *testing.T) {
+	tests := []struct {
+		names JSONNames
+		field string
+	}{
+		{names: SnakeCaseNames, field: `"receipt_id"`},
+		{names: CamelCaseNames, field: `"receiptId"`},
+		{names: "", field: `"receipt_id"`},
+	}
+
+	for _, test := range tests {
+		g := NewGateway(&Server{}, nil, nil, nil, nil, nil, test.names, 0)
+		g.route("GET", "/v1/test", "ReceiptByID",
+			func() proto.Message { return &EmptyRequest{} },
+			func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return &Receipt{ReceiptId: "1"}, nil
+			})
+
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, httptest.NewRequest("GET", "/v1/test", nil))
+
+		if !strings.Contains(w.Body.String(), test.field) {
+			t.Fatalf("(%v) field %v is missing: %v", test.names, test.field,
+				w.Body.String())
+		}
+	}
+}
+
+func TestGatewayCaching(t *testing.T) {
+	g := NewGateway(&Server{}, nil, nil, nil, nil, nil, SnakeCaseNames,
+		time.Hour)
+
+	payment := &Payment{PaymentId: "1", Status: PaymentStatus_PENDING}
+	g.route("GET", "/v1/payment", "PaymentByID",
+		func() proto.Message { return &EmptyRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return payment, nil
+		})
+
+	invoice := g.route("GET", "/v1/invoice", "ValidateReceipt",
+		func() proto.Message { return &EmptyRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return &ValidateReceiptResponse{
+				Data: &ValidateReceiptResponse_Invoice{
+					Invoice: &Invoice{},
+				},
+			}, nil
+		})
+	invoice.immutable = func(resp proto.Message) bool {
+		return resp.(*ValidateReceiptResponse).GetInvoice() != nil
+	}
+
+	get := func(path, etag string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		if etag != "" {
+			r.Header.Set("If-None-Match", etag)
+		}
+
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, r)
+		return w
+	}
+
+	// Payment is changing, that is why it is always revalidated.
+	w := get("/v1/payment", "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" ||
+		w.Header().Get("Cache-Control") != "private, no-cache" {
+		t.Fatalf("wrong response: %v %v", w.Code, w.Header())
+	}
+
+	w = get("/v1/payment", `"other", W/`+etag)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("wrong response: %v %v", w.Code, w.Body.String())
+	}
+
+	// Completed payment gets the new tag, and is still revalidated,
+	// because it could be labeled or quarantined afterwards.
+	payment.Status = PaymentStatus_COMPLETED
+
+	w = get("/v1/payment", etag)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag ||
+		w.Header().Get("Cache-Control") != "private, no-cache" {
+		t.Fatalf("wrong response: %v %v", w.Code, w.Header())
+	}
+
+	// Decoded invoice never changes, and could be reused.
+	w = get("/v1/invoice", "")
+	if w.Code != http.StatusOK ||
+		w.Header().Get("Cache-Control") != "private, max-age=3600" {
+		t.Fatalf("wrong response: %v %v", w.Code, w.Header())
+	}
+}
@@ -0,0 +1,364 @@
+package crpc
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/db/sqlite"
+	"github.com/bitlum/connector/features"
+	"github.com/bitlum/connector/locale"
+	"github.com/bitlum/connector/metrics/rpc"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	// bufSize is the size of the in-memory connection buffer of the test
+	// server.
+	bufSize = 1024 * 1024
+
+	// mockConfirmations is the number of confirmations after which
+	// payment of the mock connector is completed.
+	mockConfirmations = 3
+)
+
+// mockBlockchainConnector is the blockchain connector which doesn't talk to
+// the node. Addresses are generated sequentially, and sent payments are
+// saved in the payments store the same way real connectors do, so that
+// their state transitions could be driven by the test.
+type mockBlockchainConnector struct {
+	asset connectors.Asset
+	store connectors.PaymentsStore
+
+	mtx       sync.Mutex
+	addresses int
+	sent      int
+	fee       decimal.Decimal
+	balance   decimal.Decimal
+
+	// sendErr, if set, is returned by the media on the broadcast of the
+	// payment, in this case payment is failed.
+	sendErr error
+
+	// locked is the set of the payments which outputs are locked.
+	locked map[string]struct{}
+}
+
+// Runtime check to ensure that mockBlockchainConnector implements
+// connectors.BlockchainConnector interface.
+var _ connectors.BlockchainConnector = (*mockBlockchainConnector)(nil)
+
+// Runtime check to ensure that mockBlockchainConnector implements
+// connectors.QuarantineLocker interface.
+var _ connectors.QuarantineLocker = (*mockBlockchainConnector)(nil)
+
+func newMockBlockchainConnector(asset connectors.Asset,
+	store connectors.PaymentsStore) *mockBlockchainConnector {
+	return &mockBlockchainConnector{
+		asset:   asset,
+		store:   store,
+		fee:     decimal.New(1, -4),
+		balance: decimal.New(10, 0),
+		locked:  make(map[string]struct{}),
+	}
+}
+
+// LockPaymentOutputs marks the outputs of the payment as locked.
+//
+// NOTE: Part of the connectors.QuarantineLocker interface.
+func (c *mockBlockchainConnector) LockPaymentOutputs(
+	payment *connectors.Payment) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.locked[payment.PaymentID] = struct{}{}
+	return nil
+}
+
+// UnlockPaymentOutputs marks the outputs of the payment as unlocked.
+//
+// NOTE: Part of the connectors.QuarantineLocker interface.
+func (c *mockBlockchainConnector) UnlockPaymentOutputs(
+	payment *connectors.Payment) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.locked, payment.PaymentID)
+	return nil
+}
+
+// isLocked returns whether the outputs of the payment are locked.
+func (c *mockBlockchainConnector) isLocked(paymentID string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	_, ok := c.locked[paymentID]
+	return ok
+}
+
+// CreateAddress is used to create deposit address.
+//
+// NOTE: Part of the connectors.BlockchainConnector interface.
+func (c *mockBlockchainConnector) CreateAddress() (string, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.addresses++
+	return fmt.Sprintf("%v-address-%v", strings.ToLower(string(c.asset)),
+		c.addresses), nil
+}
+
+// ConfirmedBalance return the amount of confirmed funds available for
+// account.
+//
+// NOTE: Part of the connectors.BlockchainConnector interface.
+func (c *mockBlockchainConnector) ConfirmedBalance() (decimal.Decimal, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.balance, nil
+}
+
+// PendingBalance return the amount of funds waiting to be confirmed.
+//
+// NOTE: Part of the connectors.BlockchainConnector interface.
+func (c *mockBlockchainConnector) PendingBalance() (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+
+// SendPayment saves the payment as waiting, and then as pending, unless
+// the broadcast error is set, in this case payment is failed.
+//
+// NOTE: Part of the connectors.BlockchainConnector interface.
+func (c *mockBlockchainConnector) SendPayment(address, amount,
+	memo string) (*connectors.Payment, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return nil, errors.Errorf("unable to parse amount: %v", err)
+	}
+
+	if value.Add(c.fee).GreaterThan(c.balance) {
+		return nil, errors.New("insufficient funds")
+	}
+
+	c.sent++
+	txID := fmt.Sprintf("tx-%v", c.sent)
+
+	payment := &connectors.Payment{
+		PaymentID: connectors.GeneratePaymentID(txID, address),
+		UpdatedAt: connectors.NowInMilliSeconds(),
+		Status:    connectors.Waiting,
+		System:    connectors.External,
+		Direction: connectors.Outgoing,
+		Receipt:   address,
+		Asset:     c.asset,
+		Media:     connectors.Blockchain,
+		Amount:    value,
+		MediaFee:  c.fee,
+		MediaID:   txID,
+		Memo:      memo,
+	}
+
+	if err := c.store.SavePayment(payment); err != nil {
+		return nil, err
+	}
+
+	if c.sendErr != nil {
+		payment.Status = connectors.Failed
+		payment.FailureReason = c.sendErr.Error()
+		if err := c.store.SavePayment(payment); err != nil {
+			return nil, err
+		}
+
+		return nil, c.sendErr
+	}
+
+	payment.Status = connectors.Pending
+	payment.Detail = &connectors.BlockchainPendingDetails{
+		ConfirmationsLeft: mockConfirmations,
+	}
+	if err := c.store.SavePayment(payment); err != nil {
+		return nil, err
+	}
+
+	c.balance = c.balance.Sub(value).Sub(c.fee)
+	return payment, nil
+}
+
+// ValidateAddress accepts every non empty address as is.
+//
+// NOTE: Part of the connectors.BlockchainConnector interface.
+func (c *mockBlockchainConnector) ValidateAddress(
+	address string) (*connectors.AddressInfo, error) {
+	if address == "" {
+		return nil, errors.New("empty address")
+	}
+
+	return &connectors.AddressInfo{Address: address}, nil
+}
+
+// EstimateFee returns the fixed fee.
+//
+// NOTE: Part of the connectors.BlockchainConnector interface.
+func (c *mockBlockchainConnector) EstimateFee(
+	amount string) (decimal.Decimal, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.fee, nil
+}
+
+// WatchAddress does nothing.
+//
+// NOTE: Part of the connectors.BlockchainConnector interface.
+func (c *mockBlockchainConnector) WatchAddress(address string) error {
+	return nil
+}
+
+// confirm sets the number of the confirmations of the payment, payment is
+// completed once it has enough of them, the same way as the sync of the
+// real connector does.
+func (c *mockBlockchainConnector) confirm(paymentID string,
+	confirmations int64) error {
+	payment, err := c.store.PaymentByID(paymentID)
+	if err != nil {
+		return err
+	}
+
+	updated := *payment
+	updated.UpdatedAt = connectors.NowInMilliSeconds()
+	updated.Detail = &connectors.BlockchainPendingDetails{
+		Confirmations:     confirmations,
+		ConfirmationsLeft: mockConfirmations - confirmations,
+	}
+
+	if confirmations >= mockConfirmations {
+		updated.Status = connectors.Completed
+	}
+
+	return c.store.SavePayment(&updated)
+}
+
+// testHarness is the RPC server which is running in-process on the
+// in-memory connection, backed by the temporary database and the mock
+// connectors, so that handlers could be tested through the gRPC client.
+type testHarness struct {
+	t *testing.T
+
+	server   *Server
+	client   PayServerClient
+	admin    AdminClient
+	payments connectors.PaymentsStore
+	receipts connectors.ReceiptsStore
+	btc      *mockBlockchainConnector
+
+	grpcServer *grpc.Server
+	conn       *grpc.ClientConn
+	clearDB    func()
+}
+
+// newTestHarness starts the server with the mock BTC blockchain connector.
+// Server options might be used to install the interceptors of the calls.
+// Server fields might be adjusted by the test before the first call.
+func newTestHarness(t *testing.T, opts ...grpc.ServerOption) *testHarness {
+	db, clearDB, err := sqlite.MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+
+	payments := connectors.NewPaymentsBroadcaster(sqlite.NewPaymentStore(db))
+	receipts := sqlite.NewReceiptsStore(db)
+	btc := newMockBlockchainConnector(connectors.BTC, payments)
+
+	server, err := NewRPCServer("simnet",
+		map[connectors.Asset]connectors.BlockchainConnector{
+			connectors.BTC: btc,
+		},
+		map[connectors.Asset]connectors.LightningConnector{},
+		payments, sqlite.NewPayeesStore(db), sqlite.NewWatchStore(db),
+		sqlite.NewAPIKeysStore(db), sqlite.NewTimeLocksStore(db), receipts,
+		sqlite.NewTestPaymentsStore(db), sqlite.NewBrandingStore(db),
+		sqlite.NewBalanceSnapshotsStore(db), sqlite.NewWithdrawalPausesStore(db),
+		sqlite.NewAccountsStore(db), db,
+		nil, nil, nil, nil, nil, nil, nil, features.NewRegistry(),
+		locale.NewCatalog(),
+		&DiagnosticsInfo{}, false, &rpc.EmptyBackend{})
+	if err != nil {
+		clearDB()
+		t.Fatalf("unable to create server: %v", err)
+	}
+
+	listener := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer(opts...)
+	RegisterPayServerServer(grpcServer, server)
+	RegisterAdminServer(grpcServer, server)
+	RegisterFederationServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		grpcServer.Stop()
+		clearDB()
+		t.Fatalf("unable to connect to server: %v", err)
+	}
+
+	return &testHarness{
+		t:          t,
+		server:     server,
+		client:     NewPayServerClient(conn),
+		admin:      NewAdminClient(conn),
+		payments:   payments,
+		receipts:   receipts,
+		btc:        btc,
+		grpcServer: grpcServer,
+		conn:       conn,
+		clearDB:    clearDB,
+	}
+}
+
+// stop closes the connection, stops the server and removes the database.
+func (h *testHarness) stop() {
+	h.conn.Close()
+	h.grpcServer.Stop()
+	h.clearDB()
+}
+
+// seedPayments saves the payments in the store, as if they have been
+// synced by the connectors.
+func (h *testHarness) seedPayments(payments ...*connectors.Payment) {
+	for _, payment := range payments {
+		if err := h.payments.SavePayment(payment); err != nil {
+			h.t.Fatalf("unable to seed payment(%v): %v", payment.PaymentID,
+				err)
+		}
+	}
+}
+
+// expectInvalidArgument fails the test if the call hasn't been rejected
+// because of the given invalid argument.
+func expectInvalidArgument(t *testing.T, err error, arg string) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("call should be rejected because of '%v'", arg)
+	}
+
+	expected := newErrInvalidArgument(arg).Error()
+	if msg := status.Convert(err).Message(); msg != expected {
+		t.Fatalf("wrong error, expected(%v), got(%v)", expected, msg)
+	}
+}
@@ -0,0 +1,182 @@
+package crpc
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// defaultHealthCheckInterval is the period with which serving status of
+// the standard gRPC health service is updated.
+var defaultHealthCheckInterval = 15 * time.Second
+
+// payServerService is the name of the PayServer service, under which its
+// serving status is reported by the standard gRPC health service.
+const payServerService = "crpc.PayServer"
+
+//
+// HealthCheck checks the components of the server: reachability of the
+// blockchain daemons, sync of the lightning network nodes and writability
+// of the database. The same check drives the serving status of the
+// standard grpc.health.v1 service, which is used by the probes.
+func (s *Server) HealthCheck(ctx context.Context,
+	req *EmptyRequest) (*HealthCheckResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	stop := trackStage(ctx, stageNode)
+	resp := s.checkHealth()
+	stop()
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// checkHealth checks every component of the server, server is healthy only
+// if all of them are healthy.
+func (s *Server) checkHealth() *HealthCheckResponse {
+	resp := &HealthCheckResponse{
+		Healthy: true,
+	}
+
+	add := func(name string, err error) {
+		component := &ComponentHealth{
+			Name:    name,
+			Healthy: err == nil,
+		}
+
+		if err != nil {
+			component.Error = err.Error()
+			resp.Healthy = false
+		}
+
+		resp.Components = append(resp.Components, component)
+	}
+
+	if s.dbChecker != nil {
+		add("database", s.dbChecker.CheckWritable())
+	}
+
+	for asset, c := range s.blockchainConnectors {
+		name := strings.ToLower(string(asset)) + "_blockchain"
+
+		// Chain state is requested if it is reported by the connector,
+		// because it is cheaper than the balance.
+		if reporter, ok := c.(connectors.ChainReporter); ok {
+			_, err := reporter.ChainState()
+			add(name, err)
+			continue
+		}
+
+		_, err := c.ConfirmedBalance()
+		add(name, err)
+	}
+
+	for asset, c := range s.lightningConnectors {
+		name := strings.ToLower(string(asset)) + "_lightning"
+
+		info, err := c.Info()
+		if err == nil && !info.SyncedToChain {
+			err = errors.New("node isn't synced to chain")
+		}
+		add(name, err)
+	}
+
+	sort.Slice(resp.Components, func(i, j int) bool {
+		return resp.Components[i].Name < resp.Components[j].Name
+	})
+
+	return resp
+}
+
+// HealthReporter periodically checks the health of the server and updates
+// the serving status of the standard gRPC health service, so that load
+// balancer and probes could take unhealthy instance out of rotation.
+type HealthReporter struct {
+	server *Server
+	health *health.Server
+
+	checkInterval time.Duration
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewHealthReporter creates new instance of the reporter, which updates
+// serving status of the given health service.
+func NewHealthReporter(s *Server, h *health.Server) *HealthReporter {
+	return &HealthReporter{
+		server:        s,
+		health:        h,
+		checkInterval: defaultHealthCheckInterval,
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start launches the check goroutine.
+func (r *HealthReporter) Start() {
+	r.wg.Add(1)
+	go r.checkHandler()
+}
+
+// Stop stops the check goroutine and waits for it to exit.
+func (r *HealthReporter) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+// checkHandler updates the serving status after every check.
+//
+// NOTE: Should be run as goroutine.
+func (r *HealthReporter) checkHandler() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		r.report()
+
+		select {
+		case <-ticker.C:
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// report checks the health of the server and sets the serving status of
+// the overall server and of the PayServer service.
+func (r *HealthReporter) report() {
+	resp := r.server.checkHealth()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if !resp.Healthy {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+
+		for _, component := range resp.Components {
+			if !component.Healthy {
+				log.Warnf("Component(%v) is unhealthy: %v", component.Name,
+					component.Error)
+			}
+		}
+	}
+
+	r.health.SetServingStatus("", status)
+	r.health.SetServingStatus(payServerService, status)
+	r.health.SetServingStatus(versionedServiceDesc(
+		&_PayServer_serviceDesc).ServiceName, status)
+}
@@ -0,0 +1,53 @@
+package crpc
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// writeChecker is the database check which returns the given error.
+type writeChecker struct {
+	err error
+}
+
+func (c *writeChecker) CheckWritable() error {
+	return c.err
+}
+
+func TestHealthReporter(t *testing.T) {
+	checker := &writeChecker{}
+	s := &Server{dbChecker: checker}
+	h := health.NewServer()
+	reporter := NewHealthReporter(s, h)
+
+	status := func() healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := h.Check(context.Background(),
+			&healthpb.HealthCheckRequest{Service: payServerService})
+		if err != nil {
+			t.Fatalf("unable to check health: %v", err)
+		}
+		return resp.Status
+	}
+
+	reporter.report()
+	if status() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("healthy server should be serving")
+	}
+
+	checker.err = errors.New("disk is full")
+	reporter.report()
+	if status() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("unhealthy server shouldn't be serving")
+	}
+
+	resp := s.checkHealth()
+	if resp.Healthy || len(resp.Components) != 1 ||
+		resp.Components[0].Name != "database" ||
+		resp.Components[0].Error != "disk is full" {
+		t.Fatalf("wrong health: %v", resp)
+	}
+}
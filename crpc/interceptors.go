@@ -0,0 +1,254 @@
+package crpc
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"github.com/bitlum/connector/macaroons"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AdminTokenMetadataKey is the key of the gRPC metadata through which admin
+// token is passed by the client.
+const AdminTokenMetadataKey = "admin-token"
+
+// adminServicePrefix is the prefix of the full method name of the Admin
+// service methods.
+const adminServicePrefix = "/crpc.Admin/"
+
+// MacaroonMetadataKey is the key of the gRPC metadata through which hex
+// encoded macaroon is passed by the client.
+const MacaroonMetadataKey = "macaroon"
+
+// payServerServicePrefix is the prefix of the full method name of the
+// PayServer service methods.
+const payServerServicePrefix = "/crpc.PayServer/"
+
+// methodPermissions is the permission which macaroon should grant in order
+// to call the PayServer method.
+var methodPermissions = map[string]macaroons.Permission{
+	"CreateReceipt":         macaroons.Receive,
+	"NewAddress":            macaroons.Receive,
+	"ValidateReceipt":       macaroons.Read,
+	"ListReceipts":          macaroons.Read,
+	"ReceiptByID":           macaroons.Read,
+	"AttachRefundAddress":   macaroons.Receive,
+	"Balance":               macaroons.Read,
+	"BalanceHistory":        macaroons.Read,
+	"SubscribeBalance":      macaroons.Read,
+	"EstimateFee":           macaroons.Read,
+	"QuotePayment":          macaroons.Read,
+	"QuoteReceipt":          macaroons.Read,
+	"SendPayment":           macaroons.Send,
+	"SendPayments":          macaroons.Send,
+	"SendTimeLockedPayment": macaroons.Send,
+	"ListTimeLocks":         macaroons.Read,
+	"PaymentByID":           macaroons.Read,
+	"LabelPayment":          macaroons.Send,
+	"RefundPayment":         macaroons.Send,
+	"CancelPayment":         macaroons.Send,
+	"PaymentProof":          macaroons.Read,
+	"TransferFunds":         macaroons.Send,
+	"CreateAccount":         macaroons.Receive,
+	"ListAccounts":          macaroons.Read,
+	"ListDepositAddresses":  macaroons.Read,
+	"AccountStatement":      macaroons.Read,
+	"PaymentsByReceipt":     macaroons.Read,
+	"ListPayments":          macaroons.Read,
+	"StreamPayments":        macaroons.Read,
+	"ExportPayments":        macaroons.Read,
+	"SubscribePayments":     macaroons.Read,
+	"SubscribeReceipts":     macaroons.Read,
+	"ListPayees":            macaroons.Read,
+	"ListWatchAddresses":    macaroons.Read,
+	"ListWatchEvents":       macaroons.Read,
+	"GetPublicKeys":         macaroons.Read,
+	"GetInfo":               macaroons.Read,
+	"HealthCheck":           macaroons.Read,
+	"Assets":                macaroons.Read,
+	"GetBranding":           macaroons.Read,
+}
+
+// methodName returns the name of the method from the full gRPC method name,
+// e.g. "SyncUnspent" from "/crpc.Admin/SyncUnspent".
+func methodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+// ChainUnaryInterceptors combines interceptors in one, first interceptor is
+// the outermost one.
+func ChainUnaryInterceptors(
+	interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+
+		chain := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chain
+			chain = func(ctx context.Context, req interface{}) (
+				interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+
+		return chain(ctx, req)
+	}
+}
+
+// ChainStreamInterceptors combines interceptors in one, first interceptor is
+// the outermost one.
+func ChainStreamInterceptors(
+	interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+
+	return func(srv interface{}, stream grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		chain := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chain
+			chain = func(srv interface{}, stream grpc.ServerStream) error {
+				return interceptor(srv, stream, info, next)
+			}
+		}
+
+		return chain(srv, stream)
+	}
+}
+
+// checkAdminToken ensures that request context contains the admin token,
+// unless call has been already authorized by the API key with admin scope.
+func checkAdminToken(ctx context.Context, fullMethod, token string) error {
+	fullMethod = canonicalMethod(fullMethod)
+	if !strings.HasPrefix(fullMethod, adminServicePrefix) {
+		return nil
+	}
+
+	if isAdminByAPIKey(ctx) {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return newErrUnauthenticated(methodName(fullMethod))
+	}
+
+	// Constant time comparison is used, so that token couldn't be guessed
+	// by measuring the response time.
+	values := md.Get(AdminTokenMetadataKey)
+	if len(values) != 1 ||
+		subtle.ConstantTimeCompare([]byte(values[0]), []byte(token)) != 1 {
+		return newErrUnauthenticated(methodName(fullMethod))
+	}
+
+	return nil
+}
+
+// AdminAuthUnaryInterceptor rejects calls of the Admin service methods which
+// don't contain the valid admin token. Methods of other services are passed
+// as is.
+func AdminAuthUnaryInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+
+		if err := checkAdminToken(ctx, info.FullMethod, token); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// AdminAuthStreamInterceptor rejects calls of the Admin service streaming
+// methods which don't contain the valid admin token. Methods of other
+// services are passed as is.
+func AdminAuthStreamInterceptor(token string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		err := checkAdminToken(stream.Context(), info.FullMethod, token)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+// checkMacaroon ensures that request context contains the macaroon which
+// grants the permission required by the PayServer method.
+func checkMacaroon(ctx context.Context, fullMethod string,
+	service *macaroons.Service) error {
+	fullMethod = canonicalMethod(fullMethod)
+	if !strings.HasPrefix(fullMethod, payServerServicePrefix) {
+		return nil
+	}
+
+	method := methodName(fullMethod)
+	permission, ok := methodPermissions[method]
+	if !ok {
+		return newErrPermissionDenied(method, "method permission is unknown")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return newErrInvalidMacaroon(method, "macaroon is missing")
+	}
+
+	values := md.Get(MacaroonMetadataKey)
+	if len(values) != 1 {
+		return newErrInvalidMacaroon(method, "macaroon is missing")
+	}
+
+	data, err := hex.DecodeString(values[0])
+	if err != nil {
+		return newErrInvalidMacaroon(method, "macaroon should be hex encoded")
+	}
+
+	if err := service.Verify(data, permission); err != nil {
+		return newErrPermissionDenied(method, err.Error())
+	}
+
+	return nil
+}
+
+// MacaroonUnaryInterceptor rejects calls of the PayServer service methods
+// which don't contain the macaroon granting the required permission.
+// Methods of other services are passed as is.
+func MacaroonUnaryInterceptor(
+	service *macaroons.Service) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+
+		if err := checkMacaroon(ctx, info.FullMethod, service); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// MacaroonStreamInterceptor rejects calls of the PayServer service streaming
+// methods which don't contain the macaroon granting the required
+// permission. Methods of other services are passed as is.
+func MacaroonStreamInterceptor(
+	service *macaroons.Service) grpc.StreamServerInterceptor {
+
+	return func(srv interface{}, stream grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		err := checkMacaroon(stream.Context(), info.FullMethod, service)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
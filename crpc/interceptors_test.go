@@ -0,0 +1,80 @@
+package crpc
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitlum/connector/macaroons"
+)
+
+func TestMethodPermissions(t *testing.T) {
+	for _, method := range _PayServer_serviceDesc.Methods {
+		if _, ok := methodPermissions[method.MethodName]; !ok {
+			t.Fatalf("permission of method(%v) is missing",
+				method.MethodName)
+		}
+	}
+
+	for _, stream := range _PayServer_serviceDesc.Streams {
+		if _, ok := methodPermissions[stream.StreamName]; !ok {
+			t.Fatalf("permission of method(%v) is missing",
+				stream.StreamName)
+		}
+	}
+}
+
+func TestGatewayMacaroon(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crpc")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	service, err := macaroons.NewService(filepath.Join(dir, "macaroons.key"))
+	if err != nil {
+		t.Fatalf("unable to create macaroon service: %v", err)
+	}
+
+	mac, err := service.Mint(macaroons.Read)
+	if err != nil {
+		t.Fatalf("unable to mint macaroon: %v", err)
+	}
+
+	data, err := mac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unable to encode macaroon: %v", err)
+	}
+
+	gateway := NewGateway(&Server{}, service, nil, nil, nil, nil, SnakeCaseNames, 0)
+
+	tests := []struct {
+		macaroon string
+		status   int
+	}{
+		{macaroon: "", status: http.StatusUnauthorized},
+		{macaroon: "zz", status: http.StatusUnauthorized},
+		{macaroon: hex.EncodeToString(data), status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/v1/payments",
+			strings.NewReader(`{"asset": "BTC"}`))
+		if tt.macaroon != "" {
+			r.Header.Set(macaroonHeader, tt.macaroon)
+		}
+
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, r)
+
+		if w.Code != tt.status {
+			t.Fatalf("wrong status, want(%v), got(%v): %v", tt.status,
+				w.Code, w.Body.String())
+		}
+	}
+}
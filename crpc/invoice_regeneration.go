@@ -0,0 +1,199 @@
+package crpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// regenerationWindow is the period after the creation within which
+	// invoice is replaced, older invoices are considered to be abandoned.
+	regenerationWindow = 24 * time.Hour
+
+	// unpayableGracePeriod is the time after the creation of the invoice
+	// during which it isn't replaced because of the lack of the inbound
+	// liquidity, so that short drops of it, e.g. during channel
+	// rebalancing, don't churn invoices.
+	unpayableGracePeriod = 5 * time.Minute
+)
+
+// InvoiceRegenerator periodically replaces lightning network invoices which
+// have expired unpaid, or which couldn't be paid because of the lack of the
+// inbound liquidity, by the new ones with the same amount, description and
+// labels. Subscribers of the receipts are notified about the replacement,
+// so that checkout pages could show the new invoice.
+type InvoiceRegenerator struct {
+	server *Server
+
+	interval         time.Duration
+	maxRegenerations int
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewInvoiceRegenerator creates new instance of the regenerator, which
+// checks invoices with the given interval, and replaces the originally
+// requested invoice at most the given number of times.
+func NewInvoiceRegenerator(s *Server, interval time.Duration,
+	maxRegenerations int) *InvoiceRegenerator {
+	return &InvoiceRegenerator{
+		server:           s,
+		interval:         interval,
+		maxRegenerations: maxRegenerations,
+		quit:             make(chan struct{}),
+	}
+}
+
+// Start launches the regeneration goroutine.
+func (r *InvoiceRegenerator) Start() {
+	r.wg.Add(1)
+	go r.regenerateHandler()
+}
+
+// Stop stops the regeneration goroutine and waits for it to exit.
+func (r *InvoiceRegenerator) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+// regenerateHandler checks invoices on start and then after every interval.
+//
+// NOTE: Should be run as goroutine.
+func (r *InvoiceRegenerator) regenerateHandler() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.regenerate()
+
+		select {
+		case <-ticker.C:
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// regenerate replaces invoices of every lightning network connector which
+// have expired or became unpayable.
+func (r *InvoiceRegenerator) regenerate() {
+	now := time.Now()
+
+	for asset, c := range r.server.lightningConnectors {
+		receipts, _, err := r.server.receiptsStore.QueryReceipts(
+			connectors.ReceiptsQuery{
+				Asset: asset,
+				Media: connectors.Lightning,
+				CreatedFrom: connectors.ConvertTimeToMilliSeconds(
+					now.Add(-regenerationWindow)),
+				Unreplaced: true,
+			})
+		if err != nil {
+			log.Errorf("Unable to query %v invoices: %v", asset, err)
+			continue
+		}
+
+		// Capacity is requested only if there are invoices which might
+		// be unpayable, and only once per check.
+		var capacity *decimal.Decimal
+
+		for _, receipt := range receipts {
+			if receipt.Status == connectors.ReceiptPaid ||
+				receipt.Regenerations >= r.maxRegenerations {
+				continue
+			}
+
+			var reason string
+			switch {
+			case receipt.Status == connectors.ReceiptExpired:
+				reason = "invoice has expired"
+
+			case receipt.Amount.Sign() > 0 &&
+				receipt.CreatedAt <= connectors.ConvertTimeToMilliSeconds(
+					now.Add(-unpayableGracePeriod)):
+
+				if capacity == nil {
+					inbound, err := c.InboundCapacity()
+					if err != nil {
+						log.Errorf("Unable to get %v inbound capacity: %v",
+							asset, err)
+						inbound = decimal.Zero
+					}
+					capacity = &inbound
+				}
+
+				// Replacement carries the routing hints of the current
+				// channels, which might be able to receive the payment.
+				// Without active channels, or if capacity is unknown,
+				// replacement wouldn't be payable either.
+				if capacity.IsZero() || capacity.GreaterThanOrEqual(
+					receipt.Amount) {
+					continue
+				}
+				reason = "inbound capacity is insufficient"
+
+			default:
+				continue
+			}
+
+			replacement, err := r.replace(c, receipt)
+			if err != nil {
+				log.Errorf("Unable to replace receipt(%v): %v",
+					receipt.ReceiptID, err)
+				continue
+			}
+
+			log.Infof("Receipt(%v) has been replaced by receipt(%v), %v",
+				receipt.ReceiptID, replacement.ReceiptID, reason)
+		}
+	}
+}
+
+// replace creates new invoice with the same parameters as the receipt,
+// binds the receipt to it, and notifies subscribers about the replacement.
+func (r *InvoiceRegenerator) replace(c connectors.LightningConnector,
+	receipt *connectors.Receipt) (*connectors.Receipt, error) {
+	// Replacement commits to the same reference, so that it is still
+	// verifiable by the payer.
+	paymentRequest, invoice, err := createInvoice(c, receipt)
+	if err != nil {
+		return nil, errors.Errorf("unable to create invoice: %v", err)
+	}
+
+	createdAt := connectors.ConvertTimeToMilliSeconds(invoice.Timestamp)
+	expiry := connectors.ConvertDurationToMilliSeconds(invoice.Expiry())
+
+	replacement := &connectors.Receipt{
+		Receipt:       paymentRequest,
+		Asset:         receipt.Asset,
+		Media:         connectors.Lightning,
+		Amount:        receipt.Amount,
+		Description:   receipt.Description,
+		Reference:     receipt.Reference,
+		CreatedAt:     createdAt,
+		ExpiresAt:     createdAt + expiry,
+		Status:        connectors.ReceiptUnpaid,
+		Tenant:        receipt.Tenant,
+		AccountID:     receipt.AccountID,
+		Metadata:      receipt.Metadata,
+		Regenerations: receipt.Regenerations + 1,
+		RefundAddress: receipt.RefundAddress,
+	}
+	replacement.ReceiptID = replacement.GenReceiptID()
+
+	err = r.server.receiptsStore.ReplaceReceipt(receipt.ReceiptID,
+		replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	r.server.receiptEvents.notify(receipt.ReceiptID, replacement)
+	return replacement, nil
+}
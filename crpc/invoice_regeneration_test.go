@@ -0,0 +1,196 @@
+package crpc
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+// mockLightningConnector is the lightning connector which doesn't talk to
+// the node. Invoices are generated sequentially with the given timestamp.
+type mockLightningConnector struct {
+	mtx       sync.Mutex
+	invoices  int
+	timestamp time.Time
+	capacity  decimal.Decimal
+}
+
+// Runtime check to ensure that mockLightningConnector implements
+// connectors.LightningConnector interface.
+var _ connectors.LightningConnector = (*mockLightningConnector)(nil)
+
+func (c *mockLightningConnector) Info() (*connectors.LightningInfo, error) {
+	return &connectors.LightningInfo{}, nil
+}
+
+func (c *mockLightningConnector) CreateInvoice(receipt, amount,
+	description string) (string, *zpay32.Invoice, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.invoices++
+	return fmt.Sprintf("lnsb-invoice-%v", c.invoices),
+		&zpay32.Invoice{Timestamp: c.timestamp}, nil
+}
+
+func (c *mockLightningConnector) SendTo(invoice,
+	amount string) (*connectors.Payment, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *mockLightningConnector) ConfirmedBalance() (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+
+func (c *mockLightningConnector) PendingBalance() (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+
+func (c *mockLightningConnector) QueryRoutes(pubKey, amount string,
+	limit int32) ([]*lnrpc.Route, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *mockLightningConnector) ValidateInvoice(invoice,
+	amount string) (*zpay32.Invoice, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *mockLightningConnector) EstimateFee(
+	invoice string) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+
+func (c *mockLightningConnector) InboundCapacity() (decimal.Decimal, error) {
+	return c.capacity, nil
+}
+
+func TestInvoiceRegeneration(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	now := time.Now()
+
+	// Replacements are expired right away, so that the limit of the
+	// regenerations could be checked.
+	ln := &mockLightningConnector{
+		timestamp: now.Add(-2 * time.Hour),
+		capacity:  decimal.NewFromFloat(0.5),
+	}
+	h.server.lightningConnectors[connectors.BTC] = ln
+
+	newReceipt := func(receipt string, amount float64,
+		createdAt time.Time) *connectors.Receipt {
+		return &connectors.Receipt{
+			Receipt:     receipt,
+			Asset:       connectors.BTC,
+			Media:       connectors.Lightning,
+			Amount:      decimal.NewFromFloat(amount),
+			Description: "order",
+			CreatedAt:   connectors.ConvertTimeToMilliSeconds(createdAt),
+			ExpiresAt: connectors.ConvertTimeToMilliSeconds(
+				createdAt.Add(time.Hour)),
+			Tenant:   "merchant",
+			Metadata: map[string]string{"order": "1"},
+		}
+	}
+
+	expired := newReceipt("expired", 0.1, now.Add(-2*time.Hour))
+	unpayable := newReceipt("unpayable", 1, now.Add(-10*time.Minute))
+	fresh := newReceipt("fresh", 1, now)
+
+	for _, receipt := range []*connectors.Receipt{expired, unpayable, fresh} {
+		if err := h.receipts.SaveReceipt(receipt); err != nil {
+			t.Fatalf("unable to save receipt: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := h.client.SubscribeReceipts(ctx, &SubscribeReceiptsRequest{
+		ReceiptId: expired.ReceiptID,
+	})
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+
+	// Stream is established asynchronously, that is why replacement is
+	// made only after the subscription is registered.
+	for i := 0; ; i++ {
+		h.server.receiptEvents.mtx.Lock()
+		subscribed := len(h.server.receiptEvents.subscriptions) != 0
+		h.server.receiptEvents.mtx.Unlock()
+
+		if subscribed {
+			break
+		} else if i == 100 {
+			t.Fatalf("receipts subscription isn't registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	regenerator := NewInvoiceRegenerator(h.server, time.Hour, 1)
+	regenerator.regenerate()
+
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("unable to receive event: %v", err)
+	}
+
+	if e.Type != ReceiptEventType_RECEIPT_UPDATED ||
+		e.ReceiptId != expired.ReceiptID {
+		t.Fatalf("wrong event: %v", e)
+	}
+
+	if e.Receipt.Replaces != expired.ReceiptID || e.Receipt.Amount != "0.1" ||
+		e.Receipt.Description != "order" || e.Receipt.Metadata["order"] != "1" {
+		t.Fatalf("wrong replacement: %v", e.Receipt)
+	}
+
+	resp, err := h.client.ReceiptByID(ctx, &ReceiptByIDRequest{
+		ReceiptId: expired.ReceiptID,
+	})
+	if err != nil {
+		t.Fatalf("unable to get receipt: %v", err)
+	}
+
+	if resp.ReplacedBy != e.Receipt.ReceiptId {
+		t.Fatalf("receipt isn't bound to the replacement: %v", resp)
+	}
+
+	// Unpayable invoice is replaced, but the one which has been just
+	// created is left intact.
+	receipts, _, err := h.receipts.QueryReceipts(connectors.ReceiptsQuery{
+		Unreplaced: true,
+	})
+	if err != nil {
+		t.Fatalf("unable to query receipts: %v", err)
+	}
+
+	replaced := make(map[string]string)
+	for _, receipt := range receipts {
+		replaced[receipt.Replaces] = receipt.Receipt
+	}
+
+	if len(receipts) != 3 || replaced[unpayable.ReceiptID] == "" ||
+		replaced[""] != "fresh" {
+		t.Fatalf("wrong unreplaced receipts: %v", replaced)
+	}
+
+	// Replacements have expired too, but the limit of the regenerations
+	// is reached.
+	regenerator.regenerate()
+
+	if ln.invoices != 2 {
+		t.Fatalf("wrong number of created invoices: %v", ln.invoices)
+	}
+}
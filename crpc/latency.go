@@ -0,0 +1,167 @@
+package crpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/metrics/rpc"
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const (
+	// stageDB is the time spent in the requests to the database.
+	stageDB = "db"
+
+	// stageNode is the time spent in the connectors, which is mostly the
+	// requests to the blockchain or lightning daemon, and the selection
+	// of the outputs when payment is sent.
+	stageNode = "node"
+)
+
+// DefaultLatencyBudget is the latency budget of the methods which don't
+// have their own one.
+const DefaultLatencyBudget = time.Second
+
+// callTimings accumulates the time which call spends in the different
+// stages of the processing.
+type callTimings struct {
+	mtx    sync.Mutex
+	stages map[string]time.Duration
+}
+
+// timingsKey is the context key under which call timings are stored.
+type timingsKey struct{}
+
+// trackStage starts measuring time of the stage, returned function should be
+// called when stage is finished. If context doesn't carry the call timings
+// nothing is measured.
+func trackStage(ctx context.Context, stage string) func() {
+	timings, ok := ctx.Value(timingsKey{}).(*callTimings)
+	if !ok {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		timings.mtx.Lock()
+		timings.stages[stage] += time.Since(start)
+		timings.mtx.Unlock()
+	}
+}
+
+// breakdown returns the string representation of the stage timings, time
+// which isn't covered by any stage is reported as "other".
+func (t *callTimings) breakdown(total time.Duration) string {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	stages := make([]string, 0, len(t.stages))
+	for stage := range t.stages {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+
+	other := total
+	parts := make([]string, 0, len(stages)+1)
+	for _, stage := range stages {
+		parts = append(parts, fmt.Sprintf("%v=%v", stage, t.stages[stage]))
+		other -= t.stages[stage]
+	}
+	parts = append(parts, fmt.Sprintf("other=%v", other))
+
+	return strings.Join(parts, " ")
+}
+
+// LatencyBudgets is the time in which methods are expected to be processed,
+// calls which exceed it are reported as slow.
+type LatencyBudgets struct {
+	// Default is the budget of the methods which don't have their own
+	// one, if zero DefaultLatencyBudget is used.
+	Default time.Duration
+
+	// Methods is the budgets by the method name, e.g. "CreateReceipt".
+	Methods map[string]time.Duration
+}
+
+// budget returns the latency budget of the method.
+func (b *LatencyBudgets) budget(fullMethod string) time.Duration {
+	if budget, ok := b.Methods[methodName(fullMethod)]; ok {
+		return budget
+	}
+
+	if b.Default != 0 {
+		return b.Default
+	}
+
+	return DefaultLatencyBudget
+}
+
+// ParseLatencyBudget parses the budget from the config in form of
+// "method:duration", or just "duration" for the default budget, in which
+// case method is empty.
+func ParseLatencyBudget(s string) (string, time.Duration, error) {
+	var method, value string
+
+	parts := strings.SplitN(s, ":", 2)
+	switch len(parts) {
+	case 1:
+		value = parts[0]
+	default:
+		method, value = parts[0], parts[1]
+		if method == "" {
+			return "", 0, errors.Errorf("method of the latency budget "+
+				"is empty, got(%v)", s)
+		}
+	}
+
+	budget, err := time.ParseDuration(value)
+	if err != nil {
+		return "", 0, errors.Errorf("invalid latency budget(%v): %v", s,
+			err)
+	}
+
+	if budget <= 0 {
+		return "", 0, errors.Errorf("latency budget should be positive, "+
+			"got(%v)", s)
+	}
+
+	return method, budget, nil
+}
+
+// LatencyBudgetUnaryInterceptor measures the time of the calls, and reports
+// the calls which exceed the latency budget of the method, along with the
+// time spent in the database and connectors.
+func LatencyBudgetUnaryInterceptor(budgets *LatencyBudgets,
+	metrics rpc.MetricsBackend) grpc.UnaryServerInterceptor {
+
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+
+		timings := &callTimings{
+			stages: make(map[string]time.Duration),
+		}
+		ctx = context.WithValue(ctx, timingsKey{}, timings)
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		total := time.Since(start)
+
+		budget := budgets.budget(info.FullMethod)
+		if total > budget {
+			method := methodName(info.FullMethod)
+
+			log.Warnf("Slow call: method=%v duration=%v budget=%v %v "+
+				"failed=%v", method, total, budget,
+				timings.breakdown(total), err != nil)
+			metrics.AddSlowCall(method)
+		}
+
+		return resp, err
+	}
+}
@@ -0,0 +1,87 @@
+package crpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestParseLatencyBudget(t *testing.T) {
+	tests := []struct {
+		value   string
+		method  string
+		budget  time.Duration
+		wantErr bool
+	}{
+		{value: "2s", budget: 2 * time.Second},
+		{value: "CreateReceipt:500ms", method: "CreateReceipt",
+			budget: 500 * time.Millisecond},
+		{value: ":500ms", wantErr: true},
+		{value: "CreateReceipt:fast", wantErr: true},
+		{value: "CreateReceipt:0s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		method, budget, err := ParseLatencyBudget(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%v: wrong error, got(%v), want error(%v)", tt.value,
+				err, tt.wantErr)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if method != tt.method || budget != tt.budget {
+			t.Fatalf("%v: wrong budget, got(%v:%v), want(%v:%v)", tt.value,
+				method, budget, tt.method, tt.budget)
+		}
+	}
+}
+
+func TestLatencyBudgets(t *testing.T) {
+	budgets := &LatencyBudgets{
+		Methods: map[string]time.Duration{
+			"CreateReceipt": time.Millisecond,
+		},
+	}
+
+	if budgets.budget("/crpc.PayServer/CreateReceipt") != time.Millisecond {
+		t.Fatal("method should have its own budget")
+	}
+
+	if budgets.budget("/crpc.PayServer/Balance") != DefaultLatencyBudget {
+		t.Fatal("method without budget should have the default one")
+	}
+
+	budgets.Default = time.Minute
+	if budgets.budget("/crpc.PayServer/Balance") != time.Minute {
+		t.Fatal("method without budget should have configured default one")
+	}
+}
+
+func TestTrackStage(t *testing.T) {
+	// Stages of the context without timings are ignored.
+	trackStage(context.Background(), stageDB)()
+
+	timings := &callTimings{
+		stages: make(map[string]time.Duration),
+	}
+	ctx := context.WithValue(context.Background(), timingsKey{}, timings)
+
+	stop := trackStage(ctx, stageDB)
+	time.Sleep(time.Millisecond)
+	stop()
+
+	if timings.stages[stageDB] < time.Millisecond {
+		t.Fatalf("stage time isn't tracked, got(%v)", timings.stages[stageDB])
+	}
+
+	breakdown := timings.breakdown(time.Hour)
+	if !strings.HasPrefix(breakdown, "db=") ||
+		!strings.Contains(breakdown, " other=") {
+		t.Fatalf("wrong breakdown: %v", breakdown)
+	}
+}
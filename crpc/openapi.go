@@ -0,0 +1,505 @@
+package crpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitlum/connector/identity"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// openAPIPath is the path of the OpenAPI specification of the gateway,
+	// from which clients could be generated.
+	openAPIPath = "/v1/openapi.json"
+
+	// docsPath is the path of the page which renders the specification.
+	docsPath = "/docs"
+
+	// openAPIVersion is the version of the OpenAPI specification format.
+	openAPIVersion = "3.0.3"
+)
+
+// docsPage renders the specification with the Swagger UI loaded from the
+// CDN, so that the page doesn't require bundling of the UI in the binary.
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>PayServer REST API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@3/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@3/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({url: "` + openAPIPath + `", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// openAPIObject is the object of the specification, objects are encoded
+// with the sorted keys, so that specification is stable between releases.
+type openAPIObject map[string]interface{}
+
+// openAPIErrorStatuses are the statuses with which gateway returns the
+// error, see gatewayStatus.
+var openAPIErrorStatuses = map[int]string{
+	http.StatusBadRequest:          "Request is invalid",
+	http.StatusUnauthorized:        "Macaroon or API key is invalid",
+	http.StatusForbidden:           "Permission is denied",
+	http.StatusNotFound:            "Path doesn't exist",
+	http.StatusMethodNotAllowed:    "Path doesn't support the method",
+	http.StatusTooManyRequests:     "Rate limit is exceeded",
+	http.StatusInternalServerError: "Server or daemon has failed",
+	http.StatusServiceUnavailable:  "Withdrawals are paused",
+}
+
+// openAPIBuilder builds the specification from the gateway routes, schemas
+// of the messages are taken from the generated proto types, so that
+// specification is always in sync with the proto file.
+type openAPIBuilder struct {
+	gateway *Gateway
+	schemas openAPIObject
+}
+
+// OpenAPISpec returns the OpenAPI v3 specification of the gateway endpoints,
+// along with the authentication schemes and error model which are enabled
+// in the gateway.
+func (g *Gateway) OpenAPISpec() ([]byte, error) {
+	b := &openAPIBuilder{
+		gateway: g,
+		schemas: openAPIObject{
+			"Error": openAPIObject{
+				"type":     "object",
+				"required": []string{"error"},
+				"properties": openAPIObject{
+					"error": openAPIObject{
+						"type":        "string",
+						"description": "Description of the error",
+					},
+				},
+			},
+		},
+	}
+
+	paths := openAPIObject{}
+	operationIDs := make(map[string]struct{})
+
+	for _, route := range g.routes {
+		path := "/" + strings.Join(route.pattern, "/")
+		item, ok := paths[path].(openAPIObject)
+		if !ok {
+			item = openAPIObject{}
+			paths[path] = item
+		}
+
+		// The same method could be served on several HTTP methods, but
+		// operations should have unique ids.
+		operationID := route.rpcMethod
+		if _, ok := operationIDs[operationID]; ok {
+			operationID += strings.Title(strings.ToLower(route.method))
+		}
+		operationIDs[operationID] = struct{}{}
+
+		item[strings.ToLower(route.method)] = b.operation(route, operationID)
+	}
+
+	paths[eventsPath] = openAPIObject{
+		"get": openAPIObject{
+			"operationId": "Events",
+			"summary":     "Events",
+			"description": "WebSocket stream of the payment updates, " +
+				"browsers pass the macaroon and API key in the " +
+				"subprotocols '" + macaroonProtocolPrefix + "<hex>' and '" +
+				apiKeyProtocolPrefix + "<key>' along with '" +
+				eventsProtocol + "'.",
+			"responses": openAPIObject{
+				"101": openAPIObject{
+					"description": "Connection is upgraded to WebSocket",
+				},
+			},
+		},
+	}
+
+	components := openAPIObject{
+		"schemas": b.schemas,
+	}
+
+	spec := openAPIObject{
+		"openapi": openAPIVersion,
+		"info": openAPIObject{
+			"title":   "PayServer REST API",
+			"version": "v1",
+		},
+		"paths":      paths,
+		"components": components,
+	}
+
+	schemes := openAPIObject{}
+	requirement := openAPIObject{}
+	if g.macaroons != nil {
+		schemes["macaroon"] = openAPIObject{
+			"type":        "apiKey",
+			"in":          "header",
+			"name":        macaroonHeader,
+			"description": "Hex encoded macaroon",
+		}
+		requirement["macaroon"] = []string{}
+	}
+	if g.apiKeys != nil {
+		schemes["apiKey"] = openAPIObject{
+			"type": "apiKey",
+			"in":   "header",
+			"name": apiKeyHeader,
+		}
+		requirement["apiKey"] = []string{}
+	}
+	if len(schemes) != 0 {
+		components["securitySchemes"] = schemes
+		spec["security"] = []openAPIObject{requirement}
+	}
+
+	return json.MarshalIndent(spec, "", "  ")
+}
+
+// operation returns the operation of the route, fields of the request
+// which are not in the path are taken from the query parameters on GET,
+// and from the body on POST.
+func (b *openAPIBuilder) operation(route *gatewayRoute,
+	operationID string) openAPIObject {
+
+	req := route.newRequest()
+	reqType := reflect.TypeOf(req).Elem()
+	reqFields := b.fields(reqType)
+
+	var parameters []openAPIObject
+	inPath := make(map[string]struct{})
+	for _, segment := range route.pattern {
+		if !strings.HasPrefix(segment, "{") ||
+			!strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name := strings.Trim(segment, "{}")
+		inPath[name] = struct{}{}
+
+		schema := openAPIObject{"type": "string"}
+		for _, field := range reqFields {
+			if field.origName == name {
+				schema = field.schema
+			}
+		}
+
+		parameters = append(parameters, openAPIObject{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   schema,
+		})
+	}
+
+	op := openAPIObject{
+		"operationId": operationID,
+		"summary":     route.rpcMethod,
+	}
+
+	if route.method == "GET" {
+		for _, field := range reqFields {
+			if _, ok := inPath[field.origName]; ok || !field.scalar {
+				continue
+			}
+
+			param := openAPIObject{
+				"name":   field.origName,
+				"in":     "query",
+				"schema": field.schema,
+			}
+
+			// Included fields are given as the comma separated list of
+			// the lowercase names, see includeQueryValue.
+			if field.origName == includeQueryKey {
+				param["explode"] = false
+			}
+
+			parameters = append(parameters, param)
+		}
+	} else {
+		op["requestBody"] = openAPIObject{
+			"required": true,
+			"content":  jsonContent(b.messageRef(reqType)),
+		}
+	}
+
+	if len(parameters) != 0 {
+		op["parameters"] = parameters
+	}
+
+	respType := b.responseType(route)
+	responses := openAPIObject{
+		"200": b.response("Successful response", b.messageRef(respType)),
+	}
+	if route.method == "GET" {
+		responses["304"] = openAPIObject{
+			"description": "Response given in If-None-Match is still valid",
+		}
+	}
+	for status, description := range openAPIErrorStatuses {
+		responses[strconv.Itoa(status)] = b.response(description,
+			schemaRef("Error"))
+	}
+	op["responses"] = responses
+
+	return op
+}
+
+// responseType returns the type of the response message of the route, it
+// is taken from the return type of the server method.
+func (b *openAPIBuilder) responseType(route *gatewayRoute) reflect.Type {
+	method, ok := reflect.TypeOf(&Server{}).MethodByName(route.rpcMethod)
+	if !ok || method.Type.NumOut() == 0 {
+		return reflect.TypeOf(EmptyResponse{})
+	}
+
+	return method.Type.Out(0).Elem()
+}
+
+// response returns the JSON response, along with the headers with which it
+// is signed, if signing is enabled.
+func (b *openAPIBuilder) response(description string,
+	schema openAPIObject) openAPIObject {
+
+	resp := openAPIObject{
+		"description": description,
+		"content":     jsonContent(schema),
+	}
+
+	if b.gateway.signer == nil {
+		return resp
+	}
+
+	resp["headers"] = openAPIObject{
+		identity.SignatureHeader: openAPIObject{
+			"description": "Signature of the response body made by the " +
+				"server identity key",
+			"schema": openAPIObject{"type": "string"},
+		},
+		identity.KeyIDHeader: openAPIObject{
+			"description": "Id of the identity key, see GetPublicKeys",
+			"schema":      openAPIObject{"type": "string"},
+		},
+	}
+
+	return resp
+}
+
+// openAPIField is the field of the message as it is seen in JSON.
+type openAPIField struct {
+	origName string
+	name     string
+	schema   openAPIObject
+
+	// scalar is true if field could be passed in the query parameters.
+	scalar bool
+}
+
+// fields returns the fields of the message, fields of oneofs are returned
+// as the regular ones, because that is how they are encoded in JSON.
+func (b *openAPIBuilder) fields(t reflect.Type) []*openAPIField {
+	props := proto.GetProperties(t)
+
+	var fields []*openAPIField
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("protobuf") == "" {
+			continue
+		}
+		fields = append(fields, b.field(props.Prop[i], t.Field(i).Type))
+	}
+
+	var oneofs []string
+	for name := range props.OneofTypes {
+		oneofs = append(oneofs, name)
+	}
+	sort.Strings(oneofs)
+
+	for _, name := range oneofs {
+		oneof := props.OneofTypes[name]
+		fields = append(fields, b.field(oneof.Prop,
+			oneof.Type.Elem().Field(0).Type))
+	}
+
+	return fields
+}
+
+// field returns the field of the message with its schema.
+func (b *openAPIBuilder) field(p *proto.Properties,
+	t reflect.Type) *openAPIField {
+
+	name := p.OrigName
+	if !b.gateway.marshaler.OrigName {
+		name = p.JSONName
+	}
+
+	field := &openAPIField{
+		origName: p.OrigName,
+		name:     name,
+		scalar:   true,
+	}
+
+	switch {
+	case t.Kind() == reflect.Map:
+		field.scalar = false
+		field.schema = openAPIObject{
+			"type":                 "object",
+			"additionalProperties": b.schema(p.MapValProp, t.Elem()),
+		}
+
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		items := b.schema(p, t.Elem())
+		field.scalar = items["$ref"] == nil || p.Enum != ""
+		field.schema = openAPIObject{
+			"type":  "array",
+			"items": items,
+		}
+
+	default:
+		field.schema = b.schema(p, t)
+		field.scalar = t.Kind() != reflect.Ptr
+	}
+
+	return field
+}
+
+// schema returns the schema of the single value of the field, 64-bit
+// integers are strings as it is defined by the proto3 JSON mapping.
+func (b *openAPIBuilder) schema(p *proto.Properties,
+	t reflect.Type) openAPIObject {
+
+	if p != nil && p.Enum != "" {
+		return b.enumRef(p.Enum)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return openAPIObject{"type": "boolean"}
+	case reflect.Int32:
+		return openAPIObject{"type": "integer", "format": "int32"}
+	case reflect.Uint32:
+		return openAPIObject{"type": "integer", "format": "int64",
+			"minimum": 0}
+	case reflect.Int64:
+		return openAPIObject{"type": "string", "format": "int64"}
+	case reflect.Uint64:
+		return openAPIObject{"type": "string", "format": "uint64"}
+	case reflect.Float32:
+		return openAPIObject{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return openAPIObject{"type": "number", "format": "double"}
+	case reflect.Slice:
+		return openAPIObject{"type": "string", "format": "byte"}
+	case reflect.Ptr:
+		return b.messageRef(t.Elem())
+	default:
+		return openAPIObject{"type": "string"}
+	}
+}
+
+// messageRef returns the reference on the schema of the message, schema is
+// added to the components on the first reference.
+func (b *openAPIBuilder) messageRef(t reflect.Type) openAPIObject {
+	msg := reflect.New(t).Interface().(proto.Message)
+	name := schemaName(proto.MessageName(msg))
+	if _, ok := b.schemas[name]; ok {
+		return schemaRef(name)
+	}
+
+	// Schema is registered before the fields are walked, so that
+	// recursive messages are referencing themselves.
+	schema := openAPIObject{"type": "object"}
+	b.schemas[name] = schema
+
+	properties := openAPIObject{}
+	for _, field := range b.fields(t) {
+		properties[field.name] = field.schema
+	}
+	if len(properties) != 0 {
+		schema["properties"] = properties
+	}
+
+	return schemaRef(name)
+}
+
+// enumRef returns the reference on the schema of the enum, enums are
+// encoded with the names of their values.
+func (b *openAPIBuilder) enumRef(enum string) openAPIObject {
+	name := schemaName(enum)
+	if _, ok := b.schemas[name]; ok {
+		return schemaRef(name)
+	}
+
+	values := proto.EnumValueMap(enum)
+	names := make([]string, 0, len(values))
+	for value := range values {
+		names = append(names, value)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return values[names[i]] < values[names[j]]
+	})
+
+	b.schemas[name] = openAPIObject{
+		"type": "string",
+		"enum": names,
+	}
+
+	return schemaRef(name)
+}
+
+// schemaName returns the name of the schema of the proto type, package is
+// dropped because all types are declared in the same package.
+func schemaName(protoName string) string {
+	return strings.TrimPrefix(protoName, "crpc.")
+}
+
+// schemaRef returns the reference on the schema of the components.
+func schemaRef(name string) openAPIObject {
+	return openAPIObject{"$ref": "#/components/schemas/" + name}
+}
+
+// jsonContent returns the JSON content of the request or response.
+func jsonContent(schema openAPIObject) openAPIObject {
+	return openAPIObject{
+		"application/json": openAPIObject{"schema": schema},
+	}
+}
+
+// serveDocs writes the OpenAPI specification, or the page which renders it.
+func (g *Gateway) serveDocs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		g.writeError(w, http.StatusMethodNotAllowed,
+			newErrInvalidArgument("method"))
+		return
+	}
+
+	if r.URL.Path == docsPath {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if _, err := w.Write([]byte(docsPage)); err != nil {
+			log.Errorf("unable to write docs page: %v", err)
+		}
+		return
+	}
+
+	spec, err := g.OpenAPISpec()
+	if err != nil {
+		log.Errorf("unable to encode OpenAPI specification: %v", err)
+		g.writeError(w, http.StatusInternalServerError,
+			newErrInternal(err.Error()))
+		return
+	}
+
+	g.writeBody(w, http.StatusOK, spec)
+}
@@ -0,0 +1,113 @@
+package crpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGatewayOpenAPISpec(t *testing.T) {
+	g := NewGateway(&Server{}, nil, nil, nil, nil, nil, CamelCaseNames, 0)
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("GET", openAPIPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("wrong status: %v", w.Code)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+			RequestBody map[string]interface{} `json:"requestBody"`
+			Responses   map[string]interface{} `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]interface{} `json:"properties"`
+				Enum       []string               `json:"enum"`
+			} `json:"schemas"`
+			SecuritySchemes map[string]interface{} `json:"securitySchemes"`
+		} `json:"components"`
+		Security []map[string]interface{} `json:"security"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("unable to decode spec: %v", err)
+	}
+
+	if spec.OpenAPI != openAPIVersion {
+		t.Fatalf("wrong version: %v", spec.OpenAPI)
+	}
+
+	// Every route is described, fields of the path are the parameters.
+	for _, route := range g.routes {
+		path := "/" + strings.Join(route.pattern, "/")
+		if _, ok := spec.Paths[path][strings.ToLower(route.method)]; !ok {
+			t.Fatalf("route %v %v is missing", route.method, path)
+		}
+	}
+
+	payment := spec.Paths["/v1/payments/{payment_id}"]["get"]
+	if payment.OperationID != "PaymentByID" ||
+		len(payment.Parameters) != 1 ||
+		payment.Parameters[0].Name != "payment_id" ||
+		payment.Parameters[0].In != "path" {
+		t.Fatalf("wrong operation: %v", payment)
+	}
+
+	for _, status := range []string{"200", "304", "400", "500"} {
+		if _, ok := payment.Responses[status]; !ok {
+			t.Fatalf("response %v is missing", status)
+		}
+	}
+
+	// Methods served on both HTTP methods have unique operation ids.
+	validate := spec.Paths["/v1/receipts/validate"]
+	if validate["get"].OperationID == validate["post"].OperationID {
+		t.Fatalf("operation ids aren't unique: %v",
+			validate["get"].OperationID)
+	}
+
+	if validate["post"].RequestBody == nil {
+		t.Fatal("request body is missing")
+	}
+
+	// Schemas are named in the same way as fields in the responses.
+	if _, ok := spec.Components.Schemas["Payment"].Properties["paymentId"]; !ok {
+		t.Fatalf("wrong payment schema: %v",
+			spec.Components.Schemas["Payment"])
+	}
+
+	if _, ok := spec.Components.Schemas["Error"].Properties["error"]; !ok {
+		t.Fatal("error schema is missing")
+	}
+
+	assets := spec.Components.Schemas["Asset"].Enum
+	if len(assets) == 0 || assets[0] != Asset_name[0] {
+		t.Fatalf("wrong asset enum: %v", assets)
+	}
+
+	if spec.Components.SecuritySchemes != nil || spec.Security != nil {
+		t.Fatalf("authentication is disabled, but described: %v",
+			spec.Components.SecuritySchemes)
+	}
+
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("GET", docsPath, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(),
+		openAPIPath) {
+		t.Fatalf("wrong docs page: %v %v", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("POST", openAPIPath, nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("wrong status: %v", w.Code)
+	}
+}
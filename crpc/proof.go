@@ -0,0 +1,118 @@
+package crpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"golang.org/x/net/context"
+)
+
+// paymentProofVersion is the version of the payment proof format.
+const paymentProofVersion = 1
+
+// paymentProof is the JSON representation of the payment details which
+// are signed by the server. Account and metadata of the payment are
+// internal, that is why they are not disclosed to the customer.
+type paymentProof struct {
+	Version     int    `json:"version"`
+	Network     string `json:"network"`
+	PaymentID   string `json:"payment_id"`
+	Direction   string `json:"direction"`
+	Asset       string `json:"asset"`
+	Media       string `json:"media"`
+	Receipt     string `json:"receipt"`
+	Amount      string `json:"amount"`
+	Fee         string `json:"fee"`
+	MediaID     string `json:"media_id"`
+	CompletedAt int64  `json:"completed_at"`
+	IssuedAt    int64  `json:"issued_at"`
+}
+
+// paymentProof signs the details of the completed payment with the server
+// identity key.
+func (s *Server) paymentProof(ctx context.Context,
+	req *PaymentProofRequest) (*PaymentProof, error) {
+
+	if s.identityKey == nil {
+		return nil, newErrMethodDisabled("PaymentProof", "identitykeypath")
+	}
+
+	if req.PaymentId == "" {
+		return nil, newErrInvalidArgument("payment_id")
+	}
+
+	stop := trackStage(ctx, stageDB)
+	payment, err := s.paymentsStore.PaymentByID(req.PaymentId)
+	stop()
+	if err == connectors.PaymentNotFound {
+		return nil, newErrInvalidArgument("payment_id")
+	} else if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	// Pending payment might be still reorged or failed, that is why only
+	// the completed one is confirmed.
+	if payment.Status != connectors.Completed {
+		return nil, newErrInvalidArgument("payment_id")
+	}
+
+	proof, err := json.Marshal(&paymentProof{
+		Version:     paymentProofVersion,
+		Network:     s.net,
+		PaymentID:   payment.PaymentID,
+		Direction:   string(payment.Direction),
+		Asset:       string(payment.Asset),
+		Media:       string(payment.Media),
+		Receipt:     payment.Receipt,
+		Amount:      payment.Amount.String(),
+		Fee:         payment.MediaFee.String(),
+		MediaID:     payment.MediaID,
+		CompletedAt: payment.UpdatedAt,
+		IssuedAt:    connectors.NowInMilliSeconds(),
+	})
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	signature, err := s.identityKey.Sign(proof)
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	return &PaymentProof{
+		Proof:     string(proof),
+		Signature: signature,
+		KeyId:     s.identityKey.ID(),
+		PublicKey: hex.EncodeToString(s.identityKey.PublicKey()),
+	}, nil
+}
+
+//
+// PaymentProof returns the details of the completed payment signed by the
+// server identity key, so that merchant could hand the customer the
+// cryptographic confirmation of the payment, which is verified with the
+// public key returned by GetPublicKeys.
+func (s *Server) PaymentProof(ctx context.Context,
+	req *PaymentProofRequest) (*PaymentProof, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.paymentProof(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
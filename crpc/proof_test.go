@@ -0,0 +1,123 @@
+package crpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/identity"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+func TestPaymentProof(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	h.seedPayments(
+		&connectors.Payment{
+			PaymentID: "completed",
+			UpdatedAt: 10,
+			Status:    connectors.Completed,
+			System:    connectors.External,
+			Direction: connectors.Incoming,
+			Receipt:   "btc-address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(15, -1),
+			MediaFee:  decimal.Zero,
+			MediaID:   "tx-1",
+			AccountID: "customer-1",
+		},
+		&connectors.Payment{
+			PaymentID: "pending",
+			UpdatedAt: 20,
+			Status:    connectors.Pending,
+			System:    connectors.External,
+			Direction: connectors.Incoming,
+			Receipt:   "btc-address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(1, 0),
+			MediaFee:  decimal.Zero,
+			MediaID:   "tx-2",
+		},
+	)
+
+	proof := func(paymentID string) (*PaymentProof, error) {
+		return h.client.PaymentProof(ctx, &PaymentProofRequest{
+			PaymentId: paymentID,
+		})
+	}
+
+	// Proof couldn't be signed without the identity key.
+	if _, err := proof("completed"); err == nil {
+		t.Fatalf("proof is issued without identity key")
+	}
+
+	dir, err := ioutil.TempDir("", "proof")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	h.server.identityKey, err = identity.LoadKey(filepath.Join(dir,
+		"identity.key"))
+	if err != nil {
+		t.Fatalf("unable to create identity key: %v", err)
+	}
+
+	_, err = proof("")
+	expectInvalidArgument(t, err, "payment_id")
+
+	_, err = proof("unknown")
+	expectInvalidArgument(t, err, "payment_id")
+
+	// Pending payment isn't confirmed yet.
+	_, err = proof("pending")
+	expectInvalidArgument(t, err, "payment_id")
+
+	resp, err := proof("completed")
+	if err != nil {
+		t.Fatalf("unable to get proof: %v", err)
+	}
+
+	// Proof is verified only with the public key of the server.
+	publicKey, err := hex.DecodeString(resp.PublicKey)
+	if err != nil {
+		t.Fatalf("unable to decode public key: %v", err)
+	}
+
+	if resp.KeyId != h.server.identityKey.ID() ||
+		resp.KeyId != identity.KeyID(publicKey) {
+		t.Fatalf("wrong key id: %v", resp.KeyId)
+	}
+
+	err = identity.Verify(publicKey, []byte(resp.Proof), resp.Signature)
+	if err != nil {
+		t.Fatalf("proof signature isn't valid: %v", err)
+	}
+
+	var details paymentProof
+	if err := json.Unmarshal([]byte(resp.Proof), &details); err != nil {
+		t.Fatalf("unable to decode proof: %v", err)
+	}
+
+	if details.PaymentID != "completed" || details.Amount != "1.5" ||
+		details.Receipt != "btc-address" || details.MediaID != "tx-1" ||
+		details.CompletedAt != 10 || details.Network != "simnet" {
+		t.Fatalf("wrong proof: %v", resp.Proof)
+	}
+
+	// Altered proof isn't valid.
+	err = identity.Verify(publicKey, []byte(resp.Proof+" "), resp.Signature)
+	if err == nil {
+		t.Fatalf("signature of the altered proof is valid")
+	}
+}
@@ -0,0 +1,405 @@
+package crpc
+
+import (
+	"math/rand"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+// maxQuarantineReasonLength is the maximum length of the reason with which
+// payment is quarantined.
+const maxQuarantineReasonLength = 256
+
+// quarantinedBalance returns the amount of the completed incoming
+// blockchain payments of the asset which are quarantined, i.e. funds which
+// are in the wallet, but couldn't be spent.
+func (s *Server) quarantinedBalance(asset connectors.Asset) (decimal.Decimal,
+	error) {
+
+	balance := decimal.Zero
+	query := connectors.PaymentsQuery{
+		Asset:      asset,
+		Media:      connectors.Blockchain,
+		Direction:  connectors.Incoming,
+		Status:     connectors.Completed,
+		Quarantine: connectors.Quarantined,
+	}
+
+	_, err := s.walkPayments(query, func(payment *connectors.Payment) error {
+		balance = balance.Add(payment.Amount)
+		return nil
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return balance, nil
+}
+
+// lockPaymentOutputs excludes the outputs of the quarantined payment from
+// the coin selection of the wallet, connectors which are not selecting the
+// coins have nothing to lock.
+func (s *Server) lockPaymentOutputs(ctx context.Context,
+	payment *connectors.Payment) error {
+	c, ok := s.blockchainConnectors[payment.Asset]
+	if !ok {
+		return nil
+	}
+
+	locker, ok := c.(connectors.QuarantineLocker)
+	if !ok {
+		return nil
+	}
+
+	stop := trackStage(ctx, stageNode)
+	defer stop()
+
+	return locker.LockPaymentOutputs(payment)
+}
+
+// unlockPaymentOutputs returns the outputs of the payment, which has left
+// the quarantine, to the coin selection of the wallet.
+func (s *Server) unlockPaymentOutputs(ctx context.Context,
+	payment *connectors.Payment) error {
+	c, ok := s.blockchainConnectors[payment.Asset]
+	if !ok {
+		return nil
+	}
+
+	locker, ok := c.(connectors.QuarantineLocker)
+	if !ok {
+		return nil
+	}
+
+	stop := trackStage(ctx, stageNode)
+	defer stop()
+
+	return locker.UnlockPaymentOutputs(payment)
+}
+
+// quarantinedPayment returns the payment which is in the given quarantine
+// state, invalid argument error is returned if there is no such payment.
+func (s *Server) quarantinedPayment(ctx context.Context, paymentID string,
+	state connectors.QuarantineState) (*connectors.Payment, error) {
+	if paymentID == "" {
+		return nil, newErrInvalidArgument("payment_id")
+	}
+
+	stop := trackStage(ctx, stageDB)
+	payment, err := s.paymentsStore.PaymentByID(paymentID)
+	stop()
+	if err == connectors.PaymentNotFound {
+		return nil, newErrInvalidArgument("payment_id")
+	} else if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	if payment.Quarantine != state {
+		return nil, newErrInvalidArgument("payment_id")
+	}
+
+	return payment, nil
+}
+
+// setQuarantine sets the quarantine state of the payment and returns the
+// updated payment.
+func (s *Server) setQuarantine(ctx context.Context,
+	payment *connectors.Payment, state connectors.QuarantineState,
+	reason string) (*Payment, error) {
+
+	stop := trackStage(ctx, stageDB)
+	err := s.paymentsStore.SetPaymentQuarantine(payment.PaymentID, state,
+		reason)
+	stop()
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	updated := *payment
+	updated.Quarantine = state
+	updated.QuarantineReason = reason
+
+	resp, err := convertPaymentToProto(&updated)
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	return resp, nil
+}
+
+//
+// QuarantinePayment parks the incoming blockchain payment which has been
+// flagged by the compliance review in the quarantined balance, which isn't
+// spendable and isn't credited to the account until the payment is
+// released or returned.
+func (s *Server) QuarantinePayment(ctx context.Context,
+	req *QuarantinePaymentRequest) (*Payment, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if len(req.Reason) > maxQuarantineReasonLength {
+		err := newErrInvalidArgument("reason")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	s.refundMtx.Lock()
+	defer s.refundMtx.Unlock()
+
+	payment, err := s.quarantinedPayment(ctx, req.PaymentId,
+		connectors.NotQuarantined)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Only funds which are received in the wallet could be excluded from
+	// the spending, i.e. lightning funds are not tracked by the payment.
+	if payment.Direction != connectors.Incoming ||
+		payment.Media != connectors.Blockchain ||
+		payment.Status == connectors.Failed {
+		err := newErrInvalidArgument("payment_id")
+		log.Errorf("command(%v), id(%v), error: %v, payment(%v) is %v %v %v",
+			common.GetFunctionName(), requestID, err, payment.PaymentID,
+			payment.Status, payment.Direction, payment.Media)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Payment which has been partially refunded has already left the
+	// wallet, at least in part, so that it couldn't be parked anymore.
+	stop := trackStage(ctx, stageDB)
+	refunds, err := s.paymentsStore.PaymentRefunds(payment.PaymentID)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if !refundableAmount(payment, refunds).Equal(payment.Amount) {
+		err := newErrInvalidArgument("payment_id")
+		log.Errorf("command(%v), id(%v), error: %v, payment(%v) has been "+
+			"refunded", common.GetFunctionName(), requestID, err,
+			payment.PaymentID)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp, err := s.setQuarantine(ctx, payment, connectors.Quarantined,
+		req.Reason)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Payment is already quarantined, and outputs of the quarantined
+	// payments are locked again by the sync of the connector, that is why
+	// failure to lock them is only reported.
+	if err := s.lockPaymentOutputs(ctx, payment); err != nil {
+		log.Errorf("command(%v), id(%v), unable to lock outputs of "+
+			"payment(%v): %v", common.GetFunctionName(), requestID,
+			payment.PaymentID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+	}
+
+	log.Infof("Payment(%v) of amount(%v %v) is quarantined: %v",
+		payment.PaymentID, payment.Amount, payment.Asset, req.Reason)
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ReleasePayment releases the quarantined payment after the review, so that
+// its funds become spendable and are credited to the account.
+func (s *Server) ReleasePayment(ctx context.Context,
+	req *ReleasePaymentRequest) (*Payment, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	s.refundMtx.Lock()
+	defer s.refundMtx.Unlock()
+
+	payment, err := s.quarantinedPayment(ctx, req.PaymentId,
+		connectors.Quarantined)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Outputs are unlocked before the payment is released, otherwise they
+	// would stay locked, because sync locks only the outputs of the
+	// quarantined payments, and never unlocks them.
+	if err := s.unlockPaymentOutputs(ctx, payment); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Reason is kept for the audit of the review.
+	resp, err := s.setQuarantine(ctx, payment, connectors.QuarantineReleased,
+		payment.QuarantineReason)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Quarantined payment(%v) is released", payment.PaymentID)
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ReturnPayment sends the completed quarantined payment back to the given
+// receipt, returned payment is never credited to the account. Return is
+// linked to the payment the same way as refund.
+func (s *Server) ReturnPayment(ctx context.Context,
+	req *ReturnPaymentRequest) (*Payment, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.Receipt == "" {
+		err := newErrInvalidArgument("receipt")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	s.refundMtx.Lock()
+	defer s.refundMtx.Unlock()
+
+	payment, err := s.quarantinedPayment(ctx, req.PaymentId,
+		connectors.Quarantined)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Funds which haven't been confirmed yet couldn't be sent back.
+	if payment.Status != connectors.Completed {
+		err := newErrInvalidArgument("payment_id")
+		log.Errorf("command(%v), id(%v), error: %v, payment(%v) is %v",
+			common.GetFunctionName(), requestID, err, payment.PaymentID,
+			payment.Status)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	asset, err := convertAssetToProto(payment.Asset)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Payment is marked as returned and its outputs are unlocked before
+	// the send, so that its funds are no longer excluded from the
+	// spending, and it is put back in the quarantine if send has failed.
+	if _, err := s.setQuarantine(ctx, payment, connectors.QuarantineReturned,
+		payment.QuarantineReason); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if err := s.unlockPaymentOutputs(ctx, payment); err != nil {
+		log.Errorf("command(%v), id(%v), unable to unlock outputs of "+
+			"payment(%v): %v", common.GetFunctionName(), requestID,
+			payment.PaymentID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+
+		if _, err := s.setQuarantine(ctx, payment, connectors.Quarantined,
+			payment.QuarantineReason); err != nil {
+			log.Errorf("command(%v), id(%v), unable to put payment(%v) "+
+				"back in quarantine: %v", common.GetFunctionName(),
+				requestID, payment.PaymentID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+		}
+
+		return nil, newErrInternal(err.Error())
+	}
+
+	// Return doesn't belong to any account, because returned payment
+	// hasn't been credited to it.
+	resp, err := s.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   asset,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: req.Receipt,
+		Amount:  payment.Amount.String(),
+		Memo:    req.Memo,
+	})
+	if err != nil {
+		if _, err := s.setQuarantine(ctx, payment, connectors.Quarantined,
+			payment.QuarantineReason); err != nil {
+			log.Errorf("command(%v), id(%v), unable to put payment(%v) "+
+				"back in quarantine: %v", common.GetFunctionName(),
+				requestID, payment.PaymentID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+		} else if err := s.lockPaymentOutputs(ctx, payment); err != nil {
+			log.Errorf("command(%v), id(%v), unable to lock outputs of "+
+				"payment(%v): %v", common.GetFunctionName(), requestID,
+				payment.PaymentID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+		}
+
+		return nil, err
+	}
+
+	// Return has been already sent, failure to link it is only reported,
+	// so that it is fixed by the operator.
+	stop := trackStage(ctx, stageDB)
+	err = s.paymentsStore.SetPaymentRefund(resp.PaymentId, payment.PaymentID)
+	stop()
+	if err != nil {
+		log.Errorf("command(%v), id(%v), unable to link return(%v) to "+
+			"payment(%v): %v", common.GetFunctionName(), requestID,
+			resp.PaymentId, payment.PaymentID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+	} else {
+		resp.RefundOf = payment.PaymentID
+	}
+
+	log.Infof("Quarantined payment(%v) is returned by payment(%v)",
+		payment.PaymentID, resp.PaymentId)
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
@@ -0,0 +1,204 @@
+package crpc
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+func TestQuarantinePayment(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	deposit := func(id string, amount int64) *connectors.Payment {
+		payment := &connectors.Payment{
+			PaymentID: id,
+			UpdatedAt: 1,
+			Status:    connectors.Completed,
+			System:    connectors.External,
+			Direction: connectors.Incoming,
+			Receipt:   "btc-address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(amount, 0),
+			MediaFee:  decimal.Zero,
+			MediaID:   "tx-" + id,
+			AccountID: "customer-1",
+		}
+		h.seedPayments(payment)
+		return payment
+	}
+
+	balance := func() *Balance {
+		resp, err := h.client.Balance(ctx, &BalanceRequest{
+			Asset: Asset_BTC,
+			Media: Media_BLOCKCHAIN,
+		})
+		if err != nil {
+			t.Fatalf("unable to get balance: %v", err)
+		}
+
+		return resp.Balances[0]
+	}
+
+	accountBalance := func() string {
+		balance, err := h.server.accountBalance("customer-1", connectors.BTC,
+			connectors.Blockchain)
+		if err != nil {
+			t.Fatalf("unable to get account balance: %v", err)
+		}
+
+		return balance.String()
+	}
+
+	flagged := deposit("flagged", 2)
+
+	_, err := h.admin.QuarantinePayment(ctx, &QuarantinePaymentRequest{
+		PaymentId: "unknown",
+	})
+	expectInvalidArgument(t, err, "payment_id")
+
+	payment, err := h.admin.QuarantinePayment(ctx, &QuarantinePaymentRequest{
+		PaymentId: "flagged",
+		Reason:    "sanctions match",
+	})
+	if err != nil {
+		t.Fatalf("unable to quarantine payment: %v", err)
+	}
+
+	if payment.Quarantine != QuarantineState_QUARANTINED ||
+		payment.QuarantineReason != "sanctions match" {
+		t.Fatalf("wrong payment: %v", payment)
+	}
+
+	// Outputs of the quarantined payment are excluded from the coin
+	// selection of the wallet.
+	if !h.btc.isLocked("flagged") {
+		t.Fatalf("outputs of quarantined payment should be locked")
+	}
+
+	// Quarantined funds are neither spendable nor credited to the account.
+	if b := balance(); b.Available != "8" || b.Quarantined != "2" {
+		t.Fatalf("wrong balance: %v", b)
+	}
+
+	if balance := accountBalance(); balance != "0" {
+		t.Fatalf("quarantined payment shouldn't be credited, got: %v",
+			balance)
+	}
+
+	_, err = h.client.RefundPayment(ctx, &RefundPaymentRequest{
+		PaymentId: "flagged",
+		Receipt:   "customer-address",
+	})
+	expectInvalidArgument(t, err, "payment_id")
+
+	// Quarantine is kept when payment is updated by the sync.
+	flagged.UpdatedAt = 2
+	h.seedPayments(flagged)
+
+	list, err := h.client.ListPayments(ctx, &ListPaymentsRequest{
+		Quarantine: QuarantineState_QUARANTINED,
+	})
+	if err != nil {
+		t.Fatalf("unable to list payments: %v", err)
+	}
+
+	if len(list.Payments) != 1 || list.Payments[0].PaymentId != "flagged" {
+		t.Fatalf("wrong quarantined payments: %v", list)
+	}
+
+	payment, err = h.admin.ReleasePayment(ctx, &ReleasePaymentRequest{
+		PaymentId: "flagged",
+	})
+	if err != nil {
+		t.Fatalf("unable to release payment: %v", err)
+	}
+
+	if payment.Quarantine != QuarantineState_QUARANTINE_RELEASED {
+		t.Fatalf("wrong payment: %v", payment)
+	}
+
+	if h.btc.isLocked("flagged") {
+		t.Fatalf("outputs of released payment should be unlocked")
+	}
+
+	if b := balance(); b.Available != "10" || b.Quarantined != "0" {
+		t.Fatalf("wrong balance: %v", b)
+	}
+
+	if balance := accountBalance(); balance != "2" {
+		t.Fatalf("released payment should be credited, got: %v", balance)
+	}
+
+	_, err = h.admin.ReleasePayment(ctx, &ReleasePaymentRequest{
+		PaymentId: "flagged",
+	})
+	expectInvalidArgument(t, err, "payment_id")
+
+	deposit("returned", 1)
+	_, err = h.admin.QuarantinePayment(ctx, &QuarantinePaymentRequest{
+		PaymentId: "returned",
+	})
+	if err != nil {
+		t.Fatalf("unable to quarantine payment: %v", err)
+	}
+
+	// Payment is put back in the quarantine if return has been rejected
+	// by the media.
+	h.btc.sendErr = errors.New("fee is too low")
+	_, err = h.admin.ReturnPayment(ctx, &ReturnPaymentRequest{
+		PaymentId: "returned",
+		Receipt:   "sender-address",
+	})
+	if err == nil {
+		t.Fatalf("rejected return should return error")
+	}
+	h.btc.sendErr = nil
+
+	if b := balance(); b.Quarantined != "1" {
+		t.Fatalf("payment should be quarantined: %v", b)
+	}
+
+	if !h.btc.isLocked("returned") {
+		t.Fatalf("outputs should be locked again after rejected return")
+	}
+
+	sent, err := h.admin.ReturnPayment(ctx, &ReturnPaymentRequest{
+		PaymentId: "returned",
+		Receipt:   "sender-address",
+	})
+	if err != nil {
+		t.Fatalf("unable to return payment: %v", err)
+	}
+
+	if sent.RefundOf != "returned" || sent.Amount != "1" ||
+		sent.Account != "" {
+		t.Fatalf("wrong return: %v", sent)
+	}
+
+	payment, err = h.client.PaymentByID(ctx, &PaymentByIDRequest{
+		PaymentId: "returned",
+	})
+	if err != nil {
+		t.Fatalf("unable to get payment: %v", err)
+	}
+
+	if payment.Quarantine != QuarantineState_QUARANTINE_RETURNED {
+		t.Fatalf("wrong payment: %v", payment)
+	}
+
+	if h.btc.isLocked("returned") {
+		t.Fatalf("outputs of returned payment should be unlocked")
+	}
+
+	// Returned payment is never credited to the account.
+	if balance := accountBalance(); balance != "2" {
+		t.Fatalf("wrong account balance: %v", balance)
+	}
+}
@@ -0,0 +1,190 @@
+package crpc
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// defaultQuoteTTL is the time during which quote could be used, it is
+	// short, because fee rate of the network is changing constantly.
+	defaultQuoteTTL = time.Minute
+
+	// quoteIDSize is the number of random bytes in the quote id.
+	quoteIDSize = 16
+)
+
+// quoteEntry is the issued quote along with the id of the API key by which
+// it was requested, so that quote couldn't be used by the other tenant.
+type quoteEntry struct {
+	quote    *PaymentQuote
+	apiKeyID string
+}
+
+// quoteStore keeps the issued payment quotes in memory, quotes are
+// short-lived, so they are not persisted, and after the restart they are
+// considered as expired.
+type quoteStore struct {
+	mtx    sync.Mutex
+	quotes map[string]*quoteEntry
+	ttl    time.Duration
+
+	// now is used to mock time in tests.
+	now func() time.Time
+}
+
+// newQuoteStore creates new store of the quotes with the given validity
+// window.
+func newQuoteStore(ttl time.Duration) *quoteStore {
+	return &quoteStore{
+		quotes: make(map[string]*quoteEntry),
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// add assigns id and expiration time to the quote and stores it on behalf
+// of the given API key, empty id is used for the macaroon authenticated
+// callers.
+func (s *quoteStore) add(quote *PaymentQuote, apiKeyID string) error {
+	id := make([]byte, quoteIDSize)
+	if _, err := crand.Read(id); err != nil {
+		return errors.Errorf("unable to generate quote id: %v", err)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	s.removeExpired(now)
+
+	quote.QuoteId = hex.EncodeToString(id)
+	quote.ExpiresAt = now.Add(s.ttl).UnixNano() / int64(time.Millisecond)
+	s.quotes[quote.QuoteId] = &quoteEntry{
+		quote:    quote,
+		apiKeyID: apiKeyID,
+	}
+
+	return nil
+}
+
+// get returns the quote without removing it, so that payment request could
+// be validated against it before the quote is consumed.
+func (s *quoteStore) get(id, apiKeyID string) (*PaymentQuote, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.lookup(id, apiKeyID)
+}
+
+// take removes the quote from the store and returns it, quote could be
+// taken only once, so that the same quote isn't used for two payments.
+func (s *quoteStore) take(id, apiKeyID string) (*PaymentQuote, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	quote, err := s.lookup(id, apiKeyID)
+	if err != nil {
+		return nil, err
+	}
+	delete(s.quotes, id)
+
+	return quote, nil
+}
+
+// lookup returns the quote if it is issued for the given API key and it
+// isn't expired.
+//
+// NOTE: Should be called with the mutex held.
+func (s *quoteStore) lookup(id, apiKeyID string) (*PaymentQuote, error) {
+	entry, ok := s.quotes[id]
+	if !ok || entry.apiKeyID != apiKeyID {
+		return nil, errors.Errorf("quote(%v) not found", id)
+	}
+
+	if isQuoteExpired(entry.quote, s.now()) {
+		delete(s.quotes, id)
+		return nil, errors.Errorf("quote(%v) is expired", id)
+	}
+
+	return entry.quote, nil
+}
+
+// removeExpired removes quotes which couldn't be used anymore.
+//
+// NOTE: Should be called with the mutex held.
+func (s *quoteStore) removeExpired(now time.Time) {
+	for id, entry := range s.quotes {
+		if isQuoteExpired(entry.quote, now) {
+			delete(s.quotes, id)
+		}
+	}
+}
+
+// isQuoteExpired returns true if validity window of the quote is over.
+func isQuoteExpired(quote *PaymentQuote, now time.Time) bool {
+	return now.UnixNano()/int64(time.Millisecond) >= quote.ExpiresAt
+}
+
+// applyQuote fills the payment request from the quote, parameters which are
+// specified in the request should match the quote, so that client couldn't
+// send the other amount or to the other recipient by the mistake. Receipt
+// of the request should be already normalized by the caller.
+func applyQuote(req *SendPaymentRequest, quote *PaymentQuote) error {
+	if req.Asset != Asset_ASSET_NONE && req.Asset != quote.Asset {
+		return newErrInvalidArgument("asset")
+	}
+
+	if req.Media != Media_MEDIA_NONE && req.Media != quote.Media {
+		return newErrInvalidArgument("media")
+	}
+
+	if req.Amount != "" {
+		amount, err := decimal.NewFromString(req.Amount)
+		if err != nil {
+			return newErrInvalidArgument("amount")
+		}
+
+		quoteAmount, err := decimal.NewFromString(quote.Amount)
+		if err != nil || !amount.Equal(quoteAmount) {
+			return newErrInvalidArgument("amount")
+		}
+	}
+
+	if req.Receipt != "" && req.Receipt != quote.Receipt {
+		return newErrInvalidArgument("receipt")
+	}
+
+	req.Asset = quote.Asset
+	req.Media = quote.Media
+	req.Amount = quote.Amount
+	req.Receipt = quote.Receipt
+
+	return nil
+}
+
+// checkQuoteFee returns error if the current fee estimation exceeds the fee
+// of the quote, so that payment isn't sent with the fee which hasn't been
+// shown to the user.
+func checkQuoteFee(quote *PaymentQuote, estimatedFee string) error {
+	quoteFee, err := decimal.NewFromString(quote.MediaFee)
+	if err != nil {
+		return newErrInternal(err.Error())
+	}
+
+	fee, err := decimal.NewFromString(estimatedFee)
+	if err != nil {
+		return newErrInternal(err.Error())
+	}
+
+	if fee.GreaterThan(quoteFee) {
+		return newErrInvalidArgument("quote_id")
+	}
+
+	return nil
+}
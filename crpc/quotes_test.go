@@ -0,0 +1,133 @@
+package crpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuoteStore(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := newQuoteStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	quote := &PaymentQuote{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "address",
+		Amount:  "1",
+	}
+	if err := store.add(quote, "key"); err != nil {
+		t.Fatalf("unable to add quote: %v", err)
+	}
+
+	if quote.QuoteId == "" {
+		t.Fatal("quote id isn't assigned")
+	}
+
+	// Quote is bound to the API key by which it was requested.
+	if _, err := store.get(quote.QuoteId, "other"); err == nil {
+		t.Fatal("quote shouldn't be available for the other key")
+	}
+
+	if _, err := store.take(quote.QuoteId, ""); err == nil {
+		t.Fatal("quote shouldn't be taken without the key")
+	}
+
+	// Validation of the request doesn't consume the quote.
+	if _, err := store.get(quote.QuoteId, "key"); err != nil {
+		t.Fatalf("unable to get quote: %v", err)
+	}
+
+	if _, err := store.take(quote.QuoteId, "key"); err != nil {
+		t.Fatalf("unable to take quote: %v", err)
+	}
+
+	// Quote could be used only once.
+	if _, err := store.take(quote.QuoteId, "key"); err == nil {
+		t.Fatal("quote shouldn't be taken twice")
+	}
+
+	expired := &PaymentQuote{Amount: "1"}
+	if err := store.add(expired, ""); err != nil {
+		t.Fatalf("unable to add quote: %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := store.take(expired.QuoteId, ""); err == nil {
+		t.Fatal("expired quote shouldn't be taken")
+	}
+}
+
+func TestCheckQuoteFee(t *testing.T) {
+	quote := &PaymentQuote{MediaFee: "0.0001"}
+
+	if err := checkQuoteFee(quote, "0.00010"); err != nil {
+		t.Fatalf("same fee should be accepted: %v", err)
+	}
+
+	if err := checkQuoteFee(quote, "0.00005"); err != nil {
+		t.Fatalf("lower fee should be accepted: %v", err)
+	}
+
+	if err := checkQuoteFee(quote, "0.00011"); err == nil {
+		t.Fatal("fee above the quote should be rejected")
+	}
+}
+
+func TestApplyQuote(t *testing.T) {
+	quote := &PaymentQuote{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "address",
+		Amount:  "1.5",
+	}
+
+	tests := []struct {
+		name    string
+		req     *SendPaymentRequest
+		wantErr bool
+	}{
+		{
+			name: "empty request",
+			req:  &SendPaymentRequest{},
+		},
+		{
+			name: "matching request",
+			req: &SendPaymentRequest{Asset: Asset_BTC,
+				Media: Media_BLOCKCHAIN, Receipt: "address", Amount: "1.50"},
+		},
+		{
+			name:    "other asset",
+			req:     &SendPaymentRequest{Asset: Asset_ETH},
+			wantErr: true,
+		},
+		{
+			name:    "other amount",
+			req:     &SendPaymentRequest{Amount: "1.4"},
+			wantErr: true,
+		},
+		{
+			name:    "other receipt",
+			req:     &SendPaymentRequest{Receipt: "other"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		err := applyQuote(tt.req, quote)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%v: wrong error, got(%v), want error(%v)", tt.name,
+				err, tt.wantErr)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if tt.req.Asset != quote.Asset || tt.req.Media != quote.Media ||
+			tt.req.Receipt != quote.Receipt || tt.req.Amount != quote.Amount {
+			t.Fatalf("%v: request isn't filled from quote: %v", tt.name,
+				tt.req)
+		}
+	}
+}
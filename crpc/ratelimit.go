@@ -0,0 +1,277 @@
+package crpc
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/peer"
+)
+
+// rateLimitedMethods is the methods which could be rate limited, they are
+// the ones which allow the client to drain the hot wallet or to flood the
+// daemons with new addresses and invoices. Every output of SendPayments
+// takes its own request from the limit.
+var rateLimitedMethods = map[string]struct{}{
+	"SendPayment":   {},
+	"SendPayments":  {},
+	"CreateReceipt": {},
+}
+
+// maxRateLimitBuckets is the number of the buckets after which buckets of
+// the idle callers are removed.
+const maxRateLimitBuckets = 10000
+
+// RateLimitRule is the scope to which rate limit is applied. Empty asset or
+// API key id denotes that rule is applied to every asset or caller.
+type RateLimitRule struct {
+	Method   string
+	Asset    string
+	APIKeyID string
+}
+
+// RateLimit is the maximum number of the requests which caller is allowed
+// to make during the period. Requests are allowed in bursts up to the limit,
+// and are refilled evenly during the period.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseRateLimit parses the rate limit from the config in form of
+// "method[/asset][@apikeyid]=requests/period", e.g. "SendPayment/BTC=10/1m".
+func ParseRateLimit(s string) (RateLimitRule, RateLimit, error) {
+	var rule RateLimitRule
+
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return rule, RateLimit{}, errors.Errorf("rate limit should be in "+
+			"form of method[/asset][@apikeyid]=requests/period, got(%v)", s)
+	}
+	scope, value := parts[0], parts[1]
+
+	if i := strings.Index(scope, "@"); i != -1 {
+		scope, rule.APIKeyID = scope[:i], scope[i+1:]
+		if rule.APIKeyID == "" {
+			return rule, RateLimit{}, errors.Errorf("api key id of the "+
+				"rate limit is empty, got(%v)", s)
+		}
+	}
+
+	if i := strings.Index(scope, "/"); i != -1 {
+		scope, rule.Asset = scope[:i], strings.ToUpper(scope[i+1:])
+		if _, ok := Asset_value[rule.Asset]; !ok ||
+			rule.Asset == Asset_ASSET_NONE.String() {
+			return rule, RateLimit{}, errors.Errorf("unknown asset of the "+
+				"rate limit, got(%v)", s)
+		}
+	}
+
+	rule.Method = scope
+	if _, ok := rateLimitedMethods[rule.Method]; !ok {
+		return rule, RateLimit{}, errors.Errorf("method of the rate limit "+
+			"should be SendPayment, SendPayments or CreateReceipt, got(%v)", s)
+	}
+
+	parts = strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return rule, RateLimit{}, errors.Errorf("rate limit should be in "+
+			"form of requests/period, got(%v)", s)
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil || requests <= 0 {
+		return rule, RateLimit{}, errors.Errorf("number of requests of the "+
+			"rate limit should be positive integer, got(%v)", s)
+	}
+
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return rule, RateLimit{}, errors.Errorf("period of the rate limit "+
+			"should be positive duration, got(%v)", s)
+	}
+
+	return rule, RateLimit{Requests: requests, Period: period}, nil
+}
+
+// tokenBucket is the number of requests which caller is allowed to make at
+// the moment.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds tokens accumulated since the last request, and returns true
+// if bucket is full.
+func (b *tokenBucket) refill(limit RateLimit, now time.Time) bool {
+	rate := float64(limit.Requests) / float64(limit.Period)
+	b.tokens += float64(now.Sub(b.last)) * rate
+	b.last = now
+
+	if b.tokens >= float64(limit.Requests) {
+		b.tokens = float64(limit.Requests)
+		return true
+	}
+
+	return false
+}
+
+// rateLimitBucket identifies the bucket of the caller for the rule.
+type rateLimitBucket struct {
+	rule   RateLimitRule
+	caller string
+}
+
+// RateLimiter limits the number of requests of the callers by the token
+// bucket per caller identity, which is the API key id if call is
+// authorized by the API key, and the address of the peer otherwise.
+type RateLimiter struct {
+	mtx     sync.Mutex
+	limits  map[RateLimitRule]RateLimit
+	buckets map[rateLimitBucket]*tokenBucket
+
+	// now is used to mock time in tests.
+	now func() time.Time
+}
+
+// NewRateLimiter creates new rate limiter with the given limits.
+func NewRateLimiter(limits map[RateLimitRule]RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limits:  limits,
+		buckets: make(map[rateLimitBucket]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// rule returns the most specific rule which is applied to the call, the
+// one for the API key takes precedence over the one for the asset.
+func (l *RateLimiter) rule(method, asset, apiKeyID string) (RateLimitRule,
+	RateLimit, bool) {
+
+	var rules []RateLimitRule
+	if apiKeyID != "" {
+		rules = append(rules,
+			RateLimitRule{Method: method, Asset: asset, APIKeyID: apiKeyID},
+			RateLimitRule{Method: method, APIKeyID: apiKeyID},
+		)
+	}
+	rules = append(rules,
+		RateLimitRule{Method: method, Asset: asset},
+		RateLimitRule{Method: method},
+	)
+
+	for _, rule := range rules {
+		if limit, ok := l.limits[rule]; ok {
+			return rule, limit, true
+		}
+	}
+
+	return RateLimitRule{}, RateLimit{}, false
+}
+
+// allow takes the given number of tokens from the bucket of the caller, and
+// returns error if bucket doesn't have enough of them.
+func (l *RateLimiter) allow(method, asset, apiKeyID, caller string,
+	tokens int) error {
+
+	rule, limit, ok := l.rule(method, asset, apiKeyID)
+	if !ok {
+		return nil
+	}
+
+	// Such batch couldn't be ever allowed, and retrying it is useless.
+	if tokens > limit.Requests {
+		return newErrInvalidArgument("outputs")
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	if len(l.buckets) >= maxRateLimitBuckets {
+		l.removeIdleBuckets(now)
+	}
+
+	key := rateLimitBucket{rule: rule, caller: caller}
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{
+			tokens: float64(limit.Requests),
+			last:   now,
+		}
+		l.buckets[key] = bucket
+	}
+	bucket.refill(limit, now)
+
+	if bucket.tokens < float64(tokens) {
+		rate := float64(limit.Requests) / float64(limit.Period)
+		retryAfter := time.Duration((float64(tokens) - bucket.tokens) / rate)
+		return newErrRateLimited(method, retryAfter.Round(time.Millisecond))
+	}
+
+	bucket.tokens -= float64(tokens)
+	return nil
+}
+
+// removeIdleBuckets removes buckets which are full, because they are not
+// distinguishable from the new ones.
+//
+// NOTE: Should be called with the mutex held.
+func (l *RateLimiter) removeIdleBuckets(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.refill(l.limits[key.rule], now) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// callerIdentity returns the API key id which authorized the call, or the
+// host of the peer otherwise.
+func callerIdentity(ctx context.Context) string {
+	if id := apiKeyIDFromContext(ctx); id != "" {
+		return "apikey:" + id
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "peer:unknown"
+	}
+
+	return "peer:" + peerHost(p.Addr.String())
+}
+
+// peerHost returns the host of the peer address, so that callers couldn't
+// bypass the limit by opening new connections.
+func peerHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// limitCall takes the given number of requests of the caller from the rate
+// limit of the method. It is called by the server methods after the asset
+// has been resolved from the payee or the quote, and after the call has
+// been authenticated, so that unauthenticated callers couldn't exhaust the
+// limits of the others.
+func (s *Server) limitCall(ctx context.Context, method string, asset Asset,
+	requests int) error {
+	if s.limiter == nil {
+		return nil
+	}
+
+	caller := callerIdentity(ctx)
+	err := s.limiter.allow(method, asset.String(), apiKeyIDFromContext(ctx),
+		caller, requests)
+	if err != nil {
+		log.Warnf("Rate limited call: method=%v caller=%v", method, caller)
+		s.metrics.AddRateLimited(method)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package crpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		rule    RateLimitRule
+		limit   RateLimit
+		wantErr bool
+	}{
+		{
+			value: "SendPayment=10/1m",
+			rule:  RateLimitRule{Method: "SendPayment"},
+			limit: RateLimit{Requests: 10, Period: time.Minute},
+		},
+		{
+			value: "CreateReceipt/btc=5/1s",
+			rule:  RateLimitRule{Method: "CreateReceipt", Asset: "BTC"},
+			limit: RateLimit{Requests: 5, Period: time.Second},
+		},
+		{
+			value: "SendPayment/ETH@3fa2c1=1/1h",
+			rule: RateLimitRule{Method: "SendPayment", Asset: "ETH",
+				APIKeyID: "3fa2c1"},
+			limit: RateLimit{Requests: 1, Period: time.Hour},
+		},
+		{value: "SendPayment", wantErr: true},
+		{value: "Balance=10/1m", wantErr: true},
+		{value: "SendPayment/XRP=10/1m", wantErr: true},
+		{value: "SendPayment/ASSET_NONE=10/1m", wantErr: true},
+		{value: "SendPayment@=10/1m", wantErr: true},
+		{value: "SendPayment=10", wantErr: true},
+		{value: "SendPayment=0/1m", wantErr: true},
+		{value: "SendPayment=10/minute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		rule, limit, err := ParseRateLimit(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%v: wrong error, got(%v), want error(%v)", tt.value,
+				err, tt.wantErr)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if rule != tt.rule || limit != tt.limit {
+			t.Fatalf("%v: wrong rate limit, got(%v=%v), want(%v=%v)",
+				tt.value, rule, limit, tt.rule, tt.limit)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := NewRateLimiter(map[RateLimitRule]RateLimit{
+		{Method: "SendPayment"}: {Requests: 2, Period: time.Minute},
+		{Method: "SendPayment", Asset: "BTC"}: {
+			Requests: 1, Period: time.Minute},
+		{Method: "SendPayment", APIKeyID: "key"}: {
+			Requests: 3, Period: time.Minute},
+	})
+	limiter.now = func() time.Time { return now }
+
+	allow := func(asset, apiKeyID, caller string, want bool) {
+		t.Helper()
+
+		err := limiter.allow("SendPayment", asset, apiKeyID, caller, 1)
+		if (err == nil) != want {
+			t.Fatalf("wrong result for %v/%v@%v: got(%v), want allowed(%v)",
+				caller, asset, apiKeyID, err, want)
+		}
+	}
+
+	// Asset specific limit is used for BTC, and the common one for the
+	// other assets.
+	allow("BTC", "", "peer:a", true)
+	allow("BTC", "", "peer:a", false)
+	allow("ETH", "", "peer:a", true)
+	allow("ETH", "", "peer:a", true)
+	allow("ETH", "", "peer:a", false)
+
+	// Every caller has its own bucket.
+	allow("BTC", "", "peer:b", true)
+
+	// Limit of the API key takes precedence over the asset one.
+	for i := 0; i < 3; i++ {
+		allow("BTC", "key", "apikey:key", true)
+	}
+	allow("BTC", "key", "apikey:key", false)
+
+	// Tokens are refilled evenly during the period.
+	now = now.Add(30 * time.Second)
+	allow("ETH", "", "peer:a", true)
+	allow("ETH", "", "peer:a", false)
+
+	now = now.Add(time.Minute)
+	allow("BTC", "", "peer:a", true)
+
+	// Methods without limits are not limited.
+	for i := 0; i < 10; i++ {
+		if err := limiter.allow("CreateReceipt", "BTC", "",
+			"peer:a", 1); err != nil {
+			t.Fatalf("method without limit is limited: %v", err)
+		}
+	}
+}
+
+func TestRateLimiterRemoveIdleBuckets(t *testing.T) {
+	now := time.Unix(0, 0)
+	limit := RateLimit{Requests: 1, Period: time.Second}
+	limiter := NewRateLimiter(map[RateLimitRule]RateLimit{
+		{Method: "CreateReceipt"}: limit,
+	})
+	limiter.now = func() time.Time { return now }
+
+	if err := limiter.allow("CreateReceipt", "", "", "peer:a", 1); err != nil {
+		t.Fatalf("unable to make request: %v", err)
+	}
+
+	now = now.Add(time.Second)
+	limiter.removeIdleBuckets(now)
+	if len(limiter.buckets) != 0 {
+		t.Fatalf("idle bucket isn't removed")
+	}
+}
+
+func TestRateLimiterBatch(t *testing.T) {
+	limiter := NewRateLimiter(map[RateLimitRule]RateLimit{
+		{Method: "SendPayments"}: {Requests: 5, Period: time.Minute},
+	})
+
+	// Every output takes its own request from the limit.
+	if err := limiter.allow("SendPayments", "BTC", "", "peer:a", 3); err != nil {
+		t.Fatalf("unable to make request: %v", err)
+	}
+
+	if err := limiter.allow("SendPayments", "BTC", "", "peer:a", 3); err == nil {
+		t.Fatalf("request exceeding the remaining limit is allowed")
+	}
+
+	if err := limiter.allow("SendPayments", "BTC", "", "peer:a", 2); err != nil {
+		t.Fatalf("unable to make request: %v", err)
+	}
+
+	if err := limiter.allow("SendPayments", "BTC", "", "peer:b", 6); err == nil {
+		t.Fatalf("request exceeding the limit is allowed")
+	}
+}
@@ -0,0 +1,161 @@
+package crpc
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+)
+
+// receiptEventsBufferSize is the number of receipt events which could be
+// queued for the subscriber before it is considered to be too slow.
+const receiptEventsBufferSize = 100
+
+// receiptUpdate is the replacement of the receipt, which is sent to the
+// subscribers.
+type receiptUpdate struct {
+	// receiptID is the identifier of the replaced receipt.
+	receiptID string
+
+	// replacement is the receipt which should be used instead.
+	replacement *connectors.Receipt
+}
+
+// receiptEvents notifies subscribers about the replaced receipts.
+type receiptEvents struct {
+	mtx           sync.Mutex
+	nextID        uint64
+	subscriptions map[uint64]chan *receiptUpdate
+}
+
+func newReceiptEvents() *receiptEvents {
+	return &receiptEvents{
+		subscriptions: make(map[uint64]chan *receiptUpdate),
+	}
+}
+
+// notify sends the replacement of the receipt to the subscribers.
+func (e *receiptEvents) notify(receiptID string,
+	replacement *connectors.Receipt) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	update := &receiptUpdate{
+		receiptID:   receiptID,
+		replacement: replacement,
+	}
+
+	for id, updates := range e.subscriptions {
+		// Replacement shouldn't be blocked by the slow subscriber, that is
+		// why it is dropped, and has to resubscribe.
+		select {
+		case updates <- update:
+		default:
+			delete(e.subscriptions, id)
+			close(updates)
+		}
+	}
+}
+
+// subscribe returns channel which receives every replacement of the
+// receipts, and function which cancels the subscription. Channel is closed
+// if subscription is cancelled, or if subscriber is unable to keep up with
+// the updates.
+func (e *receiptEvents) subscribe() (<-chan *receiptUpdate, func()) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	id := e.nextID
+	e.nextID++
+
+	updates := make(chan *receiptUpdate, receiptEventsBufferSize)
+	e.subscriptions[id] = updates
+
+	return updates, func() {
+		e.mtx.Lock()
+		defer e.mtx.Unlock()
+
+		// Subscription might be already removed, if subscriber was too
+		// slow.
+		if _, ok := e.subscriptions[id]; ok {
+			delete(e.subscriptions, id)
+			close(updates)
+		}
+	}
+}
+
+//
+// SubscribeReceipts sends event every time receipt is changed, e.g. when
+// expired or unpayable invoice is replaced by the new one, so that checkout
+// pages could show the replacement without reloading.
+func (s *Server) SubscribeReceipts(req *SubscribeReceiptsRequest,
+	stream PayServer_SubscribeReceiptsServer) error {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	updates, cancel := s.receiptEvents.subscribe()
+	defer cancel()
+
+	// Merchant should receive only the events of its own receipts.
+	tenant := apiKeyIDFromContext(stream.Context())
+
+	// Followed receipt is moved along the chain of the replacements, so
+	// that page could be refreshed more than once.
+	receiptID := req.ReceiptId
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				err := newErrInternal("subscriber is unable to keep up " +
+					"with the updates, resubscribe")
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(),
+					string(metrics.LowSeverity))
+				return err
+			}
+
+			if tenant != "" && update.replacement.Tenant != tenant {
+				continue
+			}
+
+			if receiptID != "" {
+				if update.receiptID != receiptID {
+					continue
+				}
+				receiptID = update.replacement.ReceiptID
+			}
+
+			receipt, err := convertReceiptToProto(update.replacement)
+			if err != nil {
+				err := newErrInternal(err.Error())
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(),
+					string(metrics.LowSeverity))
+				return err
+			}
+
+			err = stream.Send(&ReceiptEvent{
+				Type:      ReceiptEventType_RECEIPT_UPDATED,
+				ReceiptId: update.receiptID,
+				Receipt:   receipt,
+			})
+			if err != nil {
+				log.Errorf("command(%v), id(%v), unable to send event: %v",
+					common.GetFunctionName(), requestID, err)
+				return err
+			}
+
+		case <-stream.Context().Done():
+			log.Tracef("command(%v), id(%v), subscription is closed by "+
+				"client", common.GetFunctionName(), requestID)
+			return nil
+		}
+	}
+}
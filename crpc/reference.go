@@ -0,0 +1,32 @@
+package crpc
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+// maxReferenceLength is the maximum length of the external order reference
+// which is stored along with the receipt.
+const maxReferenceLength = 16384
+
+// createInvoice creates lightning network invoice for the receipt, which
+// either carries the description, or commits to the reference by its hash
+// if reference is specified.
+func createInvoice(c connectors.LightningConnector,
+	receipt *connectors.Receipt) (string, *zpay32.Invoice, error) {
+
+	if receipt.Reference == "" {
+		return c.CreateInvoice("zigzag", receipt.Amount.String(),
+			receipt.Description)
+	}
+
+	creator, ok := c.(connectors.HashedInvoiceCreator)
+	if !ok {
+		return "", nil, errors.New("connector doesn't support invoices " +
+			"with description hash")
+	}
+
+	return creator.CreateHashedInvoice("zigzag", receipt.Amount.String(),
+		receipt.DescriptionHash())
+}
@@ -0,0 +1,106 @@
+package crpc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"golang.org/x/net/context"
+)
+
+// hashedLightningConnector is the mock lightning connector which remembers
+// the description hashes of the created invoices.
+type hashedLightningConnector struct {
+	mockLightningConnector
+
+	hashes [][]byte
+}
+
+func (c *hashedLightningConnector) CreateHashedInvoice(receipt,
+	amount string, descriptionHash []byte) (string, *zpay32.Invoice, error) {
+	c.hashes = append(c.hashes, descriptionHash)
+	return c.CreateInvoice(receipt, amount, "")
+}
+
+func TestReceiptReference(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	reference := `{"order":"1","items":["` + strings.Repeat("a", 1024) + `"]}`
+	hash := sha256.Sum256([]byte(reference))
+
+	create := func(media Media, description,
+		reference string) (*CreateReceiptResponse, error) {
+		return h.client.CreateReceipt(ctx, &CreateReceiptRequest{
+			Asset:       Asset_BTC,
+			Media:       media,
+			Amount:      "0.1",
+			Description: description,
+			Reference:   reference,
+		})
+	}
+
+	// Connector which isn't able to create invoice with the description
+	// hash doesn't support references.
+	h.server.lightningConnectors[connectors.BTC] = &mockLightningConnector{}
+	if _, err := create(Media_LIGHTNING, "", reference); err == nil {
+		t.Fatalf("receipt with reference is created by connector " +
+			"without description hash support")
+	}
+
+	ln := &hashedLightningConnector{}
+	h.server.lightningConnectors[connectors.BTC] = ln
+
+	_, err := create(Media_LIGHTNING, "order", reference)
+	expectInvalidArgument(t, err, "reference")
+
+	_, err = create(Media_BLOCKCHAIN, "", reference)
+	expectInvalidArgument(t, err, "reference")
+
+	_, err = create(Media_LIGHTNING, "", strings.Repeat("a",
+		maxReferenceLength+1))
+	expectInvalidArgument(t, err, "reference")
+
+	resp, err := create(Media_LIGHTNING, "", reference)
+	if err != nil {
+		t.Fatalf("unable to create receipt: %v", err)
+	}
+
+	if len(ln.hashes) != 1 || hex.EncodeToString(ln.hashes[0]) !=
+		hex.EncodeToString(hash[:]) {
+		t.Fatalf("invoice doesn't commit to the reference: %x", ln.hashes)
+	}
+
+	if resp.DescriptionHash != hex.EncodeToString(hash[:]) {
+		t.Fatalf("wrong description hash: %v", resp.DescriptionHash)
+	}
+
+	// Reference is stored by the server, so that it could be handed to
+	// the payer, which verifies it against the invoice.
+	receipt, err := h.client.ReceiptByID(ctx, &ReceiptByIDRequest{
+		ReceiptId: resp.ReceiptId,
+	})
+	if err != nil {
+		t.Fatalf("unable to get receipt: %v", err)
+	}
+
+	if receipt.Reference != reference || receipt.Description != "" ||
+		receipt.DescriptionHash != resp.DescriptionHash {
+		t.Fatalf("wrong receipt: %v", receipt)
+	}
+
+	// Receipt without reference carries the description.
+	resp, err = create(Media_LIGHTNING, "order", "")
+	if err != nil {
+		t.Fatalf("unable to create receipt: %v", err)
+	}
+
+	if resp.DescriptionHash != "" || len(ln.hashes) != 1 {
+		t.Fatalf("invoice without reference has description hash")
+	}
+}
@@ -0,0 +1,136 @@
+package crpc
+
+import (
+	"math/rand"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"golang.org/x/net/context"
+)
+
+// attachRefundAddress validates the refund address supplied by the payer
+// and attaches it to the receipt.
+func (s *Server) attachRefundAddress(ctx context.Context,
+	req *AttachRefundAddressRequest) (*Receipt, error) {
+
+	if req.ReceiptId == "" {
+		return nil, newErrInvalidArgument("receipt_id")
+	}
+
+	if req.RefundAddress == "" {
+		return nil, newErrInvalidArgument("refund_address")
+	}
+
+	stop := trackStage(ctx, stageDB)
+	receipt, err := s.receiptsStore.ReceiptByID(req.ReceiptId)
+	stop()
+	if err == connectors.ReceiptNotFound {
+		return nil, newErrInvalidArgument("receipt_id")
+	} else if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	// Receipt of the other tenant is reported as unknown, so that its
+	// existence isn't disclosed. Replaced receipt couldn't be paid, and
+	// address should be attached to its replacement, which is shown on
+	// the checkout page instead.
+	tenant := apiKeyIDFromContext(ctx)
+	if (tenant != "" && receipt.Tenant != tenant) || receipt.ReplacedBy != "" {
+		return nil, newErrInvalidArgument("receipt_id")
+	}
+
+	// Refunds are sent on the blockchain for both media, because lightning
+	// network invoice couldn't be paid once again later.
+	c, ok := s.blockchainConnectors[receipt.Asset]
+	if !ok {
+		return nil, newErrAssetNotSupported(string(receipt.Asset),
+			Media_BLOCKCHAIN.String())
+	}
+
+	stop = trackStage(ctx, stageNode)
+	info, err := c.ValidateAddress(req.RefundAddress)
+	stop()
+	if err != nil {
+		return nil, newErrInvalidArgument("refund_address")
+	}
+
+	// Refund on the receipt itself would be received as the new payment,
+	// rather than returned to the payer.
+	if info.Address == receipt.Receipt {
+		return nil, newErrInvalidArgument("refund_address")
+	}
+
+	stop = trackStage(ctx, stageDB)
+	err = s.receiptsStore.SetReceiptRefundAddress(receipt.ReceiptID,
+		info.Address)
+	stop()
+	if err == connectors.RefundAddressAttached {
+		return nil, newErrInvalidArgument("refund_address")
+	} else if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	receipt.RefundAddress = info.Address
+
+	resp, err := convertReceiptToProto(receipt)
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	return resp, nil
+}
+
+// paymentRefundAddress returns the refund address which has been attached
+// to the receipt of the incoming payment, empty if there is no such
+// address.
+func (s *Server) paymentRefundAddress(ctx context.Context,
+	payment *connectors.Payment) (string, error) {
+
+	receiptID := (&connectors.Receipt{
+		Receipt: payment.Receipt,
+		Asset:   payment.Asset,
+		Media:   payment.Media,
+	}).GenReceiptID()
+
+	stop := trackStage(ctx, stageDB)
+	receipt, err := s.receiptsStore.ReceiptByID(receiptID)
+	stop()
+	if err == connectors.ReceiptNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return receipt.RefundAddress, nil
+}
+
+//
+// AttachRefundAddress attaches the blockchain address, supplied by the
+// payer, e.g. on the checkout page, to the receipt. Refunds of the payments
+// received on the receipt are sent to this address, rather than to the
+// input addresses of the payment, which are likely owned by the exchange.
+// Address couldn't be changed once it is attached.
+func (s *Server) AttachRefundAddress(ctx context.Context,
+	req *AttachRefundAddressRequest) (*Receipt, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.attachRefundAddress(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Refund address(%v) is attached to receipt(%v)",
+		resp.RefundAddress, resp.ReceiptId)
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
@@ -0,0 +1,114 @@
+package crpc
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+func TestAttachRefundAddress(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	created, err := h.client.CreateReceipt(ctx, &CreateReceiptRequest{
+		Asset: Asset_BTC,
+		Media: Media_BLOCKCHAIN,
+	})
+	if err != nil {
+		t.Fatalf("unable to create receipt: %v", err)
+	}
+
+	receiptID := (&connectors.Receipt{
+		Receipt: created.Receipt,
+		Asset:   connectors.BTC,
+		Media:   connectors.Blockchain,
+	}).GenReceiptID()
+
+	h.seedPayments(&connectors.Payment{
+		PaymentID: "incoming",
+		UpdatedAt: 1,
+		Status:    connectors.Completed,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   created.Receipt,
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.New(2, 0),
+		MediaFee:  decimal.Zero,
+		MediaID:   "incoming",
+	})
+
+	refund := func() (*Payment, error) {
+		return h.client.RefundPayment(ctx, &RefundPaymentRequest{
+			PaymentId: "incoming",
+			Amount:    "1",
+		})
+	}
+
+	// Without the attached address there is nowhere to send the refund.
+	_, err = refund()
+	expectInvalidArgument(t, err, "receipt")
+
+	attach := func(id, address string) (*Receipt, error) {
+		return h.client.AttachRefundAddress(ctx, &AttachRefundAddressRequest{
+			ReceiptId:     id,
+			RefundAddress: address,
+		})
+	}
+
+	_, err = attach("", "customer-address")
+	expectInvalidArgument(t, err, "receipt_id")
+
+	_, err = attach("unknown", "customer-address")
+	expectInvalidArgument(t, err, "receipt_id")
+
+	_, err = attach(receiptID, "")
+	expectInvalidArgument(t, err, "refund_address")
+
+	_, err = attach(receiptID, created.Receipt)
+	expectInvalidArgument(t, err, "refund_address")
+
+	receipt, err := attach(receiptID, "customer-address")
+	if err != nil {
+		t.Fatalf("unable to attach refund address: %v", err)
+	}
+
+	if receipt.RefundAddress != "customer-address" {
+		t.Fatalf("wrong refund address: %v", receipt.RefundAddress)
+	}
+
+	// Attaching the same address once again is idempotent, but address
+	// couldn't be replaced.
+	if _, err := attach(receiptID, "customer-address"); err != nil {
+		t.Fatalf("unable to attach the same address: %v", err)
+	}
+
+	_, err = attach(receiptID, "other-address")
+	expectInvalidArgument(t, err, "refund_address")
+
+	receipt, err = h.client.ReceiptByID(ctx, &ReceiptByIDRequest{
+		ReceiptId: receiptID,
+	})
+	if err != nil {
+		t.Fatalf("unable to get receipt: %v", err)
+	}
+
+	if receipt.RefundAddress != "customer-address" {
+		t.Fatalf("wrong refund address: %v", receipt.RefundAddress)
+	}
+
+	payment, err := refund()
+	if err != nil {
+		t.Fatalf("unable to refund payment: %v", err)
+	}
+
+	if payment.Receipt != "customer-address" ||
+		payment.RefundOf != "incoming" {
+		t.Fatalf("payment should be refunded on the attached address: %v",
+			payment)
+	}
+}
@@ -10,23 +10,158 @@ It is generated from these files:
 It has these top-level messages:
 	EmptyRequest
 	EmptyResponse
+	ProvideAddressRequest
+	ProvideAddressResponse
 	CreateReceiptRequest
+	NewAddressRequest
+	NewAddressResponse
+	ListReceiptsRequest
+	Receipt
+	AttachRefundAddressRequest
+	ReceiptByIDRequest
+	SubscribeReceiptsRequest
+	ReceiptEvent
+	ListReceiptsResponse
 	CreateReceiptResponse
 	BalanceRequest
 	Balance
+	BalanceHistoryRequest
+	BalanceSnapshot
+	BalanceHistoryResponse
+	SubscribeBalanceRequest
+	BalanceUpdate
 	ValidateReceiptResponse
 	Invoice
 	BalanceResponse
 	ValidateReceiptRequest
 	EstimateFeeRequest
+	FeeTarget
 	EstimateFeeResponse
 	SendPaymentRequest
+	PaymentOutput
+	SendPaymentsRequest
+	SendPaymentsResponse
+	QuoteReceiptRequest
+	ReceiptQuote
+	QuoteReceiptResponse
+	QuotePaymentRequest
+	PaymentQuote
+	SendTimeLockedPaymentRequest
+	TimeLock
+	ListTimeLocksRequest
+	ListTimeLocksResponse
 	PaymentByIDRequest
+	LabelPaymentRequest
+	RefundPaymentRequest
+	CancelPaymentRequest
+	PaymentProofRequest
+	PaymentProof
+	TransferFundsRequest
+	TransferFundsResponse
+	CreateAccountRequest
+	Account
+	ListAccountsRequest
+	ListAccountsResponse
+	ListDepositAddressesRequest
+	DepositAddress
+	ListDepositAddressesResponse
+	AccountStatementRequest
+	StatementEntry
+	AccountStatementResponse
 	PaymentsByReceiptRequest
 	PaymentsByReceiptResponse
 	ListPaymentsRequest
 	ListPaymentsResponse
+	ExportPaymentsRequest
+	ExportChunk
+	SubscribePaymentsRequest
+	Payee
+	RemovePayeeRequest
+	Branding
+	RemoveBrandingRequest
+	ListPayeesResponse
+	WatchAddress
+	ImportWatchAddressesRequest
+	AddressBook
+	ImportAddressBookResponse
+	ImportWatchAddressesResponse
+	RemoveWatchAddressRequest
+	ListWatchAddressesRequest
+	ListWatchAddressesResponse
+	WatchEvent
+	ListWatchEventsRequest
+	ListWatchEventsResponse
+	RedeliverWatchEventsRequest
+	RedeliverWatchEventsResponse
+	SyncUnspentRequest
+	GetUnspentSyncStatusRequest
+	UnspentSyncStatus
+	ListUnspentRequest
+	UnspentOutput
+	ListUnspentResponse
+	IsOurAddressRequest
+	IsOurAddressResponse
+	SignMessageRequest
+	SignMessageResponse
+	VerifyMessageRequest
+	VerifyMessageResponse
+	TransactionByHashRequest
+	TransactionInput
+	TransactionOutput
+	Transaction
+	TransferToPeerRequest
+	FederationTransfer
+	FederationPosition
+	ListFederationPositionsResponse
+	SettleFederationRequest
+	FederatedTransfer
+	ReceiveTransferResponse
+	FederatedSettlement
+	SettlementAddressRequest
+	SettlementAddressResponse
+	SweepFundsRequest
+	FeeReportRequest
+	FeeTotal
+	FeeWindow
+	FeeReportResponse
+	PauseWithdrawalsRequest
+	ResumeWithdrawalsRequest
+	WithdrawalPause
+	ListWithdrawalPausesResponse
+	BalanceInvariant
+	BalanceInvariantsResponse
+	RunSelfTestRequest
+	RunSelfTestResponse
+	FeatureFlag
+	ListFeatureFlagsResponse
+	QuarantinePaymentRequest
+	ReleasePaymentRequest
+	ReturnPaymentRequest
+	InjectTestPaymentRequest
+	DiagnoseRequest
+	ConfigOption
+	ConnectorHealth
+	ErrorCount
+	QueueDepth
+	DiagnoseResponse
 	Payment
+	PaymentEvent
+	CreateAPIKeyRequest
+	APIKey
+	CreateAPIKeyResponse
+	RevokeAPIKeyRequest
+	ListAPIKeysResponse
+	PublicKey
+	GetPublicKeysResponse
+	LightningNodeInfo
+	ConnectorInfo
+	ComponentHealth
+	HealthCheckResponse
+	SelfTestCheck
+	SelfTestResult
+	GetInfoResponse
+	AssetInfo
+	AssetsResponse
 */
 package crpc
 
@@ -50,6 +185,42 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type FederationStatus int32
+
+const (
+	FederationStatus_FEDERATION_STATUS_NONE FederationStatus = 0
+	//
+	// FEDERATION_PENDING means that transfer or settlement hasn't been
+	// acknowledged by the peer yet, it is retried in the background.
+	FederationStatus_FEDERATION_PENDING FederationStatus = 1
+	//
+	// FEDERATION_COMPLETED means that transfer or settlement has been
+	// acknowledged by the peer.
+	FederationStatus_FEDERATION_COMPLETED FederationStatus = 2
+	//
+	// FEDERATION_REJECTED means that peer has refused the transfer, debit
+	// of the account is failed.
+	FederationStatus_FEDERATION_REJECTED FederationStatus = 3
+)
+
+var FederationStatus_name = map[int32]string{
+	0: "FEDERATION_STATUS_NONE",
+	1: "FEDERATION_PENDING",
+	2: "FEDERATION_COMPLETED",
+	3: "FEDERATION_REJECTED",
+}
+var FederationStatus_value = map[string]int32{
+	"FEDERATION_STATUS_NONE": 0,
+	"FEDERATION_PENDING":     1,
+	"FEDERATION_COMPLETED":   2,
+	"FEDERATION_REJECTED":    3,
+}
+
+func (x FederationStatus) String() string {
+	return proto.EnumName(FederationStatus_name, int32(x))
+}
+func (FederationStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
 // Asset is the list of a trading assets which are available in the exchange
 // platform.
 type Asset int32
@@ -92,7 +263,7 @@ var Asset_value = map[string]int32{
 func (x Asset) String() string {
 	return proto.EnumName(Asset_name, int32(x))
 }
-func (Asset) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (Asset) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
 
 // Media is a list of possible media types. Media is a type of technology which
 // is used to transport value of underlying asset.
@@ -123,7 +294,7 @@ var Media_value = map[string]int32{
 func (x Media) String() string {
 	return proto.EnumName(Media_name, int32(x))
 }
-func (Media) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (Media) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 // PaymentStatus denotes the stage of the processing the payment.
 type PaymentStatus int32
@@ -169,7 +340,7 @@ var PaymentStatus_value = map[string]int32{
 func (x PaymentStatus) String() string {
 	return proto.EnumName(PaymentStatus_name, int32(x))
 }
-func (PaymentStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (PaymentStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 // PaymentDirection denotes the direction of the payment, whether payment is
 // 	going form us to someone else, or form someone else to us.
@@ -201,7 +372,42 @@ var PaymentDirection_value = map[string]int32{
 func (x PaymentDirection) String() string {
 	return proto.EnumName(PaymentDirection_name, int32(x))
 }
-func (PaymentDirection) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (PaymentDirection) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+// QuarantineState is the state of the compliance review of the incoming
+// payment.
+type QuarantineState int32
+
+const (
+	QuarantineState_QUARANTINE_NONE QuarantineState = 0
+	//
+	// QUARANTINED payment is under the review, its funds are not spendable.
+	QuarantineState_QUARANTINED QuarantineState = 1
+	//
+	// QUARANTINE_RELEASED payment has passed the review.
+	QuarantineState_QUARANTINE_RELEASED QuarantineState = 2
+	//
+	// QUARANTINE_RETURNED payment has been sent back after the review.
+	QuarantineState_QUARANTINE_RETURNED QuarantineState = 3
+)
+
+var QuarantineState_name = map[int32]string{
+	0: "QUARANTINE_NONE",
+	1: "QUARANTINED",
+	2: "QUARANTINE_RELEASED",
+	3: "QUARANTINE_RETURNED",
+}
+var QuarantineState_value = map[string]int32{
+	"QUARANTINE_NONE":     0,
+	"QUARANTINED":         1,
+	"QUARANTINE_RELEASED": 2,
+	"QUARANTINE_RETURNED": 3,
+}
+
+func (x QuarantineState) String() string {
+	return proto.EnumName(QuarantineState_name, int32(x))
+}
+func (QuarantineState) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 // PaymentSystemSystem denotes is that payment belongs to business logic of
 // payment server or it was originated by user / third-party service.
@@ -237,7 +443,296 @@ var PaymentSystem_value = map[string]int32{
 func (x PaymentSystem) String() string {
 	return proto.EnumName(PaymentSystem_name, int32(x))
 }
-func (PaymentSystem) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (PaymentSystem) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+// ExportFormat is the format of the payments export file.
+type ExportFormat int32
+
+const (
+	//
+	// EXPORT_CSV is comma separated values with the header line.
+	ExportFormat_EXPORT_CSV ExportFormat = 0
+	//
+	// EXPORT_JSON_LINES is one JSON object per line.
+	ExportFormat_EXPORT_JSON_LINES ExportFormat = 1
+)
+
+var ExportFormat_name = map[int32]string{
+	0: "EXPORT_CSV",
+	1: "EXPORT_JSON_LINES",
+}
+var ExportFormat_value = map[string]int32{
+	"EXPORT_CSV":        0,
+	"EXPORT_JSON_LINES": 1,
+}
+
+func (x ExportFormat) String() string {
+	return proto.EnumName(ExportFormat_name, int32(x))
+}
+func (ExportFormat) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+// PaymentsSortBy is the field of the payment by which payments are sorted.
+type PaymentsSortBy int32
+
+const (
+	//
+	// SORT_UPDATED_AT sorts payments by the time of the last update.
+	PaymentsSortBy_SORT_UPDATED_AT PaymentsSortBy = 0
+	//
+	// SORT_AMOUNT sorts payments by their amount.
+	PaymentsSortBy_SORT_AMOUNT PaymentsSortBy = 1
+	//
+	// SORT_STATUS sorts payments by their status.
+	PaymentsSortBy_SORT_STATUS PaymentsSortBy = 2
+	//
+	// SORT_SEQUENCE sorts payments by their sequence number, i.e. in order
+	// of their changes.
+	PaymentsSortBy_SORT_SEQUENCE PaymentsSortBy = 3
+)
+
+var PaymentsSortBy_name = map[int32]string{
+	0: "SORT_UPDATED_AT",
+	1: "SORT_AMOUNT",
+	2: "SORT_STATUS",
+	3: "SORT_SEQUENCE",
+}
+var PaymentsSortBy_value = map[string]int32{
+	"SORT_UPDATED_AT": 0,
+	"SORT_AMOUNT":     1,
+	"SORT_STATUS":     2,
+	"SORT_SEQUENCE":   3,
+}
+
+func (x PaymentsSortBy) String() string {
+	return proto.EnumName(PaymentsSortBy_name, int32(x))
+}
+func (PaymentsSortBy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+// FeeReportPeriod is the length of the fee report window. Windows are
+// aligned in UTC, and weeks start on Monday.
+type FeeReportPeriod int32
+
+const (
+	//
+	// FEE_REPORT_DAY is the calendar day.
+	FeeReportPeriod_FEE_REPORT_DAY FeeReportPeriod = 0
+	//
+	// FEE_REPORT_WEEK is the calendar week.
+	FeeReportPeriod_FEE_REPORT_WEEK FeeReportPeriod = 1
+	//
+	// FEE_REPORT_MONTH is the calendar month.
+	FeeReportPeriod_FEE_REPORT_MONTH FeeReportPeriod = 2
+)
+
+var FeeReportPeriod_name = map[int32]string{
+	0: "FEE_REPORT_DAY",
+	1: "FEE_REPORT_WEEK",
+	2: "FEE_REPORT_MONTH",
+}
+var FeeReportPeriod_value = map[string]int32{
+	"FEE_REPORT_DAY":   0,
+	"FEE_REPORT_WEEK":  1,
+	"FEE_REPORT_MONTH": 2,
+}
+
+func (x FeeReportPeriod) String() string {
+	return proto.EnumName(FeeReportPeriod_name, int32(x))
+}
+func (FeeReportPeriod) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+// APIKeyScope is the group of methods which API key allows to call.
+type APIKeyScope int32
+
+const (
+	APIKeyScope_SCOPE_NONE APIKeyScope = 0
+	//
+	// SCOPE_RECEIVE allows only to create and validate receipts.
+	APIKeyScope_SCOPE_RECEIVE APIKeyScope = 1
+	//
+	// SCOPE_SEND allows to send payments and to call all other PayServer
+	// methods, except creation of the receipts.
+	APIKeyScope_SCOPE_SEND APIKeyScope = 2
+	//
+	// SCOPE_ADMIN allows to call all methods, including the Admin service
+	// ones, in which case admin token isn't required.
+	APIKeyScope_SCOPE_ADMIN APIKeyScope = 3
+)
+
+var APIKeyScope_name = map[int32]string{
+	0: "SCOPE_NONE",
+	1: "SCOPE_RECEIVE",
+	2: "SCOPE_SEND",
+	3: "SCOPE_ADMIN",
+}
+var APIKeyScope_value = map[string]int32{
+	"SCOPE_NONE":    0,
+	"SCOPE_RECEIVE": 1,
+	"SCOPE_SEND":    2,
+	"SCOPE_ADMIN":   3,
+}
+
+func (x APIKeyScope) String() string {
+	return proto.EnumName(APIKeyScope_name, int32(x))
+}
+func (APIKeyScope) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+// PaymentInclude is the heavy field of the payment which is returned only if
+// it is requested.
+type PaymentInclude int32
+
+const (
+	PaymentInclude_INCLUDE_NONE PaymentInclude = 0
+	//
+	// CONFIRMATIONS includes the number of confirmations of the pending
+	// blockchain payment.
+	PaymentInclude_CONFIRMATIONS PaymentInclude = 2
+	//
+	// MEMO includes the message attached to the payment.
+	PaymentInclude_MEMO PaymentInclude = 3
+	//
+	// FLAGS includes the feature flags which affected the payment.
+	PaymentInclude_FLAGS PaymentInclude = 4
+	//
+	// WARNINGS includes the advisories about the sent payment.
+	PaymentInclude_WARNINGS PaymentInclude = 5
+)
+
+var PaymentInclude_name = map[int32]string{
+	0: "INCLUDE_NONE",
+	2: "CONFIRMATIONS",
+	3: "MEMO",
+	4: "FLAGS",
+	5: "WARNINGS",
+}
+var PaymentInclude_value = map[string]int32{
+	"INCLUDE_NONE":  0,
+	"CONFIRMATIONS": 2,
+	"MEMO":          3,
+	"FLAGS":         4,
+	"WARNINGS":      5,
+}
+
+func (x PaymentInclude) String() string {
+	return proto.EnumName(PaymentInclude_name, int32(x))
+}
+func (PaymentInclude) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+type ReceiptStatus int32
+
+const (
+	ReceiptStatus_RECEIPT_STATUS_NONE ReceiptStatus = 0
+	//
+	// PAID means that completed incoming payment has been received on the
+	// receipt.
+	ReceiptStatus_PAID ReceiptStatus = 1
+	//
+	// UNPAID means that receipt hasn't been paid yet, and it still could be
+	// paid.
+	ReceiptStatus_UNPAID ReceiptStatus = 2
+	//
+	// EXPIRED means that receipt hasn't been paid before its expiration.
+	ReceiptStatus_EXPIRED ReceiptStatus = 3
+)
+
+var ReceiptStatus_name = map[int32]string{
+	0: "RECEIPT_STATUS_NONE",
+	1: "PAID",
+	2: "UNPAID",
+	3: "EXPIRED",
+}
+var ReceiptStatus_value = map[string]int32{
+	"RECEIPT_STATUS_NONE": 0,
+	"PAID":                1,
+	"UNPAID":              2,
+	"EXPIRED":             3,
+}
+
+func (x ReceiptStatus) String() string {
+	return proto.EnumName(ReceiptStatus_name, int32(x))
+}
+func (ReceiptStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+type ReceiptEventType int32
+
+const (
+	ReceiptEventType_RECEIPT_EVENT_NONE ReceiptEventType = 0
+	//
+	// RECEIPT_UPDATED means that receipt has been replaced by the new one,
+	// e.g. lightning network invoice has expired unpaid.
+	ReceiptEventType_RECEIPT_UPDATED ReceiptEventType = 1
+)
+
+var ReceiptEventType_name = map[int32]string{
+	0: "RECEIPT_EVENT_NONE",
+	1: "RECEIPT_UPDATED",
+}
+var ReceiptEventType_value = map[string]int32{
+	"RECEIPT_EVENT_NONE": 0,
+	"RECEIPT_UPDATED":    1,
+}
+
+func (x ReceiptEventType) String() string {
+	return proto.EnumName(ReceiptEventType_name, int32(x))
+}
+func (ReceiptEventType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+type PaymentEventType int32
+
+const (
+	PaymentEventType_EVENT_NONE PaymentEventType = 0
+	//
+	// EVENT_CREATED means that outgoing payment has been created.
+	PaymentEventType_EVENT_CREATED PaymentEventType = 1
+	//
+	// EVENT_BROADCAST means that outgoing payment has been passed to the
+	// media, e.g. transaction has been broadcasted in the blockchain.
+	PaymentEventType_EVENT_BROADCAST PaymentEventType = 2
+	//
+	// EVENT_FIRST_SEEN means that incoming payment has been seen for the
+	// first time.
+	PaymentEventType_EVENT_FIRST_SEEN PaymentEventType = 3
+	//
+	// EVENT_CONFIRMED means that blockchain payment has received one more
+	// confirmation, details are the number of the confirmations.
+	PaymentEventType_EVENT_CONFIRMED PaymentEventType = 4
+	//
+	// EVENT_COMPLETED means that payment has been completed.
+	PaymentEventType_EVENT_COMPLETED PaymentEventType = 5
+	//
+	// EVENT_FAILED means that payment has failed, details are the reason of
+	// the failure if it is known.
+	PaymentEventType_EVENT_FAILED PaymentEventType = 6
+	//
+	// EVENT_HOOK_DELIVERED means that finished payment has been delivered to
+	// the post-send hook, details are the target of the hook.
+	PaymentEventType_EVENT_HOOK_DELIVERED PaymentEventType = 7
+)
+
+var PaymentEventType_name = map[int32]string{
+	0: "EVENT_NONE",
+	1: "EVENT_CREATED",
+	2: "EVENT_BROADCAST",
+	3: "EVENT_FIRST_SEEN",
+	4: "EVENT_CONFIRMED",
+	5: "EVENT_COMPLETED",
+	6: "EVENT_FAILED",
+	7: "EVENT_HOOK_DELIVERED",
+}
+var PaymentEventType_value = map[string]int32{
+	"EVENT_NONE":           0,
+	"EVENT_CREATED":        1,
+	"EVENT_BROADCAST":      2,
+	"EVENT_FIRST_SEEN":     3,
+	"EVENT_CONFIRMED":      4,
+	"EVENT_COMPLETED":      5,
+	"EVENT_FAILED":         6,
+	"EVENT_HOOK_DELIVERED": 7,
+}
+
+func (x PaymentEventType) String() string {
+	return proto.EnumName(PaymentEventType_name, int32(x))
+}
+func (PaymentEventType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 type EmptyRequest struct {
 }
@@ -255,6 +750,64 @@ func (m *EmptyResponse) String() string            { return proto.CompactTextStr
 func (*EmptyResponse) ProtoMessage()               {}
 func (*EmptyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
 
+type ProvideAddressRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Network is the network of the asset, e.g. mainnet, testnet or simnet.
+	Network string `protobuf:"bytes,2,opt,name=network" json:"network,omitempty"`
+	//
+	// (optional) Tenant is the id of the API key on behalf of which address
+	// is requested, so that provider could derive it from the tenant's
+	// branch of the tree.
+	Tenant string `protobuf:"bytes,3,opt,name=tenant" json:"tenant,omitempty"`
+}
+
+func (m *ProvideAddressRequest) Reset()                    { *m = ProvideAddressRequest{} }
+func (m *ProvideAddressRequest) String() string            { return proto.CompactTextString(m) }
+func (*ProvideAddressRequest) ProtoMessage()               {}
+func (*ProvideAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ProvideAddressRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *ProvideAddressRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *ProvideAddressRequest) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
+type ProvideAddressResponse struct {
+	//
+	// Address is the derived deposit address.
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *ProvideAddressResponse) Reset()                    { *m = ProvideAddressResponse{} }
+func (m *ProvideAddressResponse) String() string            { return proto.CompactTextString(m) }
+func (*ProvideAddressResponse) ProtoMessage()               {}
+func (*ProvideAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *ProvideAddressResponse) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
 type CreateReceiptRequest struct {
 	//
 	// Asset is an acronim of the crypto currency.
@@ -272,12 +825,37 @@ type CreateReceiptRequest struct {
 	// description will be placed in the invoice itself, which would allow user
 	// to see what he paid for later in the wallet.
 	Description string `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
+	//
+	// (optional) Expiry is the time in seconds after which blockchain
+	// receipt is considered expired if it hasn't been paid. Blockchain
+	// receipt doesn't expire if it is not specified, expiry of the lightning
+	// network invoice is set by the node.
+	Expiry int64 `protobuf:"varint,5,opt,name=expiry" json:"expiry,omitempty"`
+	//
+	// (optional) Account is the identifier of the account, e.g. internal
+	// product, to which receipt and its incoming payments belong, so that
+	// balances of the several products served by the same server are kept
+	// separately.
+	Account string `protobuf:"bytes,6,opt,name=account" json:"account,omitempty"`
+	//
+	// (optional) Metadata is the set of the labels, e.g. order id or
+	// customer id, which are attached to the receipt and to its incoming
+	// payments.
+	Metadata map[string]string `protobuf:"bytes,7,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	//
+	// (optional) Reference works only for lightning invoices. Reference is
+	// the external order reference, e.g. the serialized order, which is
+	// stored by the server, while invoice carries only its SHA256 hash in
+	// the description_hash field, so that long payload doesn't bloat the
+	// invoice, yet payer is able to verify it. It couldn't be used along
+	// with the description.
+	Reference string `protobuf:"bytes,8,opt,name=reference" json:"reference,omitempty"`
 }
 
 func (m *CreateReceiptRequest) Reset()                    { *m = CreateReceiptRequest{} }
 func (m *CreateReceiptRequest) String() string            { return proto.CompactTextString(m) }
 func (*CreateReceiptRequest) ProtoMessage()               {}
-func (*CreateReceiptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*CreateReceiptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *CreateReceiptRequest) GetAsset() Asset {
 	if m != nil {
@@ -307,1106 +885,10699 @@ func (m *CreateReceiptRequest) GetDescription() string {
 	return ""
 }
 
-type CreateReceiptResponse struct {
-	//
-	// When this invoice was created.
-	// NOTE: Only returns for lightning network media.
-	CreationDate int64 `protobuf:"varint,1,opt,name=creation_date,json=creationDate" json:"creation_date,omitempty"`
-	//
-	// Receipt represent either blockchains address or lightning network invoice,
-	// depending on the type of the request.
-	Receipt string `protobuf:"bytes,2,opt,name=receipt" json:"receipt,omitempty"`
-	//
-	// Invoice expiry time in seconds. Default is 3600 (1 hour).
-	// NOTE: Only returns for lightning network media.
-	Expiry int64 `protobuf:"varint,3,opt,name=expiry" json:"expiry,omitempty"`
-}
-
-func (m *CreateReceiptResponse) Reset()                    { *m = CreateReceiptResponse{} }
-func (m *CreateReceiptResponse) String() string            { return proto.CompactTextString(m) }
-func (*CreateReceiptResponse) ProtoMessage()               {}
-func (*CreateReceiptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
-
-func (m *CreateReceiptResponse) GetCreationDate() int64 {
+func (m *CreateReceiptRequest) GetExpiry() int64 {
 	if m != nil {
-		return m.CreationDate
+		return m.Expiry
 	}
 	return 0
 }
 
-func (m *CreateReceiptResponse) GetReceipt() string {
+func (m *CreateReceiptRequest) GetAccount() string {
 	if m != nil {
-		return m.Receipt
+		return m.Account
 	}
 	return ""
 }
 
-func (m *CreateReceiptResponse) GetExpiry() int64 {
+func (m *CreateReceiptRequest) GetMetadata() map[string]string {
 	if m != nil {
-		return m.Expiry
+		return m.Metadata
 	}
-	return 0
+	return nil
 }
 
-type BalanceRequest struct {
+func (m *CreateReceiptRequest) GetReference() string {
+	if m != nil {
+		return m.Reference
+	}
+	return ""
+}
+
+type NewAddressRequest struct {
 	//
 	// Asset is an acronim of the crypto currency.
 	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
 	//
-	// Media is a type of technology which is used to transport value of
-	// underlying asset.
-	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	// (optional) Account is the identifier of the account, e.g. internal
+	// product, to which incoming payments on the address belong.
+	Account string `protobuf:"bytes,2,opt,name=account" json:"account,omitempty"`
 }
 
-func (m *BalanceRequest) Reset()                    { *m = BalanceRequest{} }
-func (m *BalanceRequest) String() string            { return proto.CompactTextString(m) }
-func (*BalanceRequest) ProtoMessage()               {}
-func (*BalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (m *NewAddressRequest) Reset()                    { *m = NewAddressRequest{} }
+func (m *NewAddressRequest) String() string            { return proto.CompactTextString(m) }
+func (*NewAddressRequest) ProtoMessage()               {}
+func (*NewAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
-func (m *BalanceRequest) GetAsset() Asset {
+func (m *NewAddressRequest) GetAsset() Asset {
 	if m != nil {
 		return m.Asset
 	}
 	return Asset_ASSET_NONE
 }
 
-func (m *BalanceRequest) GetMedia() Media {
+func (m *NewAddressRequest) GetAccount() string {
 	if m != nil {
-		return m.Media
+		return m.Account
 	}
-	return Media_MEDIA_NONE
+	return ""
 }
 
-type Balance struct {
-	//
-	// Available is the number of funds which could be used by this account
-	// to send funds to someone else within the specified media.
-	Available string `protobuf:"bytes,1,opt,name=available" json:"available,omitempty"`
-	//
-	// Pending is the number of funds are in the state of confirmation. In
-	// case of blockchain media it is the transactions which are not
-	// confirmed. In case of lightning media it is funds in pending payment
-	// channels.
-	Pending string `protobuf:"bytes,2,opt,name=pending" json:"pending,omitempty"`
-	//
-	// Asset is an acronim of the crypto currency.
-	Asset Asset `protobuf:"varint,3,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+type NewAddressResponse struct {
 	//
-	// Media is a type of technology which is used to transport value of
-	// underlying asset.
-	Media Media `protobuf:"varint,4,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	// Address is the blockchain address in the canonical form, in which it
+	// is returned in the incoming payments.
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
 }
 
-func (m *Balance) Reset()                    { *m = Balance{} }
-func (m *Balance) String() string            { return proto.CompactTextString(m) }
-func (*Balance) ProtoMessage()               {}
-func (*Balance) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (m *NewAddressResponse) Reset()                    { *m = NewAddressResponse{} }
+func (m *NewAddressResponse) String() string            { return proto.CompactTextString(m) }
+func (*NewAddressResponse) ProtoMessage()               {}
+func (*NewAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
-func (m *Balance) GetAvailable() string {
+func (m *NewAddressResponse) GetAddress() string {
 	if m != nil {
-		return m.Available
+		return m.Address
 	}
 	return ""
 }
 
-func (m *Balance) GetPending() string {
-	if m != nil {
-		return m.Pending
-	}
-	return ""
+type ListReceiptsRequest struct {
+	//
+	// (optional) Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// (optional) Media is a type of technology which is used to transport
+	// value of underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// (optional) Status is used to return only paid, unpaid or expired
+	// receipts.
+	Status ReceiptStatus `protobuf:"varint,3,opt,name=status,enum=crpc.ReceiptStatus" json:"status,omitempty"`
+	//
+	// (optional) CreatedFrom is the time in milliseconds from which
+	// receipts are returned, inclusive.
+	CreatedFrom int64 `protobuf:"varint,4,opt,name=created_from,json=createdFrom" json:"created_from,omitempty"`
+	//
+	// (optional) CreatedTo is the time in milliseconds until which
+	// receipts are returned, inclusive.
+	CreatedTo int64 `protobuf:"varint,5,opt,name=created_to,json=createdTo" json:"created_to,omitempty"`
+	//
+	// (optional) Limit is the maximum number of returned receipts, all
+	// matching receipts are returned if it is not specified.
+	Limit uint32 `protobuf:"varint,6,opt,name=limit" json:"limit,omitempty"`
+	//
+	// (optional) Offset is the number of matching receipts which are
+	// skipped, it is used along with the limit to fetch the next page.
+	Offset uint64 `protobuf:"varint,7,opt,name=offset" json:"offset,omitempty"`
 }
 
-func (m *Balance) GetAsset() Asset {
+func (m *ListReceiptsRequest) Reset()                    { *m = ListReceiptsRequest{} }
+func (m *ListReceiptsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListReceiptsRequest) ProtoMessage()               {}
+func (*ListReceiptsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *ListReceiptsRequest) GetAsset() Asset {
 	if m != nil {
 		return m.Asset
 	}
 	return Asset_ASSET_NONE
 }
 
-func (m *Balance) GetMedia() Media {
+func (m *ListReceiptsRequest) GetMedia() Media {
 	if m != nil {
 		return m.Media
 	}
 	return Media_MEDIA_NONE
 }
 
-type ValidateReceiptResponse struct {
-	// Types that are valid to be assigned to Data:
-	//	*ValidateReceiptResponse_Invoice
-	Data isValidateReceiptResponse_Data `protobuf_oneof:"data"`
+func (m *ListReceiptsRequest) GetStatus() ReceiptStatus {
+	if m != nil {
+		return m.Status
+	}
+	return ReceiptStatus_RECEIPT_STATUS_NONE
 }
 
-func (m *ValidateReceiptResponse) Reset()                    { *m = ValidateReceiptResponse{} }
-func (m *ValidateReceiptResponse) String() string            { return proto.CompactTextString(m) }
-func (*ValidateReceiptResponse) ProtoMessage()               {}
-func (*ValidateReceiptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
-
-type isValidateReceiptResponse_Data interface{ isValidateReceiptResponse_Data() }
-
-type ValidateReceiptResponse_Invoice struct {
-	Invoice *Invoice `protobuf:"bytes,1,opt,name=invoice,oneof"`
-}
-
-func (*ValidateReceiptResponse_Invoice) isValidateReceiptResponse_Data() {}
-
-func (m *ValidateReceiptResponse) GetData() isValidateReceiptResponse_Data {
+func (m *ListReceiptsRequest) GetCreatedFrom() int64 {
 	if m != nil {
-		return m.Data
-	}
-	return nil
-}
-
-func (m *ValidateReceiptResponse) GetInvoice() *Invoice {
-	if x, ok := m.GetData().(*ValidateReceiptResponse_Invoice); ok {
-		return x.Invoice
-	}
-	return nil
-}
-
-// XXX_OneofFuncs is for the internal use of the proto package.
-func (*ValidateReceiptResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
-	return _ValidateReceiptResponse_OneofMarshaler, _ValidateReceiptResponse_OneofUnmarshaler, _ValidateReceiptResponse_OneofSizer, []interface{}{
-		(*ValidateReceiptResponse_Invoice)(nil),
+		return m.CreatedFrom
 	}
+	return 0
 }
 
-func _ValidateReceiptResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
-	m := msg.(*ValidateReceiptResponse)
-	// data
-	switch x := m.Data.(type) {
-	case *ValidateReceiptResponse_Invoice:
-		b.EncodeVarint(1<<3 | proto.WireBytes)
-		if err := b.EncodeMessage(x.Invoice); err != nil {
-			return err
-		}
-	case nil:
-	default:
-		return fmt.Errorf("ValidateReceiptResponse.Data has unexpected type %T", x)
+func (m *ListReceiptsRequest) GetCreatedTo() int64 {
+	if m != nil {
+		return m.CreatedTo
 	}
-	return nil
+	return 0
 }
 
-func _ValidateReceiptResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
-	m := msg.(*ValidateReceiptResponse)
-	switch tag {
-	case 1: // data.invoice
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
-		}
-		msg := new(Invoice)
-		err := b.DecodeMessage(msg)
-		m.Data = &ValidateReceiptResponse_Invoice{msg}
-		return true, err
-	default:
-		return false, nil
+func (m *ListReceiptsRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
 	}
+	return 0
 }
 
-func _ValidateReceiptResponse_OneofSizer(msg proto.Message) (n int) {
-	m := msg.(*ValidateReceiptResponse)
-	// data
-	switch x := m.Data.(type) {
-	case *ValidateReceiptResponse_Invoice:
-		s := proto.Size(x.Invoice)
-		n += proto.SizeVarint(1<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case nil:
-	default:
-		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+func (m *ListReceiptsRequest) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
 	}
-	return n
+	return 0
 }
 
-type Invoice struct {
+type Receipt struct {
 	//
-	// An optional memo to attach along with the invoice. Used for record keeping
-	// purposes for the invoice's creator, and will also be set in the
-	// description field of the encoded payment request if the
-	// description_hash field is not being used.
-	Memo string `protobuf:"bytes,1,opt,name=memo" json:"memo,omitempty"`
+	// Receipt is either blockchain address or lightning network invoice.
+	Receipt string `protobuf:"bytes,1,opt,name=receipt" json:"receipt,omitempty"`
 	//
-	// The value of this invoice in bitcoins.
-	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
 	//
-	// When this invoice was created.
-	CreationDate int64 `protobuf:"varint,3,opt,name=creation_date,json=creationDate" json:"creation_date,omitempty"`
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,3,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
 	//
-	// Invoice expiry time in seconds. Default is 3600 (1 hour).
-	Expiry int64 `protobuf:"varint,4,opt,name=expiry" json:"expiry,omitempty"`
+	// Amount is the amount which was requested, zero if it wasn't
+	// specified.
+	Amount string `protobuf:"bytes,4,opt,name=amount" json:"amount,omitempty"`
 	//
-	// Fallback on-chain address in case of lightning network payment fail.
-	FallbackAddr string `protobuf:"bytes,5,opt,name=fallback_addr,json=fallbackAddr" json:"fallback_addr,omitempty"`
+	// Description is the description of the lightning network invoice.
+	Description string `protobuf:"bytes,5,opt,name=description" json:"description,omitempty"`
 	//
-	// Lightning Network public key of receiving node.
-	Destination string `protobuf:"bytes,6,opt,name=destination" json:"destination,omitempty"`
+	// CreatedAt is the time of the receipt creation in milliseconds.
+	CreatedAt int64 `protobuf:"varint,6,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	//
+	// ExpiresAt is the time in milliseconds after which receipt couldn't be
+	// paid, zero if receipt doesn't expire.
+	ExpiresAt int64 `protobuf:"varint,7,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+	//
+	// Status denotes whether receipt has been paid.
+	Status ReceiptStatus `protobuf:"varint,8,opt,name=status,enum=crpc.ReceiptStatus" json:"status,omitempty"`
+	//
+	// ReceiptID is the identifier of the receipt.
+	ReceiptId string `protobuf:"bytes,9,opt,name=receipt_id,json=receiptId" json:"receipt_id,omitempty"`
+	//
+	// Metadata is the set of the labels attached to the receipt.
+	Metadata map[string]string `protobuf:"bytes,10,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	//
+	// Replaces is the identifier of the receipt which has been replaced by
+	// this one, empty if receipt has been created by CreateReceipt.
+	Replaces string `protobuf:"bytes,11,opt,name=replaces" json:"replaces,omitempty"`
+	//
+	// ReplacedBy is the identifier of the receipt which has replaced this
+	// one, empty if receipt hasn't been replaced.
+	ReplacedBy string `protobuf:"bytes,12,opt,name=replaced_by,json=replacedBy" json:"replaced_by,omitempty"`
+	//
+	// Reference is the external order reference to which lightning invoice
+	// is bound by the description hash, empty if it wasn't specified.
+	Reference string `protobuf:"bytes,13,opt,name=reference" json:"reference,omitempty"`
+	//
+	// DescriptionHash is the hex encoded SHA256 hash of the reference,
+	// which is placed in the invoice instead of the description.
+	DescriptionHash string `protobuf:"bytes,14,opt,name=description_hash,json=descriptionHash" json:"description_hash,omitempty"`
+	//
+	// RefundAddress is the blockchain address supplied by the payer on
+	// which refunds are sent, empty if it hasn't been attached.
+	RefundAddress string `protobuf:"bytes,15,opt,name=refund_address,json=refundAddress" json:"refund_address,omitempty"`
 }
 
-func (m *Invoice) Reset()                    { *m = Invoice{} }
-func (m *Invoice) String() string            { return proto.CompactTextString(m) }
-func (*Invoice) ProtoMessage()               {}
-func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (m *Receipt) Reset()                    { *m = Receipt{} }
+func (m *Receipt) String() string            { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()               {}
+func (*Receipt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
-func (m *Invoice) GetMemo() string {
+func (m *Receipt) GetReceipt() string {
 	if m != nil {
-		return m.Memo
+		return m.Receipt
 	}
 	return ""
 }
 
-func (m *Invoice) GetValue() string {
+func (m *Receipt) GetAsset() Asset {
 	if m != nil {
-		return m.Value
+		return m.Asset
 	}
-	return ""
+	return Asset_ASSET_NONE
 }
 
-func (m *Invoice) GetCreationDate() int64 {
+func (m *Receipt) GetMedia() Media {
 	if m != nil {
-		return m.CreationDate
+		return m.Media
 	}
-	return 0
+	return Media_MEDIA_NONE
 }
 
-func (m *Invoice) GetExpiry() int64 {
+func (m *Receipt) GetAmount() string {
 	if m != nil {
-		return m.Expiry
+		return m.Amount
 	}
-	return 0
+	return ""
 }
 
-func (m *Invoice) GetFallbackAddr() string {
+func (m *Receipt) GetDescription() string {
 	if m != nil {
-		return m.FallbackAddr
+		return m.Description
 	}
 	return ""
 }
 
-func (m *Invoice) GetDestination() string {
+func (m *Receipt) GetCreatedAt() int64 {
 	if m != nil {
-		return m.Destination
+		return m.CreatedAt
 	}
-	return ""
+	return 0
 }
 
-type BalanceResponse struct {
-	Balances []*Balance `protobuf:"bytes,1,rep,name=balances" json:"balances,omitempty"`
+func (m *Receipt) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
 }
 
-func (m *BalanceResponse) Reset()                    { *m = BalanceResponse{} }
-func (m *BalanceResponse) String() string            { return proto.CompactTextString(m) }
-func (*BalanceResponse) ProtoMessage()               {}
-func (*BalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (m *Receipt) GetStatus() ReceiptStatus {
+	if m != nil {
+		return m.Status
+	}
+	return ReceiptStatus_RECEIPT_STATUS_NONE
+}
 
-func (m *BalanceResponse) GetBalances() []*Balance {
+func (m *Receipt) GetReceiptId() string {
 	if m != nil {
-		return m.Balances
+		return m.ReceiptId
 	}
-	return nil
+	return ""
 }
 
-type ValidateReceiptRequest struct {
-	//
-	// Receipt is the blockchain address in case of blockchain media and
-	// lightning network invoice in case of lightning media.
-	Receipt string `protobuf:"bytes,1,opt,name=receipt" json:"receipt,omitempty"`
-	//
-	// Asset is an acronim of the crypto currency.
-	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
-	//
-	// Media is a type of technology which is used to transport value of
-	// underlying asset.
-	Media Media `protobuf:"varint,3,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
-	//
-	// (optional) Amount is the amount which should be received on this
-	// receipt.
-	Amount string `protobuf:"bytes,4,opt,name=amount" json:"amount,omitempty"`
+func (m *Receipt) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
 }
 
-func (m *ValidateReceiptRequest) Reset()                    { *m = ValidateReceiptRequest{} }
-func (m *ValidateReceiptRequest) String() string            { return proto.CompactTextString(m) }
-func (*ValidateReceiptRequest) ProtoMessage()               {}
-func (*ValidateReceiptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (m *Receipt) GetReplaces() string {
+	if m != nil {
+		return m.Replaces
+	}
+	return ""
+}
 
-func (m *ValidateReceiptRequest) GetReceipt() string {
+func (m *Receipt) GetReplacedBy() string {
 	if m != nil {
-		return m.Receipt
+		return m.ReplacedBy
 	}
 	return ""
 }
 
-func (m *ValidateReceiptRequest) GetAsset() Asset {
+func (m *Receipt) GetReference() string {
 	if m != nil {
-		return m.Asset
+		return m.Reference
 	}
-	return Asset_ASSET_NONE
+	return ""
 }
 
-func (m *ValidateReceiptRequest) GetMedia() Media {
+func (m *Receipt) GetDescriptionHash() string {
 	if m != nil {
-		return m.Media
+		return m.DescriptionHash
 	}
-	return Media_MEDIA_NONE
+	return ""
 }
 
-func (m *ValidateReceiptRequest) GetAmount() string {
+func (m *Receipt) GetRefundAddress() string {
 	if m != nil {
-		return m.Amount
+		return m.RefundAddress
 	}
 	return ""
 }
 
-type EstimateFeeRequest struct {
-	//
-	// Asset is an acronim of the crypto currency.
-	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+type AttachRefundAddressRequest struct {
 	//
-	// Media is a type of technology which is used to transport value of
-	// underlying asset.
-	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
-	//
-	// (optional) Amount is number of money which should be given to the
-	// another entity.
-	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	// ReceiptID is the identifier returned by CreateReceipt.
+	ReceiptId string `protobuf:"bytes,1,opt,name=receipt_id,json=receiptId" json:"receipt_id,omitempty"`
 	//
-	// (optional) Receipt represent either blockchains address or lightning
-	// network invoice. If receipt is specified the number are more accurate
-	// for lightning network payment.
-	Receipt string `protobuf:"bytes,4,opt,name=receipt" json:"receipt,omitempty"`
+	// RefundAddress is the blockchain address in the asset of the receipt,
+	// refunds of the lightning network payments are sent on it too.
+	RefundAddress string `protobuf:"bytes,2,opt,name=refund_address,json=refundAddress" json:"refund_address,omitempty"`
 }
 
-func (m *EstimateFeeRequest) Reset()                    { *m = EstimateFeeRequest{} }
-func (m *EstimateFeeRequest) String() string            { return proto.CompactTextString(m) }
-func (*EstimateFeeRequest) ProtoMessage()               {}
-func (*EstimateFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (m *AttachRefundAddressRequest) Reset()                    { *m = AttachRefundAddressRequest{} }
+func (m *AttachRefundAddressRequest) String() string            { return proto.CompactTextString(m) }
+func (*AttachRefundAddressRequest) ProtoMessage()               {}
+func (*AttachRefundAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
-func (m *EstimateFeeRequest) GetAsset() Asset {
+func (m *AttachRefundAddressRequest) GetReceiptId() string {
 	if m != nil {
-		return m.Asset
+		return m.ReceiptId
 	}
-	return Asset_ASSET_NONE
+	return ""
 }
 
-func (m *EstimateFeeRequest) GetMedia() Media {
+func (m *AttachRefundAddressRequest) GetRefundAddress() string {
 	if m != nil {
-		return m.Media
+		return m.RefundAddress
 	}
-	return Media_MEDIA_NONE
+	return ""
 }
 
-func (m *EstimateFeeRequest) GetAmount() string {
-	if m != nil {
-		return m.Amount
-	}
-	return ""
+type ReceiptByIDRequest struct {
+	//
+	// ReceiptID is the identifier returned by CreateReceipt.
+	ReceiptId string `protobuf:"bytes,1,opt,name=receipt_id,json=receiptId" json:"receipt_id,omitempty"`
 }
 
-func (m *EstimateFeeRequest) GetReceipt() string {
+func (m *ReceiptByIDRequest) Reset()                    { *m = ReceiptByIDRequest{} }
+func (m *ReceiptByIDRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReceiptByIDRequest) ProtoMessage()               {}
+func (*ReceiptByIDRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *ReceiptByIDRequest) GetReceiptId() string {
 	if m != nil {
-		return m.Receipt
+		return m.ReceiptId
 	}
 	return ""
 }
 
-type EstimateFeeResponse struct {
+type SubscribeReceiptsRequest struct {
 	//
-	// MediaFee is the fee which is taken by the blockchain or lightning
-	// network in order to propagate the payment.
-	MediaFee string `protobuf:"bytes,1,opt,name=media_fee,json=mediaFee" json:"media_fee,omitempty"`
+	// ReceiptID, if set, restricts events to the receipt. Once receipt is
+	// replaced, events of the replacement are sent as well.
+	ReceiptId string `protobuf:"bytes,1,opt,name=receipt_id,json=receiptId" json:"receipt_id,omitempty"`
 }
 
-func (m *EstimateFeeResponse) Reset()                    { *m = EstimateFeeResponse{} }
-func (m *EstimateFeeResponse) String() string            { return proto.CompactTextString(m) }
-func (*EstimateFeeResponse) ProtoMessage()               {}
-func (*EstimateFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (m *SubscribeReceiptsRequest) Reset()                    { *m = SubscribeReceiptsRequest{} }
+func (m *SubscribeReceiptsRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeReceiptsRequest) ProtoMessage()               {}
+func (*SubscribeReceiptsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
-func (m *EstimateFeeResponse) GetMediaFee() string {
+func (m *SubscribeReceiptsRequest) GetReceiptId() string {
 	if m != nil {
-		return m.MediaFee
+		return m.ReceiptId
 	}
 	return ""
 }
 
-type SendPaymentRequest struct {
-	//
-	// Asset is an acronim of the crypto currency.
-	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+type ReceiptEvent struct {
 	//
-	// Media is a type of technology which is used to transport value of
-	// underlying asset.
-	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	// Type is the type of the event.
+	Type ReceiptEventType `protobuf:"varint,1,opt,name=type,enum=crpc.ReceiptEventType" json:"type,omitempty"`
 	//
-	// Amount is number of money which should be given to the another entity.
-	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	// ReceiptID is the identifier of the receipt which has been changed.
+	ReceiptId string `protobuf:"bytes,2,opt,name=receipt_id,json=receiptId" json:"receipt_id,omitempty"`
 	//
-	// Receipt represent either blockchains address or lightning
-	// network invoice, which we should use determine payment receiver.
-	Receipt string `protobuf:"bytes,4,opt,name=receipt" json:"receipt,omitempty"`
+	// Receipt is the current state of the receipt, for RECEIPT_UPDATED
+	// event it is the replacement, which should be shown instead.
+	Receipt *Receipt `protobuf:"bytes,3,opt,name=receipt" json:"receipt,omitempty"`
 }
 
-func (m *SendPaymentRequest) Reset()                    { *m = SendPaymentRequest{} }
-func (m *SendPaymentRequest) String() string            { return proto.CompactTextString(m) }
-func (*SendPaymentRequest) ProtoMessage()               {}
-func (*SendPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (m *ReceiptEvent) Reset()                    { *m = ReceiptEvent{} }
+func (m *ReceiptEvent) String() string            { return proto.CompactTextString(m) }
+func (*ReceiptEvent) ProtoMessage()               {}
+func (*ReceiptEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
-func (m *SendPaymentRequest) GetAsset() Asset {
+func (m *ReceiptEvent) GetType() ReceiptEventType {
 	if m != nil {
-		return m.Asset
+		return m.Type
 	}
-	return Asset_ASSET_NONE
+	return ReceiptEventType_RECEIPT_EVENT_NONE
 }
 
-func (m *SendPaymentRequest) GetMedia() Media {
+func (m *ReceiptEvent) GetReceiptId() string {
 	if m != nil {
-		return m.Media
+		return m.ReceiptId
 	}
-	return Media_MEDIA_NONE
+	return ""
 }
 
-func (m *SendPaymentRequest) GetAmount() string {
-	if m != nil {
-		return m.Amount
-	}
-	return ""
-}
-
-func (m *SendPaymentRequest) GetReceipt() string {
+func (m *ReceiptEvent) GetReceipt() *Receipt {
 	if m != nil {
 		return m.Receipt
 	}
-	return ""
-}
-
-type PaymentByIDRequest struct {
-	//
-	// PaymentID is the payment id which was created by service itself,
-	// for unified identification of the payment.
-	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
-}
-
-func (m *PaymentByIDRequest) Reset()                    { *m = PaymentByIDRequest{} }
-func (m *PaymentByIDRequest) String() string            { return proto.CompactTextString(m) }
-func (*PaymentByIDRequest) ProtoMessage()               {}
-func (*PaymentByIDRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
-
-func (m *PaymentByIDRequest) GetPaymentId() string {
-	if m != nil {
-		return m.PaymentId
-	}
-	return ""
+	return nil
 }
 
-type PaymentsByReceiptRequest struct {
+type ListReceiptsResponse struct {
+	Receipts []*Receipt `protobuf:"bytes,1,rep,name=receipts" json:"receipts,omitempty"`
 	//
-	// Receipt represent either blockchains address or lightning
-	// network invoice, depending on the type of the request.
-	Receipt string `protobuf:"bytes,1,opt,name=receipt" json:"receipt,omitempty"`
+	// Total is the overall number of receipts which are matching the
+	// filter, regardless of the limit and offset.
+	Total uint64 `protobuf:"varint,2,opt,name=total" json:"total,omitempty"`
 }
 
-func (m *PaymentsByReceiptRequest) Reset()                    { *m = PaymentsByReceiptRequest{} }
-func (m *PaymentsByReceiptRequest) String() string            { return proto.CompactTextString(m) }
-func (*PaymentsByReceiptRequest) ProtoMessage()               {}
-func (*PaymentsByReceiptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (m *ListReceiptsResponse) Reset()                    { *m = ListReceiptsResponse{} }
+func (m *ListReceiptsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListReceiptsResponse) ProtoMessage()               {}
+func (*ListReceiptsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
-func (m *PaymentsByReceiptRequest) GetReceipt() string {
+func (m *ListReceiptsResponse) GetReceipts() []*Receipt {
 	if m != nil {
-		return m.Receipt
+		return m.Receipts
 	}
-	return ""
-}
-
-type PaymentsByReceiptResponse struct {
-	Payments []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+	return nil
 }
 
-func (m *PaymentsByReceiptResponse) Reset()                    { *m = PaymentsByReceiptResponse{} }
-func (m *PaymentsByReceiptResponse) String() string            { return proto.CompactTextString(m) }
-func (*PaymentsByReceiptResponse) ProtoMessage()               {}
-func (*PaymentsByReceiptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
-
-func (m *PaymentsByReceiptResponse) GetPayments() []*Payment {
+func (m *ListReceiptsResponse) GetTotal() uint64 {
 	if m != nil {
-		return m.Payments
+		return m.Total
 	}
-	return nil
+	return 0
 }
 
-type ListPaymentsRequest struct {
+type CreateReceiptResponse struct {
 	//
-	// (optional) Status denotes the stage of the processing the payment.
-	Status PaymentStatus `protobuf:"varint,1,opt,name=status,enum=crpc.PaymentStatus" json:"status,omitempty"`
+	// When this invoice was created.
+	// NOTE: Only returns for lightning network media.
+	CreationDate int64 `protobuf:"varint,1,opt,name=creation_date,json=creationDate" json:"creation_date,omitempty"`
 	//
-	// (optional) Direction denotes the direction of the payment.
-	Direction PaymentDirection `protobuf:"varint,2,opt,name=direction,enum=crpc.PaymentDirection" json:"direction,omitempty"`
+	// Receipt represent either blockchains address or lightning network invoice,
+	// depending on the type of the request.
+	Receipt string `protobuf:"bytes,2,opt,name=receipt" json:"receipt,omitempty"`
 	//
-	// (optional) Asset is an acronim of the crypto currency.
-	Asset Asset `protobuf:"varint,3,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	// Invoice expiry time in seconds. Default is 3600 (1 hour).
+	// NOTE: Only returns for lightning network media.
+	Expiry int64 `protobuf:"varint,3,opt,name=expiry" json:"expiry,omitempty"`
 	//
-	// (optional) Media is a type of technology which is used to transport
-	// value of underlying asset.
-	Media Media `protobuf:"varint,4,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	// Warnings is the list of non-fatal advisories about the receipt, e.g.
+	// low inbound liquidity of the lightning network node.
+	Warnings []string `protobuf:"bytes,4,rep,name=warnings" json:"warnings,omitempty"`
 	//
-	// (optional) PaymentSystem denotes is that payment belongs to business
-	// logic of payment server or it was originated by user / third-party
-	// service.
-	System PaymentSystem `protobuf:"varint,5,opt,name=system,enum=crpc.PaymentSystem" json:"system,omitempty"`
+	// ReceiptID is the identifier by which receipt and its status could be
+	// requested with ReceiptByID.
+	ReceiptId string `protobuf:"bytes,5,opt,name=receipt_id,json=receiptId" json:"receipt_id,omitempty"`
+	//
+	// ExpiresAt is the time in milliseconds after which receipt couldn't be
+	// paid, zero if receipt doesn't expire.
+	ExpiresAt int64 `protobuf:"varint,6,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+	//
+	// DescriptionHash is the hex encoded SHA256 hash of the reference,
+	// which is placed in the invoice, empty if reference isn't specified.
+	DescriptionHash string `protobuf:"bytes,7,opt,name=description_hash,json=descriptionHash" json:"description_hash,omitempty"`
 }
 
-func (m *ListPaymentsRequest) Reset()                    { *m = ListPaymentsRequest{} }
-func (m *ListPaymentsRequest) String() string            { return proto.CompactTextString(m) }
-func (*ListPaymentsRequest) ProtoMessage()               {}
-func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (m *CreateReceiptResponse) Reset()                    { *m = CreateReceiptResponse{} }
+func (m *CreateReceiptResponse) String() string            { return proto.CompactTextString(m) }
+func (*CreateReceiptResponse) ProtoMessage()               {}
+func (*CreateReceiptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
-func (m *ListPaymentsRequest) GetStatus() PaymentStatus {
+func (m *CreateReceiptResponse) GetCreationDate() int64 {
 	if m != nil {
-		return m.Status
+		return m.CreationDate
 	}
-	return PaymentStatus_STATUS_NONE
+	return 0
 }
 
-func (m *ListPaymentsRequest) GetDirection() PaymentDirection {
+func (m *CreateReceiptResponse) GetReceipt() string {
 	if m != nil {
-		return m.Direction
+		return m.Receipt
 	}
-	return PaymentDirection_DIRECTION_NONE
+	return ""
 }
 
-func (m *ListPaymentsRequest) GetAsset() Asset {
+func (m *CreateReceiptResponse) GetExpiry() int64 {
 	if m != nil {
-		return m.Asset
+		return m.Expiry
 	}
-	return Asset_ASSET_NONE
+	return 0
 }
 
-func (m *ListPaymentsRequest) GetMedia() Media {
+func (m *CreateReceiptResponse) GetWarnings() []string {
 	if m != nil {
-		return m.Media
+		return m.Warnings
 	}
-	return Media_MEDIA_NONE
+	return nil
 }
 
-func (m *ListPaymentsRequest) GetSystem() PaymentSystem {
+func (m *CreateReceiptResponse) GetReceiptId() string {
 	if m != nil {
-		return m.System
+		return m.ReceiptId
 	}
-	return PaymentSystem_SYSTEM_NONE
+	return ""
 }
 
-type ListPaymentsResponse struct {
-	Payments []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+func (m *CreateReceiptResponse) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
 }
 
-func (m *ListPaymentsResponse) Reset()                    { *m = ListPaymentsResponse{} }
-func (m *ListPaymentsResponse) String() string            { return proto.CompactTextString(m) }
-func (*ListPaymentsResponse) ProtoMessage()               {}
-func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
-
-func (m *ListPaymentsResponse) GetPayments() []*Payment {
+func (m *CreateReceiptResponse) GetDescriptionHash() string {
 	if m != nil {
-		return m.Payments
+		return m.DescriptionHash
 	}
-	return nil
+	return ""
 }
 
-type Payment struct {
-	//
-	// PaymentID it is unique identificator of the payment generated inside
-	// the system.
-	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+type BalanceRequest struct {
 	//
-	// UpdatedAt denotes the time when payment object has been last updated.
-	UpdatedAt int64 `protobuf:"varint,2,opt,name=updated_at,json=updatedAt" json:"updated_at,omitempty"`
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
 	//
-	// Status denotes the stage of the processing the payment.
-	Status PaymentStatus `protobuf:"varint,3,opt,name=status,enum=crpc.PaymentStatus" json:"status,omitempty"`
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+}
+
+func (m *BalanceRequest) Reset()                    { *m = BalanceRequest{} }
+func (m *BalanceRequest) String() string            { return proto.CompactTextString(m) }
+func (*BalanceRequest) ProtoMessage()               {}
+func (*BalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *BalanceRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *BalanceRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+type Balance struct {
 	//
-	// Direction denotes the direction of the payment, whether
-	// payment is going form us to someone else, or form someone else to us.
-	Direction PaymentDirection `protobuf:"varint,4,opt,name=direction,enum=crpc.PaymentDirection" json:"direction,omitempty"`
+	// Available is the number of funds which could be used by this account
+	// to send funds to someone else within the specified media.
+	Available string `protobuf:"bytes,1,opt,name=available" json:"available,omitempty"`
 	//
-	// System denotes is that payment belongs to business logic of
-	// payment server or it was originated by user / third-party service.
-	System PaymentSystem `protobuf:"varint,11,opt,name=system,enum=crpc.PaymentSystem" json:"system,omitempty"`
+	// Pending is the number of funds are in the state of confirmation. In
+	// case of blockchain media it is the transactions which are not
+	// confirmed. In case of lightning media it is funds in pending payment
+	// channels.
+	Pending string `protobuf:"bytes,2,opt,name=pending" json:"pending,omitempty"`
 	//
 	// Asset is an acronim of the crypto currency.
-	Asset Asset `protobuf:"varint,5,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
-	//
-	// Receipt is a string which identifies the receiver of the
-	// payment. It is address in case of the blockchain media,
-	// and lightning network invoice in case lightning media.
-	Receipt string `protobuf:"bytes,6,opt,name=receipt" json:"receipt,omitempty"`
-	// MediaID is identificator of the payment inside the media.
-	// In case of blockchain media payment id is the transaction id,
-	// in case of lightning media it is the payment hash. It is not used as
-	// payment identificator because of the reason that it is not unique.
-	MediaId string `protobuf:"bytes,7,opt,name=media_id,json=mediaId" json:"media_id,omitempty"`
+	Asset Asset `protobuf:"varint,3,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
 	//
 	// Media is a type of technology which is used to transport value of
 	// underlying asset.
-	Media Media `protobuf:"varint,8,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
-	//
-	// Amount is the number of funds which receiver gets at the end.
-	Amount string `protobuf:"bytes,9,opt,name=amount" json:"amount,omitempty"`
+	Media Media `protobuf:"varint,4,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
 	//
-	// MediaFee is the fee which is taken by the blockchain or lightning
-	// network in order to propagate the payment.
-	MediaFee string `protobuf:"bytes,10,opt,name=media_fee,json=mediaFee" json:"media_fee,omitempty"`
+	// Quarantined is the number of funds of the completed incoming payments
+	// which are quarantined by the compliance review, they are not
+	// included in the available funds.
+	Quarantined string `protobuf:"bytes,5,opt,name=quarantined" json:"quarantined,omitempty"`
 }
 
-func (m *Payment) Reset()                    { *m = Payment{} }
-func (m *Payment) String() string            { return proto.CompactTextString(m) }
-func (*Payment) ProtoMessage()               {}
-func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (m *Balance) Reset()                    { *m = Balance{} }
+func (m *Balance) String() string            { return proto.CompactTextString(m) }
+func (*Balance) ProtoMessage()               {}
+func (*Balance) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
-func (m *Payment) GetPaymentId() string {
+func (m *Balance) GetAvailable() string {
 	if m != nil {
-		return m.PaymentId
+		return m.Available
 	}
 	return ""
 }
 
-func (m *Payment) GetUpdatedAt() int64 {
+func (m *Balance) GetPending() string {
 	if m != nil {
-		return m.UpdatedAt
+		return m.Pending
 	}
-	return 0
+	return ""
 }
 
-func (m *Payment) GetStatus() PaymentStatus {
+func (m *Balance) GetAsset() Asset {
 	if m != nil {
-		return m.Status
+		return m.Asset
 	}
-	return PaymentStatus_STATUS_NONE
+	return Asset_ASSET_NONE
 }
 
-func (m *Payment) GetDirection() PaymentDirection {
+func (m *Balance) GetMedia() Media {
 	if m != nil {
-		return m.Direction
+		return m.Media
 	}
-	return PaymentDirection_DIRECTION_NONE
+	return Media_MEDIA_NONE
 }
 
-func (m *Payment) GetSystem() PaymentSystem {
+func (m *Balance) GetQuarantined() string {
 	if m != nil {
-		return m.System
+		return m.Quarantined
 	}
-	return PaymentSystem_SYSTEM_NONE
+	return ""
 }
 
-func (m *Payment) GetAsset() Asset {
+type BalanceHistoryRequest struct {
+	//
+	// (optional) Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// (optional) Media is a type of technology which is used to transport
+	// value of underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// (optional) CreatedFrom is the time in milliseconds from which
+	// snapshots are returned, inclusive.
+	CreatedFrom int64 `protobuf:"varint,3,opt,name=created_from,json=createdFrom" json:"created_from,omitempty"`
+	//
+	// (optional) CreatedTo is the time in milliseconds until which
+	// snapshots are returned, inclusive.
+	CreatedTo int64 `protobuf:"varint,4,opt,name=created_to,json=createdTo" json:"created_to,omitempty"`
+	//
+	// (optional) Limit is the maximum number of returned snapshots, the
+	// latest ones are returned if there are more of them. All matching
+	// snapshots are returned if it is not specified.
+	Limit uint32 `protobuf:"varint,5,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *BalanceHistoryRequest) Reset()                    { *m = BalanceHistoryRequest{} }
+func (m *BalanceHistoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*BalanceHistoryRequest) ProtoMessage()               {}
+func (*BalanceHistoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *BalanceHistoryRequest) GetAsset() Asset {
 	if m != nil {
 		return m.Asset
 	}
 	return Asset_ASSET_NONE
 }
 
-func (m *Payment) GetReceipt() string {
+func (m *BalanceHistoryRequest) GetMedia() Media {
 	if m != nil {
-		return m.Receipt
+		return m.Media
 	}
-	return ""
+	return Media_MEDIA_NONE
 }
 
-func (m *Payment) GetMediaId() string {
+func (m *BalanceHistoryRequest) GetCreatedFrom() int64 {
 	if m != nil {
-		return m.MediaId
+		return m.CreatedFrom
 	}
-	return ""
+	return 0
 }
 
-func (m *Payment) GetMedia() Media {
+func (m *BalanceHistoryRequest) GetCreatedTo() int64 {
 	if m != nil {
-		return m.Media
+		return m.CreatedTo
 	}
-	return Media_MEDIA_NONE
+	return 0
 }
 
-func (m *Payment) GetAmount() string {
+func (m *BalanceHistoryRequest) GetLimit() uint32 {
 	if m != nil {
-		return m.Amount
+		return m.Limit
 	}
-	return ""
+	return 0
 }
 
-func (m *Payment) GetMediaFee() string {
-	if m != nil {
-		return m.MediaFee
+type BalanceSnapshot struct {
+	//
+	// CreatedAt is the time of the snapshot in milliseconds.
+	CreatedAt int64 `protobuf:"varint,1,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	//
+	// Balance is the balance of the asset in the media at the time of the
+	// snapshot.
+	Balance *Balance `protobuf:"bytes,2,opt,name=balance" json:"balance,omitempty"`
+}
+
+func (m *BalanceSnapshot) Reset()                    { *m = BalanceSnapshot{} }
+func (m *BalanceSnapshot) String() string            { return proto.CompactTextString(m) }
+func (*BalanceSnapshot) ProtoMessage()               {}
+func (*BalanceSnapshot) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *BalanceSnapshot) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
 	}
-	return ""
+	return 0
 }
 
-func init() {
-	proto.RegisterType((*EmptyRequest)(nil), "crpc.EmptyRequest")
-	proto.RegisterType((*EmptyResponse)(nil), "crpc.EmptyResponse")
-	proto.RegisterType((*CreateReceiptRequest)(nil), "crpc.CreateReceiptRequest")
-	proto.RegisterType((*CreateReceiptResponse)(nil), "crpc.CreateReceiptResponse")
-	proto.RegisterType((*BalanceRequest)(nil), "crpc.BalanceRequest")
-	proto.RegisterType((*Balance)(nil), "crpc.Balance")
-	proto.RegisterType((*ValidateReceiptResponse)(nil), "crpc.ValidateReceiptResponse")
-	proto.RegisterType((*Invoice)(nil), "crpc.Invoice")
-	proto.RegisterType((*BalanceResponse)(nil), "crpc.BalanceResponse")
-	proto.RegisterType((*ValidateReceiptRequest)(nil), "crpc.ValidateReceiptRequest")
-	proto.RegisterType((*EstimateFeeRequest)(nil), "crpc.EstimateFeeRequest")
-	proto.RegisterType((*EstimateFeeResponse)(nil), "crpc.EstimateFeeResponse")
-	proto.RegisterType((*SendPaymentRequest)(nil), "crpc.SendPaymentRequest")
-	proto.RegisterType((*PaymentByIDRequest)(nil), "crpc.PaymentByIDRequest")
-	proto.RegisterType((*PaymentsByReceiptRequest)(nil), "crpc.PaymentsByReceiptRequest")
-	proto.RegisterType((*PaymentsByReceiptResponse)(nil), "crpc.PaymentsByReceiptResponse")
-	proto.RegisterType((*ListPaymentsRequest)(nil), "crpc.ListPaymentsRequest")
-	proto.RegisterType((*ListPaymentsResponse)(nil), "crpc.ListPaymentsResponse")
-	proto.RegisterType((*Payment)(nil), "crpc.Payment")
-	proto.RegisterEnum("crpc.Asset", Asset_name, Asset_value)
-	proto.RegisterEnum("crpc.Media", Media_name, Media_value)
-	proto.RegisterEnum("crpc.PaymentStatus", PaymentStatus_name, PaymentStatus_value)
-	proto.RegisterEnum("crpc.PaymentDirection", PaymentDirection_name, PaymentDirection_value)
-	proto.RegisterEnum("crpc.PaymentSystem", PaymentSystem_name, PaymentSystem_value)
+func (m *BalanceSnapshot) GetBalance() *Balance {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+type BalanceHistoryResponse struct {
+	Snapshots []*BalanceSnapshot `protobuf:"bytes,1,rep,name=snapshots" json:"snapshots,omitempty"`
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *BalanceHistoryResponse) Reset()                    { *m = BalanceHistoryResponse{} }
+func (m *BalanceHistoryResponse) String() string            { return proto.CompactTextString(m) }
+func (*BalanceHistoryResponse) ProtoMessage()               {}
+func (*BalanceHistoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
-// Client API for PayServer service
+func (m *BalanceHistoryResponse) GetSnapshots() []*BalanceSnapshot {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
 
-type PayServerClient interface {
-	//
-	// CreateReceipt is used to create blockchain deposit address in
-	// case of blockchain media, and lightning network invoice in
-	// case of the lightning media, which will be used to receive money from
-	// external entity.
-	CreateReceipt(ctx context.Context, in *CreateReceiptRequest, opts ...grpc.CallOption) (*CreateReceiptResponse, error)
+type SubscribeBalanceRequest struct {
 	//
-	// ValidateReceipt is used to validate receipt for given asset and media.
-	ValidateReceipt(ctx context.Context, in *ValidateReceiptRequest, opts ...grpc.CallOption) (*ValidateReceiptResponse, error)
+	// (optional) Asset is an acronim of the crypto currency, by default
+	// balances of all assets are sent.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
 	//
-	// Balance is used to determine balance.
-	Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
+	// (optional) Media is a type of technology which is used to transport
+	// value of underlying asset, by default balances of all media are sent.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
 	//
-	// EstimateFee estimates the fee of the payment.
-	EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error)
+	// (optional) MinDelta is the minimal change of the available or pending
+	// funds since the previously sent balance, on which balance is sent
+	// again. By default every change is sent.
+	MinDelta string `protobuf:"bytes,3,opt,name=min_delta,json=minDelta" json:"min_delta,omitempty"`
+}
+
+func (m *SubscribeBalanceRequest) Reset()                    { *m = SubscribeBalanceRequest{} }
+func (m *SubscribeBalanceRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeBalanceRequest) ProtoMessage()               {}
+func (*SubscribeBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *SubscribeBalanceRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *SubscribeBalanceRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *SubscribeBalanceRequest) GetMinDelta() string {
+	if m != nil {
+		return m.MinDelta
+	}
+	return ""
+}
+
+type BalanceUpdate struct {
 	//
-	// SendPayment sends payment to the given recipient,
-	// ensures in the validity of the receipt as well as the
-	// account has enough money for doing that.
-	SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*Payment, error)
+	// UpdatedAt is the time when balance has been fetched in milliseconds.
+	UpdatedAt int64 `protobuf:"varint,1,opt,name=updated_at,json=updatedAt" json:"updated_at,omitempty"`
 	//
-	// PaymentByID is used to fetch the information about payment, by the
-	// given system payment id.
-	PaymentByID(ctx context.Context, in *PaymentByIDRequest, opts ...grpc.CallOption) (*Payment, error)
+	// Balance is the current balance of the asset within the media.
+	Balance *Balance `protobuf:"bytes,2,opt,name=balance" json:"balance,omitempty"`
 	//
-	// PaymentsByReceipt is used to fetch the information about payment, by the
-	// given receipt.
-	PaymentsByReceipt(ctx context.Context, in *PaymentsByReceiptRequest, opts ...grpc.CallOption) (*PaymentsByReceiptResponse, error)
+	// AvailableDelta is the change of the available funds since the
+	// previously sent balance, first sent balance has zero delta.
+	AvailableDelta string `protobuf:"bytes,3,opt,name=available_delta,json=availableDelta" json:"available_delta,omitempty"`
 	//
-	// ListPayments returnes list of payment which were registered by the
-	// system.
-	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	// PendingDelta is the change of the pending funds since the previously
+	// sent balance, first sent balance has zero delta.
+	PendingDelta string `protobuf:"bytes,4,opt,name=pending_delta,json=pendingDelta" json:"pending_delta,omitempty"`
 }
 
-type payServerClient struct {
-	cc *grpc.ClientConn
+func (m *BalanceUpdate) Reset()                    { *m = BalanceUpdate{} }
+func (m *BalanceUpdate) String() string            { return proto.CompactTextString(m) }
+func (*BalanceUpdate) ProtoMessage()               {}
+func (*BalanceUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *BalanceUpdate) GetUpdatedAt() int64 {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return 0
 }
 
-func NewPayServerClient(cc *grpc.ClientConn) PayServerClient {
-	return &payServerClient{cc}
+func (m *BalanceUpdate) GetBalance() *Balance {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
 }
 
-func (c *payServerClient) CreateReceipt(ctx context.Context, in *CreateReceiptRequest, opts ...grpc.CallOption) (*CreateReceiptResponse, error) {
-	out := new(CreateReceiptResponse)
-	err := grpc.Invoke(ctx, "/crpc.PayServer/CreateReceipt", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *BalanceUpdate) GetAvailableDelta() string {
+	if m != nil {
+		return m.AvailableDelta
 	}
-	return out, nil
+	return ""
 }
 
-func (c *payServerClient) ValidateReceipt(ctx context.Context, in *ValidateReceiptRequest, opts ...grpc.CallOption) (*ValidateReceiptResponse, error) {
-	out := new(ValidateReceiptResponse)
-	err := grpc.Invoke(ctx, "/crpc.PayServer/ValidateReceipt", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *BalanceUpdate) GetPendingDelta() string {
+	if m != nil {
+		return m.PendingDelta
 	}
-	return out, nil
+	return ""
 }
 
-func (c *payServerClient) Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
-	out := new(BalanceResponse)
-	err := grpc.Invoke(ctx, "/crpc.PayServer/Balance", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+type ValidateReceiptResponse struct {
+	// Types that are valid to be assigned to Data:
+	//	*ValidateReceiptResponse_Invoice
+	Data isValidateReceiptResponse_Data `protobuf_oneof:"data"`
+	//
+	// Receipt is the normalized canonical form of the given receipt:
+	// checksummed EIP-55 address for ethereum, lowercase for bech32 addresses
+	// and lightning network invoices, CashAddr with prefix for bitcoin cash.
+	// This form is used to store receipt in the payments.
+	Receipt string `protobuf:"bytes,2,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// Type is the detected type of the receipt, e.g. p2pkh, p2sh, p2wpkh,
+	// p2wsh for utxo based blockchains, account for ethereum, and invoice
+	// for lightning network.
+	Type string `protobuf:"bytes,3,opt,name=type" json:"type,omitempty"`
+	//
+	// Net is the network to which receipt belongs.
+	Net string `protobuf:"bytes,4,opt,name=net" json:"net,omitempty"`
+	//
+	// Warnings is the list of non-fatal advisories about the receipt, e.g.
+	// destination address which has been already used many times.
+	Warnings []string `protobuf:"bytes,5,rep,name=warnings" json:"warnings,omitempty"`
+}
+
+func (m *ValidateReceiptResponse) Reset()                    { *m = ValidateReceiptResponse{} }
+func (m *ValidateReceiptResponse) String() string            { return proto.CompactTextString(m) }
+func (*ValidateReceiptResponse) ProtoMessage()               {}
+func (*ValidateReceiptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+type isValidateReceiptResponse_Data interface{ isValidateReceiptResponse_Data() }
+
+type ValidateReceiptResponse_Invoice struct {
+	Invoice *Invoice `protobuf:"bytes,1,opt,name=invoice,oneof"`
+}
+
+func (*ValidateReceiptResponse_Invoice) isValidateReceiptResponse_Data() {}
+
+func (m *ValidateReceiptResponse) GetData() isValidateReceiptResponse_Data {
+	if m != nil {
+		return m.Data
 	}
-	return out, nil
+	return nil
 }
 
-func (c *payServerClient) EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error) {
-	out := new(EstimateFeeResponse)
-	err := grpc.Invoke(ctx, "/crpc.PayServer/EstimateFee", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ValidateReceiptResponse) GetInvoice() *Invoice {
+	if x, ok := m.GetData().(*ValidateReceiptResponse_Invoice); ok {
+		return x.Invoice
 	}
-	return out, nil
+	return nil
 }
 
-func (c *payServerClient) SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*Payment, error) {
-	out := new(Payment)
-	err := grpc.Invoke(ctx, "/crpc.PayServer/SendPayment", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ValidateReceiptResponse) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
 	}
-	return out, nil
+	return ""
 }
 
-func (c *payServerClient) PaymentByID(ctx context.Context, in *PaymentByIDRequest, opts ...grpc.CallOption) (*Payment, error) {
-	out := new(Payment)
-	err := grpc.Invoke(ctx, "/crpc.PayServer/PaymentByID", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ValidateReceiptResponse) GetType() string {
+	if m != nil {
+		return m.Type
 	}
-	return out, nil
+	return ""
 }
 
-func (c *payServerClient) PaymentsByReceipt(ctx context.Context, in *PaymentsByReceiptRequest, opts ...grpc.CallOption) (*PaymentsByReceiptResponse, error) {
-	out := new(PaymentsByReceiptResponse)
-	err := grpc.Invoke(ctx, "/crpc.PayServer/PaymentsByReceipt", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ValidateReceiptResponse) GetNet() string {
+	if m != nil {
+		return m.Net
 	}
-	return out, nil
+	return ""
 }
 
-func (c *payServerClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
-	out := new(ListPaymentsResponse)
-	err := grpc.Invoke(ctx, "/crpc.PayServer/ListPayments", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ValidateReceiptResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
 	}
-	return out, nil
+	return nil
 }
 
-// Server API for PayServer service
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*ValidateReceiptResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _ValidateReceiptResponse_OneofMarshaler, _ValidateReceiptResponse_OneofUnmarshaler, _ValidateReceiptResponse_OneofSizer, []interface{}{
+		(*ValidateReceiptResponse_Invoice)(nil),
+	}
+}
 
-type PayServerServer interface {
-	//
-	// CreateReceipt is used to create blockchain deposit address in
-	// case of blockchain media, and lightning network invoice in
-	// case of the lightning media, which will be used to receive money from
-	// external entity.
-	CreateReceipt(context.Context, *CreateReceiptRequest) (*CreateReceiptResponse, error)
-	//
-	// ValidateReceipt is used to validate receipt for given asset and media.
-	ValidateReceipt(context.Context, *ValidateReceiptRequest) (*ValidateReceiptResponse, error)
-	//
-	// Balance is used to determine balance.
-	Balance(context.Context, *BalanceRequest) (*BalanceResponse, error)
-	//
-	// EstimateFee estimates the fee of the payment.
-	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
-	//
-	// SendPayment sends payment to the given recipient,
-	// ensures in the validity of the receipt as well as the
-	// account has enough money for doing that.
-	SendPayment(context.Context, *SendPaymentRequest) (*Payment, error)
-	//
-	// PaymentByID is used to fetch the information about payment, by the
-	// given system payment id.
-	PaymentByID(context.Context, *PaymentByIDRequest) (*Payment, error)
-	//
-	// PaymentsByReceipt is used to fetch the information about payment, by the
-	// given receipt.
-	PaymentsByReceipt(context.Context, *PaymentsByReceiptRequest) (*PaymentsByReceiptResponse, error)
-	//
-	// ListPayments returnes list of payment which were registered by the
-	// system.
-	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
+func _ValidateReceiptResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*ValidateReceiptResponse)
+	// data
+	switch x := m.Data.(type) {
+	case *ValidateReceiptResponse_Invoice:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Invoice); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("ValidateReceiptResponse.Data has unexpected type %T", x)
+	}
+	return nil
 }
 
-func RegisterPayServerServer(s *grpc.Server, srv PayServerServer) {
-	s.RegisterService(&_PayServer_serviceDesc, srv)
+func _ValidateReceiptResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*ValidateReceiptResponse)
+	switch tag {
+	case 1: // data.invoice
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Invoice)
+		err := b.DecodeMessage(msg)
+		m.Data = &ValidateReceiptResponse_Invoice{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _ValidateReceiptResponse_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*ValidateReceiptResponse)
+	// data
+	switch x := m.Data.(type) {
+	case *ValidateReceiptResponse_Invoice:
+		s := proto.Size(x.Invoice)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type Invoice struct {
+	//
+	// An optional memo to attach along with the invoice. Used for record keeping
+	// purposes for the invoice's creator, and will also be set in the
+	// description field of the encoded payment request if the
+	// description_hash field is not being used.
+	Memo string `protobuf:"bytes,1,opt,name=memo" json:"memo,omitempty"`
+	//
+	// The value of this invoice in bitcoins.
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+	//
+	// When this invoice was created.
+	CreationDate int64 `protobuf:"varint,3,opt,name=creation_date,json=creationDate" json:"creation_date,omitempty"`
+	//
+	// Invoice expiry time in seconds. Default is 3600 (1 hour).
+	Expiry int64 `protobuf:"varint,4,opt,name=expiry" json:"expiry,omitempty"`
+	//
+	// Fallback on-chain address in case of lightning network payment fail.
+	FallbackAddr string `protobuf:"bytes,5,opt,name=fallback_addr,json=fallbackAddr" json:"fallback_addr,omitempty"`
+	//
+	// Lightning Network public key of receiving node.
+	Destination string `protobuf:"bytes,6,opt,name=destination" json:"destination,omitempty"`
+}
+
+func (m *Invoice) Reset()                    { *m = Invoice{} }
+func (m *Invoice) String() string            { return proto.CompactTextString(m) }
+func (*Invoice) ProtoMessage()               {}
+func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *Invoice) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *Invoice) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *Invoice) GetCreationDate() int64 {
+	if m != nil {
+		return m.CreationDate
+	}
+	return 0
+}
+
+func (m *Invoice) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *Invoice) GetFallbackAddr() string {
+	if m != nil {
+		return m.FallbackAddr
+	}
+	return ""
+}
+
+func (m *Invoice) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+type BalanceResponse struct {
+	Balances []*Balance `protobuf:"bytes,1,rep,name=balances" json:"balances,omitempty"`
+}
+
+func (m *BalanceResponse) Reset()                    { *m = BalanceResponse{} }
+func (m *BalanceResponse) String() string            { return proto.CompactTextString(m) }
+func (*BalanceResponse) ProtoMessage()               {}
+func (*BalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *BalanceResponse) GetBalances() []*Balance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+type ValidateReceiptRequest struct {
+	//
+	// Receipt is the blockchain address in case of blockchain media and
+	// lightning network invoice in case of lightning media.
+	Receipt string `protobuf:"bytes,1,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,3,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// (optional) Amount is the amount which should be received on this
+	// receipt.
+	Amount string `protobuf:"bytes,4,opt,name=amount" json:"amount,omitempty"`
+}
+
+func (m *ValidateReceiptRequest) Reset()                    { *m = ValidateReceiptRequest{} }
+func (m *ValidateReceiptRequest) String() string            { return proto.CompactTextString(m) }
+func (*ValidateReceiptRequest) ProtoMessage()               {}
+func (*ValidateReceiptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *ValidateReceiptRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *ValidateReceiptRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *ValidateReceiptRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *ValidateReceiptRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+type EstimateFeeRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// (optional) Amount is number of money which should be given to the
+	// another entity.
+	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	//
+	// (optional) Receipt represent either blockchains address or lightning
+	// network invoice. If receipt is specified the number are more accurate
+	// for lightning network payment.
+	Receipt string `protobuf:"bytes,4,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// (optional) ConfTarget is the number of blocks within which blockchain
+	// payment should be confirmed, the lower target the higher fee. If not
+	// specified, fee is estimated for the default target of the connector.
+	// Supported only by the bitcoin based assets.
+	ConfTarget uint32 `protobuf:"varint,5,opt,name=conf_target,json=confTarget" json:"conf_target,omitempty"`
+	//
+	// (optional) AllTargets denotes that fee should be estimated for the
+	// fast, normal and economy confirmation speeds as well.
+	AllTargets bool `protobuf:"varint,6,opt,name=all_targets,json=allTargets" json:"all_targets,omitempty"`
+}
+
+func (m *EstimateFeeRequest) Reset()                    { *m = EstimateFeeRequest{} }
+func (m *EstimateFeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*EstimateFeeRequest) ProtoMessage()               {}
+func (*EstimateFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *EstimateFeeRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *EstimateFeeRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *EstimateFeeRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *EstimateFeeRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *EstimateFeeRequest) GetConfTarget() uint32 {
+	if m != nil {
+		return m.ConfTarget
+	}
+	return 0
+}
+
+func (m *EstimateFeeRequest) GetAllTargets() bool {
+	if m != nil {
+		return m.AllTargets
+	}
+	return false
+}
+
+type FeeTarget struct {
+	//
+	// Speed is the name of the confirmation speed, e.g. "fast", "normal"
+	// or "economy".
+	Speed string `protobuf:"bytes,1,opt,name=speed" json:"speed,omitempty"`
+	//
+	// ConfTarget is the number of blocks within which payment is expected
+	// to be confirmed.
+	ConfTarget uint32 `protobuf:"varint,2,opt,name=conf_target,json=confTarget" json:"conf_target,omitempty"`
+	//
+	// MediaFee is the fee of the payment which is confirmed within the
+	// target.
+	MediaFee string `protobuf:"bytes,3,opt,name=media_fee,json=mediaFee" json:"media_fee,omitempty"`
+}
+
+func (m *FeeTarget) Reset()                    { *m = FeeTarget{} }
+func (m *FeeTarget) String() string            { return proto.CompactTextString(m) }
+func (*FeeTarget) ProtoMessage()               {}
+func (*FeeTarget) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *FeeTarget) GetSpeed() string {
+	if m != nil {
+		return m.Speed
+	}
+	return ""
+}
+
+func (m *FeeTarget) GetConfTarget() uint32 {
+	if m != nil {
+		return m.ConfTarget
+	}
+	return 0
+}
+
+func (m *FeeTarget) GetMediaFee() string {
+	if m != nil {
+		return m.MediaFee
+	}
+	return ""
+}
+
+type EstimateFeeResponse struct {
+	//
+	// MediaFee is the fee which is taken by the blockchain or lightning
+	// network in order to propagate the payment.
+	MediaFee string `protobuf:"bytes,1,opt,name=media_fee,json=mediaFee" json:"media_fee,omitempty"`
+	//
+	// ConfTarget is the number of blocks for which media fee has been
+	// estimated, zero if the default target of the connector has been used.
+	ConfTarget uint32 `protobuf:"varint,2,opt,name=conf_target,json=confTarget" json:"conf_target,omitempty"`
+	//
+	// Targets are the fees of the standard confirmation speeds, returned
+	// only if all targets are requested and connector supports them.
+	Targets []*FeeTarget `protobuf:"bytes,3,rep,name=targets" json:"targets,omitempty"`
+}
+
+func (m *EstimateFeeResponse) Reset()                    { *m = EstimateFeeResponse{} }
+func (m *EstimateFeeResponse) String() string            { return proto.CompactTextString(m) }
+func (*EstimateFeeResponse) ProtoMessage()               {}
+func (*EstimateFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *EstimateFeeResponse) GetMediaFee() string {
+	if m != nil {
+		return m.MediaFee
+	}
+	return ""
+}
+
+func (m *EstimateFeeResponse) GetConfTarget() uint32 {
+	if m != nil {
+		return m.ConfTarget
+	}
+	return 0
+}
+
+func (m *EstimateFeeResponse) GetTargets() []*FeeTarget {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+type SendPaymentRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Amount is number of money which should be given to the another entity.
+	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Receipt represent either blockchains address or lightning
+	// network invoice, which we should use determine payment receiver.
+	Receipt string `protobuf:"bytes,4,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// (optional) Memo is the message which will be attached to the
+	// transaction, if it is supported by the asset. For bitcoin-like assets
+	// it is put in OP_RETURN output, and it is limited by 80 bytes. For
+	// ethereum it is put in transaction data field. Not supported for
+	// lightning media.
+	Memo string `protobuf:"bytes,5,opt,name=memo" json:"memo,omitempty"`
+	//
+	// (optional) Payee is the name of the registered payee preset. If it is
+	// specified asset, media and receipt are taken from the preset, and
+	// amount is used only if it is not specified in the request.
+	Payee string `protobuf:"bytes,6,opt,name=payee" json:"payee,omitempty"`
+	//
+	// (optional) QuoteID is the id of the quote returned by QuotePayment. If
+	// it is specified asset, media, receipt and amount are taken from the
+	// quote, and if they are also specified in the request they should
+	// match it. Quote could be used only with the API key by which it was
+	// requested, and payment is rejected if the current fee estimation
+	// exceeds the fee of the quote, in this case new quote should be
+	// requested.
+	QuoteId string `protobuf:"bytes,7,opt,name=quote_id,json=quoteId" json:"quote_id,omitempty"`
+	//
+	// (optional) Include is the list of heavy payment fields which are
+	// returned only if they are requested, e.g. warnings.
+	Include []PaymentInclude `protobuf:"varint,8,rep,packed,name=include,enum=crpc.PaymentInclude" json:"include,omitempty"`
+	//
+	// (optional) Account is the identifier of the account, e.g. internal
+	// product, to which payment belongs, so that balances of the several
+	// products served by the same server are kept separately.
+	Account string `protobuf:"bytes,9,opt,name=account" json:"account,omitempty"`
+	//
+	// (optional) Metadata is the set of the labels, e.g. order id or
+	// customer id, which are attached to the payment.
+	Metadata map[string]string `protobuf:"bytes,10,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	//
+	// (optional) DryRun, if set, makes the payment to be validated, and its
+	// inputs to be selected and fee to be calculated, but nothing is
+	// broadcasted or saved. The would-be payment is returned without the
+	// payment id, quote isn't consumed and pre-send hook isn't called.
+	DryRun bool `protobuf:"varint,11,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+}
+
+func (m *SendPaymentRequest) Reset()                    { *m = SendPaymentRequest{} }
+func (m *SendPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendPaymentRequest) ProtoMessage()               {}
+func (*SendPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *SendPaymentRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *SendPaymentRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *SendPaymentRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *SendPaymentRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *SendPaymentRequest) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *SendPaymentRequest) GetPayee() string {
+	if m != nil {
+		return m.Payee
+	}
+	return ""
+}
+
+func (m *SendPaymentRequest) GetQuoteId() string {
+	if m != nil {
+		return m.QuoteId
+	}
+	return ""
+}
+
+func (m *SendPaymentRequest) GetInclude() []PaymentInclude {
+	if m != nil {
+		return m.Include
+	}
+	return nil
+}
+
+func (m *SendPaymentRequest) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *SendPaymentRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *SendPaymentRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type PaymentOutput struct {
+	//
+	// Receipt is the blockchain address of the recipient.
+	Receipt string `protobuf:"bytes,1,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// Amount is number of money which should be given to the recipient.
+	Amount string `protobuf:"bytes,2,opt,name=amount" json:"amount,omitempty"`
+}
+
+func (m *PaymentOutput) Reset()                    { *m = PaymentOutput{} }
+func (m *PaymentOutput) String() string            { return proto.CompactTextString(m) }
+func (*PaymentOutput) ProtoMessage()               {}
+func (*PaymentOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *PaymentOutput) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *PaymentOutput) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+type SendPaymentsRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Outputs is the list of recipients and amounts, addresses shouldn't
+	// be repeated.
+	Outputs []*PaymentOutput `protobuf:"bytes,2,rep,name=outputs" json:"outputs,omitempty"`
+	//
+	// (optional) Include is the list of heavy payment fields which are
+	// returned only if they are requested, e.g. warnings.
+	Include []PaymentInclude `protobuf:"varint,3,rep,packed,name=include,enum=crpc.PaymentInclude" json:"include,omitempty"`
+	//
+	// (optional) Account is the identifier of the account, e.g. internal
+	// product, to which payments belong, so that balances of the several
+	// products served by the same server are kept separately.
+	Account string `protobuf:"bytes,4,opt,name=account" json:"account,omitempty"`
+	//
+	// (optional) Metadata is the set of the labels, e.g. order id or
+	// customer id, which are attached to every payment.
+	Metadata map[string]string `protobuf:"bytes,5,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *SendPaymentsRequest) Reset()                    { *m = SendPaymentsRequest{} }
+func (m *SendPaymentsRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendPaymentsRequest) ProtoMessage()               {}
+func (*SendPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *SendPaymentsRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *SendPaymentsRequest) GetOutputs() []*PaymentOutput {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *SendPaymentsRequest) GetInclude() []PaymentInclude {
+	if m != nil {
+		return m.Include
+	}
+	return nil
+}
+
+func (m *SendPaymentsRequest) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *SendPaymentsRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type SendPaymentsResponse struct {
+	//
+	// Payments is the list of payments in the same order as outputs in the
+	// request.
+	Payments []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+}
+
+func (m *SendPaymentsResponse) Reset()                    { *m = SendPaymentsResponse{} }
+func (m *SendPaymentsResponse) String() string            { return proto.CompactTextString(m) }
+func (*SendPaymentsResponse) ProtoMessage()               {}
+func (*SendPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *SendPaymentsResponse) GetPayments() []*Payment {
+	if m != nil {
+		return m.Payments
+	}
+	return nil
+}
+
+type QuoteReceiptRequest struct {
+	//
+	// Currency is the code of the fiat currency, e.g. USD.
+	Currency string `protobuf:"bytes,1,opt,name=currency" json:"currency,omitempty"`
+	//
+	// Price is the amount in the fiat currency which should be received.
+	Price string `protobuf:"bytes,2,opt,name=price" json:"price,omitempty"`
+}
+
+func (m *QuoteReceiptRequest) Reset()                    { *m = QuoteReceiptRequest{} }
+func (m *QuoteReceiptRequest) String() string            { return proto.CompactTextString(m) }
+func (*QuoteReceiptRequest) ProtoMessage()               {}
+func (*QuoteReceiptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *QuoteReceiptRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *QuoteReceiptRequest) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+type ReceiptQuote struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Amount is the amount of the asset which covers the price, it is
+	// rounded up to the smallest unit, and could be used in CreateReceipt.
+	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Rate is the configured price of the one unit of the asset in the
+	// fiat currency.
+	Rate string `protobuf:"bytes,4,opt,name=rate" json:"rate,omitempty"`
+	//
+	// MediaFee is the projected fee of the blockchain network for the
+	// payment of the amount. It is empty for the lightning network, as fee
+	// depends on the route of the payer, and if it couldn't be estimated.
+	MediaFee string `protobuf:"bytes,5,opt,name=media_fee,json=mediaFee" json:"media_fee,omitempty"`
+}
+
+func (m *ReceiptQuote) Reset()                    { *m = ReceiptQuote{} }
+func (m *ReceiptQuote) String() string            { return proto.CompactTextString(m) }
+func (*ReceiptQuote) ProtoMessage()               {}
+func (*ReceiptQuote) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *ReceiptQuote) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *ReceiptQuote) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *ReceiptQuote) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *ReceiptQuote) GetRate() string {
+	if m != nil {
+		return m.Rate
+	}
+	return ""
+}
+
+func (m *ReceiptQuote) GetMediaFee() string {
+	if m != nil {
+		return m.MediaFee
+	}
+	return ""
+}
+
+type QuoteReceiptResponse struct {
+	//
+	// Quotes is the list of amounts ordered by the asset and media.
+	Quotes []*ReceiptQuote `protobuf:"bytes,1,rep,name=quotes" json:"quotes,omitempty"`
+}
+
+func (m *QuoteReceiptResponse) Reset()                    { *m = QuoteReceiptResponse{} }
+func (m *QuoteReceiptResponse) String() string            { return proto.CompactTextString(m) }
+func (*QuoteReceiptResponse) ProtoMessage()               {}
+func (*QuoteReceiptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *QuoteReceiptResponse) GetQuotes() []*ReceiptQuote {
+	if m != nil {
+		return m.Quotes
+	}
+	return nil
+}
+
+type QuotePaymentRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Amount is the net number of money which recipient should receive.
+	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Receipt represent either blockchains address or lightning
+	// network invoice, which we should use determine payment receiver.
+	Receipt string `protobuf:"bytes,4,opt,name=receipt" json:"receipt,omitempty"`
+}
+
+func (m *QuotePaymentRequest) Reset()                    { *m = QuotePaymentRequest{} }
+func (m *QuotePaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*QuotePaymentRequest) ProtoMessage()               {}
+func (*QuotePaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *QuotePaymentRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *QuotePaymentRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *QuotePaymentRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *QuotePaymentRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+type PaymentQuote struct {
+	//
+	// QuoteID is the id of the quote which should be passed in SendPayment.
+	QuoteId string `protobuf:"bytes,1,opt,name=quote_id,json=quoteId" json:"quote_id,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,3,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Receipt represent either blockchains address or lightning
+	// network invoice, which we should use determine payment receiver.
+	Receipt string `protobuf:"bytes,4,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// Amount is the net number of money which recipient receives.
+	Amount string `protobuf:"bytes,5,opt,name=amount" json:"amount,omitempty"`
+	//
+	// MediaFee is the projected fee which is taken by the blockchain or
+	// lightning network in order to propagate the payment.
+	MediaFee string `protobuf:"bytes,6,opt,name=media_fee,json=mediaFee" json:"media_fee,omitempty"`
+	//
+	// GrossAmount is the amount with the projected fee, which is deducted
+	// from the wallet.
+	GrossAmount string `protobuf:"bytes,7,opt,name=gross_amount,json=grossAmount" json:"gross_amount,omitempty"`
+	//
+	// ExpiresAt is the time in milliseconds after which quote couldn't be
+	// used.
+	ExpiresAt int64 `protobuf:"varint,8,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+}
+
+func (m *PaymentQuote) Reset()                    { *m = PaymentQuote{} }
+func (m *PaymentQuote) String() string            { return proto.CompactTextString(m) }
+func (*PaymentQuote) ProtoMessage()               {}
+func (*PaymentQuote) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *PaymentQuote) GetQuoteId() string {
+	if m != nil {
+		return m.QuoteId
+	}
+	return ""
+}
+
+func (m *PaymentQuote) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *PaymentQuote) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *PaymentQuote) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *PaymentQuote) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *PaymentQuote) GetMediaFee() string {
+	if m != nil {
+		return m.MediaFee
+	}
+	return ""
+}
+
+func (m *PaymentQuote) GetGrossAmount() string {
+	if m != nil {
+		return m.GrossAmount
+	}
+	return ""
+}
+
+func (m *PaymentQuote) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+type SendTimeLockedPaymentRequest struct {
+	//
+	// Asset is an acronim of the crypto currency, only BTC and LTC are
+	// supported.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Amount is number of money which should be locked.
+	Amount string `protobuf:"bytes,2,opt,name=amount" json:"amount,omitempty"`
+	//
+	// RecipientPubkey is the hex encoded compressed public key of the
+	// recipient, by which funds could be spent after the lock.
+	RecipientPubkey string `protobuf:"bytes,3,opt,name=recipient_pubkey,json=recipientPubkey" json:"recipient_pubkey,omitempty"`
+	//
+	// LockHeight is the block height after which funds could be spent.
+	// Either lock height or lock time should be specified.
+	LockHeight uint32 `protobuf:"varint,4,opt,name=lock_height,json=lockHeight" json:"lock_height,omitempty"`
+	//
+	// LockTime is the unix time in seconds after which funds could be
+	// spent. Either lock height or lock time should be specified.
+	LockTime int64 `protobuf:"varint,5,opt,name=lock_time,json=lockTime" json:"lock_time,omitempty"`
+}
+
+func (m *SendTimeLockedPaymentRequest) Reset()                    { *m = SendTimeLockedPaymentRequest{} }
+func (m *SendTimeLockedPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendTimeLockedPaymentRequest) ProtoMessage()               {}
+func (*SendTimeLockedPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *SendTimeLockedPaymentRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *SendTimeLockedPaymentRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *SendTimeLockedPaymentRequest) GetRecipientPubkey() string {
+	if m != nil {
+		return m.RecipientPubkey
+	}
+	return ""
+}
+
+func (m *SendTimeLockedPaymentRequest) GetLockHeight() uint32 {
+	if m != nil {
+		return m.LockHeight
+	}
+	return 0
+}
+
+func (m *SendTimeLockedPaymentRequest) GetLockTime() int64 {
+	if m != nil {
+		return m.LockTime
+	}
+	return 0
+}
+
+type TimeLock struct {
+	//
+	// PaymentID is the id of the outgoing payment on the script address.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Address is the pay-to-witness-script-hash address of the script.
+	Address string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+	//
+	// RedeemScript is the hex encoded script, which should be revealed by
+	// the recipient in order to spend the funds.
+	RedeemScript string `protobuf:"bytes,4,opt,name=redeem_script,json=redeemScript" json:"redeem_script,omitempty"`
+	//
+	// RecipientPubkey is the hex encoded public key of the recipient.
+	RecipientPubkey string `protobuf:"bytes,5,opt,name=recipient_pubkey,json=recipientPubkey" json:"recipient_pubkey,omitempty"`
+	//
+	// LockHeight is the block height after which funds could be spent,
+	// zero if funds are locked by time.
+	LockHeight uint32 `protobuf:"varint,6,opt,name=lock_height,json=lockHeight" json:"lock_height,omitempty"`
+	//
+	// LockTime is the unix time in seconds after which funds could be
+	// spent, zero if funds are locked by height.
+	LockTime int64 `protobuf:"varint,7,opt,name=lock_time,json=lockTime" json:"lock_time,omitempty"`
+	//
+	// CreatedAt is the time of the payment in milliseconds.
+	CreatedAt int64 `protobuf:"varint,8,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	//
+	// Unlocked denotes that funds could be spent in the next block.
+	Unlocked bool `protobuf:"varint,9,opt,name=unlocked" json:"unlocked,omitempty"`
+	//
+	// BlocksLeft is the number of blocks left before funds could be
+	// spent, only for locks by height.
+	BlocksLeft int64 `protobuf:"varint,10,opt,name=blocks_left,json=blocksLeft" json:"blocks_left,omitempty"`
+}
+
+func (m *TimeLock) Reset()                    { *m = TimeLock{} }
+func (m *TimeLock) String() string            { return proto.CompactTextString(m) }
+func (*TimeLock) ProtoMessage()               {}
+func (*TimeLock) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *TimeLock) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *TimeLock) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *TimeLock) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *TimeLock) GetRedeemScript() string {
+	if m != nil {
+		return m.RedeemScript
+	}
+	return ""
+}
+
+func (m *TimeLock) GetRecipientPubkey() string {
+	if m != nil {
+		return m.RecipientPubkey
+	}
+	return ""
+}
+
+func (m *TimeLock) GetLockHeight() uint32 {
+	if m != nil {
+		return m.LockHeight
+	}
+	return 0
+}
+
+func (m *TimeLock) GetLockTime() int64 {
+	if m != nil {
+		return m.LockTime
+	}
+	return 0
+}
+
+func (m *TimeLock) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *TimeLock) GetUnlocked() bool {
+	if m != nil {
+		return m.Unlocked
+	}
+	return false
+}
+
+func (m *TimeLock) GetBlocksLeft() int64 {
+	if m != nil {
+		return m.BlocksLeft
+	}
+	return 0
+}
+
+type ListTimeLocksRequest struct {
+	//
+	// (optional) Asset is used to return only time locks of the asset.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// (optional) IncludeUnlocked denotes that time locks which could be
+	// already spent should be returned too.
+	IncludeUnlocked bool `protobuf:"varint,2,opt,name=include_unlocked,json=includeUnlocked" json:"include_unlocked,omitempty"`
+}
+
+func (m *ListTimeLocksRequest) Reset()                    { *m = ListTimeLocksRequest{} }
+func (m *ListTimeLocksRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListTimeLocksRequest) ProtoMessage()               {}
+func (*ListTimeLocksRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *ListTimeLocksRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *ListTimeLocksRequest) GetIncludeUnlocked() bool {
+	if m != nil {
+		return m.IncludeUnlocked
+	}
+	return false
+}
+
+type ListTimeLocksResponse struct {
+	//
+	// TimeLocks is the list of time locks, locks by height go first, and
+	// locks are ordered by the height or time of unlock.
+	TimeLocks []*TimeLock `protobuf:"bytes,1,rep,name=time_locks,json=timeLocks" json:"time_locks,omitempty"`
+}
+
+func (m *ListTimeLocksResponse) Reset()                    { *m = ListTimeLocksResponse{} }
+func (m *ListTimeLocksResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListTimeLocksResponse) ProtoMessage()               {}
+func (*ListTimeLocksResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *ListTimeLocksResponse) GetTimeLocks() []*TimeLock {
+	if m != nil {
+		return m.TimeLocks
+	}
+	return nil
+}
+
+type PaymentByIDRequest struct {
+	//
+	// PaymentID is the payment id which was created by service itself,
+	// for unified identification of the payment.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+	//
+	// (optional) Include is the list of heavy payment fields which are
+	// returned only if they are requested, e.g. memo.
+	Include []PaymentInclude `protobuf:"varint,2,rep,packed,name=include,enum=crpc.PaymentInclude" json:"include,omitempty"`
+}
+
+func (m *PaymentByIDRequest) Reset()                    { *m = PaymentByIDRequest{} }
+func (m *PaymentByIDRequest) String() string            { return proto.CompactTextString(m) }
+func (*PaymentByIDRequest) ProtoMessage()               {}
+func (*PaymentByIDRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *PaymentByIDRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *PaymentByIDRequest) GetInclude() []PaymentInclude {
+	if m != nil {
+		return m.Include
+	}
+	return nil
+}
+
+type LabelPaymentRequest struct {
+	//
+	// PaymentID is the id of the payment which should be labeled.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+	//
+	// Labels are merged into the metadata of the payment, label with empty
+	// value is removed.
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *LabelPaymentRequest) Reset()                    { *m = LabelPaymentRequest{} }
+func (m *LabelPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*LabelPaymentRequest) ProtoMessage()               {}
+func (*LabelPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *LabelPaymentRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *LabelPaymentRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+type RefundPaymentRequest struct {
+	//
+	// PaymentID is the id of the completed incoming payment which should be
+	// refunded.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+	//
+	// Receipt is the blockchain address or the lightning invoice on which
+	// refund is sent, it should be in the asset and media of the payment.
+	// If not specified, refund is sent on the blockchain to the refund
+	// address attached to the receipt of the payment.
+	Receipt string `protobuf:"bytes,2,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// Amount is the amount of the refund, if not specified the rest of the
+	// payment which hasn't been refunded yet is sent.
+	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Memo is the message which is attached to the refund in the blockchain.
+	Memo string `protobuf:"bytes,4,opt,name=memo" json:"memo,omitempty"`
+}
+
+func (m *RefundPaymentRequest) Reset()                    { *m = RefundPaymentRequest{} }
+func (m *RefundPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*RefundPaymentRequest) ProtoMessage()               {}
+func (*RefundPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *RefundPaymentRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *RefundPaymentRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *RefundPaymentRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *RefundPaymentRequest) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+type CancelPaymentRequest struct {
+	//
+	// PaymentID is the id of the waiting outgoing payment which should be
+	// cancelled.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+}
+
+func (m *CancelPaymentRequest) Reset()                    { *m = CancelPaymentRequest{} }
+func (m *CancelPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*CancelPaymentRequest) ProtoMessage()               {}
+func (*CancelPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *CancelPaymentRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+type PaymentProofRequest struct {
+	//
+	// PaymentID is the identifier of the completed payment.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+}
+
+func (m *PaymentProofRequest) Reset()                    { *m = PaymentProofRequest{} }
+func (m *PaymentProofRequest) String() string            { return proto.CompactTextString(m) }
+func (*PaymentProofRequest) ProtoMessage()               {}
+func (*PaymentProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *PaymentProofRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+type PaymentProof struct {
+	//
+	// Proof is the JSON encoded details of the payment: its identifier,
+	// direction, asset, media, receipt, amount, fee, media id, time of
+	// the completion, and the time of the issue of the proof.
+	Proof string `protobuf:"bytes,1,opt,name=proof" json:"proof,omitempty"`
+	//
+	// Signature is the base64 encoded ECDSA signature of the proof bytes
+	// made by the server identity key.
+	Signature string `protobuf:"bytes,2,opt,name=signature" json:"signature,omitempty"`
+	//
+	// KeyId is the id of the identity key which has signed the proof.
+	KeyId string `protobuf:"bytes,3,opt,name=key_id,json=keyId" json:"key_id,omitempty"`
+	//
+	// PublicKey is the hex encoded DER public key which has signed the
+	// proof.
+	PublicKey string `protobuf:"bytes,4,opt,name=public_key,json=publicKey" json:"public_key,omitempty"`
+}
+
+func (m *PaymentProof) Reset()                    { *m = PaymentProof{} }
+func (m *PaymentProof) String() string            { return proto.CompactTextString(m) }
+func (*PaymentProof) ProtoMessage()               {}
+func (*PaymentProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *PaymentProof) GetProof() string {
+	if m != nil {
+		return m.Proof
+	}
+	return ""
+}
+
+func (m *PaymentProof) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
+func (m *PaymentProof) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+func (m *PaymentProof) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+type TransferFundsRequest struct {
+	//
+	// Asset is an acronym of the crypto currency which is transferred.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is the media of the balance which is transferred.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// FromAccount is the identifier of the account from which funds are
+	// taken, its balance should cover the amount.
+	FromAccount string `protobuf:"bytes,3,opt,name=from_account,json=fromAccount" json:"from_account,omitempty"`
+	//
+	// ToAccount is the identifier of the account to which funds are moved.
+	ToAccount string `protobuf:"bytes,4,opt,name=to_account,json=toAccount" json:"to_account,omitempty"`
+	//
+	// Amount is the amount of the transfer.
+	Amount string `protobuf:"bytes,5,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Memo is the description of the transfer.
+	Memo string `protobuf:"bytes,6,opt,name=memo" json:"memo,omitempty"`
+	//
+	// Metadata is the labels which are attached to both payments of the
+	// transfer.
+	Metadata map[string]string `protobuf:"bytes,7,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *TransferFundsRequest) Reset()                    { *m = TransferFundsRequest{} }
+func (m *TransferFundsRequest) String() string            { return proto.CompactTextString(m) }
+func (*TransferFundsRequest) ProtoMessage()               {}
+func (*TransferFundsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *TransferFundsRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *TransferFundsRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *TransferFundsRequest) GetFromAccount() string {
+	if m != nil {
+		return m.FromAccount
+	}
+	return ""
+}
+
+func (m *TransferFundsRequest) GetToAccount() string {
+	if m != nil {
+		return m.ToAccount
+	}
+	return ""
+}
+
+func (m *TransferFundsRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *TransferFundsRequest) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *TransferFundsRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type TransferFundsResponse struct {
+	//
+	// Debit is the outgoing payment of the source account.
+	Debit *Payment `protobuf:"bytes,1,opt,name=debit" json:"debit,omitempty"`
+	//
+	// Credit is the incoming payment of the destination account.
+	Credit *Payment `protobuf:"bytes,2,opt,name=credit" json:"credit,omitempty"`
+}
+
+func (m *TransferFundsResponse) Reset()                    { *m = TransferFundsResponse{} }
+func (m *TransferFundsResponse) String() string            { return proto.CompactTextString(m) }
+func (*TransferFundsResponse) ProtoMessage()               {}
+func (*TransferFundsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *TransferFundsResponse) GetDebit() *Payment {
+	if m != nil {
+		return m.Debit
+	}
+	return nil
+}
+
+func (m *TransferFundsResponse) GetCredit() *Payment {
+	if m != nil {
+		return m.Credit
+	}
+	return nil
+}
+
+type CreateAccountRequest struct {
+	//
+	// Account is the unique identifier of the account, which is used in the
+	// account field of the payments and receipts.
+	Account string `protobuf:"bytes,1,opt,name=account" json:"account,omitempty"`
+	//
+	// (optional) Name is the human readable name of the account.
+	Name string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *CreateAccountRequest) Reset()                    { *m = CreateAccountRequest{} }
+func (m *CreateAccountRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateAccountRequest) ProtoMessage()               {}
+func (*CreateAccountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *CreateAccountRequest) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *CreateAccountRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type Account struct {
+	//
+	// Account is the unique identifier of the account.
+	Account string `protobuf:"bytes,1,opt,name=account" json:"account,omitempty"`
+	//
+	// Name is the human readable name of the account.
+	Name string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	//
+	// CreatedAt is the time of the account registration in milliseconds.
+	CreatedAt int64 `protobuf:"varint,3,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	//
+	// Balances are the balances of the account in every asset and media in
+	// which it has payments, they are returned only if requested. Pending
+	// funds are the incoming payments which are not completed yet.
+	Balances []*Balance `protobuf:"bytes,4,rep,name=balances" json:"balances,omitempty"`
+}
+
+func (m *Account) Reset()                    { *m = Account{} }
+func (m *Account) String() string            { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()               {}
+func (*Account) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *Account) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *Account) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Account) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Account) GetBalances() []*Balance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+type ListAccountsRequest struct {
+	//
+	// (optional) IncludeBalances is used to return the balances of the
+	// accounts, which are calculated from all of their payments.
+	IncludeBalances bool `protobuf:"varint,1,opt,name=include_balances,json=includeBalances" json:"include_balances,omitempty"`
+}
+
+func (m *ListAccountsRequest) Reset()                    { *m = ListAccountsRequest{} }
+func (m *ListAccountsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListAccountsRequest) ProtoMessage()               {}
+func (*ListAccountsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *ListAccountsRequest) GetIncludeBalances() bool {
+	if m != nil {
+		return m.IncludeBalances
+	}
+	return false
+}
+
+type ListAccountsResponse struct {
+	Accounts []*Account `protobuf:"bytes,1,rep,name=accounts" json:"accounts,omitempty"`
+}
+
+func (m *ListAccountsResponse) Reset()                    { *m = ListAccountsResponse{} }
+func (m *ListAccountsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListAccountsResponse) ProtoMessage()               {}
+func (*ListAccountsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *ListAccountsResponse) GetAccounts() []*Account {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+type ListDepositAddressesRequest struct {
+	//
+	// Account is the identifier of the account which addresses are
+	// returned.
+	Account string `protobuf:"bytes,1,opt,name=account" json:"account,omitempty"`
+}
+
+func (m *ListDepositAddressesRequest) Reset()                    { *m = ListDepositAddressesRequest{} }
+func (m *ListDepositAddressesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListDepositAddressesRequest) ProtoMessage()               {}
+func (*ListDepositAddressesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *ListDepositAddressesRequest) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+type DepositAddress struct {
+	//
+	// Address is the blockchain address in the canonical form.
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// CreatedAt is the time of the address creation in milliseconds.
+	CreatedAt int64 `protobuf:"varint,3,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+}
+
+func (m *DepositAddress) Reset()                    { *m = DepositAddress{} }
+func (m *DepositAddress) String() string            { return proto.CompactTextString(m) }
+func (*DepositAddress) ProtoMessage()               {}
+func (*DepositAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+func (m *DepositAddress) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *DepositAddress) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *DepositAddress) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type ListDepositAddressesResponse struct {
+	Addresses []*DepositAddress `protobuf:"bytes,1,rep,name=addresses" json:"addresses,omitempty"`
+}
+
+func (m *ListDepositAddressesResponse) Reset()                    { *m = ListDepositAddressesResponse{} }
+func (m *ListDepositAddressesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListDepositAddressesResponse) ProtoMessage()               {}
+func (*ListDepositAddressesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+func (m *ListDepositAddressesResponse) GetAddresses() []*DepositAddress {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type AccountStatementRequest struct {
+	//
+	// Account is the identifier of the account.
+	Account string `protobuf:"bytes,1,opt,name=account" json:"account,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is the media of the balance.
+	Media Media `protobuf:"varint,3,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// (optional) From is the time in milliseconds from which ledger
+	// entries are returned, inclusive. Entries before it are summed up in
+	// the opening balance.
+	From int64 `protobuf:"varint,4,opt,name=from" json:"from,omitempty"`
+	//
+	// (optional) To is the time in milliseconds until which ledger entries
+	// are returned, inclusive, current time is used if it isn't set.
+	To int64 `protobuf:"varint,5,opt,name=to" json:"to,omitempty"`
+}
+
+func (m *AccountStatementRequest) Reset()                    { *m = AccountStatementRequest{} }
+func (m *AccountStatementRequest) String() string            { return proto.CompactTextString(m) }
+func (*AccountStatementRequest) ProtoMessage()               {}
+func (*AccountStatementRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+
+func (m *AccountStatementRequest) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *AccountStatementRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *AccountStatementRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *AccountStatementRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *AccountStatementRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+type StatementEntry struct {
+	//
+	// PaymentID is the identifier of the payment which has changed the
+	// balance.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+	//
+	// Time is the time of the entry in milliseconds, i.e. the time of the
+	// completion of the incoming payment or the time of the last update of
+	// the outgoing one.
+	Time int64 `protobuf:"varint,2,opt,name=time" json:"time,omitempty"`
+	//
+	// System denotes whether payment is external or internal transfer
+	// between the accounts.
+	System PaymentSystem `protobuf:"varint,3,opt,name=system,enum=crpc.PaymentSystem" json:"system,omitempty"`
+	//
+	// Credit is the amount which has been received by the account, zero
+	// for the debit entry.
+	Credit string `protobuf:"bytes,4,opt,name=credit" json:"credit,omitempty"`
+	//
+	// Debit is the amount which has been sent from the account, without
+	// the fee, zero for the credit entry.
+	Debit string `protobuf:"bytes,5,opt,name=debit" json:"debit,omitempty"`
+	//
+	// Fee is the media fee which has been paid by the account.
+	Fee string `protobuf:"bytes,6,opt,name=fee" json:"fee,omitempty"`
+	//
+	// Balance is the balance of the account after the entry.
+	Balance string `protobuf:"bytes,7,opt,name=balance" json:"balance,omitempty"`
+	//
+	// Receipt is the address or invoice of the payment, or the counterpart
+	// account of the internal transfer.
+	Receipt string `protobuf:"bytes,8,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// MediaId is the identifier of the payment in the media, e.g.
+	// blockchain transaction id.
+	MediaId string `protobuf:"bytes,9,opt,name=media_id,json=mediaId" json:"media_id,omitempty"`
+}
+
+func (m *StatementEntry) Reset()                    { *m = StatementEntry{} }
+func (m *StatementEntry) String() string            { return proto.CompactTextString(m) }
+func (*StatementEntry) ProtoMessage()               {}
+func (*StatementEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+
+func (m *StatementEntry) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *StatementEntry) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *StatementEntry) GetSystem() PaymentSystem {
+	if m != nil {
+		return m.System
+	}
+	return PaymentSystem_SYSTEM_NONE
+}
+
+func (m *StatementEntry) GetCredit() string {
+	if m != nil {
+		return m.Credit
+	}
+	return ""
+}
+
+func (m *StatementEntry) GetDebit() string {
+	if m != nil {
+		return m.Debit
+	}
+	return ""
+}
+
+func (m *StatementEntry) GetFee() string {
+	if m != nil {
+		return m.Fee
+	}
+	return ""
+}
+
+func (m *StatementEntry) GetBalance() string {
+	if m != nil {
+		return m.Balance
+	}
+	return ""
+}
+
+func (m *StatementEntry) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *StatementEntry) GetMediaId() string {
+	if m != nil {
+		return m.MediaId
+	}
+	return ""
+}
+
+type AccountStatementResponse struct {
+	//
+	// OpeningBalance is the balance of the account at the beginning of the
+	// time window.
+	OpeningBalance string `protobuf:"bytes,1,opt,name=opening_balance,json=openingBalance" json:"opening_balance,omitempty"`
+	//
+	// Entries are the changes of the balance within the time window in
+	// chronological order.
+	Entries []*StatementEntry `protobuf:"bytes,2,rep,name=entries" json:"entries,omitempty"`
+	//
+	// ClosingBalance is the balance of the account at the end of the time
+	// window.
+	ClosingBalance string `protobuf:"bytes,3,opt,name=closing_balance,json=closingBalance" json:"closing_balance,omitempty"`
+	//
+	// Credits is the overall amount of the credit entries.
+	Credits string `protobuf:"bytes,4,opt,name=credits" json:"credits,omitempty"`
+	//
+	// Debits is the overall amount of the debit entries.
+	Debits string `protobuf:"bytes,5,opt,name=debits" json:"debits,omitempty"`
+	//
+	// Fees is the overall fee of the debit entries.
+	Fees string `protobuf:"bytes,6,opt,name=fees" json:"fees,omitempty"`
+}
+
+func (m *AccountStatementResponse) Reset()                    { *m = AccountStatementResponse{} }
+func (m *AccountStatementResponse) String() string            { return proto.CompactTextString(m) }
+func (*AccountStatementResponse) ProtoMessage()               {}
+func (*AccountStatementResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+func (m *AccountStatementResponse) GetOpeningBalance() string {
+	if m != nil {
+		return m.OpeningBalance
+	}
+	return ""
+}
+
+func (m *AccountStatementResponse) GetEntries() []*StatementEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *AccountStatementResponse) GetClosingBalance() string {
+	if m != nil {
+		return m.ClosingBalance
+	}
+	return ""
+}
+
+func (m *AccountStatementResponse) GetCredits() string {
+	if m != nil {
+		return m.Credits
+	}
+	return ""
+}
+
+func (m *AccountStatementResponse) GetDebits() string {
+	if m != nil {
+		return m.Debits
+	}
+	return ""
+}
+
+func (m *AccountStatementResponse) GetFees() string {
+	if m != nil {
+		return m.Fees
+	}
+	return ""
+}
+
+type PaymentsByReceiptRequest struct {
+	//
+	// Receipt represent either blockchains address or lightning
+	// network invoice, depending on the type of the request.
+	Receipt string `protobuf:"bytes,1,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// (optional) Include is the list of heavy payment fields which are
+	// returned only if they are requested, e.g. memo.
+	Include []PaymentInclude `protobuf:"varint,2,rep,packed,name=include,enum=crpc.PaymentInclude" json:"include,omitempty"`
+}
+
+func (m *PaymentsByReceiptRequest) Reset()                    { *m = PaymentsByReceiptRequest{} }
+func (m *PaymentsByReceiptRequest) String() string            { return proto.CompactTextString(m) }
+func (*PaymentsByReceiptRequest) ProtoMessage()               {}
+func (*PaymentsByReceiptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+func (m *PaymentsByReceiptRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *PaymentsByReceiptRequest) GetInclude() []PaymentInclude {
+	if m != nil {
+		return m.Include
+	}
+	return nil
+}
+
+type PaymentsByReceiptResponse struct {
+	Payments []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+}
+
+func (m *PaymentsByReceiptResponse) Reset()                    { *m = PaymentsByReceiptResponse{} }
+func (m *PaymentsByReceiptResponse) String() string            { return proto.CompactTextString(m) }
+func (*PaymentsByReceiptResponse) ProtoMessage()               {}
+func (*PaymentsByReceiptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+func (m *PaymentsByReceiptResponse) GetPayments() []*Payment {
+	if m != nil {
+		return m.Payments
+	}
+	return nil
+}
+
+type ListPaymentsRequest struct {
+	//
+	// (optional) Status denotes the stage of the processing the payment.
+	Status PaymentStatus `protobuf:"varint,1,opt,name=status,enum=crpc.PaymentStatus" json:"status,omitempty"`
+	//
+	// (optional) Direction denotes the direction of the payment.
+	Direction PaymentDirection `protobuf:"varint,2,opt,name=direction,enum=crpc.PaymentDirection" json:"direction,omitempty"`
+	//
+	// (optional) Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,3,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// (optional) Media is a type of technology which is used to transport
+	// value of underlying asset.
+	Media Media `protobuf:"varint,4,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// (optional) PaymentSystem denotes is that payment belongs to business
+	// logic of payment server or it was originated by user / third-party
+	// service.
+	System PaymentSystem `protobuf:"varint,5,opt,name=system,enum=crpc.PaymentSystem" json:"system,omitempty"`
+	//
+	// (optional) Limit is the maximum number of returned payments, all
+	// matching payments are returned if it is not specified.
+	Limit uint32 `protobuf:"varint,6,opt,name=limit" json:"limit,omitempty"`
+	//
+	// (optional) Offset is the number of matching payments which are
+	// skipped, it is used along with the limit to fetch the next page.
+	Offset uint64 `protobuf:"varint,7,opt,name=offset" json:"offset,omitempty"`
+	//
+	// (optional) SortBy is the field by which payments are sorted, by
+	// default they are sorted by the time of last update.
+	SortBy PaymentsSortBy `protobuf:"varint,8,opt,name=sort_by,json=sortBy,enum=crpc.PaymentsSortBy" json:"sort_by,omitempty"`
+	//
+	// (optional) Ascending denotes that payments are sorted in ascending
+	// order, by default they are sorted in descending one.
+	Ascending bool `protobuf:"varint,9,opt,name=ascending" json:"ascending,omitempty"`
+	//
+	// (optional) Include is the list of heavy payment fields which are
+	// returned only if they are requested, e.g. memo.
+	Include []PaymentInclude `protobuf:"varint,10,rep,packed,name=include,enum=crpc.PaymentInclude" json:"include,omitempty"`
+	//
+	// (optional) FromTime is the time in milliseconds from which payments
+	// are returned, inclusive. Payments are filtered by the time of the
+	// last update, which is the completion time of completed payments.
+	FromTime int64 `protobuf:"varint,11,opt,name=from_time,json=fromTime" json:"from_time,omitempty"`
+	//
+	// (optional) ToTime is the time in milliseconds until which payments
+	// are returned, inclusive.
+	ToTime int64 `protobuf:"varint,12,opt,name=to_time,json=toTime" json:"to_time,omitempty"`
+	//
+	// (optional) MinAmount is the minimum amount of returned payments,
+	// inclusive.
+	MinAmount string `protobuf:"bytes,13,opt,name=min_amount,json=minAmount" json:"min_amount,omitempty"`
+	//
+	// (optional) MaxAmount is the maximum amount of returned payments,
+	// inclusive.
+	MaxAmount string `protobuf:"bytes,14,opt,name=max_amount,json=maxAmount" json:"max_amount,omitempty"`
+	//
+	// (optional) Account is the identifier of the account to which returned
+	// payments belong.
+	Account string `protobuf:"bytes,15,opt,name=account" json:"account,omitempty"`
+	//
+	// (optional) SinceSequence is the sequence number after which returned
+	// payments have been changed, exclusive. Along with SORT_SEQUENCE in
+	// ascending order it is used for the incremental sync, next request
+	// should specify the sequence of the last received payment.
+	SinceSequence uint64 `protobuf:"varint,16,opt,name=since_sequence,json=sinceSequence" json:"since_sequence,omitempty"`
+	//
+	// (optional) Quarantine is the quarantine state of returned payments,
+	// e.g. QUARANTINED to list payments which are under the review.
+	Quarantine QuarantineState `protobuf:"varint,17,opt,name=quarantine,enum=crpc.QuarantineState" json:"quarantine,omitempty"`
+}
+
+func (m *ListPaymentsRequest) Reset()                    { *m = ListPaymentsRequest{} }
+func (m *ListPaymentsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListPaymentsRequest) ProtoMessage()               {}
+func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *ListPaymentsRequest) GetStatus() PaymentStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PaymentStatus_STATUS_NONE
+}
+
+func (m *ListPaymentsRequest) GetDirection() PaymentDirection {
+	if m != nil {
+		return m.Direction
+	}
+	return PaymentDirection_DIRECTION_NONE
+}
+
+func (m *ListPaymentsRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *ListPaymentsRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *ListPaymentsRequest) GetSystem() PaymentSystem {
+	if m != nil {
+		return m.System
+	}
+	return PaymentSystem_SYSTEM_NONE
+}
+
+func (m *ListPaymentsRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetSortBy() PaymentsSortBy {
+	if m != nil {
+		return m.SortBy
+	}
+	return PaymentsSortBy_SORT_UPDATED_AT
+}
+
+func (m *ListPaymentsRequest) GetAscending() bool {
+	if m != nil {
+		return m.Ascending
+	}
+	return false
+}
+
+func (m *ListPaymentsRequest) GetInclude() []PaymentInclude {
+	if m != nil {
+		return m.Include
+	}
+	return nil
+}
+
+func (m *ListPaymentsRequest) GetFromTime() int64 {
+	if m != nil {
+		return m.FromTime
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetToTime() int64 {
+	if m != nil {
+		return m.ToTime
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetMinAmount() string {
+	if m != nil {
+		return m.MinAmount
+	}
+	return ""
+}
+
+func (m *ListPaymentsRequest) GetMaxAmount() string {
+	if m != nil {
+		return m.MaxAmount
+	}
+	return ""
+}
+
+func (m *ListPaymentsRequest) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *ListPaymentsRequest) GetSinceSequence() uint64 {
+	if m != nil {
+		return m.SinceSequence
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetQuarantine() QuarantineState {
+	if m != nil {
+		return m.Quarantine
+	}
+	return QuarantineState_QUARANTINE_NONE
+}
+
+type ListPaymentsResponse struct {
+	Payments []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+	//
+	// Total is the overall number of payments which are matching the
+	// filter, regardless of the limit and offset.
+	Total uint64 `protobuf:"varint,2,opt,name=total" json:"total,omitempty"`
+}
+
+func (m *ListPaymentsResponse) Reset()                    { *m = ListPaymentsResponse{} }
+func (m *ListPaymentsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListPaymentsResponse) ProtoMessage()               {}
+func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+
+func (m *ListPaymentsResponse) GetPayments() []*Payment {
+	if m != nil {
+		return m.Payments
+	}
+	return nil
+}
+
+func (m *ListPaymentsResponse) GetTotal() uint64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+type ExportPaymentsRequest struct {
+	//
+	// (optional) Filter is the set of parameters by which payments are
+	// selected, the same as in ListPayments.
+	Filter *ListPaymentsRequest `protobuf:"bytes,1,opt,name=filter" json:"filter,omitempty"`
+	//
+	// (optional) Format is the format of the export file, CSV is used by
+	// default.
+	Format ExportFormat `protobuf:"varint,2,opt,name=format,enum=crpc.ExportFormat" json:"format,omitempty"`
+}
+
+func (m *ExportPaymentsRequest) Reset()                    { *m = ExportPaymentsRequest{} }
+func (m *ExportPaymentsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportPaymentsRequest) ProtoMessage()               {}
+func (*ExportPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+
+func (m *ExportPaymentsRequest) GetFilter() *ListPaymentsRequest {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *ExportPaymentsRequest) GetFormat() ExportFormat {
+	if m != nil {
+		return m.Format
+	}
+	return ExportFormat_EXPORT_CSV
+}
+
+type ExportChunk struct {
+	//
+	// Data is the next part of the export file.
+	Data []byte `protobuf:"bytes,1,opt,name=data" json:"data,omitempty"`
+}
+
+func (m *ExportChunk) Reset()                    { *m = ExportChunk{} }
+func (m *ExportChunk) String() string            { return proto.CompactTextString(m) }
+func (*ExportChunk) ProtoMessage()               {}
+func (*ExportChunk) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+
+func (m *ExportChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type SubscribePaymentsRequest struct {
+	//
+	// (optional) Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// (optional) Media is a type of technology which is used to transport
+	// value of underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// (optional) Direction denotes the direction of the payment.
+	Direction PaymentDirection `protobuf:"varint,3,opt,name=direction,enum=crpc.PaymentDirection" json:"direction,omitempty"`
+	//
+	// (optional) Include is the list of heavy payment fields which are
+	// returned only if they are requested, e.g. memo.
+	Include []PaymentInclude `protobuf:"varint,4,rep,packed,name=include,enum=crpc.PaymentInclude" json:"include,omitempty"`
+	//
+	// (optional) MinConfirmations is the number of confirmations which
+	// pending payment should have in order to be sent, e.g. 3 to be
+	// notified only about deep enough deposits. Pending payments which
+	// confirmations are unknown, e.g. lightning ones, are not sent if it
+	// is set. Completed and failed payments are always sent.
+	MinConfirmations int64 `protobuf:"varint,5,opt,name=min_confirmations,json=minConfirmations" json:"min_confirmations,omitempty"`
+	//
+	// (optional) TerminalOnly is used to send only completed and failed
+	// payments.
+	TerminalOnly bool `protobuf:"varint,6,opt,name=terminal_only,json=terminalOnly" json:"terminal_only,omitempty"`
+}
+
+func (m *SubscribePaymentsRequest) Reset()                    { *m = SubscribePaymentsRequest{} }
+func (m *SubscribePaymentsRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribePaymentsRequest) ProtoMessage()               {}
+func (*SubscribePaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+
+func (m *SubscribePaymentsRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *SubscribePaymentsRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *SubscribePaymentsRequest) GetDirection() PaymentDirection {
+	if m != nil {
+		return m.Direction
+	}
+	return PaymentDirection_DIRECTION_NONE
+}
+
+func (m *SubscribePaymentsRequest) GetInclude() []PaymentInclude {
+	if m != nil {
+		return m.Include
+	}
+	return nil
+}
+
+func (m *SubscribePaymentsRequest) GetMinConfirmations() int64 {
+	if m != nil {
+		return m.MinConfirmations
+	}
+	return 0
+}
+
+func (m *SubscribePaymentsRequest) GetTerminalOnly() bool {
+	if m != nil {
+		return m.TerminalOnly
+	}
+	return false
+}
+
+type Payee struct {
+	//
+	// Name is the unique name of the payee, e.g. "treasury-cold".
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,3,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Receipt represent either blockchains address or lightning
+	// network invoice, which is used to determine payment receiver.
+	Receipt string `protobuf:"bytes,4,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// (optional) Amount is the default amount of the payment, which is
+	// used if amount is not specified in the send payment request.
+	Amount string `protobuf:"bytes,5,opt,name=amount" json:"amount,omitempty"`
+}
+
+func (m *Payee) Reset()                    { *m = Payee{} }
+func (m *Payee) String() string            { return proto.CompactTextString(m) }
+func (*Payee) ProtoMessage()               {}
+func (*Payee) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+
+func (m *Payee) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Payee) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *Payee) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *Payee) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *Payee) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+type RemovePayeeRequest struct {
+	//
+	// Name is the name of the payee which should be removed.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *RemovePayeeRequest) Reset()                    { *m = RemovePayeeRequest{} }
+func (m *RemovePayeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemovePayeeRequest) ProtoMessage()               {}
+func (*RemovePayeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+
+func (m *RemovePayeeRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type Branding struct {
+	//
+	// Tenant is the id of the API key of the merchant. Empty tenant denotes
+	// the default branding, which is used for tenants without their own.
+	Tenant string `protobuf:"bytes,1,opt,name=tenant" json:"tenant,omitempty"`
+	//
+	// (optional) LogoURL is the https URL of the logo image.
+	LogoUrl string `protobuf:"bytes,2,opt,name=logo_url,json=logoUrl" json:"logo_url,omitempty"`
+	//
+	// (optional) PrimaryColor is the main color of the page in form of
+	// "#rrggbb".
+	PrimaryColor string `protobuf:"bytes,3,opt,name=primary_color,json=primaryColor" json:"primary_color,omitempty"`
+	//
+	// (optional) AccentColor is the color of the buttons and links in form
+	// of "#rrggbb".
+	AccentColor string `protobuf:"bytes,4,opt,name=accent_color,json=accentColor" json:"accent_color,omitempty"`
+	//
+	// (optional) SuccessURL is the http(s) URL to which payer is redirected
+	// after the payment is received.
+	SuccessUrl string `protobuf:"bytes,5,opt,name=success_url,json=successUrl" json:"success_url,omitempty"`
+}
+
+func (m *Branding) Reset()                    { *m = Branding{} }
+func (m *Branding) String() string            { return proto.CompactTextString(m) }
+func (*Branding) ProtoMessage()               {}
+func (*Branding) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+
+func (m *Branding) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
+func (m *Branding) GetLogoUrl() string {
+	if m != nil {
+		return m.LogoUrl
+	}
+	return ""
+}
+
+func (m *Branding) GetPrimaryColor() string {
+	if m != nil {
+		return m.PrimaryColor
+	}
+	return ""
+}
+
+func (m *Branding) GetAccentColor() string {
+	if m != nil {
+		return m.AccentColor
+	}
+	return ""
+}
+
+func (m *Branding) GetSuccessUrl() string {
+	if m != nil {
+		return m.SuccessUrl
+	}
+	return ""
+}
+
+type RemoveBrandingRequest struct {
+	//
+	// Tenant is the id of the API key of the merchant whose branding should
+	// be removed.
+	Tenant string `protobuf:"bytes,1,opt,name=tenant" json:"tenant,omitempty"`
+}
+
+func (m *RemoveBrandingRequest) Reset()                    { *m = RemoveBrandingRequest{} }
+func (m *RemoveBrandingRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveBrandingRequest) ProtoMessage()               {}
+func (*RemoveBrandingRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
+
+func (m *RemoveBrandingRequest) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
+type ListPayeesResponse struct {
+	Payees []*Payee `protobuf:"bytes,1,rep,name=payees" json:"payees,omitempty"`
+}
+
+func (m *ListPayeesResponse) Reset()                    { *m = ListPayeesResponse{} }
+func (m *ListPayeesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListPayeesResponse) ProtoMessage()               {}
+func (*ListPayeesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+
+func (m *ListPayeesResponse) GetPayees() []*Payee {
+	if m != nil {
+		return m.Payees
+	}
+	return nil
+}
+
+type WatchAddress struct {
+	//
+	// Group is the label of the group to which address belongs.
+	Group string `protobuf:"bytes,1,opt,name=group" json:"group,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Address is the blockchain address which should be watched.
+	Address string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+	//
+	// (optional) Account is the identificator of the external account to
+	// which address is mapped, it is passed along with the watch events.
+	Account string `protobuf:"bytes,4,opt,name=account" json:"account,omitempty"`
+	//
+	// (optional) Inactive denotes that address is kept, but its activity
+	// doesn't produce watch events.
+	Inactive bool `protobuf:"varint,5,opt,name=inactive" json:"inactive,omitempty"`
+}
+
+func (m *WatchAddress) Reset()                    { *m = WatchAddress{} }
+func (m *WatchAddress) String() string            { return proto.CompactTextString(m) }
+func (*WatchAddress) ProtoMessage()               {}
+func (*WatchAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+
+func (m *WatchAddress) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *WatchAddress) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *WatchAddress) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *WatchAddress) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *WatchAddress) GetInactive() bool {
+	if m != nil {
+		return m.Inactive
+	}
+	return false
+}
+
+type ImportWatchAddressesRequest struct {
+	//
+	// Addresses is the list of addresses which should be watched.
+	Addresses []*WatchAddress `protobuf:"bytes,1,rep,name=addresses" json:"addresses,omitempty"`
+}
+
+func (m *ImportWatchAddressesRequest) Reset()                    { *m = ImportWatchAddressesRequest{} }
+func (m *ImportWatchAddressesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportWatchAddressesRequest) ProtoMessage()               {}
+func (*ImportWatchAddressesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+
+func (m *ImportWatchAddressesRequest) GetAddresses() []*WatchAddress {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type AddressBook struct {
+	//
+	// Bundle is the JSON encoded payee presets and watched addresses.
+	Bundle string `protobuf:"bytes,1,opt,name=bundle" json:"bundle,omitempty"`
+	//
+	// Signature is the base64 encoded signature of the bundle in the
+	// compact JSON form, so that bundle could be reformatted for the
+	// review without breaking the signature.
+	Signature string `protobuf:"bytes,2,opt,name=signature" json:"signature,omitempty"`
+	//
+	// KeyId is the id of the identity key which has signed the bundle.
+	KeyId string `protobuf:"bytes,3,opt,name=key_id,json=keyId" json:"key_id,omitempty"`
+	//
+	// PublicKey is the hex encoded DER public key which has signed the
+	// bundle. Bundle of another server is trusted only if its key is
+	// verified by the operator, e.g. with GetPublicKeys of that server.
+	PublicKey string `protobuf:"bytes,4,opt,name=public_key,json=publicKey" json:"public_key,omitempty"`
+}
+
+func (m *AddressBook) Reset()                    { *m = AddressBook{} }
+func (m *AddressBook) String() string            { return proto.CompactTextString(m) }
+func (*AddressBook) ProtoMessage()               {}
+func (*AddressBook) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+
+func (m *AddressBook) GetBundle() string {
+	if m != nil {
+		return m.Bundle
+	}
+	return ""
+}
+
+func (m *AddressBook) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
+func (m *AddressBook) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+func (m *AddressBook) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+type ImportAddressBookResponse struct {
+	//
+	// Payees is the number of the created or updated payee presets.
+	Payees uint32 `protobuf:"varint,1,opt,name=payees" json:"payees,omitempty"`
+	//
+	// WatchAddresses is the result of the import of the watched addresses.
+	WatchAddresses *ImportWatchAddressesResponse `protobuf:"bytes,2,opt,name=watch_addresses,json=watchAddresses" json:"watch_addresses,omitempty"`
+}
+
+func (m *ImportAddressBookResponse) Reset()                    { *m = ImportAddressBookResponse{} }
+func (m *ImportAddressBookResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportAddressBookResponse) ProtoMessage()               {}
+func (*ImportAddressBookResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
+
+func (m *ImportAddressBookResponse) GetPayees() uint32 {
+	if m != nil {
+		return m.Payees
+	}
+	return 0
+}
+
+func (m *ImportAddressBookResponse) GetWatchAddresses() *ImportWatchAddressesResponse {
+	if m != nil {
+		return m.WatchAddresses
+	}
+	return nil
+}
+
+type ImportWatchAddressesResponse struct {
+	//
+	// Added is the number of addresses which haven't been watched before.
+	Added uint32 `protobuf:"varint,1,opt,name=added" json:"added,omitempty"`
+	//
+	// Updated is the number of watched addresses which group, account or
+	// activation status have been changed.
+	Updated uint32 `protobuf:"varint,2,opt,name=updated" json:"updated,omitempty"`
+	//
+	// Unchanged is the number of addresses which have been already
+	// watched in the same way.
+	Unchanged uint32 `protobuf:"varint,3,opt,name=unchanged" json:"unchanged,omitempty"`
+}
+
+func (m *ImportWatchAddressesResponse) Reset()                    { *m = ImportWatchAddressesResponse{} }
+func (m *ImportWatchAddressesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportWatchAddressesResponse) ProtoMessage()               {}
+func (*ImportWatchAddressesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
+
+func (m *ImportWatchAddressesResponse) GetAdded() uint32 {
+	if m != nil {
+		return m.Added
+	}
+	return 0
+}
+
+func (m *ImportWatchAddressesResponse) GetUpdated() uint32 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+func (m *ImportWatchAddressesResponse) GetUnchanged() uint32 {
+	if m != nil {
+		return m.Unchanged
+	}
+	return 0
+}
+
+type RemoveWatchAddressRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Address is the blockchain address which shouldn't be watched anymore.
+	Address string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *RemoveWatchAddressRequest) Reset()                    { *m = RemoveWatchAddressRequest{} }
+func (m *RemoveWatchAddressRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveWatchAddressRequest) ProtoMessage()               {}
+func (*RemoveWatchAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
+
+func (m *RemoveWatchAddressRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *RemoveWatchAddressRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type ListWatchAddressesRequest struct {
+	//
+	// (optional) Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// (optional) Group is the label of the group of addresses.
+	Group string `protobuf:"bytes,2,opt,name=group" json:"group,omitempty"`
+}
+
+func (m *ListWatchAddressesRequest) Reset()                    { *m = ListWatchAddressesRequest{} }
+func (m *ListWatchAddressesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListWatchAddressesRequest) ProtoMessage()               {}
+func (*ListWatchAddressesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+
+func (m *ListWatchAddressesRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *ListWatchAddressesRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+type ListWatchAddressesResponse struct {
+	Addresses []*WatchAddress `protobuf:"bytes,1,rep,name=addresses" json:"addresses,omitempty"`
+}
+
+func (m *ListWatchAddressesResponse) Reset()                    { *m = ListWatchAddressesResponse{} }
+func (m *ListWatchAddressesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListWatchAddressesResponse) ProtoMessage()               {}
+func (*ListWatchAddressesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
+
+func (m *ListWatchAddressesResponse) GetAddresses() []*WatchAddress {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type WatchEvent struct {
+	//
+	// EventID is the unique identificator of the event.
+	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId" json:"event_id,omitempty"`
+	//
+	// CreatedAt denotes the time when event has been detected.
+	CreatedAt int64 `protobuf:"varint,2,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	//
+	// Group is the label of the group to which watched address belongs.
+	Group string `protobuf:"bytes,3,opt,name=group" json:"group,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,4,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Address is the watched address which was touched.
+	Address string `protobuf:"bytes,5,opt,name=address" json:"address,omitempty"`
+	//
+	// Direction denotes whether funds were received or sent by the watched
+	// address.
+	Direction PaymentDirection `protobuf:"varint,6,opt,name=direction,enum=crpc.PaymentDirection" json:"direction,omitempty"`
+	//
+	// Amount is the number of funds which were moved.
+	Amount string `protobuf:"bytes,7,opt,name=amount" json:"amount,omitempty"`
+	//
+	// TxID is the identificator of the transaction in the blockchain.
+	TxId string `protobuf:"bytes,8,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	//
+	// Account is the external account to which watched address is mapped.
+	Account string `protobuf:"bytes,9,opt,name=account" json:"account,omitempty"`
+}
+
+func (m *WatchEvent) Reset()                    { *m = WatchEvent{} }
+func (m *WatchEvent) String() string            { return proto.CompactTextString(m) }
+func (*WatchEvent) ProtoMessage()               {}
+func (*WatchEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
+
+func (m *WatchEvent) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+func (m *WatchEvent) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *WatchEvent) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *WatchEvent) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *WatchEvent) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *WatchEvent) GetDirection() PaymentDirection {
+	if m != nil {
+		return m.Direction
+	}
+	return PaymentDirection_DIRECTION_NONE
+}
+
+func (m *WatchEvent) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *WatchEvent) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *WatchEvent) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+type ListWatchEventsRequest struct {
+	//
+	// (optional) Group is the label of the group of addresses.
+	Group string `protobuf:"bytes,1,opt,name=group" json:"group,omitempty"`
+}
+
+func (m *ListWatchEventsRequest) Reset()                    { *m = ListWatchEventsRequest{} }
+func (m *ListWatchEventsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListWatchEventsRequest) ProtoMessage()               {}
+func (*ListWatchEventsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
+
+func (m *ListWatchEventsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+type ListWatchEventsResponse struct {
+	Events []*WatchEvent `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
+}
+
+func (m *ListWatchEventsResponse) Reset()                    { *m = ListWatchEventsResponse{} }
+func (m *ListWatchEventsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListWatchEventsResponse) ProtoMessage()               {}
+func (*ListWatchEventsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+
+func (m *ListWatchEventsResponse) GetEvents() []*WatchEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type RedeliverWatchEventsRequest struct {
+	//
+	// (optional) Group is the label of the group of addresses, events of
+	// all groups are redelivered if it isn't specified.
+	Group string `protobuf:"bytes,1,opt,name=group" json:"group,omitempty"`
+	//
+	// (optional) CreatedFrom is the time in milliseconds from which
+	// events are redelivered, inclusive.
+	CreatedFrom int64 `protobuf:"varint,2,opt,name=created_from,json=createdFrom" json:"created_from,omitempty"`
+	//
+	// (optional) CreatedTo is the time in milliseconds until which
+	// events are redelivered, inclusive.
+	CreatedTo int64 `protobuf:"varint,3,opt,name=created_to,json=createdTo" json:"created_to,omitempty"`
+}
+
+func (m *RedeliverWatchEventsRequest) Reset()                    { *m = RedeliverWatchEventsRequest{} }
+func (m *RedeliverWatchEventsRequest) String() string            { return proto.CompactTextString(m) }
+func (*RedeliverWatchEventsRequest) ProtoMessage()               {}
+func (*RedeliverWatchEventsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
+
+func (m *RedeliverWatchEventsRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *RedeliverWatchEventsRequest) GetCreatedFrom() int64 {
+	if m != nil {
+		return m.CreatedFrom
+	}
+	return 0
+}
+
+func (m *RedeliverWatchEventsRequest) GetCreatedTo() int64 {
+	if m != nil {
+		return m.CreatedTo
+	}
+	return 0
+}
+
+type RedeliverWatchEventsResponse struct {
+	//
+	// Events is the number of the events which are going to be redelivered.
+	Events uint32 `protobuf:"varint,1,opt,name=events" json:"events,omitempty"`
+}
+
+func (m *RedeliverWatchEventsResponse) Reset()                    { *m = RedeliverWatchEventsResponse{} }
+func (m *RedeliverWatchEventsResponse) String() string            { return proto.CompactTextString(m) }
+func (*RedeliverWatchEventsResponse) ProtoMessage()               {}
+func (*RedeliverWatchEventsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+
+func (m *RedeliverWatchEventsResponse) GetEvents() uint32 {
+	if m != nil {
+		return m.Events
+	}
+	return 0
+}
+
+type SyncUnspentRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Force denotes that state should be synced from the scratch.
+	Force bool `protobuf:"varint,2,opt,name=force" json:"force,omitempty"`
+}
+
+func (m *SyncUnspentRequest) Reset()                    { *m = SyncUnspentRequest{} }
+func (m *SyncUnspentRequest) String() string            { return proto.CompactTextString(m) }
+func (*SyncUnspentRequest) ProtoMessage()               {}
+func (*SyncUnspentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+
+func (m *SyncUnspentRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *SyncUnspentRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+type GetUnspentSyncStatusRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+}
+
+func (m *GetUnspentSyncStatusRequest) Reset()                    { *m = GetUnspentSyncStatusRequest{} }
+func (m *GetUnspentSyncStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetUnspentSyncStatusRequest) ProtoMessage()               {}
+func (*GetUnspentSyncStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
+
+func (m *GetUnspentSyncStatusRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+type UnspentSyncStatus struct {
+	//
+	// LastSyncAt is the time of the last successful sync in unix
+	// milliseconds, zero if sync hasn't been done yet.
+	LastSyncAt int64 `protobuf:"varint,1,opt,name=last_sync_at,json=lastSyncAt" json:"last_sync_at,omitempty"`
+	//
+	// UnspentOutputs is the number of the confirmed wallet unspent outputs,
+	// as they are counted by the daemon.
+	UnspentOutputs int64 `protobuf:"varint,2,opt,name=unspent_outputs,json=unspentOutputs" json:"unspent_outputs,omitempty"`
+	//
+	// Inconsistencies is the list of found differences between the synced
+	// state and the state of the daemon.
+	Inconsistencies []string `protobuf:"bytes,3,rep,name=inconsistencies" json:"inconsistencies,omitempty"`
+}
+
+func (m *UnspentSyncStatus) Reset()                    { *m = UnspentSyncStatus{} }
+func (m *UnspentSyncStatus) String() string            { return proto.CompactTextString(m) }
+func (*UnspentSyncStatus) ProtoMessage()               {}
+func (*UnspentSyncStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
+
+func (m *UnspentSyncStatus) GetLastSyncAt() int64 {
+	if m != nil {
+		return m.LastSyncAt
+	}
+	return 0
+}
+
+func (m *UnspentSyncStatus) GetUnspentOutputs() int64 {
+	if m != nil {
+		return m.UnspentOutputs
+	}
+	return 0
+}
+
+func (m *UnspentSyncStatus) GetInconsistencies() []string {
+	if m != nil {
+		return m.Inconsistencies
+	}
+	return nil
+}
+
+type ListUnspentRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+}
+
+func (m *ListUnspentRequest) Reset()                    { *m = ListUnspentRequest{} }
+func (m *ListUnspentRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListUnspentRequest) ProtoMessage()               {}
+func (*ListUnspentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
+
+func (m *ListUnspentRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+type UnspentOutput struct {
+	//
+	// TxID is the id of the transaction which created the output.
+	TxId string `protobuf:"bytes,1,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	//
+	// Vout is the index of the output in the transaction.
+	Vout uint32 `protobuf:"varint,2,opt,name=vout" json:"vout,omitempty"`
+	//
+	// Address is the wallet address to which output is paid.
+	Address string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+	//
+	// Amount is the value of the output.
+	Amount string `protobuf:"bytes,4,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Confirmations is the number of blocks which confirm the output.
+	Confirmations int64 `protobuf:"varint,5,opt,name=confirmations" json:"confirmations,omitempty"`
+	//
+	// Locked denotes that output is locked by the payment which is being
+	// sent, and it couldn't be selected for the other payments.
+	Locked bool `protobuf:"varint,6,opt,name=locked" json:"locked,omitempty"`
+}
+
+func (m *UnspentOutput) Reset()                    { *m = UnspentOutput{} }
+func (m *UnspentOutput) String() string            { return proto.CompactTextString(m) }
+func (*UnspentOutput) ProtoMessage()               {}
+func (*UnspentOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{89} }
+
+func (m *UnspentOutput) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *UnspentOutput) GetVout() uint32 {
+	if m != nil {
+		return m.Vout
+	}
+	return 0
+}
+
+func (m *UnspentOutput) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *UnspentOutput) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *UnspentOutput) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+func (m *UnspentOutput) GetLocked() bool {
+	if m != nil {
+		return m.Locked
+	}
+	return false
+}
+
+type ListUnspentResponse struct {
+	Outputs []*UnspentOutput `protobuf:"bytes,1,rep,name=outputs" json:"outputs,omitempty"`
+	//
+	// Total is the overall value of the outputs.
+	Total string `protobuf:"bytes,2,opt,name=total" json:"total,omitempty"`
+}
+
+func (m *ListUnspentResponse) Reset()                    { *m = ListUnspentResponse{} }
+func (m *ListUnspentResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListUnspentResponse) ProtoMessage()               {}
+func (*ListUnspentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{90} }
+
+func (m *ListUnspentResponse) GetOutputs() []*UnspentOutput {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *ListUnspentResponse) GetTotal() string {
+	if m != nil {
+		return m.Total
+	}
+	return ""
+}
+
+type IsOurAddressRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Address is the blockchain address which should be checked.
+	Address string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *IsOurAddressRequest) Reset()                    { *m = IsOurAddressRequest{} }
+func (m *IsOurAddressRequest) String() string            { return proto.CompactTextString(m) }
+func (*IsOurAddressRequest) ProtoMessage()               {}
+func (*IsOurAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{91} }
+
+func (m *IsOurAddressRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *IsOurAddressRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type IsOurAddressResponse struct {
+	//
+	// Address is the canonical form of the checked address.
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	//
+	// IsOurs denotes that address belongs to the wallet of the server.
+	IsOurs bool `protobuf:"varint,2,opt,name=is_ours,json=isOurs" json:"is_ours,omitempty"`
+	//
+	// Tenant is the id of the API key on behalf of which address has been
+	// created, empty if address isn't issued by the server or API keys are
+	// not used.
+	Tenant string `protobuf:"bytes,3,opt,name=tenant" json:"tenant,omitempty"`
+	//
+	// AccountId is the identifier of the account to which incoming
+	// payments on the address are bound.
+	AccountId string `protobuf:"bytes,4,opt,name=account_id,json=accountId" json:"account_id,omitempty"`
+	//
+	// CreatedAt is the time of the address issuance in milliseconds, zero
+	// if address isn't issued by the server.
+	CreatedAt int64 `protobuf:"varint,5,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+}
+
+func (m *IsOurAddressResponse) Reset()                    { *m = IsOurAddressResponse{} }
+func (m *IsOurAddressResponse) String() string            { return proto.CompactTextString(m) }
+func (*IsOurAddressResponse) ProtoMessage()               {}
+func (*IsOurAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{92} }
+
+func (m *IsOurAddressResponse) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *IsOurAddressResponse) GetIsOurs() bool {
+	if m != nil {
+		return m.IsOurs
+	}
+	return false
+}
+
+func (m *IsOurAddressResponse) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
+func (m *IsOurAddressResponse) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *IsOurAddressResponse) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type SignMessageRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// (optional) Address is the blockchain address of the wallet with which
+	// key message is signed, it is required for the blockchain media, and
+	// isn't used for the lightning media, where the node key is used.
+	Address string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+	//
+	// Message is the message which should be signed.
+	Message string `protobuf:"bytes,4,opt,name=message" json:"message,omitempty"`
+}
+
+func (m *SignMessageRequest) Reset()                    { *m = SignMessageRequest{} }
+func (m *SignMessageRequest) String() string            { return proto.CompactTextString(m) }
+func (*SignMessageRequest) ProtoMessage()               {}
+func (*SignMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{93} }
+
+func (m *SignMessageRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *SignMessageRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *SignMessageRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *SignMessageRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type SignMessageResponse struct {
+	//
+	// Signature is the signature of the message, base64 encoded for the
+	// blockchain media and zbase32 encoded for the lightning media.
+	Signature string `protobuf:"bytes,1,opt,name=signature" json:"signature,omitempty"`
+	//
+	// Signer is the canonical form of the address, or the public key of
+	// the lightning node, which has signed the message.
+	Signer string `protobuf:"bytes,2,opt,name=signer" json:"signer,omitempty"`
+}
+
+func (m *SignMessageResponse) Reset()                    { *m = SignMessageResponse{} }
+func (m *SignMessageResponse) String() string            { return proto.CompactTextString(m) }
+func (*SignMessageResponse) ProtoMessage()               {}
+func (*SignMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{94} }
+
+func (m *SignMessageResponse) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
+func (m *SignMessageResponse) GetSigner() string {
+	if m != nil {
+		return m.Signer
+	}
+	return ""
+}
+
+type VerifyMessageRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// (optional) Address is the blockchain address which should have
+	// signed the message, it is required for the blockchain media. For the
+	// lightning media signer is recovered from the signature.
+	Address string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+	//
+	// Message is the message which has been signed.
+	Message string `protobuf:"bytes,4,opt,name=message" json:"message,omitempty"`
+	//
+	// Signature is the signature of the message.
+	Signature string `protobuf:"bytes,5,opt,name=signature" json:"signature,omitempty"`
+}
+
+func (m *VerifyMessageRequest) Reset()                    { *m = VerifyMessageRequest{} }
+func (m *VerifyMessageRequest) String() string            { return proto.CompactTextString(m) }
+func (*VerifyMessageRequest) ProtoMessage()               {}
+func (*VerifyMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{95} }
+
+func (m *VerifyMessageRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *VerifyMessageRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *VerifyMessageRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *VerifyMessageRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *VerifyMessageRequest) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
+type VerifyMessageResponse struct {
+	//
+	// Valid denotes that signature is valid. Lightning signature is valid
+	// only if signer is known to the node, i.e. it is the node itself or
+	// the node from the channel graph.
+	Valid bool `protobuf:"varint,1,opt,name=valid" json:"valid,omitempty"`
+	//
+	// Signer is the canonical form of the address, or the public key of
+	// the lightning node recovered from the signature.
+	Signer string `protobuf:"bytes,2,opt,name=signer" json:"signer,omitempty"`
+}
+
+func (m *VerifyMessageResponse) Reset()                    { *m = VerifyMessageResponse{} }
+func (m *VerifyMessageResponse) String() string            { return proto.CompactTextString(m) }
+func (*VerifyMessageResponse) ProtoMessage()               {}
+func (*VerifyMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{96} }
+
+func (m *VerifyMessageResponse) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+func (m *VerifyMessageResponse) GetSigner() string {
+	if m != nil {
+		return m.Signer
+	}
+	return ""
+}
+
+type TransactionByHashRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// TxID is the id of the wallet transaction.
+	TxId string `protobuf:"bytes,2,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+}
+
+func (m *TransactionByHashRequest) Reset()                    { *m = TransactionByHashRequest{} }
+func (m *TransactionByHashRequest) String() string            { return proto.CompactTextString(m) }
+func (*TransactionByHashRequest) ProtoMessage()               {}
+func (*TransactionByHashRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{97} }
+
+func (m *TransactionByHashRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *TransactionByHashRequest) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+type TransactionInput struct {
+	//
+	// TxID is the id of the transaction which created the spent output.
+	TxId string `protobuf:"bytes,1,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	//
+	// Vout is the index of the spent output in its transaction.
+	Vout uint32 `protobuf:"varint,2,opt,name=vout" json:"vout,omitempty"`
+}
+
+func (m *TransactionInput) Reset()                    { *m = TransactionInput{} }
+func (m *TransactionInput) String() string            { return proto.CompactTextString(m) }
+func (*TransactionInput) ProtoMessage()               {}
+func (*TransactionInput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{98} }
+
+func (m *TransactionInput) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *TransactionInput) GetVout() uint32 {
+	if m != nil {
+		return m.Vout
+	}
+	return 0
+}
+
+type TransactionOutput struct {
+	//
+	// Vout is the index of the output in the transaction.
+	Vout uint32 `protobuf:"varint,1,opt,name=vout" json:"vout,omitempty"`
+	//
+	// Address is the address to which output is paid, it is empty if
+	// output script isn't the standard one, e.g. OP_RETURN output.
+	Address string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	//
+	// Amount is the value of the output.
+	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+}
+
+func (m *TransactionOutput) Reset()                    { *m = TransactionOutput{} }
+func (m *TransactionOutput) String() string            { return proto.CompactTextString(m) }
+func (*TransactionOutput) ProtoMessage()               {}
+func (*TransactionOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{99} }
+
+func (m *TransactionOutput) GetVout() uint32 {
+	if m != nil {
+		return m.Vout
+	}
+	return 0
+}
+
+func (m *TransactionOutput) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *TransactionOutput) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+type Transaction struct {
+	//
+	// TxID is the id of the transaction.
+	TxId string `protobuf:"bytes,1,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	//
+	// BlockHash is the hash of the block in which transaction is
+	// included, it is empty if transaction is unconfirmed.
+	BlockHash string `protobuf:"bytes,2,opt,name=block_hash,json=blockHash" json:"block_hash,omitempty"`
+	//
+	// BlockTime is the time in milliseconds of the block in which
+	// transaction is included.
+	BlockTime int64 `protobuf:"varint,3,opt,name=block_time,json=blockTime" json:"block_time,omitempty"`
+	//
+	// Confirmations is the number of blocks which confirm the
+	// transaction, it is negative if transaction conflicts with the
+	// confirmed one.
+	Confirmations int64 `protobuf:"varint,4,opt,name=confirmations" json:"confirmations,omitempty"`
+	//
+	// Fee is the fee paid by the transaction, it is known only for the
+	// transactions sent by the wallet.
+	Fee     string               `protobuf:"bytes,5,opt,name=fee" json:"fee,omitempty"`
+	Inputs  []*TransactionInput  `protobuf:"bytes,6,rep,name=inputs" json:"inputs,omitempty"`
+	Outputs []*TransactionOutput `protobuf:"bytes,7,rep,name=outputs" json:"outputs,omitempty"`
+	//
+	// RawTx is the hex encoded serialized transaction.
+	RawTx string `protobuf:"bytes,8,opt,name=raw_tx,json=rawTx" json:"raw_tx,omitempty"`
+	//
+	// PaymentIds are the ids of the payments made by the transaction.
+	PaymentIds []string `protobuf:"bytes,9,rep,name=payment_ids,json=paymentIds" json:"payment_ids,omitempty"`
+}
+
+func (m *Transaction) Reset()                    { *m = Transaction{} }
+func (m *Transaction) String() string            { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()               {}
+func (*Transaction) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{100} }
+
+func (m *Transaction) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *Transaction) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *Transaction) GetBlockTime() int64 {
+	if m != nil {
+		return m.BlockTime
+	}
+	return 0
+}
+
+func (m *Transaction) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+func (m *Transaction) GetFee() string {
+	if m != nil {
+		return m.Fee
+	}
+	return ""
+}
+
+func (m *Transaction) GetInputs() []*TransactionInput {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *Transaction) GetOutputs() []*TransactionOutput {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *Transaction) GetRawTx() string {
+	if m != nil {
+		return m.RawTx
+	}
+	return ""
+}
+
+func (m *Transaction) GetPaymentIds() []string {
+	if m != nil {
+		return m.PaymentIds
+	}
+	return nil
+}
+
+type TransferToPeerRequest struct {
+	//
+	// Peer is the name of the federation peer.
+	Peer string `protobuf:"bytes,1,opt,name=peer" json:"peer,omitempty"`
+	//
+	// Asset is an acronym of the crypto currency which is transferred,
+	// only blockchain balance could be transferred.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// FromAccount is the identifier of the local account from which funds
+	// are taken, its balance should cover the amount.
+	FromAccount string `protobuf:"bytes,3,opt,name=from_account,json=fromAccount" json:"from_account,omitempty"`
+	//
+	// ToAccount is the identifier of the account on the peer server to
+	// which funds are moved.
+	ToAccount string `protobuf:"bytes,4,opt,name=to_account,json=toAccount" json:"to_account,omitempty"`
+	//
+	// Amount is the amount of the transfer.
+	Amount string `protobuf:"bytes,5,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Memo is the description of the transfer.
+	Memo string `protobuf:"bytes,6,opt,name=memo" json:"memo,omitempty"`
+}
+
+func (m *TransferToPeerRequest) Reset()                    { *m = TransferToPeerRequest{} }
+func (m *TransferToPeerRequest) String() string            { return proto.CompactTextString(m) }
+func (*TransferToPeerRequest) ProtoMessage()               {}
+func (*TransferToPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{101} }
+
+func (m *TransferToPeerRequest) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *TransferToPeerRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *TransferToPeerRequest) GetFromAccount() string {
+	if m != nil {
+		return m.FromAccount
+	}
+	return ""
+}
+
+func (m *TransferToPeerRequest) GetToAccount() string {
+	if m != nil {
+		return m.ToAccount
+	}
+	return ""
+}
+
+func (m *TransferToPeerRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *TransferToPeerRequest) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+type FederationTransfer struct {
+	//
+	// TransferId is the id of the transfer, which is shared by both
+	// servers.
+	TransferId string `protobuf:"bytes,1,opt,name=transfer_id,json=transferId" json:"transfer_id,omitempty"`
+	//
+	// Peer is the name of the federation peer.
+	Peer string `protobuf:"bytes,2,opt,name=peer" json:"peer,omitempty"`
+	//
+	// Asset is an acronym of the crypto currency.
+	Asset Asset `protobuf:"varint,3,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Account is the local account which is debited by the transfer.
+	Account string `protobuf:"bytes,4,opt,name=account" json:"account,omitempty"`
+	//
+	// PeerAccount is the account on the peer server which is credited by
+	// the transfer.
+	PeerAccount string `protobuf:"bytes,5,opt,name=peer_account,json=peerAccount" json:"peer_account,omitempty"`
+	//
+	// Amount is the amount of the transfer.
+	Amount string `protobuf:"bytes,6,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Status is the stage of the transfer processing.
+	Status FederationStatus `protobuf:"varint,7,opt,name=status,enum=crpc.FederationStatus" json:"status,omitempty"`
+	//
+	// PaymentId is the id of the internal payment which debits the
+	// account.
+	PaymentId string `protobuf:"bytes,8,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+	//
+	// FailureReason is the reason of the rejection by the peer.
+	FailureReason string `protobuf:"bytes,9,opt,name=failure_reason,json=failureReason" json:"failure_reason,omitempty"`
+	//
+	// CreatedAt is the time of the transfer in milliseconds.
+	CreatedAt int64 `protobuf:"varint,10,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+}
+
+func (m *FederationTransfer) Reset()                    { *m = FederationTransfer{} }
+func (m *FederationTransfer) String() string            { return proto.CompactTextString(m) }
+func (*FederationTransfer) ProtoMessage()               {}
+func (*FederationTransfer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{102} }
+
+func (m *FederationTransfer) GetTransferId() string {
+	if m != nil {
+		return m.TransferId
+	}
+	return ""
+}
+
+func (m *FederationTransfer) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *FederationTransfer) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *FederationTransfer) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *FederationTransfer) GetPeerAccount() string {
+	if m != nil {
+		return m.PeerAccount
+	}
+	return ""
+}
+
+func (m *FederationTransfer) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *FederationTransfer) GetStatus() FederationStatus {
+	if m != nil {
+		return m.Status
+	}
+	return FederationStatus_FEDERATION_STATUS_NONE
+}
+
+func (m *FederationTransfer) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *FederationTransfer) GetFailureReason() string {
+	if m != nil {
+		return m.FailureReason
+	}
+	return ""
+}
+
+func (m *FederationTransfer) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type FederationPosition struct {
+	//
+	// Peer is the name of the federation peer.
+	Peer string `protobuf:"bytes,1,opt,name=peer" json:"peer,omitempty"`
+	//
+	// Asset is an acronym of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Amount is the net position with the peer, it is positive if server
+	// owes the peer, and negative if peer owes the server.
+	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	//
+	// PendingTransfers is the number of the outgoing transfers which
+	// haven't been acknowledged by the peer yet, they are not included in
+	// the position.
+	PendingTransfers int64 `protobuf:"varint,4,opt,name=pending_transfers,json=pendingTransfers" json:"pending_transfers,omitempty"`
+}
+
+func (m *FederationPosition) Reset()                    { *m = FederationPosition{} }
+func (m *FederationPosition) String() string            { return proto.CompactTextString(m) }
+func (*FederationPosition) ProtoMessage()               {}
+func (*FederationPosition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{103} }
+
+func (m *FederationPosition) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *FederationPosition) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *FederationPosition) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *FederationPosition) GetPendingTransfers() int64 {
+	if m != nil {
+		return m.PendingTransfers
+	}
+	return 0
+}
+
+type ListFederationPositionsResponse struct {
+	Positions []*FederationPosition `protobuf:"bytes,1,rep,name=positions" json:"positions,omitempty"`
+}
+
+func (m *ListFederationPositionsResponse) Reset()         { *m = ListFederationPositionsResponse{} }
+func (m *ListFederationPositionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListFederationPositionsResponse) ProtoMessage()    {}
+func (*ListFederationPositionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{103}
+}
+
+func (m *ListFederationPositionsResponse) GetPositions() []*FederationPosition {
+	if m != nil {
+		return m.Positions
+	}
+	return nil
+}
+
+type SettleFederationRequest struct {
+	//
+	// Peer is the name of the federation peer.
+	Peer string `protobuf:"bytes,1,opt,name=peer" json:"peer,omitempty"`
+	//
+	// Asset is an acronym of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+}
+
+func (m *SettleFederationRequest) Reset()                    { *m = SettleFederationRequest{} }
+func (m *SettleFederationRequest) String() string            { return proto.CompactTextString(m) }
+func (*SettleFederationRequest) ProtoMessage()               {}
+func (*SettleFederationRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{105} }
+
+func (m *SettleFederationRequest) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *SettleFederationRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+type FederatedTransfer struct {
+	//
+	// TransferId is the unique id of the transfer generated by the
+	// sending server.
+	TransferId string `protobuf:"bytes,1,opt,name=transfer_id,json=transferId" json:"transfer_id,omitempty"`
+	//
+	// FromAccount is the account on the sending server which has been
+	// debited.
+	FromAccount string `protobuf:"bytes,2,opt,name=from_account,json=fromAccount" json:"from_account,omitempty"`
+	//
+	// ToAccount is the account on the receiving server which should be
+	// credited.
+	ToAccount string `protobuf:"bytes,3,opt,name=to_account,json=toAccount" json:"to_account,omitempty"`
+	//
+	// Asset is an acronym of the crypto currency.
+	Asset Asset `protobuf:"varint,4,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Amount is the amount of the transfer.
+	Amount string `protobuf:"bytes,5,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Memo is the description of the transfer.
+	Memo string `protobuf:"bytes,6,opt,name=memo" json:"memo,omitempty"`
+}
+
+func (m *FederatedTransfer) Reset()                    { *m = FederatedTransfer{} }
+func (m *FederatedTransfer) String() string            { return proto.CompactTextString(m) }
+func (*FederatedTransfer) ProtoMessage()               {}
+func (*FederatedTransfer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{106} }
+
+func (m *FederatedTransfer) GetTransferId() string {
+	if m != nil {
+		return m.TransferId
+	}
+	return ""
+}
+
+func (m *FederatedTransfer) GetFromAccount() string {
+	if m != nil {
+		return m.FromAccount
+	}
+	return ""
+}
+
+func (m *FederatedTransfer) GetToAccount() string {
+	if m != nil {
+		return m.ToAccount
+	}
+	return ""
+}
+
+func (m *FederatedTransfer) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *FederatedTransfer) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *FederatedTransfer) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+type ReceiveTransferResponse struct {
+	//
+	// Accepted denotes that account has been credited, transfer which
+	// isn't accepted is never credited and shouldn't be retried.
+	Accepted bool `protobuf:"varint,1,opt,name=accepted" json:"accepted,omitempty"`
+	//
+	// Reason is the reason of the rejection.
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *ReceiveTransferResponse) Reset()                    { *m = ReceiveTransferResponse{} }
+func (m *ReceiveTransferResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReceiveTransferResponse) ProtoMessage()               {}
+func (*ReceiveTransferResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{107} }
+
+func (m *ReceiveTransferResponse) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *ReceiveTransferResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type FederatedSettlement struct {
+	//
+	// SettlementId is the unique id of the settlement generated by the
+	// sending server.
+	SettlementId string `protobuf:"bytes,1,opt,name=settlement_id,json=settlementId" json:"settlement_id,omitempty"`
+	//
+	// Asset is an acronym of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Amount is the amount which has been sent.
+	Amount string `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	//
+	// TxId is the id of the settlement transaction.
+	TxId string `protobuf:"bytes,4,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+}
+
+func (m *FederatedSettlement) Reset()                    { *m = FederatedSettlement{} }
+func (m *FederatedSettlement) String() string            { return proto.CompactTextString(m) }
+func (*FederatedSettlement) ProtoMessage()               {}
+func (*FederatedSettlement) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{108} }
+
+func (m *FederatedSettlement) GetSettlementId() string {
+	if m != nil {
+		return m.SettlementId
+	}
+	return ""
+}
+
+func (m *FederatedSettlement) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *FederatedSettlement) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *FederatedSettlement) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+type SettlementAddressRequest struct {
+	//
+	// Asset is an acronym of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+}
+
+func (m *SettlementAddressRequest) Reset()                    { *m = SettlementAddressRequest{} }
+func (m *SettlementAddressRequest) String() string            { return proto.CompactTextString(m) }
+func (*SettlementAddressRequest) ProtoMessage()               {}
+func (*SettlementAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{109} }
+
+func (m *SettlementAddressRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+type SettlementAddressResponse struct {
+	//
+	// Address is the blockchain address to which settlement is sent.
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *SettlementAddressResponse) Reset()                    { *m = SettlementAddressResponse{} }
+func (m *SettlementAddressResponse) String() string            { return proto.CompactTextString(m) }
+func (*SettlementAddressResponse) ProtoMessage()               {}
+func (*SettlementAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{110} }
+
+func (m *SettlementAddressResponse) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type SweepFundsRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Address is the blockchain address on which funds are sent.
+	Address string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *SweepFundsRequest) Reset()                    { *m = SweepFundsRequest{} }
+func (m *SweepFundsRequest) String() string            { return proto.CompactTextString(m) }
+func (*SweepFundsRequest) ProtoMessage()               {}
+func (*SweepFundsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{111} }
+
+func (m *SweepFundsRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *SweepFundsRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *SweepFundsRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type FeeReportRequest struct {
+	//
+	// (optional) Asset is an acronim of the crypto currency, by default
+	// fees of all assets are reported.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Period is the length of the report windows.
+	Period FeeReportPeriod `protobuf:"varint,2,opt,name=period,enum=crpc.FeeReportPeriod" json:"period,omitempty"`
+	//
+	// (optional) From is the time in milliseconds from which fees are
+	// reported, it is rounded down to the start of the window. By default
+	// only the window of the "to" time is reported.
+	From int64 `protobuf:"varint,3,opt,name=from" json:"from,omitempty"`
+	//
+	// (optional) To is the time in milliseconds until which fees are
+	// reported, inclusive, by default it is the current time.
+	To int64 `protobuf:"varint,4,opt,name=to" json:"to,omitempty"`
+}
+
+func (m *FeeReportRequest) Reset()                    { *m = FeeReportRequest{} }
+func (m *FeeReportRequest) String() string            { return proto.CompactTextString(m) }
+func (*FeeReportRequest) ProtoMessage()               {}
+func (*FeeReportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{112} }
+
+func (m *FeeReportRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *FeeReportRequest) GetPeriod() FeeReportPeriod {
+	if m != nil {
+		return m.Period
+	}
+	return FeeReportPeriod_FEE_REPORT_DAY
+}
+
+func (m *FeeReportRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *FeeReportRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+type FeeTotal struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Fees is the sum of the network fees paid.
+	Fees string `protobuf:"bytes,3,opt,name=fees" json:"fees,omitempty"`
+	//
+	// Amount is the sum of the amounts sent, without the fees.
+	Amount string `protobuf:"bytes,4,opt,name=amount" json:"amount,omitempty"`
+	//
+	// Payments is the number of the sent payments.
+	Payments uint64 `protobuf:"varint,5,opt,name=payments" json:"payments,omitempty"`
+	//
+	// Transactions is the number of the distinct blockchain transactions,
+	// or lightning network payments, in which payments have been sent.
+	// Batched payments share the transaction, and therefore its fee.
+	Transactions uint64 `protobuf:"varint,6,opt,name=transactions" json:"transactions,omitempty"`
+}
+
+func (m *FeeTotal) Reset()                    { *m = FeeTotal{} }
+func (m *FeeTotal) String() string            { return proto.CompactTextString(m) }
+func (*FeeTotal) ProtoMessage()               {}
+func (*FeeTotal) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{113} }
+
+func (m *FeeTotal) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *FeeTotal) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *FeeTotal) GetFees() string {
+	if m != nil {
+		return m.Fees
+	}
+	return ""
+}
+
+func (m *FeeTotal) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *FeeTotal) GetPayments() uint64 {
+	if m != nil {
+		return m.Payments
+	}
+	return 0
+}
+
+func (m *FeeTotal) GetTransactions() uint64 {
+	if m != nil {
+		return m.Transactions
+	}
+	return 0
+}
+
+type FeeWindow struct {
+	//
+	// Start is the time in milliseconds of the window start, inclusive.
+	Start int64 `protobuf:"varint,1,opt,name=start" json:"start,omitempty"`
+	//
+	// End is the time in milliseconds of the window end, exclusive.
+	End int64 `protobuf:"varint,2,opt,name=end" json:"end,omitempty"`
+	//
+	// Totals are the fees paid within the window per asset and media,
+	// empty if nothing has been sent.
+	Totals []*FeeTotal `protobuf:"bytes,3,rep,name=totals" json:"totals,omitempty"`
+}
+
+func (m *FeeWindow) Reset()                    { *m = FeeWindow{} }
+func (m *FeeWindow) String() string            { return proto.CompactTextString(m) }
+func (*FeeWindow) ProtoMessage()               {}
+func (*FeeWindow) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{114} }
+
+func (m *FeeWindow) GetStart() int64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *FeeWindow) GetEnd() int64 {
+	if m != nil {
+		return m.End
+	}
+	return 0
+}
+
+func (m *FeeWindow) GetTotals() []*FeeTotal {
+	if m != nil {
+		return m.Totals
+	}
+	return nil
+}
+
+type FeeReportResponse struct {
+	//
+	// Windows are the report windows from the oldest one, including the
+	// ones in which nothing has been sent.
+	Windows []*FeeWindow `protobuf:"bytes,1,rep,name=windows" json:"windows,omitempty"`
+	//
+	// Totals are the fees paid within all windows per asset and media.
+	Totals []*FeeTotal `protobuf:"bytes,2,rep,name=totals" json:"totals,omitempty"`
+}
+
+func (m *FeeReportResponse) Reset()                    { *m = FeeReportResponse{} }
+func (m *FeeReportResponse) String() string            { return proto.CompactTextString(m) }
+func (*FeeReportResponse) ProtoMessage()               {}
+func (*FeeReportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{115} }
+
+func (m *FeeReportResponse) GetWindows() []*FeeWindow {
+	if m != nil {
+		return m.Windows
+	}
+	return nil
+}
+
+func (m *FeeReportResponse) GetTotals() []*FeeTotal {
+	if m != nil {
+		return m.Totals
+	}
+	return nil
+}
+
+type PauseWithdrawalsRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Reason is the description of the pause, which is returned to the
+	// senders of the rejected payments.
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *PauseWithdrawalsRequest) Reset()                    { *m = PauseWithdrawalsRequest{} }
+func (m *PauseWithdrawalsRequest) String() string            { return proto.CompactTextString(m) }
+func (*PauseWithdrawalsRequest) ProtoMessage()               {}
+func (*PauseWithdrawalsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{116} }
+
+func (m *PauseWithdrawalsRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *PauseWithdrawalsRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type ResumeWithdrawalsRequest struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+}
+
+func (m *ResumeWithdrawalsRequest) Reset()                    { *m = ResumeWithdrawalsRequest{} }
+func (m *ResumeWithdrawalsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResumeWithdrawalsRequest) ProtoMessage()               {}
+func (*ResumeWithdrawalsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{117} }
+
+func (m *ResumeWithdrawalsRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+type WithdrawalPause struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Reason is the description of the pause.
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+	//
+	// PausedAt is the time of the pause in milliseconds.
+	PausedAt int64 `protobuf:"varint,3,opt,name=paused_at,json=pausedAt" json:"paused_at,omitempty"`
+}
+
+func (m *WithdrawalPause) Reset()                    { *m = WithdrawalPause{} }
+func (m *WithdrawalPause) String() string            { return proto.CompactTextString(m) }
+func (*WithdrawalPause) ProtoMessage()               {}
+func (*WithdrawalPause) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{118} }
+
+func (m *WithdrawalPause) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *WithdrawalPause) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *WithdrawalPause) GetPausedAt() int64 {
+	if m != nil {
+		return m.PausedAt
+	}
+	return 0
+}
+
+type ListWithdrawalPausesResponse struct {
+	Pauses []*WithdrawalPause `protobuf:"bytes,1,rep,name=pauses" json:"pauses,omitempty"`
+}
+
+func (m *ListWithdrawalPausesResponse) Reset()                    { *m = ListWithdrawalPausesResponse{} }
+func (m *ListWithdrawalPausesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListWithdrawalPausesResponse) ProtoMessage()               {}
+func (*ListWithdrawalPausesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{119} }
+
+func (m *ListWithdrawalPausesResponse) GetPauses() []*WithdrawalPause {
+	if m != nil {
+		return m.Pauses
+	}
+	return nil
+}
+
+type BalanceInvariant struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Ledger is the amount which should be held by the server according
+	// to the payments, in both media.
+	Ledger string `protobuf:"bytes,2,opt,name=ledger" json:"ledger,omitempty"`
+	//
+	// Holdings is the confirmed and pending balance of the blockchain
+	// wallet and the lightning network node.
+	Holdings string `protobuf:"bytes,3,opt,name=holdings" json:"holdings,omitempty"`
+	//
+	// Shortfall is the amount by which holdings are below the ledger, zero
+	// if holdings cover the ledger.
+	Shortfall string `protobuf:"bytes,4,opt,name=shortfall" json:"shortfall,omitempty"`
+	//
+	// Error is the reason why invariant couldn't be checked, e.g. daemon
+	// is unreachable, in this case amounts are empty.
+	Error string `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *BalanceInvariant) Reset()                    { *m = BalanceInvariant{} }
+func (m *BalanceInvariant) String() string            { return proto.CompactTextString(m) }
+func (*BalanceInvariant) ProtoMessage()               {}
+func (*BalanceInvariant) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{120} }
+
+func (m *BalanceInvariant) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *BalanceInvariant) GetLedger() string {
+	if m != nil {
+		return m.Ledger
+	}
+	return ""
+}
+
+func (m *BalanceInvariant) GetHoldings() string {
+	if m != nil {
+		return m.Holdings
+	}
+	return ""
+}
+
+func (m *BalanceInvariant) GetShortfall() string {
+	if m != nil {
+		return m.Shortfall
+	}
+	return ""
+}
+
+func (m *BalanceInvariant) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type BalanceInvariantsResponse struct {
+	Invariants []*BalanceInvariant `protobuf:"bytes,1,rep,name=invariants" json:"invariants,omitempty"`
+	//
+	// SendsSuspended is true if sends circuit breaker is tripped, and all
+	// outgoing payments are rejected.
+	SendsSuspended bool `protobuf:"varint,2,opt,name=sends_suspended,json=sendsSuspended" json:"sends_suspended,omitempty"`
+	//
+	// SuspensionReason is the description of the violated invariant which
+	// has tripped the breaker.
+	SuspensionReason string `protobuf:"bytes,3,opt,name=suspension_reason,json=suspensionReason" json:"suspension_reason,omitempty"`
+	//
+	// SuspendedAt is the time when breaker was tripped in milliseconds.
+	SuspendedAt int64 `protobuf:"varint,4,opt,name=suspended_at,json=suspendedAt" json:"suspended_at,omitempty"`
+}
+
+func (m *BalanceInvariantsResponse) Reset()                    { *m = BalanceInvariantsResponse{} }
+func (m *BalanceInvariantsResponse) String() string            { return proto.CompactTextString(m) }
+func (*BalanceInvariantsResponse) ProtoMessage()               {}
+func (*BalanceInvariantsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{121} }
+
+func (m *BalanceInvariantsResponse) GetInvariants() []*BalanceInvariant {
+	if m != nil {
+		return m.Invariants
+	}
+	return nil
+}
+
+func (m *BalanceInvariantsResponse) GetSendsSuspended() bool {
+	if m != nil {
+		return m.SendsSuspended
+	}
+	return false
+}
+
+func (m *BalanceInvariantsResponse) GetSuspensionReason() string {
+	if m != nil {
+		return m.SuspensionReason
+	}
+	return ""
+}
+
+func (m *BalanceInvariantsResponse) GetSuspendedAt() int64 {
+	if m != nil {
+		return m.SuspendedAt
+	}
+	return 0
+}
+
+type RunSelfTestRequest struct {
+	//
+	// Canary denotes that canary payments should be sent to the configured
+	// addresses, assets without canary address are tested without it.
+	Canary bool `protobuf:"varint,1,opt,name=canary" json:"canary,omitempty"`
+}
+
+func (m *RunSelfTestRequest) Reset()                    { *m = RunSelfTestRequest{} }
+func (m *RunSelfTestRequest) String() string            { return proto.CompactTextString(m) }
+func (*RunSelfTestRequest) ProtoMessage()               {}
+func (*RunSelfTestRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{122} }
+
+func (m *RunSelfTestRequest) GetCanary() bool {
+	if m != nil {
+		return m.Canary
+	}
+	return false
+}
+
+type RunSelfTestResponse struct {
+	Results []*SelfTestResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *RunSelfTestResponse) Reset()                    { *m = RunSelfTestResponse{} }
+func (m *RunSelfTestResponse) String() string            { return proto.CompactTextString(m) }
+func (*RunSelfTestResponse) ProtoMessage()               {}
+func (*RunSelfTestResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{123} }
+
+func (m *RunSelfTestResponse) GetResults() []*SelfTestResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type FeatureFlag struct {
+	//
+	// Name is the name of the feature flag, e.g. "rbf".
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	//
+	// Enabled enables flag for every tenant.
+	Enabled bool `protobuf:"varint,2,opt,name=enabled" json:"enabled,omitempty"`
+	//
+	// (optional) Tenants is the list of tenants, i.e. ids of the API keys
+	// on behalf of which payments are sent and receipts are created, for
+	// which flag is enabled.
+	Tenants []string `protobuf:"bytes,3,rep,name=tenants" json:"tenants,omitempty"`
+	//
+	// (optional) Percentage is the percentage of tenants for which flag is
+	// enabled.
+	Percentage uint32 `protobuf:"varint,4,opt,name=percentage" json:"percentage,omitempty"`
+}
+
+func (m *FeatureFlag) Reset()                    { *m = FeatureFlag{} }
+func (m *FeatureFlag) String() string            { return proto.CompactTextString(m) }
+func (*FeatureFlag) ProtoMessage()               {}
+func (*FeatureFlag) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{124} }
+
+func (m *FeatureFlag) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FeatureFlag) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *FeatureFlag) GetTenants() []string {
+	if m != nil {
+		return m.Tenants
+	}
+	return nil
+}
+
+func (m *FeatureFlag) GetPercentage() uint32 {
+	if m != nil {
+		return m.Percentage
+	}
+	return 0
+}
+
+type ListFeatureFlagsResponse struct {
+	Flags []*FeatureFlag `protobuf:"bytes,1,rep,name=flags" json:"flags,omitempty"`
+}
+
+func (m *ListFeatureFlagsResponse) Reset()                    { *m = ListFeatureFlagsResponse{} }
+func (m *ListFeatureFlagsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListFeatureFlagsResponse) ProtoMessage()               {}
+func (*ListFeatureFlagsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{125} }
+
+func (m *ListFeatureFlagsResponse) GetFlags() []*FeatureFlag {
+	if m != nil {
+		return m.Flags
+	}
+	return nil
+}
+
+type QuarantinePaymentRequest struct {
+	//
+	// PaymentID is the id of the incoming blockchain payment which should
+	// be quarantined.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+	//
+	// Reason is the reason with which payment has been flagged.
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *QuarantinePaymentRequest) Reset()                    { *m = QuarantinePaymentRequest{} }
+func (m *QuarantinePaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*QuarantinePaymentRequest) ProtoMessage()               {}
+func (*QuarantinePaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{126} }
+
+func (m *QuarantinePaymentRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *QuarantinePaymentRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type ReleasePaymentRequest struct {
+	//
+	// PaymentID is the id of the quarantined payment.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+}
+
+func (m *ReleasePaymentRequest) Reset()                    { *m = ReleasePaymentRequest{} }
+func (m *ReleasePaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReleasePaymentRequest) ProtoMessage()               {}
+func (*ReleasePaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{127} }
+
+func (m *ReleasePaymentRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+type ReturnPaymentRequest struct {
+	//
+	// PaymentID is the id of the completed quarantined payment.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+	//
+	// Receipt is the blockchain address on which payment is returned.
+	Receipt string `protobuf:"bytes,2,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// Memo is the message which is attached to the return in the blockchain.
+	Memo string `protobuf:"bytes,3,opt,name=memo" json:"memo,omitempty"`
+}
+
+func (m *ReturnPaymentRequest) Reset()                    { *m = ReturnPaymentRequest{} }
+func (m *ReturnPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReturnPaymentRequest) ProtoMessage()               {}
+func (*ReturnPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{128} }
+
+func (m *ReturnPaymentRequest) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *ReturnPaymentRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *ReturnPaymentRequest) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+type InjectTestPaymentRequest struct {
+	//
+	// Receipt is either blockchain address or lightning network invoice on
+	// which payment is received.
+	Receipt string `protobuf:"bytes,1,opt,name=receipt" json:"receipt,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,2,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,3,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Amount is the number of funds which are received.
+	Amount string `protobuf:"bytes,4,opt,name=amount" json:"amount,omitempty"`
+	//
+	// (optional) CompleteAfter is the number of seconds after which
+	// payment is completed. Default is ten seconds.
+	CompleteAfter uint64 `protobuf:"varint,5,opt,name=complete_after,json=completeAfter" json:"complete_after,omitempty"`
+}
+
+func (m *InjectTestPaymentRequest) Reset()                    { *m = InjectTestPaymentRequest{} }
+func (m *InjectTestPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*InjectTestPaymentRequest) ProtoMessage()               {}
+func (*InjectTestPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{129} }
+
+func (m *InjectTestPaymentRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *InjectTestPaymentRequest) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *InjectTestPaymentRequest) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *InjectTestPaymentRequest) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *InjectTestPaymentRequest) GetCompleteAfter() uint64 {
+	if m != nil {
+		return m.CompleteAfter
+	}
+	return 0
+}
+
+type DiagnoseRequest struct {
+	//
+	// (optional) StuckAfter is the number of seconds after which payment
+	// which is still waiting or pending is considered to be stuck. Default
+	// is one hour.
+	StuckAfter uint64 `protobuf:"varint,1,opt,name=stuck_after,json=stuckAfter" json:"stuck_after,omitempty"`
+}
+
+func (m *DiagnoseRequest) Reset()                    { *m = DiagnoseRequest{} }
+func (m *DiagnoseRequest) String() string            { return proto.CompactTextString(m) }
+func (*DiagnoseRequest) ProtoMessage()               {}
+func (*DiagnoseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{130} }
+
+func (m *DiagnoseRequest) GetStuckAfter() uint64 {
+	if m != nil {
+		return m.StuckAfter
+	}
+	return 0
+}
+
+type ConfigOption struct {
+	//
+	// Name is the name of the option, e.g. "bitcoin.host".
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	//
+	// Value is the value of the option, secrets are replaced.
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *ConfigOption) Reset()                    { *m = ConfigOption{} }
+func (m *ConfigOption) String() string            { return proto.CompactTextString(m) }
+func (*ConfigOption) ProtoMessage()               {}
+func (*ConfigOption) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{131} }
+
+func (m *ConfigOption) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ConfigOption) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type ConnectorHealth struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Healthy denotes that connector is able to reach its daemon.
+	Healthy bool `protobuf:"varint,3,opt,name=healthy" json:"healthy,omitempty"`
+	//
+	// Error is the error which is returned by the connector if it is not
+	// healthy.
+	Error string `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+	//
+	// (optional) UnspentSync is the state of the sync of the wallet
+	// unspent outputs, for utxo based connectors.
+	UnspentSync *UnspentSyncStatus `protobuf:"bytes,5,opt,name=unspent_sync,json=unspentSync" json:"unspent_sync,omitempty"`
+}
+
+func (m *ConnectorHealth) Reset()                    { *m = ConnectorHealth{} }
+func (m *ConnectorHealth) String() string            { return proto.CompactTextString(m) }
+func (*ConnectorHealth) ProtoMessage()               {}
+func (*ConnectorHealth) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{132} }
+
+func (m *ConnectorHealth) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *ConnectorHealth) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *ConnectorHealth) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *ConnectorHealth) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ConnectorHealth) GetUnspentSync() *UnspentSyncStatus {
+	if m != nil {
+		return m.UnspentSync
+	}
+	return nil
+}
+
+type ErrorCount struct {
+	//
+	// Metric is the name of the errors metric.
+	Metric string `protobuf:"bytes,1,opt,name=metric" json:"metric,omitempty"`
+	//
+	// Labels is the labels of the counter in form of "name=value".
+	Labels []string `protobuf:"bytes,2,rep,name=labels" json:"labels,omitempty"`
+	//
+	// Count is the number of errors since the start.
+	Count uint64 `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *ErrorCount) Reset()                    { *m = ErrorCount{} }
+func (m *ErrorCount) String() string            { return proto.CompactTextString(m) }
+func (*ErrorCount) ProtoMessage()               {}
+func (*ErrorCount) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{133} }
+
+func (m *ErrorCount) GetMetric() string {
+	if m != nil {
+		return m.Metric
+	}
+	return ""
+}
+
+func (m *ErrorCount) GetLabels() []string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *ErrorCount) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type QueueDepth struct {
+	//
+	// Name is the name of the in-memory queue.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	//
+	// Depth is the number of elements in the queue.
+	Depth uint64 `protobuf:"varint,2,opt,name=depth" json:"depth,omitempty"`
+}
+
+func (m *QueueDepth) Reset()                    { *m = QueueDepth{} }
+func (m *QueueDepth) String() string            { return proto.CompactTextString(m) }
+func (*QueueDepth) ProtoMessage()               {}
+func (*QueueDepth) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{134} }
+
+func (m *QueueDepth) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QueueDepth) GetDepth() uint64 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+type DiagnoseResponse struct {
+	//
+	// Version is the version of the service.
+	Version string `protobuf:"bytes,1,opt,name=version" json:"version,omitempty"`
+	//
+	// GoVersion is the version of the Go runtime.
+	GoVersion string `protobuf:"bytes,2,opt,name=go_version,json=goVersion" json:"go_version,omitempty"`
+	//
+	// Network is the blockchain network in which service is working.
+	Network string `protobuf:"bytes,3,opt,name=network" json:"network,omitempty"`
+	//
+	// StartedAt is the time of the service start in unix milliseconds.
+	StartedAt int64 `protobuf:"varint,4,opt,name=started_at,json=startedAt" json:"started_at,omitempty"`
+	//
+	// Config is the list of config options, with the secrets replaced.
+	Config []*ConfigOption `protobuf:"bytes,5,rep,name=config" json:"config,omitempty"`
+	//
+	// Connectors is the health of the blockchain and lightning connectors.
+	Connectors []*ConnectorHealth `protobuf:"bytes,6,rep,name=connectors" json:"connectors,omitempty"`
+	//
+	// Errors is the list of non-zero error counters.
+	Errors []*ErrorCount `protobuf:"bytes,7,rep,name=errors" json:"errors,omitempty"`
+	//
+	// Queues is the depth of the in-memory queues.
+	Queues []*QueueDepth `protobuf:"bytes,8,rep,name=queues" json:"queues,omitempty"`
+	//
+	// StuckPayments is the list of payments which are waiting or pending
+	// for longer than expected.
+	StuckPayments []*Payment `protobuf:"bytes,9,rep,name=stuck_payments,json=stuckPayments" json:"stuck_payments,omitempty"`
+}
+
+func (m *DiagnoseResponse) Reset()                    { *m = DiagnoseResponse{} }
+func (m *DiagnoseResponse) String() string            { return proto.CompactTextString(m) }
+func (*DiagnoseResponse) ProtoMessage()               {}
+func (*DiagnoseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{135} }
+
+func (m *DiagnoseResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *DiagnoseResponse) GetGoVersion() string {
+	if m != nil {
+		return m.GoVersion
+	}
+	return ""
+}
+
+func (m *DiagnoseResponse) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *DiagnoseResponse) GetStartedAt() int64 {
+	if m != nil {
+		return m.StartedAt
+	}
+	return 0
+}
+
+func (m *DiagnoseResponse) GetConfig() []*ConfigOption {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *DiagnoseResponse) GetConnectors() []*ConnectorHealth {
+	if m != nil {
+		return m.Connectors
+	}
+	return nil
+}
+
+func (m *DiagnoseResponse) GetErrors() []*ErrorCount {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+func (m *DiagnoseResponse) GetQueues() []*QueueDepth {
+	if m != nil {
+		return m.Queues
+	}
+	return nil
+}
+
+func (m *DiagnoseResponse) GetStuckPayments() []*Payment {
+	if m != nil {
+		return m.StuckPayments
+	}
+	return nil
+}
+
+type Payment struct {
+	//
+	// PaymentID it is unique identificator of the payment generated inside
+	// the system.
+	PaymentId string `protobuf:"bytes,1,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
+	//
+	// UpdatedAt denotes the time when payment object has been last updated.
+	UpdatedAt int64 `protobuf:"varint,2,opt,name=updated_at,json=updatedAt" json:"updated_at,omitempty"`
+	//
+	// Status denotes the stage of the processing the payment.
+	Status PaymentStatus `protobuf:"varint,3,opt,name=status,enum=crpc.PaymentStatus" json:"status,omitempty"`
+	//
+	// Direction denotes the direction of the payment, whether
+	// payment is going form us to someone else, or form someone else to us.
+	Direction PaymentDirection `protobuf:"varint,4,opt,name=direction,enum=crpc.PaymentDirection" json:"direction,omitempty"`
+	//
+	// System denotes is that payment belongs to business logic of
+	// payment server or it was originated by user / third-party service.
+	System PaymentSystem `protobuf:"varint,11,opt,name=system,enum=crpc.PaymentSystem" json:"system,omitempty"`
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,5,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Receipt is a string which identifies the receiver of the
+	// payment. It is address in case of the blockchain media,
+	// and lightning network invoice in case lightning media.
+	Receipt string `protobuf:"bytes,6,opt,name=receipt" json:"receipt,omitempty"`
+	// MediaID is identificator of the payment inside the media.
+	// In case of blockchain media payment id is the transaction id,
+	// in case of lightning media it is the payment hash. It is not used as
+	// payment identificator because of the reason that it is not unique.
+	MediaId string `protobuf:"bytes,7,opt,name=media_id,json=mediaId" json:"media_id,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,8,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Amount is the number of funds which receiver gets at the end.
+	Amount string `protobuf:"bytes,9,opt,name=amount" json:"amount,omitempty"`
+	//
+	// MediaFee is the fee which is taken by the blockchain or lightning
+	// network in order to propagate the payment.
+	MediaFee string `protobuf:"bytes,10,opt,name=media_fee,json=mediaFee" json:"media_fee,omitempty"`
+	//
+	// Memo is the message which was attached to the payment in the media.
+	// NOTE: Only returns if MEMO is included in the request.
+	Memo string `protobuf:"bytes,12,opt,name=memo" json:"memo,omitempty"`
+	//
+	// Warnings is the list of non-fatal advisories about the payment, e.g.
+	// high fee or reused destination address.
+	// NOTE: Only returns in the send payment response, if WARNINGS is
+	// included in the request.
+	Warnings []string `protobuf:"bytes,13,rep,name=warnings" json:"warnings,omitempty"`
+	//
+	// Flags is the list of feature flags which were enabled and affected
+	// the payment when it was created.
+	// NOTE: Only returns if FLAGS is included in the request.
+	Flags []string `protobuf:"bytes,14,rep,name=flags" json:"flags,omitempty"`
+	//
+	// Confirmations is the number of confirmations of the pending
+	// blockchain payment.
+	// NOTE: Only returns if CONFIRMATIONS is included in the request.
+	Confirmations int64 `protobuf:"varint,16,opt,name=confirmations" json:"confirmations,omitempty"`
+	//
+	// ConfirmationsLeft is the number of confirmations left in order to
+	// consider the pending blockchain payment as confirmed.
+	// NOTE: Only returns if CONFIRMATIONS is included in the request.
+	ConfirmationsLeft int64 `protobuf:"varint,17,opt,name=confirmations_left,json=confirmationsLeft" json:"confirmations_left,omitempty"`
+	//
+	// Account is the identifier of the account to which payment belongs,
+	// incoming payment belongs to the account of its receipt.
+	Account string `protobuf:"bytes,18,opt,name=account" json:"account,omitempty"`
+	//
+	// Metadata is the set of the labels attached to the payment, incoming
+	// payment is labeled with the metadata of its receipt.
+	Metadata map[string]string `protobuf:"bytes,19,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	//
+	// Timeline is the ordered list of the events which happened to the
+	// payment, used to investigate the complaints about the payment.
+	// NOTE: Only returned by PaymentByID.
+	Timeline []*PaymentEvent `protobuf:"bytes,20,rep,name=timeline" json:"timeline,omitempty"`
+	//
+	// RefundOf is the id of the incoming payment which is refunded by this
+	// payment.
+	RefundOf string `protobuf:"bytes,21,opt,name=refund_of,json=refundOf" json:"refund_of,omitempty"`
+	//
+	// Sequence is the global sequence number of the last change of the
+	// payment, it is strictly increasing across all payments.
+	Sequence uint64 `protobuf:"varint,22,opt,name=sequence" json:"sequence,omitempty"`
+	//
+	// Quarantine is the state of the compliance review of the incoming
+	// payment, quarantined payment isn't spendable.
+	Quarantine QuarantineState `protobuf:"varint,23,opt,name=quarantine,enum=crpc.QuarantineState" json:"quarantine,omitempty"`
+	//
+	// QuarantineReason is the reason with which payment has been flagged.
+	QuarantineReason string `protobuf:"bytes,24,opt,name=quarantine_reason,json=quarantineReason" json:"quarantine_reason,omitempty"`
+	//
+	// FiatValue is the value of the payment amount in the fiat currency at
+	// the moment when payment has been completed, empty if payment hasn't
+	// been completed yet or price of the asset isn't known.
+	FiatValue string `protobuf:"bytes,25,opt,name=fiat_value,json=fiatValue" json:"fiat_value,omitempty"`
+	//
+	// FiatCurrency is the currency of the fiat value.
+	FiatCurrency string `protobuf:"bytes,26,opt,name=fiat_currency,json=fiatCurrency" json:"fiat_currency,omitempty"`
+}
+
+func (m *Payment) Reset()                    { *m = Payment{} }
+func (m *Payment) String() string            { return proto.CompactTextString(m) }
+func (*Payment) ProtoMessage()               {}
+func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{136} }
+
+func (m *Payment) GetPaymentId() string {
+	if m != nil {
+		return m.PaymentId
+	}
+	return ""
+}
+
+func (m *Payment) GetUpdatedAt() int64 {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return 0
+}
+
+func (m *Payment) GetStatus() PaymentStatus {
+	if m != nil {
+		return m.Status
+	}
+	return PaymentStatus_STATUS_NONE
+}
+
+func (m *Payment) GetDirection() PaymentDirection {
+	if m != nil {
+		return m.Direction
+	}
+	return PaymentDirection_DIRECTION_NONE
+}
+
+func (m *Payment) GetSystem() PaymentSystem {
+	if m != nil {
+		return m.System
+	}
+	return PaymentSystem_SYSTEM_NONE
+}
+
+func (m *Payment) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *Payment) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *Payment) GetMediaId() string {
+	if m != nil {
+		return m.MediaId
+	}
+	return ""
+}
+
+func (m *Payment) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *Payment) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *Payment) GetMediaFee() string {
+	if m != nil {
+		return m.MediaFee
+	}
+	return ""
+}
+
+func (m *Payment) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *Payment) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+func (m *Payment) GetFlags() []string {
+	if m != nil {
+		return m.Flags
+	}
+	return nil
+}
+
+func (m *Payment) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+func (m *Payment) GetConfirmationsLeft() int64 {
+	if m != nil {
+		return m.ConfirmationsLeft
+	}
+	return 0
+}
+
+func (m *Payment) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *Payment) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *Payment) GetTimeline() []*PaymentEvent {
+	if m != nil {
+		return m.Timeline
+	}
+	return nil
+}
+
+func (m *Payment) GetRefundOf() string {
+	if m != nil {
+		return m.RefundOf
+	}
+	return ""
+}
+
+func (m *Payment) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Payment) GetQuarantine() QuarantineState {
+	if m != nil {
+		return m.Quarantine
+	}
+	return QuarantineState_QUARANTINE_NONE
+}
+
+func (m *Payment) GetQuarantineReason() string {
+	if m != nil {
+		return m.QuarantineReason
+	}
+	return ""
+}
+
+func (m *Payment) GetFiatValue() string {
+	if m != nil {
+		return m.FiatValue
+	}
+	return ""
+}
+
+func (m *Payment) GetFiatCurrency() string {
+	if m != nil {
+		return m.FiatCurrency
+	}
+	return ""
+}
+
+type PaymentEvent struct {
+	//
+	// Type is the type of the event.
+	Type PaymentEventType `protobuf:"varint,1,opt,name=type,enum=crpc.PaymentEventType" json:"type,omitempty"`
+	//
+	// Time is the time of the event in milliseconds.
+	Time int64 `protobuf:"varint,2,opt,name=time" json:"time,omitempty"`
+	//
+	// Details is the additional information of the event, i.e. the number
+	// of the confirmations, the reason of the failure or the target of the
+	// post-send hook.
+	Details string `protobuf:"bytes,3,opt,name=details" json:"details,omitempty"`
+}
+
+func (m *PaymentEvent) Reset()                    { *m = PaymentEvent{} }
+func (m *PaymentEvent) String() string            { return proto.CompactTextString(m) }
+func (*PaymentEvent) ProtoMessage()               {}
+func (*PaymentEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{137} }
+
+func (m *PaymentEvent) GetType() PaymentEventType {
+	if m != nil {
+		return m.Type
+	}
+	return PaymentEventType_EVENT_NONE
+}
+
+func (m *PaymentEvent) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *PaymentEvent) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
+type CreateAPIKeyRequest struct {
+	//
+	// Name is the name of the downstream service which uses the key, e.g.
+	// "checkout".
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	//
+	// Scopes is the list of scopes which define methods allowed by the key.
+	Scopes []APIKeyScope `protobuf:"varint,2,rep,packed,name=scopes,enum=crpc.APIKeyScope" json:"scopes,omitempty"`
+}
+
+func (m *CreateAPIKeyRequest) Reset()                    { *m = CreateAPIKeyRequest{} }
+func (m *CreateAPIKeyRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateAPIKeyRequest) ProtoMessage()               {}
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{138} }
+
+func (m *CreateAPIKeyRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateAPIKeyRequest) GetScopes() []APIKeyScope {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+type APIKey struct {
+	//
+	// ID is the unique identifier of the key, which is used to revoke it.
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	//
+	// Name is the name of the downstream service which uses the key.
+	Name string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	//
+	// Scopes is the list of scopes which define methods allowed by the key.
+	Scopes []APIKeyScope `protobuf:"varint,3,rep,packed,name=scopes,enum=crpc.APIKeyScope" json:"scopes,omitempty"`
+	//
+	// CreatedAt is the time of the key creation in unix milliseconds.
+	CreatedAt int64 `protobuf:"varint,4,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	//
+	// RevokedAt is the time of the key revocation in unix milliseconds,
+	// zero if key is active.
+	RevokedAt int64 `protobuf:"varint,5,opt,name=revoked_at,json=revokedAt" json:"revoked_at,omitempty"`
+}
+
+func (m *APIKey) Reset()                    { *m = APIKey{} }
+func (m *APIKey) String() string            { return proto.CompactTextString(m) }
+func (*APIKey) ProtoMessage()               {}
+func (*APIKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{139} }
+
+func (m *APIKey) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *APIKey) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *APIKey) GetScopes() []APIKeyScope {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *APIKey) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *APIKey) GetRevokedAt() int64 {
+	if m != nil {
+		return m.RevokedAt
+	}
+	return 0
+}
+
+type CreateAPIKeyResponse struct {
+	ApiKey *APIKey `protobuf:"bytes,1,opt,name=api_key,json=apiKey" json:"api_key,omitempty"`
+	//
+	// Key is the secret which should be passed by the downstream service in
+	// the "api-key" metadata. It couldn't be retrieved later.
+	Key string `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *CreateAPIKeyResponse) Reset()                    { *m = CreateAPIKeyResponse{} }
+func (m *CreateAPIKeyResponse) String() string            { return proto.CompactTextString(m) }
+func (*CreateAPIKeyResponse) ProtoMessage()               {}
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{140} }
+
+func (m *CreateAPIKeyResponse) GetApiKey() *APIKey {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *CreateAPIKeyResponse) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type RevokeAPIKeyRequest struct {
+	//
+	// ID is the identifier of the key.
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *RevokeAPIKeyRequest) Reset()                    { *m = RevokeAPIKeyRequest{} }
+func (m *RevokeAPIKeyRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeAPIKeyRequest) ProtoMessage()               {}
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{141} }
+
+func (m *RevokeAPIKeyRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ListAPIKeysResponse struct {
+	ApiKeys []*APIKey `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys" json:"api_keys,omitempty"`
+}
+
+func (m *ListAPIKeysResponse) Reset()                    { *m = ListAPIKeysResponse{} }
+func (m *ListAPIKeysResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListAPIKeysResponse) ProtoMessage()               {}
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{142} }
+
+func (m *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+	if m != nil {
+		return m.ApiKeys
+	}
+	return nil
+}
+
+type PublicKey struct {
+	//
+	// KeyID is the id of the key which is sent in the X-Signature-Key-Id
+	// header along with the signature.
+	KeyId string `protobuf:"bytes,1,opt,name=key_id,json=keyId" json:"key_id,omitempty"`
+	//
+	// Algorithm is the signature algorithm, e.g. "ecdsa-p256-sha256", which
+	// means that body is hashed with SHA-256 and signed by the ECDSA P-256
+	// key, signature is ASN.1 DER encoded and then base64 encoded.
+	Algorithm string `protobuf:"bytes,2,opt,name=algorithm" json:"algorithm,omitempty"`
+	//
+	// PublicKey is the hex encoded public key in PKIX, ASN.1 DER form.
+	PublicKey string `protobuf:"bytes,3,opt,name=public_key,json=publicKey" json:"public_key,omitempty"`
+}
+
+func (m *PublicKey) Reset()                    { *m = PublicKey{} }
+func (m *PublicKey) String() string            { return proto.CompactTextString(m) }
+func (*PublicKey) ProtoMessage()               {}
+func (*PublicKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{143} }
+
+func (m *PublicKey) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+func (m *PublicKey) GetAlgorithm() string {
+	if m != nil {
+		return m.Algorithm
+	}
+	return ""
+}
+
+func (m *PublicKey) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+type GetPublicKeysResponse struct {
+	Keys []*PublicKey `protobuf:"bytes,1,rep,name=keys" json:"keys,omitempty"`
+}
+
+func (m *GetPublicKeysResponse) Reset()                    { *m = GetPublicKeysResponse{} }
+func (m *GetPublicKeysResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetPublicKeysResponse) ProtoMessage()               {}
+func (*GetPublicKeysResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{144} }
+
+func (m *GetPublicKeysResponse) GetKeys() []*PublicKey {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type LightningNodeInfo struct {
+	//
+	// Pubkey is the identity public key of the lightning network node.
+	Pubkey string `protobuf:"bytes,1,opt,name=pubkey" json:"pubkey,omitempty"`
+	//
+	// Alias is the alias of the lightning network node.
+	Alias string `protobuf:"bytes,2,opt,name=alias" json:"alias,omitempty"`
+	//
+	// Version is the version of the lightning network daemon.
+	Version string `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	//
+	// Host is the public host via which other nodes could connect.
+	Host string `protobuf:"bytes,4,opt,name=host" json:"host,omitempty"`
+	//
+	// Port is the public port via which other nodes could connect.
+	Port string `protobuf:"bytes,5,opt,name=port" json:"port,omitempty"`
+	//
+	// NumPeers is the number of connected peers.
+	NumPeers uint32 `protobuf:"varint,6,opt,name=num_peers,json=numPeers" json:"num_peers,omitempty"`
+	//
+	// NumActiveChannels is the number of active channels.
+	NumActiveChannels uint32 `protobuf:"varint,7,opt,name=num_active_channels,json=numActiveChannels" json:"num_active_channels,omitempty"`
+}
+
+func (m *LightningNodeInfo) Reset()                    { *m = LightningNodeInfo{} }
+func (m *LightningNodeInfo) String() string            { return proto.CompactTextString(m) }
+func (*LightningNodeInfo) ProtoMessage()               {}
+func (*LightningNodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{145} }
+
+func (m *LightningNodeInfo) GetPubkey() string {
+	if m != nil {
+		return m.Pubkey
+	}
+	return ""
+}
+
+func (m *LightningNodeInfo) GetAlias() string {
+	if m != nil {
+		return m.Alias
+	}
+	return ""
+}
+
+func (m *LightningNodeInfo) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *LightningNodeInfo) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *LightningNodeInfo) GetPort() string {
+	if m != nil {
+		return m.Port
+	}
+	return ""
+}
+
+func (m *LightningNodeInfo) GetNumPeers() uint32 {
+	if m != nil {
+		return m.NumPeers
+	}
+	return 0
+}
+
+func (m *LightningNodeInfo) GetNumActiveChannels() uint32 {
+	if m != nil {
+		return m.NumActiveChannels
+	}
+	return 0
+}
+
+type ConnectorInfo struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Height is the height of the best block processed by the daemon.
+	Height int64 `protobuf:"varint,3,opt,name=height" json:"height,omitempty"`
+	//
+	// NetworkHeight is the height of the best block known by the daemon
+	// from the network, zero if daemon doesn't report it.
+	NetworkHeight int64 `protobuf:"varint,4,opt,name=network_height,json=networkHeight" json:"network_height,omitempty"`
+	//
+	// Synced denotes that daemon has caught up with the network.
+	Synced bool `protobuf:"varint,5,opt,name=synced" json:"synced,omitempty"`
+	//
+	// Error is the error which is returned by the connector if its state
+	// couldn't be requested.
+	Error string `protobuf:"bytes,6,opt,name=error" json:"error,omitempty"`
+	//
+	// (optional) Node is the info of the lightning network node, it is
+	// returned only for lightning media.
+	Node *LightningNodeInfo `protobuf:"bytes,7,opt,name=node" json:"node,omitempty"`
+}
+
+func (m *ConnectorInfo) Reset()                    { *m = ConnectorInfo{} }
+func (m *ConnectorInfo) String() string            { return proto.CompactTextString(m) }
+func (*ConnectorInfo) ProtoMessage()               {}
+func (*ConnectorInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{146} }
+
+func (m *ConnectorInfo) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *ConnectorInfo) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *ConnectorInfo) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *ConnectorInfo) GetNetworkHeight() int64 {
+	if m != nil {
+		return m.NetworkHeight
+	}
+	return 0
+}
+
+func (m *ConnectorInfo) GetSynced() bool {
+	if m != nil {
+		return m.Synced
+	}
+	return false
+}
+
+func (m *ConnectorInfo) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ConnectorInfo) GetNode() *LightningNodeInfo {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+type ComponentHealth struct {
+	//
+	// Name is the name of the component, e.g. "database" or
+	// "btc_lightning".
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	//
+	// Healthy denotes that component is able to serve requests.
+	Healthy bool `protobuf:"varint,2,opt,name=healthy" json:"healthy,omitempty"`
+	//
+	// Error is the reason why component isn't healthy.
+	Error string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *ComponentHealth) Reset()                    { *m = ComponentHealth{} }
+func (m *ComponentHealth) String() string            { return proto.CompactTextString(m) }
+func (*ComponentHealth) ProtoMessage()               {}
+func (*ComponentHealth) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{147} }
+
+func (m *ComponentHealth) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ComponentHealth) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *ComponentHealth) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type HealthCheckResponse struct {
+	//
+	// Healthy denotes that all components are healthy.
+	Healthy bool `protobuf:"varint,1,opt,name=healthy" json:"healthy,omitempty"`
+	//
+	// Components is the health of every component of the server.
+	Components []*ComponentHealth `protobuf:"bytes,2,rep,name=components" json:"components,omitempty"`
+}
+
+func (m *HealthCheckResponse) Reset()                    { *m = HealthCheckResponse{} }
+func (m *HealthCheckResponse) String() string            { return proto.CompactTextString(m) }
+func (*HealthCheckResponse) ProtoMessage()               {}
+func (*HealthCheckResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{148} }
+
+func (m *HealthCheckResponse) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *HealthCheckResponse) GetComponents() []*ComponentHealth {
+	if m != nil {
+		return m.Components
+	}
+	return nil
+}
+
+type SelfTestCheck struct {
+	//
+	// Name is the name of the check, e.g. "address_vectors".
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	//
+	// Passed denotes that check has succeeded.
+	Passed bool `protobuf:"varint,2,opt,name=passed" json:"passed,omitempty"`
+	//
+	// Skipped denotes that check isn't applicable to the asset, media or
+	// network, or that it isn't configured, skipped check is passed.
+	Skipped bool `protobuf:"varint,3,opt,name=skipped" json:"skipped,omitempty"`
+	//
+	// Detail is the error of the failed check, the reason of the skip, or
+	// the outcome of the passed check, e.g. id of the canary payment.
+	Detail string `protobuf:"bytes,4,opt,name=detail" json:"detail,omitempty"`
+}
+
+func (m *SelfTestCheck) Reset()                    { *m = SelfTestCheck{} }
+func (m *SelfTestCheck) String() string            { return proto.CompactTextString(m) }
+func (*SelfTestCheck) ProtoMessage()               {}
+func (*SelfTestCheck) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{149} }
+
+func (m *SelfTestCheck) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SelfTestCheck) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *SelfTestCheck) GetSkipped() bool {
+	if m != nil {
+		return m.Skipped
+	}
+	return false
+}
+
+func (m *SelfTestCheck) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+type SelfTestResult struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media Media `protobuf:"varint,2,opt,name=media,enum=crpc.Media" json:"media,omitempty"`
+	//
+	// Passed denotes that all checks have passed.
+	Passed bool `protobuf:"varint,3,opt,name=passed" json:"passed,omitempty"`
+	//
+	// RanAt is the time of the self-test in milliseconds.
+	RanAt  int64            `protobuf:"varint,4,opt,name=ran_at,json=ranAt" json:"ran_at,omitempty"`
+	Checks []*SelfTestCheck `protobuf:"bytes,5,rep,name=checks" json:"checks,omitempty"`
+}
+
+func (m *SelfTestResult) Reset()                    { *m = SelfTestResult{} }
+func (m *SelfTestResult) String() string            { return proto.CompactTextString(m) }
+func (*SelfTestResult) ProtoMessage()               {}
+func (*SelfTestResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{150} }
+
+func (m *SelfTestResult) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *SelfTestResult) GetMedia() Media {
+	if m != nil {
+		return m.Media
+	}
+	return Media_MEDIA_NONE
+}
+
+func (m *SelfTestResult) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *SelfTestResult) GetRanAt() int64 {
+	if m != nil {
+		return m.RanAt
+	}
+	return 0
+}
+
+func (m *SelfTestResult) GetChecks() []*SelfTestCheck {
+	if m != nil {
+		return m.Checks
+	}
+	return nil
+}
+
+type GetInfoResponse struct {
+	//
+	// Version is the version of the server.
+	Version string `protobuf:"bytes,1,opt,name=version" json:"version,omitempty"`
+	//
+	// Network is the blockchain network in which server is working.
+	Network string `protobuf:"bytes,2,opt,name=network" json:"network,omitempty"`
+	//
+	// Connectors is the list of enabled assets and media along with the
+	// state of their daemons.
+	Connectors []*ConnectorInfo `protobuf:"bytes,3,rep,name=connectors" json:"connectors,omitempty"`
+	//
+	// SelfTests is the results of the last self-test of the enabled assets
+	// and media, empty if self-test hasn't been run since the start.
+	SelfTests []*SelfTestResult `protobuf:"bytes,4,rep,name=self_tests,json=selfTests" json:"self_tests,omitempty"`
+}
+
+func (m *GetInfoResponse) Reset()                    { *m = GetInfoResponse{} }
+func (m *GetInfoResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetInfoResponse) ProtoMessage()               {}
+func (*GetInfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{151} }
+
+func (m *GetInfoResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *GetInfoResponse) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *GetInfoResponse) GetConnectors() []*ConnectorInfo {
+	if m != nil {
+		return m.Connectors
+	}
+	return nil
+}
+
+func (m *GetInfoResponse) GetSelfTests() []*SelfTestResult {
+	if m != nil {
+		return m.SelfTests
+	}
+	return nil
+}
+
+type AssetInfo struct {
+	//
+	// Asset is an acronim of the crypto currency.
+	Asset Asset `protobuf:"varint,1,opt,name=asset,enum=crpc.Asset" json:"asset,omitempty"`
+	//
+	// Decimals is the number of digits after the decimal point in the
+	// amounts of the asset.
+	Decimals int32 `protobuf:"varint,2,opt,name=decimals" json:"decimals,omitempty"`
+	//
+	// MinAmount is the minimum amount of the blockchain payment.
+	MinAmount string `protobuf:"bytes,3,opt,name=min_amount,json=minAmount" json:"min_amount,omitempty"`
+	//
+	// MaxAmount is the maximum amount of the blockchain payment, empty if
+	// it isn't limited.
+	MaxAmount string `protobuf:"bytes,4,opt,name=max_amount,json=maxAmount" json:"max_amount,omitempty"`
+	//
+	// DustLimit is the amount of the output below which transaction isn't
+	// relayed by the blockchain network.
+	DustLimit string `protobuf:"bytes,5,opt,name=dust_limit,json=dustLimit" json:"dust_limit,omitempty"`
+	//
+	// MinConfirmations is the number of confirmations after which
+	// blockchain payment is considered completed.
+	MinConfirmations int64 `protobuf:"varint,6,opt,name=min_confirmations,json=minConfirmations" json:"min_confirmations,omitempty"`
+	//
+	// Blockchain denotes whether blockchain media is available.
+	Blockchain bool `protobuf:"varint,7,opt,name=blockchain" json:"blockchain,omitempty"`
+	//
+	// Lightning denotes whether lightning media is available.
+	Lightning bool `protobuf:"varint,8,opt,name=lightning" json:"lightning,omitempty"`
+	//
+	// (optional) LightningMinAmount is the minimum amount of the lightning
+	// payment, it is returned only if lightning media is available.
+	LightningMinAmount string `protobuf:"bytes,9,opt,name=lightning_min_amount,json=lightningMinAmount" json:"lightning_min_amount,omitempty"`
+	//
+	// (optional) LightningMaxAmount is the maximum amount of the lightning
+	// payment, it is returned only if lightning media is available.
+	LightningMaxAmount string `protobuf:"bytes,10,opt,name=lightning_max_amount,json=lightningMaxAmount" json:"lightning_max_amount,omitempty"`
+	//
+	// (optional) Error is the error which occurred during the request of
+	// the lightning node, in this case lightning limits are not returned.
+	Error string `protobuf:"bytes,11,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *AssetInfo) Reset()                    { *m = AssetInfo{} }
+func (m *AssetInfo) String() string            { return proto.CompactTextString(m) }
+func (*AssetInfo) ProtoMessage()               {}
+func (*AssetInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{152} }
+
+func (m *AssetInfo) GetAsset() Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return Asset_ASSET_NONE
+}
+
+func (m *AssetInfo) GetDecimals() int32 {
+	if m != nil {
+		return m.Decimals
+	}
+	return 0
+}
+
+func (m *AssetInfo) GetMinAmount() string {
+	if m != nil {
+		return m.MinAmount
+	}
+	return ""
+}
+
+func (m *AssetInfo) GetMaxAmount() string {
+	if m != nil {
+		return m.MaxAmount
+	}
+	return ""
+}
+
+func (m *AssetInfo) GetDustLimit() string {
+	if m != nil {
+		return m.DustLimit
+	}
+	return ""
+}
+
+func (m *AssetInfo) GetMinConfirmations() int64 {
+	if m != nil {
+		return m.MinConfirmations
+	}
+	return 0
+}
+
+func (m *AssetInfo) GetBlockchain() bool {
+	if m != nil {
+		return m.Blockchain
+	}
+	return false
+}
+
+func (m *AssetInfo) GetLightning() bool {
+	if m != nil {
+		return m.Lightning
+	}
+	return false
+}
+
+func (m *AssetInfo) GetLightningMinAmount() string {
+	if m != nil {
+		return m.LightningMinAmount
+	}
+	return ""
+}
+
+func (m *AssetInfo) GetLightningMaxAmount() string {
+	if m != nil {
+		return m.LightningMaxAmount
+	}
+	return ""
+}
+
+func (m *AssetInfo) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type AssetsResponse struct {
+	//
+	// Assets is the list of parameters of the enabled assets.
+	Assets []*AssetInfo `protobuf:"bytes,1,rep,name=assets" json:"assets,omitempty"`
+}
+
+func (m *AssetsResponse) Reset()                    { *m = AssetsResponse{} }
+func (m *AssetsResponse) String() string            { return proto.CompactTextString(m) }
+func (*AssetsResponse) ProtoMessage()               {}
+func (*AssetsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{153} }
+
+func (m *AssetsResponse) GetAssets() []*AssetInfo {
+	if m != nil {
+		return m.Assets
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*EmptyRequest)(nil), "crpc.EmptyRequest")
+	proto.RegisterType((*EmptyResponse)(nil), "crpc.EmptyResponse")
+	proto.RegisterType((*ProvideAddressRequest)(nil), "crpc.ProvideAddressRequest")
+	proto.RegisterType((*ProvideAddressResponse)(nil), "crpc.ProvideAddressResponse")
+	proto.RegisterType((*CreateReceiptRequest)(nil), "crpc.CreateReceiptRequest")
+	proto.RegisterType((*NewAddressRequest)(nil), "crpc.NewAddressRequest")
+	proto.RegisterType((*NewAddressResponse)(nil), "crpc.NewAddressResponse")
+	proto.RegisterType((*ListReceiptsRequest)(nil), "crpc.ListReceiptsRequest")
+	proto.RegisterType((*Receipt)(nil), "crpc.Receipt")
+	proto.RegisterType((*AttachRefundAddressRequest)(nil), "crpc.AttachRefundAddressRequest")
+	proto.RegisterType((*ReceiptByIDRequest)(nil), "crpc.ReceiptByIDRequest")
+	proto.RegisterType((*SubscribeReceiptsRequest)(nil), "crpc.SubscribeReceiptsRequest")
+	proto.RegisterType((*ReceiptEvent)(nil), "crpc.ReceiptEvent")
+	proto.RegisterType((*ListReceiptsResponse)(nil), "crpc.ListReceiptsResponse")
+	proto.RegisterType((*CreateReceiptResponse)(nil), "crpc.CreateReceiptResponse")
+	proto.RegisterType((*BalanceRequest)(nil), "crpc.BalanceRequest")
+	proto.RegisterType((*Balance)(nil), "crpc.Balance")
+	proto.RegisterType((*BalanceHistoryRequest)(nil), "crpc.BalanceHistoryRequest")
+	proto.RegisterType((*BalanceSnapshot)(nil), "crpc.BalanceSnapshot")
+	proto.RegisterType((*BalanceHistoryResponse)(nil), "crpc.BalanceHistoryResponse")
+	proto.RegisterType((*SubscribeBalanceRequest)(nil), "crpc.SubscribeBalanceRequest")
+	proto.RegisterType((*BalanceUpdate)(nil), "crpc.BalanceUpdate")
+	proto.RegisterType((*ValidateReceiptResponse)(nil), "crpc.ValidateReceiptResponse")
+	proto.RegisterType((*Invoice)(nil), "crpc.Invoice")
+	proto.RegisterType((*BalanceResponse)(nil), "crpc.BalanceResponse")
+	proto.RegisterType((*ValidateReceiptRequest)(nil), "crpc.ValidateReceiptRequest")
+	proto.RegisterType((*EstimateFeeRequest)(nil), "crpc.EstimateFeeRequest")
+	proto.RegisterType((*FeeTarget)(nil), "crpc.FeeTarget")
+	proto.RegisterType((*EstimateFeeResponse)(nil), "crpc.EstimateFeeResponse")
+	proto.RegisterType((*SendPaymentRequest)(nil), "crpc.SendPaymentRequest")
+	proto.RegisterType((*PaymentOutput)(nil), "crpc.PaymentOutput")
+	proto.RegisterType((*SendPaymentsRequest)(nil), "crpc.SendPaymentsRequest")
+	proto.RegisterType((*SendPaymentsResponse)(nil), "crpc.SendPaymentsResponse")
+	proto.RegisterType((*QuoteReceiptRequest)(nil), "crpc.QuoteReceiptRequest")
+	proto.RegisterType((*ReceiptQuote)(nil), "crpc.ReceiptQuote")
+	proto.RegisterType((*QuoteReceiptResponse)(nil), "crpc.QuoteReceiptResponse")
+	proto.RegisterType((*QuotePaymentRequest)(nil), "crpc.QuotePaymentRequest")
+	proto.RegisterType((*PaymentQuote)(nil), "crpc.PaymentQuote")
+	proto.RegisterType((*SendTimeLockedPaymentRequest)(nil), "crpc.SendTimeLockedPaymentRequest")
+	proto.RegisterType((*TimeLock)(nil), "crpc.TimeLock")
+	proto.RegisterType((*ListTimeLocksRequest)(nil), "crpc.ListTimeLocksRequest")
+	proto.RegisterType((*ListTimeLocksResponse)(nil), "crpc.ListTimeLocksResponse")
+	proto.RegisterType((*PaymentByIDRequest)(nil), "crpc.PaymentByIDRequest")
+	proto.RegisterType((*LabelPaymentRequest)(nil), "crpc.LabelPaymentRequest")
+	proto.RegisterType((*RefundPaymentRequest)(nil), "crpc.RefundPaymentRequest")
+	proto.RegisterType((*CancelPaymentRequest)(nil), "crpc.CancelPaymentRequest")
+	proto.RegisterType((*PaymentProofRequest)(nil), "crpc.PaymentProofRequest")
+	proto.RegisterType((*PaymentProof)(nil), "crpc.PaymentProof")
+	proto.RegisterType((*TransferFundsRequest)(nil), "crpc.TransferFundsRequest")
+	proto.RegisterType((*TransferFundsResponse)(nil), "crpc.TransferFundsResponse")
+	proto.RegisterType((*CreateAccountRequest)(nil), "crpc.CreateAccountRequest")
+	proto.RegisterType((*Account)(nil), "crpc.Account")
+	proto.RegisterType((*ListAccountsRequest)(nil), "crpc.ListAccountsRequest")
+	proto.RegisterType((*ListAccountsResponse)(nil), "crpc.ListAccountsResponse")
+	proto.RegisterType((*ListDepositAddressesRequest)(nil), "crpc.ListDepositAddressesRequest")
+	proto.RegisterType((*DepositAddress)(nil), "crpc.DepositAddress")
+	proto.RegisterType((*ListDepositAddressesResponse)(nil), "crpc.ListDepositAddressesResponse")
+	proto.RegisterType((*AccountStatementRequest)(nil), "crpc.AccountStatementRequest")
+	proto.RegisterType((*StatementEntry)(nil), "crpc.StatementEntry")
+	proto.RegisterType((*AccountStatementResponse)(nil), "crpc.AccountStatementResponse")
+	proto.RegisterType((*PaymentsByReceiptRequest)(nil), "crpc.PaymentsByReceiptRequest")
+	proto.RegisterType((*PaymentsByReceiptResponse)(nil), "crpc.PaymentsByReceiptResponse")
+	proto.RegisterType((*ListPaymentsRequest)(nil), "crpc.ListPaymentsRequest")
+	proto.RegisterType((*ListPaymentsResponse)(nil), "crpc.ListPaymentsResponse")
+	proto.RegisterType((*ExportPaymentsRequest)(nil), "crpc.ExportPaymentsRequest")
+	proto.RegisterType((*ExportChunk)(nil), "crpc.ExportChunk")
+	proto.RegisterType((*SubscribePaymentsRequest)(nil), "crpc.SubscribePaymentsRequest")
+	proto.RegisterType((*Payee)(nil), "crpc.Payee")
+	proto.RegisterType((*RemovePayeeRequest)(nil), "crpc.RemovePayeeRequest")
+	proto.RegisterType((*Branding)(nil), "crpc.Branding")
+	proto.RegisterType((*RemoveBrandingRequest)(nil), "crpc.RemoveBrandingRequest")
+	proto.RegisterType((*ListPayeesResponse)(nil), "crpc.ListPayeesResponse")
+	proto.RegisterType((*WatchAddress)(nil), "crpc.WatchAddress")
+	proto.RegisterType((*ImportWatchAddressesRequest)(nil), "crpc.ImportWatchAddressesRequest")
+	proto.RegisterType((*AddressBook)(nil), "crpc.AddressBook")
+	proto.RegisterType((*ImportAddressBookResponse)(nil), "crpc.ImportAddressBookResponse")
+	proto.RegisterType((*ImportWatchAddressesResponse)(nil), "crpc.ImportWatchAddressesResponse")
+	proto.RegisterType((*RemoveWatchAddressRequest)(nil), "crpc.RemoveWatchAddressRequest")
+	proto.RegisterType((*ListWatchAddressesRequest)(nil), "crpc.ListWatchAddressesRequest")
+	proto.RegisterType((*ListWatchAddressesResponse)(nil), "crpc.ListWatchAddressesResponse")
+	proto.RegisterType((*WatchEvent)(nil), "crpc.WatchEvent")
+	proto.RegisterType((*ListWatchEventsRequest)(nil), "crpc.ListWatchEventsRequest")
+	proto.RegisterType((*ListWatchEventsResponse)(nil), "crpc.ListWatchEventsResponse")
+	proto.RegisterType((*RedeliverWatchEventsRequest)(nil), "crpc.RedeliverWatchEventsRequest")
+	proto.RegisterType((*RedeliverWatchEventsResponse)(nil), "crpc.RedeliverWatchEventsResponse")
+	proto.RegisterType((*SyncUnspentRequest)(nil), "crpc.SyncUnspentRequest")
+	proto.RegisterType((*GetUnspentSyncStatusRequest)(nil), "crpc.GetUnspentSyncStatusRequest")
+	proto.RegisterType((*UnspentSyncStatus)(nil), "crpc.UnspentSyncStatus")
+	proto.RegisterType((*ListUnspentRequest)(nil), "crpc.ListUnspentRequest")
+	proto.RegisterType((*UnspentOutput)(nil), "crpc.UnspentOutput")
+	proto.RegisterType((*ListUnspentResponse)(nil), "crpc.ListUnspentResponse")
+	proto.RegisterType((*IsOurAddressRequest)(nil), "crpc.IsOurAddressRequest")
+	proto.RegisterType((*IsOurAddressResponse)(nil), "crpc.IsOurAddressResponse")
+	proto.RegisterType((*SignMessageRequest)(nil), "crpc.SignMessageRequest")
+	proto.RegisterType((*SignMessageResponse)(nil), "crpc.SignMessageResponse")
+	proto.RegisterType((*VerifyMessageRequest)(nil), "crpc.VerifyMessageRequest")
+	proto.RegisterType((*VerifyMessageResponse)(nil), "crpc.VerifyMessageResponse")
+	proto.RegisterType((*TransactionByHashRequest)(nil), "crpc.TransactionByHashRequest")
+	proto.RegisterType((*TransactionInput)(nil), "crpc.TransactionInput")
+	proto.RegisterType((*TransactionOutput)(nil), "crpc.TransactionOutput")
+	proto.RegisterType((*Transaction)(nil), "crpc.Transaction")
+	proto.RegisterType((*TransferToPeerRequest)(nil), "crpc.TransferToPeerRequest")
+	proto.RegisterType((*FederationTransfer)(nil), "crpc.FederationTransfer")
+	proto.RegisterType((*FederationPosition)(nil), "crpc.FederationPosition")
+	proto.RegisterType((*ListFederationPositionsResponse)(nil), "crpc.ListFederationPositionsResponse")
+	proto.RegisterType((*SettleFederationRequest)(nil), "crpc.SettleFederationRequest")
+	proto.RegisterType((*FederatedTransfer)(nil), "crpc.FederatedTransfer")
+	proto.RegisterType((*ReceiveTransferResponse)(nil), "crpc.ReceiveTransferResponse")
+	proto.RegisterType((*FederatedSettlement)(nil), "crpc.FederatedSettlement")
+	proto.RegisterType((*SettlementAddressRequest)(nil), "crpc.SettlementAddressRequest")
+	proto.RegisterType((*SettlementAddressResponse)(nil), "crpc.SettlementAddressResponse")
+	proto.RegisterType((*SweepFundsRequest)(nil), "crpc.SweepFundsRequest")
+	proto.RegisterType((*FeeReportRequest)(nil), "crpc.FeeReportRequest")
+	proto.RegisterType((*FeeTotal)(nil), "crpc.FeeTotal")
+	proto.RegisterType((*FeeWindow)(nil), "crpc.FeeWindow")
+	proto.RegisterType((*FeeReportResponse)(nil), "crpc.FeeReportResponse")
+	proto.RegisterType((*PauseWithdrawalsRequest)(nil), "crpc.PauseWithdrawalsRequest")
+	proto.RegisterType((*ResumeWithdrawalsRequest)(nil), "crpc.ResumeWithdrawalsRequest")
+	proto.RegisterType((*WithdrawalPause)(nil), "crpc.WithdrawalPause")
+	proto.RegisterType((*ListWithdrawalPausesResponse)(nil), "crpc.ListWithdrawalPausesResponse")
+	proto.RegisterType((*BalanceInvariant)(nil), "crpc.BalanceInvariant")
+	proto.RegisterType((*BalanceInvariantsResponse)(nil), "crpc.BalanceInvariantsResponse")
+	proto.RegisterType((*RunSelfTestRequest)(nil), "crpc.RunSelfTestRequest")
+	proto.RegisterType((*RunSelfTestResponse)(nil), "crpc.RunSelfTestResponse")
+	proto.RegisterType((*FeatureFlag)(nil), "crpc.FeatureFlag")
+	proto.RegisterType((*ListFeatureFlagsResponse)(nil), "crpc.ListFeatureFlagsResponse")
+	proto.RegisterType((*QuarantinePaymentRequest)(nil), "crpc.QuarantinePaymentRequest")
+	proto.RegisterType((*ReleasePaymentRequest)(nil), "crpc.ReleasePaymentRequest")
+	proto.RegisterType((*ReturnPaymentRequest)(nil), "crpc.ReturnPaymentRequest")
+	proto.RegisterType((*InjectTestPaymentRequest)(nil), "crpc.InjectTestPaymentRequest")
+	proto.RegisterType((*DiagnoseRequest)(nil), "crpc.DiagnoseRequest")
+	proto.RegisterType((*ConfigOption)(nil), "crpc.ConfigOption")
+	proto.RegisterType((*ConnectorHealth)(nil), "crpc.ConnectorHealth")
+	proto.RegisterType((*ErrorCount)(nil), "crpc.ErrorCount")
+	proto.RegisterType((*QueueDepth)(nil), "crpc.QueueDepth")
+	proto.RegisterType((*DiagnoseResponse)(nil), "crpc.DiagnoseResponse")
+	proto.RegisterType((*Payment)(nil), "crpc.Payment")
+	proto.RegisterType((*PaymentEvent)(nil), "crpc.PaymentEvent")
+	proto.RegisterType((*CreateAPIKeyRequest)(nil), "crpc.CreateAPIKeyRequest")
+	proto.RegisterType((*APIKey)(nil), "crpc.APIKey")
+	proto.RegisterType((*CreateAPIKeyResponse)(nil), "crpc.CreateAPIKeyResponse")
+	proto.RegisterType((*RevokeAPIKeyRequest)(nil), "crpc.RevokeAPIKeyRequest")
+	proto.RegisterType((*ListAPIKeysResponse)(nil), "crpc.ListAPIKeysResponse")
+	proto.RegisterType((*PublicKey)(nil), "crpc.PublicKey")
+	proto.RegisterType((*GetPublicKeysResponse)(nil), "crpc.GetPublicKeysResponse")
+	proto.RegisterType((*LightningNodeInfo)(nil), "crpc.LightningNodeInfo")
+	proto.RegisterType((*ConnectorInfo)(nil), "crpc.ConnectorInfo")
+	proto.RegisterType((*ComponentHealth)(nil), "crpc.ComponentHealth")
+	proto.RegisterType((*HealthCheckResponse)(nil), "crpc.HealthCheckResponse")
+	proto.RegisterType((*SelfTestCheck)(nil), "crpc.SelfTestCheck")
+	proto.RegisterType((*SelfTestResult)(nil), "crpc.SelfTestResult")
+	proto.RegisterType((*GetInfoResponse)(nil), "crpc.GetInfoResponse")
+	proto.RegisterType((*AssetInfo)(nil), "crpc.AssetInfo")
+	proto.RegisterType((*AssetsResponse)(nil), "crpc.AssetsResponse")
+	proto.RegisterEnum("crpc.FederationStatus", FederationStatus_name, FederationStatus_value)
+	proto.RegisterEnum("crpc.Asset", Asset_name, Asset_value)
+	proto.RegisterEnum("crpc.Media", Media_name, Media_value)
+	proto.RegisterEnum("crpc.PaymentStatus", PaymentStatus_name, PaymentStatus_value)
+	proto.RegisterEnum("crpc.PaymentDirection", PaymentDirection_name, PaymentDirection_value)
+	proto.RegisterEnum("crpc.QuarantineState", QuarantineState_name, QuarantineState_value)
+	proto.RegisterEnum("crpc.PaymentSystem", PaymentSystem_name, PaymentSystem_value)
+	proto.RegisterEnum("crpc.ExportFormat", ExportFormat_name, ExportFormat_value)
+	proto.RegisterEnum("crpc.PaymentsSortBy", PaymentsSortBy_name, PaymentsSortBy_value)
+	proto.RegisterEnum("crpc.FeeReportPeriod", FeeReportPeriod_name, FeeReportPeriod_value)
+	proto.RegisterEnum("crpc.APIKeyScope", APIKeyScope_name, APIKeyScope_value)
+	proto.RegisterEnum("crpc.PaymentInclude", PaymentInclude_name, PaymentInclude_value)
+	proto.RegisterEnum("crpc.ReceiptStatus", ReceiptStatus_name, ReceiptStatus_value)
+	proto.RegisterEnum("crpc.ReceiptEventType", ReceiptEventType_name, ReceiptEventType_value)
+	proto.RegisterEnum("crpc.PaymentEventType", PaymentEventType_name, PaymentEventType_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for PayServer service
+
+type PayServerClient interface {
+	//
+	// CreateReceipt is used to create blockchain deposit address in
+	// case of blockchain media, and lightning network invoice in
+	// case of the lightning media, which will be used to receive money from
+	// external entity.
+	CreateReceipt(ctx context.Context, in *CreateReceiptRequest, opts ...grpc.CallOption) (*CreateReceiptResponse, error)
+	//
+	// NewAddress returns fresh blockchain address bound to the account,
+	// without creating the receipt, for the integrations which manage
+	// their own invoicing. Incoming payments on the address are detected
+	// and belong to the account, but address isn't returned by the
+	// ListReceipts.
+	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	//
+	// ValidateReceipt is used to validate receipt for given asset and media.
+	ValidateReceipt(ctx context.Context, in *ValidateReceiptRequest, opts ...grpc.CallOption) (*ValidateReceiptResponse, error)
+	//
+	// ListReceipts returns receipts which were created by CreateReceipt,
+	// along with their status, from the newest one.
+	ListReceipts(ctx context.Context, in *ListReceiptsRequest, opts ...grpc.CallOption) (*ListReceiptsResponse, error)
+	//
+	// ReceiptByID returns receipt which was created by CreateReceipt, along
+	// with its status, by the identifier returned on creation.
+	ReceiptByID(ctx context.Context, in *ReceiptByIDRequest, opts ...grpc.CallOption) (*Receipt, error)
+	//
+	// AttachRefundAddress attaches the blockchain address, supplied by the
+	// payer, e.g. on the checkout page, to the receipt. Refunds of the
+	// payments received on the receipt are sent to this address, rather
+	// than to the input addresses of the payment, which are likely owned
+	// by the exchange. Address couldn't be changed once it is attached.
+	AttachRefundAddress(ctx context.Context, in *AttachRefundAddressRequest, opts ...grpc.CallOption) (*Receipt, error)
+	//
+	// Balance is used to determine balance.
+	Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
+	//
+	// BalanceHistory returns the periodic snapshots of the balances from the
+	// oldest one, so that level of the wallet could be charted and its drain
+	// could be noticed.
+	BalanceHistory(ctx context.Context, in *BalanceHistoryRequest, opts ...grpc.CallOption) (*BalanceHistoryResponse, error)
+	//
+	// SubscribeBalance sends balance every time its available or pending
+	// funds are changed by at least the given delta, so that treasury
+	// tooling could react to large deposits without polling Balance.
+	SubscribeBalance(ctx context.Context, in *SubscribeBalanceRequest, opts ...grpc.CallOption) (PayServer_SubscribeBalanceClient, error)
+	//
+	// EstimateFee estimates the fee of the payment.
+	EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error)
+	//
+	// QuotePayment returns the quote of the payment by which recipient
+	// receives exactly the given amount, i.e. the projected network fee and
+	// the total amount which is deducted from the wallet. Quote is valid
+	// for the limited time, and it could be used only once in SendPayment.
+	QuotePayment(ctx context.Context, in *QuotePaymentRequest, opts ...grpc.CallOption) (*PaymentQuote, error)
+	//
+	// QuoteReceipt converts the fiat price into the amount of every enabled
+	// asset and media for which price of the asset is configured, so that
+	// checkout page could render all payment options in one call.
+	QuoteReceipt(ctx context.Context, in *QuoteReceiptRequest, opts ...grpc.CallOption) (*QuoteReceiptResponse, error)
+	//
+	// SendPayment sends payment to the given recipient,
+	// ensures in the validity of the receipt as well as the
+	// account has enough money for doing that.
+	SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// SendPayments sends payments to the several recipients in one
+	// blockchain transaction, so that network fee is paid only once. Fee is
+	// split between the returned payments in proportion to the sizes of
+	// their outputs. Method is gated by the "batching" feature flag of the
+	// API key.
+	SendPayments(ctx context.Context, in *SendPaymentsRequest, opts ...grpc.CallOption) (*SendPaymentsResponse, error)
+	//
+	// SendTimeLockedPayment sends blockchain payment on the script address,
+	// which could be spent by the recipient key only after the given block
+	// height or time, e.g. escrow which is released to the vendor.
+	SendTimeLockedPayment(ctx context.Context, in *SendTimeLockedPaymentRequest, opts ...grpc.CallOption) (*TimeLock, error)
+	//
+	// ListTimeLocks returns time-locked payments along with the state of
+	// their locks, by default only the ones which are still locked.
+	ListTimeLocks(ctx context.Context, in *ListTimeLocksRequest, opts ...grpc.CallOption) (*ListTimeLocksResponse, error)
+	//
+	// PaymentByID is used to fetch the information about payment, by the
+	// given system payment id, along with the timeline of its events.
+	PaymentByID(ctx context.Context, in *PaymentByIDRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// LabelPayment attaches the labels, e.g. order id or customer id, to the
+	// payment. Labels are merged into the metadata of the payment, label
+	// with empty value is removed.
+	LabelPayment(ctx context.Context, in *LabelPaymentRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// RefundPayment sends the completed incoming payment, or the part of it,
+	// back to the given receipt, or to the refund address attached to the
+	// receipt of the payment. Refunds of the payment couldn't exceed its
+	// amount, refund is linked to the refunded payment for the audit.
+	RefundPayment(ctx context.Context, in *RefundPaymentRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// CancelPayment aborts the outgoing blockchain payment which is still
+	// waiting to be sent, i.e. which transaction hasn't been broadcasted yet,
+	// and releases the funds reserved for it. Cancelled payment is marked as
+	// failed.
+	CancelPayment(ctx context.Context, in *CancelPaymentRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// PaymentProof returns the details of the completed payment signed by
+	// the server identity key, so that merchant could hand the customer
+	// the cryptographic confirmation of the payment, which is verified
+	// with the public key returned by GetPublicKeys.
+	PaymentProof(ctx context.Context, in *PaymentProofRequest, opts ...grpc.CallOption) (*PaymentProof, error)
+	//
+	// TransferFunds moves funds between the accounts of the ledger without
+	// the blockchain transaction and the fee. Transfer is recorded as the
+	// pair of internal payments, outgoing one of the source account and
+	// incoming one of the destination account.
+	TransferFunds(ctx context.Context, in *TransferFundsRequest, opts ...grpc.CallOption) (*TransferFundsResponse, error)
+	//
+	// CreateAccount registers the account of the ledger, so that it is
+	// listed by ListAccounts along with its balances. Payments could be
+	// bound to the account before it is registered.
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	//
+	// ListAccounts returns the registered accounts of the caller from the
+	// oldest one, optionally along with their balances which are
+	// aggregated from the payments of the accounts.
+	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	//
+	// ListDepositAddresses returns the blockchain addresses on which
+	// incoming payments of the account are received, i.e. the ones which
+	// were created by NewAddress and CreateReceipt, from the oldest one.
+	ListDepositAddresses(ctx context.Context, in *ListDepositAddressesRequest, opts ...grpc.CallOption) (*ListDepositAddressesResponse, error)
+	//
+	// AccountStatement returns the statement of the account in the given
+	// asset and media for the time window: opening balance, chronological
+	// ledger of credits and debits along with their fees, and closing
+	// balance.
+	AccountStatement(ctx context.Context, in *AccountStatementRequest, opts ...grpc.CallOption) (*AccountStatementResponse, error)
+	//
+	// PaymentsByReceipt is used to fetch the information about payment, by the
+	// given receipt.
+	PaymentsByReceipt(ctx context.Context, in *PaymentsByReceiptRequest, opts ...grpc.CallOption) (*PaymentsByReceiptResponse, error)
+	//
+	// ListPayments returnes list of payment which were registered by the
+	// system.
+	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	//
+	// StreamPayments is the streaming variant of ListPayments, which sends
+	// payments one by one, so that big lists are not limited by the
+	// maximum size of the message.
+	StreamPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (PayServer_StreamPaymentsClient, error)
+	//
+	// ExportPayments streams payments in the format which is suitable for
+	// the accounting software, e.g. CSV. Payments are sent as the chunks of
+	// the export file, which should be written one after another.
+	ExportPayments(ctx context.Context, in *ExportPaymentsRequest, opts ...grpc.CallOption) (PayServer_ExportPaymentsClient, error)
+	//
+	// SubscribePayments sends payment every time its state is changed, e.g.
+	// when it is transitioned from waiting to pending and to completed or
+	// failed, so that clients don't have to poll ListPayments.
+	SubscribePayments(ctx context.Context, in *SubscribePaymentsRequest, opts ...grpc.CallOption) (PayServer_SubscribePaymentsClient, error)
+	//
+	// SubscribeReceipts sends event every time receipt is changed, e.g.
+	// when expired or unpayable invoice is replaced by the new one, so that
+	// checkout pages could show the replacement without reloading.
+	SubscribeReceipts(ctx context.Context, in *SubscribeReceiptsRequest, opts ...grpc.CallOption) (PayServer_SubscribeReceiptsClient, error)
+	//
+	// ListPayees returns list of all registered payee presets.
+	ListPayees(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListPayeesResponse, error)
+	//
+	// ListWatchAddresses returns list of the watched addresses.
+	ListWatchAddresses(ctx context.Context, in *ListWatchAddressesRequest, opts ...grpc.CallOption) (*ListWatchAddressesResponse, error)
+	//
+	// ListWatchEvents returns list of events which were produced by the
+	// activity on watched addresses.
+	ListWatchEvents(ctx context.Context, in *ListWatchEventsRequest, opts ...grpc.CallOption) (*ListWatchEventsResponse, error)
+	//
+	// GetPublicKeys returns public keys of the server identity, which are
+	// used to verify signatures of the webhook bodies and REST responses.
+	GetPublicKeys(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetPublicKeysResponse, error)
+	//
+	// GetInfo returns the version of the server, enabled assets and media,
+	// and the sync state of their daemons, so that clients could detect
+	// supported features and monitor the server.
+	GetInfo(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	//
+	// HealthCheck checks the components of the server: reachability of the
+	// blockchain daemons, sync of the lightning network nodes and
+	// writability of the database. The same check drives the serving status
+	// of the standard grpc.health.v1 service, which is used by the probes.
+	HealthCheck(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+	//
+	// Assets returns the parameters of every enabled asset: precision of
+	// the amounts, limits of the payment amount, dust limit, required
+	// confirmations and available media, so that clients don't have to
+	// hardcode them.
+	Assets(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*AssetsResponse, error)
+	//
+	// GetBranding returns the logo, colors and success redirect URL with
+	// which the hosted checkout and QR pages of the caller are rendered.
+	// Default branding is returned if caller doesn't have its own.
+	GetBranding(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*Branding, error)
+}
+
+type payServerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPayServerClient(cc *grpc.ClientConn) PayServerClient {
+	return &payServerClient{cc}
+}
+
+func (c *payServerClient) CreateReceipt(ctx context.Context, in *CreateReceiptRequest, opts ...grpc.CallOption) (*CreateReceiptResponse, error) {
+	out := new(CreateReceiptResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/CreateReceipt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
+	out := new(NewAddressResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/NewAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) ValidateReceipt(ctx context.Context, in *ValidateReceiptRequest, opts ...grpc.CallOption) (*ValidateReceiptResponse, error) {
+	out := new(ValidateReceiptResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ValidateReceipt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) ListReceipts(ctx context.Context, in *ListReceiptsRequest, opts ...grpc.CallOption) (*ListReceiptsResponse, error) {
+	out := new(ListReceiptsResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ListReceipts", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) ReceiptByID(ctx context.Context, in *ReceiptByIDRequest, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ReceiptByID", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) AttachRefundAddress(ctx context.Context, in *AttachRefundAddressRequest, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/AttachRefundAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
+	out := new(BalanceResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/Balance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) BalanceHistory(ctx context.Context, in *BalanceHistoryRequest, opts ...grpc.CallOption) (*BalanceHistoryResponse, error) {
+	out := new(BalanceHistoryResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/BalanceHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) SubscribeBalance(ctx context.Context, in *SubscribeBalanceRequest, opts ...grpc.CallOption) (PayServer_SubscribeBalanceClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_PayServer_serviceDesc.Streams[0], c.cc, "/crpc.PayServer/SubscribeBalance", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &payServerSubscribeBalanceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PayServer_SubscribeBalanceClient interface {
+	Recv() (*BalanceUpdate, error)
+	grpc.ClientStream
+}
+
+type payServerSubscribeBalanceClient struct {
+	grpc.ClientStream
+}
+
+func (x *payServerSubscribeBalanceClient) Recv() (*BalanceUpdate, error) {
+	m := new(BalanceUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *payServerClient) EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error) {
+	out := new(EstimateFeeResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/EstimateFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) QuotePayment(ctx context.Context, in *QuotePaymentRequest, opts ...grpc.CallOption) (*PaymentQuote, error) {
+	out := new(PaymentQuote)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/QuotePayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) QuoteReceipt(ctx context.Context, in *QuoteReceiptRequest, opts ...grpc.CallOption) (*QuoteReceiptResponse, error) {
+	out := new(QuoteReceiptResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/QuoteReceipt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/SendPayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) SendPayments(ctx context.Context, in *SendPaymentsRequest, opts ...grpc.CallOption) (*SendPaymentsResponse, error) {
+	out := new(SendPaymentsResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/SendPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) SendTimeLockedPayment(ctx context.Context, in *SendTimeLockedPaymentRequest, opts ...grpc.CallOption) (*TimeLock, error) {
+	out := new(TimeLock)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/SendTimeLockedPayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) ListTimeLocks(ctx context.Context, in *ListTimeLocksRequest, opts ...grpc.CallOption) (*ListTimeLocksResponse, error) {
+	out := new(ListTimeLocksResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ListTimeLocks", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) PaymentByID(ctx context.Context, in *PaymentByIDRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/PaymentByID", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) LabelPayment(ctx context.Context, in *LabelPaymentRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/LabelPayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) RefundPayment(ctx context.Context, in *RefundPaymentRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/RefundPayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) CancelPayment(ctx context.Context, in *CancelPaymentRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/CancelPayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) PaymentProof(ctx context.Context, in *PaymentProofRequest, opts ...grpc.CallOption) (*PaymentProof, error) {
+	out := new(PaymentProof)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/PaymentProof", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) TransferFunds(ctx context.Context, in *TransferFundsRequest, opts ...grpc.CallOption) (*TransferFundsResponse, error) {
+	out := new(TransferFundsResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/TransferFunds", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/CreateAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	out := new(ListAccountsResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ListAccounts", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) ListDepositAddresses(ctx context.Context, in *ListDepositAddressesRequest, opts ...grpc.CallOption) (*ListDepositAddressesResponse, error) {
+	out := new(ListDepositAddressesResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ListDepositAddresses", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) AccountStatement(ctx context.Context, in *AccountStatementRequest, opts ...grpc.CallOption) (*AccountStatementResponse, error) {
+	out := new(AccountStatementResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/AccountStatement", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) PaymentsByReceipt(ctx context.Context, in *PaymentsByReceiptRequest, opts ...grpc.CallOption) (*PaymentsByReceiptResponse, error) {
+	out := new(PaymentsByReceiptResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/PaymentsByReceipt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
+	out := new(ListPaymentsResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ListPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) StreamPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (PayServer_StreamPaymentsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_PayServer_serviceDesc.Streams[1], c.cc, "/crpc.PayServer/StreamPayments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &payServerStreamPaymentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PayServer_StreamPaymentsClient interface {
+	Recv() (*Payment, error)
+	grpc.ClientStream
+}
+
+type payServerStreamPaymentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *payServerStreamPaymentsClient) Recv() (*Payment, error) {
+	m := new(Payment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *payServerClient) ExportPayments(ctx context.Context, in *ExportPaymentsRequest, opts ...grpc.CallOption) (PayServer_ExportPaymentsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_PayServer_serviceDesc.Streams[2], c.cc, "/crpc.PayServer/ExportPayments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &payServerExportPaymentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PayServer_ExportPaymentsClient interface {
+	Recv() (*ExportChunk, error)
+	grpc.ClientStream
+}
+
+type payServerExportPaymentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *payServerExportPaymentsClient) Recv() (*ExportChunk, error) {
+	m := new(ExportChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *payServerClient) SubscribePayments(ctx context.Context, in *SubscribePaymentsRequest, opts ...grpc.CallOption) (PayServer_SubscribePaymentsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_PayServer_serviceDesc.Streams[3], c.cc, "/crpc.PayServer/SubscribePayments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &payServerSubscribePaymentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PayServer_SubscribePaymentsClient interface {
+	Recv() (*Payment, error)
+	grpc.ClientStream
+}
+
+type payServerSubscribePaymentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *payServerSubscribePaymentsClient) Recv() (*Payment, error) {
+	m := new(Payment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *payServerClient) SubscribeReceipts(ctx context.Context, in *SubscribeReceiptsRequest, opts ...grpc.CallOption) (PayServer_SubscribeReceiptsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_PayServer_serviceDesc.Streams[4], c.cc, "/crpc.PayServer/SubscribeReceipts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &payServerSubscribeReceiptsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PayServer_SubscribeReceiptsClient interface {
+	Recv() (*ReceiptEvent, error)
+	grpc.ClientStream
+}
+
+type payServerSubscribeReceiptsClient struct {
+	grpc.ClientStream
+}
+
+func (x *payServerSubscribeReceiptsClient) Recv() (*ReceiptEvent, error) {
+	m := new(ReceiptEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *payServerClient) ListPayees(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListPayeesResponse, error) {
+	out := new(ListPayeesResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ListPayees", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) ListWatchAddresses(ctx context.Context, in *ListWatchAddressesRequest, opts ...grpc.CallOption) (*ListWatchAddressesResponse, error) {
+	out := new(ListWatchAddressesResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ListWatchAddresses", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) ListWatchEvents(ctx context.Context, in *ListWatchEventsRequest, opts ...grpc.CallOption) (*ListWatchEventsResponse, error) {
+	out := new(ListWatchEventsResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/ListWatchEvents", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) GetPublicKeys(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetPublicKeysResponse, error) {
+	out := new(GetPublicKeysResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/GetPublicKeys", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) GetInfo(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
+	out := new(GetInfoResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/GetInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) HealthCheck(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
+	out := new(HealthCheckResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/HealthCheck", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) Assets(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*AssetsResponse, error) {
+	out := new(AssetsResponse)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/Assets", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *payServerClient) GetBranding(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*Branding, error) {
+	out := new(Branding)
+	err := grpc.Invoke(ctx, "/crpc.PayServer/GetBranding", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for PayServer service
+
+type PayServerServer interface {
+	//
+	// CreateReceipt is used to create blockchain deposit address in
+	// case of blockchain media, and lightning network invoice in
+	// case of the lightning media, which will be used to receive money from
+	// external entity.
+	CreateReceipt(context.Context, *CreateReceiptRequest) (*CreateReceiptResponse, error)
+	//
+	// NewAddress returns fresh blockchain address bound to the account,
+	// without creating the receipt, for the integrations which manage
+	// their own invoicing. Incoming payments on the address are detected
+	// and belong to the account, but address isn't returned by the
+	// ListReceipts.
+	NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error)
+	//
+	// ValidateReceipt is used to validate receipt for given asset and media.
+	ValidateReceipt(context.Context, *ValidateReceiptRequest) (*ValidateReceiptResponse, error)
+	//
+	// ListReceipts returns receipts which were created by CreateReceipt,
+	// along with their status, from the newest one.
+	ListReceipts(context.Context, *ListReceiptsRequest) (*ListReceiptsResponse, error)
+	//
+	// ReceiptByID returns receipt which was created by CreateReceipt, along
+	// with its status, by the identifier returned on creation.
+	ReceiptByID(context.Context, *ReceiptByIDRequest) (*Receipt, error)
+	//
+	// AttachRefundAddress attaches the blockchain address, supplied by the
+	// payer, e.g. on the checkout page, to the receipt. Refunds of the
+	// payments received on the receipt are sent to this address, rather
+	// than to the input addresses of the payment, which are likely owned
+	// by the exchange. Address couldn't be changed once it is attached.
+	AttachRefundAddress(context.Context, *AttachRefundAddressRequest) (*Receipt, error)
+	//
+	// Balance is used to determine balance.
+	Balance(context.Context, *BalanceRequest) (*BalanceResponse, error)
+	//
+	// BalanceHistory returns the periodic snapshots of the balances from the
+	// oldest one, so that level of the wallet could be charted and its drain
+	// could be noticed.
+	BalanceHistory(context.Context, *BalanceHistoryRequest) (*BalanceHistoryResponse, error)
+	//
+	// SubscribeBalance sends balance every time its available or pending
+	// funds are changed by at least the given delta, so that treasury
+	// tooling could react to large deposits without polling Balance.
+	SubscribeBalance(*SubscribeBalanceRequest, PayServer_SubscribeBalanceServer) error
+	//
+	// EstimateFee estimates the fee of the payment.
+	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
+	//
+	// QuotePayment returns the quote of the payment by which recipient
+	// receives exactly the given amount, i.e. the projected network fee and
+	// the total amount which is deducted from the wallet. Quote is valid
+	// for the limited time, and it could be used only once in SendPayment.
+	QuotePayment(context.Context, *QuotePaymentRequest) (*PaymentQuote, error)
+	//
+	// QuoteReceipt converts the fiat price into the amount of every enabled
+	// asset and media for which price of the asset is configured, so that
+	// checkout page could render all payment options in one call.
+	QuoteReceipt(context.Context, *QuoteReceiptRequest) (*QuoteReceiptResponse, error)
+	//
+	// SendPayment sends payment to the given recipient,
+	// ensures in the validity of the receipt as well as the
+	// account has enough money for doing that.
+	SendPayment(context.Context, *SendPaymentRequest) (*Payment, error)
+	//
+	// SendPayments sends payments to the several recipients in one
+	// blockchain transaction, so that network fee is paid only once. Fee is
+	// split between the returned payments in proportion to the sizes of
+	// their outputs. Method is gated by the "batching" feature flag of the
+	// API key.
+	SendPayments(context.Context, *SendPaymentsRequest) (*SendPaymentsResponse, error)
+	//
+	// SendTimeLockedPayment sends blockchain payment on the script address,
+	// which could be spent by the recipient key only after the given block
+	// height or time, e.g. escrow which is released to the vendor.
+	SendTimeLockedPayment(context.Context, *SendTimeLockedPaymentRequest) (*TimeLock, error)
+	//
+	// ListTimeLocks returns time-locked payments along with the state of
+	// their locks, by default only the ones which are still locked.
+	ListTimeLocks(context.Context, *ListTimeLocksRequest) (*ListTimeLocksResponse, error)
+	//
+	// PaymentByID is used to fetch the information about payment, by the
+	// given system payment id, along with the timeline of its events.
+	PaymentByID(context.Context, *PaymentByIDRequest) (*Payment, error)
+	//
+	// LabelPayment attaches the labels, e.g. order id or customer id, to the
+	// payment. Labels are merged into the metadata of the payment, label
+	// with empty value is removed.
+	LabelPayment(context.Context, *LabelPaymentRequest) (*Payment, error)
+	//
+	// RefundPayment sends the completed incoming payment, or the part of it,
+	// back to the given receipt, or to the refund address attached to the
+	// receipt of the payment. Refunds of the payment couldn't exceed its
+	// amount, refund is linked to the refunded payment for the audit.
+	RefundPayment(context.Context, *RefundPaymentRequest) (*Payment, error)
+	//
+	// CancelPayment aborts the outgoing blockchain payment which is still
+	// waiting to be sent, i.e. which transaction hasn't been broadcasted yet,
+	// and releases the funds reserved for it. Cancelled payment is marked as
+	// failed.
+	CancelPayment(context.Context, *CancelPaymentRequest) (*Payment, error)
+	//
+	// PaymentProof returns the details of the completed payment signed by
+	// the server identity key, so that merchant could hand the customer
+	// the cryptographic confirmation of the payment, which is verified
+	// with the public key returned by GetPublicKeys.
+	PaymentProof(context.Context, *PaymentProofRequest) (*PaymentProof, error)
+	//
+	// TransferFunds moves funds between the accounts of the ledger without
+	// the blockchain transaction and the fee. Transfer is recorded as the
+	// pair of internal payments, outgoing one of the source account and
+	// incoming one of the destination account.
+	TransferFunds(context.Context, *TransferFundsRequest) (*TransferFundsResponse, error)
+	//
+	// CreateAccount registers the account of the ledger, so that it is
+	// listed by ListAccounts along with its balances. Payments could be
+	// bound to the account before it is registered.
+	CreateAccount(context.Context, *CreateAccountRequest) (*Account, error)
+	//
+	// ListAccounts returns the registered accounts of the caller from the
+	// oldest one, optionally along with their balances which are
+	// aggregated from the payments of the accounts.
+	ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
+	//
+	// ListDepositAddresses returns the blockchain addresses on which
+	// incoming payments of the account are received, i.e. the ones which
+	// were created by NewAddress and CreateReceipt, from the oldest one.
+	ListDepositAddresses(context.Context, *ListDepositAddressesRequest) (*ListDepositAddressesResponse, error)
+	//
+	// AccountStatement returns the statement of the account in the given
+	// asset and media for the time window: opening balance, chronological
+	// ledger of credits and debits along with their fees, and closing
+	// balance.
+	AccountStatement(context.Context, *AccountStatementRequest) (*AccountStatementResponse, error)
+	//
+	// PaymentsByReceipt is used to fetch the information about payment, by the
+	// given receipt.
+	PaymentsByReceipt(context.Context, *PaymentsByReceiptRequest) (*PaymentsByReceiptResponse, error)
+	//
+	// ListPayments returnes list of payment which were registered by the
+	// system.
+	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
+	//
+	// StreamPayments is the streaming variant of ListPayments, which sends
+	// payments one by one, so that big lists are not limited by the
+	// maximum size of the message.
+	StreamPayments(*ListPaymentsRequest, PayServer_StreamPaymentsServer) error
+	//
+	// ExportPayments streams payments in the format which is suitable for
+	// the accounting software, e.g. CSV. Payments are sent as the chunks of
+	// the export file, which should be written one after another.
+	ExportPayments(*ExportPaymentsRequest, PayServer_ExportPaymentsServer) error
+	//
+	// SubscribePayments sends payment every time its state is changed, e.g.
+	// when it is transitioned from waiting to pending and to completed or
+	// failed, so that clients don't have to poll ListPayments.
+	SubscribePayments(*SubscribePaymentsRequest, PayServer_SubscribePaymentsServer) error
+	//
+	// SubscribeReceipts sends event every time receipt is changed, e.g.
+	// when expired or unpayable invoice is replaced by the new one, so that
+	// checkout pages could show the replacement without reloading.
+	SubscribeReceipts(*SubscribeReceiptsRequest, PayServer_SubscribeReceiptsServer) error
+	//
+	// ListPayees returns list of all registered payee presets.
+	ListPayees(context.Context, *EmptyRequest) (*ListPayeesResponse, error)
+	//
+	// ListWatchAddresses returns list of the watched addresses.
+	ListWatchAddresses(context.Context, *ListWatchAddressesRequest) (*ListWatchAddressesResponse, error)
+	//
+	// ListWatchEvents returns list of events which were produced by the
+	// activity on watched addresses.
+	ListWatchEvents(context.Context, *ListWatchEventsRequest) (*ListWatchEventsResponse, error)
+	//
+	// GetPublicKeys returns public keys of the server identity, which are
+	// used to verify signatures of the webhook bodies and REST responses.
+	GetPublicKeys(context.Context, *EmptyRequest) (*GetPublicKeysResponse, error)
+	//
+	// GetInfo returns the version of the server, enabled assets and media,
+	// and the sync state of their daemons, so that clients could detect
+	// supported features and monitor the server.
+	GetInfo(context.Context, *EmptyRequest) (*GetInfoResponse, error)
+	//
+	// HealthCheck checks the components of the server: reachability of the
+	// blockchain daemons, sync of the lightning network nodes and
+	// writability of the database. The same check drives the serving status
+	// of the standard grpc.health.v1 service, which is used by the probes.
+	HealthCheck(context.Context, *EmptyRequest) (*HealthCheckResponse, error)
+	//
+	// Assets returns the parameters of every enabled asset: precision of
+	// the amounts, limits of the payment amount, dust limit, required
+	// confirmations and available media, so that clients don't have to
+	// hardcode them.
+	Assets(context.Context, *EmptyRequest) (*AssetsResponse, error)
+	//
+	// GetBranding returns the logo, colors and success redirect URL with
+	// which the hosted checkout and QR pages of the caller are rendered.
+	// Default branding is returned if caller doesn't have its own.
+	GetBranding(context.Context, *EmptyRequest) (*Branding, error)
+}
+
+func RegisterPayServerServer(s *grpc.Server, srv PayServerServer) {
+	s.RegisterService(&_PayServer_serviceDesc, srv)
+}
+
+func _PayServer_CreateReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).CreateReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/CreateReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).CreateReceipt(ctx, req.(*CreateReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).NewAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/NewAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).NewAddress(ctx, req.(*NewAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_ValidateReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ValidateReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ValidateReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ValidateReceipt(ctx, req.(*ValidateReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_ListReceipts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReceiptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ListReceipts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ListReceipts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ListReceipts(ctx, req.(*ListReceiptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_ReceiptByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceiptByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ReceiptByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ReceiptByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ReceiptByID(ctx, req.(*ReceiptByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_AttachRefundAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachRefundAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).AttachRefundAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/AttachRefundAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).AttachRefundAddress(ctx, req.(*AttachRefundAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_Balance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).Balance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/Balance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).Balance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_BalanceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).BalanceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/BalanceHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).BalanceHistory(ctx, req.(*BalanceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_SubscribeBalance_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBalanceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PayServerServer).SubscribeBalance(m, &payServerSubscribeBalanceServer{stream})
+}
+
+type PayServer_SubscribeBalanceServer interface {
+	Send(*BalanceUpdate) error
+	grpc.ServerStream
+}
+
+type payServerSubscribeBalanceServer struct {
+	grpc.ServerStream
+}
+
+func (x *payServerSubscribeBalanceServer) Send(m *BalanceUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PayServer_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).EstimateFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/EstimateFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).EstimateFee(ctx, req.(*EstimateFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_QuotePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuotePaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).QuotePayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/QuotePayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).QuotePayment(ctx, req.(*QuotePaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_QuoteReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuoteReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).QuoteReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/QuoteReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).QuoteReceipt(ctx, req.(*QuoteReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_SendPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).SendPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/SendPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).SendPayment(ctx, req.(*SendPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_SendPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).SendPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/SendPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).SendPayments(ctx, req.(*SendPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_SendTimeLockedPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendTimeLockedPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).SendTimeLockedPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/SendTimeLockedPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).SendTimeLockedPayment(ctx, req.(*SendTimeLockedPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_ListTimeLocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTimeLocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ListTimeLocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ListTimeLocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ListTimeLocks(ctx, req.(*ListTimeLocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_PaymentByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).PaymentByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/PaymentByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).PaymentByID(ctx, req.(*PaymentByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_LabelPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).LabelPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/LabelPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).LabelPayment(ctx, req.(*LabelPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_RefundPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).RefundPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/RefundPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).RefundPayment(ctx, req.(*RefundPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_CancelPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).CancelPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/CancelPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).CancelPayment(ctx, req.(*CancelPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_PaymentProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).PaymentProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/PaymentProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).PaymentProof(ctx, req.(*PaymentProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_TransferFunds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferFundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).TransferFunds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/TransferFunds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).TransferFunds(ctx, req.(*TransferFundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/CreateAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ListAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ListAccounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ListAccounts(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_ListDepositAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDepositAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ListDepositAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ListDepositAddresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ListDepositAddresses(ctx, req.(*ListDepositAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_AccountStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).AccountStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/AccountStatement",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).AccountStatement(ctx, req.(*AccountStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_PaymentsByReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentsByReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).PaymentsByReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/PaymentsByReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).PaymentsByReceipt(ctx, req.(*PaymentsByReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ListPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ListPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_StreamPayments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPaymentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PayServerServer).StreamPayments(m, &payServerStreamPaymentsServer{stream})
+}
+
+type PayServer_StreamPaymentsServer interface {
+	Send(*Payment) error
+	grpc.ServerStream
+}
+
+type payServerStreamPaymentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *payServerStreamPaymentsServer) Send(m *Payment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PayServer_ExportPayments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportPaymentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PayServerServer).ExportPayments(m, &payServerExportPaymentsServer{stream})
+}
+
+type PayServer_ExportPaymentsServer interface {
+	Send(*ExportChunk) error
+	grpc.ServerStream
+}
+
+type payServerExportPaymentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *payServerExportPaymentsServer) Send(m *ExportChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PayServer_SubscribePayments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribePaymentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PayServerServer).SubscribePayments(m, &payServerSubscribePaymentsServer{stream})
+}
+
+type PayServer_SubscribePaymentsServer interface {
+	Send(*Payment) error
+	grpc.ServerStream
+}
+
+type payServerSubscribePaymentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *payServerSubscribePaymentsServer) Send(m *Payment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PayServer_SubscribeReceipts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeReceiptsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PayServerServer).SubscribeReceipts(m, &payServerSubscribeReceiptsServer{stream})
+}
+
+type PayServer_SubscribeReceiptsServer interface {
+	Send(*ReceiptEvent) error
+	grpc.ServerStream
+}
+
+type payServerSubscribeReceiptsServer struct {
+	grpc.ServerStream
+}
+
+func (x *payServerSubscribeReceiptsServer) Send(m *ReceiptEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PayServer_ListPayees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ListPayees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ListPayees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ListPayees(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_ListWatchAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWatchAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ListWatchAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ListWatchAddresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ListWatchAddresses(ctx, req.(*ListWatchAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_ListWatchEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWatchEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).ListWatchEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/ListWatchEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).ListWatchEvents(ctx, req.(*ListWatchEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_GetPublicKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).GetPublicKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/GetPublicKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).GetPublicKeys(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).GetInfo(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).HealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/HealthCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).HealthCheck(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_Assets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).Assets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/Assets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).Assets(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PayServer_GetBranding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PayServerServer).GetBranding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.PayServer/GetBranding",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PayServerServer).GetBranding(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PayServer_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "crpc.PayServer",
+	HandlerType: (*PayServerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateReceipt",
+			Handler:    _PayServer_CreateReceipt_Handler,
+		},
+		{
+			MethodName: "NewAddress",
+			Handler:    _PayServer_NewAddress_Handler,
+		},
+		{
+			MethodName: "ValidateReceipt",
+			Handler:    _PayServer_ValidateReceipt_Handler,
+		},
+		{
+			MethodName: "ListReceipts",
+			Handler:    _PayServer_ListReceipts_Handler,
+		},
+		{
+			MethodName: "ReceiptByID",
+			Handler:    _PayServer_ReceiptByID_Handler,
+		},
+		{
+			MethodName: "AttachRefundAddress",
+			Handler:    _PayServer_AttachRefundAddress_Handler,
+		},
+		{
+			MethodName: "Balance",
+			Handler:    _PayServer_Balance_Handler,
+		},
+		{
+			MethodName: "BalanceHistory",
+			Handler:    _PayServer_BalanceHistory_Handler,
+		},
+		{
+			MethodName: "EstimateFee",
+			Handler:    _PayServer_EstimateFee_Handler,
+		},
+		{
+			MethodName: "QuotePayment",
+			Handler:    _PayServer_QuotePayment_Handler,
+		},
+		{
+			MethodName: "QuoteReceipt",
+			Handler:    _PayServer_QuoteReceipt_Handler,
+		},
+		{
+			MethodName: "SendPayment",
+			Handler:    _PayServer_SendPayment_Handler,
+		},
+		{
+			MethodName: "SendPayments",
+			Handler:    _PayServer_SendPayments_Handler,
+		},
+		{
+			MethodName: "SendTimeLockedPayment",
+			Handler:    _PayServer_SendTimeLockedPayment_Handler,
+		},
+		{
+			MethodName: "ListTimeLocks",
+			Handler:    _PayServer_ListTimeLocks_Handler,
+		},
+		{
+			MethodName: "PaymentByID",
+			Handler:    _PayServer_PaymentByID_Handler,
+		},
+		{
+			MethodName: "LabelPayment",
+			Handler:    _PayServer_LabelPayment_Handler,
+		},
+		{
+			MethodName: "RefundPayment",
+			Handler:    _PayServer_RefundPayment_Handler,
+		},
+		{
+			MethodName: "CancelPayment",
+			Handler:    _PayServer_CancelPayment_Handler,
+		},
+		{
+			MethodName: "PaymentProof",
+			Handler:    _PayServer_PaymentProof_Handler,
+		},
+		{
+			MethodName: "TransferFunds",
+			Handler:    _PayServer_TransferFunds_Handler,
+		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _PayServer_CreateAccount_Handler,
+		},
+		{
+			MethodName: "ListAccounts",
+			Handler:    _PayServer_ListAccounts_Handler,
+		},
+		{
+			MethodName: "ListDepositAddresses",
+			Handler:    _PayServer_ListDepositAddresses_Handler,
+		},
+		{
+			MethodName: "AccountStatement",
+			Handler:    _PayServer_AccountStatement_Handler,
+		},
+		{
+			MethodName: "PaymentsByReceipt",
+			Handler:    _PayServer_PaymentsByReceipt_Handler,
+		},
+		{
+			MethodName: "ListPayments",
+			Handler:    _PayServer_ListPayments_Handler,
+		},
+		{
+			MethodName: "ListPayees",
+			Handler:    _PayServer_ListPayees_Handler,
+		},
+		{
+			MethodName: "ListWatchAddresses",
+			Handler:    _PayServer_ListWatchAddresses_Handler,
+		},
+		{
+			MethodName: "ListWatchEvents",
+			Handler:    _PayServer_ListWatchEvents_Handler,
+		},
+		{
+			MethodName: "GetPublicKeys",
+			Handler:    _PayServer_GetPublicKeys_Handler,
+		},
+		{
+			MethodName: "GetInfo",
+			Handler:    _PayServer_GetInfo_Handler,
+		},
+		{
+			MethodName: "HealthCheck",
+			Handler:    _PayServer_HealthCheck_Handler,
+		},
+		{
+			MethodName: "Assets",
+			Handler:    _PayServer_Assets_Handler,
+		},
+		{
+			MethodName: "GetBranding",
+			Handler:    _PayServer_GetBranding_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBalance",
+			Handler:       _PayServer_SubscribeBalance_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamPayments",
+			Handler:       _PayServer_StreamPayments_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportPayments",
+			Handler:       _PayServer_ExportPayments_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribePayments",
+			Handler:       _PayServer_SubscribePayments_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeReceipts",
+			Handler:       _PayServer_SubscribeReceipts_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc.proto",
+}
+
+// Client API for Admin service
+
+type AdminClient interface {
+	//
+	// SetPayee creates or updates the payee preset, which could be used
+	// later in the send payment request instead of the raw receipt.
+	SetPayee(ctx context.Context, in *Payee, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// RemovePayee removes the payee preset by its name.
+	RemovePayee(ctx context.Context, in *RemovePayeeRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// SetBranding creates or updates the branding of the hosted checkout
+	// and QR pages of the tenant, so that they could be used by the
+	// white-label partners.
+	SetBranding(ctx context.Context, in *Branding, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// RemoveBranding removes the branding of the tenant, after that default
+	// branding is used.
+	RemoveBranding(ctx context.Context, in *RemoveBrandingRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// AddWatchAddress starts tracking activity of the address which doesn't
+	// belong to us, e.g. old legacy wallet or address of the attacker.
+	// Activity on this address produces watch event with group label.
+	// Funding of the address is also sent by SubscribePayments as the
+	// received payment with the watch_group metadata.
+	AddWatchAddress(ctx context.Context, in *WatchAddress, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// ImportWatchAddresses adds the list of externally generated addresses,
+	// e.g. deposit addresses of the partner, to the watched ones. Import is
+	// idempotent, addresses which are already watched are updated with the
+	// given group, account and activation status. Import is atomic, if any
+	// of the addresses is invalid nothing is imported.
+	ImportWatchAddresses(ctx context.Context, in *ImportWatchAddressesRequest, opts ...grpc.CallOption) (*ImportWatchAddressesResponse, error)
+	//
+	// RemoveWatchAddress stops tracking activity of the address.
+	RemoveWatchAddress(ctx context.Context, in *RemoveWatchAddressRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// RedeliverWatchEvents sends again the already delivered watch events
+	// of the group, which were detected within the time range, so that
+	// listener could recover after the outage on its side. Events are
+	// delivered in the background, in the order they were detected, and
+	// are marked as redelivery in the webhook body.
+	RedeliverWatchEvents(ctx context.Context, in *RedeliverWatchEventsRequest, opts ...grpc.CallOption) (*RedeliverWatchEventsResponse, error)
+	//
+	// ExportAddressBook returns the payee presets and the watched addresses
+	// as the JSON bundle signed by the server identity key, so that the
+	// whole destination surface could be reviewed and migrated to another
+	// environment.
+	ExportAddressBook(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*AddressBook, error)
+	//
+	// ImportAddressBook verifies the signature of the bundle exported by
+	// ExportAddressBook and imports its payee presets and watched
+	// addresses. Entries are validated before anything is imported,
+	// existing entries are updated, so that import could be repeated.
+	ImportAddressBook(ctx context.Context, in *AddressBook, opts ...grpc.CallOption) (*ImportAddressBookResponse, error)
+	//
+	// SyncUnspent triggers the sync of the wallet unspent outputs with the
+	// blockchain daemon and waits for it to finish. Forced sync resyncs
+	// state from the scratch, which is the remedy for the stale state.
+	SyncUnspent(ctx context.Context, in *SyncUnspentRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// GetUnspentSyncStatus returns the state of the sync of the wallet
+	// unspent outputs, and inconsistencies found against the daemon.
+	GetUnspentSyncStatus(ctx context.Context, in *GetUnspentSyncStatusRequest, opts ...grpc.CallOption) (*UnspentSyncStatus, error)
+	//
+	// ListUnspent returns the wallet unspent outputs of the asset, so that
+	// fragmentation of the wallet could be inspected and failures of the
+	// coin selection could be debugged.
+	ListUnspent(ctx context.Context, in *ListUnspentRequest, opts ...grpc.CallOption) (*ListUnspentResponse, error)
+	//
+	// TransactionByHash returns the details of the wallet transaction of
+	// the asset: inputs, outputs, fee, confirmations, block and raw
+	// transaction, so that stuck payments could be investigated without
+	// access to the daemon.
+	TransactionByHash(ctx context.Context, in *TransactionByHashRequest, opts ...grpc.CallOption) (*Transaction, error)
+	//
+	// IsOurAddress reports whether the blockchain address belongs to the
+	// wallet of the server, and to which account incoming payments on it
+	// are bound, so that misdirected deposits could be traced.
+	IsOurAddress(ctx context.Context, in *IsOurAddressRequest, opts ...grpc.CallOption) (*IsOurAddressResponse, error)
+	//
+	// SignMessage signs the message with the key of the wallet address or
+	// of the lightning node, so that their ownership could be proven to
+	// the exchanges and auditors.
+	SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error)
+	//
+	// VerifyMessage verifies the signature of the message made by the
+	// blockchain address or by the lightning node.
+	VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error)
+	//
+	// TransferToPeer moves funds from the account of this server to the
+	// account of the federation peer, e.g. the payserver of another
+	// region, without the blockchain transaction. Account is debited
+	// right away, transfer is completed once peer credits it, and net
+	// positions with the peer are settled on-chain periodically.
+	TransferToPeer(ctx context.Context, in *TransferToPeerRequest, opts ...grpc.CallOption) (*FederationTransfer, error)
+	//
+	// ListFederationPositions returns the net positions with the
+	// federation peers, i.e. the amounts which are owed to the peers or
+	// by them and are not settled yet.
+	ListFederationPositions(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListFederationPositionsResponse, error)
+	//
+	// SettleFederation sends the amount which is owed to the federation
+	// peer in the asset on-chain right away, without waiting for the
+	// periodic settlement.
+	SettleFederation(ctx context.Context, in *SettleFederationRequest, opts ...grpc.CallOption) (*FederationPosition, error)
+	//
+	// SweepFunds sends all confirmed funds of the asset wallet to the
+	// address, fee is subtracted from the sent amount. It is used on the
+	// rotation of the cold storage and on the decommissioning of the node.
+	SweepFunds(ctx context.Context, in *SweepFundsRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// FeeReport summarizes the network fees paid by the completed outgoing
+	// payments per asset and media over the day, week or month windows, so
+	// that fee spend could be tracked as a cost center and batching could
+	// be tuned accordingly.
+	FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error)
+	//
+	// PauseWithdrawals rejects every outgoing payment of the asset, in both
+	// media, until withdrawals are resumed, so that funds stop leaving the
+	// wallet during the incident without the shutdown of the service.
+	// Pause is kept across restarts.
+	PauseWithdrawals(ctx context.Context, in *PauseWithdrawalsRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// ResumeWithdrawals removes the pause of the outgoing payments of the
+	// asset.
+	ResumeWithdrawals(ctx context.Context, in *ResumeWithdrawalsRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// ListWithdrawalPauses returns assets which outgoing payments are
+	// paused.
+	ListWithdrawalPauses(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListWithdrawalPausesResponse, error)
+	//
+	// BalanceInvariants compares the total of the ledger, i.e. incoming
+	// payments minus outgoing payments with their fees, with the funds
+	// held on-chain and in the lightning network for every asset, and
+	// returns the state of the sends circuit breaker, which is tripped by
+	// the watchdog once holdings fall short of the ledger.
+	BalanceInvariants(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*BalanceInvariantsResponse, error)
+	//
+	// ResetSendsBreaker closes the sends circuit breaker, so that outgoing
+	// payments are allowed again. Breaker is tripped once again by the next
+	// check of the watchdog if the shortfall persists.
+	ResetSendsBreaker(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// RunSelfTest runs the self-test of every enabled asset and media:
+	// validation of the known address vectors, crafting of the transaction
+	// which isn't broadcasted, decoding of the sample invoice, and
+	// optionally sending of the canary payments. Results are returned and
+	// reported by GetInfo until the next run.
+	RunSelfTest(ctx context.Context, in *RunSelfTestRequest, opts ...grpc.CallOption) (*RunSelfTestResponse, error)
+	//
+	// SetFeatureFlag replaces the rollout rule of the feature flag, which
+	// gates the risky behaviour. Rule is saved in the database and
+	// overrides the rule of the config across restarts.
+	SetFeatureFlag(ctx context.Context, in *FeatureFlag, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// ListFeatureFlags returns rollout rules of all known feature flags.
+	ListFeatureFlags(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListFeatureFlagsResponse, error)
+	//
+	// Diagnose gathers the state of the service which is needed to
+	// investigate the problem: sanitized config, versions, health of the
+	// connectors, error counters, queue depths and stuck payments.
+	Diagnose(ctx context.Context, in *DiagnoseRequest, opts ...grpc.CallOption) (*DiagnoseResponse, error)
+	//
+	// InjectTestPayment fabricates incoming payment on the given receipt,
+	// which is pending at first, and completed after the delay, without
+	// moving any coins. It is used to test payment consumers on staging
+	// environments, and is available only if test payments are enabled in
+	// the config.
+	InjectTestPayment(ctx context.Context, in *InjectTestPaymentRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// QuarantinePayment parks the incoming blockchain payment which has been
+	// flagged by the compliance review in the quarantined balance, which
+	// isn't spendable and isn't credited to the account until the payment
+	// is released or returned.
+	QuarantinePayment(ctx context.Context, in *QuarantinePaymentRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// ReleasePayment releases the quarantined payment after the review, so
+	// that its funds become spendable and are credited to the account.
+	ReleasePayment(ctx context.Context, in *ReleasePaymentRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// ReturnPayment sends the completed quarantined payment back to the
+	// given receipt, returned payment is never credited to the account.
+	// Return is linked to the payment the same way as refund.
+	ReturnPayment(ctx context.Context, in *ReturnPaymentRequest, opts ...grpc.CallOption) (*Payment, error)
+	//
+	// CreateAPIKey creates the key with which downstream service is
+	// authorized to call the methods allowed by the key scopes. Key itself
+	// is returned only once, server keeps only its hash.
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	//
+	// RevokeAPIKey revokes the key by its id, so that calls with it are
+	// rejected.
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// ListAPIKeys returns the information about all created keys,
+	// including revoked ones.
+	ListAPIKeys(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+}
+
+type adminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAdminClient(cc *grpc.ClientConn) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) SetPayee(ctx context.Context, in *Payee, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/SetPayee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RemovePayee(ctx context.Context, in *RemovePayeeRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/RemovePayee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetBranding(ctx context.Context, in *Branding, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/SetBranding", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RemoveBranding(ctx context.Context, in *RemoveBrandingRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/RemoveBranding", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AddWatchAddress(ctx context.Context, in *WatchAddress, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/AddWatchAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ImportWatchAddresses(ctx context.Context, in *ImportWatchAddressesRequest, opts ...grpc.CallOption) (*ImportWatchAddressesResponse, error) {
+	out := new(ImportWatchAddressesResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ImportWatchAddresses", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RemoveWatchAddress(ctx context.Context, in *RemoveWatchAddressRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/RemoveWatchAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RedeliverWatchEvents(ctx context.Context, in *RedeliverWatchEventsRequest, opts ...grpc.CallOption) (*RedeliverWatchEventsResponse, error) {
+	out := new(RedeliverWatchEventsResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/RedeliverWatchEvents", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ExportAddressBook(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*AddressBook, error) {
+	out := new(AddressBook)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ExportAddressBook", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ImportAddressBook(ctx context.Context, in *AddressBook, opts ...grpc.CallOption) (*ImportAddressBookResponse, error) {
+	out := new(ImportAddressBookResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ImportAddressBook", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SyncUnspent(ctx context.Context, in *SyncUnspentRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/SyncUnspent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetUnspentSyncStatus(ctx context.Context, in *GetUnspentSyncStatusRequest, opts ...grpc.CallOption) (*UnspentSyncStatus, error) {
+	out := new(UnspentSyncStatus)
+	err := grpc.Invoke(ctx, "/crpc.Admin/GetUnspentSyncStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListUnspent(ctx context.Context, in *ListUnspentRequest, opts ...grpc.CallOption) (*ListUnspentResponse, error) {
+	out := new(ListUnspentResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ListUnspent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) TransactionByHash(ctx context.Context, in *TransactionByHashRequest, opts ...grpc.CallOption) (*Transaction, error) {
+	out := new(Transaction)
+	err := grpc.Invoke(ctx, "/crpc.Admin/TransactionByHash", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) IsOurAddress(ctx context.Context, in *IsOurAddressRequest, opts ...grpc.CallOption) (*IsOurAddressResponse, error) {
+	out := new(IsOurAddressResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/IsOurAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error) {
+	out := new(SignMessageResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/SignMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error) {
+	out := new(VerifyMessageResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/VerifyMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) TransferToPeer(ctx context.Context, in *TransferToPeerRequest, opts ...grpc.CallOption) (*FederationTransfer, error) {
+	out := new(FederationTransfer)
+	err := grpc.Invoke(ctx, "/crpc.Admin/TransferToPeer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListFederationPositions(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListFederationPositionsResponse, error) {
+	out := new(ListFederationPositionsResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ListFederationPositions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SettleFederation(ctx context.Context, in *SettleFederationRequest, opts ...grpc.CallOption) (*FederationPosition, error) {
+	out := new(FederationPosition)
+	err := grpc.Invoke(ctx, "/crpc.Admin/SettleFederation", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SweepFunds(ctx context.Context, in *SweepFundsRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.Admin/SweepFunds", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
+	out := new(FeeReportResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/FeeReport", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) PauseWithdrawals(ctx context.Context, in *PauseWithdrawalsRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/PauseWithdrawals", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ResumeWithdrawals(ctx context.Context, in *ResumeWithdrawalsRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ResumeWithdrawals", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListWithdrawalPauses(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListWithdrawalPausesResponse, error) {
+	out := new(ListWithdrawalPausesResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ListWithdrawalPauses", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) BalanceInvariants(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*BalanceInvariantsResponse, error) {
+	out := new(BalanceInvariantsResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/BalanceInvariants", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ResetSendsBreaker(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ResetSendsBreaker", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RunSelfTest(ctx context.Context, in *RunSelfTestRequest, opts ...grpc.CallOption) (*RunSelfTestResponse, error) {
+	out := new(RunSelfTestResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/RunSelfTest", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetFeatureFlag(ctx context.Context, in *FeatureFlag, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/SetFeatureFlag", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListFeatureFlags(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListFeatureFlagsResponse, error) {
+	out := new(ListFeatureFlagsResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ListFeatureFlags", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Diagnose(ctx context.Context, in *DiagnoseRequest, opts ...grpc.CallOption) (*DiagnoseResponse, error) {
+	out := new(DiagnoseResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/Diagnose", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) InjectTestPayment(ctx context.Context, in *InjectTestPaymentRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.Admin/InjectTestPayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) QuarantinePayment(ctx context.Context, in *QuarantinePaymentRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.Admin/QuarantinePayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ReleasePayment(ctx context.Context, in *ReleasePaymentRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ReleasePayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ReturnPayment(ctx context.Context, in *ReturnPaymentRequest, opts ...grpc.CallOption) (*Payment, error) {
+	out := new(Payment)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ReturnPayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
+	out := new(CreateAPIKeyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/CreateAPIKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/RevokeAPIKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListAPIKeys(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
+	out := new(ListAPIKeysResponse)
+	err := grpc.Invoke(ctx, "/crpc.Admin/ListAPIKeys", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Admin service
+
+type AdminServer interface {
+	//
+	// SetPayee creates or updates the payee preset, which could be used
+	// later in the send payment request instead of the raw receipt.
+	SetPayee(context.Context, *Payee) (*EmptyResponse, error)
+	//
+	// RemovePayee removes the payee preset by its name.
+	RemovePayee(context.Context, *RemovePayeeRequest) (*EmptyResponse, error)
+	//
+	// SetBranding creates or updates the branding of the hosted checkout
+	// and QR pages of the tenant, so that they could be used by the
+	// white-label partners.
+	SetBranding(context.Context, *Branding) (*EmptyResponse, error)
+	//
+	// RemoveBranding removes the branding of the tenant, after that default
+	// branding is used.
+	RemoveBranding(context.Context, *RemoveBrandingRequest) (*EmptyResponse, error)
+	//
+	// AddWatchAddress starts tracking activity of the address which doesn't
+	// belong to us, e.g. old legacy wallet or address of the attacker.
+	// Activity on this address produces watch event with group label.
+	// Funding of the address is also sent by SubscribePayments as the
+	// received payment with the watch_group metadata.
+	AddWatchAddress(context.Context, *WatchAddress) (*EmptyResponse, error)
+	//
+	// ImportWatchAddresses adds the list of externally generated addresses,
+	// e.g. deposit addresses of the partner, to the watched ones. Import is
+	// idempotent, addresses which are already watched are updated with the
+	// given group, account and activation status. Import is atomic, if any
+	// of the addresses is invalid nothing is imported.
+	ImportWatchAddresses(context.Context, *ImportWatchAddressesRequest) (*ImportWatchAddressesResponse, error)
+	//
+	// RemoveWatchAddress stops tracking activity of the address.
+	RemoveWatchAddress(context.Context, *RemoveWatchAddressRequest) (*EmptyResponse, error)
+	//
+	// RedeliverWatchEvents sends again the already delivered watch events
+	// of the group, which were detected within the time range, so that
+	// listener could recover after the outage on its side. Events are
+	// delivered in the background, in the order they were detected, and
+	// are marked as redelivery in the webhook body.
+	RedeliverWatchEvents(context.Context, *RedeliverWatchEventsRequest) (*RedeliverWatchEventsResponse, error)
+	//
+	// ExportAddressBook returns the payee presets and the watched addresses
+	// as the JSON bundle signed by the server identity key, so that the
+	// whole destination surface could be reviewed and migrated to another
+	// environment.
+	ExportAddressBook(context.Context, *EmptyRequest) (*AddressBook, error)
+	//
+	// ImportAddressBook verifies the signature of the bundle exported by
+	// ExportAddressBook and imports its payee presets and watched
+	// addresses. Entries are validated before anything is imported,
+	// existing entries are updated, so that import could be repeated.
+	ImportAddressBook(context.Context, *AddressBook) (*ImportAddressBookResponse, error)
+	//
+	// SyncUnspent triggers the sync of the wallet unspent outputs with the
+	// blockchain daemon and waits for it to finish. Forced sync resyncs
+	// state from the scratch, which is the remedy for the stale state.
+	SyncUnspent(context.Context, *SyncUnspentRequest) (*EmptyResponse, error)
+	//
+	// GetUnspentSyncStatus returns the state of the sync of the wallet
+	// unspent outputs, and inconsistencies found against the daemon.
+	GetUnspentSyncStatus(context.Context, *GetUnspentSyncStatusRequest) (*UnspentSyncStatus, error)
+	//
+	// ListUnspent returns the wallet unspent outputs of the asset, so that
+	// fragmentation of the wallet could be inspected and failures of the
+	// coin selection could be debugged.
+	ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error)
+	//
+	// TransactionByHash returns the details of the wallet transaction of
+	// the asset: inputs, outputs, fee, confirmations, block and raw
+	// transaction, so that stuck payments could be investigated without
+	// access to the daemon.
+	TransactionByHash(context.Context, *TransactionByHashRequest) (*Transaction, error)
+	//
+	// IsOurAddress reports whether the blockchain address belongs to the
+	// wallet of the server, and to which account incoming payments on it
+	// are bound, so that misdirected deposits could be traced.
+	IsOurAddress(context.Context, *IsOurAddressRequest) (*IsOurAddressResponse, error)
+	//
+	// SignMessage signs the message with the key of the wallet address or
+	// of the lightning node, so that their ownership could be proven to
+	// the exchanges and auditors.
+	SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error)
+	//
+	// VerifyMessage verifies the signature of the message made by the
+	// blockchain address or by the lightning node.
+	VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error)
+	//
+	// TransferToPeer moves funds from the account of this server to the
+	// account of the federation peer, e.g. the payserver of another
+	// region, without the blockchain transaction. Account is debited
+	// right away, transfer is completed once peer credits it, and net
+	// positions with the peer are settled on-chain periodically.
+	TransferToPeer(context.Context, *TransferToPeerRequest) (*FederationTransfer, error)
+	//
+	// ListFederationPositions returns the net positions with the
+	// federation peers, i.e. the amounts which are owed to the peers or
+	// by them and are not settled yet.
+	ListFederationPositions(context.Context, *EmptyRequest) (*ListFederationPositionsResponse, error)
+	//
+	// SettleFederation sends the amount which is owed to the federation
+	// peer in the asset on-chain right away, without waiting for the
+	// periodic settlement.
+	SettleFederation(context.Context, *SettleFederationRequest) (*FederationPosition, error)
+	//
+	// SweepFunds sends all confirmed funds of the asset wallet to the
+	// address, fee is subtracted from the sent amount. It is used on the
+	// rotation of the cold storage and on the decommissioning of the node.
+	SweepFunds(context.Context, *SweepFundsRequest) (*Payment, error)
+	//
+	// FeeReport summarizes the network fees paid by the completed outgoing
+	// payments per asset and media over the day, week or month windows, so
+	// that fee spend could be tracked as a cost center and batching could
+	// be tuned accordingly.
+	FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error)
+	//
+	// PauseWithdrawals rejects every outgoing payment of the asset, in both
+	// media, until withdrawals are resumed, so that funds stop leaving the
+	// wallet during the incident without the shutdown of the service.
+	// Pause is kept across restarts.
+	PauseWithdrawals(context.Context, *PauseWithdrawalsRequest) (*EmptyResponse, error)
+	//
+	// ResumeWithdrawals removes the pause of the outgoing payments of the
+	// asset.
+	ResumeWithdrawals(context.Context, *ResumeWithdrawalsRequest) (*EmptyResponse, error)
+	//
+	// ListWithdrawalPauses returns assets which outgoing payments are
+	// paused.
+	ListWithdrawalPauses(context.Context, *EmptyRequest) (*ListWithdrawalPausesResponse, error)
+	//
+	// BalanceInvariants compares the total of the ledger, i.e. incoming
+	// payments minus outgoing payments with their fees, with the funds
+	// held on-chain and in the lightning network for every asset, and
+	// returns the state of the sends circuit breaker, which is tripped by
+	// the watchdog once holdings fall short of the ledger.
+	BalanceInvariants(context.Context, *EmptyRequest) (*BalanceInvariantsResponse, error)
+	//
+	// ResetSendsBreaker closes the sends circuit breaker, so that outgoing
+	// payments are allowed again. Breaker is tripped once again by the next
+	// check of the watchdog if the shortfall persists.
+	ResetSendsBreaker(context.Context, *EmptyRequest) (*EmptyResponse, error)
+	//
+	// RunSelfTest runs the self-test of every enabled asset and media:
+	// validation of the known address vectors, crafting of the transaction
+	// which isn't broadcasted, decoding of the sample invoice, and
+	// optionally sending of the canary payments. Results are returned and
+	// reported by GetInfo until the next run.
+	RunSelfTest(context.Context, *RunSelfTestRequest) (*RunSelfTestResponse, error)
+	//
+	// SetFeatureFlag replaces the rollout rule of the feature flag, which
+	// gates the risky behaviour. Rule is saved in the database and
+	// overrides the rule of the config across restarts.
+	SetFeatureFlag(context.Context, *FeatureFlag) (*EmptyResponse, error)
+	//
+	// ListFeatureFlags returns rollout rules of all known feature flags.
+	ListFeatureFlags(context.Context, *EmptyRequest) (*ListFeatureFlagsResponse, error)
+	//
+	// Diagnose gathers the state of the service which is needed to
+	// investigate the problem: sanitized config, versions, health of the
+	// connectors, error counters, queue depths and stuck payments.
+	Diagnose(context.Context, *DiagnoseRequest) (*DiagnoseResponse, error)
+	//
+	// InjectTestPayment fabricates incoming payment on the given receipt,
+	// which is pending at first, and completed after the delay, without
+	// moving any coins. It is used to test payment consumers on staging
+	// environments, and is available only if test payments are enabled in
+	// the config.
+	InjectTestPayment(context.Context, *InjectTestPaymentRequest) (*Payment, error)
+	//
+	// QuarantinePayment parks the incoming blockchain payment which has been
+	// flagged by the compliance review in the quarantined balance, which
+	// isn't spendable and isn't credited to the account until the payment
+	// is released or returned.
+	QuarantinePayment(context.Context, *QuarantinePaymentRequest) (*Payment, error)
+	//
+	// ReleasePayment releases the quarantined payment after the review, so
+	// that its funds become spendable and are credited to the account.
+	ReleasePayment(context.Context, *ReleasePaymentRequest) (*Payment, error)
+	//
+	// ReturnPayment sends the completed quarantined payment back to the
+	// given receipt, returned payment is never credited to the account.
+	// Return is linked to the payment the same way as refund.
+	ReturnPayment(context.Context, *ReturnPaymentRequest) (*Payment, error)
+	//
+	// CreateAPIKey creates the key with which downstream service is
+	// authorized to call the methods allowed by the key scopes. Key itself
+	// is returned only once, server keeps only its hash.
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	//
+	// RevokeAPIKey revokes the key by its id, so that calls with it are
+	// rejected.
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*EmptyResponse, error)
+	//
+	// ListAPIKeys returns the information about all created keys,
+	// including revoked ones.
+	ListAPIKeys(context.Context, *EmptyRequest) (*ListAPIKeysResponse, error)
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
+}
+
+func _Admin_SetPayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Payee)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetPayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/SetPayee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetPayee(ctx, req.(*Payee))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RemovePayee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePayeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RemovePayee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/RemovePayee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RemovePayee(ctx, req.(*RemovePayeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetBranding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Branding)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetBranding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/SetBranding",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetBranding(ctx, req.(*Branding))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RemoveBranding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBrandingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RemoveBranding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/RemoveBranding",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RemoveBranding(ctx, req.(*RemoveBrandingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AddWatchAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WatchAddress)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AddWatchAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/AddWatchAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AddWatchAddress(ctx, req.(*WatchAddress))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ImportWatchAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportWatchAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ImportWatchAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/ImportWatchAddresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ImportWatchAddresses(ctx, req.(*ImportWatchAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RemoveWatchAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveWatchAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RemoveWatchAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/RemoveWatchAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RemoveWatchAddress(ctx, req.(*RemoveWatchAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RedeliverWatchEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWatchEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RedeliverWatchEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/RedeliverWatchEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RedeliverWatchEvents(ctx, req.(*RedeliverWatchEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ExportAddressBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ExportAddressBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/ExportAddressBook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ExportAddressBook(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ImportAddressBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressBook)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ImportAddressBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/ImportAddressBook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ImportAddressBook(ctx, req.(*AddressBook))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SyncUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncUnspentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SyncUnspent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/SyncUnspent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SyncUnspent(ctx, req.(*SyncUnspentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetUnspentSyncStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUnspentSyncStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetUnspentSyncStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/GetUnspentSyncStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetUnspentSyncStatus(ctx, req.(*GetUnspentSyncStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnspentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListUnspent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/ListUnspent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListUnspent(ctx, req.(*ListUnspentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_TransactionByHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionByHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).TransactionByHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/TransactionByHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).TransactionByHash(ctx, req.(*TransactionByHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_IsOurAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsOurAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).IsOurAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/IsOurAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).IsOurAddress(ctx, req.(*IsOurAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SignMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/SignMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SignMessage(ctx, req.(*SignMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).VerifyMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/VerifyMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_TransferToPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferToPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).TransferToPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/TransferToPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).TransferToPeer(ctx, req.(*TransferToPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListFederationPositions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListFederationPositions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/ListFederationPositions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListFederationPositions(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SettleFederation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettleFederationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SettleFederation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/SettleFederation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SettleFederation(ctx, req.(*SettleFederationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SweepFunds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SweepFundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SweepFunds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/SweepFunds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SweepFunds(ctx, req.(*SweepFundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).FeeReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/FeeReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).FeeReport(ctx, req.(*FeeReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_PauseWithdrawals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseWithdrawalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).PauseWithdrawals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/PauseWithdrawals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).PauseWithdrawals(ctx, req.(*PauseWithdrawalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ResumeWithdrawals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeWithdrawalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ResumeWithdrawals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/ResumeWithdrawals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ResumeWithdrawals(ctx, req.(*ResumeWithdrawalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListWithdrawalPauses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListWithdrawalPauses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/ListWithdrawalPauses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListWithdrawalPauses(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_BalanceInvariants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).BalanceInvariants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/BalanceInvariants",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).BalanceInvariants(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ResetSendsBreaker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ResetSendsBreaker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/ResetSendsBreaker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ResetSendsBreaker(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RunSelfTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunSelfTestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RunSelfTest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/RunSelfTest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RunSelfTest(ctx, req.(*RunSelfTestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetFeatureFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeatureFlag)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetFeatureFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/SetFeatureFlag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetFeatureFlag(ctx, req.(*FeatureFlag))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListFeatureFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListFeatureFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Admin/ListFeatureFlags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListFeatureFlags(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _PayServer_CreateReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateReceiptRequest)
+func _Admin_Diagnose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiagnoseRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PayServerServer).CreateReceipt(ctx, in)
+		return srv.(AdminServer).Diagnose(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/crpc.PayServer/CreateReceipt",
+		FullMethod: "/crpc.Admin/Diagnose",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PayServerServer).CreateReceipt(ctx, req.(*CreateReceiptRequest))
+		return srv.(AdminServer).Diagnose(ctx, req.(*DiagnoseRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PayServer_ValidateReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ValidateReceiptRequest)
+func _Admin_InjectTestPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectTestPaymentRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PayServerServer).ValidateReceipt(ctx, in)
+		return srv.(AdminServer).InjectTestPayment(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/crpc.PayServer/ValidateReceipt",
+		FullMethod: "/crpc.Admin/InjectTestPayment",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PayServerServer).ValidateReceipt(ctx, req.(*ValidateReceiptRequest))
+		return srv.(AdminServer).InjectTestPayment(ctx, req.(*InjectTestPaymentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PayServer_Balance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BalanceRequest)
+func _Admin_QuarantinePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuarantinePaymentRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PayServerServer).Balance(ctx, in)
+		return srv.(AdminServer).QuarantinePayment(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/crpc.PayServer/Balance",
+		FullMethod: "/crpc.Admin/QuarantinePayment",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PayServerServer).Balance(ctx, req.(*BalanceRequest))
+		return srv.(AdminServer).QuarantinePayment(ctx, req.(*QuarantinePaymentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PayServer_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EstimateFeeRequest)
+func _Admin_ReleasePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleasePaymentRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PayServerServer).EstimateFee(ctx, in)
+		return srv.(AdminServer).ReleasePayment(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/crpc.PayServer/EstimateFee",
+		FullMethod: "/crpc.Admin/ReleasePayment",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PayServerServer).EstimateFee(ctx, req.(*EstimateFeeRequest))
+		return srv.(AdminServer).ReleasePayment(ctx, req.(*ReleasePaymentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PayServer_SendPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendPaymentRequest)
+func _Admin_ReturnPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReturnPaymentRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PayServerServer).SendPayment(ctx, in)
+		return srv.(AdminServer).ReturnPayment(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/crpc.PayServer/SendPayment",
+		FullMethod: "/crpc.Admin/ReturnPayment",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PayServerServer).SendPayment(ctx, req.(*SendPaymentRequest))
+		return srv.(AdminServer).ReturnPayment(ctx, req.(*ReturnPaymentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PayServer_PaymentByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PaymentByIDRequest)
+func _Admin_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PayServerServer).PaymentByID(ctx, in)
+		return srv.(AdminServer).CreateAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/crpc.PayServer/PaymentByID",
+		FullMethod: "/crpc.Admin/CreateAPIKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PayServerServer).PaymentByID(ctx, req.(*PaymentByIDRequest))
+		return srv.(AdminServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PayServer_PaymentsByReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PaymentsByReceiptRequest)
+func _Admin_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PayServerServer).PaymentsByReceipt(ctx, in)
+		return srv.(AdminServer).RevokeAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/crpc.PayServer/PaymentsByReceipt",
+		FullMethod: "/crpc.Admin/RevokeAPIKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PayServerServer).PaymentsByReceipt(ctx, req.(*PaymentsByReceiptRequest))
+		return srv.(AdminServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _PayServer_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPaymentsRequest)
+func _Admin_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PayServerServer).ListPayments(ctx, in)
+		return srv.(AdminServer).ListAPIKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/crpc.PayServer/ListPayments",
+		FullMethod: "/crpc.Admin/ListAPIKeys",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PayServerServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+		return srv.(AdminServer).ListAPIKeys(ctx, req.(*EmptyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _PayServer_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "crpc.PayServer",
-	HandlerType: (*PayServerServer)(nil),
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "crpc.Admin",
+	HandlerType: (*AdminServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "CreateReceipt",
-			Handler:    _PayServer_CreateReceipt_Handler,
+			MethodName: "SetPayee",
+			Handler:    _Admin_SetPayee_Handler,
 		},
 		{
-			MethodName: "ValidateReceipt",
-			Handler:    _PayServer_ValidateReceipt_Handler,
+			MethodName: "RemovePayee",
+			Handler:    _Admin_RemovePayee_Handler,
 		},
 		{
-			MethodName: "Balance",
-			Handler:    _PayServer_Balance_Handler,
+			MethodName: "SetBranding",
+			Handler:    _Admin_SetBranding_Handler,
 		},
 		{
-			MethodName: "EstimateFee",
-			Handler:    _PayServer_EstimateFee_Handler,
+			MethodName: "RemoveBranding",
+			Handler:    _Admin_RemoveBranding_Handler,
 		},
 		{
-			MethodName: "SendPayment",
-			Handler:    _PayServer_SendPayment_Handler,
+			MethodName: "AddWatchAddress",
+			Handler:    _Admin_AddWatchAddress_Handler,
 		},
 		{
-			MethodName: "PaymentByID",
-			Handler:    _PayServer_PaymentByID_Handler,
+			MethodName: "ImportWatchAddresses",
+			Handler:    _Admin_ImportWatchAddresses_Handler,
 		},
 		{
-			MethodName: "PaymentsByReceipt",
-			Handler:    _PayServer_PaymentsByReceipt_Handler,
+			MethodName: "RemoveWatchAddress",
+			Handler:    _Admin_RemoveWatchAddress_Handler,
 		},
 		{
-			MethodName: "ListPayments",
-			Handler:    _PayServer_ListPayments_Handler,
+			MethodName: "RedeliverWatchEvents",
+			Handler:    _Admin_RedeliverWatchEvents_Handler,
+		},
+		{
+			MethodName: "ExportAddressBook",
+			Handler:    _Admin_ExportAddressBook_Handler,
+		},
+		{
+			MethodName: "ImportAddressBook",
+			Handler:    _Admin_ImportAddressBook_Handler,
+		},
+		{
+			MethodName: "SyncUnspent",
+			Handler:    _Admin_SyncUnspent_Handler,
+		},
+		{
+			MethodName: "GetUnspentSyncStatus",
+			Handler:    _Admin_GetUnspentSyncStatus_Handler,
+		},
+		{
+			MethodName: "ListUnspent",
+			Handler:    _Admin_ListUnspent_Handler,
+		},
+		{
+			MethodName: "TransactionByHash",
+			Handler:    _Admin_TransactionByHash_Handler,
+		},
+		{
+			MethodName: "IsOurAddress",
+			Handler:    _Admin_IsOurAddress_Handler,
+		},
+		{
+			MethodName: "SignMessage",
+			Handler:    _Admin_SignMessage_Handler,
+		},
+		{
+			MethodName: "VerifyMessage",
+			Handler:    _Admin_VerifyMessage_Handler,
+		},
+		{
+			MethodName: "TransferToPeer",
+			Handler:    _Admin_TransferToPeer_Handler,
+		},
+		{
+			MethodName: "ListFederationPositions",
+			Handler:    _Admin_ListFederationPositions_Handler,
+		},
+		{
+			MethodName: "SettleFederation",
+			Handler:    _Admin_SettleFederation_Handler,
+		},
+		{
+			MethodName: "SweepFunds",
+			Handler:    _Admin_SweepFunds_Handler,
+		},
+		{
+			MethodName: "FeeReport",
+			Handler:    _Admin_FeeReport_Handler,
+		},
+		{
+			MethodName: "PauseWithdrawals",
+			Handler:    _Admin_PauseWithdrawals_Handler,
+		},
+		{
+			MethodName: "ResumeWithdrawals",
+			Handler:    _Admin_ResumeWithdrawals_Handler,
+		},
+		{
+			MethodName: "ListWithdrawalPauses",
+			Handler:    _Admin_ListWithdrawalPauses_Handler,
+		},
+		{
+			MethodName: "BalanceInvariants",
+			Handler:    _Admin_BalanceInvariants_Handler,
+		},
+		{
+			MethodName: "ResetSendsBreaker",
+			Handler:    _Admin_ResetSendsBreaker_Handler,
+		},
+		{
+			MethodName: "RunSelfTest",
+			Handler:    _Admin_RunSelfTest_Handler,
+		},
+		{
+			MethodName: "SetFeatureFlag",
+			Handler:    _Admin_SetFeatureFlag_Handler,
+		},
+		{
+			MethodName: "ListFeatureFlags",
+			Handler:    _Admin_ListFeatureFlags_Handler,
+		},
+		{
+			MethodName: "Diagnose",
+			Handler:    _Admin_Diagnose_Handler,
+		},
+		{
+			MethodName: "InjectTestPayment",
+			Handler:    _Admin_InjectTestPayment_Handler,
+		},
+		{
+			MethodName: "QuarantinePayment",
+			Handler:    _Admin_QuarantinePayment_Handler,
+		},
+		{
+			MethodName: "ReleasePayment",
+			Handler:    _Admin_ReleasePayment_Handler,
+		},
+		{
+			MethodName: "ReturnPayment",
+			Handler:    _Admin_ReturnPayment_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _Admin_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _Admin_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _Admin_ListAPIKeys_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rpc.proto",
+}
+
+// Client API for AddressProvider service
+
+type AddressProviderClient interface {
+	//
+	// ProvideAddress returns the new deposit address of the asset. Address
+	// should be derived on every call, the same address shouldn't be
+	// returned twice.
+	ProvideAddress(ctx context.Context, in *ProvideAddressRequest, opts ...grpc.CallOption) (*ProvideAddressResponse, error)
+}
+
+type addressProviderClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAddressProviderClient(cc *grpc.ClientConn) AddressProviderClient {
+	return &addressProviderClient{cc}
+}
+
+func (c *addressProviderClient) ProvideAddress(ctx context.Context, in *ProvideAddressRequest, opts ...grpc.CallOption) (*ProvideAddressResponse, error) {
+	out := new(ProvideAddressResponse)
+	err := grpc.Invoke(ctx, "/crpc.AddressProvider/ProvideAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for AddressProvider service
+
+type AddressProviderServer interface {
+	//
+	// ProvideAddress returns the new deposit address of the asset. Address
+	// should be derived on every call, the same address shouldn't be
+	// returned twice.
+	ProvideAddress(context.Context, *ProvideAddressRequest) (*ProvideAddressResponse, error)
+}
+
+func RegisterAddressProviderServer(s *grpc.Server, srv AddressProviderServer) {
+	s.RegisterService(&_AddressProvider_serviceDesc, srv)
+}
+
+func _AddressProvider_ProvideAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProvideAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddressProviderServer).ProvideAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.AddressProvider/ProvideAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddressProviderServer).ProvideAddress(ctx, req.(*ProvideAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AddressProvider_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "crpc.AddressProvider",
+	HandlerType: (*AddressProviderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ProvideAddress",
+			Handler:    _AddressProvider_ProvideAddress_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rpc.proto",
+}
+
+// Client API for Federation service
+
+type FederationClient interface {
+	//
+	// ReceiveTransfer credits the account of the server with the transfer
+	// from the account of the peer. Transfer is idempotent by its id, so
+	// that it could be retried if response has been lost.
+	ReceiveTransfer(ctx context.Context, in *FederatedTransfer, opts ...grpc.CallOption) (*ReceiveTransferResponse, error)
+	//
+	// ReceiveSettlement records the on-chain payment with which peer has
+	// settled its position. Settlement is idempotent by its id.
+	ReceiveSettlement(ctx context.Context, in *FederatedSettlement, opts ...grpc.CallOption) (*EmptyResponse, error)
+	//
+	// SettlementAddress returns the address to which peer should send the
+	// settlement of the asset.
+	SettlementAddress(ctx context.Context, in *SettlementAddressRequest, opts ...grpc.CallOption) (*SettlementAddressResponse, error)
+}
+
+type federationClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFederationClient(cc *grpc.ClientConn) FederationClient {
+	return &federationClient{cc}
+}
+
+func (c *federationClient) ReceiveTransfer(ctx context.Context, in *FederatedTransfer, opts ...grpc.CallOption) (*ReceiveTransferResponse, error) {
+	out := new(ReceiveTransferResponse)
+	err := grpc.Invoke(ctx, "/crpc.Federation/ReceiveTransfer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *federationClient) ReceiveSettlement(ctx context.Context, in *FederatedSettlement, opts ...grpc.CallOption) (*EmptyResponse, error) {
+	out := new(EmptyResponse)
+	err := grpc.Invoke(ctx, "/crpc.Federation/ReceiveSettlement", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *federationClient) SettlementAddress(ctx context.Context, in *SettlementAddressRequest, opts ...grpc.CallOption) (*SettlementAddressResponse, error) {
+	out := new(SettlementAddressResponse)
+	err := grpc.Invoke(ctx, "/crpc.Federation/SettlementAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Federation service
+
+type FederationServer interface {
+	//
+	// ReceiveTransfer credits the account of the server with the transfer
+	// from the account of the peer. Transfer is idempotent by its id, so
+	// that it could be retried if response has been lost.
+	ReceiveTransfer(context.Context, *FederatedTransfer) (*ReceiveTransferResponse, error)
+	//
+	// ReceiveSettlement records the on-chain payment with which peer has
+	// settled its position. Settlement is idempotent by its id.
+	ReceiveSettlement(context.Context, *FederatedSettlement) (*EmptyResponse, error)
+	//
+	// SettlementAddress returns the address to which peer should send the
+	// settlement of the asset.
+	SettlementAddress(context.Context, *SettlementAddressRequest) (*SettlementAddressResponse, error)
+}
+
+func RegisterFederationServer(s *grpc.Server, srv FederationServer) {
+	s.RegisterService(&_Federation_serviceDesc, srv)
+}
+
+func _Federation_ReceiveTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FederatedTransfer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FederationServer).ReceiveTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Federation/ReceiveTransfer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FederationServer).ReceiveTransfer(ctx, req.(*FederatedTransfer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Federation_ReceiveSettlement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FederatedSettlement)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FederationServer).ReceiveSettlement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Federation/ReceiveSettlement",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FederationServer).ReceiveSettlement(ctx, req.(*FederatedSettlement))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Federation_SettlementAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettlementAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FederationServer).SettlementAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/crpc.Federation/SettlementAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FederationServer).SettlementAddress(ctx, req.(*SettlementAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Federation_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "crpc.Federation",
+	HandlerType: (*FederationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReceiveTransfer",
+			Handler:    _Federation_ReceiveTransfer_Handler,
+		},
+		{
+			MethodName: "ReceiveSettlement",
+			Handler:    _Federation_ReceiveSettlement_Handler,
+		},
+		{
+			MethodName: "SettlementAddress",
+			Handler:    _Federation_SettlementAddress_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -1416,71 +11587,487 @@ var _PayServer_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1048 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xcd, 0x72, 0x9b, 0x56,
-	0x14, 0x0e, 0x02, 0xfd, 0x70, 0xf4, 0x63, 0x7a, 0xed, 0xb8, 0x58, 0x49, 0x5a, 0x97, 0x6e, 0x52,
-	0x77, 0xc6, 0x0b, 0x27, 0x93, 0x55, 0x36, 0x48, 0xc2, 0x16, 0x53, 0x09, 0x79, 0x10, 0x4e, 0xdb,
-	0x95, 0xe6, 0x1a, 0x6e, 0x3a, 0x4c, 0x25, 0x44, 0x01, 0x79, 0xaa, 0x27, 0xc8, 0xa6, 0x8b, 0xae,
-	0xfa, 0x18, 0xdd, 0xf6, 0xa1, 0xfa, 0x12, 0x1d, 0xee, 0xbd, 0x08, 0x90, 0xe4, 0xb1, 0x33, 0x93,
-	0x69, 0x76, 0x9c, 0xf3, 0x9d, 0x73, 0xf4, 0x9d, 0x3f, 0x0e, 0x02, 0x39, 0x0a, 0xdd, 0xf3, 0x30,
-	0x5a, 0x26, 0x4b, 0x24, 0xb9, 0x51, 0xe8, 0x6a, 0x1d, 0x68, 0x19, 0x8b, 0x30, 0x59, 0xdb, 0xe4,
-	0xb7, 0x15, 0x89, 0x13, 0xed, 0x00, 0xda, 0x5c, 0x8e, 0xc3, 0x65, 0x10, 0x13, 0xed, 0x2f, 0x01,
-	0x8e, 0xfa, 0x11, 0xc1, 0x09, 0xb1, 0x89, 0x4b, 0xfc, 0x30, 0xe1, 0x96, 0xe8, 0x1b, 0xa8, 0xe2,
-	0x38, 0x26, 0x89, 0x2a, 0x9c, 0x0a, 0x2f, 0x3b, 0x17, 0xcd, 0xf3, 0x34, 0xde, 0xb9, 0x9e, 0xaa,
-	0x6c, 0x86, 0xa4, 0x26, 0x0b, 0xe2, 0xf9, 0x58, 0xad, 0x14, 0x4d, 0xc6, 0xa9, 0xca, 0x66, 0x08,
-	0x3a, 0x86, 0x1a, 0x5e, 0x2c, 0x57, 0x41, 0xa2, 0x8a, 0xa7, 0xc2, 0x4b, 0xd9, 0xe6, 0x12, 0x3a,
-	0x85, 0xa6, 0x47, 0x62, 0x37, 0xf2, 0xc3, 0xc4, 0x5f, 0x06, 0xaa, 0x44, 0xc1, 0xa2, 0x4a, 0x0b,
-	0xe0, 0xe9, 0x16, 0x2f, 0xc6, 0x18, 0x7d, 0x0b, 0x6d, 0x37, 0x05, 0xfc, 0x65, 0x30, 0xf3, 0x70,
-	0x42, 0x28, 0x41, 0xd1, 0x6e, 0x65, 0xca, 0x01, 0x4e, 0x08, 0x52, 0xa1, 0x1e, 0x31, 0x3f, 0x4a,
-	0x4e, 0xb6, 0x33, 0x31, 0x65, 0x44, 0x7e, 0x0f, 0xfd, 0x68, 0x4d, 0x19, 0x89, 0x36, 0x97, 0xb4,
-	0x77, 0xd0, 0xe9, 0xe1, 0x39, 0x0e, 0x5c, 0xf2, 0x49, 0x2b, 0xa0, 0x7d, 0x10, 0xa0, 0xce, 0x03,
-	0xa3, 0xe7, 0x20, 0xe3, 0x3b, 0xec, 0xcf, 0xf1, 0xed, 0x9c, 0xd1, 0x96, 0xed, 0x5c, 0x91, 0x72,
-	0x0e, 0x49, 0xe0, 0xf9, 0xc1, 0x2f, 0x19, 0x67, 0x2e, 0xe6, 0x4c, 0xc4, 0x87, 0x99, 0x48, 0xf7,
-	0x32, 0x19, 0xc1, 0x97, 0xef, 0xf0, 0xdc, 0xf7, 0xf6, 0xd4, 0xf4, 0x3b, 0xa8, 0xfb, 0xc1, 0xdd,
-	0xd2, 0x77, 0x19, 0xad, 0xe6, 0x45, 0x9b, 0xf9, 0x9b, 0x4c, 0x39, 0x7c, 0x62, 0x67, 0x78, 0xaf,
-	0x06, 0x92, 0x87, 0x13, 0xac, 0xfd, 0x23, 0x40, 0x9d, 0xc3, 0x08, 0x81, 0xb4, 0x20, 0x8b, 0x25,
-	0x4f, 0x89, 0x3e, 0xa3, 0x23, 0xa8, 0xde, 0xe1, 0xf9, 0x8a, 0xf0, 0x5c, 0x98, 0xb0, 0xdb, 0x3c,
-	0x71, 0x4f, 0xf3, 0xf2, 0x16, 0x49, 0xc5, 0x16, 0xa5, 0xce, 0xef, 0xf1, 0x7c, 0x7e, 0x8b, 0xdd,
-	0x5f, 0x67, 0xd8, 0xf3, 0x22, 0xb5, 0x4a, 0x43, 0xb7, 0x32, 0xa5, 0xee, 0x79, 0x11, 0x9f, 0xac,
-	0xc4, 0x0f, 0x68, 0x3c, 0xb5, 0xb6, 0x99, 0xac, 0x4c, 0xa5, 0xbd, 0x85, 0x83, 0x4d, 0xa7, 0x37,
-	0xf9, 0x37, 0x6e, 0x99, 0x2a, 0x56, 0x85, 0x53, 0x31, 0x2f, 0x40, 0x66, 0xb8, 0x81, 0xb5, 0x3f,
-	0x05, 0x38, 0xde, 0x29, 0x23, 0x1b, 0x98, 0xc2, 0xd0, 0x09, 0xe5, 0xa1, 0xdb, 0x34, 0xb0, 0xf2,
-	0x70, 0x03, 0xc5, 0x47, 0x2c, 0x93, 0x54, 0x5c, 0x26, 0xed, 0x0f, 0x01, 0x90, 0x11, 0x27, 0xfe,
-	0x02, 0x27, 0xe4, 0x92, 0x90, 0xff, 0x67, 0x83, 0x0b, 0xc9, 0x4a, 0xa5, 0x64, 0xb5, 0x0b, 0x38,
-	0x2c, 0xb1, 0xe1, 0x35, 0x7e, 0x06, 0x32, 0x8d, 0x38, 0x7b, 0x4f, 0xb2, 0xe1, 0x6f, 0x50, 0xc5,
-	0x25, 0x21, 0x34, 0x85, 0x29, 0x09, 0xbc, 0x6b, 0xbc, 0x5e, 0x90, 0x20, 0xf9, 0xdc, 0x29, 0xbc,
-	0x02, 0xc4, 0x99, 0xf4, 0xd6, 0xe6, 0x20, 0x63, 0xf3, 0x02, 0x20, 0x64, 0xda, 0x99, 0xef, 0x65,
-	0xfb, 0xcb, 0x35, 0xa6, 0xa7, 0xbd, 0x06, 0x95, 0x3b, 0xc5, 0xbd, 0xf5, 0x63, 0x47, 0x43, 0xbb,
-	0x84, 0x93, 0x3d, 0x5e, 0xf9, 0x5c, 0xf2, 0xf8, 0x5b, 0x73, 0x99, 0xd5, 0x69, 0x03, 0x6b, 0xff,
-	0x0a, 0x70, 0x38, 0xf2, 0xe3, 0x24, 0x0b, 0x96, 0xfd, 0xf2, 0xf7, 0x50, 0x8b, 0x13, 0x9c, 0xac,
-	0x62, 0x5e, 0xc3, 0xc3, 0x52, 0x80, 0x29, 0x85, 0x6c, 0x6e, 0x82, 0x5e, 0x83, 0xec, 0xf9, 0x11,
-	0x71, 0xe9, 0xea, 0xb0, 0x82, 0x1e, 0x97, 0xec, 0x07, 0x19, 0x6a, 0xe7, 0x86, 0x9f, 0xe6, 0xf5,
-	0x44, 0x89, 0xae, 0xe3, 0x84, 0x2c, 0xe8, 0x5a, 0xef, 0x10, 0xa5, 0x90, 0xcd, 0x4d, 0x34, 0x1d,
-	0x8e, 0xca, 0xc9, 0x7e, 0x7c, 0xc1, 0x3e, 0x88, 0x50, 0xe7, 0xda, 0x07, 0x3a, 0x9b, 0xc2, 0xab,
-	0x30, 0x5d, 0x78, 0x6f, 0x86, 0xd9, 0x0e, 0x8b, 0xb6, 0xcc, 0x35, 0x7a, 0xb1, 0xc4, 0xe2, 0x47,
-	0x96, 0x58, 0x7a, 0x6c, 0x89, 0xf3, 0xe2, 0x34, 0x1f, 0x2c, 0x4e, 0xde, 0x8f, 0xea, 0xbd, 0xfd,
-	0x28, 0xcc, 0x63, 0xad, 0xfc, 0xaa, 0x3a, 0x01, 0xb6, 0x95, 0x69, 0x21, 0xea, 0x0c, 0xa2, 0xb2,
-	0xe9, 0xe5, 0x4d, 0x6c, 0x3c, 0x62, 0xd5, 0xe4, 0xd2, 0xaa, 0x95, 0x96, 0x1f, 0xca, 0xcb, 0x7f,
-	0x66, 0x40, 0x95, 0x92, 0x43, 0x1d, 0x00, 0x7d, 0x3a, 0x35, 0x9c, 0x99, 0x35, 0xb1, 0x0c, 0xe5,
-	0x09, 0xaa, 0x83, 0xd8, 0x73, 0xfa, 0x8a, 0x40, 0x1f, 0xfa, 0x43, 0xa5, 0x92, 0x3e, 0x18, 0xce,
-	0x50, 0x11, 0xd3, 0x87, 0x91, 0xd3, 0x57, 0x24, 0xd4, 0x00, 0x69, 0xa0, 0x4f, 0x87, 0x4a, 0xf5,
-	0xec, 0x0d, 0x54, 0x29, 0x97, 0x34, 0xcc, 0xd8, 0x18, 0x98, 0x7a, 0x16, 0xa6, 0x03, 0xd0, 0x1b,
-	0x4d, 0xfa, 0x3f, 0xf4, 0x87, 0xba, 0x69, 0x29, 0x02, 0x6a, 0x83, 0x3c, 0x32, 0xaf, 0x86, 0x8e,
-	0x65, 0x5a, 0x57, 0x4a, 0xe5, 0xec, 0x06, 0xda, 0xa5, 0x56, 0xa1, 0x03, 0x68, 0x4e, 0x1d, 0xdd,
-	0xb9, 0x99, 0x66, 0x01, 0x9a, 0x50, 0xff, 0x51, 0x37, 0x9d, 0xd4, 0x5c, 0x48, 0x85, 0x6b, 0xc3,
-	0x1a, 0x50, 0xdf, 0x34, 0x54, 0x7f, 0x32, 0xbe, 0x1e, 0x19, 0x8e, 0x31, 0x50, 0x44, 0x04, 0x50,
-	0xbb, 0xd4, 0xcd, 0x91, 0x31, 0x50, 0xa4, 0xb3, 0x1e, 0x28, 0xdb, 0x1d, 0x45, 0x08, 0x3a, 0x03,
-	0xd3, 0x36, 0xfa, 0x8e, 0x39, 0xb1, 0xb2, 0xe0, 0x2d, 0x68, 0x98, 0x56, 0x7f, 0x32, 0x66, 0xd1,
-	0x5b, 0xd0, 0x98, 0xdc, 0x38, 0x57, 0x13, 0x46, 0xed, 0x6d, 0x4e, 0x8d, 0xb5, 0x36, 0xa5, 0xf6,
-	0xf3, 0xd4, 0x31, 0xc6, 0x25, 0x6f, 0xc7, 0xb0, 0x2d, 0x7d, 0xc4, 0xbc, 0x8d, 0x9f, 0xb8, 0x54,
-	0xb9, 0xf8, 0x5b, 0x02, 0xf9, 0x1a, 0xaf, 0xa7, 0x24, 0xba, 0x23, 0x11, 0x1a, 0x42, 0xbb, 0xf4,
-	0x41, 0x85, 0xba, 0xac, 0x7f, 0xfb, 0xbe, 0xfe, 0xba, 0xcf, 0xf6, 0x62, 0x7c, 0xc9, 0x2c, 0x38,
-	0xd8, 0xba, 0x80, 0xe8, 0x39, 0xb3, 0xdf, 0x7f, 0x18, 0xbb, 0x2f, 0xee, 0x41, 0x79, 0xbc, 0x37,
-	0xf9, 0x17, 0xd2, 0x51, 0xf9, 0xec, 0x72, 0xff, 0xa7, 0x5b, 0x5a, 0xee, 0xd7, 0x83, 0x66, 0xe1,
-	0xd0, 0x20, 0x95, 0x59, 0xed, 0x5e, 0xc2, 0xee, 0xc9, 0x1e, 0x64, 0xf3, 0xdb, 0xcd, 0xc2, 0xdd,
-	0xc9, 0x62, 0xec, 0x9e, 0xa2, 0x6e, 0xf9, 0x3d, 0x92, 0xfa, 0x15, 0x2e, 0x44, 0xe6, 0xb7, 0x7b,
-	0x34, 0xb6, 0xfd, 0x1c, 0xf8, 0x62, 0xe7, 0x75, 0x8f, 0xbe, 0x2a, 0xd9, 0xec, 0x5c, 0x8f, 0xee,
-	0xd7, 0xf7, 0xe2, 0x3c, 0x0b, 0x03, 0x5a, 0xc5, 0xd7, 0x21, 0xe2, 0x09, 0xef, 0xb9, 0x07, 0xdd,
-	0xee, 0x3e, 0x88, 0x85, 0xb9, 0xad, 0xd1, 0xbf, 0x0e, 0xaf, 0xfe, 0x0b, 0x00, 0x00, 0xff, 0xff,
-	0xeb, 0x68, 0xcc, 0x2b, 0x47, 0x0c, 0x00, 0x00,
+	// 7703 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcd, 0x3d, 0x5d, 0x73, 0x24, 0xc9,
+	0x51, 0x9e, 0x4f, 0x8d, 0x52, 0xdf, 0x2d, 0x69, 0x57, 0x3b, 0x7b, 0xbe, 0x3d, 0x37, 0xac, 0x7d,
+	0xde, 0xb3, 0x17, 0x7b, 0xef, 0xc3, 0x77, 0xe7, 0x3b, 0xfb, 0x46, 0xd2, 0x68, 0x57, 0x5e, 0x7d,
+	0x5d, 0xcf, 0x68, 0xef, 0xce, 0x04, 0x4c, 0xb4, 0x66, 0x5a, 0xd2, 0xb0, 0xf3, 0x75, 0xd3, 0x3d,
+	0xda, 0x55, 0x40, 0x10, 0xc6, 0x4f, 0x04, 0x01, 0x84, 0x23, 0x08, 0x3e, 0x5e, 0x08, 0x9e, 0x20,
+	0xe0, 0x05, 0x22, 0x20, 0x6c, 0x82, 0x80, 0x27, 0x1c, 0xbc, 0x01, 0xe1, 0x27, 0x7e, 0x00, 0x6f,
+	0x3c, 0x11, 0xc0, 0x0b, 0x6f, 0x90, 0x59, 0x95, 0xd5, 0x5d, 0xd5, 0xd3, 0x2d, 0x8d, 0x6e, 0xf7,
+	0x7c, 0x7e, 0xd2, 0x54, 0xd6, 0x47, 0x67, 0x65, 0x65, 0x66, 0x65, 0x65, 0x65, 0x96, 0x60, 0x7a,
+	0x38, 0x68, 0xde, 0x1d, 0x0c, 0xfb, 0x41, 0xdf, 0xca, 0x37, 0xf1, 0xb7, 0x3d, 0x0f, 0xb3, 0xd5,
+	0xee, 0x20, 0x38, 0x77, 0xbc, 0x8f, 0x47, 0x9e, 0x1f, 0xd8, 0x0b, 0x30, 0xc7, 0x65, 0x7f, 0xd0,
+	0xef, 0xf9, 0x9e, 0xdd, 0x81, 0xd5, 0x83, 0x61, 0xff, 0xac, 0xdd, 0xf2, 0x2a, 0xad, 0xd6, 0xd0,
+	0xf3, 0x7d, 0x6e, 0x69, 0x7d, 0x01, 0x0a, 0xae, 0xef, 0x7b, 0xc1, 0x5a, 0xe6, 0xa5, 0xcc, 0xcb,
+	0xf3, 0xf7, 0x66, 0xee, 0xd2, 0x78, 0x77, 0x2b, 0x04, 0x72, 0x64, 0x8d, 0xb5, 0x06, 0x53, 0x3d,
+	0x2f, 0x78, 0xd2, 0x1f, 0x3e, 0x5e, 0xcb, 0x62, 0xa3, 0x69, 0x47, 0x15, 0xad, 0x6b, 0x50, 0x0c,
+	0xbc, 0x9e, 0xdb, 0x0b, 0xd6, 0x72, 0xa2, 0x82, 0x4b, 0xf6, 0x3d, 0xb8, 0x16, 0xff, 0x9a, 0xc4,
+	0x83, 0xc6, 0x72, 0x25, 0x48, 0x7c, 0x10, 0xc7, 0xe2, 0xa2, 0xfd, 0x1f, 0x59, 0x58, 0xd9, 0x18,
+	0x7a, 0x6e, 0xe0, 0x39, 0x5e, 0xd3, 0x6b, 0x0f, 0x82, 0x2b, 0x60, 0x88, 0x4d, 0xba, 0x5e, 0xab,
+	0xed, 0x0a, 0xfc, 0xc2, 0x26, 0xbb, 0x04, 0x72, 0x64, 0x0d, 0xa1, 0xea, 0x76, 0xfb, 0xa3, 0x08,
+	0x55, 0x59, 0xb2, 0x5e, 0x82, 0x99, 0x96, 0xe7, 0x37, 0x87, 0xf8, 0xc1, 0x76, 0xbf, 0xb7, 0x96,
+	0x17, 0x95, 0x3a, 0x88, 0x7a, 0x7a, 0x4f, 0x07, 0xed, 0xe1, 0xf9, 0x5a, 0x01, 0x2b, 0x73, 0x0e,
+	0x97, 0xc4, 0x54, 0x9a, 0x4d, 0x31, 0x64, 0x91, 0xa7, 0x22, 0x8b, 0xd6, 0x26, 0x94, 0xba, 0x5e,
+	0xe0, 0xb6, 0xdc, 0xc0, 0x5d, 0x9b, 0x7a, 0x29, 0xf7, 0xf2, 0xcc, 0xbd, 0x97, 0x25, 0x46, 0x49,
+	0xf3, 0x43, 0x34, 0x65, 0xd3, 0x6a, 0x2f, 0x18, 0x9e, 0x3b, 0x61, 0x4f, 0xeb, 0x05, 0x98, 0x1e,
+	0x7a, 0xc7, 0xde, 0xd0, 0xeb, 0x35, 0xbd, 0xb5, 0x92, 0xf8, 0x42, 0x04, 0x28, 0x7f, 0x13, 0xe6,
+	0x8c, 0x8e, 0xd6, 0x22, 0xe4, 0x1e, 0x7b, 0xe7, 0x4c, 0x55, 0xfa, 0x69, 0xad, 0x40, 0xe1, 0xcc,
+	0xed, 0x8c, 0x3c, 0x5e, 0x35, 0x59, 0x78, 0x3b, 0xfb, 0x66, 0xc6, 0x3e, 0x80, 0xa5, 0x3d, 0xef,
+	0xc9, 0x27, 0xe2, 0x04, 0x35, 0xe5, 0xac, 0x31, 0x65, 0xfb, 0x2e, 0x58, 0xfa, 0x88, 0x97, 0xae,
+	0xf6, 0xff, 0x66, 0x60, 0x79, 0xa7, 0xed, 0x07, 0x4c, 0x0b, 0xff, 0xf9, 0x2e, 0xf6, 0x2b, 0x50,
+	0xf4, 0x03, 0x37, 0x18, 0xf9, 0x62, 0xb1, 0xe7, 0xef, 0x2d, 0xcb, 0x36, 0xfc, 0xb1, 0x9a, 0xa8,
+	0x72, 0xb8, 0x09, 0x8e, 0x37, 0xdb, 0x14, 0xeb, 0xd2, 0x6a, 0x1c, 0x0f, 0xfb, 0x5d, 0xc1, 0x02,
+	0x39, 0x67, 0x86, 0x61, 0x5b, 0x08, 0xb2, 0x3e, 0x0f, 0xa0, 0x9a, 0x04, 0x7d, 0x66, 0x83, 0x69,
+	0x86, 0xd4, 0xfb, 0x44, 0xe8, 0x4e, 0xbb, 0xdb, 0x96, 0x7c, 0x30, 0xe7, 0xc8, 0x02, 0xf1, 0x4d,
+	0xff, 0xf8, 0x98, 0xe6, 0x32, 0x85, 0xe0, 0xbc, 0xc3, 0x25, 0xfb, 0x27, 0x79, 0x98, 0x62, 0x4c,
+	0x88, 0x40, 0x43, 0xf9, 0x53, 0x11, 0x88, 0x8b, 0x11, 0x21, 0xb2, 0x97, 0x13, 0x22, 0x37, 0x01,
+	0xd7, 0xe7, 0x2f, 0xe2, 0xfa, 0xc2, 0x38, 0xd7, 0x6b, 0x53, 0x76, 0xe5, 0xc4, 0xa2, 0x29, 0x57,
+	0x02, 0xaa, 0x16, 0x62, 0xe0, 0xf9, 0x54, 0x3d, 0x25, 0xab, 0x19, 0x82, 0xd5, 0xd1, 0x02, 0x94,
+	0x2e, 0x5f, 0x00, 0x1c, 0x8b, 0x67, 0xdd, 0x68, 0xb7, 0xd6, 0xa6, 0x15, 0xa7, 0x0b, 0xc8, 0x76,
+	0xcb, 0xfa, 0x86, 0x26, 0x4d, 0x20, 0xa4, 0xe9, 0xa6, 0x31, 0x5a, 0xaa, 0x00, 0x95, 0xa1, 0x34,
+	0xf4, 0x06, 0x1d, 0xb7, 0xe9, 0xf9, 0x6b, 0x33, 0x62, 0xd4, 0xb0, 0x6c, 0xdd, 0x82, 0x19, 0xfe,
+	0xdd, 0x6a, 0x1c, 0x9d, 0xaf, 0xcd, 0x8a, 0x6a, 0x50, 0xa0, 0xf5, 0x73, 0x53, 0xfa, 0xe6, 0x62,
+	0xd2, 0x67, 0x7d, 0x19, 0x16, 0x35, 0x62, 0x35, 0x4e, 0x5d, 0xff, 0x74, 0x6d, 0x5e, 0x34, 0x5a,
+	0xd0, 0xe0, 0x0f, 0x10, 0x6c, 0xdd, 0x86, 0x79, 0xec, 0x37, 0xea, 0x21, 0x1d, 0x59, 0x14, 0x16,
+	0x44, 0xc3, 0x39, 0x09, 0x65, 0x91, 0x79, 0x36, 0x79, 0x3e, 0x82, 0x72, 0x25, 0x08, 0xdc, 0xe6,
+	0xa9, 0xa3, 0x8f, 0xa9, 0x64, 0xca, 0xa4, 0x6f, 0x26, 0x4e, 0xdf, 0x71, 0x04, 0xb3, 0x09, 0x08,
+	0xda, 0xaf, 0x82, 0xc5, 0x04, 0x5f, 0x3f, 0xdf, 0xde, 0x9c, 0x6c, 0x6c, 0xfb, 0x2d, 0x58, 0xab,
+	0x8d, 0x8e, 0x88, 0x20, 0x47, 0x5e, 0x5c, 0xd4, 0x2f, 0xe9, 0xfa, 0xfd, 0x0c, 0xcc, 0x72, 0x97,
+	0xea, 0x99, 0x87, 0x3c, 0x7b, 0x07, 0xf2, 0xc1, 0xf9, 0xc0, 0x63, 0xcd, 0x70, 0xcd, 0xe0, 0x01,
+	0xd1, 0xa2, 0x8e, 0xb5, 0x8e, 0x68, 0x13, 0x1b, 0x3b, 0x1b, 0x9f, 0xf2, 0x97, 0x22, 0xb1, 0x23,
+	0xd9, 0x99, 0xb9, 0x37, 0x67, 0x8c, 0x16, 0x4a, 0xa1, 0xfd, 0x01, 0xac, 0x98, 0x5a, 0x8a, 0x15,
+	0xdb, 0x97, 0x89, 0xb5, 0x24, 0x0c, 0xf1, 0xc9, 0x8d, 0x8f, 0x10, 0x56, 0xd3, 0xaa, 0x05, 0xfd,
+	0xc0, 0xed, 0x08, 0x2c, 0xf2, 0x8e, 0x2c, 0xd8, 0xff, 0x93, 0x81, 0xd5, 0xd8, 0x6e, 0xc0, 0x43,
+	0xff, 0x1c, 0xcc, 0x09, 0x31, 0x23, 0xbe, 0x42, 0x6e, 0x90, 0xf3, 0xcd, 0x39, 0xb3, 0x0a, 0xb8,
+	0x89, 0x30, 0x5d, 0x6f, 0x64, 0x4d, 0xbd, 0x11, 0xed, 0x56, 0x39, 0x63, 0xb7, 0x42, 0x61, 0x78,
+	0xe2, 0x0e, 0x7b, 0xed, 0xde, 0x89, 0x8f, 0xba, 0x20, 0x47, 0xc2, 0xa0, 0xca, 0x31, 0x6a, 0x15,
+	0xe2, 0xd4, 0x32, 0x65, 0xbd, 0x18, 0x97, 0xf5, 0x24, 0x59, 0x98, 0x4a, 0x94, 0x05, 0xfb, 0x11,
+	0xcc, 0xaf, 0xbb, 0x1d, 0x17, 0x25, 0xe8, 0xb9, 0xea, 0x7b, 0xfb, 0x2f, 0x32, 0x30, 0xc5, 0x03,
+	0x93, 0xe0, 0xba, 0x67, 0x6e, 0xbb, 0xe3, 0x1e, 0x75, 0x3c, 0xc5, 0x55, 0x21, 0x80, 0x08, 0x37,
+	0xf0, 0x7a, 0x2d, 0x9c, 0xb6, 0x22, 0x1c, 0x17, 0x23, 0x4c, 0x72, 0x97, 0x63, 0x92, 0x4f, 0x55,
+	0xb8, 0xa8, 0x58, 0x3f, 0x1e, 0xb9, 0x43, 0x34, 0x82, 0xda, 0x3d, 0x4f, 0xd1, 0x52, 0x07, 0xd9,
+	0x3f, 0xc4, 0x95, 0x67, 0x5c, 0x1f, 0x20, 0x6b, 0xf5, 0x87, 0xe7, 0xcf, 0x77, 0xef, 0x8b, 0x6f,
+	0x67, 0xb9, 0xcb, 0xb6, 0xb3, 0x7c, 0xea, 0x76, 0x56, 0xd0, 0xb6, 0x33, 0xfb, 0x23, 0x58, 0x60,
+	0xb4, 0x6b, 0x3d, 0x77, 0xe0, 0x9f, 0xf6, 0x83, 0xd8, 0x1e, 0x91, 0x89, 0xef, 0x11, 0x28, 0x65,
+	0x47, 0xb2, 0x87, 0x40, 0x37, 0x94, 0x11, 0xc5, 0x02, 0xaa, 0xd6, 0xde, 0x85, 0x6b, 0x71, 0x8a,
+	0xb0, 0x30, 0xbc, 0x0a, 0xd3, 0x3e, 0x7f, 0x4d, 0x09, 0xda, 0xaa, 0x31, 0x88, 0xc2, 0xc5, 0x89,
+	0xda, 0xd9, 0xbf, 0x06, 0xd7, 0x43, 0xa5, 0xf3, 0x69, 0xb0, 0x9b, 0x75, 0x13, 0xa6, 0xbb, 0x6d,
+	0x94, 0x4e, 0xaf, 0x13, 0xb8, 0x6c, 0x4e, 0x96, 0x10, 0xb0, 0x49, 0x65, 0xfb, 0x4f, 0x33, 0x30,
+	0xc7, 0x5f, 0x3d, 0x1c, 0x90, 0x00, 0x13, 0x99, 0x46, 0xe2, 0x97, 0x4e, 0x26, 0x86, 0x5c, 0x81,
+	0x4c, 0xd8, 0x70, 0x21, 0x64, 0x64, 0xe3, 0xe3, 0xf3, 0x21, 0x58, 0xa0, 0x40, 0x2a, 0x84, 0xb9,
+	0x9a, 0x9b, 0xc9, 0xcd, 0x7f, 0x96, 0x81, 0x12, 0xcf, 0x3f, 0xcf, 0xc0, 0xf5, 0x47, 0x6e, 0xa7,
+	0xdd, 0x4a, 0xd0, 0x41, 0x5f, 0x86, 0xa9, 0x76, 0xef, 0xac, 0xdf, 0x6e, 0x4a, 0x09, 0x0a, 0x51,
+	0xda, 0x96, 0xc0, 0x07, 0x9f, 0x73, 0x54, 0xfd, 0x05, 0x9a, 0xc8, 0x62, 0x7d, 0x2d, 0x71, 0x94,
+	0x7a, 0x19, 0x37, 0x35, 0x3c, 0x3b, 0x30, 0x3e, 0xf4, 0xd3, 0xd0, 0x4b, 0x05, 0x53, 0x2f, 0xad,
+	0x17, 0x21, 0x4f, 0xfb, 0xa1, 0xfd, 0xb7, 0x28, 0xde, 0xfc, 0x69, 0x1a, 0xb5, 0xeb, 0x75, 0xfb,
+	0x2c, 0xd9, 0xe2, 0x77, 0xf2, 0xc6, 0x38, 0xae, 0x48, 0x73, 0x09, 0x8a, 0x34, 0x52, 0x97, 0x79,
+	0x43, 0x5d, 0x62, 0xe7, 0x63, 0xb7, 0xd3, 0x39, 0x72, 0x9b, 0x8f, 0xc5, 0xb6, 0xc8, 0x92, 0x3c,
+	0xab, 0x80, 0xb4, 0x2b, 0xb2, 0x15, 0x85, 0x62, 0x2d, 0xc6, 0xe3, 0x53, 0x80, 0x0e, 0xb2, 0xdf,
+	0x09, 0x85, 0x46, 0xdf, 0x3a, 0x78, 0x41, 0x63, 0x5b, 0x87, 0x6a, 0x18, 0x56, 0xdb, 0x3f, 0xc8,
+	0xc0, 0xb5, 0xb1, 0x25, 0x92, 0x8c, 0xfc, 0x19, 0x19, 0x8e, 0xf6, 0xbf, 0x66, 0xc0, 0xaa, 0xe2,
+	0xfc, 0xba, 0x88, 0xd2, 0x96, 0xe7, 0xfd, 0x74, 0xce, 0x68, 0xda, 0x64, 0xf3, 0xe6, 0x64, 0xd1,
+	0x8c, 0x6b, 0xf6, 0x7b, 0xc7, 0x8d, 0xc0, 0x1d, 0x9e, 0x78, 0x4a, 0x61, 0x01, 0x81, 0xea, 0x02,
+	0x42, 0x0d, 0x70, 0xc5, 0xb8, 0xde, 0x17, 0x4b, 0x54, 0x72, 0x00, 0x41, 0xb2, 0xde, 0xb7, 0x1b,
+	0x30, 0x8d, 0xf3, 0xe0, 0xd6, 0xc8, 0x48, 0xfe, 0xc0, 0xf3, 0x94, 0x35, 0x22, 0x0b, 0xf1, 0x8f,
+	0x64, 0xc7, 0x3e, 0x42, 0xfa, 0x80, 0x26, 0xd0, 0x38, 0xf6, 0xbc, 0x50, 0x1f, 0x10, 0x00, 0x47,
+	0xb6, 0x7f, 0x1d, 0x96, 0x0d, 0x82, 0x31, 0x1b, 0x18, 0x7d, 0x32, 0x66, 0x9f, 0xcb, 0xbf, 0x88,
+	0x02, 0xaa, 0xa6, 0x94, 0x13, 0x3c, 0xb4, 0x20, 0xc9, 0x19, 0x4e, 0xc5, 0x51, 0xf5, 0xf6, 0x0f,
+	0x73, 0x60, 0xd5, 0x50, 0xf0, 0x0f, 0xdc, 0xf3, 0x2e, 0x1a, 0x49, 0x9f, 0xf5, 0x8a, 0x29, 0xf9,
+	0x2d, 0x98, 0xf2, 0x3b, 0x70, 0xcf, 0x91, 0x0e, 0x52, 0x82, 0x64, 0xc1, 0xba, 0x01, 0xa5, 0x8f,
+	0x47, 0xfd, 0xc0, 0x23, 0x9b, 0x44, 0xda, 0x13, 0x53, 0xa2, 0x8c, 0x16, 0xc9, 0x5d, 0xd2, 0x4f,
+	0xcd, 0xce, 0xa8, 0x45, 0x07, 0xe3, 0x1c, 0xe2, 0xb6, 0x22, 0x71, 0xe3, 0x39, 0x6e, 0xcb, 0x3a,
+	0x47, 0x35, 0xd2, 0xcf, 0xad, 0xd3, 0xe6, 0x51, 0x7d, 0x7d, 0xec, 0x70, 0xf1, 0x45, 0x39, 0xd4,
+	0x38, 0xc9, 0x52, 0xcf, 0x19, 0xd7, 0x61, 0xaa, 0x35, 0x3c, 0x6f, 0x0c, 0x47, 0x3d, 0x71, 0xcc,
+	0x28, 0x39, 0x45, 0x2c, 0x3a, 0xa3, 0xde, 0xb3, 0xd9, 0xf4, 0x15, 0x98, 0xe3, 0xef, 0xef, 0x8f,
+	0x82, 0xc1, 0xe8, 0x22, 0x91, 0x8f, 0x56, 0x21, 0x6b, 0x08, 0xeb, 0xdf, 0x64, 0x61, 0x59, 0x9b,
+	0xc7, 0x55, 0x0e, 0xd9, 0x5f, 0x85, 0xa9, 0xbe, 0xf8, 0x2c, 0x9d, 0x06, 0x88, 0x2c, 0xcb, 0x06,
+	0x85, 0x25, 0x4a, 0x8e, 0x6a, 0xa3, 0x2f, 0x48, 0xee, 0x8a, 0x0b, 0x92, 0x37, 0x17, 0x64, 0x43,
+	0x5b, 0x90, 0x82, 0xf8, 0xf2, 0x97, 0xc6, 0x16, 0xc4, 0xbf, 0x64, 0x45, 0x9e, 0x95, 0xf0, 0x2b,
+	0xe6, 0xb7, 0x22, 0xc5, 0x3d, 0x60, 0x98, 0xa9, 0xb8, 0x15, 0x9b, 0x84, 0xd5, 0xf6, 0x7d, 0x58,
+	0x7e, 0x9f, 0x58, 0x35, 0xa6, 0xb4, 0x71, 0xaf, 0x6b, 0x8e, 0x86, 0x74, 0x82, 0x54, 0xa8, 0x84,
+	0x65, 0x21, 0x03, 0xc3, 0x76, 0x33, 0xc4, 0x47, 0x14, 0xec, 0x3f, 0x8e, 0x0e, 0x41, 0x62, 0xc0,
+	0x4f, 0x59, 0x6c, 0x51, 0x38, 0x87, 0xb4, 0x53, 0xca, 0x35, 0x11, 0xbf, 0x4d, 0x45, 0x55, 0x88,
+	0x29, 0xb7, 0x75, 0x58, 0x31, 0x27, 0xca, 0xb4, 0xba, 0x03, 0x45, 0x21, 0xab, 0x8a, 0x52, 0x96,
+	0x71, 0x3a, 0x92, 0x5d, 0xb8, 0x85, 0xfd, 0x3b, 0x19, 0xa6, 0xd6, 0xcf, 0x86, 0x86, 0xb2, 0xbf,
+	0x97, 0x85, 0x59, 0x46, 0x45, 0xd2, 0x5c, 0x57, 0x44, 0x19, 0x53, 0x11, 0x3d, 0x9f, 0xcd, 0x36,
+	0x5d, 0x5b, 0x46, 0xd8, 0x17, 0x0c, 0xec, 0x8d, 0x45, 0x29, 0xc6, 0x76, 0x0f, 0x3c, 0x01, 0x9c,
+	0x0c, 0xfb, 0x3e, 0x9e, 0xd6, 0x64, 0x57, 0xa9, 0x3c, 0x67, 0x04, 0xac, 0x22, 0xfb, 0x9b, 0x47,
+	0xba, 0x52, 0xec, 0x48, 0x67, 0xff, 0x63, 0x06, 0x5e, 0x20, 0x19, 0xa8, 0xb7, 0xbb, 0xde, 0x4e,
+	0xbf, 0xf9, 0xd8, 0xfb, 0x04, 0xbb, 0x47, 0x8a, 0x52, 0xa2, 0xe3, 0x22, 0xce, 0xae, 0x3d, 0x68,
+	0xe3, 0x70, 0x8d, 0xc1, 0xe8, 0x88, 0xe4, 0x52, 0x2e, 0xcd, 0x42, 0x08, 0x3f, 0x10, 0x60, 0xda,
+	0x06, 0x3b, 0xf8, 0xf5, 0xc6, 0xa9, 0xd7, 0x3e, 0x39, 0x95, 0xb4, 0xc1, 0x6d, 0x90, 0x40, 0x0f,
+	0x04, 0x84, 0xc8, 0x20, 0x1a, 0xe0, 0xf6, 0xea, 0xb1, 0x5b, 0xae, 0x44, 0x00, 0xc2, 0xdb, 0xfe,
+	0x49, 0x16, 0x4a, 0x6a, 0x02, 0x34, 0x61, 0x96, 0x4e, 0xcd, 0xd9, 0xc0, 0x90, 0xc9, 0xd6, 0x51,
+	0xf3, 0x65, 0xe6, 0x0c, 0x5f, 0x26, 0xd9, 0x8a, 0x43, 0xaf, 0xe5, 0x79, 0xdd, 0x86, 0x3c, 0xed,
+	0x2a, 0x73, 0x5b, 0x02, 0x6b, 0x02, 0x96, 0x38, 0xed, 0xc2, 0x44, 0xd3, 0x2e, 0x5e, 0x3c, 0xed,
+	0x29, 0x73, 0xda, 0xb1, 0x43, 0x59, 0x29, 0x7e, 0x28, 0x43, 0x1d, 0x34, 0xea, 0x75, 0xc4, 0x9a,
+	0x8a, 0xbd, 0xb0, 0xe4, 0x84, 0x65, 0xfa, 0xf0, 0x11, 0xfd, 0xf4, 0x1b, 0x1d, 0xef, 0x38, 0xc0,
+	0xfd, 0x90, 0xfa, 0x82, 0x04, 0xed, 0x20, 0xc4, 0x6e, 0x49, 0x77, 0x88, 0xa2, 0xea, 0x55, 0x36,
+	0x14, 0x9c, 0x3f, 0x2b, 0xff, 0x46, 0xf8, 0xfd, 0xac, 0xf8, 0xfe, 0x02, 0xc3, 0x0f, 0x19, 0x6c,
+	0x6f, 0xc1, 0x6a, 0xec, 0x2b, 0xac, 0x55, 0xbe, 0x0a, 0x40, 0x53, 0x6e, 0x08, 0x84, 0x58, 0xb3,
+	0xcc, 0xcb, 0x6f, 0xa9, 0xc6, 0xce, 0x74, 0xa0, 0xba, 0xd9, 0x4d, 0xb0, 0x98, 0x6d, 0x63, 0x1e,
+	0xab, 0x8b, 0x38, 0x41, 0xdb, 0xc9, 0xb2, 0x13, 0xec, 0x64, 0xf6, 0x5f, 0x92, 0x23, 0xdb, 0x3d,
+	0xf2, 0x3a, 0x31, 0x09, 0xb9, 0xe4, 0x33, 0xef, 0x42, 0xb1, 0x43, 0xbd, 0xd4, 0xf6, 0x7a, 0x5b,
+	0x7e, 0x25, 0x61, 0x24, 0x09, 0xf3, 0xe5, 0x16, 0xc7, 0x9d, 0xca, 0x6f, 0xc1, 0x8c, 0x06, 0xbe,
+	0xd2, 0xf6, 0xf6, 0xab, 0xb0, 0x22, 0xbd, 0x84, 0x57, 0x43, 0xf8, 0x42, 0x8f, 0x53, 0xda, 0x66,
+	0x22, 0x2c, 0xbd, 0x7c, 0x64, 0xe9, 0xd9, 0xaf, 0xc3, 0xca, 0x06, 0x9d, 0x6d, 0xae, 0x46, 0x2d,
+	0xfb, 0x35, 0x58, 0xe6, 0x0e, 0x07, 0xc3, 0x7e, 0xff, 0x78, 0xc2, 0x5e, 0x4f, 0x43, 0x3d, 0x2e,
+	0x7a, 0xc9, 0x2d, 0x16, 0x7f, 0x28, 0xeb, 0x5e, 0x14, 0xc8, 0x5f, 0xe4, 0xb7, 0x4f, 0xf0, 0xbc,
+	0x36, 0x1a, 0x2a, 0x6a, 0x45, 0x00, 0x6b, 0x15, 0x8a, 0x48, 0x4e, 0x1a, 0x5e, 0x4e, 0xae, 0x80,
+	0x25, 0xe9, 0x12, 0x43, 0x19, 0xee, 0xb4, 0x9b, 0x0d, 0xa2, 0x7b, 0x9e, 0xbf, 0x2c, 0x20, 0x0f,
+	0xbd, 0x73, 0xfb, 0x5f, 0xb2, 0xb0, 0x52, 0x1f, 0xba, 0x3d, 0xff, 0xd8, 0x1b, 0x6e, 0x21, 0xa9,
+	0xfd, 0xe7, 0xee, 0xe2, 0x21, 0xd7, 0x4e, 0x43, 0x99, 0x50, 0x12, 0xb5, 0x19, 0x82, 0x55, 0xd8,
+	0x8c, 0x42, 0x04, 0x83, 0x7e, 0xc3, 0xb4, 0xb1, 0xa6, 0x83, 0xbe, 0xaa, 0x4e, 0xdb, 0x57, 0xd4,
+	0x9a, 0x15, 0x35, 0xeb, 0x3c, 0xf5, 0x36, 0x2b, 0x69, 0x86, 0x9f, 0x8e, 0x49, 0xd6, 0x84, 0xd5,
+	0xd8, 0xc7, 0x42, 0x67, 0x69, 0xa1, 0xe5, 0x1d, 0xb5, 0x03, 0xd3, 0x4d, 0xa1, 0x98, 0x4b, 0xd6,
+	0x59, 0xb7, 0xa1, 0x88, 0xfa, 0xaf, 0xd5, 0x0e, 0x4c, 0xff, 0x8a, 0x6a, 0xc5, 0x95, 0xf6, 0xa6,
+	0xba, 0x7f, 0x64, 0x22, 0x69, 0x47, 0x6d, 0x45, 0xc7, 0x8c, 0x69, 0xab, 0x22, 0xb5, 0x7a, 0x6e,
+	0x57, 0xe1, 0x2b, 0x7e, 0xdb, 0xbf, 0x91, 0x81, 0x29, 0x45, 0xe5, 0x2b, 0xf5, 0x8c, 0x29, 0xee,
+	0x5c, 0x5c, 0x71, 0xeb, 0x7e, 0x83, 0xfc, 0xc5, 0x7e, 0x83, 0xf7, 0xe4, 0xdd, 0x1a, 0xa3, 0x11,
+	0x32, 0x9f, 0xa6, 0x82, 0x35, 0x0f, 0x84, 0xae, 0x82, 0xd7, 0xd5, 0x08, 0x15, 0xa9, 0xe8, 0xa3,
+	0x11, 0x22, 0x1b, 0x98, 0xa7, 0x10, 0xb3, 0x81, 0x15, 0xcd, 0xc2, 0x6a, 0xfb, 0x1b, 0x70, 0x93,
+	0x86, 0xd8, 0xf4, 0x06, 0x7d, 0xbf, 0x1d, 0xf0, 0x2d, 0x82, 0xe7, 0x5f, 0x4a, 0x55, 0xbb, 0x03,
+	0xf3, 0x66, 0xa7, 0xf4, 0x6b, 0xc4, 0x49, 0xf6, 0xed, 0x8b, 0xc9, 0x6a, 0x3b, 0xf0, 0x42, 0x32,
+	0x9a, 0x3c, 0xe3, 0x7b, 0x30, 0xed, 0x2a, 0x20, 0x4f, 0x99, 0x77, 0x04, 0xb3, 0x8b, 0x13, 0x35,
+	0x23, 0xab, 0xfd, 0x3a, 0x13, 0x84, 0xae, 0xba, 0x3c, 0x5d, 0xd3, 0xa5, 0xf3, 0xc4, 0xf3, 0xb1,
+	0x25, 0x91, 0xb3, 0xb4, 0x5b, 0x4c, 0xf1, 0xdb, 0x9a, 0x87, 0x6c, 0x78, 0x6d, 0x89, 0xbf, 0xec,
+	0xff, 0xcb, 0xc0, 0x7c, 0x88, 0x98, 0x94, 0xc6, 0x4b, 0xb4, 0x3f, 0xf9, 0xf2, 0xda, 0xcc, 0xaf,
+	0x38, 0x2a, 0xfd, 0x16, 0x77, 0x7c, 0xe7, 0x3e, 0x0e, 0x62, 0x5e, 0xb2, 0xb2, 0x58, 0xd5, 0x44,
+	0x95, 0xc3, 0x4d, 0x48, 0xe1, 0xb0, 0x0c, 0xb2, 0x3f, 0x49, 0x96, 0x48, 0xe6, 0xa5, 0x00, 0x4b,
+	0x3d, 0xc4, 0x12, 0x8b, 0xba, 0x21, 0x32, 0x6c, 0xe9, 0x27, 0x91, 0x4d, 0x39, 0x49, 0xd9, 0x17,
+	0xa0, 0xbc, 0xa2, 0xda, 0xc6, 0x54, 0x32, 0x37, 0xa6, 0x1b, 0x20, 0x6d, 0xe2, 0xe8, 0x56, 0x71,
+	0x4a, 0x94, 0x71, 0x6b, 0xf8, 0xf7, 0x0c, 0xac, 0x8d, 0xaf, 0x10, 0x2f, 0xf9, 0x97, 0x60, 0xa1,
+	0x3f, 0xf0, 0xc8, 0x05, 0xa9, 0xe4, 0x84, 0x09, 0x32, 0xcf, 0x60, 0x75, 0xd5, 0x80, 0xb6, 0x02,
+	0xf6, 0x1b, 0xb6, 0x3d, 0xb5, 0x8b, 0x33, 0x67, 0x98, 0xb4, 0x75, 0x54, 0x23, 0x1a, 0xb8, 0xd9,
+	0x41, 0x9e, 0xd1, 0x06, 0x66, 0x07, 0x2e, 0x83, 0xd7, 0xa3, 0x39, 0x49, 0xfa, 0xf8, 0xea, 0x40,
+	0xc0, 0x45, 0xa2, 0xa3, 0x20, 0x91, 0xaf, 0x14, 0xb7, 0x2c, 0x89, 0x65, 0xf7, 0x3c, 0x5f, 0x29,
+	0x6e, 0xfa, 0x8d, 0xd6, 0xda, 0x9a, 0x3a, 0xc4, 0xae, 0x9f, 0x4f, 0xec, 0x3f, 0xbc, 0xaa, 0x01,
+	0xb4, 0x05, 0x37, 0x12, 0xbe, 0x72, 0xf5, 0x33, 0xf3, 0xf7, 0x0b, 0x52, 0x6b, 0xc5, 0x9d, 0x15,
+	0xd1, 0x55, 0x72, 0x26, 0x89, 0xcd, 0xcc, 0xab, 0xe4, 0xd7, 0x60, 0xba, 0x85, 0x67, 0x98, 0xa6,
+	0xf0, 0xc7, 0x66, 0xf5, 0x8b, 0x42, 0x6e, 0xbf, 0xa9, 0x6a, 0x9d, 0xa8, 0xe1, 0x73, 0xba, 0xfa,
+	0x89, 0xe4, 0xa1, 0x70, 0xb9, 0x3c, 0x5c, 0x29, 0x64, 0x80, 0xbc, 0x31, 0x7e, 0x7f, 0x18, 0xd0,
+	0x4d, 0xb5, 0xbc, 0x4f, 0x37, 0xd7, 0xc4, 0xaf, 0x61, 0x25, 0x12, 0xbf, 0xe8, 0x8b, 0xbf, 0xe2,
+	0x0a, 0xcc, 0x6f, 0xf2, 0x35, 0x97, 0x34, 0xf2, 0x23, 0x80, 0xbe, 0xc0, 0x30, 0x89, 0xaf, 0x06,
+	0x4f, 0x1b, 0xc2, 0xda, 0x10, 0x0a, 0x60, 0x46, 0x9e, 0x36, 0x08, 0x20, 0x4e, 0x1b, 0xd7, 0x61,
+	0x0a, 0xed, 0x0c, 0x51, 0x35, 0x2b, 0x1d, 0xe8, 0x41, 0x5f, 0x1d, 0x43, 0xe8, 0x8e, 0x84, 0xad,
+	0x0c, 0xbe, 0x40, 0x47, 0x48, 0x74, 0x00, 0xed, 0xba, 0x4f, 0x55, 0xf5, 0x3c, 0x57, 0xbb, 0x4f,
+	0x2b, 0xdd, 0xf8, 0xce, 0xb9, 0x60, 0x6a, 0xc9, 0xdb, 0x30, 0x8f, 0x92, 0xd2, 0xf4, 0x1a, 0x3e,
+	0xf1, 0x07, 0x89, 0xd0, 0xa2, 0x20, 0xd5, 0x9c, 0x80, 0xd6, 0x18, 0x68, 0xbd, 0x0e, 0x10, 0x5d,
+	0xba, 0xad, 0x2d, 0x09, 0xa2, 0xf1, 0xcd, 0xd1, 0xfb, 0x21, 0x5c, 0xc8, 0xa9, 0xa3, 0x35, 0x54,
+	0xf7, 0xbd, 0xcf, 0xe0, 0xfb, 0x49, 0xb9, 0xef, 0x3d, 0x83, 0xd5, 0xea, 0xd3, 0x01, 0x2e, 0x4f,
+	0x9c, 0xbd, 0xbf, 0x0e, 0xc5, 0xe3, 0x76, 0x27, 0xf0, 0x86, 0x6c, 0xc2, 0xdc, 0xe0, 0x83, 0xc0,
+	0xb8, 0x24, 0x38, 0xdc, 0x90, 0x9c, 0x2b, 0xc7, 0xfd, 0x61, 0xd7, 0x55, 0x3b, 0x05, 0x3b, 0x57,
+	0xe4, 0xf8, 0x5b, 0xa2, 0xc6, 0xe1, 0x16, 0xf6, 0x17, 0x60, 0x46, 0xc2, 0x37, 0x4e, 0x47, 0xbd,
+	0xc7, 0xa4, 0x26, 0x84, 0x1d, 0x47, 0xdf, 0x9a, 0x75, 0xe4, 0xed, 0xca, 0x1f, 0x65, 0xb5, 0x4b,
+	0xfa, 0x4f, 0xe0, 0x2a, 0x9c, 0xc0, 0x60, 0x35, 0xc4, 0x32, 0x37, 0xa9, 0x58, 0x6a, 0x8c, 0x9a,
+	0x9f, 0x84, 0x51, 0x5f, 0x81, 0x25, 0x62, 0x39, 0x72, 0x93, 0xb7, 0x69, 0xf2, 0x38, 0x86, 0xcf,
+	0xbb, 0xde, 0x22, 0x56, 0x6c, 0xe8, 0x70, 0x3a, 0xb4, 0x23, 0x2d, 0x11, 0xec, 0x76, 0x1a, 0xfd,
+	0x5e, 0xe7, 0x9c, 0xaf, 0x06, 0x66, 0x15, 0x70, 0x1f, 0x61, 0xf6, 0xef, 0x65, 0xa0, 0x70, 0x20,
+	0x9c, 0xd1, 0xca, 0x60, 0xcb, 0x68, 0x06, 0xdb, 0x67, 0xe4, 0xfc, 0xb1, 0x5f, 0xa6, 0x48, 0x8c,
+	0x6e, 0xff, 0xcc, 0x13, 0xa8, 0xa9, 0x95, 0x4a, 0xc0, 0xd0, 0xfe, 0xb3, 0x0c, 0x94, 0xd6, 0x91,
+	0xb7, 0x85, 0xdc, 0x47, 0xc1, 0x7a, 0x19, 0x3d, 0x58, 0x8f, 0xb6, 0xc9, 0x4e, 0xff, 0xa4, 0xdf,
+	0x18, 0x0d, 0x3b, 0xea, 0x68, 0x47, 0xe5, 0xc3, 0x61, 0x47, 0x5c, 0x24, 0x0e, 0xdb, 0x5d, 0x77,
+	0x78, 0x8e, 0x54, 0xed, 0xf4, 0x87, 0xbc, 0x5d, 0xcd, 0x32, 0x70, 0x83, 0x60, 0x74, 0x1a, 0x41,
+	0xe1, 0x24, 0xcb, 0x41, 0xb6, 0xe1, 0x10, 0x3a, 0x09, 0x93, 0x4d, 0x6e, 0xc1, 0x8c, 0x3f, 0xc2,
+	0xb2, 0xef, 0x8b, 0xaf, 0xc8, 0xe9, 0x00, 0x83, 0xf0, 0x43, 0xf6, 0x2f, 0xc0, 0xaa, 0x9c, 0x92,
+	0xc2, 0x56, 0xcd, 0x2a, 0x05, 0x69, 0xfb, 0x2d, 0xb0, 0x58, 0x44, 0x3c, 0x4f, 0x3f, 0x0e, 0x14,
+	0xc5, 0xdd, 0x81, 0x12, 0xd2, 0x99, 0x90, 0x61, 0x90, 0x4e, 0x5c, 0x65, 0xff, 0x61, 0x06, 0x66,
+	0x3f, 0x70, 0x83, 0xe6, 0xa9, 0x32, 0x2f, 0x51, 0x62, 0x4f, 0x86, 0xfd, 0xd1, 0x40, 0x9d, 0x0b,
+	0x45, 0xe1, 0xd9, 0x5c, 0x42, 0xe9, 0xfe, 0xed, 0x32, 0x94, 0x90, 0xbd, 0x90, 0xc1, 0xcf, 0xa4,
+	0xc7, 0xaa, 0xe4, 0x84, 0x65, 0x7b, 0x1f, 0x6e, 0x6e, 0x77, 0x49, 0x58, 0x75, 0xf4, 0x22, 0x93,
+	0xf9, 0x6b, 0xe3, 0xa6, 0x28, 0x8b, 0xbe, 0xde, 0x5e, 0x37, 0x44, 0xcf, 0x61, 0x86, 0xa1, 0xeb,
+	0xfd, 0xbe, 0x08, 0xd7, 0x3c, 0xc2, 0xe3, 0x53, 0x18, 0x17, 0xc1, 0xa5, 0x4f, 0xe5, 0x08, 0xfc,
+	0xbd, 0x0c, 0xdc, 0x90, 0x93, 0xd1, 0x30, 0x08, 0x17, 0xea, 0x9a, 0xb6, 0x50, 0xb4, 0xff, 0x71,
+	0xc9, 0x7a, 0x08, 0x0b, 0x4f, 0x68, 0x2e, 0x8d, 0x68, 0xa2, 0xf2, 0xcc, 0x66, 0xf3, 0x05, 0x74,
+	0x22, 0x79, 0xe4, 0xa0, 0xce, 0xfc, 0x13, 0x03, 0x8e, 0x07, 0x89, 0x17, 0x2e, 0x6a, 0x4f, 0xeb,
+	0x8e, 0x9f, 0xe1, 0xdb, 0x3e, 0xdc, 0x83, 0x45, 0x81, 0x96, 0x8e, 0xef, 0xe6, 0xf9, 0xde, 0x4d,
+	0x15, 0x89, 0x4c, 0xa3, 0x5e, 0xf3, 0xd4, 0xed, 0x9d, 0x78, 0x92, 0x16, 0x73, 0x4e, 0x04, 0xb0,
+	0x3f, 0x84, 0x1b, 0x92, 0x85, 0x8d, 0xc5, 0xb8, 0x5a, 0x6c, 0xa5, 0x11, 0x7f, 0x15, 0xc6, 0x4a,
+	0xd6, 0xe1, 0x06, 0xf1, 0x7a, 0x32, 0x53, 0x4c, 0x30, 0x72, 0xc8, 0xdf, 0x59, 0x8d, 0xbf, 0xed,
+	0x3d, 0x28, 0x27, 0x8d, 0xca, 0xb4, 0xb9, 0x3a, 0xaf, 0xfd, 0x41, 0x16, 0x40, 0xd4, 0xc9, 0x68,
+	0x2d, 0xd4, 0x2a, 0xde, 0x99, 0x71, 0x9c, 0x98, 0x12, 0x65, 0xc9, 0x39, 0xda, 0x89, 0x2c, 0x1b,
+	0x3f, 0xe8, 0x86, 0xe8, 0xe6, 0x12, 0xc5, 0x31, 0x3f, 0x09, 0x05, 0x0b, 0xa6, 0x38, 0x1a, 0xfb,
+	0x4f, 0x71, 0xd2, 0xfd, 0x27, 0xd2, 0xbf, 0x53, 0x86, 0x93, 0x64, 0x19, 0x77, 0xf8, 0xa7, 0x34,
+	0xaf, 0x12, 0x47, 0x36, 0x3c, 0x95, 0xfe, 0xb1, 0xe4, 0x2b, 0x46, 0xfb, 0x2e, 0x5c, 0x0b, 0x09,
+	0x2d, 0x68, 0x13, 0xae, 0x5d, 0xa2, 0xe2, 0xb1, 0x37, 0xe0, 0xfa, 0x58, 0x7b, 0x5e, 0x95, 0x97,
+	0xa1, 0x28, 0x88, 0xa8, 0x96, 0x64, 0x51, 0x5b, 0x12, 0xd1, 0xd4, 0xe1, 0x7a, 0x7b, 0x04, 0x37,
+	0x1d, 0xaf, 0xe5, 0x75, 0x50, 0xad, 0x0c, 0x27, 0xfd, 0xf2, 0x58, 0xe8, 0x50, 0xf6, 0xb2, 0xd0,
+	0xa1, 0x5c, 0x2c, 0x74, 0xc8, 0x7e, 0x03, 0x5e, 0x48, 0xfe, 0x6c, 0x24, 0xf7, 0xe1, 0x04, 0x84,
+	0xdc, 0x33, 0xba, 0xbb, 0x60, 0xd5, 0xce, 0x7b, 0xcd, 0xc3, 0x9e, 0x3f, 0xb8, 0xda, 0x2d, 0x03,
+	0x4e, 0x04, 0x2d, 0x1d, 0xbe, 0x36, 0x2b, 0x39, 0xb2, 0x60, 0xbf, 0x07, 0x37, 0xef, 0x7b, 0x01,
+	0x8f, 0x46, 0x03, 0xf3, 0x31, 0x61, 0xe2, 0x71, 0xed, 0xdf, 0xcc, 0xc0, 0xd2, 0x58, 0x7f, 0xeb,
+	0x25, 0x98, 0xed, 0xb8, 0x7e, 0xd0, 0xf0, 0x11, 0x14, 0xc5, 0xf2, 0x00, 0xc1, 0xa8, 0x95, 0x08,
+	0xe6, 0x59, 0x18, 0xc9, 0x6e, 0x8d, 0xe8, 0xfe, 0x94, 0x1a, 0xcd, 0x33, 0x78, 0x9f, 0x6f, 0x4c,
+	0x5f, 0x06, 0x72, 0xba, 0x20, 0x51, 0x70, 0xa9, 0xd1, 0x62, 0xa5, 0x33, 0x64, 0x4e, 0x84, 0xbf,
+	0xc4, 0xc1, 0xf6, 0x37, 0xe4, 0x56, 0x77, 0x65, 0xda, 0x50, 0x84, 0xcf, 0xdc, 0xa1, 0xfe, 0xd5,
+	0x88, 0x73, 0x33, 0x1a, 0xe7, 0xa2, 0xe1, 0x70, 0x86, 0xb8, 0xb2, 0xb6, 0x13, 0xbf, 0x2f, 0xd8,
+	0xd9, 0xd2, 0x22, 0x8a, 0x7f, 0x1e, 0xe6, 0x92, 0x0c, 0x2f, 0x13, 0x48, 0xbd, 0xd9, 0xf7, 0x2f,
+	0xcd, 0x2d, 0x2e, 0xd9, 0xdf, 0x95, 0x67, 0xbf, 0x70, 0x8e, 0xa1, 0xc3, 0x3f, 0xbc, 0x85, 0xce,
+	0xe8, 0xb7, 0xd0, 0xc6, 0xac, 0xa2, 0x5b, 0x68, 0xc3, 0xf4, 0x9e, 0x56, 0xa6, 0xb7, 0x03, 0xcb,
+	0xdb, 0xfe, 0xfe, 0x68, 0xf8, 0x3c, 0x55, 0xf2, 0x9f, 0x64, 0x60, 0xc5, 0x1c, 0xf4, 0xb2, 0x88,
+	0x77, 0x3a, 0x29, 0xb5, 0x7d, 0x64, 0x8a, 0xa1, 0xcf, 0xbc, 0x5a, 0x6c, 0xd3, 0x00, 0x7e, 0x5a,
+	0x12, 0x05, 0x89, 0x1a, 0xab, 0x10, 0x5a, 0x31, 0xde, 0x60, 0x19, 0x32, 0xa6, 0x45, 0x0b, 0x71,
+	0xbf, 0xd6, 0xef, 0x66, 0x50, 0xa4, 0x70, 0x0f, 0xdf, 0xc5, 0x6f, 0xbb, 0x27, 0xcf, 0x39, 0x50,
+	0xe7, 0x42, 0xc3, 0xa7, 0x2b, 0xbf, 0xa8, 0x0c, 0x1f, 0x2e, 0xda, 0x0f, 0x61, 0xd9, 0xc0, 0x87,
+	0x09, 0x66, 0xd8, 0x1e, 0x99, 0xb8, 0xed, 0x81, 0xb4, 0xa1, 0x02, 0x9e, 0x8e, 0xf8, 0x12, 0x51,
+	0x96, 0xe8, 0xd6, 0x65, 0xe5, 0x91, 0x37, 0x6c, 0x1f, 0x9f, 0xff, 0xac, 0xcc, 0xcf, 0x9c, 0x48,
+	0x21, 0x36, 0x11, 0xbb, 0x0a, 0xab, 0x31, 0x7c, 0x23, 0x23, 0xe4, 0x8c, 0x42, 0xbc, 0xd8, 0x13,
+	0x2b, 0x0b, 0xa9, 0xf3, 0x76, 0x60, 0x4d, 0x38, 0xc2, 0x5d, 0xb1, 0x43, 0xad, 0x9f, 0x53, 0x54,
+	0xed, 0x15, 0xa6, 0x1e, 0xca, 0x7f, 0x36, 0x92, 0x7f, 0xfb, 0x9b, 0xb0, 0xa8, 0x8d, 0xb9, 0xdd,
+	0xbb, 0x8a, 0xa2, 0xb0, 0x3f, 0x82, 0x25, 0xad, 0x33, 0xab, 0x19, 0xd5, 0x30, 0x93, 0xac, 0x51,
+	0xb2, 0x69, 0x1a, 0x25, 0x17, 0x8f, 0x5e, 0x99, 0xd1, 0xc6, 0x4e, 0xc6, 0x09, 0xa5, 0xe0, 0x48,
+	0x5e, 0x96, 0x52, 0xd8, 0x31, 0xdb, 0xae, 0x02, 0x22, 0x82, 0xef, 0xc3, 0x6a, 0xe1, 0xa1, 0xe0,
+	0xed, 0xea, 0x28, 0xbc, 0x2b, 0x1d, 0x53, 0x5a, 0xf9, 0x24, 0xa5, 0xc5, 0xde, 0xc8, 0x42, 0xe4,
+	0x8d, 0xbc, 0x0b, 0xc5, 0x76, 0x4f, 0xa8, 0xa5, 0xa2, 0x50, 0x4b, 0xd7, 0xb4, 0x0b, 0x11, 0x8d,
+	0x8c, 0x0e, 0xb7, 0xc2, 0x43, 0x7e, 0xa8, 0xc7, 0xe4, 0x0d, 0xca, 0xf5, 0xb1, 0x0e, 0x71, 0x5d,
+	0x86, 0x56, 0xf7, 0xd0, 0x7d, 0xd2, 0x08, 0x9e, 0xb2, 0x95, 0x51, 0xc0, 0x52, 0xfd, 0x29, 0x9d,
+	0xa4, 0x22, 0x3f, 0xad, 0x8f, 0xa6, 0x06, 0x6d, 0x19, 0x10, 0x3a, 0x6a, 0x7d, 0xfb, 0x1f, 0x32,
+	0xd1, 0x5d, 0x49, 0xbd, 0x7f, 0xe0, 0x79, 0x43, 0xed, 0x80, 0x38, 0xf0, 0xd8, 0xcf, 0x80, 0xe4,
+	0xa3, 0xdf, 0x93, 0x1d, 0x61, 0x7f, 0x8a, 0x97, 0x4d, 0xf6, 0xbf, 0x65, 0xc1, 0xda, 0x42, 0x0b,
+	0x62, 0x28, 0x68, 0xaf, 0x26, 0x42, 0xd3, 0x0e, 0xf8, 0x77, 0xc4, 0x01, 0xa0, 0x40, 0x92, 0x37,
+	0xc5, 0xe4, 0xb2, 0x49, 0x93, 0xcb, 0x4d, 0x92, 0xd0, 0x94, 0x8f, 0x7b, 0xe3, 0x67, 0x69, 0x90,
+	0x70, 0x56, 0x1c, 0xc9, 0x4d, 0xb0, 0xf1, 0x79, 0x15, 0x8d, 0x79, 0xdd, 0x0d, 0x3d, 0x96, 0x53,
+	0xba, 0xa9, 0x19, 0x4d, 0x6b, 0x3c, 0xff, 0x45, 0xf3, 0xbd, 0x97, 0xe2, 0xbe, 0xf7, 0xdb, 0x30,
+	0x7f, 0xec, 0xb6, 0x3b, 0xa8, 0x45, 0x1a, 0xa8, 0xdd, 0x7d, 0xb4, 0x60, 0xa5, 0x81, 0x39, 0xc7,
+	0x50, 0x47, 0x00, 0x63, 0xfb, 0x01, 0xc4, 0xf7, 0x83, 0x1f, 0x64, 0x74, 0xc2, 0x1e, 0xd0, 0xcd,
+	0x05, 0x09, 0xd5, 0x27, 0x64, 0x8a, 0xb4, 0x3b, 0xdf, 0x57, 0x60, 0x49, 0x45, 0x1e, 0xab, 0xc5,
+	0x51, 0x42, 0xb5, 0xc8, 0x15, 0x6a, 0x4d, 0x7d, 0xd4, 0x1d, 0xb7, 0x68, 0xd3, 0x1f, 0xc7, 0x2a,
+	0xda, 0x4e, 0xdf, 0x80, 0xe9, 0x81, 0x02, 0xb2, 0x09, 0xb0, 0x16, 0xa7, 0xa6, 0xea, 0xe5, 0x44,
+	0x4d, 0xed, 0x03, 0xb8, 0x5e, 0xf3, 0x82, 0xa0, 0xe3, 0x45, 0xcd, 0x9e, 0x4d, 0x0c, 0xec, 0x7f,
+	0x42, 0x83, 0x90, 0x07, 0x43, 0x4b, 0x77, 0x62, 0xbe, 0x8c, 0x4b, 0x4f, 0xf6, 0x32, 0xe9, 0xc9,
+	0xc5, 0xa5, 0x67, 0x82, 0x83, 0xcf, 0x55, 0x04, 0x6c, 0x17, 0xae, 0x0b, 0x27, 0xfd, 0x99, 0xa7,
+	0x26, 0x11, 0x12, 0xbb, 0x2c, 0x2e, 0xf7, 0xbc, 0x41, 0xe0, 0xa9, 0xdd, 0x28, 0x2c, 0xd3, 0x27,
+	0x98, 0xf9, 0x78, 0x43, 0x92, 0x25, 0xfb, 0xb7, 0x32, 0xb0, 0x1c, 0x92, 0x45, 0x92, 0x9c, 0xd8,
+	0x96, 0x3c, 0x47, 0x7e, 0x58, 0x8a, 0x48, 0x33, 0x1b, 0x01, 0x27, 0x8b, 0xba, 0x49, 0x63, 0xb4,
+	0x70, 0x33, 0xc8, 0x6b, 0x3b, 0xd9, 0xbb, 0xb0, 0x16, 0xa1, 0x70, 0x65, 0x73, 0xcf, 0x7e, 0x1d,
+	0x6e, 0x24, 0x74, 0xbf, 0x34, 0x95, 0xd1, 0x87, 0xa5, 0xda, 0x13, 0xcf, 0x1b, 0x7c, 0x0a, 0x17,
+	0xfd, 0xa9, 0x76, 0x08, 0x9d, 0x4f, 0x16, 0x45, 0x38, 0x31, 0xf9, 0x37, 0xae, 0x14, 0xd7, 0x59,
+	0x1c, 0xa0, 0x1d, 0xd2, 0x6f, 0xf1, 0x57, 0x57, 0xc3, 0xb8, 0x61, 0x39, 0xd4, 0x81, 0xa8, 0x74,
+	0xb8, 0x51, 0x78, 0x9b, 0x98, 0x1b, 0xbb, 0x4d, 0xcc, 0x87, 0xb7, 0x89, 0xb8, 0xe3, 0x94, 0x28,
+	0xee, 0x98, 0x8c, 0xed, 0xe7, 0x34, 0x6f, 0x75, 0x9b, 0x95, 0x8b, 0x6e, 0xb3, 0x52, 0x0f, 0x1e,
+	0x65, 0xcd, 0x35, 0x5f, 0x10, 0x2e, 0xf7, 0xc8, 0x17, 0x6f, 0xc3, 0x6c, 0x10, 0x6d, 0xb1, 0xf2,
+	0x76, 0x2c, 0xef, 0x18, 0x30, 0xfb, 0x17, 0x45, 0x00, 0xf8, 0x07, 0xed, 0x5e, 0xab, 0xff, 0x44,
+	0x04, 0x80, 0x07, 0xee, 0x50, 0x9d, 0xec, 0x64, 0x81, 0x0c, 0x00, 0xd4, 0x5d, 0x7c, 0x90, 0xa3,
+	0x9f, 0xd6, 0x17, 0xd1, 0x66, 0xa7, 0xf9, 0xaa, 0xf0, 0xeb, 0xf9, 0x28, 0xfc, 0x9a, 0xc0, 0x0e,
+	0xd7, 0xda, 0xc7, 0xa4, 0x34, 0xc2, 0x55, 0x8a, 0xb2, 0x2b, 0x9e, 0x88, 0xcf, 0x29, 0x95, 0x16,
+	0x05, 0x6f, 0x4b, 0x34, 0x1c, 0x55, 0xaf, 0x7d, 0x27, 0x7b, 0xe1, 0x77, 0xea, 0x70, 0xfd, 0xc0,
+	0x1d, 0xf9, 0xd8, 0x3f, 0x38, 0x6d, 0xa1, 0xa5, 0x80, 0xb0, 0xab, 0x85, 0xea, 0x25, 0x0a, 0x37,
+	0xca, 0x13, 0x22, 0x3d, 0xea, 0x7e, 0xb2, 0x61, 0xed, 0x36, 0x2c, 0x44, 0x1d, 0x05, 0x7a, 0xcf,
+	0x80, 0x0c, 0x5d, 0x43, 0x0d, 0x68, 0x0c, 0xed, 0x1a, 0xbf, 0x24, 0x01, 0xb8, 0xbb, 0xed, 0xca,
+	0x5b, 0xfc, 0xd8, 0xe7, 0xf4, 0xc8, 0xb1, 0xa2, 0x68, 0x1b, 0x4b, 0x22, 0x8a, 0xb5, 0x77, 0xb8,
+	0x11, 0x5d, 0xe0, 0x2f, 0xf2, 0x5d, 0xec, 0x76, 0xef, 0xcc, 0x1d, 0xb6, 0xdd, 0xde, 0xa4, 0x84,
+	0xec, 0x78, 0xad, 0x93, 0xc8, 0x6c, 0x97, 0x25, 0xe2, 0xd1, 0xd3, 0x7e, 0xa7, 0x25, 0x92, 0x5c,
+	0x38, 0x3f, 0x40, 0x95, 0xc5, 0xb9, 0xe1, 0x14, 0xd9, 0x83, 0x32, 0x4b, 0x94, 0xed, 0x14, 0x02,
+	0x88, 0x21, 0xbd, 0xe1, 0xb0, 0xaf, 0xf2, 0x4f, 0x64, 0xc1, 0xfe, 0xe7, 0x0c, 0xdc, 0x88, 0xe3,
+	0xa7, 0x6f, 0x9a, 0xd0, 0x0e, 0xa1, 0x3c, 0xe1, 0x6b, 0x46, 0xac, 0x48, 0xd8, 0xc9, 0xd1, 0x5a,
+	0x92, 0xef, 0xc2, 0x47, 0xe6, 0xf6, 0x1b, 0xfe, 0x88, 0x8e, 0xd7, 0xad, 0x30, 0x42, 0x6f, 0x5e,
+	0x80, 0x6b, 0x0a, 0x4a, 0xbb, 0xbc, 0x6c, 0xe2, 0x53, 0x6e, 0x0d, 0xaf, 0x96, 0x9c, 0xd7, 0x62,
+	0x54, 0xc1, 0x76, 0x09, 0xee, 0x80, 0xe1, 0x78, 0xb4, 0x74, 0x9c, 0x5e, 0x1d, 0xc2, 0x70, 0xf5,
+	0xbe, 0x02, 0x96, 0x33, 0xea, 0xd5, 0xbc, 0xce, 0x71, 0x9d, 0xee, 0xb9, 0x22, 0xd7, 0x7f, 0xd3,
+	0xed, 0xb9, 0xc3, 0x73, 0xde, 0x8c, 0xb8, 0x84, 0x47, 0xa9, 0x65, 0xa3, 0x35, 0xcf, 0xfa, 0x2e,
+	0xdd, 0xa3, 0xf8, 0xa3, 0x4e, 0x10, 0x0b, 0xd3, 0xd0, 0x1a, 0x62, 0xa5, 0xa3, 0x1a, 0xd9, 0x23,
+	0x98, 0xd9, 0xf2, 0xc4, 0xe1, 0x6c, 0xab, 0xe3, 0x9e, 0x24, 0x5e, 0xf0, 0xac, 0xd1, 0xfd, 0x3e,
+	0xa5, 0x55, 0x29, 0x42, 0xa8, 0x22, 0xd5, 0xc8, 0x53, 0xba, 0xf2, 0xda, 0xa8, 0xa2, 0xf5, 0x22,
+	0x1a, 0x73, 0xde, 0x90, 0xae, 0x3e, 0xd4, 0x19, 0x71, 0xce, 0xd1, 0x20, 0xf6, 0x06, 0xac, 0x49,
+	0xa3, 0x27, 0xfc, 0xb4, 0xaf, 0x05, 0x1e, 0x14, 0x8e, 0x09, 0xc0, 0x13, 0x58, 0x52, 0xc2, 0x1e,
+	0x36, 0x75, 0x64, 0xbd, 0xfd, 0x3e, 0xac, 0x45, 0xb7, 0x98, 0x57, 0x8b, 0xe3, 0x4b, 0x93, 0xf5,
+	0x37, 0xe8, 0x06, 0xa6, 0x83, 0xbf, 0xaf, 0x36, 0x9e, 0xdd, 0xa4, 0x70, 0x42, 0xc4, 0xaf, 0xf7,
+	0xbc, 0xc2, 0x09, 0x95, 0xd1, 0x92, 0xd3, 0x8c, 0x96, 0xbf, 0xcb, 0xc0, 0xda, 0x76, 0xef, 0x57,
+	0xbc, 0x66, 0x40, 0x2b, 0x19, 0xfb, 0xd2, 0x67, 0x95, 0x43, 0x8f, 0x76, 0x79, 0xb3, 0xdf, 0x1d,
+	0x74, 0xbc, 0xc0, 0x6b, 0xb8, 0xc7, 0x74, 0x83, 0x2b, 0xb7, 0x9f, 0x39, 0x05, 0xad, 0x10, 0xd0,
+	0xbe, 0x07, 0x0b, 0x9b, 0x6d, 0xf7, 0xa4, 0xd7, 0xf7, 0x43, 0x27, 0x05, 0x5d, 0x87, 0x05, 0x23,
+	0xca, 0x2c, 0x3b, 0x56, 0x17, 0xbf, 0x79, 0x07, 0x04, 0x48, 0xf6, 0x79, 0x13, 0x66, 0xc5, 0x6d,
+	0xe5, 0xc9, 0xfe, 0x40, 0x59, 0xe9, 0x63, 0xcc, 0x99, 0x18, 0x2d, 0x67, 0xff, 0x38, 0x03, 0x0b,
+	0xd8, 0xb5, 0x87, 0xa4, 0xea, 0x0f, 0x1f, 0x78, 0x6e, 0x27, 0x38, 0x7d, 0x7e, 0xb6, 0xc8, 0xa9,
+	0x18, 0x4f, 0x86, 0x6b, 0xa3, 0x30, 0x70, 0x31, 0xd2, 0x51, 0x79, 0x4d, 0x47, 0x59, 0x6f, 0xc3,
+	0xac, 0xf2, 0x84, 0x92, 0xbb, 0x54, 0x10, 0x27, 0x3c, 0xf8, 0x8e, 0xbb, 0x66, 0x67, 0x46, 0x11,
+	0xc8, 0x76, 0x00, 0xaa, 0x34, 0xc8, 0x86, 0x3a, 0x67, 0x75, 0xbd, 0x60, 0xd8, 0x6e, 0xaa, 0x6b,
+	0x2b, 0x59, 0x12, 0xda, 0x36, 0x8a, 0xa1, 0x9d, 0x56, 0xc1, 0xb1, 0x84, 0x4f, 0x64, 0x4b, 0xe7,
+	0x1d, 0x59, 0x40, 0x06, 0x87, 0xf7, 0x47, 0xde, 0xc8, 0xdb, 0x44, 0x83, 0xf6, 0x34, 0x8d, 0xa2,
+	0x2d, 0xaa, 0x54, 0x37, 0xf7, 0xa2, 0x60, 0xff, 0x57, 0x16, 0x16, 0xa3, 0x05, 0x8c, 0xac, 0xc1,
+	0x33, 0x3c, 0xc2, 0xd0, 0x75, 0x02, 0xf3, 0x1c, 0x17, 0x89, 0xef, 0x4f, 0xfa, 0x0d, 0x55, 0xc9,
+	0x0e, 0x89, 0x93, 0xfe, 0x23, 0xae, 0xd6, 0xde, 0x52, 0xc9, 0x99, 0x6f, 0xa9, 0x60, 0x47, 0x61,
+	0x6d, 0xe8, 0x5a, 0x72, 0x9a, 0x21, 0x15, 0xca, 0x7e, 0x2f, 0x0a, 0xaf, 0xc4, 0x09, 0x67, 0xc5,
+	0xf0, 0x6d, 0x8c, 0xce, 0x26, 0x0e, 0xb7, 0xa0, 0xe0, 0x87, 0xa6, 0xe2, 0x01, 0xe5, 0xa2, 0x58,
+	0x0d, 0xdb, 0xeb, 0xbc, 0xe1, 0x68, 0x0d, 0xc5, 0xed, 0x02, 0x51, 0x5d, 0x39, 0x29, 0xf8, 0x76,
+	0x21, 0x5a, 0x09, 0x87, 0xeb, 0xa9, 0xe5, 0xc7, 0x44, 0x4b, 0x5f, 0xa4, 0x5f, 0x85, 0x2d, 0x23,
+	0xfa, 0x3a, 0x5c, 0x6f, 0xbd, 0x06, 0xf3, 0x92, 0xd5, 0x43, 0x1b, 0x6d, 0x3a, 0x29, 0x7c, 0x62,
+	0x4e, 0x34, 0x52, 0xc1, 0x07, 0xf6, 0x8f, 0xa7, 0x60, 0x8a, 0x0b, 0x97, 0x29, 0x12, 0x33, 0xb9,
+	0x36, 0x1b, 0x4f, 0xae, 0x4d, 0x79, 0x09, 0x64, 0x82, 0xe8, 0xa1, 0xfc, 0xa4, 0xd7, 0x44, 0x51,
+	0xdc, 0xcf, 0xcc, 0xe5, 0x71, 0x3f, 0xa1, 0x2c, 0x16, 0x2e, 0xf2, 0x49, 0x28, 0x7d, 0x56, 0x4c,
+	0x0f, 0x68, 0x9b, 0x32, 0x02, 0xda, 0x22, 0x01, 0x2e, 0x4d, 0xa0, 0xc7, 0xa6, 0xd3, 0x73, 0x49,
+	0x20, 0x96, 0x4b, 0xa2, 0xb4, 0xf1, 0xac, 0x16, 0x10, 0xac, 0xa7, 0xec, 0xce, 0xc5, 0x9e, 0x12,
+	0x58, 0x51, 0x5b, 0xd8, 0xbc, 0xa8, 0x90, 0x85, 0x71, 0x3f, 0xdb, 0x62, 0x92, 0x9f, 0xed, 0xab,
+	0x60, 0x19, 0x00, 0x99, 0x85, 0xb0, 0x24, 0x9a, 0x2e, 0x19, 0x35, 0x94, 0x8c, 0xa0, 0xfb, 0x6e,
+	0x2c, 0xd3, 0x77, 0xa3, 0xbf, 0x18, 0xb2, 0xac, 0xbf, 0x18, 0xc2, 0x6b, 0x92, 0x9a, 0xc9, 0x77,
+	0x17, 0x4a, 0xe4, 0x28, 0xec, 0x50, 0xcc, 0xd0, 0x8a, 0x2e, 0x66, 0xdc, 0x51, 0xde, 0xb1, 0x85,
+	0x6d, 0x88, 0x74, 0xfc, 0x74, 0x46, 0xff, 0x78, 0x6d, 0x55, 0x3d, 0x31, 0x42, 0x80, 0xfd, 0x63,
+	0x22, 0x53, 0x18, 0xa3, 0x74, 0x4d, 0x1e, 0x4c, 0xfc, 0xe4, 0xf0, 0xa4, 0xeb, 0x13, 0x86, 0x27,
+	0x91, 0xe1, 0x15, 0x95, 0x94, 0xe1, 0xb5, 0x26, 0x0d, 0xaf, 0xa8, 0x22, 0x72, 0x08, 0x1d, 0xb7,
+	0xdd, 0xa0, 0x21, 0x77, 0x89, 0x1b, 0x52, 0x70, 0x08, 0xf2, 0x48, 0xa5, 0x47, 0x8b, 0xea, 0x30,
+	0x23, 0xad, 0xcc, 0x19, 0xce, 0x08, 0xdc, 0x60, 0xd8, 0xb3, 0x45, 0x6d, 0x9f, 0x86, 0xf1, 0xf7,
+	0x17, 0x3c, 0xe0, 0xa1, 0xb7, 0xd0, 0x1e, 0xf0, 0x48, 0x0a, 0x38, 0xc5, 0x05, 0x6f, 0x21, 0x32,
+	0xed, 0x4e, 0x78, 0x1a, 0xe6, 0x22, 0x1e, 0x7f, 0x96, 0x39, 0x74, 0xfb, 0x60, 0xfb, 0xa1, 0x77,
+	0x7e, 0x41, 0x48, 0x0c, 0x1e, 0xbe, 0x8a, 0x7e, 0xb3, 0x3f, 0xe0, 0x90, 0xcd, 0x79, 0x65, 0x64,
+	0xc9, 0x8e, 0x35, 0xaa, 0x71, 0xb8, 0x81, 0xfd, 0xfb, 0x19, 0x28, 0x4a, 0x38, 0x9d, 0x79, 0x43,
+	0xe5, 0x83, 0xbf, 0x12, 0xe3, 0xb7, 0xa3, 0x91, 0x73, 0x97, 0x8c, 0x1c, 0xf3, 0xd5, 0xe5, 0x13,
+	0x1e, 0xd7, 0x19, 0x7a, 0x67, 0xfd, 0xc7, 0xc6, 0xd5, 0x0e, 0x43, 0xd0, 0x5c, 0xde, 0x0f, 0x03,
+	0xd5, 0x79, 0xb6, 0xbc, 0x29, 0xdd, 0x46, 0x81, 0x18, 0xb4, 0x1b, 0x6a, 0x7d, 0x66, 0xee, 0xcd,
+	0xea, 0x18, 0xa0, 0xbc, 0x0f, 0xda, 0x34, 0x17, 0x5e, 0xc2, 0x6c, 0xb8, 0x84, 0xf6, 0x6d, 0xb4,
+	0xa8, 0xc5, 0xe8, 0x26, 0xf9, 0x62, 0x93, 0xb6, 0xbf, 0xc5, 0x61, 0xe5, 0xa2, 0x91, 0x6e, 0xb5,
+	0x96, 0xf8, 0xb3, 0xca, 0x70, 0x35, 0xbf, 0x3b, 0x25, 0xbf, 0x2b, 0x52, 0xad, 0x0f, 0x54, 0x7c,
+	0x88, 0x16, 0x55, 0x92, 0xd1, 0xa3, 0x4a, 0x28, 0x74, 0xb1, 0x73, 0xd2, 0x1f, 0xe2, 0xc1, 0xac,
+	0xab, 0x76, 0xcf, 0x10, 0x10, 0x8b, 0x39, 0xc9, 0xc5, 0x63, 0x4e, 0xde, 0x81, 0xd5, 0xfb, 0x5e,
+	0x10, 0x7e, 0x43, 0x8f, 0x0b, 0xca, 0x6b, 0xe8, 0xf1, 0x71, 0x3b, 0x6c, 0xe7, 0x88, 0x4a, 0xfb,
+	0x27, 0x19, 0x58, 0xda, 0xa1, 0xfc, 0x2a, 0xd2, 0x64, 0x7b, 0xfd, 0x16, 0x9e, 0x92, 0x8e, 0xfb,
+	0x22, 0x52, 0x45, 0x66, 0x6b, 0xb1, 0xf1, 0x21, 0x4b, 0x22, 0x78, 0xa4, 0xd3, 0x76, 0xd5, 0x6d,
+	0x86, 0x2c, 0xe8, 0x76, 0x41, 0xce, 0xb4, 0x0b, 0x90, 0x63, 0x4e, 0xfb, 0xbe, 0xb2, 0x21, 0xc5,
+	0x6f, 0xe1, 0x8a, 0xc4, 0x83, 0x9e, 0xca, 0x85, 0xa6, 0xdf, 0xa4, 0x52, 0x7a, 0xa3, 0x6e, 0x83,
+	0xdc, 0x92, 0x3e, 0x07, 0x87, 0x96, 0x10, 0x40, 0x8e, 0x7c, 0x4a, 0xb3, 0x5d, 0xa6, 0x4a, 0x19,
+	0x2e, 0xd4, 0xa0, 0xc8, 0x93, 0x1e, 0x99, 0x3f, 0x53, 0xa2, 0xd9, 0x12, 0x56, 0x55, 0x44, 0xcd,
+	0x06, 0x57, 0xd8, 0xff, 0x99, 0x81, 0xb9, 0x70, 0xc7, 0x17, 0xd3, 0x79, 0x6e, 0x49, 0x95, 0x9c,
+	0x9c, 0xc6, 0x8f, 0xcc, 0xc8, 0x12, 0x99, 0xc4, 0x6c, 0xce, 0xe8, 0x39, 0x7b, 0xa8, 0xe8, 0x19,
+	0xca, 0xf9, 0x6b, 0x74, 0xbb, 0x85, 0x66, 0x1e, 0xbf, 0x8f, 0x52, 0x72, 0xb8, 0x14, 0x19, 0x92,
+	0x45, 0xdd, 0x90, 0x7c, 0x05, 0x65, 0x0d, 0x57, 0x43, 0xcc, 0x32, 0x34, 0x20, 0xc7, 0x16, 0xca,
+	0x11, 0x8d, 0xec, 0x43, 0x32, 0x7f, 0xbb, 0xb8, 0xea, 0xa8, 0x4e, 0xd8, 0xfc, 0x4d, 0x39, 0xd9,
+	0x29, 0x63, 0x36, 0x9b, 0x62, 0xcc, 0xe6, 0xf4, 0x03, 0xf7, 0x31, 0x2c, 0xcb, 0xd1, 0x36, 0x4e,
+	0xbd, 0xe6, 0x63, 0xdd, 0x0c, 0x54, 0xc3, 0x64, 0xcc, 0x61, 0x84, 0x09, 0xc6, 0x78, 0x28, 0xe7,
+	0x4d, 0x68, 0x82, 0x19, 0xf8, 0x39, 0x5a, 0x43, 0xbb, 0x0b, 0x73, 0xea, 0xbc, 0x2a, 0xbe, 0x94,
+	0x88, 0xbc, 0x08, 0x9e, 0xc2, 0xa5, 0x52, 0xa7, 0x52, 0x2e, 0x11, 0x36, 0xfe, 0xe3, 0xf6, 0x60,
+	0xc0, 0xd1, 0x49, 0x88, 0x0d, 0x17, 0x65, 0xd4, 0x38, 0xa9, 0x4a, 0x75, 0x84, 0x91, 0x25, 0xfb,
+	0xaf, 0x29, 0x11, 0xc0, 0x38, 0x1f, 0x3f, 0x3f, 0x06, 0x61, 0x14, 0x73, 0x06, 0x8a, 0xe2, 0x56,
+	0xab, 0x17, 0x29, 0xbd, 0x02, 0x96, 0xa4, 0x95, 0xd6, 0xa4, 0xe9, 0xfa, 0x6c, 0xdc, 0x2e, 0x9b,
+	0x47, 0x77, 0x49, 0x74, 0x6e, 0x62, 0xff, 0x15, 0x1e, 0x71, 0x50, 0xcc, 0xc5, 0xa2, 0x5f, 0x6e,
+	0x8f, 0xa7, 0x3f, 0x5e, 0xf8, 0xaa, 0x61, 0x25, 0xe7, 0xf4, 0x0f, 0x1b, 0x32, 0x63, 0xd8, 0xc8,
+	0xd8, 0xc9, 0x47, 0xac, 0x1a, 0x01, 0xa2, 0xa5, 0xf2, 0x70, 0x92, 0x1d, 0x0d, 0xd3, 0x3e, 0x97,
+	0x7d, 0xfb, 0xb7, 0x73, 0x30, 0x2d, 0x28, 0x38, 0xa9, 0x08, 0xa2, 0xe9, 0xd0, 0xf2, 0x9a, 0xed,
+	0xae, 0x74, 0xfc, 0x65, 0x5e, 0x2e, 0x38, 0x61, 0x39, 0x16, 0x58, 0x9d, 0xbb, 0x38, 0xb0, 0x3a,
+	0x1f, 0x0f, 0xac, 0xc6, 0xea, 0xd6, 0xc8, 0x0f, 0x1a, 0xd1, 0x03, 0x3f, 0x58, 0x4d, 0x90, 0x1d,
+	0x11, 0x80, 0x9e, 0x18, 0x42, 0x5b, 0x4c, 0x09, 0xa1, 0x7d, 0x91, 0x2f, 0x57, 0x51, 0x0f, 0xb5,
+	0x7b, 0x42, 0x3c, 0x4b, 0x8e, 0x06, 0x21, 0x5d, 0xde, 0x51, 0x62, 0x2a, 0xec, 0xd2, 0x92, 0x13,
+	0x01, 0xac, 0xaf, 0xc1, 0x4a, 0x58, 0x68, 0x68, 0x33, 0x92, 0xc6, 0xa9, 0x15, 0xd6, 0xed, 0x86,
+	0x53, 0x33, 0x7b, 0x44, 0x93, 0x84, 0x78, 0x8f, 0x70, 0xb6, 0xa1, 0x30, 0xcf, 0xe8, 0xc2, 0xfc,
+	0x16, 0xcc, 0x0b, 0x6a, 0xeb, 0x5b, 0x58, 0x51, 0x10, 0x3e, 0xb6, 0x43, 0x84, 0x6b, 0xe6, 0x70,
+	0xf5, 0x9d, 0x73, 0xf2, 0xba, 0x9b, 0xd7, 0x78, 0xb8, 0x58, 0xd7, 0xb6, 0xaa, 0x9b, 0x55, 0xa7,
+	0x52, 0xdf, 0xde, 0xdf, 0x6b, 0xd4, 0xea, 0x95, 0xfa, 0x61, 0xad, 0xb1, 0xb7, 0xbf, 0x57, 0x5d,
+	0xfc, 0x1c, 0xca, 0x81, 0xa5, 0xd5, 0x1d, 0x54, 0xf7, 0x36, 0xb7, 0xf7, 0xee, 0x2f, 0x66, 0x90,
+	0x2b, 0x57, 0x34, 0xf8, 0xc6, 0xfe, 0xee, 0xc1, 0x4e, 0xb5, 0x5e, 0xdd, 0x5c, 0xcc, 0x5a, 0xd7,
+	0x61, 0x59, 0xab, 0x71, 0xaa, 0xdf, 0xa9, 0x6e, 0x50, 0x45, 0xee, 0x4e, 0x15, 0x0a, 0x02, 0x1f,
+	0xdc, 0x96, 0xa1, 0x52, 0xab, 0x55, 0xeb, 0xea, 0x1b, 0x53, 0x90, 0x5b, 0xaf, 0x6f, 0xe0, 0xa0,
+	0xf4, 0x63, 0xe3, 0x01, 0x8e, 0x81, 0x3f, 0xaa, 0xf5, 0x07, 0x8b, 0x39, 0xfa, 0xb1, 0x83, 0x55,
+	0x79, 0xab, 0x04, 0xf9, 0xcd, 0x4a, 0xed, 0xc1, 0x62, 0xe1, 0xce, 0x1b, 0x50, 0x10, 0x92, 0x4a,
+	0xc3, 0xec, 0x56, 0x37, 0xb7, 0x2b, 0x6a, 0x18, 0x2c, 0xaf, 0xef, 0xec, 0x6f, 0x3c, 0xdc, 0x78,
+	0x50, 0xd9, 0xde, 0xc3, 0xd1, 0xe6, 0x60, 0x7a, 0x67, 0xfb, 0xfe, 0x83, 0xfa, 0x1e, 0x61, 0x9c,
+	0xbd, 0x73, 0x18, 0x3e, 0x47, 0xc1, 0xd3, 0x5e, 0x80, 0x19, 0x73, 0xae, 0x33, 0x30, 0xf5, 0x41,
+	0x65, 0xbb, 0x2e, 0x27, 0x88, 0x05, 0x35, 0xdb, 0x2c, 0x0d, 0x15, 0x4d, 0x31, 0x67, 0x01, 0x14,
+	0xb7, 0x2a, 0xdb, 0x3b, 0xf8, 0x3b, 0x7f, 0x67, 0x1d, 0x16, 0xe3, 0x67, 0x2b, 0xd4, 0x79, 0xf3,
+	0x9b, 0xdb, 0x0e, 0xce, 0x9b, 0x28, 0xc0, 0x83, 0xcf, 0x42, 0x69, 0x7b, 0x0f, 0x07, 0x91, 0xa3,
+	0x63, 0x69, 0xff, 0xb0, 0x7e, 0x7f, 0x5f, 0xa2, 0xd6, 0x86, 0x85, 0x98, 0xd1, 0x6c, 0x2d, 0x23,
+	0xe8, 0xb0, 0xe2, 0x54, 0xf6, 0x10, 0x9d, 0xaa, 0x1a, 0x03, 0x31, 0x8e, 0x80, 0x9b, 0x38, 0x0c,
+	0xd2, 0x5a, 0x6b, 0xe5, 0x54, 0x77, 0xaa, 0x95, 0x9a, 0x5a, 0x04, 0xa3, 0xa2, 0x7e, 0xe8, 0xec,
+	0x89, 0x45, 0x78, 0x27, 0xa2, 0x82, 0x3c, 0xcf, 0x11, 0x15, 0x3e, 0xaa, 0xd5, 0xab, 0xbb, 0x06,
+	0xa2, 0xf5, 0xaa, 0xb3, 0x57, 0xd9, 0x91, 0x88, 0x56, 0x3f, 0xe4, 0x52, 0xf6, 0xce, 0xeb, 0x30,
+	0xab, 0x07, 0xe9, 0x13, 0xc9, 0xab, 0x1f, 0x1e, 0xec, 0x3b, 0xf5, 0xc6, 0x46, 0xed, 0x11, 0xf6,
+	0x5d, 0x85, 0x25, 0x2e, 0x7f, 0xa7, 0x86, 0x53, 0xdf, 0xc1, 0x8f, 0xd7, 0x16, 0x33, 0x77, 0xbe,
+	0x0b, 0xf3, 0x66, 0xa2, 0x07, 0x4d, 0xaf, 0x46, 0xcd, 0x0e, 0x0f, 0x36, 0x2b, 0x48, 0xd3, 0x46,
+	0xa5, 0x2e, 0xa7, 0x27, 0x80, 0x95, 0xdd, 0xfd, 0xc3, 0xbd, 0x3a, 0x7e, 0x5c, 0x01, 0xe4, 0x32,
+	0xe1, 0xb4, 0x96, 0x70, 0x77, 0x11, 0x80, 0xea, 0xfb, 0x87, 0xd5, 0xbd, 0x8d, 0x2a, 0x4e, 0xe8,
+	0x00, 0x16, 0x62, 0x77, 0x3f, 0x44, 0xfe, 0xad, 0x2a, 0xcd, 0x5a, 0x60, 0xb2, 0x59, 0xf9, 0x08,
+	0xc7, 0xc6, 0x0f, 0x6a, 0xb0, 0x0f, 0xaa, 0xd5, 0x87, 0x38, 0xfe, 0x0a, 0x0a, 0x43, 0x04, 0xdc,
+	0xdd, 0xdf, 0x43, 0x9e, 0xcb, 0xde, 0x79, 0x1f, 0x66, 0x34, 0x93, 0x97, 0xe6, 0x58, 0xdb, 0xd8,
+	0x3f, 0x08, 0x17, 0x81, 0x70, 0x10, 0x65, 0x5c, 0xe0, 0xea, 0xf6, 0xa3, 0x2a, 0x8e, 0x13, 0x36,
+	0xa9, 0x21, 0xc7, 0x20, 0x9a, 0x84, 0xb7, 0x28, 0x57, 0x36, 0x71, 0xbd, 0x11, 0xc9, 0x0f, 0x43,
+	0x02, 0x70, 0xcc, 0x3f, 0xda, 0xb0, 0xb3, 0xc8, 0x0e, 0x3b, 0x87, 0x9b, 0xfa, 0xb8, 0x1b, 0xfb,
+	0x7b, 0x5b, 0xdb, 0xce, 0xae, 0x90, 0x1c, 0x9a, 0x2e, 0x32, 0xfd, 0x6e, 0x75, 0x77, 0x1f, 0x39,
+	0x6e, 0x1a, 0x0a, 0x5b, 0x3b, 0x95, 0xfb, 0x35, 0x94, 0x04, 0x5c, 0x91, 0x0f, 0x2a, 0x0e, 0x31,
+	0x75, 0x0d, 0xa5, 0xe1, 0x21, 0xcc, 0x19, 0x6f, 0x52, 0xd2, 0xca, 0x0b, 0xc4, 0x0e, 0xea, 0x31,
+	0x49, 0xc6, 0xc1, 0x0e, 0x2a, 0xdb, 0xc4, 0x35, 0xc8, 0xbe, 0x87, 0x7b, 0xe2, 0x77, 0x96, 0xd8,
+	0x1c, 0x57, 0x0c, 0x99, 0x95, 0x98, 0xe3, 0xdb, 0xb0, 0x18, 0x7f, 0x8e, 0x90, 0x14, 0x80, 0x1a,
+	0xaf, 0xfa, 0xa8, 0xba, 0x17, 0x0a, 0x2d, 0x12, 0x54, 0xc1, 0x79, 0x11, 0x71, 0xa1, 0x7f, 0x94,
+	0x09, 0xa5, 0x21, 0x1a, 0x81, 0x98, 0x44, 0xef, 0x89, 0x13, 0x95, 0xe5, 0x0d, 0xa7, 0x2a, 0xfb,
+	0xd1, 0x60, 0x12, 0xb4, 0xee, 0xec, 0x57, 0x36, 0x37, 0x2a, 0xb5, 0x3a, 0xa2, 0x86, 0xab, 0x23,
+	0x81, 0x48, 0x93, 0x1a, 0xad, 0x79, 0x15, 0x49, 0x19, 0x35, 0x65, 0x62, 0x91, 0x10, 0xea, 0x40,
+	0x25, 0xa5, 0x05, 0x22, 0x31, 0xf7, 0x97, 0xb2, 0x5a, 0x24, 0xa5, 0x25, 0x21, 0x0f, 0xf6, 0xf7,
+	0x1f, 0x36, 0x36, 0xab, 0x3b, 0xb8, 0x7c, 0x34, 0xf3, 0xa9, 0x7b, 0x3f, 0x5a, 0x45, 0xd3, 0xde,
+	0x3d, 0xaf, 0x79, 0x43, 0xdc, 0x76, 0xad, 0x07, 0xb8, 0x14, 0xfa, 0xd3, 0x86, 0x56, 0x39, 0xfd,
+	0xf5, 0xdb, 0xf2, 0xcd, 0xc4, 0x3a, 0xd6, 0xcb, 0xdf, 0x06, 0x88, 0x5e, 0x95, 0xb5, 0xd8, 0xf4,
+	0x1b, 0x7b, 0xb9, 0xb6, 0xbc, 0x36, 0x5e, 0xc1, 0x03, 0xec, 0xc1, 0x42, 0xec, 0x01, 0x2d, 0xeb,
+	0x05, 0xd9, 0x38, 0xf9, 0x5d, 0xad, 0xf2, 0xe7, 0x53, 0x6a, 0x79, 0xbc, 0x2a, 0xcc, 0xea, 0xef,
+	0x41, 0x5a, 0x5a, 0xb6, 0x4e, 0xec, 0x79, 0xcb, 0x72, 0x39, 0xa9, 0x2a, 0xbc, 0xa1, 0x99, 0xd1,
+	0xde, 0xd2, 0xb4, 0xd6, 0x8c, 0xd7, 0x51, 0xb4, 0xc7, 0x0a, 0xca, 0xe6, 0xab, 0x92, 0xd6, 0x16,
+	0x2c, 0x27, 0xbc, 0xf3, 0x69, 0xbd, 0xc4, 0xdb, 0x55, 0xea, 0x13, 0xa0, 0xf1, 0x71, 0xde, 0x88,
+	0x9e, 0x4b, 0x5c, 0x31, 0x93, 0x88, 0xb9, 0xfd, 0x6a, 0x0c, 0xca, 0x78, 0x3f, 0x0c, 0xdf, 0x6f,
+	0xe4, 0x87, 0xfa, 0xac, 0x9b, 0x46, 0x43, 0xf3, 0x41, 0xc3, 0xf2, 0x0b, 0xc9, 0x95, 0x3c, 0xd8,
+	0x03, 0x58, 0x8c, 0x3f, 0xd3, 0x67, 0x31, 0xf9, 0x53, 0x9e, 0xef, 0x2b, 0x2f, 0x1b, 0x03, 0xca,
+	0xe7, 0xf5, 0xbe, 0x96, 0xb1, 0xd6, 0x61, 0x46, 0x7b, 0x62, 0x4b, 0x91, 0x73, 0xfc, 0x99, 0xb2,
+	0xf2, 0x8d, 0x84, 0x1a, 0xc6, 0xe6, 0x5d, 0x98, 0xd5, 0x1f, 0xa1, 0x51, 0x2b, 0x9b, 0xf0, 0x30,
+	0x4d, 0xd9, 0xf4, 0x09, 0xc9, 0x37, 0x62, 0xaa, 0xdc, 0x5d, 0x51, 0x58, 0xef, 0x1e, 0x63, 0xb1,
+	0x72, 0x52, 0x55, 0xc4, 0x18, 0xda, 0xdb, 0x43, 0x6a, 0x26, 0xe3, 0x6f, 0x51, 0x95, 0x4d, 0xff,
+	0x29, 0x7d, 0x5e, 0x7f, 0xb3, 0x48, 0x7d, 0x3e, 0xe1, 0xcd, 0x24, 0xf5, 0xf9, 0xc4, 0x27, 0x8e,
+	0x1e, 0xc2, 0x6a, 0xe2, 0xb3, 0x2f, 0x96, 0x1d, 0x75, 0x4a, 0x7b, 0x13, 0xa6, 0x1c, 0x7b, 0x89,
+	0x83, 0xd4, 0x80, 0xf1, 0x8c, 0x87, 0xa5, 0x49, 0x44, 0xfc, 0x05, 0x11, 0xa5, 0x06, 0x92, 0xdf,
+	0xfd, 0x40, 0xaa, 0x68, 0x0f, 0x79, 0x28, 0xaa, 0x8c, 0xbf, 0xed, 0x11, 0xa7, 0xca, 0x9b, 0x28,
+	0xad, 0xda, 0x83, 0x1a, 0xa1, 0xb4, 0x8e, 0x3f, 0xb2, 0x11, 0xef, 0xf9, 0x36, 0xed, 0x0b, 0xda,
+	0x23, 0x19, 0x0a, 0xf7, 0xa4, 0x97, 0x33, 0x12, 0xfa, 0x1a, 0x6f, 0x5c, 0x84, 0xea, 0x2f, 0xe1,
+	0xe1, 0x8b, 0x78, 0xdf, 0x77, 0x63, 0x4f, 0x56, 0xdc, 0x30, 0xaa, 0xf5, 0xc7, 0x2f, 0x62, 0x5c,
+	0x28, 0x9b, 0x23, 0xc9, 0x8d, 0x77, 0x12, 0xd4, 0xa7, 0x93, 0x5e, 0x6a, 0x50, 0x24, 0x4f, 0x7e,
+	0x58, 0xe1, 0x6d, 0xa5, 0xc3, 0x55, 0x18, 0x92, 0xa1, 0xc3, 0xcd, 0x17, 0x12, 0xca, 0xe6, 0x1b,
+	0x00, 0x4a, 0x49, 0xaa, 0xc7, 0x03, 0x74, 0x25, 0x19, 0x7b, 0x92, 0x40, 0x57, 0x92, 0x63, 0x6f,
+	0x0d, 0xfc, 0x92, 0xcc, 0xc5, 0x8c, 0x67, 0xe6, 0x5b, 0x5f, 0x88, 0xfa, 0xa4, 0x3c, 0x2e, 0x50,
+	0xb6, 0x2f, 0x6a, 0xc2, 0xc3, 0xbf, 0x0f, 0x8b, 0xf1, 0x0c, 0x70, 0xa5, 0x7e, 0x52, 0x72, 0xf7,
+	0xcb, 0x2f, 0xa6, 0x55, 0xf3, 0x90, 0x75, 0x58, 0x1a, 0x4b, 0x85, 0xb6, 0x5e, 0x34, 0x53, 0x75,
+	0xe3, 0x99, 0xd8, 0xe5, 0x5b, 0xa9, 0xf5, 0xe6, 0x9e, 0x13, 0x97, 0xed, 0x84, 0x0c, 0x51, 0x9d,
+	0x9c, 0x63, 0xb2, 0xfd, 0x0e, 0xe5, 0xfc, 0xe3, 0xea, 0x75, 0x27, 0x19, 0xc8, 0x64, 0x4b, 0xa1,
+	0x62, 0xe7, 0xcd, 0xfc, 0x55, 0xa5, 0xf9, 0x13, 0xb3, 0x5a, 0xcb, 0x4b, 0x7a, 0xa5, 0x48, 0x3d,
+	0xc5, 0x31, 0x36, 0x61, 0x69, 0x2c, 0xcf, 0x54, 0x91, 0x27, 0x2d, 0x01, 0x75, 0x1c, 0x93, 0x6d,
+	0x6d, 0x94, 0x70, 0x1f, 0x8e, 0x8f, 0x12, 0xdf, 0x8c, 0xad, 0xf1, 0xd7, 0xa2, 0x71, 0xa8, 0xb7,
+	0x01, 0xa2, 0x24, 0x42, 0x4b, 0xa5, 0xd1, 0x6a, 0xff, 0x47, 0x41, 0x59, 0x16, 0x09, 0xa9, 0x86,
+	0x1f, 0xc8, 0xac, 0x0c, 0x33, 0x7d, 0xca, 0xba, 0x15, 0xb5, 0x4f, 0x4c, 0xd7, 0x2a, 0xbf, 0x94,
+	0xde, 0x20, 0x32, 0x59, 0x62, 0xe9, 0x3f, 0xca, 0x64, 0x49, 0xce, 0x22, 0x52, 0x26, 0x4b, 0x5a,
+	0xce, 0xd0, 0x7b, 0x30, 0x67, 0x38, 0x45, 0x13, 0xe7, 0xc9, 0x8b, 0x99, 0xec, 0x3d, 0x7d, 0x0d,
+	0xa6, 0xd8, 0xdf, 0x92, 0xd8, 0x77, 0x35, 0xec, 0x6b, 0xb8, 0x64, 0xde, 0x81, 0x19, 0xcd, 0x65,
+	0x96, 0xd8, 0x93, 0x19, 0x30, 0xc9, 0xb3, 0x76, 0x0f, 0x8a, 0xf2, 0x8c, 0x9e, 0xd8, 0x71, 0x45,
+	0x3b, 0x9f, 0x47, 0x78, 0x7e, 0x1d, 0x66, 0x10, 0x89, 0x30, 0xdf, 0x35, 0xa9, 0x23, 0xef, 0x51,
+	0xaa, 0xcd, 0xbd, 0xbf, 0x5f, 0xc1, 0x53, 0x75, 0xab, 0xdb, 0xee, 0x59, 0x5f, 0x81, 0x52, 0xcd,
+	0x93, 0x8b, 0x6c, 0xe9, 0x69, 0xa3, 0xca, 0xe6, 0x30, 0xfe, 0x9d, 0x06, 0x4d, 0x4e, 0x4b, 0xc1,
+	0x8d, 0x0c, 0xb8, 0x78, 0x56, 0x6e, 0x72, 0xef, 0x7b, 0xb4, 0xcb, 0x47, 0x88, 0xc6, 0x90, 0x4a,
+	0xee, 0x83, 0x02, 0x68, 0x66, 0xc8, 0x5a, 0x37, 0xf5, 0x8f, 0xc6, 0xf2, 0x66, 0x93, 0xc7, 0x78,
+	0x1b, 0x16, 0x90, 0xdf, 0x8c, 0xdc, 0xd7, 0x84, 0xa4, 0xbe, 0xe4, 0xbe, 0xa8, 0x8d, 0x93, 0x92,
+	0x29, 0x95, 0x36, 0xbe, 0x20, 0x6f, 0xb5, 0x3c, 0x41, 0xee, 0xa6, 0xf5, 0x1d, 0x95, 0xd3, 0x6c,
+	0x60, 0x77, 0x4b, 0x9f, 0x62, 0x42, 0x5e, 0x65, 0x2a, 0xaa, 0x49, 0x49, 0x68, 0x0a, 0xd5, 0x0b,
+	0xf2, 0xe2, 0x14, 0xaa, 0x17, 0xe6, 0xb0, 0xbd, 0x8d, 0xa7, 0xf4, 0xa7, 0xb1, 0xc4, 0xd6, 0x44,
+	0x66, 0x53, 0x17, 0x40, 0x5a, 0xb3, 0xfb, 0xb0, 0x34, 0x96, 0x14, 0x6b, 0x8d, 0xb7, 0x53, 0x9b,
+	0x42, 0x7a, 0x02, 0x2d, 0x32, 0xa0, 0x96, 0x30, 0x17, 0x1a, 0x8a, 0x63, 0x39, 0x74, 0xc9, 0x14,
+	0x72, 0x60, 0x25, 0x29, 0x3f, 0x4e, 0x51, 0xe8, 0x82, 0xdc, 0xb9, 0x72, 0x5a, 0x00, 0x07, 0x19,
+	0xe1, 0x5a, 0x0a, 0x97, 0xa5, 0x69, 0xce, 0x18, 0x46, 0x37, 0x12, 0x6a, 0x18, 0xaf, 0x2d, 0x23,
+	0x9b, 0x44, 0xa6, 0xb7, 0x28, 0xdd, 0x9e, 0x96, 0xf7, 0xa2, 0xc8, 0xac, 0xa7, 0x8a, 0xe0, 0x96,
+	0xa9, 0x67, 0x67, 0xa9, 0x9d, 0x2e, 0x21, 0x0d, 0x4c, 0x6d, 0x99, 0x89, 0xc9, 0x5c, 0x38, 0x25,
+	0x2d, 0x65, 0x29, 0x24, 0xf2, 0x58, 0x56, 0x95, 0x9a, 0x52, 0x52, 0x7e, 0x13, 0x9a, 0x64, 0x46,
+	0xe2, 0x8f, 0x32, 0xa4, 0x92, 0xb2, 0x97, 0x94, 0x1a, 0x4e, 0xce, 0x14, 0xba, 0x0f, 0xf3, 0x66,
+	0x62, 0x87, 0x15, 0xb3, 0xe0, 0x8c, 0x74, 0x8f, 0xf2, 0x58, 0x9c, 0x7c, 0x18, 0xb4, 0x5e, 0x97,
+	0x09, 0xa6, 0x09, 0x71, 0xf7, 0x89, 0x6c, 0x7c, 0x3b, 0x5a, 0xaf, 0x8b, 0x42, 0xf5, 0x1f, 0xe2,
+	0x71, 0x2e, 0x16, 0x72, 0x1f, 0x1e, 0xe7, 0x92, 0x43, 0xf1, 0xcb, 0xa9, 0xa1, 0xfc, 0xb8, 0xe5,
+	0x40, 0x14, 0x53, 0xad, 0x0e, 0xfe, 0x63, 0x51, 0xd6, 0x71, 0xeb, 0xf9, 0x1d, 0x11, 0xca, 0x2b,
+	0x9d, 0x59, 0xd6, 0xb5, 0x58, 0x64, 0x73, 0x8c, 0x81, 0xc7, 0xc3, 0x72, 0xb7, 0xc8, 0xf9, 0x62,
+	0xc6, 0xd0, 0xaa, 0x09, 0xa4, 0xc4, 0xd6, 0x26, 0x0b, 0xd7, 0x03, 0x58, 0x1a, 0x8b, 0x9a, 0x55,
+	0x4c, 0x9c, 0x16, 0x4e, 0x9b, 0x3c, 0xd2, 0x9e, 0xb4, 0x80, 0xe3, 0x51, 0xad, 0x89, 0xab, 0xa4,
+	0x99, 0xbc, 0xa9, 0x51, 0xb0, 0x88, 0xd9, 0x58, 0xd4, 0x68, 0xe2, 0x60, 0xb7, 0x92, 0xa3, 0x45,
+	0x75, 0x63, 0x92, 0xe6, 0xe8, 0x05, 0x74, 0x20, 0xf4, 0xd7, 0xd1, 0xaa, 0x7c, 0x8c, 0x7c, 0x95,
+	0x34, 0x52, 0xca, 0x5e, 0x36, 0xa3, 0x45, 0x70, 0x86, 0xbb, 0xe7, 0x58, 0x08, 0xa8, 0x92, 0xab,
+	0xa4, 0x70, 0xcf, 0x37, 0xe9, 0xe6, 0x4a, 0x0f, 0xa3, 0xb4, 0xc6, 0xc3, 0x25, 0x93, 0xbf, 0x8e,
+	0xeb, 0x1c, 0x8f, 0xc0, 0x4c, 0x44, 0xfd, 0x45, 0x9d, 0xef, 0x13, 0xa2, 0x35, 0xdf, 0x82, 0x92,
+	0x8a, 0x0b, 0xb3, 0xd8, 0x06, 0x8a, 0x05, 0xfa, 0x95, 0xaf, 0xc5, 0xc1, 0x21, 0x01, 0x96, 0xc6,
+	0xc2, 0x19, 0x15, 0x8b, 0xa4, 0xc5, 0x39, 0xc6, 0x99, 0x1d, 0xc7, 0x18, 0x8b, 0x01, 0x55, 0x63,
+	0xa4, 0x05, 0x87, 0x8e, 0x0b, 0xcc, 0xbc, 0x19, 0xf4, 0x19, 0x19, 0x15, 0x09, 0xa1, 0xa0, 0x89,
+	0x87, 0x64, 0x2d, 0xf4, 0x33, 0x3a, 0x24, 0x8f, 0xc7, 0x83, 0x26, 0x38, 0x2c, 0xf4, 0x18, 0x06,
+	0xa5, 0xa1, 0x13, 0xa2, 0x38, 0xca, 0xe5, 0xa4, 0x2a, 0x26, 0xe4, 0xb7, 0xe8, 0x79, 0xe4, 0x28,
+	0x72, 0x41, 0x0d, 0x93, 0x10, 0xcd, 0x90, 0x6a, 0xc7, 0x69, 0x21, 0x0d, 0x17, 0x19, 0xa9, 0x09,
+	0x91, 0x0f, 0xf7, 0x7e, 0x59, 0xd8, 0x53, 0xb4, 0x65, 0xf0, 0x7f, 0x3b, 0x1b, 0x92, 0x87, 0xcc,
+	0xfc, 0xcf, 0x67, 0x8a, 0xa2, 0x89, 0xff, 0x7d, 0x4d, 0x79, 0xc8, 0x92, 0xff, 0x59, 0xda, 0xbd,
+	0xff, 0xce, 0x00, 0x68, 0xda, 0x74, 0x1b, 0x16, 0x62, 0xa9, 0x3b, 0xd6, 0x75, 0x43, 0x83, 0x46,
+	0x89, 0x49, 0xea, 0x50, 0x90, 0x96, 0xea, 0xb3, 0x41, 0xf2, 0x2b, 0xaa, 0xb4, 0x9c, 0x9d, 0x1b,
+	0xb1, 0xc1, 0xa2, 0xaa, 0x64, 0xe2, 0xe1, 0x71, 0x77, 0x2c, 0x5f, 0x26, 0x3c, 0x89, 0xa5, 0xe4,
+	0xe1, 0x28, 0xd5, 0x92, 0x9a, 0x68, 0x73, 0x54, 0x14, 0xff, 0xd7, 0xee, 0xd5, 0xff, 0x07, 0x2e,
+	0x4f, 0x04, 0x89, 0xe4, 0x6e, 0x00, 0x00,
 }
@@ -0,0 +1,525 @@
+package crpc
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+// selfTestMetric is the name under which failed check of the self-test is
+// reported to the metrics backend.
+const selfTestMetric = "SelfTest"
+
+const (
+	// checkAddressVectors validates the known addresses of the network, and
+	// compares their canonical form with the expected one.
+	checkAddressVectors = "address_vectors"
+
+	// checkCraftTransaction builds the transaction without broadcasting
+	// it, so that coin selection and fee estimation are checked.
+	checkCraftTransaction = "craft_transaction"
+
+	// checkDecodeInvoice creates the sample invoice and decodes it back.
+	checkDecodeInvoice = "decode_invoice"
+
+	// checkCanaryPayment sends the canary payment to the controlled
+	// address.
+	checkCanaryPayment = "canary_payment"
+)
+
+// sampleInvoiceAmount is the amount of the sample invoice created by the
+// self-test, which is never paid.
+var sampleInvoiceAmount = decimal.New(1, -8)
+
+// addressVector is the known address along with its canonical form, empty
+// canonical form denotes that address should be rejected.
+type addressVector struct {
+	address   string
+	canonical string
+}
+
+// ethAddressVectors are the known addresses of ethereum, which are the same
+// in every network.
+var ethAddressVectors = []addressVector{
+	{
+		"0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae",
+		"0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe",
+	},
+	{"0xdg0B295669a9FD93d5F28D9Ec85E40f4cb697BAe", ""},
+}
+
+// addressVectors are the known addresses of the assets in the networks
+// taken from the tests of the address validation, so that upgrade which
+// breaks decoding or normalization of the addresses is detected before
+// deposits are lost. Simnet addresses depend on the setup of the daemons,
+// so they are not checked.
+var addressVectors = map[connectors.Asset]map[string][]addressVector{
+	connectors.BTC: {
+		"mainnet": {
+			{
+				"1PFMrJdc6K61x945CwA7BAYvtVkNoaPcYx",
+				"1PFMrJdc6K61x945CwA7BAYvtVkNoaPcYx",
+			},
+			{
+				"bc1qn6f5cd9rpxtgavsxyk7lgyvgn75mj8tcnxexy2",
+				"bc1qn6f5cd9rpxtgavsxyk7lgyvgn75mj8tcnxexy2",
+			},
+			{"n3mK9MiauLXGjFXgvW8V15mFkVM5hMXy5V", ""},
+		},
+		"testnet": {
+			{
+				"mwjKXu5HKes1Pq8avb8faJWMoSpD3TmeCP",
+				"mwjKXu5HKes1Pq8avb8faJWMoSpD3TmeCP",
+			},
+			{
+				"tb1qn6f5cd9rpxtgavsxyk7lgyvgn75mj8tceqz4le",
+				"tb1qn6f5cd9rpxtgavsxyk7lgyvgn75mj8tceqz4le",
+			},
+			{"bc1qn6f5cd9rpxtgavsxyk7lgyvgn75mj8tcnxexy2", ""},
+		},
+	},
+	connectors.BCH: {
+		"mainnet": {
+			{
+				"1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+				"bitcoincash:qpm47l0kukuzjnk2vsp70256s9pd99qs5u2e7gd5f7",
+			},
+			{
+				"bitcoincash:qpm47l0kukuzjnk2vsp70256s9pd99qs5u2e7gd5f7",
+				"bitcoincash:qpm47l0kukuzjnk2vsp70256s9pd99qs5u2e7gd5f7",
+			},
+			{"mycY7kfzccdaaSvH3gHwoqPkxhQPXVzSwz", ""},
+		},
+		"testnet": {
+			{
+				"bchtest:qpm47l0kukuzjnk2vsp70256s9pd99qs5uwt600rwz",
+				"bchtest:qpm47l0kukuzjnk2vsp70256s9pd99qs5uwt600rwz",
+			},
+			{"yYQRhUDfzXn4b6acrnenZdDGRTpDUTqmQs", ""},
+		},
+	},
+	connectors.LTC: {
+		"mainnet": {
+			{
+				"LQasau6s59W354CqQk7Hmks5E7XagUMwxj",
+				"LQasau6s59W354CqQk7Hmks5E7XagUMwxj",
+			},
+			{
+				"ltc1qupndfjxttgfdtq3k4wzuvyegcdz8uun0t09j0n",
+				"ltc1qupndfjxttgfdtq3k4wzuvyegcdz8uun0t09j0n",
+			},
+			{"1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e", ""},
+		},
+		"testnet": {
+			{
+				"mnf5Etv6JePkDPXJtNZgZZ45dQSrieJfLk",
+				"mnf5Etv6JePkDPXJtNZgZZ45dQSrieJfLk",
+			},
+			{
+				"tltc1ql00u9jm8qwzhv4e53hthz34t5744wh4p5rksqg",
+				"tltc1ql00u9jm8qwzhv4e53hthz34t5744wh4p5rksqg",
+			},
+			{"LQasau6s59W354CqQk7Hmks5E7XagUMwxj", ""},
+		},
+	},
+	connectors.DASH: {
+		"mainnet": {
+			{
+				"XwXafPNkhTBQiRFsu8qZiLNEmsWi9nbTfw",
+				"XwXafPNkhTBQiRFsu8qZiLNEmsWi9nbTfw",
+			},
+			{"yhABgLTC8zqV4ABRTz9xkMnb4A15b8zc57", ""},
+		},
+		"testnet": {
+			{
+				"yhABgLTC8zqV4ABRTz9xkMnb4A15b8zc57",
+				"yhABgLTC8zqV4ABRTz9xkMnb4A15b8zc57",
+			},
+			{"XwXafPNkhTBQiRFsu8qZiLNEmsWi9nbTfw", ""},
+		},
+	},
+	connectors.ETH: {
+		"mainnet": ethAddressVectors,
+		"testnet": ethAddressVectors,
+		"simnet":  ethAddressVectors,
+	},
+}
+
+// SelfTestCanary is the tiny payment which is sent by the self-test to the
+// controlled address, so that the whole path of the outgoing payment is
+// checked.
+type SelfTestCanary struct {
+	// Address is the address of the controlled wallet.
+	Address string
+
+	// Amount is the amount of the canary payment.
+	Amount decimal.Decimal
+}
+
+// ParseSelfTestCanary parses the canary payment of the asset from the
+// config in form of "asset=address:amount", e.g. "BTC=bc1q...:0.0001".
+// Amount is separated by the last colon, because address might contain
+// the colon, e.g. prefix of the bitcoin cash address.
+func ParseSelfTestCanary(s string) (connectors.Asset, *SelfTestCanary,
+	error) {
+
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return "", nil, errors.Errorf("self-test canary should be in "+
+			"form of asset=address:amount, got(%v)", s)
+	}
+
+	asset := strings.ToUpper(parts[0])
+	if _, ok := Asset_value[asset]; !ok || asset == Asset_ASSET_NONE.String() {
+		return "", nil, errors.Errorf("unknown asset of the self-test "+
+			"canary, got(%v)", s)
+	}
+
+	i := strings.LastIndex(parts[1], ":")
+	if i <= 0 {
+		return "", nil, errors.Errorf("self-test canary should be in "+
+			"form of asset=address:amount, got(%v)", s)
+	}
+
+	amount, err := decimal.NewFromString(parts[1][i+1:])
+	if err != nil || amount.Sign() <= 0 {
+		return "", nil, errors.Errorf("amount of the self-test canary "+
+			"should be positive number, got(%v)", s)
+	}
+
+	return connectors.Asset(asset), &SelfTestCanary{
+		Address: parts[1][:i],
+		Amount:  amount,
+	}, nil
+}
+
+// selfTests keeps the canary payments and the results of the last
+// self-test, which are reported by GetInfo.
+type selfTests struct {
+	canaries map[connectors.Asset]*SelfTestCanary
+
+	// runMtx serializes the runs, so that concurrent runs don't send the
+	// canary payments twice.
+	runMtx sync.Mutex
+
+	mtx     sync.RWMutex
+	results []*SelfTestResult
+}
+
+func newSelfTests(canaries map[connectors.Asset]*SelfTestCanary) *selfTests {
+	if canaries == nil {
+		canaries = make(map[connectors.Asset]*SelfTestCanary)
+	}
+
+	return &selfTests{
+		canaries: canaries,
+	}
+}
+
+// last returns the results of the last self-test, nil if it hasn't been
+// run.
+func (t *selfTests) last() []*SelfTestResult {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return t.results
+}
+
+// passedCheck returns the passed check with the outcome.
+func passedCheck(name, format string, args ...interface{}) *SelfTestCheck {
+	return &SelfTestCheck{
+		Name:   name,
+		Passed: true,
+		Detail: fmt.Sprintf(format, args...),
+	}
+}
+
+// failedCheck returns the failed check with the error.
+func failedCheck(name, format string, args ...interface{}) *SelfTestCheck {
+	return &SelfTestCheck{
+		Name:   name,
+		Detail: fmt.Sprintf(format, args...),
+	}
+}
+
+// skippedCheck returns the skipped check with the reason of the skip.
+func skippedCheck(name, format string, args ...interface{}) *SelfTestCheck {
+	return &SelfTestCheck{
+		Name:    name,
+		Passed:  true,
+		Skipped: true,
+		Detail:  fmt.Sprintf(format, args...),
+	}
+}
+
+// checkAddresses validates the known addresses of the asset in the network
+// of the server.
+func (s *Server) checkAddresses(ctx context.Context, asset connectors.Asset,
+	c connectors.BlockchainConnector) *SelfTestCheck {
+
+	vectors, ok := addressVectors[asset][s.net]
+	if !ok {
+		return skippedCheck(checkAddressVectors, "no address vectors for "+
+			"network(%v)", s.net)
+	}
+
+	for _, vector := range vectors {
+		stop := trackStage(ctx, stageNode)
+		info, err := c.ValidateAddress(vector.address)
+		stop()
+
+		if vector.canonical == "" {
+			if err == nil {
+				return failedCheck(checkAddressVectors, "invalid "+
+					"address(%v) is accepted", vector.address)
+			}
+			continue
+		}
+
+		if err != nil {
+			return failedCheck(checkAddressVectors, "valid address(%v) "+
+				"is rejected: %v", vector.address, err)
+		}
+
+		if info.Address != vector.canonical {
+			return failedCheck(checkAddressVectors, "address(%v) is "+
+				"normalized to(%v), expected(%v)", vector.address,
+				info.Address, vector.canonical)
+		}
+	}
+
+	return passedCheck(checkAddressVectors, "%v addresses are checked",
+		len(vectors))
+}
+
+// craftTransaction builds the transaction of the canary amount, or the
+// minimum amount of the asset if canary isn't configured, without
+// broadcasting it. Transaction is sent to the canary address, or to the new
+// deposit address if canary isn't configured.
+func (s *Server) craftTransaction(ctx context.Context, asset connectors.Asset,
+	c connectors.BlockchainConnector) *SelfTestCheck {
+
+	var (
+		address string
+		amount  decimal.Decimal
+	)
+
+	if canary, ok := s.selfTests.canaries[asset]; ok {
+		address = canary.Address
+		amount = canary.Amount
+	} else {
+		reporter, ok := c.(connectors.AssetReporter)
+		if !ok || reporter.AssetParams().MinAmount.Sign() <= 0 {
+			return skippedCheck(checkCraftTransaction, "amount of the "+
+				"transaction is unknown, canary isn't configured")
+		}
+		amount = reporter.AssetParams().MinAmount
+
+		stop := trackStage(ctx, stageNode)
+		var err error
+		address, err = c.CreateAddress()
+		stop()
+		if err != nil {
+			return failedCheck(checkCraftTransaction, "unable to create "+
+				"address: %v", err)
+		}
+	}
+
+	protoAsset, _ := convertAssetToProto(asset)
+	payment, err := s.simulatePayment(ctx, &SendPaymentRequest{
+		Asset:   protoAsset,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: address,
+		Amount:  amount.String(),
+	})
+	if err != nil {
+		return failedCheck(checkCraftTransaction, "unable to craft "+
+			"transaction of amount(%v) to address(%v): %v", amount,
+			address, err)
+	}
+
+	return passedCheck(checkCraftTransaction, "transaction of amount(%v) "+
+		"to address(%v) with fee(%v)", payment.Amount, payment.Receipt,
+		payment.MediaFee)
+}
+
+// sendCanary sends the canary payment of the asset. Payment goes through
+// the same checks as the payment requested by the client, so that it isn't
+// sent while withdrawals are paused.
+func (s *Server) sendCanary(ctx context.Context, asset connectors.Asset,
+	send bool) *SelfTestCheck {
+
+	canary, ok := s.selfTests.canaries[asset]
+	if !ok {
+		return skippedCheck(checkCanaryPayment, "canary isn't configured")
+	}
+
+	if !send {
+		return skippedCheck(checkCanaryPayment, "canary isn't requested")
+	}
+
+	protoAsset, _ := convertAssetToProto(asset)
+	payment, err := s.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   protoAsset,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: canary.Address,
+		Amount:  canary.Amount.String(),
+	})
+	if err != nil {
+		return failedCheck(checkCanaryPayment, "unable to send canary "+
+			"payment: %v", err)
+	}
+
+	return passedCheck(checkCanaryPayment, "payment(%v)", payment.PaymentId)
+}
+
+// decodeInvoice creates the sample invoice, which is never paid, and
+// decodes it back, so that encoding of the invoices in the network of the
+// node is checked.
+func (s *Server) decodeInvoice(ctx context.Context,
+	c connectors.LightningConnector) *SelfTestCheck {
+
+	amount := sampleInvoiceAmount.String()
+
+	stop := trackStage(ctx, stageNode)
+	invoice, created, err := c.CreateInvoice("zigzag", amount, "self-test")
+	stop()
+	if err != nil {
+		return failedCheck(checkDecodeInvoice, "unable to create "+
+			"invoice: %v", err)
+	}
+
+	stop = trackStage(ctx, stageNode)
+	decoded, err := c.ValidateInvoice(invoice, amount)
+	stop()
+	if err != nil {
+		return failedCheck(checkDecodeInvoice, "unable to decode "+
+			"invoice: %v", err)
+	}
+
+	if created != nil && created.PaymentHash != nil &&
+		(decoded.PaymentHash == nil ||
+			!bytes.Equal(decoded.PaymentHash[:], created.PaymentHash[:])) {
+		return failedCheck(checkDecodeInvoice, "payment hash of the "+
+			"decoded invoice doesn't match")
+	}
+
+	return passedCheck(checkDecodeInvoice, "invoice of amount(%v)", amount)
+}
+
+// runSelfTest runs the self-test of every enabled asset and media, and
+// saves the results, so that they are reported by GetInfo. Canary payments
+// are sent only if requested.
+func (s *Server) runSelfTest(ctx context.Context,
+	canary bool) []*SelfTestResult {
+
+	s.selfTests.runMtx.Lock()
+	defer s.selfTests.runMtx.Unlock()
+
+	var results []*SelfTestResult
+	newResult := func(asset connectors.Asset, media Media,
+		checks ...*SelfTestCheck) {
+
+		protoAsset, _ := convertAssetToProto(asset)
+		result := &SelfTestResult{
+			Asset:  protoAsset,
+			Media:  media,
+			Passed: true,
+			RanAt:  connectors.NowInMilliSeconds(),
+			Checks: checks,
+		}
+
+		for _, check := range checks {
+			if check.Passed {
+				continue
+			}
+
+			result.Passed = false
+			log.Errorf("Self-test of asset(%v) media(%v) has failed "+
+				"check(%v): %v", asset, media, check.Name, check.Detail)
+			s.metrics.AddError(selfTestMetric, string(metrics.MiddleSeverity))
+		}
+
+		results = append(results, result)
+	}
+
+	for asset, c := range s.blockchainConnectors {
+		newResult(asset, Media_BLOCKCHAIN,
+			s.checkAddresses(ctx, asset, c),
+			s.craftTransaction(ctx, asset, c),
+			s.sendCanary(ctx, asset, canary),
+		)
+	}
+
+	for asset, c := range s.lightningConnectors {
+		newResult(asset, Media_LIGHTNING, s.decodeInvoice(ctx, c))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Media != results[j].Media {
+			return results[i].Media < results[j].Media
+		}
+		return results[i].Asset < results[j].Asset
+	})
+
+	s.selfTests.mtx.Lock()
+	s.selfTests.results = results
+	s.selfTests.mtx.Unlock()
+
+	return results
+}
+
+// SelfTest runs the self-test of every enabled asset and media on the
+// start of the service, canary payments are sent if they are configured.
+// Results are reported by GetInfo.
+func (s *Server) SelfTest() {
+	results := s.runSelfTest(context.Background(), true)
+
+	var failed int
+	for _, result := range results {
+		if !result.Passed {
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		log.Errorf("Self-test has failed for %v of %v assets and media",
+			failed, len(results))
+		return
+	}
+
+	log.Infof("Self-test has passed for %v assets and media", len(results))
+}
+
+//
+// RunSelfTest runs the self-test of every enabled asset and media:
+// validation of the known address vectors, crafting of the transaction
+// which isn't broadcasted, decoding of the sample invoice, and optionally
+// sending of the canary payments. Results are returned and reported by
+// GetInfo until the next run.
+func (s *Server) RunSelfTest(ctx context.Context,
+	req *RunSelfTestRequest) (*RunSelfTestResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp := &RunSelfTestResponse{
+		Results: s.runSelfTest(ctx, req.Canary),
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
@@ -0,0 +1,132 @@
+package crpc
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+func TestParseSelfTestCanary(t *testing.T) {
+	asset, canary, err := ParseSelfTestCanary(
+		"bch=bitcoincash:qpm47l0kukuzjnk2vsp70256s9pd99qs5u2e7gd5f7:0.0001")
+	if err != nil {
+		t.Fatalf("unable to parse canary: %v", err)
+	}
+
+	if asset != connectors.BCH ||
+		canary.Address != "bitcoincash:qpm47l0kukuzjnk2vsp70256s9pd99qs5u2e7gd5f7" ||
+		!canary.Amount.Equal(decimal.New(1, -4)) {
+		t.Fatalf("wrong canary: %v %v", asset, canary)
+	}
+
+	for _, value := range []string{"BTC", "XYZ=address:1", "BTC=address",
+		"BTC=:1", "BTC=address:0", "BTC=address:abc"} {
+		if _, _, err := ParseSelfTestCanary(value); err == nil {
+			t.Fatalf("invalid canary(%v) is parsed", value)
+		}
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	h.server.selfTests.canaries[connectors.BTC] = &SelfTestCanary{
+		Address: "canary-address",
+		Amount:  decimal.New(1, -3),
+	}
+
+	info, err := h.client.GetInfo(ctx, &EmptyRequest{})
+	if err != nil {
+		t.Fatalf("unable to get info: %v", err)
+	}
+
+	if len(info.SelfTests) != 0 {
+		t.Fatalf("self-test hasn't been run: %v", info.SelfTests)
+	}
+
+	run := func(canary bool) *SelfTestResult {
+		resp, err := h.admin.RunSelfTest(ctx, &RunSelfTestRequest{
+			Canary: canary,
+		})
+		if err != nil {
+			t.Fatalf("unable to run self-test: %v", err)
+		}
+
+		if len(resp.Results) != 1 || resp.Results[0].Asset != Asset_BTC ||
+			resp.Results[0].Media != Media_BLOCKCHAIN {
+			t.Fatalf("wrong results: %v", resp.Results)
+		}
+
+		return resp.Results[0]
+	}
+
+	check := func(result *SelfTestResult, name string) *SelfTestCheck {
+		for _, check := range result.Checks {
+			if check.Name == name {
+				return check
+			}
+		}
+
+		t.Fatalf("check(%v) is missing: %v", name, result.Checks)
+		return nil
+	}
+
+	// Addresses of the simnet are not known, and canary isn't sent unless
+	// requested.
+	result := run(false)
+	if !result.Passed || !check(result, checkAddressVectors).Skipped ||
+		!check(result, checkCanaryPayment).Skipped {
+		t.Fatalf("wrong result: %v", result)
+	}
+
+	crafted := check(result, checkCraftTransaction)
+	if !crafted.Passed || crafted.Skipped {
+		t.Fatalf("transaction should be crafted: %v", crafted)
+	}
+
+	balance, _ := h.btc.ConfirmedBalance()
+	if !balance.Equal(decimal.New(10, 0)) {
+		t.Fatalf("crafted transaction shouldn't be sent: %v", balance)
+	}
+
+	result = run(true)
+	canary := check(result, checkCanaryPayment)
+	if !result.Passed || !canary.Passed || canary.Skipped {
+		t.Fatalf("canary should be sent: %v", result)
+	}
+
+	payments, err := h.payments.ListPayments("", "", "", "", "")
+	if err != nil {
+		t.Fatalf("unable to list payments: %v", err)
+	}
+
+	if len(payments) != 1 || payments[0].Receipt != "canary-address" ||
+		!payments[0].Amount.Equal(decimal.New(1, -3)) {
+		t.Fatalf("wrong canary payment: %v", payments)
+	}
+
+	info, err = h.client.GetInfo(ctx, &EmptyRequest{})
+	if err != nil {
+		t.Fatalf("unable to get info: %v", err)
+	}
+
+	if len(info.SelfTests) != 1 ||
+		check(info.SelfTests[0], checkCanaryPayment).Skipped {
+		t.Fatalf("results of the last self-test should be reported: %v",
+			info.SelfTests)
+	}
+
+	// Mock connector accepts every address, so that known invalid address
+	// of the mainnet fails the check.
+	h.server.net = "mainnet"
+
+	result = run(false)
+	if result.Passed || check(result, checkAddressVectors).Passed {
+		t.Fatalf("address vectors should fail: %v", result)
+	}
+}
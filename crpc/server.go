@@ -1,42 +1,169 @@
 package crpc
 
 import (
+	crand "crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"github.com/bitlum/connector/common"
 	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/features"
+	"github.com/bitlum/connector/identity"
+	"github.com/bitlum/connector/locale"
 	"github.com/bitlum/connector/metrics"
 	"github.com/bitlum/connector/metrics/rpc"
 	"github.com/go-errors/errors"
 	"github.com/shopspring/decimal"
 	"golang.org/x/net/context"
+	"math"
 	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 )
 
 // Server is the gRPC server which implements PayServer interface.
 type Server struct {
-	net                  string
-	blockchainConnectors map[connectors.Asset]connectors.BlockchainConnector
-	lightningConnectors  map[connectors.Asset]connectors.LightningConnector
-	paymentsStore        connectors.PaymentsStore
-	metrics              rpc.MetricsBackend
+	net                   string
+	blockchainConnectors  map[connectors.Asset]connectors.BlockchainConnector
+	lightningConnectors   map[connectors.Asset]connectors.LightningConnector
+	paymentsStore         connectors.PaymentsStore
+	payeesStore           connectors.PayeesStore
+	watchStore            connectors.WatchStore
+	apiKeysStore          connectors.APIKeysStore
+	timeLocksStore        connectors.TimeLocksStore
+	receiptsStore         connectors.ReceiptsStore
+	testPaymentsStore     connectors.TestPaymentsStore
+	brandingStore         connectors.BrandingStore
+	balanceSnapshotsStore connectors.BalanceSnapshotsStore
+	accountsStore         connectors.AccountsStore
+	dbChecker             connectors.WriteChecker
+	sendHook              connectors.SendHook
+	addressProvider       connectors.AddressProvider
+	identityKey           *identity.Key
+	federation            *federation
+	quotes                *quoteStore
+	receiptEvents         *receiptEvents
+	withdrawals           *withdrawalPauses
+	breaker               *sendsBreaker
+	selfTests             *selfTests
+	limiter               *RateLimiter
+	fiatRates             FiatRates
+	features              *features.Registry
+	messages              *locale.Catalog
+	info                  *DiagnosticsInfo
+	metrics               rpc.MetricsBackend
+
+	// refundMtx serializes the refunds, so that concurrent refunds of the
+	// same payment couldn't exceed its amount.
+	refundMtx sync.Mutex
+
+	// transferMtx serializes the transfers between the accounts, so that
+	// concurrent transfers couldn't overdraw the account.
+	transferMtx sync.Mutex
+
+	// testPayments denotes that fabricated incoming payments could be
+	// injected, it is enabled only on staging environments.
+	testPayments bool
+}
+
+// DiagnosticsInfo is the information about the service, which is returned
+// along with its state by the Diagnose method.
+type DiagnosticsInfo struct {
+	// Version is the version of the service.
+	Version string
+
+	// StartedAt is the time of the service start.
+	StartedAt time.Time
+
+	// Config is the list of config options, secrets should be replaced
+	// before they are passed to the server.
+	Config []*ConfigOption
 }
 
 // A compile time check to ensure that Server fully implements the
 // PayServer gRPC service.
 var _ PayServerServer = (*Server)(nil)
 
+// A compile time check to ensure that Server fully implements the Admin
+// gRPC service.
+var _ AdminServer = (*Server)(nil)
+
+// A compile time check to ensure that Server fully implements the
+// Federation gRPC service.
+var _ FederationServer = (*Server)(nil)
+
 // NewRPCServer creates and returns a new instance of the Server.
 func NewRPCServer(net string,
 	blockchainConnectors map[connectors.Asset]connectors.BlockchainConnector,
 	lightningConnectors map[connectors.Asset]connectors.LightningConnector,
 	paymentsStore connectors.PaymentsStore,
+	payeesStore connectors.PayeesStore,
+	watchStore connectors.WatchStore,
+	apiKeysStore connectors.APIKeysStore,
+	timeLocksStore connectors.TimeLocksStore,
+	receiptsStore connectors.ReceiptsStore,
+	testPaymentsStore connectors.TestPaymentsStore,
+	brandingStore connectors.BrandingStore,
+	balanceSnapshotsStore connectors.BalanceSnapshotsStore,
+	withdrawalPausesStore connectors.WithdrawalPausesStore,
+	accountsStore connectors.AccountsStore,
+	dbChecker connectors.WriteChecker,
+	sendHook connectors.SendHook,
+	addressProvider connectors.AddressProvider,
+	identityKey *identity.Key,
+	federationConfig *FederationConfig,
+	limiter *RateLimiter,
+	fiatRates FiatRates,
+	selfTestCanaries map[connectors.Asset]*SelfTestCanary,
+	features *features.Registry,
+	messages *locale.Catalog,
+	info *DiagnosticsInfo,
+	testPayments bool,
 	metrics rpc.MetricsBackend) (*Server, error) {
+	withdrawals, err := newWithdrawalPauses(withdrawalPausesStore)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests to the federation peers are signed by the identity key.
+	if federationConfig != nil && identityKey == nil {
+		return nil, errors.New("identity key is required by federation")
+	}
+
 	return &Server{
-		blockchainConnectors: blockchainConnectors,
-		lightningConnectors:  lightningConnectors,
-		paymentsStore:        paymentsStore,
-		metrics:              metrics,
-		net:                  net,
+		blockchainConnectors:  blockchainConnectors,
+		lightningConnectors:   lightningConnectors,
+		paymentsStore:         paymentsStore,
+		payeesStore:           payeesStore,
+		watchStore:            watchStore,
+		apiKeysStore:          apiKeysStore,
+		timeLocksStore:        timeLocksStore,
+		receiptsStore:         receiptsStore,
+		testPaymentsStore:     testPaymentsStore,
+		brandingStore:         brandingStore,
+		balanceSnapshotsStore: balanceSnapshotsStore,
+		accountsStore:         accountsStore,
+		dbChecker:             dbChecker,
+		sendHook:              sendHook,
+		addressProvider:       addressProvider,
+		identityKey:           identityKey,
+		federation:            newFederation(federationConfig),
+		quotes:                newQuoteStore(defaultQuoteTTL),
+		receiptEvents:         newReceiptEvents(),
+		withdrawals:           withdrawals,
+		breaker:               &sendsBreaker{},
+		selfTests:             newSelfTests(selfTestCanaries),
+		limiter:               limiter,
+		fiatRates:             fiatRates,
+		features:              features,
+		messages:              messages,
+		info:                  info,
+		testPayments:          testPayments,
+		metrics:               metrics,
+		net:                   net,
 	}, nil
 }
 
@@ -52,7 +179,74 @@ func (s *Server) CreateReceipt(ctx context.Context,
 	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
 		requestID, convertProtoMessage(req))
 
-	var resp *CreateReceiptResponse
+	var (
+		resp    *CreateReceiptResponse
+		receipt *connectors.Receipt
+	)
+
+	amount := decimal.Zero
+	if req.Amount != "" {
+		var err error
+		amount, err = decimal.NewFromString(req.Amount)
+		if err != nil {
+			err := newErrInvalidArgument("amount")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+	}
+
+	if req.Expiry < 0 {
+		err := newErrInvalidArgument("expiry")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if !isValidAccount(req.Account) {
+		err := newErrInvalidArgument("account")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Incoming funds of the receipt are credited to the account, so
+	// account of another tenant couldn't be used.
+	if err := s.checkAccountTenant(ctx, req.Account); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if !isValidMetadata(req.Metadata) {
+		err := newErrInvalidArgument("metadata")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Invoice carries either the description or the hash of the reference,
+	// and blockchain receipt carries neither of them.
+	if req.Reference != "" && (req.Media != Media_LIGHTNING ||
+		req.Description != "" || len(req.Reference) > maxReferenceLength ||
+		!utf8.ValidString(req.Reference)) {
+		err := newErrInvalidArgument("reference")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if err := s.limitCall(ctx, "CreateReceipt", req.Asset, 1); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		return nil, err
+	}
 
 	switch req.Media {
 	case Media_BLOCKCHAIN:
@@ -65,7 +259,10 @@ func (s *Server) CreateReceipt(ctx context.Context,
 			return nil, err
 		}
 
-		address, err := c.CreateAddress()
+		stop := trackStage(ctx, stageNode)
+		address, err := s.createAddress(ctx, c,
+			connectors.Asset(req.Asset.String()))
+		stop()
 		if err != nil {
 			err := newErrInternal(err.Error())
 			log.Errorf("command(%v), id(%v),error: %v",
@@ -74,9 +271,28 @@ func (s *Server) CreateReceipt(ctx context.Context,
 			return nil, err
 		}
 
+		// Receipt is returned and stored in the same form as the payments
+		// on it, so that they could be matched.
+		address = connectors.NormalizeReceipt(connectors.Asset(
+			req.Asset.String()), connectors.Blockchain, address)
+
 		resp = &CreateReceiptResponse{
 			Receipt: address,
 		}
+
+		receipt = &connectors.Receipt{
+			Receipt:   address,
+			Asset:     connectors.Asset(req.Asset.String()),
+			Media:     connectors.Blockchain,
+			Amount:    amount,
+			CreatedAt: connectors.NowInMilliSeconds(),
+		}
+
+		// Address could be paid at any time, expiry only denotes the time
+		// after which payment on it is no longer expected.
+		if req.Expiry != 0 {
+			receipt.ExpiresAt = receipt.CreatedAt + req.Expiry*1000
+		}
 	case Media_LIGHTNING:
 		c, ok := s.lightningConnectors[connectors.Asset(req.Asset.String())]
 		if !ok {
@@ -93,8 +309,37 @@ func (s *Server) CreateReceipt(ctx context.Context,
 			req.Amount = "0"
 		}
 
-		paymentRequest, invoice, err := c.CreateInvoice("zigzag", req.Amount,
-			req.Description)
+		// Payers see the invoice description in their wallets, that is why
+		// the default one is rendered in the locale of the merchant.
+		description := req.Description
+		if description == "" && req.Reference == "" {
+			description = s.messages.ForTenant(apiKeyIDFromContext(ctx),
+				locale.InvoiceDescription, locale.Args{
+					"amount": req.Amount,
+					"asset":  req.Asset.String(),
+				})
+		}
+
+		receipt = &connectors.Receipt{
+			Asset:       connectors.Asset(req.Asset.String()),
+			Media:       connectors.Lightning,
+			Amount:      amount,
+			Description: description,
+			Reference:   req.Reference,
+		}
+
+		if _, ok := c.(connectors.HashedInvoiceCreator); !ok &&
+			receipt.Reference != "" {
+			err := newErrAssetNotSupported(req.Asset.String(), req.Media.String())
+			log.Errorf("command(%v), id(%v),error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		stop := trackStage(ctx, stageNode)
+		paymentRequest, invoice, err := createInvoice(c, receipt)
+		stop()
 		if err != nil {
 			err := newErrInternal(err.Error())
 			log.Errorf("command(%v), id(%v),error: %v",
@@ -109,6 +354,13 @@ func (s *Server) CreateReceipt(ctx context.Context,
 			Receipt:      paymentRequest,
 		}
 
+		resp.Warnings = s.inboundLiquidityWarning(ctx, c, amount)
+		resp.DescriptionHash = hex.EncodeToString(receipt.DescriptionHash())
+
+		receipt.Receipt = paymentRequest
+		receipt.CreatedAt = resp.CreationDate
+		receipt.ExpiresAt = resp.CreationDate + resp.Expiry
+
 	default:
 		err := errors.Errorf("media(%v) is not supported", req.Media.String())
 		log.Errorf("command(%v), id(%v), error: %v",
@@ -117,6 +369,251 @@ func (s *Server) CreateReceipt(ctx context.Context,
 		return nil, err
 	}
 
+	// Receipt is bound to the tenant, so that feature flags are rolled
+	// out on the incoming payments of the same merchant.
+	receipt.Tenant = apiKeyIDFromContext(ctx)
+	receipt.AccountID = req.Account
+	receipt.Metadata = connectors.MergeLabels(nil, req.Metadata)
+
+	receipt.ReceiptID = receipt.GenReceiptID()
+
+	stop := trackStage(ctx, stageDB)
+	err := s.receiptsStore.SaveReceipt(receipt)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp.ReceiptId = receipt.ReceiptID
+	resp.ExpiresAt = receipt.ExpiresAt
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// NewAddress returns fresh blockchain address bound to the account, without
+// creating the receipt, for the integrations which manage their own
+// invoicing. Incoming payments on the address are detected and belong to
+// the account, but address isn't returned by the ListReceipts.
+func (s *Server) NewAddress(ctx context.Context,
+	req *NewAddressRequest) (*NewAddressResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if !isValidAccount(req.Account) {
+		err := newErrInvalidArgument("account")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Incoming funds of the address are credited to the account, so
+	// account of another tenant couldn't be used.
+	if err := s.checkAccountTenant(ctx, req.Account); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Address is generated in the same way as for the receipt, that is why
+	// it takes the request from the same limit, so that limit couldn't be
+	// bypassed.
+	if err := s.limitCall(ctx, "CreateReceipt", req.Asset, 1); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		return nil, err
+	}
+
+	asset := connectors.Asset(req.Asset.String())
+
+	c, ok := s.blockchainConnectors[asset]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageNode)
+	address, err := s.createAddress(ctx, c, asset)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Address is stored in the same form as the payments on it, so that
+	// they could be bound to the account.
+	address = connectors.NormalizeReceipt(asset, connectors.Blockchain,
+		address)
+
+	stop = trackStage(ctx, stageDB)
+	err = s.receiptsStore.SaveDepositAddress(&connectors.DepositAddress{
+		Address:   address,
+		Asset:     asset,
+		CreatedAt: connectors.NowInMilliSeconds(),
+		Tenant:    apiKeyIDFromContext(ctx),
+		AccountID: req.Account,
+	})
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &NewAddressResponse{
+		Address: address,
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListReceipts returns receipts which were created by CreateReceipt, along
+// with their status, from the newest one.
+func (s *Server) ListReceipts(ctx context.Context,
+	req *ListReceiptsRequest) (*ListReceiptsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	var (
+		query connectors.ReceiptsQuery
+		err   error
+	)
+
+	if req.Asset != Asset_ASSET_NONE {
+		query.Asset, err = ConvertAssetFromProto(req.Asset)
+		if err != nil {
+			err := newErrInvalidArgument("asset")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+	}
+
+	query.Media, err = ConvertMediaFromProto(req.Media)
+	if err != nil {
+		err := newErrInvalidArgument("media")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	query.Status, err = ConvertReceiptStatusFromProto(req.Status)
+	if err != nil {
+		err := newErrInvalidArgument("status")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	query.CreatedFrom = req.CreatedFrom
+	query.CreatedTo = req.CreatedTo
+	query.Offset = int(req.Offset)
+	query.Limit = int(req.Limit)
+
+	stop := trackStage(ctx, stageDB)
+	receipts, total, err := s.receiptsStore.QueryReceipts(query)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &ListReceiptsResponse{
+		Total: uint64(total),
+	}
+	for _, receipt := range receipts {
+		protoReceipt, err := convertReceiptToProto(receipt)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		resp.Receipts = append(resp.Receipts, protoReceipt)
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ReceiptByID returns receipt which was created by CreateReceipt, along
+// with its status, by the identifier returned on creation.
+func (s *Server) ReceiptByID(ctx context.Context,
+	req *ReceiptByIDRequest) (*Receipt, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.ReceiptId == "" {
+		err := newErrInvalidArgument("receipt_id")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageDB)
+	receipt, err := s.receiptsStore.ReceiptByID(req.ReceiptId)
+	stop()
+	if err != nil {
+		if err == connectors.ReceiptNotFound {
+			err = newErrInvalidArgument("receipt_id")
+		} else {
+			err = newErrInternal(err.Error())
+		}
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp, err := convertReceiptToProto(receipt)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
 	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
 		requestID, convertProtoMessage(resp))
 
@@ -132,7 +629,7 @@ func (s *Server) ValidateReceipt(ctx context.Context,
 	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
 		requestID, convertProtoMessage(req))
 
-	var data isValidateReceiptResponse_Data
+	resp := &ValidateReceiptResponse{}
 
 	switch req.Media {
 	case Media_BLOCKCHAIN:
@@ -145,13 +642,21 @@ func (s *Server) ValidateReceipt(ctx context.Context,
 			return nil, err
 		}
 
-		if err := c.ValidateAddress(req.Receipt); err != nil {
+		stop := trackStage(ctx, stageNode)
+		addressInfo, err := c.ValidateAddress(req.Receipt)
+		stop()
+		if err != nil {
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
 			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 			return nil, err
 		}
 
+		resp.Receipt = addressInfo.Address
+		resp.Type = string(addressInfo.Type)
+		resp.Net = addressInfo.Net
+		resp.Warnings = s.addressReuseWarning(ctx, addressInfo.Address, "")
+
 	case Media_LIGHTNING:
 		c, ok := s.lightningConnectors[connectors.Asset(req.Asset.String())]
 		if !ok {
@@ -166,7 +671,9 @@ func (s *Server) ValidateReceipt(ctx context.Context,
 			req.Amount = "0"
 		}
 
+		stop := trackStage(ctx, stageNode)
 		invoice, err := c.ValidateInvoice(req.Receipt, req.Amount)
+		stop()
 		if err != nil {
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
@@ -194,8 +701,11 @@ func (s *Server) ValidateReceipt(ctx context.Context,
 			destination = hex.EncodeToString(invoice.Destination.SerializeCompressed())
 		}
 
-		connectors.NowInMilliSeconds()
-		data = &ValidateReceiptResponse_Invoice{
+		// Invoice is bech32 encoded, and its canonical form is lowercase.
+		resp.Receipt = strings.ToLower(req.Receipt)
+		resp.Type = "invoice"
+		resp.Net = s.net
+		resp.Data = &ValidateReceiptResponse_Invoice{
 			Invoice: &Invoice{
 				Memo:         description,
 				Value:        invoiceAmount.Round(8).String(),
@@ -213,10 +723,6 @@ func (s *Server) ValidateReceipt(ctx context.Context,
 		return nil, err
 	}
 
-	resp := &ValidateReceiptResponse{
-		Data: data,
-	}
-
 	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
 		requestID, convertProtoMessage(resp))
 
@@ -255,7 +761,9 @@ func (s *Server) Balance(ctx context.Context, req *BalanceRequest,
 		}
 
 		for asset, c := range cntrs {
+			stop := trackStage(ctx, stageNode)
 			available, err := c.ConfirmedBalance()
+			stop()
 			if err != nil {
 				err := newErrInternal(err.Error())
 				log.Errorf("command(%v), id(%v), error: %v",
@@ -264,7 +772,22 @@ func (s *Server) Balance(ctx context.Context, req *BalanceRequest,
 				return nil, err
 			}
 
+			stop = trackStage(ctx, stageNode)
 			pending, err := c.PendingBalance()
+			stop()
+			if err != nil {
+				err := newErrInternal(err.Error())
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+				return nil, err
+			}
+
+			// Quarantined funds are in the wallet, but they couldn't be
+			// spent until the review is over.
+			stop = trackStage(ctx, stageDB)
+			quarantined, err := s.quarantinedBalance(asset)
+			stop()
 			if err != nil {
 				err := newErrInternal(err.Error())
 				log.Errorf("command(%v), id(%v), error: %v",
@@ -273,6 +796,11 @@ func (s *Server) Balance(ctx context.Context, req *BalanceRequest,
 				return nil, err
 			}
 
+			available = available.Sub(quarantined)
+			if available.Sign() < 0 {
+				available = decimal.Zero
+			}
+
 			protoAsset, err := convertAssetToProto(asset)
 			if err != nil {
 				err := newErrInternal(err.Error())
@@ -283,10 +811,11 @@ func (s *Server) Balance(ctx context.Context, req *BalanceRequest,
 			}
 
 			resp.Balances = append(resp.Balances, &Balance{
-				Media:     Media_BLOCKCHAIN,
-				Asset:     protoAsset,
-				Available: available.String(),
-				Pending:   pending.String(),
+				Media:       Media_BLOCKCHAIN,
+				Asset:       protoAsset,
+				Available:   available.String(),
+				Pending:     pending.String(),
+				Quarantined: quarantined.String(),
 			})
 
 			// TODO(andrew.shvv) Combine btc balance with lightning btc
@@ -321,7 +850,9 @@ func (s *Server) Balance(ctx context.Context, req *BalanceRequest,
 			// balance it will be unclear for end user how use this balance,
 			// otherwise we would ned to have two different rpc methods for that.
 
+			stop := trackStage(ctx, stageNode)
 			available, err := c.ConfirmedBalance()
+			stop()
 			if err != nil {
 				err := newErrInternal(err.Error())
 				log.Errorf("command(%v), id(%v), error: %v",
@@ -330,7 +861,9 @@ func (s *Server) Balance(ctx context.Context, req *BalanceRequest,
 				return nil, err
 			}
 
+			stop = trackStage(ctx, stageNode)
 			pending, err := c.PendingBalance()
+			stop()
 			if err != nil {
 				err := newErrInternal(err.Error())
 				log.Errorf("command(%v), id(%v), error: %v",
@@ -363,7 +896,18 @@ func (s *Server) Balance(ctx context.Context, req *BalanceRequest,
 	return resp, nil
 }
 
-//
+// feeTargets are the standard confirmation speeds, for which fees are
+// estimated if all targets are requested.
+var feeTargets = []struct {
+	speed      string
+	confTarget uint32
+}{
+	{speed: "fast", confTarget: 2},
+	{speed: "normal", confTarget: 6},
+	{speed: "economy", confTarget: 144},
+}
+
+//
 // EstimateFee estimates the fee of the outgoing payment.
 func (s *Server) EstimateFee(ctx context.Context,
 	req *EstimateFeeRequest) (*EstimateFeeResponse, error) {
@@ -374,6 +918,14 @@ func (s *Server) EstimateFee(ctx context.Context,
 
 	var resp *EstimateFeeResponse
 
+	if req.ConfTarget > connectors.MaxConfTarget {
+		err := newErrInvalidArgument("conf_target")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
 	switch req.Media {
 	case Media_BLOCKCHAIN:
 		c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
@@ -385,11 +937,35 @@ func (s *Server) EstimateFee(ctx context.Context,
 			return nil, err
 		}
 
+		// Account based blockchains, e.g. ethereum, have no notion of
+		// the confirmation target.
+		estimator, ok := c.(connectors.FeeTargetEstimator)
+		if !ok && req.ConfTarget != 0 {
+			err := newErrInvalidArgument("conf_target")
+			log.Errorf("command(%v), id(%v), error: %v, confirmation "+
+				"targets are not supported", common.GetFunctionName(),
+				requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
 		if req.Amount == "" {
 			req.Amount = "0"
 		}
 
-		fee, err := c.EstimateFee(req.Amount)
+		var (
+			fee decimal.Decimal
+			err error
+		)
+
+		stop := trackStage(ctx, stageNode)
+		if req.ConfTarget != 0 {
+			fee, err = estimator.EstimateFeeWithTarget(req.Amount,
+				req.ConfTarget)
+		} else {
+			fee, err = c.EstimateFee(req.Amount)
+		}
+		stop()
 		if err != nil {
 			err := newErrInternal(err.Error())
 			log.Errorf("command(%v), id(%v), error: %v",
@@ -399,7 +975,30 @@ func (s *Server) EstimateFee(ctx context.Context,
 		}
 
 		resp = &EstimateFeeResponse{
-			MediaFee: fee.String(),
+			MediaFee:   fee.String(),
+			ConfTarget: req.ConfTarget,
+		}
+
+		if req.AllTargets && estimator != nil {
+			for _, target := range feeTargets {
+				stop := trackStage(ctx, stageNode)
+				fee, err := estimator.EstimateFeeWithTarget(req.Amount,
+					target.confTarget)
+				stop()
+				if err != nil {
+					err := newErrInternal(err.Error())
+					log.Errorf("command(%v), id(%v), error: %v",
+						common.GetFunctionName(), requestID, err)
+					s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+					return nil, err
+				}
+
+				resp.Targets = append(resp.Targets, &FeeTarget{
+					Speed:      target.speed,
+					ConfTarget: target.confTarget,
+					MediaFee:   fee.String(),
+				})
+			}
 		}
 
 	case Media_LIGHTNING:
@@ -412,7 +1011,17 @@ func (s *Server) EstimateFee(ctx context.Context,
 			return nil, err
 		}
 
+		if req.ConfTarget != 0 {
+			err := newErrInvalidArgument("conf_target")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		stop := trackStage(ctx, stageNode)
 		fee, err := c.EstimateFee(req.Receipt)
+		stop()
 		if err != nil {
 			err := newErrInternal(err.Error())
 			log.Errorf("command(%v), id(%v), error: %v",
@@ -440,22 +1049,26 @@ func (s *Server) EstimateFee(ctx context.Context,
 }
 
 //
-// SendPayment sends payment to the given recipient,
-// ensures in the validity of the receipt as well as the
-// account has enough money for doing that.
-func (s *Server) SendPayment(ctx context.Context,
-	req *SendPaymentRequest) (*Payment, error) {
+// QuotePayment returns the quote of the payment by which recipient receives
+// exactly the given amount, fee of the network is paid by the sender on top
+// of the amount.
+func (s *Server) QuotePayment(ctx context.Context,
+	req *QuotePaymentRequest) (*PaymentQuote, error) {
 	requestID := rand.Int()
 
 	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
 		requestID, convertProtoMessage(req))
 
-	var (
-		resp    *Payment
-		payment *connectors.Payment
-		err     error
-	)
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || amount.Sign() <= 0 {
+		err := newErrInvalidArgument("amount")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
 
+	receipt := req.Receipt
 	switch req.Media {
 	case Media_BLOCKCHAIN:
 		c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
@@ -467,21 +1080,21 @@ func (s *Server) SendPayment(ctx context.Context,
 			return nil, err
 		}
 
-		// TODO(andrew.shvv) generate and send can't be used separately,
-		// because of the work of the lock inputs.
-		if req.Amount == "" {
-			req.Amount = "0"
-		}
-
-		payment, err = c.SendPayment(req.Receipt, req.Amount)
+		stop := trackStage(ctx, stageNode)
+		info, err := c.ValidateAddress(req.Receipt)
+		stop()
 		if err != nil {
-			err := newErrInternal(err.Error())
+			err := newErrInvalidArgument("receipt")
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
 			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 			return nil, err
 		}
 
+		// Quote is matched with the payment request by the receipt, that
+		// is why it is stored in the canonical form.
+		receipt = info.Address
+
 	case Media_LIGHTNING:
 		c, ok := s.lightningConnectors[connectors.Asset(req.Asset.String())]
 		if !ok {
@@ -492,19 +1105,20 @@ func (s *Server) SendPayment(ctx context.Context,
 			return nil, err
 		}
 
-		if req.Amount == "" {
-			req.Amount = "0"
-		}
-
-		payment, err = c.SendTo(req.Receipt, req.Amount)
+		stop := trackStage(ctx, stageNode)
+		_, err := c.ValidateInvoice(req.Receipt, req.Amount)
+		stop()
 		if err != nil {
-			err := newErrInternal(err.Error())
+			err := newErrInvalidArgument("receipt")
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
 			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 			return nil, err
 		}
 
+		// Invoice is bech32 encoded, and its canonical form is lowercase.
+		receipt = strings.ToLower(req.Receipt)
+
 	default:
 		err := errors.Errorf("media(%v) is not supported", req.Media.String())
 		log.Errorf("command(%v), id(%v), error: %v",
@@ -513,7 +1127,17 @@ func (s *Server) SendPayment(ctx context.Context,
 		return nil, err
 	}
 
-	resp, err = convertPaymentToProto(payment)
+	estimation, err := s.EstimateFee(ctx, &EstimateFeeRequest{
+		Asset:   req.Asset,
+		Media:   req.Media,
+		Amount:  req.Amount,
+		Receipt: req.Receipt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := decimal.NewFromString(estimation.MediaFee)
 	if err != nil {
 		err := newErrInternal(err.Error())
 		log.Errorf("command(%v), id(%v), error: %v",
@@ -522,6 +1146,25 @@ func (s *Server) SendPayment(ctx context.Context,
 		return nil, err
 	}
 
+	resp := &PaymentQuote{
+		Asset:       req.Asset,
+		Media:       req.Media,
+		Receipt:     receipt,
+		Amount:      amount.String(),
+		MediaFee:    fee.String(),
+		GrossAmount: amount.Add(fee).String(),
+	}
+
+	// Quote is bound to the API key by which it was requested, so that it
+	// couldn't be used by the other tenant.
+	if err := s.quotes.add(resp, apiKeyIDFromContext(ctx)); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
 	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
 		requestID, convertProtoMessage(resp))
 
@@ -529,75 +1172,85 @@ func (s *Server) SendPayment(ctx context.Context,
 }
 
 //
-// PaymentByID is used to fetch the information about payment, by the
-// given system payment id.
-func (s *Server) PaymentByID(ctx context.Context, req *PaymentByIDRequest) (*Payment,
-	error) {
+// QuoteReceipt converts the fiat price into the amount of every enabled
+// asset and media for which price of the asset is configured.
+func (s *Server) QuoteReceipt(ctx context.Context,
+	req *QuoteReceiptRequest) (*QuoteReceiptResponse, error) {
 	requestID := rand.Int()
 
 	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
 		requestID, convertProtoMessage(req))
 
-	payment, err := s.paymentsStore.PaymentByID(req.PaymentId)
-	if err != nil {
-		err := newErrInternal(err.Error())
+	if _, ok := s.fiatRates[strings.ToUpper(req.Currency)]; !ok {
+		err := newErrInvalidArgument("currency")
 		log.Errorf("command(%v), id(%v), error: %v",
 			common.GetFunctionName(), requestID, err)
 		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 		return nil, err
 	}
 
-	resp, err := convertPaymentToProto(payment)
-	if err != nil {
-		err := newErrInternal(err.Error())
+	price, err := decimal.NewFromString(req.Price)
+	if err != nil || price.Sign() <= 0 {
+		err := newErrInvalidArgument("price")
 		log.Errorf("command(%v), id(%v), error: %v",
 			common.GetFunctionName(), requestID, err)
 		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 		return nil, err
 	}
 
-	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
-		requestID, convertProtoMessage(resp))
+	resp := &QuoteReceiptResponse{}
 
-	return resp, err
-}
+	for asset, c := range s.blockchainConnectors {
+		rate, ok := s.fiatRates.Price(asset, req.Currency)
+		if !ok {
+			continue
+		}
 
-//
-// PaymentsByReceipt is used to fetch the information about payment, by the
-// given receipt.
-func (s *Server) PaymentsByReceipt(ctx context.Context,
-	req *PaymentsByReceiptRequest) (*PaymentsByReceiptResponse, error) {
-	requestID := rand.Int()
+		quote, err := newReceiptQuote(asset, Media_BLOCKCHAIN, price, rate)
+		if err != nil {
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			continue
+		}
 
-	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
-		requestID, convertProtoMessage(req))
+		// Checkout page should render the other options even if one of
+		// the daemons is unavailable, that is why fee is optional.
+		stop := trackStage(ctx, stageNode)
+		fee, err := c.EstimateFee(quote.Amount)
+		stop()
+		if err != nil {
+			log.Errorf("command(%v), id(%v), unable to estimate fee of "+
+				"asset(%v): %v", common.GetFunctionName(), requestID,
+				asset, err)
+		} else {
+			quote.MediaFee = fee.String()
+		}
 
-	payments, err := s.paymentsStore.PaymentByReceipt(req.Receipt)
-	if err != nil {
-		err := newErrInternal(err.Error())
-		log.Errorf("command(%v), id(%v), error: %v",
-			common.GetFunctionName(), requestID, err)
-		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
-		return nil, err
+		resp.Quotes = append(resp.Quotes, quote)
 	}
 
-	var protoPayments []*Payment
-	for _, payment := range payments {
-		protoPayment, err := convertPaymentToProto(payment)
+	for asset := range s.lightningConnectors {
+		rate, ok := s.fiatRates.Price(asset, req.Currency)
+		if !ok {
+			continue
+		}
+
+		quote, err := newReceiptQuote(asset, Media_LIGHTNING, price, rate)
 		if err != nil {
-			err := newErrInternal(err.Error())
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
-			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
-			return nil, err
+			continue
 		}
 
-		protoPayments = append(protoPayments, protoPayment)
+		resp.Quotes = append(resp.Quotes, quote)
 	}
 
-	resp := &PaymentsByReceiptResponse{
-		Payments: protoPayments,
-	}
+	sort.Slice(resp.Quotes, func(i, j int) bool {
+		if resp.Quotes[i].Asset != resp.Quotes[j].Asset {
+			return resp.Quotes[i].Asset < resp.Quotes[j].Asset
+		}
+		return resp.Quotes[i].Media < resp.Quotes[j].Media
+	})
 
 	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
 		requestID, convertProtoMessage(resp))
@@ -606,50 +1259,86 @@ func (s *Server) PaymentsByReceipt(ctx context.Context,
 }
 
 //
-// ListPayments returns list of payment which were registered by the
-// system.
-func (s *Server) ListPayments(ctx context.Context,
-	req *ListPaymentsRequest) (*ListPaymentsResponse, error) {
+// SendPayment sends payment to the given recipient,
+// ensures in the validity of the receipt as well as the
+// account has enough money for doing that.
+func (s *Server) SendPayment(ctx context.Context,
+	req *SendPaymentRequest) (*Payment, error) {
 	requestID := rand.Int()
 
 	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
 		requestID, convertProtoMessage(req))
 
 	var (
-		asset     connectors.Asset
-		status    connectors.PaymentStatus
-		direction connectors.PaymentDirection
-		media     connectors.PaymentMedia
-		system    connectors.PaymentSystem
-		err       error
+		resp    *Payment
+		payment *connectors.Payment
+		quote   *PaymentQuote
+		err     error
 	)
 
-	if req.Asset != Asset_ASSET_NONE {
-		asset, err = ConvertAssetFromProto(req.Asset)
-		if err != nil {
-			err := newErrInternal(err.Error())
+	if !isValidAccount(req.Account) {
+		err := newErrInvalidArgument("account")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Payment is debited from the account, so account of another tenant
+	// couldn't be used.
+	if err := s.checkAccountTenant(ctx, req.Account); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if !isValidMetadata(req.Metadata) {
+		err := newErrInvalidArgument("metadata")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if req.QuoteId != "" {
+		if req.Payee != "" {
+			err := newErrInvalidArgument("payee")
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
 			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 			return nil, err
 		}
-	}
 
-	if req.Direction != PaymentDirection_DIRECTION_NONE {
-		direction, err = ConvertPaymentDirectionFromProto(req.Direction)
+		// Quote is consumed only after the request has been validated
+		// against it, so that the mistaken request doesn't burn it.
+		quote, err = s.quotes.get(req.QuoteId, apiKeyIDFromContext(ctx))
 		if err != nil {
-			err := newErrInternal(err.Error())
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
+			err := newErrInvalidArgument("quote_id")
 			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 			return nil, err
 		}
-	}
 
-	if req.System != PaymentSystem_SYSTEM_NONE {
-		system, err = ConvertPaymentSystemFromProto(req.System)
-		if err != nil {
-			err := newErrInternal(err.Error())
+		// Receipt of the quote is stored in the canonical form, so the
+		// receipt of the request is normalized before the comparison.
+		if req.Receipt != "" {
+			asset, _ := ConvertAssetFromProto(quote.Asset)
+			media, _ := ConvertMediaFromProto(quote.Media)
+
+			stop := trackStage(ctx, stageNode)
+			req.Receipt, err = s.normalizeReceipt(asset, media, req.Receipt)
+			stop()
+			if err != nil {
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+				return nil, err
+			}
+		}
+
+		if err := applyQuote(req, quote); err != nil {
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
 			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
@@ -657,19 +1346,31 @@ func (s *Server) ListPayments(ctx context.Context,
 		}
 	}
 
-	if req.Status != PaymentStatus_STATUS_NONE {
-		status, err = ConvertPaymentStatusFromProto(req.Status)
+	if req.Payee != "" {
+		if req.Receipt != "" {
+			err := newErrInvalidArgument("receipt")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		stop := trackStage(ctx, stageDB)
+		payee, err := s.payeesStore.PayeeByName(req.Payee)
+		stop()
 		if err != nil {
-			err := newErrInternal(err.Error())
+			if err == connectors.PayeeNotFound {
+				err = newErrInvalidArgument("payee")
+			} else {
+				err = newErrInternal(err.Error())
+			}
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
 			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 			return nil, err
 		}
-	}
 
-	if req.Media != Media_MEDIA_NONE {
-		media, err = ConvertMediaFromProto(req.Media)
+		protoPayee, err := convertPayeeToProto(payee)
 		if err != nil {
 			err := newErrInternal(err.Error())
 			log.Errorf("command(%v), id(%v), error: %v",
@@ -677,38 +1378,3768 @@ func (s *Server) ListPayments(ctx context.Context,
 			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 			return nil, err
 		}
+
+		// Asset and media might be specified in the request as an
+		// additional safety check, in this case they should match the
+		// payee preset.
+		if req.Asset != Asset_ASSET_NONE && req.Asset != protoPayee.Asset {
+			err := newErrInvalidArgument("asset")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		if req.Media != Media_MEDIA_NONE && req.Media != protoPayee.Media {
+			err := newErrInvalidArgument("media")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		req.Asset = protoPayee.Asset
+		req.Media = protoPayee.Media
+		req.Receipt = protoPayee.Receipt
+
+		if req.Amount == "" && payee.Amount.Sign() != 0 {
+			req.Amount = protoPayee.Amount
+		}
 	}
 
-	payments, err := s.paymentsStore.ListPayments(asset, status, direction,
-		media, system)
-	if err != nil {
-		err := newErrInternal(err.Error())
+	// Limit is checked after the asset has been resolved from the payee or
+	// the quote, so that it couldn't be bypassed by them.
+	if err := s.limitCall(ctx, "SendPayment", req.Asset, 1); err != nil {
 		log.Errorf("command(%v), id(%v), error: %v",
 			common.GetFunctionName(), requestID, err)
-		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 		return nil, err
 	}
 
-	var protoPayments []*Payment
-	for _, payment := range payments {
-		protoPayment, err := convertPaymentToProto(payment)
+	// Hook is called before the quote is taken, so that vetoed payment
+	// doesn't burn it. Dry run isn't passed to the hook, because nothing
+	// is sent.
+	asset, _ := ConvertAssetFromProto(req.Asset)
+	media, _ := ConvertMediaFromProto(req.Media)
+	if !req.DryRun {
+		stop := trackStage(ctx, stageNode)
+		output, err := s.intentOutput(asset, media, req.Receipt, req.Amount)
+		stop()
 		if err != nil {
-			err := newErrInternal(err.Error())
 			log.Errorf("command(%v), id(%v), error: %v",
 				common.GetFunctionName(), requestID, err)
 			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
 			return nil, err
 		}
 
-		protoPayments = append(protoPayments, protoPayment)
+		// Payment is sent to the receipt in the same form in which it
+		// has been checked by the hook.
+		req.Receipt = output.Address
+
+		err = s.checkSendHook(ctx, &connectors.PaymentIntent{
+			Method:  "SendPayment",
+			Asset:   asset,
+			Media:   media,
+			Outputs: []*connectors.PaymentOutput{output},
+			Memo:    req.Memo,
+			Account: req.Account,
+		})
+		if err != nil {
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		// Sends from the account are serialized with the transfers until
+		// the payment is attached to it, so that concurrent ones couldn't
+		// overdraw the account.
+		if req.Account != "" {
+			s.transferMtx.Lock()
+			defer s.transferMtx.Unlock()
+
+			err := s.checkAccountFunds(ctx, req.Account, &EstimateFeeRequest{
+				Asset:   req.Asset,
+				Media:   req.Media,
+				Amount:  output.Amount,
+				Receipt: req.Receipt,
+			})
+			if err != nil {
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+				return nil, err
+			}
+		}
 	}
 
-	resp := &ListPaymentsResponse{
-		Payments: protoPayments,
+	if quote != nil {
+		// Fee of the network might grow since the quote was issued, in
+		// this case payment is rejected, and the new quote should be
+		// requested, so that user doesn't pay more than was shown.
+		estimation, err := s.EstimateFee(ctx, &EstimateFeeRequest{
+			Asset:   req.Asset,
+			Media:   req.Media,
+			Amount:  req.Amount,
+			Receipt: req.Receipt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkQuoteFee(quote, estimation.MediaFee); err != nil {
+			log.Errorf("command(%v), id(%v), error: %v, fee(%v) exceeds "+
+				"quote fee(%v)", common.GetFunctionName(), requestID, err,
+				estimation.MediaFee, quote.MediaFee)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		// Quote is taken right before the sending, concurrent request
+		// with the same quote fails here. Dry run leaves the quote for
+		// the actual payment.
+		if !req.DryRun {
+			if _, err := s.quotes.take(req.QuoteId,
+				apiKeyIDFromContext(ctx)); err != nil {
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				err := newErrInvalidArgument("quote_id")
+				s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+				return nil, err
+			}
+		}
 	}
 
-	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
-		requestID, convertProtoMessage(resp))
+	if req.DryRun {
+		if req.Amount == "" {
+			req.Amount = "0"
+		}
 
-	return resp, nil
+		payment, err = s.simulatePayment(ctx, req)
+		if err != nil {
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		// Payment isn't saved, so account and metadata are only shown.
+		payment.AccountID = req.Account
+		payment.Metadata = connectors.MergeLabels(nil, req.Metadata)
+
+		resp, err = convertPaymentToProto(payment)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		if isIncluded(req.Include, PaymentInclude_WARNINGS) {
+			resp.Warnings = s.feeWarning(ctx, payment.Amount, payment.MediaFee)
+			if req.Media == Media_BLOCKCHAIN {
+				resp.Warnings = append(resp.Warnings,
+					s.addressReuseWarning(ctx, payment.Receipt, "")...)
+			}
+		}
+
+		log.Tracef("command(%v), id(%v), response(%v)",
+			common.GetFunctionName(), requestID, convertProtoMessage(resp))
+
+		return resp, nil
+	}
+
+	switch req.Media {
+	case Media_BLOCKCHAIN:
+		c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
+		if !ok {
+			err := newErrAssetNotSupported(req.Asset.String(), req.Media.String())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		// TODO(andrew.shvv) generate and send can't be used separately,
+		// because of the work of the lock inputs.
+		if req.Amount == "" {
+			req.Amount = "0"
+		}
+
+		// Feature flags are rolled out per API key, so connectors which
+		// are gating sending behaviour receive it as the tenant.
+		stop := trackStage(ctx, stageNode)
+		if sender, ok := c.(connectors.TenantSender); ok {
+			payment, err = sender.SendTenantPayment(apiKeyIDFromContext(ctx),
+				req.Receipt, req.Amount, req.Memo)
+		} else {
+			payment, err = c.SendPayment(req.Receipt, req.Amount, req.Memo)
+		}
+		stop()
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+	case Media_LIGHTNING:
+		c, ok := s.lightningConnectors[connectors.Asset(req.Asset.String())]
+		if !ok {
+			err := newErrAssetNotSupported(req.Asset.String(), req.Media.String())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		if req.Memo != "" {
+			// Lightning payment description is the part of the invoice,
+			// and couldn't be set by the sender.
+			err := newErrInvalidArgument("memo")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		if req.Amount == "" {
+			req.Amount = "0"
+		}
+
+		stop := trackStage(ctx, stageNode)
+		payment, err = c.SendTo(req.Receipt, req.Amount)
+		stop()
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+	default:
+		err := errors.Errorf("media(%v) is not supported", req.Media.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Payment is annotated after it has been sent, failure to annotate it
+	// doesn't revert the payment, that is why it is only reported.
+	err = s.annotatePayment(ctx, payment, req.Account, req.Metadata)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), payment(%v): %v",
+			common.GetFunctionName(), requestID, payment.PaymentID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+	}
+
+	resp, err = convertPaymentToProto(payment)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	includePaymentFields(resp, payment, req.Include)
+
+	// Payment has been already sent, so warnings are only returned to the
+	// caller, so that it could notify the user. Address reuse check queries
+	// the store, that is why they are returned only on demand.
+	if isIncluded(req.Include, PaymentInclude_WARNINGS) {
+		resp.Warnings = s.feeWarning(ctx, payment.Amount, payment.MediaFee)
+		if req.Media == Media_BLOCKCHAIN {
+			resp.Warnings = append(resp.Warnings,
+				s.addressReuseWarning(ctx, payment.Receipt,
+					payment.PaymentID)...)
+		}
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// checkSendHook passes the payment to the pre-send hook before it is
+// broadcasted. Payment rejected by the hook is denied, failure of the hook
+// itself is returned as the internal error, in both cases payment
+// shouldn't be sent. Payments of the asset which withdrawals are paused,
+// and all payments while sends circuit breaker is tripped, are rejected
+// before the hook is asked.
+func (s *Server) checkSendHook(ctx context.Context,
+	intent *connectors.PaymentIntent) error {
+	if trip := s.breaker.tripped(); trip != nil {
+		return newErrSendsSuspended(trip.reason)
+	}
+
+	if pause := s.withdrawals.paused(intent.Asset); pause != nil {
+		return newErrWithdrawalsPaused(string(intent.Asset), pause.Reason)
+	}
+
+	if s.sendHook == nil {
+		return nil
+	}
+
+	intent.Tenant = apiKeyIDFromContext(ctx)
+
+	err := s.sendHook.BeforeSend(intent)
+	switch err := err.(type) {
+	case nil:
+		return nil
+
+	case *connectors.SendVetoError:
+		return newErrPermissionDenied(intent.Method, err.Reason)
+
+	case *connectors.SendHoldError:
+		return newErrPaymentHeld(intent.Method, err.Reason)
+
+	default:
+		return newErrInternal(fmt.Sprintf("pre-send hook failed: %v", err))
+	}
+}
+
+// annotatePayment binds the sent payment to the account and attaches the
+// metadata labels to it.
+func (s *Server) annotatePayment(ctx context.Context,
+	payment *connectors.Payment, account string,
+	metadata map[string]string) error {
+
+	stop := trackStage(ctx, stageDB)
+	defer stop()
+
+	if account != "" {
+		err := s.paymentsStore.SetPaymentAccount(payment.PaymentID, account)
+		if err != nil {
+			return errors.Errorf("unable to bind payment to account(%v): %v",
+				account, err)
+		}
+		payment.AccountID = account
+	}
+
+	if len(metadata) != 0 {
+		err := s.paymentsStore.LabelPayment(payment.PaymentID, metadata)
+		if err != nil {
+			return errors.Errorf("unable to label payment: %v", err)
+		}
+		payment.Metadata = connectors.MergeLabels(payment.Metadata, metadata)
+	}
+
+	return nil
+}
+
+// maxPaymentOutputs is the maximum number of the outputs in SendPayments,
+// so that transaction isn't exceeding the standard size.
+const maxPaymentOutputs = 250
+
+//
+// SendPayments sends payments to the several recipients in one blockchain
+// transaction.
+func (s *Server) SendPayments(ctx context.Context,
+	req *SendPaymentsRequest) (*SendPaymentsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if len(req.Outputs) == 0 || len(req.Outputs) > maxPaymentOutputs {
+		err := newErrInvalidArgument("outputs")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if !isValidAccount(req.Account) {
+		err := newErrInvalidArgument("account")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Payment is debited from the account, so account of another tenant
+	// couldn't be used.
+	if err := s.checkAccountTenant(ctx, req.Account); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if !isValidMetadata(req.Metadata) {
+		err := newErrInvalidArgument("metadata")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Batching is rolled out per API key, so that it could be enabled for
+	// the part of the tenants first.
+	if !s.features.IsEnabled(features.Batching, apiKeyIDFromContext(ctx)) {
+		err := newErrPermissionDenied("SendPayments", fmt.Sprintf("feature "+
+			"flag(%v) isn't enabled", features.Batching))
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Every output is the payment, which is limited in the same way as if
+	// it was sent separately.
+	err := s.limitCall(ctx, "SendPayments", req.Asset, len(req.Outputs))
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		return nil, err
+	}
+
+	c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Account based blockchains, e.g. ethereum, couldn't pay to several
+	// recipients in one transaction.
+	sender, ok := c.(connectors.BatchSender)
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v, batch payments are "+
+			"not supported", common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Receipts are normalized, so that they are checked by the hook in
+	// the canonical form.
+	outputs := make([]*connectors.PaymentOutput, len(req.Outputs))
+	for i, output := range req.Outputs {
+		stop := trackStage(ctx, stageNode)
+		outputs[i], err = s.intentOutput(connectors.Asset(req.Asset.String()),
+			connectors.Blockchain, output.Receipt, output.Amount)
+		stop()
+		if err != nil {
+			log.Errorf("command(%v), id(%v), error: %v, output(%v)",
+				common.GetFunctionName(), requestID, err, i)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+	}
+
+	err = s.checkSendHook(ctx, &connectors.PaymentIntent{
+		Method:  "SendPayments",
+		Asset:   connectors.Asset(req.Asset.String()),
+		Media:   connectors.Blockchain,
+		Outputs: outputs,
+		Account: req.Account,
+	})
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Sends from the account are serialized with the transfers until the
+	// payments are attached to it, fee of the batch is estimated as if
+	// the whole amount is sent in one payment.
+	if req.Account != "" {
+		total := decimal.Zero
+		for i, output := range outputs {
+			amount, err := decimal.NewFromString(output.Amount)
+			if err != nil {
+				err := newErrInvalidArgument("amount")
+				log.Errorf("command(%v), id(%v), error: %v, output(%v)",
+					common.GetFunctionName(), requestID, err, i)
+				s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+				return nil, err
+			}
+			total = total.Add(amount)
+		}
+
+		s.transferMtx.Lock()
+		defer s.transferMtx.Unlock()
+
+		err := s.checkAccountFunds(ctx, req.Account, &EstimateFeeRequest{
+			Asset:  req.Asset,
+			Media:  Media_BLOCKCHAIN,
+			Amount: total.String(),
+		})
+		if err != nil {
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+	}
+
+	stop := trackStage(ctx, stageNode)
+	payments, err := sender.SendPayments(apiKeyIDFromContext(ctx), outputs)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &SendPaymentsResponse{}
+	for _, payment := range payments {
+		// Payment is annotated after it has been sent, failure to
+		// annotate it doesn't revert the payment, that is why it is only
+		// reported.
+		err := s.annotatePayment(ctx, payment, req.Account, req.Metadata)
+		if err != nil {
+			log.Errorf("command(%v), id(%v), payment(%v): %v",
+				common.GetFunctionName(), requestID, payment.PaymentID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+		}
+
+		protoPayment, err := convertPaymentToProto(payment)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		includePaymentFields(protoPayment, payment, req.Include)
+
+		// Payments have been already sent, so warnings are only returned
+		// to the caller, so that it could notify the user.
+		if isIncluded(req.Include, PaymentInclude_WARNINGS) {
+			protoPayment.Warnings = append(
+				s.feeWarning(ctx, payment.Amount, payment.MediaFee),
+				s.addressReuseWarning(ctx, payment.Receipt,
+					payment.PaymentID)...)
+		}
+
+		resp.Payments = append(resp.Payments, protoPayment)
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// SendTimeLockedPayment sends blockchain payment on the script address,
+// which could be spent by the recipient key only after the given block
+// height or time.
+func (s *Server) SendTimeLockedPayment(ctx context.Context,
+	req *SendTimeLockedPaymentRequest) (*TimeLock, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	locker, ok := c.(connectors.TimeLocker)
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v, time locks are not "+
+			"supported", common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageNode)
+	bestHeight, err := locker.BestHeight()
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Lock should be in the future, otherwise funds are sent on the
+	// script address which anyone could mistake for the locked one.
+	var lockTime uint32
+	switch {
+	case req.LockHeight != 0 && req.LockTime == 0:
+		if int64(req.LockHeight) <= bestHeight ||
+			req.LockHeight >= connectors.LockTimeThreshold {
+			err := newErrInvalidArgument("lock_height")
+			log.Errorf("command(%v), id(%v), error: %v, best height(%v)",
+				common.GetFunctionName(), requestID, err, bestHeight)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+		lockTime = req.LockHeight
+
+	case req.LockTime != 0 && req.LockHeight == 0:
+		if req.LockTime <= time.Now().Unix() ||
+			req.LockTime < connectors.LockTimeThreshold ||
+			req.LockTime > math.MaxUint32 {
+			err := newErrInvalidArgument("lock_time")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+		lockTime = uint32(req.LockTime)
+
+	default:
+		err := newErrInvalidArgument("lock_height")
+		log.Errorf("command(%v), id(%v), error: %v, either lock height or "+
+			"lock time should be specified", common.GetFunctionName(),
+			requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	err = s.checkSendHook(ctx, &connectors.PaymentIntent{
+		Method: "SendTimeLockedPayment",
+		Asset:  connectors.Asset(req.Asset.String()),
+		Media:  connectors.Blockchain,
+		Outputs: []*connectors.PaymentOutput{{
+			// Public key is hex encoded, and its canonical form is
+			// lowercase.
+			Address: strings.ToLower(req.RecipientPubkey),
+			Amount:  req.Amount,
+		}},
+	})
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop = trackStage(ctx, stageNode)
+	payment, lock, err := locker.SendTimeLockedPayment(req.RecipientPubkey,
+		req.Amount, lockTime)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Time-locked payment(%v) sent: address(%v), lock time(%v)",
+		payment.PaymentID, lock.Address, lock.LockTime)
+
+	resp := convertTimeLockToProto(lock, bestHeight, time.Now())
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListTimeLocks returns time-locked payments along with the state of their
+// locks.
+func (s *Server) ListTimeLocks(ctx context.Context,
+	req *ListTimeLocksRequest) (*ListTimeLocksResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	var asset connectors.Asset
+	if req.Asset != Asset_ASSET_NONE {
+		asset = connectors.Asset(req.Asset.String())
+	}
+
+	stop := trackStage(ctx, stageDB)
+	locks, err := s.timeLocksStore.ListTimeLocks(asset)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	now := time.Now()
+	heights := make(map[connectors.Asset]int64)
+
+	resp := &ListTimeLocksResponse{}
+	for _, lock := range locks {
+		bestHeight, ok := heights[lock.Asset]
+		if !ok {
+			c := s.blockchainConnectors[lock.Asset]
+			locker, _ := c.(connectors.TimeLocker)
+			if locker == nil {
+				err := newErrAssetNotSupported(string(lock.Asset),
+					Media_BLOCKCHAIN.String())
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+				return nil, err
+			}
+
+			stop := trackStage(ctx, stageNode)
+			bestHeight, err = locker.BestHeight()
+			stop()
+			if err != nil {
+				err := newErrInternal(err.Error())
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+				return nil, err
+			}
+			heights[lock.Asset] = bestHeight
+		}
+
+		protoLock := convertTimeLockToProto(lock, bestHeight, now)
+		if protoLock.Unlocked && !req.IncludeUnlocked {
+			continue
+		}
+
+		resp.TimeLocks = append(resp.TimeLocks, protoLock)
+	}
+
+	sort.SliceStable(resp.TimeLocks, func(i, j int) bool {
+		a, b := resp.TimeLocks[i], resp.TimeLocks[j]
+		if (a.LockHeight != 0) != (b.LockHeight != 0) {
+			return a.LockHeight != 0
+		}
+		if a.LockHeight != b.LockHeight {
+			return a.LockHeight < b.LockHeight
+		}
+		return a.LockTime < b.LockTime
+	})
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// PaymentByID is used to fetch the information about payment, by the
+// given system payment id, along with the timeline of its events.
+func (s *Server) PaymentByID(ctx context.Context, req *PaymentByIDRequest) (*Payment,
+	error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	stop := trackStage(ctx, stageDB)
+	payment, err := s.paymentsStore.PaymentByID(req.PaymentId)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp, err := convertPaymentToProto(payment)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+	includePaymentFields(resp, payment, req.Include)
+
+	stop = trackStage(ctx, stageDB)
+	events, err := s.paymentsStore.PaymentTimeline(payment.PaymentID)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp.Timeline, err = convertPaymentTimelineToProto(events)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, err
+}
+
+//
+// LabelPayment attaches the labels, e.g. order id or customer id, to the
+// payment. Labels are merged into the metadata of the payment, label
+// with empty value is removed.
+func (s *Server) LabelPayment(ctx context.Context,
+	req *LabelPaymentRequest) (*Payment, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if len(req.Labels) == 0 || !isValidMetadata(req.Labels) {
+		err := newErrInvalidArgument("labels")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageDB)
+	err := s.paymentsStore.LabelPayment(req.PaymentId, req.Labels)
+	stop()
+	if err != nil {
+		if err == connectors.PaymentNotFound {
+			err = newErrInvalidArgument("payment_id")
+		} else {
+			err = newErrInternal(err.Error())
+		}
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop = trackStage(ctx, stageDB)
+	payment, err := s.paymentsStore.PaymentByID(req.PaymentId)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp, err := convertPaymentToProto(payment)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// RefundPayment sends the completed incoming payment, or the part of it,
+// back to the given receipt, or to the refund address attached to the
+// receipt of the payment. Refunds of the payment couldn't exceed its
+// amount, refund is linked to the refunded payment for the audit.
+func (s *Server) RefundPayment(ctx context.Context,
+	req *RefundPaymentRequest) (*Payment, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.PaymentId == "" {
+		err := newErrInvalidArgument("payment_id")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	var amount decimal.Decimal
+	if req.Amount != "" {
+		var err error
+		amount, err = decimal.NewFromString(req.Amount)
+		if err != nil || amount.Sign() <= 0 {
+			err := newErrInvalidArgument("amount")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+	}
+
+	s.refundMtx.Lock()
+	defer s.refundMtx.Unlock()
+
+	stop := trackStage(ctx, stageDB)
+	payment, err := s.paymentsStore.PaymentByID(req.PaymentId)
+	stop()
+	if err != nil {
+		if err == connectors.PaymentNotFound {
+			err = newErrInvalidArgument("payment_id")
+		} else {
+			err = newErrInternal(err.Error())
+		}
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Only the money which has been actually received could be returned,
+	// quarantined payment is returned only after the review.
+	if payment.Direction != connectors.Incoming ||
+		payment.Status != connectors.Completed ||
+		payment.Quarantine == connectors.Quarantined ||
+		payment.Quarantine == connectors.QuarantineReturned {
+		err := newErrInvalidArgument("payment_id")
+		log.Errorf("command(%v), id(%v), error: %v, payment(%v) is %v %v, "+
+			"quarantine(%v)", common.GetFunctionName(), requestID, err,
+			payment.PaymentID, payment.Status, payment.Direction,
+			payment.Quarantine)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop = trackStage(ctx, stageDB)
+	refunds, err := s.paymentsStore.PaymentRefunds(payment.PaymentID)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	refundable := refundableAmount(payment, refunds)
+	if req.Amount == "" {
+		amount = refundable
+	}
+
+	if amount.Sign() <= 0 || amount.GreaterThan(refundable) {
+		err := newErrInvalidArgument("amount")
+		log.Errorf("command(%v), id(%v), error: %v, amount(%v) exceeds "+
+			"refundable amount(%v) of payment(%v)", common.GetFunctionName(),
+			requestID, err, amount, refundable, payment.PaymentID)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	asset, err := convertAssetToProto(payment.Asset)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	media, err := convertMediaToProto(payment.Media)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Without the given receipt refund is sent to the address supplied by
+	// the payer, which is always the blockchain one.
+	receipt := req.Receipt
+	if receipt == "" {
+		receipt, err = s.paymentRefundAddress(ctx, payment)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		if receipt == "" {
+			err := newErrInvalidArgument("receipt")
+			log.Errorf("command(%v), id(%v), error: %v, refund address "+
+				"isn't attached to receipt of payment(%v)",
+				common.GetFunctionName(), requestID, err, payment.PaymentID)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		media = Media_BLOCKCHAIN
+	}
+
+	// Refund is sent as the regular payment, so that it goes through the
+	// same limits and pre-send hook, and belongs to the account of the
+	// refunded payment.
+	resp, err := s.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   asset,
+		Media:   media,
+		Receipt: receipt,
+		Amount:  amount.String(),
+		Memo:    req.Memo,
+		Account: payment.AccountID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Refund has been already sent, failure to link it is only reported,
+	// so that it is fixed by the operator.
+	stop = trackStage(ctx, stageDB)
+	err = s.paymentsStore.SetPaymentRefund(resp.PaymentId, payment.PaymentID)
+	stop()
+	if err != nil {
+		log.Errorf("command(%v), id(%v), unable to link refund(%v) to "+
+			"payment(%v): %v", common.GetFunctionName(), requestID,
+			resp.PaymentId, payment.PaymentID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+	} else {
+		resp.RefundOf = payment.PaymentID
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// CancelPayment aborts the outgoing blockchain payment which is still
+// waiting to be sent, i.e. which transaction hasn't been broadcasted yet,
+// and releases the funds reserved for it. Cancelled payment is marked as
+// failed.
+func (s *Server) CancelPayment(ctx context.Context,
+	req *CancelPaymentRequest) (*Payment, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.PaymentId == "" {
+		err := newErrInvalidArgument("payment_id")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageDB)
+	payment, err := s.paymentsStore.PaymentByID(req.PaymentId)
+	stop()
+	if err != nil {
+		if err == connectors.PaymentNotFound {
+			err = newErrInvalidArgument("payment_id")
+		} else {
+			err = newErrInternal(err.Error())
+		}
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Payment is cancelled on behalf of its account, so payment of the
+	// account of another tenant couldn't be cancelled.
+	if err := s.checkAccountTenant(ctx, payment.AccountID); err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Only the connectors which create the payment before sending it
+	// could have the payment which hasn't been broadcasted yet.
+	var canceler connectors.PaymentCanceler
+	if payment.Media == connectors.Blockchain {
+		if c, ok := s.blockchainConnectors[payment.Asset]; ok {
+			canceler, _ = c.(connectors.PaymentCanceler)
+		}
+	}
+
+	if payment.Direction != connectors.Outgoing ||
+		payment.Status != connectors.Waiting || canceler == nil {
+		err := newErrInvalidArgument("payment_id")
+		log.Errorf("command(%v), id(%v), error: %v, payment(%v) of "+
+			"asset(%v) and media(%v) is %v %v", common.GetFunctionName(),
+			requestID, err, payment.PaymentID, payment.Asset, payment.Media,
+			payment.Status, payment.Direction)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop = trackStage(ctx, stageNode)
+	payment, err = canceler.CancelPayment(payment.PaymentID)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Payment(%v) has been cancelled", payment.PaymentID)
+
+	resp, err := convertPaymentToProto(payment)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// PaymentsByReceipt is used to fetch the information about payment, by the
+// given receipt.
+func (s *Server) PaymentsByReceipt(ctx context.Context,
+	req *PaymentsByReceiptRequest) (*PaymentsByReceiptResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	// Payments are stored with the canonical form of the receipt, and
+	// because request doesn't specify the asset, all of its forms are
+	// looked up.
+	var payments []*connectors.Payment
+	found := make(map[string]bool)
+	for _, receipt := range s.receiptForms(req.Receipt) {
+		stop := trackStage(ctx, stageDB)
+		receiptPayments, err := s.paymentsStore.PaymentByReceipt(receipt)
+		stop()
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		for _, payment := range receiptPayments {
+			if !found[payment.PaymentID] {
+				found[payment.PaymentID] = true
+				payments = append(payments, payment)
+			}
+		}
+	}
+
+	var protoPayments []*Payment
+	for _, payment := range payments {
+		protoPayment, err := convertPaymentToProto(payment)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+		includePaymentFields(protoPayment, payment, req.Include)
+
+		protoPayments = append(protoPayments, protoPayment)
+	}
+
+	resp := &PaymentsByReceiptResponse{
+		Payments: protoPayments,
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// receiptForms returns the given receipt along with its distinct canonical
+// forms for every enabled asset and media, because the same string might be
+// the receipt of several assets, e.g. legacy address of bitcoin and bitcoin
+// cash.
+func (s *Server) receiptForms(receipt string) []string {
+	forms := []string{receipt}
+	seen := map[string]bool{receipt: true}
+
+	add := func(asset connectors.Asset, media connectors.PaymentMedia) {
+		form := connectors.NormalizeReceipt(asset, media, receipt)
+		if !seen[form] {
+			seen[form] = true
+			forms = append(forms, form)
+		}
+	}
+
+	for asset := range s.blockchainConnectors {
+		add(asset, connectors.Blockchain)
+	}
+
+	for asset := range s.lightningConnectors {
+		add(asset, connectors.Lightning)
+	}
+
+	return forms
+}
+
+//
+// ListPayments returns list of payment which were registered by the
+// system.
+func (s *Server) ListPayments(ctx context.Context,
+	req *ListPaymentsRequest) (*ListPaymentsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	payments, total, err := s.fetchPayments(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	var protoPayments []*Payment
+	for _, payment := range payments {
+		protoPayment, err := convertPaymentToProto(payment)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+		includePaymentFields(protoPayment, payment, req.Include)
+
+		protoPayments = append(protoPayments, protoPayment)
+	}
+
+	resp := &ListPaymentsResponse{
+		Payments: protoPayments,
+		Total:    uint64(total),
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// streamPageSize is the number of payments which are read from the store at
+// once by StreamPayments and ExportPayments.
+const streamPageSize = 1000
+
+// walkPayments reads payments from the store page by page, so that the
+// whole list isn't loaded in memory, and calls the handler for every
+// payment. Limit of the query, if any, bounds the overall number of walked
+// payments. Returns number of walked payments.
+func (s *Server) walkPayments(query connectors.PaymentsQuery,
+	handler func(payment *connectors.Payment) error) (int, error) {
+
+	limit := query.Limit
+	walked := 0
+
+	for limit == 0 || walked < limit {
+		query.Limit = streamPageSize
+		if limit != 0 && limit-walked < streamPageSize {
+			query.Limit = limit - walked
+		}
+
+		payments, _, err := s.paymentsStore.QueryPayments(query)
+		if err != nil {
+			return walked, err
+		}
+
+		for _, payment := range payments {
+			if err := handler(payment); err != nil {
+				return walked, err
+			}
+			walked++
+		}
+
+		query.Offset += len(payments)
+
+		if len(payments) < query.Limit {
+			break
+		}
+	}
+
+	return walked, nil
+}
+
+//
+// StreamPayments is the streaming variant of ListPayments, which sends
+// payments one by one, so that big lists are not limited by the
+// maximum size of the message.
+func (s *Server) StreamPayments(req *ListPaymentsRequest,
+	stream PayServer_StreamPaymentsServer) error {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	query, err := s.paymentsQuery(stream.Context(), req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return err
+	}
+
+	var sendErr error
+	sent, err := s.walkPayments(query, func(payment *connectors.Payment) error {
+		protoPayment, err := convertPaymentToProto(payment)
+		if err != nil {
+			return err
+		}
+		includePaymentFields(protoPayment, payment, req.Include)
+
+		if err := stream.Send(protoPayment); err != nil {
+			sendErr = err
+			return err
+		}
+
+		return nil
+	})
+	switch {
+	case sendErr != nil:
+		log.Errorf("command(%v), id(%v), unable to send payment: %v",
+			common.GetFunctionName(), requestID, sendErr)
+		return sendErr
+
+	case err != nil:
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return err
+	}
+
+	// Payments are not logged one by one, because the number of them
+	// might be huge.
+	log.Tracef("command(%v), id(%v), response(%v payments)",
+		common.GetFunctionName(), requestID, sent)
+
+	return nil
+}
+
+//
+// SubscribePayments sends payment every time its state is changed, e.g.
+// when it is transitioned from waiting to pending and to completed or
+// failed, so that clients don't have to poll ListPayments.
+func (s *Server) SubscribePayments(req *SubscribePaymentsRequest,
+	stream PayServer_SubscribePaymentsServer) error {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	subscriber, ok := s.paymentsStore.(connectors.PaymentsSubscriber)
+	if !ok {
+		err := newErrInternal("payments store doesn't support subscriptions")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return err
+	}
+
+	var (
+		asset     connectors.Asset
+		media     connectors.PaymentMedia
+		direction connectors.PaymentDirection
+		err       error
+	)
+
+	if req.Asset != Asset_ASSET_NONE {
+		asset, err = ConvertAssetFromProto(req.Asset)
+		if err != nil {
+			err := newErrInvalidArgument("asset")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return err
+		}
+	}
+
+	if req.Media != Media_MEDIA_NONE {
+		media, err = ConvertMediaFromProto(req.Media)
+		if err != nil {
+			err := newErrInvalidArgument("media")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return err
+		}
+	}
+
+	if req.Direction != PaymentDirection_DIRECTION_NONE {
+		direction, err = ConvertPaymentDirectionFromProto(req.Direction)
+		if err != nil {
+			err := newErrInvalidArgument("direction")
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return err
+		}
+	}
+
+	if req.MinConfirmations < 0 {
+		err := newErrInvalidArgument("min_confirmations")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return err
+	}
+
+	// Updates of the payments which haven't reached the settlement depth
+	// of the subscriber are filtered here, so that subscriber doesn't have
+	// to count the confirmations on its own.
+	finality := connectors.Finality{
+		MinConfirmations: req.MinConfirmations,
+		TerminalOnly:     req.TerminalOnly,
+	}
+
+	subscription := subscriber.SubscribePayments()
+	defer subscription.Cancel()
+
+	for {
+		select {
+		case payment, ok := <-subscription.Updates:
+			if !ok {
+				err := newErrInternal("subscriber is unable to keep up " +
+					"with the updates, resubscribe")
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(),
+					string(metrics.LowSeverity))
+				return err
+			}
+
+			if (asset != "" && payment.Asset != asset) ||
+				(media != "" && payment.Media != media) ||
+				(direction != "" && payment.Direction != direction) ||
+				!finality.Reached(payment) {
+				continue
+			}
+
+			protoPayment, err := convertPaymentToProto(payment)
+			if err != nil {
+				err := newErrInternal(err.Error())
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(),
+					string(metrics.LowSeverity))
+				return err
+			}
+			includePaymentFields(protoPayment, payment, req.Include)
+
+			if err := stream.Send(protoPayment); err != nil {
+				log.Errorf("command(%v), id(%v), unable to send payment: %v",
+					common.GetFunctionName(), requestID, err)
+				return err
+			}
+
+		case <-stream.Context().Done():
+			log.Tracef("command(%v), id(%v), subscription is closed by "+
+				"client", common.GetFunctionName(), requestID)
+			return nil
+		}
+	}
+}
+
+// fetchPayments returns payments which are matching filter, sort and page
+// parameters of the request, along with the overall number of payments
+// which are matching the filter.
+func (s *Server) fetchPayments(ctx context.Context,
+	req *ListPaymentsRequest) ([]*connectors.Payment, int, error) {
+	query, err := s.paymentsQuery(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stop := trackStage(ctx, stageDB)
+	payments, total, err := s.paymentsStore.QueryPayments(query)
+	stop()
+	if err != nil {
+		return nil, 0, newErrInternal(err.Error())
+	}
+
+	return payments, total, nil
+}
+
+// paymentsQuery converts the request to the store query, payments of the
+// account which is filtered by the request should be accessible to the
+// caller.
+func (s *Server) paymentsQuery(ctx context.Context,
+	req *ListPaymentsRequest) (connectors.PaymentsQuery, error) {
+	query, err := convertPaymentsQuery(req)
+	if err != nil {
+		return query, err
+	}
+
+	if err := s.checkAccountTenant(ctx, query.AccountID); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+// convertPaymentsQuery converts filter, sort and page parameters of the
+// request to the store query.
+func convertPaymentsQuery(req *ListPaymentsRequest) (connectors.PaymentsQuery,
+	error) {
+	var (
+		query connectors.PaymentsQuery
+		err   error
+	)
+
+	if req.Asset != Asset_ASSET_NONE {
+		query.Asset, err = ConvertAssetFromProto(req.Asset)
+		if err != nil {
+			return query, newErrInternal(err.Error())
+		}
+	}
+
+	if req.Direction != PaymentDirection_DIRECTION_NONE {
+		query.Direction, err = ConvertPaymentDirectionFromProto(req.Direction)
+		if err != nil {
+			return query, newErrInternal(err.Error())
+		}
+	}
+
+	if req.System != PaymentSystem_SYSTEM_NONE {
+		query.System, err = ConvertPaymentSystemFromProto(req.System)
+		if err != nil {
+			return query, newErrInternal(err.Error())
+		}
+	}
+
+	if req.Status != PaymentStatus_STATUS_NONE {
+		query.Status, err = ConvertPaymentStatusFromProto(req.Status)
+		if err != nil {
+			return query, newErrInternal(err.Error())
+		}
+	}
+
+	if req.Media != Media_MEDIA_NONE {
+		query.Media, err = ConvertMediaFromProto(req.Media)
+		if err != nil {
+			return query, newErrInternal(err.Error())
+		}
+	}
+
+	query.Quarantine, err = ConvertQuarantineStateFromProto(req.Quarantine)
+	if err != nil {
+		return query, newErrInvalidArgument("quarantine")
+	}
+
+	query.SortBy, err = ConvertPaymentsSortFromProto(req.SortBy)
+	if err != nil {
+		return query, newErrInvalidArgument("sort_by")
+	}
+
+	if req.MinAmount != "" {
+		query.MinAmount, err = decimal.NewFromString(req.MinAmount)
+		if err != nil || query.MinAmount.Sign() < 0 {
+			return query, newErrInvalidArgument("min_amount")
+		}
+	}
+
+	if req.MaxAmount != "" {
+		query.MaxAmount, err = decimal.NewFromString(req.MaxAmount)
+		if err != nil || query.MaxAmount.Sign() < 0 {
+			return query, newErrInvalidArgument("max_amount")
+		}
+	}
+
+	if req.FromTime < 0 {
+		return query, newErrInvalidArgument("from_time")
+	}
+
+	if req.ToTime < 0 {
+		return query, newErrInvalidArgument("to_time")
+	}
+
+	if !isValidAccount(req.Account) {
+		return query, newErrInvalidArgument("account")
+	}
+
+	query.AccountID = req.Account
+	query.UpdatedFrom = req.FromTime
+	query.UpdatedTo = req.ToTime
+	query.SinceSequence = req.SinceSequence
+	query.Ascending = req.Ascending
+	query.Offset = int(req.Offset)
+	query.Limit = int(req.Limit)
+
+	return query, nil
+}
+
+// newPayee validates the payee preset and normalizes its receipt.
+func (s *Server) newPayee(ctx context.Context,
+	req *Payee) (*connectors.Payee, error) {
+
+	if req.Name == "" {
+		return nil, newErrInvalidArgument("name")
+	}
+
+	asset, err := ConvertAssetFromProto(req.Asset)
+	if err != nil || asset == "" {
+		return nil, newErrInvalidArgument("asset")
+	}
+
+	media, err := ConvertMediaFromProto(req.Media)
+	if err != nil || media == "" {
+		return nil, newErrInvalidArgument("media")
+	}
+
+	amount := decimal.Zero
+	if req.Amount != "" {
+		amount, err = decimal.NewFromString(req.Amount)
+		if err != nil || amount.Sign() < 0 {
+			return nil, newErrInvalidArgument("amount")
+		}
+	}
+
+	// Receipt is validated and normalized at the moment of creation of the
+	// preset, so that mistake would be found before the actual payment.
+	receipt := req.Receipt
+	switch req.Media {
+	case Media_BLOCKCHAIN:
+		c, ok := s.blockchainConnectors[asset]
+		if !ok {
+			return nil, newErrAssetNotSupported(req.Asset.String(),
+				req.Media.String())
+		}
+
+		stop := trackStage(ctx, stageNode)
+		addressInfo, err := c.ValidateAddress(req.Receipt)
+		stop()
+		if err != nil {
+			return nil, newErrInvalidArgument("receipt")
+		}
+
+		receipt = addressInfo.Address
+
+	case Media_LIGHTNING:
+		c, ok := s.lightningConnectors[asset]
+		if !ok {
+			return nil, newErrAssetNotSupported(req.Asset.String(),
+				req.Media.String())
+		}
+
+		if _, err := c.ValidateInvoice(req.Receipt, amount.String()); err != nil {
+			return nil, newErrInvalidArgument("receipt")
+		}
+
+		receipt = strings.ToLower(req.Receipt)
+	}
+
+	return &connectors.Payee{
+		Name:    req.Name,
+		Asset:   asset,
+		Media:   media,
+		Receipt: receipt,
+		Amount:  amount,
+	}, nil
+}
+
+//
+// SetPayee creates or updates the payee preset, which could be used
+// later in the send payment request instead of the raw receipt.
+func (s *Server) SetPayee(ctx context.Context, req *Payee) (*EmptyResponse,
+	error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	payee, err := s.newPayee(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if err := s.payeesStore.SavePayee(payee); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &EmptyResponse{}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// RemovePayee removes the payee preset by its name.
+func (s *Server) RemovePayee(ctx context.Context,
+	req *RemovePayeeRequest) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if err := s.payeesStore.RemovePayee(req.Name); err != nil {
+		if err == connectors.PayeeNotFound {
+			err = newErrInvalidArgument("name")
+		} else {
+			err = newErrInternal(err.Error())
+		}
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &EmptyResponse{}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListPayees returns list of all registered payee presets.
+func (s *Server) ListPayees(ctx context.Context,
+	req *EmptyRequest) (*ListPayeesResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	stop := trackStage(ctx, stageDB)
+	payees, err := s.payeesStore.ListPayees()
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	var protoPayees []*Payee
+	for _, payee := range payees {
+		protoPayee, err := convertPayeeToProto(payee)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		protoPayees = append(protoPayees, protoPayee)
+	}
+
+	resp := &ListPayeesResponse{
+		Payees: protoPayees,
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// SetBranding creates or updates the branding of the hosted checkout
+// and QR pages of the tenant, so that they could be used by the
+// white-label partners.
+func (s *Server) SetBranding(ctx context.Context, req *Branding) (
+	*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.Tenant != "" {
+		stop := trackStage(ctx, stageDB)
+		_, err := s.apiKeysStore.APIKeyByID(req.Tenant)
+		stop()
+		if err != nil {
+			if err == connectors.APIKeyNotFound {
+				err = newErrInvalidArgument("tenant")
+			} else {
+				err = newErrInternal(err.Error())
+			}
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+	}
+
+	// Logo is loaded by the page itself, so that it should be served
+	// over https in order to not break the secure page.
+	if !isValidBrandingURL(req.LogoUrl, false) {
+		err := newErrInvalidArgument("logo_url")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if !isValidBrandingURL(req.SuccessUrl, true) {
+		err := newErrInvalidArgument("success_url")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if !isValidColor(req.PrimaryColor) {
+		err := newErrInvalidArgument("primary_color")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if !isValidColor(req.AccentColor) {
+		err := newErrInvalidArgument("accent_color")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageDB)
+	err := s.brandingStore.SaveBranding(&connectors.Branding{
+		Tenant:       req.Tenant,
+		LogoURL:      req.LogoUrl,
+		PrimaryColor: strings.ToLower(req.PrimaryColor),
+		AccentColor:  strings.ToLower(req.AccentColor),
+		SuccessURL:   req.SuccessUrl,
+	})
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &EmptyResponse{}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// RemoveBranding removes the branding of the tenant, after that default
+// branding is used.
+func (s *Server) RemoveBranding(ctx context.Context,
+	req *RemoveBrandingRequest) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	stop := trackStage(ctx, stageDB)
+	err := s.brandingStore.RemoveBranding(req.Tenant)
+	stop()
+	if err != nil {
+		if err == connectors.BrandingNotFound {
+			err = newErrInvalidArgument("tenant")
+		} else {
+			err = newErrInternal(err.Error())
+		}
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &EmptyResponse{}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// AddWatchAddress starts tracking activity of the address which doesn't
+// belong to us, e.g. old legacy wallet or address of the attacker.
+// Activity on this address produces watch event with group label.
+// Funding of the address is also sent by SubscribePayments as the
+// received payment with the watch_group metadata.
+func (s *Server) AddWatchAddress(ctx context.Context,
+	req *WatchAddress) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.Group == "" {
+		err := newErrInvalidArgument("group")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageNode)
+	addressInfo, err := c.ValidateAddress(req.Address)
+	stop()
+	if err != nil {
+		err := newErrInvalidArgument("address")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if err := c.WatchAddress(addressInfo.Address); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	err = s.watchStore.SaveWatchAddress(&connectors.WatchAddress{
+		Group:    req.Group,
+		Asset:    connectors.Asset(req.Asset.String()),
+		Address:  addressInfo.Address,
+		Account:  req.Account,
+		Inactive: req.Inactive,
+	})
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &EmptyResponse{}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// maxImportWatchAddresses is the maximum number of the addresses in
+// ImportWatchAddresses, so that import fits into one request.
+const maxImportWatchAddresses = 10000
+
+//
+// ImportWatchAddresses adds the list of externally generated addresses,
+// e.g. deposit addresses of the partner, to the watched ones. Import is
+// idempotent, addresses which are already watched are updated with the
+// given group, account and activation status. Import is atomic, if any of
+// the addresses is invalid nothing is imported.
+func (s *Server) ImportWatchAddresses(ctx context.Context,
+	req *ImportWatchAddressesRequest) (*ImportWatchAddressesResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if len(req.Addresses) == 0 ||
+		len(req.Addresses) > maxImportWatchAddresses {
+		err := newErrInvalidArgument("addresses")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Addresses are validated before anything is watched, so that invalid
+	// entry doesn't leave the list imported partially. The same address
+	// might be listed twice, in this case the last entry wins.
+	var addresses []*connectors.WatchAddress
+	positions := make(map[string]int)
+	for i, protoAddress := range req.Addresses {
+		if protoAddress.Group == "" {
+			err := newErrInvalidArgument(fmt.Sprintf("addresses[%v].group", i))
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		asset := connectors.Asset(protoAddress.Asset.String())
+		c, ok := s.blockchainConnectors[asset]
+		if !ok {
+			err := newErrAssetNotSupported(protoAddress.Asset.String(),
+				Media_BLOCKCHAIN.String())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		stop := trackStage(ctx, stageNode)
+		addressInfo, err := c.ValidateAddress(protoAddress.Address)
+		stop()
+		if err != nil {
+			err := newErrInvalidArgument(fmt.Sprintf("addresses[%v].address", i))
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		address := &connectors.WatchAddress{
+			Group:    protoAddress.Group,
+			Asset:    asset,
+			Address:  addressInfo.Address,
+			Account:  protoAddress.Account,
+			Inactive: protoAddress.Inactive,
+		}
+
+		key := string(asset) + ":" + address.Address
+		if pos, ok := positions[key]; ok {
+			addresses[pos] = address
+			continue
+		}
+
+		positions[key] = len(addresses)
+		addresses = append(addresses, address)
+	}
+
+	for _, address := range addresses {
+		stop := trackStage(ctx, stageNode)
+		c := s.blockchainConnectors[address.Asset]
+		err := c.WatchAddress(address.Address)
+		stop()
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+	}
+
+	stop := trackStage(ctx, stageDB)
+	added, updated, err := s.watchStore.ImportWatchAddresses(addresses)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &ImportWatchAddressesResponse{
+		Added:     uint32(added),
+		Updated:   uint32(updated),
+		Unchanged: uint32(len(addresses) - added - updated),
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// RemoveWatchAddress stops tracking activity of the address.
+func (s *Server) RemoveWatchAddress(ctx context.Context,
+	req *RemoveWatchAddressRequest) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	address := req.Address
+
+	// Address is stored in the canonical form, that is why we try to
+	// normalize it, but connector might be disabled since address has been
+	// added.
+	c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
+	if ok {
+		if addressInfo, err := c.ValidateAddress(req.Address); err == nil {
+			address = addressInfo.Address
+		}
+	}
+
+	err := s.watchStore.RemoveWatchAddress(
+		connectors.Asset(req.Asset.String()), address)
+	if err != nil {
+		if err == connectors.WatchAddressNotFound {
+			err = newErrInvalidArgument("address")
+		} else {
+			err = newErrInternal(err.Error())
+		}
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &EmptyResponse{}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// RedeliverWatchEvents sends again the already delivered watch events of
+// the group, which were detected within the time range, so that listener
+// could recover after the outage on its side. Events are delivered in the
+// background, in the order they were detected, and are marked as
+// redelivery in the webhook body.
+func (s *Server) RedeliverWatchEvents(ctx context.Context,
+	req *RedeliverWatchEventsRequest) (*RedeliverWatchEventsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.CreatedFrom < 0 {
+		err := newErrInvalidArgument("created_from")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if req.CreatedTo < 0 || (req.CreatedTo != 0 &&
+		req.CreatedTo < req.CreatedFrom) {
+		err := newErrInvalidArgument("created_to")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageDB)
+	n, err := s.watchStore.RedeliverWatchEvents(req.Group, req.CreatedFrom,
+		req.CreatedTo)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("%v watch events of group(%v) created in [%v, %v] are "+
+		"scheduled for redelivery", n, req.Group, req.CreatedFrom,
+		req.CreatedTo)
+
+	resp := &RedeliverWatchEventsResponse{
+		Events: uint32(n),
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListWatchAddresses returns list of the watched addresses.
+func (s *Server) ListWatchAddresses(ctx context.Context,
+	req *ListWatchAddressesRequest) (*ListWatchAddressesResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	asset, err := ConvertAssetFromProto(req.Asset)
+	if err != nil {
+		err := newErrInvalidArgument("asset")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageDB)
+	addresses, err := s.watchStore.ListWatchAddresses(asset, req.Group)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	var protoAddresses []*WatchAddress
+	for _, address := range addresses {
+		protoAddress, err := convertWatchAddressToProto(address)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		protoAddresses = append(protoAddresses, protoAddress)
+	}
+
+	resp := &ListWatchAddressesResponse{
+		Addresses: protoAddresses,
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListWatchEvents returns list of events which were produced by the
+// activity on watched addresses.
+func (s *Server) ListWatchEvents(ctx context.Context,
+	req *ListWatchEventsRequest) (*ListWatchEventsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	stop := trackStage(ctx, stageDB)
+	events, err := s.watchStore.ListWatchEvents(req.Group)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	var protoEvents []*WatchEvent
+	for _, event := range events {
+		protoEvent, err := convertWatchEventToProto(event)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		protoEvents = append(protoEvents, protoEvent)
+	}
+
+	resp := &ListWatchEventsResponse{
+		Events: protoEvents,
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// GetPublicKeys returns public keys of the server identity, which are used
+// to verify signatures of the webhook bodies and REST responses.
+func (s *Server) GetPublicKeys(ctx context.Context,
+	req *EmptyRequest) (*GetPublicKeysResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp := &GetPublicKeysResponse{}
+	if s.identityKey != nil {
+		resp.Keys = append(resp.Keys, &PublicKey{
+			KeyId:     s.identityKey.ID(),
+			Algorithm: identity.Algorithm,
+			PublicKey: hex.EncodeToString(s.identityKey.PublicKey()),
+		})
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// GetInfo returns the version of the server, enabled assets and media,
+// and the sync state of their daemons, so that clients could detect
+// supported features and monitor the server.
+func (s *Server) GetInfo(ctx context.Context,
+	req *EmptyRequest) (*GetInfoResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp := &GetInfoResponse{
+		Network: s.net,
+	}
+
+	if s.info != nil {
+		resp.Version = s.info.Version
+	}
+
+	stop := trackStage(ctx, stageNode)
+	resp.Connectors = s.connectorsInfo()
+	stop()
+
+	resp.SelfTests = s.selfTests.last()
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// connectorsInfo returns the state of the daemons of the enabled
+// connectors. Failure to reach the daemon is reported in the info of the
+// connector, so that state of the others is still returned.
+func (s *Server) connectorsInfo() []*ConnectorInfo {
+	var infos []*ConnectorInfo
+
+	for asset, c := range s.blockchainConnectors {
+		protoAsset, _ := convertAssetToProto(asset)
+		info := &ConnectorInfo{
+			Asset: protoAsset,
+			Media: Media_BLOCKCHAIN,
+		}
+
+		if reporter, ok := c.(connectors.ChainReporter); ok {
+			state, err := reporter.ChainState()
+			if err != nil {
+				info.Error = err.Error()
+			} else {
+				info.Height = state.Height
+				info.NetworkHeight = state.NetworkHeight
+				info.Synced = state.Synced
+			}
+		}
+
+		infos = append(infos, info)
+	}
+
+	for asset, c := range s.lightningConnectors {
+		protoAsset, _ := convertAssetToProto(asset)
+		info := &ConnectorInfo{
+			Asset: protoAsset,
+			Media: Media_LIGHTNING,
+		}
+
+		nodeInfo, err := c.Info()
+		if err != nil {
+			info.Error = err.Error()
+		} else {
+			info.Height = int64(nodeInfo.BlockHeight)
+			info.Synced = nodeInfo.SyncedToChain
+			info.Node = &LightningNodeInfo{
+				Pubkey:            nodeInfo.IdentityPubkey,
+				Alias:             nodeInfo.Alias,
+				Version:           nodeInfo.Version,
+				Host:              nodeInfo.Host,
+				Port:              nodeInfo.Port,
+				NumPeers:          nodeInfo.NumPeers,
+				NumActiveChannels: nodeInfo.NumActiveChannels,
+			}
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Media != infos[j].Media {
+			return infos[i].Media < infos[j].Media
+		}
+		return infos[i].Asset < infos[j].Asset
+	})
+
+	return infos
+}
+
+//
+// Assets returns the parameters of every enabled asset: precision of the
+// amounts, limits of the payment amount, dust limit, required
+// confirmations and available media, so that clients don't have to
+// hardcode them.
+func (s *Server) Assets(ctx context.Context,
+	req *EmptyRequest) (*AssetsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	infos := make(map[connectors.Asset]*AssetInfo)
+	assetInfo := func(asset connectors.Asset) *AssetInfo {
+		info, ok := infos[asset]
+		if !ok {
+			protoAsset, _ := convertAssetToProto(asset)
+			info = &AssetInfo{Asset: protoAsset}
+			infos[asset] = info
+		}
+		return info
+	}
+
+	for asset, c := range s.blockchainConnectors {
+		info := assetInfo(asset)
+		info.Blockchain = true
+
+		reporter, ok := c.(connectors.AssetReporter)
+		if !ok {
+			continue
+		}
+
+		params := reporter.AssetParams()
+		info.Decimals = params.Decimals
+		info.MinAmount = params.MinAmount.String()
+		info.DustLimit = params.DustLimit.String()
+		info.MinConfirmations = params.MinConfirmations
+
+		if !params.MaxAmount.IsZero() {
+			info.MaxAmount = params.MaxAmount.String()
+		}
+	}
+
+	stop := trackStage(ctx, stageNode)
+	for asset, c := range s.lightningConnectors {
+		info := assetInfo(asset)
+		info.Lightning = true
+
+		nodeInfo, err := c.Info()
+		if err != nil {
+			info.Error = err.Error()
+			continue
+		}
+
+		info.LightningMinAmount = nodeInfo.MinAmount
+		info.LightningMaxAmount = nodeInfo.MaxAmount
+	}
+	stop()
+
+	resp := &AssetsResponse{}
+	for _, info := range infos {
+		resp.Assets = append(resp.Assets, info)
+	}
+
+	sort.Slice(resp.Assets, func(i, j int) bool {
+		return resp.Assets[i].Asset < resp.Assets[j].Asset
+	})
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// GetBranding returns the logo, colors and success redirect URL with
+// which the hosted checkout and QR pages of the caller are rendered.
+// Default branding is returned if caller doesn't have its own.
+func (s *Server) GetBranding(ctx context.Context,
+	req *EmptyRequest) (*Branding, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	tenant := apiKeyIDFromContext(ctx)
+
+	stop := trackStage(ctx, stageDB)
+	branding, err := s.brandingStore.BrandingByTenant(tenant)
+	if err == connectors.BrandingNotFound && tenant != "" {
+		branding, err = s.brandingStore.BrandingByTenant("")
+	}
+	stop()
+
+	var resp *Branding
+	switch {
+	case err == connectors.BrandingNotFound:
+		resp = &Branding{}
+
+	case err != nil:
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+
+	default:
+		resp = convertBrandingToProto(branding)
+	}
+
+	// Page is rendered for the caller, that is why branding is returned
+	// as its own even if the default one is used.
+	resp.Tenant = tenant
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// SyncUnspent triggers the sync of the wallet unspent outputs with the
+// blockchain daemon and waits for it to finish. Forced sync resyncs
+// state from the scratch, which is the remedy for the stale state.
+func (s *Server) SyncUnspent(ctx context.Context,
+	req *SyncUnspentRequest) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	syncer, ok := c.(connectors.UnspentSyncer)
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if err := syncer.SyncUnspent(ctx, req.Force); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &EmptyResponse{}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// GetUnspentSyncStatus returns the state of the sync of the wallet
+// unspent outputs, and inconsistencies found against the daemon.
+func (s *Server) GetUnspentSyncStatus(ctx context.Context,
+	req *GetUnspentSyncStatusRequest) (*UnspentSyncStatus, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	syncer, ok := c.(connectors.UnspentSyncer)
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageNode)
+	status, err := syncer.UnspentSyncStatus()
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &UnspentSyncStatus{
+		LastSyncAt:      status.LastSyncAt,
+		UnspentOutputs:  int64(status.UnspentOutputs),
+		Inconsistencies: status.Inconsistencies,
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListUnspent returns the wallet unspent outputs of the asset, so that
+// fragmentation of the wallet could be inspected and failures of the coin
+// selection could be debugged.
+func (s *Server) ListUnspent(ctx context.Context,
+	req *ListUnspentRequest) (*ListUnspentResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	lister, ok := c.(connectors.UnspentLister)
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageNode)
+	outputs, err := lister.ListUnspent()
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	total := decimal.Zero
+	resp := &ListUnspentResponse{}
+	for _, output := range outputs {
+		total = total.Add(output.Amount)
+		resp.Outputs = append(resp.Outputs, &UnspentOutput{
+			TxId:          output.TxID,
+			Vout:          output.Vout,
+			Address:       output.Address,
+			Amount:        output.Amount.String(),
+			Confirmations: output.Confirmations,
+			Locked:        output.Locked,
+		})
+	}
+	resp.Total = total.String()
+
+	// Outputs are not logged one by one, because the number of them
+	// might be huge.
+	log.Tracef("command(%v), id(%v), response(%v outputs, total %v)",
+		common.GetFunctionName(), requestID, len(resp.Outputs), resp.Total)
+
+	return resp, nil
+}
+
+//
+// TransactionByHash returns the details of the wallet transaction of the
+// asset: inputs, outputs, fee, confirmations, block and raw transaction, so
+// that stuck payments could be investigated without access to the daemon.
+func (s *Server) TransactionByHash(ctx context.Context,
+	req *TransactionByHashRequest) (*Transaction, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.TxId == "" {
+		err := newErrInvalidArgument("tx_id")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	asset := connectors.Asset(req.Asset.String())
+
+	c, ok := s.blockchainConnectors[asset]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	inspector, ok := c.(connectors.TransactionInspector)
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageNode)
+	tx, err := inspector.TransactionByHash(req.TxId)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Payments are linked to the transaction, so that it is clear which
+	// of them are stuck along with it.
+	stop = trackStage(ctx, stageDB)
+	payments, _, err := s.paymentsStore.QueryPayments(connectors.PaymentsQuery{
+		Asset:   asset,
+		Media:   connectors.Blockchain,
+		MediaID: tx.TxID,
+	})
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &Transaction{
+		TxId:          tx.TxID,
+		BlockHash:     tx.BlockHash,
+		BlockTime:     tx.BlockTime,
+		Confirmations: tx.Confirmations,
+		Fee:           tx.Fee.String(),
+		RawTx:         tx.RawTx,
+	}
+
+	for _, input := range tx.Inputs {
+		resp.Inputs = append(resp.Inputs, &TransactionInput{
+			TxId: input.TxID,
+			Vout: input.Vout,
+		})
+	}
+
+	for _, output := range tx.Outputs {
+		resp.Outputs = append(resp.Outputs, &TransactionOutput{
+			Vout:    output.Vout,
+			Address: output.Address,
+			Amount:  output.Amount.String(),
+		})
+	}
+
+	for _, payment := range payments {
+		resp.PaymentIds = append(resp.PaymentIds, payment.PaymentID)
+	}
+
+	// Raw transaction is not logged, because it might be huge.
+	log.Tracef("command(%v), id(%v), response(tx %v, %v inputs, %v "+
+		"outputs, payments %v)", common.GetFunctionName(), requestID,
+		resp.TxId, len(resp.Inputs), len(resp.Outputs), resp.PaymentIds)
+
+	return resp, nil
+}
+
+//
+// IsOurAddress reports whether the blockchain address belongs to the wallet
+// of the server, and to which account incoming payments on it are bound, so
+// that misdirected deposits could be traced.
+func (s *Server) IsOurAddress(ctx context.Context,
+	req *IsOurAddressRequest) (*IsOurAddressResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.Address == "" {
+		err := newErrInvalidArgument("address")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	c, ok := s.blockchainConnectors[connectors.Asset(req.Asset.String())]
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(),
+			Media_BLOCKCHAIN.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Addresses are stored in the canonical form, that is why address is
+	// normalized before the lookup.
+	stop := trackStage(ctx, stageNode)
+	info, err := c.ValidateAddress(req.Address)
+	stop()
+	if err != nil {
+		err := newErrInvalidArgument("address")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &IsOurAddressResponse{
+		Address: info.Address,
+	}
+
+	stop = trackStage(ctx, stageDB)
+	address, err := s.receiptsStore.DepositAddressByAddress(info.Address)
+	stop()
+	switch {
+	case err == nil:
+		resp.IsOurs = true
+		resp.Tenant = address.Tenant
+		resp.AccountId = address.AccountID
+		resp.CreatedAt = address.CreatedAt
+
+	case err != connectors.ReceiptNotFound:
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Wallet of the daemon is the source of truth if connector is able to
+	// check it, e.g. change addresses are never issued by the server, but
+	// belong to the wallet.
+	if checker, ok := c.(connectors.AddressOwnershipChecker); ok {
+		stop := trackStage(ctx, stageNode)
+		resp.IsOurs, err = checker.IsOurAddress(info.Address)
+		stop()
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// SweepFunds sends all confirmed funds of the asset wallet to the address,
+// fee is subtracted from the sent amount. It is used on the rotation of the
+// cold storage and on the decommissioning of the node.
+func (s *Server) SweepFunds(ctx context.Context,
+	req *SweepFundsRequest) (*Payment, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.Address == "" {
+		err := newErrInvalidArgument("address")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	asset := connectors.Asset(req.Asset.String())
+	address := req.Address
+
+	var (
+		sweeper          connectors.FundsSweeper
+		confirmedBalance func() (decimal.Decimal, error)
+		ok               bool
+	)
+
+	switch req.Media {
+	case Media_BLOCKCHAIN:
+		c, found := s.blockchainConnectors[asset]
+		if found {
+			sweeper, ok = c.(connectors.FundsSweeper)
+			confirmedBalance = c.ConfirmedBalance
+		}
+
+		// Address is checked before anything is sent, so that funds are
+		// not swept to the address of the other network.
+		if ok {
+			stop := trackStage(ctx, stageNode)
+			info, err := c.ValidateAddress(address)
+			stop()
+			if err != nil {
+				err := newErrInvalidArgument("address")
+				log.Errorf("command(%v), id(%v), error: %v",
+					common.GetFunctionName(), requestID, err)
+				s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+				return nil, err
+			}
+			address = info.Address
+		}
+
+	case Media_LIGHTNING:
+		c, found := s.lightningConnectors[asset]
+		if found {
+			sweeper, ok = c.(connectors.FundsSweeper)
+			confirmedBalance = c.ConfirmedBalance
+		}
+	}
+
+	if !ok {
+		err := newErrAssetNotSupported(req.Asset.String(), req.Media.String())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop := trackStage(ctx, stageNode)
+	balance, err := confirmedBalance()
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Swept amount is known only after the fee is subtracted, that is why
+	// hook receives the confirmed balance, which is its upper bound.
+	media, _ := ConvertMediaFromProto(req.Media)
+	err = s.checkSendHook(ctx, &connectors.PaymentIntent{
+		Method: "SweepFunds",
+		Asset:  asset,
+		Media:  media,
+		Outputs: []*connectors.PaymentOutput{{
+			Address: address,
+			Amount:  balance.String(),
+		}},
+	})
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	stop = trackStage(ctx, stageNode)
+	payment, err := sweeper.SweepFunds(address)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Funds of %v %v swept by payment(%v): address(%v), "+
+		"amount(%v)", req.Asset, req.Media, payment.PaymentID, address,
+		payment.Amount)
+
+	resp, err := convertPaymentToProto(payment)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// SetFeatureFlag replaces the rollout rule of the feature flag, which
+// gates the risky behaviour. Rule is saved in the database and
+// overrides the rule of the config across restarts.
+func (s *Server) SetFeatureFlag(ctx context.Context,
+	req *FeatureFlag) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	rule := features.Rule{
+		Enabled:    req.Enabled,
+		Tenants:    req.Tenants,
+		Percentage: req.Percentage,
+	}
+
+	if err := s.features.Set(features.Flag(req.Name), rule); err != nil {
+		err := newErrInvalidArgument(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Feature flag(%v) rule changed: enabled(%v), tenants(%v), "+
+		"percentage(%v)", req.Name, req.Enabled, req.Tenants, req.Percentage)
+
+	resp := &EmptyResponse{}
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListFeatureFlags returns rollout rules of all known feature flags.
+func (s *Server) ListFeatureFlags(ctx context.Context,
+	req *EmptyRequest) (*ListFeatureFlagsResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	rules := s.features.Rules()
+
+	resp := &ListFeatureFlagsResponse{}
+	for _, flag := range features.KnownFlags {
+		rule := rules[flag]
+		resp.Flags = append(resp.Flags, &FeatureFlag{
+			Name:       string(flag),
+			Enabled:    rule.Enabled,
+			Tenants:    rule.Tenants,
+			Percentage: rule.Percentage,
+		})
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// Diagnose gathers the state of the service which is needed to
+// investigate the problem: sanitized config, versions, health of the
+// connectors, error counters, queue depths and stuck payments.
+func (s *Server) Diagnose(ctx context.Context,
+	req *DiagnoseRequest) (*DiagnoseResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp := &DiagnoseResponse{
+		GoVersion: runtime.Version(),
+		Network:   s.net,
+	}
+
+	if s.info != nil {
+		resp.Version = s.info.Version
+		resp.StartedAt = connectors.ConvertTimeToMilliSeconds(s.info.StartedAt)
+		resp.Config = s.info.Config
+	}
+
+	resp.Connectors = s.connectorsHealth()
+
+	counters, err := metrics.ErrorCounters()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	for _, counter := range counters {
+		errorCount := &ErrorCount{
+			Metric: counter.Name,
+			Count:  uint64(counter.Value),
+		}
+
+		for name, value := range counter.Labels {
+			errorCount.Labels = append(errorCount.Labels, name+"="+value)
+		}
+		sort.Strings(errorCount.Labels)
+
+		resp.Errors = append(resp.Errors, errorCount)
+	}
+
+	resp.Queues = s.queueDepths()
+
+	stuckAfter := time.Hour
+	if req.StuckAfter != 0 {
+		stuckAfter = time.Duration(req.StuckAfter) * time.Second
+	}
+
+	resp.StuckPayments, err = s.stuckPayments(stuckAfter)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// connectorsHealth checks that connectors are able to reach their daemons,
+// by requesting the balance of blockchain connectors and info of the
+// lightning ones.
+func (s *Server) connectorsHealth() []*ConnectorHealth {
+	var health []*ConnectorHealth
+
+	for asset, c := range s.blockchainConnectors {
+		protoAsset, _ := convertAssetToProto(asset)
+		h := &ConnectorHealth{
+			Asset:   protoAsset,
+			Media:   Media_BLOCKCHAIN,
+			Healthy: true,
+		}
+
+		if _, err := c.ConfirmedBalance(); err != nil {
+			h.Healthy = false
+			h.Error = err.Error()
+		}
+
+		if syncer, ok := c.(connectors.UnspentSyncer); ok {
+			status, err := syncer.UnspentSyncStatus()
+			if err != nil {
+				h.Healthy = false
+				h.Error = err.Error()
+			} else {
+				h.UnspentSync = &UnspentSyncStatus{
+					LastSyncAt:      status.LastSyncAt,
+					UnspentOutputs:  int64(status.UnspentOutputs),
+					Inconsistencies: status.Inconsistencies,
+				}
+			}
+		}
+
+		health = append(health, h)
+	}
+
+	for asset, c := range s.lightningConnectors {
+		protoAsset, _ := convertAssetToProto(asset)
+		h := &ConnectorHealth{
+			Asset:   protoAsset,
+			Media:   Media_LIGHTNING,
+			Healthy: true,
+		}
+
+		if _, err := c.Info(); err != nil {
+			h.Healthy = false
+			h.Error = err.Error()
+		}
+
+		health = append(health, h)
+	}
+
+	sort.Slice(health, func(i, j int) bool {
+		if health[i].Media != health[j].Media {
+			return health[i].Media < health[j].Media
+		}
+		return health[i].Asset < health[j].Asset
+	})
+
+	return health
+}
+
+// queueDepths returns depths of the in-memory queues of the payments store
+// and the connectors.
+func (s *Server) queueDepths() []*QueueDepth {
+	var queues []*QueueDepth
+
+	add := func(prefix string, reporter connectors.QueueReporter) {
+		for name, depth := range reporter.QueueDepths() {
+			queues = append(queues, &QueueDepth{
+				Name:  prefix + name,
+				Depth: uint64(depth),
+			})
+		}
+	}
+
+	if reporter, ok := s.paymentsStore.(connectors.QueueReporter); ok {
+		add("", reporter)
+	}
+
+	for asset, c := range s.blockchainConnectors {
+		if reporter, ok := c.(connectors.QueueReporter); ok {
+			add(strings.ToLower(string(asset))+"_", reporter)
+		}
+	}
+
+	for asset, c := range s.lightningConnectors {
+		if reporter, ok := c.(connectors.QueueReporter); ok {
+			add(strings.ToLower(string(asset))+"_lightning_", reporter)
+		}
+	}
+
+	sort.Slice(queues, func(i, j int) bool {
+		return queues[i].Name < queues[j].Name
+	})
+
+	return queues
+}
+
+// maxStuckPayments is the maximum number of the oldest stuck payments of
+// each status, which are returned in the diagnostics.
+const maxStuckPayments = 100
+
+// stuckPayments returns the oldest payments which are waiting or pending for
+// longer than given duration.
+func (s *Server) stuckPayments(stuckAfter time.Duration) ([]*Payment, error) {
+	deadline := connectors.ConvertTimeToMilliSeconds(time.Now().Add(-stuckAfter))
+
+	var stuck []*Payment
+	for _, status := range []connectors.PaymentStatus{
+		connectors.Waiting,
+		connectors.Pending,
+	} {
+		payments, _, err := s.paymentsStore.QueryPayments(
+			connectors.PaymentsQuery{
+				Status:    status,
+				SortBy:    connectors.SortByUpdatedAt,
+				Ascending: true,
+				Limit:     maxStuckPayments,
+			})
+		if err != nil {
+			return nil, errors.Errorf("unable to query payments: %v", err)
+		}
+
+		for _, payment := range payments {
+			if payment.UpdatedAt > deadline {
+				break
+			}
+
+			p, err := convertPaymentToProto(payment)
+			if err != nil {
+				return nil, errors.Errorf("unable to convert payment: %v",
+					err)
+			}
+
+			stuck = append(stuck, p)
+		}
+	}
+
+	return stuck, nil
+}
+
+// defaultTestPaymentDelay is the time after which injected test payment is
+// completed, if delay isn't specified in the request.
+const defaultTestPaymentDelay = 10 * time.Second
+
+//
+// InjectTestPayment fabricates incoming payment on the given receipt,
+// which is pending at first, and completed after the delay, without
+// moving any coins. It is used to test payment consumers on staging
+// environments, and is available only if test payments are enabled in
+// the config.
+func (s *Server) InjectTestPayment(ctx context.Context,
+	req *InjectTestPaymentRequest) (*Payment, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if !s.testPayments {
+		err := newErrMethodDisabled("InjectTestPayment", "testpayments")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	payment, err := newTestPayment(req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	// Test payment should look exactly as the real one, that is why
+	// receipt is validated and stored in the canonical form.
+	stop := trackStage(ctx, stageNode)
+	payment.Receipt, err = s.normalizeReceipt(payment.Asset, payment.Media,
+		payment.Receipt)
+	stop()
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	payment.PaymentID, err = payment.GenPaymentID()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	delay := defaultTestPaymentDelay
+	if req.CompleteAfter != 0 {
+		delay = time.Duration(req.CompleteAfter) * time.Second
+	}
+
+	// Completion is scheduled in the store before the payment is saved, so
+	// that payment isn't left pending forever if server is restarted.
+	schedule := &connectors.TestPaymentSchedule{
+		PaymentID: payment.PaymentID,
+		CompleteAt: connectors.ConvertTimeToMilliSeconds(
+			time.Now().Add(delay)),
+	}
+	if err := s.testPaymentsStore.ScheduleTestPayment(schedule); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if err := s.paymentsStore.SavePayment(payment); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Test payment(%v) injected: receipt(%v), asset(%v), "+
+		"media(%v), amount(%v)", payment.PaymentID, payment.Receipt,
+		payment.Asset, payment.Media, payment.Amount)
+
+	s.scheduleTestPayment(schedule)
+
+	resp, err := convertPaymentToProto(payment)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// CreateAPIKey creates the key with which downstream service is authorized
+// to call the methods allowed by the key scopes. Key itself is returned only
+// once, server keeps only its hash.
+func (s *Server) CreateAPIKey(ctx context.Context,
+	req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	key, err := newAPIKeyFromRequest(req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	secret, id, err := newAPIKey()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+	key.ID = id
+	key.Hash = hashAPIKey(secret)
+
+	if err := s.apiKeysStore.SaveAPIKey(key); err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	protoKey, err := convertAPIKeyToProto(key)
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("API key(%v) has been created, name(%v), scopes(%v)", key.ID,
+		key.Name, key.Scopes)
+
+	// Response is traced without the key, so that it doesn't appear in the
+	// logs.
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(protoKey))
+
+	return &CreateAPIKeyResponse{
+		ApiKey: protoKey,
+		Key:    secret,
+	}, nil
+}
+
+// newAPIKeyFromRequest validates the request and creates API key without
+// the secret.
+func newAPIKeyFromRequest(req *CreateAPIKeyRequest) (*connectors.APIKey,
+	error) {
+	if req.Name == "" {
+		return nil, newErrInvalidArgument("name")
+	}
+
+	if len(req.Scopes) == 0 {
+		return nil, newErrInvalidArgument("scopes")
+	}
+
+	scopes := make([]connectors.APIKeyScope, len(req.Scopes))
+	for i, protoScope := range req.Scopes {
+		scope, err := ConvertAPIKeyScopeFromProto(protoScope)
+		if err != nil {
+			return nil, newErrInvalidArgument("scopes")
+		}
+		scopes[i] = scope
+	}
+
+	return &connectors.APIKey{
+		Name:      req.Name,
+		Scopes:    scopes,
+		CreatedAt: connectors.NowInMilliSeconds(),
+	}, nil
+}
+
+//
+// RevokeAPIKey revokes the key by its id, so that calls with it are
+// rejected.
+func (s *Server) RevokeAPIKey(ctx context.Context,
+	req *RevokeAPIKeyRequest) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	key, err := s.apiKeysStore.APIKeyByID(req.Id)
+	if err != nil {
+		if err == connectors.APIKeyNotFound {
+			err = newErrInvalidArgument("id")
+		} else {
+			err = newErrInternal(err.Error())
+		}
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	if key.RevokedAt == 0 {
+		key.RevokedAt = connectors.NowInMilliSeconds()
+		if err := s.apiKeysStore.SaveAPIKey(key); err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		log.Infof("API key(%v) has been revoked, name(%v)", key.ID, key.Name)
+	}
+
+	resp := &EmptyResponse{}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListAPIKeys returns the information about all created keys, including
+// revoked ones.
+func (s *Server) ListAPIKeys(ctx context.Context,
+	req *EmptyRequest) (*ListAPIKeysResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	keys, err := s.apiKeysStore.ListAPIKeys()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	resp := &ListAPIKeysResponse{}
+	for _, key := range keys {
+		protoKey, err := convertAPIKeyToProto(key)
+		if err != nil {
+			err := newErrInternal(err.Error())
+			log.Errorf("command(%v), id(%v), error: %v",
+				common.GetFunctionName(), requestID, err)
+			s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+			return nil, err
+		}
+
+		resp.ApiKeys = append(resp.ApiKeys, protoKey)
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+// ResumeTestPayments schedules completion of the test payments which have
+// been injected before the restart, overdue payments are completed right
+// away. It should be called once on start, even if test payments are
+// disabled, so that previously injected ones aren't left pending.
+func (s *Server) ResumeTestPayments() error {
+	schedules, err := s.testPaymentsStore.ListTestPaymentSchedules()
+	if err != nil {
+		return errors.Errorf("unable to list test payments: %v", err)
+	}
+
+	for _, schedule := range schedules {
+		s.scheduleTestPayment(schedule)
+	}
+
+	return nil
+}
+
+// scheduleTestPayment completes the test payment at the scheduled time.
+func (s *Server) scheduleTestPayment(schedule *connectors.TestPaymentSchedule) {
+	delay := time.Duration(schedule.CompleteAt-connectors.NowInMilliSeconds()) *
+		time.Millisecond
+	if delay < 0 {
+		delay = 0
+	}
+
+	time.AfterFunc(delay, func() {
+		if err := s.completeTestPayment(schedule.PaymentID); err != nil {
+			log.Errorf("Unable to complete test payment(%v): %v",
+				schedule.PaymentID, err)
+			return
+		}
+
+		log.Infof("Test payment(%v) completed", schedule.PaymentID)
+	})
+}
+
+// completeTestPayment marks the pending test payment as completed and
+// removes its schedule.
+func (s *Server) completeTestPayment(paymentID string) error {
+	payment, err := s.paymentsStore.PaymentByID(paymentID)
+	switch {
+	case err == connectors.PaymentNotFound:
+		// Payment hasn't been saved, only schedule should be removed.
+	case err != nil:
+		return err
+	case payment.Status == connectors.Pending:
+		payment.Status = connectors.Completed
+		payment.UpdatedAt = connectors.NowInMilliSeconds()
+		payment.Detail = nil
+
+		if err := s.paymentsStore.SavePayment(payment); err != nil {
+			return err
+		}
+	}
+
+	return s.testPaymentsStore.RemoveTestPaymentSchedule(paymentID)
+}
+
+// normalizeReceipt validates the receipt with the connector of the asset
+// and media, and returns its canonical form.
+func (s *Server) normalizeReceipt(asset connectors.Asset,
+	media connectors.PaymentMedia, receipt string) (string, error) {
+
+	switch media {
+	case connectors.Blockchain:
+		c, ok := s.blockchainConnectors[asset]
+		if !ok {
+			return "", newErrAssetNotSupported(string(asset), string(media))
+		}
+
+		addressInfo, err := c.ValidateAddress(receipt)
+		if err != nil {
+			return "", newErrInvalidArgument("receipt")
+		}
+
+		return addressInfo.Address, nil
+
+	case connectors.Lightning:
+		c, ok := s.lightningConnectors[asset]
+		if !ok {
+			return "", newErrAssetNotSupported(string(asset), string(media))
+		}
+
+		if _, err := c.ValidateInvoice(receipt, "0"); err != nil {
+			return "", newErrInvalidArgument("receipt")
+		}
+
+		// Invoice is bech32 encoded, and its canonical form is lowercase.
+		return strings.ToLower(receipt), nil
+
+	default:
+		return "", newErrInvalidArgument("media")
+	}
+}
+
+// intentOutput returns the output of the payment intent which is checked by
+// the send hook. Receipt is normalized, so that it is matched with the
+// destinations of the policy regardless of its form. Amount of the
+// lightning invoice is taken from the invoice if it isn't specified, so
+// that amount limits couldn't be bypassed by the invoice with the amount.
+func (s *Server) intentOutput(asset connectors.Asset,
+	media connectors.PaymentMedia, receipt,
+	amount string) (*connectors.PaymentOutput, error) {
+
+	receipt, err := s.normalizeReceipt(asset, media, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	if media == connectors.Lightning {
+		value, err := decimal.NewFromString(amount)
+		if amount == "" || (err == nil && value.Sign() == 0) {
+			// Connector existence is checked by the normalization.
+			c := s.lightningConnectors[asset]
+			invoice, err := c.ValidateInvoice(receipt, "0")
+			if err != nil {
+				return nil, newErrInvalidArgument("receipt")
+			}
+
+			if invoice.MilliSat != nil {
+				amount = common.Sat2DecAmount(
+					invoice.MilliSat.ToSatoshis()).String()
+			}
+		}
+	}
+
+	return &connectors.PaymentOutput{
+		Address: receipt,
+		Amount:  amount,
+	}, nil
+}
+
+// newTestPayment validates the request and creates pending incoming payment
+// with random media id, so that it couldn't be mixed up with the real one.
+// Receipt should be normalized and payment id generated by the caller.
+func newTestPayment(req *InjectTestPaymentRequest) (*connectors.Payment,
+	error) {
+	if req.Receipt == "" {
+		return nil, newErrInvalidArgument("receipt")
+	}
+
+	if req.Asset == Asset_ASSET_NONE {
+		return nil, newErrInvalidArgument("asset")
+	}
+
+	asset, err := ConvertAssetFromProto(req.Asset)
+	if err != nil {
+		return nil, newErrInvalidArgument("asset")
+	}
+
+	if req.Media == Media_MEDIA_NONE {
+		return nil, newErrInvalidArgument("media")
+	}
+
+	media, err := ConvertMediaFromProto(req.Media)
+	if err != nil {
+		return nil, newErrInvalidArgument("media")
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || amount.Sign() <= 0 {
+		return nil, newErrInvalidArgument("amount")
+	}
+
+	var mediaID [16]byte
+	if _, err := crand.Read(mediaID[:]); err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	payment := &connectors.Payment{
+		UpdatedAt: connectors.NowInMilliSeconds(),
+		Status:    connectors.Pending,
+		Direction: connectors.Incoming,
+		System:    connectors.External,
+		Receipt:   req.Receipt,
+		Asset:     asset,
+		Media:     media,
+		Amount:    amount,
+		MediaFee:  decimal.Zero,
+		MediaID:   "test-" + hex.EncodeToString(mediaID[:]),
+	}
+
+	if media == connectors.Blockchain {
+		payment.Detail = &connectors.BlockchainPendingDetails{
+			ConfirmationsLeft: 1,
+		}
+	}
+
+	return payment, nil
 }
@@ -0,0 +1,1009 @@
+package crpc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateReceiptValidation(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	tests := []struct {
+		name string
+		req  *CreateReceiptRequest
+		arg  string
+	}{
+		{
+			name: "invalid amount",
+			req: &CreateReceiptRequest{
+				Asset:  Asset_BTC,
+				Media:  Media_BLOCKCHAIN,
+				Amount: "one",
+			},
+			arg: "amount",
+		},
+		{
+			name: "negative expiry",
+			req: &CreateReceiptRequest{
+				Asset:  Asset_BTC,
+				Media:  Media_BLOCKCHAIN,
+				Expiry: -1,
+			},
+			arg: "expiry",
+		},
+		{
+			name: "invalid account",
+			req: &CreateReceiptRequest{
+				Asset:   Asset_BTC,
+				Media:   Media_BLOCKCHAIN,
+				Account: "bad account",
+			},
+			arg: "account",
+		},
+		{
+			name: "empty metadata key",
+			req: &CreateReceiptRequest{
+				Asset:    Asset_BTC,
+				Media:    Media_BLOCKCHAIN,
+				Metadata: map[string]string{"": "value"},
+			},
+			arg: "metadata",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := h.client.CreateReceipt(context.Background(), test.req)
+			expectInvalidArgument(t, err, test.arg)
+		})
+	}
+
+	_, err := h.client.CreateReceipt(context.Background(),
+		&CreateReceiptRequest{
+			Asset: Asset_ETH,
+			Media: Media_BLOCKCHAIN,
+		})
+	expected := newErrAssetNotSupported("ETH", "BLOCKCHAIN").Error()
+	if msg := status.Convert(err).Message(); msg != expected {
+		t.Fatalf("wrong error, expected(%v), got(%v)", expected, msg)
+	}
+}
+
+func TestCreateReceipt(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	resp, err := h.client.CreateReceipt(context.Background(),
+		&CreateReceiptRequest{
+			Asset:    Asset_BTC,
+			Media:    Media_BLOCKCHAIN,
+			Amount:   "0.5",
+			Account:  "customer-1",
+			Metadata: map[string]string{"order_id": "1001"},
+		})
+	if err != nil {
+		t.Fatalf("unable to create receipt: %v", err)
+	}
+
+	if resp.Receipt != "btc-address-1" || resp.ReceiptId == "" {
+		t.Fatalf("wrong receipt: %v", resp)
+	}
+
+	receipts, err := h.client.ListReceipts(context.Background(),
+		&ListReceiptsRequest{Status: ReceiptStatus_UNPAID})
+	if err != nil {
+		t.Fatalf("unable to list receipts: %v", err)
+	}
+
+	if receipts.Total != 1 || len(receipts.Receipts) != 1 {
+		t.Fatalf("wrong number of receipts: %v", receipts)
+	}
+
+	receipt := receipts.Receipts[0]
+	if receipt.ReceiptId != resp.ReceiptId || receipt.Amount != "0.5" ||
+		receipt.Metadata["order_id"] != "1001" {
+		t.Fatalf("wrong receipt: %v", receipt)
+	}
+}
+
+func TestNewAddress(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	_, err := h.client.NewAddress(ctx, &NewAddressRequest{
+		Asset: Asset_ETH,
+	})
+	expected := newErrAssetNotSupported("ETH", "BLOCKCHAIN").Error()
+	if msg := status.Convert(err).Message(); msg != expected {
+		t.Fatalf("wrong error, expected(%v), got(%v)", expected, msg)
+	}
+
+	resp, err := h.client.NewAddress(ctx, &NewAddressRequest{
+		Asset:   Asset_BTC,
+		Account: "customer-1",
+	})
+	if err != nil {
+		t.Fatalf("unable to create address: %v", err)
+	}
+
+	if resp.Address != "btc-address-1" {
+		t.Fatalf("wrong address: %v", resp.Address)
+	}
+
+	err = h.payments.SavePayment(&connectors.Payment{
+		PaymentID: "deposit",
+		Status:    connectors.Completed,
+		Direction: connectors.Incoming,
+		System:    connectors.External,
+		Receipt:   resp.Address,
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(0.5),
+		MediaFee:  decimal.Zero,
+	})
+	if err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	payment, err := h.client.PaymentByID(ctx, &PaymentByIDRequest{
+		PaymentId: "deposit",
+	})
+	if err != nil {
+		t.Fatalf("unable to get payment: %v", err)
+	}
+
+	if payment.Account != "customer-1" {
+		t.Fatalf("deposit isn't bound to the account: %v", payment.Account)
+	}
+
+	receipts, err := h.client.ListReceipts(ctx, &ListReceiptsRequest{})
+	if err != nil {
+		t.Fatalf("unable to list receipts: %v", err)
+	}
+
+	if receipts.Total != 0 {
+		t.Fatalf("address shouldn't create the receipt: %v", receipts)
+	}
+}
+
+// timelineTypes returns the types of the events in the timeline of the
+// payment.
+func timelineTypes(payment *Payment) []PaymentEventType {
+	var types []PaymentEventType
+	for _, event := range payment.Timeline {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func TestSendPaymentTransitions(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	_, err := h.client.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "recipient",
+		Amount:  "1",
+		Account: "bad account",
+	})
+	expectInvalidArgument(t, err, "account")
+
+	// Payment is debited from the account, so it should be funded.
+	h.seedPayments(&connectors.Payment{
+		PaymentID: "deposit",
+		UpdatedAt: 1,
+		Status:    connectors.Completed,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "btc-address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.New(2, 0),
+		MediaFee:  decimal.Zero,
+		MediaID:   "deposit",
+		AccountID: "customer-1",
+	})
+
+	sent, err := h.client.SendPayment(ctx, &SendPaymentRequest{
+		Asset:    Asset_BTC,
+		Media:    Media_BLOCKCHAIN,
+		Receipt:  "recipient",
+		Amount:   "1",
+		Account:  "customer-1",
+		Metadata: map[string]string{"order_id": "1001"},
+	})
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	if sent.Status != PaymentStatus_PENDING || sent.Account != "customer-1" ||
+		sent.Metadata["order_id"] != "1001" {
+		t.Fatalf("wrong payment: %v", sent)
+	}
+
+	for _, confirmations := range []int64{1, mockConfirmations} {
+		if err := h.btc.confirm(sent.PaymentId, confirmations); err != nil {
+			t.Fatalf("unable to confirm payment: %v", err)
+		}
+	}
+
+	payment, err := h.client.PaymentByID(ctx, &PaymentByIDRequest{
+		PaymentId: sent.PaymentId,
+	})
+	if err != nil {
+		t.Fatalf("unable to get payment: %v", err)
+	}
+
+	// Account and metadata are kept when payment is updated by the sync.
+	if payment.Status != PaymentStatus_COMPLETED ||
+		payment.Account != "customer-1" ||
+		payment.Metadata["order_id"] != "1001" {
+		t.Fatalf("wrong payment: %v", payment)
+	}
+
+	expected := []PaymentEventType{
+		PaymentEventType_EVENT_CREATED,
+		PaymentEventType_EVENT_BROADCAST,
+		PaymentEventType_EVENT_CONFIRMED,
+		PaymentEventType_EVENT_CONFIRMED,
+		PaymentEventType_EVENT_COMPLETED,
+	}
+	if types := timelineTypes(payment); !reflect.DeepEqual(types, expected) {
+		t.Fatalf("wrong timeline, expected(%v), got(%v)", expected, types)
+	}
+
+	// Payment which has been rejected by the media is failed with the
+	// reason of the rejection.
+	h.btc.sendErr = errors.New("fee is too low")
+	_, err = h.client.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "recipient",
+		Amount:  "1",
+	})
+	if err == nil {
+		t.Fatalf("rejected payment should return error")
+	}
+
+	failed, err := h.client.ListPayments(ctx, &ListPaymentsRequest{
+		Status: PaymentStatus_FAILED,
+	})
+	if err != nil {
+		t.Fatalf("unable to list payments: %v", err)
+	}
+
+	if len(failed.Payments) != 1 {
+		t.Fatalf("wrong number of failed payments: %v", failed)
+	}
+
+	payment, err = h.client.PaymentByID(ctx, &PaymentByIDRequest{
+		PaymentId: failed.Payments[0].PaymentId,
+	})
+	if err != nil {
+		t.Fatalf("unable to get payment: %v", err)
+	}
+
+	last := payment.Timeline[len(payment.Timeline)-1]
+	if last.Type != PaymentEventType_EVENT_FAILED ||
+		last.Details != "fee is too low" {
+		t.Fatalf("wrong failure event: %v", last)
+	}
+}
+
+func TestSendPaymentDryRun(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	quote, err := h.client.QuotePayment(ctx, &QuotePaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Amount:  "1",
+		Receipt: "recipient",
+	})
+	if err != nil {
+		t.Fatalf("unable to quote payment: %v", err)
+	}
+
+	dryRun, err := h.client.SendPayment(ctx, &SendPaymentRequest{
+		QuoteId:  quote.QuoteId,
+		Account:  "customer-1",
+		Metadata: map[string]string{"order_id": "1001"},
+		DryRun:   true,
+	})
+	if err != nil {
+		t.Fatalf("unable to dry run payment: %v", err)
+	}
+
+	if dryRun.PaymentId != "" || dryRun.Status != PaymentStatus_WAITING ||
+		dryRun.Amount != "1" || dryRun.MediaFee != "0.0001" ||
+		dryRun.Receipt != "recipient" || dryRun.Account != "customer-1" ||
+		dryRun.Metadata["order_id"] != "1001" {
+		t.Fatalf("wrong payment: %v", dryRun)
+	}
+
+	// Nothing is sent or saved by the dry run.
+	if h.btc.sent != 0 {
+		t.Fatalf("dry run shouldn't be sent to the media")
+	}
+
+	payments, err := h.client.ListPayments(ctx, &ListPaymentsRequest{})
+	if err != nil {
+		t.Fatalf("unable to list payments: %v", err)
+	}
+
+	if payments.Total != 0 {
+		t.Fatalf("dry run shouldn't be saved: %v", payments)
+	}
+
+	// Funds should be enough to pay the fee as well.
+	_, err = h.client.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "recipient",
+		Amount:  "10",
+		DryRun:  true,
+	})
+	expectInvalidArgument(t, err, "amount")
+
+	// Quote isn't consumed by the dry run.
+	sent, err := h.client.SendPayment(ctx, &SendPaymentRequest{
+		QuoteId: quote.QuoteId,
+	})
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	if sent.PaymentId == "" || sent.MediaFee != dryRun.MediaFee {
+		t.Fatalf("wrong payment: %v", sent)
+	}
+}
+
+func TestListPaymentsPagination(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	for i := 1; i <= 5; i++ {
+		h.seedPayments(&connectors.Payment{
+			PaymentID: fmt.Sprintf("payment-%v", i),
+			UpdatedAt: int64(i),
+			Status:    connectors.Completed,
+			System:    connectors.External,
+			Direction: connectors.Incoming,
+			Receipt:   "btc-address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(int64(i), 0),
+			MediaFee:  decimal.Zero,
+			MediaID:   fmt.Sprintf("tx-%v", i),
+		})
+	}
+
+	tests := []struct {
+		name string
+		req  *ListPaymentsRequest
+		ids  []string
+	}{
+		{
+			name: "first page",
+			req: &ListPaymentsRequest{
+				Limit:     2,
+				Ascending: true,
+			},
+			ids: []string{"payment-1", "payment-2"},
+		},
+		{
+			name: "last page",
+			req: &ListPaymentsRequest{
+				Limit:     2,
+				Offset:    4,
+				Ascending: true,
+			},
+			ids: []string{"payment-5"},
+		},
+		{
+			name: "newest first",
+			req: &ListPaymentsRequest{
+				Limit: 2,
+			},
+			ids: []string{"payment-5", "payment-4"},
+		},
+		{
+			name: "by amount",
+			req: &ListPaymentsRequest{
+				SortBy:    PaymentsSortBy_SORT_AMOUNT,
+				MinAmount: "2",
+				MaxAmount: "3",
+				Ascending: true,
+			},
+			ids: []string{"payment-2", "payment-3"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := h.client.ListPayments(context.Background(),
+				test.req)
+			if err != nil {
+				t.Fatalf("unable to list payments: %v", err)
+			}
+
+			var ids []string
+			for _, payment := range resp.Payments {
+				ids = append(ids, payment.PaymentId)
+			}
+
+			if !reflect.DeepEqual(ids, test.ids) {
+				t.Fatalf("wrong payments, expected(%v), got(%v)",
+					test.ids, ids)
+			}
+
+			// Total doesn't depend on the page.
+			if test.req.MinAmount == "" && resp.Total != 5 {
+				t.Fatalf("wrong total: %v", resp.Total)
+			}
+		})
+	}
+
+	_, err := h.client.ListPayments(context.Background(),
+		&ListPaymentsRequest{MinAmount: "-1"})
+	expectInvalidArgument(t, err, "min_amount")
+}
+
+func TestRefundPayment(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	incoming := func(id string, status connectors.PaymentStatus) {
+		h.seedPayments(&connectors.Payment{
+			PaymentID: id,
+			UpdatedAt: 1,
+			Status:    status,
+			System:    connectors.External,
+			Direction: connectors.Incoming,
+			Receipt:   "btc-address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(2, 0),
+			MediaFee:  decimal.Zero,
+			MediaID:   id,
+		})
+	}
+	incoming("completed", connectors.Completed)
+	incoming("pending", connectors.Pending)
+
+	refund := func(id, amount string) (*Payment, error) {
+		return h.client.RefundPayment(ctx, &RefundPaymentRequest{
+			PaymentId: id,
+			Receipt:   "customer-address",
+			Amount:    amount,
+		})
+	}
+
+	_, err := refund("pending", "")
+	expectInvalidArgument(t, err, "payment_id")
+
+	_, err = refund("unknown", "")
+	expectInvalidArgument(t, err, "payment_id")
+
+	_, err = refund("completed", "-1")
+	expectInvalidArgument(t, err, "amount")
+
+	partial, err := refund("completed", "1.5")
+	if err != nil {
+		t.Fatalf("unable to refund payment: %v", err)
+	}
+
+	if partial.RefundOf != "completed" || partial.Amount != "1.5" ||
+		partial.Direction != PaymentDirection_OUTGOING {
+		t.Fatalf("wrong refund: %v", partial)
+	}
+
+	_, err = refund("completed", "1")
+	expectInvalidArgument(t, err, "amount")
+
+	// Refund rejected by the media isn't counted, so the rest is still
+	// refundable.
+	h.btc.sendErr = errors.New("fee is too low")
+	if _, err := refund("completed", ""); err == nil {
+		t.Fatalf("rejected refund should return error")
+	}
+	h.btc.sendErr = nil
+
+	rest, err := refund("completed", "")
+	if err != nil {
+		t.Fatalf("unable to refund payment: %v", err)
+	}
+
+	if rest.Amount != "0.5" {
+		t.Fatalf("rest of the payment should be refunded, got: %v",
+			rest.Amount)
+	}
+
+	_, err = refund("completed", "")
+	expectInvalidArgument(t, err, "amount")
+
+	// Link is kept when refund is updated by the sync.
+	if err := h.btc.confirm(rest.PaymentId, mockConfirmations); err != nil {
+		t.Fatalf("unable to confirm refund: %v", err)
+	}
+
+	payment, err := h.client.PaymentByID(ctx, &PaymentByIDRequest{
+		PaymentId: rest.PaymentId,
+	})
+	if err != nil {
+		t.Fatalf("unable to get refund: %v", err)
+	}
+
+	if payment.RefundOf != "completed" {
+		t.Fatalf("refund should be linked to the payment: %v", payment)
+	}
+}
+
+// unspentConnector is the mock connector which is able to list the wallet
+// unspent outputs.
+type unspentConnector struct {
+	*mockBlockchainConnector
+
+	outputs []*connectors.UnspentOutput
+}
+
+func (c *unspentConnector) ListUnspent() ([]*connectors.UnspentOutput,
+	error) {
+	return c.outputs, nil
+}
+
+func TestListUnspent(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+	req := &ListUnspentRequest{Asset: Asset_BTC}
+
+	if _, err := h.admin.ListUnspent(ctx, req); err == nil {
+		t.Fatalf("outputs shouldn't be listed by connector which isn't " +
+			"utxo based")
+	}
+
+	h.server.blockchainConnectors[connectors.BTC] = &unspentConnector{
+		mockBlockchainConnector: h.btc,
+		outputs: []*connectors.UnspentOutput{
+			{
+				TxID:          "tx1",
+				Vout:          0,
+				Address:       "address1",
+				Amount:        decimal.New(15, -1),
+				Confirmations: 10,
+			},
+			{
+				TxID:    "tx2",
+				Vout:    3,
+				Address: "address2",
+				Amount:  decimal.New(25, -2),
+				Locked:  true,
+			},
+		},
+	}
+
+	resp, err := h.admin.ListUnspent(ctx, req)
+	if err != nil {
+		t.Fatalf("unable to list unspent: %v", err)
+	}
+
+	if len(resp.Outputs) != 2 || resp.Total != "1.75" {
+		t.Fatalf("wrong unspent outputs: %v", resp)
+	}
+
+	if resp.Outputs[0].Locked || !resp.Outputs[1].Locked ||
+		resp.Outputs[1].TxId != "tx2" || resp.Outputs[1].Vout != 3 ||
+		resp.Outputs[1].Amount != "0.25" {
+		t.Fatalf("wrong unspent outputs: %v", resp.Outputs)
+	}
+}
+
+// inspectingConnector is the mock connector which is able to return the
+// details of the wallet transaction.
+type inspectingConnector struct {
+	*mockBlockchainConnector
+
+	tx *connectors.Transaction
+}
+
+func (c *inspectingConnector) TransactionByHash(
+	txID string) (*connectors.Transaction, error) {
+	if txID != c.tx.TxID {
+		return nil, errors.New("transaction not found")
+	}
+	return c.tx, nil
+}
+
+func TestTransactionByHash(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+	req := &TransactionByHashRequest{Asset: Asset_BTC, TxId: "tx"}
+
+	if _, err := h.admin.TransactionByHash(ctx, req); err == nil {
+		t.Fatalf("transaction shouldn't be returned by connector which " +
+			"isn't able to inspect it")
+	}
+
+	h.server.blockchainConnectors[connectors.BTC] = &inspectingConnector{
+		mockBlockchainConnector: h.btc,
+		tx: &connectors.Transaction{
+			TxID:          "tx",
+			BlockHash:     "block",
+			Confirmations: 3,
+			Fee:           decimal.New(1, -4),
+			Inputs: []*connectors.TransactionInput{
+				{TxID: "prev", Vout: 1},
+			},
+			Outputs: []*connectors.TransactionOutput{
+				{Vout: 0, Address: "address", Amount: decimal.New(5, -1)},
+				{Vout: 1, Amount: decimal.Zero},
+			},
+			RawTx: "0100",
+		},
+	}
+
+	payment := &connectors.Payment{
+		PaymentID: "payment",
+		Status:    connectors.Pending,
+		Direction: connectors.Outgoing,
+		System:    connectors.External,
+		Receipt:   "address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.New(5, -1),
+		MediaFee:  decimal.New(1, -4),
+		MediaID:   "tx",
+	}
+	if err := h.payments.SavePayment(payment); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	if _, err := h.admin.TransactionByHash(ctx, &TransactionByHashRequest{
+		Asset: Asset_BTC,
+		TxId:  "unknown",
+	}); err == nil {
+		t.Fatalf("unknown transaction shouldn't be returned")
+	}
+
+	resp, err := h.admin.TransactionByHash(ctx, req)
+	if err != nil {
+		t.Fatalf("unable to get transaction: %v", err)
+	}
+
+	if resp.BlockHash != "block" || resp.Confirmations != 3 ||
+		resp.Fee != "0.0001" || resp.RawTx != "0100" {
+		t.Fatalf("wrong transaction: %v", resp)
+	}
+
+	if len(resp.Inputs) != 1 || resp.Inputs[0].TxId != "prev" ||
+		resp.Inputs[0].Vout != 1 {
+		t.Fatalf("wrong inputs: %v", resp.Inputs)
+	}
+
+	if len(resp.Outputs) != 2 || resp.Outputs[0].Amount != "0.5" ||
+		resp.Outputs[0].Address != "address" || resp.Outputs[1].Address != "" {
+		t.Fatalf("wrong outputs: %v", resp.Outputs)
+	}
+
+	if len(resp.PaymentIds) != 1 || resp.PaymentIds[0] != "payment" {
+		t.Fatalf("wrong payments: %v", resp.PaymentIds)
+	}
+}
+
+// owningConnector is the mock connector which wallet owns the given
+// addresses.
+type owningConnector struct {
+	*mockBlockchainConnector
+
+	owned map[string]bool
+}
+
+func (c *owningConnector) IsOurAddress(address string) (bool, error) {
+	return c.owned[address], nil
+}
+
+func TestIsOurAddress(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	err := h.receipts.SaveDepositAddress(&connectors.DepositAddress{
+		Address:   "deposit",
+		Asset:     connectors.BTC,
+		CreatedAt: 1,
+		Tenant:    "merchant",
+		AccountID: "shop",
+	})
+	if err != nil {
+		t.Fatalf("unable to save deposit address: %v", err)
+	}
+
+	isOurs := func(address string) *IsOurAddressResponse {
+		resp, err := h.admin.IsOurAddress(ctx, &IsOurAddressRequest{
+			Asset:   Asset_BTC,
+			Address: address,
+		})
+		if err != nil {
+			t.Fatalf("unable to check address: %v", err)
+		}
+		return resp
+	}
+
+	// Without the access to the wallet only issued addresses are ours.
+	resp := isOurs("deposit")
+	if !resp.IsOurs || resp.AccountId != "shop" || resp.Tenant != "merchant" ||
+		resp.CreatedAt != 1 {
+		t.Fatalf("wrong deposit address: %v", resp)
+	}
+
+	if resp := isOurs("change"); resp.IsOurs {
+		t.Fatalf("unknown address shouldn't be ours: %v", resp)
+	}
+
+	h.server.blockchainConnectors[connectors.BTC] = &owningConnector{
+		mockBlockchainConnector: h.btc,
+		owned: map[string]bool{
+			"deposit": true,
+			"change":  true,
+		},
+	}
+
+	if resp := isOurs("change"); !resp.IsOurs || resp.AccountId != "" {
+		t.Fatalf("change address should be ours: %v", resp)
+	}
+
+	if resp := isOurs("stranger"); resp.IsOurs {
+		t.Fatalf("address of the stranger shouldn't be ours: %v", resp)
+	}
+
+	_, err = h.admin.IsOurAddress(ctx, &IsOurAddressRequest{Asset: Asset_BTC})
+	if err == nil {
+		t.Fatalf("empty address should be rejected")
+	}
+}
+
+// sweepingConnector is the mock connector which is able to send the whole
+// balance to the address.
+type sweepingConnector struct {
+	*mockBlockchainConnector
+}
+
+func (c *sweepingConnector) SweepFunds(address string) (*connectors.Payment,
+	error) {
+	c.mtx.Lock()
+	amount := c.balance.Sub(c.fee)
+	c.mtx.Unlock()
+
+	return c.SendPayment(address, amount.String(), "")
+}
+
+func TestSweepFunds(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+	req := &SweepFundsRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Address: "cold-storage",
+	}
+
+	if _, err := h.admin.SweepFunds(ctx, req); err == nil {
+		t.Fatalf("funds shouldn't be swept by connector which isn't able " +
+			"to sweep")
+	}
+
+	h.server.blockchainConnectors[connectors.BTC] = &sweepingConnector{
+		mockBlockchainConnector: h.btc,
+	}
+
+	_, err := h.admin.SweepFunds(ctx, &SweepFundsRequest{
+		Asset: Asset_BTC,
+		Media: Media_BLOCKCHAIN,
+	})
+	expectInvalidArgument(t, err, "address")
+
+	resp, err := h.admin.SweepFunds(ctx, req)
+	if err != nil {
+		t.Fatalf("unable to sweep funds: %v", err)
+	}
+
+	// Fee is subtracted from the swept amount, so that nothing is left.
+	if resp.Receipt != "cold-storage" || resp.Amount != "9.9999" ||
+		resp.MediaFee != "0.0001" {
+		t.Fatalf("wrong sweep payment: %v", resp)
+	}
+
+	if !h.btc.balance.IsZero() {
+		t.Fatalf("funds are left in the wallet: %v", h.btc.balance)
+	}
+
+	if _, err := h.payments.PaymentByID(resp.PaymentId); err != nil {
+		t.Fatalf("sweep payment isn't saved: %v", err)
+	}
+}
+
+// cancelingConnector is the mock connector which is able to cancel the
+// payments which are waiting to be sent.
+type cancelingConnector struct {
+	*mockBlockchainConnector
+}
+
+func (c *cancelingConnector) CancelPayment(
+	paymentID string) (*connectors.Payment, error) {
+	payment, err := c.store.PaymentByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled := *payment
+	cancelled.Status = connectors.Failed
+	cancelled.UpdatedAt = connectors.NowInMilliSeconds()
+	if err := c.store.SavePayment(&cancelled); err != nil {
+		return nil, err
+	}
+
+	return &cancelled, nil
+}
+
+func TestCancelPayment(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	newPayment := func(id string, status connectors.PaymentStatus,
+		direction connectors.PaymentDirection) {
+		h.seedPayments(&connectors.Payment{
+			PaymentID: id,
+			UpdatedAt: 1,
+			Status:    status,
+			System:    connectors.External,
+			Direction: direction,
+			Receipt:   "btc-address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.New(1, 0),
+			MediaFee:  decimal.Zero,
+			MediaID:   id,
+		})
+	}
+
+	newPayment("waiting", connectors.Waiting, connectors.Outgoing)
+	newPayment("sent", connectors.Pending, connectors.Outgoing)
+	newPayment("incoming", connectors.Waiting, connectors.Incoming)
+
+	cancel := func(id string) (*Payment, error) {
+		return h.client.CancelPayment(ctx, &CancelPaymentRequest{
+			PaymentId: id,
+		})
+	}
+
+	// Connector which sends payments right away couldn't cancel them.
+	_, err := cancel("waiting")
+	expectInvalidArgument(t, err, "payment_id")
+
+	h.server.blockchainConnectors[connectors.BTC] = &cancelingConnector{
+		mockBlockchainConnector: h.btc,
+	}
+
+	_, err = cancel("")
+	expectInvalidArgument(t, err, "payment_id")
+
+	_, err = cancel("unknown")
+	expectInvalidArgument(t, err, "payment_id")
+
+	_, err = cancel("incoming")
+	expectInvalidArgument(t, err, "payment_id")
+
+	// Payment which has been already broadcasted couldn't be cancelled.
+	_, err = cancel("sent")
+	expectInvalidArgument(t, err, "payment_id")
+
+	cancelled, err := cancel("waiting")
+	if err != nil {
+		t.Fatalf("unable to cancel payment: %v", err)
+	}
+
+	if cancelled.Status != PaymentStatus_FAILED {
+		t.Fatalf("cancelled payment should be failed: %v", cancelled)
+	}
+
+	// Failed payment couldn't be cancelled again.
+	_, err = cancel("waiting")
+	expectInvalidArgument(t, err, "payment_id")
+}
+
+// recordingHook is the send hook which vetoes every payment, remembering
+// the intent which it has been asked about.
+type recordingHook struct {
+	intent *connectors.PaymentIntent
+}
+
+func (h *recordingHook) BeforeSend(intent *connectors.PaymentIntent) error {
+	h.intent = intent
+	return &connectors.SendVetoError{Reason: "recorded"}
+}
+
+// invoiceConnector is the mock lightning connector which decodes every
+// invoice as the invoice with the given amount.
+type invoiceConnector struct {
+	mockLightningConnector
+
+	amount lnwire.MilliSatoshi
+}
+
+func (c *invoiceConnector) ValidateInvoice(invoice,
+	amount string) (*zpay32.Invoice, error) {
+	return &zpay32.Invoice{MilliSat: &c.amount}, nil
+}
+
+func TestSendHookIntent(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	hook := &recordingHook{}
+	h.server.sendHook = hook
+	h.server.lightningConnectors[connectors.BTC] = &invoiceConnector{
+		amount: lnwire.NewMSatFromSatoshis(100000),
+	}
+
+	// Invoice is passed to the hook in the canonical form, along with its
+	// amount, so that the policy couldn't be bypassed by the case of the
+	// invoice or by the amount inside of it.
+	_, err := h.client.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_LIGHTNING,
+		Receipt: "LNSB1INVOICE",
+	})
+	if err == nil {
+		t.Fatalf("payment should be vetoed by the hook")
+	}
+
+	output := hook.intent.Outputs[0]
+	if output.Address != "lnsb1invoice" || output.Amount != "0.001" {
+		t.Fatalf("wrong intent output: %v", output)
+	}
+
+	// Amount of the request is kept if it is specified.
+	_, err = h.client.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_LIGHTNING,
+		Receipt: "lnsb1invoice",
+		Amount:  "0.002",
+	})
+	if err == nil {
+		t.Fatalf("payment should be vetoed by the hook")
+	}
+
+	if output := hook.intent.Outputs[0]; output.Amount != "0.002" {
+		t.Fatalf("wrong intent output: %v", output)
+	}
+}
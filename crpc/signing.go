@@ -0,0 +1,242 @@
+package crpc
+
+import (
+	"encoding/base64"
+	"math/rand"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"golang.org/x/net/context"
+)
+
+// maxSignedMessageLength is the maximum length of the message which could
+// be signed or verified.
+const maxSignedMessageLength = 4096
+
+// messageAddress validates the blockchain address with which message is
+// signed and returns the signer of the connector along with the canonical
+// form of the address.
+func (s *Server) messageAddress(ctx context.Context, asset Asset,
+	address string) (connectors.MessageSigner, string, error) {
+
+	c, ok := s.blockchainConnectors[connectors.Asset(asset.String())]
+	if !ok {
+		return nil, "", newErrAssetNotSupported(asset.String(),
+			Media_BLOCKCHAIN.String())
+	}
+
+	signer, ok := c.(connectors.MessageSigner)
+	if !ok {
+		return nil, "", newErrAssetNotSupported(asset.String(),
+			Media_BLOCKCHAIN.String())
+	}
+
+	if address == "" {
+		return nil, "", newErrInvalidArgument("address")
+	}
+
+	stop := trackStage(ctx, stageNode)
+	info, err := c.ValidateAddress(address)
+	stop()
+	if err != nil {
+		return nil, "", newErrInvalidArgument("address")
+	}
+
+	return signer, info.Address, nil
+}
+
+// nodeMessageSigner returns the signer of the lightning connector of the
+// asset.
+func (s *Server) nodeMessageSigner(
+	asset Asset) (connectors.NodeMessageSigner, error) {
+
+	c, ok := s.lightningConnectors[connectors.Asset(asset.String())]
+	if !ok {
+		return nil, newErrAssetNotSupported(asset.String(),
+			Media_LIGHTNING.String())
+	}
+
+	signer, ok := c.(connectors.NodeMessageSigner)
+	if !ok {
+		return nil, newErrAssetNotSupported(asset.String(),
+			Media_LIGHTNING.String())
+	}
+
+	return signer, nil
+}
+
+// signMessage signs the message with the key of the wallet address or of
+// the lightning node.
+func (s *Server) signMessage(ctx context.Context,
+	req *SignMessageRequest) (*SignMessageResponse, error) {
+
+	if req.Message == "" || len(req.Message) > maxSignedMessageLength {
+		return nil, newErrInvalidArgument("message")
+	}
+
+	switch req.Media {
+	case Media_BLOCKCHAIN:
+		signer, address, err := s.messageAddress(ctx, req.Asset, req.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		stop := trackStage(ctx, stageNode)
+		signature, err := signer.SignMessage(address, req.Message)
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		return &SignMessageResponse{
+			Signature: signature,
+			Signer:    address,
+		}, nil
+
+	case Media_LIGHTNING:
+		signer, err := s.nodeMessageSigner(req.Asset)
+		if err != nil {
+			return nil, err
+		}
+
+		stop := trackStage(ctx, stageNode)
+		signature, err := signer.SignNodeMessage(req.Message)
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		// Signer of the node message is recovered from the signature,
+		// so that node key is returned in the same way as on the
+		// verification.
+		stop = trackStage(ctx, stageNode)
+		pubKey, _, err := signer.VerifyNodeMessage(req.Message, signature)
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		return &SignMessageResponse{
+			Signature: signature,
+			Signer:    pubKey,
+		}, nil
+
+	default:
+		return nil, newErrInvalidArgument("media")
+	}
+}
+
+// verifyMessage verifies the signature of the message made by the
+// blockchain address or by the lightning node.
+func (s *Server) verifyMessage(ctx context.Context,
+	req *VerifyMessageRequest) (*VerifyMessageResponse, error) {
+
+	if req.Message == "" || len(req.Message) > maxSignedMessageLength {
+		return nil, newErrInvalidArgument("message")
+	}
+
+	if req.Signature == "" {
+		return nil, newErrInvalidArgument("signature")
+	}
+
+	switch req.Media {
+	case Media_BLOCKCHAIN:
+		signer, address, err := s.messageAddress(ctx, req.Asset, req.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		// Daemon fails on the malformed signature, rather than reports
+		// it as invalid, that is why it is checked beforehand.
+		if _, err := base64.StdEncoding.DecodeString(req.Signature); err != nil {
+			return nil, newErrInvalidArgument("signature")
+		}
+
+		stop := trackStage(ctx, stageNode)
+		valid, err := signer.VerifyMessage(address, req.Signature, req.Message)
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		return &VerifyMessageResponse{
+			Valid:  valid,
+			Signer: address,
+		}, nil
+
+	case Media_LIGHTNING:
+		signer, err := s.nodeMessageSigner(req.Asset)
+		if err != nil {
+			return nil, err
+		}
+
+		stop := trackStage(ctx, stageNode)
+		pubKey, valid, err := signer.VerifyNodeMessage(req.Message,
+			req.Signature)
+		stop()
+		if err != nil {
+			return nil, newErrInternal(err.Error())
+		}
+
+		return &VerifyMessageResponse{
+			Valid:  valid,
+			Signer: pubKey,
+		}, nil
+
+	default:
+		return nil, newErrInvalidArgument("media")
+	}
+}
+
+//
+// SignMessage signs the message with the key of the wallet address or of
+// the lightning node, so that their ownership could be proven to the
+// exchanges and auditors.
+func (s *Server) SignMessage(ctx context.Context,
+	req *SignMessageRequest) (*SignMessageResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.signMessage(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Infof("Message is signed by %v %v(%v)", req.Asset, req.Media,
+		resp.Signer)
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// VerifyMessage verifies the signature of the message made by the
+// blockchain address or by the lightning node.
+func (s *Server) VerifyMessage(ctx context.Context,
+	req *VerifyMessageRequest) (*VerifyMessageResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.verifyMessage(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
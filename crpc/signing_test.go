@@ -0,0 +1,172 @@
+package crpc
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// signingConnector is the mock connector which wallet owns the given
+// addresses, and which signature is the encoded address and message.
+type signingConnector struct {
+	*mockBlockchainConnector
+
+	owned map[string]bool
+}
+
+func (c *signingConnector) SignMessage(address, message string) (string,
+	error) {
+	if !c.owned[address] {
+		return "", errors.New("private key is not available")
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(address + ":" +
+		message)), nil
+}
+
+func (c *signingConnector) VerifyMessage(address, signature,
+	message string) (bool, error) {
+	expected := base64.StdEncoding.EncodeToString([]byte(address + ":" +
+		message))
+	return signature == expected, nil
+}
+
+// signingLightningConnector is the mock lightning connector which node
+// signature is the reversed message.
+type signingLightningConnector struct {
+	mockLightningConnector
+}
+
+func (c *signingLightningConnector) SignNodeMessage(message string) (string,
+	error) {
+	return reverse(message), nil
+}
+
+func (c *signingLightningConnector) VerifyNodeMessage(message,
+	signature string) (string, bool, error) {
+	if signature != reverse(message) {
+		return "", false, nil
+	}
+
+	return "node-pubkey", true, nil
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func TestSignMessage(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	sign := func(media Media, address, message string) (
+		*SignMessageResponse, error) {
+		return h.admin.SignMessage(ctx, &SignMessageRequest{
+			Asset:   Asset_BTC,
+			Media:   media,
+			Address: address,
+			Message: message,
+		})
+	}
+
+	verify := func(media Media, address, message,
+		signature string) (*VerifyMessageResponse, error) {
+		return h.admin.VerifyMessage(ctx, &VerifyMessageRequest{
+			Asset:     Asset_BTC,
+			Media:     media,
+			Address:   address,
+			Message:   message,
+			Signature: signature,
+		})
+	}
+
+	// Connector which isn't able to sign doesn't support the method.
+	if _, err := sign(Media_BLOCKCHAIN, "ours", "proof"); err == nil {
+		t.Fatalf("message is signed by connector without signer")
+	}
+
+	h.server.blockchainConnectors[connectors.BTC] = &signingConnector{
+		mockBlockchainConnector: h.btc,
+		owned:                   map[string]bool{"ours": true},
+	}
+
+	_, err := sign(Media_BLOCKCHAIN, "", "proof")
+	expectInvalidArgument(t, err, "address")
+
+	_, err = sign(Media_BLOCKCHAIN, "ours", "")
+	expectInvalidArgument(t, err, "message")
+
+	_, err = sign(Media_BLOCKCHAIN, "ours", strings.Repeat("a",
+		maxSignedMessageLength+1))
+	expectInvalidArgument(t, err, "message")
+
+	signed, err := sign(Media_BLOCKCHAIN, "ours", "proof")
+	if err != nil {
+		t.Fatalf("unable to sign message: %v", err)
+	}
+
+	if signed.Signer != "ours" {
+		t.Fatalf("wrong signer: %v", signed.Signer)
+	}
+
+	if _, err := sign(Media_BLOCKCHAIN, "stranger", "proof"); err == nil {
+		t.Fatalf("message is signed by address which isn't ours")
+	}
+
+	resp, err := verify(Media_BLOCKCHAIN, "ours", "proof", signed.Signature)
+	if err != nil || !resp.Valid || resp.Signer != "ours" {
+		t.Fatalf("signature isn't valid: %v, %v", resp, err)
+	}
+
+	resp, err = verify(Media_BLOCKCHAIN, "ours", "altered", signed.Signature)
+	if err != nil || resp.Valid {
+		t.Fatalf("signature of the altered message is valid: %v, %v", resp,
+			err)
+	}
+
+	_, err = verify(Media_BLOCKCHAIN, "ours", "proof", "not-base64!")
+	expectInvalidArgument(t, err, "signature")
+
+	_, err = verify(Media_BLOCKCHAIN, "ours", "proof", "")
+	expectInvalidArgument(t, err, "signature")
+
+	// Lightning messages are signed by the node key.
+	if _, err := sign(Media_LIGHTNING, "", "proof"); err == nil {
+		t.Fatalf("message is signed without lightning connector")
+	}
+
+	h.server.lightningConnectors[connectors.BTC] = &signingLightningConnector{}
+
+	signed, err = sign(Media_LIGHTNING, "", "proof")
+	if err != nil {
+		t.Fatalf("unable to sign message: %v", err)
+	}
+
+	if signed.Signature != "foorp" || signed.Signer != "node-pubkey" {
+		t.Fatalf("wrong signed message: %v", signed)
+	}
+
+	resp, err = verify(Media_LIGHTNING, "", "proof", signed.Signature)
+	if err != nil || !resp.Valid || resp.Signer != "node-pubkey" {
+		t.Fatalf("signature isn't valid: %v, %v", resp, err)
+	}
+
+	resp, err = verify(Media_LIGHTNING, "", "altered", signed.Signature)
+	if err != nil || resp.Valid {
+		t.Fatalf("signature of the altered message is valid: %v, %v", resp,
+			err)
+	}
+
+	_, err = sign(Media_MEDIA_NONE, "ours", "proof")
+	expectInvalidArgument(t, err, "media")
+}
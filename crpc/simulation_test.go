@@ -0,0 +1,520 @@
+package crpc
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// simulationSeeds is the number of the randomized event orderings
+	// which are checked by the simulation.
+	simulationSeeds = 20
+
+	// simulationSteps is the number of the events in every ordering.
+	simulationSteps = 300
+
+	// simulationBurst is the number of the concurrent transfers which are
+	// made from the same account by the burst event.
+	simulationBurst = 5
+)
+
+// simulationAccounts are the accounts which receive the deposits and
+// between which funds are transferred.
+var simulationAccounts = []string{"alice", "bob", "carol"}
+
+// simDeposit is the expected state of the incoming payment, which is
+// tracked by the simulation independently from the server.
+type simDeposit struct {
+	id            string
+	account       string
+	amount        decimal.Decimal
+	confirmations int64
+	status        connectors.PaymentStatus
+	quarantine    connectors.QuarantineState
+}
+
+// simEvent is the event which might happen to the payments, apply returns
+// false if event isn't applicable to the current state, e.g. there are no
+// pending deposits to confirm.
+type simEvent struct {
+	name   string
+	weight int
+	apply  func(s *simulation) bool
+}
+
+// simulation drives the payment state machine of the server with the
+// events in the random, but reproducible, order, and checks after every
+// event that the state of the server matches the expected one. Events are
+// the ones which are produced by the connector sync and the operators:
+// deposits, confirmations, reorganisations, cancellations, restarts,
+// quarantine reviews and transfers between the accounts.
+type simulation struct {
+	t    *testing.T
+	h    *testHarness
+	ctx  context.Context
+	rnd  *rand.Rand
+	seed int64
+
+	deposits  []*simDeposit
+	transfers map[string]decimal.Decimal
+	payments  int
+
+	// trace is the list of the applied events, which is printed on
+	// failure, so that ordering could be analysed without the rerun.
+	trace []string
+}
+
+// simEvents is the list of the events of the simulation along with their
+// relative frequency.
+var simEvents = []simEvent{
+	{"deposit", 4, (*simulation).deposit},
+	{"confirm", 8, (*simulation).confirm},
+	{"reorg", 2, (*simulation).reorg},
+	{"cancel", 1, (*simulation).cancel},
+	{"restart", 1, (*simulation).restart},
+	{"quarantine", 1, (*simulation).quarantine},
+	{"release", 1, (*simulation).release},
+	{"transfer", 4, (*simulation).transfer},
+	{"burst", 1, (*simulation).burst},
+}
+
+func newSimulation(t *testing.T, seed int64) *simulation {
+	return &simulation{
+		t:         t,
+		h:         newTestHarness(t),
+		ctx:       context.Background(),
+		rnd:       rand.New(rand.NewSource(seed)),
+		seed:      seed,
+		transfers: make(map[string]decimal.Decimal),
+	}
+}
+
+// fatalf fails the test with the seed and the trace of the simulation, so
+// that failure could be reproduced.
+func (s *simulation) fatalf(format string, args ...interface{}) {
+	s.t.Helper()
+
+	s.t.Fatalf("seed(%v), events(%v): %v", s.seed,
+		strings.Join(s.trace, ", "), fmt.Sprintf(format, args...))
+}
+
+// run applies the given number of the random events and checks the
+// invariants after every one of them.
+func (s *simulation) run(steps int) {
+	total := 0
+	for _, event := range simEvents {
+		total += event.weight
+	}
+
+	for step := 0; step < steps; step++ {
+		// Event is drawn until the applicable one is found, deposit is
+		// always applicable, so that it couldn't loop forever.
+		for {
+			n := s.rnd.Intn(total)
+
+			var event simEvent
+			for _, event = range simEvents {
+				if n < event.weight {
+					break
+				}
+				n -= event.weight
+			}
+
+			if event.apply(s) {
+				s.trace = append(s.trace, event.name)
+				break
+			}
+		}
+
+		s.check()
+	}
+}
+
+// pick returns the random deposit which satisfies the filter, nil if there
+// is no such deposit.
+func (s *simulation) pick(filter func(d *simDeposit) bool) *simDeposit {
+	var candidates []*simDeposit
+	for _, d := range s.deposits {
+		if filter(d) {
+			candidates = append(candidates, d)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	return candidates[s.rnd.Intn(len(candidates))]
+}
+
+// pickAccount returns the random account of the simulation.
+func (s *simulation) pickAccount() string {
+	return simulationAccounts[s.rnd.Intn(len(simulationAccounts))]
+}
+
+// randomAmount returns the random amount in range of 0.01-1.
+func (s *simulation) randomAmount() decimal.Decimal {
+	return decimal.New(int64(1+s.rnd.Intn(100)), -2)
+}
+
+// isPending returns true if deposit is seen in the blockchain, but hasn't
+// been confirmed enough times yet.
+func isPending(d *simDeposit) bool {
+	return d.status == connectors.Pending
+}
+
+// sync saves the deposit in the same way as the connector sync does.
+// Payment is regenerated by the sync from the blockchain, that is why
+// account is passed only on the first save, along with the payment
+// detection, and quarantine state is never passed.
+func (s *simulation) sync(d *simDeposit, first bool) {
+	s.t.Helper()
+
+	left := mockConfirmations - d.confirmations
+	if left < 0 {
+		left = 0
+	}
+
+	payment := &connectors.Payment{
+		PaymentID: d.id,
+		UpdatedAt: connectors.NowInMilliSeconds(),
+		Status:    d.status,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "btc-address-" + d.account,
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    d.amount,
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx-" + d.id,
+		Detail: &connectors.BlockchainPendingDetails{
+			Confirmations:     d.confirmations,
+			ConfirmationsLeft: left,
+		},
+	}
+
+	if first {
+		payment.AccountID = d.account
+	}
+
+	if err := s.h.payments.SavePayment(payment); err != nil {
+		s.fatalf("unable to sync payment(%v): %v", d.id, err)
+	}
+}
+
+// deposit detects the new incoming payment in the mempool.
+func (s *simulation) deposit() bool {
+	d := &simDeposit{
+		id:      fmt.Sprintf("deposit-%v", len(s.deposits)),
+		account: s.pickAccount(),
+		amount:  s.randomAmount(),
+		status:  connectors.Pending,
+	}
+
+	s.deposits = append(s.deposits, d)
+	s.payments++
+	s.sync(d, true)
+	return true
+}
+
+// confirm mines the block with the pending deposit, deposit is completed
+// once it has enough confirmations.
+func (s *simulation) confirm() bool {
+	d := s.pick(isPending)
+	if d == nil {
+		return false
+	}
+
+	d.confirmations++
+	if d.confirmations >= mockConfirmations {
+		d.status = connectors.Completed
+	}
+
+	s.sync(d, false)
+	return true
+}
+
+// reorg reorganises the blocks with the pending deposit, so that its
+// transaction returns to the mempool. Reorganisations deeper than the
+// required number of the confirmations are not simulated, completed
+// deposits are final.
+func (s *simulation) reorg() bool {
+	d := s.pick(func(d *simDeposit) bool {
+		return isPending(d) && d.confirmations > 0
+	})
+	if d == nil {
+		return false
+	}
+
+	d.confirmations = 0
+	s.sync(d, false)
+	return true
+}
+
+// cancel drops the pending deposit, e.g. its transaction has been double
+// spent or evicted from the mempool.
+func (s *simulation) cancel() bool {
+	d := s.pick(isPending)
+	if d == nil {
+		return false
+	}
+
+	d.status = connectors.Failed
+	s.sync(d, false)
+	return true
+}
+
+// restart makes the full resync of the deposits, in the random order, as
+// it is done by the connector after the restart, every deposit is saved
+// again in its current state.
+func (s *simulation) restart() bool {
+	if len(s.deposits) == 0 {
+		return false
+	}
+
+	for _, i := range s.rnd.Perm(len(s.deposits)) {
+		s.sync(s.deposits[i], false)
+	}
+
+	return true
+}
+
+// quarantine flags the deposit by the compliance review. Only pending
+// deposits are flagged, because flagged completed deposit might have been
+// already spent, in this case account is expectedly overdrawn.
+func (s *simulation) quarantine() bool {
+	d := s.pick(func(d *simDeposit) bool {
+		return isPending(d) && d.quarantine == connectors.NotQuarantined
+	})
+	if d == nil {
+		return false
+	}
+
+	_, err := s.h.admin.QuarantinePayment(s.ctx, &QuarantinePaymentRequest{
+		PaymentId: d.id,
+		Reason:    "simulation",
+	})
+	if err != nil {
+		s.fatalf("unable to quarantine payment(%v): %v", d.id, err)
+	}
+
+	d.quarantine = connectors.Quarantined
+	return true
+}
+
+// release releases the quarantined deposit after the review.
+func (s *simulation) release() bool {
+	d := s.pick(func(d *simDeposit) bool {
+		return d.quarantine == connectors.Quarantined
+	})
+	if d == nil {
+		return false
+	}
+
+	_, err := s.h.admin.ReleasePayment(s.ctx, &ReleasePaymentRequest{
+		PaymentId: d.id,
+	})
+	if err != nil {
+		s.fatalf("unable to release payment(%v): %v", d.id, err)
+	}
+
+	d.quarantine = connectors.QuarantineReleased
+	return true
+}
+
+// transferFunds transfers funds between the accounts through the server,
+// and returns the error of the call.
+func (s *simulation) transferFunds(from, to string,
+	amount decimal.Decimal) error {
+	_, err := s.h.client.TransferFunds(s.ctx, &TransferFundsRequest{
+		Asset:       Asset_BTC,
+		Media:       Media_BLOCKCHAIN,
+		FromAccount: from,
+		ToAccount:   to,
+		Amount:      amount.String(),
+	})
+	return err
+}
+
+// recordTransfer updates the expected balances with the transfer which
+// has been made.
+func (s *simulation) recordTransfer(from, to string, amount decimal.Decimal) {
+	s.transfers[from] = s.transfers[from].Sub(amount)
+	s.transfers[to] = s.transfers[to].Add(amount)
+	s.payments += 2
+}
+
+// pickAccounts returns two distinct random accounts.
+func (s *simulation) pickAccounts() (string, string) {
+	from := s.pickAccount()
+	for {
+		to := s.pickAccount()
+		if to != from {
+			return from, to
+		}
+	}
+}
+
+// transfer transfers funds between the accounts, transfer should be
+// accepted only if source account has enough funds.
+func (s *simulation) transfer() bool {
+	from, to := s.pickAccounts()
+	amount := s.randomAmount()
+
+	err := s.transferFunds(from, to, amount)
+	if amount.GreaterThan(s.balance(from)) {
+		expected := newErrInvalidArgument("amount").Error()
+		if msg := status.Convert(err).Message(); err == nil ||
+			msg != expected {
+			s.fatalf("transfer of %v from account(%v) with balance(%v) "+
+				"isn't rejected: %v", amount, from, s.balance(from), err)
+		}
+		return true
+	}
+
+	if err != nil {
+		s.fatalf("unable to transfer %v from account(%v): %v", amount,
+			from, err)
+	}
+
+	s.recordTransfer(from, to, amount)
+	return true
+}
+
+// burst makes several concurrent transfers from the same account, only as
+// many of them as are covered by the balance should be accepted.
+func (s *simulation) burst() bool {
+	from, to := s.pickAccounts()
+	balance := s.balance(from)
+	if !balance.IsPositive() {
+		return false
+	}
+
+	// Amount is chosen so that only part of the transfers is covered.
+	amount := balance.Div(decimal.New(int64(1+s.rnd.Intn(simulationBurst)),
+		0)).Truncate(8)
+	if !amount.IsPositive() {
+		return false
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mtx      sync.Mutex
+		accepted int
+	)
+	for i := 0; i < simulationBurst; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := s.transferFunds(from, to, amount); err == nil {
+				mtx.Lock()
+				accepted++
+				mtx.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	expected := int(balance.Div(amount).IntPart())
+	if expected > simulationBurst {
+		expected = simulationBurst
+	}
+
+	if accepted != expected {
+		s.fatalf("%v of %v concurrent transfers of %v from balance(%v) "+
+			"are accepted, expected %v", accepted, simulationBurst, amount,
+			balance, expected)
+	}
+
+	for i := 0; i < accepted; i++ {
+		s.recordTransfer(from, to, amount)
+	}
+
+	return true
+}
+
+// balance returns the expected balance of the account. Deposit is credited
+// once it is completed, unless it is quarantined.
+func (s *simulation) balance(account string) decimal.Decimal {
+	balance := s.transfers[account]
+	for _, d := range s.deposits {
+		if d.account == account && d.status == connectors.Completed &&
+			d.quarantine != connectors.Quarantined {
+			balance = balance.Add(d.amount)
+		}
+	}
+
+	return balance
+}
+
+// check checks that state of the server matches the expected one: there
+// are no negative balances, every deposit is credited exactly once, and
+// every payment has the distinct sequence number.
+func (s *simulation) check() {
+	s.t.Helper()
+
+	for _, account := range simulationAccounts {
+		balance, err := s.h.server.accountBalance(account, connectors.BTC,
+			connectors.Blockchain)
+		if err != nil {
+			s.fatalf("unable to get balance: %v", err)
+		}
+
+		if balance.IsNegative() {
+			s.fatalf("balance(%v) of account(%v) is negative", balance,
+				account)
+		}
+
+		if expected := s.balance(account); !balance.Equal(expected) {
+			s.fatalf("wrong balance of account(%v), expected(%v), got(%v)",
+				account, expected, balance)
+		}
+	}
+
+	payments, err := s.h.payments.ListPayments("", "", "", "", "")
+	if err != nil {
+		s.fatalf("unable to list payments: %v", err)
+	}
+
+	if len(payments) != s.payments {
+		s.fatalf("wrong number of payments, expected(%v), got(%v)",
+			s.payments, len(payments))
+	}
+
+	sequences := make(map[uint64]string, len(payments))
+	for _, payment := range payments {
+		if id, ok := sequences[payment.Sequence]; ok {
+			s.fatalf("payments(%v, %v) have the same sequence(%v)", id,
+				payment.PaymentID, payment.Sequence)
+		}
+		sequences[payment.Sequence] = payment.PaymentID
+	}
+}
+
+// TestPaymentSimulation drives the payment state machine with the
+// randomized orderings of the events. Failed ordering is reproduced by
+// running the subtest of its seed, e.g. -run TestPaymentSimulation/seed-7.
+func TestPaymentSimulation(t *testing.T) {
+	seeds, steps := simulationSeeds, simulationSteps
+	if testing.Short() {
+		seeds, steps = 3, 100
+	}
+
+	for seed := int64(1); seed <= int64(seeds); seed++ {
+		t.Run(fmt.Sprintf("seed-%v", seed), func(t *testing.T) {
+			s := newSimulation(t, seed)
+			defer s.h.stop()
+
+			s.run(steps)
+		})
+	}
+}
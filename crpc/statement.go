@@ -0,0 +1,152 @@
+package crpc
+
+import (
+	"math/rand"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+// accountStatement builds the statement of the account from its payments.
+// Payment is dated by the time of its last update, which is the time of
+// the completion of the incoming payment, since only completed incoming
+// payments change the balance, see accountBalanceDelta.
+func (s *Server) accountStatement(ctx context.Context,
+	req *AccountStatementRequest) (*AccountStatementResponse, error) {
+
+	if req.Account == "" || !isValidAccount(req.Account) {
+		return nil, newErrInvalidArgument("account")
+	}
+
+	asset, err := ConvertAssetFromProto(req.Asset)
+	if err != nil || req.Asset == Asset_ASSET_NONE {
+		return nil, newErrInvalidArgument("asset")
+	}
+
+	media, err := ConvertMediaFromProto(req.Media)
+	if err != nil || req.Media == Media_MEDIA_NONE {
+		return nil, newErrInvalidArgument("media")
+	}
+
+	if req.From < 0 {
+		return nil, newErrInvalidArgument("from")
+	}
+
+	to := req.To
+	if to == 0 {
+		to = connectors.NowInMilliSeconds()
+	}
+
+	if to < req.From {
+		return nil, newErrInvalidArgument("to")
+	}
+
+	if err := s.checkAccountTenant(ctx, req.Account); err != nil {
+		return nil, err
+	}
+
+	var (
+		balance = decimal.Zero
+		credits = decimal.Zero
+		debits  = decimal.Zero
+		fees    = decimal.Zero
+		opening = decimal.Zero
+		entries []*StatementEntry
+	)
+
+	query := connectors.PaymentsQuery{
+		AccountID: req.Account,
+		Asset:     asset,
+		Media:     media,
+		UpdatedTo: to,
+		SortBy:    connectors.SortByUpdatedAt,
+		Ascending: true,
+	}
+
+	stop := trackStage(ctx, stageDB)
+	_, err = s.walkPayments(query, func(payment *connectors.Payment) error {
+		delta := accountBalanceDelta(payment)
+		if delta.IsZero() {
+			return nil
+		}
+
+		// Payments are walked in chronological order, so that payments
+		// before the time window are summed up first.
+		balance = balance.Add(delta)
+		if payment.UpdatedAt < req.From {
+			opening = balance
+			return nil
+		}
+
+		system, err := convertPaymentSystemToProto(payment.System)
+		if err != nil {
+			return err
+		}
+
+		entry := &StatementEntry{
+			PaymentId: payment.PaymentID,
+			Time:      payment.UpdatedAt,
+			System:    system,
+			Credit:    "0",
+			Debit:     "0",
+			Fee:       "0",
+			Balance:   balance.String(),
+			Receipt:   payment.Receipt,
+			MediaId:   payment.MediaID,
+		}
+
+		if payment.Direction == connectors.Incoming {
+			entry.Credit = payment.Amount.String()
+			credits = credits.Add(payment.Amount)
+		} else {
+			entry.Debit = payment.Amount.String()
+			entry.Fee = payment.MediaFee.String()
+			debits = debits.Add(payment.Amount)
+			fees = fees.Add(payment.MediaFee)
+		}
+
+		entries = append(entries, entry)
+		return nil
+	})
+	stop()
+	if err != nil {
+		return nil, newErrInternal(err.Error())
+	}
+
+	return &AccountStatementResponse{
+		OpeningBalance: opening.String(),
+		Entries:        entries,
+		ClosingBalance: balance.String(),
+		Credits:        credits.String(),
+		Debits:         debits.String(),
+		Fees:           fees.String(),
+	}, nil
+}
+
+//
+// AccountStatement returns the statement of the account in the given asset
+// and media for the time window: opening balance, chronological ledger of
+// credits and debits along with their fees, and closing balance.
+func (s *Server) AccountStatement(ctx context.Context,
+	req *AccountStatementRequest) (*AccountStatementResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp, err := s.accountStatement(ctx, req)
+	if err != nil {
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
@@ -0,0 +1,127 @@
+package crpc
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+func TestAccountStatement(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	payment := func(id string, updatedAt int64,
+		status connectors.PaymentStatus,
+		direction connectors.PaymentDirection, amount,
+		fee decimal.Decimal) *connectors.Payment {
+		return &connectors.Payment{
+			PaymentID: id,
+			UpdatedAt: updatedAt,
+			Status:    status,
+			System:    connectors.External,
+			Direction: direction,
+			Receipt:   "receipt-" + id,
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    amount,
+			MediaFee:  fee,
+			MediaID:   "tx-" + id,
+			AccountID: "customer-1",
+		}
+	}
+
+	h.seedPayments(
+		payment("deposit", 10, connectors.Completed, connectors.Incoming,
+			decimal.New(2, 0), decimal.Zero),
+		payment("withdrawal", 20, connectors.Completed,
+			connectors.Outgoing, decimal.New(5, -1), decimal.New(1, -1)),
+		payment("unconfirmed", 25, connectors.Pending,
+			connectors.Incoming, decimal.New(7, 0), decimal.Zero),
+		payment("failed", 30, connectors.Failed, connectors.Outgoing,
+			decimal.New(1, 0), decimal.New(1, -1)),
+		payment("refund", 40, connectors.Completed, connectors.Incoming,
+			decimal.New(1, 0), decimal.Zero),
+		payment("late", 60, connectors.Completed, connectors.Incoming,
+			decimal.New(3, 0), decimal.Zero),
+	)
+
+	statement := func(account string, from, to int64) (
+		*AccountStatementResponse, error) {
+		return h.client.AccountStatement(ctx, &AccountStatementRequest{
+			Account: account,
+			Asset:   Asset_BTC,
+			Media:   Media_BLOCKCHAIN,
+			From:    from,
+			To:      to,
+		})
+	}
+
+	_, err := statement("", 0, 0)
+	expectInvalidArgument(t, err, "account")
+
+	_, err = statement("customer-1", 50, 15)
+	expectInvalidArgument(t, err, "to")
+
+	resp, err := statement("customer-1", 15, 50)
+	if err != nil {
+		t.Fatalf("unable to get statement: %v", err)
+	}
+
+	if resp.OpeningBalance != "2" || resp.ClosingBalance != "2.4" ||
+		resp.Credits != "1" || resp.Debits != "0.5" || resp.Fees != "0.1" {
+		t.Fatalf("wrong statement: %v", resp)
+	}
+
+	// Pending and failed payments don't change the balance, that is why
+	// they are not in the ledger.
+	expected := []struct {
+		id      string
+		credit  string
+		debit   string
+		fee     string
+		balance string
+	}{
+		{id: "withdrawal", credit: "0", debit: "0.5", fee: "0.1",
+			balance: "1.4"},
+		{id: "refund", credit: "1", debit: "0", fee: "0", balance: "2.4"},
+	}
+
+	if len(resp.Entries) != len(expected) {
+		t.Fatalf("wrong number of entries: %v", resp.Entries)
+	}
+
+	for i, entry := range resp.Entries {
+		e := expected[i]
+		if entry.PaymentId != e.id || entry.Credit != e.credit ||
+			entry.Debit != e.debit || entry.Fee != e.fee ||
+			entry.Balance != e.balance {
+			t.Fatalf("wrong entry(%v): %v", i, entry)
+		}
+	}
+
+	// Whole history of the account is returned by default.
+	resp, err = statement("customer-1", 0, 0)
+	if err != nil {
+		t.Fatalf("unable to get statement: %v", err)
+	}
+
+	if resp.OpeningBalance != "0" || resp.ClosingBalance != "5.4" ||
+		len(resp.Entries) != 4 {
+		t.Fatalf("wrong statement: %v", resp)
+	}
+
+	// Window without entries has the same opening and closing balance.
+	resp, err = statement("customer-1", 45, 55)
+	if err != nil {
+		t.Fatalf("unable to get statement: %v", err)
+	}
+
+	if resp.OpeningBalance != "2.4" || resp.ClosingBalance != "2.4" ||
+		len(resp.Entries) != 0 {
+		t.Fatalf("wrong statement: %v", resp)
+	}
+}
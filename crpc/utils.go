@@ -1,11 +1,15 @@
 package crpc
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/bitlum/connector/connectors"
 	"github.com/go-errors/errors"
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
+	"net/url"
+	"time"
+	"unicode/utf8"
 )
 
 func convertProtoMessage(resp proto.Message) string {
@@ -91,6 +95,26 @@ func convertPaymentSystemToProto(system connectors.PaymentSystem) (
 	return protoSystem, nil
 }
 
+func convertQuarantineStateToProto(state connectors.QuarantineState) (
+	QuarantineState, error) {
+	var protoState QuarantineState
+	switch state {
+	case connectors.NotQuarantined:
+		protoState = QuarantineState_QUARANTINE_NONE
+	case connectors.Quarantined:
+		protoState = QuarantineState_QUARANTINED
+	case connectors.QuarantineReleased:
+		protoState = QuarantineState_QUARANTINE_RELEASED
+	case connectors.QuarantineReturned:
+		protoState = QuarantineState_QUARANTINE_RETURNED
+	default:
+		return protoState, errors.Errorf("unable convert unknown "+
+			"quarantine state: %v", state)
+	}
+
+	return protoState, nil
+}
+
 func convertMediaToProto(media connectors.PaymentMedia) (Media, error) {
 	var protoMedia Media
 	switch media {
@@ -131,6 +155,16 @@ func convertPaymentToProto(payment *connectors.Payment) (*Payment, error) {
 		return nil, err
 	}
 
+	quarantine, err := convertQuarantineStateToProto(payment.Quarantine)
+	if err != nil {
+		return nil, err
+	}
+
+	var fiatValue string
+	if payment.FiatCurrency != "" {
+		fiatValue = payment.FiatValue.String()
+	}
+
 	return &Payment{
 		PaymentId: payment.PaymentID,
 		UpdatedAt: payment.UpdatedAt,
@@ -143,6 +177,215 @@ func convertPaymentToProto(payment *connectors.Payment) (*Payment, error) {
 		Amount:    payment.Amount.String(),
 		MediaFee:  payment.MediaFee.String(),
 		MediaId:   payment.MediaID,
+		Account:   payment.AccountID,
+		Metadata:  payment.Metadata,
+		RefundOf:  payment.RefundOf,
+		Sequence:  payment.Sequence,
+
+		Quarantine:       quarantine,
+		QuarantineReason: payment.QuarantineReason,
+
+		FiatValue:    fiatValue,
+		FiatCurrency: payment.FiatCurrency,
+	}, nil
+}
+
+// refundableAmount returns the part of the payment which hasn't been
+// refunded yet. Failed refunds haven't returned anything, that is why they
+// are not taken into account.
+func refundableAmount(payment *connectors.Payment,
+	refunds []*connectors.Payment) decimal.Decimal {
+	amount := payment.Amount
+	for _, refund := range refunds {
+		if refund.Status != connectors.Failed {
+			amount = amount.Sub(refund.Amount)
+		}
+	}
+
+	return amount
+}
+
+// maxAccountLength is the maximum length of the account identifier.
+const maxAccountLength = 64
+
+// isValidAccount returns true if account identifier is empty, or consists
+// of the printable ASCII characters.
+func isValidAccount(account string) bool {
+	if len(account) > maxAccountLength {
+		return false
+	}
+
+	for _, c := range account {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+const (
+	// maxMetadataLabels is the maximum number of the labels in the
+	// metadata of the payment or receipt.
+	maxMetadataLabels = 16
+
+	// maxLabelValueLength is the maximum length of the label value.
+	maxLabelValueLength = 256
+)
+
+// isValidMetadata returns true if metadata has limited number of the
+// labels, keys of which are valid identifiers, and values of which are
+// limited UTF-8 strings.
+func isValidMetadata(metadata map[string]string) bool {
+	if len(metadata) > maxMetadataLabels {
+		return false
+	}
+
+	for key, value := range metadata {
+		if key == "" || !isValidAccount(key) {
+			return false
+		}
+
+		if len(value) > maxLabelValueLength || !utf8.ValidString(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidColor returns true if color is empty, or is in form of "#rrggbb".
+func isValidColor(color string) bool {
+	if color == "" {
+		return true
+	}
+
+	if len(color) != 7 || color[0] != '#' {
+		return false
+	}
+
+	_, err := hex.DecodeString(color[1:])
+	return err == nil
+}
+
+// isValidBrandingURL returns true if URL is empty, or is the absolute URL
+// with https scheme, or http scheme if insecure URLs are allowed.
+func isValidBrandingURL(rawURL string, allowInsecure bool) bool {
+	if rawURL == "" {
+		return true
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	return u.Scheme == "https" || (allowInsecure && u.Scheme == "http")
+}
+
+// convertBrandingToProto converts branding to the proto message.
+func convertBrandingToProto(branding *connectors.Branding) *Branding {
+	return &Branding{
+		Tenant:       branding.Tenant,
+		LogoUrl:      branding.LogoURL,
+		PrimaryColor: branding.PrimaryColor,
+		AccentColor:  branding.AccentColor,
+		SuccessUrl:   branding.SuccessURL,
+	}
+}
+
+// includePaymentFields fills the heavy fields of the payment which are
+// requested by the client. Warnings are computed by the send methods
+// themselves.
+func includePaymentFields(protoPayment *Payment, payment *connectors.Payment,
+	include []PaymentInclude) {
+
+	for _, field := range include {
+		switch field {
+		case PaymentInclude_MEMO:
+			protoPayment.Memo = payment.Memo
+
+		case PaymentInclude_FLAGS:
+			protoPayment.Flags = payment.Flags
+
+		case PaymentInclude_CONFIRMATIONS:
+			details, ok := payment.Detail.(*connectors.BlockchainPendingDetails)
+			if ok {
+				protoPayment.Confirmations = details.Confirmations
+				protoPayment.ConfirmationsLeft = details.ConfirmationsLeft
+			}
+		}
+	}
+}
+
+// isIncluded returns true if field is requested by the client.
+func isIncluded(include []PaymentInclude, field PaymentInclude) bool {
+	for _, included := range include {
+		if included == field {
+			return true
+		}
+	}
+	return false
+}
+
+func convertPayeeToProto(payee *connectors.Payee) (*Payee, error) {
+	asset, err := convertAssetToProto(payee.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := convertMediaToProto(payee.Media)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Payee{
+		Name:    payee.Name,
+		Asset:   asset,
+		Media:   media,
+		Receipt: payee.Receipt,
+		Amount:  payee.Amount.String(),
+	}, nil
+}
+
+func convertWatchAddressToProto(address *connectors.WatchAddress) (
+	*WatchAddress, error) {
+	asset, err := convertAssetToProto(address.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WatchAddress{
+		Group:    address.Group,
+		Asset:    asset,
+		Address:  address.Address,
+		Account:  address.Account,
+		Inactive: address.Inactive,
+	}, nil
+}
+
+func convertWatchEventToProto(event *connectors.WatchEvent) (*WatchEvent,
+	error) {
+	asset, err := convertAssetToProto(event.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	direction, err := convertPaymentDirectionToProto(event.Direction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WatchEvent{
+		EventId:   event.EventID,
+		CreatedAt: event.CreatedAt,
+		Group:     event.Group,
+		Asset:     asset,
+		Address:   event.Address,
+		Direction: direction,
+		Amount:    event.Amount.String(),
+		TxId:      event.TxID,
+		Account:   event.Account,
 	}, nil
 }
 
@@ -168,6 +411,26 @@ func ConvertPaymentStatusFromProto(protoStatus PaymentStatus) (
 	return status, nil
 }
 
+func ConvertPaymentsSortFromProto(protoSort PaymentsSortBy) (
+	connectors.PaymentsSortField, error) {
+	var field connectors.PaymentsSortField
+	switch protoSort {
+	case PaymentsSortBy_SORT_UPDATED_AT:
+		field = connectors.SortByUpdatedAt
+	case PaymentsSortBy_SORT_AMOUNT:
+		field = connectors.SortByAmount
+	case PaymentsSortBy_SORT_STATUS:
+		field = connectors.SortByStatus
+	case PaymentsSortBy_SORT_SEQUENCE:
+		field = connectors.SortBySequence
+	default:
+		return field, errors.Errorf("unable convert unknown sort field: %v",
+			protoSort)
+	}
+
+	return field, nil
+}
+
 func ConvertAssetFromProto(protoAsset Asset) (connectors.Asset, error) {
 	var asset connectors.Asset
 	switch protoAsset {
@@ -227,6 +490,26 @@ func ConvertPaymentSystemFromProto(protoSystem PaymentSystem) (
 	return system, nil
 }
 
+func ConvertQuarantineStateFromProto(protoState QuarantineState) (
+	connectors.QuarantineState, error) {
+	var state connectors.QuarantineState
+	switch protoState {
+	case QuarantineState_QUARANTINED:
+		state = connectors.Quarantined
+	case QuarantineState_QUARANTINE_RELEASED:
+		state = connectors.QuarantineReleased
+	case QuarantineState_QUARANTINE_RETURNED:
+		state = connectors.QuarantineReturned
+	case QuarantineState_QUARANTINE_NONE:
+		state = connectors.NotQuarantined
+	default:
+		return state, errors.Errorf("unable convert unknown quarantine "+
+			"state: %v", protoState)
+	}
+
+	return state, nil
+}
+
 func ConvertMediaFromProto(protoMedia Media) (connectors.PaymentMedia, error) {
 	var media connectors.PaymentMedia
 	switch protoMedia {
@@ -243,3 +526,228 @@ func ConvertMediaFromProto(protoMedia Media) (connectors.PaymentMedia, error) {
 
 	return media, nil
 }
+
+func convertAPIKeyScopeToProto(scope connectors.APIKeyScope) (APIKeyScope,
+	error) {
+	var protoScope APIKeyScope
+	switch scope {
+	case connectors.ReceiveScope:
+		protoScope = APIKeyScope_SCOPE_RECEIVE
+	case connectors.SendScope:
+		protoScope = APIKeyScope_SCOPE_SEND
+	case connectors.AdminScope:
+		protoScope = APIKeyScope_SCOPE_ADMIN
+	default:
+		return protoScope, errors.Errorf("unable convert unknown scope: %v",
+			scope)
+	}
+
+	return protoScope, nil
+}
+
+func ConvertAPIKeyScopeFromProto(protoScope APIKeyScope) (
+	connectors.APIKeyScope, error) {
+	var scope connectors.APIKeyScope
+	switch protoScope {
+	case APIKeyScope_SCOPE_RECEIVE:
+		scope = connectors.ReceiveScope
+	case APIKeyScope_SCOPE_SEND:
+		scope = connectors.SendScope
+	case APIKeyScope_SCOPE_ADMIN:
+		scope = connectors.AdminScope
+	default:
+		return scope, errors.Errorf("unable convert unknown scope: %v",
+			protoScope)
+	}
+
+	return scope, nil
+}
+
+func convertAPIKeyToProto(key *connectors.APIKey) (*APIKey, error) {
+	scopes := make([]APIKeyScope, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		protoScope, err := convertAPIKeyScopeToProto(scope)
+		if err != nil {
+			return nil, err
+		}
+		scopes[i] = protoScope
+	}
+
+	return &APIKey{
+		Id:        key.ID,
+		Name:      key.Name,
+		Scopes:    scopes,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}, nil
+}
+
+func convertReceiptStatusToProto(status connectors.ReceiptStatus) (
+	ReceiptStatus, error) {
+	var protoStatus ReceiptStatus
+	switch status {
+	case connectors.ReceiptPaid:
+		protoStatus = ReceiptStatus_PAID
+	case connectors.ReceiptUnpaid:
+		protoStatus = ReceiptStatus_UNPAID
+	case connectors.ReceiptExpired:
+		protoStatus = ReceiptStatus_EXPIRED
+	default:
+		return protoStatus, errors.Errorf("unable convert unknown receipt "+
+			"status: %v", status)
+	}
+
+	return protoStatus, nil
+}
+
+func ConvertReceiptStatusFromProto(protoStatus ReceiptStatus) (
+	connectors.ReceiptStatus, error) {
+	var status connectors.ReceiptStatus
+	switch protoStatus {
+	case ReceiptStatus_PAID:
+		status = connectors.ReceiptPaid
+	case ReceiptStatus_UNPAID:
+		status = connectors.ReceiptUnpaid
+	case ReceiptStatus_EXPIRED:
+		status = connectors.ReceiptExpired
+	case ReceiptStatus_RECEIPT_STATUS_NONE:
+		status = ""
+	default:
+		return status, errors.Errorf("unable convert unknown receipt "+
+			"status: %v", protoStatus)
+	}
+
+	return status, nil
+}
+
+func convertReceiptToProto(receipt *connectors.Receipt) (*Receipt, error) {
+	asset, err := convertAssetToProto(receipt.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := convertMediaToProto(receipt.Media)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := convertReceiptStatusToProto(receipt.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Receipt{
+		ReceiptId:   receipt.ReceiptID,
+		Receipt:     receipt.Receipt,
+		Asset:       asset,
+		Media:       media,
+		Amount:      receipt.Amount.String(),
+		Description: receipt.Description,
+		CreatedAt:   receipt.CreatedAt,
+		ExpiresAt:   receipt.ExpiresAt,
+		Status:      status,
+		Metadata:    receipt.Metadata,
+		Replaces:    receipt.Replaces,
+		ReplacedBy:  receipt.ReplacedBy,
+		Reference:   receipt.Reference,
+		DescriptionHash: hex.EncodeToString(
+			receipt.DescriptionHash()),
+		RefundAddress: receipt.RefundAddress,
+	}, nil
+}
+
+func convertBalanceSnapshotToProto(snapshot *connectors.BalanceSnapshot) (
+	*BalanceSnapshot, error) {
+	asset, err := convertAssetToProto(snapshot.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := convertMediaToProto(snapshot.Media)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BalanceSnapshot{
+		CreatedAt: snapshot.CreatedAt,
+		Balance: &Balance{
+			Asset:       asset,
+			Media:       media,
+			Available:   snapshot.Available.String(),
+			Pending:     snapshot.Pending.String(),
+			Quarantined: snapshot.Quarantined.String(),
+		},
+	}, nil
+}
+
+func convertPaymentEventTypeToProto(eventType connectors.PaymentEventType) (
+	PaymentEventType, error) {
+	var protoType PaymentEventType
+	switch eventType {
+	case connectors.EventCreated:
+		protoType = PaymentEventType_EVENT_CREATED
+	case connectors.EventBroadcast:
+		protoType = PaymentEventType_EVENT_BROADCAST
+	case connectors.EventFirstSeen:
+		protoType = PaymentEventType_EVENT_FIRST_SEEN
+	case connectors.EventConfirmed:
+		protoType = PaymentEventType_EVENT_CONFIRMED
+	case connectors.EventCompleted:
+		protoType = PaymentEventType_EVENT_COMPLETED
+	case connectors.EventFailed:
+		protoType = PaymentEventType_EVENT_FAILED
+	case connectors.EventHookDelivered:
+		protoType = PaymentEventType_EVENT_HOOK_DELIVERED
+	default:
+		return protoType, errors.Errorf("unable convert unknown payment "+
+			"event type: %v", eventType)
+	}
+
+	return protoType, nil
+}
+
+func convertPaymentTimelineToProto(events []*connectors.PaymentEvent) (
+	[]*PaymentEvent, error) {
+	timeline := make([]*PaymentEvent, len(events))
+	for i, event := range events {
+		eventType, err := convertPaymentEventTypeToProto(event.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		timeline[i] = &PaymentEvent{
+			Type:    eventType,
+			Time:    event.Time,
+			Details: event.Details,
+		}
+	}
+
+	return timeline, nil
+}
+
+// convertTimeLockToProto converts time lock and determines whether it could
+// be spent at the given best height of the blockchain.
+func convertTimeLockToProto(lock *connectors.TimeLock, bestHeight int64,
+	now time.Time) *TimeLock {
+
+	protoLock := &TimeLock{
+		PaymentId:       lock.PaymentID,
+		Asset:           Asset(Asset_value[string(lock.Asset)]),
+		Address:         lock.Address,
+		RedeemScript:    hex.EncodeToString(lock.RedeemScript),
+		RecipientPubkey: lock.RecipientPubKey,
+		CreatedAt:       lock.CreatedAt,
+		Unlocked:        lock.IsUnlocked(bestHeight, now),
+	}
+
+	if lock.IsHeight() {
+		protoLock.LockHeight = lock.LockTime
+		if !protoLock.Unlocked {
+			protoLock.BlocksLeft = int64(lock.LockTime) - bestHeight
+		}
+	} else {
+		protoLock.LockTime = int64(lock.LockTime)
+	}
+
+	return protoLock
+}
@@ -0,0 +1,149 @@
+package crpc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+)
+
+func TestIncludePaymentFields(t *testing.T) {
+	payment := &connectors.Payment{
+		Memo:  "memo",
+		Flags: []string{"rbf"},
+		Detail: &connectors.BlockchainPendingDetails{
+			Confirmations:     2,
+			ConfirmationsLeft: 4,
+		},
+	}
+
+	protoPayment := &Payment{}
+	includePaymentFields(protoPayment, payment, nil)
+	if protoPayment.Memo != "" || len(protoPayment.Flags) != 0 ||
+		protoPayment.Confirmations != 0 {
+		t.Fatalf("fields shouldn't be returned if they aren't requested: %v",
+			protoPayment)
+	}
+
+	includePaymentFields(protoPayment, payment,
+		[]PaymentInclude{PaymentInclude_MEMO})
+	if protoPayment.Memo != "memo" || len(protoPayment.Flags) != 0 {
+		t.Fatalf("wrong included fields: %v", protoPayment)
+	}
+
+	includePaymentFields(protoPayment, payment,
+		[]PaymentInclude{PaymentInclude_FLAGS})
+	if len(protoPayment.Flags) != 1 || protoPayment.Flags[0] != "rbf" {
+		t.Fatalf("wrong flags: %v", protoPayment)
+	}
+
+	includePaymentFields(protoPayment, payment,
+		[]PaymentInclude{PaymentInclude_CONFIRMATIONS})
+	if protoPayment.Confirmations != 2 ||
+		protoPayment.ConfirmationsLeft != 4 {
+		t.Fatalf("wrong confirmations: %v", protoPayment)
+	}
+}
+
+func TestConvertTimeLockToProto(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	lock := &connectors.TimeLock{
+		Asset:        connectors.BTC,
+		RedeemScript: []byte{0xb1},
+		LockTime:     100,
+	}
+
+	protoLock := convertTimeLockToProto(lock, 90, now)
+	if protoLock.Unlocked || protoLock.BlocksLeft != 10 ||
+		protoLock.LockHeight != 100 || protoLock.LockTime != 0 {
+		t.Fatalf("wrong lock by height: %v", protoLock)
+	}
+
+	if protoLock.Asset != Asset_BTC || protoLock.RedeemScript != "b1" {
+		t.Fatalf("wrong lock fields: %v", protoLock)
+	}
+
+	protoLock = convertTimeLockToProto(lock, 100, now)
+	if !protoLock.Unlocked || protoLock.BlocksLeft != 0 {
+		t.Fatalf("lock by height should be unlocked: %v", protoLock)
+	}
+
+	lock.LockTime = uint32(now.Unix() + 60)
+	protoLock = convertTimeLockToProto(lock, 100, now)
+	if protoLock.Unlocked || protoLock.LockHeight != 0 ||
+		protoLock.LockTime != now.Unix()+60 {
+		t.Fatalf("wrong lock by time: %v", protoLock)
+	}
+
+	protoLock = convertTimeLockToProto(lock, 100, now.Add(time.Minute))
+	if !protoLock.Unlocked {
+		t.Fatalf("lock by time should be unlocked: %v", protoLock)
+	}
+}
+
+func TestIsValidMetadata(t *testing.T) {
+	if !isValidMetadata(map[string]string{"order_id": "1001", "note": ""}) {
+		t.Fatalf("metadata should be valid")
+	}
+
+	invalid := []map[string]string{
+		{"": "1001"},
+		{"order id": "1001"},
+		{"order_id": strings.Repeat("x", maxLabelValueLength+1)},
+		{"order_id": "\xff"},
+	}
+
+	for _, metadata := range invalid {
+		if isValidMetadata(metadata) {
+			t.Fatalf("metadata(%v) shouldn't be valid", metadata)
+		}
+	}
+
+	tooMany := make(map[string]string)
+	for i := 0; i <= maxMetadataLabels; i++ {
+		tooMany[fmt.Sprintf("label%v", i)] = "value"
+	}
+
+	if isValidMetadata(tooMany) {
+		t.Fatalf("metadata with too many labels shouldn't be valid")
+	}
+}
+
+func TestBrandingValidation(t *testing.T) {
+	colors := map[string]bool{
+		"":         true,
+		"#0a1B2c":  true,
+		"0a1b2c":   false,
+		"#fff":     false,
+		"#gggggg":  false,
+		"#0a1b2c3": false,
+	}
+
+	for color, valid := range colors {
+		if isValidColor(color) != valid {
+			t.Fatalf("color(%v) validity should be %v", color, valid)
+		}
+	}
+
+	urls := []struct {
+		url           string
+		allowInsecure bool
+		valid         bool
+	}{
+		{"", false, true},
+		{"https://partner.example/logo.png", false, true},
+		{"http://partner.example/logo.png", false, false},
+		{"http://partner.example/thanks", true, true},
+		{"javascript:alert(1)", true, false},
+		{"/thanks", true, false},
+	}
+
+	for _, test := range urls {
+		if isValidBrandingURL(test.url, test.allowInsecure) != test.valid {
+			t.Fatalf("url(%v) validity should be %v", test.url, test.valid)
+		}
+	}
+}
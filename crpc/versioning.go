@@ -0,0 +1,267 @@
+package crpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// APIVersion is the current version of the PayServer and Admin
+	// services, under which they are served in the versioned package,
+	// e.g. "crpc.v1.PayServer".
+	APIVersion = "v1"
+
+	// legacyPackage is the unversioned package of the services, calls of
+	// the services in it are served, but are deprecated.
+	legacyPackage = "crpc"
+
+	// versionedPackage is the package of the current version of the
+	// services.
+	versionedPackage = legacyPackage + "." + APIVersion
+
+	// versionedProtoFile is the name of the descriptor of the versioned
+	// services, which is returned by the server reflection.
+	versionedProtoFile = "crpc/" + APIVersion + ".proto"
+
+	// DeprecationMetadataKey is the key of the response header through
+	// which client is notified that it calls the deprecated service.
+	DeprecationMetadataKey = "payserver-deprecated"
+)
+
+// versionedServices are the services which are served in the versioned
+// package. Address provider and federation services are called by the
+// server rather than by the clients, that is why they are left as is.
+var versionedServices = map[string]*grpc.ServiceDesc{
+	_PayServer_serviceDesc.ServiceName: &_PayServer_serviceDesc,
+	_Admin_serviceDesc.ServiceName:     &_Admin_serviceDesc,
+}
+
+func init() {
+	data, err := versionedFileDescriptor()
+	if err != nil {
+		panic(err)
+	}
+	proto.RegisterFile(versionedProtoFile, data)
+}
+
+// versionedFileDescriptor returns the gzipped descriptor of the file which
+// declares the versioned services. Services are copied from the descriptor
+// of rpc.proto, so that both versions are always in sync, and are
+// referencing its messages, which are not versioned.
+func versionedFileDescriptor() ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(fileDescriptor0))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	legacy := &descriptor.FileDescriptorProto{}
+	if err := proto.Unmarshal(data, legacy); err != nil {
+		return nil, err
+	}
+
+	file := &descriptor.FileDescriptorProto{
+		Name:       proto.String(versionedProtoFile),
+		Package:    proto.String(versionedPackage),
+		Dependency: []string{legacy.GetName()},
+		Syntax:     legacy.Syntax,
+	}
+
+	for _, service := range legacy.Service {
+		name := legacyPackage + "." + service.GetName()
+		if _, ok := versionedServices[name]; ok {
+			file.Service = append(file.Service, service)
+		}
+	}
+
+	data, err = proto.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// versionedServiceDesc returns the descriptor of the service in the
+// versioned package. Handlers are shared, that is why methods of both
+// versions are seen by the unary interceptors under the legacy name.
+func versionedServiceDesc(desc *grpc.ServiceDesc) *grpc.ServiceDesc {
+	versioned := *desc
+	versioned.ServiceName = versionedPackage + "." +
+		strings.TrimPrefix(desc.ServiceName, legacyPackage+".")
+	versioned.Metadata = versionedProtoFile
+	return &versioned
+}
+
+// RegisterVersionedPayServerServer registers the server under the versioned
+// name of the PayServer service, e.g. "crpc.v1.PayServer".
+func RegisterVersionedPayServerServer(s *grpc.Server, srv PayServerServer) {
+	s.RegisterService(versionedServiceDesc(&_PayServer_serviceDesc), srv)
+}
+
+// RegisterVersionedAdminServer registers the server under the versioned name
+// of the Admin service, e.g. "crpc.v1.Admin".
+func RegisterVersionedAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(versionedServiceDesc(&_Admin_serviceDesc), srv)
+}
+
+// splitFullMethod splits the full gRPC method name on the service and the
+// method, e.g. "crpc.v1.Admin" and "SyncUnspent" from
+// "/crpc.v1.Admin/SyncUnspent".
+func splitFullMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	i := strings.LastIndex(fullMethod, "/")
+	if i < 0 {
+		return "", fullMethod
+	}
+	return fullMethod[:i], fullMethod[i+1:]
+}
+
+// canonicalMethod returns the name under which the method of the versioned
+// service is known to the interceptors, e.g. "/crpc.PayServer/SendPayment"
+// from "/crpc.v1.PayServer/SendPayment". Names of the other methods are
+// returned as is.
+func canonicalMethod(fullMethod string) string {
+	service, method := splitFullMethod(fullMethod)
+	if !strings.HasPrefix(service, versionedPackage+".") {
+		return fullMethod
+	}
+
+	legacy := legacyPackage + "." +
+		strings.TrimPrefix(service, versionedPackage+".")
+	if _, ok := versionedServices[legacy]; !ok {
+		return fullMethod
+	}
+
+	return "/" + legacy + "/" + method
+}
+
+// VersionedMethod returns the name of the method in the versioned service,
+// e.g. "/crpc.v1.PayServer/SendPayment" from "/crpc.PayServer/SendPayment".
+// Names of the other methods are returned as is.
+func VersionedMethod(fullMethod string) string {
+	service, method := splitFullMethod(fullMethod)
+	desc, ok := versionedServices[service]
+	if !ok {
+		return fullMethod
+	}
+
+	return "/" + versionedServiceDesc(desc).ServiceName + "/" + method
+}
+
+// isDeprecatedMethod returns true if the method is called in the legacy
+// service, which has the versioned replacement.
+func isDeprecatedMethod(fullMethod string) bool {
+	service, _ := splitFullMethod(fullMethod)
+	_, ok := versionedServices[service]
+	return ok
+}
+
+// deprecationWarnings is used to log the deprecated call of the method
+// only once, rather than on every call.
+var deprecationWarnings sync.Map
+
+// deprecationHeader returns the header which notifies the client about the
+// call of the deprecated method, and logs the call once per method.
+func deprecationHeader(fullMethod string) metadata.MD {
+	if _, logged := deprecationWarnings.LoadOrStore(fullMethod, true); !logged {
+		log.Warnf("Deprecated method %v is called, clients should "+
+			"switch to %v", fullMethod, VersionedMethod(fullMethod))
+	}
+
+	return metadata.Pairs(DeprecationMetadataKey,
+		"use "+VersionedMethod(fullMethod))
+}
+
+// VersionUnaryInterceptor notifies the clients which are calling methods of
+// the legacy unversioned services, that they should switch to the
+// versioned ones. It should be the outermost interceptor.
+func VersionUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (
+		interface{}, error) {
+
+		// Unary handlers are shared by both versions, and report the
+		// legacy name, that is why the called one is taken from the
+		// stream.
+		if fullMethod, ok := grpc.Method(ctx); ok &&
+			isDeprecatedMethod(fullMethod) {
+			if err := grpc.SetHeader(ctx,
+				deprecationHeader(fullMethod)); err != nil {
+				log.Errorf("unable to set deprecation header: %v", err)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// VersionStreamInterceptor notifies the clients which are calling methods of
+// the legacy unversioned services, that they should switch to the
+// versioned ones, and passes the methods of the versioned services to the
+// next interceptors under their canonical names. It should be the
+// outermost interceptor.
+func VersionStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		if isDeprecatedMethod(info.FullMethod) {
+			err := stream.SetHeader(deprecationHeader(info.FullMethod))
+			if err != nil {
+				log.Errorf("unable to set deprecation header: %v", err)
+			}
+		}
+
+		// Info is created by the server for every call, and is shared
+		// by the chained interceptors, that is why it is updated in
+		// place.
+		info.FullMethod = canonicalMethod(info.FullMethod)
+
+		return handler(srv, stream)
+	}
+}
+
+// VersionUnaryClientInterceptor calls methods of the versioned services
+// instead of the legacy ones, which are used by the generated clients.
+func VersionUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+
+		return invoker(ctx, VersionedMethod(method), req, reply, cc, opts...)
+	}
+}
+
+// VersionStreamClientInterceptor calls streaming methods of the versioned
+// services instead of the legacy ones, which are used by the generated
+// clients.
+func VersionStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc,
+		cc *grpc.ClientConn, method string, streamer grpc.Streamer,
+		opts ...grpc.CallOption) (grpc.ClientStream, error) {
+
+		return streamer(ctx, desc, cc, VersionedMethod(method), opts...)
+	}
+}
@@ -0,0 +1,97 @@
+package crpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"golang.org/x/net/context"
+)
+
+func TestVersionedMethod(t *testing.T) {
+	tests := []struct {
+		legacy    string
+		versioned string
+	}{
+		{
+			legacy:    "/crpc.PayServer/SendPayment",
+			versioned: "/crpc.v1.PayServer/SendPayment",
+		},
+		{
+			legacy:    "/crpc.Admin/SyncUnspent",
+			versioned: "/crpc.v1.Admin/SyncUnspent",
+		},
+		{
+			// Services called by the server are not versioned.
+			legacy:    "/crpc.Federation/SettleTransfer",
+			versioned: "/crpc.Federation/SettleTransfer",
+		},
+		{
+			legacy:    "/grpc.health.v1.Health/Check",
+			versioned: "/grpc.health.v1.Health/Check",
+		},
+	}
+
+	for _, test := range tests {
+		if VersionedMethod(test.legacy) != test.versioned {
+			t.Fatalf("wrong versioned method of %v: %v", test.legacy,
+				VersionedMethod(test.legacy))
+		}
+
+		if canonicalMethod(test.versioned) != test.legacy {
+			t.Fatalf("wrong canonical method of %v: %v", test.versioned,
+				canonicalMethod(test.versioned))
+		}
+	}
+
+	if !isDeprecatedMethod("/crpc.PayServer/SendPayment") ||
+		isDeprecatedMethod("/crpc.v1.PayServer/SendPayment") {
+		t.Fatal("wrong deprecated method")
+	}
+}
+
+func TestVersionedFileDescriptor(t *testing.T) {
+	r, err := gzip.NewReader(bytes.NewReader(
+		proto.FileDescriptor(versionedProtoFile)))
+	if err != nil {
+		t.Fatalf("descriptor isn't registered: %v", err)
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unable to read descriptor: %v", err)
+	}
+
+	file := &descriptor.FileDescriptorProto{}
+	if err := proto.Unmarshal(data, file); err != nil {
+		t.Fatalf("unable to decode descriptor: %v", err)
+	}
+
+	if file.GetPackage() != "crpc.v1" || len(file.Service) != 2 {
+		t.Fatalf("wrong descriptor: %v", file)
+	}
+
+	for _, service := range file.Service {
+		desc := versionedServices["crpc."+service.GetName()]
+		if desc == nil {
+			t.Fatalf("unknown service: %v", service.GetName())
+		}
+
+		if len(service.Method) != len(desc.Methods)+len(desc.Streams) {
+			t.Fatalf("methods of %v are missing", service.GetName())
+		}
+	}
+}
+
+func TestVersionedMethodPermission(t *testing.T) {
+	// Versioned methods are checked in the same way as the legacy ones,
+	// rather than passed as methods of the unknown service.
+	err := checkAdminToken(context.Background(), "/crpc.v1.Admin/SyncUnspent",
+		"token")
+	if err == nil {
+		t.Fatal("versioned admin method is called without the token")
+	}
+}
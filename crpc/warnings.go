@@ -0,0 +1,89 @@
+package crpc
+
+import (
+	"strconv"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/locale"
+	"github.com/shopspring/decimal"
+	"golang.org/x/net/context"
+)
+
+var (
+	// feeWarningRatio is the ratio of fee to the payment amount, after
+	// which payment fee is considered to be too high.
+	feeWarningRatio = decimal.New(5, -2)
+
+	// addressReuseWarningThreshold is the number of outgoing payments to
+	// the same address after which address is considered to be reused too
+	// many times.
+	addressReuseWarningThreshold = 10
+)
+
+// feeWarning returns warning if payment fee exceeds the allowed ratio of
+// the payment amount.
+func (s *Server) feeWarning(ctx context.Context, amount,
+	fee decimal.Decimal) []string {
+	if amount.Cmp(decimal.Zero) <= 0 {
+		return nil
+	}
+
+	if fee.Div(amount).Cmp(feeWarningRatio) <= 0 {
+		return nil
+	}
+
+	percent := feeWarningRatio.Mul(decimal.New(100, 0))
+	return []string{s.messages.ForTenant(apiKeyIDFromContext(ctx),
+		locale.FeeTooHigh, locale.Args{"percent": percent.String()})}
+}
+
+// addressReuseWarning returns warning if we have already sent funds to the
+// given address more times than it is considered safe. Warnings are
+// advisory, that is why error of the payment store is only logged.
+func (s *Server) addressReuseWarning(ctx context.Context, address,
+	excludePaymentID string) []string {
+	stop := trackStage(ctx, stageDB)
+	payments, err := s.paymentsStore.PaymentByReceipt(address)
+	stop()
+	if err != nil {
+		log.Warnf("unable to fetch payments by receipt(%v): %v", address, err)
+		return nil
+	}
+
+	var reused int
+	for _, payment := range payments {
+		if payment.Direction != connectors.Outgoing ||
+			payment.PaymentID == excludePaymentID {
+			continue
+		}
+
+		reused++
+	}
+
+	if reused < addressReuseWarningThreshold {
+		return nil
+	}
+
+	return []string{s.messages.ForTenant(apiKeyIDFromContext(ctx),
+		locale.AddressReused, locale.Args{"count": strconv.Itoa(reused)})}
+}
+
+// inboundLiquidityWarning returns warning if lightning node is unable to
+// receive the given amount through its active channels.
+func (s *Server) inboundLiquidityWarning(ctx context.Context,
+	c connectors.LightningConnector, amount decimal.Decimal) []string {
+	stop := trackStage(ctx, stageNode)
+	capacity, err := c.InboundCapacity()
+	stop()
+	if err != nil {
+		log.Warnf("unable to fetch inbound capacity: %v", err)
+		return nil
+	}
+
+	if capacity.Sign() > 0 && capacity.Cmp(amount) >= 0 {
+		return nil
+	}
+
+	return []string{s.messages.ForTenant(apiKeyIDFromContext(ctx),
+		locale.InboundLiquidityLow, locale.Args{"capacity": capacity.String()})}
+}
@@ -0,0 +1,223 @@
+package crpc
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/metrics"
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// defaultPauseReason is the reason of the pause, which is used if operator
+// hasn't given one.
+const defaultPauseReason = "paused by the operator"
+
+// withdrawalPauses keeps the pauses of the outgoing payments in memory, so
+// that every payment could be checked without the database lookup, and
+// writes them through to the store, so that they survive the restart.
+type withdrawalPauses struct {
+	mtx    sync.RWMutex
+	store  connectors.WithdrawalPausesStore
+	pauses map[connectors.Asset]*connectors.WithdrawalPause
+}
+
+// newWithdrawalPauses creates the pauses, and loads the ones which were
+// set before the restart.
+func newWithdrawalPauses(
+	store connectors.WithdrawalPausesStore) (*withdrawalPauses, error) {
+	saved, err := store.Pauses()
+	if err != nil {
+		return nil, errors.Errorf("unable to load withdrawal pauses: %v", err)
+	}
+
+	pauses := make(map[connectors.Asset]*connectors.WithdrawalPause,
+		len(saved))
+	for _, pause := range saved {
+		log.Warnf("Withdrawals of asset(%v) are paused: %v", pause.Asset,
+			pause.Reason)
+		pauses[pause.Asset] = pause
+	}
+
+	return &withdrawalPauses{
+		store:  store,
+		pauses: pauses,
+	}, nil
+}
+
+// pause saves the pause, replacing the previous pause of the same asset.
+func (w *withdrawalPauses) pause(pause *connectors.WithdrawalPause) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if err := w.store.SavePause(pause); err != nil {
+		return err
+	}
+
+	w.pauses[pause.Asset] = pause
+	return nil
+}
+
+// resume removes the pause of the asset, and returns whether it was paused.
+func (w *withdrawalPauses) resume(asset connectors.Asset) (bool, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if err := w.store.DeletePause(asset); err != nil {
+		return false, err
+	}
+
+	_, ok := w.pauses[asset]
+	delete(w.pauses, asset)
+	return ok, nil
+}
+
+// paused returns the pause of the asset, or nil if its withdrawals aren't
+// paused.
+func (w *withdrawalPauses) paused(
+	asset connectors.Asset) *connectors.WithdrawalPause {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	return w.pauses[asset]
+}
+
+// list returns all pauses ordered by the asset.
+func (w *withdrawalPauses) list() []*connectors.WithdrawalPause {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	pauses := make([]*connectors.WithdrawalPause, 0, len(w.pauses))
+	for _, pause := range w.pauses {
+		pauses = append(pauses, pause)
+	}
+
+	sort.Slice(pauses, func(i, j int) bool {
+		return pauses[i].Asset < pauses[j].Asset
+	})
+
+	return pauses
+}
+
+//
+// PauseWithdrawals rejects every outgoing payment of the asset, in both
+// media, until withdrawals are resumed, so that funds stop leaving the
+// wallet during the incident without the shutdown of the service. Pause is
+// kept across restarts.
+func (s *Server) PauseWithdrawals(ctx context.Context,
+	req *PauseWithdrawalsRequest) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.Asset == Asset_ASSET_NONE {
+		err := newErrInvalidArgument("asset")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = defaultPauseReason
+	}
+
+	pause := &connectors.WithdrawalPause{
+		Asset:    connectors.Asset(req.Asset.String()),
+		Reason:   reason,
+		PausedAt: connectors.ConvertTimeToMilliSeconds(time.Now()),
+	}
+
+	stop := trackStage(ctx, stageDB)
+	err := s.withdrawals.pause(pause)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+		return nil, err
+	}
+
+	log.Warnf("Withdrawals of asset(%v) have been paused: %v", pause.Asset,
+		pause.Reason)
+
+	resp := &EmptyResponse{}
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ResumeWithdrawals removes the pause of the outgoing payments of the
+// asset.
+func (s *Server) ResumeWithdrawals(ctx context.Context,
+	req *ResumeWithdrawalsRequest) (*EmptyResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	if req.Asset == Asset_ASSET_NONE {
+		err := newErrInvalidArgument("asset")
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.LowSeverity))
+		return nil, err
+	}
+
+	asset := connectors.Asset(req.Asset.String())
+
+	stop := trackStage(ctx, stageDB)
+	paused, err := s.withdrawals.resume(asset)
+	stop()
+	if err != nil {
+		err := newErrInternal(err.Error())
+		log.Errorf("command(%v), id(%v), error: %v",
+			common.GetFunctionName(), requestID, err)
+		s.metrics.AddError(common.GetFunctionName(), string(metrics.HighSeverity))
+		return nil, err
+	}
+
+	// Resume is idempotent, so that it could be safely retried.
+	if paused {
+		log.Infof("Withdrawals of asset(%v) have been resumed", asset)
+	}
+
+	resp := &EmptyResponse{}
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
+
+//
+// ListWithdrawalPauses returns assets which outgoing payments are paused.
+func (s *Server) ListWithdrawalPauses(ctx context.Context,
+	req *EmptyRequest) (*ListWithdrawalPausesResponse, error) {
+	requestID := rand.Int()
+
+	log.Tracef("command(%v), id(%v), request(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(req))
+
+	resp := &ListWithdrawalPausesResponse{}
+	for _, pause := range s.withdrawals.list() {
+		resp.Pauses = append(resp.Pauses, &WithdrawalPause{
+			Asset:    Asset(Asset_value[string(pause.Asset)]),
+			Reason:   pause.Reason,
+			PausedAt: pause.PausedAt,
+		})
+	}
+
+	log.Tracef("command(%v), id(%v), response(%v)", common.GetFunctionName(),
+		requestID, convertProtoMessage(resp))
+
+	return resp, nil
+}
@@ -0,0 +1,88 @@
+package crpc
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/status"
+)
+
+func TestPauseWithdrawals(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.stop()
+
+	ctx := context.Background()
+
+	send := func() error {
+		_, err := h.client.SendPayment(ctx, &SendPaymentRequest{
+			Asset:   Asset_BTC,
+			Media:   Media_BLOCKCHAIN,
+			Receipt: "recipient",
+			Amount:  "1",
+		})
+		return err
+	}
+
+	_, err := h.admin.PauseWithdrawals(ctx, &PauseWithdrawalsRequest{})
+	expectInvalidArgument(t, err, "asset")
+
+	_, err = h.admin.PauseWithdrawals(ctx, &PauseWithdrawalsRequest{
+		Asset:  Asset_BTC,
+		Reason: "hot wallet drain",
+	})
+	if err != nil {
+		t.Fatalf("unable to pause withdrawals: %v", err)
+	}
+
+	expected := newErrWithdrawalsPaused("BTC", "hot wallet drain").Error()
+	if msg := status.Convert(send()).Message(); msg != expected {
+		t.Fatalf("wrong error, expected(%v), got(%v)", expected, msg)
+	}
+
+	// Dry run doesn't send anything, that is why it is allowed, so that
+	// fee could still be checked.
+	_, err = h.client.SendPayment(ctx, &SendPaymentRequest{
+		Asset:   Asset_BTC,
+		Media:   Media_BLOCKCHAIN,
+		Receipt: "recipient",
+		Amount:  "1",
+		DryRun:  true,
+	})
+	if err != nil {
+		t.Fatalf("unable to make dry run: %v", err)
+	}
+
+	resp, err := h.admin.ListWithdrawalPauses(ctx, &EmptyRequest{})
+	if err != nil {
+		t.Fatalf("unable to list pauses: %v", err)
+	}
+
+	if len(resp.Pauses) != 1 || resp.Pauses[0].Asset != Asset_BTC ||
+		resp.Pauses[0].Reason != "hot wallet drain" ||
+		resp.Pauses[0].PausedAt == 0 {
+		t.Fatalf("wrong pauses: %v", resp.Pauses)
+	}
+
+	// Pause is loaded from the store after the restart.
+	restored, err := newWithdrawalPauses(h.server.withdrawals.store)
+	if err != nil {
+		t.Fatalf("unable to load pauses: %v", err)
+	}
+
+	if restored.paused("BTC") == nil {
+		t.Fatalf("pause isn't restored")
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = h.admin.ResumeWithdrawals(ctx, &ResumeWithdrawalsRequest{
+			Asset: Asset_BTC,
+		})
+		if err != nil {
+			t.Fatalf("unable to resume withdrawals: %v", err)
+		}
+	}
+
+	if err := send(); err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+}
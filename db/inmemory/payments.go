@@ -2,7 +2,9 @@ package inmemory
 
 import (
 	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
 	"sort"
+	"strings"
 	"sync"
 )
 
@@ -12,6 +14,10 @@ import (
 type MemoryPaymentsStore struct {
 	paymentsMutex sync.RWMutex
 	paymentsByID  map[string]*connectors.Payment
+	timelines     map[string][]*connectors.PaymentEvent
+
+	// sequence is the sequence number of the last change of the payments.
+	sequence uint64
 }
 
 // Runtime check to ensure that MemoryPaymentsStore implements
@@ -23,6 +29,7 @@ var _ connectors.PaymentsStore = (*MemoryPaymentsStore)(nil)
 func NewMemoryPaymentsStore() *MemoryPaymentsStore {
 	return &MemoryPaymentsStore{
 		paymentsByID: make(map[string]*connectors.Payment),
+		timelines:    make(map[string][]*connectors.PaymentEvent),
 	}
 }
 
@@ -64,7 +71,180 @@ func (s *MemoryPaymentsStore) SavePayment(p *connectors.Payment) error {
 
 	payment := &connectors.Payment{}
 	*payment = *p
+
+	// Flags are recorded when payment is created, and kept if payment is
+	// later regenerated without them, the same goes for the account,
+	// metadata, refund link and quarantine which are attached after payment
+	// is created.
+	old, ok := s.paymentsByID[p.PaymentID]
+	if ok {
+		if len(p.Flags) == 0 {
+			payment.Flags = old.Flags
+		}
+
+		if p.AccountID == "" {
+			payment.AccountID = old.AccountID
+		}
+
+		if len(p.Metadata) == 0 {
+			payment.Metadata = old.Metadata
+		}
+
+		if p.RefundOf == "" {
+			payment.RefundOf = old.RefundOf
+		}
+
+		if p.Quarantine == connectors.NotQuarantined {
+			payment.Quarantine = old.Quarantine
+			payment.QuarantineReason = old.QuarantineReason
+		}
+
+		// Fiat value is recorded once, at the moment of the completion.
+		if old.FiatCurrency != "" {
+			payment.FiatValue = old.FiatValue
+			payment.FiatCurrency = old.FiatCurrency
+		}
+	}
+
+	s.sequence++
+	payment.Sequence = s.sequence
 	s.paymentsByID[p.PaymentID] = payment
+
+	events := connectors.PaymentTimelineEvents(old, payment,
+		connectors.NowInMilliSeconds())
+	s.timelines[p.PaymentID] = append(s.timelines[p.PaymentID], events...)
+	return nil
+}
+
+// AddPaymentEvent appends the event to the timeline of the payment.
+func (s *MemoryPaymentsStore) AddPaymentEvent(
+	event *connectors.PaymentEvent) error {
+	s.paymentsMutex.Lock()
+	defer s.paymentsMutex.Unlock()
+
+	s.timelines[event.PaymentID] = append(s.timelines[event.PaymentID],
+		event)
+	return nil
+}
+
+// PaymentTimeline returns the events of the payment ordered by time.
+func (s *MemoryPaymentsStore) PaymentTimeline(paymentID string) (
+	[]*connectors.PaymentEvent, error) {
+	s.paymentsMutex.RLock()
+	defer s.paymentsMutex.RUnlock()
+
+	events := make([]*connectors.PaymentEvent, len(s.timelines[paymentID]))
+	copy(events, s.timelines[paymentID])
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time < events[j].Time
+	})
+
+	return events, nil
+}
+
+// SetPaymentAccount binds payment to the account.
+func (s *MemoryPaymentsStore) SetPaymentAccount(paymentID,
+	accountID string) error {
+	s.paymentsMutex.Lock()
+	defer s.paymentsMutex.Unlock()
+
+	old, ok := s.paymentsByID[paymentID]
+	if !ok {
+		return connectors.PaymentNotFound
+	}
+
+	payment := &connectors.Payment{}
+	*payment = *old
+	payment.AccountID = accountID
+
+	s.sequence++
+	payment.Sequence = s.sequence
+	s.paymentsByID[paymentID] = payment
+	return nil
+}
+
+// SetPaymentRefund links the refund to the incoming payment.
+func (s *MemoryPaymentsStore) SetPaymentRefund(paymentID,
+	refundOf string) error {
+	s.paymentsMutex.Lock()
+	defer s.paymentsMutex.Unlock()
+
+	old, ok := s.paymentsByID[paymentID]
+	if !ok {
+		return connectors.PaymentNotFound
+	}
+
+	payment := &connectors.Payment{}
+	*payment = *old
+	payment.RefundOf = refundOf
+
+	s.sequence++
+	payment.Sequence = s.sequence
+	s.paymentsByID[paymentID] = payment
+	return nil
+}
+
+// PaymentRefunds returns the refunds of the incoming payment.
+func (s *MemoryPaymentsStore) PaymentRefunds(paymentID string) (
+	[]*connectors.Payment, error) {
+	s.paymentsMutex.RLock()
+	defer s.paymentsMutex.RUnlock()
+
+	var refunds []*connectors.Payment
+	for _, payment := range s.paymentsByID {
+		if payment.RefundOf == paymentID {
+			refunds = append(refunds, payment)
+		}
+	}
+
+	sort.Slice(refunds, func(i, j int) bool {
+		return refunds[i].UpdatedAt < refunds[j].UpdatedAt
+	})
+
+	return refunds, nil
+}
+
+// SetPaymentQuarantine sets the quarantine state of the incoming payment.
+func (s *MemoryPaymentsStore) SetPaymentQuarantine(paymentID string,
+	state connectors.QuarantineState, reason string) error {
+	s.paymentsMutex.Lock()
+	defer s.paymentsMutex.Unlock()
+
+	old, ok := s.paymentsByID[paymentID]
+	if !ok {
+		return connectors.PaymentNotFound
+	}
+
+	payment := &connectors.Payment{}
+	*payment = *old
+	payment.Quarantine = state
+	payment.QuarantineReason = reason
+
+	s.sequence++
+	payment.Sequence = s.sequence
+	s.paymentsByID[paymentID] = payment
+	return nil
+}
+
+// LabelPayment merges the labels into the metadata of the payment.
+func (s *MemoryPaymentsStore) LabelPayment(paymentID string,
+	labels map[string]string) error {
+	s.paymentsMutex.Lock()
+	defer s.paymentsMutex.Unlock()
+
+	old, ok := s.paymentsByID[paymentID]
+	if !ok {
+		return connectors.PaymentNotFound
+	}
+
+	payment := &connectors.Payment{}
+	*payment = *old
+	payment.Metadata = connectors.MergeLabels(old.Metadata, labels)
+
+	s.sequence++
+	payment.Sequence = s.sequence
+	s.paymentsByID[paymentID] = payment
 	return nil
 }
 
@@ -73,37 +253,125 @@ func (s *MemoryPaymentsStore) ListPayments(asset connectors.Asset,
 	status connectors.PaymentStatus, direction connectors.PaymentDirection,
 	media connectors.PaymentMedia, system connectors.PaymentSystem) ([]*connectors.Payment, error) {
 
+	payments, _, err := s.QueryPayments(connectors.PaymentsQuery{
+		Asset:     asset,
+		Status:    status,
+		Direction: direction,
+		Media:     media,
+		System:    system,
+	})
+	return payments, err
+}
+
+// QueryPayments returns sorted page of payments which are matching the
+// query, and the overall number of matching payments.
+func (s *MemoryPaymentsStore) QueryPayments(query connectors.PaymentsQuery) (
+	[]*connectors.Payment, int, error) {
+
 	s.paymentsMutex.RLock()
 	defer s.paymentsMutex.RUnlock()
 
 	var payments []*connectors.Payment
 	for _, payment := range s.paymentsByID {
-		if asset != "" && payment.Asset != asset {
+		if query.Asset != "" && payment.Asset != query.Asset {
 			continue
 		}
 
-		if status != "" && payment.Status != status {
+		if query.Status != "" && payment.Status != query.Status {
 			continue
 		}
 
-		if direction != "" && payment.Direction != direction {
+		if query.Direction != "" && payment.Direction != query.Direction {
 			continue
 		}
 
-		if media != "" && payment.Media != media {
+		if query.Media != "" && payment.Media != query.Media {
 			continue
 		}
 
-		if system != "" && payment.System != system {
+		if query.System != "" && payment.System != query.System {
+			continue
+		}
+
+		if query.AccountID != "" && payment.AccountID != query.AccountID {
+			continue
+		}
+
+		if query.MediaID != "" && payment.MediaID != query.MediaID {
+			continue
+		}
+
+		if payment.Sequence <= query.SinceSequence {
+			continue
+		}
+
+		if query.Quarantine != "" && payment.Quarantine != query.Quarantine {
 			continue
 		}
 
 		payments = append(payments, payment)
 	}
 
+	// compare returns negative number if first payment should be placed
+	// before the second one in ascending order.
+	var compare func(a, b *connectors.Payment) int
+	switch query.SortBy {
+	case "", connectors.SortByUpdatedAt:
+		compare = func(a, b *connectors.Payment) int {
+			switch {
+			case a.UpdatedAt < b.UpdatedAt:
+				return -1
+			case a.UpdatedAt > b.UpdatedAt:
+				return 1
+			default:
+				return 0
+			}
+		}
+	case connectors.SortByAmount:
+		compare = func(a, b *connectors.Payment) int {
+			return a.Amount.Cmp(b.Amount)
+		}
+	case connectors.SortByStatus:
+		compare = func(a, b *connectors.Payment) int {
+			return strings.Compare(string(a.Status), string(b.Status))
+		}
+	case connectors.SortBySequence:
+		compare = func(a, b *connectors.Payment) int {
+			switch {
+			case a.Sequence < b.Sequence:
+				return -1
+			case a.Sequence > b.Sequence:
+				return 1
+			default:
+				return 0
+			}
+		}
+	default:
+		return nil, 0, errors.Errorf("unknown sort field(%v)", query.SortBy)
+	}
+
 	sort.Slice(payments, func(i, j int) bool {
-		return payments[i].UpdatedAt > payments[j].UpdatedAt
+		c := compare(payments[i], payments[j])
+		if c == 0 {
+			c = strings.Compare(payments[i].PaymentID, payments[j].PaymentID)
+		}
+
+		if query.Ascending {
+			return c < 0
+		}
+		return c > 0
 	})
 
-	return payments, nil
+	total := len(payments)
+
+	if query.Offset >= len(payments) {
+		return nil, total, nil
+	}
+	payments = payments[query.Offset:]
+
+	if query.Limit != 0 && query.Limit < len(payments) {
+		payments = payments[:query.Limit]
+	}
+
+	return payments, total, nil
 }
@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/jinzhu/gorm"
+)
+
+type AccountsStore struct {
+	db *DB
+}
+
+func NewAccountsStore(db *DB) *AccountsStore {
+	return &AccountsStore{
+		db: db,
+	}
+}
+
+type Account struct {
+	// ID is the unique identifier of the account.
+	ID string `gorm:"primary_key"`
+
+	// Name is the human readable name of the account.
+	Name string
+
+	// Tenant is the id of the API key on behalf of which account has been
+	// created.
+	Tenant string `gorm:"index"`
+
+	// CreatedAt is the time of the account creation in milliseconds.
+	CreatedAt int64
+}
+
+// Runtime check to ensure that AccountsStore implements
+// connectors.AccountsStore interface.
+var _ connectors.AccountsStore = (*AccountsStore)(nil)
+
+// CreateAccount adds account to the store, AccountExists error is returned
+// if account with the same id is already stored.
+//
+// NOTE: Part of the connectors.AccountsStore interface.
+func (s *AccountsStore) CreateAccount(account *connectors.Account) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	tx := s.db.Begin()
+
+	err := tx.Where("id = ?", account.ID).First(&Account{}).Error
+	switch {
+	case err == nil:
+		tx.Rollback()
+		return connectors.AccountExists
+	case !gorm.IsRecordNotFoundError(err):
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(convertAccountTo(account)).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// AccountByID returns account by its id, AccountNotFound error is returned
+// if account isn't stored.
+//
+// NOTE: Part of the connectors.AccountsStore interface.
+func (s *AccountsStore) AccountByID(id string) (*connectors.Account, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbAccount := &Account{}
+	err := s.db.Where("id = ?", id).First(dbAccount).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, connectors.AccountNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return convertAccountFrom(dbAccount), nil
+}
+
+// ListAccounts returns accounts of the given tenant ordered by the creation
+// time, empty tenant is used to return all of them.
+//
+// NOTE: Part of the connectors.AccountsStore interface.
+func (s *AccountsStore) ListAccounts(tenant string) ([]*connectors.Account,
+	error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.Order("created_at").Order("id")
+	if tenant != "" {
+		db = db.Where("tenant = ?", tenant)
+	}
+
+	var dbAccounts []*Account
+	if err := db.Find(&dbAccounts).Error; err != nil {
+		return nil, err
+	}
+
+	var accounts []*connectors.Account
+	for _, dbAccount := range dbAccounts {
+		accounts = append(accounts, convertAccountFrom(dbAccount))
+	}
+
+	return accounts, nil
+}
+
+func convertAccountTo(account *connectors.Account) *Account {
+	return &Account{
+		ID:        account.ID,
+		Name:      account.Name,
+		Tenant:    account.Tenant,
+		CreatedAt: account.CreatedAt,
+	}
+}
+
+func convertAccountFrom(dbAccount *Account) *connectors.Account {
+	return &connectors.Account{
+		ID:        dbAccount.ID,
+		Name:      dbAccount.Name,
+		Tenant:    dbAccount.Tenant,
+		CreatedAt: dbAccount.CreatedAt,
+	}
+}
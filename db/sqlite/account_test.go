@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"reflect"
+	"testing"
+)
+
+func TestLedgerAccountsStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewAccountsStore(db)
+
+	accounts := []*connectors.Account{
+		{
+			ID:        "shop",
+			Name:      "Shop",
+			Tenant:    "merchant",
+			CreatedAt: 1,
+		},
+		{
+			ID:        "games",
+			Name:      "Games",
+			Tenant:    "exchange",
+			CreatedAt: 2,
+		},
+		{
+			ID:        "treasury",
+			CreatedAt: 3,
+		},
+	}
+
+	for _, account := range accounts {
+		if err := store.CreateAccount(account); err != nil {
+			t.Fatalf("unable to create account: %v", err)
+		}
+	}
+
+	err = store.CreateAccount(&connectors.Account{
+		ID:     "shop",
+		Tenant: "exchange",
+	})
+	if err != connectors.AccountExists {
+		t.Fatalf("wrong error, got(%v), want(%v)", err,
+			connectors.AccountExists)
+	}
+
+	all, err := store.ListAccounts("")
+	if err != nil {
+		t.Fatalf("unable to list accounts: %v", err)
+	}
+
+	if !reflect.DeepEqual(accounts, all) {
+		t.Fatalf("wrong data")
+	}
+
+	own, err := store.ListAccounts("exchange")
+	if err != nil {
+		t.Fatalf("unable to list accounts: %v", err)
+	}
+
+	if !reflect.DeepEqual(accounts[1:2], own) {
+		t.Fatalf("wrong accounts of the tenant: %v", own)
+	}
+
+	account, err := store.AccountByID("shop")
+	if err != nil {
+		t.Fatalf("unable to get account: %v", err)
+	}
+
+	if !reflect.DeepEqual(accounts[0], account) {
+		t.Fatalf("wrong account: %v", account)
+	}
+
+	if _, err := store.AccountByID("unknown"); err != connectors.AccountNotFound {
+		t.Fatalf("wrong error, got(%v), want(%v)", err,
+			connectors.AccountNotFound)
+	}
+}
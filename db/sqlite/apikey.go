@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/jinzhu/gorm"
+)
+
+type APIKeysStore struct {
+	db *DB
+}
+
+func NewAPIKeysStore(db *DB) *APIKeysStore {
+	return &APIKeysStore{
+		db: db,
+	}
+}
+
+type APIKey struct {
+	// ID is the unique identifier of the key.
+	ID string `gorm:"primary_key"`
+
+	// Name is the name of the downstream service which uses the key.
+	Name string
+
+	// Hash is the hex encoded hash of the key.
+	Hash string `gorm:"unique_index"`
+
+	// Scopes is the comma separated list of the key scopes.
+	Scopes string
+
+	// CreatedAt is the time of the key creation in milliseconds.
+	CreatedAt int64
+
+	// RevokedAt is the time of the key revocation in milliseconds.
+	RevokedAt int64
+}
+
+// Runtime check to ensure that APIKeysStore implements
+// connectors.APIKeysStore interface.
+var _ connectors.APIKeysStore = (*APIKeysStore)(nil)
+
+// APIKeyByID returns key by its id.
+//
+// NOTE: Part of the connectors.APIKeysStore interface.
+func (s *APIKeysStore) APIKeyByID(id string) (*connectors.APIKey, error) {
+	return s.apiKeyWhere("id = ?", id)
+}
+
+// APIKeyByHash returns key by the hash of the key.
+//
+// NOTE: Part of the connectors.APIKeysStore interface.
+func (s *APIKeysStore) APIKeyByHash(hash string) (*connectors.APIKey, error) {
+	return s.apiKeyWhere("hash = ?", hash)
+}
+
+// apiKeyWhere returns the key which matches the condition.
+func (s *APIKeysStore) apiKeyWhere(query string,
+	args ...interface{}) (*connectors.APIKey, error) {
+
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbKey := &APIKey{}
+	err := s.db.Where(query, args...).First(dbKey).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, connectors.APIKeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return convertAPIKeyFrom(dbKey), nil
+}
+
+// SaveAPIKey creates or updates the key in the store.
+//
+// NOTE: Part of the connectors.APIKeysStore interface.
+func (s *APIKeysStore) SaveAPIKey(key *connectors.APIKey) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(convertAPIKeyTo(key)).Error
+}
+
+// ListAPIKeys return list of all keys, including revoked ones.
+//
+// NOTE: Part of the connectors.APIKeysStore interface.
+func (s *APIKeysStore) ListAPIKeys() ([]*connectors.APIKey, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbKeys []*APIKey
+	if err := s.db.Order("created_at").Find(&dbKeys).Error; err != nil {
+		return nil, err
+	}
+
+	var keys []*connectors.APIKey
+	for _, dbKey := range dbKeys {
+		keys = append(keys, convertAPIKeyFrom(dbKey))
+	}
+
+	return keys, nil
+}
+
+func convertAPIKeyTo(key *connectors.APIKey) *APIKey {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &APIKey{
+		ID:        key.ID,
+		Name:      key.Name,
+		Hash:      key.Hash,
+		Scopes:    strings.Join(scopes, ","),
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+func convertAPIKeyFrom(dbKey *APIKey) *connectors.APIKey {
+	var scopes []connectors.APIKeyScope
+	if dbKey.Scopes != "" {
+		for _, scope := range strings.Split(dbKey.Scopes, ",") {
+			scopes = append(scopes, connectors.APIKeyScope(scope))
+		}
+	}
+
+	return &connectors.APIKey{
+		ID:        dbKey.ID,
+		Name:      dbKey.Name,
+		Hash:      dbKey.Hash,
+		Scopes:    scopes,
+		CreatedAt: dbKey.CreatedAt,
+		RevokedAt: dbKey.RevokedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"reflect"
+	"testing"
+)
+
+func TestAPIKeysStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewAPIKeysStore(db)
+
+	keysBefore := []*connectors.APIKey{
+		{
+			ID:        "1",
+			Name:      "checkout",
+			Hash:      "hash1",
+			Scopes:    []connectors.APIKeyScope{connectors.ReceiveScope},
+			CreatedAt: 1,
+		},
+		{
+			ID:   "2",
+			Name: "payouts",
+			Hash: "hash2",
+			Scopes: []connectors.APIKeyScope{
+				connectors.ReceiveScope,
+				connectors.SendScope,
+			},
+			CreatedAt: 2,
+		},
+	}
+
+	for _, key := range keysBefore {
+		if err := store.SaveAPIKey(key); err != nil {
+			t.Fatalf("unable to save key: %v", err)
+		}
+	}
+
+	keysAfter, err := store.ListAPIKeys()
+	if err != nil {
+		t.Fatalf("unable to list keys: %v", err)
+	}
+
+	if !reflect.DeepEqual(keysBefore, keysAfter) {
+		t.Fatalf("wrong data")
+	}
+
+	key, err := store.APIKeyByHash("hash2")
+	if err != nil {
+		t.Fatalf("unable to get key: %v", err)
+	}
+
+	key.RevokedAt = 3
+	if err := store.SaveAPIKey(key); err != nil {
+		t.Fatalf("unable to save key: %v", err)
+	}
+
+	key, err = store.APIKeyByID("2")
+	if err != nil {
+		t.Fatalf("unable to get key: %v", err)
+	}
+
+	if key.RevokedAt != 3 {
+		t.Fatalf("key isn't revoked")
+	}
+
+	if _, err := store.APIKeyByHash("hash3"); err != connectors.APIKeyNotFound {
+		t.Fatalf("wrong error, got(%v), want(%v)", err,
+			connectors.APIKeyNotFound)
+	}
+}
@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+type BalanceSnapshotsStore struct {
+	db *DB
+}
+
+func NewBalanceSnapshotsStore(db *DB) *BalanceSnapshotsStore {
+	return &BalanceSnapshotsStore{
+		db: db,
+	}
+}
+
+type BalanceSnapshot struct {
+	ID uint `gorm:"primary_key"`
+
+	// Asset is an acronym of the crypto currency.
+	Asset string `gorm:"index"`
+
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media string
+
+	// Amounts are stored as strings, so that precision isn't lost.
+	Available   string
+	Pending     string
+	Quarantined string
+
+	// CreatedAt is the time of the snapshot in milliseconds.
+	CreatedAt int64 `gorm:"index"`
+}
+
+// Runtime check to ensure that BalanceSnapshotsStore implements
+// connectors.BalanceSnapshotsStore interface.
+var _ connectors.BalanceSnapshotsStore = (*BalanceSnapshotsStore)(nil)
+
+// SaveBalanceSnapshot adds snapshot to the store.
+//
+// NOTE: Part of the connectors.BalanceSnapshotsStore interface.
+func (s *BalanceSnapshotsStore) SaveBalanceSnapshot(
+	snapshot *connectors.BalanceSnapshot) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Create(&BalanceSnapshot{
+		Asset:       string(snapshot.Asset),
+		Media:       string(snapshot.Media),
+		Available:   snapshot.Available.String(),
+		Pending:     snapshot.Pending.String(),
+		Quarantined: snapshot.Quarantined.String(),
+		CreatedAt:   snapshot.CreatedAt,
+	}).Error
+}
+
+// QueryBalanceSnapshots returns snapshots which are matching the query,
+// ordered by the time from the oldest.
+//
+// NOTE: Part of the connectors.BalanceSnapshotsStore interface.
+func (s *BalanceSnapshotsStore) QueryBalanceSnapshots(
+	query connectors.BalanceSnapshotsQuery) ([]*connectors.BalanceSnapshot,
+	error) {
+
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.DB.Model(&BalanceSnapshot{})
+
+	if query.Asset != "" {
+		db = db.Where("asset = ?", string(query.Asset))
+	}
+
+	if query.Media != "" {
+		db = db.Where("media = ?", string(query.Media))
+	}
+
+	if query.CreatedFrom != 0 {
+		db = db.Where("created_at >= ?", query.CreatedFrom)
+	}
+
+	if query.CreatedTo != 0 {
+		db = db.Where("created_at <= ?", query.CreatedTo)
+	}
+
+	// Snapshots are selected from the newest, so that limit keeps the
+	// latest ones, and then reversed.
+	db = db.Order("created_at DESC, id DESC")
+
+	if query.Limit != 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	var dbSnapshots []*BalanceSnapshot
+	if err := db.Find(&dbSnapshots).Error; err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]*connectors.BalanceSnapshot, len(dbSnapshots))
+	for i, dbSnapshot := range dbSnapshots {
+		snapshot, err := convertBalanceSnapshotFromDatabase(dbSnapshot)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots[len(dbSnapshots)-1-i] = snapshot
+	}
+
+	return snapshots, nil
+}
+
+func convertBalanceSnapshotFromDatabase(
+	dbSnapshot *BalanceSnapshot) (*connectors.BalanceSnapshot, error) {
+
+	available, err := decimal.NewFromString(dbSnapshot.Available)
+	if err != nil {
+		return nil, errors.Errorf("unable to decode available balance: %v",
+			err)
+	}
+
+	pending, err := decimal.NewFromString(dbSnapshot.Pending)
+	if err != nil {
+		return nil, errors.Errorf("unable to decode pending balance: %v",
+			err)
+	}
+
+	quarantined, err := decimal.NewFromString(dbSnapshot.Quarantined)
+	if err != nil {
+		return nil, errors.Errorf("unable to decode quarantined "+
+			"balance: %v", err)
+	}
+
+	return &connectors.BalanceSnapshot{
+		Asset:       connectors.Asset(dbSnapshot.Asset),
+		Media:       connectors.PaymentMedia(dbSnapshot.Media),
+		Available:   available,
+		Pending:     pending,
+		Quarantined: quarantined,
+		CreatedAt:   dbSnapshot.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+)
+
+func TestBalanceSnapshotsStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewBalanceSnapshotsStore(db)
+
+	for i := int64(1); i <= 4; i++ {
+		for _, asset := range []connectors.Asset{connectors.BTC,
+			connectors.ETH} {
+			err := store.SaveBalanceSnapshot(&connectors.BalanceSnapshot{
+				Asset:       asset,
+				Media:       connectors.Blockchain,
+				Available:   decimal.New(i, -1),
+				Pending:     decimal.Zero,
+				Quarantined: decimal.Zero,
+				CreatedAt:   i * 10,
+			})
+			if err != nil {
+				t.Fatalf("unable to save snapshot: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name      string
+		query     connectors.BalanceSnapshotsQuery
+		available []string
+	}{
+		{
+			name: "all of asset",
+			query: connectors.BalanceSnapshotsQuery{
+				Asset: connectors.BTC,
+			},
+			available: []string{"0.1", "0.2", "0.3", "0.4"},
+		},
+		{
+			name: "time range",
+			query: connectors.BalanceSnapshotsQuery{
+				Asset:       connectors.BTC,
+				CreatedFrom: 20,
+				CreatedTo:   30,
+			},
+			available: []string{"0.2", "0.3"},
+		},
+		{
+			name: "latest",
+			query: connectors.BalanceSnapshotsQuery{
+				Asset: connectors.BTC,
+				Media: connectors.Blockchain,
+				Limit: 2,
+			},
+			available: []string{"0.3", "0.4"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snapshots, err := store.QueryBalanceSnapshots(test.query)
+			if err != nil {
+				t.Fatalf("unable to query snapshots: %v", err)
+			}
+
+			if len(snapshots) != len(test.available) {
+				t.Fatalf("wrong number of snapshots, expected(%v), "+
+					"got(%v)", len(test.available), len(snapshots))
+			}
+
+			for i, snapshot := range snapshots {
+				if snapshot.Asset != connectors.BTC ||
+					snapshot.Available.String() != test.available[i] {
+					t.Fatalf("wrong snapshot(%v): %v", i, snapshot)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/jinzhu/gorm"
+)
+
+type BrandingStore struct {
+	db *DB
+}
+
+func NewBrandingStore(db *DB) *BrandingStore {
+	return &BrandingStore{
+		db: db,
+	}
+}
+
+type Branding struct {
+	// Tenant is the id of the API key of the merchant, empty for the
+	// default branding.
+	Tenant string `gorm:"primary_key"`
+
+	// LogoURL is the URL of the logo image.
+	LogoURL string
+
+	// PrimaryColor is the main color of the page.
+	PrimaryColor string
+
+	// AccentColor is the color of the buttons and links.
+	AccentColor string
+
+	// SuccessURL is the URL to which payer is redirected after the
+	// payment.
+	SuccessURL string
+}
+
+// Runtime check to ensure that BrandingStore implements
+// connectors.BrandingStore interface.
+var _ connectors.BrandingStore = (*BrandingStore)(nil)
+
+// BrandingByTenant returns branding of the tenant.
+//
+// NOTE: Part of the connectors.BrandingStore interface.
+func (s *BrandingStore) BrandingByTenant(tenant string) (*connectors.Branding,
+	error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbBranding := &Branding{}
+	err := s.db.Where("tenant = ?", tenant).Find(dbBranding).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, connectors.BrandingNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return convertBrandingFrom(dbBranding), nil
+}
+
+// SaveBranding creates or updates the branding of the tenant.
+//
+// NOTE: Part of the connectors.BrandingStore interface.
+func (s *BrandingStore) SaveBranding(branding *connectors.Branding) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	// Default branding has the empty primary key, on which gorm always
+	// inserts the row, that is why old branding is replaced explicitly.
+	tx := s.db.Begin()
+	err := tx.Delete(&Branding{}, "tenant = ?", branding.Tenant).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(convertBrandingTo(branding)).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// RemoveBranding removes the branding of the tenant.
+//
+// NOTE: Part of the connectors.BrandingStore interface.
+func (s *BrandingStore) RemoveBranding(tenant string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.Delete(&Branding{}, "tenant = ?", tenant)
+	if db.Error != nil {
+		return db.Error
+	}
+
+	if db.RowsAffected == 0 {
+		return connectors.BrandingNotFound
+	}
+
+	return nil
+}
+
+func convertBrandingTo(branding *connectors.Branding) *Branding {
+	return &Branding{
+		Tenant:       branding.Tenant,
+		LogoURL:      branding.LogoURL,
+		PrimaryColor: branding.PrimaryColor,
+		AccentColor:  branding.AccentColor,
+		SuccessURL:   branding.SuccessURL,
+	}
+}
+
+func convertBrandingFrom(dbBranding *Branding) *connectors.Branding {
+	return &connectors.Branding{
+		Tenant:       dbBranding.Tenant,
+		LogoURL:      dbBranding.LogoURL,
+		PrimaryColor: dbBranding.PrimaryColor,
+		AccentColor:  dbBranding.AccentColor,
+		SuccessURL:   dbBranding.SuccessURL,
+	}
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+)
+
+func TestBrandingStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewBrandingStore(db)
+
+	if _, err := store.BrandingByTenant(""); err != connectors.BrandingNotFound {
+		t.Fatalf("expected branding not found error, got: %v", err)
+	}
+
+	brandings := []*connectors.Branding{
+		{
+			Tenant:       "",
+			PrimaryColor: "#000000",
+		},
+		{
+			Tenant:       "a1b2c3d4e5f60708",
+			LogoURL:      "https://partner.example/logo.png",
+			PrimaryColor: "#112233",
+			AccentColor:  "#ffaa00",
+			SuccessURL:   "https://partner.example/thanks",
+		},
+	}
+
+	for _, branding := range brandings {
+		if err := store.SaveBranding(branding); err != nil {
+			t.Fatalf("unable to save branding: %v", err)
+		}
+	}
+
+	// Saving the branding again replaces it, including the default one.
+	brandings[0].AccentColor = "#ffffff"
+	if err := store.SaveBranding(brandings[0]); err != nil {
+		t.Fatalf("unable to update branding: %v", err)
+	}
+
+	for _, expected := range brandings {
+		branding, err := store.BrandingByTenant(expected.Tenant)
+		if err != nil {
+			t.Fatalf("unable to get branding: %v", err)
+		}
+
+		if !reflect.DeepEqual(branding, expected) {
+			t.Fatalf("wrong data, expected(%v), got(%v)", expected,
+				branding)
+		}
+	}
+
+	if err := store.RemoveBranding("a1b2c3d4e5f60708"); err != nil {
+		t.Fatalf("unable to remove branding: %v", err)
+	}
+
+	_, err = store.BrandingByTenant("a1b2c3d4e5f60708")
+	if err != connectors.BrandingNotFound {
+		t.Fatalf("expected branding not found error, got: %v", err)
+	}
+
+	err = store.RemoveBranding("a1b2c3d4e5f60708")
+	if err != connectors.BrandingNotFound {
+		t.Fatalf("expected branding not found error, got: %v", err)
+	}
+}
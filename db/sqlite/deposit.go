@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/jinzhu/gorm"
+)
+
+type DepositsStore struct {
+	db *DB
+}
+
+func NewDepositsStore(db *DB) *DepositsStore {
+	return &DepositsStore{
+		db: db,
+	}
+}
+
+type ProcessedDeposit struct {
+	// Asset is an acronym of the crypto currency of the deposit.
+	Asset string `gorm:"primary_key"`
+
+	// DepositID is the identifier of the deposit in the media, e.g.
+	// txid:vout of the output or hash of the lightning payment.
+	DepositID string `gorm:"primary_key"`
+
+	// PaymentID is the id of the payment as which deposit has been
+	// recorded first.
+	PaymentID string
+
+	// CreatedAt is the time when deposit has been processed in
+	// milliseconds.
+	CreatedAt int64
+}
+
+// Runtime check to ensure that DepositsStore implements
+// connectors.DepositsStore interface.
+var _ connectors.DepositsStore = (*DepositsStore)(nil)
+
+// RecordDeposit binds the deposit to the payment if deposit hasn't been
+// processed yet, and returns id of the payment to which deposit is bound.
+//
+// NOTE: Part of the connectors.DepositsStore interface.
+func (s *DepositsStore) RecordDeposit(asset connectors.Asset, depositID,
+	paymentID string) (string, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	tx := s.db.Begin()
+
+	deposit := &ProcessedDeposit{}
+	err := tx.Where("asset = ? AND deposit_id = ?", string(asset),
+		depositID).Find(deposit).Error
+	if err == nil {
+		tx.Rollback()
+		return deposit.PaymentID, nil
+	} else if !gorm.IsRecordNotFoundError(err) {
+		tx.Rollback()
+		return "", err
+	}
+
+	err = tx.Create(&ProcessedDeposit{
+		Asset:     string(asset),
+		DepositID: depositID,
+		PaymentID: paymentID,
+		CreatedAt: connectors.NowInMilliSeconds(),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+
+	return paymentID, nil
+}
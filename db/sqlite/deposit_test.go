@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+)
+
+func TestRecordDeposit(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewDepositsStore(db)
+
+	tests := []struct {
+		name      string
+		asset     connectors.Asset
+		depositID string
+		paymentID string
+		expected  string
+	}{
+		{
+			name:      "new deposit",
+			asset:     connectors.BTC,
+			depositID: "txid:0",
+			paymentID: "1",
+			expected:  "1",
+		},
+		{
+			// Rescan which derives the other payment id for the same
+			// output shouldn't produce the new payment.
+			name:      "rescanned deposit",
+			asset:     connectors.BTC,
+			depositID: "txid:0",
+			paymentID: "2",
+			expected:  "1",
+		},
+		{
+			name:      "other output",
+			asset:     connectors.BTC,
+			depositID: "txid:1",
+			paymentID: "3",
+			expected:  "3",
+		},
+		{
+			name:      "other asset",
+			asset:     connectors.LTC,
+			depositID: "txid:0",
+			paymentID: "4",
+			expected:  "4",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			paymentID, err := store.RecordDeposit(test.asset,
+				test.depositID, test.paymentID)
+			if err != nil {
+				t.Fatalf("unable to record deposit: %v", err)
+			}
+
+			if paymentID != test.expected {
+				t.Fatalf("wrong payment id, expected(%v), got(%v)",
+					test.expected, paymentID)
+			}
+		})
+	}
+}
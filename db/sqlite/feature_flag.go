@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/bitlum/connector/features"
+)
+
+type FeatureFlagsStore struct {
+	db *DB
+}
+
+func NewFeatureFlagsStore(db *DB) *FeatureFlagsStore {
+	return &FeatureFlagsStore{
+		db: db,
+	}
+}
+
+type FeatureFlag struct {
+	// Name is the name of the feature flag.
+	Name string `gorm:"primary_key"`
+
+	// Enabled enables flag for every tenant.
+	Enabled bool
+
+	// Tenants is the comma separated list of tenants for which flag is
+	// enabled.
+	Tenants string
+
+	// Percentage is the percentage of tenants for which flag is enabled.
+	Percentage uint32
+}
+
+// Runtime check to ensure that FeatureFlagsStore implements
+// features.Store interface.
+var _ features.Store = (*FeatureFlagsStore)(nil)
+
+// SaveRule saves or replaces the rule of the flag.
+//
+// NOTE: Part of the features.Store interface.
+func (s *FeatureFlagsStore) SaveRule(flag features.Flag,
+	rule features.Rule) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(&FeatureFlag{
+		Name:       string(flag),
+		Enabled:    rule.Enabled,
+		Tenants:    strings.Join(rule.Tenants, ","),
+		Percentage: rule.Percentage,
+	}).Error
+}
+
+// Rules returns all saved rules.
+//
+// NOTE: Part of the features.Store interface.
+func (s *FeatureFlagsStore) Rules() (map[features.Flag]features.Rule, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbFlags []*FeatureFlag
+	if err := s.db.Find(&dbFlags).Error; err != nil {
+		return nil, err
+	}
+
+	rules := make(map[features.Flag]features.Rule, len(dbFlags))
+	for _, dbFlag := range dbFlags {
+		rule := features.Rule{
+			Enabled:    dbFlag.Enabled,
+			Percentage: dbFlag.Percentage,
+		}
+
+		if dbFlag.Tenants != "" {
+			rule.Tenants = strings.Split(dbFlag.Tenants, ",")
+		}
+
+		rules[features.Flag(dbFlag.Name)] = rule
+	}
+
+	return rules, nil
+}
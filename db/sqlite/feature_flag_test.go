@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bitlum/connector/features"
+)
+
+func TestFeatureFlagsStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewFeatureFlagsStore(db)
+
+	rulesBefore := map[features.Flag]features.Rule{
+		features.RBF:      {Tenants: []string{"a", "b"}},
+		features.ZeroConf: {Percentage: 25},
+	}
+
+	for flag, rule := range rulesBefore {
+		if err := store.SaveRule(flag, rule); err != nil {
+			t.Fatalf("unable to save rule: %v", err)
+		}
+	}
+
+	// Saving of the rule replaces the previous one.
+	rulesBefore[features.ZeroConf] = features.Rule{Enabled: true}
+	err = store.SaveRule(features.ZeroConf, rulesBefore[features.ZeroConf])
+	if err != nil {
+		t.Fatalf("unable to save rule: %v", err)
+	}
+
+	rulesAfter, err := store.Rules()
+	if err != nil {
+		t.Fatalf("unable to list rules: %v", err)
+	}
+
+	if !reflect.DeepEqual(rulesBefore, rulesAfter) {
+		t.Fatalf("wrong data")
+	}
+}
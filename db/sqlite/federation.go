@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+)
+
+type FederationStore struct {
+	db *DB
+}
+
+func NewFederationStore(db *DB) *FederationStore {
+	return &FederationStore{
+		db: db,
+	}
+}
+
+type FederationEntry struct {
+	// ID is the id of the transfer or settlement.
+	ID string `gorm:"primary_key"`
+
+	// Peer is the name of the peer server.
+	Peer string `gorm:"index"`
+
+	Kind      string
+	Direction string
+	Status    string
+	Asset     string
+
+	// Amount is stored as string, so that precision isn't lost.
+	Amount string
+
+	Account       string
+	PeerAccount   string
+	Memo          string
+	PaymentID     string
+	TxID          string
+	FailureReason string
+
+	// CreatedAt is the time of the entry creation in milliseconds.
+	CreatedAt int64
+
+	// UpdatedAt is the time of the last entry update in milliseconds.
+	UpdatedAt int64
+}
+
+// Runtime check to ensure that FederationStore implements
+// connectors.FederationStore interface.
+var _ connectors.FederationStore = (*FederationStore)(nil)
+
+// SaveFederationEntry adds entry to the store, or replaces the entry with
+// the same id.
+//
+// NOTE: Part of the connectors.FederationStore interface.
+func (s *FederationStore) SaveFederationEntry(
+	entry *connectors.FederationEntry) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(&FederationEntry{
+		ID:            entry.ID,
+		Peer:          entry.Peer,
+		Kind:          string(entry.Kind),
+		Direction:     string(entry.Direction),
+		Status:        string(entry.Status),
+		Asset:         string(entry.Asset),
+		Amount:        entry.Amount.String(),
+		Account:       entry.Account,
+		PeerAccount:   entry.PeerAccount,
+		Memo:          entry.Memo,
+		PaymentID:     entry.PaymentID,
+		TxID:          entry.TxID,
+		FailureReason: entry.FailureReason,
+		CreatedAt:     entry.CreatedAt,
+		UpdatedAt:     entry.UpdatedAt,
+	}).Error
+}
+
+// FederationEntryByID returns the entry, FederationEntryNotFound error is
+// returned if there is no such entry.
+//
+// NOTE: Part of the connectors.FederationStore interface.
+func (s *FederationStore) FederationEntryByID(
+	id string) (*connectors.FederationEntry, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbEntry := &FederationEntry{}
+	err := s.db.Where("id = ?", id).First(dbEntry).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, connectors.FederationEntryNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return convertFederationEntryFrom(dbEntry)
+}
+
+// FederationEntries returns all entries of the peer, ordered by the time of
+// creation.
+//
+// NOTE: Part of the connectors.FederationStore interface.
+func (s *FederationStore) FederationEntries(
+	peer string) ([]*connectors.FederationEntry, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbEntries []*FederationEntry
+	err := s.db.Where("peer = ?", peer).Order("created_at").
+		Find(&dbEntries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*connectors.FederationEntry, len(dbEntries))
+	for i, dbEntry := range dbEntries {
+		entry, err := convertFederationEntryFrom(dbEntry)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry
+	}
+
+	return entries, nil
+}
+
+func convertFederationEntryFrom(
+	dbEntry *FederationEntry) (*connectors.FederationEntry, error) {
+	amount, err := decimal.NewFromString(dbEntry.Amount)
+	if err != nil {
+		return nil, errors.Errorf("unable to parse amount of federation "+
+			"entry(%v): %v", dbEntry.ID, err)
+	}
+
+	return &connectors.FederationEntry{
+		ID:            dbEntry.ID,
+		Peer:          dbEntry.Peer,
+		Kind:          connectors.FederationEntryKind(dbEntry.Kind),
+		Direction:     connectors.PaymentDirection(dbEntry.Direction),
+		Status:        connectors.FederationEntryStatus(dbEntry.Status),
+		Asset:         connectors.Asset(dbEntry.Asset),
+		Amount:        amount,
+		Account:       dbEntry.Account,
+		PeerAccount:   dbEntry.PeerAccount,
+		Memo:          dbEntry.Memo,
+		PaymentID:     dbEntry.PaymentID,
+		TxID:          dbEntry.TxID,
+		FailureReason: dbEntry.FailureReason,
+		CreatedAt:     dbEntry.CreatedAt,
+		UpdatedAt:     dbEntry.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+)
+
+func TestFederationStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewFederationStore(db)
+
+	transfer := &connectors.FederationEntry{
+		ID:          "transfer-1",
+		Peer:        "us",
+		Kind:        connectors.FederationTransfer,
+		Direction:   connectors.Outgoing,
+		Status:      connectors.FederationPending,
+		Asset:       connectors.BTC,
+		Amount:      decimal.NewFromFloat(0.5),
+		Account:     "merchant-eu",
+		PeerAccount: "merchant-us",
+		Memo:        "payout",
+		PaymentID:   "payment-1",
+		CreatedAt:   1,
+		UpdatedAt:   1,
+	}
+	settlement := &connectors.FederationEntry{
+		ID:        "settlement-1",
+		Peer:      "us",
+		Kind:      connectors.FederationSettlement,
+		Direction: connectors.Incoming,
+		Status:    connectors.FederationCompleted,
+		Asset:     connectors.BTC,
+		Amount:    decimal.NewFromFloat(0.2),
+		TxID:      "tx-1",
+		CreatedAt: 2,
+		UpdatedAt: 2,
+	}
+	other := &connectors.FederationEntry{
+		ID:        "transfer-2",
+		Peer:      "asia",
+		Kind:      connectors.FederationTransfer,
+		Direction: connectors.Incoming,
+		Status:    connectors.FederationCompleted,
+		Asset:     connectors.LTC,
+		Amount:    decimal.New(1, 0),
+		CreatedAt: 3,
+		UpdatedAt: 3,
+	}
+
+	for _, entry := range []*connectors.FederationEntry{settlement,
+		transfer, other} {
+		if err := store.SaveFederationEntry(entry); err != nil {
+			t.Fatalf("unable to save entry: %v", err)
+		}
+	}
+
+	// Saving of the entry replaces the previous one with the same id.
+	transfer.Status = connectors.FederationCompleted
+	transfer.UpdatedAt = 4
+	if err := store.SaveFederationEntry(transfer); err != nil {
+		t.Fatalf("unable to save entry: %v", err)
+	}
+
+	entry, err := store.FederationEntryByID("transfer-1")
+	if err != nil {
+		t.Fatalf("unable to get entry: %v", err)
+	}
+
+	if !reflect.DeepEqual(entry, transfer) {
+		t.Fatalf("wrong entry: %v", entry)
+	}
+
+	_, err = store.FederationEntryByID("missing")
+	if err != connectors.FederationEntryNotFound {
+		t.Fatalf("wrong error: %v", err)
+	}
+
+	entries, err := store.FederationEntries("us")
+	if err != nil {
+		t.Fatalf("unable to list entries: %v", err)
+	}
+
+	if !reflect.DeepEqual(entries,
+		[]*connectors.FederationEntry{transfer, settlement}) {
+		t.Fatalf("wrong entries: %v", entries)
+	}
+}
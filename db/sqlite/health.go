@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+)
+
+// HealthCheck is the probe which is written by the health check, there is
+// only one row in the table.
+type HealthCheck struct {
+	ID uint `gorm:"primary_key"`
+
+	// CheckedAt is the time of the last check in milliseconds.
+	CheckedAt int64
+}
+
+// Runtime check to ensure that DB implements connectors.WriteChecker
+// interface.
+var _ connectors.WriteChecker = (*DB)(nil)
+
+// CheckWritable writes the probe into the database, so that failure to
+// persist payments is detected before they are sent.
+//
+// NOTE: Part of the connectors.WriteChecker interface.
+func (db *DB) CheckWritable() error {
+	db.globalMutex.Lock()
+	defer db.globalMutex.Unlock()
+
+	return db.Save(&HealthCheck{
+		ID:        1,
+		CheckedAt: connectors.NowInMilliSeconds(),
+	}).Error
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"testing"
+)
+
+func TestCheckWritable(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	for i := 0; i < 2; i++ {
+		if err := db.CheckWritable(); err != nil {
+			t.Fatalf("unable to check database: %v", err)
+		}
+	}
+
+	var count int
+	if err := db.Model(&HealthCheck{}).Count(&count).Error; err != nil {
+		t.Fatalf("unable to count probes: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("wrong number of probes, got(%v), want(1)", count)
+	}
+}
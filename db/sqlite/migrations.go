@@ -12,7 +12,25 @@ func (db *DB) Migrate() error {
 		&ConnectorState{},
 		&EthereumAddress{},
 		&Payment{},
+		&PaymentAlias{},
 		&BitcoinSimpleState{},
+		&Payee{},
+		&WatchAddress{},
+		&WatchEvent{},
+		&APIKey{},
+		&TimeLock{},
+		&Receipt{},
+		&FeatureFlag{},
+		&TestPaymentSchedule{},
+		&HealthCheck{},
+		&Branding{},
+		&PaymentEvent{},
+		&BalanceSnapshot{},
+		&ProcessedDeposit{},
+		&WithdrawalPause{},
+		&DepositAddress{},
+		&FederationEntry{},
+		&Account{},
 	).Error; err != nil {
 		return err
 	}
@@ -26,6 +44,11 @@ func migrate(gdb *gorm.DB, migrations []*gormigrate.Migration) error {
 
 var allMigrations = []*gormigrate.Migration{
 	addPaymentSystemType,
+	deriveInternalPayments,
+	normalizePaymentReceipts,
+	markWatchEventsDelivered,
+	addReceiptIDs,
+	addPaymentSequences,
 }
 
 var addPaymentSystemType = &gormigrate.Migration{
@@ -76,3 +99,244 @@ var addPaymentSystemType = &gormigrate.Migration{
 		return nil
 	},
 }
+
+// deriveInternalPayments backfills the system of the payments which were
+// marked as external, although they only moved funds inside our wallet.
+// Identifiers of the payments which are generated from the system are
+// regenerated as well, so that the sync would find the same payments, and
+// previous identifiers are kept as aliases, so that payments which were
+// already returned to the clients could be still fetched by them.
+var deriveInternalPayments = &gormigrate.Migration{
+	ID: "derive_internal_payments",
+	Migrate: func(tx *gorm.DB) error {
+		store := PaymentsStore{db: &DB{DB: tx}}
+
+		payments, err := store.ListPayments("", "", "", "", "")
+		if err != nil {
+			return err
+		}
+
+		// Payment id should be checked before the system is changed, in
+		// order to find out whether it was generated from it.
+		generatedIDs := make(map[*connectors.Payment]bool, len(payments))
+		for _, payment := range payments {
+			id, err := payment.GenPaymentID()
+			generatedIDs[payment] = err == nil && id == payment.PaymentID
+		}
+
+		for _, payment := range connectors.MarkInternalPayments(payments) {
+			oldID := payment.PaymentID
+			if generatedIDs[payment] {
+				err := tx.Delete(&Payment{}, "payment_id = ?", oldID).Error
+				if err != nil {
+					return err
+				}
+
+				payment.PaymentID, err = payment.GenPaymentID()
+				if err != nil {
+					return err
+				}
+
+				err = saveAlias(tx, oldID, payment.PaymentID)
+				if err != nil {
+					return err
+				}
+			}
+
+			log.Infof("Payment migration (%v) => (%v), system(%v)", oldID,
+				payment.PaymentID, payment.System)
+			if err := store.SavePayment(payment); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+// normalizePaymentReceipts backfills the canonical form of the receipts of
+// the payments and created receipts, previously they were stored in the
+// form returned by the daemon, and the same receipt might have been stored
+// in different forms. Identifiers of the payments which are generated from
+// the receipt are regenerated, and previous identifiers are kept as
+// aliases. If payment with the new identifier has been already saved by the
+// sync, it is kept, and the previous payment is replaced by the alias.
+var normalizePaymentReceipts = &gormigrate.Migration{
+	ID: "normalize_payment_receipts",
+	Migrate: func(tx *gorm.DB) error {
+		store := PaymentsStore{db: &DB{DB: tx}}
+
+		payments, err := store.ListPayments("", "", "", "", "")
+		if err != nil {
+			return err
+		}
+
+		for _, payment := range payments {
+			receipt := connectors.NormalizeReceipt(payment.Asset,
+				payment.Media, payment.Receipt)
+			if receipt == payment.Receipt {
+				continue
+			}
+
+			oldID := payment.PaymentID
+			id, err := payment.GenPaymentID()
+			if err != nil || id != oldID {
+				// Identifier isn't generated from the receipt, and it is
+				// kept as is.
+				err := tx.Model(&Payment{}).Where("payment_id = ?", oldID).
+					Update("receipt", receipt).Error
+				if err != nil {
+					return err
+				}
+
+				log.Infof("Payment migration (%v), receipt(%v) => (%v)",
+					oldID, payment.Receipt, receipt)
+				continue
+			}
+
+			err = tx.Delete(&Payment{}, "payment_id = ?", oldID).Error
+			if err != nil {
+				return err
+			}
+
+			payment.Receipt = receipt
+			payment.PaymentID, err = payment.GenPaymentID()
+			if err != nil {
+				return err
+			}
+
+			var count int
+			err = tx.Model(&Payment{}).Where("payment_id = ?",
+				payment.PaymentID).Count(&count).Error
+			if err != nil {
+				return err
+			}
+
+			if count == 0 {
+				if err := store.SavePayment(payment); err != nil {
+					return err
+				}
+			}
+
+			if err := saveAlias(tx, oldID, payment.PaymentID); err != nil {
+				return err
+			}
+
+			log.Infof("Payment migration (%v) => (%v), receipt(%v)", oldID,
+				payment.PaymentID, receipt)
+		}
+
+		var receipts []*Receipt
+		if err := tx.Find(&receipts).Error; err != nil {
+			return err
+		}
+
+		for _, r := range receipts {
+			receipt := connectors.NormalizeReceipt(connectors.Asset(r.Asset),
+				connectors.PaymentMedia(r.Media), r.Receipt)
+			if receipt == r.Receipt {
+				continue
+			}
+
+			err := tx.Delete(&Receipt{}, "receipt = ?", r.Receipt).Error
+			if err != nil {
+				return err
+			}
+
+			r.Receipt = receipt
+			if err := tx.Save(r).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+// markWatchEventsDelivered marks previously stored watch events as
+// delivered, before delivery state was introduced they were notified
+// right after they have been saved, and shouldn't be sent again.
+var markWatchEventsDelivered = &gormigrate.Migration{
+	ID: "mark_watch_events_delivered",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Model(&WatchEvent{}).Update("delivered", true).Error
+	},
+}
+
+// addReceiptIDs generates identifiers of the receipts which were stored
+// before receipts could be requested by the identifier.
+var addReceiptIDs = &gormigrate.Migration{
+	ID: "add_receipt_ids",
+	Migrate: func(tx *gorm.DB) error {
+		var receipts []*Receipt
+		if err := tx.Where("receipt_id = ? OR receipt_id IS NULL", "").
+			Find(&receipts).Error; err != nil {
+			return err
+		}
+
+		for _, r := range receipts {
+			receipt := &connectors.Receipt{
+				Receipt: r.Receipt,
+				Asset:   connectors.Asset(r.Asset),
+				Media:   connectors.PaymentMedia(r.Media),
+			}
+
+			err := tx.Model(&Receipt{}).Where("receipt = ?", r.Receipt).
+				Update("receipt_id", receipt.GenReceiptID()).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+// addPaymentSequences assigns sequence numbers to the payments which were
+// stored before the changes of the payments were numbered, in order of
+// their last update.
+var addPaymentSequences = &gormigrate.Migration{
+	ID: "add_payment_sequences",
+	Migrate: func(tx *gorm.DB) error {
+		store := PaymentsStore{db: &DB{DB: tx}}
+
+		var payments []*Payment
+		if err := tx.Where("sequence = ? OR sequence IS NULL", 0).
+			Order("updated_at, payment_id").Find(&payments).Error; err != nil {
+			return err
+		}
+
+		sequence, err := store.nextSequence()
+		if err != nil {
+			return err
+		}
+
+		for _, payment := range payments {
+			err := tx.Model(&Payment{}).Where("payment_id = ?",
+				payment.PaymentID).UpdateColumn("sequence", sequence).Error
+			if err != nil {
+				return err
+			}
+
+			sequence++
+		}
+
+		return nil
+	},
+}
+
+// saveAlias records the previous id of the payment, and repoints aliases
+// which were pointing on it, so that chain of migrations is resolved in one
+// lookup.
+func saveAlias(tx *gorm.DB, oldID, newID string) error {
+	err := tx.Model(&PaymentAlias{}).Where("payment_id = ?", oldID).
+		Update("payment_id", newID).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Save(&PaymentAlias{
+		AliasID:   oldID,
+		PaymentID: newID,
+	}).Error
+}
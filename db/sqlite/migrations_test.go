@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"github.com/bitlum/connector/connectors"
 	"gopkg.in/gormigrate.v1"
 	"testing"
 )
@@ -19,3 +20,246 @@ func TestAddPaymentStatusMigration(t *testing.T) {
 		t.Fatalf("unable migrate db: %v", err)
 	}
 }
+
+func TestDeriveInternalPaymentsMigration(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+
+	// Consolidation to our own address, and deposit of the user.
+	payments := []*connectors.Payment{
+		{
+			Status:    connectors.Completed,
+			Direction: connectors.Outgoing,
+			System:    connectors.External,
+			Receipt:   "own",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			MediaID:   "consolidation",
+		},
+		{
+			Status:    connectors.Completed,
+			Direction: connectors.Incoming,
+			System:    connectors.External,
+			Receipt:   "own",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			MediaID:   "consolidation",
+		},
+		{
+			Status:    connectors.Completed,
+			Direction: connectors.Incoming,
+			System:    connectors.External,
+			Receipt:   "user",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			MediaID:   "deposit",
+		},
+	}
+
+	var oldIDs []string
+	for _, payment := range payments {
+		payment.PaymentID, err = payment.GenPaymentID()
+		if err != nil {
+			t.Fatalf("unable to generate payment id: %v", err)
+		}
+		oldIDs = append(oldIDs, payment.PaymentID)
+
+		if err := store.SavePayment(payment); err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+	}
+
+	if err := deriveInternalPayments.Migrate(db.DB); err != nil {
+		t.Fatalf("unable migrate db: %v", err)
+	}
+
+	internal, err := store.ListPayments("", "", "", "", connectors.Internal)
+	if err != nil {
+		t.Fatalf("unable to list payments: %v", err)
+	}
+
+	if len(internal) != 2 {
+		t.Fatalf("wrong number of internal payments, got(%v), want(2)",
+			len(internal))
+	}
+
+	for _, payment := range internal {
+		if payment.MediaID != "consolidation" {
+			t.Fatalf("payment(%v) shouldn't be internal", payment.MediaID)
+		}
+
+		id, err := payment.GenPaymentID()
+		if err != nil {
+			t.Fatalf("unable to generate payment id: %v", err)
+		}
+
+		if payment.PaymentID != id {
+			t.Fatalf("payment id isn't regenerated")
+		}
+	}
+
+	external, err := store.ListPayments("", "", "", "", connectors.External)
+	if err != nil {
+		t.Fatalf("unable to list payments: %v", err)
+	}
+
+	if len(external) != 1 {
+		t.Fatalf("wrong number of external payments, got(%v), want(1)",
+			len(external))
+	}
+
+	// Payments should be still reachable by the ids which were returned
+	// before the migration.
+	for _, oldID := range oldIDs {
+		if _, err := store.PaymentByID(oldID); err != nil {
+			t.Fatalf("unable to get payment by old id(%v): %v", oldID, err)
+		}
+	}
+}
+
+func TestNormalizePaymentReceiptsMigration(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+
+	payment := &connectors.Payment{
+		Status:    connectors.Completed,
+		Direction: connectors.Incoming,
+		System:    connectors.External,
+		Receipt:   "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		Asset:     connectors.ETH,
+		Media:     connectors.Blockchain,
+		MediaID:   "deposit",
+	}
+
+	payment.PaymentID, err = payment.GenPaymentID()
+	if err != nil {
+		t.Fatalf("unable to generate payment id: %v", err)
+	}
+	oldID := payment.PaymentID
+
+	if err := store.SavePayment(payment); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	if err := normalizePaymentReceipts.Migrate(db.DB); err != nil {
+		t.Fatalf("unable migrate db: %v", err)
+	}
+
+	payments, err := store.PaymentByReceipt(
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	if err != nil {
+		t.Fatalf("unable to get payments: %v", err)
+	}
+
+	if len(payments) != 1 {
+		t.Fatalf("wrong number of payments, got(%v), want(1)",
+			len(payments))
+	}
+
+	id, err := payments[0].GenPaymentID()
+	if err != nil {
+		t.Fatalf("unable to generate payment id: %v", err)
+	}
+
+	if payments[0].PaymentID != id {
+		t.Fatalf("payment id isn't regenerated")
+	}
+
+	if _, err := store.PaymentByID(oldID); err != nil {
+		t.Fatalf("unable to get payment by old id: %v", err)
+	}
+}
+
+func TestAddReceiptIDsMigration(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	// Receipt which was stored before identifiers were introduced.
+	err = db.Save(&Receipt{
+		Receipt: "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+		Asset:   string(connectors.BTC),
+		Media:   string(connectors.Blockchain),
+		Amount:  "0",
+	}).Error
+	if err != nil {
+		t.Fatalf("unable to save receipt: %v", err)
+	}
+
+	if err := addReceiptIDs.Migrate(db.DB); err != nil {
+		t.Fatalf("unable migrate db: %v", err)
+	}
+
+	receipt := &connectors.Receipt{
+		Receipt: "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+		Asset:   connectors.BTC,
+		Media:   connectors.Blockchain,
+	}
+
+	store := ReceiptsStore{db: db}
+	stored, err := store.ReceiptByID(receipt.GenReceiptID())
+	if err != nil {
+		t.Fatalf("unable to get receipt by id: %v", err)
+	}
+
+	if stored.Receipt != receipt.Receipt {
+		t.Fatalf("wrong receipt, got(%v), want(%v)", stored.Receipt,
+			receipt.Receipt)
+	}
+}
+
+func TestAddPaymentSequencesMigration(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	// Payments which were stored before changes were numbered.
+	for _, p := range []struct {
+		id        string
+		updatedAt int64
+	}{
+		{id: "second", updatedAt: 2},
+		{id: "first", updatedAt: 1},
+	} {
+		err := db.Save(&Payment{
+			PaymentID: p.id,
+			UpdatedAt: p.updatedAt,
+			Amount:    "1",
+			MediaFee:  "0",
+		}).Error
+		if err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+	}
+
+	if err := addPaymentSequences.Migrate(db.DB); err != nil {
+		t.Fatalf("unable migrate db: %v", err)
+	}
+
+	store := PaymentsStore{db: db}
+	for id, sequence := range map[string]uint64{"first": 1, "second": 2} {
+		payment, err := store.PaymentByID(id)
+		if err != nil {
+			t.Fatalf("unable to get payment: %v", err)
+		}
+
+		if payment.Sequence != sequence {
+			t.Fatalf("wrong sequence of payment(%v), got(%v), want(%v)",
+				id, payment.Sequence, sequence)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+)
+
+type PayeesStore struct {
+	db *DB
+}
+
+func NewPayeesStore(db *DB) *PayeesStore {
+	return &PayeesStore{
+		db: db,
+	}
+}
+
+type Payee struct {
+	// Name is the unique name of the payee.
+	Name string `gorm:"primary_key"`
+
+	// Asset is an acronym of the crypto currency.
+	Asset string
+
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media string
+
+	// Receipt is the address in case of the blockchain media, and
+	// lightning network invoice in case lightning media.
+	Receipt string
+
+	// Amount is the default amount of the payment.
+	Amount string
+}
+
+// Runtime check to ensure that PayeesStore implements
+// connectors.PayeesStore interface.
+var _ connectors.PayeesStore = (*PayeesStore)(nil)
+
+// PayeeByName returns payee by its name.
+//
+// NOTE: Part of the connectors.PayeesStore interface.
+func (s *PayeesStore) PayeeByName(name string) (*connectors.Payee, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbPayee := &Payee{}
+	err := s.db.Where("name = ?", name).Find(dbPayee).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, connectors.PayeeNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return convertPayeeFrom(dbPayee)
+}
+
+// SavePayee creates or updates the payee in the store.
+//
+// NOTE: Part of the connectors.PayeesStore interface.
+func (s *PayeesStore) SavePayee(payee *connectors.Payee) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(convertPayeeTo(payee)).Error
+}
+
+// RemovePayee removes the payee from the store.
+//
+// NOTE: Part of the connectors.PayeesStore interface.
+func (s *PayeesStore) RemovePayee(name string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.Delete(&Payee{}, "name = ?", name)
+	if db.Error != nil {
+		return db.Error
+	}
+
+	if db.RowsAffected == 0 {
+		return connectors.PayeeNotFound
+	}
+
+	return nil
+}
+
+// ListPayees return list of all payees.
+//
+// NOTE: Part of the connectors.PayeesStore interface.
+func (s *PayeesStore) ListPayees() ([]*connectors.Payee, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbPayees []*Payee
+	if err := s.db.Order("name").Find(&dbPayees).Error; err != nil {
+		return nil, err
+	}
+
+	var payees []*connectors.Payee
+	for _, dbPayee := range dbPayees {
+		payee, err := convertPayeeFrom(dbPayee)
+		if err != nil {
+			return nil, err
+		}
+
+		payees = append(payees, payee)
+	}
+
+	return payees, nil
+}
+
+func convertPayeeTo(payee *connectors.Payee) *Payee {
+	return &Payee{
+		Name:    payee.Name,
+		Asset:   string(payee.Asset),
+		Media:   string(payee.Media),
+		Receipt: payee.Receipt,
+		Amount:  payee.Amount.String(),
+	}
+}
+
+func convertPayeeFrom(dbPayee *Payee) (*connectors.Payee, error) {
+	amount, err := decimal.NewFromString(dbPayee.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connectors.Payee{
+		Name:    dbPayee.Name,
+		Asset:   connectors.Asset(dbPayee.Asset),
+		Media:   connectors.PaymentMedia(dbPayee.Media),
+		Receipt: dbPayee.Receipt,
+		Amount:  amount,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"reflect"
+	"testing"
+)
+
+func TestPayeesStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewPayeesStore(db)
+
+	payeesBefore := []*connectors.Payee{
+		{
+			Name:    "exchange-hot",
+			Asset:   connectors.ETH,
+			Media:   connectors.Blockchain,
+			Receipt: "0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe",
+			Amount:  decimal.New(2, 0),
+		},
+		{
+			Name:    "treasury-cold",
+			Asset:   connectors.BTC,
+			Media:   connectors.Blockchain,
+			Receipt: "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+			Amount:  decimal.New(15, -1),
+		},
+	}
+
+	for _, payee := range payeesBefore {
+		if err := store.SavePayee(payee); err != nil {
+			t.Fatalf("unable to save payee: %v", err)
+		}
+	}
+
+	payeesAfter, err := store.ListPayees()
+	if err != nil {
+		t.Fatalf("unable to list payees: %v", err)
+	}
+
+	if !reflect.DeepEqual(payeesBefore, payeesAfter) {
+		t.Fatalf("wrong data")
+	}
+
+	payee, err := store.PayeeByName("treasury-cold")
+	if err != nil {
+		t.Fatalf("unable to get payee: %v", err)
+	}
+
+	if !reflect.DeepEqual(payee, payeesBefore[1]) {
+		t.Fatalf("wrong data")
+	}
+
+	if err := store.RemovePayee("treasury-cold"); err != nil {
+		t.Fatalf("unable to remove payee: %v", err)
+	}
+
+	if _, err := store.PayeeByName("treasury-cold"); err != connectors.PayeeNotFound {
+		t.Fatalf("expected payee not found error, got: %v", err)
+	}
+
+	if err := store.RemovePayee("treasury-cold"); err != connectors.PayeeNotFound {
+		t.Fatalf("expected payee not found error, got: %v", err)
+	}
+}
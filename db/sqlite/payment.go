@@ -2,10 +2,13 @@ package sqlite
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/bitlum/connector/connectors"
 	"github.com/go-errors/errors"
+	"github.com/jinzhu/gorm"
 	"github.com/shopspring/decimal"
-	"sort"
+	"math"
+	"strings"
 )
 
 type PaymentsStore struct {
@@ -65,12 +68,58 @@ type Payment struct {
 	// payment identificator because of the reason that it is not unique.
 	MediaID string
 
+	// Memo is the message which is attached to the payment in the media.
+	Memo string
+
 	// Detail stores all additional information which is needed for this type
 	// and status of payment.
 	Detail string
 
 	// DetailType is used to identify details type, to decode it properly.
 	DetailType int
+
+	// Flags is the comma separated list of feature flags which affected
+	// the payment.
+	Flags string
+
+	// AccountID is the identifier of the account to which payment belongs.
+	AccountID string `gorm:"index"`
+
+	// Metadata is the JSON encoded labels of the payment.
+	Metadata string
+
+	// RefundOf is the id of the incoming payment which is refunded by this
+	// payment.
+	RefundOf string `gorm:"index"`
+
+	// Sequence is the global sequence number of the last change of the
+	// payment.
+	Sequence uint64 `gorm:"index"`
+
+	// Quarantine is the state of the compliance review of the payment.
+	Quarantine string `gorm:"index"`
+
+	// QuarantineReason is the reason with which payment has been flagged.
+	QuarantineReason string
+
+	// FiatValue is the value of the payment in the fiat currency at the
+	// moment of its completion, empty if payment hasn't been valued.
+	FiatValue string
+
+	// FiatCurrency is the currency of the fiat value.
+	FiatCurrency string
+}
+
+// PaymentAlias maps the previous id of the payment on its current one. Ids
+// of the payments are derived from their fields, and when migration changes
+// such field the id is regenerated, but payment should be still reachable by
+// the id which has been already returned to the clients.
+type PaymentAlias struct {
+	// AliasID is the previous id of the payment.
+	AliasID string `gorm:"primary_key"`
+
+	// PaymentID is the current id of the payment.
+	PaymentID string
 }
 
 // Runtime check to ensure that PaymentStore implements
@@ -85,7 +134,20 @@ func (s *PaymentsStore) PaymentByID(paymentID string) (*connectors.Payment, erro
 	defer s.db.globalMutex.Unlock()
 
 	dbPayment := &Payment{PaymentID: paymentID}
-	if err := s.db.Find(dbPayment).Error; err != nil {
+	err := s.db.Find(dbPayment).Error
+	if gorm.IsRecordNotFoundError(err) {
+		// Payment might be requested by the id which it had before the
+		// migration, in this case it is found by its alias.
+		alias := &PaymentAlias{}
+		aliasErr := s.db.Where("alias_id = ?", paymentID).First(alias).Error
+		if aliasErr != nil && !gorm.IsRecordNotFoundError(aliasErr) {
+			return nil, aliasErr
+		} else if aliasErr == nil {
+			dbPayment = &Payment{PaymentID: alias.PaymentID}
+			err = s.db.Find(dbPayment).Error
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -130,7 +192,287 @@ func (s *PaymentsStore) SavePayment(payment *connectors.Payment) error {
 		return err
 	}
 
-	return s.db.Save(dbPayment).Error
+	// Previous state of the payment is needed to find out which events of
+	// its timeline have happened since the last save.
+	var prev *connectors.Payment
+	existing := &Payment{}
+	err = s.db.Where("payment_id = ?", dbPayment.PaymentID).
+		First(existing).Error
+	switch {
+	case gorm.IsRecordNotFoundError(err):
+	case err != nil:
+		return err
+	default:
+		prev, err = convertPaymentFrom(existing)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Flags, account, metadata, refund link and quarantine are recorded
+	// when payment is created, but payment might be later regenerated by
+	// the sync without them, in this case previously recorded ones are
+	// kept.
+	if dbPayment.Flags == "" {
+		dbPayment.Flags = existing.Flags
+	}
+
+	if dbPayment.AccountID == "" {
+		dbPayment.AccountID = existing.AccountID
+	}
+
+	if dbPayment.Metadata == "" {
+		dbPayment.Metadata = existing.Metadata
+	}
+
+	if dbPayment.RefundOf == "" {
+		dbPayment.RefundOf = existing.RefundOf
+	}
+
+	if dbPayment.Quarantine == "" {
+		dbPayment.Quarantine = existing.Quarantine
+		dbPayment.QuarantineReason = existing.QuarantineReason
+	}
+
+	// Fiat value is recorded once, at the moment of the completion, so
+	// that later valuation of the same payment doesn't override it.
+	if existing.FiatCurrency != "" {
+		dbPayment.FiatValue = existing.FiatValue
+		dbPayment.FiatCurrency = existing.FiatCurrency
+	}
+
+	// Incoming payment belongs to the account of the receipt on which it
+	// has been received, and is labeled with the metadata of the receipt.
+	if (dbPayment.AccountID == "" || dbPayment.Metadata == "") &&
+		payment.Direction == connectors.Incoming {
+		receipt := &Receipt{}
+		err := s.db.Select("account_id, metadata").Where("receipt = ?",
+			dbPayment.Receipt).First(receipt).Error
+		if err != nil && !gorm.IsRecordNotFoundError(err) {
+			return err
+		}
+
+		if dbPayment.AccountID == "" {
+			dbPayment.AccountID = receipt.AccountID
+		}
+
+		if dbPayment.Metadata == "" {
+			dbPayment.Metadata = receipt.Metadata
+		}
+
+		// Address might have been generated without the receipt, in this
+		// case payment belongs to the account of the address.
+		if dbPayment.AccountID == "" {
+			address := &DepositAddress{}
+			err := s.db.Select("account_id").Where("address = ?",
+				dbPayment.Receipt).First(address).Error
+			if err != nil && !gorm.IsRecordNotFoundError(err) {
+				return err
+			}
+
+			dbPayment.AccountID = address.AccountID
+		}
+	}
+
+	metadata, err := decodeMetadata(dbPayment.Metadata)
+	if err != nil {
+		return err
+	}
+
+	// Sequence is assigned only if payment has changed, so that repeated
+	// saves of the same state by the sync aren't returned to the clients
+	// which are following the changes.
+	dbPayment.Sequence = existing.Sequence
+	if prev == nil || *dbPayment != *existing {
+		dbPayment.Sequence, err = s.nextSequence()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.db.Save(dbPayment).Error; err != nil {
+		return err
+	}
+
+	// Account, metadata, refund link, quarantine, fiat value and sequence
+	// are returned along with the payment, so that they are received by
+	// the subscribers of the store.
+	payment.AccountID = dbPayment.AccountID
+	payment.Metadata = metadata
+	payment.RefundOf = dbPayment.RefundOf
+	payment.Quarantine = connectors.QuarantineState(dbPayment.Quarantine)
+	payment.QuarantineReason = dbPayment.QuarantineReason
+	payment.FiatCurrency = dbPayment.FiatCurrency
+	payment.Sequence = dbPayment.Sequence
+	if dbPayment.FiatValue != "" {
+		payment.FiatValue, err = decimal.NewFromString(dbPayment.FiatValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	events := connectors.PaymentTimelineEvents(prev, payment,
+		connectors.NowInMilliSeconds())
+	for _, event := range events {
+		if err := s.db.Create(convertPaymentEventTo(event)).Error; err != nil {
+			return errors.Errorf("unable to record event(%v) of "+
+				"payment(%v): %v", event.Type, payment.PaymentID, err)
+		}
+	}
+
+	return nil
+}
+
+// SetPaymentAccount binds the payment to the account, account is kept when
+// payment is later updated by the connector.
+//
+// NOTE: Part of the connectors.PaymentsStore interface.
+func (s *PaymentsStore) SetPaymentAccount(paymentID, accountID string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.updatePaymentColumns(paymentID, map[string]interface{}{
+		"account_id": accountID,
+	})
+}
+
+// nextSequence returns the sequence number of the next change of the
+// payments.
+//
+// NOTE: Global mutex should be held.
+func (s *PaymentsStore) nextSequence() (uint64, error) {
+	var last struct {
+		Sequence uint64
+	}
+
+	err := s.db.Model(&Payment{}).
+		Select("COALESCE(MAX(sequence), 0) AS sequence").Scan(&last).Error
+	if err != nil {
+		return 0, errors.Errorf("unable to get last sequence: %v", err)
+	}
+
+	return last.Sequence + 1, nil
+}
+
+// updatePaymentColumns updates the columns of the payment and assigns the
+// new sequence number to it.
+//
+// NOTE: Global mutex should be held.
+func (s *PaymentsStore) updatePaymentColumns(paymentID string,
+	columns map[string]interface{}) error {
+	sequence, err := s.nextSequence()
+	if err != nil {
+		return err
+	}
+
+	updates := make(map[string]interface{}, len(columns)+1)
+	for column, value := range columns {
+		updates[column] = value
+	}
+	updates["sequence"] = sequence
+
+	// Columns are updated without the hooks, so that the update time of
+	// the payment isn't touched.
+	db := s.db.Model(&Payment{}).Where("payment_id = ?", paymentID).
+		UpdateColumns(updates)
+	if db.Error != nil {
+		return db.Error
+	}
+
+	if db.RowsAffected == 0 {
+		return connectors.PaymentNotFound
+	}
+
+	return nil
+}
+
+// SetPaymentRefund links the refund to the incoming payment which it
+// refunds, link is kept when refund is later updated by the connector.
+//
+// NOTE: Part of the connectors.PaymentsStore interface.
+func (s *PaymentsStore) SetPaymentRefund(paymentID, refundOf string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.updatePaymentColumns(paymentID, map[string]interface{}{
+		"refund_of": refundOf,
+	})
+}
+
+// SetPaymentQuarantine sets the state of the compliance review of the
+// incoming payment along with its reason, state is kept when payment is
+// later updated by the connector.
+//
+// NOTE: Part of the connectors.PaymentsStore interface.
+func (s *PaymentsStore) SetPaymentQuarantine(paymentID string,
+	state connectors.QuarantineState, reason string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.updatePaymentColumns(paymentID, map[string]interface{}{
+		"quarantine":        string(state),
+		"quarantine_reason": reason,
+	})
+}
+
+// PaymentRefunds returns the refunds of the incoming payment, from the
+// oldest one.
+//
+// NOTE: Part of the connectors.PaymentsStore interface.
+func (s *PaymentsStore) PaymentRefunds(paymentID string) (
+	[]*connectors.Payment, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbPayments []*Payment
+	err := s.db.Where("refund_of = ?", paymentID).Order("updated_at").
+		Find(&dbPayments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]*connectors.Payment, len(dbPayments))
+	for i, dbPayment := range dbPayments {
+		payments[i], err = convertPaymentFrom(dbPayment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return payments, nil
+}
+
+// LabelPayment merges the labels into the metadata of the payment, label
+// with empty value is removed.
+//
+// NOTE: Part of the connectors.PaymentsStore interface.
+func (s *PaymentsStore) LabelPayment(paymentID string,
+	labels map[string]string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbPayment := &Payment{}
+	err := s.db.Select("metadata").Where("payment_id = ?", paymentID).
+		First(dbPayment).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return connectors.PaymentNotFound
+	} else if err != nil {
+		return err
+	}
+
+	metadata, err := decodeMetadata(dbPayment.Metadata)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := encodeMetadata(connectors.MergeLabels(metadata, labels))
+	if err != nil {
+		return err
+	}
+
+	return s.updatePaymentColumns(paymentID, map[string]interface{}{
+		"metadata": encoded,
+	})
 }
 
 // ListPayments return list of all payments.
@@ -144,49 +486,152 @@ func (s *PaymentsStore) ListPayments(asset connectors.Asset,
 	s.db.globalMutex.Lock()
 	defer s.db.globalMutex.Unlock()
 
-	db := s.db.DB
+	payments, _, err := s.queryPayments(connectors.PaymentsQuery{
+		Asset:     asset,
+		Status:    status,
+		Direction: direction,
+		Media:     media,
+		System:    system,
+	})
+	return payments, err
+}
+
+// QueryPayments returns sorted page of payments which are matching the
+// query, and the overall number of matching payments.
+//
+// NOTE: Part of the connectors.PaymentsStore interface.
+func (s *PaymentsStore) QueryPayments(query connectors.PaymentsQuery) (
+	[]*connectors.Payment, int, error) {
+
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.queryPayments(query)
+}
+
+// queryPayments returns page of payments which are matching the query.
+//
+// NOTE: Global mutex should be held.
+func (s *PaymentsStore) queryPayments(query connectors.PaymentsQuery) (
+	[]*connectors.Payment, int, error) {
+
+	db := s.db.DB.Model(&Payment{})
+
+	if query.Asset != "" {
+		db = db.Where("asset = ?", query.Asset)
+	}
+
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
 
-	if asset != "" {
-		db = db.Where("asset = ?", asset)
+	if query.Direction != "" {
+		db = db.Where("direction = ?", query.Direction)
 	}
 
-	if status != "" {
-		db = db.Where("status = ?", status)
+	if query.Media != "" {
+		db = db.Where("media = ?", query.Media)
 	}
 
-	if direction != "" {
-		db = db.Where("direction = ?", direction)
+	if query.System != "" {
+		db = db.Where("system = ?", query.System)
 	}
 
-	if media != "" {
-		db = db.Where("media = ?", media)
+	if query.AccountID != "" {
+		db = db.Where("account_id = ?", query.AccountID)
 	}
 
-	if system != "" {
-		db = db.Where("system = ?", system)
+	if query.MediaID != "" {
+		db = db.Where("media_id = ?", query.MediaID)
+	}
+
+	if query.UpdatedFrom != 0 {
+		db = db.Where("updated_at >= ?", query.UpdatedFrom)
+	}
+
+	if query.UpdatedTo != 0 {
+		db = db.Where("updated_at <= ?", query.UpdatedTo)
+	}
+
+	if query.SinceSequence != 0 {
+		db = db.Where("sequence > ?", query.SinceSequence)
+	}
+
+	if query.Quarantine != "" {
+		db = db.Where("quarantine = ?", query.Quarantine)
+	}
+
+	// Amount is compared as number in the same way as it is sorted.
+	if !query.MinAmount.IsZero() {
+		min, _ := query.MinAmount.Float64()
+		db = db.Where("CAST(amount AS REAL) >= ?", min)
+	}
+
+	if !query.MaxAmount.IsZero() {
+		max, _ := query.MaxAmount.Float64()
+		db = db.Where("CAST(amount AS REAL) <= ?", max)
+	}
+
+	var total int
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var field string
+	switch query.SortBy {
+	case "", connectors.SortByUpdatedAt:
+		field = "updated_at"
+	case connectors.SortByAmount:
+		// Amount is stored as string, so that precision isn't lost, that
+		// is why it has to be converted to be sorted as number.
+		field = "CAST(amount AS REAL)"
+	case connectors.SortByStatus:
+		field = "status"
+	case connectors.SortBySequence:
+		field = "sequence"
+	default:
+		return nil, 0, errors.Errorf("unknown sort field(%v)", query.SortBy)
+	}
+
+	order := "DESC"
+	if query.Ascending {
+		order = "ASC"
+	}
+
+	// Payment id is used as tie-breaker, so that pages are not overlapping
+	// if payments have the same value of the sort field.
+	db = db.Order(fmt.Sprintf("%v %v, payment_id %v", field, order, order))
+
+	limit := query.Limit
+	if limit == 0 && query.Offset != 0 {
+		// Offset couldn't be used without limit in sqlite.
+		limit = math.MaxInt32
+	}
+
+	if limit != 0 {
+		db = db.Limit(limit)
+	}
+
+	if query.Offset != 0 {
+		db = db.Offset(query.Offset)
 	}
 
 	var dbPayments []*Payment
-	err := db.Find(&dbPayments).Error
-	if err != nil {
-		return nil, err
+	if err := db.Find(&dbPayments).Error; err != nil {
+		return nil, 0, err
 	}
 
 	var payments []*connectors.Payment
 	for _, dbPayment := range dbPayments {
 		payment, err := convertPaymentFrom(dbPayment)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		payments = append(payments, payment)
 	}
 
-	sort.Slice(payments, func(i, j int) bool {
-		return payments[i].UpdatedAt > payments[j].UpdatedAt
-	})
-
-	return payments, nil
+	return payments, total, nil
 }
 
 func convertPaymentTo(payment *connectors.Payment) (*Payment, error) {
@@ -211,6 +656,16 @@ func convertPaymentTo(payment *connectors.Payment) (*Payment, error) {
 		}
 	}
 
+	metadata, err := encodeMetadata(payment.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var fiatValue string
+	if payment.FiatCurrency != "" {
+		fiatValue = payment.FiatValue.String()
+	}
+
 	dbPayment := &Payment{
 		PaymentID:  payment.PaymentID,
 		UpdatedAt:  payment.UpdatedAt,
@@ -224,8 +679,20 @@ func convertPaymentTo(payment *connectors.Payment) (*Payment, error) {
 		Amount:     payment.Amount.String(),
 		MediaFee:   payment.MediaFee.String(),
 		MediaID:    payment.MediaID,
+		Memo:       payment.Memo,
 		Detail:     details,
 		DetailType: detailType,
+		Flags:      strings.Join(payment.Flags, ","),
+		AccountID:  payment.AccountID,
+		Metadata:   metadata,
+		RefundOf:   payment.RefundOf,
+		Sequence:   payment.Sequence,
+
+		Quarantine:       string(payment.Quarantine),
+		QuarantineReason: payment.QuarantineReason,
+
+		FiatValue:    fiatValue,
+		FiatCurrency: payment.FiatCurrency,
 	}
 
 	return dbPayment, nil
@@ -260,6 +727,19 @@ func convertPaymentFrom(dbPayment *Payment) (*connectors.Payment, error) {
 		return nil, err
 	}
 
+	metadata, err := decodeMetadata(dbPayment.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	fiatValue := decimal.Zero
+	if dbPayment.FiatValue != "" {
+		fiatValue, err = decimal.NewFromString(dbPayment.FiatValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	payment := &connectors.Payment{
 		PaymentID: dbPayment.PaymentID,
 		UpdatedAt: dbPayment.UpdatedAt,
@@ -273,7 +753,22 @@ func convertPaymentFrom(dbPayment *Payment) (*connectors.Payment, error) {
 		Amount:    amount,
 		MediaFee:  mediaFee,
 		MediaID:   dbPayment.MediaID,
+		Memo:      dbPayment.Memo,
 		Detail:    detail,
+		AccountID: dbPayment.AccountID,
+		Metadata:  metadata,
+		RefundOf:  dbPayment.RefundOf,
+		Sequence:  dbPayment.Sequence,
+
+		Quarantine:       connectors.QuarantineState(dbPayment.Quarantine),
+		QuarantineReason: dbPayment.QuarantineReason,
+
+		FiatValue:    fiatValue,
+		FiatCurrency: dbPayment.FiatCurrency,
+	}
+
+	if dbPayment.Flags != "" {
+		payment.Flags = strings.Split(dbPayment.Flags, ",")
 	}
 
 	return payment, nil
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+)
+
+// PaymentEvent is the event in the timeline of the payment.
+type PaymentEvent struct {
+	ID uint `gorm:"primary_key"`
+
+	// PaymentID is the id of the payment to which event belongs.
+	PaymentID string `gorm:"index"`
+
+	// Type is the type of the event.
+	Type string
+
+	// Time is the time of the event in milliseconds.
+	Time int64
+
+	// Details is the additional information of the event.
+	Details string
+}
+
+// AddPaymentEvent appends the event to the timeline of the payment.
+//
+// NOTE: Part of the connectors.PaymentEventsStore interface.
+func (s *PaymentsStore) AddPaymentEvent(event *connectors.PaymentEvent) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Create(convertPaymentEventTo(event)).Error
+}
+
+// PaymentTimeline returns the events of the payment ordered by time, events
+// which happened at the same time are returned in order of recording.
+//
+// NOTE: Part of the connectors.PaymentEventsStore interface.
+func (s *PaymentsStore) PaymentTimeline(paymentID string) (
+	[]*connectors.PaymentEvent, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbEvents []*PaymentEvent
+	err := s.db.Where("payment_id = ?", paymentID).Order("time").
+		Order("id").Find(&dbEvents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*connectors.PaymentEvent, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = &connectors.PaymentEvent{
+			PaymentID: dbEvent.PaymentID,
+			Type:      connectors.PaymentEventType(dbEvent.Type),
+			Time:      dbEvent.Time,
+			Details:   dbEvent.Details,
+		}
+	}
+
+	return events, nil
+}
+
+func convertPaymentEventTo(event *connectors.PaymentEvent) *PaymentEvent {
+	return &PaymentEvent{
+		PaymentID: event.PaymentID,
+		Type:      string(event.Type),
+		Time:      event.Time,
+		Details:   event.Details,
+	}
+}
@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"fmt"
 	"github.com/bitlum/connector/connectors"
 	"github.com/shopspring/decimal"
 	"reflect"
@@ -50,6 +51,7 @@ func TestPaymentsStorage(t *testing.T) {
 			Amount:    decimal.NewFromFloat(1.1),
 			MediaFee:  decimal.NewFromFloat(1.1),
 			MediaID:   "media_id",
+			Memo:      "memo",
 			Detail: &connectors.GeneratedTxDetails{
 				RawTx: []byte("rawtx"),
 				TxID:  "123",
@@ -140,3 +142,701 @@ func TestPaymentsStorage(t *testing.T) {
 		}
 	}
 }
+
+func TestPaymentFlagsKept(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+
+	payment := &connectors.Payment{
+		PaymentID: "1",
+		UpdatedAt: 1,
+		Status:    connectors.Pending,
+		System:    connectors.External,
+		Direction: connectors.Outgoing,
+		Receipt:   "receipt",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(1.1),
+		MediaFee:  decimal.NewFromFloat(0.1),
+		MediaID:   "media_id",
+		Flags:     []string{"rbf"},
+	}
+
+	if err := store.SavePayment(payment); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	// Payment is regenerated by the sync without flags.
+	updated := *payment
+	updated.Status = connectors.Completed
+	updated.Flags = nil
+
+	if err := store.SavePayment(&updated); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	stored, err := store.PaymentByID("1")
+	if err != nil {
+		t.Fatalf("unable to get payment: %v", err)
+	}
+
+	if stored.Status != connectors.Completed {
+		t.Fatalf("wrong status: %v", stored.Status)
+	}
+
+	if !reflect.DeepEqual(stored.Flags, []string{"rbf"}) {
+		t.Fatalf("flags should be kept, got(%v)", stored.Flags)
+	}
+}
+
+func TestPaymentAccount(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+	receiptsStore := NewReceiptsStore(db)
+
+	err = receiptsStore.SaveReceipt(&connectors.Receipt{
+		Receipt:   "deposit",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.Zero,
+		AccountID: "shop",
+	})
+	if err != nil {
+		t.Fatalf("unable to save receipt: %v", err)
+	}
+
+	incoming := &connectors.Payment{
+		PaymentID: "incoming",
+		UpdatedAt: 1,
+		Status:    connectors.Pending,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "deposit",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(1.1),
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx1",
+	}
+
+	outgoing := &connectors.Payment{
+		PaymentID: "outgoing",
+		UpdatedAt: 2,
+		Status:    connectors.Pending,
+		System:    connectors.External,
+		Direction: connectors.Outgoing,
+		Receipt:   "withdrawal",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(0.5),
+		MediaFee:  decimal.NewFromFloat(0.1),
+		MediaID:   "tx2",
+	}
+
+	for _, payment := range []*connectors.Payment{incoming, outgoing} {
+		if err := store.SavePayment(payment); err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+	}
+
+	if incoming.AccountID != "shop" {
+		t.Fatalf("incoming payment should belong to the account of the "+
+			"receipt, got(%v)", incoming.AccountID)
+	}
+
+	if err := store.SetPaymentAccount("outgoing", "games"); err != nil {
+		t.Fatalf("unable to set payment account: %v", err)
+	}
+
+	if err := store.SetPaymentAccount("unknown", "games"); err !=
+		connectors.PaymentNotFound {
+		t.Fatalf("expected payment not found error, got: %v", err)
+	}
+
+	// Payment is regenerated by the sync without account.
+	updated := *outgoing
+	updated.Status = connectors.Completed
+	if err := store.SavePayment(&updated); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	payments, total, err := store.QueryPayments(connectors.PaymentsQuery{
+		AccountID: "games",
+	})
+	if err != nil {
+		t.Fatalf("unable to query payments: %v", err)
+	}
+
+	if total != 1 || payments[0].PaymentID != "outgoing" ||
+		payments[0].Status != connectors.Completed {
+		t.Fatalf("wrong payments of the account: %v", payments)
+	}
+
+	if payments[0].UpdatedAt != 2 {
+		t.Fatalf("update time shouldn't be changed, got(%v)",
+			payments[0].UpdatedAt)
+	}
+}
+
+func TestPaymentMetadata(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+	receiptsStore := NewReceiptsStore(db)
+
+	err = receiptsStore.SaveReceipt(&connectors.Receipt{
+		Receipt:  "deposit",
+		Asset:    connectors.BTC,
+		Media:    connectors.Blockchain,
+		Amount:   decimal.Zero,
+		Metadata: map[string]string{"order_id": "1001"},
+	})
+	if err != nil {
+		t.Fatalf("unable to save receipt: %v", err)
+	}
+
+	payment := &connectors.Payment{
+		PaymentID: "incoming",
+		UpdatedAt: 1,
+		Status:    connectors.Pending,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "deposit",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(1.1),
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx1",
+	}
+
+	if err := store.SavePayment(payment); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	if payment.Metadata["order_id"] != "1001" {
+		t.Fatalf("incoming payment should receive metadata of the "+
+			"receipt, got(%v)", payment.Metadata)
+	}
+
+	err = store.LabelPayment("incoming", map[string]string{
+		"order_id":    "",
+		"customer_id": "42",
+	})
+	if err != nil {
+		t.Fatalf("unable to label payment: %v", err)
+	}
+
+	err = store.LabelPayment("unknown", map[string]string{"a": "b"})
+	if err != connectors.PaymentNotFound {
+		t.Fatalf("expected payment not found error, got: %v", err)
+	}
+
+	// Payment is regenerated by the sync without metadata.
+	updated := *payment
+	updated.Metadata = nil
+	updated.Status = connectors.Completed
+	if err := store.SavePayment(&updated); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	stored, err := store.PaymentByID("incoming")
+	if err != nil {
+		t.Fatalf("unable to get payment: %v", err)
+	}
+
+	expected := map[string]string{"customer_id": "42"}
+	if !reflect.DeepEqual(stored.Metadata, expected) {
+		t.Fatalf("wrong metadata, expected(%v), got(%v)", expected,
+			stored.Metadata)
+	}
+
+	if stored.Status != connectors.Completed || stored.UpdatedAt != 1 {
+		t.Fatalf("wrong payment: %v", stored)
+	}
+}
+
+func TestQueryPayments(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+
+	// Amounts are chosen so that their string and numeric orders differ.
+	amounts := []float64{9, 10, 0.5, 100}
+	for i, amount := range amounts {
+		payment := &connectors.Payment{
+			PaymentID: fmt.Sprintf("%v", i),
+			UpdatedAt: int64(i),
+			Status:    connectors.Completed,
+			System:    connectors.External,
+			Direction: connectors.Incoming,
+			Receipt:   "receipt",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.NewFromFloat(amount),
+			MediaFee:  decimal.Zero,
+			MediaID:   fmt.Sprintf("tx%v", i/2),
+		}
+
+		if err := store.SavePayment(payment); err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query connectors.PaymentsQuery
+		ids   []string
+		total int
+	}{
+		{
+			name:  "default order",
+			query: connectors.PaymentsQuery{},
+			ids:   []string{"3", "2", "1", "0"},
+			total: 4,
+		},
+		{
+			name: "amount ascending",
+			query: connectors.PaymentsQuery{
+				SortBy:    connectors.SortByAmount,
+				Ascending: true,
+			},
+			ids:   []string{"2", "0", "1", "3"},
+			total: 4,
+		},
+		{
+			name: "page",
+			query: connectors.PaymentsQuery{
+				SortBy: connectors.SortByAmount,
+				Offset: 1,
+				Limit:  2,
+			},
+			ids:   []string{"1", "0"},
+			total: 4,
+		},
+		{
+			name: "offset without limit",
+			query: connectors.PaymentsQuery{
+				Offset: 3,
+			},
+			ids:   []string{"0"},
+			total: 4,
+		},
+		{
+			name: "time range",
+			query: connectors.PaymentsQuery{
+				UpdatedFrom: 1,
+				UpdatedTo:   2,
+			},
+			ids:   []string{"2", "1"},
+			total: 2,
+		},
+		{
+			name: "amount range",
+			query: connectors.PaymentsQuery{
+				MinAmount: decimal.NewFromFloat(0.5),
+				MaxAmount: decimal.NewFromFloat(10),
+			},
+			ids:   []string{"2", "1", "0"},
+			total: 3,
+		},
+		{
+			name: "media id",
+			query: connectors.PaymentsQuery{
+				MediaID: "tx1",
+			},
+			ids:   []string{"3", "2"},
+			total: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payments, total, err := store.QueryPayments(tt.query)
+			if err != nil {
+				t.Fatalf("unable to query payments: %v", err)
+			}
+
+			if total != tt.total {
+				t.Fatalf("wrong total, got(%v), want(%v)", total, tt.total)
+			}
+
+			var ids []string
+			for _, payment := range payments {
+				ids = append(ids, payment.PaymentID)
+			}
+
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Fatalf("wrong payments, got(%v), want(%v)", ids, tt.ids)
+			}
+		})
+	}
+}
+
+func TestPaymentTimeline(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+
+	payment := &connectors.Payment{
+		PaymentID: "outgoing",
+		UpdatedAt: 1,
+		Status:    connectors.Waiting,
+		System:    connectors.External,
+		Direction: connectors.Outgoing,
+		Receipt:   "address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(1.1),
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx1",
+	}
+
+	save := func(status connectors.PaymentStatus, confirmations int64) {
+		payment.Status = status
+		payment.Detail = &connectors.BlockchainPendingDetails{
+			Confirmations: confirmations,
+		}
+
+		if err := store.SavePayment(payment); err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+	}
+
+	save(connectors.Waiting, 0)
+	save(connectors.Pending, 0)
+	save(connectors.Pending, 1)
+	save(connectors.Pending, 1)
+	save(connectors.Completed, 2)
+
+	err = store.AddPaymentEvent(&connectors.PaymentEvent{
+		PaymentID: "outgoing",
+		Type:      connectors.EventHookDelivered,
+		Time:      connectors.NowInMilliSeconds(),
+		Details:   "https://example.com/hook",
+	})
+	if err != nil {
+		t.Fatalf("unable to add payment event: %v", err)
+	}
+
+	events, err := store.PaymentTimeline("outgoing")
+	if err != nil {
+		t.Fatalf("unable to get payment timeline: %v", err)
+	}
+
+	expected := []connectors.PaymentEventType{
+		connectors.EventCreated,
+		connectors.EventBroadcast,
+		connectors.EventConfirmed,
+		connectors.EventConfirmed,
+		connectors.EventCompleted,
+		connectors.EventHookDelivered,
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("wrong number of events, expected(%v), got(%v)",
+			len(expected), len(events))
+	}
+
+	for i, event := range events {
+		if event.Type != expected[i] || event.PaymentID != "outgoing" {
+			t.Fatalf("wrong event(%v): %v", i, event)
+		}
+	}
+
+	if events[3].Details != "2" {
+		t.Fatalf("wrong number of confirmations: %v", events[3].Details)
+	}
+
+	events, err = store.PaymentTimeline("unknown")
+	if err != nil {
+		t.Fatalf("unable to get payment timeline: %v", err)
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("unknown payment shouldn't have events: %v", events)
+	}
+}
+
+func TestPaymentRefunds(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+
+	refund := &connectors.Payment{
+		PaymentID: "refund",
+		UpdatedAt: 1,
+		Status:    connectors.Pending,
+		System:    connectors.External,
+		Direction: connectors.Outgoing,
+		Receipt:   "customer",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(0.5),
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx1",
+	}
+
+	if err := store.SavePayment(refund); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	if err := store.SetPaymentRefund("refund", "incoming"); err != nil {
+		t.Fatalf("unable to link refund: %v", err)
+	}
+
+	if err := store.SetPaymentRefund("unknown", "incoming"); err !=
+		connectors.PaymentNotFound {
+		t.Fatalf("expected payment not found error, got: %v", err)
+	}
+
+	// Link is kept when refund is updated by the connector.
+	refund.Status = connectors.Completed
+	refund.UpdatedAt = 2
+	if err := store.SavePayment(refund); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	refunds, err := store.PaymentRefunds("incoming")
+	if err != nil {
+		t.Fatalf("unable to get refunds: %v", err)
+	}
+
+	if len(refunds) != 1 || refunds[0].PaymentID != "refund" ||
+		refunds[0].Status != connectors.Completed ||
+		refunds[0].RefundOf != "incoming" {
+		t.Fatalf("wrong refunds: %v", refunds)
+	}
+}
+
+func TestPaymentQuarantine(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+
+	deposit := &connectors.Payment{
+		PaymentID: "deposit",
+		UpdatedAt: 1,
+		Status:    connectors.Pending,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(0.5),
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx1",
+	}
+
+	if err := store.SavePayment(deposit); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	err = store.SetPaymentQuarantine("deposit", connectors.Quarantined,
+		"sanctions match")
+	if err != nil {
+		t.Fatalf("unable to quarantine payment: %v", err)
+	}
+
+	err = store.SetPaymentQuarantine("unknown", connectors.Quarantined, "")
+	if err != connectors.PaymentNotFound {
+		t.Fatalf("expected payment not found error, got: %v", err)
+	}
+
+	// Quarantine is kept when payment is updated by the connector.
+	deposit.Status = connectors.Completed
+	deposit.UpdatedAt = 2
+	if err := store.SavePayment(deposit); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	if deposit.Quarantine != connectors.Quarantined {
+		t.Fatalf("saved payment should be returned with quarantine")
+	}
+
+	payments, total, err := store.QueryPayments(connectors.PaymentsQuery{
+		Quarantine: connectors.Quarantined,
+	})
+	if err != nil {
+		t.Fatalf("unable to query payments: %v", err)
+	}
+
+	if total != 1 || payments[0].Status != connectors.Completed ||
+		payments[0].QuarantineReason != "sanctions match" {
+		t.Fatalf("wrong quarantined payments: %v", payments)
+	}
+
+	err = store.SetPaymentQuarantine("deposit", connectors.QuarantineReleased,
+		"sanctions match")
+	if err != nil {
+		t.Fatalf("unable to release payment: %v", err)
+	}
+
+	_, total, err = store.QueryPayments(connectors.PaymentsQuery{
+		Quarantine: connectors.Quarantined,
+	})
+	if err != nil {
+		t.Fatalf("unable to query payments: %v", err)
+	}
+
+	if total != 0 {
+		t.Fatalf("released payment shouldn't be quarantined")
+	}
+}
+
+func TestPaymentSequence(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+
+	newPayment := func(id string) *connectors.Payment {
+		return &connectors.Payment{
+			PaymentID: id,
+			UpdatedAt: 1,
+			Status:    connectors.Pending,
+			System:    connectors.External,
+			Direction: connectors.Outgoing,
+			Receipt:   "address",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.NewFromFloat(1.1),
+			MediaFee:  decimal.Zero,
+			MediaID:   id,
+		}
+	}
+
+	save := func(payment *connectors.Payment, sequence uint64) {
+		if err := store.SavePayment(payment); err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+
+		if payment.Sequence != sequence {
+			t.Fatalf("wrong sequence of payment(%v), got(%v), want(%v)",
+				payment.PaymentID, payment.Sequence, sequence)
+		}
+	}
+
+	first := newPayment("first")
+	save(first, 1)
+
+	// Sequence isn't changed if payment hasn't changed.
+	save(newPayment("first"), 1)
+
+	first.Status = connectors.Completed
+	save(first, 2)
+
+	save(newPayment("second"), 3)
+
+	err = store.LabelPayment("first", map[string]string{"order_id": "1"})
+	if err != nil {
+		t.Fatalf("unable to label payment: %v", err)
+	}
+
+	payments, _, err := store.QueryPayments(connectors.PaymentsQuery{
+		SinceSequence: 2,
+		SortBy:        connectors.SortBySequence,
+		Ascending:     true,
+	})
+	if err != nil {
+		t.Fatalf("unable to query payments: %v", err)
+	}
+
+	if len(payments) != 2 || payments[0].PaymentID != "second" ||
+		payments[1].PaymentID != "first" || payments[1].Sequence != 4 {
+		t.Fatalf("payments should be returned in order of changes, "+
+			"got: %v", payments)
+	}
+}
+
+func TestPaymentFiatValue(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := PaymentsStore{db: db}
+
+	deposit := &connectors.Payment{
+		PaymentID: "deposit",
+		UpdatedAt: 1,
+		Status:    connectors.Pending,
+		System:    connectors.External,
+		Direction: connectors.Incoming,
+		Receipt:   "address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(0.5),
+		MediaFee:  decimal.Zero,
+		MediaID:   "tx1",
+	}
+
+	if err := store.SavePayment(deposit); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	deposit.Status = connectors.Completed
+	deposit.UpdatedAt = 2
+	deposit.FiatValue = decimal.New(325006, -2)
+	deposit.FiatCurrency = "USD"
+	if err := store.SavePayment(deposit); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	// Value recorded at the completion isn't changed by the later
+	// valuation of the same payment.
+	deposit.UpdatedAt = 3
+	deposit.FiatValue = decimal.New(4000, 0)
+	if err := store.SavePayment(deposit); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	if deposit.FiatValue.String() != "3250.06" {
+		t.Fatalf("saved payment should be returned with the first value, "+
+			"got: %v", deposit.FiatValue)
+	}
+
+	payment, err := store.PaymentByID("deposit")
+	if err != nil {
+		t.Fatalf("unable to get payment: %v", err)
+	}
+
+	if payment.FiatValue.String() != "3250.06" ||
+		payment.FiatCurrency != "USD" {
+		t.Fatalf("wrong fiat value: %v %v", payment.FiatValue,
+			payment.FiatCurrency)
+	}
+}
@@ -0,0 +1,563 @@
+package sqlite
+
+import (
+	"math"
+	"sort"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+)
+
+// paidReceiptCondition is the condition which is true if completed incoming
+// payment has been received on the receipt.
+const paidReceiptCondition = "EXISTS (SELECT 1 FROM payments WHERE " +
+	"payments.receipt = receipts.receipt AND payments.direction = ? AND " +
+	"payments.status = ?)"
+
+type ReceiptsStore struct {
+	db *DB
+}
+
+func NewReceiptsStore(db *DB) *ReceiptsStore {
+	return &ReceiptsStore{
+		db: db,
+	}
+}
+
+type Receipt struct {
+	// Receipt is the blockchain address or lightning network invoice.
+	Receipt string `gorm:"primary_key"`
+
+	// ReceiptID is the identifier of the receipt, which is derived from the
+	// asset, media and receipt.
+	ReceiptID string `gorm:"index"`
+
+	// Asset is an acronym of the crypto currency.
+	Asset string `gorm:"index"`
+
+	// Media is a type of technology which is used to transport value of
+	// underlying asset.
+	Media string
+
+	// Amount is the requested amount.
+	Amount string
+
+	// Description is the description of the receipt.
+	Description string
+
+	// Reference is the external order reference which invoice commits to
+	// by the description hash.
+	Reference string
+
+	// CreatedAt is the time of the receipt creation in milliseconds.
+	CreatedAt int64 `gorm:"index"`
+
+	// ExpiresAt is the time of the receipt expiration in milliseconds.
+	ExpiresAt int64
+
+	// Tenant is the id of the API key on behalf of which receipt has been
+	// created.
+	Tenant string
+
+	// AccountID is the identifier of the account to which receipt belongs.
+	AccountID string `gorm:"index"`
+
+	// Metadata is the JSON encoded labels of the receipt.
+	Metadata string
+
+	// Replaces is the identifier of the receipt which has been replaced by
+	// this one.
+	Replaces string
+
+	// ReplacedBy is the identifier of the receipt which has replaced this
+	// one.
+	ReplacedBy string `gorm:"index"`
+
+	// Regenerations is the number of the replacements made from the
+	// originally requested receipt up to this one.
+	Regenerations int
+
+	// RefundAddress is the address supplied by the payer on which refunds
+	// are sent.
+	RefundAddress string
+}
+
+type DepositAddress struct {
+	// Address is the blockchain address in the canonical form.
+	Address string `gorm:"primary_key"`
+
+	// Asset is an acronym of the crypto currency.
+	Asset string
+
+	// CreatedAt is the time of the address creation in milliseconds.
+	CreatedAt int64
+
+	// Tenant is the id of the API key on behalf of which address has been
+	// created.
+	Tenant string
+
+	// AccountID is the identifier of the account to which address belongs.
+	AccountID string `gorm:"index"`
+}
+
+// Runtime check to ensure that ReceiptsStore implements
+// connectors.ReceiptsStore interface.
+var _ connectors.ReceiptsStore = (*ReceiptsStore)(nil)
+
+// SaveReceipt adds receipt to the store.
+//
+// NOTE: Part of the connectors.ReceiptsStore interface.
+func (s *ReceiptsStore) SaveReceipt(receipt *connectors.Receipt) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbReceipt, err := newDBReceipt(receipt)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Save(dbReceipt).Error
+}
+
+// ReplaceReceipt saves the replacement and binds the replaced receipt to
+// it, ReceiptReplaced error is returned if receipt has already been
+// replaced.
+//
+// NOTE: Part of the connectors.ReceiptsStore interface.
+func (s *ReceiptsStore) ReplaceReceipt(receiptID string,
+	replacement *connectors.Receipt) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	replacement.Replaces = receiptID
+
+	dbReplacement, err := newDBReceipt(replacement)
+	if err != nil {
+		return err
+	}
+
+	tx := s.db.Begin()
+
+	// Receipt is bound only if it hasn't been replaced yet, so that
+	// concurrent replacements couldn't fork the chain.
+	res := tx.Model(&Receipt{}).
+		Where("receipt_id = ? AND replaced_by = ?", receiptID, "").
+		Update("replaced_by", dbReplacement.ReceiptID)
+	if res.Error != nil {
+		tx.Rollback()
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tx.Rollback()
+
+		var count int
+		err := s.db.Model(&Receipt{}).Where("receipt_id = ?", receiptID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			return connectors.ReceiptNotFound
+		}
+		return connectors.ReceiptReplaced
+	}
+
+	if err := tx.Create(dbReplacement).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// SetReceiptRefundAddress attaches the refund address to the receipt,
+// RefundAddressAttached error is returned if the other address has been
+// already attached.
+//
+// NOTE: Part of the connectors.ReceiptsStore interface.
+func (s *ReceiptsStore) SetReceiptRefundAddress(receiptID,
+	address string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	// Address is attached only once, so that it couldn't be replaced by
+	// the one who has learned the receipt later.
+	res := s.db.Model(&Receipt{}).
+		Where("receipt_id = ? AND (refund_address = ? OR refund_address = ?)",
+			receiptID, "", address).
+		Update("refund_address", address)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected != 0 {
+		return nil
+	}
+
+	var count int
+	err := s.db.Model(&Receipt{}).Where("receipt_id = ?", receiptID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return connectors.ReceiptNotFound
+	}
+	return connectors.RefundAddressAttached
+}
+
+// newDBReceipt converts receipt to the database model, identifier of the
+// receipt is generated if it isn't set.
+func newDBReceipt(receipt *connectors.Receipt) (*Receipt, error) {
+	if receipt.ReceiptID == "" {
+		receipt.ReceiptID = receipt.GenReceiptID()
+	}
+
+	metadata, err := encodeMetadata(receipt.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Receipt{
+		Receipt:       receipt.Receipt,
+		ReceiptID:     receipt.ReceiptID,
+		Asset:         string(receipt.Asset),
+		Media:         string(receipt.Media),
+		Amount:        receipt.Amount.String(),
+		Description:   receipt.Description,
+		Reference:     receipt.Reference,
+		CreatedAt:     receipt.CreatedAt,
+		ExpiresAt:     receipt.ExpiresAt,
+		Tenant:        receipt.Tenant,
+		AccountID:     receipt.AccountID,
+		Metadata:      metadata,
+		Replaces:      receipt.Replaces,
+		ReplacedBy:    receipt.ReplacedBy,
+		Regenerations: receipt.Regenerations,
+		RefundAddress: receipt.RefundAddress,
+	}, nil
+}
+
+// ReceiptTenant returns the tenant on behalf of which receipt, or deposit
+// address, has been created, empty if neither of them is stored.
+//
+// NOTE: Part of the connectors.ReceiptsStore interface.
+func (s *ReceiptsStore) ReceiptTenant(receipt string) (string, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbReceipt := &Receipt{}
+	err := s.db.Where("receipt = ?", receipt).First(dbReceipt).Error
+	switch {
+	case err == nil:
+		return dbReceipt.Tenant, nil
+	case !gorm.IsRecordNotFoundError(err):
+		return "", err
+	}
+
+	dbAddress := &DepositAddress{}
+	err = s.db.Where("address = ?", receipt).First(dbAddress).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return dbAddress.Tenant, nil
+}
+
+// SaveDepositAddress adds address to the store, incoming payments on it are
+// bound to its account, and its tenant is returned by the ReceiptTenant as
+// if it was the receipt.
+//
+// NOTE: Part of the connectors.ReceiptsStore interface.
+func (s *ReceiptsStore) SaveDepositAddress(
+	address *connectors.DepositAddress) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(&DepositAddress{
+		Address:   address.Address,
+		Asset:     string(address.Asset),
+		CreatedAt: address.CreatedAt,
+		Tenant:    address.Tenant,
+		AccountID: address.AccountID,
+	}).Error
+}
+
+// DepositAddressByAddress returns the tenant and the account to which
+// incoming payments on the address are bound. Blockchain receipts are
+// returned as the deposit addresses too. ReceiptNotFound error is returned
+// if address isn't stored.
+//
+// NOTE: Part of the connectors.ReceiptsStore interface.
+func (s *ReceiptsStore) DepositAddressByAddress(address string) (
+	*connectors.DepositAddress, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbReceipt := &Receipt{}
+	err := s.db.Where("receipt = ? AND media = ?", address,
+		string(connectors.Blockchain)).First(dbReceipt).Error
+	switch {
+	case err == nil:
+		return &connectors.DepositAddress{
+			Address:   dbReceipt.Receipt,
+			Asset:     connectors.Asset(dbReceipt.Asset),
+			CreatedAt: dbReceipt.CreatedAt,
+			Tenant:    dbReceipt.Tenant,
+			AccountID: dbReceipt.AccountID,
+		}, nil
+	case !gorm.IsRecordNotFoundError(err):
+		return nil, err
+	}
+
+	dbAddress := &DepositAddress{}
+	err = s.db.Where("address = ?", address).First(dbAddress).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, connectors.ReceiptNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &connectors.DepositAddress{
+		Address:   dbAddress.Address,
+		Asset:     connectors.Asset(dbAddress.Asset),
+		CreatedAt: dbAddress.CreatedAt,
+		Tenant:    dbAddress.Tenant,
+		AccountID: dbAddress.AccountID,
+	}, nil
+}
+
+// ListDepositAddresses returns the deposit addresses of the account ordered
+// by the creation time, blockchain receipts of the account are returned as
+// the deposit addresses too.
+//
+// NOTE: Part of the connectors.ReceiptsStore interface.
+func (s *ReceiptsStore) ListDepositAddresses(accountID string) (
+	[]*connectors.DepositAddress, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbReceipts []*Receipt
+	err := s.db.Where("account_id = ? AND media = ?", accountID,
+		string(connectors.Blockchain)).Find(&dbReceipts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var dbAddresses []*DepositAddress
+	err = s.db.Where("account_id = ?", accountID).Find(&dbAddresses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []*connectors.DepositAddress
+	for _, dbReceipt := range dbReceipts {
+		addresses = append(addresses, &connectors.DepositAddress{
+			Address:   dbReceipt.Receipt,
+			Asset:     connectors.Asset(dbReceipt.Asset),
+			CreatedAt: dbReceipt.CreatedAt,
+			Tenant:    dbReceipt.Tenant,
+			AccountID: dbReceipt.AccountID,
+		})
+	}
+
+	for _, dbAddress := range dbAddresses {
+		addresses = append(addresses, &connectors.DepositAddress{
+			Address:   dbAddress.Address,
+			Asset:     connectors.Asset(dbAddress.Asset),
+			CreatedAt: dbAddress.CreatedAt,
+			Tenant:    dbAddress.Tenant,
+			AccountID: dbAddress.AccountID,
+		})
+	}
+
+	sort.SliceStable(addresses, func(i, j int) bool {
+		if addresses[i].CreatedAt != addresses[j].CreatedAt {
+			return addresses[i].CreatedAt < addresses[j].CreatedAt
+		}
+		return addresses[i].Address < addresses[j].Address
+	})
+
+	return addresses, nil
+}
+
+// ReceiptByID returns receipt with its current status, ReceiptNotFound
+// error is returned if receipt isn't stored.
+//
+// NOTE: Part of the connectors.ReceiptsStore interface.
+func (s *ReceiptsStore) ReceiptByID(receiptID string) (*connectors.Receipt,
+	error) {
+
+	receipts, _, err := s.QueryReceipts(connectors.ReceiptsQuery{
+		ReceiptID: receiptID,
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(receipts) == 0 {
+		return nil, connectors.ReceiptNotFound
+	}
+
+	return receipts[0], nil
+}
+
+// QueryReceipts returns page of receipts which are matching the query,
+// ordered by the creation time from the newest, and the overall number of
+// matching receipts.
+//
+// NOTE: Part of the connectors.ReceiptsStore interface.
+func (s *ReceiptsStore) QueryReceipts(query connectors.ReceiptsQuery) (
+	[]*connectors.Receipt, int, error) {
+
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.DB.Model(&Receipt{})
+
+	if query.ReceiptID != "" {
+		db = db.Where("receipt_id = ?", query.ReceiptID)
+	}
+
+	if query.Asset != "" {
+		db = db.Where("asset = ?", string(query.Asset))
+	}
+
+	if query.Media != "" {
+		db = db.Where("media = ?", string(query.Media))
+	}
+
+	if query.CreatedFrom != 0 {
+		db = db.Where("created_at >= ?", query.CreatedFrom)
+	}
+
+	if query.CreatedTo != 0 {
+		db = db.Where("created_at <= ?", query.CreatedTo)
+	}
+
+	if query.Unreplaced {
+		db = db.Where("replaced_by = ?", "")
+	}
+
+	now := connectors.NowInMilliSeconds()
+	paidArgs := []interface{}{string(connectors.Incoming),
+		string(connectors.Completed)}
+
+	switch query.Status {
+	case "":
+	case connectors.ReceiptPaid:
+		db = db.Where(paidReceiptCondition, paidArgs...)
+	case connectors.ReceiptUnpaid:
+		db = db.Where("NOT "+paidReceiptCondition, paidArgs...).
+			Where("expires_at = 0 OR expires_at > ?", now)
+	case connectors.ReceiptExpired:
+		db = db.Where("NOT "+paidReceiptCondition, paidArgs...).
+			Where("expires_at != 0 AND expires_at <= ?", now)
+	default:
+		return nil, 0, errors.Errorf("unknown receipt status(%v)",
+			query.Status)
+	}
+
+	var total int
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Receipt is used as tie-breaker, so that pages are not overlapping
+	// if receipts are created at the same time.
+	db = db.Order("created_at DESC, receipt DESC")
+
+	limit := query.Limit
+	if limit == 0 && query.Offset != 0 {
+		// Offset couldn't be used without limit in sqlite.
+		limit = math.MaxInt32
+	}
+
+	if limit != 0 {
+		db = db.Limit(limit)
+	}
+
+	if query.Offset != 0 {
+		db = db.Offset(query.Offset)
+	}
+
+	var dbReceipts []*Receipt
+	if err := db.Find(&dbReceipts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if len(dbReceipts) == 0 {
+		return nil, total, nil
+	}
+
+	values := make([]string, len(dbReceipts))
+	for i, dbReceipt := range dbReceipts {
+		values[i] = dbReceipt.Receipt
+	}
+
+	var paid []string
+	err := s.db.DB.Model(&Payment{}).
+		Where("receipt IN (?) AND direction = ? AND status = ?", values,
+			string(connectors.Incoming), string(connectors.Completed)).
+		Pluck("DISTINCT receipt", &paid).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	paidReceipts := make(map[string]struct{}, len(paid))
+	for _, receipt := range paid {
+		paidReceipts[receipt] = struct{}{}
+	}
+
+	var receipts []*connectors.Receipt
+	for _, dbReceipt := range dbReceipts {
+		amount, err := decimal.NewFromString(dbReceipt.Amount)
+		if err != nil {
+			return nil, 0, errors.Errorf("unable to decode amount of "+
+				"receipt(%v): %v", dbReceipt.Receipt, err)
+		}
+
+		metadata, err := decodeMetadata(dbReceipt.Metadata)
+		if err != nil {
+			return nil, 0, errors.Errorf("unable to decode metadata of "+
+				"receipt(%v): %v", dbReceipt.Receipt, err)
+		}
+
+		status := connectors.ReceiptUnpaid
+		if _, ok := paidReceipts[dbReceipt.Receipt]; ok {
+			status = connectors.ReceiptPaid
+		} else if dbReceipt.ExpiresAt != 0 && dbReceipt.ExpiresAt <= now {
+			status = connectors.ReceiptExpired
+		}
+
+		receipts = append(receipts, &connectors.Receipt{
+			ReceiptID:     dbReceipt.ReceiptID,
+			Receipt:       dbReceipt.Receipt,
+			Asset:         connectors.Asset(dbReceipt.Asset),
+			Media:         connectors.PaymentMedia(dbReceipt.Media),
+			Amount:        amount,
+			Description:   dbReceipt.Description,
+			Reference:     dbReceipt.Reference,
+			CreatedAt:     dbReceipt.CreatedAt,
+			ExpiresAt:     dbReceipt.ExpiresAt,
+			Status:        status,
+			Tenant:        dbReceipt.Tenant,
+			AccountID:     dbReceipt.AccountID,
+			Metadata:      metadata,
+			Replaces:      dbReceipt.Replaces,
+			ReplacedBy:    dbReceipt.ReplacedBy,
+			Regenerations: dbReceipt.Regenerations,
+			RefundAddress: dbReceipt.RefundAddress,
+		})
+	}
+
+	return receipts, total, nil
+}
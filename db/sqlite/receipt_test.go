@@ -0,0 +1,494 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"reflect"
+	"testing"
+)
+
+func TestReceiptsStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewReceiptsStore(db)
+	paymentsStore := NewPaymentStore(db)
+
+	receipts := []*connectors.Receipt{
+		{
+			Receipt:   "paid",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.Zero,
+			CreatedAt: 1,
+		},
+		{
+			Receipt:   "unpaid",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			Amount:    decimal.Zero,
+			CreatedAt: 2,
+			Tenant:    "merchant",
+		},
+		{
+			Receipt:     "expired",
+			Asset:       connectors.BTC,
+			Media:       connectors.Lightning,
+			Amount:      decimal.NewFromFloat(0.1),
+			Description: "order",
+			CreatedAt:   3,
+			ExpiresAt:   4,
+		},
+	}
+
+	for _, receipt := range receipts {
+		if err := store.SaveReceipt(receipt); err != nil {
+			t.Fatalf("unable to save receipt: %v", err)
+		}
+	}
+
+	err = paymentsStore.SavePayment(&connectors.Payment{
+		PaymentID: "1",
+		Status:    connectors.Completed,
+		Direction: connectors.Incoming,
+		System:    connectors.External,
+		Receipt:   "paid",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(1),
+		MediaFee:  decimal.Zero,
+	})
+	if err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	tests := []struct {
+		query connectors.ReceiptsQuery
+		want  []string
+		total int
+	}{
+		{
+			query: connectors.ReceiptsQuery{},
+			want:  []string{"expired", "unpaid", "paid"},
+			total: 3,
+		},
+		{
+			query: connectors.ReceiptsQuery{Status: connectors.ReceiptPaid},
+			want:  []string{"paid"},
+			total: 1,
+		},
+		{
+			query: connectors.ReceiptsQuery{Status: connectors.ReceiptUnpaid},
+			want:  []string{"unpaid"},
+			total: 1,
+		},
+		{
+			query: connectors.ReceiptsQuery{Status: connectors.ReceiptExpired},
+			want:  []string{"expired"},
+			total: 1,
+		},
+		{
+			query: connectors.ReceiptsQuery{Media: connectors.Blockchain,
+				Limit: 1, Offset: 1},
+			want:  []string{"paid"},
+			total: 2,
+		},
+		{
+			query: connectors.ReceiptsQuery{CreatedFrom: 2, CreatedTo: 2},
+			want:  []string{"unpaid"},
+			total: 1,
+		},
+	}
+
+	for i, test := range tests {
+		page, total, err := store.QueryReceipts(test.query)
+		if err != nil {
+			t.Fatalf("(%v) unable to query receipts: %v", i, err)
+		}
+
+		if total != test.total {
+			t.Fatalf("(%v) wrong total, got(%v), want(%v)", i, total,
+				test.total)
+		}
+
+		if len(page) != len(test.want) {
+			t.Fatalf("(%v) wrong number of receipts, got(%v), want(%v)", i,
+				len(page), len(test.want))
+		}
+
+		for j, receipt := range page {
+			if receipt.Receipt != test.want[j] {
+				t.Fatalf("(%v) wrong receipt, got(%v), want(%v)", i,
+					receipt.Receipt, test.want[j])
+			}
+
+			if string(receipt.Status) != receipt.Receipt {
+				t.Fatalf("(%v) wrong status of receipt(%v): %v", i,
+					receipt.Receipt, receipt.Status)
+			}
+		}
+	}
+
+	receipt, err := store.ReceiptByID(receipts[2].ReceiptID)
+	if err != nil {
+		t.Fatalf("unable to get receipt by id: %v", err)
+	}
+
+	if receipt.Receipt != "expired" || receipt.Status != connectors.ReceiptExpired {
+		t.Fatalf("wrong receipt(%v) with status(%v)", receipt.Receipt,
+			receipt.Status)
+	}
+
+	if _, err := store.ReceiptByID("unknown"); err != connectors.ReceiptNotFound {
+		t.Fatalf("unknown receipt shouldn't be found: %v", err)
+	}
+
+	tenant, err := store.ReceiptTenant("unpaid")
+	if err != nil {
+		t.Fatalf("unable to get receipt tenant: %v", err)
+	}
+
+	if tenant != "merchant" {
+		t.Fatalf("wrong tenant, got(%v), want(%v)", tenant, "merchant")
+	}
+
+	tenant, err = store.ReceiptTenant("unknown")
+	if err != nil || tenant != "" {
+		t.Fatalf("unknown receipt should have empty tenant, got(%v): %v",
+			tenant, err)
+	}
+}
+
+func TestReplaceReceipt(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewReceiptsStore(db)
+
+	original := &connectors.Receipt{
+		Receipt:   "original",
+		Asset:     connectors.BTC,
+		Media:     connectors.Lightning,
+		Amount:    decimal.NewFromFloat(0.1),
+		CreatedAt: 1,
+		ExpiresAt: 2,
+		Tenant:    "merchant",
+	}
+	if err := store.SaveReceipt(original); err != nil {
+		t.Fatalf("unable to save receipt: %v", err)
+	}
+
+	newReplacement := func(receipt string) *connectors.Receipt {
+		return &connectors.Receipt{
+			Receipt:       receipt,
+			Asset:         connectors.BTC,
+			Media:         connectors.Lightning,
+			Amount:        original.Amount,
+			CreatedAt:     3,
+			Tenant:        original.Tenant,
+			Regenerations: 1,
+		}
+	}
+
+	replacement := newReplacement("replacement")
+	if err := store.ReplaceReceipt(original.ReceiptID, replacement); err != nil {
+		t.Fatalf("unable to replace receipt: %v", err)
+	}
+
+	// Chain shouldn't be forked by the second replacement of the same
+	// receipt.
+	err = store.ReplaceReceipt(original.ReceiptID, newReplacement("fork"))
+	if err != connectors.ReceiptReplaced {
+		t.Fatalf("receipt shouldn't be replaced twice: %v", err)
+	}
+
+	err = store.ReplaceReceipt("unknown", newReplacement("unknown"))
+	if err != connectors.ReceiptNotFound {
+		t.Fatalf("unknown receipt shouldn't be replaced: %v", err)
+	}
+
+	receipt, err := store.ReceiptByID(original.ReceiptID)
+	if err != nil {
+		t.Fatalf("unable to get receipt by id: %v", err)
+	}
+
+	if receipt.ReplacedBy != replacement.ReceiptID {
+		t.Fatalf("receipt isn't bound to the replacement: %v",
+			receipt.ReplacedBy)
+	}
+
+	receipts, total, err := store.QueryReceipts(connectors.ReceiptsQuery{
+		Unreplaced: true,
+	})
+	if err != nil {
+		t.Fatalf("unable to query receipts: %v", err)
+	}
+
+	if total != 1 || receipts[0].Receipt != "replacement" {
+		t.Fatalf("only replacement should be unreplaced: %v", receipts)
+	}
+
+	if receipts[0].Replaces != original.ReceiptID ||
+		receipts[0].Regenerations != 1 || receipts[0].Tenant != "merchant" {
+		t.Fatalf("wrong replacement: %v", receipts[0])
+	}
+}
+
+func TestReceiptRefundAddress(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewReceiptsStore(db)
+
+	receipt := &connectors.Receipt{
+		Receipt:   "address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.Zero,
+		CreatedAt: 1,
+	}
+	if err := store.SaveReceipt(receipt); err != nil {
+		t.Fatalf("unable to save receipt: %v", err)
+	}
+
+	err = store.SetReceiptRefundAddress(receipt.ReceiptID, "refund")
+	if err != nil {
+		t.Fatalf("unable to attach refund address: %v", err)
+	}
+
+	// The same address could be attached again, e.g. on retry.
+	err = store.SetReceiptRefundAddress(receipt.ReceiptID, "refund")
+	if err != nil {
+		t.Fatalf("unable to attach refund address again: %v", err)
+	}
+
+	err = store.SetReceiptRefundAddress(receipt.ReceiptID, "other")
+	if err != connectors.RefundAddressAttached {
+		t.Fatalf("refund address shouldn't be replaced: %v", err)
+	}
+
+	err = store.SetReceiptRefundAddress("unknown", "refund")
+	if err != connectors.ReceiptNotFound {
+		t.Fatalf("address shouldn't be attached to unknown receipt: %v",
+			err)
+	}
+
+	stored, err := store.ReceiptByID(receipt.ReceiptID)
+	if err != nil {
+		t.Fatalf("unable to get receipt by id: %v", err)
+	}
+
+	if stored.RefundAddress != "refund" {
+		t.Fatalf("wrong refund address: %v", stored.RefundAddress)
+	}
+}
+
+func TestDepositAddress(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewReceiptsStore(db)
+	paymentsStore := NewPaymentStore(db)
+
+	err = store.SaveDepositAddress(&connectors.DepositAddress{
+		Address:   "address",
+		Asset:     connectors.BTC,
+		CreatedAt: 1,
+		Tenant:    "merchant",
+		AccountID: "shop",
+	})
+	if err != nil {
+		t.Fatalf("unable to save deposit address: %v", err)
+	}
+
+	tenant, err := store.ReceiptTenant("address")
+	if err != nil || tenant != "merchant" {
+		t.Fatalf("wrong tenant, got(%v): %v", tenant, err)
+	}
+
+	payment := &connectors.Payment{
+		PaymentID: "1",
+		Status:    connectors.Pending,
+		Direction: connectors.Incoming,
+		System:    connectors.External,
+		Receipt:   "address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(1),
+		MediaFee:  decimal.Zero,
+	}
+	if err := paymentsStore.SavePayment(payment); err != nil {
+		t.Fatalf("unable to save payment: %v", err)
+	}
+
+	if payment.AccountID != "shop" {
+		t.Fatalf("payment isn't bound to the account of the address: %v",
+			payment.AccountID)
+	}
+
+	// Address isn't the receipt, that is why it isn't listed.
+	receipts, _, err := store.QueryReceipts(connectors.ReceiptsQuery{})
+	if err != nil {
+		t.Fatalf("unable to query receipts: %v", err)
+	}
+
+	if len(receipts) != 0 {
+		t.Fatalf("deposit address shouldn't be listed as receipt")
+	}
+}
+
+func TestDepositAddressByAddress(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewReceiptsStore(db)
+
+	err = store.SaveReceipt(&connectors.Receipt{
+		ReceiptID: "receipt_id",
+		Receipt:   "receipt",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		CreatedAt: 1,
+		Tenant:    "merchant",
+		AccountID: "shop",
+	})
+	if err != nil {
+		t.Fatalf("unable to save receipt: %v", err)
+	}
+
+	err = store.SaveDepositAddress(&connectors.DepositAddress{
+		Address:   "address",
+		Asset:     connectors.LTC,
+		CreatedAt: 2,
+		Tenant:    "exchange",
+		AccountID: "games",
+	})
+	if err != nil {
+		t.Fatalf("unable to save deposit address: %v", err)
+	}
+
+	address, err := store.DepositAddressByAddress("receipt")
+	if err != nil {
+		t.Fatalf("unable to get receipt address: %v", err)
+	}
+
+	if address.Asset != connectors.BTC || address.Tenant != "merchant" ||
+		address.AccountID != "shop" {
+		t.Fatalf("wrong receipt address: %v", address)
+	}
+
+	address, err = store.DepositAddressByAddress("address")
+	if err != nil {
+		t.Fatalf("unable to get deposit address: %v", err)
+	}
+
+	if address.Asset != connectors.LTC || address.Tenant != "exchange" ||
+		address.AccountID != "games" || address.CreatedAt != 2 {
+		t.Fatalf("wrong deposit address: %v", address)
+	}
+
+	_, err = store.DepositAddressByAddress("unknown")
+	if err != connectors.ReceiptNotFound {
+		t.Fatalf("unknown address shouldn't be found: %v", err)
+	}
+}
+
+func TestListDepositAddresses(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewReceiptsStore(db)
+
+	receipts := []*connectors.Receipt{
+		{
+			ReceiptID: "receipt_id",
+			Receipt:   "receipt",
+			Asset:     connectors.BTC,
+			Media:     connectors.Blockchain,
+			CreatedAt: 2,
+			AccountID: "shop",
+		},
+		{
+			ReceiptID: "invoice_id",
+			Receipt:   "invoice",
+			Asset:     connectors.BTC,
+			Media:     connectors.Lightning,
+			CreatedAt: 3,
+			AccountID: "shop",
+		},
+	}
+
+	for _, receipt := range receipts {
+		if err := store.SaveReceipt(receipt); err != nil {
+			t.Fatalf("unable to save receipt: %v", err)
+		}
+	}
+
+	addresses := []*connectors.DepositAddress{
+		{
+			Address:   "address1",
+			Asset:     connectors.LTC,
+			CreatedAt: 1,
+			AccountID: "shop",
+		},
+		{
+			Address:   "address2",
+			Asset:     connectors.LTC,
+			CreatedAt: 2,
+			AccountID: "games",
+		},
+		{
+			Address:   "address3",
+			Asset:     connectors.LTC,
+			CreatedAt: 4,
+			AccountID: "shop",
+		},
+	}
+
+	for _, address := range addresses {
+		if err := store.SaveDepositAddress(address); err != nil {
+			t.Fatalf("unable to save deposit address: %v", err)
+		}
+	}
+
+	addresses, err = store.ListDepositAddresses("shop")
+	if err != nil {
+		t.Fatalf("unable to list deposit addresses: %v", err)
+	}
+
+	// Lightning invoice isn't the deposit address, and addresses are
+	// ordered by the creation time.
+	var listed []string
+	for _, address := range addresses {
+		listed = append(listed, address.Address)
+	}
+
+	expected := []string{"address1", "receipt", "address3"}
+	if !reflect.DeepEqual(listed, expected) {
+		t.Fatalf("wrong addresses, got(%v), want(%v)", listed, expected)
+	}
+
+	addresses, err = store.ListDepositAddresses("unknown")
+	if err != nil || len(addresses) != 0 {
+		t.Fatalf("addresses of unknown account are listed: %v, %v",
+			addresses, err)
+	}
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+)
+
+type TestPaymentsStore struct {
+	db *DB
+}
+
+func NewTestPaymentsStore(db *DB) *TestPaymentsStore {
+	return &TestPaymentsStore{
+		db: db,
+	}
+}
+
+type TestPaymentSchedule struct {
+	// PaymentID is the id of the pending test payment.
+	PaymentID string `gorm:"primary_key"`
+
+	// CompleteAt is the time of the completion in milliseconds.
+	CompleteAt int64
+}
+
+// Runtime check to ensure that TestPaymentsStore implements
+// connectors.TestPaymentsStore interface.
+var _ connectors.TestPaymentsStore = (*TestPaymentsStore)(nil)
+
+// ScheduleTestPayment adds completion time of the test payment.
+//
+// NOTE: Part of the connectors.TestPaymentsStore interface.
+func (s *TestPaymentsStore) ScheduleTestPayment(
+	schedule *connectors.TestPaymentSchedule) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(&TestPaymentSchedule{
+		PaymentID:  schedule.PaymentID,
+		CompleteAt: schedule.CompleteAt,
+	}).Error
+}
+
+// ListTestPaymentSchedules returns schedules of the test payments which
+// haven't been completed yet.
+//
+// NOTE: Part of the connectors.TestPaymentsStore interface.
+func (s *TestPaymentsStore) ListTestPaymentSchedules() (
+	[]*connectors.TestPaymentSchedule, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbSchedules []*TestPaymentSchedule
+	if err := s.db.Order("complete_at").Find(&dbSchedules).Error; err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*connectors.TestPaymentSchedule, len(dbSchedules))
+	for i, schedule := range dbSchedules {
+		schedules[i] = &connectors.TestPaymentSchedule{
+			PaymentID:  schedule.PaymentID,
+			CompleteAt: schedule.CompleteAt,
+		}
+	}
+
+	return schedules, nil
+}
+
+// RemoveTestPaymentSchedule removes schedule of the completed test payment.
+//
+// NOTE: Part of the connectors.TestPaymentsStore interface.
+func (s *TestPaymentsStore) RemoveTestPaymentSchedule(paymentID string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Where("payment_id = ?", paymentID).
+		Delete(&TestPaymentSchedule{}).Error
+}
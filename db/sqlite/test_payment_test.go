@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+)
+
+func TestTestPaymentsStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewTestPaymentsStore(db)
+
+	schedules := []*connectors.TestPaymentSchedule{
+		{PaymentID: "2", CompleteAt: 20},
+		{PaymentID: "1", CompleteAt: 10},
+	}
+
+	for _, schedule := range schedules {
+		if err := store.ScheduleTestPayment(schedule); err != nil {
+			t.Fatalf("unable to schedule test payment: %v", err)
+		}
+	}
+
+	if err := store.RemoveTestPaymentSchedule("2"); err != nil {
+		t.Fatalf("unable to remove schedule: %v", err)
+	}
+
+	schedulesAfter, err := store.ListTestPaymentSchedules()
+	if err != nil {
+		t.Fatalf("unable to list schedules: %v", err)
+	}
+
+	if !reflect.DeepEqual(schedulesAfter, schedules[1:]) {
+		t.Fatalf("wrong schedules: %v", schedulesAfter)
+	}
+}
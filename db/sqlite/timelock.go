@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"encoding/hex"
+
+	"github.com/bitlum/connector/connectors"
+)
+
+type TimeLocksStore struct {
+	db *DB
+}
+
+func NewTimeLocksStore(db *DB) *TimeLocksStore {
+	return &TimeLocksStore{
+		db: db,
+	}
+}
+
+type TimeLock struct {
+	// PaymentID is the id of the outgoing payment on the script address.
+	PaymentID string `gorm:"primary_key"`
+
+	// Asset is an acronym of the crypto currency.
+	Asset string `gorm:"index"`
+
+	// Address is the script address.
+	Address string
+
+	// RedeemScript is the hex encoded script.
+	RedeemScript string
+
+	// RecipientPubKey is the hex encoded public key of the recipient.
+	RecipientPubKey string
+
+	// LockTime is the block height or the unix time after which output
+	// could be spent.
+	LockTime uint32
+
+	// CreatedAt is the time of the payment in milliseconds.
+	CreatedAt int64
+}
+
+// Runtime check to ensure that TimeLocksStore implements
+// connectors.TimeLocksStore interface.
+var _ connectors.TimeLocksStore = (*TimeLocksStore)(nil)
+
+// SaveTimeLock adds time lock to the store.
+//
+// NOTE: Part of the connectors.TimeLocksStore interface.
+func (s *TimeLocksStore) SaveTimeLock(lock *connectors.TimeLock) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(convertTimeLockTo(lock)).Error
+}
+
+// SaveTimeLockedPayment saves the payment along with its time lock in one
+// transaction.
+//
+// NOTE: Part of the connectors.TimeLocksStore interface.
+func (s *TimeLocksStore) SaveTimeLockedPayment(payment *connectors.Payment,
+	lock *connectors.TimeLock) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	dbPayment, err := convertPaymentTo(payment)
+	if err != nil {
+		return err
+	}
+
+	tx := s.db.Begin()
+	if err := tx.Save(dbPayment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Save(convertTimeLockTo(lock)).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ListTimeLocks returns time locks of the given asset, empty asset is used
+// to return all of them.
+//
+// NOTE: Part of the connectors.TimeLocksStore interface.
+func (s *TimeLocksStore) ListTimeLocks(asset connectors.Asset) (
+	[]*connectors.TimeLock, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.Order("created_at")
+	if asset != "" {
+		db = db.Where("asset = ?", string(asset))
+	}
+
+	var dbLocks []*TimeLock
+	if err := db.Find(&dbLocks).Error; err != nil {
+		return nil, err
+	}
+
+	var locks []*connectors.TimeLock
+	for _, dbLock := range dbLocks {
+		script, err := hex.DecodeString(dbLock.RedeemScript)
+		if err != nil {
+			return nil, err
+		}
+
+		locks = append(locks, &connectors.TimeLock{
+			PaymentID:       dbLock.PaymentID,
+			Asset:           connectors.Asset(dbLock.Asset),
+			Address:         dbLock.Address,
+			RedeemScript:    script,
+			RecipientPubKey: dbLock.RecipientPubKey,
+			LockTime:        dbLock.LockTime,
+			CreatedAt:       dbLock.CreatedAt,
+		})
+	}
+
+	return locks, nil
+}
+
+func convertTimeLockTo(lock *connectors.TimeLock) *TimeLock {
+	return &TimeLock{
+		PaymentID:       lock.PaymentID,
+		Asset:           string(lock.Asset),
+		Address:         lock.Address,
+		RedeemScript:    hex.EncodeToString(lock.RedeemScript),
+		RecipientPubKey: lock.RecipientPubKey,
+		LockTime:        lock.LockTime,
+		CreatedAt:       lock.CreatedAt,
+	}
+}
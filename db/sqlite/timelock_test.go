@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+	"reflect"
+	"testing"
+)
+
+func TestTimeLocksStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewTimeLocksStore(db)
+
+	locksBefore := []*connectors.TimeLock{
+		{
+			PaymentID:       "1",
+			Asset:           connectors.BTC,
+			Address:         "address1",
+			RedeemScript:    []byte{0x01, 0x02},
+			RecipientPubKey: "pubkey1",
+			LockTime:        600000,
+			CreatedAt:       1,
+		},
+		{
+			PaymentID:       "2",
+			Asset:           connectors.LTC,
+			Address:         "address2",
+			RedeemScript:    []byte{0x03},
+			RecipientPubKey: "pubkey2",
+			LockTime:        1700000000,
+			CreatedAt:       2,
+		},
+	}
+
+	for _, lock := range locksBefore {
+		if err := store.SaveTimeLock(lock); err != nil {
+			t.Fatalf("unable to save time lock: %v", err)
+		}
+	}
+
+	locksAfter, err := store.ListTimeLocks("")
+	if err != nil {
+		t.Fatalf("unable to list time locks: %v", err)
+	}
+
+	if !reflect.DeepEqual(locksBefore, locksAfter) {
+		t.Fatalf("wrong data")
+	}
+
+	locksAfter, err = store.ListTimeLocks(connectors.LTC)
+	if err != nil {
+		t.Fatalf("unable to list time locks: %v", err)
+	}
+
+	if !reflect.DeepEqual(locksBefore[1:], locksAfter) {
+		t.Fatalf("wrong data")
+	}
+}
+
+func TestSaveTimeLockedPayment(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewTimeLocksStore(db)
+
+	payment := &connectors.Payment{
+		PaymentID: "1",
+		UpdatedAt: 1,
+		Status:    connectors.Pending,
+		System:    connectors.External,
+		Direction: connectors.Outgoing,
+		Receipt:   "address",
+		Asset:     connectors.BTC,
+		Media:     connectors.Blockchain,
+		Amount:    decimal.NewFromFloat(1.1),
+		MediaFee:  decimal.NewFromFloat(0.1),
+		MediaID:   "txid",
+	}
+
+	lock := &connectors.TimeLock{
+		PaymentID:       "1",
+		Asset:           connectors.BTC,
+		Address:         "address",
+		RedeemScript:    []byte{0x01, 0x02},
+		RecipientPubKey: "pubkey",
+		LockTime:        600000,
+		CreatedAt:       1,
+	}
+
+	if err := store.SaveTimeLockedPayment(payment, lock); err != nil {
+		t.Fatalf("unable to save time-locked payment: %v", err)
+	}
+
+	locks, err := store.ListTimeLocks(connectors.BTC)
+	if err != nil {
+		t.Fatalf("unable to list time locks: %v", err)
+	}
+
+	if !reflect.DeepEqual(locks, []*connectors.TimeLock{lock}) {
+		t.Fatalf("wrong time locks: %v", locks)
+	}
+
+	stored, err := NewPaymentStore(db).PaymentByID("1")
+	if err != nil {
+		t.Fatalf("payment should be saved along with lock: %v", err)
+	}
+
+	if stored.Receipt != payment.Receipt || stored.MediaID != payment.MediaID {
+		t.Fatalf("wrong payment: %v", stored)
+	}
+}
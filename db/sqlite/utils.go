@@ -1,8 +1,11 @@
 package sqlite
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
+
+	"github.com/go-errors/errors"
 )
 
 // MakeTestDB creates a new instance of the ChannelDB for testing purposes. A
@@ -28,3 +31,32 @@ func MakeTestDB() (*DB, func(), error) {
 
 	return db, cleanUp, nil
 }
+
+// encodeMetadata encodes metadata labels in JSON, empty metadata is
+// encoded as empty string.
+func encodeMetadata(metadata map[string]string) (string, error) {
+	if len(metadata) == 0 {
+		return "", nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", errors.Errorf("unable to encode metadata: %v", err)
+	}
+
+	return string(data), nil
+}
+
+// decodeMetadata decodes JSON encoded metadata labels.
+func decodeMetadata(data string) (map[string]string, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(data), &metadata); err != nil {
+		return nil, errors.Errorf("unable to decode metadata: %v", err)
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,352 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
+)
+
+type WatchStore struct {
+	db *DB
+}
+
+func NewWatchStore(db *DB) *WatchStore {
+	return &WatchStore{
+		db: db,
+	}
+}
+
+type WatchAddress struct {
+	// Asset is an acronym of the crypto currency.
+	Asset string `gorm:"primary_key"`
+
+	// Address is the blockchain address in the canonical form.
+	Address string `gorm:"primary_key"`
+
+	// Group is the label of the group to which address belongs.
+	Group string `gorm:"index"`
+
+	// Account is the external account to which address is mapped.
+	Account string
+
+	// Inactive denotes that activity of the address isn't tracked.
+	Inactive bool
+}
+
+type WatchEvent struct {
+	// EventID is the unique identificator of the event.
+	EventID string `gorm:"primary_key"`
+
+	// CreatedAt denotes the time when event has been detected.
+	CreatedAt int64
+
+	// Group is the label of the group to which watched address belongs.
+	Group string `gorm:"index"`
+
+	// Asset is an acronym of the crypto currency.
+	Asset string
+
+	// Address is the watched address which was touched.
+	Address string
+
+	// Account is the external account to which watched address is mapped.
+	Account string
+
+	// Direction denotes whether funds were received or sent by the
+	// watched address.
+	Direction string
+
+	// Amount is the number of funds which were moved.
+	Amount string
+
+	// TxID is the identificator of the transaction in the blockchain.
+	TxID string
+
+	// Delivered denotes whether event has been delivered to the listener.
+	Delivered bool `gorm:"index"`
+
+	// Redelivery denotes that event is sent again on the request of the
+	// operator.
+	Redelivery bool
+}
+
+// Runtime check to ensure that WatchStore implements
+// connectors.WatchStore interface.
+var _ connectors.WatchStore = (*WatchStore)(nil)
+
+// SaveWatchAddress adds address to the watched group.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) SaveWatchAddress(address *connectors.WatchAddress) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(convertWatchAddressTo(address)).Error
+}
+
+// ImportWatchAddresses saves addresses in one transaction, addresses which
+// are already watched are updated, so that import could be repeated.
+// Returns number of added and updated addresses.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) ImportWatchAddresses(
+	addresses []*connectors.WatchAddress) (int, int, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var added, updated int
+
+	tx := s.db.Begin()
+	for _, address := range addresses {
+		dbAddress := convertWatchAddressTo(address)
+
+		existing := &WatchAddress{}
+		err := tx.Where("asset = ? AND address = ?", dbAddress.Asset,
+			dbAddress.Address).Find(existing).Error
+		switch {
+		case gorm.IsRecordNotFoundError(err):
+			added++
+		case err != nil:
+			tx.Rollback()
+			return 0, 0, err
+		case *existing == *dbAddress:
+			continue
+		default:
+			updated++
+		}
+
+		if err := tx.Save(dbAddress).Error; err != nil {
+			tx.Rollback()
+			return 0, 0, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, 0, err
+	}
+
+	return added, updated, nil
+}
+
+// RemoveWatchAddress stops watching the address.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) RemoveWatchAddress(asset connectors.Asset,
+	address string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.Delete(&WatchAddress{}, "asset = ? AND address = ?",
+		string(asset), address)
+	if db.Error != nil {
+		return db.Error
+	}
+
+	if db.RowsAffected == 0 {
+		return connectors.WatchAddressNotFound
+	}
+
+	return nil
+}
+
+// ListWatchAddresses returns watched addresses of the given asset and
+// group, empty values are used to return all of them.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) ListWatchAddresses(asset connectors.Asset,
+	group string) ([]*connectors.WatchAddress, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.DB
+
+	if asset != "" {
+		db = db.Where("asset = ?", string(asset))
+	}
+
+	if group != "" {
+		db = db.Where("`group` = ?", group)
+	}
+
+	var dbAddresses []*WatchAddress
+	if err := db.Order("`group`, address").Find(&dbAddresses).Error; err != nil {
+		return nil, err
+	}
+
+	var addresses []*connectors.WatchAddress
+	for _, dbAddress := range dbAddresses {
+		addresses = append(addresses, &connectors.WatchAddress{
+			Group:    dbAddress.Group,
+			Asset:    connectors.Asset(dbAddress.Asset),
+			Address:  dbAddress.Address,
+			Account:  dbAddress.Account,
+			Inactive: dbAddress.Inactive,
+		})
+	}
+
+	return addresses, nil
+}
+
+// WatchEventExists returns whether event with given id has been already
+// saved.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) WatchEventExists(eventID string) (bool, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	err := s.db.Where("event_id = ?", eventID).Find(&WatchEvent{}).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SaveWatchEvent add event to the store.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) SaveWatchEvent(event *connectors.WatchEvent) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(&WatchEvent{
+		EventID:   event.EventID,
+		CreatedAt: event.CreatedAt,
+		Group:     event.Group,
+		Asset:     string(event.Asset),
+		Address:   event.Address,
+		Account:   event.Account,
+		Direction: string(event.Direction),
+		Amount:    event.Amount.String(),
+		TxID:      event.TxID,
+	}).Error
+}
+
+// ListUndeliveredWatchEvents returns events which haven't been delivered
+// to the listener yet, oldest first.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) ListUndeliveredWatchEvents() ([]*connectors.WatchEvent,
+	error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbEvents []*WatchEvent
+	err := s.db.Where("delivered = ?", false).Order("created_at").
+		Find(&dbEvents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return convertWatchEvents(dbEvents)
+}
+
+// MarkWatchEventDelivered marks event as delivered, so that it wouldn't be
+// sent again.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) MarkWatchEventDelivered(eventID string) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Model(&WatchEvent{}).Where("event_id = ?", eventID).
+		Update("delivered", true).Error
+}
+
+// RedeliverWatchEvents marks delivered events of the given group, which were
+// created within the bounds in milliseconds, inclusive, as undelivered
+// redeliveries. Empty group and zero bounds are not used in the filter.
+// Returns number of the events to be redelivered.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) RedeliverWatchEvents(group string, createdFrom,
+	createdTo int64) (int, error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.Model(&WatchEvent{}).Where("delivered = ?", true)
+
+	if group != "" {
+		db = db.Where("`group` = ?", group)
+	}
+
+	if createdFrom != 0 {
+		db = db.Where("created_at >= ?", createdFrom)
+	}
+
+	if createdTo != 0 {
+		db = db.Where("created_at <= ?", createdTo)
+	}
+
+	db = db.Updates(map[string]interface{}{
+		"delivered":  false,
+		"redelivery": true,
+	})
+	if db.Error != nil {
+		return 0, db.Error
+	}
+
+	return int(db.RowsAffected), nil
+}
+
+// ListWatchEvents returns events of the given group, empty group is used
+// to return all of them.
+//
+// NOTE: Part of the connectors.WatchStore interface.
+func (s *WatchStore) ListWatchEvents(group string) ([]*connectors.WatchEvent,
+	error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	db := s.db.DB
+
+	if group != "" {
+		db = db.Where("`group` = ?", group)
+	}
+
+	var dbEvents []*WatchEvent
+	if err := db.Order("created_at desc").Find(&dbEvents).Error; err != nil {
+		return nil, err
+	}
+
+	return convertWatchEvents(dbEvents)
+}
+
+func convertWatchEvents(dbEvents []*WatchEvent) ([]*connectors.WatchEvent,
+	error) {
+	var events []*connectors.WatchEvent
+	for _, dbEvent := range dbEvents {
+		amount, err := decimal.NewFromString(dbEvent.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, &connectors.WatchEvent{
+			EventID:    dbEvent.EventID,
+			CreatedAt:  dbEvent.CreatedAt,
+			Group:      dbEvent.Group,
+			Asset:      connectors.Asset(dbEvent.Asset),
+			Address:    dbEvent.Address,
+			Account:    dbEvent.Account,
+			Direction:  connectors.PaymentDirection(dbEvent.Direction),
+			Amount:     amount,
+			TxID:       dbEvent.TxID,
+			Redelivery: dbEvent.Redelivery,
+		})
+	}
+
+	return events, nil
+}
+
+func convertWatchAddressTo(address *connectors.WatchAddress) *WatchAddress {
+	return &WatchAddress{
+		Asset:    string(address.Asset),
+		Address:  address.Address,
+		Group:    address.Group,
+		Account:  address.Account,
+		Inactive: address.Inactive,
+	}
+}
@@ -0,0 +1,274 @@
+package sqlite
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+)
+
+func TestWatchAddressesStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewWatchStore(db)
+
+	addresses := []*connectors.WatchAddress{
+		{
+			Group:   "attacker",
+			Asset:   connectors.BTC,
+			Address: "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+		},
+		{
+			Group:   "legacy",
+			Asset:   connectors.ETH,
+			Address: "0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe",
+		},
+	}
+
+	for _, address := range addresses {
+		if err := store.SaveWatchAddress(address); err != nil {
+			t.Fatalf("unable to save watch address: %v", err)
+		}
+	}
+
+	allAddresses, err := store.ListWatchAddresses("", "")
+	if err != nil {
+		t.Fatalf("unable to list watch addresses: %v", err)
+	}
+
+	if !reflect.DeepEqual(addresses, allAddresses) {
+		t.Fatalf("wrong data")
+	}
+
+	btcAddresses, err := store.ListWatchAddresses(connectors.BTC, "")
+	if err != nil {
+		t.Fatalf("unable to list watch addresses: %v", err)
+	}
+
+	if !reflect.DeepEqual(addresses[:1], btcAddresses) {
+		t.Fatalf("wrong data")
+	}
+
+	legacyAddresses, err := store.ListWatchAddresses("", "legacy")
+	if err != nil {
+		t.Fatalf("unable to list watch addresses: %v", err)
+	}
+
+	if !reflect.DeepEqual(addresses[1:], legacyAddresses) {
+		t.Fatalf("wrong data")
+	}
+
+	err = store.RemoveWatchAddress(connectors.BTC, addresses[0].Address)
+	if err != nil {
+		t.Fatalf("unable to remove watch address: %v", err)
+	}
+
+	err = store.RemoveWatchAddress(connectors.BTC, addresses[0].Address)
+	if err != connectors.WatchAddressNotFound {
+		t.Fatalf("expected watch address not found error, got: %v", err)
+	}
+}
+
+func TestImportWatchAddresses(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewWatchStore(db)
+
+	addresses := []*connectors.WatchAddress{
+		{
+			Group:   "partner",
+			Asset:   connectors.ETH,
+			Address: "0x52908400098527886E0F7030069857D2E4169EE7",
+			Account: "1",
+		},
+		{
+			Group:    "partner",
+			Asset:    connectors.ETH,
+			Address:  "0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe",
+			Account:  "2",
+			Inactive: true,
+		},
+	}
+
+	added, updated, err := store.ImportWatchAddresses(addresses)
+	if err != nil {
+		t.Fatalf("unable to import watch addresses: %v", err)
+	}
+
+	if added != 2 || updated != 0 {
+		t.Fatalf("wrong counts, added(%v), updated(%v)", added, updated)
+	}
+
+	// Repeated import of the same list shouldn't change anything.
+	added, updated, err = store.ImportWatchAddresses(addresses)
+	if err != nil {
+		t.Fatalf("unable to import watch addresses: %v", err)
+	}
+
+	if added != 0 || updated != 0 {
+		t.Fatalf("wrong counts, added(%v), updated(%v)", added, updated)
+	}
+
+	activated := *addresses[1]
+	activated.Inactive = false
+	addresses[1] = &activated
+
+	added, updated, err = store.ImportWatchAddresses(addresses)
+	if err != nil {
+		t.Fatalf("unable to import watch addresses: %v", err)
+	}
+
+	if added != 0 || updated != 1 {
+		t.Fatalf("wrong counts, added(%v), updated(%v)", added, updated)
+	}
+
+	allAddresses, err := store.ListWatchAddresses(connectors.ETH, "partner")
+	if err != nil {
+		t.Fatalf("unable to list watch addresses: %v", err)
+	}
+
+	if !reflect.DeepEqual(addresses, allAddresses) {
+		t.Fatalf("wrong data")
+	}
+}
+
+func TestWatchEventsStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewWatchStore(db)
+
+	event := &connectors.WatchEvent{
+		CreatedAt: 1,
+		Group:     "attacker",
+		Asset:     connectors.BTC,
+		Address:   "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+		Account:   "account",
+		Direction: connectors.Incoming,
+		Amount:    decimal.New(15, -1),
+		TxID:      "txid",
+	}
+
+	created, err := connectors.ProcessWatchEvent(store, nil, event)
+	if err != nil {
+		t.Fatalf("unable to process event: %v", err)
+	}
+
+	if !created {
+		t.Fatalf("new event should be created")
+	}
+
+	exist, err := store.WatchEventExists(event.EventID)
+	if err != nil {
+		t.Fatalf("unable to check event existence: %v", err)
+	}
+
+	if !exist {
+		t.Fatalf("event should exist")
+	}
+
+	// Processing of the same activity shouldn't create another event.
+	duplicate := *event
+	duplicate.CreatedAt = 2
+	created, err = connectors.ProcessWatchEvent(store, nil, &duplicate)
+	if err != nil {
+		t.Fatalf("unable to process event: %v", err)
+	}
+
+	if created {
+		t.Fatalf("duplicate event shouldn't be created")
+	}
+
+	events, err := store.ListWatchEvents("attacker")
+	if err != nil {
+		t.Fatalf("unable to list events: %v", err)
+	}
+
+	if !reflect.DeepEqual([]*connectors.WatchEvent{event}, events) {
+		t.Fatalf("wrong data")
+	}
+
+	events, err = store.ListWatchEvents("legacy")
+	if err != nil {
+		t.Fatalf("unable to list events: %v", err)
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("wrong number of events")
+	}
+}
+
+func TestRedeliverWatchEvents(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewWatchStore(db)
+
+	newEvent := func(group string, createdAt int64) *connectors.WatchEvent {
+		event := &connectors.WatchEvent{
+			CreatedAt: createdAt,
+			Group:     group,
+			Asset:     connectors.BTC,
+			Address:   "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+			Direction: connectors.Incoming,
+			Amount:    decimal.New(15, -1),
+			TxID:      fmt.Sprintf("txid%v", createdAt),
+		}
+
+		if _, err := connectors.ProcessWatchEvent(store, nil, event); err != nil {
+			t.Fatalf("unable to process event: %v", err)
+		}
+		return event
+	}
+
+	before := newEvent("partner", 1)
+	inRange := newEvent("partner", 2)
+	otherGroup := newEvent("legacy", 3)
+	undelivered := newEvent("partner", 4)
+	after := newEvent("partner", 5)
+
+	for _, event := range []*connectors.WatchEvent{before, inRange,
+		otherGroup, after} {
+		if err := store.MarkWatchEventDelivered(event.EventID); err != nil {
+			t.Fatalf("unable to mark event delivered: %v", err)
+		}
+	}
+
+	// Undelivered event is going to be sent anyway, that is why it isn't
+	// counted.
+	n, err := store.RedeliverWatchEvents("partner", 2, 4)
+	if err != nil {
+		t.Fatalf("unable to redeliver events: %v", err)
+	}
+
+	if n != 1 {
+		t.Fatalf("wrong number of redelivered events: %v", n)
+	}
+
+	events, err := store.ListUndeliveredWatchEvents()
+	if err != nil {
+		t.Fatalf("unable to list events: %v", err)
+	}
+
+	if len(events) != 2 || events[0].EventID != inRange.EventID ||
+		!events[0].Redelivery || events[1].EventID != undelivered.EventID ||
+		events[1].Redelivery {
+		t.Fatalf("wrong undelivered events: %v", events)
+	}
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"github.com/bitlum/connector/connectors"
+)
+
+type WithdrawalPausesStore struct {
+	db *DB
+}
+
+func NewWithdrawalPausesStore(db *DB) *WithdrawalPausesStore {
+	return &WithdrawalPausesStore{
+		db: db,
+	}
+}
+
+type WithdrawalPause struct {
+	// Asset is an acronym of the crypto currency which outgoing payments
+	// are paused.
+	Asset string `gorm:"primary_key"`
+
+	// Reason is the description of the pause.
+	Reason string
+
+	// PausedAt is the time of the pause in milliseconds.
+	PausedAt int64
+}
+
+// Runtime check to ensure that WithdrawalPausesStore implements
+// connectors.WithdrawalPausesStore interface.
+var _ connectors.WithdrawalPausesStore = (*WithdrawalPausesStore)(nil)
+
+// SavePause adds pause to the store, or replaces the pause of the same
+// asset.
+//
+// NOTE: Part of the connectors.WithdrawalPausesStore interface.
+func (s *WithdrawalPausesStore) SavePause(
+	pause *connectors.WithdrawalPause) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Save(&WithdrawalPause{
+		Asset:    string(pause.Asset),
+		Reason:   pause.Reason,
+		PausedAt: pause.PausedAt,
+	}).Error
+}
+
+// DeletePause removes pause of the asset, if there is one.
+//
+// NOTE: Part of the connectors.WithdrawalPausesStore interface.
+func (s *WithdrawalPausesStore) DeletePause(asset connectors.Asset) error {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	return s.db.Where("asset = ?", string(asset)).
+		Delete(&WithdrawalPause{}).Error
+}
+
+// Pauses returns all pauses.
+//
+// NOTE: Part of the connectors.WithdrawalPausesStore interface.
+func (s *WithdrawalPausesStore) Pauses() ([]*connectors.WithdrawalPause,
+	error) {
+	s.db.globalMutex.Lock()
+	defer s.db.globalMutex.Unlock()
+
+	var dbPauses []*WithdrawalPause
+	if err := s.db.Order("asset").Find(&dbPauses).Error; err != nil {
+		return nil, err
+	}
+
+	pauses := make([]*connectors.WithdrawalPause, len(dbPauses))
+	for i, dbPause := range dbPauses {
+		pauses[i] = &connectors.WithdrawalPause{
+			Asset:    connectors.Asset(dbPause.Asset),
+			Reason:   dbPause.Reason,
+			PausedAt: dbPause.PausedAt,
+		}
+	}
+
+	return pauses, nil
+}
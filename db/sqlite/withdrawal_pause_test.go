@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bitlum/connector/connectors"
+)
+
+func TestWithdrawalPausesStorage(t *testing.T) {
+	db, clear, err := MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := NewWithdrawalPausesStore(db)
+
+	btc := &connectors.WithdrawalPause{
+		Asset:    connectors.BTC,
+		Reason:   "hot wallet drain",
+		PausedAt: 1,
+	}
+	ltc := &connectors.WithdrawalPause{
+		Asset:    connectors.LTC,
+		Reason:   "node upgrade",
+		PausedAt: 2,
+	}
+
+	for _, pause := range []*connectors.WithdrawalPause{btc, ltc} {
+		if err := store.SavePause(pause); err != nil {
+			t.Fatalf("unable to save pause: %v", err)
+		}
+	}
+
+	// Saving of the pause replaces the previous pause of the asset.
+	btc.Reason = "investigation"
+	if err := store.SavePause(btc); err != nil {
+		t.Fatalf("unable to save pause: %v", err)
+	}
+
+	if err := store.DeletePause(connectors.LTC); err != nil {
+		t.Fatalf("unable to delete pause: %v", err)
+	}
+
+	// Deletion of the missing pause isn't an error.
+	if err := store.DeletePause(connectors.DASH); err != nil {
+		t.Fatalf("unable to delete pause: %v", err)
+	}
+
+	pauses, err := store.Pauses()
+	if err != nil {
+		t.Fatalf("unable to list pauses: %v", err)
+	}
+
+	if !reflect.DeepEqual(pauses, []*connectors.WithdrawalPause{btc}) {
+		t.Fatalf("wrong data")
+	}
+}
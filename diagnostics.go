@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	rpc "github.com/bitlum/connector/crpc"
+)
+
+// redactedValue replaces the values of the secret config options.
+const redactedValue = "REDACTED"
+
+// secretOptions is the list of the names of config options, which values
+// shouldn't leave the host, e.g. in the support bundle.
+var secretOptions = []string{
+	"user",
+	"password",
+}
+
+// sanitizedConfig returns the list of config options with the secrets
+// replaced, so that config could be attached to the support ticket.
+func sanitizedConfig(cfg config) []*rpc.ConfigOption {
+	return configOptions("", reflect.ValueOf(cfg))
+}
+
+// configOptions walks through the fields of the config struct, and returns
+// options named in the same way as on the command line.
+func configOptions(namespace string, v reflect.Value) []*rpc.ConfigOption {
+	var options []*rpc.ConfigOption
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value := v.Field(i)
+
+		// Nested groups of options, e.g. config of the bitcoin daemon.
+		if group := field.Tag.Get("namespace"); group != "" {
+			if value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+
+			options = append(options, configOptions(namespace+group+".",
+				value)...)
+			continue
+		}
+
+		name := field.Tag.Get("long")
+		if name == "" {
+			continue
+		}
+
+		options = append(options, &rpc.ConfigOption{
+			Name:  namespace + name,
+			Value: sanitizeOption(name, fmt.Sprint(value.Interface())),
+		})
+	}
+
+	return options
+}
+
+// sanitizeOption replaces the value of the option if it is the secret, and
+// strips credentials from the URLs.
+func sanitizeOption(name, value string) string {
+	if value == "" {
+		return value
+	}
+
+	for _, secret := range secretOptions {
+		if strings.HasSuffix(name, secret) {
+			return redactedValue
+		}
+	}
+
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" &&
+		u.Host != "" {
+		if u.User != nil {
+			u.User = url.User(redactedValue)
+		}
+		if u.RawQuery != "" {
+			u.RawQuery = redactedValue
+		}
+		return u.String()
+	}
+
+	return value
+}
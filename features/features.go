@@ -0,0 +1,231 @@
+package features
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-errors/errors"
+)
+
+// Flag is the name of the feature flag which gates risky behaviour.
+type Flag string
+
+const (
+	// RBF marks outgoing bitcoin transactions as replaceable, so that
+	// stuck transaction could be later replaced with the one with higher
+	// fee.
+	RBF Flag = "rbf"
+
+	// ZeroConf accepts incoming bitcoin payments as completed as soon as
+	// they are seen in the mempool, without waiting for confirmations.
+	ZeroConf Flag = "zeroconf"
+
+	// Batching allows to send payments to the several recipients in one
+	// transaction.
+	Batching Flag = "batching"
+)
+
+// KnownFlags is the list of flags which are checked by the connectors and
+// the RPC server.
+var KnownFlags = []Flag{
+	RBF,
+	ZeroConf,
+	Batching,
+}
+
+// Rule defines for which subjects the flag is enabled. Subject is the
+// tenant, i.e. the id of the API key of the merchant on behalf of which
+// payment is sent or receipt has been created, empty if API keys are not
+// used.
+type Rule struct {
+	// Enabled enables flag for every subject.
+	Enabled bool
+
+	// Tenants is the list of subjects for which flag is enabled.
+	Tenants []string
+
+	// Percentage is the percentage of subjects for which flag is enabled.
+	// Subjects are chosen deterministically by the hash, so that flag
+	// doesn't flap for the same subject.
+	Percentage uint32
+}
+
+// Store is an external storage of the rules, which keeps rules changed on
+// the fly across restarts.
+type Store interface {
+	// SaveRule saves or replaces the rule of the flag.
+	SaveRule(flag Flag, rule Rule) error
+
+	// Rules returns all saved rules.
+	Rules() (map[Flag]Rule, error)
+}
+
+// Registry holds the rules of the feature flags, and could be updated on
+// the fly. Nil registry has all flags disabled.
+type Registry struct {
+	mtx   sync.RWMutex
+	rules map[Flag]Rule
+
+	// store keeps the rules which are set after the registry has been
+	// loaded from it, if nil rules are kept only in memory.
+	store Store
+}
+
+// NewRegistry creates registry with all flags disabled.
+func NewRegistry() *Registry {
+	return &Registry{
+		rules: make(map[Flag]Rule),
+	}
+}
+
+// isKnown returns true if flag is checked by the connectors.
+func isKnown(flag Flag) bool {
+	for _, known := range KnownFlags {
+		if known == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Load replaces rules with the ones saved in the store, and saves rules
+// which are set later in it. Rules which were set before, e.g. from the
+// config, are kept for the flags which haven't been saved.
+func (r *Registry) Load(store Store) error {
+	rules, err := store.Rules()
+	if err != nil {
+		return errors.Errorf("unable to load feature flags: %v", err)
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for flag, rule := range rules {
+		if !isKnown(flag) {
+			continue
+		}
+
+		r.rules[flag] = rule
+	}
+	r.store = store
+
+	return nil
+}
+
+// Set replaces the rule of the flag.
+func (r *Registry) Set(flag Flag, rule Rule) error {
+	if !isKnown(flag) {
+		return errors.Errorf("unknown feature flag(%v)", flag)
+	}
+
+	if rule.Percentage > 100 {
+		return errors.Errorf("percentage should be in range [0, 100]")
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.store != nil {
+		if err := r.store.SaveRule(flag, rule); err != nil {
+			return errors.Errorf("unable to save feature flag: %v", err)
+		}
+	}
+
+	r.rules[flag] = rule
+	return nil
+}
+
+// Rules returns rules of all known flags.
+func (r *Registry) Rules() map[Flag]Rule {
+	rules := make(map[Flag]Rule, len(KnownFlags))
+	for _, flag := range KnownFlags {
+		rules[flag] = Rule{}
+	}
+
+	if r == nil {
+		return rules
+	}
+
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	for flag, rule := range r.rules {
+		rules[flag] = rule
+	}
+
+	return rules
+}
+
+// IsEnabled returns true if flag is enabled for the given subject.
+func (r *Registry) IsEnabled(flag Flag, subject string) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mtx.RLock()
+	rule, ok := r.rules[flag]
+	r.mtx.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	if rule.Enabled {
+		return true
+	}
+
+	for _, tenant := range rule.Tenants {
+		if tenant == subject {
+			return true
+		}
+	}
+
+	return bucket(flag, subject) < rule.Percentage
+}
+
+// bucket returns number in range [0, 100) which is assigned to the subject.
+// Flag name is mixed in, so that the same subjects are not always the
+// first ones to receive new features.
+func bucket(flag Flag, subject string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(string(flag) + ":" + subject))
+	return h.Sum32() % 100
+}
+
+// ParseRule parses the flag rule from the config in form of "flag:value",
+// where value is either "on", "off", percentage like "25%", or comma
+// separated list of tenants.
+func ParseRule(s string) (Flag, Rule, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", Rule{}, errors.Errorf("feature flag should be in form "+
+			"of 'flag:value', got(%v)", s)
+	}
+
+	flag, value := Flag(parts[0]), parts[1]
+
+	var rule Rule
+	switch {
+	case value == "on":
+		rule.Enabled = true
+
+	case value == "off":
+
+	case strings.HasSuffix(value, "%"):
+		percentage, err := strconv.ParseUint(strings.TrimSuffix(value, "%"),
+			10, 32)
+		if err != nil {
+			return "", Rule{}, errors.Errorf("invalid percentage of the "+
+				"feature flag(%v): %v", flag, err)
+		}
+		rule.Percentage = uint32(percentage)
+
+	default:
+		rule.Tenants = strings.Split(value, ",")
+	}
+
+	return flag, rule, nil
+}
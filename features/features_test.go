@@ -0,0 +1,133 @@
+package features
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		value   string
+		rule    Rule
+		wantErr bool
+	}{
+		{value: "rbf:on", rule: Rule{Enabled: true}},
+		{value: "rbf:off", rule: Rule{}},
+		{value: "rbf:25%", rule: Rule{Percentage: 25}},
+		{value: "rbf:a,b", rule: Rule{Tenants: []string{"a", "b"}}},
+		{value: "rbf:x%", wantErr: true},
+		{value: "rbf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		flag, rule, err := ParseRule(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%v: wrong error, got(%v), want error(%v)", tt.value,
+				err, tt.wantErr)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if flag != RBF || !reflect.DeepEqual(rule, tt.rule) {
+			t.Fatalf("%v: wrong rule, got(%v:%v), want(%v:%v)", tt.value,
+				flag, rule, RBF, tt.rule)
+		}
+	}
+}
+
+func TestRegistryIsEnabled(t *testing.T) {
+	var nilRegistry *Registry
+	if nilRegistry.IsEnabled(RBF, "a") {
+		t.Fatal("flag of nil registry should be disabled")
+	}
+
+	r := NewRegistry()
+	if r.IsEnabled(RBF, "a") {
+		t.Fatal("flag should be disabled by default")
+	}
+
+	if err := r.Set("unknown", Rule{Enabled: true}); err == nil {
+		t.Fatal("unknown flag shouldn't be accepted")
+	}
+
+	if err := r.Set(RBF, Rule{Percentage: 101}); err == nil {
+		t.Fatal("percentage above 100 shouldn't be accepted")
+	}
+
+	if err := r.Set(RBF, Rule{Tenants: []string{"a"}}); err != nil {
+		t.Fatalf("unable to set rule: %v", err)
+	}
+
+	if !r.IsEnabled(RBF, "a") || r.IsEnabled(RBF, "b") {
+		t.Fatal("flag should be enabled only for the tenant")
+	}
+
+	if err := r.Set(RBF, Rule{Percentage: 50}); err != nil {
+		t.Fatalf("unable to set rule: %v", err)
+	}
+
+	var enabled int
+	for i := 0; i < 1000; i++ {
+		subject := fmt.Sprintf("subject%v", i)
+		if r.IsEnabled(RBF, subject) != r.IsEnabled(RBF, subject) {
+			t.Fatal("flag state should be stable for the subject")
+		}
+
+		if r.IsEnabled(RBF, subject) {
+			enabled++
+		}
+	}
+
+	if enabled < 400 || enabled > 600 {
+		t.Fatalf("flag should be enabled for about half of subjects, "+
+			"got(%v)", enabled)
+	}
+}
+
+// memoryStore is the in-memory implementation of the rules store.
+type memoryStore map[Flag]Rule
+
+func (s memoryStore) SaveRule(flag Flag, rule Rule) error {
+	s[flag] = rule
+	return nil
+}
+
+func (s memoryStore) Rules() (map[Flag]Rule, error) {
+	return s, nil
+}
+
+func TestRegistryLoad(t *testing.T) {
+	store := memoryStore{RBF: Rule{Tenants: []string{"a"}}}
+
+	// Rules from the config are overridden by the saved ones.
+	r := NewRegistry()
+	if err := r.Set(RBF, Rule{Enabled: true}); err != nil {
+		t.Fatalf("unable to set rule: %v", err)
+	}
+	if err := r.Set(ZeroConf, Rule{Enabled: true}); err != nil {
+		t.Fatalf("unable to set rule: %v", err)
+	}
+
+	if err := r.Load(store); err != nil {
+		t.Fatalf("unable to load rules: %v", err)
+	}
+
+	if r.IsEnabled(RBF, "b") || !r.IsEnabled(RBF, "a") {
+		t.Fatal("saved rule should override the config one")
+	}
+
+	if !r.IsEnabled(ZeroConf, "b") {
+		t.Fatal("config rule should be kept if it isn't saved")
+	}
+
+	if err := r.Set(Batching, Rule{Percentage: 10}); err != nil {
+		t.Fatalf("unable to set rule: %v", err)
+	}
+
+	if !reflect.DeepEqual(store[Batching], Rule{Percentage: 10}) {
+		t.Fatal("rule should be saved after load")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	rpc "github.com/bitlum/connector/crpc"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// dialFederationPeer connects to the Federation service of the peer.
+// Balances are moved over the connection, that is why it is always
+// encrypted, peer is verified by the system certificates if its own
+// certificate isn't given.
+func dialFederationPeer(peer *rpc.FederationPeerConfig,
+	dial common.DialFunc) (*grpc.ClientConn, error) {
+
+	creds := credentials.NewTLS(&tls.Config{})
+	if peer.TLSCertPath != "" {
+		var err error
+		creds, err = credentials.NewClientTLSFromFile(peer.TLSCertPath, "")
+		if err != nil {
+			return nil, errors.Errorf("unable to load certificate of "+
+				"federation peer(%v): %v", peer.Name, err)
+		}
+	}
+
+	conn, err := grpc.Dial(peer.Address,
+		grpc.WithDialer(func(address string,
+			timeout time.Duration) (net.Conn, error) {
+			return dial("tcp", address)
+		}),
+		grpc.WithTransportCredentials(creds),
+	)
+	if err != nil {
+		return nil, errors.Errorf("unable to connect to federation "+
+			"peer(%v): %v", peer.Name, err)
+	}
+
+	return conn, nil
+}
+
+// newFederationConfig connects to the federation peers from the config,
+// nil config is returned if there are no peers. Returned connections
+// should be closed by the caller.
+func newFederationConfig(cfg config, store connectors.FederationStore,
+	dial common.DialFunc) (*rpc.FederationConfig, []*grpc.ClientConn,
+	error) {
+
+	if len(cfg.FederationPeers) == 0 {
+		return nil, nil, nil
+	}
+
+	federationConfig := &rpc.FederationConfig{
+		Store:         store,
+		Timeout:       cfg.FederationTimeout,
+		MinSettlement: decimal.Zero,
+	}
+
+	if cfg.FederationMinSettlement != "" {
+		min, err := decimal.NewFromString(cfg.FederationMinSettlement)
+		if err != nil || min.Sign() < 0 {
+			return nil, nil, errors.Errorf("invalid minimum federation "+
+				"settlement(%v)", cfg.FederationMinSettlement)
+		}
+		federationConfig.MinSettlement = min
+	}
+
+	var conns []*grpc.ClientConn
+	closeConns := func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+
+	names := make(map[string]struct{})
+	for _, value := range cfg.FederationPeers {
+		peer, err := rpc.ParseFederationPeer(value)
+		if err != nil {
+			closeConns()
+			return nil, nil, err
+		}
+
+		if _, ok := names[peer.Name]; ok {
+			closeConns()
+			return nil, nil, errors.Errorf("federation peer(%v) is "+
+				"specified twice", peer.Name)
+		}
+		names[peer.Name] = struct{}{}
+
+		conn, err := dialFederationPeer(peer, dial)
+		if err != nil {
+			closeConns()
+			return nil, nil, err
+		}
+		conns = append(conns, conn)
+
+		federationConfig.Peers = append(federationConfig.Peers,
+			rpc.NewFederationPeer(peer.Name, peer.PublicKey, conn))
+	}
+
+	return federationConfig, conns, nil
+}
@@ -0,0 +1,224 @@
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+const (
+	// SignatureHeader is the HTTP header with the base64 encoded signature
+	// of the body.
+	SignatureHeader = "X-Signature"
+
+	// KeyIDHeader is the HTTP header with the id of the key by which body
+	// is signed.
+	KeyIDHeader = "X-Signature-Key-Id"
+
+	// TimestampHeader is the HTTP header with the unix time in seconds at
+	// which the timestamped payload was signed.
+	TimestampHeader = "X-Signature-Timestamp"
+
+	// Algorithm is the signature algorithm, body is hashed with SHA-256 and
+	// signed by the ECDSA P-256 key, signature is encoded in ASN.1 DER.
+	Algorithm = "ecdsa-p256-sha256"
+
+	// keyIDSize is the number of bytes of the public key hash which are
+	// used as the key id.
+	keyIDSize = 8
+
+	// pemType is the type of the PEM block in which private key is stored.
+	pemType = "EC PRIVATE KEY"
+)
+
+// MaxSignatureAge is the freshness window of the timestamped signatures.
+// Consumers should reject the payloads whose timestamp differs from their
+// own clock by more than this value, and deduplicate the accepted ones
+// within the window, so that the captured request can't be replayed.
+const MaxSignatureAge = 5 * time.Minute
+
+// ecdsaSignature is the ASN.1 form of the ECDSA signature.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// Key is the identity key of the server, which is used to sign the payloads,
+// so that consumers could authenticate them, even if they are passing
+// through the third-party infrastructure.
+type Key struct {
+	priv      *ecdsa.PrivateKey
+	publicKey []byte
+	id        string
+}
+
+// LoadKey reads the identity key from the file. If file doesn't exist new
+// key is generated and written in it.
+func LoadKey(path string) (*Key, error) {
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, errors.Errorf("unable to generate identity key: %v",
+				err)
+		}
+
+		der, err := x509.MarshalECPrivateKey(priv)
+		if err != nil {
+			return nil, errors.Errorf("unable to encode identity key: %v", err)
+		}
+
+		data = pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: der})
+		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+			return nil, errors.Errorf("unable to write identity key: %v", err)
+		}
+
+		return newKey(priv)
+
+	case err != nil:
+		return nil, errors.Errorf("unable to read identity key: %v", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemType {
+		return nil, errors.Errorf("identity key file(%v) is corrupted", path)
+	}
+
+	priv, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Errorf("unable to parse identity key: %v", err)
+	}
+
+	return newKey(priv)
+}
+
+// newKey creates key and derives its id from the public key.
+func newKey(priv *ecdsa.PrivateKey) (*Key, error) {
+	if priv.Curve != elliptic.P256() {
+		return nil, errors.Errorf("identity key should be on P-256 curve")
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		return nil, errors.Errorf("unable to encode public key: %v", err)
+	}
+
+	return &Key{
+		priv:      priv,
+		publicKey: publicKey,
+		id:        KeyID(publicKey),
+	}, nil
+}
+
+// KeyID returns the id of the DER encoded public key, which is the hex
+// encoded prefix of its hash.
+func KeyID(publicKey []byte) string {
+	hash := sha256.Sum256(publicKey)
+	return hex.EncodeToString(hash[:keyIDSize])
+}
+
+// ID returns the id of the key, which is sent along with the signature.
+func (k *Key) ID() string {
+	return k.id
+}
+
+// PublicKey returns the public key in PKIX, ASN.1 DER form.
+func (k *Key) PublicKey() []byte {
+	return k.publicKey
+}
+
+// Sign returns the base64 encoded signature of the payload.
+func (k *Key) Sign(payload []byte) (string, error) {
+	hash := sha256.Sum256(payload)
+	r, s, err := ecdsa.Sign(rand.Reader, k.priv, hash[:])
+	if err != nil {
+		return "", errors.Errorf("unable to sign payload: %v", err)
+	}
+
+	sig, err := asn1.Marshal(ecdsaSignature{R: r, S: s})
+	if err != nil {
+		return "", errors.Errorf("unable to encode signature: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// Verify checks the base64 encoded signature of the payload with the DER
+// encoded public key.
+func Verify(publicKey, payload []byte, signature string) error {
+	parsed, err := x509.ParsePKIXPublicKey(publicKey)
+	if err != nil {
+		return errors.Errorf("unable to parse public key: %v", err)
+	}
+
+	pub, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return errors.Errorf("public key isn't ECDSA key")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.Errorf("signature should be base64 encoded: %v", err)
+	}
+
+	var sig ecdsaSignature
+	rest, err := asn1.Unmarshal(data, &sig)
+	if err != nil || len(rest) != 0 {
+		return errors.Errorf("signature should be ASN.1 DER encoded")
+	}
+
+	hash := sha256.Sum256(payload)
+	if !ecdsa.Verify(pub, hash[:], sig.R, sig.S) {
+		return errors.Errorf("signature is invalid")
+	}
+
+	return nil
+}
+
+// TimestampedPayload returns the data which is signed for the timestamped
+// payload, it is the decimal unix timestamp, the dot, and the payload.
+func TimestampedPayload(timestamp int64, payload []byte) []byte {
+	data := strconv.AppendInt(nil, timestamp, 10)
+	data = append(data, '.')
+	return append(data, payload...)
+}
+
+// SignTimestamped returns the base64 encoded signature of the payload
+// along with the given unix timestamp.
+func (k *Key) SignTimestamped(timestamp int64, payload []byte) (string, error) {
+	return k.Sign(TimestampedPayload(timestamp, payload))
+}
+
+// VerifyTimestamped checks the signature of the payload along with the
+// timestamp from the TimestampHeader, and that the timestamp is within the
+// MaxSignatureAge of the given time.
+func VerifyTimestamped(publicKey, payload []byte, timestamp,
+	signature string, now time.Time) error {
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Errorf("timestamp should be unix time in "+
+			"seconds: %v", err)
+	}
+
+	age := now.Sub(time.Unix(ts, 0))
+	if age > MaxSignatureAge || age < -MaxSignatureAge {
+		return errors.Errorf("signature timestamp is out of the "+
+			"freshness window(%v)", MaxSignatureAge)
+	}
+
+	return Verify(publicKey, TimestampedPayload(ts, payload), signature)
+}
@@ -0,0 +1,117 @@
+package identity
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestKeySign(t *testing.T) {
+	dir, err := ioutil.TempDir("", "identity")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "identity.key")
+	key, err := LoadKey(path)
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	payload := []byte(`{"payment_id":"1"}`)
+	sig, err := key.Sign(payload)
+	if err != nil {
+		t.Fatalf("unable to sign payload: %v", err)
+	}
+
+	if err := Verify(key.PublicKey(), payload, sig); err != nil {
+		t.Fatalf("signature should be valid: %v", err)
+	}
+
+	if err := Verify(key.PublicKey(), []byte(`{}`), sig); err == nil {
+		t.Fatal("signature of the other payload should be invalid")
+	}
+
+	// Key should be the same after the restart, so that consumers don't
+	// need to fetch it again.
+	loaded, err := LoadKey(path)
+	if err != nil {
+		t.Fatalf("unable to load key: %v", err)
+	}
+
+	if loaded.ID() != key.ID() || loaded.ID() != KeyID(key.PublicKey()) {
+		t.Fatalf("wrong key id, got(%v), want(%v)", loaded.ID(), key.ID())
+	}
+
+	if err := Verify(loaded.PublicKey(), payload, sig); err != nil {
+		t.Fatalf("signature should be valid with loaded key: %v", err)
+	}
+}
+
+func TestKeySignTimestamped(t *testing.T) {
+	dir, err := ioutil.TempDir("", "identity")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := LoadKey(filepath.Join(dir, "identity.key"))
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	now := time.Unix(1500000000, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	payload := []byte(`{"event_id":"1"}`)
+
+	sig, err := key.SignTimestamped(now.Unix(), payload)
+	if err != nil {
+		t.Fatalf("unable to sign payload: %v", err)
+	}
+
+	err = VerifyTimestamped(key.PublicKey(), payload, timestamp, sig,
+		now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("signature should be valid: %v", err)
+	}
+
+	// Signature without timestamp shouldn't be accepted as timestamped.
+	if err := Verify(key.PublicKey(), payload, sig); err == nil {
+		t.Fatal("signature should cover the timestamp")
+	}
+
+	// Timestamp can't be replaced without the signature.
+	err = VerifyTimestamped(key.PublicKey(), payload,
+		strconv.FormatInt(now.Unix()+60, 10), sig, now)
+	if err == nil {
+		t.Fatal("signature with other timestamp should be invalid")
+	}
+
+	// Replay after the freshness window should be rejected.
+	err = VerifyTimestamped(key.PublicKey(), payload, timestamp, sig,
+		now.Add(MaxSignatureAge+time.Second))
+	if err == nil {
+		t.Fatal("stale signature should be rejected")
+	}
+}
+
+func TestLoadKeyCorrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "identity")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "identity.key")
+	if err := ioutil.WriteFile(path, []byte("garbage"), 0600); err != nil {
+		t.Fatalf("unable to write key: %v", err)
+	}
+
+	if _, err := LoadKey(path); err == nil {
+		t.Fatal("corrupted key shouldn't be loaded")
+	}
+}
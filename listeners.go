@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// unixPrefix is the prefix of the listen address which denotes the UNIX
+// domain socket, e.g. "unix:///var/run/connector.sock".
+const unixPrefix = "unix:"
+
+// rpcListener is the address on which gRPC server accepts connections.
+type rpcListener struct {
+	// network is either "tcp" or "unix".
+	network string
+
+	// address is host:port for tcp, and path to the socket file for unix.
+	address string
+
+	// admin denotes that listener is used by the operator, and Admin
+	// service is served through it.
+	admin bool
+}
+
+// parseRPCListener converts listen address from the config into listener.
+func parseRPCListener(address string, admin bool) rpcListener {
+	if strings.HasPrefix(address, unixPrefix) {
+		path := strings.TrimPrefix(address, unixPrefix)
+		path = strings.TrimPrefix(path, "//")
+
+		return rpcListener{
+			network: "unix",
+			address: cleanAndExpandPath(path),
+			admin:   admin,
+		}
+	}
+
+	return rpcListener{
+		network: "tcp",
+		address: address,
+		admin:   admin,
+	}
+}
+
+// isUnix returns true if listener is the UNIX domain socket.
+func (l rpcListener) isUnix() bool {
+	return l.network == "unix"
+}
+
+// listen starts listening on the address. Socket file of the UNIX listener
+// is created with the given permissions, so that access could be limited by
+// the file system.
+func (l rpcListener) listen(socketMode os.FileMode) (net.Listener, error) {
+	if !l.isUnix() {
+		return net.Listen(l.network, l.address)
+	}
+
+	// Remove socket file which might left after unclean shutdown,
+	// otherwise listen fails with "address already in use".
+	if err := os.Remove(l.address); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Errorf("unable to remove stale socket: %v", err)
+	}
+
+	lis, err := net.Listen(l.network, l.address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(l.address, socketMode); err != nil {
+		lis.Close()
+		return nil, errors.Errorf("unable to set socket permissions: %v",
+			err)
+	}
+
+	return lis, nil
+}
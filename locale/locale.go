@@ -0,0 +1,258 @@
+package locale
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/go-errors/errors"
+)
+
+// Locale is the language tag of the user-facing strings, e.g. "es" or
+// "pt-BR".
+type Locale string
+
+const (
+	// English is the locale in which messages are returned if translation
+	// isn't available.
+	English Locale = "en"
+
+	// Spanish is the built-in spanish locale.
+	Spanish Locale = "es"
+
+	// Portuguese is the built-in portuguese locale.
+	Portuguese Locale = "pt"
+)
+
+// Message is the identifier of the string which is shown to the payers.
+// Strings might contain placeholders in form of {name}, which are replaced
+// with the arguments on translation.
+type Message string
+
+const (
+	// FeeTooHigh is the warning about the payment fee, placeholder:
+	// {percent}.
+	FeeTooHigh Message = "fee_too_high"
+
+	// AddressReused is the warning about the reused destination address,
+	// placeholder: {count}.
+	AddressReused Message = "address_reused"
+
+	// InboundLiquidityLow is the warning about the lightning invoice which
+	// might not be paid, placeholder: {capacity}.
+	InboundLiquidityLow Message = "inbound_liquidity_low"
+
+	// InvoiceDescription is the template of the lightning invoice
+	// description, which is used if description isn't specified,
+	// placeholders: {amount}, {asset}. Empty by default, i.e. invoices
+	// are created without description.
+	InvoiceDescription Message = "invoice_description"
+)
+
+// builtinMessages are the translations which are shipped with the server,
+// they could be overridden or extended by the messages file.
+var builtinMessages = map[Locale]map[Message]string{
+	English: {
+		FeeTooHigh:          "fee exceeds {percent}% of amount",
+		AddressReused:       "destination address reused {count} times",
+		InboundLiquidityLow: "inbound liquidity low, able to receive only {capacity}",
+	},
+	Spanish: {
+		FeeTooHigh:          "la comisión supera el {percent}% del monto",
+		AddressReused:       "la dirección de destino se reutilizó {count} veces",
+		InboundLiquidityLow: "liquidez entrante baja, solo es posible recibir {capacity}",
+	},
+	Portuguese: {
+		FeeTooHigh:          "a taxa excede {percent}% do valor",
+		AddressReused:       "o endereço de destino foi reutilizado {count} vezes",
+		InboundLiquidityLow: "liquidez de entrada baixa, é possível receber apenas {capacity}",
+	},
+}
+
+// Args are the values of the message placeholders.
+type Args map[string]string
+
+// Catalog holds the translations of the messages and the locales of the
+// tenants, i.e. the ids of the API keys of the merchants. Nil catalog
+// returns built-in english messages.
+type Catalog struct {
+	mtx           sync.RWMutex
+	messages      map[Locale]map[Message]string
+	defaultLocale Locale
+	tenants       map[string]Locale
+}
+
+// NewCatalog creates catalog with the built-in translations, and english
+// as the default locale.
+func NewCatalog() *Catalog {
+	messages := make(map[Locale]map[Message]string)
+	for locale, translations := range builtinMessages {
+		messages[locale] = make(map[Message]string)
+		for msg, text := range translations {
+			messages[locale][msg] = text
+		}
+	}
+
+	return &Catalog{
+		messages:      messages,
+		defaultLocale: English,
+		tenants:       make(map[string]Locale),
+	}
+}
+
+// normalize returns locale in the lower case, with underscore replaced by
+// the dash, e.g. "pt_BR" becomes "pt-br".
+func normalize(locale Locale) Locale {
+	return Locale(strings.Replace(strings.ToLower(
+		strings.TrimSpace(string(locale))), "_", "-", -1))
+}
+
+// base returns the language of the locale without the region, e.g. "pt" for
+// "pt-br".
+func base(locale Locale) Locale {
+	return Locale(strings.SplitN(string(locale), "-", 2)[0])
+}
+
+// LoadFile reads translations from the JSON file in form of
+// {"locale": {"message": "text"}}, they are replacing the built-in ones, so
+// that operators could adjust the wording or add new locales.
+func (c *Catalog) LoadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Errorf("unable to read messages file: %v", err)
+	}
+
+	var messages map[Locale]map[Message]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return errors.Errorf("unable to decode messages file: %v", err)
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for locale, translations := range messages {
+		locale = normalize(locale)
+		if locale == "" {
+			return errors.Errorf("empty locale in messages file")
+		}
+
+		if c.messages[locale] == nil {
+			c.messages[locale] = make(map[Message]string)
+		}
+
+		for msg, text := range translations {
+			c.messages[locale][msg] = text
+		}
+	}
+
+	return nil
+}
+
+// isKnown returns true if there are translations of the locale or of its
+// language.
+func (c *Catalog) isKnown(locale Locale) bool {
+	_, ok := c.messages[locale]
+	if !ok {
+		_, ok = c.messages[base(locale)]
+	}
+
+	return ok
+}
+
+// SetDefault sets the locale which is used for the tenants without the
+// locale.
+func (c *Catalog) SetDefault(locale Locale) error {
+	locale = normalize(locale)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if !c.isKnown(locale) {
+		return errors.Errorf("unknown locale(%v)", locale)
+	}
+
+	c.defaultLocale = locale
+	return nil
+}
+
+// SetTenantLocale sets the locale of the messages returned to the tenant.
+func (c *Catalog) SetTenantLocale(tenant string, locale Locale) error {
+	locale = normalize(locale)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if !c.isKnown(locale) {
+		return errors.Errorf("unknown locale(%v)", locale)
+	}
+
+	c.tenants[tenant] = locale
+	return nil
+}
+
+// TenantLocale returns the locale of the tenant, or the default one if it
+// isn't set.
+func (c *Catalog) TenantLocale(tenant string) Locale {
+	if c == nil {
+		return English
+	}
+
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if locale, ok := c.tenants[tenant]; ok {
+		return locale
+	}
+
+	return c.defaultLocale
+}
+
+// lookup returns text of the message in the given locale, falling back to
+// the language of the locale, the default locale and english.
+func (c *Catalog) lookup(locale Locale, msg Message) string {
+	if c == nil {
+		return builtinMessages[English][msg]
+	}
+
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	for _, l := range []Locale{locale, base(locale), c.defaultLocale,
+		English} {
+		if text, ok := c.messages[l][msg]; ok {
+			return text
+		}
+	}
+
+	return ""
+}
+
+// Translate returns the message in the given locale with the placeholders
+// replaced by the arguments.
+func (c *Catalog) Translate(locale Locale, msg Message, args Args) string {
+	text := c.lookup(normalize(locale), msg)
+
+	for name, value := range args {
+		text = strings.Replace(text, "{"+name+"}", value, -1)
+	}
+
+	return text
+}
+
+// ForTenant returns the message in the locale of the tenant.
+func (c *Catalog) ForTenant(tenant string, msg Message, args Args) string {
+	return c.Translate(c.TenantLocale(tenant), msg, args)
+}
+
+// ParseTenantLocale parses the locale of the tenant from the config in form
+// of "tenant:locale".
+func ParseTenantLocale(s string) (string, Locale, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("tenant locale should be in form "+
+			"of 'tenant:locale', got(%v)", s)
+	}
+
+	return parts[0], Locale(parts[1]), nil
+}
@@ -0,0 +1,108 @@
+package locale
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTranslate(t *testing.T) {
+	catalog := NewCatalog()
+
+	if err := catalog.SetTenantLocale("merchant", "pt_BR"); err != nil {
+		t.Fatalf("unable to set tenant locale: %v", err)
+	}
+
+	if err := catalog.SetTenantLocale("merchant", "xx"); err == nil {
+		t.Fatalf("unknown locale should be rejected")
+	}
+
+	args := Args{"percent": "5"}
+
+	tests := []struct {
+		name   string
+		tenant string
+		text   string
+	}{
+		{
+			name:   "default locale",
+			tenant: "other",
+			text:   "fee exceeds 5% of amount",
+		},
+		{
+			name:   "region falls back to language",
+			tenant: "merchant",
+			text:   "a taxa excede 5% do valor",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text := catalog.ForTenant(test.tenant, FeeTooHigh, args)
+			if text != test.text {
+				t.Fatalf("wrong text, expected(%v), got(%v)", test.text,
+					text)
+			}
+		})
+	}
+
+	if err := catalog.SetDefault(Spanish); err != nil {
+		t.Fatalf("unable to set default locale: %v", err)
+	}
+
+	text := catalog.ForTenant("other", AddressReused, Args{"count": "11"})
+	if text != "la dirección de destino se reutilizó 11 veces" {
+		t.Fatalf("wrong text: %v", text)
+	}
+
+	// Nil catalog returns english messages.
+	var empty *Catalog
+	if text := empty.ForTenant("", FeeTooHigh, args); text !=
+		"fee exceeds 5% of amount" {
+		t.Fatalf("wrong text: %v", text)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "locale")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "messages.json")
+	err = ioutil.WriteFile(path, []byte(`{
+		"es-MX": {"invoice_description": "Pago de {amount} {asset}"},
+		"fr": {"fee_too_high": "les frais dépassent {percent}% du montant"}
+	}`), 0600)
+	if err != nil {
+		t.Fatalf("unable to write messages file: %v", err)
+	}
+
+	catalog := NewCatalog()
+	if err := catalog.LoadFile(path); err != nil {
+		t.Fatalf("unable to load messages file: %v", err)
+	}
+
+	text := catalog.Translate("es-MX", InvoiceDescription,
+		Args{"amount": "0.1", "asset": "BTC"})
+	if text != "Pago de 0.1 BTC" {
+		t.Fatalf("wrong text: %v", text)
+	}
+
+	// Messages which aren't overridden are taken from the language.
+	text = catalog.Translate("es-MX", FeeTooHigh, Args{"percent": "5"})
+	if text != "la comisión supera el 5% del monto" {
+		t.Fatalf("wrong text: %v", text)
+	}
+
+	if err := catalog.SetTenantLocale("merchant", "fr"); err != nil {
+		t.Fatalf("locale of the file should be known: %v", err)
+	}
+
+	// Invoice description isn't set by default.
+	if text := catalog.Translate(English, InvoiceDescription, nil); text != "" {
+		t.Fatalf("invoice description should be empty, got: %v", text)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/bitlum/connector/connectors/rpc"
 	"github.com/bitlum/connector/crpc"
 	"github.com/bitlum/connector/metrics"
+	"github.com/bitlum/connector/policy"
+	"github.com/bitlum/connector/webhook"
 	"github.com/btcsuite/btclog"
 	"github.com/jrick/logrotate/rotator"
 )
@@ -56,6 +58,8 @@ var (
 	crpcLog    = backendLog.Logger("CONNECTOR_RPC")
 	rpcLog     = backendLog.Logger("BLOCKCHAIN_RPC")
 	lndLog     = backendLog.Logger("LND")
+	webhookLog = backendLog.Logger("WEBHOOK")
+	policyLog  = backendLog.Logger("POLICY")
 )
 
 // Initialize package-global logger variables.
@@ -65,6 +69,8 @@ func init() {
 	rpc.UseLogger(rpcLog)
 	lnd.UseLogger(lndLog)
 	sqlite.UseLogger(sqliteLog)
+	webhook.UseLogger(webhookLog)
+	policy.UseLogger(policyLog)
 }
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -75,6 +81,8 @@ var subsystemLoggers = map[string]btclog.Logger{
 	"BLOCKCHAIN_RPC": rpcLog,
 	"SQLITE":         sqliteLog,
 	"CONNECTOR_RPC":  crpcLog,
+	"WEBHOOK":        webhookLog,
+	"POLICY":         policyLog,
 }
 
 // initLogRotator initializes the logging rotator to write logs to logFile and
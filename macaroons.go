@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/bitlum/connector/macaroons"
+	"github.com/go-errors/errors"
+)
+
+const (
+	// macaroonRootKeyFilename is the name of the file with the root key of
+	// the macaroons.
+	macaroonRootKeyFilename = "macaroons.key"
+
+	// payMacaroonFilename is the name of the macaroon which allows to call
+	// all PayServer methods.
+	payMacaroonFilename = "pay.macaroon"
+
+	// receiveMacaroonFilename is the name of the macaroon which allows to
+	// create receipts, but not to send payments.
+	receiveMacaroonFilename = "receive.macaroon"
+
+	// readonlyMacaroonFilename is the name of the macaroon which allows to
+	// call only methods which return the state.
+	readonlyMacaroonFilename = "readonly.macaroon"
+)
+
+// defaultMacaroons is the permissions of the macaroons which are generated
+// on the first start, by the file name.
+var defaultMacaroons = map[string][]macaroons.Permission{
+	payMacaroonFilename:      macaroons.AllPermissions,
+	receiveMacaroonFilename:  {macaroons.Read, macaroons.Receive},
+	readonlyMacaroonFilename: {macaroons.Read},
+}
+
+// loadMacaroonService creates macaroon service with the root key stored in
+// the given directory, and generates default macaroons if they don't exist,
+// so that operator could pass them to the clients.
+func loadMacaroonService(dir string) (*macaroons.Service, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Errorf("unable to create macaroon dir: %v", err)
+	}
+
+	service, err := macaroons.NewService(filepath.Join(dir,
+		macaroonRootKeyFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	for filename, permissions := range defaultMacaroons {
+		path := filepath.Join(dir, filename)
+		if fileExists(path) {
+			continue
+		}
+
+		mac, err := service.Mint(permissions...)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := mac.MarshalBinary()
+		if err != nil {
+			return nil, errors.Errorf("unable to encode macaroon: %v", err)
+		}
+
+		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+			return nil, errors.Errorf("unable to write macaroon: %v", err)
+		}
+	}
+
+	return service, nil
+}
@@ -0,0 +1,132 @@
+package macaroons
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"gopkg.in/macaroon.v2"
+)
+
+// Permission is the group of RPC methods which macaroon allows to call.
+type Permission string
+
+const (
+	// Read allows to call methods which only return the state, e.g.
+	// balance or list of payments.
+	Read Permission = "read"
+
+	// Receive allows to create receipts on which funds are received.
+	Receive Permission = "receive"
+
+	// Send allows to send funds.
+	Send Permission = "send"
+)
+
+// AllPermissions is the list of all known permissions.
+var AllPermissions = []Permission{
+	Read,
+	Receive,
+	Send,
+}
+
+const (
+	// location is the location of the macaroons, it is only informational.
+	location = "connector"
+
+	// rootKeySize is the size in bytes of the generated root key.
+	rootKeySize = 32
+
+	// permissionsCaveat is the prefix of the first party caveat which
+	// restricts the permissions of the macaroon.
+	permissionsCaveat = "permissions="
+)
+
+// Service mints macaroons and verifies them with the root key.
+type Service struct {
+	rootKey []byte
+}
+
+// NewService creates the service with the root key read from the file. If
+// file doesn't exist new random root key is generated and written in it.
+func NewService(rootKeyPath string) (*Service, error) {
+	rootKey, err := ioutil.ReadFile(rootKeyPath)
+	switch {
+	case os.IsNotExist(err):
+		rootKey = make([]byte, rootKeySize)
+		if _, err := rand.Read(rootKey); err != nil {
+			return nil, errors.Errorf("unable to generate root key: %v",
+				err)
+		}
+
+		if err := ioutil.WriteFile(rootKeyPath, rootKey, 0600); err != nil {
+			return nil, errors.Errorf("unable to write root key: %v", err)
+		}
+
+	case err != nil:
+		return nil, errors.Errorf("unable to read root key: %v", err)
+
+	case len(rootKey) != rootKeySize:
+		return nil, errors.Errorf("root key file(%v) is corrupted, "+
+			"wrong size(%v)", rootKeyPath, len(rootKey))
+	}
+
+	return &Service{
+		rootKey: rootKey,
+	}, nil
+}
+
+// Mint creates new macaroon which allows to call methods with the given
+// permissions.
+func (s *Service) Mint(permissions ...Permission) (*macaroon.Macaroon, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errors.Errorf("unable to generate macaroon id: %v", err)
+	}
+
+	mac, err := macaroon.New(s.rootKey, id, location, macaroon.LatestVersion)
+	if err != nil {
+		return nil, errors.Errorf("unable to create macaroon: %v", err)
+	}
+
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+
+	caveat := permissionsCaveat + strings.Join(names, ",")
+	if err := mac.AddFirstPartyCaveat([]byte(caveat)); err != nil {
+		return nil, errors.Errorf("unable to add caveat: %v", err)
+	}
+
+	return mac, nil
+}
+
+// Verify ensures that macaroon is minted with our root key, and that
+// every permissions caveat allows the given permission. Caveats could only
+// be added to the macaroon, that is why macaroon could be restricted by its
+// holder, but not extended.
+func (s *Service) Verify(data []byte, permission Permission) error {
+	mac := &macaroon.Macaroon{}
+	if err := mac.UnmarshalBinary(data); err != nil {
+		return errors.Errorf("unable to decode macaroon: %v", err)
+	}
+
+	return mac.Verify(s.rootKey, func(caveat string) error {
+		if !strings.HasPrefix(caveat, permissionsCaveat) {
+			return errors.Errorf("unknown caveat(%v)", caveat)
+		}
+
+		allowed := strings.Split(strings.TrimPrefix(caveat,
+			permissionsCaveat), ",")
+		for _, p := range allowed {
+			if Permission(p) == permission {
+				return nil
+			}
+		}
+
+		return errors.Errorf("permission(%v) is not granted", permission)
+	}, nil)
+}
@@ -0,0 +1,74 @@
+package macaroons
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServiceVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "macaroons")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	rootKeyPath := filepath.Join(dir, "macaroons.key")
+	s, err := NewService(rootKeyPath)
+	if err != nil {
+		t.Fatalf("unable to create service: %v", err)
+	}
+
+	mac, err := s.Mint(Read, Receive)
+	if err != nil {
+		t.Fatalf("unable to mint macaroon: %v", err)
+	}
+
+	data, err := mac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unable to encode macaroon: %v", err)
+	}
+
+	if err := s.Verify(data, Read); err != nil {
+		t.Fatalf("read permission should be granted: %v", err)
+	}
+
+	if err := s.Verify(data, Send); err == nil {
+		t.Fatal("send permission shouldn't be granted")
+	}
+
+	// Holder of the macaroon is able to restrict it further.
+	if err := mac.AddFirstPartyCaveat([]byte("permissions=read")); err != nil {
+		t.Fatalf("unable to add caveat: %v", err)
+	}
+
+	data, err = mac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unable to encode macaroon: %v", err)
+	}
+
+	if err := s.Verify(data, Receive); err == nil {
+		t.Fatal("receive permission shouldn't be granted after restriction")
+	}
+
+	// Service which is restarted with the same root key accepts
+	// previously minted macaroons.
+	s, err = NewService(rootKeyPath)
+	if err != nil {
+		t.Fatalf("unable to create service: %v", err)
+	}
+
+	if err := s.Verify(data, Read); err != nil {
+		t.Fatalf("read permission should be granted: %v", err)
+	}
+
+	other, err := NewService(filepath.Join(dir, "other.key"))
+	if err != nil {
+		t.Fatalf("unable to create service: %v", err)
+	}
+
+	if err := other.Verify(data, Read); err == nil {
+		t.Fatal("macaroon of the other root key shouldn't be accepted")
+	}
+}
@@ -7,25 +7,39 @@ import (
 	"runtime"
 
 	"net"
+	"net/http"
 	"sync"
 
+	"github.com/bitlum/connector/common"
 	"github.com/bitlum/connector/connectors"
 	bitcoind "github.com/bitlum/connector/connectors/daemons/bitcoind_simple"
 	"github.com/bitlum/connector/connectors/daemons/geth"
 	"github.com/bitlum/connector/connectors/daemons/lnd"
+	daemonRPC "github.com/bitlum/connector/connectors/rpc"
 	"github.com/bitlum/connector/connectors/rpc/bitcoin"
 	"github.com/bitlum/connector/connectors/rpc/bitcoincash"
 	"github.com/bitlum/connector/connectors/rpc/dash"
 	"github.com/bitlum/connector/connectors/rpc/litecoin"
 	rpc "github.com/bitlum/connector/crpc"
 	"github.com/bitlum/connector/db/sqlite"
+	"github.com/bitlum/connector/features"
+	"github.com/bitlum/connector/identity"
+	"github.com/bitlum/connector/locale"
+	"github.com/bitlum/connector/macaroons"
 	"github.com/bitlum/connector/metrics"
 	cryptoMetrics "github.com/bitlum/connector/metrics/crypto"
 	rpcMetrics "github.com/bitlum/connector/metrics/rpc"
+	"github.com/bitlum/connector/policy"
+	"github.com/bitlum/connector/webhook"
 	"github.com/btcsuite/go-flags"
 	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"time"
 )
 
@@ -35,6 +49,25 @@ var (
 	shutdownChannel = make(chan struct{})
 )
 
+// dialTimeout is the maximum time to establish outgoing connection, which
+// is not made to the daemons, e.g. to the webhook.
+const dialTimeout = 30 * time.Second
+
+const (
+	// restReadTimeout is the time in which client should send the REST
+	// request, so that slow clients couldn't hold the connections.
+	restReadTimeout = 30 * time.Second
+
+	// restWriteTimeout is the time in which REST request should be
+	// processed and the response written. WebSocket connections reset it
+	// after the upgrade.
+	restWriteTimeout = time.Minute
+
+	// restIdleTimeout is the time after which idle keep-alive connection
+	// is closed.
+	restIdleTimeout = 2 * time.Minute
+)
+
 func backendMain() error {
 	// Load the configuration, and parse any command line options.
 	defaultConfig := getDefaultConfig()
@@ -71,6 +104,147 @@ func backendMain() error {
 		return errors.Errorf("unable open sqlite db: %v", err)
 	}
 
+	// If proxy is specified, outgoing connections to Tor hidden services
+	// are established through it.
+	proxyDial, err := common.NewDialer(loadedConfig.Proxy, dialTimeout)
+	if err != nil {
+		return errors.Errorf("unable to create proxy dialer: %v", err)
+	}
+
+	// Feature flags gate the risky behaviour of the connectors, rules could
+	// be later changed through the admin RPC.
+	featureFlags := features.NewRegistry()
+	for _, value := range loadedConfig.Features {
+		flag, rule, err := features.ParseRule(value)
+		if err != nil {
+			return err
+		}
+
+		if err := featureFlags.Set(flag, rule); err != nil {
+			return errors.Errorf("unable to set feature flag: %v", err)
+		}
+	}
+
+	// Strings which are shown to the payers are translated to the locale
+	// of the merchant.
+	messages := locale.NewCatalog()
+	if loadedConfig.MessagesFile != "" {
+		if err := messages.LoadFile(loadedConfig.MessagesFile); err != nil {
+			return err
+		}
+	}
+
+	if loadedConfig.Locale != "" {
+		err := messages.SetDefault(locale.Locale(loadedConfig.Locale))
+		if err != nil {
+			return errors.Errorf("unable to set default locale: %v", err)
+		}
+	}
+
+	for _, value := range loadedConfig.TenantLocales {
+		tenant, l, err := locale.ParseTenantLocale(value)
+		if err != nil {
+			return err
+		}
+
+		if err := messages.SetTenantLocale(tenant, l); err != nil {
+			return errors.Errorf("unable to set tenant locale: %v", err)
+		}
+	}
+
+	// Rules changed through the admin RPC are kept in the database and
+	// override the ones of the config.
+	if err := featureFlags.Load(sqlite.NewFeatureFlagsStore(dbConn)); err != nil {
+		return errors.Errorf("unable to load feature flags: %v", err)
+	}
+
+	// Receipts store keeps the tenant of the deposit addresses, so that
+	// connectors could check feature flags for incoming payments.
+	receiptsStore := sqlite.NewReceiptsStore(dbConn)
+
+	// Time-locked payments are saved by connectors along with their locks,
+	// which are listed by the RPC server.
+	timeLocksStore := sqlite.NewTimeLocksStore(dbConn)
+
+	// Processed deposits are kept by connectors, so that deposits listed
+	// again after resync of the daemon aren't recorded twice.
+	depositsStore := sqlite.NewDepositsStore(dbConn)
+
+	// Fiat rates are used to quote receipts and to value the payments.
+	fiatRates := make(rpc.FiatRates)
+	for _, value := range loadedConfig.FiatRates {
+		asset, currency, price, err := rpc.ParseFiatRate(value)
+		if err != nil {
+			return err
+		}
+
+		fiatRates.Add(asset, currency, price)
+	}
+
+	// Payments store is shared by connectors and the RPC server, so that
+	// payment updates made by connectors could be streamed to the clients.
+	// Payments are valued in fiat when they are completed, before they
+	// are broadcasted, so that subscribers receive the value as well.
+	paymentsStore := connectors.NewPaymentsBroadcaster(
+		connectors.NewPaymentsValuer(sqlite.NewPaymentStore(dbConn),
+			loadedConfig.FiatCurrency, fiatRates))
+
+	// Identity key signs the payloads which are leaving the server, so
+	// that consumers could authenticate them.
+	identityKey, err := identity.LoadKey(loadedConfig.IdentityKeyPath)
+	if err != nil {
+		return errors.Errorf("unable to load identity key: %v", err)
+	}
+	mainLog.Infof("Server identity key id: %v", identityKey.ID())
+
+	// Activity on the watched addresses is stored and posted on the
+	// webhook, if it is specified. Delivery is done in the background and
+	// retried until the webhook accepts the event, so that unavailable
+	// webhook doesn't block the sync of the connectors.
+	watchStore := sqlite.NewWatchStore(dbConn)
+	watchNotifier := webhook.NewWatchDispatcher(watchStore,
+		webhook.NewWatchNotifier(loadedConfig.WatchWebhook, proxyDial,
+			identityKey))
+	watchNotifier.Start()
+	defer watchNotifier.Stop()
+
+	// Outgoing payments are checked by the pre-send hook before they are
+	// sent, and finished ones are passed to the post-send hook, so that
+	// operators could plug in the custom policy and automation.
+	sendHooks := webhook.NewSendHooks(loadedConfig.PreSendHook,
+		loadedConfig.PostSendHook, loadedConfig.HookTimeout, paymentsStore,
+		paymentsStore, proxyDial, identityKey)
+	sendHooks.Start()
+	defer sendHooks.Stop()
+
+	// Deposit addresses are requested from the external provider if it is
+	// specified, so that they are derived in the separated security
+	// domain, daemons only track the deposits on them.
+	var addressProvider connectors.AddressProvider
+	if loadedConfig.AddressProvider != "" {
+		conn, err := dialAddressProvider(loadedConfig.AddressProvider,
+			loadedConfig.AddressProviderTLSCert, proxyDial)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		addressProvider = rpc.NewRemoteAddressProvider(conn,
+			loadedConfig.Network, loadedConfig.AddressProviderTimeout)
+	}
+
+	// Internal balances are transferred to the payservers of other
+	// regions, if they are specified, and positions with them are settled
+	// on-chain periodically.
+	federationConfig, federationConns, err := newFederationConfig(
+		loadedConfig, sqlite.NewFederationStore(dbConn), proxyDial)
+	if err != nil {
+		return err
+	}
+	for _, conn := range federationConns {
+		defer conn.Close()
+	}
+
 	bitcoinRPCClient, err := bitcoin.NewClient(bitcoin.ClientConfig{
 		Name:     "bitcoind",
 		Logger:   rpcLog,
@@ -79,6 +253,12 @@ func backendMain() error {
 		RPCPort:  loadedConfig.Bitcoin.Port,
 		User:     loadedConfig.Bitcoin.User,
 		Password: loadedConfig.Bitcoin.Password,
+		PoolSize: loadedConfig.Bitcoin.PoolSize,
+		Timeout:  loadedConfig.Bitcoin.Timeout,
+
+		CookiePath:   loadedConfig.Bitcoin.CookiePath,
+		PasswordFile: loadedConfig.Bitcoin.PasswordFile,
+		Proxy:        loadedConfig.Proxy,
 	})
 	if err != nil {
 		return errors.Errorf("unable to create bitcoin rpc client: %v")
@@ -92,6 +272,12 @@ func backendMain() error {
 		RPCPort:  loadedConfig.BitcoinCash.Port,
 		User:     loadedConfig.BitcoinCash.User,
 		Password: loadedConfig.BitcoinCash.Password,
+		PoolSize: loadedConfig.BitcoinCash.PoolSize,
+		Timeout:  loadedConfig.BitcoinCash.Timeout,
+
+		CookiePath:   loadedConfig.BitcoinCash.CookiePath,
+		PasswordFile: loadedConfig.BitcoinCash.PasswordFile,
+		Proxy:        loadedConfig.Proxy,
 	})
 	if err != nil {
 		return errors.Errorf("unable to create bitcoin cash rpc client: %v")
@@ -105,6 +291,12 @@ func backendMain() error {
 		RPCPort:  loadedConfig.Dash.Port,
 		User:     loadedConfig.Dash.User,
 		Password: loadedConfig.Dash.Password,
+		PoolSize: loadedConfig.Dash.PoolSize,
+		Timeout:  loadedConfig.Dash.Timeout,
+
+		CookiePath:   loadedConfig.Dash.CookiePath,
+		PasswordFile: loadedConfig.Dash.PasswordFile,
+		Proxy:        loadedConfig.Proxy,
 	})
 	if err != nil {
 		return errors.Errorf("unable to create dash rpc client: %v")
@@ -118,11 +310,39 @@ func backendMain() error {
 		RPCPort:  loadedConfig.Litecoin.Port,
 		User:     loadedConfig.Litecoin.User,
 		Password: loadedConfig.Litecoin.Password,
+		PoolSize: loadedConfig.Litecoin.PoolSize,
+		Timeout:  loadedConfig.Litecoin.Timeout,
+
+		CookiePath:   loadedConfig.Litecoin.CookiePath,
+		PasswordFile: loadedConfig.Litecoin.PasswordFile,
+		Proxy:        loadedConfig.Proxy,
 	})
 	if err != nil {
 		return errors.Errorf("unable to create dash rpc client: %v")
 	}
 
+	// Calls to the daemons are limited, so that flood of the requests
+	// couldn't overflow daemon rpc work queue and stall the sync.
+	limitCalls := func(client daemonRPC.Client, asset connectors.Asset,
+		cfg *BitcoindConfig) daemonRPC.Client {
+		return daemonRPC.NewLimitedClient(client, daemonRPC.LimiterConfig{
+			Asset:              string(asset),
+			MaxConcurrentCalls: cfg.MaxCalls,
+			MaxQueuedCalls:     cfg.MaxQueuedCalls,
+			QueueTimeout:       cfg.QueueTimeout,
+			Metrics:            cryptoMetricsBackend,
+		})
+	}
+
+	bitcoinDaemon := limitCalls(bitcoinRPCClient, connectors.BTC,
+		loadedConfig.Bitcoin)
+	bitcoincashDaemon := limitCalls(bitcoincashRPCClient, connectors.BCH,
+		loadedConfig.BitcoinCash)
+	dashDaemon := limitCalls(dashRPCClient, connectors.DASH,
+		loadedConfig.Dash)
+	litecoinDaemon := limitCalls(litecoinRPCClient, connectors.LTC,
+		loadedConfig.Litecoin)
+
 	// Create blockchain connectors in order to be able to listen for incoming
 	// transaction, be able to answer on the question how many
 	// pending transaction user have and also to withdraw money from exchange.
@@ -130,14 +350,22 @@ func backendMain() error {
 		blockchainConnectors[connectors.BCH], err = bitcoind.NewConnector(&bitcoind.Config{
 			Net:              loadedConfig.Network,
 			MinConfirmations: loadedConfig.BitcoinCash.MinConfirmations,
+			SyncWorkers:      loadedConfig.BitcoinCash.SyncWorkers,
 			Asset:            connectors.BCH,
 			Logger:           mainLog,
 			Metrics:          cryptoMetricsBackend,
-			PaymentStore:     sqlite.NewPaymentStore(dbConn),
+			PaymentStore:     paymentsStore,
 			StateStore:       sqlite.NewBitcoinSimpleStateStorage(connectors.BCH, dbConn),
 			// TODO(andrew.shvv) Create subsystem to return current fee per unit
-			FeePerByte: loadedConfig.BitcoinCash.FeePerUnit,
-			RPCClient:  bitcoincashRPCClient,
+			FeePerByte:         loadedConfig.BitcoinCash.FeePerUnit,
+			RPCClient:          bitcoincashDaemon,
+			WatchStore:         watchStore,
+			WatchNotifier:      watchNotifier,
+			Features:           featureFlags,
+			ReceiptsStore:      receiptsStore,
+			DepositsStore:      depositsStore,
+			DelegatedAddresses: addressProvider != nil,
+			TimeLocksStore:     timeLocksStore,
 		})
 		if err != nil {
 			return errors.Errorf("unable to create bitcoin cash connector: %v", err)
@@ -148,14 +376,22 @@ func backendMain() error {
 		blockchainConnectors[connectors.BTC], err = bitcoind.NewConnector(&bitcoind.Config{
 			Net:              loadedConfig.Network,
 			MinConfirmations: loadedConfig.Bitcoin.MinConfirmations,
+			SyncWorkers:      loadedConfig.Bitcoin.SyncWorkers,
 			Asset:            connectors.BTC,
 			Logger:           mainLog,
 			Metrics:          cryptoMetricsBackend,
-			PaymentStore:     sqlite.NewPaymentStore(dbConn),
+			PaymentStore:     paymentsStore,
 			StateStore:       sqlite.NewBitcoinSimpleStateStorage(connectors.BTC, dbConn),
 			// TODO(andrew.shvv) Create subsystem to return current fee per unit
-			FeePerByte: loadedConfig.BitcoinCash.FeePerUnit,
-			RPCClient:  bitcoinRPCClient,
+			FeePerByte:         loadedConfig.BitcoinCash.FeePerUnit,
+			RPCClient:          bitcoinDaemon,
+			WatchStore:         watchStore,
+			WatchNotifier:      watchNotifier,
+			Features:           featureFlags,
+			ReceiptsStore:      receiptsStore,
+			DepositsStore:      depositsStore,
+			DelegatedAddresses: addressProvider != nil,
+			TimeLocksStore:     timeLocksStore,
 		})
 		if err != nil {
 			return errors.Errorf("unable to create bitcoin connector: %v", err)
@@ -166,15 +402,23 @@ func backendMain() error {
 		blockchainConnectors[connectors.DASH], err = bitcoind.NewConnector(&bitcoind.Config{
 			Net:              loadedConfig.Network,
 			MinConfirmations: loadedConfig.Dash.MinConfirmations,
+			SyncWorkers:      loadedConfig.Dash.SyncWorkers,
 			Asset:            connectors.DASH,
 			Logger:           mainLog,
 			Metrics:          cryptoMetricsBackend,
-			PaymentStore:     sqlite.NewPaymentStore(dbConn),
+			PaymentStore:     paymentsStore,
 			StateStore: sqlite.NewBitcoinSimpleStateStorage(connectors.
 				DASH, dbConn),
 			// TODO(andrew.shvv) Create subsystem to return current fee per unit
-			FeePerByte: loadedConfig.Dash.FeePerUnit,
-			RPCClient:  dashRPCClient,
+			FeePerByte:         loadedConfig.Dash.FeePerUnit,
+			RPCClient:          dashDaemon,
+			WatchStore:         watchStore,
+			WatchNotifier:      watchNotifier,
+			Features:           featureFlags,
+			ReceiptsStore:      receiptsStore,
+			DepositsStore:      depositsStore,
+			DelegatedAddresses: addressProvider != nil,
+			TimeLocksStore:     timeLocksStore,
 		})
 		if err != nil {
 			return errors.Errorf("unable to create dash connector: %v", err)
@@ -185,14 +429,22 @@ func backendMain() error {
 		blockchainConnectors[connectors.LTC], err = bitcoind.NewConnector(&bitcoind.Config{
 			Net:              loadedConfig.Network,
 			MinConfirmations: loadedConfig.Litecoin.MinConfirmations,
+			SyncWorkers:      loadedConfig.Litecoin.SyncWorkers,
 			Asset:            connectors.LTC,
 			Logger:           mainLog,
 			Metrics:          cryptoMetricsBackend,
-			PaymentStore:     sqlite.NewPaymentStore(dbConn),
+			PaymentStore:     paymentsStore,
 			StateStore:       sqlite.NewBitcoinSimpleStateStorage(connectors.LTC, dbConn),
 			// TODO(andrew.shvv) Create subsystem to return current fee per unit
-			FeePerByte: loadedConfig.Litecoin.FeePerUnit,
-			RPCClient:  litecoinRPCClient,
+			FeePerByte:         loadedConfig.Litecoin.FeePerUnit,
+			RPCClient:          litecoinDaemon,
+			WatchStore:         watchStore,
+			WatchNotifier:      watchNotifier,
+			Features:           featureFlags,
+			ReceiptsStore:      receiptsStore,
+			DepositsStore:      depositsStore,
+			DelegatedAddresses: addressProvider != nil,
+			TimeLocksStore:     timeLocksStore,
 		})
 		if err != nil {
 			return errors.Errorf("unable to create litecoin connector: %v", err)
@@ -204,19 +456,29 @@ func backendMain() error {
 			Net:                 loadedConfig.Network,
 			MinConfirmations:    loadedConfig.Ethereum.MinConfirmations,
 			SyncTickDelay:       loadedConfig.Ethereum.SyncDelay,
+			SyncWorkers:         loadedConfig.Ethereum.SyncWorkers,
+			MaxPendingPayments:  loadedConfig.Ethereum.MaxPending,
 			Asset:               connectors.ETH,
 			Logger:              mainLog,
 			Metrics:             cryptoMetricsBackend,
 			LastSyncedBlockHash: loadedConfig.Ethereum.ForceLastHash,
-			PaymentStorage:      sqlite.NewPaymentStore(dbConn),
+			PaymentStorage:      paymentsStore,
 			StateStorage: sqlite.NewConnectorStateStorage(connectors.
 				ETH, dbConn),
 			AccountStorage: sqlite.NewGethAccountsStorage(dbConn),
+			WatchStore:     watchStore,
+			WatchNotifier:  watchNotifier,
 			DaemonCfg: &geth.DaemonConfig{
 				Name:       "geth",
 				ServerHost: loadedConfig.Ethereum.Host,
 				ServerPort: loadedConfig.Ethereum.Port,
 				Password:   loadedConfig.Ethereum.Password,
+				MaxConns:   loadedConfig.Ethereum.MaxConns,
+				Timeout:    loadedConfig.Ethereum.Timeout,
+				KeepAlive:  loadedConfig.Ethereum.KeepAlive,
+				Proxy:      loadedConfig.Proxy,
+
+				PasswordFile: loadedConfig.Ethereum.PasswordFile,
 			},
 		})
 		if err != nil {
@@ -226,16 +488,18 @@ func backendMain() error {
 
 	if !loadedConfig.BitcoinLightning.Disabled {
 		lightningConnector, err := lnd.NewConnector(&lnd.Config{
-			PeerHost:     loadedConfig.BitcoinLightning.PeerHost,
-			PeerPort:     loadedConfig.BitcoinLightning.PeerPort,
-			Net:          loadedConfig.Network,
-			Name:         "lnd",
-			Host:         loadedConfig.BitcoinLightning.Host,
-			Port:         loadedConfig.BitcoinLightning.Port,
-			TlsCertPath:  loadedConfig.BitcoinLightning.TlsCertPath,
-			MacaroonPath: loadedConfig.BitcoinLightning.MacaroonPath,
-			Metrics:      cryptoMetricsBackend,
-			PaymentStore: sqlite.NewPaymentStore(dbConn),
+			PeerHost:      loadedConfig.BitcoinLightning.PeerHost,
+			PeerPort:      loadedConfig.BitcoinLightning.PeerPort,
+			Net:           loadedConfig.Network,
+			Name:          "lnd",
+			Host:          loadedConfig.BitcoinLightning.Host,
+			Port:          loadedConfig.BitcoinLightning.Port,
+			TlsCertPath:   loadedConfig.BitcoinLightning.TlsCertPath,
+			MacaroonPath:  loadedConfig.BitcoinLightning.MacaroonPath,
+			Proxy:         loadedConfig.Proxy,
+			Metrics:       cryptoMetricsBackend,
+			PaymentStore:  paymentsStore,
+			DepositsStore: depositsStore,
 		})
 		if err != nil {
 			return errors.Errorf("unable to create lightning bitcoin "+
@@ -333,52 +597,403 @@ func backendMain() error {
 
 	// Initialize RPC server to handle gRPC requests from trading bots and
 	// frontend users.
+	if loadedConfig.TestPayments {
+		mainLog.Warn("Test payments are enabled, fabricated incoming " +
+			"payments could be injected through the admin RPC")
+	}
+
+	apiKeysStore := sqlite.NewAPIKeysStore(dbConn)
+
+	// Rate limits protect the hot wallet and the daemons from the buggy or
+	// compromised clients. Limits are checked by the
+	// server methods, after the call has been authenticated and the asset
+	// has been resolved.
+	rateLimits := make(map[rpc.RateLimitRule]rpc.RateLimit)
+	for _, value := range loadedConfig.RPCRateLimits {
+		rule, limit, err := rpc.ParseRateLimit(value)
+		if err != nil {
+			return err
+		}
+
+		rateLimits[rule] = limit
+	}
+	rateLimiter := rpc.NewRateLimiter(rateLimits)
+
+	// Canary payments are kept by the server, so that they could be sent
+	// by the self-test requested through the admin RPC as well.
+	selfTestCanaries := make(map[connectors.Asset]*rpc.SelfTestCanary)
+	for _, value := range loadedConfig.SelfTestCanaries {
+		asset, canary, err := rpc.ParseSelfTestCanary(value)
+		if err != nil {
+			return err
+		}
+
+		selfTestCanaries[asset] = canary
+	}
+
+	// Declarative send policy is evaluated before the pre-send hook, so
+	// that payments denied by the policy don't reach the hook. Destinations
+	// of the policy are normalized by the connectors the same way as the
+	// receipts of the payments.
+	var sendHook connectors.SendHook = sendHooks
+	if loadedConfig.PolicyFile != "" {
+		policyEngine, err := policy.NewEngine(loadedConfig.PolicyFile,
+			policyNormalizer(blockchainConnectors))
+		if err != nil {
+			return err
+		}
+
+		sendHook = connectors.SendHookChain{policyEngine, sendHooks}
+	}
+
 	rpcServer, err := rpc.NewRPCServer(loadedConfig.Network, blockchainConnectors,
-		lightningConnectors, sqlite.NewPaymentStore(dbConn), rpcMetricsBackend)
+		lightningConnectors, paymentsStore,
+		sqlite.NewPayeesStore(dbConn), watchStore, apiKeysStore,
+		timeLocksStore, receiptsStore,
+		sqlite.NewTestPaymentsStore(dbConn), sqlite.NewBrandingStore(dbConn),
+		sqlite.NewBalanceSnapshotsStore(dbConn),
+		sqlite.NewWithdrawalPausesStore(dbConn),
+		sqlite.NewAccountsStore(dbConn), dbConn, sendHook,
+		addressProvider, identityKey, federationConfig,
+		rateLimiter, fiatRates, selfTestCanaries, featureFlags, messages,
+		&rpc.DiagnosticsInfo{
+			Version:   version(),
+			StartedAt: time.Now(),
+			Config:    sanitizedConfig(loadedConfig),
+		}, loadedConfig.TestPayments, rpcMetricsBackend)
 	if err != nil {
 		return errors.Errorf("unable to init RPC server: %v", err)
 	}
 
-	var opts []grpc.ServerOption
+	// Serving status of the standard gRPC health service is updated in the
+	// background, so that probes are not hitting the daemons.
+	healthServer := health.NewServer()
+	healthReporter := rpc.NewHealthReporter(rpcServer, healthServer)
+	healthReporter.Start()
+	defer healthReporter.Stop()
+
+	// Balances are saved in the background, so that their history is
+	// available even if nobody is requesting them.
+	if loadedConfig.BalanceSnapshotInterval > 0 {
+		balanceRecorder := rpc.NewBalanceRecorder(rpcServer,
+			loadedConfig.BalanceSnapshotInterval)
+		balanceRecorder.Start()
+		defer balanceRecorder.Stop()
+	}
+
+	// Self-test is run in the background, so that slow daemon doesn't
+	// delay the start, its results are reported by GetInfo.
+	if loadedConfig.SelfTest {
+		go rpcServer.SelfTest()
+	}
+
+	// Holdings are compared with the ledger in the background, so that
+	// outgoing payments are suspended as soon as funds are missing.
+	if loadedConfig.BalanceCheckInterval > 0 {
+		tolerances := make(map[connectors.Asset]decimal.Decimal)
+		for _, value := range loadedConfig.BalanceTolerances {
+			asset, amount, err := rpc.ParseBalanceTolerance(value)
+			if err != nil {
+				return err
+			}
+
+			tolerances[asset] = amount
+		}
+
+		balanceWatchdog := rpc.NewBalanceWatchdog(rpcServer,
+			loadedConfig.BalanceCheckInterval, tolerances)
+		balanceWatchdog.Start()
+		defer balanceWatchdog.Stop()
+	}
+
+	// Expired or unpayable invoices are replaced in the background, so
+	// that checkout pages could show the new one instead.
+	if loadedConfig.InvoiceRegenerations > 0 {
+		invoiceRegenerator := rpc.NewInvoiceRegenerator(rpcServer,
+			loadedConfig.InvoiceRegenerationInterval,
+			loadedConfig.InvoiceRegenerations)
+		invoiceRegenerator.Start()
+		defer invoiceRegenerator.Stop()
+	}
+
+	// Transfers which haven't reached the peers are retried and owed
+	// amounts are settled in the background.
+	if federationConfig != nil && loadedConfig.FederationSettleInterval > 0 {
+		federationSettler := rpc.NewFederationSettler(rpcServer,
+			loadedConfig.FederationSettleInterval)
+		federationSettler.Start()
+		defer federationSettler.Stop()
+	}
+
+	// Test payments injected before the restart are completed by their
+	// persisted schedule.
+	if err := rpcServer.ResumeTestPayments(); err != nil {
+		return err
+	}
+
+	// Compressors are negotiated with the client by the gRPC itself, gzip
+	// compressor is registered by the import of its package.
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(loadedConfig.RPCMaxMsgSize),
+		grpc.MaxSendMsgSize(loadedConfig.RPCMaxMsgSize),
+	}
+
+	// Use TLS to encrypt gRPC endpoints communications, self-signed
+	// certificate is generated if operator hasn't provided one. UNIX sockets
+	// are protected by the file permissions, that is why connections
+	// through them aren't encrypted.
+	var tlsOpts []grpc.ServerOption
+	if !loadedConfig.NoTLS {
+		err := ensureTLSCert(loadedConfig.TLSCertPath, loadedConfig.TLSKeyPath,
+			loadedConfig.TLSExtraIPs, loadedConfig.TLSExtraDomains)
+		if err != nil {
+			return errors.Errorf("unable to generate TLS keys: %v", err)
+		}
 
-	// If TLS files are exist than use it to encrypt gRPC endpoints
-	// communications.
-	if fileExists(loadedConfig.TLSCertPath) && fileExists(loadedConfig.TLSKeyPath) {
 		creds, err := credentials.NewServerTLSFromFile(loadedConfig.TLSCertPath,
 			loadedConfig.TLSKeyPath)
 		if err != nil {
 			return errors.Errorf("unable to load TLS keys: %v", err)
 		}
-		opts = append(opts, grpc.Creds(creds))
+		tlsOpts = append(tlsOpts, grpc.Creds(creds))
 		mainLog.Info("TLS encryption enabled")
+	} else {
+		mainLog.Warn("TLS encryption is disabled, RPC endpoint is " +
+			"communicating in plaintext")
 	}
 
-	grpcServer := grpc.NewServer(opts...)
-	rpc.RegisterPayServerServer(grpcServer, rpcServer)
+	listeners := []rpcListener{{
+		network: "tcp",
+		address: net.JoinHostPort(loadedConfig.RPCHost, loadedConfig.RPCPort),
+	}}
+	for _, address := range loadedConfig.RPCListen {
+		listeners = append(listeners, parseRPCListener(address, false))
+	}
+	for _, address := range loadedConfig.RPCAdminListen {
+		listeners = append(listeners, parseRPCListener(address, true))
+	}
 
-	grpcAddr := net.JoinHostPort(loadedConfig.RPCHost, loadedConfig.RPCPort)
-	lis, err := net.Listen("tcp", grpcAddr)
+	adminToken, err := loadAdminToken(loadedConfig.AdminTokenPath)
 	if err != nil {
-		return errors.Errorf("unable to listen on gRPC addr: %v", err)
+		return err
+	}
+
+	// Macaroons protect PayServer methods, so that clients are able to
+	// call only methods which are granted to them, e.g. read-only dashboard
+	// is unable to send payments.
+	var macaroonService *macaroons.Service
+	if !loadedConfig.NoMacaroons {
+		macaroonService, err = loadMacaroonService(loadedConfig.MacaroonDir)
+		if err != nil {
+			return errors.Errorf("unable to init macaroons: %v", err)
+		}
+	} else if !loadedConfig.APIKeys {
+		mainLog.Warn("Macaroons are disabled, PayServer methods are " +
+			"available to anyone who is able to reach the RPC endpoint")
+	}
+
+	// API keys identify downstream services and limit them by the scopes,
+	// keys are managed through the Admin service.
+	var gatewayAPIKeys connectors.APIKeysStore
+	if loadedConfig.APIKeys {
+		gatewayAPIKeys = apiKeysStore
+		mainLog.Info("API keys are required to call PayServer methods")
+	}
+
+	// Admin service is served only on the operator listeners, so that
+	// merchant facing listeners don't expose methods which are dangerous.
+	serveAdmin := func(l rpcListener) bool {
+		return l.admin
+	}
+
+	// Calls which exceed the latency budget of the method are logged along
+	// with the time spent in the database and connectors.
+	latencyBudgets := &rpc.LatencyBudgets{
+		Methods: make(map[string]time.Duration),
+	}
+	for _, value := range loadedConfig.RPCLatencyBudgets {
+		method, budget, err := rpc.ParseLatencyBudget(value)
+		if err != nil {
+			return err
+		}
+
+		if method == "" {
+			latencyBudgets.Default = budget
+			continue
+		}
+		latencyBudgets.Methods[method] = budget
+	}
+
+	// Listeners of the same kind share the gRPC server, because registered
+	// services and transport credentials are the options of the server.
+	type serverKind struct {
+		admin bool
+		unix  bool
+	}
+	grpcServers := make(map[serverKind]*grpc.Server)
+
+	getServer := func(l rpcListener) *grpc.Server {
+		kind := serverKind{admin: serveAdmin(l), unix: l.isUnix()}
+		if grpcServer, ok := grpcServers[kind]; ok {
+			return grpcServer
+		}
+
+		// Version interceptors go first, because calls of the versioned
+		// services are checked by the others under the canonical names.
+		unaryChain := []grpc.UnaryServerInterceptor{
+			rpc.VersionUnaryInterceptor(),
+			rpc.LatencyBudgetUnaryInterceptor(latencyBudgets,
+				rpcMetricsBackend),
+		}
+		streamChain := []grpc.StreamServerInterceptor{
+			rpc.VersionStreamInterceptor(),
+		}
+
+		if macaroonService != nil {
+			unaryChain = append(unaryChain,
+				rpc.MacaroonUnaryInterceptor(macaroonService))
+			streamChain = append(streamChain,
+				rpc.MacaroonStreamInterceptor(macaroonService))
+		}
+
+		// API key interceptor goes before the admin one, because API key
+		// with admin scope replaces the admin token.
+		if loadedConfig.APIKeys {
+			unaryChain = append(unaryChain,
+				rpc.APIKeyUnaryInterceptor(apiKeysStore))
+			streamChain = append(streamChain,
+				rpc.APIKeyStreamInterceptor(apiKeysStore))
+		}
+
+		if kind.admin {
+			unaryChain = append(unaryChain,
+				rpc.AdminAuthUnaryInterceptor(adminToken))
+			streamChain = append(streamChain,
+				rpc.AdminAuthStreamInterceptor(adminToken))
+		}
+
+		serverOpts := append([]grpc.ServerOption{}, opts...)
+		serverOpts = append(serverOpts,
+			grpc.UnaryInterceptor(rpc.ChainUnaryInterceptors(unaryChain...)),
+			grpc.StreamInterceptor(rpc.ChainStreamInterceptors(streamChain...)),
+		)
+		if !l.isUnix() {
+			serverOpts = append(serverOpts, tlsOpts...)
+		}
+
+		grpcServer := grpc.NewServer(serverOpts...)
+		rpc.RegisterPayServerServer(grpcServer, rpcServer)
+		rpc.RegisterVersionedPayServerServer(grpcServer, rpcServer)
+		healthpb.RegisterHealthServer(grpcServer, healthServer)
+		if kind.admin {
+			rpc.RegisterAdminServer(grpcServer, rpcServer)
+			rpc.RegisterVersionedAdminServer(grpcServer, rpcServer)
+		}
+		if !loadedConfig.NoReflection {
+			reflection.Register(grpcServer)
+		}
+
+		// Peers are authenticated by the signatures of the requests,
+		// which are checked by the methods, rather than by the
+		// interceptors.
+		if federationConfig != nil && !kind.admin && !kind.unix {
+			rpc.RegisterFederationServer(grpcServer, rpcServer)
+		}
+		grpcServers[kind] = grpcServer
+
+		return grpcServer
 	}
 
-	// Spawn goroutine which runs the original gRPC server, which will be
+	// Spawn goroutine per listener which runs the gRPC server, which will be
 	// responsible for transferring requests from trading robots to the rpc
 	// server.
-	errChan := make(chan error)
-	go func() {
-		mainLog.Infof("server gRPC on addr: '%v'", grpcAddr)
-		if err := grpcServer.Serve(lis); err != nil {
-			errChan <- errors.Errorf("unable to server gRPC server: %v", err)
-			return
+	errChan := make(chan error, len(listeners)+len(loadedConfig.RESTListen))
+	for _, l := range listeners {
+		lis, err := l.listen(loadedConfig.rpcSocketMode)
+		if err != nil {
+			return errors.Errorf("unable to listen on gRPC addr(%v): %v",
+				l.address, err)
+		}
+
+		grpcServer := getServer(l)
+
+		go func(l rpcListener) {
+			mainLog.Infof("server gRPC on %v addr: '%v', admin(%v)",
+				l.network, l.address, serveAdmin(l))
+			if err := grpcServer.Serve(lis); err != nil {
+				errChan <- errors.Errorf("unable to server gRPC server: %v", err)
+				return
+			}
+			mainLog.Infof("stop serving gRPC on addr: '%v'", l.address)
+		}(l)
+	}
+
+	// REST gateway exposes merchant facing methods for the clients which
+	// are unable to use gRPC, it is encrypted with the same TLS keys.
+	// REST responses are signed only on demand, because signing of every
+	// response isn't free.
+	var gatewaySigner *identity.Key
+	if loadedConfig.SignResponses {
+		gatewaySigner = identityKey
+	}
+
+	// REST calls are measured against the same latency budgets as gRPC
+	// ones, authentication is done by the gateway.
+	gateway := rpc.NewGateway(rpcServer, macaroonService, gatewayAPIKeys,
+		gatewaySigner,
+		rpc.LatencyBudgetUnaryInterceptor(latencyBudgets, rpcMetricsBackend),
+		loadedConfig.RESTAllowedOrigins,
+		rpc.JSONNames(loadedConfig.RESTJSONNames),
+		loadedConfig.RESTCacheMaxAge)
+	tlsEnabled := len(tlsOpts) != 0
+
+	var restServers []*http.Server
+	for _, address := range loadedConfig.RESTListen {
+		lis, err := net.Listen("tcp", address)
+		if err != nil {
+			return errors.Errorf("unable to listen on REST addr(%v): %v",
+				address, err)
+		}
+
+		restServer := &http.Server{
+			Handler:           gateway,
+			ReadHeaderTimeout: restReadTimeout,
+			ReadTimeout:       restReadTimeout,
+			WriteTimeout:      restWriteTimeout,
+			IdleTimeout:       restIdleTimeout,
 		}
-		mainLog.Info("stop serving gRPC")
-	}()
+		restServers = append(restServers, restServer)
+
+		go func(address string) {
+			mainLog.Infof("server REST on addr: '%v', tls(%v)", address,
+				tlsEnabled)
+
+			var err error
+			if tlsEnabled {
+				err = restServer.ServeTLS(lis, loadedConfig.TLSCertPath,
+					loadedConfig.TLSKeyPath)
+			} else {
+				err = restServer.Serve(lis)
+			}
+
+			if err != nil && err != http.ErrServerClosed {
+				errChan <- errors.Errorf("unable to serve REST: %v", err)
+				return
+			}
+			mainLog.Infof("stop serving REST on addr: '%v'", address)
+		}(address)
+	}
 
 	var wg sync.WaitGroup
 
 	addInterruptHandler(shutdownChannel, func() {
-		grpcServer.Stop()
+		for _, grpcServer := range grpcServers {
+			grpcServer.Stop()
+		}
+
+		for _, restServer := range restServers {
+			restServer.Close()
+		}
 
 		for _, c := range blockchainConnectors {
 			switch c := c.(type) {
@@ -406,9 +1021,17 @@ func main() {
 	// Use all processor cores.
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
+	// Config check shares the options of the server, that is why it is
+	// the command rather than the separate binary.
+	run := backendMain
+	if len(os.Args) > 1 && os.Args[1] == checkConfigCommand {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		run = checkConfigMain
+	}
+
 	// Call the "real" main in a nested manner so the defers will properly
 	// be executed in the case of a graceful shutdown.
-	if err := backendMain(); err != nil {
+	if err := run(); err != nil {
 		if e, ok := err.(*flags.Error); ok && e.Type == flags.ErrHelp {
 		} else {
 			fmt.Fprintln(os.Stderr, err)
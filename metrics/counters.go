@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Counter is the value of the counter metric with the particular set of
+// labels.
+type Counter struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// ErrorCounters returns non-zero counters of the errors, panics and slow
+// calls of all subsystems, which have been registered in the default
+// prometheus registry.
+func ErrorCounters() ([]*Counter, error) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return nil, errors.Errorf("unable to gather metrics: %v", err)
+	}
+
+	var counters []*Counter
+	for _, family := range families {
+		name := family.GetName()
+		if !strings.HasPrefix(name, Namespace+"_") {
+			continue
+		}
+
+		if !strings.HasSuffix(name, "_errors_total") &&
+			!strings.HasSuffix(name, "_panics_total") &&
+			!strings.HasSuffix(name, "_slow_calls_total") {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			value := metric.GetCounter().GetValue()
+			if value == 0 {
+				continue
+			}
+
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			counters = append(counters, &Counter{
+				Name:   name,
+				Labels: labels,
+				Value:  value,
+			})
+		}
+	}
+
+	sort.Slice(counters, func(i, j int) bool {
+		return counters[i].Name < counters[j].Name
+	})
+
+	return counters, nil
+}
@@ -28,6 +28,10 @@ const (
 	// daemonLabel is used to distinguish different daemon names,
 	// and quickly identify the problem if such occurs.
 	daemonLabel = "daemon"
+
+	// cacheLabel is used to distinguish different in-memory caches of the
+	// connector.
+	cacheLabel = "cache"
 )
 
 // MetricsBackend is a system which is responsible for receiving and storing
@@ -38,11 +42,16 @@ type MetricsBackend interface {
 	OverallFee(daemon, asset string, amount float64)
 	CurrentFunds(daemon, asset string, amount float64)
 	BlockNumber(daemon, asset string, blockNumber int64)
+	CacheSize(daemon, asset, cache string, size int)
+	RPCCallsInFlight(daemon, asset string, calls int)
+	RPCCallsQueued(daemon, asset string, calls int)
 
 	AddRequest(daemon, asset, request string)
 	AddError(daemon, asset, request, severity string)
 	AddPanic(daemon, asset, request string)
 	AddRequestDuration(daemon, asset, request string, dur time.Duration)
+	AddRPCCallRejected(daemon, asset, request string)
+	AddRPCQueueDuration(daemon, asset string, dur time.Duration)
 }
 
 // DisabledBackend metric backend which does nothing.
@@ -56,10 +65,15 @@ func (b *MockBackend) OverallReceived(daemon, asset string, amount float64)
 func (b *MockBackend) OverallFee(daemon, asset string, amount float64)                     {}
 func (b *MockBackend) CurrentFunds(daemon, asset string, amount float64)                   {}
 func (b *MockBackend) BlockNumber(daemon, asset string, blockNumber int64)                 {}
+func (b *MockBackend) CacheSize(daemon, asset, cache string, size int)                     {}
+func (b *MockBackend) RPCCallsInFlight(daemon, asset string, calls int)                    {}
+func (b *MockBackend) RPCCallsQueued(daemon, asset string, calls int)                      {}
 func (b *MockBackend) AddRequest(daemon, asset, request string)                            {}
 func (b *MockBackend) AddError(daemon, asset, request, severity string)                    {}
 func (b *MockBackend) AddPanic(daemon, asset, request string)                              {}
 func (b *MockBackend) AddRequestDuration(daemon, asset, request string, dur time.Duration) {}
+func (b *MockBackend) AddRPCCallRejected(daemon, asset, request string)                    {}
+func (b *MockBackend) AddRPCQueueDuration(daemon, asset string, dur time.Duration)         {}
 
 // PrometheusBackend is the main subsystem metrics implementation. Uses
 // prometheus metrics singletons defined above.
@@ -80,6 +94,11 @@ type PrometheusBackend struct {
 	overallReceivedFunds   *prometheus.GaugeVec
 	overallFeeFunds        *prometheus.GaugeVec
 	blockNumber            *prometheus.GaugeVec
+	cacheSize              *prometheus.GaugeVec
+	rpcCallsInFlight       *prometheus.GaugeVec
+	rpcCallsQueued         *prometheus.GaugeVec
+	rpcCallsRejected       *prometheus.CounterVec
+	rpcQueueDurationSecs   *prometheus.HistogramVec
 }
 
 // CurrentFunds sets the number of funds available under control of system.
@@ -148,6 +167,53 @@ func (m PrometheusBackend) BlockNumber(daemon, asset string, blockNumber int64)
 	).Set(float64(blockNumber))
 }
 
+// CacheSize sets the number of entries in the in-memory cache of the
+// connector.
+//
+// WARN: Cache name should be taken from limited set.
+// Don't use dynamic naming, it may cause dramatic increase of the amount of
+// data on the metric server.
+//
+// NOTE: Non-pointer receiver made by intent to avoid conflict in the system
+// with parallel metrics report.
+func (m PrometheusBackend) CacheSize(daemon, asset, cache string, size int) {
+	m.cacheSize.With(
+		prometheus.Labels{
+			cacheLabel:  cache,
+			assetLabel:  asset,
+			daemonLabel: daemon,
+		},
+	).Set(float64(size))
+}
+
+// RPCCallsInFlight sets the number of calls to the daemon which are
+// currently executed by the connector.
+//
+// NOTE: Non-pointer receiver made by intent to avoid conflict in the system
+// with parallel metrics report.
+func (m PrometheusBackend) RPCCallsInFlight(daemon, asset string, calls int) {
+	m.rpcCallsInFlight.With(
+		prometheus.Labels{
+			assetLabel:  asset,
+			daemonLabel: daemon,
+		},
+	).Set(float64(calls))
+}
+
+// RPCCallsQueued sets the number of calls to the daemon which are waiting
+// for the free slot.
+//
+// NOTE: Non-pointer receiver made by intent to avoid conflict in the system
+// with parallel metrics report.
+func (m PrometheusBackend) RPCCallsQueued(daemon, asset string, calls int) {
+	m.rpcCallsQueued.With(
+		prometheus.Labels{
+			assetLabel:  asset,
+			daemonLabel: daemon,
+		},
+	).Set(float64(calls))
+}
+
 // AddRequest increases request counter for the given request name.
 //
 // NOTE: Non-pointer receiver made by intent to avoid conflict in the system
@@ -211,6 +277,37 @@ func (m PrometheusBackend) AddRequestDuration(daemon, asset, request string,
 	).Observe(dur.Seconds())
 }
 
+// AddRPCCallRejected increases counter of the calls to the daemon which
+// were rejected because the queue was full or the call waited for the free
+// slot for too long.
+//
+// NOTE: Non-pointer receiver made by intent to avoid conflict in the system
+// with parallel metrics report.
+func (m PrometheusBackend) AddRPCCallRejected(daemon, asset, request string) {
+	m.rpcCallsRejected.With(
+		prometheus.Labels{
+			requestLabel: request,
+			assetLabel:   asset,
+			daemonLabel:  daemon,
+		},
+	).Add(1)
+}
+
+// AddRPCQueueDuration sends the metric with how much time call to the
+// daemon has waited for the free slot.
+//
+// NOTE: Non-pointer receiver made by intent to avoid conflict in the system
+// with parallel metrics report.
+func (m PrometheusBackend) AddRPCQueueDuration(daemon, asset string,
+	dur time.Duration) {
+	m.rpcQueueDurationSecs.With(
+		prometheus.Labels{
+			assetLabel:  asset,
+			daemonLabel: daemon,
+		},
+	).Observe(dur.Seconds())
+}
+
 // InitMetricsBackend creates subsystem metrics for specified
 // net. Creates and tries to register metrics singletons. If register was
 // already done, than function not returning error.
@@ -422,5 +519,117 @@ func InitMetricsBackend(net string) (MetricsBackend, error) {
 				err.Error())
 	}
 
+	backend.cacheSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "cache_size",
+			Help:      "Number of entries in the in-memory cache",
+			ConstLabels: prometheus.Labels{
+				metrics.NetLabel: net,
+			},
+		},
+		[]string{
+			cacheLabel,
+			assetLabel,
+			daemonLabel,
+		},
+	)
+
+	if err := prometheus.Register(backend.cacheSize); err != nil {
+		return backend, errors.Errorf(
+			"unable to register 'cacheSize' metric: " +
+				err.Error())
+	}
+
+	backend.rpcCallsInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "rpc_calls_in_flight",
+			Help:      "Number of calls to the daemon which are executed",
+			ConstLabels: prometheus.Labels{
+				metrics.NetLabel: net,
+			},
+		},
+		[]string{
+			assetLabel,
+			daemonLabel,
+		},
+	)
+
+	if err := prometheus.Register(backend.rpcCallsInFlight); err != nil {
+		return backend, errors.Errorf(
+			"unable to register 'rpcCallsInFlight' metric: " +
+				err.Error())
+	}
+
+	backend.rpcCallsQueued = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "rpc_calls_queued",
+			Help:      "Number of calls to the daemon waiting for the free slot",
+			ConstLabels: prometheus.Labels{
+				metrics.NetLabel: net,
+			},
+		},
+		[]string{
+			assetLabel,
+			daemonLabel,
+		},
+	)
+
+	if err := prometheus.Register(backend.rpcCallsQueued); err != nil {
+		return backend, errors.Errorf(
+			"unable to register 'rpcCallsQueued' metric: " +
+				err.Error())
+	}
+
+	backend.rpcCallsRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "rpc_calls_rejected_total",
+			Help:      "Total calls to the daemon rejected by the limiter",
+			ConstLabels: prometheus.Labels{
+				metrics.NetLabel: net,
+			},
+		},
+		[]string{
+			requestLabel,
+			assetLabel,
+			daemonLabel,
+		},
+	)
+
+	if err := prometheus.Register(backend.rpcCallsRejected); err != nil {
+		return backend, errors.Errorf(
+			"unable to register 'rpcCallsRejected' metric: " +
+				err.Error())
+	}
+
+	backend.rpcQueueDurationSecs = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "rpc_queue_duration_seconds",
+			Help:      "Time calls to the daemon wait for the free slot",
+			ConstLabels: prometheus.Labels{
+				metrics.NetLabel: net,
+			},
+		},
+		[]string{
+			assetLabel,
+			daemonLabel,
+		},
+	)
+
+	if err := prometheus.Register(backend.rpcQueueDurationSecs); err != nil {
+		return backend, errors.Errorf(
+			"unable to register 'rpcQueueDurationSecs' metric: " +
+				err.Error())
+	}
+
 	return backend, nil
 }
@@ -92,6 +92,11 @@ func (m Metric) BlockNumber(blockNumber int64) {
 	m.backend.BlockNumber(m.daemon, m.asset, blockNumber)
 }
 
+// CacheSize is used to report number of entries in the in-memory cache.
+func (m Metric) CacheSize(cache string, size int) {
+	m.backend.CacheSize(m.daemon, m.asset, cache, size)
+}
+
 // AddRequestDuration adds request duration metric. Supposed to be
 // called after `NewMetric` which defines `startTime`. Calculates
 // duration using `startTime` and now as end time.
@@ -24,6 +24,14 @@ const (
 // the connector metrics.
 type MetricsBackend interface {
 	AddError(request, severity string)
+
+	// AddSlowCall increases counter of the calls which exceeded the latency
+	// budget of the request.
+	AddSlowCall(request string)
+
+	// AddRateLimited increases counter of the calls which were rejected
+	// because caller exceeded the rate limit of the request.
+	AddRateLimited(request string)
 }
 
 // EmptyBackend is used as an empty metrics backend in order to avoid
@@ -31,6 +39,10 @@ type EmptyBackend struct{}
 
 func (b *EmptyBackend) AddError(request, severity string) {}
 
+func (b *EmptyBackend) AddSlowCall(request string) {}
+
+func (b *EmptyBackend) AddRateLimited(request string) {}
+
 // PrometheusBackend is the main subsystem metrics implementation. Uses
 // prometheus metrics singletons defined above.
 //
@@ -41,7 +53,9 @@ func (b *EmptyBackend) AddError(request, severity string) {}
 // NOTE: Non-pointer receiver made by intent to avoid conflict in the system
 // with parallel metrics report.
 type PrometheusBackend struct {
-	errorsTotal *prometheus.CounterVec
+	errorsTotal      *prometheus.CounterVec
+	slowCallsTotal   *prometheus.CounterVec
+	rateLimitedTotal *prometheus.CounterVec
 }
 
 // AddError increases error counter for the given method name.
@@ -61,6 +75,32 @@ func (m PrometheusBackend) AddError(method, severity string) {
 	).Add(1)
 }
 
+// AddSlowCall increases counter of the calls which exceeded the latency
+// budget of the given method.
+//
+// NOTE: Non-pointer receiver made by intent to avoid conflict in the system
+// with parallel metrics report.
+func (m PrometheusBackend) AddSlowCall(method string) {
+	m.slowCallsTotal.With(
+		prometheus.Labels{
+			requestLabel: method,
+		},
+	).Add(1)
+}
+
+// AddRateLimited increases counter of the calls which were rejected by the
+// rate limiter for the given method.
+//
+// NOTE: Non-pointer receiver made by intent to avoid conflict in the system
+// with parallel metrics report.
+func (m PrometheusBackend) AddRateLimited(method string) {
+	m.rateLimitedTotal.With(
+		prometheus.Labels{
+			requestLabel: method,
+		},
+	).Add(1)
+}
+
 // InitMetricsBackend creates subsystem metrics for specified
 // net. Creates and tries to register metrics singletons. If register was
 // already done, than function not returning error.
@@ -92,5 +132,53 @@ func InitMetricsBackend(net string) (MetricsBackend, error) {
 		}
 	}
 
+	backend.slowCallsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "slow_calls_total",
+			Help:      "Total requests which processing exceeded latency budget",
+			ConstLabels: prometheus.Labels{
+				metrics.NetLabel: net,
+			},
+		},
+		[]string{
+			requestLabel,
+		},
+	)
+
+	if err := prometheus.Register(backend.slowCallsTotal); err != nil {
+		// Skip returning error if we re-registered metric.
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return backend, errors.Errorf(
+				"unable to register 'slowCallsTotal' metric: " +
+					err.Error())
+		}
+	}
+
+	backend.rateLimitedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "rate_limited_total",
+			Help:      "Total requests which were rejected by the rate limiter",
+			ConstLabels: prometheus.Labels{
+				metrics.NetLabel: net,
+			},
+		},
+		[]string{
+			requestLabel,
+		},
+	)
+
+	if err := prometheus.Register(backend.rateLimitedTotal); err != nil {
+		// Skip returning error if we re-registered metric.
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return backend, errors.Errorf(
+				"unable to register 'rateLimitedTotal' metric: " +
+					err.Error())
+		}
+	}
+
 	return backend, nil
 }
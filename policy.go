@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/policy"
+)
+
+// policyNormalizer returns the normalizer of the policy destinations, which
+// normalizes the destination by every blockchain connector which accepts
+// it, the same way as the receipts of the sent payments are normalized.
+// Destination which isn't accepted by any connector, e.g. lightning
+// invoice or public key, is lowercased.
+func policyNormalizer(
+	blockchainConnectors map[connectors.Asset]connectors.BlockchainConnector) policy.Normalizer {
+
+	return func(destination string) []string {
+		var normalized []string
+		for _, c := range blockchainConnectors {
+			addressInfo, err := c.ValidateAddress(destination)
+			if err != nil {
+				continue
+			}
+
+			normalized = append(normalized, addressInfo.Address)
+		}
+
+		if len(normalized) == 0 {
+			normalized = append(normalized, strings.ToLower(destination))
+		}
+
+		return normalized
+	}
+}
@@ -0,0 +1,147 @@
+package policy
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+)
+
+// checkInterval is the minimal interval between the checks of the policy
+// file modification.
+const checkInterval = time.Second
+
+// Engine evaluates the policy file for every outgoing payment. File is
+// reloaded once it has been modified, so that rules could be changed
+// without restart, invalid file is reported and ignored, previous policy
+// stays in effect.
+type Engine struct {
+	path      string
+	normalize Normalizer
+
+	// checkedAt is the unix nano time of the last file check, used
+	// atomically.
+	checkedAt int64
+
+	mtx     sync.RWMutex
+	policy  *Policy
+	modTime time.Time
+}
+
+// A compile time check to ensure Engine implements the SendHook interface.
+var _ connectors.SendHook = (*Engine)(nil)
+
+// NewEngine loads the policy file and returns the engine which evaluates
+// it, destinations of the rules are normalized with the given normalizer.
+func NewEngine(path string, normalize Normalizer) (*Engine, error) {
+	e := &Engine{
+		path:      path,
+		normalize: normalize,
+		checkedAt: time.Now().UnixNano(),
+	}
+
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// load reads and parses the policy file.
+func (e *Engine) load() error {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return errors.Errorf("unable to read policy file: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(e.path)
+	if err != nil {
+		return errors.Errorf("unable to read policy file: %v", err)
+	}
+
+	policy, err := Parse(data, e.normalize)
+	if err != nil {
+		return errors.Errorf("invalid policy file(%v): %v", e.path, err)
+	}
+
+	e.mtx.Lock()
+	e.policy = policy
+	e.modTime = info.ModTime()
+	e.mtx.Unlock()
+
+	return nil
+}
+
+// reload reloads the policy file if it has been modified. Modification of
+// the file is checked not often than once in the check interval.
+func (e *Engine) reload() {
+	now := time.Now().UnixNano()
+	checkedAt := atomic.LoadInt64(&e.checkedAt)
+	if now-checkedAt < int64(checkInterval) ||
+		!atomic.CompareAndSwapInt64(&e.checkedAt, checkedAt, now) {
+		return
+	}
+
+	e.mtx.RLock()
+	modTime := e.modTime
+	e.mtx.RUnlock()
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		log.Errorf("unable to check policy file: %v", err)
+		return
+	}
+
+	if info.ModTime().Equal(modTime) {
+		return
+	}
+
+	if err := e.load(); err != nil {
+		log.Errorf("unable to reload policy, previous policy is kept: %v",
+			err)
+
+		// Remember the modification time of the invalid file, so that
+		// it isn't parsed and reported again until it is changed.
+		e.mtx.Lock()
+		e.modTime = info.ModTime()
+		e.mtx.Unlock()
+		return
+	}
+
+	log.Infof("Policy file(%v) has been reloaded", e.path)
+}
+
+// Policy returns the policy which is currently in effect.
+func (e *Engine) Policy() *Policy {
+	e.reload()
+
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+
+	return e.policy
+}
+
+// BeforeSend evaluates the policy for the payment.
+//
+// NOTE: Part of the connectors.SendHook interface.
+func (e *Engine) BeforeSend(intent *connectors.PaymentIntent) error {
+	decision := e.Policy().Evaluate(intent, time.Now())
+
+	switch decision.Action {
+	case Deny:
+		log.Infof("Payment(%v) of account(%v) is denied by rule(%v): %v",
+			intent.Method, intent.Account, decision.Rule, decision.Reason)
+		return &connectors.SendVetoError{Reason: decision.Reason}
+
+	case Hold:
+		log.Infof("Payment(%v) of account(%v) is held by rule(%v): %v",
+			intent.Method, intent.Account, decision.Rule, decision.Reason)
+		return &connectors.SendHoldError{Reason: decision.Reason}
+	}
+
+	return nil
+}
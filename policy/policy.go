@@ -0,0 +1,412 @@
+package policy
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/go-errors/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Action is the decision about the outgoing payment.
+type Action string
+
+const (
+	// Allow lets the payment through to the next checks.
+	Allow Action = "allow"
+
+	// Deny rejects the payment.
+	Deny Action = "deny"
+
+	// Hold rejects the payment as the one which should be reviewed by the
+	// operator before it is sent.
+	Hold Action = "hold"
+)
+
+// Rule is the rule of the policy file. Rule matches the payment if all of
+// its conditions are matching, empty condition matches every payment.
+type Rule struct {
+	// Name is the name of the rule, which is logged and returned along
+	// with the decision.
+	Name string `json:"name"`
+
+	// Action is the decision about the matching payment.
+	Action Action `json:"action"`
+
+	// Reason is the explanation of the decision which is returned to the
+	// sender, name of the rule is returned if it isn't specified.
+	Reason string `json:"reason"`
+
+	// Methods are the names of the methods through which payment is
+	// sent, e.g. SendPayment or SweepFunds.
+	Methods []string `json:"methods"`
+
+	// Assets are the acronyms of the crypto currencies, e.g. BTC.
+	Assets []string `json:"assets"`
+
+	// Media are the media of the payment, blockchain or lightning.
+	Media []string `json:"media"`
+
+	// Tenants are the ids of the API keys with which payment is sent,
+	// payments sent by the admin have empty tenant.
+	Tenants []string `json:"tenants"`
+
+	// Accounts are the identifiers of the accounts of the payment.
+	Accounts []string `json:"accounts"`
+
+	// Destinations matches the payment which sends to any of the given
+	// addresses, invoices or public keys.
+	Destinations []string `json:"destinations"`
+
+	// NotDestinations matches the payment which sends to any address
+	// which isn't listed, e.g. to deny payments outside of the whitelist.
+	NotDestinations []string `json:"not_destinations"`
+
+	// MinAmount matches the payment which overall amount is not less than
+	// the given one.
+	MinAmount string `json:"min_amount"`
+
+	// MaxAmount matches the payment which overall amount is not greater
+	// than the given one.
+	MaxAmount string `json:"max_amount"`
+
+	// Hours matches the payment which is sent within the UTC time range in
+	// form of HH:MM-HH:MM, range could cross the midnight, e.g.
+	// 22:00-06:00.
+	Hours string `json:"hours"`
+
+	// Weekdays matches the payment which is sent on the given UTC days,
+	// e.g. sat and sun.
+	Weekdays []string `json:"weekdays"`
+
+	minAmount    *decimal.Decimal
+	maxAmount    *decimal.Decimal
+	from, to     int
+	weekdays     map[time.Weekday]struct{}
+	tenants      map[string]struct{}
+	accounts     map[string]struct{}
+	destinations map[string]struct{}
+	allowed      map[string]struct{}
+}
+
+// Policy is the list of rules which are evaluated for every outgoing
+// payment in order, the first matching rule decides.
+type Policy struct {
+	// Default is the action for the payment which doesn't match any rule,
+	// it is allow if it isn't specified.
+	Default Action `json:"default"`
+
+	// Rules are the rules of the policy in order of evaluation.
+	Rules []*Rule `json:"rules"`
+}
+
+// Decision is the result of the policy evaluation.
+type Decision struct {
+	// Action is the decision about the payment.
+	Action Action
+
+	// Rule is the name of the matched rule, empty if default action is
+	// taken.
+	Rule string
+
+	// Reason is the explanation of the decision.
+	Reason string
+}
+
+// Normalizer returns the canonical forms of the destination, in which
+// receipts of the payments are passed to the policy, e.g. the address of
+// the every asset which accepts it. Destination is kept as it is if there
+// are no forms returned.
+type Normalizer func(destination string) []string
+
+// weekdays maps the short names of the days to their numbers.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Parse parses and validates the JSON policy. Destinations of the rules are
+// normalized with the given normalizer, if it is specified.
+func Parse(data []byte, normalize Normalizer) (*Policy, error) {
+	policy := &Policy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, errors.Errorf("unable to decode policy: %v", err)
+	}
+
+	switch policy.Default {
+	case "":
+		policy.Default = Allow
+	case Allow, Deny, Hold:
+	default:
+		return nil, errors.Errorf("unknown default action(%v)",
+			policy.Default)
+	}
+
+	for i, rule := range policy.Rules {
+		if rule.Name == "" {
+			rule.Name = "rule " + strconv.Itoa(i+1)
+		}
+
+		if err := rule.compile(normalize); err != nil {
+			return nil, errors.Errorf("invalid %v: %v", rule.Name, err)
+		}
+	}
+
+	return policy, nil
+}
+
+// compile validates the rule and parses its conditions.
+func (r *Rule) compile(normalize Normalizer) error {
+	switch r.Action {
+	case Allow, Deny, Hold:
+	default:
+		return errors.Errorf("unknown action(%v)", r.Action)
+	}
+
+	if r.MinAmount != "" {
+		amount, err := decimal.NewFromString(r.MinAmount)
+		if err != nil {
+			return errors.Errorf("invalid min amount(%v)", r.MinAmount)
+		}
+		r.minAmount = &amount
+	}
+
+	if r.MaxAmount != "" {
+		amount, err := decimal.NewFromString(r.MaxAmount)
+		if err != nil {
+			return errors.Errorf("invalid max amount(%v)", r.MaxAmount)
+		}
+		r.maxAmount = &amount
+	}
+
+	if r.Hours != "" {
+		var err error
+		r.from, r.to, err = parseHours(r.Hours)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(r.Weekdays) != 0 {
+		r.weekdays = make(map[time.Weekday]struct{}, len(r.Weekdays))
+		for _, name := range r.Weekdays {
+			day, ok := weekdays[strings.ToLower(name)]
+			if !ok {
+				return errors.Errorf("unknown weekday(%v)", name)
+			}
+			r.weekdays[day] = struct{}{}
+		}
+	}
+
+	r.tenants = newSet(r.Tenants)
+	r.accounts = newSet(r.Accounts)
+	r.destinations = newDestinationSet(r.Destinations, normalize)
+	r.allowed = newDestinationSet(r.NotDestinations, normalize)
+
+	return nil
+}
+
+// parseHours parses the time range in form of HH:MM-HH:MM, and returns its
+// bounds in minutes of the day.
+func parseHours(value string) (int, int, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return 0, 0, errors.Errorf("hours(%v) should be in form of "+
+			"HH:MM-HH:MM", value)
+	}
+
+	var bounds [2]int
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, errors.Errorf("hours(%v) should be in form of "+
+				"HH:MM-HH:MM", value)
+		}
+		bounds[i] = t.Hour()*60 + t.Minute()
+	}
+
+	return bounds[0], bounds[1], nil
+}
+
+// newSet returns the set of the values, nil if there are no values.
+func newSet(values []string) map[string]struct{} {
+	if len(values) == 0 {
+		return nil
+	}
+
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+
+	return set
+}
+
+// newDestinationSet returns the set of the destinations along with their
+// canonical forms, nil if there are no destinations.
+func newDestinationSet(destinations []string,
+	normalize Normalizer) map[string]struct{} {
+
+	set := newSet(destinations)
+	if normalize == nil {
+		return set
+	}
+
+	for _, destination := range destinations {
+		for _, normalized := range normalize(destination) {
+			set[normalized] = struct{}{}
+		}
+	}
+
+	return set
+}
+
+// contains returns true if list is empty or contains the value, values are
+// compared case insensitively.
+func contains(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// intentAmount returns the overall amount of the payment outputs. Sender
+// puts the amount of the lightning invoice in the output if it isn't
+// specified in the request, outputs which are still without the amount are
+// counted as zero.
+func intentAmount(intent *connectors.PaymentIntent) decimal.Decimal {
+	total := decimal.Zero
+	for _, output := range intent.Outputs {
+		amount, err := decimal.NewFromString(output.Amount)
+		if err != nil {
+			continue
+		}
+		total = total.Add(amount)
+	}
+
+	return total
+}
+
+// matches returns true if all conditions of the rule are matching the
+// payment sent at the given time.
+func (r *Rule) matches(intent *connectors.PaymentIntent,
+	amount decimal.Decimal, now time.Time) bool {
+
+	if !contains(r.Methods, intent.Method) ||
+		!contains(r.Assets, string(intent.Asset)) ||
+		!contains(r.Media, string(intent.Media)) {
+		return false
+	}
+
+	// Tenants and accounts are case sensitive identifiers.
+	if r.tenants != nil {
+		if _, ok := r.tenants[intent.Tenant]; !ok {
+			return false
+		}
+	}
+
+	if r.accounts != nil {
+		if _, ok := r.accounts[intent.Account]; !ok {
+			return false
+		}
+	}
+
+	if r.destinations != nil {
+		found := false
+		for _, output := range intent.Outputs {
+			if _, ok := r.destinations[output.Address]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if r.allowed != nil {
+		found := false
+		for _, output := range intent.Outputs {
+			if _, ok := r.allowed[output.Address]; !ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if r.minAmount != nil && amount.LessThan(*r.minAmount) {
+		return false
+	}
+
+	if r.maxAmount != nil && amount.GreaterThan(*r.maxAmount) {
+		return false
+	}
+
+	now = now.UTC()
+	if r.Hours != "" {
+		minute := now.Hour()*60 + now.Minute()
+		if r.from <= r.to {
+			if minute < r.from || minute >= r.to {
+				return false
+			}
+		} else if minute < r.from && minute >= r.to {
+			return false
+		}
+	}
+
+	if r.weekdays != nil {
+		if _, ok := r.weekdays[now.Weekday()]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Evaluate returns the decision of the first rule which matches the
+// payment sent at the given time, or the default action if there is no
+// such rule.
+func (p *Policy) Evaluate(intent *connectors.PaymentIntent,
+	now time.Time) Decision {
+
+	amount := intentAmount(intent)
+	for _, rule := range p.Rules {
+		if !rule.matches(intent, amount, now) {
+			continue
+		}
+
+		reason := rule.Reason
+		if reason == "" {
+			reason = "payment matches policy rule '" + rule.Name + "'"
+		}
+
+		return Decision{
+			Action: rule.Action,
+			Rule:   rule.Name,
+			Reason: reason,
+		}
+	}
+
+	return Decision{
+		Action: p.Default,
+		Reason: "payment doesn't match any policy rule",
+	}
+}
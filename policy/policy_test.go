@@ -0,0 +1,315 @@
+package policy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+)
+
+const testPolicy = `{
+	"default": "allow",
+	"rules": [
+		{
+			"name": "whitelist",
+			"action": "deny",
+			"reason": "destination isn't whitelisted",
+			"tenants": ["shop"],
+			"not_destinations": ["addr-1", "addr-2"]
+		},
+		{
+			"name": "night",
+			"action": "hold",
+			"assets": ["btc"],
+			"min_amount": "1",
+			"hours": "22:00-06:00"
+		},
+		{
+			"name": "weekend",
+			"action": "hold",
+			"min_amount": "10",
+			"weekdays": ["sat", "sun"]
+		},
+		{
+			"action": "deny",
+			"methods": ["SweepFunds"],
+			"max_amount": "0.001"
+		}
+	]
+}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		valid  bool
+	}{
+		{"empty", `{}`, true},
+		{"rules", testPolicy, true},
+		{"malformed", `{"rules": [`, false},
+		{"default", `{"default": "maybe"}`, false},
+		{"action", `{"rules": [{"action": "block"}]}`, false},
+		{"amount", `{"rules": [{"action": "deny", "min_amount": "x"}]}`,
+			false},
+		{"hours", `{"rules": [{"action": "deny", "hours": "22-06"}]}`,
+			false},
+		{"weekdays", `{"rules": [{"action": "deny", "weekdays": ["fun"]}]}`,
+			false},
+	}
+
+	for _, test := range tests {
+		policy, err := Parse([]byte(test.policy), nil)
+		if test.valid && err != nil {
+			t.Fatalf("%v: unable to parse policy: %v", test.name, err)
+		} else if !test.valid && err == nil {
+			t.Fatalf("%v: invalid policy is parsed", test.name)
+		}
+
+		if test.name == "empty" && policy.Default != Allow {
+			t.Fatalf("wrong default action: %v", policy.Default)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	policy, err := Parse([]byte(testPolicy), nil)
+	if err != nil {
+		t.Fatalf("unable to parse policy: %v", err)
+	}
+
+	// 2019-06-05 is wednesday.
+	day := time.Date(2019, 6, 5, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2019, 6, 5, 23, 30, 0, 0, time.UTC)
+	morning := time.Date(2019, 6, 6, 5, 59, 0, 0, time.UTC)
+	weekend := time.Date(2019, 6, 8, 12, 0, 0, 0, time.UTC)
+
+	intent := func(method, tenant string, asset connectors.Asset,
+		outputs ...*connectors.PaymentOutput) *connectors.PaymentIntent {
+		return &connectors.PaymentIntent{
+			Method:  method,
+			Tenant:  tenant,
+			Asset:   asset,
+			Media:   connectors.Blockchain,
+			Outputs: outputs,
+		}
+	}
+
+	output := func(address, amount string) *connectors.PaymentOutput {
+		return &connectors.PaymentOutput{Address: address, Amount: amount}
+	}
+
+	tests := []struct {
+		name   string
+		intent *connectors.PaymentIntent
+		now    time.Time
+		action Action
+		rule   string
+	}{
+		{
+			name: "whitelisted",
+			intent: intent("SendPayment", "shop", connectors.ETH,
+				output("addr-1", "5")),
+			now:    day,
+			action: Allow,
+		},
+		{
+			name: "not whitelisted",
+			intent: intent("SendPayment", "shop", connectors.ETH,
+				output("addr-1", "1"), output("addr-3", "1")),
+			now:    day,
+			action: Deny,
+			rule:   "whitelist",
+		},
+		{
+			name: "other tenant",
+			intent: intent("SendPayment", "exchange", connectors.ETH,
+				output("addr-3", "1")),
+			now:    day,
+			action: Allow,
+		},
+		{
+			name: "night",
+			intent: intent("SendPayment", "", connectors.BTC,
+				output("addr-3", "0.5"), output("addr-4", "0.5")),
+			now:    night,
+			action: Hold,
+			rule:   "night",
+		},
+		{
+			name: "morning",
+			intent: intent("SendPayment", "", connectors.BTC,
+				output("addr-3", "1")),
+			now:    morning,
+			action: Hold,
+			rule:   "night",
+		},
+		{
+			name: "night below amount",
+			intent: intent("SendPayment", "", connectors.BTC,
+				output("addr-3", "0.5")),
+			now:    night,
+			action: Allow,
+		},
+		{
+			name: "night other asset",
+			intent: intent("SendPayment", "", connectors.ETH,
+				output("addr-3", "5")),
+			now:    night,
+			action: Allow,
+		},
+		{
+			name: "weekend",
+			intent: intent("SendPayment", "", connectors.ETH,
+				output("addr-3", "10")),
+			now:    weekend,
+			action: Hold,
+			rule:   "weekend",
+		},
+		{
+			name: "dust sweep",
+			intent: intent("SweepFunds", "", connectors.BTC,
+				output("addr-3", "0.0001")),
+			now:    day,
+			action: Deny,
+			rule:   "rule 4",
+		},
+	}
+
+	for _, test := range tests {
+		decision := policy.Evaluate(test.intent, test.now)
+		if decision.Action != test.action || decision.Rule != test.rule {
+			t.Fatalf("%v: wrong decision: %v", test.name, decision)
+		}
+	}
+}
+
+func TestNormalizedDestinations(t *testing.T) {
+	// Destinations are normalized the same way as receipts, e.g. invoice
+	// is lowercased.
+	normalize := func(destination string) []string {
+		return []string{strings.ToLower(destination)}
+	}
+
+	policy, err := Parse([]byte(`{
+		"rules": [
+			{
+				"name": "blacklist",
+				"action": "deny",
+				"destinations": ["LNBC1INVOICE"]
+			},
+			{
+				"name": "whitelist",
+				"action": "deny",
+				"not_destinations": ["Addr-1"]
+			}
+		]
+	}`), normalize)
+	if err != nil {
+		t.Fatalf("unable to parse policy: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		address string
+		rule    string
+	}{
+		{"blacklisted", "lnbc1invoice", "blacklist"},
+		{"whitelisted", "addr-1", ""},
+		{"whitelisted as written", "Addr-1", ""},
+		{"not whitelisted", "addr-2", "whitelist"},
+	}
+
+	for _, test := range tests {
+		decision := policy.Evaluate(&connectors.PaymentIntent{
+			Method: "SendPayment",
+			Asset:  connectors.BTC,
+			Media:  connectors.Lightning,
+			Outputs: []*connectors.PaymentOutput{
+				{Address: test.address, Amount: "1"},
+			},
+		}, time.Now())
+		if decision.Rule != test.rule {
+			t.Fatalf("%v: wrong decision: %v", test.name, decision)
+		}
+	}
+}
+
+func TestEngineReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policy")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "policy.json")
+
+	var e *Engine
+	write := func(policy string, modTime time.Time) {
+		t.Helper()
+
+		if err := ioutil.WriteFile(path, []byte(policy), 0600); err != nil {
+			t.Fatalf("unable to write policy: %v", err)
+		}
+
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("unable to change modification time: %v", err)
+		}
+
+		// Force the check of the file on the next payment.
+		if e != nil {
+			e.checkedAt = 0
+		}
+	}
+
+	intent := &connectors.PaymentIntent{
+		Method: "SendPayment",
+		Asset:  connectors.BTC,
+		Media:  connectors.Blockchain,
+		Outputs: []*connectors.PaymentOutput{
+			{Address: "addr", Amount: "1"},
+		},
+	}
+
+	now := time.Now()
+	write(`{"rules": [{"action": "hold"}]}`, now)
+
+	e, err = NewEngine(path, nil)
+	if err != nil {
+		t.Fatalf("unable to create engine: %v", err)
+	}
+
+	if _, ok := e.BeforeSend(intent).(*connectors.SendHoldError); !ok {
+		t.Fatalf("payment isn't held")
+	}
+
+	write(`{"rules": [{"action": "deny", "reason": "frozen"}]}`,
+		now.Add(time.Minute))
+
+	err = e.BeforeSend(intent)
+	if veto, ok := err.(*connectors.SendVetoError); !ok ||
+		veto.Reason != "frozen" {
+		t.Fatalf("payment isn't denied: %v", err)
+	}
+
+	// Invalid policy is ignored, previous one stays in effect.
+	write(`{"rules": [`, now.Add(2*time.Minute))
+
+	if _, ok := e.BeforeSend(intent).(*connectors.SendVetoError); !ok {
+		t.Fatalf("previous policy isn't kept")
+	}
+
+	write(`{}`, now.Add(3*time.Minute))
+
+	if err := e.BeforeSend(intent); err != nil {
+		t.Fatalf("payment isn't allowed: %v", err)
+	}
+
+	_, err = NewEngine(filepath.Join(dir, "missing.json"), nil)
+	if err == nil {
+		t.Fatalf("engine is created without policy file")
+	}
+}
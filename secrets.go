@@ -0,0 +1,115 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+const (
+	// secretEnvPrefix is the prefix of the environment variables from which
+	// secret config options are taken, e.g. CONNECTOR_BITCOIN_PASSWORD.
+	secretEnvPrefix = "CONNECTOR_"
+
+	// secretFileEnvSuffix is the suffix of the environment variable with
+	// the path to the file which contains the secret, e.g.
+	// CONNECTOR_BITCOIN_PASSWORD_FILE.
+	secretFileEnvSuffix = "_FILE"
+)
+
+// secretEnv returns the name of the environment variable of the config
+// option, e.g. CONNECTOR_BITCOINCASH_PASSWORD for "bitcoincash.password".
+func secretEnv(option string) string {
+	return secretEnvPrefix + strings.ToUpper(
+		strings.Replace(option, ".", "_", -1))
+}
+
+// loadSecrets sets the secret options, which haven't been given in the
+// config file or on the command line, from the environment variables.
+// Secret is taken either from the variable itself, or from the file which
+// path is given in the variable with the _FILE suffix, so that secrets
+// mounted by the orchestrator are never written in plain text config.
+//
+// NOTE: Config isn't reloaded while server is running, that is why secrets
+// are resolved once on the start. Only the daemon passwords given in the
+// files are read again once the files are rotated.
+func (c *config) loadSecrets() error {
+	return loadSecrets("", reflect.ValueOf(c).Elem())
+}
+
+// loadSecrets walks through the fields of the config struct, and sets the
+// empty options marked as secret from the environment.
+func loadSecrets(namespace string, v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value := v.Field(i)
+
+		// Nested groups of options, e.g. config of the bitcoin daemon,
+		// are left nil by the parser if they are not configured.
+		if group := field.Tag.Get("namespace"); group != "" {
+			if value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+
+			if err := loadSecrets(namespace+group+".", value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := field.Tag.Get("long")
+		if field.Tag.Get("secret") == "" || name == "" ||
+			value.Kind() != reflect.String || value.String() != "" {
+			continue
+		}
+
+		env := secretEnv(namespace + name)
+		if secret, ok := os.LookupEnv(env); ok {
+			value.SetString(secret)
+			continue
+		}
+
+		path, ok := os.LookupEnv(env + secretFileEnvSuffix)
+		if !ok || path == "" {
+			continue
+		}
+
+		// Option which has the file counterpart, e.g. password file of
+		// the bitcoin daemon, is passed as the path, so that secret is
+		// reloaded by the client once it is rotated.
+		if file := fileOption(v, name+"file"); file.IsValid() &&
+			file.String() == "" {
+			file.SetString(cleanAndExpandPath(path))
+			continue
+		}
+
+		data, err := ioutil.ReadFile(cleanAndExpandPath(path))
+		if err != nil {
+			return errors.Errorf("unable to read secret of option %v "+
+				"from %v: %v", namespace+name, env+secretFileEnvSuffix, err)
+		}
+
+		value.SetString(strings.TrimSpace(string(data)))
+	}
+
+	return nil
+}
+
+// fileOption returns the string option with the given name from the same
+// group, invalid value if there is no such option.
+func fileOption(v reflect.Value, name string) reflect.Value {
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("long") == name &&
+			v.Field(i).Kind() == reflect.String {
+			return v.Field(i)
+		}
+	}
+
+	return reflect.Value{}
+}
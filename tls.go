@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+const (
+	// autogenCertOrganization is the organization of the self-signed
+	// certificate, it is used to distinguish generated certificates, which
+	// could be rotated, from the ones provided by the operator.
+	autogenCertOrganization = "connector autogenerated cert"
+
+	// autogenCertValidity is the validity period of the self-signed
+	// certificate.
+	autogenCertValidity = 14 * 30 * 24 * time.Hour
+
+	// autogenCertRenewBefore is the time before the expiration at which
+	// self-signed certificate is regenerated on the start.
+	autogenCertRenewBefore = 30 * 24 * time.Hour
+)
+
+// ensureTLSCert generates self-signed certificate and its key if they don't
+// exist, or if the previously generated certificate is close to expiration
+// or doesn't cover the given hosts. Certificates which are not generated by
+// the connector are left untouched.
+func ensureTLSCert(certPath, keyPath string, extraIPs,
+	extraDomains []string) error {
+
+	if fileExists(certPath) && fileExists(keyPath) {
+		reason, err := autogenCertRenewReason(certPath, extraIPs, extraDomains)
+		if err != nil {
+			return err
+		}
+
+		if reason == "" {
+			return nil
+		}
+
+		mainLog.Infof("Rotating self-signed TLS certificate: %v", reason)
+	} else {
+		mainLog.Infof("Generating self-signed TLS certificate(%v) and "+
+			"key(%v)", certPath, keyPath)
+	}
+
+	return genCertPair(certPath, keyPath, extraIPs, extraDomains)
+}
+
+// autogenCertRenewReason returns the reason why certificate should be
+// regenerated, or empty string if it is still valid.
+func autogenCertRenewReason(certPath string, extraIPs,
+	extraDomains []string) (string, error) {
+
+	data, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return "", errors.Errorf("unable to read TLS certificate: %v", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", errors.Errorf("unable to decode TLS certificate(%v)",
+			certPath)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", errors.Errorf("unable to parse TLS certificate: %v", err)
+	}
+
+	organization := cert.Subject.Organization
+	if len(organization) != 1 || organization[0] != autogenCertOrganization {
+		return "", nil
+	}
+
+	if time.Now().Add(autogenCertRenewBefore).After(cert.NotAfter) {
+		return "certificate expires at " + cert.NotAfter.String(), nil
+	}
+
+	ips, domains := certHosts(extraIPs, extraDomains)
+	for _, ip := range ips {
+		if !certHasIP(cert, ip) {
+			return "ip " + ip.String() + " isn't covered", nil
+		}
+	}
+
+	for _, domain := range domains {
+		if !certHasDomain(cert, domain) {
+			return "domain " + domain + " isn't covered", nil
+		}
+	}
+
+	// Previously generated certificates covered all interfaces of the
+	// host, they are rotated to cover only the configured hosts.
+	if len(cert.IPAddresses) != len(ips) || len(cert.DNSNames) != len(domains) {
+		return "certificate covers hosts which aren't configured", nil
+	}
+
+	return "", nil
+}
+
+// certHasIP returns true if ip is in the certificate alternative names.
+func certHasIP(cert *x509.Certificate, ip net.IP) bool {
+	for _, certIP := range cert.IPAddresses {
+		if certIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// certHasDomain returns true if domain is in the certificate alternative
+// names.
+func certHasDomain(cert *x509.Certificate, domain string) bool {
+	for _, certDomain := range cert.DNSNames {
+		if certDomain == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// certHosts returns ips and domains which should be covered by the
+// certificate: loopback and the ones given by the operator. Addresses of
+// the interfaces aren't added, so that certificate doesn't disclose the
+// internal network and isn't valid for the hosts operator didn't choose.
+func certHosts(extraIPs, extraDomains []string) ([]net.IP, []string) {
+	var ips []net.IP
+	seenIPs := make(map[string]struct{})
+	for _, ip := range append([]string{"127.0.0.1", "::1"}, extraIPs...) {
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			continue
+		}
+
+		if _, ok := seenIPs[parsed.String()]; ok {
+			continue
+		}
+		seenIPs[parsed.String()] = struct{}{}
+
+		ips = append(ips, parsed)
+	}
+
+	var domains []string
+	seenDomains := make(map[string]struct{})
+	for _, domain := range append([]string{"localhost"}, extraDomains...) {
+		if _, ok := seenDomains[domain]; ok {
+			continue
+		}
+		seenDomains[domain] = struct{}{}
+
+		domains = append(domains, domain)
+	}
+
+	return ips, domains
+}
+
+// genCertPair generates self-signed ECDSA certificate and its key, and
+// writes them in PEM format in the given paths.
+func genCertPair(certPath, keyPath string, extraIPs,
+	extraDomains []string) error {
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return errors.Errorf("unable to generate serial number: %v", err)
+	}
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return errors.Errorf("unable to generate key: %v", err)
+	}
+
+	ips, domains := certHosts(extraIPs, extraDomains)
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{autogenCertOrganization},
+			CommonName:   domains[len(domains)-1],
+		},
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(autogenCertValidity),
+
+		KeyUsage: x509.KeyUsageKeyEncipherment |
+			x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+
+		DNSNames:    domains,
+		IPAddresses: ips,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template,
+		&template, &priv.PublicKey, priv)
+	if err != nil {
+		return errors.Errorf("unable to create certificate: %v", err)
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return errors.Errorf("unable to encode key: %v", err)
+	}
+
+	var certBuf, keyBuf bytes.Buffer
+	err = pem.Encode(&certBuf, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+	if err != nil {
+		return errors.Errorf("unable to encode certificate: %v", err)
+	}
+
+	err = pem.Encode(&keyBuf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+	if err != nil {
+		return errors.Errorf("unable to encode key: %v", err)
+	}
+
+	for _, path := range []string{certPath, keyPath} {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return errors.Errorf("unable to create TLS dir: %v", err)
+		}
+	}
+
+	if err := ioutil.WriteFile(keyPath, keyBuf.Bytes(), 0600); err != nil {
+		return errors.Errorf("unable to write TLS key: %v", err)
+	}
+
+	if err := ioutil.WriteFile(certPath, certBuf.Bytes(), 0644); err != nil {
+		return errors.Errorf("unable to write TLS certificate: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+)
+
+// defaultRetryInterval is the time after which delivery of the undelivered
+// events is retried.
+var defaultRetryInterval = time.Minute
+
+// WatchDispatcher delivers stored watch events in the background, so that
+// unavailable listener doesn't block the sync of the connectors. Events
+// which failed to be delivered stay undelivered in the store and are
+// retried periodically, including those which were left after restart.
+type WatchDispatcher struct {
+	store    connectors.WatchStore
+	notifier connectors.WatchNotifier
+
+	retryInterval time.Duration
+
+	wakeup chan struct{}
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// Runtime check to ensure that WatchDispatcher implements
+// connectors.WatchNotifier interface.
+var _ connectors.WatchNotifier = (*WatchDispatcher)(nil)
+
+// NewWatchDispatcher creates new instance of the dispatcher, which sends
+// undelivered events of the store with the given notifier.
+func NewWatchDispatcher(store connectors.WatchStore,
+	notifier connectors.WatchNotifier) *WatchDispatcher {
+	return &WatchDispatcher{
+		store:         store,
+		notifier:      notifier,
+		retryInterval: defaultRetryInterval,
+		wakeup:        make(chan struct{}, 1),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start launches the delivery goroutine.
+func (d *WatchDispatcher) Start() {
+	d.wg.Add(1)
+	go d.deliveryHandler()
+}
+
+// Stop stops the delivery goroutine and waits for it to exit.
+func (d *WatchDispatcher) Stop() {
+	close(d.quit)
+	d.wg.Wait()
+}
+
+// NotifyWatchEvent wakes up the delivery goroutine, event itself is read
+// from the store, so that it is delivered even if this call is lost.
+//
+// NOTE: Part of the connectors.WatchNotifier interface.
+func (d *WatchDispatcher) NotifyWatchEvent(event *connectors.WatchEvent) error {
+	select {
+	case d.wakeup <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// deliveryHandler delivers undelivered events on wakeup and periodically
+// retries the failed ones.
+//
+// NOTE: Should be run as goroutine.
+func (d *WatchDispatcher) deliveryHandler() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverEvents()
+
+		select {
+		case <-d.wakeup:
+		case <-ticker.C:
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// deliverEvents sends undelivered events in the order they were detected,
+// and stops on the first failure, so that listener receives them in order.
+func (d *WatchDispatcher) deliverEvents() {
+	events, err := d.store.ListUndeliveredWatchEvents()
+	if err != nil {
+		log.Errorf("Unable to list undelivered watch events: %v", err)
+		return
+	}
+
+	for _, event := range events {
+		select {
+		case <-d.quit:
+			return
+		default:
+		}
+
+		if err := d.notifier.NotifyWatchEvent(event); err != nil {
+			log.Errorf("Unable to deliver watch event(%v), retry in %v: %v",
+				event.EventID, d.retryInterval, err)
+			return
+		}
+
+		if err := d.store.MarkWatchEventDelivered(event.EventID); err != nil {
+			log.Errorf("Unable to mark watch event(%v) as delivered: %v",
+				event.EventID, err)
+			return
+		}
+	}
+}
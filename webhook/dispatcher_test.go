@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/db/sqlite"
+	"github.com/shopspring/decimal"
+)
+
+// flakyNotifier fails the first notifications and reports delivered
+// events in the channel.
+type flakyNotifier struct {
+	failures  int
+	delivered chan string
+}
+
+func (n *flakyNotifier) NotifyWatchEvent(event *connectors.WatchEvent) error {
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("webhook unavailable")
+	}
+
+	n.delivered <- event.EventID
+	return nil
+}
+
+func TestWatchDispatcherRetry(t *testing.T) {
+	db, clear, err := sqlite.MakeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create test database: %v", err)
+	}
+	defer clear()
+
+	store := sqlite.NewWatchStore(db)
+	notifier := &flakyNotifier{
+		failures:  1,
+		delivered: make(chan string, 1),
+	}
+
+	dispatcher := NewWatchDispatcher(store, notifier)
+	dispatcher.retryInterval = 10 * time.Millisecond
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	event := &connectors.WatchEvent{
+		CreatedAt: 1,
+		Group:     "attacker",
+		Asset:     connectors.BTC,
+		Address:   "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+		Direction: connectors.Incoming,
+		Amount:    decimal.New(15, -1),
+		TxID:      "txid",
+	}
+
+	// Failed delivery shouldn't be returned to the connector.
+	if _, err := connectors.ProcessWatchEvent(store, dispatcher, event); err != nil {
+		t.Fatalf("unable to process event: %v", err)
+	}
+
+	select {
+	case eventID := <-notifier.delivered:
+		if eventID != event.EventID {
+			t.Fatalf("wrong event delivered: %v", eventID)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("event hasn't been redelivered")
+	}
+
+	// Give dispatcher time to mark the event.
+	for i := 0; i < 100; i++ {
+		events, err := store.ListUndeliveredWatchEvents()
+		if err != nil {
+			t.Fatalf("unable to list undelivered events: %v", err)
+		}
+
+		if len(events) == 0 {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("event should be marked as delivered")
+}
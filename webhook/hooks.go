@@ -0,0 +1,378 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/identity"
+	"github.com/go-errors/errors"
+)
+
+const (
+	// maxHookReason is the maximum length of the hook response which is
+	// used as the reason of the veto.
+	maxHookReason = 256
+
+	// maxNotifiedPayments is the number of the latest payments for which
+	// the post-send hook has been called, which are remembered so that
+	// repeated saves of the finished payment don't trigger the hook again.
+	maxNotifiedPayments = 1000
+)
+
+// hook is the operator provided handler, which is either the URL on which
+// JSON body is posted, or the executable which receives JSON body on the
+// stdin.
+type hook struct {
+	target  string
+	timeout time.Duration
+	client  *http.Client
+	signer  *identity.Key
+}
+
+func newHook(target string, timeout time.Duration, dial common.DialFunc,
+	signer *identity.Key) *hook {
+	if target == "" {
+		return nil
+	}
+
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	client := newClient(dial)
+	client.Timeout = timeout
+
+	return &hook{
+		target:  target,
+		timeout: timeout,
+		client:  client,
+		signer:  signer,
+	}
+}
+
+// isHTTPHook returns true if hook target is the URL.
+func isHTTPHook(target string) bool {
+	return strings.HasPrefix(target, "http://") ||
+		strings.HasPrefix(target, "https://")
+}
+
+// call passes body to the hook. If hook has rejected the body, i.e. URL has
+// responded with non 2xx status or executable has exited with non zero
+// code, *connectors.SendVetoError is returned with the response as the
+// reason.
+func (h *hook) call(body []byte) error {
+	if isHTTPHook(h.target) {
+		return h.post(body)
+	}
+
+	return h.exec(body)
+}
+
+func (h *hook) post(body []byte) error {
+	req, err := http.NewRequest("POST", h.target, bytes.NewReader(body))
+	if err != nil {
+		return errors.Errorf("unable to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := signRequest(req, body, h.signer); err != nil {
+		return err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return errors.Errorf("unable to post to hook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	reason, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxHookReason))
+	return &connectors.SendVetoError{
+		Reason: hookReason(reason, resp.Status),
+	}
+}
+
+func (h *hook) exec(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.target)
+	cmd.Stdin = bytes.NewReader(body)
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return errors.Errorf("hook hasn't finished in %v", h.timeout)
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if len(output) > maxHookReason {
+			output = output[:maxHookReason]
+		}
+
+		return &connectors.SendVetoError{
+			Reason: hookReason(output, exitErr.Error()),
+		}
+	}
+
+	if err != nil {
+		return errors.Errorf("unable to run hook: %v", err)
+	}
+
+	return nil
+}
+
+// hookReason returns the response of the hook, or the fallback if hook
+// hasn't returned anything.
+func hookReason(response []byte, fallback string) string {
+	reason := strings.TrimSpace(string(response))
+	if reason == "" {
+		return fallback
+	}
+
+	return reason
+}
+
+// SendHooks calls the operator provided hooks before the outgoing payment
+// is broadcasted, so that it could be vetoed by the custom policy, and
+// after the outgoing payment is finished, so that downstream automation
+// could be triggered.
+type SendHooks struct {
+	preSend  *hook
+	postSend *hook
+
+	payments connectors.PaymentsSubscriber
+	events   connectors.PaymentEventsStore
+
+	// notified is the set of the payments for which post-send hook has
+	// been called, order is used to evict the oldest of them.
+	notified map[string]struct{}
+	order    []string
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Runtime check to ensure that SendHooks implements connectors.SendHook
+// interface.
+var _ connectors.SendHook = (*SendHooks)(nil)
+
+// NewSendHooks creates new instance of the hooks. Hook is either http(s)
+// URL, on which JSON body is posted, or path to the executable, which
+// receives JSON body on the stdin. Empty hook is disabled. Finished
+// payments are received from the payments subscriber, and their delivery
+// to the post-send hook is recorded in the timeline of the payment, if
+// events store is specified. If dial function is specified it is used to
+// establish connections, e.g. through the proxy. If signer is specified,
+// bodies are signed the same way as the watch events.
+func NewSendHooks(preSend, postSend string, timeout time.Duration,
+	payments connectors.PaymentsSubscriber,
+	events connectors.PaymentEventsStore, dial common.DialFunc,
+	signer *identity.Key) *SendHooks {
+	return &SendHooks{
+		preSend:  newHook(preSend, timeout, dial, signer),
+		postSend: newHook(postSend, timeout, dial, signer),
+		payments: payments,
+		events:   events,
+		notified: make(map[string]struct{}),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine which calls the post-send hook, if it is
+// specified.
+func (h *SendHooks) Start() {
+	if h.postSend == nil || h.payments == nil {
+		return
+	}
+
+	// Subscription is made before the start returns, so that payments
+	// which are finished right after it are not missed.
+	h.wg.Add(1)
+	go h.paymentsHandler(h.payments.SubscribePayments())
+}
+
+// Stop stops the goroutines and waits for the pending hook calls.
+func (h *SendHooks) Stop() {
+	close(h.quit)
+	h.wg.Wait()
+}
+
+type paymentIntent struct {
+	Method  string          `json:"method"`
+	Tenant  string          `json:"tenant"`
+	Asset   string          `json:"asset"`
+	Media   string          `json:"media"`
+	Outputs []paymentOutput `json:"outputs"`
+	Memo    string          `json:"memo"`
+	Account string          `json:"account"`
+}
+
+type paymentOutput struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+}
+
+// BeforeSend calls pre-send hook, payment is allowed only if hook has
+// accepted it. Failure to reach the hook also vetoes the payment, so that
+// policy couldn't be bypassed by making hook unavailable.
+//
+// NOTE: Part of the connectors.SendHook interface.
+func (h *SendHooks) BeforeSend(intent *connectors.PaymentIntent) error {
+	if h.preSend == nil {
+		return nil
+	}
+
+	outputs := make([]paymentOutput, len(intent.Outputs))
+	for i, output := range intent.Outputs {
+		outputs[i] = paymentOutput{
+			Address: output.Address,
+			Amount:  output.Amount,
+		}
+	}
+
+	body, err := json.Marshal(&paymentIntent{
+		Method:  intent.Method,
+		Tenant:  intent.Tenant,
+		Asset:   string(intent.Asset),
+		Media:   string(intent.Media),
+		Outputs: outputs,
+		Memo:    intent.Memo,
+		Account: intent.Account,
+	})
+	if err != nil {
+		return errors.Errorf("unable to encode payment: %v", err)
+	}
+
+	if err := h.preSend.call(body); err != nil {
+		log.Warnf("Payment of asset(%v), method(%v), tenant(%v) is "+
+			"rejected by pre-send hook: %v", intent.Asset, intent.Method,
+			intent.Tenant, err)
+		return err
+	}
+
+	return nil
+}
+
+type sentPayment struct {
+	PaymentID string `json:"payment_id"`
+	UpdatedAt int64  `json:"updated_at"`
+	Status    string `json:"status"`
+	System    string `json:"system"`
+	Asset     string `json:"asset"`
+	Media     string `json:"media"`
+	Receipt   string `json:"receipt"`
+	TxID      string `json:"tx_id"`
+	Amount    string `json:"amount"`
+	Fee       string `json:"fee"`
+	Memo      string `json:"memo"`
+}
+
+// paymentsHandler calls post-send hook for every outgoing payment which is
+// finished. Hook is called in the background and its failures are only
+// logged.
+//
+// NOTE: Should be run as goroutine.
+func (h *SendHooks) paymentsHandler(
+	subscription *connectors.PaymentsSubscription) {
+	defer h.wg.Done()
+
+	for {
+		select {
+		case payment, ok := <-subscription.Updates:
+			if !ok {
+				// Subscription is closed if we haven't kept up with
+				// the updates, in this case we are starting over.
+				log.Warnf("Payments subscription of the post-send " +
+					"hook is closed, resubscribing")
+				subscription = h.payments.SubscribePayments()
+				continue
+			}
+
+			h.handlePayment(payment)
+
+		case <-h.quit:
+			subscription.Cancel()
+			return
+		}
+	}
+}
+
+func (h *SendHooks) handlePayment(payment *connectors.Payment) {
+	if payment.Direction != connectors.Outgoing {
+		return
+	}
+
+	if payment.Status != connectors.Completed &&
+		payment.Status != connectors.Failed {
+		return
+	}
+
+	if _, ok := h.notified[payment.PaymentID]; ok {
+		return
+	}
+
+	h.notified[payment.PaymentID] = struct{}{}
+	h.order = append(h.order, payment.PaymentID)
+	if len(h.order) > maxNotifiedPayments {
+		delete(h.notified, h.order[0])
+		h.order = h.order[1:]
+	}
+
+	body, err := json.Marshal(&sentPayment{
+		PaymentID: payment.PaymentID,
+		UpdatedAt: payment.UpdatedAt,
+		Status:    string(payment.Status),
+		System:    string(payment.System),
+		Asset:     string(payment.Asset),
+		Media:     string(payment.Media),
+		Receipt:   payment.Receipt,
+		TxID:      payment.MediaID,
+		Amount:    payment.Amount.String(),
+		Fee:       payment.MediaFee.String(),
+		Memo:      payment.Memo,
+	})
+	if err != nil {
+		log.Errorf("Unable to encode payment(%v) for post-send hook: %v",
+			payment.PaymentID, err)
+		return
+	}
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		if err := h.postSend.call(body); err != nil {
+			log.Errorf("Post-send hook of payment(%v) has failed: %v",
+				payment.PaymentID, err)
+			return
+		}
+
+		if h.events == nil {
+			return
+		}
+
+		err := h.events.AddPaymentEvent(&connectors.PaymentEvent{
+			PaymentID: payment.PaymentID,
+			Type:      connectors.EventHookDelivered,
+			Time:      connectors.NowInMilliSeconds(),
+			Details:   h.postSend.target,
+		})
+		if err != nil {
+			log.Errorf("Unable to record delivery of payment(%v) to "+
+				"post-send hook: %v", payment.PaymentID, err)
+		}
+	}()
+}
@@ -0,0 +1,234 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/shopspring/decimal"
+)
+
+// discardPaymentsStore is the payments store which doesn't store anything.
+type discardPaymentsStore struct {
+	connectors.PaymentsStore
+}
+
+func (s *discardPaymentsStore) SavePayment(payment *connectors.Payment) error {
+	return nil
+}
+
+// recordingEventsStore is the payment events store which passes recorded
+// events to the channel.
+type recordingEventsStore struct {
+	connectors.PaymentEventsStore
+	events chan *connectors.PaymentEvent
+}
+
+func (s *recordingEventsStore) AddPaymentEvent(
+	event *connectors.PaymentEvent) error {
+	s.events <- event
+	return nil
+}
+
+func TestBeforeSend(t *testing.T) {
+	received := make(chan paymentIntent, 1)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var intent paymentIntent
+			if err := json.NewDecoder(r.Body).Decode(&intent); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			received <- intent
+
+			if intent.Outputs[0].Amount != "1.5" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte("amount exceeds daily limit\n"))
+			}
+		}))
+	defer server.Close()
+
+	hooks := NewSendHooks(server.URL, "", 0, nil, nil, nil, nil)
+
+	intent := &connectors.PaymentIntent{
+		Method: "SendPayment",
+		Tenant: "tenant",
+		Asset:  connectors.BTC,
+		Media:  connectors.Blockchain,
+		Outputs: []*connectors.PaymentOutput{{
+			Address: "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+			Amount:  "1.5",
+		}},
+	}
+	if err := hooks.BeforeSend(intent); err != nil {
+		t.Fatalf("payment should be accepted: %v", err)
+	}
+
+	if req := <-received; req.Tenant != "tenant" || req.Asset != "BTC" ||
+		req.Method != "SendPayment" {
+		t.Fatalf("wrong payment: %v", req)
+	}
+
+	intent.Outputs[0].Amount = "100"
+	err := hooks.BeforeSend(intent)
+	<-received
+
+	veto, ok := err.(*connectors.SendVetoError)
+	if !ok {
+		t.Fatalf("expected veto error, got: %v", err)
+	}
+
+	if veto.Reason != "amount exceeds daily limit" {
+		t.Fatalf("wrong veto reason: %v", veto.Reason)
+	}
+}
+
+func TestBeforeSendUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	hooks := NewSendHooks(url, "", time.Second, nil, nil, nil, nil)
+	err := hooks.BeforeSend(&connectors.PaymentIntent{})
+	if err == nil {
+		t.Fatalf("unavailable hook should reject payment")
+	}
+
+	if _, ok := err.(*connectors.SendVetoError); ok {
+		t.Fatalf("unavailable hook shouldn't be reported as veto")
+	}
+
+	// Disabled hook accepts everything.
+	hooks = NewSendHooks("", "", 0, nil, nil, nil, nil)
+	if err := hooks.BeforeSend(&connectors.PaymentIntent{}); err != nil {
+		t.Fatalf("payment should be accepted: %v", err)
+	}
+}
+
+func TestBeforeSendExecutable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hooks")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	script := filepath.Join(dir, "presend.sh")
+	err = ioutil.WriteFile(script, []byte("#!/bin/sh\n"+
+		"grep -q '\"asset\":\"ETH\"' && exit 0\n"+
+		"echo 'asset is frozen'\n"+
+		"exit 1\n"), 0700)
+	if err != nil {
+		t.Fatalf("unable to write script: %v", err)
+	}
+
+	hooks := NewSendHooks(script, "", 0, nil, nil, nil, nil)
+
+	err = hooks.BeforeSend(&connectors.PaymentIntent{Asset: connectors.ETH})
+	if err != nil {
+		t.Fatalf("payment should be accepted: %v", err)
+	}
+
+	err = hooks.BeforeSend(&connectors.PaymentIntent{Asset: connectors.BTC})
+	veto, ok := err.(*connectors.SendVetoError)
+	if !ok {
+		t.Fatalf("expected veto error, got: %v", err)
+	}
+
+	if veto.Reason != "asset is frozen" {
+		t.Fatalf("wrong veto reason: %v", veto.Reason)
+	}
+}
+
+func TestPostSend(t *testing.T) {
+	received := make(chan sentPayment, 10)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var payment sentPayment
+			if err := json.NewDecoder(r.Body).Decode(&payment); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			received <- payment
+		}))
+	defer server.Close()
+
+	payments := connectors.NewPaymentsBroadcaster(&discardPaymentsStore{})
+
+	events := &recordingEventsStore{
+		events: make(chan *connectors.PaymentEvent, 10),
+	}
+
+	hooks := NewSendHooks("", server.URL, 0, payments, events, nil, nil)
+	hooks.Start()
+
+	save := func(id string, status connectors.PaymentStatus,
+		direction connectors.PaymentDirection) {
+		err := payments.SavePayment(&connectors.Payment{
+			PaymentID: id,
+			Status:    status,
+			Direction: direction,
+			Amount:    decimal.New(15, -1),
+			MediaFee:  decimal.Zero,
+		})
+		if err != nil {
+			t.Fatalf("unable to save payment: %v", err)
+		}
+	}
+
+	save("pending", connectors.Pending, connectors.Outgoing)
+	save("incoming", connectors.Completed, connectors.Incoming)
+	save("completed", connectors.Completed, connectors.Outgoing)
+	save("completed", connectors.Completed, connectors.Outgoing)
+	save("failed", connectors.Failed, connectors.Outgoing)
+
+	ids := make(map[string]int)
+	for i := 0; i < 2; i++ {
+		select {
+		case payment := <-received:
+			ids[payment.PaymentID]++
+
+			if payment.Amount != "1.5" {
+				t.Fatalf("wrong payment: %v", payment)
+			}
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("hook hasn't been called")
+		}
+	}
+
+	// Stop waits for the pending hook calls, so that duplicate calls
+	// would be already received.
+	hooks.Stop()
+	close(received)
+	for payment := range received {
+		ids[payment.PaymentID]++
+	}
+
+	if len(ids) != 2 || ids["completed"] != 1 || ids["failed"] != 1 {
+		t.Fatalf("hook should be called once for every finished "+
+			"outgoing payment, got: %v", ids)
+	}
+
+	close(events.events)
+	delivered := make(map[string]int)
+	for event := range events.events {
+		if event.Type != connectors.EventHookDelivered ||
+			event.Details != server.URL {
+			t.Fatalf("wrong event: %v", event)
+		}
+		delivered[event.PaymentID]++
+	}
+
+	if len(delivered) != 2 || delivered["completed"] != 1 ||
+		delivered["failed"] != 1 {
+		t.Fatalf("delivery should be recorded once for every finished "+
+			"outgoing payment, got: %v", delivered)
+	}
+}
@@ -0,0 +1,154 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bitlum/connector/common"
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/identity"
+	"github.com/go-errors/errors"
+)
+
+// defaultTimeout is the time after which delivery of the event is
+// considered to be failed.
+var defaultTimeout = time.Second * 10
+
+// WatchNotifier posts watch events as JSON to the given URL.
+type WatchNotifier struct {
+	url    string
+	client *http.Client
+
+	// signer signs the bodies along with the timestamp with the server
+	// identity key, so that listener could authenticate them and reject
+	// the replayed ones. If nil bodies are not signed.
+	signer *identity.Key
+}
+
+// Runtime check to ensure that WatchNotifier implements
+// connectors.WatchNotifier interface.
+var _ connectors.WatchNotifier = (*WatchNotifier)(nil)
+
+// newClient returns HTTP client which establishes connections with the
+// dial function, if it is specified.
+func newClient(dial common.DialFunc) *http.Client {
+	client := &http.Client{
+		Timeout: defaultTimeout,
+	}
+
+	if dial != nil {
+		client.Transport = &http.Transport{
+			Dial: dial,
+		}
+	}
+
+	return client
+}
+
+// signRequest signs the body along with the timestamp with the server
+// identity key, signature and timestamp are passed in the headers, see
+// identity.VerifyTimestamped. Request isn't signed if signer is nil.
+func signRequest(req *http.Request, body []byte, signer *identity.Key) error {
+	if signer == nil {
+		return nil
+	}
+
+	// Timestamp is signed along with the body, so that the captured
+	// request can't be replayed out of the identity.MaxSignatureAge
+	// window.
+	timestamp := time.Now().Unix()
+	sig, err := signer.SignTimestamped(timestamp, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(identity.SignatureHeader, sig)
+	req.Header.Set(identity.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(identity.KeyIDHeader, signer.ID())
+
+	return nil
+}
+
+// NewWatchNotifier creates new instance of the notifier. If url is empty,
+// events are only logged. If dial function is specified it is used to
+// establish connections, e.g. through the proxy. If signer is specified,
+// bodies are signed along with the timestamp, signature and timestamp are
+// passed in the headers, see identity.VerifyTimestamped.
+func NewWatchNotifier(url string, dial common.DialFunc,
+	signer *identity.Key) *WatchNotifier {
+	return &WatchNotifier{
+		url:    url,
+		client: newClient(dial),
+		signer: signer,
+	}
+}
+
+// watchEvent is the JSON representation of the event.
+type watchEvent struct {
+	EventID   string `json:"event_id"`
+	CreatedAt int64  `json:"created_at"`
+	Group     string `json:"group"`
+	Asset     string `json:"asset"`
+	Address   string `json:"address"`
+	Direction string `json:"direction"`
+	Amount    string `json:"amount"`
+	TxID      string `json:"tx_id"`
+
+	// Redelivery is set if event has been already delivered, listener
+	// should deduplicate it by the event id.
+	Redelivery bool `json:"redelivery,omitempty"`
+}
+
+// NotifyWatchEvent sends the event to the listener.
+//
+// NOTE: Part of the connectors.WatchNotifier interface.
+func (n *WatchNotifier) NotifyWatchEvent(event *connectors.WatchEvent) error {
+	log.Warnf("Activity on watched address(%v), group(%v), asset(%v), "+
+		"direction(%v), amount(%v), tx(%v)", event.Address, event.Group,
+		event.Asset, event.Direction, event.Amount, event.TxID)
+
+	if n.url == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(&watchEvent{
+		EventID:    event.EventID,
+		CreatedAt:  event.CreatedAt,
+		Group:      event.Group,
+		Asset:      string(event.Asset),
+		Address:    event.Address,
+		Direction:  string(event.Direction),
+		Amount:     event.Amount.String(),
+		TxID:       event.TxID,
+		Redelivery: event.Redelivery,
+	})
+	if err != nil {
+		return errors.Errorf("unable to encode event: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", n.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Errorf("unable to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := signRequest(req, body, n.signer); err != nil {
+		return err
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Errorf("unable to post event: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook responded with status: %v",
+			resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitlum/connector/connectors"
+	"github.com/bitlum/connector/identity"
+	"github.com/shopspring/decimal"
+)
+
+func TestNotifyWatchEvent(t *testing.T) {
+	received := make(chan watchEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var event watchEvent
+			if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			received <- event
+		}))
+	defer server.Close()
+
+	notifier := NewWatchNotifier(server.URL, nil, nil)
+	err := notifier.NotifyWatchEvent(&connectors.WatchEvent{
+		EventID:    "1",
+		Group:      "attacker",
+		Asset:      connectors.BTC,
+		Address:    "1BtBojSMWGpp8z4EgrFbd2BZKiThXRYX1e",
+		Direction:  connectors.Incoming,
+		Amount:     decimal.New(15, -1),
+		TxID:       "txid",
+		Redelivery: true,
+	})
+	if err != nil {
+		t.Fatalf("unable to notify: %v", err)
+	}
+
+	event := <-received
+	if event.Group != "attacker" || event.Amount != "1.5" ||
+		event.Direction != "Incoming" || !event.Redelivery {
+		t.Fatalf("wrong event: %v", event)
+	}
+}
+
+func TestNotifyWatchEventFailedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+	defer server.Close()
+
+	notifier := NewWatchNotifier(server.URL, nil, nil)
+	err := notifier.NotifyWatchEvent(&connectors.WatchEvent{
+		Amount: decimal.Zero,
+	})
+	if err == nil {
+		t.Fatalf("expected error on failed status")
+	}
+}
+
+func TestNotifyWatchEventSigned(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := identity.LoadKey(filepath.Join(dir, "identity.key"))
+	if err != nil {
+		t.Fatalf("unable to create identity key: %v", err)
+	}
+
+	verified := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				verified <- err
+				return
+			}
+
+			if id := r.Header.Get(identity.KeyIDHeader); id != key.ID() {
+				verified <- fmt.Errorf("wrong key id: %v", id)
+				return
+			}
+
+			verified <- identity.VerifyTimestamped(key.PublicKey(), body,
+				r.Header.Get(identity.TimestampHeader),
+				r.Header.Get(identity.SignatureHeader), time.Now())
+		}))
+	defer server.Close()
+
+	notifier := NewWatchNotifier(server.URL, nil, key)
+	err = notifier.NotifyWatchEvent(&connectors.WatchEvent{
+		EventID: "1",
+		Amount:  decimal.New(15, -1),
+	})
+	if err != nil {
+		t.Fatalf("unable to notify: %v", err)
+	}
+
+	if err := <-verified; err != nil {
+		t.Fatalf("body signature isn't valid: %v", err)
+	}
+}